@@ -3,11 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/constants"
 	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/notify"
+	"github.com/ajxudir/goupdate/pkg/outdated"
 	"github.com/ajxudir/goupdate/pkg/verbose"
 	"github.com/spf13/cobra"
 )
@@ -30,6 +33,10 @@ var (
 // This is used by the scan command where we want to detect files even if some configs
 // in subdirectories are malformed (e.g., test fixtures).
 func loadConfigWithoutValidation(configPath, workDir string) (*config.Config, error) {
+	configPath, err := resolveConfigPath(configPath)
+	if err != nil {
+		return nil, err
+	}
 	cfg, err := loadConfigFunc(configPath, workDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
@@ -37,51 +44,98 @@ func loadConfigWithoutValidation(configPath, workDir string) (*config.Config, er
 	return cfg, nil
 }
 
+// resolveConfigPath combines a command's --config flag with the global --config-dir flag.
+//
+// Parameters:
+//   - configPath: Value of the command's --config flag
+//
+// Returns:
+//   - string: The config file or directory to load, or empty for auto-detection
+//   - error: Exit error when both flags are set
+func resolveConfigPath(configPath string) (string, error) {
+	if configDirFlag == "" {
+		return configPath, nil
+	}
+	if configPath != "" {
+		return "", errors.NewExitErrorf(errors.ExitConfigError, "--config and --config-dir cannot be used together")
+	}
+	return configDirFlag, nil
+}
+
+// checkConfigFile validates one config file for unknown fields.
+//
+// Parameters:
+//   - path: Path of the config file, shown in the error
+//   - data: Contents of the config file
+//   - kind: Where the file came from for verbose output ("custom config" or "local config")
+//
+// Returns:
+//   - error: Exit error listing the validation errors, or nil when the file is valid
+func checkConfigFile(path string, data []byte, kind string) error {
+	result := config.ValidateConfigFileAt(path, data)
+	if !result.HasErrors() {
+		return nil
+	}
+
+	var errBuilder strings.Builder
+	errBuilder.WriteString(fmt.Sprintf("configuration validation failed for %s:\n", path))
+	for _, e := range result.Errors {
+		errBuilder.WriteString(fmt.Sprintf("  - %s\n", e.Error()))
+	}
+	errBuilder.WriteString("\n💡 Run 'goupdate config --validate' for details, or see docs/configuration.md")
+	verbose.Infof("Exit code %d (config error): configuration validation failed for %s %s", errors.ExitConfigError, kind, path)
+	return errors.NewExitErrorf(errors.ExitConfigError, "%s", errBuilder.String()).WithCause(result.Err())
+}
+
 // loadAndValidateConfig loads the configuration and validates it for unknown fields.
 //
 // This provides preflight validation to catch configuration errors early,
 // ensuring users are notified of typos or deprecated options before processing.
+// Pin ranges are parsed once the configuration is loaded.
 //
 // Parameters:
-//   - configPath: Path to custom config file, or empty for default location
+//   - configPath: Path to custom config file or directory, or empty for default location
 //   - workDir: Working directory to search for default config
 //
 // Returns:
 //   - *config.Config: Loaded and validated configuration
 //   - error: Validation or load error with details
 func loadAndValidateConfig(configPath, workDir string) (*config.Config, error) {
+	configPath, err := resolveConfigPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// If a custom config path is specified, validate it first
 	if configPath != "" {
-		data, err := readFileFunc(configPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read config file '%s': %w", configPath, err)
+		paths := []string{configPath}
+		if config.IsConfigDir(configPath) {
+			if paths, err = config.ConfigDirFiles(configPath); err != nil {
+				return nil, fmt.Errorf("failed to read config directory '%s': %w", configPath, err)
+			}
 		}
-
-		result := config.ValidateConfigFile(data)
-		if result.HasErrors() {
-			var errBuilder strings.Builder
-			errBuilder.WriteString(fmt.Sprintf("configuration validation failed for %s:\n", configPath))
-			for _, e := range result.Errors {
-				errBuilder.WriteString(fmt.Sprintf("  - %s\n", e.Error()))
+		for _, path := range paths {
+			data, err := readFileFunc(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read config file '%s': %w", path, err)
+			}
+			if err := checkConfigFile(path, data, "custom config"); err != nil {
+				return nil, err
 			}
-			errBuilder.WriteString("\n💡 Run 'goupdate config --validate' for details, or see docs/configuration.md")
-			verbose.Infof("Exit code %d (config error): configuration validation failed for custom config %s", errors.ExitConfigError, configPath)
-			return nil, errors.NewExitError(errors.ExitConfigError, fmt.Errorf("%s", errBuilder.String()))
 		}
 	} else {
-		// Check for .goupdate.yml in workDir and validate if it exists
-		localConfig := workDir + "/.goupdate.yml"
-		if data, err := readFileFunc(localConfig); err == nil {
-			result := config.ValidateConfigFile(data)
-			if result.HasErrors() {
-				var errBuilder strings.Builder
-				errBuilder.WriteString(fmt.Sprintf("configuration validation failed for %s:\n", localConfig))
-				for _, e := range result.Errors {
-					errBuilder.WriteString(fmt.Sprintf("  - %s\n", e.Error()))
+		// Check for a local config (or config directory) in workDir and validate if it exists
+		var paths []string
+		if localConfig := config.FindLocalConfig(workDir); localConfig != "" {
+			paths = []string{localConfig}
+		} else if configDir := config.FindConfigDir(workDir); configDir != "" {
+			paths, _ = config.ConfigDirFiles(configDir)
+		}
+		for _, path := range paths {
+			if data, err := readFileFunc(path); err == nil {
+				if err := checkConfigFile(path, data, "local config"); err != nil {
+					return nil, err
 				}
-				errBuilder.WriteString("\n💡 Run 'goupdate config --validate' for details, or see docs/configuration.md")
-				verbose.Infof("Exit code %d (config error): configuration validation failed for local config %s", errors.ExitConfigError, localConfig)
-				return nil, errors.NewExitError(errors.ExitConfigError, fmt.Errorf("%s", errBuilder.String()))
 			}
 		}
 	}
@@ -92,6 +146,12 @@ func loadAndValidateConfig(configPath, workDir string) (*config.Config, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Pin ranges need the version range parser, which the config schema check cannot reach
+	if err := outdated.ValidatePins(cfg); err != nil {
+		verbose.Infof("Exit code %d (config error): invalid pin ranges", errors.ExitConfigError)
+		return nil, errors.NewExitErrorf(errors.ExitConfigError, "configuration validation failed:\n  - %s", strings.ReplaceAll(err.Error(), "\n", "\n  - ")).WithCause(err)
+	}
+
 	return cfg, nil
 }
 
@@ -102,7 +162,19 @@ var configCmd = &cobra.Command{
 	RunE:  runConfig,
 }
 
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for the config file",
+	Long: `Print a JSON Schema (draft-07) describing .goupdate.yml for editor autocompletion.
+
+Example:
+  goupdate config schema > goupdate.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigSchema,
+}
+
 func init() {
+	configCmd.AddCommand(configSchemaCmd)
 	configCmd.Flags().BoolVar(&configShowDefaultsFlag, "show-defaults", false, "Show default configuration")
 	configCmd.Flags().BoolVar(&configShowEffectiveFlag, "show-effective", false, "Show effective configuration")
 	configCmd.Flags().BoolVar(&configInitFlag, "init", false, "Create .goupdate.yml template")
@@ -143,7 +215,7 @@ func runConfig(cmd *cobra.Command, args []string) error {
 
 	if configShowEffectiveFlag {
 		workDir, _ := os.Getwd()
-		cfg, err := loadConfigFunc("", workDir)
+		cfg, err := loadConfigFunc(configDirFlag, workDir)
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -184,33 +256,132 @@ func runConfig(cmd *cobra.Command, args []string) error {
 			}
 			fmt.Println()
 		}
+
+		// Show notify configuration with the webhook secret and header values redacted
+		if cfg.Notify != nil {
+			fmt.Println("Notify:")
+			fmt.Printf("  URL: %s\n", notify.RedactURL(os.ExpandEnv(cfg.Notify.URL)))
+			fmt.Printf("  Format: %s\n", cfg.Notify.GetFormat())
+			headers := make([]string, 0, len(cfg.Notify.Headers))
+			for name := range cfg.Notify.Headers {
+				headers = append(headers, name+": ***")
+			}
+			sort.Strings(headers)
+			for _, header := range headers {
+				fmt.Printf("  Header: %s\n", header)
+			}
+			if cfg.Notify.OnDryRun {
+				fmt.Printf("  On Dry Run: true\n")
+			}
+			fmt.Println()
+		}
 		return nil
 	}
 
 	return cmd.Help()
 }
 
+// runConfigSchema prints the JSON Schema of the configuration file to stdout.
+//
+// Parameters:
+//   - cmd: Cobra command instance
+//   - args: Command line arguments (unused)
+//
+// Returns:
+//   - error: Returns error if the schema cannot be generated
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	schema, err := config.JSONSchema()
+	if err != nil {
+		return fmt.Errorf("failed to generate config schema: %w", err)
+	}
+	fmt.Println(string(schema))
+	return nil
+}
+
 // validateConfigFile validates the configuration file at the specified path.
 //
-// If no path is specified via --config flag, validates .goupdate.yml in the
-// current working directory. Reports validation errors and warnings.
+// If no path is specified via --config or --config-dir, validates the local config
+// (.goupdate.yml, .goupdate.toml, or goupdate.toml) in the current working directory,
+// or its .goupdate.d directory. Reports validation errors and warnings.
 //
 // Returns:
 //   - error: Returns ExitError with ExitConfigError code on validation failure
 func validateConfigFile() error {
-	configPath := configPathFlag
+	configPath, err := resolveConfigPath(configPathFlag)
+	if err != nil {
+		return err
+	}
+	workDir, _ := os.Getwd()
 	if configPath == "" {
 		// Try default location
-		workDir, _ := os.Getwd()
-		configPath = workDir + "/.goupdate.yml"
+		configPath = config.FindLocalConfig(workDir)
+		if configPath == "" {
+			configPath = config.FindConfigDir(workDir)
+		}
+		if configPath == "" {
+			configPath = workDir + "/.goupdate.yml"
+		}
+	}
+
+	if config.IsConfigDir(configPath) {
+		return validateConfigDir(configPath, workDir)
 	}
+	return validateConfigFileAt(configPath)
+}
 
+// validateConfigDir validates every file of a config directory and how they merge.
+//
+// Each file is reported like a single config file. When all of them are valid,
+// the directory is loaded to catch rules defined by two files without override.
+//
+// Parameters:
+//   - dir: Config directory to validate
+//   - workDir: Working directory passed to the config loader
+//
+// Returns:
+//   - error: Returns ExitError with ExitConfigError code on validation failure
+func validateConfigDir(dir, workDir string) error {
+	files, err := config.ConfigDirFiles(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read config directory '%s': %w", dir, err)
+	}
+	if len(files) == 0 {
+		return errors.NewExitErrorf(errors.ExitConfigError, "no .yml, .yaml or .toml config files in %s", dir)
+	}
+
+	var firstErr error
+	for _, path := range files {
+		if err := validateConfigFileAt(path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if _, err := loadConfigFunc(dir, workDir); err != nil {
+		fmt.Printf("%s Configuration directory invalid: %s\n\n  ERROR: %v\n", constants.IconError, dir, err)
+		verbose.Infof("Exit code %d (config error): config directory %s failed to merge", errors.ExitConfigError, dir)
+		return errors.NewExitErrorf(errors.ExitConfigError, "configuration validation failed").WithCause(err)
+	}
+	fmt.Printf("%s Configuration directory valid: %s (%d files)\n", constants.IconCheckmarkBox, dir, len(files))
+	return nil
+}
+
+// validateConfigFileAt validates a single configuration file and prints the result.
+//
+// Parameters:
+//   - configPath: Path of the config file to validate
+//
+// Returns:
+//   - error: Returns ExitError with ExitConfigError code on validation failure
+func validateConfigFileAt(configPath string) error {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return fmt.Errorf("failed to read config file '%s': %w", configPath, err)
 	}
 
-	result := config.ValidateConfigFile(data)
+	result := config.ValidateConfigFileAt(configPath, data)
 
 	if result.HasErrors() {
 		fmt.Printf("%s Configuration validation failed for: %s\n\n", constants.IconError, configPath)
@@ -238,7 +409,7 @@ func validateConfigFile() error {
 		}
 		fmt.Printf("%s See docs/configuration.md for valid configuration options\n", constants.IconLightbulb)
 		verbose.Infof("Exit code %d (config error): configuration validation failed for %s", errors.ExitConfigError, configPath)
-		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("configuration validation failed"))
+		return errors.NewExitErrorf(errors.ExitConfigError, "configuration validation failed").WithCause(result.Err())
 	}
 
 	if len(result.Warnings) > 0 {
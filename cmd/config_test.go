@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -199,6 +200,26 @@ func TestConfigCommand(t *testing.T) {
 	})
 }
 
+// TestRunConfigSchema tests the config schema subcommand.
+//
+// It verifies:
+//   - The schema subcommand is registered under config
+//   - The printed output is a JSON Schema document describing rules
+func TestRunConfigSchema(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"config", "schema"})
+	require.NoError(t, err)
+	assert.Equal(t, configSchemaCmd, cmd)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, runConfigSchema(configSchemaCmd, nil))
+	})
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &schema))
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", schema["$schema"])
+	assert.Contains(t, schema["properties"], "rules")
+}
+
 // TestRunConfigEffectiveError tests the behavior of runConfig when loading fails.
 //
 // It verifies:
@@ -329,6 +350,8 @@ func captureStderr(t *testing.T, fn func()) string {
 //   - Default config is used when no local config exists
 //   - Valid local config files are loaded successfully
 //   - Config load failures after validation are handled
+//   - --config-dir loads and validates every file, and conflicts with --config
+//   - Files of an auto-detected .goupdate.d are validated
 func TestLoadAndValidateConfig(t *testing.T) {
 	t.Run("valid config file", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -433,12 +456,48 @@ rules:
 		assert.Nil(t, cfg)
 		assert.Contains(t, err.Error(), "failed to load config")
 	})
+
+	t.Run("config directory", func(t *testing.T) {
+		oldDir := configDirFlag
+		defer func() { configDirFlag = oldDir }()
+
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(tmpDir+"/10-npm.yml", []byte("extends: [default]\nrules:\n  npm:\n    enabled: false\n"), 0644))
+		require.NoError(t, os.WriteFile(tmpDir+"/20-custom.yml", []byte("rules:\n  custom:\n    manager: custom\n    include: [\"*.custom\"]\n    format: raw\n"), 0644))
+
+		configDirFlag = tmpDir
+		cfg, err := loadAndValidateConfig("", tmpDir)
+		require.NoError(t, err)
+		npm := cfg.Rules["npm"]
+		assert.False(t, npm.IsEnabled())
+		assert.Contains(t, cfg.Rules, "custom")
+
+		_, err = loadAndValidateConfig(tmpDir+"/10-npm.yml", tmpDir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--config and --config-dir cannot be used together")
+
+		require.NoError(t, os.WriteFile(tmpDir+"/30-typo.yml", []byte("rules:\n  other:\n    typo_field: oops\n"), 0644))
+		_, err = loadAndValidateConfig("", tmpDir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "configuration validation failed for "+tmpDir+"/30-typo.yml")
+	})
+
+	t.Run("local config directory with unknown field", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.Mkdir(tmpDir+"/.goupdate.d", 0755))
+		require.NoError(t, os.WriteFile(tmpDir+"/.goupdate.d/npm.yml", []byte("rules:\n  npm:\n    typo_field: oops\n"), 0644))
+
+		_, err := loadAndValidateConfig("", tmpDir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "configuration validation failed for "+tmpDir+"/.goupdate.d/npm.yml")
+	})
 }
 
 // TestLoadAndValidateConfigExitCode tests the behavior of loadAndValidateConfig exit codes.
 //
 // It verifies:
 //   - Config validation errors return ExitConfigError code
+//   - Malformed pin ranges return ExitConfigError code naming the pin
 func TestLoadAndValidateConfigExitCode(t *testing.T) {
 	t.Run("returns config error exit code", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -457,6 +516,21 @@ rules:
 		code := errors.GetExitCode(err)
 		assert.Equal(t, errors.ExitConfigError, code)
 	})
+
+	t.Run("returns config error exit code for invalid pin", func(t *testing.T) {
+		oldLoad := loadConfigFunc
+		defer func() { loadConfigFunc = oldLoad }()
+		loadConfigFunc = func(configPath, workDir string) (*config.Config, error) {
+			return &config.Config{Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Pin: map[string]string{"react": "<<17"}},
+			}}, nil
+		}
+
+		_, err := loadAndValidateConfig("", t.TempDir())
+		require.Error(t, err)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+		assert.Contains(t, err.Error(), "rules.npm.pin.react")
+	})
 }
 
 // TestRunConfigShowEffectiveWithSystemTests tests the behavior of runConfig --show-effective with system tests.
@@ -514,6 +588,52 @@ func TestRunConfigShowEffectiveWithSystemTests(t *testing.T) {
 	assert.Contains(t, output, "integration")
 }
 
+// TestRunConfigShowEffectiveWithNotify tests the behavior of runConfig --show-effective with a notify block.
+//
+// It verifies:
+//   - The webhook URL is shown with its path redacted
+//   - Header names are shown without their values
+func TestRunConfigShowEffectiveWithNotify(t *testing.T) {
+	oldLoad := loadConfigFunc
+	defer func() { loadConfigFunc = oldLoad }()
+
+	loadConfigFunc = func(configPath, baseDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: baseDir,
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Include: []string{"**/package.json"}},
+			},
+			Notify: &config.NotifyCfg{
+				URL:     "https://hooks.slack.com/services/T0/B0/webhook-secret",
+				Format:  config.NotifyFormatSlack,
+				Headers: map[string]string{"Authorization": "Bearer header-secret"},
+			},
+		}, nil
+	}
+
+	oldInit, oldDefaults, oldEffective := configInitFlag, configShowDefaultsFlag, configShowEffectiveFlag
+	defer func() {
+		configInitFlag = oldInit
+		configShowDefaultsFlag = oldDefaults
+		configShowEffectiveFlag = oldEffective
+	}()
+
+	configInitFlag = false
+	configShowDefaultsFlag = false
+	configShowEffectiveFlag = true
+
+	output := captureStdout(t, func() {
+		err := runConfig(nil, nil)
+		assert.NoError(t, err)
+	})
+
+	assert.Contains(t, output, "Notify:")
+	assert.Contains(t, output, "URL: https://hooks.slack.com/***")
+	assert.Contains(t, output, "Format: slack")
+	assert.Contains(t, output, "Header: Authorization: ***")
+	assert.NotContains(t, output, "secret")
+}
+
 // TestRunConfigShowEffectiveWithDisabledRule tests the behavior of runConfig --show-effective with disabled rules.
 //
 // It verifies:
@@ -598,6 +718,7 @@ func TestRunConfigShowEffectiveWithExclude(t *testing.T) {
 //   - Valid config files pass validation
 //   - Invalid config files are rejected with appropriate error
 //   - Missing config files are handled properly
+//   - Config directories validate each file and reject rules defined twice
 func TestConfigValidateCommand(t *testing.T) {
 	t.Run("validates valid config", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -814,4 +935,32 @@ rules:
 		// When verbose is enabled, shouldn't show hint to run with --verbose
 		assert.NotContains(t, output, "Run with --verbose for detailed")
 	})
+
+	t.Run("validates a config directory", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(tmpDir+"/a.yml", []byte("rules:\n  npm:\n    manager: js\n    include: [\"**/package.json\"]\n"), 0644))
+		require.NoError(t, os.WriteFile(tmpDir+"/b.yml", []byte("rules:\n  npm:\n    include: [\"web/package.json\"]\n"), 0644))
+
+		oldPath, oldDir, oldValidate := configPathFlag, configDirFlag, configValidateFlag
+		defer func() {
+			configPathFlag, configDirFlag, configValidateFlag = oldPath, oldDir, oldValidate
+		}()
+		configPathFlag, configDirFlag, configValidateFlag = "", tmpDir, true
+
+		var err error
+		output := captureStdout(t, func() {
+			err = runConfig(nil, nil)
+		})
+		require.Error(t, err)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+		assert.Contains(t, output, "Configuration valid: "+tmpDir+"/a.yml")
+		assert.Contains(t, output, `rule "npm" in b.yml is already defined in a.yml`)
+
+		require.NoError(t, os.WriteFile(tmpDir+"/b.yml", []byte("rules:\n  npm:\n    override: true\n    include: [\"web/package.json\"]\n"), 0644))
+		output = captureStdout(t, func() {
+			err = runConfig(nil, nil)
+		})
+		require.NoError(t, err)
+		assert.Contains(t, output, "Configuration directory valid: "+tmpDir+" (2 files)")
+	})
 }
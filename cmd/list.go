@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"os"
@@ -10,9 +12,11 @@ import (
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/display"
+	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/filtering"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/outdated"
 	"github.com/ajxudir/goupdate/pkg/output"
 	"github.com/ajxudir/goupdate/pkg/packages"
 	"github.com/ajxudir/goupdate/pkg/supervision"
@@ -31,6 +35,15 @@ var (
 	listDirFlag    string
 	listOutputFlag string
 	listFileFlag   string
+
+	listVersionRangeFlag string
+	listDriftedFlag      bool
+	listSelectFlag       string
+
+	listOutdatedOnlyFlag bool
+	listShowSourceFlag   bool
+
+	listGroupByFlag = display.GroupByNone
 )
 
 var (
@@ -56,6 +69,12 @@ func init() {
 	listCmd.Flags().StringVarP(&listDirFlag, "directory", "d", ".", "Directory to scan")
 	listCmd.Flags().StringVarP(&listOutputFlag, "output", "o", "", "Output format: json, csv, xml (default: table)")
 	listCmd.Flags().StringVarP(&listFileFlag, "file", "f", "", "Filter by file path patterns (comma-separated, supports globs)")
+	listCmd.Flags().StringVar(&listVersionRangeFlag, "version-range", "", "Only include packages whose installed version satisfies a semver range (e.g. \"<2.0.0\")")
+	listCmd.Flags().BoolVar(&listDriftedFlag, "drifted", false, "Only include packages whose installed version does not satisfy the declared version")
+	listCmd.Flags().StringVar(&listSelectFlag, "select", "", "Only include packages matching an expression (e.g. \"rule==npm && type==prod\")")
+	listCmd.Flags().BoolVar(&listOutdatedOnlyFlag, "outdated-only", false, "Only list packages with newer versions available (runs version lookups)")
+	listCmd.Flags().Var(&listGroupByFlag, "group-by", "Split output into sections: rule, type, group, or none")
+	listCmd.Flags().BoolVar(&listShowSourceFlag, "show-source", false, "Add a SOURCE column with the manifest file and line declaring each package")
 }
 
 // runList executes the list command to display package versions.
@@ -75,6 +94,23 @@ func runList(cmd *cobra.Command, args []string) error {
 	if err := output.ValidateStructuredOutputFlags(outputFormat, verboseFlag); err != nil {
 		return err
 	}
+	if err := output.ValidateUpdateOnlyFormat(outputFormat, "list"); err != nil {
+		return err
+	}
+	if err := output.ValidateShieldsSupport(outputFormat, "list"); err != nil {
+		return err
+	}
+	installedFilter := filtering.FilterOptions{VersionConstraint: listVersionRangeFlag, DriftedOnly: listDriftedFlag}
+	if err := installedFilter.Validate(); err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+	selector, err := filtering.ParseSelect(listSelectFlag)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+	if selector.NeedsLatest() {
+		return errors.NewExitErrorf(errors.ExitConfigError, "--select: gap needs version lookups; use it with outdated or update")
+	}
 
 	collector := &display.WarningCollector{}
 	restoreWarnings := warnings.SetWarningWriter(collector)
@@ -106,34 +142,98 @@ func runList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if installedFilter.HasInstalledFilter() {
+		pkgs = filtering.FilterPackages(pkgs, installedFilter)
+	}
 	pkgs = filtering.ApplyPackageGroups(pkgs, cfg)
 	pkgs = filtering.FilterByGroup(pkgs, listGroupFlag)
+	pkgs = filtering.SelectPackages(pkgs, selector)
+	supervision.WarnVersionConflicts(pkgs, workDir)
 	for _, p := range pkgs {
 		if supervision.ShouldTrackUnsupported(p.InstallStatus) {
 			unsupported.Add(p, supervision.DeriveUnsupportedReason(p, cfg, nil, false))
 		}
 	}
 
+	foundPackages := len(pkgs) > 0
+	if listOutdatedOnlyFlag && foundPackages {
+		pkgs, err = filterOutdatedPackages(pkgs, cfg, workDir)
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(pkgs) == 0 {
 		if output.IsStructuredFormat(outputFormat) {
-			return printListStructured(pkgs, collector.Messages(), outputFormat)
+			return printListStructured(pkgs, collector.Messages(), warningDetails(collector, pkgs), outputFormat)
+		}
+		if foundPackages {
+			display.PrintNoPackagesMessage(os.Stdout, "with newer versions available")
+		} else {
+			display.PrintNoPackagesMessageWithFilters(os.Stdout, listTypeFlag, listPMFlag, listRuleFlag)
 		}
-		display.PrintNoPackagesMessageWithFilters(os.Stdout, listTypeFlag, listPMFlag, listRuleFlag)
 		display.PrintUnsupportedMessages(os.Stdout, unsupported.Messages())
 		display.PrintWarnings(os.Stdout, collector.Messages())
 		return nil
 	}
 
 	if output.IsStructuredFormat(outputFormat) {
-		return printListStructured(pkgs, collector.Messages(), outputFormat)
+		return printListStructured(pkgs, collector.Messages(), warningDetails(collector, pkgs), outputFormat)
 	}
 
-	printPackages(pkgs)
+	printPackages(pkgs, workDir)
 	display.PrintUnsupportedMessages(os.Stdout, unsupported.Messages())
 	display.PrintWarnings(os.Stdout, collector.Messages())
 	return nil
 }
 
+// filterOutdatedPackages keeps only packages that have newer versions available.
+//
+// Versions are looked up the same way as the outdated command, using the
+// default concurrency. Ignored, floating, and non-registry packages are never
+// outdated, and packages whose rule has no outdated configuration are dropped
+// without an error.
+//
+// Parameters:
+//   - pkgs: Packages to check
+//   - cfg: The global configuration
+//   - workDir: Base directory for command execution
+//
+// Returns:
+//   - []formats.Package: Packages with at least one newer version, in input order
+//   - error: ExitError listing every failed lookup; returns nil on success
+func filterOutdatedPackages(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+	lookups := outdated.StartVersionLookups(context.Background(), pkgs, cfg, workDir, outdated.DefaultConcurrency(), listNewerVersionsFunc, func(i int) bool {
+		return needsOutdatedLookup(pkgs[i])
+	})
+
+	kept := make([]formats.Package, 0, len(pkgs))
+	var errs []error
+	for i, p := range pkgs {
+		if !needsOutdatedLookup(p) {
+			continue
+		}
+
+		versions, err := lookups.Get(i)
+		if err != nil {
+			if !errors.IsUnsupported(err) {
+				errs = append(errs, fmt.Errorf("%s (%s/%s): %w", p.Name, p.PackageType, p.Rule, err))
+			}
+			continue
+		}
+
+		if len(outdated.FilterVersionsByConstraint(p, versions, outdated.UpdateSelectionFlags{Major: true})) > 0 {
+			kept = append(kept, p)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.NewExitError(errors.ExitFailure, stderrors.Join(errs...))
+	}
+
+	return kept, nil
+}
+
 // getListOutputFormat determines the output format for list results.
 //
 // Parses the --output flag value and returns the corresponding format.
@@ -148,46 +248,80 @@ func getListOutputFormat() output.Format {
 // printListStructured outputs list results in a structured format.
 //
 // Converts packages to structured output format, sorts for consistent display,
-// and outputs in the requested format (JSON, CSV, or XML).
+// and outputs in the requested format (JSON, CSV, or XML). With --group-by,
+// the packages are also split into named sections.
 //
 // Parameters:
 //   - pkgs: Packages to output
 //   - warnings: Warning messages to include in output
+//   - details: Classified warnings to include in output
 //   - format: Output format to use
 //
 // Returns:
 //   - error: Returns error on output failure
-func printListStructured(pkgs []formats.Package, warnings []string, format output.Format) error {
-	sortedPkgs := filtering.SortPackagesForDisplay(pkgs)
-
-	packages := make([]output.ListPackage, 0, len(sortedPkgs))
-	for _, p := range sortedPkgs {
-		constraintDisplay := display.FormatConstraintDisplay(p)
-		packages = append(packages, output.ListPackage{
-			Rule:             p.Rule,
-			PM:               p.PackageType,
-			Type:             p.Type,
-			Constraint:       constraintDisplay,
-			Version:          display.SafeDeclaredValue(p.Version),
-			InstalledVersion: display.SafeInstalledValue(p.InstalledVersion),
-			Status:           p.InstallStatus,
-			Group:            p.Group,
-			Name:             p.Name,
-			IgnoreReason:     p.IgnoreReason,
-		})
+func printListStructured(pkgs []formats.Package, warnings []string, details []warnings.Warning, format output.Format) error {
+	sections := display.GroupPackages(pkgs, listGroupByFlag)
+
+	packages := make([]output.ListPackage, 0, len(pkgs))
+	var listSections output.ListSections
+	for _, section := range sections {
+		sectionPackages := make([]output.ListPackage, 0, len(section.Packages))
+		for _, p := range section.Packages {
+			constraintDisplay := display.FormatConstraintDisplay(p)
+			sectionPackages = append(sectionPackages, output.ListPackage{
+				Rule:             p.Rule,
+				PM:               p.PackageType,
+				Type:             p.Type,
+				Constraint:       constraintDisplay,
+				Version:          display.SafeDeclaredValue(p.Version),
+				InstalledVersion: display.SafeInstalledValue(p.InstalledVersion),
+				Status:           p.InstallStatus,
+				Group:            p.Group,
+				Name:             p.Name,
+				Source:           p.Source,
+				Line:             p.Line,
+				IgnoreReason:     p.IgnoreReason,
+				UsedBy:           p.UsedBy,
+			})
+		}
+		packages = append(packages, sectionPackages...)
+		if listGroupByFlag.Enabled() {
+			listSections = append(listSections, output.ListSection{
+				Name:     section.Key,
+				Summary:  output.ListSummary{TotalPackages: len(sectionPackages)},
+				Packages: sectionPackages,
+			})
+		}
 	}
 
 	result := &output.ListResult{
 		Summary: output.ListSummary{
 			TotalPackages: len(packages),
 		},
-		Packages: packages,
-		Warnings: warnings,
+		Packages:       packages,
+		Sections:       listSections,
+		Warnings:       warnings,
+		WarningDetails: details,
+	}
+	if listGroupByFlag.Enabled() {
+		result.GroupBy = string(listGroupByFlag)
 	}
 
 	return output.WriteListResult(os.Stdout, format, result)
 }
 
+// warningDetails combines collected warnings with per-package status warnings.
+//
+// Parameters:
+//   - collector: Collector that captured warnings during the command
+//   - pkgs: Packages whose install status may need attention
+//
+// Returns:
+//   - []warnings.Warning: Collected warnings first, then status warnings in package order
+func warningDetails(collector *display.WarningCollector, pkgs []formats.Package) []warnings.Warning {
+	return append(collector.Warnings(), supervision.StatusWarnings(pkgs)...)
+}
+
 // getPackages retrieves packages either from specified files or by auto-detection.
 //
 // If args contains file paths, parses only those files. Otherwise, auto-detects
@@ -323,7 +457,7 @@ func detectAndParseAll(cfg *config.Config, parser *packages.DynamicParser, workD
 		for _, file := range files {
 			pkgList, err := parser.ParseFile(file, &ruleCfg)
 			if err != nil {
-				warnings.Warnf("⚠️ failed to parse %s: %v\n", file, err)
+				warnings.Warn(warnings.CodeParseFailed, "", "⚠️ failed to parse %s: %v", file, err)
 				continue
 			}
 			for i := range pkgList.Packages {
@@ -342,21 +476,24 @@ type listDisplayRow struct {
 	pkg               formats.Package
 	constraintDisplay string
 	statusDisplay     string
+	source            string
 }
 
 // prepareListDisplayRows prepares display data for package listing.
 //
 // Formats constraint and status values for each package while capturing
-// any warnings generated during formatting.
+// any warnings generated during formatting. With --show-source, the manifest
+// location of each package is formatted relative to workDir.
 //
 // Parameters:
 //   - pkgs: Packages to prepare for display
+//   - workDir: Directory source paths are shown relative to
 //
 // Returns:
 //   - []listDisplayRow: Formatted rows ready for table output
 //   - string: Captured warning messages
 //   - io.Writer: Previous warning writer for restoration
-func prepareListDisplayRows(pkgs []formats.Package) ([]listDisplayRow, string, io.Writer) {
+func prepareListDisplayRows(pkgs []formats.Package, workDir string) ([]listDisplayRow, string, io.Writer) {
 	rows := make([]listDisplayRow, 0, len(pkgs))
 	var warningsOut strings.Builder
 	warningWriter := warnings.WarningWriter()
@@ -370,11 +507,15 @@ func prepareListDisplayRows(pkgs []formats.Package) ([]listDisplayRow, string, i
 			warningsOut.WriteString(warn)
 		}
 
-		rows = append(rows, listDisplayRow{
+		row := listDisplayRow{
 			pkg:               p,
 			constraintDisplay: constraintDisplay,
 			statusDisplay:     display.FormatStatusWithIcon(p.InstallStatus),
-		})
+		}
+		if listShowSourceFlag {
+			row.source = display.FormatSource(p.Source, p.Line, workDir)
+		}
+		rows = append(rows, row)
 	}
 
 	return rows, warningsOut.String(), warningWriter
@@ -383,13 +524,16 @@ func prepareListDisplayRows(pkgs []formats.Package) ([]listDisplayRow, string, i
 // printPackages outputs packages in table format to stdout.
 //
 // Sorts packages for display, formats all values, and prints a table
-// with headers showing all package information.
+// with headers showing all package information. With --group-by, each
+// section gets a subheader and its own header rows; column widths are shared
+// so the sections line up.
 //
 // Parameters:
 //   - pkgs: Packages to display
-func printPackages(pkgs []formats.Package) {
-	sortedPkgs := filtering.SortPackagesForDisplay(pkgs)
-	rows, warningsOut, warningWriter := prepareListDisplayRows(sortedPkgs)
+//   - workDir: Directory the SOURCE column is relative to
+func printPackages(pkgs []formats.Package, workDir string) {
+	sections := display.GroupPackages(pkgs, listGroupByFlag)
+	rows, warningsOut, warningWriter := prepareListDisplayRows(display.FlattenSections(sections), workDir)
 
 	table := buildListTable(rows)
 
@@ -397,9 +541,30 @@ func printPackages(pkgs []formats.Package) {
 		_, _ = fmt.Fprint(warningWriter, warningsOut)
 	}
 
-	fmt.Println(table.HeaderRow())
-	fmt.Println(table.SeparatorRow())
+	sectionStart := 0
+	for i, section := range sections {
+		if listGroupByFlag.Enabled() {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(display.SectionTitle(listGroupByFlag, section.Key, len(section.Packages)))
+		}
+		fmt.Println(table.HeaderRow())
+		fmt.Println(table.SeparatorRow())
+
+		sectionRows := rows[sectionStart : sectionStart+len(section.Packages)]
+		sectionStart += len(section.Packages)
+		printListRows(table, sectionRows)
+	}
+	fmt.Printf("\nTotal packages: %d\n", len(pkgs))
+}
 
+// printListRows prints formatted list rows using a table formatter.
+//
+// Parameters:
+//   - table: Table formatter with calculated column widths
+//   - rows: Display rows to print
+func printListRows(table *output.Table, rows []listDisplayRow) {
 	for _, row := range rows {
 		fmt.Println(table.FormatRow(
 			row.pkg.Rule,
@@ -411,15 +576,16 @@ func printPackages(pkgs []formats.Package) {
 			row.statusDisplay,
 			row.pkg.Group,
 			row.pkg.Name,
+			row.source,
 		))
 	}
-	fmt.Printf("\nTotal packages: %d\n", len(pkgs))
 }
 
 // buildListTable creates a table formatter with calculated column widths.
 //
 // Initializes a table with package information columns, conditionally
-// including the GROUP column based on whether any packages have groups.
+// including the GROUP column based on whether any packages have groups and
+// the SOURCE column when any row has a source (--show-source).
 //
 // Parameters:
 //   - rows: Display rows to calculate widths from
@@ -434,6 +600,14 @@ func buildListTable(rows []listDisplayRow) *output.Table {
 	}
 	showGroup := output.ShouldShowGroupColumn(groups)
 
+	showSource := false
+	for _, row := range rows {
+		if row.source != "" {
+			showSource = true
+			break
+		}
+	}
+
 	table := output.NewTable().
 		AddColumn("RULE").
 		AddColumn("PM").
@@ -443,7 +617,8 @@ func buildListTable(rows []listDisplayRow) *output.Table {
 		AddColumn("INSTALLED").
 		AddColumn("STATUS").
 		AddConditionalColumn("GROUP", showGroup).
-		AddColumn("NAME")
+		AddColumn("NAME").
+		AddConditionalColumn("SOURCE", showSource)
 
 	for _, row := range rows {
 		table.UpdateWidths(
@@ -456,6 +631,7 @@ func buildListTable(rows []listDisplayRow) *output.Table {
 			row.statusDisplay,
 			row.pkg.Group,
 			row.pkg.Name,
+			row.source,
 		)
 	}
 
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/display"
+	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/filtering"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/lock"
@@ -410,7 +413,7 @@ func TestPrintPackagesDisplaysLatestFallback(t *testing.T) {
 	}}
 
 	output := captureStdout(t, func() {
-		printPackages(packages)
+		printPackages(packages, ".")
 	})
 
 	lines := strings.Split(output, "\n")
@@ -541,6 +544,388 @@ func TestRunListPrintsUnsupportedReasons(t *testing.T) {
 	assert.Contains(t, output, "Floating constraint")
 }
 
+// TestRunListOutdatedOnly tests the --outdated-only flag.
+//
+// It verifies:
+//   - Only packages with newer versions are listed
+//   - Floating packages are never looked up
+//   - A dedicated message is shown when every package is up to date
+//   - Lookup failures fail the command
+func TestRunListOutdatedOnly(t *testing.T) {
+	originalLoad := loadConfigFunc
+	originalGet := getPackagesFunc
+	originalApply := applyInstalledVersionsFunc
+	originalListNewer := listNewerVersionsFunc
+	originalType := listTypeFlag
+	originalPM := listPMFlag
+	originalDir := listDirFlag
+	originalConfig := listConfigFlag
+	originalOutdatedOnly := listOutdatedOnlyFlag
+	t.Cleanup(func() {
+		loadConfigFunc = originalLoad
+		getPackagesFunc = originalGet
+		applyInstalledVersionsFunc = originalApply
+		listNewerVersionsFunc = originalListNewer
+		listTypeFlag = originalType
+		listPMFlag = originalPM
+		listDirFlag = originalDir
+		listConfigFlag = originalConfig
+		listOutdatedOnlyFlag = originalOutdatedOnly
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{WorkingDir: ".", Rules: map[string]config.PackageManagerCfg{
+			"npm": {Manager: "js", Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+		}}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "behind", PackageType: "js", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "current", PackageType: "js", Type: "prod", Version: "2.0.0", InstalledVersion: "2.0.0", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "floating", PackageType: "js", Type: "prod", Version: "*", InstallStatus: lock.InstallStatusFloating},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listTypeFlag, listPMFlag, listDirFlag, listConfigFlag = "all", "all", ".", ""
+	listOutdatedOnlyFlag = true
+
+	var lookedUp []string
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		lookedUp = append(lookedUp, p.Name)
+		if p.Name == "behind" {
+			return []string{"1.1.0"}, nil
+		}
+		return nil, nil
+	}
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runList(listCmd, nil))
+	})
+	assert.Contains(t, out, "behind")
+	assert.NotContains(t, out, "current")
+	assert.Contains(t, out, "Total packages: 1")
+	assert.NotContains(t, lookedUp, "floating")
+
+	t.Run("nothing outdated", func(t *testing.T) {
+		listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			return nil, nil
+		}
+		out := captureStdout(t, func() {
+			require.NoError(t, runList(listCmd, nil))
+		})
+		assert.Contains(t, out, "No packages found with newer versions available")
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			return nil, fmt.Errorf("registry unreachable")
+		}
+		err := runList(listCmd, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "registry unreachable")
+	})
+}
+
+// TestRunListVersionRange tests the --version-range flag.
+//
+// It verifies:
+//   - Only packages whose installed version satisfies the range are listed
+//   - Packages without a semver installed version are excluded
+//   - An invalid range fails with a config exit code before loading config
+func TestRunListVersionRange(t *testing.T) {
+	originalLoad := loadConfigFunc
+	originalGet := getPackagesFunc
+	originalApply := applyInstalledVersionsFunc
+	originalType := listTypeFlag
+	originalPM := listPMFlag
+	originalDir := listDirFlag
+	originalConfig := listConfigFlag
+	originalRange := listVersionRangeFlag
+	t.Cleanup(func() {
+		loadConfigFunc = originalLoad
+		getPackagesFunc = originalGet
+		applyInstalledVersionsFunc = originalApply
+		listTypeFlag = originalType
+		listPMFlag = originalPM
+		listDirFlag = originalDir
+		listConfigFlag = originalConfig
+		listVersionRangeFlag = originalRange
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{WorkingDir: ".", Rules: map[string]config.PackageManagerCfg{"npm": {Manager: "js"}}}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "legacy", PackageType: "js", Type: "prod", Version: "^1.4.0", InstalledVersion: "1.4.2", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "modern", PackageType: "js", Type: "prod", Version: "^2.1.0", InstalledVersion: "2.1.0", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "unresolved", PackageType: "js", Type: "prod", Version: "*", InstallStatus: lock.InstallStatusFloating},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listTypeFlag, listPMFlag, listDirFlag, listConfigFlag = "all", "all", ".", ""
+
+	listVersionRangeFlag = "<2.0.0"
+	out := captureStdout(t, func() {
+		require.NoError(t, runList(listCmd, nil))
+	})
+	assert.Contains(t, out, "legacy")
+	assert.NotContains(t, out, "modern")
+	assert.NotContains(t, out, "unresolved")
+	assert.Contains(t, out, "Total packages: 1")
+
+	t.Run("invalid range", func(t *testing.T) {
+		loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+			t.Fatal("config should not load for an invalid range")
+			return nil, nil
+		}
+		listVersionRangeFlag = "<two"
+		err := runList(listCmd, nil)
+		require.Error(t, err)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+		_, ok := errors.IsValidationError(err)
+		assert.True(t, ok)
+	})
+}
+
+// TestRunListDrifted tests the --drifted flag.
+//
+// It verifies:
+//   - Only packages whose installed version does not satisfy the declaration are listed
+//   - Packages without an installed version are not reported as drifted
+func TestRunListDrifted(t *testing.T) {
+	originalLoad := loadConfigFunc
+	originalGet := getPackagesFunc
+	originalApply := applyInstalledVersionsFunc
+	originalType := listTypeFlag
+	originalPM := listPMFlag
+	originalDir := listDirFlag
+	originalConfig := listConfigFlag
+	originalDrifted := listDriftedFlag
+	t.Cleanup(func() {
+		loadConfigFunc = originalLoad
+		getPackagesFunc = originalGet
+		applyInstalledVersionsFunc = originalApply
+		listTypeFlag = originalType
+		listPMFlag = originalPM
+		listDirFlag = originalDir
+		listConfigFlag = originalConfig
+		listDriftedFlag = originalDrifted
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{WorkingDir: ".", Rules: map[string]config.PackageManagerCfg{"npm": {Manager: "js"}}}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "drifted-pkg", PackageType: "js", Type: "prod", Version: "1.4.0", Constraint: "^", InstalledVersion: "2.0.0", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "in-sync", PackageType: "js", Type: "prod", Version: "2.1.0", Constraint: "^", InstalledVersion: "2.3.0", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "not-installed", PackageType: "js", Type: "prod", Version: "1.0.0", Constraint: "^", InstallStatus: lock.InstallStatusNotInLock},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listTypeFlag, listPMFlag, listDirFlag, listConfigFlag = "all", "all", ".", ""
+
+	listDriftedFlag = true
+	out := captureStdout(t, func() {
+		require.NoError(t, runList(listCmd, nil))
+	})
+	assert.Contains(t, out, "drifted-pkg")
+	assert.NotContains(t, out, "in-sync")
+	assert.NotContains(t, out, "not-installed")
+	assert.Contains(t, out, "Total packages: 1")
+}
+
+// TestRunListSelect tests the --select flag.
+//
+// It verifies:
+//   - Only packages matching the expression are listed
+//   - Gap comparisons are rejected because list does not look up versions
+func TestRunListSelect(t *testing.T) {
+	originalLoad := loadConfigFunc
+	originalGet := getPackagesFunc
+	originalApply := applyInstalledVersionsFunc
+	originalType := listTypeFlag
+	originalPM := listPMFlag
+	originalDir := listDirFlag
+	originalConfig := listConfigFlag
+	originalSelect := listSelectFlag
+	t.Cleanup(func() {
+		loadConfigFunc = originalLoad
+		getPackagesFunc = originalGet
+		applyInstalledVersionsFunc = originalApply
+		listTypeFlag = originalType
+		listPMFlag = originalPM
+		listDirFlag = originalDir
+		listConfigFlag = originalConfig
+		listSelectFlag = originalSelect
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{WorkingDir: ".", Rules: map[string]config.PackageManagerCfg{"npm": {Manager: "js"}}}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "^17.0.0", InstalledVersion: "17.0.2", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "@types/react", PackageType: "js", Type: "dev", Version: "^17.0.0", InstalledVersion: "17.0.5", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "jest", PackageType: "js", Type: "dev", Version: "^29.0.0", InstalledVersion: "29.7.0", InstallStatus: lock.InstallStatusLockFound},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listTypeFlag, listPMFlag, listDirFlag, listConfigFlag = "all", "all", ".", ""
+
+	listSelectFlag = "type==prod || (name==@types/* && installed<18)"
+	out := captureStdout(t, func() {
+		require.NoError(t, runList(listCmd, nil))
+	})
+	assert.Contains(t, out, "react")
+	assert.Contains(t, out, "@types/react")
+	assert.NotContains(t, out, "jest")
+	assert.Contains(t, out, "Total packages: 2")
+
+	t.Run("gap needs lookups", func(t *testing.T) {
+		listSelectFlag = "gap>=major"
+		err := runList(listCmd, nil)
+		require.Error(t, err)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+		assert.Contains(t, err.Error(), "use it with outdated or update")
+	})
+}
+
+// TestRunListGroupBy tests the --group-by flag.
+//
+// It verifies:
+//   - Table output is split into sections with subheaders, ungrouped packages last
+//   - JSON output keys sections by group name
+func TestRunListGroupBy(t *testing.T) {
+	originalLoad := loadConfigFunc
+	originalGet := getPackagesFunc
+	originalApply := applyInstalledVersionsFunc
+	originalType := listTypeFlag
+	originalPM := listPMFlag
+	originalDir := listDirFlag
+	originalConfig := listConfigFlag
+	originalOutput := listOutputFlag
+	originalGroupBy := listGroupByFlag
+	t.Cleanup(func() {
+		loadConfigFunc = originalLoad
+		getPackagesFunc = originalGet
+		applyInstalledVersionsFunc = originalApply
+		listTypeFlag = originalType
+		listPMFlag = originalPM
+		listDirFlag = originalDir
+		listConfigFlag = originalConfig
+		listOutputFlag = originalOutput
+		listGroupByFlag = originalGroupBy
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{"npm": {
+				Manager: "js",
+				Groups:  map[string]config.GroupCfg{"ui": {Packages: []string{"react", "react-dom"}}},
+			}},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "18.2.0", InstalledVersion: "18.2.0", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "axios", PackageType: "js", Type: "prod", Version: "1.6.0", InstalledVersion: "1.6.0", InstallStatus: lock.InstallStatusLockFound},
+			{Rule: "npm", Name: "react-dom", PackageType: "js", Type: "prod", Version: "18.2.0", InstalledVersion: "18.2.0", InstallStatus: lock.InstallStatusLockFound},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listTypeFlag, listPMFlag, listDirFlag, listConfigFlag, listOutputFlag = "all", "all", ".", "", ""
+	require.NoError(t, listGroupByFlag.Set("group"))
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runList(listCmd, nil))
+	})
+	uiIdx := strings.Index(out, "group: ui (2 packages)")
+	ungroupedIdx := strings.Index(out, "group: ungrouped (1 package)")
+	assert.True(t, uiIdx >= 0 && uiIdx < ungroupedIdx, "ungrouped section should come last:\n%s", out)
+	assert.Contains(t, out, "Total packages: 3")
+
+	listOutputFlag = "json"
+	out = captureStdout(t, func() {
+		require.NoError(t, runList(listCmd, nil))
+	})
+	var result output.ListResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Equal(t, "group", result.GroupBy)
+	require.Len(t, result.Sections, 2)
+	assert.Equal(t, "ui", result.Sections[0].Name)
+	assert.Equal(t, 2, result.Sections[0].Summary.TotalPackages)
+	assert.Equal(t, "axios", result.Sections[1].Packages[0].Name)
+}
+
+// TestRunListVersionConflicts tests reporting packages declared at different versions.
+//
+// It verifies:
+//   - Conflicting declarations are listed with their versions and files in the console warnings
+//   - Structured output carries a VERSION_CONFLICT warning detail for the package
+func TestRunListVersionConflicts(t *testing.T) {
+	originalLoad := loadConfigFunc
+	originalGet := getPackagesFunc
+	originalApply := applyInstalledVersionsFunc
+	originalType := listTypeFlag
+	originalPM := listPMFlag
+	originalDir := listDirFlag
+	originalConfig := listConfigFlag
+	originalOutput := listOutputFlag
+	t.Cleanup(func() {
+		loadConfigFunc = originalLoad
+		getPackagesFunc = originalGet
+		applyInstalledVersionsFunc = originalApply
+		listTypeFlag = originalType
+		listPMFlag = originalPM
+		listDirFlag = originalDir
+		listConfigFlag = originalConfig
+		listOutputFlag = originalOutput
+	})
+
+	tmpDir := t.TempDir()
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{WorkingDir: tmpDir, Rules: map[string]config.PackageManagerCfg{"npm": {Manager: "js"}}}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "18.2.0", InstalledVersion: "18.2.0", InstallStatus: lock.InstallStatusLockFound, Source: filepath.Join(tmpDir, "web", "package.json")},
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.2", InstalledVersion: "17.0.2", InstallStatus: lock.InstallStatusLockFound, Source: filepath.Join(tmpDir, "admin", "package.json")},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listTypeFlag, listPMFlag, listDirFlag, listConfigFlag, listOutputFlag = "all", "all", tmpDir, "", ""
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runList(listCmd, nil))
+	})
+	assert.Contains(t, out, "react is declared at 2 different versions: 17.0.2 in admin/package.json, 18.2.0 in web/package.json")
+
+	listOutputFlag = "json"
+	out = captureStdout(t, func() {
+		require.NoError(t, runList(listCmd, nil))
+	})
+	var result output.ListResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	require.Len(t, result.WarningDetails, 1)
+	assert.Equal(t, warnings.CodeVersionConflict, result.WarningDetails[0].Code)
+	assert.Equal(t, "react (js/npm)", result.WarningDetails[0].PackageRef)
+}
+
 // TestRunListTracksUnsupported tests the behavior of unsupported package tracking.
 //
 // It verifies:
@@ -890,6 +1275,42 @@ func TestBuildListTable(t *testing.T) {
 	assert.GreaterOrEqual(t, table.GetColumnWidthByHeader("NAME"), 4)
 }
 
+// TestPrintPackagesShowSource tests the --show-source table column of list.
+//
+// It verifies:
+//   - Without --show-source there is no SOURCE column
+//   - With --show-source each row shows the manifest relative to the work directory and its line
+//   - Structured output always carries the source file and line
+func TestPrintPackagesShowSource(t *testing.T) {
+	original := listShowSourceFlag
+	t.Cleanup(func() { listShowSourceFlag = original })
+
+	workDir := t.TempDir()
+	pkgs := []formats.Package{
+		{Name: "react", Rule: "npm", PackageType: "js", Type: "prod", Version: "18.2.0", InstallStatus: "LockFound", Source: filepath.Join(workDir, "web", "package.json"), Line: 7},
+		{Name: "lodash", Rule: "npm", PackageType: "js", Type: "prod", Version: "4.17.21", InstallStatus: "LockFound", Source: filepath.Join(workDir, "package.json")},
+	}
+
+	listShowSourceFlag = false
+	out := captureStdout(t, func() { printPackages(pkgs, workDir) })
+	assert.NotContains(t, out, "SOURCE")
+
+	listShowSourceFlag = true
+	out = captureStdout(t, func() { printPackages(pkgs, workDir) })
+	assert.Contains(t, out, "SOURCE")
+	assert.Contains(t, out, "web/package.json:7")
+	assert.Regexp(t, `lodash\s+package\.json\s*\n`, out)
+
+	out = captureStdout(t, func() {
+		require.NoError(t, printListStructured(pkgs[:1], nil, nil, output.FormatJSON))
+	})
+	var parsed output.ListResult
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed))
+	require.Len(t, parsed.Packages, 1)
+	assert.Equal(t, filepath.Join(workDir, "web", "package.json"), parsed.Packages[0].Source)
+	assert.Equal(t, 7, parsed.Packages[0].Line)
+}
+
 // TestPrintWarnings tests the behavior of warning display.
 //
 // It verifies:
@@ -919,6 +1340,7 @@ func TestPrintWarnings(t *testing.T) {
 //   - JSON format produces valid JSON output
 //   - CSV format produces valid CSV output
 //   - XML format produces valid XML output
+//   - Classified warnings are included under warning_details next to the plain warnings
 func TestPrintListStructured(t *testing.T) {
 	pkgs := []formats.Package{
 		{
@@ -947,7 +1369,7 @@ func TestPrintListStructured(t *testing.T) {
 
 	t.Run("JSON format", func(t *testing.T) {
 		out := captureStdout(t, func() {
-			err := printListStructured(pkgs, []string{}, output.FormatJSON)
+			err := printListStructured(pkgs, []string{}, nil, output.FormatJSON)
 			require.NoError(t, err)
 		})
 		assert.Contains(t, out, `"name":"lodash"`)
@@ -957,7 +1379,7 @@ func TestPrintListStructured(t *testing.T) {
 
 	t.Run("CSV format", func(t *testing.T) {
 		out := captureStdout(t, func() {
-			err := printListStructured(pkgs, []string{}, output.FormatCSV)
+			err := printListStructured(pkgs, []string{}, nil, output.FormatCSV)
 			require.NoError(t, err)
 		})
 		assert.Contains(t, out, "lodash")
@@ -966,13 +1388,23 @@ func TestPrintListStructured(t *testing.T) {
 
 	t.Run("XML format", func(t *testing.T) {
 		out := captureStdout(t, func() {
-			err := printListStructured(pkgs, []string{"warning1"}, output.FormatXML)
+			err := printListStructured(pkgs, []string{"warning1"}, nil, output.FormatXML)
 			require.NoError(t, err)
 		})
 		assert.Contains(t, out, "<name>lodash</name>")
 		assert.Contains(t, out, "<name>express</name>")
 		assert.Contains(t, out, "<warning>warning1</warning>")
 	})
+
+	t.Run("JSON format with warning details", func(t *testing.T) {
+		details := []warnings.Warning{{Code: warnings.CodeLockMissing, Message: "no lock", PackageRef: "lodash (js/npm)"}}
+		out := captureStdout(t, func() {
+			err := printListStructured(pkgs, []string{"no lock"}, details, output.FormatJSON)
+			require.NoError(t, err)
+		})
+		assert.Contains(t, out, `"warnings":["no lock"]`)
+		assert.Contains(t, out, `"warning_details":[{"code":"LOCK_MISSING","message":"no lock","package":"lodash (js/npm)"}]`)
+	})
 }
 
 // TestMatchesGroupFilterAndFilterByGroup tests the behavior of group matching and filtering.
@@ -1195,7 +1627,7 @@ func TestPrepareListDisplayRowsEdgeCases(t *testing.T) {
 				InstallStatus: "LockFound",
 			},
 		}
-		rows, _, _ := prepareListDisplayRows(pkgs)
+		rows, _, _ := prepareListDisplayRows(pkgs, ".")
 		assert.Len(t, rows, 1)
 	})
 }
@@ -1233,7 +1665,7 @@ func TestPrintPackagesEdgeCases(t *testing.T) {
 			},
 		}
 		output := captureStdout(t, func() {
-			printPackages(pkgs)
+			printPackages(pkgs, ".")
 		})
 		assert.Contains(t, output, "mygroup")
 	})
@@ -1253,7 +1685,7 @@ func TestPrintPackagesEdgeCases(t *testing.T) {
 			},
 		}
 		output := captureStdout(t, func() {
-			printPackages(pkgs)
+			printPackages(pkgs, ".")
 		})
 		assert.Contains(t, output, "test")
 		assert.NotContains(t, output, "GROUP")
@@ -1461,7 +1893,7 @@ func TestPrepareListDisplayRowsWithWarnings(t *testing.T) {
 	restore := warnings.SetWarningWriter(&buf)
 	t.Cleanup(restore)
 
-	rows, warningsOut, _ := prepareListDisplayRows(pkgs)
+	rows, warningsOut, _ := prepareListDisplayRows(pkgs, ".")
 	assert.Len(t, rows, 1)
 	// Either warningsOut or the warning writer should capture the warning
 	_ = warningsOut // We just verify the function runs without panic
@@ -1490,7 +1922,7 @@ func TestPrintPackagesWithWarningsOutput(t *testing.T) {
 	}
 
 	out := captureStdout(t, func() {
-		printPackages(pkgs)
+		printPackages(pkgs, ".")
 	})
 
 	assert.Contains(t, out, "test-pkg-warn")
@@ -1726,7 +2158,7 @@ func TestPrintPackagesWithGroupColumn(t *testing.T) {
 	}
 
 	output := captureStdout(t, func() {
-		printPackages(pkgs)
+		printPackages(pkgs, ".")
 	})
 
 	// Should show GROUP column header (utils has 2+ packages)
@@ -1832,3 +2264,19 @@ func TestFilterPackagesByFile(t *testing.T) {
 		})
 	}
 }
+
+// TestRunListRejectsJUnitOutput tests that list rejects the JUnit format.
+//
+// It verifies:
+//   - runList returns an error before loading packages
+//   - The error names the list command
+func TestRunListRejectsJUnitOutput(t *testing.T) {
+	oldOutput := listOutputFlag
+	t.Cleanup(func() { listOutputFlag = oldOutput })
+
+	listOutputFlag = "junit"
+
+	err := runList(nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported by the list command")
+}
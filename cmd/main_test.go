@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/display"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+)
+
+// TestMain forces status icons for the cmd tests.
+//
+// Many tests run commands through rootCmd, whose PersistentPreRun resolves
+// --color. Output assertions expect icons, so the default auto mode (which
+// disables them when stdout is not a terminal) and NO_COLOR are overridden.
+//
+// The on-disk version cache is disabled so stubbed lookups never leak between
+// tests or into the user's cache directory; cache tests install their own.
+// Update runs do not record --since state in the test working directories,
+// and yanked version and repository lookups never reach a registry.
+func TestMain(m *testing.M) {
+	_ = os.Unsetenv("NO_COLOR")
+	colorFlag = display.ColorAlways
+	newVersionCacheFunc = func(time.Duration) *outdated.VersionCache { return nil }
+	saveRunStateFunc = func(string, time.Time) error { return nil }
+	listYankedVersionsFunc = func(context.Context, formats.Package, *config.Config, string) (outdated.YankedVersions, error) {
+		return outdated.YankedVersions{}, nil
+	}
+	lookupRepositoryFunc = func(context.Context, formats.Package, *config.Config, string) (string, error) {
+		return "", nil
+	}
+	os.Exit(m.Run())
+}
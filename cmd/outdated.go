@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/constants"
@@ -26,24 +27,57 @@ import (
 )
 
 var (
-	outdatedTypeFlag       string
-	outdatedPMFlag         string
-	outdatedRuleFlag       string
-	outdatedNameFlag       string
-	outdatedGroupFlag      string
-	outdatedConfigFlag     string
-	outdatedDirFlag        string
-	outdatedFileFlag       string
-	outdatedMajorFlag      bool
-	outdatedMinorFlag      bool
-	outdatedPatchFlag      bool
-	outdatedNoTimeoutFlag  bool
-	outdatedSkipPreflight  bool
-	outdatedContinueOnFail bool
-	outdatedOutputFlag     string
+	outdatedTypeFlag         string
+	outdatedPMFlag           string
+	outdatedRuleFlag         string
+	outdatedNameFlag         string
+	outdatedGroupFlag        string
+	outdatedConfigFlag       string
+	outdatedDirFlag          string
+	outdatedFileFlag         string
+	outdatedVersionRangeFlag string
+	outdatedDriftedFlag      bool
+	outdatedSelectFlag       string
+	outdatedMajorFlag        bool
+	outdatedMinorFlag        bool
+	outdatedPatchFlag        bool
+	outdatedNoTimeoutFlag    bool
+	outdatedSkipPreflight    bool
+	outdatedContinueOnFail   bool
+	outdatedOutputFlag       string
+	outdatedConcurrency      int
+	outdatedAgeFlag          bool
+	outdatedMinAgeFlag       int
+	outdatedGroupByFlag      = display.GroupByNone
+	outdatedCacheTTLFlag     time.Duration
+	outdatedNoCacheFlag      bool
+	outdatedSinceFlag        string
+	outdatedQuarantineDays   int
+	outdatedShowSourceFlag   bool
+	outdatedChangedSinceGit  string
+	outdatedAllowYankedFlag  bool
 )
 
-var listNewerVersionsFunc = outdated.ListNewerVersions
+var listNewerVersionsFunc = outdated.ListNewerVersionsWithRetry
+
+// listAllVersionsFunc lists every published version of a package; used to verify update --update-to
+var listAllVersionsFunc = outdated.ListAllVersionsWithRetry
+
+// newVersionCacheFunc opens the on-disk version lookup cache.
+// This allows for dependency injection during testing.
+var newVersionCacheFunc = outdated.NewVersionCache
+
+// listReleaseDatesFunc allows mocking release date lookups in tests
+var listReleaseDatesFunc = outdated.ListReleaseDates
+
+// listYankedVersionsFunc allows mocking yanked version lookups in tests
+var listYankedVersionsFunc = outdated.ListYankedVersions
+
+// lookupRepositoryFunc allows mocking repository URL lookups in tests
+var lookupRepositoryFunc = outdated.LookupRepository
+
+// releaseAgeNowFunc returns the reference time for release ages; tests pin it
+var releaseAgeNowFunc = time.Now
 
 // writeOutdatedResultFunc allows mocking structured output in tests
 var writeOutdatedResultFunc = output.WriteOutdatedResult
@@ -64,13 +98,27 @@ func init() {
 	outdatedCmd.Flags().StringVarP(&outdatedConfigFlag, "config", "c", "", "Config file path")
 	outdatedCmd.Flags().StringVarP(&outdatedDirFlag, "directory", "d", ".", "Directory to scan")
 	outdatedCmd.Flags().StringVarP(&outdatedFileFlag, "file", "f", "", "Filter by file path patterns (comma-separated, supports globs)")
+	outdatedCmd.Flags().StringVar(&outdatedChangedSinceGit, "changed-since-git", "", "Only include packages from manifests changed since a git ref (git diff <ref>...HEAD), e.g. origin/main")
+	outdatedCmd.Flags().StringVar(&outdatedVersionRangeFlag, "version-range", "", "Only include packages whose installed version satisfies a semver range (e.g. \"<2.0.0\")")
+	outdatedCmd.Flags().BoolVar(&outdatedDriftedFlag, "drifted", false, "Only include packages whose installed version does not satisfy the declared version")
+	outdatedCmd.Flags().StringVar(&outdatedSelectFlag, "select", "", "Only include packages matching an expression (e.g. \"rule==npm && type==prod || gap>=major\")")
 	outdatedCmd.Flags().BoolVar(&outdatedMajorFlag, "major", false, "Allow major, minor, and patch comparisons")
 	outdatedCmd.Flags().BoolVar(&outdatedMinorFlag, "minor", false, "Allow minor and patch comparisons")
 	outdatedCmd.Flags().BoolVar(&outdatedPatchFlag, "patch", false, "Restrict comparisons to patch scope")
+	outdatedCmd.Flags().BoolVar(&outdatedAllowYankedFlag, "allow-yanked", false, "Include versions the registry marks yanked, deprecated, or retracted")
 	outdatedCmd.Flags().BoolVar(&outdatedNoTimeoutFlag, "no-timeout", false, "Disable command timeouts")
 	outdatedCmd.Flags().BoolVar(&outdatedSkipPreflight, "skip-preflight", false, "Skip pre-flight command validation")
 	outdatedCmd.Flags().BoolVar(&outdatedContinueOnFail, "continue-on-fail", false, "Continue processing remaining packages after failures (exit code 1 for partial success)")
-	outdatedCmd.Flags().StringVarP(&outdatedOutputFlag, "output", "o", "", "Output format: json, csv, xml (default: table)")
+	outdatedCmd.Flags().StringVarP(&outdatedOutputFlag, "output", "o", "", "Output format: json, csv, xml, shields (default: table)")
+	outdatedCmd.Flags().IntVar(&outdatedConcurrency, "concurrency", outdated.DefaultConcurrency(), "Maximum number of concurrent version lookups")
+	outdatedCmd.Flags().BoolVar(&outdatedAgeFlag, "age", false, "Show how long the installed version has been superseded (looks up release dates)")
+	outdatedCmd.Flags().IntVar(&outdatedMinAgeFlag, "min-age", 0, "Only consider releases published at least N days ago (implies --age)")
+	outdatedCmd.Flags().IntVar(&outdatedQuarantineDays, "quarantine-days", 0, "Skip versions published less than N days ago (overrides the rule's quarantine_days; looks up release dates)")
+	outdatedCmd.Flags().StringVar(&outdatedSinceFlag, "since", "", "Only show versions released after an ISO date (2006-01-02) or \"last-run\" of update (looks up release dates)")
+	outdatedCmd.Flags().Var(&outdatedGroupByFlag, "group-by", "Split output into sections: rule, type, group, or none")
+	outdatedCmd.Flags().BoolVar(&outdatedShowSourceFlag, "show-source", false, "Add a SOURCE column with the manifest file and line declaring each package")
+	outdatedCmd.Flags().DurationVar(&outdatedCacheTTLFlag, "cache-ttl", outdated.DefaultCacheTTL, "How long cached version lookups are reused (e.g. 30m, 6h; 0 disables the cache)")
+	outdatedCmd.Flags().BoolVar(&outdatedNoCacheFlag, "no-cache", false, "Query registries without reading or writing the version cache")
 }
 
 // outdatedResult holds the result of checking a package for available updates.
@@ -83,6 +131,8 @@ type outdatedResult struct {
 	target        string
 	status        string
 	available     []string
+	age           string
+	source        string
 	err           error
 	latestMissing bool
 }
@@ -110,6 +160,23 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 	if err := output.ValidateStructuredOutputFlags(outputFormat, verboseFlag); err != nil {
 		return err
 	}
+	if err := output.ValidateUpdateOnlyFormat(outputFormat, "outdated"); err != nil {
+		return err
+	}
+	if outdatedMinAgeFlag < 0 {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--min-age cannot be negative"))
+	}
+	if outdatedQuarantineDays < 0 {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--quarantine-days cannot be negative"))
+	}
+	installedFilter := filtering.FilterOptions{VersionConstraint: outdatedVersionRangeFlag, DriftedOnly: outdatedDriftedFlag}
+	if err := installedFilter.Validate(); err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+	selector, err := filtering.ParseSelect(outdatedSelectFlag)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
 
 	collector := &display.WarningCollector{}
 	restoreWarnings := warnings.SetWarningWriter(collector)
@@ -127,6 +194,11 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 	cfg.WorkingDir = workDir
 	cfg.NoTimeout = outdatedNoTimeoutFlag
 
+	since, err := outdated.ParseSince(outdatedSinceFlag, workDir)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+
 	packages, err := getPackagesFunc(cfg, args, workDir)
 	if err != nil {
 		return err
@@ -136,14 +208,26 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 	if outdatedFileFlag != "" {
 		packages = filtering.FilterPackagesByFile(packages, outdatedFileFlag, workDir)
 	}
+	packages, err = filtering.FilterPackagesChangedSinceGit(packages, outdatedChangedSinceGit, workDir)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
 
 	packages = filtering.FilterPackagesWithFilters(packages, outdatedTypeFlag, outdatedPMFlag, outdatedRuleFlag, outdatedNameFlag, "")
 	packages, err = applyInstalledVersionsFunc(packages, cfg, workDir)
 	if err != nil {
 		return err
 	}
+	if installedFilter.HasInstalledFilter() {
+		packages = filtering.FilterPackages(packages, installedFilter)
+	}
 	packages = filtering.ApplyPackageGroups(packages, cfg)
 	packages = filtering.FilterByGroup(packages, outdatedGroupFlag)
+	// Expressions comparing gap need the latest version and are applied per row below
+	if !selector.NeedsLatest() {
+		packages = filtering.SelectPackages(packages, selector)
+	}
+	supervision.WarnVersionConflicts(packages, workDir)
 	for _, p := range packages {
 		if supervision.ShouldTrackUnsupported(p.InstallStatus) {
 			unsupported.Add(p, supervision.DeriveUnsupportedReason(p, cfg, nil, false))
@@ -152,7 +236,7 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 
 	if len(packages) == 0 {
 		if output.IsStructuredFormat(outputFormat) {
-			return printOutdatedStructured(nil, collector.Messages(), nil, outputFormat)
+			return printOutdatedStructured(nil, collector.Messages(), collector.Warnings(), nil, outputFormat, cfg.Badge)
 		}
 		display.PrintNoPackagesMessageWithFilters(os.Stdout, outdatedTypeFlag, outdatedPMFlag, outdatedRuleFlag)
 		return nil
@@ -163,11 +247,34 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 		validation := preflight.ValidatePackages(packages, cfg)
 		if validation.HasErrors() {
 			verbose.Infof("Exit code %d (config error): preflight validation failed - %s", errors.ExitConfigError, validation.ErrorMessage())
-			return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("%s\n  💡 Options:\n     --skip-preflight     Bypass validation if commands are available through other means\n     --rule <name>        Filter to specific rules (e.g., --rule npm)\n     enabled: false       Disable unused rules in your config file", validation.ErrorMessage()))
+			return errors.NewExitErrorf(errors.ExitConfigError, "%s\n  💡 Options:\n     --skip-preflight     Bypass validation if commands are available through other means\n     --rule <name>        Filter to specific rules (e.g., --rule npm)\n     enabled: false       Disable unused rules in your config file", validation.ErrorMessage()).WithCause(validation.Err())
 		}
 	}
 
-	ordered := filtering.SortPackagesForDisplay(packages)
+	sections := display.GroupPackages(packages, outdatedGroupByFlag)
+	ordered := display.FlattenSections(sections)
+
+	// Release dates cost an extra registry call per outdated package, so the AGE column is opt-in
+	showAge := outdatedAgeFlag || outdatedMinAgeFlag > 0
+	skippedAge := ""
+	if showAge {
+		skippedAge = constants.PlaceholderNA
+	}
+
+	// The SOURCE column is opt-in too, to keep the default table narrow
+	var sources []string
+	if outdatedShowSourceFlag {
+		sources = make([]string, len(ordered))
+		for i, p := range ordered {
+			sources[i] = display.FormatSource(p.Source, p.Line, workDir)
+		}
+	}
+	sourceOf := func(i int) string {
+		if sources == nil {
+			return ""
+		}
+		return sources[i]
+	}
 
 	// For structured output, suppress progress entirely (no stderr output)
 	// Progress messages are only shown in table (interactive) mode
@@ -177,20 +284,53 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 	var table *output.Table
 	if !useStructuredOutput {
 		// Calculate column widths from package data (before fetching versions)
-		table = buildOutdatedTableFromPackages(ordered)
+		table = buildOutdatedTableFromPackages(ordered, showAge, sources)
+
+		// Print header; grouped output prints one per section inside the loop
+		if !outdatedGroupByFlag.Enabled() {
+			fmt.Println(table.HeaderRow())
+			fmt.Println(table.SeparatorRow())
+		}
+	}
 
-		// Print header
-		fmt.Println(table.HeaderRow())
-		fmt.Println(table.SeparatorRow())
+	// Index of the first package of each section, for section subheaders
+	sectionStarts := make(map[int]display.PackageSection, len(sections))
+	start := 0
+	for _, section := range sections {
+		sectionStarts[start] = section
+		start += len(section.Packages)
 	}
 
 	results := make([]outdatedResult, 0, len(ordered))
 	var errs []error
 	selection := outdated.UpdateSelectionFlags{Major: outdatedMajorFlag, Minor: outdatedMinorFlag, Patch: outdatedPatchFlag}
 
-	for _, p := range ordered {
+	// Fan out version lookups; results are consumed below in display order
+	lister := outdated.WithoutYanked(outdatedVersionLister(), listYankedVersionsFunc, outdatedAllowYankedFlag)
+	lister = outdated.WithReleasedSince(lister, listReleaseDatesFunc, since)
+	lister = outdated.WithQuarantine(lister, listReleaseDatesFunc, quarantineOverride(cmd, outdatedQuarantineDays), releaseAgeNowFunc())
+	// Repository URLs are only reported in structured output
+	var repositories *outdated.RepositoryIndex
+	if useStructuredOutput {
+		repositories = outdated.NewRepositoryIndex()
+	}
+	lister = outdated.WithRepository(lister, lookupRepositoryFunc, repositories)
+	lookups := outdated.StartVersionLookups(context.Background(), ordered, cfg, workDir, outdatedConcurrency, lister, func(i int) bool {
+		return needsOutdatedLookup(ordered[i])
+	})
+
+	for i, p := range ordered {
 		ruleCfg := cfg.Rules[p.Rule]
 
+		if section, ok := sectionStarts[i]; ok && !useStructuredOutput && outdatedGroupByFlag.Enabled() {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(display.SectionTitle(outdatedGroupByFlag, section.Key, len(section.Packages)))
+			fmt.Println(table.HeaderRow())
+			fmt.Println(table.SeparatorRow())
+		}
+
 		// Skip outdated command for Ignored packages - they are excluded by config
 		if p.InstallStatus == lock.InstallStatusIgnored {
 			result := outdatedResult{
@@ -199,8 +339,13 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 				major:  constants.PlaceholderNA,
 				minor:  constants.PlaceholderNA,
 				patch:  constants.PlaceholderNA,
+				age:    skippedAge,
+				source: sourceOf(i),
 				status: lock.InstallStatusIgnored,
 			}
+			if !selector.Match(result.pkg) {
+				continue
+			}
 			results = append(results, result)
 			if useStructuredOutput {
 				progress.Increment()
@@ -210,16 +355,22 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		// Skip outdated command for Floating packages - they cannot be processed automatically
-		// because their constraints (*, x, ranges) make version comparison meaningless
-		if p.InstallStatus == lock.InstallStatusFloating {
+		// Skip outdated command for Floating and NonRegistry packages - they cannot be processed
+		// automatically because their constraints (*, x, ranges) make version comparison meaningless
+		// or they are installed from git/path sources with no registry versions
+		if p.InstallStatus == lock.InstallStatusFloating || p.InstallStatus == lock.InstallStatusNonRegistry {
 			result := outdatedResult{
 				pkg:    p,
 				group:  p.Group,
 				major:  constants.PlaceholderNA,
 				minor:  constants.PlaceholderNA,
 				patch:  constants.PlaceholderNA,
-				status: lock.InstallStatusFloating,
+				age:    skippedAge,
+				source: sourceOf(i),
+				status: p.InstallStatus,
+			}
+			if !selector.Match(result.pkg) {
+				continue
 			}
 			results = append(results, result)
 			if useStructuredOutput {
@@ -230,9 +381,16 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		versions, err := listNewerVersionsFunc(context.Background(), p, cfg, workDir)
+		versions, err := lookups.Get(i)
 
-		result := outdatedResult{pkg: p, group: p.Group, err: err, major: constants.PlaceholderNA, minor: constants.PlaceholderNA, patch: constants.PlaceholderNA, latestMissing: isLatestMissing(p, &ruleCfg)}
+		result := outdatedResult{pkg: p, group: p.Group, err: err, major: constants.PlaceholderNA, minor: constants.PlaceholderNA, patch: constants.PlaceholderNA, source: sourceOf(i), latestMissing: isLatestMissing(p, &ruleCfg)}
+		result.pkg.Repository = repositories.Get(p)
+		if showAge {
+			result.age = skippedAge
+			if err == nil && len(versions) > 0 && outdated.ReleaseDatesConfigured(p, cfg) {
+				versions, result.age = applyReleaseAge(p, cfg, workDir, versions)
+			}
+		}
 		if err == nil {
 			// For display, show ALL available versions (including major) without constraint filtering
 			// This ensures users see major updates even when their package uses ^ or ~ constraints
@@ -251,6 +409,7 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 					result.major = displayMajor
 					result.minor = displayMinor
 					result.patch = displayPatch
+					result.pkg.LatestVersion = newestAvailable(displayMajor, displayMinor, displayPatch)
 				}
 
 				targetMajor, targetMinor, targetPatch, targetSummarizeErr := outdated.SummarizeAvailableVersions(outdated.CurrentVersionForOutdated(p), targetFiltered, ruleCfg.Outdated.Versioning, incremental)
@@ -264,12 +423,22 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		// Rows whose lookup failed stay visible so the failure is not hidden by the filter
+		if result.err == nil && !selector.Match(result.pkg) {
+			continue
+		}
+
 		unsupportedErr := errors.IsUnsupported(err)
 		if unsupportedErr {
 			result.err = nil
 			result.status = lock.InstallStatusNotConfigured
 			unsupported.Add(p, supervision.DeriveUnsupportedReason(p, cfg, err, result.latestMissing))
 		} else {
+			if err == nil {
+				if reason, violated := outdated.PinViolation(p, cfg); violated {
+					unsupported.Add(p, reason)
+				}
+			}
 			result.status = deriveOutdatedStatus(result)
 			// Note: shouldTrackUnsupported is not checked here because deriveOutdatedStatus
 			// only returns Floating (handled earlier), Failed, Outdated, or UpToDate.
@@ -296,7 +465,7 @@ func runOutdated(cmd *cobra.Command, args []string) error {
 		for _, e := range errs {
 			errStrings = append(errStrings, e.Error())
 		}
-		if err := printOutdatedStructured(results, collector.Messages(), errStrings, outputFormat); err != nil {
+		if err := printOutdatedStructured(results, collector.Messages(), warningDetails(collector, packages), errStrings, outputFormat, cfg.Badge); err != nil {
 			return err
 		}
 	} else {
@@ -359,21 +528,22 @@ func getOutdatedOutputFormat() output.Format {
 //
 // Converts results to structured output format with package information,
 // version availability, and status. Includes summary counts for outdated,
-// up-to-date, and failed packages.
+// up-to-date, and failed packages. With --group-by, the packages are also
+// split into named sections, each with its own summary.
 //
 // Parameters:
 //   - results: Outdated check results to output
 //   - warnings: Warning messages to include
+//   - details: Classified warnings to include
 //   - errs: Error messages to include
-//   - format: Output format (JSON, CSV, or XML)
+//   - format: Output format (JSON, CSV, XML, or Shields)
+//   - badge: Badge label and colors for the shields format; nil uses the defaults
 //
 // Returns:
 //   - error: Returns error on output failure
-func printOutdatedStructured(results []outdatedResult, warnings []string, errs []string, format output.Format) error {
+func printOutdatedStructured(results []outdatedResult, warnings []string, details []warnings.Warning, errs []string, format output.Format, badge *config.BadgeCfg) error {
 	packages := make([]output.OutdatedPackage, 0, len(results))
-
-	var outdatedCount, uptodateCount, failedCount int
-	var hasMajor, hasMinor, hasPatch int
+	var sections output.OutdatedSections
 
 	for _, res := range results {
 		constraintDisplay := display.FormatConstraintDisplayWithFlags(res.pkg, outdatedMajorFlag, outdatedMinorFlag, outdatedPatchFlag)
@@ -393,51 +563,210 @@ func printOutdatedStructured(results []outdatedResult, warnings []string, errs [
 			Major:            res.major,
 			Minor:            res.minor,
 			Patch:            res.patch,
+			Age:              res.age,
 			Status:           res.status,
 			Group:            res.group,
 			Name:             res.pkg.Name,
+			Source:           res.pkg.Source,
+			Line:             res.pkg.Line,
+			Repository:       res.pkg.Repository,
 			Error:            errStr,
 		})
 
+		// Results arrive in section order, so a new key starts a new section
+		if outdatedGroupByFlag.Enabled() {
+			key := display.SectionKey(res.pkg, outdatedGroupByFlag)
+			if len(sections) == 0 || sections[len(sections)-1].Name != key {
+				sections = append(sections, output.OutdatedSection{Name: key})
+			}
+			last := &sections[len(sections)-1]
+			last.Packages = append(last.Packages, packages[len(packages)-1])
+		}
+	}
+
+	for i := range sections {
+		sections[i].Summary = summarizeOutdatedPackages(sections[i].Packages)
+	}
+
+	result := &output.OutdatedResult{
+		Summary:        summarizeOutdatedPackages(packages),
+		Packages:       packages,
+		Sections:       sections,
+		Warnings:       warnings,
+		Errors:         errs,
+		WarningDetails: details,
+	}
+	if outdatedGroupByFlag.Enabled() {
+		result.GroupBy = string(outdatedGroupByFlag)
+	}
+
+	if format == output.FormatShields {
+		return output.WriteShieldsBadge(os.Stdout, newOutdatedBadge(result.Summary.OutdatedPackages, badge))
+	}
+
+	return writeOutdatedResultFunc(os.Stdout, format, result)
+}
+
+// newOutdatedBadge builds the shields.io badge for an outdated count.
+//
+// Parameters:
+//   - count: Number of outdated packages after filtering
+//   - badge: Configured label and color thresholds; nil uses the defaults
+//
+// Returns:
+//   - output.ShieldsBadge: The badge to write
+func newOutdatedBadge(count int, badge *config.BadgeCfg) output.ShieldsBadge {
+	if badge == nil {
+		return output.NewShieldsBadge(count, "", nil)
+	}
+
+	colors := make([]output.ShieldsColor, 0, len(badge.Colors))
+	for _, c := range badge.Colors {
+		colors = append(colors, output.ShieldsColor{Min: c.Min, Color: c.Color})
+	}
+	return output.NewShieldsBadge(count, badge.Label, colors)
+}
+
+// summarizeOutdatedPackages counts outdated, up-to-date, and failed packages.
+//
+// Parameters:
+//   - packages: Structured outdated entries
+//
+// Returns:
+//   - output.OutdatedSummary: Totals and counts of available major, minor, and patch updates
+func summarizeOutdatedPackages(packages []output.OutdatedPackage) output.OutdatedSummary {
+	summary := output.OutdatedSummary{TotalPackages: len(packages)}
+
+	for _, pkg := range packages {
 		// Count packages with available updates by type
-		if res.major != constants.PlaceholderNA {
-			hasMajor++
+		if pkg.Major != constants.PlaceholderNA {
+			summary.HasMajor++
 		}
-		if res.minor != constants.PlaceholderNA {
-			hasMinor++
+		if pkg.Minor != constants.PlaceholderNA {
+			summary.HasMinor++
 		}
-		if res.patch != constants.PlaceholderNA {
-			hasPatch++
+		if pkg.Patch != constants.PlaceholderNA {
+			summary.HasPatch++
 		}
 
-		switch res.status {
+		switch pkg.Status {
 		case outdatedStatusOutdated:
-			outdatedCount++
+			summary.OutdatedPackages++
 		case outdatedStatusUpToDate:
-			uptodateCount++
+			summary.UpToDatePackages++
 		default:
-			if res.err != nil || strings.HasPrefix(res.status, outdatedStatusFailed) {
-				failedCount++
+			if pkg.Error != "" || strings.HasPrefix(pkg.Status, outdatedStatusFailed) {
+				summary.FailedPackages++
 			}
 		}
 	}
 
-	result := &output.OutdatedResult{
-		Summary: output.OutdatedSummary{
-			TotalPackages:    len(packages),
-			OutdatedPackages: outdatedCount,
-			UpToDatePackages: uptodateCount,
-			FailedPackages:   failedCount,
-			HasMajor:         hasMajor,
-			HasMinor:         hasMinor,
-			HasPatch:         hasPatch,
-		},
-		Packages: packages,
-		Warnings: warnings,
-		Errors:   errs,
+	return summary
+}
+
+// applyReleaseAge looks up release dates for a package's newer versions.
+//
+// Versions published less than --min-age days ago are dropped, and the age of
+// the oldest remaining version is formatted for the AGE column. When the
+// lookup fails a warning is recorded and the versions are returned unchanged.
+//
+// Parameters:
+//   - p: Package whose versions were looked up
+//   - cfg: The global configuration
+//   - workDir: Base directory for command execution
+//   - versions: Newer versions returned by the version lookup
+//
+// Returns:
+//   - []string: Versions that pass the --min-age filter
+//   - string: Formatted age, or "#N/A" when no publish date is known
+func applyReleaseAge(p formats.Package, cfg *config.Config, workDir string, versions []string) ([]string, string) {
+	dates, err := listReleaseDatesFunc(context.Background(), p, cfg, workDir)
+	if err != nil {
+		warnings.Warn(warnings.CodeReleaseDatesUnavailable, warnings.PackageRef(p.Name, p.PackageType, p.Rule), "⚠️ release dates unavailable for %s: %v", p.Name, err)
+		return versions, constants.PlaceholderNA
 	}
 
-	return writeOutdatedResultFunc(os.Stdout, format, result)
+	now := releaseAgeNowFunc()
+	minAge := time.Duration(outdatedMinAgeFlag) * 24 * time.Hour
+	candidates := outdated.FilterCandidatesByMinAge(dates.Candidates(versions), minAge, now)
+	if excluded := len(versions) - len(candidates); excluded > 0 {
+		verbose.Infof("Excluded %d release(s) of %s published within the last %d days", excluded, p.Name, outdatedMinAgeFlag)
+	}
+
+	age := constants.PlaceholderNA
+	if superseded, ok := outdated.SupersededAge(candidates, now); ok {
+		age = display.FormatAge(superseded)
+	}
+
+	return outdated.CandidateVersions(candidates), age
+}
+
+// quarantineOverride returns the --quarantine-days window passed to outdated.WithQuarantine.
+//
+// An explicit --quarantine-days 0 is returned as a zero window, which turns
+// off the rule's quarantine_days.
+//
+// Parameters:
+//   - command: The running command; may be nil
+//   - days: The flag value
+//
+// Returns:
+//   - *int: The window; nil when the flag is unset so each rule's quarantine_days applies
+func quarantineOverride(command *cobra.Command, days int) *int {
+	if !quarantineDaysSet(command) {
+		return nil
+	}
+	return &days
+}
+
+// quarantineDaysSet reports whether --quarantine-days was passed on the command line.
+//
+// Parameters:
+//   - command: The running command; may be nil
+//
+// Returns:
+//   - bool: true if the flag was set, including an explicit 0
+func quarantineDaysSet(command *cobra.Command) bool {
+	return command != nil && command.Flags().Changed("quarantine-days")
+}
+
+// outdatedVersionLister returns the version lister used by the outdated command.
+//
+// Lookups are served from the on-disk cache for --cache-ttl unless --no-cache
+// is set or the TTL is zero.
+//
+// Returns:
+//   - outdated.ListNewerVersionsFunc: listNewerVersionsFunc, wrapped with the cache when enabled
+func outdatedVersionLister() outdated.ListNewerVersionsFunc {
+	if outdatedNoCacheFlag {
+		return listNewerVersionsFunc
+	}
+	return outdated.WithCache(listNewerVersionsFunc, newVersionCacheFunc(outdatedCacheTTLFlag))
+}
+
+// needsOutdatedLookup reports whether a package requires a version lookup.
+//
+// Ignored, floating, and non-registry packages are displayed without querying the registry.
+//
+// Parameters:
+//   - p: Package to check
+//
+// Returns:
+//   - bool: True if the outdated command should run for the package
+func needsOutdatedLookup(p formats.Package) bool {
+	return p.InstallStatus != lock.InstallStatusIgnored && p.InstallStatus != lock.InstallStatusFloating &&
+		p.InstallStatus != lock.InstallStatusNonRegistry
+}
+
+// newestAvailable returns the first real version among the major, minor, and
+// patch summary columns, which is the newest one found; empty when all are #N/A.
+func newestAvailable(major, minor, patch string) string {
+	for _, v := range []string{major, minor, patch} {
+		if v != "" && v != constants.PlaceholderNA {
+			return v
+		}
+	}
+	return ""
 }
 
 // isLatestMissing checks if a package declared as "latest" has no resolved version.
@@ -455,7 +784,7 @@ func isLatestMissing(p formats.Package, ruleCfg *config.PackageManagerCfg) bool
 // deriveOutdatedStatus determines the display status for an outdated check result.
 //
 // Returns status based on available updates (Outdated), errors (Failed),
-// floating constraints (Floating), or no updates available (UpToDate).
+// floating constraints (Floating), non-registry sources (NonRegistry), or no updates available (UpToDate).
 //
 // Parameters:
 //   - res: Outdated check result
@@ -465,8 +794,8 @@ func isLatestMissing(p formats.Package, ruleCfg *config.PackageManagerCfg) bool
 func deriveOutdatedStatus(res outdatedResult) string {
 	// Preserve Floating status - these packages cannot be processed automatically
 	// because their constraints (*, x, ranges) make version comparison meaningless
-	if res.pkg.InstallStatus == lock.InstallStatusFloating {
-		return lock.InstallStatusFloating
+	if res.pkg.InstallStatus == lock.InstallStatusFloating || res.pkg.InstallStatus == lock.InstallStatusNonRegistry {
+		return res.pkg.InstallStatus
 	}
 
 	if res.err != nil {
@@ -491,6 +820,8 @@ type outdatedDisplayRow struct {
 	major             string
 	minor             string
 	patch             string
+	age               string
+	source            string
 	target            string
 	group             string
 }
@@ -517,6 +848,8 @@ func prepareOutdatedDisplayRows(results []outdatedResult) []outdatedDisplayRow {
 			major:             res.major,
 			minor:             res.minor,
 			patch:             res.patch,
+			age:               res.age,
+			source:            res.source,
 			target:            display.SafeVersionValue(res.target, constants.PlaceholderNA),
 			group:             res.group,
 		})
@@ -549,9 +882,11 @@ func printOutdatedResults(results []outdatedResult, typeFlag, pmFlag string) {
 			row.major,
 			row.minor,
 			row.patch,
+			row.age,
 			row.statusDisplay,
 			row.group,
 			row.pkg.Name,
+			row.source,
 		))
 	}
 
@@ -561,7 +896,9 @@ func printOutdatedResults(results []outdatedResult, typeFlag, pmFlag string) {
 // buildOutdatedTable creates a table formatter with calculated column widths.
 //
 // Initializes a table with package and version columns including MAJOR, MINOR,
-// and PATCH columns for available updates. Conditionally includes GROUP column.
+// and PATCH columns for available updates. Conditionally includes the AGE
+// column (when any row has an age), the GROUP column, and the SOURCE column
+// (when any row has a source).
 //
 // Parameters:
 //   - rows: Display rows to calculate widths from
@@ -576,6 +913,13 @@ func buildOutdatedTable(rows []outdatedDisplayRow) *output.Table {
 	}
 	showGroup := output.ShouldShowGroupColumn(groups)
 
+	showAge := false
+	showSource := false
+	for _, row := range rows {
+		showAge = showAge || row.age != ""
+		showSource = showSource || row.source != ""
+	}
+
 	table := output.NewTable().
 		AddColumn("RULE").
 		AddColumn("PM").
@@ -586,9 +930,11 @@ func buildOutdatedTable(rows []outdatedDisplayRow) *output.Table {
 		AddColumn("MAJOR").
 		AddColumn("MINOR").
 		AddColumn("PATCH").
+		AddConditionalColumn("AGE", showAge).
 		AddColumn("STATUS").
 		AddConditionalColumn("GROUP", showGroup).
-		AddColumn("NAME")
+		AddColumn("NAME").
+		AddConditionalColumn("SOURCE", showSource)
 
 	for _, row := range rows {
 		table.UpdateWidths(
@@ -601,9 +947,11 @@ func buildOutdatedTable(rows []outdatedDisplayRow) *output.Table {
 			row.major,
 			row.minor,
 			row.patch,
+			row.age,
 			row.statusDisplay,
 			row.group,
 			row.pkg.Name,
+			row.source,
 		)
 	}
 
@@ -618,10 +966,12 @@ func buildOutdatedTable(rows []outdatedDisplayRow) *output.Table {
 //
 // Parameters:
 //   - packages: Packages to calculate base widths from
+//   - showAge: Whether to include the AGE column
+//   - sources: SOURCE column values aligned with packages; nil hides the column
 //
 // Returns:
 //   - *output.Table: Configured table formatter with reserved column widths
-func buildOutdatedTableFromPackages(packages []formats.Package) *output.Table {
+func buildOutdatedTableFromPackages(packages []formats.Package, showAge bool, sources []string) *output.Table {
 	// Extract groups to determine if GROUP column should be shown
 	groups := make([]string, len(packages))
 	for i, p := range packages {
@@ -636,14 +986,20 @@ func buildOutdatedTableFromPackages(packages []formats.Package) *output.Table {
 		AddColumn("CONSTRAINT").
 		AddColumn("VERSION").
 		AddColumn("INSTALLED").
-		AddColumnWithMinWidth("MAJOR", 12).  // Reserve space for version numbers
-		AddColumnWithMinWidth("MINOR", 12).  // Reserve space for version numbers
-		AddColumnWithMinWidth("PATCH", 12).  // Reserve space for version numbers
-		AddColumnWithMinWidth("STATUS", 14). // Reserve space for "🔴 Unsupported"
+		AddColumnWithMinWidth("MAJOR", 12).   // Reserve space for version numbers
+		AddColumnWithMinWidth("MINOR", 12).   // Reserve space for version numbers
+		AddColumnWithMinWidth("PATCH", 12).   // Reserve space for version numbers
+		AddConditionalColumn("AGE", showAge). // Only with --age or --min-age
+		AddColumnWithMinWidth("STATUS", 14).  // Reserve space for "🔴 Unsupported"
 		AddConditionalColumn("GROUP", showGroup).
-		AddColumn("NAME")
+		AddColumn("NAME").
+		AddConditionalColumn("SOURCE", sources != nil) // Only with --show-source
 
-	for _, p := range packages {
+	for i, p := range packages {
+		source := ""
+		if sources != nil {
+			source = sources[i]
+		}
 		table.UpdateWidths(
 			p.Rule,
 			p.PackageType,
@@ -651,9 +1007,10 @@ func buildOutdatedTableFromPackages(packages []formats.Package) *output.Table {
 			display.FormatConstraintDisplayWithFlags(p, outdatedMajorFlag, outdatedMinorFlag, outdatedPatchFlag),
 			display.SafeDeclaredValue(p.Version),
 			display.SafeInstalledValue(p.InstalledVersion),
-			"", "", "", "", // Placeholders for MAJOR, MINOR, PATCH, STATUS (will use min widths)
+			"", "", "", constants.PlaceholderNA, "", // Placeholders for MAJOR, MINOR, PATCH, AGE, STATUS (will use min widths)
 			p.Group,
 			p.Name,
+			source,
 		)
 	}
 
@@ -679,9 +1036,11 @@ func printOutdatedRowWithTable(res outdatedResult, table *output.Table) {
 		res.major,
 		res.minor,
 		res.patch,
+		res.age,
 		display.FormatStatusWithIcon(res.status),
 		res.group,
 		res.pkg.Name,
+		res.source,
 	))
 }
 
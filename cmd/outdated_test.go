@@ -2,13 +2,16 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	stderrors "errors"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/constants"
@@ -16,7 +19,9 @@ import (
 	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/outdated"
 	"github.com/ajxudir/goupdate/pkg/output"
+	"github.com/ajxudir/goupdate/pkg/warnings"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -316,6 +321,868 @@ func TestRunOutdatedFloatingPackage(t *testing.T) {
 	assert.Contains(t, out, "Floating")
 }
 
+// TestRunOutdatedBrewfile tests outdated reports of Brewfile formulae with the built-in brew rule.
+//
+// It verifies:
+//   - Unversioned formulae are marked Floating and never looked up
+//   - The installed version from brew list is still shown so drift is visible
+//   - Formulae are reported as unsupported with the rule's unpinned reason
+func TestRunOutdatedBrewfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Brewfile"), []byte("brew \"git\"\nbrew \"wget\"\n"), 0o644))
+
+	oldLoad := loadConfigFunc
+	oldListNewer := listNewerVersionsFunc
+	oldDir := outdatedDirFlag
+	oldConfig := outdatedConfigFlag
+	oldSkip := outdatedSkipPreflight
+	oldFormat := outdatedOutputFlag
+	defer func() {
+		loadConfigFunc = oldLoad
+		listNewerVersionsFunc = oldListNewer
+		outdatedDirFlag = oldDir
+		outdatedConfigFlag = oldConfig
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldFormat
+	}()
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		cfg, err := config.LoadConfig(path, workDir)
+		if err != nil {
+			return nil, err
+		}
+		rule := cfg.Rules["brew"]
+		lockFile := rule.LockFiles[0]
+		lockFile.Commands = "printf 'git 2.43.0\\n'"
+		rule.LockFiles = []config.LockFileCfg{lockFile}
+		cfg.Rules = map[string]config.PackageManagerCfg{"brew": rule}
+		return cfg, nil
+	}
+	lookups := 0
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		lookups++
+		return nil, nil
+	}
+
+	outdatedDirFlag = tmpDir
+	outdatedConfigFlag = ""
+	outdatedSkipPreflight = true
+	outdatedOutputFlag = ""
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runOutdated(nil, nil))
+	})
+
+	assert.Zero(t, lookups)
+	assert.Contains(t, out, "2.43.0")
+	assert.Contains(t, out, "Floating")
+	assert.Contains(t, out, "Brewfile entries are unpinned; goupdate reports drift only.")
+}
+
+// TestRunOutdatedConcurrentLookups tests outdated with concurrent version lookups.
+//
+// It verifies:
+//   - Every package is looked up exactly once
+//   - Output order matches display order regardless of lookup completion
+//   - Per-package errors are still reported
+func TestRunOutdatedConcurrentLookups(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldDir := outdatedDirFlag
+	oldConfig := outdatedConfigFlag
+	oldSkip := outdatedSkipPreflight
+	oldOutput := outdatedOutputFlag
+	oldContinue := outdatedContinueOnFail
+	oldConcurrency := outdatedConcurrency
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		outdatedDirFlag = oldDir
+		outdatedConfigFlag = oldConfig
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldOutput
+		outdatedContinueOnFail = oldContinue
+		outdatedConcurrency = oldConcurrency
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager:  "js",
+					Outdated: &config.OutdatedCfg{Commands: "echo ok"},
+				},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "zeta", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "alpha", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "mid", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	var mu sync.Mutex
+	calls := map[string]int{}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		mu.Lock()
+		calls[p.Name]++
+		mu.Unlock()
+		if p.Name == "alpha" {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if p.Name == "mid" {
+			return nil, stderrors.New("version check failed")
+		}
+		return []string{"2.0.0"}, nil
+	}
+
+	outdatedDirFlag = "."
+	outdatedConfigFlag = ""
+	outdatedSkipPreflight = true
+	outdatedOutputFlag = "json"
+	outdatedContinueOnFail = true
+	outdatedConcurrency = 3
+
+	out := captureStdout(t, func() {
+		err := runOutdated(nil, nil)
+		assert.Error(t, err)
+	})
+
+	assert.Equal(t, map[string]int{"alpha": 1, "mid": 1, "zeta": 1}, calls)
+	alphaIdx := strings.Index(out, `"alpha"`)
+	midIdx := strings.Index(out, `"mid"`)
+	zetaIdx := strings.Index(out, `"zeta"`)
+	assert.True(t, alphaIdx >= 0 && alphaIdx < midIdx && midIdx < zetaIdx, "packages should be in display order")
+	assert.Contains(t, out, "version check failed")
+}
+
+// TestRunOutdatedGroupBy tests the --group-by flag.
+//
+// It verifies:
+//   - Table output prints a subheader and header rows per section
+//   - JSON output adds group_by and per-section summaries keyed by section name
+//   - The flat packages list is still included
+func TestRunOutdatedGroupBy(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldDir := outdatedDirFlag
+	oldConfig := outdatedConfigFlag
+	oldSkip := outdatedSkipPreflight
+	oldOutput := outdatedOutputFlag
+	oldGroupBy := outdatedGroupByFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		outdatedDirFlag = oldDir
+		outdatedConfigFlag = oldConfig
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldOutput
+		outdatedGroupByFlag = oldGroupBy
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+				"mod": {Manager: "golang", Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "react", Rule: "npm", PackageType: "js", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "jest", Rule: "npm", PackageType: "js", Type: "dev", Version: "2.0.0", InstalledVersion: "2.0.0"},
+			{Name: "github.com/spf13/cobra", Rule: "mod", PackageType: "golang", Type: "prod", Version: "v1.0.0", InstalledVersion: "v1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Name == "react" {
+			return []string{"1.1.0"}, nil
+		}
+		return nil, nil
+	}
+
+	outdatedDirFlag = "."
+	outdatedConfigFlag = ""
+	outdatedSkipPreflight = true
+	require.NoError(t, outdatedGroupByFlag.Set("rule"))
+
+	outdatedOutputFlag = ""
+	out := captureStdout(t, func() {
+		require.NoError(t, runOutdated(nil, nil))
+	})
+	modIdx := strings.Index(out, "rule: mod (1 package)")
+	npmIdx := strings.Index(out, "rule: npm (2 packages)")
+	assert.True(t, modIdx >= 0 && modIdx < npmIdx, "sections should be ordered by key:\n%s", out)
+	assert.Equal(t, 2, strings.Count(out, "NAME"), "each section has its own header row")
+
+	outdatedOutputFlag = "json"
+	out = captureStdout(t, func() {
+		require.NoError(t, runOutdated(nil, nil))
+	})
+	var result output.OutdatedResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Equal(t, "rule", result.GroupBy)
+	assert.Len(t, result.Packages, 3)
+	require.Len(t, result.Sections, 2)
+	assert.Equal(t, "mod", result.Sections[0].Name)
+	assert.Equal(t, "npm", result.Sections[1].Name)
+	assert.Equal(t, 2, result.Sections[1].Summary.TotalPackages)
+	assert.Equal(t, 1, result.Sections[1].Summary.OutdatedPackages)
+}
+
+// TestRunOutdatedSelect tests the --select flag.
+//
+// It verifies:
+//   - Expressions without gap filter packages before the version lookup
+//   - Gap comparisons are evaluated against the newest available version
+//   - An invalid expression is a config error
+func TestRunOutdatedSelect(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldDir := outdatedDirFlag
+	oldConfig := outdatedConfigFlag
+	oldSkip := outdatedSkipPreflight
+	oldOutput := outdatedOutputFlag
+	oldSelect := outdatedSelectFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		outdatedDirFlag = oldDir
+		outdatedConfigFlag = oldConfig
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldOutput
+		outdatedSelectFlag = oldSelect
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+				"mod": {Manager: "golang", Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "react", Rule: "npm", PackageType: "js", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "jest", Rule: "npm", PackageType: "js", Type: "dev", Version: "2.0.0", InstalledVersion: "2.0.0"},
+			{Name: "github.com/spf13/cobra", Rule: "mod", PackageType: "golang", Type: "prod", Version: "v1.0.0", InstalledVersion: "v1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	var looked []string
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		looked = append(looked, p.Name)
+		switch p.Name {
+		case "react":
+			return []string{"2.0.0"}, nil
+		case "jest":
+			return []string{"2.0.1"}, nil
+		}
+		return nil, nil
+	}
+
+	outdatedDirFlag = "."
+	outdatedConfigFlag = ""
+	outdatedSkipPreflight = true
+	outdatedOutputFlag = "json"
+
+	names := func(expr string) []string {
+		outdatedSelectFlag = expr
+		out := captureStdout(t, func() {
+			require.NoError(t, runOutdated(nil, nil))
+		})
+		var result output.OutdatedResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		var got []string
+		for _, p := range result.Packages {
+			got = append(got, p.Name)
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"github.com/spf13/cobra"}, names("rule==mod"))
+	assert.Equal(t, []string{"github.com/spf13/cobra"}, looked, "non-matching packages are not looked up")
+
+	assert.ElementsMatch(t, []string{"react", "jest"}, names("gap>=major || type==dev"))
+	assert.Equal(t, []string{"jest"}, names("gap==patch"))
+
+	outdatedSelectFlag = "gap>=huge"
+	err := runOutdated(nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+}
+
+// TestRunOutdatedRepository tests reporting repository URLs in structured output.
+//
+// It verifies:
+//   - JSON output carries the repository found for each looked up package
+//   - Table output does not look up repositories
+func TestRunOutdatedRepository(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldLookup := lookupRepositoryFunc
+	oldDir := outdatedDirFlag
+	oldConfig := outdatedConfigFlag
+	oldSkip := outdatedSkipPreflight
+	oldOutput := outdatedOutputFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		lookupRepositoryFunc = oldLookup
+		outdatedDirFlag = oldDir
+		outdatedConfigFlag = oldConfig
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldOutput
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules:      map[string]config.PackageManagerCfg{"npm": {Manager: "js", Outdated: &config.OutdatedCfg{Commands: "echo ok"}}},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "react", Rule: "npm", PackageType: "js", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "internal-lib", Rule: "npm", PackageType: "js", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"2.0.0"}, nil
+	}
+	var looked []string
+	lookupRepositoryFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (string, error) {
+		looked = append(looked, p.Name)
+		if p.Name == "react" {
+			return "https://github.com/facebook/react", nil
+		}
+		return "", nil
+	}
+
+	outdatedDirFlag = "."
+	outdatedConfigFlag = ""
+	outdatedSkipPreflight = true
+	outdatedOutputFlag = "json"
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runOutdated(nil, nil))
+	})
+	var result output.OutdatedResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	require.Len(t, result.Packages, 2)
+	repositories := map[string]string{}
+	for _, p := range result.Packages {
+		repositories[p.Name] = p.Repository
+	}
+	assert.Equal(t, map[string]string{"react": "https://github.com/facebook/react", "internal-lib": ""}, repositories)
+	assert.NotContains(t, out, `"repository": ""`)
+
+	looked = nil
+	outdatedOutputFlag = ""
+	captureStdout(t, func() {
+		require.NoError(t, runOutdated(nil, nil))
+	})
+	assert.Empty(t, looked)
+}
+
+// TestRunOutdatedVersionCache tests the --cache-ttl and --no-cache flags.
+//
+// It verifies:
+//   - A second run within the TTL reuses cached lookups
+//   - --no-cache queries the registry again
+//   - The cache is opened with the --cache-ttl duration
+func TestRunOutdatedVersionCache(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldNewCache := newVersionCacheFunc
+	oldDir := outdatedDirFlag
+	oldConfig := outdatedConfigFlag
+	oldSkip := outdatedSkipPreflight
+	oldOutput := outdatedOutputFlag
+	oldTTL := outdatedCacheTTLFlag
+	oldNoCache := outdatedNoCacheFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		newVersionCacheFunc = oldNewCache
+		outdatedDirFlag = oldDir
+		outdatedConfigFlag = oldConfig
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldOutput
+		outdatedCacheTTLFlag = oldTTL
+		outdatedNoCacheFlag = oldNoCache
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "react", Rule: "npm", PackageType: "js", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	var lookups int
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		lookups++
+		return []string{"1.1.0"}, nil
+	}
+
+	cacheDir := t.TempDir()
+	var openedTTL time.Duration
+	newVersionCacheFunc = func(ttl time.Duration) *outdated.VersionCache {
+		openedTTL = ttl
+		return &outdated.VersionCache{Dir: cacheDir, TTL: ttl}
+	}
+
+	outdatedDirFlag = "."
+	outdatedConfigFlag = ""
+	outdatedSkipPreflight = true
+	outdatedOutputFlag = "json"
+	outdatedCacheTTLFlag = 30 * time.Minute
+	outdatedNoCacheFlag = false
+
+	for i := 0; i < 2; i++ {
+		out := captureStdout(t, func() {
+			require.NoError(t, runOutdated(nil, nil))
+		})
+		assert.Contains(t, out, "1.1.0")
+	}
+	assert.Equal(t, 1, lookups, "second run is served from the cache")
+	assert.Equal(t, 30*time.Minute, openedTTL)
+
+	outdatedNoCacheFlag = true
+	captureStdout(t, func() {
+		require.NoError(t, runOutdated(nil, nil))
+	})
+	assert.Equal(t, 2, lookups, "--no-cache bypasses the cache")
+}
+
+// TestRunOutdatedReleaseAge tests the --age and --min-age flags.
+//
+// It verifies:
+//   - Releases younger than --min-age are not offered as updates
+//   - The age of the oldest remaining release is reported
+//   - Rules without release dates report #N/A and skip the lookup
+//   - A negative --min-age is rejected
+func TestRunOutdatedReleaseAge(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldListDates := listReleaseDatesFunc
+	oldNow := releaseAgeNowFunc
+	oldDir := outdatedDirFlag
+	oldConfig := outdatedConfigFlag
+	oldSkip := outdatedSkipPreflight
+	oldOutput := outdatedOutputFlag
+	oldAge := outdatedAgeFlag
+	oldMinAge := outdatedMinAgeFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		listReleaseDatesFunc = oldListDates
+		releaseAgeNowFunc = oldNow
+		outdatedDirFlag = oldDir
+		outdatedConfigFlag = oldConfig
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldOutput
+		outdatedAgeFlag = oldAge
+		outdatedMinAgeFlag = oldMinAge
+	})
+
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager: "js",
+					Outdated: &config.OutdatedCfg{
+						Commands:     "echo ok",
+						ReleaseDates: &config.ReleaseDatesCfg{Commands: "echo {{package}}"},
+					},
+				},
+				"mod": {
+					Manager:  "golang",
+					Outdated: &config.OutdatedCfg{Commands: "echo ok"},
+				},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "fresh", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "stale", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "gomod", Rule: "mod", PackageType: "golang", Version: "v1.0.0", InstalledVersion: "v1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Rule == "mod" {
+			return []string{"v1.1.0"}, nil
+		}
+		return []string{"1.1.0", "2.0.0"}, nil
+	}
+	var dateLookups []string
+	listReleaseDatesFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (outdated.ReleaseDates, error) {
+		dateLookups = append(dateLookups, p.Name)
+		if p.Name == "fresh" {
+			return outdated.ReleaseDates{"1.1.0": now.AddDate(0, 0, -3), "2.0.0": now.AddDate(0, 0, -1)}, nil
+		}
+		return outdated.ReleaseDates{"1.1.0": now.AddDate(0, 0, -120), "2.0.0": now.AddDate(0, 0, -2)}, nil
+	}
+	releaseAgeNowFunc = func() time.Time { return now }
+
+	outdatedDirFlag = "."
+	outdatedConfigFlag = ""
+	outdatedSkipPreflight = true
+	outdatedOutputFlag = "json"
+	outdatedAgeFlag = false
+	outdatedMinAgeFlag = 7
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runOutdated(nil, nil))
+	})
+
+	var result output.OutdatedResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	byName := map[string]output.OutdatedPackage{}
+	for _, pkg := range result.Packages {
+		byName[pkg.Name] = pkg
+	}
+
+	assert.ElementsMatch(t, []string{"fresh", "stale"}, dateLookups)
+	assert.Equal(t, constants.StatusUpToDate, byName["fresh"].Status)
+	assert.Equal(t, constants.PlaceholderNA, byName["fresh"].Age)
+	assert.Equal(t, constants.StatusOutdated, byName["stale"].Status)
+	assert.Equal(t, constants.PlaceholderNA, byName["stale"].Major)
+	assert.Equal(t, "1.1.0", byName["stale"].Minor)
+	assert.Equal(t, "4mo", byName["stale"].Age)
+	assert.Equal(t, constants.PlaceholderNA, byName["gomod"].Age)
+	assert.Equal(t, constants.StatusOutdated, byName["gomod"].Status)
+
+	t.Run("negative min age", func(t *testing.T) {
+		outdatedMinAgeFlag = -1
+		err := runOutdated(nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--min-age")
+	})
+}
+
+// TestRunOutdatedSince tests the --since flag of the outdated command.
+//
+// It verifies:
+//   - Only versions released after the cutoff are reported
+//   - Rules without release dates are reported as not configured instead of dropped
+//   - An invalid --since value is a config error
+func TestRunOutdatedSince(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldListDates := listReleaseDatesFunc
+	oldDir := outdatedDirFlag
+	oldSkip := outdatedSkipPreflight
+	oldOutput := outdatedOutputFlag
+	oldSince := outdatedSinceFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		listReleaseDatesFunc = oldListDates
+		outdatedDirFlag = oldDir
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldOutput
+		outdatedSinceFlag = oldSince
+	})
+
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager: "js",
+					Outdated: &config.OutdatedCfg{
+						Commands:     "echo ok",
+						ReleaseDates: &config.ReleaseDatesCfg{Commands: "echo {{package}}"},
+					},
+				},
+				"mod": {
+					Manager:  "golang",
+					Outdated: &config.OutdatedCfg{Commands: "echo ok"},
+				},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "react", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "gomod", Rule: "mod", PackageType: "golang", Version: "v1.0.0", InstalledVersion: "v1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Rule == "mod" {
+			return []string{"v1.1.0"}, nil
+		}
+		return []string{"1.1.0", "2.0.0"}, nil
+	}
+	listReleaseDatesFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (outdated.ReleaseDates, error) {
+		return outdated.ReleaseDates{"1.1.0": since.AddDate(0, 0, 5), "2.0.0": since.AddDate(0, -1, 0)}, nil
+	}
+
+	outdatedDirFlag = t.TempDir()
+	outdatedSkipPreflight = true
+	outdatedOutputFlag = "json"
+	outdatedSinceFlag = "2025-06-01"
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runOutdated(nil, nil))
+	})
+
+	var result output.OutdatedResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	byName := map[string]output.OutdatedPackage{}
+	for _, pkg := range result.Packages {
+		byName[pkg.Name] = pkg
+	}
+
+	assert.Equal(t, constants.StatusOutdated, byName["react"].Status)
+	assert.Equal(t, constants.PlaceholderNA, byName["react"].Major)
+	assert.Equal(t, "1.1.0", byName["react"].Minor)
+	assert.Equal(t, lock.InstallStatusNotConfigured, byName["gomod"].Status)
+
+	t.Run("invalid since", func(t *testing.T) {
+		outdatedSinceFlag = "last week"
+		err := runOutdated(nil, nil)
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+	})
+}
+
+// TestRunOutdatedYanked tests yanked version filtering in the outdated command.
+//
+// It verifies:
+//   - Yanked versions are not reported as available updates
+//   - A package whose only newer versions are yanked is up to date with a YANKED_ONLY warning
+//   - --allow-yanked reports yanked versions again
+func TestRunOutdatedYanked(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldListYanked := listYankedVersionsFunc
+	oldDir := outdatedDirFlag
+	oldSkip := outdatedSkipPreflight
+	oldOutput := outdatedOutputFlag
+	oldAllow := outdatedAllowYankedFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		listYankedVersionsFunc = oldListYanked
+		outdatedDirFlag = oldDir
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldOutput
+		outdatedAllowYankedFlag = oldAllow
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager: "js",
+					Outdated: &config.OutdatedCfg{
+						Commands: "echo ok",
+						Yanked:   &config.YankedCfg{Commands: "echo {{package}}"},
+					},
+				},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "react", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "left-pad", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"1.0.0", "1.1.0", "1.2.0"}, nil
+	}
+	listYankedVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (outdated.YankedVersions, error) {
+		if p.Name == "left-pad" {
+			return outdated.YankedVersions{Versions: map[string]string{"1.1.0": "", "1.2.0": "unpublished"}}, nil
+		}
+		return outdated.YankedVersions{Versions: map[string]string{"1.2.0": "broken"}}, nil
+	}
+
+	outdatedDirFlag = t.TempDir()
+	outdatedSkipPreflight = true
+	outdatedOutputFlag = "json"
+
+	run := func() (map[string]output.OutdatedPackage, output.OutdatedResult) {
+		out := captureStdout(t, func() {
+			require.NoError(t, runOutdated(nil, nil))
+		})
+		var result output.OutdatedResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		byName := map[string]output.OutdatedPackage{}
+		for _, pkg := range result.Packages {
+			byName[pkg.Name] = pkg
+		}
+		return byName, result
+	}
+
+	byName, result := run()
+	assert.Equal(t, "1.1.0", byName["react"].Minor)
+	assert.Equal(t, constants.StatusUpToDate, byName["left-pad"].Status)
+	var codes []warnings.Code
+	for _, w := range result.WarningDetails {
+		codes = append(codes, w.Code)
+	}
+	assert.Contains(t, codes, warnings.CodeYankedOnly)
+
+	outdatedAllowYankedFlag = true
+	byName, _ = run()
+	assert.Equal(t, "1.2.0", byName["react"].Minor)
+	assert.Equal(t, "1.2.0", byName["left-pad"].Minor)
+}
+
+// TestRunOutdatedShields tests the shields.io badge output.
+//
+// It verifies:
+//   - The badge counts outdated packages with the default label and colors
+//   - Filters narrow the count, and the configured label and colors are used
+//   - Other commands reject --output shields
+func TestRunOutdatedShields(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldDir := outdatedDirFlag
+	oldSkip := outdatedSkipPreflight
+	oldOutput := outdatedOutputFlag
+	oldRule := outdatedRuleFlag
+	oldListOutput := listOutputFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		outdatedDirFlag = oldDir
+		outdatedSkipPreflight = oldSkip
+		outdatedOutputFlag = oldOutput
+		outdatedRuleFlag = oldRule
+		listOutputFlag = oldListOutput
+	})
+
+	var badge *config.BadgeCfg
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+				"mod": {Manager: "golang", Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+			},
+			Badge: badge,
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "react", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Name: "left-pad", Rule: "npm", PackageType: "js", Version: "1.2.0", InstalledVersion: "1.2.0"},
+			{Name: "gin", Rule: "mod", PackageType: "golang", Version: "1.0.0", InstalledVersion: "1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"1.0.0", "1.1.0", "1.2.0"}, nil
+	}
+
+	outdatedDirFlag = t.TempDir()
+	outdatedSkipPreflight = true
+	outdatedOutputFlag = "shields"
+
+	run := func() output.ShieldsBadge {
+		out := captureStdout(t, func() {
+			require.NoError(t, runOutdated(nil, nil))
+		})
+		var result output.ShieldsBadge
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		return result
+	}
+
+	assert.Equal(t, output.ShieldsBadge{SchemaVersion: 1, Label: "outdated", Message: "2", Color: "yellow"}, run())
+
+	outdatedRuleFlag = "npm"
+	badge = &config.BadgeCfg{Label: "npm deps", Colors: []config.BadgeColorCfg{{Min: 0, Color: "green"}, {Min: 1, Color: "red"}}}
+	assert.Equal(t, output.ShieldsBadge{SchemaVersion: 1, Label: "npm deps", Message: "1", Color: "red"}, run())
+
+	listOutputFlag = "shields"
+	assert.ErrorContains(t, runList(nil, nil), "--output shields is not supported by the list command")
+}
+
 // TestRunOutdatedWithStructuredOutputAndErrors tests the behavior of structured output with errors.
 //
 // It verifies:
@@ -570,6 +1437,11 @@ func TestDeriveOutdatedStatus(t *testing.T) {
 			result:   outdatedResult{pkg: formats.Package{Name: "test", Version: "5.*", InstallStatus: lock.InstallStatusFloating}, major: "6.0.0", minor: "5.5.0", patch: "5.1.1"},
 			expected: lock.InstallStatusFloating,
 		},
+		{
+			name:     "non-registry source preserves status",
+			result:   outdatedResult{pkg: formats.Package{Name: "test", InstallStatus: lock.InstallStatusNonRegistry, NonRegistrySource: "git"}, major: "#N/A", minor: "#N/A", patch: "#N/A"},
+			expected: lock.InstallStatusNonRegistry,
+		},
 	}
 
 	for _, tt := range tests {
@@ -621,6 +1493,18 @@ func TestIsLatestMissing(t *testing.T) {
 	assert.False(t, isLatestMissing(p, ruleCfg))
 }
 
+// TestNewestAvailable tests picking the latest version from the summary columns.
+//
+// It verifies:
+//   - The major column wins when it has a version
+//   - #N/A and empty columns are skipped
+//   - All-#N/A columns return an empty string
+func TestNewestAvailable(t *testing.T) {
+	assert.Equal(t, "3.0.0", newestAvailable("3.0.0", "2.5.0", "2.4.1"))
+	assert.Equal(t, "2.4.1", newestAvailable(constants.PlaceholderNA, "", "2.4.1"))
+	assert.Empty(t, newestAvailable(constants.PlaceholderNA, constants.PlaceholderNA, constants.PlaceholderNA))
+}
+
 // TestPrepareOutdatedDisplayRows tests the behavior of display row preparation.
 //
 // It verifies:
@@ -670,6 +1554,30 @@ func TestBuildOutdatedTable(t *testing.T) {
 	assert.GreaterOrEqual(t, table.GetColumnWidthByHeader("NAME"), 4)
 }
 
+// TestOutdatedShowSource tests the --show-source table column of outdated.
+//
+// It verifies:
+//   - The SOURCE column is hidden unless sources are given
+//   - Streamed rows end with the manifest location of the package
+//   - Display rows carry the source, which shows the column in the summary table
+func TestOutdatedShowSource(t *testing.T) {
+	pkgs := []formats.Package{{Rule: "npm", PackageType: "js", Type: "prod", Name: "react", Version: "17.0.0", Source: "web/package.json", Line: 4}}
+
+	hidden := buildOutdatedTableFromPackages(pkgs, false, nil)
+	assert.NotContains(t, hidden.HeaderRow(), "SOURCE")
+
+	table := buildOutdatedTableFromPackages(pkgs, false, []string{"web/package.json:4"})
+	assert.Contains(t, table.HeaderRow(), "SOURCE")
+	out := captureStdout(t, func() {
+		printOutdatedRowWithTable(outdatedResult{pkg: pkgs[0], status: "Outdated", source: "web/package.json:4"}, table)
+	})
+	assert.Equal(t, "web/package.json:4", strings.Fields(out)[len(strings.Fields(out))-1])
+
+	rows := prepareOutdatedDisplayRows([]outdatedResult{{pkg: pkgs[0], source: "web/package.json:4"}})
+	assert.Equal(t, "web/package.json:4", rows[0].source)
+	assert.Contains(t, buildOutdatedTable(rows).HeaderRow(), "SOURCE")
+}
+
 // TestOutdatedTableFormatters tests the behavior of outdated table formatting.
 //
 // It verifies:
@@ -988,7 +1896,7 @@ func TestPrintOutdatedStructured(t *testing.T) {
 
 	t.Run("JSON format", func(t *testing.T) {
 		out := captureStdout(t, func() {
-			err := printOutdatedStructured(results, []string{}, []string{}, output.FormatJSON)
+			err := printOutdatedStructured(results, []string{}, nil, []string{}, output.FormatJSON, nil)
 			require.NoError(t, err)
 		})
 		assert.Contains(t, out, `"name":"lodash"`)
@@ -999,7 +1907,7 @@ func TestPrintOutdatedStructured(t *testing.T) {
 
 	t.Run("CSV format", func(t *testing.T) {
 		out := captureStdout(t, func() {
-			err := printOutdatedStructured(results, []string{}, []string{}, output.FormatCSV)
+			err := printOutdatedStructured(results, []string{}, nil, []string{}, output.FormatCSV, nil)
 			require.NoError(t, err)
 		})
 		assert.Contains(t, out, "lodash")
@@ -1008,7 +1916,7 @@ func TestPrintOutdatedStructured(t *testing.T) {
 
 	t.Run("XML format with warnings and errors", func(t *testing.T) {
 		out := captureStdout(t, func() {
-			err := printOutdatedStructured(results, []string{"warning1"}, []string{"error1"}, output.FormatXML)
+			err := printOutdatedStructured(results, []string{"warning1"}, nil, []string{"error1"}, output.FormatXML, nil)
 			require.NoError(t, err)
 		})
 		assert.Contains(t, out, "<name>lodash</name>")
@@ -2293,7 +2293,7 @@ func TestIntegration_KnownCorruptionIndicators(t *testing.T) {
 		"Total packages:",
 		"Scanned package files",
 		"Updated packages that may have caused issues",
-		"Consider rolling back",
+		"Rolled back",
 
 		// Warning messages (cmd/update.go)
 		"⚠",
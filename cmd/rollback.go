@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/update"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+	"github.com/spf13/cobra"
+)
+
+// CLI flags
+var (
+	rollbackPlanFlag      string
+	rollbackConfigFlag    string
+	rollbackDirFlag       string
+	rollbackDryRunFlag    bool
+	rollbackSkipLockRun   bool
+	rollbackNoTimeoutFlag bool
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore packages to the versions recorded in a saved plan",
+	Long: `Reverts the packages of a plan written by 'goupdate update --plan-out' to their
+original versions, runs the lock commands, and verifies the files landed back
+at the original versions.`,
+	RunE: runRollback,
+}
+
+func init() {
+	rollbackCmd.Flags().StringVar(&rollbackPlanFlag, "plan", "", "Plan file written by 'goupdate update --plan-out' (required)")
+	rollbackCmd.Flags().StringVarP(&rollbackConfigFlag, "config", "c", "", "Config file path")
+	rollbackCmd.Flags().StringVarP(&rollbackDirFlag, "directory", "d", "", "Directory to roll back (default: the plan's working directory)")
+	rollbackCmd.Flags().BoolVar(&rollbackDryRunFlag, "dry-run", false, "Show what would be rolled back without writing files")
+	rollbackCmd.Flags().BoolVar(&rollbackSkipLockRun, "skip-lock", false, "Skip running lock/install command")
+	rollbackCmd.Flags().BoolVar(&rollbackNoTimeoutFlag, "no-timeout", false, "Disable command timeouts")
+}
+
+// runRollback executes the rollback command.
+//
+// Loads a saved plan, restores each planned package to its Original version
+// rule by rule using RollbackPlans, and runs the drift check against a fresh
+// package reload.
+//
+// Parameters:
+//   - cmd: Cobra command instance
+//   - args: Command line arguments (unused)
+//
+// Returns:
+//   - error: Returns ExitError with appropriate code on failure
+func runRollback(cmd *cobra.Command, args []string) error {
+	if rollbackPlanFlag == "" {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--plan is required\n  💡 Create a plan with: goupdate update --plan-out plan.json"))
+	}
+
+	planFile, err := update.ReadPlanFile(rollbackPlanFlag)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+
+	workDir := rollbackDirFlag
+	if workDir == "" {
+		workDir = planFile.WorkDir
+	}
+	if workDir == "" {
+		workDir = "."
+	}
+
+	cfg, err := loadAndValidateConfig(rollbackConfigFlag, workDir)
+	if err != nil {
+		return err
+	}
+
+	workDir = resolveWorkingDir(workDir, cfg)
+	cfg.WorkingDir = workDir
+	cfg.NoTimeout = rollbackNoTimeoutFlag
+
+	plans := planFile.RollbackCandidates()
+	if len(plans) == 0 {
+		fmt.Println("Nothing to roll back: plan contains no applied updates")
+		return nil
+	}
+
+	ctx := update.NewUpdateContext(cfg, workDir, nil).
+		WithReloadList(func() ([]formats.Package, error) {
+			return reloadRollbackPackages(cfg, workDir)
+		})
+
+	failed := 0
+	for _, rulePlans := range update.PartitionPlans(plans, func(p *update.PlannedUpdate) string { return p.Res.Pkg.Rule }) {
+		rule := rulePlans[0].Res.Pkg.Rule
+		reason := fmt.Errorf("rollback requested from plan %s", rollbackPlanFlag)
+		rollbackErr := update.RollbackPlans(rulePlans, cfg, workDir, ctx, reason, updatePackageFunc, rollbackDryRunFlag, rollbackSkipLockRun)
+		if rollbackErr != nil {
+			failed++
+			verbose.Infof("Rollback for rule %s failed: %v", rule, rollbackErr)
+			fmt.Printf("✗ %s: rollback failed: %v\n", rule, rollbackErr)
+			continue
+		}
+
+		for _, plan := range rulePlans {
+			if rollbackDryRunFlag {
+				fmt.Printf("Would roll back %s (%s): %s → %s\n", plan.Res.Pkg.Name, rule, plan.Res.Target, plan.Original)
+			} else {
+				fmt.Printf("✓ Rolled back %s (%s): %s → %s\n", plan.Res.Pkg.Name, rule, plan.Res.Target, plan.Original)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return errors.NewExitError(errors.ExitFailure, fmt.Errorf("rollback failed for %d rule(s)", failed))
+	}
+
+	return nil
+}
+
+// reloadRollbackPackages reloads all packages with installed versions for the rollback drift check.
+//
+// Parameters:
+//   - cfg: Configuration to use for package detection
+//   - workDir: Working directory to scan
+//
+// Returns:
+//   - []formats.Package: Reloaded packages
+//   - error: When detection or lock resolution fails
+func reloadRollbackPackages(cfg *config.Config, workDir string) ([]formats.Package, error) {
+	packages, err := getPackagesFunc(cfg, nil, workDir)
+	if err != nil {
+		return nil, err
+	}
+	return applyInstalledVersionsFunc(packages, cfg, workDir)
+}
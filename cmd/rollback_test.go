@@ -0,0 +1,416 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/output"
+	"github.com/ajxudir/goupdate/pkg/update"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRollbackTest stubs config loading, package detection, and the updater for rollback tests.
+//
+// Parameters:
+//   - t: Test instance used for cleanup
+//   - installed: Version reported for react by the reload after rollback
+//
+// The rollback plan flag is pointed at a plan file updating react from 17.0.0 to 18.0.0.
+//
+// Returns:
+//   - *[]string: Recorded updater calls as "name@target"
+func setupRollbackTest(t *testing.T, installed string) *[]string {
+	t.Helper()
+
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldPlan := rollbackPlanFlag
+	oldDir := rollbackDirFlag
+	oldDryRun := rollbackDryRunFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		updatePackageFunc = oldUpdate
+		rollbackPlanFlag = oldPlan
+		rollbackDirFlag = oldDir
+		rollbackDryRunFlag = oldDryRun
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{Rules: map[string]config.PackageManagerCfg{"npm": {Manager: "js", Update: &config.UpdateCfg{}}}}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: installed, InstalledVersion: installed},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+
+	var calls []string
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		calls = append(calls, p.Name+"@"+target)
+		return nil
+	}
+
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "plan.json")
+	plans := []*update.PlannedUpdate{
+		{
+			Res: update.UpdateResult{
+				Pkg:    formats.Package{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0"},
+				Target: "18.0.0",
+				Status: constants.StatusPlanned,
+			},
+			Original: "17.0.0",
+			GroupKey: "react",
+		},
+		{
+			Res: update.UpdateResult{
+				Pkg:    formats.Package{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.17.21"},
+				Status: constants.StatusUpToDate,
+			},
+			Original: "4.17.21",
+			GroupKey: "lodash",
+		},
+	}
+	require.NoError(t, update.WritePlanFile(planPath, update.NewPlanFile(plans, dir)))
+
+	rollbackPlanFlag = planPath
+	rollbackDirFlag = ""
+	rollbackDryRunFlag = false
+
+	return &calls
+}
+
+// TestRunRollback tests the behavior of the rollback command.
+//
+// It verifies:
+//   - Only applied plan entries are restored to their Original version
+//   - A successful drift check reports the rolled back package
+//   - A drift check mismatch fails with ExitFailure
+//   - A missing --plan flag is a config error
+func TestRunRollback(t *testing.T) {
+	t.Run("restores original versions", func(t *testing.T) {
+		calls := setupRollbackTest(t, "17.0.0")
+
+		out := captureStdout(t, func() {
+			require.NoError(t, runRollback(nil, nil))
+		})
+
+		assert.Equal(t, []string{"react@17.0.0"}, *calls)
+		assert.Contains(t, out, "Rolled back react (npm): 18.0.0 → 17.0.0")
+	})
+
+	t.Run("fails when drift check finds target version", func(t *testing.T) {
+		setupRollbackTest(t, "18.0.0")
+
+		var err error
+		out := captureStdout(t, func() {
+			err = runRollback(nil, nil)
+		})
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitFailure, exitErr.Code)
+		assert.Contains(t, out, "drift check failed")
+	})
+
+	t.Run("requires plan flag", func(t *testing.T) {
+		setupRollbackTest(t, "17.0.0")
+		rollbackPlanFlag = ""
+
+		err := runRollback(nil, nil)
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+	})
+}
+
+// TestRunUpdatePlanOut tests update with --plan-out.
+//
+// It verifies:
+//   - The plan file is written with Original and Target versions
+func TestRunUpdatePlanOut(t *testing.T) {
+	setupRollbackTest(t, "17.0.0")
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	t.Cleanup(func() {
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		resetUpdateFlagsToDefaults()
+	})
+
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"17.0.2"}, nil
+	}
+
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	resetUpdateFlagsToDefaults()
+	updatePlanOutFlag = planPath
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateYesFlag = true
+
+	captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+
+	planFile, err := update.ReadPlanFile(planPath)
+	require.NoError(t, err)
+	require.Len(t, planFile.Plans, 1)
+	assert.Equal(t, "react", planFile.Plans[0].Package.Name)
+	assert.Equal(t, "17.0.0", planFile.Plans[0].Original)
+	assert.Equal(t, "17.0.2", planFile.Plans[0].Target)
+}
+
+// TestRunUpdatePlanIn tests applying a plan written by --plan-out with --plan-in.
+//
+// It verifies:
+//   - The saved plan is applied without looking up versions
+//   - A package changed since the plan was written fails with ExitFailure before any update
+//   - A missing plan file fails with ExitConfigError
+func TestRunUpdatePlanIn(t *testing.T) {
+	calls := setupRollbackTest(t, "17.0.0")
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	t.Cleanup(func() {
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		resetUpdateFlagsToDefaults()
+	})
+
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{Commands: "npm install"}, nil
+	}
+	lookups := 0
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		lookups++
+		return []string{"17.0.2"}, nil
+	}
+
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	resetUpdateFlagsToDefaults()
+	updatePlanOutFlag = planPath
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateYesFlag = true
+	captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	require.Equal(t, 1, lookups)
+	*calls = nil
+
+	t.Run("applies the saved plan", func(t *testing.T) {
+		updatePlanOutFlag = ""
+		updatePlanInFlag = planPath
+		updateDryRunFlag = false
+
+		// The reload after the update sees the version the updater wrote
+		current := "17.0.0"
+		getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+			return []formats.Package{
+				{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: current, InstalledVersion: current},
+			}, nil
+		}
+		updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			*calls = append(*calls, p.Name+"@"+target)
+			current = target
+			return nil
+		}
+
+		var err error
+		out := captureStdout(t, func() {
+			err = runUpdate(nil, nil)
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, lookups, "versions are not looked up again")
+		assert.Contains(t, out, "Applying plan from "+planPath)
+		assert.Contains(t, *calls, "react@17.0.2")
+	})
+
+	t.Run("rejects a stale plan", func(t *testing.T) {
+		staleCalls := setupRollbackTest(t, "17.0.1")
+		updatePlanInFlag = planPath
+
+		var err error
+		captureStdout(t, func() {
+			err = runUpdate(nil, nil)
+		})
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitFailure, exitErr.Code)
+		assert.ErrorContains(t, err, "declared version changed 17.0.0 → 17.0.1")
+		assert.Empty(t, *staleCalls)
+	})
+
+	t.Run("missing plan file", func(t *testing.T) {
+		updatePlanInFlag = filepath.Join(t.TempDir(), "missing.json")
+
+		err := runUpdate(nil, nil)
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+	})
+}
+
+// TestValidatePlanIn tests the flag checks for --plan-in.
+//
+// It verifies:
+//   - Without --plan-in nothing is validated
+//   - Flags that select packages or versions are rejected with ExitConfigError
+//   - File arguments are rejected
+//   - An explicit --quarantine-days 0 is rejected like any other value
+//   - Execution flags such as --dry-run and --yes are allowed
+func TestValidatePlanIn(t *testing.T) {
+	t.Cleanup(resetUpdateFlagsToDefaults)
+	resetUpdateFlagsToDefaults()
+
+	updateMajorFlag = true
+	assert.NoError(t, validatePlanIn(nil, nil))
+
+	updatePlanInFlag = "plan.json"
+	err := validatePlanIn(nil, nil)
+	exitErr, ok := errors.IsExitError(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+	assert.ErrorContains(t, err, "--plan-in cannot be combined with --major")
+
+	updateMajorFlag = false
+	updateRuleFlag = "npm"
+	assert.ErrorContains(t, validatePlanIn(nil, nil), "--rule")
+
+	updateRuleFlag = "all"
+	assert.ErrorContains(t, validatePlanIn(nil, []string{"package.json"}), "file arguments")
+
+	updateSinceFlag = "last-run"
+	assert.ErrorContains(t, validatePlanIn(nil, nil), "--since")
+	updateSinceFlag = ""
+
+	require.NoError(t, updateCmd.Flags().Set("quarantine-days", "0"))
+	assert.ErrorContains(t, validatePlanIn(updateCmd, nil), "--quarantine-days")
+	resetUpdateFlagsToDefaults()
+	updatePlanInFlag = "plan.json"
+
+	updateDryRunFlag = true
+	updateYesFlag = true
+	assert.NoError(t, validatePlanIn(nil, nil))
+}
+
+// TestRunUpdatePlanDiff tests comparing two saved plans with --plan-diff.
+//
+// It verifies:
+//   - The table lists retargeted, added and removed packages with a summary
+//   - JSON output reports the changes without requiring --yes or --dry-run
+//   - Neither configuration nor versions are loaded
+//   - A wrong number of plans, a missing plan and conflicting flags fail with ExitConfigError
+func TestRunUpdatePlanDiff(t *testing.T) {
+	oldLoad := loadConfigFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		resetUpdateFlagsToDefaults()
+	})
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		t.Fatal("configuration should not be loaded")
+		return nil, nil
+	}
+
+	dir := t.TempDir()
+	react := formats.Package{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Source: filepath.Join(dir, "package.json")}
+	vue := formats.Package{Rule: "npm", Name: "vue", PackageType: "js", Type: "prod", Source: filepath.Join(dir, "package.json")}
+	jest := formats.Package{Rule: "npm", Name: "jest", PackageType: "js", Type: "dev", Source: filepath.Join(dir, "package.json")}
+	oldPath := filepath.Join(dir, "old.json")
+	newPath := filepath.Join(dir, "new.json")
+	require.NoError(t, update.WritePlanFile(oldPath, &update.PlanFile{Version: update.PlanFileVersion, WorkDir: dir, Plans: []update.PlanEntry{
+		{Package: react, Original: "17.0.0", Target: "18.2.0", Status: constants.StatusPlanned},
+		{Package: vue, Original: "3.3.0", Target: "3.4.0", Status: constants.StatusPlanned},
+	}}))
+	require.NoError(t, update.WritePlanFile(newPath, &update.PlanFile{Version: update.PlanFileVersion, WorkDir: dir, Plans: []update.PlanEntry{
+		{Package: react, Original: "17.0.0", Target: "19.0.0", Status: constants.StatusPlanned},
+		{Package: jest, Original: "29.0.0", Target: "29.7.0", Status: constants.StatusPlanned},
+	}}))
+
+	t.Run("table", func(t *testing.T) {
+		resetUpdateFlagsToDefaults()
+		updatePlanDiffFlag = []string{oldPath, newPath}
+
+		var err error
+		out := captureStdout(t, func() {
+			err = runUpdate(nil, nil)
+		})
+
+		require.NoError(t, err)
+		assert.Contains(t, out, "OLD TARGET")
+		assert.Regexp(t, `react\s+changed\s+17\.0\.0\s+18\.2\.0\s+19\.0\.0`, out)
+		assert.Regexp(t, `jest\s+added\s+29\.0\.0\s+-\s+29\.7\.0`, out)
+		assert.Regexp(t, `vue\s+removed\s+3\.3\.0\s+3\.4\.0\s+-`, out)
+		assert.Contains(t, out, "Added: 1, Removed: 1, Changed: 1, Unchanged: 0")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		resetUpdateFlagsToDefaults()
+		updatePlanDiffFlag = []string{oldPath, newPath}
+		updateOutputFlag = "json"
+
+		var err error
+		out := captureStdout(t, func() {
+			err = runUpdate(nil, nil)
+		})
+
+		require.NoError(t, err)
+		var result output.PlanDiffResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		assert.Equal(t, output.PlanDiffSummary{OldPlan: oldPath, NewPlan: newPath, Added: 1, Removed: 1, Changed: 1}, result.Summary)
+		require.Len(t, result.Changes, 3)
+		assert.Equal(t, "package.json", result.Changes[0].Source)
+	})
+
+	t.Run("identical plans", func(t *testing.T) {
+		resetUpdateFlagsToDefaults()
+		updatePlanDiffFlag = []string{newPath, newPath}
+
+		out := captureStdout(t, func() {
+			require.NoError(t, runUpdate(nil, nil))
+		})
+
+		assert.Contains(t, out, "Plans are identical (2 pending update(s))")
+	})
+
+	for name, setup := range map[string]func(){
+		"one plan":      func() { updatePlanDiffFlag = []string{oldPath} },
+		"missing plan":  func() { updatePlanDiffFlag = []string{oldPath, filepath.Join(dir, "missing.json")} },
+		"with plan-out": func() { updatePlanDiffFlag = []string{oldPath, newPath}; updatePlanOutFlag = "plan.json" },
+		"with markdown": func() { updatePlanDiffFlag = []string{oldPath, newPath}; updateOutputFlag = "markdown" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			resetUpdateFlagsToDefaults()
+			setup()
+
+			err := runUpdate(nil, nil)
+
+			exitErr, ok := errors.IsExitError(err)
+			require.True(t, ok, "%v", err)
+			assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+		})
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/ajxudir/goupdate/pkg/display"
 	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/output"
 	"github.com/ajxudir/goupdate/pkg/verbose"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +20,10 @@ var exitFunc = os.Exit
 var verboseFlag bool
 var versionFlag bool
 var skipBuildChecksFlag bool
+var colorFlag = display.ColorAuto
+var statusStyleFlag = display.StatusStyleAuto
+var csvDelimiterFlag = output.DefaultCSVDelimiter
+var configDirFlag string
 
 var rootCmd = &cobra.Command{
 	Use:   "goupdate",
@@ -27,6 +33,9 @@ var rootCmd = &cobra.Command{
 		if verboseFlag {
 			verbose.Enable()
 		}
+		display.SetColorMode(colorFlag)
+		display.SetStatusStyle(statusStyleFlag)
+		output.SetCSVDelimiter(csvDelimiterFlag)
 		// Show build warnings (arch mismatch, dev build) at the top of every command
 		if !skipBuildChecksFlag {
 			if warnings := GetBuildWarnings(); warnings != "" {
@@ -79,6 +88,10 @@ func ExecuteTest() error {
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&verboseFlag, "verbose", false, "Enable verbose debug output")
+	rootCmd.PersistentFlags().Var(&colorFlag, "color", "Status icons in output: auto (terminal only), always, or never (NO_COLOR forces never)")
+	rootCmd.PersistentFlags().Var(&statusStyleFlag, "status-style", "Status presentation: auto (follows --color), emoji, ascii ([+] Updated), or text")
+	rootCmd.PersistentFlags().Var(&csvDelimiterFlag, "csv-delimiter", "Field separator for --output csv: a single character, or tab")
+	rootCmd.PersistentFlags().StringVar(&configDirFlag, "config-dir", "", "Directory of config files merged in alphabetical order (default .goupdate.d when no config file exists)")
 	rootCmd.PersistentFlags().BoolVar(&skipBuildChecksFlag, "skip-build-checks", false, "Skip build validation warnings (dev build, arch mismatch)")
 
 	// Add -v/--version as a LOCAL flag (not persistent) so it only works on root command
@@ -87,10 +100,13 @@ func init() {
 	// Commands ordered logically: info → config → workflow (scan → list → outdated → update)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(scanCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(sbomCmd)
 	rootCmd.AddCommand(outdatedCmd)
 	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(rollbackCmd)
 }
 
 // printVersionOutput prints version, build, and runtime information to stdout.
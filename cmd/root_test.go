@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"testing"
 
+	"github.com/ajxudir/goupdate/pkg/display"
+	"github.com/ajxudir/goupdate/pkg/output"
 	"github.com/ajxudir/goupdate/pkg/verbose"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestPersistentPreRunVerbose tests the behavior of PersistentPreRun with verbose flag.
@@ -54,6 +58,92 @@ func TestPersistentPreRunNotVerbose(t *testing.T) {
 	assert.False(t, verbose.IsEnabled())
 }
 
+// TestPersistentPreRunColor tests the behavior of PersistentPreRun with the color flag.
+//
+// It verifies:
+//   - --color never disables status icons
+//   - --color always enables them
+//   - Invalid --color values are rejected when parsing flags
+func TestPersistentPreRunColor(t *testing.T) {
+	oldColor := colorFlag
+	t.Cleanup(func() {
+		colorFlag = oldColor
+		display.SetColorMode(display.ColorAlways)
+	})
+	t.Setenv("NO_COLOR", "")
+
+	colorFlag = display.ColorNever
+	rootCmd.PersistentPreRun(rootCmd, []string{})
+	assert.False(t, display.ColorEnabled())
+	assert.Equal(t, "Updated", display.FormatStatus("Updated"))
+
+	colorFlag = display.ColorAlways
+	rootCmd.PersistentPreRun(rootCmd, []string{})
+	assert.True(t, display.ColorEnabled())
+
+	assert.Error(t, rootCmd.PersistentFlags().Set("color", "sometimes"))
+}
+
+// TestPersistentPreRunStatusStyle tests the behavior of PersistentPreRun with the status-style flag.
+//
+// It verifies:
+//   - --status-style ascii renders ASCII markers even with --color never
+//   - --status-style text renders plain labels even with --color always
+//   - auto keeps the icons selected by --color
+//   - Invalid --status-style values are rejected when parsing flags
+func TestPersistentPreRunStatusStyle(t *testing.T) {
+	oldColor := colorFlag
+	oldStyle := statusStyleFlag
+	t.Cleanup(func() {
+		colorFlag = oldColor
+		statusStyleFlag = oldStyle
+		display.SetColorMode(display.ColorAlways)
+		display.SetStatusStyle(display.StatusStyleAuto)
+	})
+	t.Setenv("NO_COLOR", "")
+
+	colorFlag = display.ColorNever
+	statusStyleFlag = display.StatusStyleASCII
+	rootCmd.PersistentPreRun(rootCmd, []string{})
+	assert.Equal(t, "[+] Updated", display.FormatStatus("Updated"))
+
+	colorFlag = display.ColorAlways
+	statusStyleFlag = display.StatusStyleText
+	rootCmd.PersistentPreRun(rootCmd, []string{})
+	assert.Equal(t, "Updated", display.FormatStatus("Updated"))
+
+	statusStyleFlag = display.StatusStyleAuto
+	rootCmd.PersistentPreRun(rootCmd, []string{})
+	assert.Equal(t, "🟢 Updated", display.FormatStatus("Updated"))
+
+	assert.Error(t, rootCmd.PersistentFlags().Set("status-style", "fancy"))
+}
+
+// TestPersistentPreRunCSVDelimiter tests the behavior of PersistentPreRun with the csv-delimiter flag.
+//
+// It verifies:
+//   - --csv-delimiter changes the separator of CSV output
+//   - Invalid --csv-delimiter values are rejected when parsing flags
+func TestPersistentPreRunCSVDelimiter(t *testing.T) {
+	oldDelimiter := csvDelimiterFlag
+	t.Cleanup(func() {
+		csvDelimiterFlag = oldDelimiter
+		output.SetCSVDelimiter(output.DefaultCSVDelimiter)
+	})
+
+	require.NoError(t, rootCmd.PersistentFlags().Set("csv-delimiter", ";"))
+	rootCmd.PersistentPreRun(rootCmd, []string{})
+
+	var buf bytes.Buffer
+	require.NoError(t, output.WriteListResult(&buf, output.FormatCSV, &output.ListResult{
+		Packages: []output.ListPackage{{Rule: "npm", PM: "js", Type: "prod", Name: "react", Version: "18.2.0"}},
+	}))
+	assert.Contains(t, buf.String(), "RULE;PM;TYPE;CONSTRAINT;VERSION;INSTALLED;STATUS;GROUP;NAME")
+	assert.Contains(t, buf.String(), "npm;js;prod;;18.2.0;")
+
+	assert.Error(t, rootCmd.PersistentFlags().Set("csv-delimiter", "::"))
+}
+
 // TestPersistentPreRunBuildWarnings tests the behavior of PersistentPreRun with build warnings.
 //
 // It verifies:
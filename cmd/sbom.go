@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/display"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/filtering"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/sbom"
+	"github.com/ajxudir/goupdate/pkg/supervision"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+	"github.com/spf13/cobra"
+)
+
+// sbomFormatCycloneDXJSON is the only SBOM format currently supported.
+const sbomFormatCycloneDXJSON = "cyclonedx-json"
+
+var (
+	sbomFormatFlag string
+	sbomTypeFlag   string
+	sbomPMFlag     string
+	sbomRuleFlag   string
+	sbomNameFlag   string
+	sbomGroupFlag  string
+	sbomConfigFlag string
+	sbomDirFlag    string
+	sbomFileFlag   string
+)
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom [file...]",
+	Short: "Write a software bill of materials of installed packages",
+	Long: `Export the resolved dependency set as a CycloneDX 1.5 JSON SBOM.
+
+Each package with a concrete installed version becomes a component with a
+Package URL. Packages without one are omitted and reported on stderr.`,
+	RunE: runSBOM,
+}
+
+func init() {
+	sbomCmd.Flags().StringVar(&sbomFormatFlag, "format", sbomFormatCycloneDXJSON, "SBOM format: cyclonedx-json")
+	sbomCmd.Flags().StringVarP(&sbomTypeFlag, "type", "t", "all", "Filter by type (comma-separated): all,prod,dev")
+	sbomCmd.Flags().StringVarP(&sbomPMFlag, "package-manager", "p", "all", "Filter by package manager (comma-separated)")
+	sbomCmd.Flags().StringVarP(&sbomRuleFlag, "rule", "r", "all", "Filter by rule (comma-separated)")
+	sbomCmd.Flags().StringVarP(&sbomNameFlag, "name", "n", "", "Filter by package name (comma-separated)")
+	sbomCmd.Flags().StringVarP(&sbomGroupFlag, "group", "g", "", "Filter by group (comma-separated)")
+	sbomCmd.Flags().StringVarP(&sbomConfigFlag, "config", "c", "", "Config file path")
+	sbomCmd.Flags().StringVarP(&sbomDirFlag, "directory", "d", ".", "Directory to scan")
+	sbomCmd.Flags().StringVarP(&sbomFileFlag, "file", "f", "", "Filter by file path patterns (comma-separated, supports globs)")
+}
+
+// runSBOM executes the sbom command to export installed packages as an SBOM.
+//
+// Resolves packages and installed versions the same way as list, then writes
+// a CycloneDX JSON document to stdout. Packages without a concrete installed
+// version are omitted and reported through the unsupported tracker on stderr,
+// so stdout stays a valid JSON document.
+//
+// Parameters:
+//   - cmd: Cobra command instance
+//   - args: Optional file paths to include (empty to auto-detect)
+//
+// Returns:
+//   - error: Returns error on unknown format, config, parsing, or write failure
+func runSBOM(cmd *cobra.Command, args []string) error {
+	if !strings.EqualFold(strings.TrimSpace(sbomFormatFlag), sbomFormatCycloneDXJSON) {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("unsupported SBOM format %q: must be %s", sbomFormatFlag, sbomFormatCycloneDXJSON))
+	}
+
+	collector := &display.WarningCollector{}
+	restoreWarnings := warnings.SetWarningWriter(collector)
+	defer restoreWarnings()
+	unsupported := supervision.NewUnsupportedTracker()
+
+	workDir := sbomDirFlag
+
+	cfg, err := loadAndValidateConfig(sbomConfigFlag, workDir)
+	if err != nil {
+		return err
+	}
+
+	workDir = resolveWorkingDir(workDir, cfg)
+	cfg.WorkingDir = workDir
+
+	pkgs, err := getPackagesFunc(cfg, args, workDir)
+	if err != nil {
+		return err
+	}
+
+	if sbomFileFlag != "" {
+		pkgs = filtering.FilterPackagesByFile(pkgs, sbomFileFlag, workDir)
+	}
+
+	pkgs = filtering.FilterPackagesWithFilters(pkgs, sbomTypeFlag, sbomPMFlag, sbomRuleFlag, sbomNameFlag, "")
+	pkgs, err = applyInstalledVersionsFunc(pkgs, cfg, workDir)
+	if err != nil {
+		return err
+	}
+	pkgs = filtering.ApplyPackageGroups(pkgs, cfg)
+	pkgs = filtering.FilterByGroup(pkgs, sbomGroupFlag)
+
+	doc, omitted := sbom.BuildCycloneDX(pkgs, sbom.Options{ToolVersion: Version})
+	for _, p := range omitted {
+		unsupported.Add(p, sbomOmittedReason(p, cfg))
+	}
+
+	if err := sbom.WriteCycloneDXJSON(os.Stdout, doc); err != nil {
+		return err
+	}
+
+	display.PrintUnsupportedMessages(os.Stderr, unsupported.Messages())
+	display.PrintWarnings(os.Stderr, collector.Messages())
+	return nil
+}
+
+// sbomOmittedReason explains why a package was left out of the SBOM.
+//
+// Parameters:
+//   - p: The omitted package
+//   - cfg: Configuration passed to supervision.DeriveUnsupportedReason
+//
+// Returns:
+//   - string: The unsupported reason, prefixed with the supervision reason when one applies
+func sbomOmittedReason(p formats.Package, cfg *config.Config) string {
+	if supervision.ShouldTrackUnsupported(p.InstallStatus) {
+		if derived := supervision.DeriveUnsupportedReason(p, cfg, nil, false); derived != "" {
+			return derived + " Omitted from SBOM."
+		}
+	}
+	return fmt.Sprintf("No concrete installed version (%s) - omitted from SBOM.", p.InstallStatus)
+}
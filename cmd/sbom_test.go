@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/sbom"
+)
+
+// setSBOMFlagsForTest sets sbom flags to their defaults and restores them after the test.
+func setSBOMFlagsForTest(t *testing.T, dir string) {
+	t.Helper()
+	oldFormat, oldType, oldPM, oldRule := sbomFormatFlag, sbomTypeFlag, sbomPMFlag, sbomRuleFlag
+	oldName, oldGroup, oldConfig, oldDir, oldFile := sbomNameFlag, sbomGroupFlag, sbomConfigFlag, sbomDirFlag, sbomFileFlag
+	oldGetPackages, oldApplyInstalled := getPackagesFunc, applyInstalledVersionsFunc
+	t.Cleanup(func() {
+		sbomFormatFlag, sbomTypeFlag, sbomPMFlag, sbomRuleFlag = oldFormat, oldType, oldPM, oldRule
+		sbomNameFlag, sbomGroupFlag, sbomConfigFlag, sbomDirFlag, sbomFileFlag = oldName, oldGroup, oldConfig, oldDir, oldFile
+		getPackagesFunc, applyInstalledVersionsFunc = oldGetPackages, oldApplyInstalled
+	})
+
+	sbomFormatFlag = sbomFormatCycloneDXJSON
+	sbomTypeFlag, sbomPMFlag, sbomRuleFlag = "all", "all", "all"
+	sbomNameFlag, sbomGroupFlag, sbomConfigFlag, sbomFileFlag = "", "", "", ""
+	sbomDirFlag = dir
+}
+
+// TestRunSBOM tests the behavior of the sbom command.
+//
+// It verifies:
+//   - A CycloneDX JSON document is written to stdout
+//   - Packages with concrete installed versions become components with Package URLs
+//   - Packages without a concrete installed version are omitted and reported on stderr
+//   - Type filters apply before the document is built
+func TestRunSBOM(t *testing.T) {
+	setSBOMFlagsForTest(t, t.TempDir())
+
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "lodash", PackageType: "js", Rule: "npm", Type: "prod", Version: "4.17.21"},
+			{Name: "jest", PackageType: "js", Rule: "npm", Type: "dev", Version: "29.7.0"},
+			{Name: "left-pad", PackageType: "js", Rule: "npm", Type: "prod", Version: "1.3.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, baseDir string) ([]formats.Package, error) {
+		for i := range pkgs {
+			pkgs[i].InstalledVersion = pkgs[i].Version
+			pkgs[i].InstallStatus = lock.InstallStatusLockFound
+			if pkgs[i].Name == "left-pad" {
+				pkgs[i].InstalledVersion = "#N/A"
+				pkgs[i].InstallStatus = lock.InstallStatusNotInLock
+			}
+		}
+		return pkgs, nil
+	}
+
+	var stdout string
+	stderr := captureStderr(t, func() {
+		stdout = captureStdout(t, func() {
+			require.NoError(t, runSBOM(nil, nil))
+		})
+	})
+
+	var doc sbom.Document
+	require.NoError(t, json.Unmarshal([]byte(stdout), &doc))
+	assert.Equal(t, "CycloneDX", doc.BOMFormat)
+	require.Len(t, doc.Components, 2)
+	assert.Equal(t, "pkg:npm/jest@29.7.0", doc.Components[0].PURL)
+	assert.Equal(t, "pkg:npm/lodash@4.17.21", doc.Components[1].PURL)
+	assert.Contains(t, stderr, "No concrete installed version (NotInLock) - omitted from SBOM. (1 packages)")
+
+	sbomTypeFlag = "prod"
+	stdout = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			require.NoError(t, runSBOM(nil, nil))
+		})
+	})
+	doc = sbom.Document{}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &doc))
+	require.Len(t, doc.Components, 1)
+	assert.Equal(t, "lodash", doc.Components[0].Name)
+}
+
+// TestRunSBOMInvalidFormat tests the behavior of the sbom command with an unknown format.
+//
+// It verifies:
+//   - Unknown formats are rejected with a config error exit code
+func TestRunSBOMInvalidFormat(t *testing.T) {
+	setSBOMFlagsForTest(t, t.TempDir())
+	sbomFormatFlag = "spdx"
+
+	err := runSBOM(nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported SBOM format "spdx"`)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+}
@@ -1,29 +1,41 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/deptree"
+	"github.com/ajxudir/goupdate/pkg/display"
+	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/filtering"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/outdated"
 	"github.com/ajxudir/goupdate/pkg/output"
 	"github.com/ajxudir/goupdate/pkg/packages"
+	"github.com/ajxudir/goupdate/pkg/supervision"
 	"github.com/ajxudir/goupdate/pkg/verbose"
 	"github.com/spf13/cobra"
 )
 
 var (
-	scanDirFlag    string
-	scanConfigFlag string
-	scanOutputFlag string
-	scanFileFlag   string
+	scanDirFlag         string
+	scanConfigFlag      string
+	scanOutputFlag      string
+	scanFileFlag        string
+	scanDeepFlag        bool
+	scanConcurrencyFlag int
 )
 
 var detectFilesFunc = packages.DetectFiles
 
+var resolveTreeFunc = deptree.Resolve
+
 var scanCmd = &cobra.Command{
 	Use:   "scan",
 	Short: "Discover package manifest files",
@@ -36,6 +48,8 @@ func init() {
 	scanCmd.Flags().StringVarP(&scanConfigFlag, "config", "c", "", "Config file path")
 	scanCmd.Flags().StringVarP(&scanOutputFlag, "output", "o", "", "Output format: json, csv, xml (default: table)")
 	scanCmd.Flags().StringVarP(&scanFileFlag, "file", "f", "", "Filter by file path patterns (comma-separated, supports globs)")
+	scanCmd.Flags().BoolVar(&scanDeepFlag, "deep", false, "Resolve the full transitive dependency tree of each manifest with its package manager")
+	scanCmd.Flags().IntVar(&scanConcurrencyFlag, "concurrency", outdated.DefaultConcurrency(), "Maximum number of concurrent tree commands with --deep")
 }
 
 // runScan executes the scan command to discover package manifest files.
@@ -50,6 +64,13 @@ func init() {
 // Returns:
 //   - error: Returns error on config loading or detection failure
 func runScan(cmd *cobra.Command, args []string) error {
+	if err := output.ValidateUpdateOnlyFormat(getScanOutputFormat(), "scan"); err != nil {
+		return err
+	}
+	if err := output.ValidateShieldsSupport(getScanOutputFormat(), "scan"); err != nil {
+		return err
+	}
+
 	// Scan uses non-validating config load to avoid errors from malformed test fixtures
 	cfg, err := loadConfigWithoutValidation(scanConfigFlag, scanDirFlag)
 	if err != nil {
@@ -69,6 +90,10 @@ func runScan(cmd *cobra.Command, args []string) error {
 		detected = filtering.FilterDetectedFiles(detected, scanFileFlag, workDir)
 	}
 
+	if scanDeepFlag {
+		return runDeepScan(detected, workDir, cfg)
+	}
+
 	if len(detected) == 0 {
 		outputFormat := getScanOutputFormat()
 		if output.IsStructuredFormat(outputFormat) {
@@ -97,6 +122,140 @@ func runScan(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runDeepScan resolves and prints the full dependency set of the detected manifests (--deep).
+//
+// Rules without a tree command contribute their declared packages and are
+// reported as unsupported. Manifests whose tree command fails are reported
+// after the packages of the others.
+//
+// Parameters:
+//   - detected: Map of rule names to detected file paths
+//   - workDir: Working directory for relative path display
+//   - cfg: Configuration containing rule definitions
+//
+// Returns:
+//   - error: ExitPartialFailure when some manifests failed, ExitFailure when all did; nil otherwise
+func runDeepScan(detected map[string][]string, workDir string, cfg *config.Config) error {
+	unsupported := supervision.NewUnsupportedTracker()
+	pkgs, resolveErr := resolveTreeFunc(context.Background(), cfg, detected, deptree.Options{
+		Concurrency: scanConcurrencyFlag,
+		Unsupported: unsupported,
+	})
+
+	outputFormat := getScanOutputFormat()
+	if output.IsStructuredFormat(outputFormat) {
+		result := buildDeepScanResult(pkgs, workDir, unsupported.Messages(), resolveErr)
+		if err := output.WriteDeepScanResult(os.Stdout, outputFormat, result); err != nil {
+			return err
+		}
+	} else {
+		printDeepScan(pkgs, workDir)
+		display.PrintUnsupportedMessages(os.Stdout, unsupported.Messages())
+	}
+
+	if resolveErr == nil {
+		return nil
+	}
+	if len(pkgs) == 0 {
+		return errors.NewExitError(errors.ExitFailure, resolveErr)
+	}
+	return errors.NewExitError(errors.ExitPartialFailure, resolveErr)
+}
+
+// buildDeepScanResult converts a resolved dependency set into structured output.
+//
+// Parameters:
+//   - pkgs: Resolved packages
+//   - workDir: Working directory for relative source paths
+//   - unsupported: Unsupported tracker messages
+//   - resolveErr: Joined manifest failures, or nil
+//
+// Returns:
+//   - *output.DeepScanResult: Result with summary, packages, unsupported messages and errors
+func buildDeepScanResult(pkgs []formats.Package, workDir string, unsupported []string, resolveErr error) *output.DeepScanResult {
+	result := &output.DeepScanResult{
+		Summary:     output.DeepScanSummary{Directory: workDir, TotalPackages: len(pkgs)},
+		Packages:    make([]output.DeepScanPackage, 0, len(pkgs)),
+		Unsupported: unsupported,
+	}
+	for _, p := range pkgs {
+		if p.Direct {
+			result.Summary.DirectPackages++
+		} else {
+			result.Summary.TransitivePackages++
+		}
+		result.Packages = append(result.Packages, output.DeepScanPackage{
+			Rule:             p.Rule,
+			PM:               p.PackageType,
+			Name:             p.Name,
+			Version:          p.Version,
+			InstalledVersion: p.InstalledVersion,
+			Status:           p.InstallStatus,
+			Direct:           p.Direct,
+			Source:           scanRelativePath(workDir, p.Source),
+		})
+	}
+	if resolveErr != nil {
+		result.Errors = strings.Split(resolveErr.Error(), "\n")
+	}
+	return result
+}
+
+// printDeepScan outputs a resolved dependency set in table format to stdout.
+//
+// Parameters:
+//   - pkgs: Resolved packages, in the order returned by deptree.Resolve
+//   - workDir: Working directory for relative source paths
+func printDeepScan(pkgs []formats.Package, workDir string) {
+	if len(pkgs) == 0 {
+		fmt.Printf("No packages resolved in %s\n", workDir)
+		return
+	}
+	fmt.Printf("Resolved dependency tree in %s\n\n", workDir)
+
+	table := output.NewTable().
+		AddColumn("RULE").
+		AddColumn("PM").
+		AddColumn("DEPENDENCY").
+		AddColumn("NAME").
+		AddColumn("VERSION").
+		AddColumn("INSTALLED").
+		AddColumn("SOURCE")
+	direct := 0
+	for _, p := range pkgs {
+		table.UpdateWidths(p.Rule, p.PackageType, dependencyKind(p), p.Name, display.SafeDeclaredValue(p.Version), display.SafeInstalledValue(p.InstalledVersion), scanRelativePath(workDir, p.Source))
+		if p.Direct {
+			direct++
+		}
+	}
+
+	fmt.Println(table.HeaderRow())
+	fmt.Println(table.SeparatorRow())
+	for _, p := range pkgs {
+		fmt.Println(table.FormatRow(p.Rule, p.PackageType, dependencyKind(p), p.Name, display.SafeDeclaredValue(p.Version), display.SafeInstalledValue(p.InstalledVersion), scanRelativePath(workDir, p.Source)))
+	}
+	fmt.Printf("\nTotal packages: %d\n", len(pkgs))
+	fmt.Printf("Direct: %d\n", direct)
+	fmt.Printf("Transitive: %d\n", len(pkgs)-direct)
+}
+
+// dependencyKind returns "direct" or "transitive" for the DEPENDENCY column.
+func dependencyKind(p formats.Package) string {
+	if p.Direct {
+		return "direct"
+	}
+	return "transitive"
+}
+
+// scanRelativePath returns path relative to baseDir, or the base name when it cannot be made relative.
+func scanRelativePath(baseDir, path string) string {
+	relPath, err := filepath.Rel(baseDir, path)
+	if err != nil || relPath == "" {
+		return filepath.Base(path)
+	}
+	return relPath
+}
+
 // getScanOutputFormat determines the output format for scan results.
 //
 // Parses the --output flag value and returns the corresponding format.
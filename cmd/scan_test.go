@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +11,9 @@ import (
 	"testing"
 
 	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/deptree"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/output"
 	"github.com/ajxudir/goupdate/pkg/packages"
 	"github.com/stretchr/testify/assert"
@@ -759,3 +764,81 @@ func TestValidateFile(t *testing.T) {
 		assert.NotEmpty(t, errMsg)
 	})
 }
+
+// TestRunScanDeep tests the behavior of scan --deep.
+//
+// It verifies:
+//   - Detected manifests are passed to the tree resolver with the --concurrency value
+//   - The table lists direct and transitive packages with totals and unsupported messages
+//   - JSON output carries the summary, the direct flag and the resolution errors
+//   - Failed manifests exit with a partial failure, or a failure when nothing resolved
+func TestRunScanDeep(t *testing.T) {
+	tmpDir := t.TempDir()
+	manifest := filepath.Join(tmpDir, "web", "package.json")
+
+	oldDetect, oldResolve := detectFilesFunc, resolveTreeFunc
+	oldDir, oldConfig, oldOutput := scanDirFlag, scanConfigFlag, scanOutputFlag
+	oldDeep, oldConcurrency := scanDeepFlag, scanConcurrencyFlag
+	t.Cleanup(func() {
+		detectFilesFunc, resolveTreeFunc = oldDetect, oldResolve
+		scanDirFlag, scanConfigFlag, scanOutputFlag = oldDir, oldConfig, oldOutput
+		scanDeepFlag, scanConcurrencyFlag = oldDeep, oldConcurrency
+	})
+
+	detectFilesFunc = func(cfg *config.Config, baseDir string) (map[string][]string, error) {
+		return map[string][]string{"npm": {manifest}}, nil
+	}
+	var resolveErr error
+	var resolved []formats.Package
+	resolveTreeFunc = func(_ context.Context, _ *config.Config, detected map[string][]string, opts deptree.Options) ([]formats.Package, error) {
+		assert.Equal(t, map[string][]string{"npm": {manifest}}, detected)
+		assert.Equal(t, 3, opts.Concurrency)
+		opts.Unsupported.Add(formats.Package{Rule: "pnpm", PackageType: "js"}, deptree.UnsupportedReason)
+		return resolved, resolveErr
+	}
+
+	scanDirFlag = tmpDir
+	scanConfigFlag = ""
+	scanDeepFlag = true
+	scanConcurrencyFlag = 3
+	resolved = []formats.Package{
+		{Rule: "npm", PackageType: "js", Name: "react", Version: "18.2.0", Constraint: "^", InstalledVersion: "18.2.0", InstallStatus: "LockFound", Source: manifest, Direct: true},
+		{Rule: "npm", PackageType: "js", Name: "loose-envify", Version: "1.4.0", InstalledVersion: "1.4.0", InstallStatus: "LockFound", Source: manifest},
+	}
+
+	scanOutputFlag = ""
+	out := captureStdout(t, func() {
+		require.NoError(t, runScan(nil, nil))
+	})
+	assert.Contains(t, out, "DEPENDENCY")
+	assert.Regexp(t, `direct\s+react\s+18\.2\.0`, out)
+	assert.Regexp(t, `transitive\s+loose-envify\s+1\.4\.0`, out)
+	assert.Contains(t, out, filepath.Join("web", "package.json"))
+	assert.Contains(t, out, "Direct: 1")
+	assert.Contains(t, out, "Transitive: 1")
+	assert.Contains(t, out, deptree.UnsupportedReason)
+
+	scanOutputFlag = "json"
+	resolveErr = fmt.Errorf("api/package.json: failed to resolve dependency tree")
+	var err error
+	out = captureStdout(t, func() {
+		err = runScan(nil, nil)
+	})
+	assert.Equal(t, errors.ExitPartialFailure, errors.GetExitCode(err))
+	var result output.DeepScanResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Equal(t, output.DeepScanSummary{Directory: tmpDir, TotalPackages: 2, DirectPackages: 1, TransitivePackages: 1}, result.Summary)
+	require.Len(t, result.Packages, 2)
+	assert.True(t, result.Packages[0].Direct)
+	assert.Equal(t, filepath.Join("web", "package.json"), result.Packages[0].Source)
+	assert.Equal(t, []string{"api/package.json: failed to resolve dependency tree"}, result.Errors)
+	require.Len(t, result.Unsupported, 1)
+
+	resolved = nil
+	scanOutputFlag = ""
+	out = captureStdout(t, func() {
+		err = runScan(nil, nil)
+	})
+	assert.Contains(t, out, "No packages resolved")
+	assert.Equal(t, errors.ExitFailure, errors.GetExitCode(err))
+}
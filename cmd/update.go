@@ -5,19 +5,28 @@ import (
 	"context"
 	stderrors "errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ajxudir/goupdate/pkg/changelog"
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/constants"
 	"github.com/ajxudir/goupdate/pkg/display"
 	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/filtering"
 	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/notify"
 	"github.com/ajxudir/goupdate/pkg/outdated"
 	"github.com/ajxudir/goupdate/pkg/output"
 	"github.com/ajxudir/goupdate/pkg/preflight"
+	"github.com/ajxudir/goupdate/pkg/security"
 	"github.com/ajxudir/goupdate/pkg/supervision"
 	"github.com/ajxudir/goupdate/pkg/systemtest"
 	"github.com/ajxudir/goupdate/pkg/update"
@@ -41,6 +50,7 @@ var (
 	updatePatchFlag          bool
 	updateIncrementalFlag    bool
 	updateDryRunFlag         bool
+	updateDryRunVerifyFlag   bool
 	updateSkipLockRun        bool
 	updateYesFlag            bool
 	updateNoTimeoutFlag      bool
@@ -49,13 +59,66 @@ var (
 	updateOutputFlag         string
 	updateSkipSystemTests    bool
 	updateSystemTestModeFlag string
+	updateConcurrency        int
+	updateOnlySecurityFlag   bool
+	updatePlanOutFlag        string
+	updatePlanInFlag         string
+	updatePlanDiffFlag       []string
+	updateAllowPrerelease    bool
+	updateMaxBumpFlag        string
+	updateVersionRangeFlag   string
+	updateSelectFlag         string
+	updateShowDiffFlag       bool
+	updateFailOnFlag         string
+	updateStagedFlag         bool
+	updateInteractiveFlag    bool
+	updateSinceFlag          string
+	updateQuarantineDays     int
+	updateParallelGroups     int
+	updateIncludeTransitive  bool
+	updateStrictLockFlag     bool
+	updateChangelogFlag      bool
+	updateReportFileFlag     string
+	updateCommitFlag         bool
+	updateCommitMessageFlag  string
+	updateAllowDirtyFlag     bool
+	updateRequireCleanLock   bool
+	updateLockfileOnlyFlag   bool
+	updatePinFloatingFlag    bool
+	updateLimitFlag          int
+	updateLimitByFlag        string
+	updateGroupCommandsFlag  bool
+	updateDedupeFlag         bool
+	updateShowCommandsFlag   bool
+	updateChangedSinceGit    string
+	updateToFlag             string
+	updateNoVerifyFlag       bool
+	updateAllowYankedFlag    bool
+	updateProfileFlag        bool
 )
 
 // Testable function variables
 var updatePackageFunc = update.UpdatePackage
 var resolveUpdateCfgFunc = update.ResolveUpdateCfg
 var stdinReaderFunc = func() *bufio.Reader { return bufio.NewReader(os.Stdin) }
+var stdinIsTerminalFunc = func() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+var stderrIsTerminalFunc = func() bool {
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
 var writeUpdateResultFunc = output.WriteUpdateResult
+var advisoryProviderFunc = func() security.Provider { return security.NewOSVClient() }
+var changelogProviderFunc = func() changelog.Provider { return changelog.NewClient() }
+var sendNotificationFunc = notify.Send
+var writeReportFileFunc = update.WriteReportFile
+var checkCleanWorkTreeFunc = update.CheckCleanWorkTree
+var checkCleanLockFilesFunc = update.CheckCleanLockFiles
+
+// saveRunStateFunc records the update run for --since last-run; tests stub it
+var saveRunStateFunc = outdated.SaveRunState
 
 // ValidationRunner is an interface for running validation tests.
 // This allows mocking in tests.
@@ -80,19 +143,56 @@ func init() {
 	updateCmd.Flags().StringVarP(&updateConfigFlag, "config", "c", "", "Config file path")
 	updateCmd.Flags().StringVarP(&updateDirFlag, "directory", "d", ".", "Directory to scan")
 	updateCmd.Flags().StringVarP(&updateFileFlag, "file", "f", "", "Filter by file path patterns (comma-separated, supports globs)")
+	updateCmd.Flags().StringVar(&updateChangedSinceGit, "changed-since-git", "", "Only include packages from manifests changed since a git ref (git diff <ref>...HEAD), e.g. origin/main")
 	updateCmd.Flags().BoolVar(&updateMajorFlag, "major", false, "Force major upgrades (cascade to minor/patch)")
+	updateCmd.Flags().StringVar(&updateToFlag, "update-to", "", "Set the package selected with --name to this exact version, skipping candidate selection")
+	updateCmd.Flags().BoolVar(&updateNoVerifyFlag, "no-verify", false, "With --update-to, do not check that the version exists in the registry")
 	updateCmd.Flags().BoolVar(&updateMinorFlag, "minor", false, "Force minor upgrades (cascade to patch)")
 	updateCmd.Flags().BoolVar(&updatePatchFlag, "patch", false, "Force patch upgrades")
+	updateCmd.Flags().BoolVar(&updateAllowYankedFlag, "allow-yanked", false, "Allow targeting versions the registry marks yanked, deprecated, or retracted")
 	updateCmd.Flags().BoolVar(&updateDryRunFlag, "dry-run", false, "Plan updates without writing files")
+	updateCmd.Flags().BoolVar(&updateDryRunVerifyFlag, "dry-run-verify", false, "Plan updates like --dry-run, then apply each group's edits and run its lock command in a temporary copy of its manifests and lock files")
+	updateCmd.Flags().BoolVar(&updateShowCommandsFlag, "show-commands", false, "With --dry-run, list the lock commands a real run would execute")
 	updateCmd.Flags().BoolVar(&updateSkipLockRun, "skip-lock", false, "Skip running lock/install command")
+	updateCmd.Flags().BoolVar(&updateLockfileOnlyFlag, "lockfile-only", false, "Regenerate lock files to match the current manifests without looking up versions or changing declared versions")
+	updateCmd.Flags().BoolVar(&updateStrictLockFlag, "strict-lock", false, "Fail (and roll back the group) when the lock file does not hold the target version after the lock command")
 	updateCmd.Flags().BoolVarP(&updateYesFlag, "yes", "y", false, "Skip confirmation prompt")
+	updateCmd.Flags().BoolVar(&updateInteractiveFlag, "interactive", false, "Choose which packages to update and pick their target versions before applying (ignored with --yes)")
 	updateCmd.Flags().BoolVar(&updateNoTimeoutFlag, "no-timeout", false, "Disable command timeouts")
 	updateCmd.Flags().BoolVar(&updateContinueOnFail, "continue-on-fail", false, "Continue processing remaining packages after failures")
 	updateCmd.Flags().BoolVar(&updateIncrementalFlag, "incremental", false, "Force incremental updates (one version step at a time)")
+	updateCmd.Flags().BoolVar(&updateStagedFlag, "staged", false, "Apply every release up to the target one at a time, keeping the last version that passes")
+	updateCmd.Flags().IntVar(&updateParallelGroups, "parallel-groups", 1, "Maximum number of update groups of a rule applied at once; groups sharing a lock file still run one at a time")
+	updateCmd.Flags().BoolVar(&updateIncludeTransitive, "include-transitive", false, "Let lock commands update transitive dependencies; otherwise an untargeted package version change fails the update")
 	updateCmd.Flags().BoolVar(&updateSkipPreflight, "skip-preflight", false, "Skip pre-flight command validation")
-	updateCmd.Flags().StringVarP(&updateOutputFlag, "output", "o", "", "Output format: json, csv, xml (default: table)")
+	updateCmd.Flags().StringVarP(&updateOutputFlag, "output", "o", "", "Output format: json, csv, xml, junit, markdown (default: table)")
 	updateCmd.Flags().BoolVar(&updateSkipSystemTests, "skip-system-tests", false, "Skip all system tests (preflight and validation)")
 	updateCmd.Flags().StringVar(&updateSystemTestModeFlag, "system-test-mode", "", "Override system test run mode: after_each, after_all, none")
+	updateCmd.Flags().IntVar(&updateConcurrency, "concurrency", outdated.DefaultConcurrency(), "Maximum number of concurrent version lookups")
+	updateCmd.Flags().BoolVar(&updateOnlySecurityFlag, "only-security", false, "Only update packages affected by known security advisories (queries OSV.dev)")
+	updateCmd.Flags().StringVar(&updateVersionRangeFlag, "version-range", "", "Only include packages whose installed version satisfies a semver range (e.g. \"<2.0.0\")")
+	updateCmd.Flags().StringVar(&updateSelectFlag, "select", "", "Only include packages matching an expression (e.g. \"rule==npm && type==prod || gap>=major\")")
+	updateCmd.Flags().StringVar(&updateMaxBumpFlag, "max-bump", "", "Cap how far a target may move from the installed version (e.g. minor:2, patch:5)")
+	updateCmd.Flags().BoolVar(&updatePinFloatingFlag, "pin-floating", false, "Resolve NuGet floating versions (e.g. 13.0.*) to the highest matching release and rewrite them to it")
+	updateCmd.Flags().BoolVar(&updateAllowPrerelease, "allow-prerelease", false, "Include pre-release versions (alpha, beta, rc) as update targets")
+	updateCmd.Flags().StringVar(&updateFailOnFlag, "fail-on", string(errors.FailOnPartial), "Which outcomes exit non-zero: none, any-failure, any-unsupported, only-unsupported, partial")
+	updateCmd.Flags().BoolVar(&updateShowDiffFlag, "show-diff", false, "Show the manifest edit each update makes as a diff (collapsed in tables, full in structured output)")
+	updateCmd.Flags().BoolVar(&updateChangelogFlag, "changelog", false, "Fetch release notes for each planned update (GitHub Releases or the rule's changelog_url); failures are not fatal")
+	updateCmd.Flags().BoolVar(&updateCommitFlag, "commit", false, "Commit each successful package (or group) update to git on its own commit; requires a clean working tree")
+	updateCmd.Flags().StringVar(&updateCommitMessageFlag, "commit-message", update.DefaultCommitMessage, "Commit message template for --commit ({{name}}, {{from}}, {{to}}, {{rule}}, {{group}})")
+	updateCmd.Flags().BoolVar(&updateAllowDirtyFlag, "allow-dirty", false, "Allow --commit and --require-clean-lock with uncommitted changes in the working tree")
+	updateCmd.Flags().BoolVar(&updateRequireCleanLock, "require-clean-lock", false, "Refuse to update when a lock file of the selected packages has uncommitted git changes")
+	updateCmd.Flags().StringVar(&updatePlanOutFlag, "plan-out", "", "Write the update plan as JSON to this file (used by 'goupdate rollback --plan' and --plan-in)")
+	updateCmd.Flags().IntVar(&updateQuarantineDays, "quarantine-days", 0, "Skip versions published less than N days ago so targets are settled releases (overrides the rule's quarantine_days; looks up release dates)")
+	updateCmd.Flags().StringVar(&updateSinceFlag, "since", "", "Only consider versions released after an ISO date (2006-01-02) or \"last-run\" (looks up release dates)")
+	updateCmd.Flags().StringVar(&updateReportFileFlag, "report-file", "", "Write a JSON report of the run (config, results, system tests, unsupported packages, timings, exit reason) to this file, also when the run fails")
+	updateCmd.Flags().BoolVar(&updateProfileFlag, "profile", false, "Print how long each phase and rule took at the end of the run (also written to --report-file)")
+	updateCmd.Flags().IntVar(&updateLimitFlag, "limit", 0, "Update at most N packages this run; the remaining updates are listed as Deferred (0 = no limit)")
+	updateCmd.Flags().BoolVar(&updateGroupCommandsFlag, "group-commands", false, "Apply the manifest edits of all groups of a rule, then run its lock command once; a failure rolls back the whole rule")
+	updateCmd.Flags().BoolVar(&updateDedupeFlag, "dedupe", false, "Run each rule's update.dedupe command (e.g. npm dedupe) once its updates succeed")
+	updateCmd.Flags().StringVar(&updateLimitByFlag, "limit-by", update.LimitByGap, "Which updates --limit applies first: gap (largest version jump) or age (installed version superseded longest ago; looks up release dates)")
+	updateCmd.Flags().StringVar(&updatePlanInFlag, "plan-in", "", "Apply a plan written by --plan-out without looking up versions; fails if packages changed since")
+	updateCmd.Flags().StringSliceVar(&updatePlanDiffFlag, "plan-diff", nil, "Compare two plans written by --plan-out (old,new) and show added, removed and retargeted packages; nothing is updated")
 }
 
 // runUpdate executes the update command to apply package updates.
@@ -106,15 +206,98 @@ func init() {
 //
 // Returns:
 //   - error: Returns ExitError with appropriate code on failure
-func runUpdate(cmd *cobra.Command, args []string) error {
+func runUpdate(cmd *cobra.Command, args []string) (runErr error) {
+	// A verified dry run is a dry run: nothing in the working tree is written
+	dryRun := updateDryRunFlag || updateDryRunVerifyFlag
+
+	// The report is written however the run ends, so CI always has an artifact
+	report := update.NewRunReport(time.Now())
+	report.Config = updateReportConfig(nil, updateDirFlag, dryRun)
+	if updateReportFileFlag != "" {
+		defer func() { runErr = writeUpdateReport(updateReportFileFlag, report, runErr) }()
+	}
+
+	// Timers on a nil profile are no-ops, so phases are timed unconditionally
+	var profile *update.Profile
+	if updateProfileFlag {
+		profile = update.NewProfile(report.StartedAt)
+		defer func() {
+			report.Profile = profile.Report(time.Now())
+			printUpdateProfile(report.Profile, updateOutputFlag)
+		}()
+	}
+
 	// Validate flag compatibility before proceeding
 	outputFormat := output.ParseFormat(updateOutputFlag)
 	if err := output.ValidateStructuredOutputFlags(outputFormat, verboseFlag); err != nil {
 		return err
 	}
-	if err := output.ValidateUpdateStructuredFlags(outputFormat, updateYesFlag, updateDryRunFlag); err != nil {
+	// Comparing saved plans neither loads the configuration nor updates anything
+	if len(updatePlanDiffFlag) > 0 {
+		return runPlanDiff(args, outputFormat)
+	}
+	if err := output.ValidateUpdateStructuredFlags(outputFormat, updateYesFlag, dryRun); err != nil {
+		return err
+	}
+	if err := output.ValidateShieldsSupport(outputFormat, "update"); err != nil {
 		return err
 	}
+	maxBump, err := outdated.ParseMaxBump(updateMaxBumpFlag)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+	failOn, err := errors.ParseFailOn(updateFailOnFlag)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+	if err := validateInteractive(outputFormat); err != nil {
+		return err
+	}
+	if err := validatePlanIn(cmd, args); err != nil {
+		return err
+	}
+	if err := validateLockfileOnly(cmd, outputFormat); err != nil {
+		return err
+	}
+	if err := validateDryRunVerify(outputFormat); err != nil {
+		return err
+	}
+	if err := validateUpdateTo(cmd); err != nil {
+		return err
+	}
+	if updateShowCommandsFlag && !dryRun {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--show-commands requires --dry-run"))
+	}
+	if updateShowCommandsFlag && output.IsStructuredFormat(outputFormat) {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--show-commands cannot be combined with --output %s; the commands are only listed in table output", outputFormat))
+	}
+	if updateStrictLockFlag && updateSkipLockRun {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--strict-lock cannot be combined with --skip-lock; strict mode checks the lock file written by the lock command"))
+	}
+	if updateGroupCommandsFlag && (updateSkipLockRun || updateStagedFlag) {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--group-commands cannot be combined with --skip-lock or --staged; it batches the lock command of each rule"))
+	}
+	if updateAllowDirtyFlag && !updateCommitFlag && !updateRequireCleanLock {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--allow-dirty requires --commit or --require-clean-lock"))
+	}
+	if updateLimitFlag < 0 {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--limit must not be negative"))
+	}
+	if updateQuarantineDays < 0 {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--quarantine-days must not be negative"))
+	}
+	limitBy, err := update.ParseLimitBy(updateLimitByFlag)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+	versionRange := filtering.FilterOptions{VersionConstraint: updateVersionRangeFlag}
+	if err := versionRange.Validate(); err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+	selector, err := filtering.ParseSelect(updateSelectFlag)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
 
 	collector := &display.WarningCollector{}
 	restoreWarnings := warnings.SetWarningWriter(collector)
@@ -123,15 +306,32 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	workDir := updateDirFlag
 
+	profile.Enter(update.PhaseConfigLoad)
 	cfg, err := loadAndValidateConfig(updateConfigFlag, workDir)
+	profile.Leave()
 	if err != nil {
 		return err
 	}
 
 	workDir = resolveWorkingDir(workDir, cfg)
 	cfg.WorkingDir = workDir
+	report.Config = updateReportConfig(cfg, workDir, dryRun)
+	if err := checkCommitWorkTree(workDir, dryRun); err != nil {
+		return err
+	}
+	if err := checkCommitDedupe(cfg, dryRun); err != nil {
+		return err
+	}
 	cfg.NoTimeout = updateNoTimeoutFlag
+	cfg.AllowPrerelease = updateAllowPrerelease
+	cfg.IncludeTransitive = updateIncludeTransitive
 
+	since, err := outdated.ParseSince(updateSinceFlag, workDir)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+
+	profile.Enter(update.PhasePackageDiscovery)
 	packages, err := getPackagesFunc(cfg, args, workDir)
 	if err != nil {
 		return err
@@ -141,23 +341,74 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	if updateFileFlag != "" {
 		packages = filtering.FilterPackagesByFile(packages, updateFileFlag, workDir)
 	}
+	packages, err = filtering.FilterPackagesChangedSinceGit(packages, updateChangedSinceGit, workDir)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
 	packages = filtering.FilterPackagesWithFilters(packages, updateTypeFlag, updatePMFlag, updateRuleFlag, updateNameFlag, "")
 	packages, err = applyInstalledVersionsFunc(packages, cfg, workDir)
 	if err != nil {
 		return err
 	}
+	if versionRange.HasVersionConstraint() {
+		packages = filtering.FilterPackages(packages, versionRange)
+	}
 	packages = filtering.ApplyPackageGroups(packages, cfg)
 	packages = filtering.FilterByGroup(packages, updateGroupFlag)
+	// Expressions comparing gap need the latest version and are applied to the plans
+	if !selector.NeedsLatest() {
+		packages = filtering.SelectPackages(packages, selector)
+	}
 
 	for _, p := range packages {
 		if update.ShouldTrackUnsupported(p.InstallStatus) {
 			unsupported.Add(p, supervision.DeriveUnsupportedReason(p, cfg, nil, false))
 		}
 	}
+	profile.Leave()
+
+	// Build context for cancellation support
+	cmdCtx := context.Background()
+	if cmd != nil && cmd.Context() != nil {
+		cmdCtx = cmd.Context()
+	}
+
+	// Restrict to vulnerable packages and steer targets toward fixing versions
+	registryVersions := outdated.WithoutYanked(listNewerVersionsFunc, listYankedVersionsFunc, updateAllowYankedFlag)
+	listVersions := registryVersions
+	var advisories *security.Report
+	if updateOnlySecurityFlag {
+		advisories = security.Scan(cmdCtx, packages, advisoryProviderFunc(), unsupported)
+		packages = filtering.FilterPackages(packages, filtering.FilterOptions{SecurityOnly: true, Advisories: advisories})
+		listVersions = security.PreferFixedVersions(listVersions, advisories)
+	}
+	listVersions = outdated.WithReleasedSince(listVersions, listReleaseDatesFunc, since)
+	listVersions = outdated.WithQuarantine(listVersions, listReleaseDatesFunc, quarantineOverride(cmd, updateQuarantineDays), releaseAgeNowFunc())
+	if updateToFlag != "" {
+		// An exact version may be older than the installed one, so it is looked up among all published versions
+		publishedVersions := outdated.WithoutYanked(listAllVersionsFunc, listYankedVersionsFunc, updateAllowYankedFlag)
+		listVersions = outdated.ListNewerVersionsFunc(update.ExactVersionLister(update.VersionLister(publishedVersions), updateToFlag, !updateNoVerifyFlag))
+	}
+	// Repository URLs are only reported in structured output
+	var repositories *outdated.RepositoryIndex
+	if output.IsStructuredFormat(outputFormat) {
+		repositories = outdated.NewRepositoryIndex()
+	}
+	listVersions = outdated.WithRepository(listVersions, lookupRepositoryFunc, repositories)
 
-	if len(packages) == 0 {
+	// With --plan-in an empty package list is drift, reported by loadPlanIn
+	if len(packages) == 0 && updatePlanInFlag == "" {
+		report.SetResults(update.BuildUpdateOutput(nil, nil, collector.Messages(), nil, dryRun, outdated.UpdateSelectionFlags{}), unsupported.Messages())
 		if output.IsStructuredFormat(outputFormat) {
-			return printUpdateStructuredOutput(nil, collector.Messages(), nil, outputFormat)
+			if err := printUpdateStructuredOutput(nil, nil, nil, collector.Messages(), collector.Warnings(), nil, unsupported.Messages(), outputFormat, dryRun); err != nil {
+				return err
+			}
+			return handleUpdateResult(nil, &update.UpdateContext{}, unsupported, failOn)
+		}
+		if updateOnlySecurityFlag {
+			fmt.Println("No packages affected by known security advisories")
+			display.PrintUnsupportedMessages(os.Stdout, unsupported.Messages())
+			return handleUpdateResult(nil, &update.UpdateContext{}, unsupported, failOn)
 		}
 		display.PrintNoPackagesMessageWithFilters(os.Stdout, updateTypeFlag, updatePMFlag, updateRuleFlag)
 		return nil
@@ -168,148 +419,1215 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		validation := preflight.ValidatePackages(packages, cfg)
 		if validation.HasErrors() {
 			verbose.Infof("Exit code %d (config error): preflight validation failed - %s", errors.ExitConfigError, validation.ErrorMessage())
-			return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("%s\n  💡 Options:\n     --skip-preflight     Bypass validation if commands are available through other means\n     --rule <name>        Filter to specific rules (e.g., --rule npm)\n     enabled: false       Disable unused rules in your config file", validation.ErrorMessage()))
+			return errors.NewExitErrorf(errors.ExitConfigError, "%s\n  💡 Options:\n     --skip-preflight     Bypass validation if commands are available through other means\n     --rule <name>        Filter to specific rules (e.g., --rule npm)\n     enabled: false       Disable unused rules in your config file", validation.ErrorMessage()).WithCause(validation.Err())
 		}
 	}
 
+	if err := checkCleanLockFiles(cfg, packages, workDir, dryRun); err != nil {
+		return err
+	}
+
+	if updateLockfileOnlyFlag {
+		return runLockfileRefresh(cmdCtx, cfg, args, workDir, packages, unsupported, dryRun)
+	}
+
 	// Create system test runner and run preflight tests
 	systemTestRunner := createSystemTestRunner(cfg, workDir)
-	if err := runPreflightTests(systemTestRunner); err != nil {
+	if err := checkCommitAfterAll(systemTestRunner, dryRun); err != nil {
+		return err
+	}
+	if systemTestRunner != nil {
+		profile.Enter(update.PhaseSystemTests)
+	}
+	if err := runPreflightTests(systemTestRunner, dryRun); err != nil {
+		return err
+	}
+	profile.Leave()
+
+	// Build selection flags
+	selection := outdated.UpdateSelectionFlags{Major: updateMajorFlag, Minor: updateMinorFlag, Patch: updatePatchFlag, MaxBump: maxBump}
+
+	// The untargeted-change guard compares every package of the updated rules, not only the filtered ones
+	rules := packageRules(packages)
+	baselinePackages, err := reloadPackages(cfg, args, workDir, rules)
+	if err != nil {
+		return err
+	}
+	baseline := update.SnapshotVersions(baselinePackages)
+
+	// Create update context
+	updateCtx := update.NewUpdateContext(cfg, workDir, unsupported).
+		WithFlags(dryRun, updateContinueOnFail, updateSkipLockRun).
+		WithBaseline(baseline).
+		WithSystemTestRunner(systemTestRunner).
+		WithSelection(selection).
+		WithSkipSystemTests(updateSkipSystemTests).
+		WithIncrementalMode(updateIncrementalFlag).
+		WithStaged(updateStagedFlag).
+		WithStrictLock(updateStrictLockFlag).
+		WithGroupCommands(updateGroupCommandsFlag).
+		WithDedupe(updateDedupeFlag).
+		WithGroupConcurrency(updateParallelGroups).
+		WithUpdaterFunc(updatePackageFunc).
+		WithCommitter(newUpdateCommitter(workDir, dryRun)).
+		WithContentBackups(update.NewContentBackups()).
+		WithReloadList(func() ([]formats.Package, error) {
+			return reloadPackages(cfg, args, workDir, rules)
+		})
+
+	useStructuredOutput := output.IsStructuredFormat(outputFormat)
+
+	// A saved plan replaces version discovery; otherwise look up versions and plan now
+	var groupedPlans []*update.PlannedUpdate
+	var resolvedPkgs []formats.Package
+	profile.Enter(update.PhaseVersionLookup)
+	if updatePlanInFlag != "" {
+		groupedPlans, err = loadPlanIn(cfg, packages)
+		if err != nil {
+			return err
+		}
+		resolvedPkgs = make([]formats.Package, 0, len(groupedPlans))
+		for _, plan := range groupedPlans {
+			resolvedPkgs = append(resolvedPkgs, plan.Res.Pkg)
+		}
+		if !useStructuredOutput {
+			fmt.Printf("\nApplying plan from %s (%d package(s), version lookup skipped)\n", updatePlanInFlag, len(groupedPlans))
+		}
+	} else {
+		selectors := targetSelectors(cmdCtx, cfg, packages, advisories, unsupported)
+		groupedPlans, resolvedPkgs, err = discoverUpdatePlans(cmdCtx, packages, updateCtx, selection, listVersions, selectors, useStructuredOutput)
+		if err != nil {
+			return err
+		}
+		if selector.NeedsLatest() {
+			groupedPlans, resolvedPkgs = selectPlans(groupedPlans, selector)
+		}
+		for _, plan := range groupedPlans {
+			plan.Res.Pkg.Repository = repositories.Get(plan.Res.Pkg)
+		}
+	}
+	profile.Leave()
+
+	// Hold back everything past --limit; deferred plans are listed but not applied
+	if updateLimitFlag > 0 {
+		if deferred := update.LimitPlans(groupedPlans, updateLimitFlag, limitBy, limitPlanAge(cmdCtx, cfg, workDir)); deferred > 0 {
+			verbose.Infof("Deferred %d package(s) past --limit %d (by %s)", deferred, updateLimitFlag, limitBy)
+		}
+	}
+
+	// Let the user pick packages and targets; the selection replaces the confirmation prompt
+	pendingUpdates := update.CountPendingUpdates(groupedPlans)
+	selectedInteractively := updateInteractiveFlag && !updateYesFlag && pendingUpdates > 0
+	if selectedInteractively {
+		selected, proceed, selectErr := update.SelectPlans(stdinReaderFunc(), os.Stdout, groupedPlans)
+		if selectErr != nil {
+			fmt.Println("\nUpdate cancelled (input not available).")
+			return nil
+		}
+		if !proceed {
+			fmt.Println("Update cancelled.")
+			return nil
+		}
+		groupedPlans = selected
+		pendingUpdates = update.CountPendingUpdates(groupedPlans)
+		if pendingUpdates == 0 {
+			fmt.Println("No packages selected. Nothing to update.")
+			return nil
+		}
+		fmt.Printf("\n%d package(s) selected.\n\n", pendingUpdates)
+	}
+
+	if updateShowDiffFlag {
+		update.AttachDiffs(groupedPlans, cfg, workDir)
+	}
+	if updateChangelogFlag {
+		update.AttachChangelogs(cmdCtx, groupedPlans, cfg, changelogProviderFunc())
+	}
+
+	// Calculate column widths
+	table := update.BuildUpdateTableFromPackages(resolvedPkgs, selection)
+
+	// Show preview and confirm for non-dry-run updates
+	if !dryRun && !useStructuredOutput && !selectedInteractively && pendingUpdates > 0 {
+		update.PrintUpdatePreview(groupedPlans, table, selection)
+
+		if !confirmUpdate(pendingUpdates) {
+			return nil
+		}
+		fmt.Println()
+	}
+
+	// Save the plan before mutating files so it survives a failed run
+	if updatePlanOutFlag != "" {
+		if err := update.WritePlanFile(updatePlanOutFlag, update.NewPlanFile(groupedPlans, workDir)); err != nil {
+			return err
+		}
+		verbose.Infof("Wrote update plan with %d packages to %s", len(groupedPlans), updatePlanOutFlag)
+	}
+
+	// SIGINT stops scheduling new package updates; completed updates are kept
+	// and a partially applied group is rolled back before returning
+	execCtx, stopExecSignals := signal.NotifyContext(cmdCtx, os.Interrupt)
+	defer stopExecSignals()
+
+	var results []update.UpdateResult
+	updateCtx.WithTable(table).WithContext(execCtx)
+
+	// Create callbacks for live output
+	callbacks := update.ExecutionCallbacks{
+		OnResultReady: func(res update.UpdateResult, dryRun bool) {
+			update.PrintUpdateRow(res, table, dryRun, selection)
+			update.PrintCollapsedDiff(os.Stdout, res.Diff)
+			update.PrintChangelog(os.Stdout, res.Changelog, updateShowDiffFlag || verbose.IsEnabled())
+		},
+		DeriveReason: supervision.DeriveUnsupportedReason,
+	}
+
+	if useStructuredOutput {
+		// Structured output has no live rows, so an interactive stderr gets a
+		// progress bar with the time remaining instead
+		var updateProgress *output.Progress
+		if stderrIsTerminalFunc() {
+			updateProgress = output.NewProgress(os.Stderr, len(groupedPlans), "Updating packages")
+		}
+		profile.Enter(update.PhaseExecution)
+		update.ProcessGroupedPlansWithProgress(updateCtx, groupedPlans, &results, updateProgress, callbacks)
+		profile.Leave()
+		updateProgress.Done()
+
+		var errStrings []string
+		for _, e := range updateCtx.Failures {
+			errStrings = append(errStrings, e.Error())
+		}
+		if err := printUpdateStructuredOutput(results, updateCtx.SystemTestFailures, updateCtx.DedupeSteps, collector.Messages(), warningDetails(collector, packages), errStrings, unsupported.Messages(), outputFormat, dryRun); err != nil {
+			return err
+		}
+	} else {
+		// Print header and process with live output
+		fmt.Println(table.HeaderRow())
+		fmt.Println(table.SeparatorRow())
+		_ = os.Stdout.Sync()
+
+		profile.Enter(update.PhaseExecution)
+		update.ProcessGroupedPlansLive(updateCtx, groupedPlans, &results, callbacks)
+		profile.Leave()
+
+		fmt.Printf("\nTotal packages: %d\n", len(results))
+
+		if len(updateCtx.DedupeSteps) > 0 {
+			printDedupeSteps(updateCtx.DedupeSteps)
+		}
+		if updateShowCommandsFlag {
+			printDryRunCommands(updateCtx.DryRunCommands)
+		}
+		if updateDryRunVerifyFlag {
+			profile.Enter(update.PhaseExecution)
+			verifications := update.VerifyPlans(updateCtx, groupedPlans)
+			profile.Leave()
+			printDryRunVerifications(verifications)
+		}
+
+		// Run after_all system tests
+		var afterAllTestResult *systemtest.Result
+		if systemTestRunner != nil && systemTestRunner.ShouldRunAfterAll() && !updateSkipSystemTests && !dryRun {
+			var afterAllErr error
+			profile.Enter(update.PhaseSystemTests)
+			afterAllTestResult, afterAllErr = runAfterAllValidation(systemTestRunner, groupedPlans, results, updateCtx)
+			profile.Leave()
+			if afterAllErr != nil {
+				updateCtx.AppendFailure(afterAllErr)
+			}
+		}
+
+		// Print summaries
+		update.PrintUpdateSummary(results, dryRun, wrapSystemTestResult(afterAllTestResult))
+		display.PrintUnsupportedMessages(os.Stdout, unsupported.Messages())
+		display.PrintWarnings(os.Stdout, collector.Messages())
+		update.PrintUpdateErrorsWithHints(updateCtx.Failures, errors.EnhanceErrorWithHint)
+		printUpdateTotals(results, updateCtx, unsupported, failOn, dryRun)
+	}
+
+	var errStrings []string
+	for _, e := range updateCtx.Failures {
+		errStrings = append(errStrings, e.Error())
+	}
+	profile.SetResults(results)
+	report.SetResults(update.BuildUpdateOutput(results, updateCtx.SystemTestFailures, collector.Messages(), errStrings, dryRun, selection), unsupported.Messages())
+	report.SetSummary(update.BuildSummary(results, updateCtx, unsupported, updateContinueOnFail, failOn))
+
+	resultErr := handleUpdateResult(results, updateCtx, unsupported, failOn)
+	if resultErr == nil && !dryRun {
+		recordUpdateRun(workDir)
+	}
+	notifyUpdateCompletion(cmdCtx, cfg.Notify, results, unsupported, dryRun)
+	return resultErr
+}
+
+// targetSelectors builds the selectors for the rules' selection strategies.
+//
+// The lowest-non-vulnerable strategy needs the advisories of its packages.
+// With --only-security they were already scanned; otherwise only packages of
+// rules using the strategy are scanned, so other rules cost no advisory
+// lookups.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - cfg: Loaded configuration
+//   - packages: Packages about to be planned
+//   - advisories: Advisories from --only-security; nil when it is not set
+//   - unsupported: Tracker for packages without advisory data
+//
+// Returns:
+//   - outdated.TargetSelectors: Selectors keyed by strategy name
+func targetSelectors(ctx context.Context, cfg *config.Config, packages []formats.Package, advisories *security.Report, unsupported *supervision.UnsupportedTracker) outdated.TargetSelectors {
+	if advisories == nil && updateToFlag == "" {
+		var scan []formats.Package
+		for _, p := range packages {
+			if config.SelectionStrategyFor(p, cfg) == config.SelectionStrategyLowestNonVulnerable {
+				scan = append(scan, p)
+			}
+		}
+		if len(scan) > 0 {
+			advisories = security.Scan(ctx, scan, advisoryProviderFunc(), unsupported)
+		}
+	}
+
+	return outdated.TargetSelectors{
+		config.SelectionStrategyLowestNonVulnerable: security.LowestNonVulnerable(advisories),
+		config.SelectionStrategyMostRecentByDate:    outdated.MostRecentByDate(listReleaseDatesFunc),
+	}
+}
+
+// discoverUpdatePlans looks up available versions and builds the grouped update plans.
+//
+// In table mode the outdated-style check table is printed while versions are
+// looked up, followed by the summary of the checking phase. SIGINT cancels
+// in-flight lookups.
+//
+// Parameters:
+//   - cmdCtx: Command context for cancellation
+//   - packages: Filtered packages with installed versions
+//   - updateCtx: Update context carrying configuration and selection
+//   - selection: Version selection flags
+//   - listVersions: Version lister used for the lookups
+//   - selectors: Selectors for the rules' selection strategies
+//   - useStructuredOutput: true to suppress the check table
+//
+// Returns:
+//   - []*update.PlannedUpdate: Grouped plans in execution order
+//   - []formats.Package: Resolved packages in plan order (used for table widths)
+//   - error: ExitError when the lookup was interrupted
+func discoverUpdatePlans(cmdCtx context.Context, packages []formats.Package, updateCtx *update.UpdateContext, selection outdated.UpdateSelectionFlags, listVersions outdated.ListNewerVersionsFunc, selectors outdated.TargetSelectors, useStructuredOutput bool) ([]*update.PlannedUpdate, []formats.Package, error) {
+	resolved := update.ResolvePackagePlans(packages, updateCtx.Cfg, resolveUpdateCfgFunc)
+	update.SortResolvedPlans(resolved)
+	resolvedPkgs := update.ExtractPackagesFromPlans(resolved)
+
+	// Build grouped plans with progress feedback for table mode
+	opts := update.PlanningOptions{IncrementalMode: updateIncrementalFlag, Concurrency: updateConcurrency, PinFloating: updatePinFloatingFlag, UpdateTo: updateToFlag, Selectors: selectors}
+
+	// Build outdated-style table for progress display during planning phase
+	var outdatedCheckTable *output.Table
+	var progress *output.Progress
+	if !useStructuredOutput && len(resolvedPkgs) > 0 {
+		fmt.Println()
+		fmt.Println("Checking for available updates...")
+		fmt.Println(strings.Repeat("═", 70))
+
+		outdatedCheckTable = update.BuildOutdatedCheckTable(resolvedPkgs, selection)
+		fmt.Println(outdatedCheckTable.HeaderRow())
+		fmt.Println(outdatedCheckTable.SeparatorRow())
+
+		// Rows print in plan order and can wait on a slow lookup, so a live count of
+		// finished lookups is kept on stderr and cleared around each row
+		var progressMu sync.Mutex
+		lastResolved := 0
+		if stderrIsTerminalFunc() {
+			progress = output.NewProgress(os.Stderr, countVersionLookups(resolved, opts), "Resolving versions")
+			opts.OnVersionsResolved = func(p formats.Package, versions []string, err error, resolved, total int) {
+				progressMu.Lock()
+				defer progressMu.Unlock()
+				lastResolved = resolved
+				progress.SetCurrent(resolved)
+			}
+		}
+
+		opts.OnPackageChecked = func(plan *update.PlannedUpdate, current, total int) {
+			progressMu.Lock()
+			defer progressMu.Unlock()
+			progress.Clear()
+			update.PrintOutdatedCheckRow(plan, outdatedCheckTable, selection)
+			if progress != nil && lastResolved > 0 && current < total {
+				progress.SetCurrent(lastResolved)
+			}
+		}
+	}
+
+	// SIGINT cancels in-flight version lookups; signal handling is released again
+	// before the confirmation prompt so Ctrl-C there still exits immediately
+	lookupCtx, stopLookupSignals := signal.NotifyContext(cmdCtx, os.Interrupt)
+	groupedPlans := update.BuildGroupedPlans(lookupCtx, resolved, updateCtx, opts, update.VersionLister(listVersions), supervision.DeriveUnsupportedReason)
+	progress.Clear()
+	lookupErr := lookupCtx.Err()
+	stopLookupSignals()
+	if lookupErr != nil {
+		return nil, nil, errors.NewExitError(errors.ExitFailure, fmt.Errorf("update interrupted during version lookup: %w", lookupErr))
+	}
+
+	if !useStructuredOutput && len(resolvedPkgs) > 0 {
+		// Print summary for the outdated checking phase
+		summaryData := make([]update.OutdatedResultData, len(groupedPlans))
+		for i, plan := range groupedPlans {
+			summaryData[i] = update.OutdatedResultData{
+				Status: update.DeriveOutdatedStatus(plan),
+				Major:  plan.Res.Major,
+				Minor:  plan.Res.Minor,
+				Patch:  plan.Res.Patch,
+				Err:    plan.Res.Err,
+			}
+		}
+		fmt.Printf("\nTotal packages: %d\n", len(groupedPlans))
+		counts := update.ComputeSummaryFromOutdatedResults(summaryData)
+		update.PrintUpdateSummaryLines(counts, update.SummaryModeOutdated)
+	}
+
+	return groupedPlans, resolvedPkgs, nil
+}
+
+// selectPlans keeps the plans whose package matches a --select expression.
+//
+// Used for expressions comparing gap, which can only be evaluated once the
+// versions are known: each plan's package gets its newest available version
+// as LatestVersion before it is matched. Plans that failed to resolve are
+// kept so the failure is still reported.
+//
+// Parameters:
+//   - plans: Grouped plans from discoverUpdatePlans
+//   - selector: Parsed --select expression
+//
+// Returns:
+//   - []*update.PlannedUpdate: Matching plans in their original order
+//   - []formats.Package: Packages of the matching plans (used for table widths)
+func selectPlans(plans []*update.PlannedUpdate, selector *filtering.Selector) ([]*update.PlannedUpdate, []formats.Package) {
+	kept := make([]*update.PlannedUpdate, 0, len(plans))
+	pkgs := make([]formats.Package, 0, len(plans))
+	for _, plan := range plans {
+		plan.Res.Pkg.LatestVersion = newestAvailable(plan.Res.Major, plan.Res.Minor, plan.Res.Patch)
+		if plan.Res.Err == nil && !selector.Match(plan.Res.Pkg) {
+			continue
+		}
+		kept = append(kept, plan)
+		pkgs = append(pkgs, plan.Res.Pkg)
+	}
+	return kept, pkgs
+}
+
+// countVersionLookups counts the resolved plans that need a version lookup.
+//
+// Parameters:
+//   - resolved: Resolved plans for the filtered packages
+//   - opts: Planning options deciding which plans are looked up
+//
+// Returns:
+//   - int: Number of packages whose available versions are looked up
+func countVersionLookups(resolved []update.ResolvedUpdatePlan, opts update.PlanningOptions) int {
+	count := 0
+	for _, plan := range resolved {
+		if opts.NeedsVersionLookup(plan) {
+			count++
+		}
+	}
+	return count
+}
+
+// loadPlanIn reads the --plan-in plan file and checks it still applies.
+//
+// The plan's update commands must match the current configuration, and every
+// pending package must still be at the declared and installed versions recorded
+// in the plan. Nothing is written when the check fails.
+//
+// Parameters:
+//   - cfg: Current configuration
+//   - packages: Freshly loaded packages with installed versions
+//
+// Returns:
+//   - []*update.PlannedUpdate: Plans in the order they were written
+//   - error: ExitError with ExitConfigError for unreadable or stale plans and ExitFailure for drift
+func loadPlanIn(cfg *config.Config, packages []formats.Package) ([]*update.PlannedUpdate, error) {
+	planFile, err := update.ReadPlanFile(updatePlanInFlag)
+	if err != nil {
+		return nil, errors.NewExitError(errors.ExitConfigError, err)
+	}
+
+	plans, err := planFile.PlannedUpdates(cfg, resolveUpdateCfgFunc)
+	if err != nil {
+		return nil, errors.NewExitError(errors.ExitConfigError, err)
+	}
+
+	if err := update.CheckPlanDrift(plans, packages); err != nil {
+		verbose.Infof("Exit code %d (failure): plan %s no longer matches the working tree", errors.ExitFailure, updatePlanInFlag)
+		return nil, errors.NewExitError(errors.ExitFailure, fmt.Errorf("%w\n  💡 Create a fresh plan with: goupdate update --dry-run --plan-out %s", err, updatePlanInFlag))
+	}
+
+	verbose.Infof("Loaded update plan with %d packages from %s", len(plans), updatePlanInFlag)
+	return plans, nil
+}
+
+// validatePlanIn checks that --plan-in is not combined with planning flags.
+//
+// A saved plan already fixes which packages are updated and to which versions,
+// so flags that select packages or versions would silently be ignored.
+//
+// Parameters:
+//   - cmd: The running update command; may be nil
+//   - args: File arguments passed to the update command
+//
+// Returns:
+//   - error: ExitError with ExitConfigError naming the first conflicting flag; nil otherwise
+func validatePlanIn(cmd *cobra.Command, args []string) error {
+	if updatePlanInFlag == "" {
+		return nil
+	}
+
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--plan-out", updatePlanOutFlag != ""},
+		{"--interactive", updateInteractiveFlag},
+		{"--only-security", updateOnlySecurityFlag},
+		{"--major", updateMajorFlag},
+		{"--minor", updateMinorFlag},
+		{"--patch", updatePatchFlag},
+		{"--incremental", updateIncrementalFlag},
+		{"--max-bump", updateMaxBumpFlag != ""},
+		{"--allow-prerelease", updateAllowPrerelease},
+		{"--pin-floating", updatePinFloatingFlag},
+		{"--version-range", updateVersionRangeFlag != ""},
+		{"--select", updateSelectFlag != ""},
+		{"--since", updateSinceFlag != ""},
+		{"--quarantine-days", quarantineDaysSet(cmd)},
+		{"--limit", updateLimitFlag != 0},
+		{"--type", updateTypeFlag != "all"},
+		{"--package-manager", updatePMFlag != "all"},
+		{"--rule", updateRuleFlag != "all"},
+		{"--name", updateNameFlag != ""},
+		{"--group", updateGroupFlag != ""},
+		{"--file", updateFileFlag != ""},
+		{"--changed-since-git", updateChangedSinceGit != ""},
+		{"file arguments", len(args) > 0},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--plan-in cannot be combined with %s; the plan already selects packages and versions", c.flag))
+		}
+	}
+	return nil
+}
+
+// runPlanDiff compares two plan files written by --plan-out (--plan-diff).
+//
+// It is a pure comparison of the saved plans: the configuration is not
+// loaded and no versions are looked up, so a regenerated plan can be reviewed
+// for unexpected new targets before it is applied with --plan-in.
+//
+// It performs the following operations:
+//   - Step 1: Reject conflicting flags and output formats
+//   - Step 2: Read the old and new plan files
+//   - Step 3: Print the differences as a table or structured output
+//
+// Parameters:
+//   - args: File arguments passed to the update command
+//   - format: Requested output format
+//
+// Returns:
+//   - error: ExitError with ExitConfigError for conflicting flags or unreadable plans; nil otherwise
+func runPlanDiff(args []string, format output.Format) error {
+	if err := validatePlanDiff(args, format); err != nil {
 		return err
 	}
 
-	// Build selection flags
-	selection := outdated.UpdateSelectionFlags{Major: updateMajorFlag, Minor: updateMinorFlag, Patch: updatePatchFlag}
-
-	// Resolve and build plans
-	resolved := update.ResolvePackagePlans(packages, cfg, resolveUpdateCfgFunc)
-	update.SortResolvedPlans(resolved)
-	resolvedPkgs := update.ExtractPackagesFromPlans(resolved)
+	oldPath, newPath := updatePlanDiffFlag[0], updatePlanDiffFlag[1]
+	oldPlan, err := update.ReadPlanFile(oldPath)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+	newPlan, err := update.ReadPlanFile(newPath)
+	if err != nil {
+		return errors.NewExitError(errors.ExitConfigError, err)
+	}
+
+	result := buildPlanDiffResult(update.DiffPlans(oldPlan, newPlan), oldPlan, newPlan)
+	result.Summary.OldPlan, result.Summary.NewPlan = oldPath, newPath
+	if output.IsStructuredFormat(format) {
+		return output.WritePlanDiffResult(os.Stdout, format, result)
+	}
+
+	printPlanDiff(result)
+	return nil
+}
+
+// validatePlanDiff checks the --plan-diff arguments and rejects flags it would ignore.
+//
+// Parameters:
+//   - args: File arguments passed to the update command
+//   - format: Requested output format
+//
+// Returns:
+//   - error: ExitError with ExitConfigError describing the problem; nil otherwise
+func validatePlanDiff(args []string, format output.Format) error {
+	if len(updatePlanDiffFlag) != 2 {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--plan-diff takes exactly two plan files (old,new), got %d", len(updatePlanDiffFlag)))
+	}
+	if format == output.FormatJUnit || format == output.FormatMarkdown {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--output %s is not supported with --plan-diff; use --output table/json/csv/xml", format))
+	}
+
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--plan-in", updatePlanInFlag != ""},
+		{"--plan-out", updatePlanOutFlag != ""},
+		{"--lockfile-only", updateLockfileOnlyFlag},
+		{"--interactive", updateInteractiveFlag},
+		{"file arguments", len(args) > 0},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--plan-diff cannot be combined with %s; it only compares two saved plans", c.flag))
+		}
+	}
+	return nil
+}
+
+// buildPlanDiffResult converts plan differences into structured output.
+//
+// Parameters:
+//   - diff: Differences returned by update.DiffPlans
+//   - oldPlan: The old plan, whose working directory removed packages are shown relative to
+//   - newPlan: The new plan, whose working directory other packages are shown relative to
+//
+// Returns:
+//   - *output.PlanDiffResult: Result with summary counts and one entry per change
+func buildPlanDiffResult(diff *update.PlanDiff, oldPlan, newPlan *update.PlanFile) *output.PlanDiffResult {
+	result := &output.PlanDiffResult{
+		Summary: output.PlanDiffSummary{Unchanged: diff.Unchanged},
+		Changes: make([]output.PlanDiffEntry, 0, len(diff.Changes)),
+	}
+	for _, change := range diff.Changes {
+		workDir := newPlan.WorkDir
+		switch change.Change {
+		case update.PlanChangeAdded:
+			result.Summary.Added++
+		case update.PlanChangeRemoved:
+			result.Summary.Removed++
+			workDir = oldPlan.WorkDir
+		case update.PlanChangeChanged:
+			result.Summary.Changed++
+		}
+
+		p := change.Package
+		result.Changes = append(result.Changes, output.PlanDiffEntry{
+			Rule:      p.Rule,
+			PM:        p.PackageType,
+			Type:      p.Type,
+			Name:      p.Name,
+			Change:    change.Change,
+			Original:  change.Original,
+			OldTarget: change.OldTarget,
+			NewTarget: change.NewTarget,
+			Source:    scanRelativePath(workDir, p.Source),
+		})
+	}
+	return result
+}
+
+// printPlanDiff outputs plan differences in table format to stdout.
+//
+// Parameters:
+//   - result: Plan diff result built by buildPlanDiffResult
+func printPlanDiff(result *output.PlanDiffResult) {
+	summary := result.Summary
+	fmt.Printf("Comparing plan %s with %s\n\n", summary.OldPlan, summary.NewPlan)
+
+	if len(result.Changes) == 0 {
+		fmt.Printf("Plans are identical (%d pending update(s))\n", summary.Unchanged)
+		return
+	}
+
+	table := output.NewTable().
+		AddColumn("RULE").
+		AddColumn("PM").
+		AddColumn("TYPE").
+		AddColumn("NAME").
+		AddColumn("CHANGE").
+		AddColumn("ORIGINAL").
+		AddColumn("OLD TARGET").
+		AddColumn("NEW TARGET")
+	rows := make([][]string, 0, len(result.Changes))
+	for _, entry := range result.Changes {
+		row := []string{
+			entry.Rule,
+			entry.PM,
+			entry.Type,
+			entry.Name,
+			entry.Change,
+			display.SafeDeclaredValue(entry.Original),
+			display.SafeVersionValue(entry.OldTarget, "-"),
+			display.SafeVersionValue(entry.NewTarget, "-"),
+		}
+		table.UpdateWidths(row...)
+		rows = append(rows, row)
+	}
+
+	fmt.Println(table.HeaderRow())
+	fmt.Println(table.SeparatorRow())
+	for _, row := range rows {
+		fmt.Println(table.FormatRow(row...))
+	}
+	fmt.Printf("\nAdded: %d, Removed: %d, Changed: %d, Unchanged: %d\n", summary.Added, summary.Removed, summary.Changed, summary.Unchanged)
+}
+
+// validateLockfileOnly rejects flags that have no meaning with --lockfile-only.
+//
+// A lock refresh keeps every declared version, so flags that pick versions or
+// change how updates are applied would silently be ignored.
+//
+// Parameters:
+//   - cmd: The running update command; may be nil
+//   - format: Requested output format
+//
+// Returns:
+//   - error: ExitError with ExitConfigError naming the first conflicting flag; nil otherwise
+func validateLockfileOnly(cmd *cobra.Command, format output.Format) error {
+	if !updateLockfileOnlyFlag {
+		return nil
+	}
+
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--skip-lock", updateSkipLockRun},
+		{"--plan-in", updatePlanInFlag != ""},
+		{"--plan-out", updatePlanOutFlag != ""},
+		{"--interactive", updateInteractiveFlag},
+		{"--only-security", updateOnlySecurityFlag},
+		{"--major", updateMajorFlag},
+		{"--minor", updateMinorFlag},
+		{"--patch", updatePatchFlag},
+		{"--incremental", updateIncrementalFlag},
+		{"--staged", updateStagedFlag},
+		{"--strict-lock", updateStrictLockFlag},
+		{"--group-commands", updateGroupCommandsFlag},
+		{"--dedupe", updateDedupeFlag},
+		{"--max-bump", updateMaxBumpFlag != ""},
+		{"--allow-prerelease", updateAllowPrerelease},
+		{"--pin-floating", updatePinFloatingFlag},
+		{"--since", updateSinceFlag != ""},
+		{"--quarantine-days", quarantineDaysSet(cmd)},
+		{"--update-to", updateToFlag != ""},
+		{"--show-diff", updateShowDiffFlag},
+		{"--changelog", updateChangelogFlag},
+		{"--commit", updateCommitFlag},
+		{"--output " + string(format), output.IsStructuredFormat(format)},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--lockfile-only cannot be combined with %s; it only reruns lock commands against the current manifests", c.flag))
+		}
+	}
+	return nil
+}
+
+// validateDryRunVerify rejects flags that cannot be combined with --dry-run-verify.
+//
+// Verification exists to run lock commands, so skipping them leaves nothing to
+// verify; the per-group outcome is only reported in table output.
+//
+// Parameters:
+//   - format: Requested output format
+//
+// Returns:
+//   - error: ExitError with ExitConfigError naming the first conflicting flag; nil otherwise
+func validateDryRunVerify(format output.Format) error {
+	if !updateDryRunVerifyFlag {
+		return nil
+	}
+
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--skip-lock", updateSkipLockRun},
+		{"--lockfile-only", updateLockfileOnlyFlag},
+		{"--output " + string(format), output.IsStructuredFormat(format)},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--dry-run-verify cannot be combined with %s; it runs each group's lock command in a temporary copy", c.flag))
+		}
+	}
+	return nil
+}
+
+// validateUpdateTo checks that --update-to targets a single package and is not
+// combined with flags that select versions.
+//
+// Parameters:
+//   - cmd: The running update command; may be nil
+//
+// Returns:
+//   - error: ExitError with ExitConfigError describing the first problem; nil otherwise
+func validateUpdateTo(cmd *cobra.Command) error {
+	if updateToFlag == "" {
+		if updateNoVerifyFlag {
+			return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--no-verify requires --update-to"))
+		}
+		return nil
+	}
+
+	names := 0
+	for _, name := range strings.Split(updateNameFlag, ",") {
+		if strings.TrimSpace(name) != "" {
+			names++
+		}
+	}
+	if names != 1 {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--update-to requires exactly one --name value"))
+	}
+
+	conflicts := []struct {
+		flag string
+		set  bool
+	}{
+		{"--major", updateMajorFlag},
+		{"--minor", updateMinorFlag},
+		{"--patch", updatePatchFlag},
+		{"--incremental", updateIncrementalFlag},
+		{"--max-bump", updateMaxBumpFlag != ""},
+		{"--since", updateSinceFlag != ""},
+		{"--quarantine-days", quarantineDaysSet(cmd)},
+		{"--only-security", updateOnlySecurityFlag},
+		{"--plan-in", updatePlanInFlag != ""},
+	}
+	for _, c := range conflicts {
+		if c.set {
+			return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--update-to cannot be combined with %s; it sets the target version directly", c.flag))
+		}
+	}
+	return nil
+}
+
+// printDryRunCommands prints the lock commands a dry run skipped.
+//
+// Parameters:
+//   - commands: The skipped commands, as recorded in update.UpdateContext.DryRunCommands
+func printDryRunCommands(commands []update.DryRunCommand) {
+	fmt.Println()
+	fmt.Println("Commands a real run would execute:")
+	if len(commands) == 0 {
+		fmt.Println("  No lock commands")
+		return
+	}
+	for _, command := range commands {
+		fmt.Printf("  %s [%s]\n", command, strings.Join(command.Packages, ", "))
+	}
+}
+
+// printDedupeSteps prints the outcome of each --dedupe command.
+//
+// Parameters:
+//   - steps: The dedupe runs, as recorded in update.UpdateContext.DedupeSteps
+func printDedupeSteps(steps []update.DedupeStep) {
+	fmt.Println()
+	fmt.Println("Dedupe:")
+	for _, step := range steps {
+		switch {
+		case step.Err == nil:
+			fmt.Printf("  ✓ %s: %s in %s (%s)\n", step.Rule, step.Command, step.Dir, step.Duration.Round(time.Millisecond))
+		case step.Critical:
+			fmt.Printf("  ✗ %s: %v; rolled back %d package(s)\n", step.Rule, step.Err, step.RolledBack)
+		default:
+			fmt.Printf("  ⚠ %s: %v; updates kept\n", step.Rule, step.Err)
+		}
+	}
+}
+
+// printUpdateProfile prints the --profile timing breakdown.
+//
+// Structured output keeps stdout machine-readable, so the breakdown goes to
+// stderr there; table output prints it after the summary.
+//
+// Parameters:
+//   - profile: The breakdown; nil prints nothing
+//   - outputFlag: The --output value of the run
+func printUpdateProfile(profile *update.ProfileReport, outputFlag string) {
+	w := os.Stdout
+	if output.IsStructuredFormat(output.ParseFormat(outputFlag)) {
+		w = os.Stderr
+	}
+	update.PrintProfile(w, profile)
+}
+
+// printDryRunVerifications prints the outcome of rehearsing each update group.
+//
+// Parameters:
+//   - verifications: One entry per rehearsed group, as returned by update.VerifyPlans
+func printDryRunVerifications(verifications []update.GroupVerification) {
+	fmt.Println()
+	fmt.Println("Dry-run verification:")
+	if len(verifications) == 0 {
+		fmt.Println("  No pending updates to verify")
+		return
+	}
+
+	table := output.NewTable().
+		AddColumn("RULE").
+		AddColumn("GROUP").
+		AddColumn("STATUS").
+		AddColumn("DETAILS")
+
+	type row struct{ rule, group, status, details string }
+	rows := make([]row, 0, len(verifications))
+	verified, failed := 0, 0
+	for _, v := range verifications {
+		entry := row{rule: v.Rule, group: v.Group}
+		switch {
+		case v.Err != nil:
+			failed++
+			entry.status = display.FormatStatus(constants.StatusFailed)
+			entry.details = v.Err.Error()
+		case v.Skipped != "":
+			entry.status = display.FormatStatus(lock.InstallStatusNotConfigured)
+			entry.details = v.Skipped
+		default:
+			verified++
+			entry.status = display.FormatStatus(constants.StatusVerified)
+			entry.details = fmt.Sprintf("%s (%s)", strings.Join(v.Packages, ", "), v.Duration.Round(time.Millisecond))
+		}
+		table.UpdateWidths(entry.rule, entry.group, entry.status, entry.details)
+		rows = append(rows, entry)
+	}
+
+	table.Print()
+	for _, entry := range rows {
+		fmt.Println(table.FormatRow(entry.rule, entry.group, entry.status, entry.details))
+	}
+	fmt.Printf("\nLock commands succeeded for %d of %d group(s), %d failed\n", verified, len(verifications), failed)
+}
+
+// runLockfileRefresh regenerates lock files without changing manifests.
+//
+// It performs the following operations:
+//   - Step 1: List the locations to refresh; a dry run stops here
+//   - Step 2: Confirm the lock commands unless --yes is set, then run each rule's lock command once per manifest directory
+//   - Step 3: Reload the packages and compare installed versions with the baseline
+//   - Step 4: Print the lock files and installed versions that changed
+//
+// Parameters:
+//   - cmdCtx: Context for cancellation
+//   - cfg: Loaded configuration
+//   - args: File arguments, used to reload the same packages
+//   - workDir: Working directory
+//   - packages: Filtered packages whose lock files are refreshed
+//   - unsupported: Tracker of packages that cannot be updated
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
+//
+// Returns:
+//   - error: ExitError with ExitFailure when a lock command failed; nil otherwise
+func runLockfileRefresh(cmdCtx context.Context, cfg *config.Config, args []string, workDir string, packages []formats.Package, unsupported *supervision.UnsupportedTracker, dryRun bool) error {
+	planned := update.RefreshLockFiles(update.NewUpdateContext(cfg, workDir, unsupported).WithFlags(true, false, false), packages)
+	if dryRun {
+		printLockRefreshes(planned, nil, workDir, true)
+		display.PrintUnsupportedMessages(os.Stdout, unsupported.Messages())
+		return nil
+	}
+	if !confirmLockRefresh(len(planned)) {
+		return nil
+	}
+	fmt.Println()
+
+	updateCtx := update.NewUpdateContext(cfg, workDir, unsupported).
+		WithFlags(false, false, false).
+		WithContext(cmdCtx)
 	baseline := update.SnapshotVersions(packages)
+	refreshes := update.RefreshLockFiles(updateCtx, packages)
 
-	// Build context for cancellation support
-	cmdCtx := context.Background()
-	if cmd != nil && cmd.Context() != nil {
-		cmdCtx = cmd.Context()
+	reloaded, err := reloadPackages(cfg, args, workDir, packageRules(packages))
+	if err != nil {
+		updateCtx.AppendFailure(fmt.Errorf("failed to reload packages after lock refresh: %w", err))
 	}
+	changes := update.InstalledChanges(baseline, reloaded)
 
-	// Create update context
-	updateCtx := update.NewUpdateContext(cfg, workDir, unsupported).
-		WithFlags(updateDryRunFlag, updateContinueOnFail, updateSkipLockRun).
-		WithBaseline(baseline).
-		WithSystemTestRunner(systemTestRunner).
-		WithSelection(selection).
-		WithSkipSystemTests(updateSkipSystemTests).
-		WithIncrementalMode(updateIncrementalFlag).
-		WithUpdaterFunc(updatePackageFunc).
-		WithReloadList(func() ([]formats.Package, error) {
-			return reloadPackages(cfg, args, workDir, unsupported)
-		})
+	printLockRefreshes(refreshes, changes, workDir, false)
+	display.PrintUnsupportedMessages(os.Stdout, unsupported.Messages())
 
-	// Build grouped plans with progress feedback for table mode
-	opts := update.PlanningOptions{IncrementalMode: updateIncrementalFlag}
-	useStructuredOutput := output.IsStructuredFormat(outputFormat)
+	if err := updateCtx.Err(); err != nil {
+		return err
+	}
+	if len(updateCtx.Failures) > 0 {
+		return errors.NewExitError(errors.ExitFailure, stderrors.Join(updateCtx.Failures...))
+	}
+	return nil
+}
 
-	// Build outdated-style table for progress display during planning phase
-	var outdatedCheckTable *output.Table
-	if !useStructuredOutput && len(resolvedPkgs) > 0 {
-		fmt.Println()
-		fmt.Println("Checking for available updates...")
-		fmt.Println(strings.Repeat("═", 70))
+// confirmLockRefresh asks before running lock commands in dirs directories.
+//
+// Parameters:
+//   - dirs: Number of rule and directory pairs whose lock command will run
+//
+// Returns:
+//   - bool: True if the user confirms or --yes is set
+func confirmLockRefresh(dirs int) bool {
+	if updateYesFlag {
+		fmt.Printf("\nLock commands will run in %d location(s). Proceeding (--yes)...\n", dirs)
+		return true
+	}
 
-		outdatedCheckTable = update.BuildOutdatedCheckTable(resolvedPkgs, selection)
-		fmt.Println(outdatedCheckTable.HeaderRow())
-		fmt.Println(outdatedCheckTable.SeparatorRow())
+	fmt.Printf("\nLock commands will run in %d location(s). Continue? [y/N]: ", dirs)
+	response, err := stdinReaderFunc().ReadString('\n')
+	if err != nil {
+		fmt.Println("\nLock refresh cancelled (input not available).")
+		return false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "y" && response != "yes" {
+		fmt.Println("Lock refresh cancelled.")
+		return false
+	}
+	return true
+}
 
-		opts.OnPackageChecked = func(plan *update.PlannedUpdate, current, total int) {
-			update.PrintOutdatedCheckRow(plan, outdatedCheckTable, selection)
+// printLockRefreshes prints the outcome of a lock refresh.
+//
+// Parameters:
+//   - refreshes: One entry per rule and directory
+//   - changes: Packages whose installed version changed
+//   - workDir: Directory paths are shown relative to
+//   - dryRun: Whether the lock commands were only planned
+func printLockRefreshes(refreshes []update.LockRefresh, changes []update.InstalledChange, workDir string, dryRun bool) {
+	table := output.NewTable().
+		AddColumn("RULE").
+		AddColumn("DIRECTORY").
+		AddColumn("STATUS").
+		AddColumn("LOCK FILES")
+
+	type row struct{ rule, dir, status, files string }
+	rows := make([]row, 0, len(refreshes))
+	changed, failed := 0, 0
+	for _, r := range refreshes {
+		entry := row{rule: r.Rule, dir: relativeToWorkDir(r.Dir, workDir)}
+		switch {
+		case r.Err != nil:
+			failed++
+			entry.status = display.FormatStatus(constants.StatusFailed)
+			entry.files = r.Err.Error()
+		case r.Skipped != "":
+			entry.status = display.FormatStatus(lock.InstallStatusNotConfigured)
+			entry.files = r.Skipped
+		case dryRun:
+			entry.status = display.FormatStatus(constants.StatusPlanned)
+		case len(r.Changed) > 0:
+			changed++
+			entry.status = display.FormatStatus(constants.StatusUpdated)
+			files := make([]string, 0, len(r.Changed))
+			for _, path := range r.Changed {
+				files = append(files, relativeToWorkDir(path, workDir))
+			}
+			entry.files = strings.Join(files, ", ")
+		default:
+			entry.status = display.FormatStatus(constants.StatusUpToDate)
 		}
+		table.UpdateWidths(entry.rule, entry.dir, entry.status, entry.files)
+		rows = append(rows, entry)
 	}
 
-	groupedPlans := update.BuildGroupedPlans(cmdCtx, resolved, updateCtx, opts, listNewerVersionsFunc, supervision.DeriveUnsupportedReason)
+	table.Print()
+	for _, entry := range rows {
+		fmt.Println(table.FormatRow(entry.rule, entry.dir, entry.status, entry.files))
+	}
 
-	if !useStructuredOutput && len(resolvedPkgs) > 0 {
-		// Print summary for the outdated checking phase
-		summaryData := make([]update.OutdatedResultData, len(groupedPlans))
-		for i, plan := range groupedPlans {
-			summaryData[i] = update.OutdatedResultData{
-				Status: update.DeriveOutdatedStatus(plan),
-				Major:  plan.Res.Major,
-				Minor:  plan.Res.Minor,
-				Patch:  plan.Res.Patch,
-				Err:    plan.Res.Err,
-			}
+	if len(changes) > 0 {
+		fmt.Println()
+		fmt.Println("Installed versions changed:")
+		for _, c := range changes {
+			fmt.Printf("  • %s (%s/%s) %s → %s\n", c.Pkg.Name, c.Pkg.PackageType, c.Pkg.Rule, display.SafeInstalledValue(c.From), display.SafeInstalledValue(c.To))
 		}
-		fmt.Printf("\nTotal packages: %d\n", len(groupedPlans))
-		counts := update.ComputeSummaryFromOutdatedResults(summaryData)
-		update.PrintUpdateSummaryLines(counts, update.SummaryModeOutdated)
 	}
 
-	// Calculate column widths
-	table := update.BuildUpdateTableFromPackages(resolvedPkgs, selection)
-	pendingUpdates := update.CountPendingUpdates(groupedPlans)
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("Dry run: lock commands would run in %d location(s)\n", len(refreshes))
+		return
+	}
+	fmt.Printf("Lock files changed in %d of %d location(s), %d failed\n", changed, len(refreshes), failed)
+}
 
-	// Show preview and confirm for non-dry-run updates
-	if !updateDryRunFlag && !useStructuredOutput && pendingUpdates > 0 {
-		update.PrintUpdatePreview(groupedPlans, table, selection)
+// relativeToWorkDir returns path relative to workDir when it lies inside it.
+//
+// Parameters:
+//   - path: Path to shorten
+//   - workDir: Working directory
+//
+// Returns:
+//   - string: The relative path, "." for workDir itself, or path unchanged
+func relativeToWorkDir(path, workDir string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	absWork, err := filepath.Abs(workDir)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(absWork, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
 
-		if !confirmUpdate(pendingUpdates) {
-			return nil
-		}
-		fmt.Println()
+// notifyUpdateCompletion posts the run summary to the configured webhook.
+//
+// Delivery failures are reported on stderr and never change the exit code
+// derived by handleUpdateResult. Dry runs are only reported when
+// notify.on_dry_run is set.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - notifyCfg: The notify configuration; nil disables notifications
+//   - results: Final update results
+//   - unsupported: Tracker of unsupported packages
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
+func notifyUpdateCompletion(ctx context.Context, notifyCfg *config.NotifyCfg, results []update.UpdateResult, unsupported *supervision.UnsupportedTracker, dryRun bool) {
+	if notifyCfg == nil || (dryRun && !notifyCfg.OnDryRun) {
+		return
 	}
 
-	var results []update.UpdateResult
-	updateCtx.WithTable(table)
+	summary := notify.BuildSummary(results, unsupported, dryRun)
+	if err := sendNotificationFunc(ctx, notifyCfg, summary); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Notification not delivered: %v\n", constants.IconWarning, err)
+		return
+	}
+	verbose.Infof("Sent update notification to %s", notify.RedactURL(os.ExpandEnv(notifyCfg.URL)))
+}
 
-	// Create callbacks for live output
-	callbacks := update.ExecutionCallbacks{
-		OnResultReady: func(res update.UpdateResult, dryRun bool) {
-			update.PrintUpdateRow(res, table, dryRun, selection)
-		},
-		DeriveReason: supervision.DeriveUnsupportedReason,
+// recordUpdateRun writes the run time to the state file for --since last-run.
+//
+// Write failures are reported on stderr and never change the exit code.
+//
+// Parameters:
+//   - workDir: Working directory holding the state file
+func recordUpdateRun(workDir string) {
+	if err := saveRunStateFunc(workDir, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Run state not recorded: %v\n", constants.IconWarning, err)
+		return
 	}
+	verbose.Infof("Recorded update run in %s", filepath.Join(workDir, outdated.StateFileName))
+}
 
-	if useStructuredOutput {
-		// Process without progress indicator - structured output suppresses stderr
-		// Progress messages are only shown in table (interactive) mode
-		update.ProcessGroupedPlansWithProgress(updateCtx, groupedPlans, &results, nil, callbacks)
+// updateReportConfig builds the config section of the --report-file report.
+//
+// Parameters:
+//   - cfg: Loaded configuration; nil before it is loaded
+//   - workDir: Working directory of the run
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
+//
+// Returns:
+//   - update.ReportConfig: The run's options
+func updateReportConfig(cfg *config.Config, workDir string, dryRun bool) update.ReportConfig {
+	selection := outdated.UpdateSelectionFlags{Major: updateMajorFlag, Minor: updateMinorFlag, Patch: updatePatchFlag}
+	rc := update.NewReportConfig(cfg, workDir, selection)
+	rc.ConfigFile = updateConfigFlag
+	rc.FailOn = updateFailOnFlag
+	rc.DryRun = dryRun
+	rc.ContinueOnFail = updateContinueOnFail
+	rc.SkipLock = updateSkipLockRun
+	rc.Staged = updateStagedFlag
+	rc.Incremental = updateIncrementalFlag
+	rc.StrictLock = updateStrictLockFlag
+	rc.GroupCommands = updateGroupCommandsFlag
+	rc.Limit = updateLimitFlag
+	return rc
+}
 
-		var errStrings []string
-		for _, e := range updateCtx.Failures {
-			errStrings = append(errStrings, e.Error())
+// limitPlanAge returns the age lookup --limit-by age ranks updates with.
+//
+// The age of a plan is the time since the first version newer than its
+// installed one was published. Lookups that fail or find no dates leave the
+// age unknown, so those updates rank after the ones with a known age.
+//
+// Parameters:
+//   - ctx: Context for the release date lookups
+//   - cfg: The global configuration
+//   - workDir: Base directory for command execution
+//
+// Returns:
+//   - update.PlanAgeFunc: Age lookup backed by listReleaseDatesFunc
+func limitPlanAge(ctx context.Context, cfg *config.Config, workDir string) update.PlanAgeFunc {
+	now := releaseAgeNowFunc()
+	return func(plan *update.PlannedUpdate) (time.Duration, bool) {
+		dates, err := listReleaseDatesFunc(ctx, plan.Res.Pkg, cfg, workDir)
+		if err != nil {
+			verbose.Debugf("Release dates unavailable for %s: %v", plan.Res.Pkg.Name, err)
+			return 0, false
 		}
-		if err := printUpdateStructuredOutput(results, collector.Messages(), errStrings, outputFormat); err != nil {
-			return err
+		since, ok := dates.SupersededSince(outdated.CurrentVersionForOutdated(plan.Res.Pkg))
+		if !ok {
+			return 0, false
 		}
-	} else {
-		// Print header and process with live output
-		fmt.Println(table.HeaderRow())
-		fmt.Println(table.SeparatorRow())
-		_ = os.Stdout.Sync()
-
-		update.ProcessGroupedPlansLive(updateCtx, groupedPlans, &results, callbacks)
-
-		fmt.Printf("\nTotal packages: %d\n", len(results))
+		return now.Sub(since), true
+	}
+}
 
-		// Run after_all system tests
-		var afterAllTestResult *systemtest.Result
-		if systemTestRunner != nil && systemTestRunner.ShouldRunAfterAll() && !updateSkipSystemTests && !updateDryRunFlag {
-			var afterAllErr error
-			afterAllTestResult, afterAllErr = runAfterAllValidation(systemTestRunner, results, updateCtx)
-			if afterAllErr != nil {
-				updateCtx.AppendFailure(afterAllErr)
-			}
+// writeUpdateReport finishes the run report and writes it to path.
+//
+// A report that cannot be written fails an otherwise successful run, since CI
+// relies on the artifact; when the run already failed, its error is kept and
+// the write failure is reported on stderr.
+//
+// Parameters:
+//   - path: Destination of --report-file
+//   - report: The report filled in during the run
+//   - runErr: The error the run returns; nil on success
+//
+// Returns:
+//   - error: runErr, or an ExitError when only the report write failed
+func writeUpdateReport(path string, report *update.RunReport, runErr error) error {
+	report.Finish(runErr, time.Now())
+	if err := writeReportFileFunc(path, report); err != nil {
+		if runErr != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", constants.IconWarning, err)
+			return runErr
 		}
-
-		// Print summaries
-		update.PrintUpdateSummary(results, updateDryRunFlag, wrapSystemTestResult(afterAllTestResult))
-		display.PrintUnsupportedMessages(os.Stdout, unsupported.Messages())
-		display.PrintWarnings(os.Stdout, collector.Messages())
-		update.PrintUpdateErrorsWithHints(updateCtx.Failures, errors.EnhanceErrorWithHint)
+		return errors.NewExitError(errors.ExitFailure, err)
 	}
+	verbose.Infof("Wrote run report to %s", path)
+	return runErr
+}
 
-	return handleUpdateResult(results, updateCtx)
+// validateInteractive checks that --interactive can prompt the user.
+//
+// --yes bypasses the selection, so it is not validated then. Otherwise the
+// selection needs table output and a terminal on stdin.
+//
+// Parameters:
+//   - format: The parsed --output format
+//
+// Returns:
+//   - error: ExitError with ExitConfigError when the selection cannot be shown; nil otherwise
+func validateInteractive(format output.Format) error {
+	if !updateInteractiveFlag || updateYesFlag {
+		return nil
+	}
+	if output.IsStructuredFormat(format) {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--interactive cannot be combined with --output %s", format))
+	}
+	if !stdinIsTerminalFunc() {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--interactive requires a terminal on stdin; use --yes for non-interactive runs"))
+	}
+	return nil
 }
 
 // confirmUpdate prompts the user to confirm the update.
@@ -375,11 +1693,12 @@ func createSystemTestRunner(cfg *config.Config, workDir string) *systemtest.Runn
 //
 // Parameters:
 //   - runner: System test runner instance
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
 //
 // Returns:
 //   - error: Returns ExitError if tests fail critically with stop_on_fail
-func runPreflightTests(runner *systemtest.Runner) error {
-	if runner == nil || !runner.ShouldRunPreflight() || updateSkipSystemTests || updateDryRunFlag {
+func runPreflightTests(runner *systemtest.Runner, dryRun bool) error {
+	if runner == nil || !runner.ShouldRunPreflight() || updateSkipSystemTests || dryRun {
 		return nil
 	}
 
@@ -409,20 +1728,131 @@ func runPreflightTests(runner *systemtest.Runner) error {
 	return nil
 }
 
+// checkCommitWorkTree refuses --commit on a dirty working tree.
+//
+// Dry runs never commit and --allow-dirty skips the check.
+//
+// Parameters:
+//   - workDir: Directory inside the git working tree
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
+//
+// Returns:
+//   - error: Config error when the working tree is dirty or not a git repository
+func checkCommitWorkTree(workDir string, dryRun bool) error {
+	if !updateCommitFlag || dryRun || updateAllowDirtyFlag {
+		return nil
+	}
+	if err := checkCleanWorkTreeFunc(workDir); err != nil {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--commit needs a clean git working tree: %w (commit or stash your changes, or pass --allow-dirty)", err))
+	}
+	return nil
+}
+
+// checkCommitDedupe refuses --commit with --dedupe when a rule's dedupe command is critical.
+//
+// Dedupe runs after the rule's groups are committed, and a critical failure
+// rolls back every update of the rule, which would undo committed files.
+//
+// Parameters:
+//   - cfg: Configuration with each rule's update.dedupe settings
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
+//
+// Returns:
+//   - error: Config error naming the rules with a critical dedupe command
+func checkCommitDedupe(cfg *config.Config, dryRun bool) error {
+	if !updateCommitFlag || !updateDedupeFlag || dryRun || updateSkipLockRun {
+		return nil
+	}
+	var critical []string
+	for key, rule := range cfg.Rules {
+		if rule.Update != nil && rule.Update.Dedupe != nil && rule.Update.Dedupe.Critical {
+			critical = append(critical, key)
+		}
+	}
+	if len(critical) == 0 {
+		return nil
+	}
+	sort.Strings(critical)
+	return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--commit cannot be combined with --dedupe when update.dedupe is critical (rules: %s); its rollback would undo committed files", strings.Join(critical, ", ")))
+}
+
+// checkCommitAfterAll refuses --commit when after_all system tests can roll back the run.
+//
+// A critical after_all failure with stop_on_fail restores every updated file,
+// but with --commit each group is already committed, so the rollback would
+// leave the working tree out of step with HEAD. Dry runs and
+// --skip-system-tests never run the tests.
+//
+// Parameters:
+//   - runner: System test runner instance; nil when no system tests are configured
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
+//
+// Returns:
+//   - error: Config error when --commit meets after_all tests that stop on failure
+func checkCommitAfterAll(runner *systemtest.Runner, dryRun bool) error {
+	if !updateCommitFlag || dryRun || updateSkipSystemTests || runner == nil {
+		return nil
+	}
+	if runner.ShouldRunAfterAll() && runner.StopOnFail() {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--commit cannot be combined with after_all system tests that stop on failure; a failure would roll back files that are already committed\n  💡 Use --system-test-mode after_each, set stop_on_fail: false, or pass --skip-system-tests"))
+	}
+	return nil
+}
+
+// checkCleanLockFiles refuses to update over uncommitted lock file changes (--require-clean-lock).
+//
+// Dry runs never run lock commands and --allow-dirty skips the check.
+//
+// Parameters:
+//   - cfg: Configuration used to find each rule's lock files
+//   - packages: Packages selected for the update
+//   - workDir: Directory inside the git working tree
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
+//
+// Returns:
+//   - error: Config error listing the dirty lock files, or when git cannot be run
+func checkCleanLockFiles(cfg *config.Config, packages []formats.Package, workDir string, dryRun bool) error {
+	if !updateRequireCleanLock || dryRun || updateAllowDirtyFlag {
+		return nil
+	}
+	if err := checkCleanLockFilesFunc(cfg, packages, workDir); err != nil {
+		return errors.NewExitError(errors.ExitConfigError, fmt.Errorf("--require-clean-lock: %w\n  💡 Commit or stash the lock file changes, or pass --allow-dirty", err))
+	}
+	return nil
+}
+
+// newUpdateCommitter returns the committer for --commit, or nil when commit mode is off.
+//
+// Parameters:
+//   - workDir: Directory inside the git working tree
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
+//
+// Returns:
+//   - *update.Committer: Committer using --commit-message; nil without --commit or on a dry run
+func newUpdateCommitter(workDir string, dryRun bool) *update.Committer {
+	if !updateCommitFlag || dryRun {
+		return nil
+	}
+	return update.NewCommitter(workDir, updateCommitMessageFlag)
+}
+
 // runAfterAllValidation runs system tests after all updates.
 //
 // Only runs if there were successful updates. Reports failures and lists
-// packages that may have caused issues.
+// packages that may have caused issues. A critical failure with stop_on_fail
+// rolls back the whole batch: every updated package returns to its original
+// version and its result is marked failed.
 //
 // Parameters:
 //   - runner: Validation runner interface
-//   - results: Update results to check for successful updates
-//   - ctx: Update context for failure tracking
+//   - plans: Plans of the run, rolled back together on a critical failure
+//   - results: Update results to check for successful updates; updated in place on rollback
+//   - ctx: Update context for failure tracking and rollback
 //
 // Returns:
 //   - *systemtest.Result: Test results (nil if no tests run)
-//   - error: Returns error if tests fail critically with stop_on_fail
-func runAfterAllValidation(runner ValidationRunner, results []update.UpdateResult, ctx *update.UpdateContext) (*systemtest.Result, error) {
+//   - error: Returns error if tests fail critically with stop_on_fail, joined with any rollback failure
+func runAfterAllValidation(runner ValidationRunner, plans []*update.PlannedUpdate, results []update.UpdateResult, ctx *update.UpdateContext) (*systemtest.Result, error) {
 	updatedCount := 0
 	for _, res := range results {
 		if res.Status == constants.StatusUpdated {
@@ -457,8 +1887,15 @@ func runAfterAllValidation(runner ValidationRunner, results []update.UpdateResul
 			}
 		}
 		fmt.Println()
-		fmt.Println("Consider rolling back changes or investigating the failures.")
-		return validationResult, fmt.Errorf("system tests failed after updates: %s", validationResult.Summary())
+
+		testErr := fmt.Errorf("system tests failed after updates: %s", validationResult.Summary())
+		rolledBack, rollbackErr := update.RollbackBatch(ctx, plans, results, testErr)
+		if rollbackErr != nil {
+			fmt.Printf("⚠ Rollback of %d package(s) did not complete; check the manifests and lock files.\n", rolledBack)
+			return validationResult, stderrors.Join(testErr, fmt.Errorf("rollback failed: %w", rollbackErr))
+		}
+		fmt.Printf("Rolled back %d package(s) to their original versions.\n", rolledBack)
+		return validationResult, testErr
 	} else if validationResult.Passed() && verbose.IsEnabled() {
 		fmt.Println("All system tests passed. Updates validated successfully.")
 	} else if !validationResult.Passed() {
@@ -468,39 +1905,59 @@ func runAfterAllValidation(runner ValidationRunner, results []update.UpdateResul
 	return validationResult, nil
 }
 
-// reloadPackages reloads and filters packages for validation.
+// reloadPackages reloads every package of the given rules for validation.
 //
 // Re-parses package files to get updated versions after updates are applied.
-// Applies the same filters used in the original update command.
+// The --file, --changed-since-git, --type, --name and --group filters are not
+// applied, so the untargeted-change guard also sees siblings of the targeted
+// packages that share their lock file.
 //
 // Parameters:
 //   - cfg: Configuration for parsing
 //   - args: Original file arguments
 //   - workDir: Working directory
-//   - unsupported: Tracker for unsupported packages
+//   - rules: Rules whose packages are kept (see packageRules)
 //
 // Returns:
 //   - []formats.Package: Refreshed package list
 //   - error: Returns error on parsing failure
-func reloadPackages(cfg *config.Config, args []string, workDir string, _ *supervision.UnsupportedTracker) ([]formats.Package, error) {
-	refreshed, err := getPackagesFunc(cfg, args, workDir)
+func reloadPackages(cfg *config.Config, args []string, workDir string, rules map[string]bool) ([]formats.Package, error) {
+	discovered, err := getPackagesFunc(cfg, args, workDir)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshed = filtering.FilterPackagesWithFilters(refreshed, updateTypeFlag, updatePMFlag, updateRuleFlag, updateNameFlag, "")
+	refreshed := make([]formats.Package, 0, len(discovered))
+	for _, p := range discovered {
+		if rules[p.Rule] {
+			refreshed = append(refreshed, p)
+		}
+	}
 	refreshed, err = applyInstalledVersionsFunc(refreshed, cfg, workDir)
 	if err != nil {
 		return nil, err
 	}
-	refreshed = filtering.ApplyPackageGroups(refreshed, cfg)
-	refreshed = filtering.FilterByGroup(refreshed, updateGroupFlag)
 
 	// NOTE: Do not add to unsupported tracker here - it's already done during
 	// initial package loading. Reloading packages after updates should not
 	// re-count unsupported packages (would cause inflated counts).
 
-	return refreshed, nil
+	return filtering.ApplyPackageGroups(refreshed, cfg), nil
+}
+
+// packageRules returns the set of rules the packages belong to.
+//
+// Parameters:
+//   - packages: Packages selected for the run
+//
+// Returns:
+//   - map[string]bool: Rule names present in packages
+func packageRules(packages []formats.Package) map[string]bool {
+	rules := make(map[string]bool)
+	for _, p := range packages {
+		rules[p.Rule] = true
+	}
+	return rules
 }
 
 // printUpdateStructuredOutput outputs results in structured format.
@@ -510,59 +1967,125 @@ func reloadPackages(cfg *config.Config, args []string, workDir string, _ *superv
 //
 // Parameters:
 //   - results: Update results to output
+//   - systemTestFailures: System test failures collected during updates
+//   - dedupe: Dedupe commands run after the rules' updates
 //   - warnings: Warning messages to include
+//   - details: Classified warnings to include
 //   - errs: Error messages to include
-//   - format: Output format (JSON, CSV, XML)
+//   - unsupported: Unsupported package messages (rendered by the markdown format)
+//   - format: Output format (JSON, CSV, XML, JUnit, Markdown)
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
 //
 // Returns:
 //   - error: Returns error on output failure
-func printUpdateStructuredOutput(results []update.UpdateResult, warnings []string, errs []string, format output.Format) error {
+func printUpdateStructuredOutput(results []update.UpdateResult, systemTestFailures []update.SystemTestFailure, dedupe []update.DedupeStep, warnings []string, details []warnings.Warning, errs []string, unsupported []string, format output.Format, dryRun bool) error {
 	selection := outdated.UpdateSelectionFlags{Major: updateMajorFlag, Minor: updateMinorFlag, Patch: updatePatchFlag}
-	return update.PrintUpdateStructured(results, warnings, errs, format, updateDryRunFlag, selection, writeUpdateResultFunc)
+	writeFunc := func(w io.Writer, format output.Format, result *output.UpdateResult) error {
+		result.Unsupported = unsupported
+		result.WarningDetails = details
+		result.Dedupe = update.DedupeEntries(dedupe)
+		return writeUpdateResultFunc(w, format, result)
+	}
+	return update.PrintUpdateStructuredWithSystemTests(results, systemTestFailures, warnings, errs, format, dryRun, selection, writeFunc)
 }
 
 // handleUpdateResult handles the final result of the update operation.
 //
-// Returns appropriate exit error based on success/failure count and
-// --continue-on-fail flag setting.
+// Maps the run outcome to an exit code under the --fail-on policy. With the
+// default policy (partial), failures exit with ExitPartialFailure when
+// --continue-on-fail let other packages succeed or at least one rule fully
+// succeeded, and with ExitFailure otherwise. Update groups never span rules,
+// so failures in other rules are reported as a partial success instead of
+// failing the whole run. The returned ExitError's Reason names the policy and
+// the outcome kind, so a partial success and a run with only unsupported
+// packages can be told apart.
 //
 // Parameters:
 //   - results: Update results for success counting
 //   - ctx: Update context containing failure list
+//   - unsupported: Tracker of unsupported packages; may be nil
+//   - policy: The --fail-on policy
 //
 // Returns:
-//   - error: Returns nil on full success, ExitError on any failures
-func handleUpdateResult(results []update.UpdateResult, ctx *update.UpdateContext) error {
-	if len(ctx.Failures) == 0 {
-		verbose.Infof("Exit code %d (success): all %d packages processed successfully", errors.ExitSuccess, len(results))
-		return nil
-	}
-
-	successCount := 0
-	for _, res := range results {
-		if res.Status == constants.StatusUpdated || res.Status == constants.StatusPlanned {
-			successCount++
-		}
-	}
+//   - error: Returns nil when the policy maps the outcome to success, ExitError otherwise
+func handleUpdateResult(results []update.UpdateResult, ctx *update.UpdateContext, unsupported *supervision.UnsupportedTracker, policy errors.FailOnPolicy) error {
+	summary := update.BuildSummary(results, ctx, unsupported, updateContinueOnFail, policy)
 
 	// Log detailed failure info in verbose mode
-	if verbose.IsEnabled() {
+	if summary.Failed > 0 && verbose.IsEnabled() {
 		fmt.Fprintln(os.Stderr, "\nFailure details:")
-		for i, err := range ctx.Failures {
+		for i, err := range summary.Failures {
 			fmt.Fprintf(os.Stderr, "  [%d] %v\n", i+1, err)
 		}
 	}
 
+	if summary.Warned > 0 {
+		verbose.Infof("%d failures reported as warnings by the severity config: %s", summary.Warned, strings.Join(summary.Severity, ", "))
+	}
+
+	reason := summary.Reason()
+
 	// Always log exit code reason for diagnostics
-	if successCount > 0 && updateContinueOnFail {
-		verbose.Infof("Exit code %d (partial failure): %d succeeded, %d failed with --continue-on-fail flag", errors.ExitPartialFailure, successCount, len(ctx.Failures))
-		fmt.Fprintf(os.Stderr, "Exit code 1: %d succeeded, %d failed (partial failure with --continue-on-fail)\n", successCount, len(ctx.Failures))
-		return errors.NewExitError(errors.ExitPartialFailure, errors.NewPartialSuccessError(successCount, len(ctx.Failures), ctx.Failures))
+	switch summary.ExitCode {
+	case errors.ExitSuccess:
+		if summary.Failed > 0 {
+			verbose.Infof("Exit code %d (success): %d failures ignored with --fail-on %s", errors.ExitSuccess, summary.Failed, policy)
+			fmt.Fprintf(os.Stderr, "Exit code 0: %d failed (ignored with --fail-on %s)\n", summary.Failed, policy)
+			return nil
+		}
+		verbose.Infof("Exit code %d (success): all %d packages processed successfully", errors.ExitSuccess, len(results))
+		return nil
+	case errors.ExitPartialFailure:
+		verbose.Infof("Exit code %d (partial failure): %d succeeded, %d failed (%s)", errors.ExitPartialFailure, summary.Updated, summary.Failed, summary.PartialReason)
+		fmt.Fprintf(os.Stderr, "Exit code 1: %d succeeded, %d failed (%s)\n", summary.Updated, summary.Failed, summary.PartialReason)
+		return errors.NewExitError(errors.ExitPartialFailure, errors.NewPartialSuccessError(summary.Updated, summary.Failed, summary.Failures)).WithReason(reason)
+	case errors.ExitConfigError:
+		verbose.Infof("Exit code %d (unsupported): %d unsupported packages with --fail-on %s", errors.ExitConfigError, summary.Unsupported, policy)
+		fmt.Fprintf(os.Stderr, "Exit code 3: %d unsupported packages (--fail-on %s)\n", summary.Unsupported, policy)
+		return errors.NewExitErrorf(errors.ExitConfigError, "%d unsupported packages", summary.Unsupported).WithReason(reason)
+	case errors.ExitOnlyUnsupported:
+		verbose.Infof("Exit code %d (only unsupported): nothing updated, %d unsupported packages with --fail-on %s", errors.ExitOnlyUnsupported, summary.Unsupported, policy)
+		fmt.Fprintf(os.Stderr, "Exit code 4: nothing updated, %d unsupported packages (--fail-on %s)\n", summary.Unsupported, policy)
+		return errors.NewExitErrorf(errors.ExitOnlyUnsupported, "nothing updated: all %d remaining packages are unsupported", summary.Unsupported).WithReason(reason)
+	}
+
+	verbose.Infof("Exit code %d (failure): %d packages failed, successCount=%d, continueOnFail=%v, failOn=%s", errors.ExitFailure, summary.Failed, summary.Updated, updateContinueOnFail, policy)
+	fmt.Fprintf(os.Stderr, "Exit code 2: %d failed\n", summary.Failed)
+	return errors.NewExitError(errors.ExitFailure, stderrors.Join(summary.Failures...)).WithReason(reason)
+}
+
+// printUpdateTotals prints the totals footer after the table output.
+//
+// Counts come from update.BuildSummary, so the footer shows a success icon
+// only when handleUpdateResult exits with code 0. Failed counts packages,
+// not errors (see update.CountFailedPackages).
+//
+// Parameters:
+//   - results: Final update results
+//   - ctx: Update context holding failures and rule outcomes
+//   - unsupported: Tracker of unsupported packages; may be nil
+//   - policy: The --fail-on policy deciding the exit code
+//   - dryRun: Whether nothing is written (--dry-run or --dry-run-verify)
+func printUpdateTotals(results []update.UpdateResult, ctx *update.UpdateContext, unsupported *supervision.UnsupportedTracker, policy errors.FailOnPolicy, dryRun bool) {
+	summary := update.BuildSummary(results, ctx, unsupported, updateContinueOnFail, policy)
+
+	upToDate := 0
+	for _, res := range results {
+		if res.Status == constants.StatusUpToDate {
+			upToDate++
+		}
 	}
 
-	verbose.Infof("Exit code %d (failure): %d packages failed, successCount=%d, continueOnFail=%v", errors.ExitFailure, len(ctx.Failures), successCount, updateContinueOnFail)
-	fmt.Fprintf(os.Stderr, "Exit code 2: %d failed\n", len(ctx.Failures))
-	return errors.NewExitError(errors.ExitFailure, stderrors.Join(ctx.Failures...))
+	display.PrintTotals(os.Stdout, display.Totals{
+		Updated:     summary.Updated,
+		UpToDate:    upToDate,
+		Failed:      update.CountFailedPackages(results, summary.Failures),
+		Unsupported: summary.Unsupported,
+		DryRun:      dryRun,
+		Passed:      summary.Passed(),
+		Warned:      summary.Warned,
+		Severity:    summary.Severity,
+	})
 }
 
 // systemTestResultWrapper wraps *systemtest.Result to implement SystemTestResultFormatter.
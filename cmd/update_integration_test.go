@@ -2729,6 +2729,97 @@ system_tests:
 	t.Logf("Test completed - system test rollback scenario executed")
 }
 
+// TestIntegration_SystemTests_AfterAll_RollbackOnFailure verifies that in
+// after_all mode a failing system test run rolls back every package updated
+// in the batch, not just marks them failed.
+func TestIntegration_SystemTests_AfterAll_RollbackOnFailure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	// Check if npm is available
+	if _, err := exec.LookPath("npm"); err != nil {
+		t.Skip("npm not available")
+	}
+
+	tmpDir := t.TempDir()
+
+	// Two exact old versions so the batch holds more than one update
+	packageJSON := `{
+	"name": "test-systemtest-afterall-rollback",
+	"version": "1.0.0",
+	"dependencies": {
+		"is-odd": "3.0.0",
+		"is-number": "7.0.0"
+	}
+}`
+	packageJSONPath := filepath.Join(tmpDir, "package.json")
+	require.NoError(t, os.WriteFile(packageJSONPath, []byte(packageJSON), 0644), "failed to create package.json")
+
+	// System tests that ALWAYS FAIL once after all updates
+	goupdateYML := `
+extends: [default]
+
+system_tests:
+  run_preflight: false
+  run_mode: after_all
+  stop_on_fail: true
+  tests:
+    - name: always-fail
+      commands: |
+        echo "This test always fails"
+        exit 1
+      timeout_seconds: 10
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".goupdate.yml"), []byte(goupdateYML), 0644), "failed to create .goupdate.yml")
+
+	cmd := exec.Command("npm", "install", "--package-lock-only")
+	cmd.Dir = tmpDir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "failed to run npm install: %s", string(output))
+
+	lockPath := filepath.Join(tmpDir, "package-lock.json")
+	originalLock, err := os.ReadFile(lockPath)
+	require.NoError(t, err, "failed to read original package-lock.json")
+	originalVersions := parseNPMLockVersions(t, originalLock)
+
+	t.Cleanup(resetUpdateFlagsToDefaults)
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = tmpDir
+	updateConfigFlag = filepath.Join(tmpDir, ".goupdate.yml")
+	updateYesFlag = true
+	updateSkipPreflight = true
+	updateRuleFlag = "npm"
+	updateMajorFlag = true
+
+	var updateErr error
+	out := captureStdout(t, func() {
+		updateErr = runUpdate(nil, nil)
+	})
+	t.Logf("Update output:\n%s", out)
+
+	if !strings.Contains(out, "System tests failed after updates") {
+		t.Skip("no update was applied, so the after_all tests did not run")
+	}
+	require.Error(t, updateErr, "a failed after_all run must fail the update")
+	assert.Contains(t, out, "Rolled back")
+
+	// Every package of the batch is back at its original declared and locked version
+	var manifest struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	finalContent, err := os.ReadFile(packageJSONPath)
+	require.NoError(t, err, "failed to read final package.json")
+	require.NoError(t, json.Unmarshal(finalContent, &manifest))
+	assert.Equal(t, map[string]string{"is-odd": "3.0.0", "is-number": "7.0.0"}, manifest.Dependencies)
+
+	finalLock, err := os.ReadFile(lockPath)
+	require.NoError(t, err, "failed to read final package-lock.json")
+	finalVersions := parseNPMLockVersions(t, finalLock)
+	assert.Equal(t, originalVersions["is-odd"], finalVersions["is-odd"])
+	assert.Equal(t, originalVersions["is-number"], finalVersions["is-number"])
+}
+
 // TestIntegration_SystemTests_AfterEach_RunsPerPackage verifies that in
 // after_each mode, system tests run after each individual package update.
 func TestIntegration_SystemTests_AfterEach_RunsPerPackage(t *testing.T) {
@@ -7,8 +7,11 @@ import (
 	"testing"
 
 	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/output"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Interactive confirmation tests extracted from update_test.go
@@ -187,3 +190,114 @@ func TestStdinReaderFuncDefault(t *testing.T) {
 	reader := stdinReaderFunc()
 	assert.NotNil(t, reader)
 }
+
+// TestRunUpdateInteractiveSelection tests choosing packages with --interactive.
+//
+// It verifies:
+//   - Pending updates are listed with their proposed targets
+//   - A deselected package is not updated
+//   - A target picked from the candidate versions is applied
+//   - The confirmation prompt is replaced by the selection
+func TestRunUpdateInteractiveSelection(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldList := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldStdin := stdinReaderFunc
+	oldTerminal := stdinIsTerminalFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldList
+		updatePackageFunc = oldUpdate
+		resolveUpdateCfgFunc = oldResolve
+		stdinReaderFunc = oldStdin
+		stdinIsTerminalFunc = oldTerminal
+		resetUpdateFlagsToDefaults()
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Name: "alpha", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0", Type: "prod"},
+			{Name: "beta", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0", Type: "prod"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"1.1.0", "1.2.0"}, nil
+	}
+	var updated []string
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		updated = append(updated, p.Name+"@"+target)
+		return nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return cfg.Rules[p.Rule].Update, nil
+	}
+	stdinIsTerminalFunc = func() bool { return true }
+	// Deselect alpha, then pick the second candidate (1.1.0) for beta
+	stdinReaderFunc = func() *bufio.Reader {
+		return bufio.NewReader(strings.NewReader("1\nv 2\n2\n\n"))
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateInteractiveFlag = true
+
+	out := captureStdout(t, func() {
+		_ = runUpdate(nil, nil)
+	})
+
+	assert.Contains(t, out, "1. [x] alpha (js/npm) 1.0.0 → 1.2.0")
+	assert.Contains(t, out, "1. [ ] alpha (js/npm) 1.0.0 → 1.2.0")
+	assert.Contains(t, out, "1 package(s) selected.")
+	assert.NotContains(t, out, "Continue?")
+	assert.Equal(t, []string{"beta@1.1.0"}, updated)
+}
+
+// TestValidateInteractive tests the --interactive flag checks.
+//
+// It verifies:
+//   - Without a terminal on stdin, --interactive fails with a config error
+//   - Structured output cannot be combined with --interactive
+//   - --yes bypasses the selection, so no terminal is needed
+func TestValidateInteractive(t *testing.T) {
+	oldTerminal := stdinIsTerminalFunc
+	t.Cleanup(func() {
+		stdinIsTerminalFunc = oldTerminal
+		resetUpdateFlagsToDefaults()
+	})
+
+	resetUpdateFlagsToDefaults()
+	updateInteractiveFlag = true
+	stdinIsTerminalFunc = func() bool { return false }
+
+	err := validateInteractive(output.FormatTable)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires a terminal on stdin")
+	var exitErr *errors.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+
+	stdinIsTerminalFunc = func() bool { return true }
+	assert.NoError(t, validateInteractive(output.FormatTable))
+	assert.ErrorContains(t, validateInteractive(output.FormatJSON), "--interactive cannot be combined with --output json")
+
+	stdinIsTerminalFunc = func() bool { return false }
+	updateYesFlag = true
+	assert.NoError(t, validateInteractive(output.FormatTable))
+}
@@ -1,17 +1,32 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	stderrors "errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/ajxudir/goupdate/pkg/changelog"
+	"github.com/ajxudir/goupdate/pkg/cmdexec"
 	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/display"
 	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/filtering"
 	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/notify"
+	"github.com/ajxudir/goupdate/pkg/outdated"
 	"github.com/ajxudir/goupdate/pkg/output"
+	"github.com/ajxudir/goupdate/pkg/security"
+	"github.com/ajxudir/goupdate/pkg/supervision"
+	"github.com/ajxudir/goupdate/pkg/update"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -305,6 +320,96 @@ func TestRunUpdateWithMockedVersions(t *testing.T) {
 	assert.Contains(t, output, "Total packages: 1")
 }
 
+// TestRunUpdatePin tests that a rule's pin limits the update target.
+//
+// It verifies:
+//   - The pin is the outermost limit: --major cannot select a version outside it
+//   - A package already at the newest pinned version is up to date
+//   - A package installed above its pin is reported as violating it
+func TestRunUpdatePin(t *testing.T) {
+	resetUpdateFlagsToDefaults()
+	originalLoad, originalGet, originalListNewer, originalUpdate := loadConfigFunc, getPackagesFunc, listNewerVersionsFunc, updatePackageFunc
+	t.Cleanup(func() {
+		loadConfigFunc, getPackagesFunc, listNewerVersionsFunc, updatePackageFunc = originalLoad, originalGet, originalListNewer, originalUpdate
+		resetUpdateFlagsToDefaults()
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager:  "js",
+					Pin:      map[string]string{"react": "17.x", "vue": "2.x"},
+					Update:   &config.UpdateCfg{Commands: "npm install"},
+					Outdated: &config.OutdatedCfg{Commands: "npm view {{package}}"},
+				},
+			},
+		}, nil
+	}
+	installed := "17.0.0"
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: installed, InstalledVersion: installed, Constraint: "^"},
+			{Rule: "npm", Name: "vue", PackageType: "js", Type: "prod", Version: "3.4.0", InstalledVersion: "3.4.0", Constraint: "^"},
+		}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Name == "vue" {
+			return []string{"3.4.1", "3.5.0"}, nil
+		}
+		return []string{"17.0.1", "17.0.2", "18.0.0", "18.2.0"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return nil
+	}
+
+	updateDryRunFlag = true
+	updateSkipLockRun = true
+	updateMajorFlag = true
+
+	runJSON := func(t *testing.T) map[string]output.UpdatePackage {
+		updateOutputFlag = "json"
+		var runErr error
+		out := captureStdout(t, func() {
+			runErr = runUpdate(updateCmd, nil)
+		})
+		require.NoError(t, runErr)
+
+		var result output.UpdateResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result), out)
+		byName := make(map[string]output.UpdatePackage, len(result.Packages))
+		for _, pkg := range result.Packages {
+			byName[pkg.Name] = pkg
+		}
+		return byName
+	}
+
+	t.Run("major stays inside the pin", func(t *testing.T) {
+		pkgs := runJSON(t)
+		assert.Equal(t, "17.0.2", pkgs["react"].Target)
+		assert.Equal(t, constants.StatusUpToDate, pkgs["vue"].Status)
+	})
+
+	t.Run("newest pinned version is up to date", func(t *testing.T) {
+		installed = "17.0.2"
+		t.Cleanup(func() { installed = "17.0.0" })
+		pkgs := runJSON(t)
+		assert.Equal(t, constants.StatusUpToDate, pkgs["react"].Status)
+	})
+
+	t.Run("installed version above the pin is reported", func(t *testing.T) {
+		updateOutputFlag = ""
+		var runErr error
+		out := captureStdout(t, func() {
+			runErr = runUpdate(updateCmd, nil)
+		})
+		require.NoError(t, runErr)
+		assert.Contains(t, out, `installed version 3.4.0 violates pin "2.x"`)
+		assert.NotContains(t, out, "violates pin \"17.x\"")
+	})
+}
+
 // TestUpdateFlags tests the behavior of update command flags.
 //
 // It verifies:
@@ -1922,3 +2027,2447 @@ func TestRunUpdateExitCodeCompleteFailure(t *testing.T) {
 		}
 	})
 }
+
+// staticAdvisoryProvider returns fixed advisories keyed by package name.
+type staticAdvisoryProvider struct {
+	advisories map[string][]security.Advisory
+}
+
+// Query returns the configured advisories for the package.
+func (p *staticAdvisoryProvider) Query(ctx context.Context, ecosystem, name, version string) ([]security.Advisory, error) {
+	return p.advisories[name], nil
+}
+
+// TestRunUpdateOnlySecurity tests the behavior of update with --only-security.
+//
+// It verifies:
+//   - Only packages affected by advisories are planned
+//   - The target is the lowest version clearing all advisories
+//   - Packages without an advisory ecosystem are reported as unsupported
+func TestRunUpdateOnlySecurity(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldProvider := advisoryProviderFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		advisoryProviderFunc = oldProvider
+		resetUpdateFlagsToDefaults()
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm":    {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}},
+				"custom": {Manager: "custom", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.17.11", InstalledVersion: "4.17.11", Constraint: "^"},
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^"},
+			{Rule: "custom", Name: "internal-tool", PackageType: "custom", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0", Constraint: "^"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	var listed []string
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		listed = append(listed, p.Name)
+		return []string{"4.17.12", "4.17.19", "4.17.21"}, nil
+	}
+	var targets []string
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		targets = append(targets, p.Name+"@"+target)
+		return nil
+	}
+	advisoryProviderFunc = func() security.Provider {
+		return &staticAdvisoryProvider{advisories: map[string][]security.Advisory{
+			"lodash": {
+				{ID: "GHSA-jf85-cpcp-j695", FixedVersions: []string{"4.17.12"}},
+				{ID: "GHSA-p6mc-m468-83gw", FixedVersions: []string{"4.17.19"}},
+			},
+		}}
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateOnlySecurityFlag = true
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateSkipLockRun = true
+	updateYesFlag = true
+	updateConcurrency = 1
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+
+	assert.Equal(t, []string{"lodash"}, listed)
+	assert.Equal(t, []string{"lodash@4.17.19"}, targets)
+	assert.NotContains(t, out, "react")
+	assert.Contains(t, out, "no advisory data source for custom packages")
+}
+
+// TestRunUpdateSelectionStrategy tests update with a rule's selection_strategy.
+//
+// It verifies:
+//   - lowest-non-vulnerable targets the lowest version clearing the advisories of affected packages
+//   - Unaffected packages of the rule take the smallest update
+//   - Only packages of rules using the strategy are scanned for advisories
+//   - Rules without a strategy keep the highest target
+func TestRunUpdateSelectionStrategy(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldProvider := advisoryProviderFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		advisoryProviderFunc = oldProvider
+		resetUpdateFlagsToDefaults()
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm":  {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}, SelectionStrategy: config.SelectionStrategyLowestNonVulnerable},
+				"yarn": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.17.11", InstalledVersion: "4.17.11", Constraint: "^"},
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^"},
+			{Rule: "yarn", Name: "vue", PackageType: "js", Type: "prod", Version: "3.3.0", InstalledVersion: "3.3.0", Constraint: "^"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	available := map[string][]string{
+		"lodash": {"4.17.12", "4.17.19", "4.17.21"},
+		"react":  {"17.0.2", "17.0.1", "18.2.0"},
+		"vue":    {"3.4.0", "3.4.5"},
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return available[p.Name], nil
+	}
+	var targets []string
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		targets = append(targets, p.Name+"@"+target)
+		return nil
+	}
+	provider := &recordingAdvisoryProvider{staticAdvisoryProvider: staticAdvisoryProvider{advisories: map[string][]security.Advisory{
+		"lodash": {
+			{ID: "GHSA-jf85-cpcp-j695", FixedVersions: []string{"4.17.12"}},
+			{ID: "GHSA-p6mc-m468-83gw", FixedVersions: []string{"4.17.19"}},
+		},
+	}}}
+	advisoryProviderFunc = func() security.Provider { return provider }
+
+	resetUpdateFlagsToDefaults()
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateSkipLockRun = true
+	updateYesFlag = true
+	updateConcurrency = 1
+
+	captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+
+	assert.ElementsMatch(t, []string{"lodash@4.17.19", "react@17.0.1", "vue@3.4.5"}, targets)
+	assert.Equal(t, []string{"lodash", "react"}, provider.queried)
+}
+
+// recordingAdvisoryProvider is a staticAdvisoryProvider that records the queried package names.
+type recordingAdvisoryProvider struct {
+	staticAdvisoryProvider
+	queried []string
+}
+
+// Query records the package name and returns its configured advisories.
+func (p *recordingAdvisoryProvider) Query(ctx context.Context, ecosystem, name, version string) ([]security.Advisory, error) {
+	p.queried = append(p.queried, name)
+	return p.staticAdvisoryProvider.Query(ctx, ecosystem, name, version)
+}
+
+// TestRunUpdateJUnitOutput tests update with --output junit.
+//
+// It verifies:
+//   - The structured writer receives the JUnit format
+//   - Update results are passed through for conversion
+func TestRunUpdateJUnitOutput(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldWrite := writeUpdateResultFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		writeUpdateResultFunc = oldWrite
+		resetUpdateFlagsToDefaults()
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.17.20", InstalledVersion: "4.17.20", Constraint: "^"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"4.17.21"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return nil
+	}
+	var gotFormat output.Format
+	var gotResult *output.UpdateResult
+	writeUpdateResultFunc = func(w io.Writer, format output.Format, result *output.UpdateResult) error {
+		gotFormat = format
+		gotResult = result
+		return nil
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateOutputFlag = "junit"
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+
+	require.NoError(t, runUpdate(nil, nil))
+
+	assert.Equal(t, output.FormatJUnit, gotFormat)
+	require.NotNil(t, gotResult)
+	require.Len(t, gotResult.Packages, 1)
+	assert.Equal(t, "lodash", gotResult.Packages[0].Name)
+}
+
+// TestRunUpdateMarkdownOutput tests update with --output markdown.
+//
+// It verifies:
+//   - The structured writer receives the Markdown format
+//   - The rendered table lists the planned package
+//   - Unsupported package messages are passed through to the trailing section
+func TestRunUpdateMarkdownOutput(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldWrite := writeUpdateResultFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		writeUpdateResultFunc = oldWrite
+		resetUpdateFlagsToDefaults()
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.17.20", InstalledVersion: "4.17.20", Constraint: "^"},
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "*", InstalledVersion: "18.2.0", InstallStatus: lock.InstallStatusFloating},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"4.17.21"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return nil
+	}
+	var buf bytes.Buffer
+	var gotFormat output.Format
+	writeUpdateResultFunc = func(w io.Writer, format output.Format, result *output.UpdateResult) error {
+		gotFormat = format
+		return output.WriteUpdateResult(&buf, format, result)
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateOutputFlag = "markdown"
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+
+	require.NoError(t, runUpdate(nil, nil))
+
+	assert.Equal(t, output.FormatMarkdown, gotFormat)
+	out := buf.String()
+	assert.Contains(t, out, "## Dependency updates (dry run)")
+	assert.Contains(t, out, "| `lodash` | js | 4.17.20 | 4.17.21 | Planned |")
+	assert.Contains(t, out, "### Unsupported packages")
+	assert.Contains(t, out, "Floating")
+}
+
+// TestRunUpdateAllowPrerelease tests update with --allow-prerelease.
+//
+// It verifies:
+//   - The flag is passed to version listing through the runtime config
+//   - A stable release is preferred over a newer pre-release in the same category
+//   - The chosen version is reported as the result target
+func TestRunUpdateAllowPrerelease(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldWrite := writeUpdateResultFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		writeUpdateResultFunc = oldWrite
+		resetUpdateFlagsToDefaults()
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.17.20", InstalledVersion: "4.17.20", Constraint: "^"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	var allowed bool
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		allowed = cfg.AllowPrerelease
+		return []string{"4.19.0-rc.1", "4.18.0"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return nil
+	}
+	var gotResult *output.UpdateResult
+	writeUpdateResultFunc = func(w io.Writer, format output.Format, result *output.UpdateResult) error {
+		gotResult = result
+		return nil
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateAllowPrerelease = true
+	updateOutputFlag = "json"
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+
+	require.NoError(t, runUpdate(nil, nil))
+
+	assert.True(t, allowed)
+	require.NotNil(t, gotResult)
+	require.Len(t, gotResult.Packages, 1)
+	assert.Equal(t, "4.18.0", gotResult.Packages[0].Target)
+}
+
+// TestRunUpdateMaxBump tests update with --max-bump.
+//
+// It verifies:
+//   - An invalid --max-bump value is a config error
+//   - The highest version within the cap is chosen instead of the latest
+func TestRunUpdateMaxBump(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldWrite := writeUpdateResultFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		writeUpdateResultFunc = oldWrite
+		resetUpdateFlagsToDefaults()
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.10.0", InstalledVersion: "4.10.0", Constraint: "^"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"4.11.0", "4.12.3", "4.17.21", "5.0.0"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return nil
+	}
+	var gotResult *output.UpdateResult
+	writeUpdateResultFunc = func(w io.Writer, format output.Format, result *output.UpdateResult) error {
+		gotResult = result
+		return nil
+	}
+
+	t.Run("invalid value", func(t *testing.T) {
+		resetUpdateFlagsToDefaults()
+		updateMaxBumpFlag = "minor"
+
+		err := runUpdate(nil, nil)
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+	})
+
+	t.Run("caps target", func(t *testing.T) {
+		resetUpdateFlagsToDefaults()
+		updateMaxBumpFlag = "minor:2"
+		updateOutputFlag = "json"
+		updateDryRunFlag = true
+		updateSkipPreflight = true
+		updateSkipSystemTests = true
+
+		require.NoError(t, runUpdate(nil, nil))
+
+		require.NotNil(t, gotResult)
+		require.Len(t, gotResult.Packages, 1)
+		assert.Equal(t, "4.12.3", gotResult.Packages[0].Target)
+	})
+}
+
+// TestHandleUpdateResultRuleOutcomes tests how per-rule outcomes affect the exit code.
+//
+// It verifies:
+//   - A failure is partial when another rule fully succeeded, even without --continue-on-fail
+//   - A failure is complete when no rule fully succeeded
+func TestHandleUpdateResultRuleOutcomes(t *testing.T) {
+	oldContinue := updateContinueOnFail
+	updateContinueOnFail = false
+	t.Cleanup(func() { updateContinueOnFail = oldContinue })
+
+	composerErr := stderrors.New("composer lock failed")
+	results := []update.UpdateResult{
+		{Pkg: formats.Package{Name: "react", Rule: "npm"}, Status: constants.StatusUpdated},
+		{Pkg: formats.Package{Name: "laravel/framework", Rule: "composer"}, Status: constants.StatusFailed, Err: composerErr},
+	}
+
+	t.Run("partial when another rule fully succeeded", func(t *testing.T) {
+		ctx := &update.UpdateContext{
+			Failures: []error{composerErr},
+			RuleOutcomes: []update.RuleOutcome{
+				{Rule: "npm", Succeeded: 1},
+				{Rule: "composer", Failures: []error{composerErr}},
+			},
+		}
+
+		err := handleUpdateResult(results, ctx, nil, errors.FailOnPartial)
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitPartialFailure, exitErr.Code)
+		pse, ok := errors.IsPartialSuccess(err)
+		require.True(t, ok)
+		assert.Equal(t, 1, pse.Succeeded)
+		assert.Equal(t, 1, pse.Failed)
+	})
+
+	t.Run("complete failure when no rule fully succeeded", func(t *testing.T) {
+		ctx := &update.UpdateContext{
+			Failures: []error{composerErr},
+			RuleOutcomes: []update.RuleOutcome{
+				{Rule: "npm", Succeeded: 1, Failures: []error{composerErr}},
+			},
+		}
+
+		err := handleUpdateResult(results, ctx, nil, errors.FailOnPartial)
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitFailure, exitErr.Code)
+	})
+}
+
+// TestHandleUpdateResultFailOn tests the --fail-on policies.
+//
+// It verifies:
+//   - any-unsupported exits with ExitConfigError when all updates succeeded but packages are unsupported
+//   - partial (the default) ignores unsupported packages
+//   - only-unsupported exits with ExitOnlyUnsupported when nothing was updated or failed
+//   - any-failure turns a partial success into ExitFailure
+//   - none exits successfully despite failures
+//   - The chosen policy and outcome kind are recorded in ExitError.Reason
+func TestHandleUpdateResultFailOn(t *testing.T) {
+	oldContinue := updateContinueOnFail
+	updateContinueOnFail = true
+	t.Cleanup(func() { updateContinueOnFail = oldContinue })
+
+	succeeded := []update.UpdateResult{
+		{Pkg: formats.Package{Name: "react", Rule: "npm"}, Status: constants.StatusUpdated},
+		{Pkg: formats.Package{Name: "left-pad", Rule: "npm", InstallStatus: lock.InstallStatusFloating}, Status: lock.InstallStatusFloating},
+	}
+	tracker := supervision.NewUnsupportedTracker()
+	tracker.Add(formats.Package{Name: "left-pad", Rule: "npm", PackageType: "js", InstallStatus: lock.InstallStatusFloating}, "Floating constraint")
+
+	t.Run("any-unsupported with all updates succeeded", func(t *testing.T) {
+		err := handleUpdateResult(succeeded, &update.UpdateContext{}, tracker, errors.FailOnAnyUnsupported)
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+		assert.Equal(t, "--fail-on any-unsupported: success (1 succeeded, 0 failed, 1 unsupported)", exitErr.Reason)
+	})
+
+	t.Run("any-unsupported counts packages filtered before planning", func(t *testing.T) {
+		err := handleUpdateResult(nil, &update.UpdateContext{}, tracker, errors.FailOnAnyUnsupported)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	})
+
+	t.Run("partial ignores unsupported", func(t *testing.T) {
+		assert.NoError(t, handleUpdateResult(succeeded, &update.UpdateContext{}, tracker, errors.FailOnPartial))
+	})
+
+	onlyUnsupported := succeeded[1:]
+
+	t.Run("only-unsupported exits when nothing could be updated", func(t *testing.T) {
+		err := handleUpdateResult(onlyUnsupported, &update.UpdateContext{}, tracker, errors.FailOnOnlyUnsupported)
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitOnlyUnsupported, exitErr.Code)
+		assert.Equal(t, "--fail-on only-unsupported: only-unsupported (0 succeeded, 0 failed, 1 unsupported)", exitErr.Reason)
+		_, partial := errors.IsPartialSuccessError(err)
+		assert.False(t, partial)
+	})
+
+	t.Run("only-unsupported succeeds when something was updated", func(t *testing.T) {
+		assert.NoError(t, handleUpdateResult(succeeded, &update.UpdateContext{}, tracker, errors.FailOnOnlyUnsupported))
+	})
+
+	t.Run("partial succeeds with only unsupported packages", func(t *testing.T) {
+		assert.NoError(t, handleUpdateResult(onlyUnsupported, &update.UpdateContext{}, tracker, errors.FailOnPartial))
+	})
+
+	failure := stderrors.New("lodash update failed")
+	partial := append([]update.UpdateResult{
+		{Pkg: formats.Package{Name: "lodash", Rule: "npm"}, Status: constants.StatusFailed, Err: failure},
+	}, succeeded...)
+
+	t.Run("partial keeps the default mapping", func(t *testing.T) {
+		err := handleUpdateResult(partial, &update.UpdateContext{Failures: []error{failure}}, tracker, errors.FailOnPartial)
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitPartialFailure, exitErr.Code)
+		assert.Equal(t, "--fail-on partial: partial-success (1 succeeded, 1 failed, 1 unsupported)", exitErr.Reason)
+	})
+
+	t.Run("any-failure fails partial success", func(t *testing.T) {
+		err := handleUpdateResult(partial, &update.UpdateContext{Failures: []error{failure}}, tracker, errors.FailOnAnyFailure)
+
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitFailure, exitErr.Code)
+		assert.Contains(t, exitErr.Reason, "--fail-on any-failure")
+		assert.ErrorIs(t, err, failure)
+	})
+
+	t.Run("none ignores failures", func(t *testing.T) {
+		assert.NoError(t, handleUpdateResult(partial, &update.UpdateContext{Failures: []error{failure}}, tracker, errors.FailOnNone))
+	})
+}
+
+// TestPrintUpdateTotals tests the totals footer printed after table output.
+//
+// It verifies:
+//   - Counts match the outcome used for the exit code
+//   - A package recorded in several failures counts as one failed package
+//   - The footer shows the error icon whenever the run exits non-zero
+//   - Failures ignored by --fail-on none show the success icon
+func TestPrintUpdateTotals(t *testing.T) {
+	oldContinue := updateContinueOnFail
+	updateContinueOnFail = true
+	t.Cleanup(func() {
+		updateContinueOnFail = oldContinue
+		display.SetColorMode(display.ColorAlways)
+	})
+	display.SetColorMode(display.ColorAlways)
+
+	failure := stderrors.New("lock failed")
+	results := []update.UpdateResult{
+		{Pkg: formats.Package{Name: "react", Rule: "npm"}, Status: constants.StatusUpdated},
+		{Pkg: formats.Package{Name: "lodash", Rule: "npm"}, Status: constants.StatusUpToDate},
+		{Pkg: formats.Package{Name: "chalk", Rule: "npm"}, Status: constants.StatusFailed, Err: failure},
+		{Pkg: formats.Package{Name: "left-pad", Rule: "npm"}, Status: lock.InstallStatusFloating},
+	}
+	ctx := &update.UpdateContext{Failures: []error{failure, fmt.Errorf("rollback of chalk: %w", failure)}}
+
+	out := captureStdout(t, func() { printUpdateTotals(results, ctx, nil, errors.FailOnPartial, false) })
+	assert.Contains(t, out, constants.IconError+" Updated: 1, Up-to-date: 1, Failed: 1, Unsupported: 1")
+	assert.Error(t, handleUpdateResult(results, ctx, nil, errors.FailOnPartial))
+
+	out = captureStdout(t, func() { printUpdateTotals(results, ctx, nil, errors.FailOnNone, false) })
+	assert.Contains(t, out, constants.IconSuccess+" Updated: 1")
+}
+
+// TestRunUpdateInvalidFailOn tests rejecting an unknown --fail-on value.
+//
+// It verifies:
+//   - The run exits with ExitConfigError before loading any config
+func TestRunUpdateInvalidFailOn(t *testing.T) {
+	oldFailOn := updateFailOnFlag
+	updateFailOnFlag = "sometimes"
+	t.Cleanup(func() { updateFailOnFlag = oldFailOn })
+
+	err := runUpdate(nil, nil)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.Contains(t, err.Error(), "invalid --fail-on value")
+}
+
+// TestRunUpdateStrictLockWithSkipLock tests rejecting --strict-lock together with --skip-lock.
+//
+// It verifies:
+//   - The run exits with ExitConfigError before loading any config
+func TestRunUpdateStrictLockWithSkipLock(t *testing.T) {
+	t.Cleanup(resetUpdateFlagsToDefaults)
+	updateStrictLockFlag = true
+	updateSkipLockRun = true
+
+	err := runUpdate(nil, nil)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.Contains(t, err.Error(), "--strict-lock cannot be combined with --skip-lock")
+}
+
+// TestRunUpdateGroupCommandsConflicts tests rejecting --group-commands with flags that skip or split the lock command.
+//
+// It verifies:
+//   - --group-commands with --skip-lock or --staged exits with ExitConfigError
+func TestRunUpdateGroupCommandsConflicts(t *testing.T) {
+	for _, set := range []func(){
+		func() { updateSkipLockRun = true },
+		func() { updateStagedFlag = true },
+	} {
+		resetUpdateFlagsToDefaults()
+		t.Cleanup(resetUpdateFlagsToDefaults)
+		updateGroupCommandsFlag = true
+		set()
+
+		err := runUpdate(nil, nil)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+		assert.Contains(t, err.Error(), "--group-commands cannot be combined with --skip-lock or --staged")
+	}
+}
+
+// TestRunUpdateDryRunRunsNoLockCommand tests that a dry run never executes a lock command.
+//
+// It verifies:
+//   - With the real updater, no command is executed for any rule, grouped or not
+//   - Manifests are left unchanged
+//   - --show-commands lists the lock commands a real run would execute
+//   - --show-commands requires --dry-run
+func TestRunUpdateDryRunRunsNoLockCommand(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldExecute := cmdexec.Execute
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		cmdexec.Execute = oldExecute
+		resetUpdateFlagsToDefaults()
+	})
+
+	dir := t.TempDir()
+	webManifest := filepath.Join(dir, "web", "package.json")
+	apiManifest := filepath.Join(dir, "api", "composer.json")
+	manifests := map[string]string{
+		webManifest: `{"dependencies": {"react": "^17.0.0", "react-dom": "^17.0.0"}}`,
+		apiManifest: `{"require": {"monolog/monolog": "^2.0.0"}}`,
+	}
+	for path, content := range manifests {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: dir,
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager: "js", Format: "json", Fields: map[string]string{"dependencies": "prod"},
+					Update:   &config.UpdateCfg{Commands: "npm install"},
+					Outdated: &config.OutdatedCfg{},
+					Groups:   map[string]config.GroupCfg{"ui": {Packages: []string{"react", "react-dom"}}},
+				},
+				"composer": {
+					Manager: "php", Format: "json", Fields: map[string]string{"require": "prod"},
+					Update:   &config.UpdateCfg{Commands: "composer update {{package}}"},
+					Outdated: &config.OutdatedCfg{},
+				},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^", Source: webManifest},
+			{Rule: "npm", Name: "react-dom", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^", Source: webManifest},
+			{Rule: "composer", Name: "monolog/monolog", PackageType: "php", Type: "prod", Version: "2.0.0", InstalledVersion: "2.0.0", Constraint: "^", Source: apiManifest},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Rule == "composer" {
+			return []string{"2.9.0"}, nil
+		}
+		return []string{"17.0.2"}, nil
+	}
+	updatePackageFunc = update.UpdatePackage
+	cmdexec.Execute = func(commands string, env map[string]string, dir string, timeoutSeconds int, replacements map[string]string) ([]byte, error) {
+		t.Errorf("dry run executed %q in %s", commands, dir)
+		return nil, stderrors.New("dry run must not execute commands")
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = dir
+	updateDryRunFlag = true
+	updateShowCommandsFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateYesFlag = true
+
+	var err error
+	out := captureStdout(t, func() {
+		err = runUpdate(nil, nil)
+	})
+	require.NoError(t, err)
+	for path, content := range manifests {
+		data, readErr := os.ReadFile(path)
+		require.NoError(t, readErr)
+		assert.Equal(t, content, string(data), path)
+	}
+	assert.Contains(t, out, "Commands a real run would execute:")
+	assert.Contains(t, out, "npm ("+filepath.Dir(webManifest)+"): npm install [react, react-dom]")
+	assert.Contains(t, out, "composer ("+filepath.Dir(apiManifest)+"): composer update monolog/monolog [monolog/monolog]")
+
+	updateDryRunFlag = false
+	err = runUpdate(nil, nil)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.ErrorContains(t, err, "--show-commands requires --dry-run")
+}
+
+// TestRunUpdateChangedSinceGit tests --changed-since-git outside a git repository.
+//
+// It verifies:
+//   - A directory outside a git working tree exits with ExitConfigError and a clear message
+func TestRunUpdateChangedSinceGit(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		resetUpdateFlagsToDefaults()
+	})
+
+	dir := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{WorkingDir: dir, Rules: map[string]config.PackageManagerCfg{"npm": {Manager: "js"}}}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{{Rule: "npm", Name: "react", Source: filepath.Join(dir, "package.json")}}, nil
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = dir
+	updateChangedSinceGit = "origin/main"
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+
+	err := runUpdate(nil, nil)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.ErrorContains(t, err, "--changed-since-git requires a git repository")
+}
+
+// TestRunUpdateUpdateTo tests pinning a package to an exact version with --update-to.
+//
+// It verifies:
+//   - The requested version is applied even when it is outside the constraint
+//   - --no-verify skips the registry lookup
+//   - A version missing from the registry fails the package instead of applying it
+//   - --update-to needs exactly one --name and rejects version selection flags
+func TestRunUpdateUpdateTo(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldListAll := listAllVersionsFunc
+	oldUpdate := updatePackageFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		listAllVersionsFunc = oldListAll
+		updatePackageFunc = oldUpdate
+		resetUpdateFlagsToDefaults()
+	})
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules:      map[string]config.PackageManagerCfg{"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}}},
+		}, nil
+	}
+	applied := map[string]string{}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		react := "17.0.0"
+		if v, ok := applied["react"]; ok {
+			react = v
+		}
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: react, InstalledVersion: react, Constraint: "^"},
+			{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.0.0", InstalledVersion: "4.0.0", Constraint: "^"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return nil, fmt.Errorf("newer versions must not be used to verify --update-to")
+	}
+	lookups := 0
+	listAllVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		lookups++
+		return []string{"16.14.0", "17.0.0", "17.0.2", "18.2.0"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		applied[p.Name] = target
+		return nil
+	}
+
+	run := func(version string, noVerify bool) error {
+		resetUpdateFlagsToDefaults()
+		updateNameFlag = "react"
+		updateToFlag = version
+		updateNoVerifyFlag = noVerify
+		updateSkipPreflight = true
+		updateSkipSystemTests = true
+		updateYesFlag = true
+		var err error
+		captureStdout(t, func() {
+			err = runUpdate(nil, nil)
+		})
+		return err
+	}
+
+	require.NoError(t, run("18.2.0", false))
+	assert.Equal(t, map[string]string{"react": "18.2.0"}, applied)
+	assert.Equal(t, 1, lookups)
+
+	delete(applied, "react")
+	require.NoError(t, run("19.0.0-rc.1", true))
+	assert.Equal(t, map[string]string{"react": "19.0.0-rc.1"}, applied)
+	assert.Equal(t, 1, lookups)
+
+	delete(applied, "react")
+	assert.Error(t, run("19.0.0", false))
+	assert.Empty(t, applied)
+
+	require.NoError(t, run("16.14.0", false))
+	assert.Equal(t, map[string]string{"react": "16.14.0"}, applied)
+
+	for name, set := range map[string]func(){
+		"exactly one --name value":        func() { updateNameFlag = "react,lodash" },
+		"cannot be combined with --major": func() { updateMajorFlag = true },
+		"cannot be combined with --patch": func() { updatePatchFlag = true },
+	} {
+		resetUpdateFlagsToDefaults()
+		updateNameFlag = "react"
+		updateToFlag = "18.2.0"
+		set()
+		err := runUpdate(nil, nil)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err), name)
+		assert.ErrorContains(t, err, name)
+	}
+}
+
+// TestRunUpdateNotify tests the update completion notification.
+//
+// It verifies:
+//   - The summary counts planned, failed, and unsupported packages and lists the changes
+//   - A failed delivery does not change the exit code
+//   - Dry runs are only reported when notify.on_dry_run is set
+func TestRunUpdateNotify(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	oldSend := sendNotificationFunc
+	oldDir := updateDirFlag
+	oldDryRun := updateDryRunFlag
+	oldSkipPreflight := updateSkipPreflight
+	oldSkipLock := updateSkipLockRun
+	oldSkipSys := updateSkipSystemTests
+	oldContinue := updateContinueOnFail
+	oldYes := updateYesFlag
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		sendNotificationFunc = oldSend
+		updateDirFlag = oldDir
+		updateDryRunFlag = oldDryRun
+		updateSkipPreflight = oldSkipPreflight
+		updateSkipLockRun = oldSkipLock
+		updateSkipSystemTests = oldSkipSys
+		updateContinueOnFail = oldContinue
+		updateYesFlag = oldYes
+	})
+
+	var notifyCfg *config.NotifyCfg
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: ".",
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{}},
+			},
+			Notify: notifyCfg,
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^"},
+			{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.0.0", InstalledVersion: "4.0.0", Constraint: "^"},
+			{Rule: "npm", Name: "left-pad", PackageType: "js", Type: "prod", Version: "*", InstallStatus: lock.InstallStatusFloating},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Name == "lodash" {
+			return nil, stderrors.New("version fetch failed for lodash")
+		}
+		return []string{"17.0.2"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return nil
+	}
+
+	var sent []notify.Summary
+	sendNotificationFunc = func(ctx context.Context, cfg *config.NotifyCfg, summary notify.Summary) error {
+		sent = append(sent, summary)
+		return stderrors.New("notification to https://hooks.example.com/*** failed: 500 Internal Server Error")
+	}
+
+	updateDirFlag = "."
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+	updateSkipLockRun = true
+	updateSkipSystemTests = true
+	updateContinueOnFail = true
+	updateYesFlag = true
+
+	exitCode := func() int {
+		var err error
+		captureStdout(t, func() {
+			err = runUpdate(nil, nil)
+		})
+		var exitErr *errors.ExitError
+		require.True(t, stderrors.As(err, &exitErr), "expected exit error, got %v", err)
+		return exitErr.Code
+	}
+
+	baseline := exitCode()
+	assert.Empty(t, sent, "no notify block, no notification")
+
+	notifyCfg = &config.NotifyCfg{URL: "https://hooks.example.com/secret"}
+	assert.Equal(t, baseline, exitCode())
+	assert.Empty(t, sent, "dry runs are skipped without on_dry_run")
+
+	notifyCfg.OnDryRun = true
+	assert.Equal(t, baseline, exitCode(), "failed delivery must not change the exit code")
+	require.Len(t, sent, 1)
+	assert.True(t, sent[0].DryRun)
+	assert.Equal(t, 1, sent[0].Updated)
+	assert.Equal(t, 1, sent[0].Failed)
+	assert.Equal(t, 1, sent[0].Unsupported)
+	assert.Equal(t, []notify.Change{{Name: "react", Rule: "npm", From: "17.0.0", To: "17.0.2"}}, sent[0].Changes)
+}
+
+// TestRunUpdateShowDiff tests previewing manifest edits with --show-diff.
+//
+// It verifies:
+//   - Table output shows each edit collapsed below the package row
+//   - JSON output carries the full unified diff per package
+//   - The manifest on disk is left untouched in dry-run
+func TestRunUpdateShowDiff(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldDir := updateDirFlag
+	oldDryRun := updateDryRunFlag
+	oldOutput := updateOutputFlag
+	oldShowDiff := updateShowDiffFlag
+	oldSkipPreflight := updateSkipPreflight
+	oldSkipSys := updateSkipSystemTests
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updateDirFlag = oldDir
+		updateDryRunFlag = oldDryRun
+		updateOutputFlag = oldOutput
+		updateShowDiffFlag = oldShowDiff
+		updateSkipPreflight = oldSkipPreflight
+		updateSkipSystemTests = oldSkipSys
+	})
+
+	tmpDir := t.TempDir()
+	manifest := filepath.Join(tmpDir, "package.json")
+	original := "{\n  \"dependencies\": {\n    \"react\": \"^17.0.0\"\n  }\n}\n"
+	require.NoError(t, os.WriteFile(manifest, []byte(original), 0o644))
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: tmpDir,
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager:  "js",
+					Format:   "json",
+					Fields:   map[string]string{"dependencies": "prod"},
+					Update:   &config.UpdateCfg{},
+					Outdated: &config.OutdatedCfg{},
+				},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^", Source: manifest},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"17.0.2"}, nil
+	}
+
+	updateDirFlag = tmpDir
+	updateDryRunFlag = true
+	updateShowDiffFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+
+	updateOutputFlag = ""
+	out := captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	assert.Contains(t, out, "↳ package.json:3 \"react\": \"^17.0.0\" → \"react\": \"^17.0.2\"")
+
+	updateOutputFlag = "json"
+	out = captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	var result output.UpdateResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result), out)
+	require.Len(t, result.Packages, 1)
+	assert.Contains(t, result.Packages[0].Diff, "--- a/package.json\n+++ b/package.json\n")
+	assert.Contains(t, result.Packages[0].Diff, "+    \"react\": \"^17.0.2\"\n")
+
+	content, err := os.ReadFile(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(content))
+}
+
+// changelogProviderStub returns fixed notes or an error for every package.
+type changelogProviderStub struct {
+	notes *changelog.Notes
+	err   error
+}
+
+// Fetch returns the stub's notes and error.
+func (s changelogProviderStub) Fetch(context.Context, formats.Package, config.PackageManagerCfg, string, string) (*changelog.Notes, error) {
+	return s.notes, s.err
+}
+
+// TestRunUpdateChangelog tests the --changelog flag of the update command.
+//
+// It verifies:
+//   - The release notes URL is printed below the row, the snippet only with --show-diff
+//   - Structured output carries the notes per package
+//   - Fetch failures never fail the run
+func TestRunUpdateChangelog(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldProvider := changelogProviderFunc
+	oldDir := updateDirFlag
+	oldDryRun := updateDryRunFlag
+	oldOutput := updateOutputFlag
+	oldShowDiff := updateShowDiffFlag
+	oldChangelog := updateChangelogFlag
+	oldSkipPreflight := updateSkipPreflight
+	oldSkipSys := updateSkipSystemTests
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		changelogProviderFunc = oldProvider
+		updateDirFlag = oldDir
+		updateDryRunFlag = oldDryRun
+		updateOutputFlag = oldOutput
+		updateShowDiffFlag = oldShowDiff
+		updateChangelogFlag = oldChangelog
+		updateSkipPreflight = oldSkipPreflight
+		updateSkipSystemTests = oldSkipSys
+	})
+
+	tmpDir := t.TempDir()
+	manifest := filepath.Join(tmpDir, "package.json")
+	require.NoError(t, os.WriteFile(manifest, []byte("{\n  \"dependencies\": {\n    \"react\": \"^17.0.0\"\n  }\n}\n"), 0o644))
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: tmpDir,
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager:  "js",
+					Format:   "json",
+					Fields:   map[string]string{"dependencies": "prod"},
+					Update:   &config.UpdateCfg{},
+					Outdated: &config.OutdatedCfg{},
+				},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^", Source: manifest},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"17.0.2"}, nil
+	}
+	changelogProviderFunc = func() changelog.Provider {
+		return changelogProviderStub{notes: &changelog.Notes{
+			URL:     "https://github.com/facebook/react/releases/tag/v17.0.2",
+			Snippet: "## v17.0.2\n- Remove unstable_createPortal",
+		}}
+	}
+
+	updateDirFlag = tmpDir
+	updateDryRunFlag = true
+	updateChangelogFlag = true
+	updateShowDiffFlag = false
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+
+	updateOutputFlag = ""
+	out := captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	assert.Contains(t, out, "↳ Release notes: https://github.com/facebook/react/releases/tag/v17.0.2")
+	assert.NotContains(t, out, "Remove unstable_createPortal")
+
+	updateShowDiffFlag = true
+	out = captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	assert.Contains(t, out, "      - Remove unstable_createPortal")
+
+	updateOutputFlag = "json"
+	out = captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	var result output.UpdateResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result), out)
+	require.Len(t, result.Packages, 1)
+	require.NotNil(t, result.Packages[0].Changelog)
+	assert.Equal(t, "https://github.com/facebook/react/releases/tag/v17.0.2", result.Packages[0].Changelog.URL)
+
+	changelogProviderFunc = func() changelog.Provider {
+		return changelogProviderStub{err: stderrors.New("GitHub API rate limit exceeded")}
+	}
+	updateOutputFlag = ""
+	out = captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	assert.NotContains(t, out, "Release notes")
+}
+
+// TestRunUpdateReportFile tests the --report-file flag of the update command.
+//
+// It verifies:
+//   - A failed run still writes the report with results, errors, config and exit reason
+//   - A run stopped by a config error writes a report with its exit code
+//   - Failing to write the report fails an otherwise successful run
+func TestRunUpdateReportFile(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		resetUpdateFlagsToDefaults()
+	})
+
+	tmpDir := t.TempDir()
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: tmpDir,
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Format: "json", Update: &config.UpdateCfg{Commands: "npm install"}, Outdated: &config.OutdatedCfg{}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^", Source: filepath.Join(tmpDir, "package.json")},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{Commands: "npm install"}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"17.0.2"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return stderrors.New("npm install failed")
+	}
+
+	readReport := func(path string) update.RunReport {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var report update.RunReport
+		require.NoError(t, json.Unmarshal(data, &report), string(data))
+		return report
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = tmpDir
+	updateYesFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateOutputFlag = "json"
+	updateReportFileFlag = filepath.Join(tmpDir, "report.json")
+
+	var runErr error
+	captureStdout(t, func() {
+		runErr = runUpdate(nil, nil)
+	})
+	require.Error(t, runErr)
+
+	report := readReport(updateReportFileFlag)
+	assert.Equal(t, update.ReportFileVersion, report.Version)
+	assert.Equal(t, tmpDir, report.Config.WorkDir)
+	assert.Equal(t, []string{"npm"}, report.Config.Rules)
+	assert.Equal(t, "partial", report.Config.FailOn)
+	assert.Equal(t, errors.ExitFailure, report.Outcome.ExitCode)
+	assert.Equal(t, 1, report.Outcome.Failed)
+	assert.Contains(t, report.Outcome.Reason, "--fail-on partial")
+	require.Len(t, report.Packages, 1)
+	assert.Equal(t, "react", report.Packages[0].Name)
+	assert.Contains(t, report.Packages[0].Error, "npm install failed")
+	assert.NotEmpty(t, report.Errors)
+	assert.False(t, report.FinishedAt.Before(report.StartedAt))
+
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files are left behind")
+
+	updateFailOnFlag = "sometimes"
+	require.Error(t, runUpdate(nil, nil))
+	report = readReport(updateReportFileFlag)
+	assert.Equal(t, errors.ExitConfigError, report.Outcome.ExitCode)
+	assert.Contains(t, report.Outcome.Error, "sometimes")
+	assert.Empty(t, report.Packages)
+
+	updateFailOnFlag = "none"
+	updateReportFileFlag = filepath.Join(tmpDir, "missing", "report.json")
+	captureStdout(t, func() {
+		runErr = runUpdate(nil, nil)
+	})
+	require.Error(t, runErr)
+	assert.Equal(t, errors.ExitFailure, errors.GetExitCode(runErr))
+	assert.Contains(t, runErr.Error(), "failed to write report file")
+}
+
+// TestRunUpdateProfile tests the --profile flag of the update command.
+//
+// It verifies:
+//   - Table output ends with the phase and per-rule timing breakdown
+//   - Structured output stays valid JSON and the breakdown is written to the report file
+func TestRunUpdateProfile(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldResolve := resolveUpdateCfgFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		resolveUpdateCfgFunc = oldResolve
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		resetUpdateFlagsToDefaults()
+	})
+
+	tmpDir := t.TempDir()
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: tmpDir,
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Format: "json", Update: &config.UpdateCfg{Commands: "npm install"}, Outdated: &config.OutdatedCfg{}},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^", Source: filepath.Join(tmpDir, "package.json")},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	resolveUpdateCfgFunc = func(p formats.Package, cfg *config.Config) (*config.UpdateCfg, error) {
+		return &config.UpdateCfg{Commands: "npm install"}, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"17.0.2"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return nil
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = tmpDir
+	updateDryRunFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateProfileFlag = true
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	assert.Contains(t, out, "Profile (total")
+	for _, phase := range []string{update.PhaseConfigLoad, update.PhasePackageDiscovery, update.PhaseVersionLookup, update.PhaseExecution} {
+		assert.Regexp(t, `(?m)^`+phase+`\s+\d`, out)
+	}
+	assert.Regexp(t, `(?m)^npm\s+1\s`, out)
+
+	updateOutputFlag = "json"
+	updateReportFileFlag = filepath.Join(tmpDir, "report.json")
+	out = captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	var result output.UpdateResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result), out)
+
+	data, err := os.ReadFile(updateReportFileFlag)
+	require.NoError(t, err)
+	var report update.RunReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.NotNil(t, report.Profile)
+	var phases []string
+	for _, phase := range report.Profile.Phases {
+		phases = append(phases, phase.Name)
+	}
+	assert.Equal(t, []string{update.PhaseConfigLoad, update.PhasePackageDiscovery, update.PhaseVersionLookup, update.PhaseExecution}, phases)
+	require.Len(t, report.Profile.Rules, 1)
+	assert.Equal(t, update.RuleProfile{Rule: "npm", Packages: 1, LookupMs: report.Profile.Rules[0].LookupMs, ExecutionMs: report.Profile.Rules[0].ExecutionMs}, report.Profile.Rules[0])
+}
+
+// TestRunUpdateSince tests the --since flag of the update command.
+//
+// It verifies:
+//   - Only versions released after the cutoff are update targets
+//   - Rules without release dates are reported as not configured instead of dropped
+//   - A successful run records its time for --since last-run, dry runs do not
+//   - --since last-run without a recorded run is a config error
+func TestRunUpdateSince(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldListDates := listReleaseDatesFunc
+	oldUpdate := updatePackageFunc
+	oldSave := saveRunStateFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		listReleaseDatesFunc = oldListDates
+		updatePackageFunc = oldUpdate
+		saveRunStateFunc = oldSave
+		resetUpdateFlagsToDefaults()
+	})
+
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{Rules: map[string]config.PackageManagerCfg{
+			"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{
+				Commands:     "echo ok",
+				ReleaseDates: &config.ReleaseDatesCfg{Commands: "echo {{package}}"},
+			}},
+			"mod": {Manager: "golang", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+		}}, nil
+	}
+	current := "1.0.0"
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: current, InstalledVersion: current},
+			{Rule: "mod", Name: "gomod", PackageType: "golang", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"1.1.0", "1.2.0"}, nil
+	}
+	listReleaseDatesFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (outdated.ReleaseDates, error) {
+		return outdated.ReleaseDates{"1.1.0": since.AddDate(0, 0, 2), "1.2.0": since.AddDate(0, 0, -2)}, nil
+	}
+	var calls []string
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		calls = append(calls, p.Name+"@"+target)
+		if !dryRun {
+			current = target
+		}
+		return nil
+	}
+	var recorded []string
+	saveRunStateFunc = func(dir string, now time.Time) error {
+		recorded = append(recorded, dir)
+		return nil
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = t.TempDir()
+	updateSinceFlag = "2025-06-01"
+	updateMinorFlag = true
+	updateYesFlag = true
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateOutputFlag = "json"
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+
+	var result output.UpdateResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	byName := map[string]output.UpdatePackage{}
+	for _, pkg := range result.Packages {
+		byName[pkg.Name] = pkg
+	}
+	assert.Equal(t, "1.1.0", byName["react"].Target)
+	assert.Equal(t, lock.InstallStatusNotConfigured, byName["gomod"].Status)
+	assert.Equal(t, []string{"react@1.1.0"}, calls)
+	assert.Equal(t, []string{updateDirFlag}, recorded)
+
+	t.Run("dry run does not record", func(t *testing.T) {
+		recorded = nil
+		current = "1.0.0"
+		updateDryRunFlag = true
+		captureStdout(t, func() {
+			require.NoError(t, runUpdate(nil, nil))
+		})
+		assert.Empty(t, recorded)
+		updateDryRunFlag = false
+	})
+
+	t.Run("last-run without state", func(t *testing.T) {
+		updateSinceFlag = outdated.SinceLastRun
+		err := runUpdate(nil, nil)
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+		assert.ErrorContains(t, err, "no update run recorded")
+	})
+}
+
+// TestRunUpdateQuarantine tests the quarantine window of the update command.
+//
+// It verifies:
+//   - The rule's quarantine_days skips versions published within the window
+//   - A package whose newer versions are all quarantined is up to date
+//   - Rules without release dates are reported as not configured
+//   - --quarantine-days overrides the rule's window
+//   - An explicit --quarantine-days 0 turns off the rule's window
+//   - A negative --quarantine-days is a config error
+func TestRunUpdateQuarantine(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldListDates := listReleaseDatesFunc
+	oldUpdate := updatePackageFunc
+	oldNow := releaseAgeNowFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		listReleaseDatesFunc = oldListDates
+		updatePackageFunc = oldUpdate
+		releaseAgeNowFunc = oldNow
+		resetUpdateFlagsToDefaults()
+	})
+
+	now := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	releaseAgeNowFunc = func() time.Time { return now }
+	quarantineDays := 7
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{Rules: map[string]config.PackageManagerCfg{
+			"npm": {Manager: "js", QuarantineDays: &quarantineDays, Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{
+				Commands:     "echo ok",
+				ReleaseDates: &config.ReleaseDatesCfg{Commands: "echo {{package}}"},
+			}},
+			"mod": {Manager: "golang", QuarantineDays: &quarantineDays, Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+		}}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0"},
+			{Rule: "npm", Name: "vue", PackageType: "js", Type: "prod", Version: "1.1.0", InstalledVersion: "1.1.0"},
+			{Rule: "mod", Name: "gomod", PackageType: "golang", Type: "prod", Version: "1.0.0", InstalledVersion: "1.0.0"},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Name == "vue" {
+			return []string{"1.2.0"}, nil
+		}
+		return []string{"1.1.0", "1.2.0"}, nil
+	}
+	listReleaseDatesFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (outdated.ReleaseDates, error) {
+		return outdated.ReleaseDates{"1.1.0": now.AddDate(0, 0, -30), "1.2.0": now.AddDate(0, 0, -2)}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return nil
+	}
+
+	setup := func(t *testing.T) {
+		resetUpdateFlagsToDefaults()
+		updateDirFlag = t.TempDir()
+		updateMinorFlag = true
+		updateDryRunFlag = true
+		updateOutputFlag = "json"
+	}
+	run := func(t *testing.T) map[string]output.UpdatePackage {
+		out := captureStdout(t, func() {
+			require.NoError(t, runUpdate(updateCmd, nil))
+		})
+		var result output.UpdateResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		byName := map[string]output.UpdatePackage{}
+		for _, pkg := range result.Packages {
+			byName[pkg.Name] = pkg
+		}
+		return byName
+	}
+
+	setup(t)
+	byName := run(t)
+	assert.Equal(t, "1.1.0", byName["react"].Target)
+	assert.Equal(t, constants.StatusUpToDate, byName["vue"].Status)
+	assert.Equal(t, lock.InstallStatusNotConfigured, byName["gomod"].Status)
+
+	t.Run("flag overrides rule", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, updateCmd.Flags().Set("quarantine-days", "60"))
+		byName := run(t)
+		assert.Equal(t, constants.StatusUpToDate, byName["react"].Status)
+	})
+
+	t.Run("zero flag disables rule quarantine", func(t *testing.T) {
+		setup(t)
+		require.NoError(t, updateCmd.Flags().Set("quarantine-days", "0"))
+		byName := run(t)
+		assert.Equal(t, "1.2.0", byName["react"].Target)
+		assert.Equal(t, "1.2.0", byName["vue"].Target)
+	})
+
+	t.Run("negative flag", func(t *testing.T) {
+		setup(t)
+		updateQuarantineDays = -1
+		err := runUpdate(nil, nil)
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+	})
+}
+
+// TestDiscoverUpdatePlansLookupProgress tests the live lookup count shown during discovery.
+//
+// It verifies:
+//   - A terminal stderr shows the number of finished version lookups
+//   - Packages that need no lookup are not counted
+//   - No count is written when stderr is not a terminal
+func TestDiscoverUpdatePlansLookupProgress(t *testing.T) {
+	originalTerminal := stderrIsTerminalFunc
+	t.Cleanup(func() {
+		stderrIsTerminalFunc = originalTerminal
+		resetUpdateFlagsToDefaults()
+	})
+	resetUpdateFlagsToDefaults()
+	updateConcurrency = 1
+
+	cfg := &config.Config{
+		Rules: map[string]config.PackageManagerCfg{
+			"npm": {
+				Manager:  "js",
+				Update:   &config.UpdateCfg{Commands: "npm install"},
+				Outdated: &config.OutdatedCfg{Commands: "npm view {{package}}"},
+			},
+		},
+	}
+	packages := []formats.Package{
+		{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^"},
+		{Rule: "npm", Name: "vue", PackageType: "js", Type: "prod", Version: "3.0.0", InstalledVersion: "3.0.0", Constraint: "^"},
+		{Rule: "npm", Name: "floating", PackageType: "js", Type: "prod", Version: "*", InstalledVersion: "1.0.0"},
+	}
+	lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"99.0.0"}, nil
+	}
+
+	discover := func() string {
+		return captureStderr(t, func() {
+			captureStdout(t, func() {
+				updateCtx := update.NewUpdateContext(cfg, ".", nil)
+				plans, _, err := discoverUpdatePlans(context.Background(), packages, updateCtx, outdated.UpdateSelectionFlags{}, lister, nil, false)
+				require.NoError(t, err)
+				assert.Len(t, plans, 3)
+			})
+		})
+	}
+
+	stderrIsTerminalFunc = func() bool { return true }
+	out := discover()
+	assert.Contains(t, out, "Resolving versions: 1/2")
+	assert.Contains(t, out, "Resolving versions: 2/2")
+	assert.NotContains(t, out, "/3")
+
+	stderrIsTerminalFunc = func() bool { return false }
+	assert.NotContains(t, discover(), "Resolving versions")
+}
+
+// TestRunUpdateRequireCleanLock tests the --require-clean-lock preflight.
+//
+// It verifies:
+//   - Without the flag lock files are not checked
+//   - Dirty lock files are refused with a config error before any update
+//   - --allow-dirty and --dry-run skip the check
+func TestRunUpdateRequireCleanLock(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldCheck := checkCleanLockFilesFunc
+	oldUpdate := updatePackageFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		checkCleanLockFilesFunc = oldCheck
+		updatePackageFunc = oldUpdate
+		resetUpdateFlagsToDefaults()
+	})
+
+	tmpDir := t.TempDir()
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{WorkingDir: tmpDir, Rules: map[string]config.PackageManagerCfg{"npm": {Manager: "js"}}}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{{Name: "react", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0", Source: filepath.Join(tmpDir, "package.json")}}, nil
+	}
+	applyInstalledVersionsFunc = func(pkgs []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return pkgs, nil
+	}
+	var updated bool
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		updated = true
+		return nil
+	}
+	var checked []string
+	checkCleanLockFilesFunc = func(cfg *config.Config, pkgs []formats.Package, dir string) error {
+		for _, p := range pkgs {
+			checked = append(checked, p.Name)
+		}
+		return &errors.ValidationError{Category: errors.ValidationCategoryPreflight, Message: "1 lock file(s) have uncommitted changes: package-lock.json"}
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = tmpDir
+	updateSkipPreflight = true
+	captureStdout(t, func() { _ = runUpdate(nil, nil) })
+	assert.Empty(t, checked)
+
+	updated = false
+	updateRequireCleanLock = true
+	var err error
+	captureStdout(t, func() { err = runUpdate(nil, nil) })
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.Contains(t, err.Error(), "package-lock.json")
+	assert.Contains(t, err.Error(), "--allow-dirty")
+	assert.Equal(t, []string{"react"}, checked)
+	assert.False(t, updated)
+
+	checked = nil
+	updateAllowDirtyFlag = true
+	captureStdout(t, func() { _ = runUpdate(nil, nil) })
+	assert.Empty(t, checked)
+
+	updateAllowDirtyFlag = false
+	updateDryRunFlag = true
+	captureStdout(t, func() { _ = runUpdate(nil, nil) })
+	assert.Empty(t, checked)
+}
+
+// TestRunUpdateCommitWorkTree tests the working tree checks of --commit.
+//
+// It verifies:
+//   - --allow-dirty without --commit is a config error
+//   - A dirty working tree is refused with a config error before any update
+//   - --allow-dirty and --dry-run skip the check
+func TestRunUpdateCommitWorkTree(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldCheck := checkCleanWorkTreeFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		checkCleanWorkTreeFunc = oldCheck
+		resetUpdateFlagsToDefaults()
+	})
+
+	tmpDir := t.TempDir()
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{WorkingDir: tmpDir, Rules: map[string]config.PackageManagerCfg{}}, nil
+	}
+	var listed bool
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		listed = true
+		return nil, nil
+	}
+	var checked int
+	checkCleanWorkTreeFunc = func(dir string) error {
+		checked++
+		return stderrors.New("working tree has 1 uncommitted change(s): ?? notes.txt")
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = tmpDir
+	updateAllowDirtyFlag = true
+	err := runUpdate(nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.Contains(t, err.Error(), "--allow-dirty requires --commit")
+
+	updateAllowDirtyFlag = false
+	updateCommitFlag = true
+	err = runUpdate(nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.Contains(t, err.Error(), "notes.txt")
+	assert.Contains(t, err.Error(), "--allow-dirty")
+	assert.False(t, listed)
+	assert.Equal(t, 1, checked)
+
+	updateAllowDirtyFlag = true
+	captureStdout(t, func() { err = runUpdate(nil, nil) })
+	require.NoError(t, err)
+	assert.True(t, listed)
+
+	updateAllowDirtyFlag = false
+	updateDryRunFlag = true
+	captureStdout(t, func() { err = runUpdate(nil, nil) })
+	require.NoError(t, err)
+	assert.Equal(t, 1, checked)
+}
+
+// TestRunUpdateCommitCriticalDedupe tests refusing --commit with a critical dedupe command.
+//
+// It verifies:
+//   - --commit with --dedupe is a config error naming the rules with critical: true
+//   - A dedupe command that is not critical is accepted
+func TestRunUpdateCommitCriticalDedupe(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		resetUpdateFlagsToDefaults()
+	})
+
+	tmpDir := t.TempDir()
+	critical := true
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{WorkingDir: tmpDir, Rules: map[string]config.PackageManagerCfg{
+			"npm": {Manager: "js", Update: &config.UpdateCfg{Dedupe: &config.DedupeCfg{Commands: "npm dedupe", Critical: critical}}},
+		}}, nil
+	}
+	var listed bool
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		listed = true
+		return nil, nil
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = tmpDir
+	updateCommitFlag = true
+	updateAllowDirtyFlag = true
+	updateDedupeFlag = true
+	err := runUpdate(nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.Contains(t, err.Error(), "--commit cannot be combined with --dedupe when update.dedupe is critical (rules: npm)")
+	assert.False(t, listed)
+
+	critical = false
+	captureStdout(t, func() { err = runUpdate(nil, nil) })
+	require.NoError(t, err)
+	assert.True(t, listed)
+}
+
+// TestRunUpdateLockfileOnly tests refreshing lock files without bumping manifests.
+//
+// It verifies:
+//   - Flags that pick versions are rejected with a config error
+//   - A dry run lists the locations without running lock commands
+//   - The lock command runs, changed lock files and installed versions are printed
+//   - Versions are never looked up and manifests are not rewritten
+func TestRunUpdateLockfileOnly(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		resetUpdateFlagsToDefaults()
+	})
+
+	tmpDir := t.TempDir()
+	manifest := filepath.Join(tmpDir, "package.json")
+	require.NoError(t, os.WriteFile(manifest, []byte(`{"dependencies":{"react":"^18.0.0"}}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "package-lock.json"), []byte("stale"), 0o644))
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: tmpDir,
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager:   "js",
+					Format:    "json",
+					LockFiles: []config.LockFileCfg{{Files: []string{"package-lock.json"}, RefreshCommands: "printf refreshed > package-lock.json"}},
+					Update:    &config.UpdateCfg{Commands: "npm install {{package}}"},
+				},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "^18.0.0", Constraint: "^", Source: manifest},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		lockContent, _ := os.ReadFile(filepath.Join(tmpDir, "package-lock.json"))
+		for i := range packages {
+			packages[i].InstalledVersion = "18.2.0"
+			if string(lockContent) == "refreshed" {
+				packages[i].InstalledVersion = "18.3.1"
+			}
+		}
+		return packages, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		t.Fatal("versions must not be looked up with --lockfile-only")
+		return nil, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		t.Fatal("manifests must not be updated with --lockfile-only")
+		return nil
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = tmpDir
+	updateSkipPreflight = true
+	updateLockfileOnlyFlag = true
+	updateMinorFlag = true
+	err := runUpdate(nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.Contains(t, err.Error(), "--lockfile-only cannot be combined with --minor")
+
+	updateMinorFlag = false
+	updateDryRunFlag = true
+	out := captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	assert.Contains(t, out, "Dry run: lock commands would run in 1 location(s)")
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "package-lock.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "stale", string(lockContent))
+
+	updateDryRunFlag = false
+	updateYesFlag = true
+	out = captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	assert.Contains(t, out, "package-lock.json")
+	assert.Contains(t, out, "react (js/npm) 18.2.0 → 18.3.1")
+	assert.Contains(t, out, "Lock files changed in 1 of 1 location(s), 0 failed")
+
+	manifestContent, err := os.ReadFile(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, `{"dependencies":{"react":"^18.0.0"}}`, string(manifestContent))
+}
+
+// TestRunUpdateDryRunVerify tests rehearsing lock commands with --dry-run-verify.
+//
+// It verifies:
+//   - --skip-lock is rejected with a config error
+//   - The lock command sees the edited manifest in a temporary copy and the group is reported as verified
+//   - The --dry-run flag variable is left unchanged for later runs
+//   - A failing lock command fails the run, while the working tree stays unchanged
+func TestRunUpdateDryRunVerify(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		resetUpdateFlagsToDefaults()
+	})
+
+	tmpDir := t.TempDir()
+	manifest := filepath.Join(tmpDir, "package.json")
+	original := `{"dependencies":{"react":"^18.0.0"}}`
+	require.NoError(t, os.WriteFile(manifest, []byte(original), 0o644))
+
+	lockCommand := "grep -q 18.3.1 package.json"
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: tmpDir,
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager: "js",
+					Format:  "json",
+					Fields:  map[string]string{"dependencies": "prod"},
+					Update:  &config.UpdateCfg{Commands: lockCommand},
+				},
+			},
+		}, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: "18.0.0", Constraint: "^", Source: manifest},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		for i := range packages {
+			packages[i].InstalledVersion = "18.0.0"
+		}
+		return packages, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"18.3.1"}, nil
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = tmpDir
+	updateSkipPreflight = true
+	updateDryRunVerifyFlag = true
+	updateSkipLockRun = true
+	err := runUpdate(nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.Contains(t, err.Error(), "--dry-run-verify cannot be combined with --skip-lock")
+
+	updateSkipLockRun = false
+	out := captureStdout(t, func() {
+		require.NoError(t, runUpdate(nil, nil))
+	})
+	assert.Contains(t, out, "Dry-run verification:")
+	assert.Contains(t, out, "Verified")
+	assert.Contains(t, out, "Lock commands succeeded for 1 of 1 group(s), 0 failed")
+	assert.False(t, updateDryRunFlag)
+
+	lockCommand = "echo 'npm ERR! ERESOLVE' >&2; exit 1"
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = tmpDir
+	updateSkipPreflight = true
+	updateDryRunVerifyFlag = true
+	out = captureStdout(t, func() {
+		err = runUpdate(nil, nil)
+	})
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitPartialFailure, errors.GetExitCode(err))
+	assert.Contains(t, out, "Lock commands succeeded for 0 of 1 group(s), 1 failed")
+	assert.Contains(t, out, "dry-run verification failed")
+
+	manifestContent, err := os.ReadFile(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(manifestContent))
+}
+
+// TestRunUpdateLimit tests the --limit flag of the update command.
+//
+// It verifies:
+//   - Only the N updates with the largest version gap are applied
+//   - The other updates are listed as Deferred and counted in the summary
+//   - --limit-by age applies the longest-pending update first
+//   - A negative limit and an unknown priority are config errors
+func TestRunUpdateLimit(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldListDates := listReleaseDatesFunc
+	oldUpdate := updatePackageFunc
+	oldNow := releaseAgeNowFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		listReleaseDatesFunc = oldListDates
+		updatePackageFunc = oldUpdate
+		releaseAgeNowFunc = oldNow
+		resetUpdateFlagsToDefaults()
+	})
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	releaseAgeNowFunc = func() time.Time { return now }
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{Rules: map[string]config.PackageManagerCfg{
+			"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+		}}, nil
+	}
+	var versions map[string]string
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: versions["react"], InstalledVersion: versions["react"]},
+			{Rule: "npm", Name: "vue", PackageType: "js", Type: "prod", Version: versions["vue"], InstalledVersion: versions["vue"]},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Name == "react" {
+			return []string{"1.0.1"}, nil
+		}
+		return []string{"1.6.0"}, nil
+	}
+	listReleaseDatesFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (outdated.ReleaseDates, error) {
+		if p.Name == "react" {
+			return outdated.ReleaseDates{"1.0.1": now.AddDate(-1, 0, 0)}, nil
+		}
+		return outdated.ReleaseDates{"1.6.0": now.AddDate(0, 0, -3)}, nil
+	}
+	var calls []string
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		calls = append(calls, p.Name+"@"+target)
+		versions[p.Name] = target
+		return nil
+	}
+
+	run := func(t *testing.T, limitBy string) output.UpdateResult {
+		t.Helper()
+		resetUpdateFlagsToDefaults()
+		calls = nil
+		versions = map[string]string{"react": "1.0.0", "vue": "1.4.0"}
+		updateDirFlag = t.TempDir()
+		updateMinorFlag = true
+		updateYesFlag = true
+		updateSkipPreflight = true
+		updateSkipSystemTests = true
+		updateOutputFlag = "json"
+		updateLimitFlag = 1
+		updateLimitByFlag = limitBy
+
+		out := captureStdout(t, func() {
+			require.NoError(t, runUpdate(nil, nil))
+		})
+		var result output.UpdateResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		return result
+	}
+	statusOf := func(result output.UpdateResult) map[string]string {
+		byName := map[string]string{}
+		for _, pkg := range result.Packages {
+			byName[pkg.Name] = pkg.Status
+		}
+		return byName
+	}
+
+	result := run(t, update.LimitByGap)
+	assert.Equal(t, []string{"vue@1.6.0"}, calls)
+	assert.Equal(t, map[string]string{"react": constants.StatusDeferred, "vue": constants.StatusUpdated}, statusOf(result))
+	assert.Equal(t, 1, result.Summary.DeferredPackages)
+	assert.Equal(t, 1, result.Summary.UpdatedPackages)
+
+	result = run(t, update.LimitByAge)
+	assert.Equal(t, []string{"react@1.0.1"}, calls)
+	assert.Equal(t, constants.StatusDeferred, statusOf(result)["vue"])
+
+	for _, tc := range []struct {
+		limit   int
+		limitBy string
+	}{{-1, update.LimitByGap}, {1, "size"}} {
+		resetUpdateFlagsToDefaults()
+		updateLimitFlag = tc.limit
+		updateLimitByFlag = tc.limitBy
+		err := runUpdate(nil, nil)
+		exitErr, ok := errors.IsExitError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ExitConfigError, exitErr.Code)
+	}
+}
+
+// TestSelectPlans tests applying a --select expression to update plans.
+//
+// It verifies:
+//   - Gap comparisons use the newest available version of each plan
+//   - Plans that failed to resolve are kept so the failure is reported
+//   - The returned packages follow the kept plans
+func TestSelectPlans(t *testing.T) {
+	plans := []*update.PlannedUpdate{
+		{Res: update.UpdateResult{Pkg: formats.Package{Name: "react", Version: "17.0.0", InstalledVersion: "17.0.2"}, Major: "18.2.0", Minor: constants.PlaceholderNA, Patch: "17.0.3"}},
+		{Res: update.UpdateResult{Pkg: formats.Package{Name: "jest", Version: "29.0.0", InstalledVersion: "29.7.0"}, Major: constants.PlaceholderNA, Minor: constants.PlaceholderNA, Patch: "29.7.1"}},
+		{Res: update.UpdateResult{Pkg: formats.Package{Name: "broken", Version: "1.0.0", InstalledVersion: "1.0.0"}, Err: stderrors.New("lookup failed")}},
+	}
+
+	selector, err := filtering.ParseSelect("gap>=major")
+	require.NoError(t, err)
+	kept, pkgs := selectPlans(plans, selector)
+	require.Len(t, kept, 2)
+	assert.Equal(t, "react", kept[0].Res.Pkg.Name)
+	assert.Equal(t, "18.2.0", kept[0].Res.Pkg.LatestVersion)
+	assert.Equal(t, "broken", kept[1].Res.Pkg.Name)
+	assert.Equal(t, []string{"react", "broken"}, []string{pkgs[0].Name, pkgs[1].Name})
+}
+
+// TestRunUpdateAfterAllRollback tests rolling back the batch when after_all system tests fail.
+//
+// It verifies:
+//   - Every updated package is re-applied at its original version after the failure
+//   - The rolled back packages are reported as failed and the run exits with an error
+//   - Passing tests keep the updates
+//   - --commit is refused up front, since the rollback would undo committed files
+func TestRunUpdateAfterAllRollback(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		resetUpdateFlagsToDefaults()
+	})
+
+	testCommand := "exit 1"
+	stopOnFail := true
+	runPreflight := false
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Manager: "js", Update: &config.UpdateCfg{}, Outdated: &config.OutdatedCfg{Commands: "echo ok"}},
+			},
+			SystemTests: &config.SystemTestsCfg{
+				Tests:        []config.SystemTestCfg{{Name: "build", Commands: testCommand, TimeoutSeconds: 10}},
+				RunPreflight: &runPreflight,
+				RunMode:      config.SystemTestRunModeAfterAll,
+				StopOnFail:   &stopOnFail,
+			},
+		}, nil
+	}
+	var versions map[string]string
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: versions["react"], InstalledVersion: versions["react"]},
+			{Rule: "npm", Name: "vue", PackageType: "js", Type: "prod", Version: versions["vue"], InstalledVersion: versions["vue"]},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Name == "react" {
+			return []string{"1.0.1"}, nil
+		}
+		return []string{"1.6.0"}, nil
+	}
+	var calls []string
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		calls = append(calls, p.Name+"@"+target)
+		versions[p.Name] = target
+		return nil
+	}
+
+	run := func(t *testing.T) (string, error) {
+		t.Helper()
+		resetUpdateFlagsToDefaults()
+		calls = nil
+		versions = map[string]string{"react": "1.0.0", "vue": "1.4.0"}
+		updateDirFlag = t.TempDir()
+		updateMinorFlag = true
+		updateYesFlag = true
+		updateSkipPreflight = true
+
+		var err error
+		out := captureStdout(t, func() {
+			err = runUpdate(nil, nil)
+		})
+		return out, err
+	}
+
+	out, err := run(t)
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitFailure, errors.GetExitCode(err))
+	assert.Equal(t, []string{"react@1.0.1", "vue@1.6.0", "react@1.0.0", "vue@1.4.0"}, calls)
+	assert.Equal(t, map[string]string{"react": "1.0.0", "vue": "1.4.0"}, versions)
+	assert.Contains(t, out, "Rolled back 2 package(s) to their original versions.")
+
+	testCommand = "exit 0"
+	_, err = run(t)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"react@1.0.1", "vue@1.6.0"}, calls)
+	assert.Equal(t, map[string]string{"react": "1.0.1", "vue": "1.6.0"}, versions)
+
+	resetUpdateFlagsToDefaults()
+	calls = nil
+	updateDirFlag = t.TempDir()
+	updateMinorFlag = true
+	updateYesFlag = true
+	updateCommitFlag = true
+	updateAllowDirtyFlag = true
+	err = runUpdate(nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+	assert.Contains(t, err.Error(), "--commit cannot be combined with after_all system tests")
+	assert.Empty(t, calls)
+}
+
+// TestRunUpdateNameFilterUntargetedSibling tests the untargeted-change guard with --name.
+//
+// It verifies:
+//   - A sibling excluded by --name is still part of the baseline and the reload
+//   - A sibling whose installed version changes with the target fails the update
+func TestRunUpdateNameFilterUntargetedSibling(t *testing.T) {
+	oldLoad := loadConfigFunc
+	oldGet := getPackagesFunc
+	oldApply := applyInstalledVersionsFunc
+	oldListNewer := listNewerVersionsFunc
+	oldUpdate := updatePackageFunc
+	t.Cleanup(func() {
+		loadConfigFunc = oldLoad
+		getPackagesFunc = oldGet
+		applyInstalledVersionsFunc = oldApply
+		listNewerVersionsFunc = oldListNewer
+		updatePackageFunc = oldUpdate
+		resetUpdateFlagsToDefaults()
+	})
+
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "package.json")
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return &config.Config{
+			WorkingDir: dir,
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager: "js", Format: "json", Fields: map[string]string{"dependencies": "prod"},
+					Update:   &config.UpdateCfg{Commands: "npm install"},
+					Outdated: &config.OutdatedCfg{},
+				},
+			},
+		}, nil
+	}
+	updated := false
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		react, lodash := "17.0.0", "4.17.20"
+		if updated {
+			react, lodash = "17.0.2", "4.17.21"
+		}
+		return []formats.Package{
+			{Rule: "npm", Name: "react", PackageType: "js", Type: "prod", Version: react, InstalledVersion: react, Constraint: "^", Source: manifest},
+			{Rule: "npm", Name: "lodash", PackageType: "js", Type: "prod", Version: "4.17.20", InstalledVersion: lodash, Constraint: "^", Source: manifest},
+		}, nil
+	}
+	applyInstalledVersionsFunc = func(packages []formats.Package, cfg *config.Config, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	listNewerVersionsFunc = func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"17.0.2"}, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		updated = true
+		return nil
+	}
+
+	resetUpdateFlagsToDefaults()
+	updateDirFlag = dir
+	updateNameFlag = "react"
+	updateSkipPreflight = true
+	updateSkipSystemTests = true
+	updateYesFlag = true
+
+	var err error
+	out := captureStdout(t, func() {
+		err = runUpdate(nil, nil)
+	})
+	require.Error(t, err)
+	assert.Contains(t, out+err.Error(), "untargeted package lodash changed from 4.17.20 to 4.17.21")
+}
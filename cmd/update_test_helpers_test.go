@@ -1,5 +1,10 @@
 package cmd
 
+import (
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/update"
+)
+
 // resetUpdateFlagsToDefaults is a test helper that resets all update flags to their default values.
 //
 // This function ensures test isolation by resetting all update command flags to their initial state.
@@ -25,4 +30,43 @@ func resetUpdateFlagsToDefaults() {
 	updateOutputFlag = ""
 	updateSkipSystemTests = false
 	updateSystemTestModeFlag = ""
+	updateConcurrency = outdated.DefaultConcurrency()
+	updateOnlySecurityFlag = false
+	updatePlanOutFlag = ""
+	updatePlanInFlag = ""
+	updatePlanDiffFlag = nil
+	updateAllowPrerelease = false
+	updateMaxBumpFlag = ""
+	updateSelectFlag = ""
+	updateShowDiffFlag = false
+	updateFailOnFlag = "partial"
+	updateStagedFlag = false
+	updateInteractiveFlag = false
+	updateSinceFlag = ""
+	updateQuarantineDays = 0
+	if flag := updateCmd.Flags().Lookup("quarantine-days"); flag != nil {
+		flag.Changed = false
+	}
+	updateParallelGroups = 1
+	updateIncludeTransitive = false
+	updateStrictLockFlag = false
+	updateChangelogFlag = false
+	updateReportFileFlag = ""
+	updateCommitFlag = false
+	updateCommitMessageFlag = update.DefaultCommitMessage
+	updateAllowDirtyFlag = false
+	updateRequireCleanLock = false
+	updateLockfileOnlyFlag = false
+	updatePinFloatingFlag = false
+	updateDryRunVerifyFlag = false
+	updateLimitFlag = 0
+	updateLimitByFlag = update.LimitByGap
+	updateGroupCommandsFlag = false
+	updateDedupeFlag = false
+	updateShowCommandsFlag = false
+	updateChangedSinceGit = ""
+	updateToFlag = ""
+	updateNoVerifyFlag = false
+	updateAllowYankedFlag = false
+	updateProfileFlag = false
 }
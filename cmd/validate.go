@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/display"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/filtering"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/output"
+	"github.com/ajxudir/goupdate/pkg/preflight"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateTypeFlag   string
+	validatePMFlag     string
+	validateRuleFlag   string
+	validateConfigFlag string
+	validateDirFlag    string
+	validateFileFlag   string
+	validateOutputFlag string
+	validateCleanLock  bool
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [file...]",
+	Short: "Check configuration and environment without updating",
+	Long: `Load the configuration, detect packages, and run the same pre-flight checks as
+update, without looking up versions or changing any file.
+
+Checks that the outdated, update, and lock file commands of every detected
+rule are available, and that each group member matches a detected package.
+With --require-clean-lock, the lock files of the detected packages must also
+have no uncommitted git changes.
+Exits with code 3 when any check fails, so it can gate a CI job before the
+real run. With --output json, xml, or csv every checked command is listed per
+rule with whether it was found and where, so CI can report which tool is missing.`,
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().StringVarP(&validateTypeFlag, "type", "t", "all", "Filter by type (comma-separated): all,prod,dev")
+	validateCmd.Flags().StringVarP(&validatePMFlag, "package-manager", "p", "all", "Filter by package manager (comma-separated)")
+	validateCmd.Flags().StringVarP(&validateRuleFlag, "rule", "r", "all", "Filter by rule (comma-separated)")
+	validateCmd.Flags().StringVarP(&validateConfigFlag, "config", "c", "", "Config file path")
+	validateCmd.Flags().StringVarP(&validateDirFlag, "directory", "d", ".", "Directory to scan")
+	validateCmd.Flags().StringVarP(&validateFileFlag, "file", "f", "", "Filter by file path patterns (comma-separated, supports globs)")
+	validateCmd.Flags().StringVarP(&validateOutputFlag, "output", "o", "", "Output format: json, csv, xml (default: table)")
+	validateCmd.Flags().BoolVar(&validateCleanLock, "require-clean-lock", false, "Also fail when a lock file of the detected packages has uncommitted git changes")
+}
+
+// runValidate executes the validate command.
+//
+// It performs the following operations:
+//   - Step 1: Load and schema-validate the configuration
+//   - Step 2: Detect packages and apply the filters
+//   - Step 3: Check the outdated and update commands (preflight.ValidatePackages)
+//     and the lock file commands (preflight.ValidateLockCommands) of detected rules
+//   - Step 4: Check that group members match detected packages and, with
+//     --require-clean-lock, that their lock files have no uncommitted changes
+//   - Step 5: Print every problem found as a validation error list, or with
+//     --output write the command checks and problems as structured output
+//
+// No version lookups run and no file is written.
+//
+// Parameters:
+//   - cmd: Cobra command instance
+//   - args: Optional file paths to validate (empty to auto-detect)
+//
+// Returns:
+//   - error: ExitError with ExitConfigError when any check fails
+func runValidate(cmd *cobra.Command, args []string) error {
+	outputFormat := output.ParseFormat(validateOutputFlag)
+	if err := output.ValidateStructuredOutputFlags(outputFormat, verboseFlag); err != nil {
+		return err
+	}
+	if err := output.ValidateUpdateOnlyFormat(outputFormat, "validate"); err != nil {
+		return err
+	}
+	if err := output.ValidateShieldsSupport(outputFormat, "validate"); err != nil {
+		return err
+	}
+
+	collector := &display.WarningCollector{}
+	restoreWarnings := warnings.SetWarningWriter(collector)
+	defer restoreWarnings()
+
+	workDir := validateDirFlag
+
+	cfg, err := loadAndValidateConfig(validateConfigFlag, workDir)
+	if err != nil {
+		return asConfigError(err)
+	}
+
+	workDir = resolveWorkingDir(workDir, cfg)
+	cfg.WorkingDir = workDir
+
+	packages, err := getPackagesFunc(cfg, args, workDir)
+	if err != nil {
+		return asConfigError(err)
+	}
+	if validateFileFlag != "" {
+		packages = filtering.FilterPackagesByFile(packages, validateFileFlag, workDir)
+	}
+	packages = filtering.FilterPackagesWithFilters(packages, validateTypeFlag, validatePMFlag, validateRuleFlag, "", "")
+
+	result, checks := validatePackagesForRun(packages, cfg)
+	if validateCleanLock {
+		if err := checkCleanLockFilesFunc(cfg, packages, workDir); err != nil {
+			verr, ok := errors.IsValidationError(err)
+			if !ok {
+				verr = &errors.ValidationError{Category: errors.ValidationCategoryPreflight, Message: err.Error()}
+			}
+			result.AddError(verr)
+		}
+	}
+	rules := countRules(packages)
+
+	if output.IsStructuredFormat(outputFormat) {
+		if err := output.WriteValidateResult(os.Stdout, outputFormat, buildValidateOutput(result, checks, len(packages), rules, collector.Messages())); err != nil {
+			return err
+		}
+		if result.HasErrors() {
+			return errors.NewExitErrorf(errors.ExitConfigError, "validation failed: %d problems found", len(result.Errors)).WithCause(result.Err())
+		}
+		return nil
+	}
+
+	if result.HasErrors() {
+		fmt.Printf("%s Validation failed for %d packages across %d rules\n\n", constants.IconError, len(packages), rules)
+		result.PrintTo(os.Stdout, verbose.IsEnabled())
+		verbose.Infof("Exit code %d (config error): validation found %d problems", errors.ExitConfigError, len(result.Errors))
+		return errors.NewExitErrorf(errors.ExitConfigError, "validation failed: %d problems found", len(result.Errors)).WithCause(result.Err())
+	}
+
+	for _, w := range collector.Messages() {
+		fmt.Printf("%s %s\n", constants.IconWarn, w)
+	}
+	fmt.Printf("%s Configuration and environment valid: %d packages across %d rules\n", constants.IconCheckmarkBox, len(packages), rules)
+	return nil
+}
+
+// validatePackagesForRun collects every problem that would stop an update run.
+//
+// Parameters:
+//   - packages: Detected packages after filtering
+//   - cfg: Loaded configuration
+//
+// Returns:
+//   - *errors.ValidationResult: Preflight errors for missing commands and config errors for unmatched group members
+//   - []preflight.CommandCheck: Every command checked, per rule, found or not
+func validatePackagesForRun(packages []formats.Package, cfg *config.Config) (*errors.ValidationResult, []preflight.CommandCheck) {
+	result := errors.NewValidationResult()
+	var checks []preflight.CommandCheck
+
+	// A command used by both update and lock file commands is reported once
+	reported := make(map[string]bool)
+	for _, check := range []*preflight.ValidateResult{
+		preflight.ValidatePackages(packages, cfg),
+		preflight.ValidateLockCommands(packages, cfg),
+	} {
+		checks = append(checks, check.Checks...)
+		for _, e := range check.Errors {
+			if !reported[e.Command] {
+				reported[e.Command] = true
+				result.AddError(errors.NewPreflightValidationError(e.Command, e.Hint))
+			}
+		}
+	}
+
+	for _, e := range filtering.ValidateGroupMembers(packages, cfg) {
+		result.AddError(e)
+	}
+
+	return result, checks
+}
+
+// buildValidateOutput converts validation results to the structured output format.
+//
+// Parameters:
+//   - result: Problems found by validatePackagesForRun
+//   - checks: Command checks from validatePackagesForRun
+//   - packages: Number of packages validated
+//   - rules: Number of distinct rules among the packages
+//   - warnings: Warning messages collected while loading packages
+//
+// Returns:
+//   - *output.ValidateResult: Structured result listing every check and problem
+func buildValidateOutput(result *errors.ValidationResult, checks []preflight.CommandCheck, packages, rules int, warnings []string) *output.ValidateResult {
+	out := &output.ValidateResult{
+		Summary:  output.ValidateSummary{TotalPackages: packages, Rules: rules, Problems: len(result.Errors)},
+		Valid:    !result.HasErrors(),
+		Commands: make([]output.ValidateCommand, 0, len(checks)),
+		Warnings: warnings,
+	}
+
+	missing := make(map[string]bool)
+	for _, c := range checks {
+		entry := output.ValidateCommand{Rule: c.Rule, Kind: c.Kind, Command: c.Command, Found: c.Found, Path: c.Path}
+		if c.Error != nil {
+			entry.Error = c.Error.Error()
+			missing[c.Command] = true
+		}
+		out.Commands = append(out.Commands, entry)
+	}
+	out.Summary.MissingCommands = len(missing)
+
+	for _, e := range result.Errors {
+		out.Errors = append(out.Errors, e.Error())
+	}
+	return out
+}
+
+// countRules returns the number of distinct rules among packages.
+func countRules(packages []formats.Package) int {
+	rules := make(map[string]bool)
+	for _, p := range packages {
+		rules[p.Rule] = true
+	}
+	return len(rules)
+}
+
+// asConfigError returns err as an ExitError with ExitConfigError unless it already carries an exit code.
+func asConfigError(err error) error {
+	if _, ok := errors.IsExitError(err); ok {
+		return err
+	}
+	return errors.NewExitError(errors.ExitConfigError, err)
+}
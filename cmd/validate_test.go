@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/output"
+)
+
+// setValidateFlagsForTest sets validate flags to their defaults and stubs package loading.
+//
+// The package updater is replaced with one that fails the test, since validate
+// must never apply an update.
+func setValidateFlagsForTest(t *testing.T, cfg *config.Config, packages []formats.Package) {
+	t.Helper()
+	oldType, oldPM, oldRule := validateTypeFlag, validatePMFlag, validateRuleFlag
+	oldConfig, oldDir, oldFile, oldOutput := validateConfigFlag, validateDirFlag, validateFileFlag, validateOutputFlag
+	oldLoad, oldGetPackages, oldUpdate := loadConfigFunc, getPackagesFunc, updatePackageFunc
+	oldCleanLock, oldCheckCleanLock := validateCleanLock, checkCleanLockFilesFunc
+	t.Cleanup(func() {
+		validateTypeFlag, validatePMFlag, validateRuleFlag = oldType, oldPM, oldRule
+		validateConfigFlag, validateDirFlag, validateFileFlag, validateOutputFlag = oldConfig, oldDir, oldFile, oldOutput
+		loadConfigFunc, getPackagesFunc, updatePackageFunc = oldLoad, oldGetPackages, oldUpdate
+		validateCleanLock, checkCleanLockFilesFunc = oldCleanLock, oldCheckCleanLock
+	})
+
+	validateTypeFlag, validatePMFlag, validateRuleFlag = "all", "all", "all"
+	validateConfigFlag, validateFileFlag, validateOutputFlag = "", "", ""
+	validateCleanLock = false
+	validateDirFlag = t.TempDir()
+
+	loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+		return cfg, nil
+	}
+	getPackagesFunc = func(cfg *config.Config, args []string, workDir string) ([]formats.Package, error) {
+		return packages, nil
+	}
+	updatePackageFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		t.Fatalf("validate must not update %s", p.Name)
+		return nil
+	}
+}
+
+// TestRunValidate tests the behavior of the validate command.
+//
+// It verifies:
+//   - A config whose commands exist and whose group members are detected passes
+//   - Missing outdated and lock file commands are reported as preflight errors
+//   - Group members without a detected package are reported as config errors
+//   - Every problem is listed and the command exits with ExitConfigError
+//   - Rule filters limit the checks to the selected rules
+//   - --output json lists every command check per rule instead of the human output
+//   - --require-clean-lock reports dirty lock files as a problem; without it they are not checked
+func TestRunValidate(t *testing.T) {
+	packages := []formats.Package{
+		{Name: "react", Rule: "npm", PackageType: "js", Type: "prod", Version: "18.2.0"},
+		{Name: "react-dom", Rule: "npm", PackageType: "js", Type: "prod", Version: "18.2.0"},
+		{Name: "requests", Rule: "pip", PackageType: "python", Type: "prod", Version: "2.31.0"},
+	}
+
+	t.Run("valid config", func(t *testing.T) {
+		cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+			"npm": {
+				Manager:   "js",
+				Outdated:  &config.OutdatedCfg{Commands: "echo {{package}}"},
+				Update:    &config.UpdateCfg{Commands: "echo {{package}}@{{version}}"},
+				LockFiles: []config.LockFileCfg{{Files: []string{"package-lock.json"}, Commands: "cat package-lock.json"}},
+				Groups:    map[string]config.GroupCfg{"react": {Packages: []string{"react", "React-DOM"}}},
+			},
+			"pip": {Manager: "python", Outdated: &config.OutdatedCfg{Commands: "echo"}},
+		}}
+		setValidateFlagsForTest(t, cfg, packages)
+
+		out := captureStdout(t, func() {
+			require.NoError(t, runValidate(nil, nil))
+		})
+		assert.Contains(t, out, "Configuration and environment valid: 3 packages across 2 rules")
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		cfg := &config.Config{
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Manager:   "js",
+					Outdated:  &config.OutdatedCfg{Commands: "goupdate_missing_outdated_cmd {{package}}"},
+					LockFiles: []config.LockFileCfg{{Files: []string{"package-lock.json"}, Commands: "goupdate_missing_lock_cmd"}},
+					Groups:    map[string]config.GroupCfg{"react": {Packages: []string{"react", "react-native"}}},
+				},
+				"pip": {
+					Manager:  "python",
+					Outdated: &config.OutdatedCfg{Commands: "goupdate_missing_pip_cmd"},
+				},
+				// Groups of rules without detected packages are not checked
+				"composer": {Groups: map[string]config.GroupCfg{"laravel": {Packages: []string{"laravel/framework"}}}},
+			},
+			Groups: map[string]config.GroupCfg{"core": {Packages: []string{"requests", "django"}}},
+		}
+		setValidateFlagsForTest(t, cfg, packages)
+
+		var err error
+		out := captureStdout(t, func() {
+			err = runValidate(nil, nil)
+		})
+		require.Error(t, err)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+		assert.Contains(t, err.Error(), "5 problems found")
+		assert.Contains(t, out, "Validation failed for 3 packages across 2 rules")
+		assert.Contains(t, out, "command not found: goupdate_missing_outdated_cmd")
+		assert.Contains(t, out, "command not found: goupdate_missing_lock_cmd")
+		assert.Contains(t, out, "command not found: goupdate_missing_pip_cmd")
+		assert.Contains(t, out, `rules.npm.groups.react: package "react-native" does not match any package detected for rule npm`)
+		assert.Contains(t, out, `groups.core: package "django" does not match any package detected in any rule`)
+		assert.NotContains(t, out, "laravel")
+
+		validateRuleFlag = "pip"
+		out = captureStdout(t, func() {
+			err = runValidate(nil, nil)
+		})
+		require.Error(t, err)
+		assert.Contains(t, out, "goupdate_missing_pip_cmd")
+		assert.NotContains(t, out, "goupdate_missing_outdated_cmd")
+		assert.NotContains(t, out, "react-native")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+			"npm": {
+				Manager:   "js",
+				Outdated:  &config.OutdatedCfg{Commands: "echo {{package}}"},
+				LockFiles: []config.LockFileCfg{{Files: []string{"package-lock.json"}, Commands: "goupdate_missing_lock_cmd"}},
+			},
+		}}
+		setValidateFlagsForTest(t, cfg, packages[:2])
+		validateOutputFlag = "json"
+
+		var err error
+		out := captureStdout(t, func() {
+			err = runValidate(nil, nil)
+		})
+		require.Error(t, err)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+		assert.NotContains(t, out, "Validation failed")
+
+		var result output.ValidateResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		assert.False(t, result.Valid)
+		assert.Equal(t, output.ValidateSummary{TotalPackages: 2, Rules: 1, Problems: 1, MissingCommands: 1}, result.Summary)
+		require.Len(t, result.Commands, 2)
+		assert.Equal(t, "echo", result.Commands[0].Command)
+		assert.True(t, result.Commands[0].Found)
+		missing := result.Commands[1]
+		assert.Equal(t, []any{"npm", "lock", "goupdate_missing_lock_cmd", false, ""}, []any{missing.Rule, missing.Kind, missing.Command, missing.Found, missing.Path})
+		assert.Contains(t, missing.Error, "command not found: goupdate_missing_lock_cmd")
+	})
+
+	t.Run("require clean lock", func(t *testing.T) {
+		cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+			"npm": {Manager: "js", Outdated: &config.OutdatedCfg{Commands: "echo {{package}}"}},
+		}}
+		setValidateFlagsForTest(t, cfg, packages[:2])
+		var checked int
+		checkCleanLockFilesFunc = func(cfg *config.Config, pkgs []formats.Package, dir string) error {
+			checked++
+			return &errors.ValidationError{Category: errors.ValidationCategoryPreflight, Message: "1 lock file(s) have uncommitted changes: package-lock.json"}
+		}
+
+		captureStdout(t, func() {
+			require.NoError(t, runValidate(nil, nil))
+		})
+		assert.Zero(t, checked)
+
+		validateCleanLock = true
+		var err error
+		out := captureStdout(t, func() {
+			err = runValidate(nil, nil)
+		})
+		require.Error(t, err)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+		assert.Contains(t, out, "1 lock file(s) have uncommitted changes: package-lock.json")
+
+		var cause *errors.ValidationError
+		require.True(t, stderrors.As(err, &cause), "exit error should wrap the validation errors")
+		assert.Equal(t, errors.ValidationCategoryPreflight, cause.Category)
+	})
+
+	t.Run("config load failure", func(t *testing.T) {
+		setValidateFlagsForTest(t, &config.Config{}, nil)
+		loadConfigFunc = func(path, workDir string) (*config.Config, error) {
+			return nil, stderrors.New("broken config")
+		}
+
+		err := runValidate(nil, nil)
+		require.Error(t, err)
+		assert.Equal(t, errors.ExitConfigError, errors.GetExitCode(err))
+		assert.Contains(t, err.Error(), "broken config")
+	})
+}
@@ -3,8 +3,10 @@ module github.com/ajxudir/goupdate
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/iancoleman/orderedmap v0.3.0
 	github.com/mattn/go-runewidth v0.0.19
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/spf13/cobra v1.7.0
 	github.com/stretchr/testify v1.8.4
 	golang.org/x/mod v0.30.0
@@ -15,6 +17,5 @@ require (
 	github.com/clipperhouse/uax29/v2 v2.2.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 )
@@ -0,0 +1,492 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// DefaultGitHubAPI is the GitHub REST API base URL.
+const DefaultGitHubAPI = "https://api.github.com"
+
+// MaxSnippetLength caps the release notes text attached to an update, in characters.
+const MaxSnippetLength = 600
+
+// defaultTimeout bounds a single release notes request.
+const defaultTimeout = 15 * time.Second
+
+// maxResponseSize caps the response body read from any source (5MB).
+const maxResponseSize = 5 * 1024 * 1024
+
+// Default registry base URLs used for repository lookups when the rule has no registry override.
+const (
+	defaultNPMRegistry  = "https://registry.npmjs.org"
+	defaultPyPIRegistry = "https://pypi.org/pypi"
+)
+
+// Notes summarizes the release notes between two versions of a package.
+type Notes struct {
+	// URL is the page with the full release notes.
+	URL string
+
+	// Snippet is the beginning of the release notes, at most MaxSnippetLength
+	// characters; empty when the source has no notes for the version range.
+	Snippet string
+}
+
+// Provider fetches release notes for a package version range.
+type Provider interface {
+	// Fetch returns the release notes published after from, up to and including to.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation
+	//   - p: The package being updated
+	//   - rule: The package's rule configuration
+	//   - from: The version before the update
+	//   - to: The target version
+	//
+	// Returns:
+	//   - *Notes: The release notes summary
+	//   - error: When no source is known for the package or the lookup fails
+	Fetch(ctx context.Context, p formats.Package, rule config.PackageManagerCfg, from, to string) (*Notes, error)
+}
+
+// Client fetches release notes from GitHub Releases, registry metadata and
+// plain-text changelog URLs.
+//
+// Client implements Provider.
+type Client struct {
+	// GitHubAPI is the GitHub REST API base URL. Defaults to DefaultGitHubAPI.
+	GitHubAPI string
+
+	// HTTPClient is the client used for requests.
+	HTTPClient *http.Client
+
+	// Token authenticates GitHub API requests; empty sends anonymous requests.
+	Token string
+}
+
+// NewClient creates a client for the public GitHub API.
+//
+// The GitHub token is read from $GITHUB_TOKEN to raise the API rate limit.
+//
+// Returns:
+//   - *Client: Client with default endpoint and timeout
+func NewClient() *Client {
+	return &Client{
+		GitHubAPI:  DefaultGitHubAPI,
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+		Token:      os.Getenv("GITHUB_TOKEN"),
+	}
+}
+
+// Fetch returns the release notes published after from, up to and including to.
+//
+// It performs the following operations:
+//   - Step 1: Use the rule's changelog_url template when configured
+//   - Step 2: Otherwise resolve the GitHub repository from the package name, its
+//     Repository URL or registry metadata
+//   - Step 3: Collect the GitHub releases in the version range
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package being updated
+//   - rule: The package's rule configuration
+//   - from: The version before the update
+//   - to: The target version
+//
+// Returns:
+//   - *Notes: The release notes summary
+//   - error: When no source is known for the package or the lookup fails
+func (c *Client) Fetch(ctx context.Context, p formats.Package, rule config.PackageManagerCfg, from, to string) (*Notes, error) {
+	if rule.ChangelogURL != "" {
+		target := ExpandURL(rule.ChangelogURL, p.Name, from, to)
+		if repo, ok := releasesRepository(target); ok {
+			return c.fetchReleases(ctx, repo, p.Name, from, to, versioningFor(rule))
+		}
+		return c.fetchText(ctx, target, to)
+	}
+
+	repo, err := c.repositoryFor(ctx, p, rule)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchReleases(ctx, repo, p.Name, from, to, versioningFor(rule))
+}
+
+// ExpandURL fills the placeholders of a changelog_url template.
+//
+// Parameters:
+//   - template: URL with {{package}}, {{from}} and {{to}} placeholders
+//   - name: Package name; path-escaped except for "/" so scoped and owner/repo names stay readable
+//   - from: The version before the update
+//   - to: The target version
+//
+// Returns:
+//   - string: The expanded URL
+//
+// Example:
+//
+//	changelog.ExpandURL("https://github.com/{{package}}", "actions/checkout", "v3", "v4") // "https://github.com/actions/checkout"
+func ExpandURL(template, name, from, to string) string {
+	escaped := strings.ReplaceAll(url.PathEscape(name), "%2F", "/")
+	return strings.NewReplacer(
+		"{{package}}", escaped,
+		"{{from}}", url.PathEscape(from),
+		"{{to}}", url.PathEscape(to),
+	).Replace(template)
+}
+
+// versioningFor returns the rule's versioning configuration used to order release tags.
+func versioningFor(rule config.PackageManagerCfg) *config.VersioningCfg {
+	if rule.Outdated == nil {
+		return nil
+	}
+	return rule.Outdated.Versioning
+}
+
+// repositoryFor resolves the GitHub repository ("owner/repo") of a package.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package to resolve
+//   - rule: The package's rule configuration; its registry override is used for metadata lookups
+//
+// Returns:
+//   - string: The repository as "owner/repo"
+//   - error: When the package's manager has no repository source or none is published
+func (c *Client) repositoryFor(ctx context.Context, p formats.Package, rule config.PackageManagerCfg) (string, error) {
+	if p.PackageType == "github-actions" {
+		if repo, ok := GitHubRepository("github.com/" + p.Name); ok {
+			return repo, nil
+		}
+	}
+	if repo, ok := GitHubRepository(p.Name); ok {
+		return repo, nil
+	}
+	if repo, ok := GitHubRepository(p.Repository); ok {
+		return repo, nil
+	}
+
+	var candidates []string
+	var err error
+	switch p.PackageType {
+	case "js":
+		candidates, err = c.npmRepositoryURLs(ctx, registryBase(rule, defaultNPMRegistry), p.Name)
+	case "python":
+		candidates, err = c.pypiRepositoryURLs(ctx, registryBase(rule, defaultPyPIRegistry), p.Name)
+	default:
+		return "", fmt.Errorf("no release notes source for %s packages; set changelog_url on rule %s", p.PackageType, p.Rule)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, candidate := range candidates {
+		if repo, ok := GitHubRepository(candidate); ok {
+			return repo, nil
+		}
+	}
+	return "", fmt.Errorf("no GitHub repository published for %s; set changelog_url on rule %s", p.Name, p.Rule)
+}
+
+// registryBase returns the rule's registry override or the given default, without a trailing slash.
+func registryBase(rule config.PackageManagerCfg, fallback string) string {
+	base := strings.TrimSpace(rule.Registry)
+	if base == "" {
+		base = fallback
+	}
+	return strings.TrimRight(base, "/")
+}
+
+// npmRepositoryURLs reads the repository field of an npm package's latest manifest.
+//
+// The latest manifest is requested instead of the full package document,
+// which can exceed the response size limit for packages with many versions.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - registry: npm registry base URL
+//   - name: Package name, scoped names included
+//
+// Returns:
+//   - []string: Repository and homepage URLs, most specific first
+//   - error: When the request fails or the document cannot be parsed
+func (c *Client) npmRepositoryURLs(ctx context.Context, registry, name string) ([]string, error) {
+	data, err := c.get(ctx, registry+"/"+url.PathEscape(name)+"/latest", "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Repository json.RawMessage `json:"repository"`
+		Homepage   string          `json:"homepage"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse npm metadata for %s: %w", name, err)
+	}
+
+	var urls []string
+	var repository struct {
+		URL string `json:"url"`
+	}
+	var shorthand string
+	if json.Unmarshal(doc.Repository, &repository) == nil && repository.URL != "" {
+		urls = append(urls, repository.URL)
+	} else if json.Unmarshal(doc.Repository, &shorthand) == nil && shorthand != "" {
+		// npm shorthand: "owner/repo" or "github:owner/repo"
+		if !strings.Contains(shorthand, ":") && strings.Count(shorthand, "/") == 1 {
+			shorthand = "github:" + shorthand
+		}
+		urls = append(urls, shorthand)
+	}
+	if doc.Homepage != "" {
+		urls = append(urls, doc.Homepage)
+	}
+	return urls, nil
+}
+
+// pypiRepositoryURLs reads the project URLs of a PyPI package.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - registry: PyPI JSON API base URL
+//   - name: Package name
+//
+// Returns:
+//   - []string: Project URLs sorted by label, then the home page
+//   - error: When the request fails or the document cannot be parsed
+func (c *Client) pypiRepositoryURLs(ctx context.Context, registry, name string) ([]string, error) {
+	data, err := c.get(ctx, registry+"/"+url.PathEscape(name)+"/json", "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Info struct {
+			HomePage    string            `json:"home_page"`
+			ProjectURLs map[string]string `json:"project_urls"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse PyPI metadata for %s: %w", name, err)
+	}
+
+	labels := make([]string, 0, len(doc.Info.ProjectURLs))
+	for label := range doc.Info.ProjectURLs {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	urls := make([]string, 0, len(labels)+1)
+	for _, label := range labels {
+		urls = append(urls, doc.Info.ProjectURLs[label])
+	}
+	if doc.Info.HomePage != "" {
+		urls = append(urls, doc.Info.HomePage)
+	}
+	return urls, nil
+}
+
+// GitHubRepository extracts "owner/repo" from a GitHub URL or module path.
+//
+// Parameters:
+//   - raw: A URL such as "git+https://github.com/lodash/lodash.git",
+//     "git@github.com:owner/repo.git", "github:owner/repo" or a module path
+//     such as "github.com/owner/repo/v2"
+//
+// Returns:
+//   - string: The repository as "owner/repo"
+//   - bool: false when raw does not point into a GitHub repository
+func GitHubRepository(raw string) (string, bool) {
+	s := strings.TrimSpace(raw)
+	s = strings.TrimPrefix(s, "git+")
+	if rest, ok := strings.CutPrefix(s, "github:"); ok {
+		s = "github.com/" + rest
+	}
+	for _, prefix := range []string{"https://", "http://", "git://", "ssh://"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s = strings.TrimPrefix(s, "git@")
+	s = strings.TrimPrefix(s, "www.")
+	s = strings.Replace(s, "github.com:", "github.com/", 1)
+
+	rest, ok := strings.CutPrefix(s, "github.com/")
+	if !ok {
+		return "", false
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 {
+		return "", false
+	}
+	owner := parts[0]
+	repo := strings.TrimSuffix(strings.SplitN(parts[1], "#", 2)[0], ".git")
+	if owner == "" || repo == "" {
+		return "", false
+	}
+	return owner + "/" + repo, true
+}
+
+// releasesRepository reports whether a changelog URL points at a repository's releases.
+//
+// Only https://github.com/owner/repo and https://github.com/owner/repo/releases are read
+// through the Releases API; any other URL (e.g. a raw CHANGELOG.md) is fetched as text.
+//
+// Parameters:
+//   - target: The expanded changelog URL
+//
+// Returns:
+//   - string: The repository as "owner/repo"
+//   - bool: true when the URL is a repository or its releases page
+func releasesRepository(target string) (string, bool) {
+	u, err := url.Parse(target)
+	if err != nil || !strings.EqualFold(strings.TrimPrefix(u.Host, "www."), "github.com") {
+		return "", false
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) == 2 || (len(segments) == 3 && segments[2] == "releases") {
+		return GitHubRepository("github.com/" + segments[0] + "/" + segments[1])
+	}
+	return "", false
+}
+
+// fetchText fetches a plain-text changelog and keeps the section of the target version.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - target: The changelog URL
+//   - to: The target version, used to find its section
+//
+// Returns:
+//   - *Notes: The URL and the target version's section (or the start of the file)
+//   - error: When the request fails
+func (c *Client) fetchText(ctx context.Context, target, to string) (*Notes, error) {
+	data, err := c.get(ctx, target, "text/plain, text/markdown, */*")
+	if err != nil {
+		return nil, err
+	}
+	return &Notes{URL: target, Snippet: Truncate(versionSection(string(data), to), MaxSnippetLength)}, nil
+}
+
+// versionSection returns the part of a changelog starting at the target version's heading.
+//
+// The section ends at the next heading of the same or a higher level. When no
+// heading mentions the version, the whole text is returned.
+//
+// Parameters:
+//   - text: The changelog text
+//   - version: The version to look for
+//
+// Returns:
+//   - string: The version's section, or text when it has none
+func versionSection(text, version string) string {
+	lines := strings.Split(text, "\n")
+	bare := strings.TrimPrefix(version, "v")
+
+	for i, line := range lines {
+		level := headingLevel(line)
+		if level == 0 || bare == "" || !strings.Contains(line, bare) {
+			continue
+		}
+
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if next := headingLevel(lines[j]); next > 0 && next <= level {
+				end = j
+				break
+			}
+		}
+		return strings.Join(lines[i:end], "\n")
+	}
+	return text
+}
+
+// headingLevel returns the Markdown heading level of a line; 0 for non-heading lines.
+func headingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level == len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+// Truncate shortens text to at most limit characters, marking the cut with "…".
+//
+// Parameters:
+//   - text: The text to shorten; surrounding whitespace is trimmed
+//   - limit: Maximum number of characters kept before the marker
+//
+// Returns:
+//   - string: text, or its first limit characters followed by "…"
+func Truncate(text string, limit int) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
+
+// get performs a GET request and returns the response body.
+//
+// The token is only sent to the GitHub API, never to registries or changelog URLs.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - target: The URL to fetch
+//   - accept: The Accept header value
+//
+// Returns:
+//   - []byte: The response body, at most maxResponseSize bytes
+//   - error: When the request fails or the status is not 200
+func (c *Client) get(ctx context.Context, target, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", target, err)
+	}
+	req.Header.Set("Accept", accept)
+	if c.Token != "" && strings.HasPrefix(target, c.githubAPI()+"/repos/") {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("release notes request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", target, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", target, resp.Status)
+	}
+	return data, nil
+}
+
+// githubAPI returns the configured GitHub API base URL without a trailing slash.
+func (c *Client) githubAPI() string {
+	if c.GitHubAPI == "" {
+		return DefaultGitHubAPI
+	}
+	return strings.TrimRight(c.GitHubAPI, "/")
+}
@@ -0,0 +1,200 @@
+package changelog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestGitHubRepository tests extracting owner/repo from repository URLs.
+//
+// It verifies:
+//   - npm, git, ssh, shorthand and module path forms are recognized
+//   - Non-GitHub URLs and incomplete paths are rejected
+func TestGitHubRepository(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+		ok   bool
+	}{
+		{"git+https://github.com/lodash/lodash.git", "lodash/lodash", true},
+		{"https://github.com/psf/requests", "psf/requests", true},
+		{"https://www.github.com/psf/requests/tree/main/docs", "psf/requests", true},
+		{"git://github.com/expressjs/express.git", "expressjs/express", true},
+		{"git@github.com:facebook/react.git", "facebook/react", true},
+		{"ssh://git@github.com/facebook/react.git", "facebook/react", true},
+		{"github:sindresorhus/got", "sindresorhus/got", true},
+		{"github.com/spf13/cobra/v2", "spf13/cobra", true},
+		{"https://github.com/vuejs/core.git#main", "vuejs/core", true},
+		{"https://gitlab.com/inkscape/inkscape", "", false},
+		{"https://github.com/lodash", "", false},
+		{"golang.org/x/mod", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := GitHubRepository(tt.raw)
+		assert.Equal(t, tt.ok, ok, tt.raw)
+		assert.Equal(t, tt.want, got, tt.raw)
+	}
+}
+
+// TestExpandURL tests filling changelog_url placeholders.
+//
+// It verifies:
+//   - Package, from and to placeholders are replaced
+//   - Slashes in scoped and owner/repo names are kept
+func TestExpandURL(t *testing.T) {
+	assert.Equal(t, "https://github.com/actions/checkout", ExpandURL("https://github.com/{{package}}", "actions/checkout", "v3", "v4"))
+	assert.Equal(t, "https://example.com/@babel/core/compare/7.0.0...7.1.0",
+		ExpandURL("https://example.com/{{package}}/compare/{{from}}...{{to}}", "@babel/core", "7.0.0", "7.1.0"))
+}
+
+// TestTruncate tests shortening release notes.
+//
+// It verifies:
+//   - Short text is returned trimmed
+//   - Long text is cut at the limit and marked with an ellipsis, counting characters not bytes
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "fixed", Truncate("  fixed\r\n", 10))
+	assert.Equal(t, "abc…", Truncate("abcdef", 3))
+	assert.Equal(t, "äöü…", Truncate("äöüß", 3))
+}
+
+// TestVersionSection tests locating a version's section in a changelog.
+//
+// It verifies:
+//   - The section runs from the version heading to the next heading of the same level
+//   - Sub-headings stay in the section
+//   - Text without a matching heading is returned whole
+func TestVersionSection(t *testing.T) {
+	text := `# Changelog
+
+## [2.1.0] - 2024-05-01
+### Fixed
+- Crash on empty input
+
+## [2.0.0] - 2024-01-01
+- Breaking change`
+
+	assert.Equal(t, "## [2.1.0] - 2024-05-01\n### Fixed\n- Crash on empty input\n", versionSection(text, "v2.1.0"))
+	assert.Equal(t, "## [2.0.0] - 2024-01-01\n- Breaking change", versionSection(text, "2.0.0"))
+	assert.Equal(t, text, versionSection(text, "3.0.0"))
+	assert.Equal(t, "no headings", versionSection("no headings", "1.0.0"))
+}
+
+// newTestClient starts a server answering the given paths and returns a client pointed at it.
+func newTestClient(t *testing.T, responses map[string]string) (*Client, *httptest.Server, *[]*http.Request) {
+	t.Helper()
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return &Client{GitHubAPI: server.URL, HTTPClient: server.Client()}, server, &requests
+}
+
+// TestClientFetchRegistryMetadata tests resolving the repository from registry metadata.
+//
+// It verifies:
+//   - npm repository objects and PyPI project URLs are resolved to GitHub repositories
+//   - The rule's registry override is used for the metadata lookup
+//   - The GitHub token is only sent to the GitHub API
+func TestClientFetchRegistryMetadata(t *testing.T) {
+	releases := `[
+		{"tag_name": "v4.17.21", "body": "Fix prototype pollution", "html_url": "https://github.com/lodash/lodash/releases/tag/v4.17.21"},
+		{"tag_name": "v4.17.20", "body": "Older"}
+	]`
+	client, server, requests := newTestClient(t, map[string]string{
+		"/lodash/latest":                `{"repository": {"type": "git", "url": "git+https://github.com/lodash/lodash.git"}}`,
+		"/requests/json":                `{"info": {"home_page": "", "project_urls": {"Documentation": "https://requests.readthedocs.io", "Source": "https://github.com/psf/requests"}}}`,
+		"/repos/lodash/lodash/releases": releases,
+		"/repos/psf/requests/releases":  `[{"tag_name": "v2.32.0", "body": "Security fix", "html_url": "https://github.com/psf/requests/releases/tag/v2.32.0"}]`,
+	})
+	client.Token = "secret"
+
+	npm := formats.Package{Name: "lodash", Rule: "npm", PackageType: "js"}
+	notes, err := client.Fetch(context.Background(), npm, config.PackageManagerCfg{Registry: server.URL + "/"}, "4.17.20", "4.17.21")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/lodash/lodash/releases/tag/v4.17.21", notes.URL)
+	assert.Equal(t, "## v4.17.21\nFix prototype pollution", notes.Snippet)
+
+	pypi := formats.Package{Name: "requests", Rule: "pipfile", PackageType: "python"}
+	notes, err = client.Fetch(context.Background(), pypi, config.PackageManagerCfg{Registry: server.URL}, "2.31.0", "2.32.0")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/psf/requests/releases/tag/v2.32.0", notes.URL)
+	assert.Contains(t, notes.Snippet, "Security fix")
+
+	for _, r := range *requests {
+		if strings.HasPrefix(r.URL.Path, "/repos/") {
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"), r.URL.Path)
+		} else {
+			assert.Empty(t, r.Header.Get("Authorization"), r.URL.Path)
+		}
+	}
+}
+
+// TestClientFetchSources tests the release notes sources that need no registry lookup.
+//
+// It verifies:
+//   - GitHub Actions and Go module names are read from their own repository
+//   - A Repository URL found during the version lookup is used without registry metadata
+//   - A changelog_url pointing at a repository uses the Releases API
+//   - Any other changelog_url is fetched as text and cut to the target version's section
+//   - Packages without a source and failed requests return errors
+func TestClientFetchSources(t *testing.T) {
+	client, server, _ := newTestClient(t, map[string]string{
+		"/repos/actions/checkout/releases": `[{"tag_name": "v4.1.0", "body": "Node 20"}]`,
+		"/repos/spf13/cobra/releases":      `[{"tag_name": "v1.8.0", "body": "Completions"}]`,
+		"/CHANGELOG.md":                    "# Changelog\n\n## 2.0.0\n- New API\n\n## 1.0.0\n- First release\n",
+	})
+
+	action := formats.Package{Name: "actions/checkout", Rule: "github-actions", PackageType: "github-actions"}
+	notes, err := client.Fetch(context.Background(), action, config.PackageManagerCfg{}, "v4.0.0", "v4.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/actions/checkout/releases", notes.URL, "target release has no page")
+	assert.Equal(t, "## v4.1.0\nNode 20", notes.Snippet)
+
+	module := formats.Package{Name: "github.com/spf13/cobra", Rule: "mod", PackageType: "golang"}
+	notes, err = client.Fetch(context.Background(), module, config.PackageManagerCfg{}, "v1.7.0", "v1.8.0")
+	require.NoError(t, err)
+	assert.Contains(t, notes.Snippet, "Completions")
+
+	gem := formats.Package{Name: "rails", Rule: "bundler", PackageType: "ruby"}
+	withRepository := gem
+	withRepository.Repository = "https://github.com/spf13/cobra"
+	notes, err = client.Fetch(context.Background(), withRepository, config.PackageManagerCfg{}, "1.7.0", "1.8.0")
+	require.NoError(t, err)
+	assert.Contains(t, notes.Snippet, "Completions")
+
+	notes, err = client.Fetch(context.Background(), gem, config.PackageManagerCfg{ChangelogURL: "https://github.com/spf13/cobra/releases"}, "1.7.0", "1.8.0")
+	require.NoError(t, err)
+	assert.Contains(t, notes.Snippet, "Completions")
+
+	notes, err = client.Fetch(context.Background(), gem, config.PackageManagerCfg{ChangelogURL: server.URL + "/CHANGELOG.md"}, "1.0.0", "2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/CHANGELOG.md", notes.URL)
+	assert.Equal(t, "## 2.0.0\n- New API", notes.Snippet)
+
+	_, err = client.Fetch(context.Background(), gem, config.PackageManagerCfg{}, "1.0.0", "2.0.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no release notes source for ruby packages")
+
+	_, err = client.Fetch(context.Background(), gem, config.PackageManagerCfg{ChangelogURL: server.URL + "/missing.md"}, "1.0.0", "2.0.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
@@ -0,0 +1,27 @@
+// Package changelog fetches release notes between two versions of a package.
+//
+// It is used by `goupdate update --changelog` to attach a short release notes
+// summary to every planned update, so changes can be reviewed before they are
+// applied (typically together with --dry-run).
+//
+// # Core Types
+//
+// Provider fetches the notes for a single package. Client is the default
+// implementation:
+//
+//	provider := changelog.NewClient()
+//	notes, err := provider.Fetch(ctx, pkg, rule, "4.17.20", "4.17.21")
+//
+// # Sources
+//
+// Release notes are located in this order:
+//   - The rule's changelog_url template, with {{package}}, {{from}} and {{to}} expanded
+//   - The package name itself for GitHub-hosted packages (Go modules, GitHub Actions)
+//   - The repository URL from registry metadata (npm and PyPI)
+//
+// GitHub repositories are read through the GitHub Releases API (authenticated
+// with $GITHUB_TOKEN when set); any other URL is fetched as plain text.
+//
+// Fetching is best-effort: callers report errors in verbose mode and never
+// let them change the outcome of an update.
+package changelog
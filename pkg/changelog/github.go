@@ -0,0 +1,148 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+)
+
+// githubReleasesPerPage is the number of releases requested; older releases are not considered.
+const githubReleasesPerPage = 100
+
+// githubRelease is a single entry of the GitHub list-releases response.
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	Draft   bool   `json:"draft"`
+}
+
+// releaseInRange is a release whose tag falls in the requested version range.
+type releaseInRange struct {
+	githubRelease
+	version string
+}
+
+// fetchReleases collects the GitHub releases published after from, up to and including to.
+//
+// It performs the following operations:
+//   - Step 1: List the repository's most recent releases
+//   - Step 2: Keep releases whose tag version is in (from, to], skipping drafts
+//   - Step 3: Join their notes newest first, each under its tag
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - repo: The repository as "owner/repo"
+//   - name: Package name, used to match monorepo tags such as "name@1.2.3"
+//   - from: The version before the update
+//   - to: The target version
+//   - versioning: Versioning configuration used to compare tags; nil uses semver
+//
+// Returns:
+//   - *Notes: The target release's page (or the releases page) and the joined notes
+//   - error: When the request fails or the response cannot be parsed
+func (c *Client) fetchReleases(ctx context.Context, repo, name, from, to string, versioning *config.VersioningCfg) (*Notes, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/releases?per_page=%d", c.githubAPI(), repo, githubReleasesPerPage)
+	data, err := c.get(ctx, endpoint, "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []githubRelease
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub releases of %s: %w", repo, err)
+	}
+
+	notes := &Notes{URL: "https://github.com/" + repo + "/releases"}
+	selected := selectReleases(releases, name, from, to, versioning)
+	if len(selected) == 0 {
+		return notes, nil
+	}
+
+	// Releases are sorted newest first, so only the first one can be the target
+	if latest := selected[0]; latest.HTMLURL != "" && compare(latest.version, to, versioning) == 0 {
+		notes.URL = latest.HTMLURL
+	}
+
+	var sections []string
+	for _, release := range selected {
+		section := "## " + release.TagName
+		if body := strings.TrimSpace(release.Body); body != "" {
+			section += "\n" + body
+		}
+		sections = append(sections, section)
+	}
+	notes.Snippet = Truncate(strings.Join(sections, "\n\n"), MaxSnippetLength)
+	return notes, nil
+}
+
+// selectReleases returns the releases in (from, to], newest first.
+//
+// Parameters:
+//   - releases: Releases as listed by the GitHub API
+//   - name: Package name, used to match monorepo tags such as "name@1.2.3"
+//   - from: The version before the update
+//   - to: The target version
+//   - versioning: Versioning configuration used to compare tags; nil uses semver
+//
+// Returns:
+//   - []releaseInRange: Matching non-draft releases sorted by version, newest first
+func selectReleases(releases []githubRelease, name, from, to string, versioning *config.VersioningCfg) []releaseInRange {
+	var selected []releaseInRange
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		version, ok := tagVersion(release.TagName, name)
+		if !ok {
+			continue
+		}
+		if compare(version, from, versioning) <= 0 || compare(version, to, versioning) > 0 {
+			continue
+		}
+		selected = append(selected, releaseInRange{githubRelease: release, version: version})
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return compare(selected[i].version, selected[j].version, versioning) > 0
+	})
+	return selected
+}
+
+// tagVersion extracts the version from a release tag.
+//
+// Parameters:
+//   - tag: The release tag, e.g. "v1.2.3", "1.2.3" or "name@1.2.3"
+//   - name: Package name; monorepo tags for other packages are rejected
+//
+// Returns:
+//   - string: The version without a "v" prefix
+//   - bool: false when the tag has no version or belongs to another package
+func tagVersion(tag, name string) (string, bool) {
+	version := strings.TrimSpace(tag)
+	if at := strings.LastIndex(version, "@"); at > 0 {
+		if version[:at] != name {
+			return "", false
+		}
+		version = version[at+1:]
+	}
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return "", false
+	}
+	return version, true
+}
+
+// compare compares two versions, treating invalid versioning configuration as semver.
+func compare(a, b string, versioning *config.VersioningCfg) int {
+	result, err := outdated.CompareVersions(a, b, versioning)
+	if err != nil {
+		result, _ = outdated.CompareVersions(a, b, nil)
+	}
+	return result
+}
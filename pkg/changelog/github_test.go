@@ -0,0 +1,86 @@
+package changelog
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTagVersion tests extracting versions from release tags.
+//
+// It verifies:
+//   - "v" prefixes and monorepo "name@version" tags are handled
+//   - Tags of other packages and tags without a version are rejected
+func TestTagVersion(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+		ok   bool
+	}{
+		{"v1.2.3", "1.2.3", true},
+		{"1.2.3", "1.2.3", true},
+		{"@babel/core@7.24.0", "7.24.0", true},
+		{"@babel/parser@7.24.0", "", false},
+		{"nightly", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := tagVersion(tt.tag, "@babel/core")
+		assert.Equal(t, tt.ok, ok, tt.tag)
+		assert.Equal(t, tt.want, got, tt.tag)
+	}
+}
+
+// TestSelectReleases tests choosing the releases between two versions.
+//
+// It verifies:
+//   - Only releases after from and up to to are kept
+//   - Drafts are skipped
+//   - Releases are sorted newest first regardless of listing order
+func TestSelectReleases(t *testing.T) {
+	releases := []githubRelease{
+		{TagName: "v1.3.0"},
+		{TagName: "v1.1.0"},
+		{TagName: "v1.2.0"},
+		{TagName: "v1.2.1", Draft: true},
+		{TagName: "v1.0.0"},
+		{TagName: "nightly"},
+	}
+
+	selected := selectReleases(releases, "pkg", "1.0.0", "v1.2.1", nil)
+	require.Len(t, selected, 2)
+	assert.Equal(t, "v1.2.0", selected[0].TagName)
+	assert.Equal(t, "v1.1.0", selected[1].TagName)
+}
+
+// TestFetchReleases tests building notes from GitHub releases.
+//
+// It verifies:
+//   - Notes of every release in range are joined under their tags and truncated
+//   - Without releases in range the releases page is returned with no snippet
+//   - Invalid responses return errors
+func TestFetchReleases(t *testing.T) {
+	long := strings.Repeat("x", MaxSnippetLength)
+	client, _, _ := newTestClient(t, map[string]string{
+		"/repos/o/r/releases":   `[{"tag_name": "v2.0.0", "body": "` + long + `"}, {"tag_name": "v1.1.0", "body": "minor"}]`,
+		"/repos/o/bad/releases": `{"message": "rate limited"}`,
+	})
+
+	notes, err := client.fetchReleases(context.Background(), "o/r", "r", "1.0.0", "2.0.0", nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(notes.Snippet, "## v2.0.0\nxxx"))
+	assert.True(t, strings.HasSuffix(notes.Snippet, "…"), "long notes are truncated")
+	assert.NotContains(t, notes.Snippet, "minor")
+
+	notes, err = client.fetchReleases(context.Background(), "o/r", "r", "2.0.0", "2.0.0", nil)
+	require.NoError(t, err)
+	assert.Equal(t, &Notes{URL: "https://github.com/o/r/releases"}, notes)
+
+	_, err = client.fetchReleases(context.Background(), "o/bad", "bad", "1.0.0", "2.0.0", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse GitHub releases")
+}
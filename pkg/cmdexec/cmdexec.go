@@ -6,6 +6,7 @@ package cmdexec
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -216,6 +217,21 @@ func applyReplacements(commands string, replacements map[string]string) string {
 	return result
 }
 
+// RenderCommands returns the commands Execute would run for the given replacements.
+//
+// Placeholders are replaced exactly as Execute replaces them, so the result is
+// what a dry run reports as the commands it skipped.
+//
+// Parameters:
+//   - commands: Command string containing template placeholders
+//   - replacements: Map of template keys to replacement values
+//
+// Returns:
+//   - string: Trimmed command string with all placeholders replaced and values shell-escaped
+func RenderCommands(commands string, replacements map[string]string) string {
+	return strings.TrimSpace(applyReplacements(commands, replacements))
+}
+
 // shellEscape escapes a string for safe use in shell commands.
 //
 // This function wraps values in single quotes and properly escapes any single quotes
@@ -567,6 +583,33 @@ func executeCommand(ctx context.Context, cmdStr string, environ []string, dir st
 	return stdout.Bytes(), nil
 }
 
+// commandNotFoundExitCode is the exit status POSIX shells use when a command is not found.
+const commandNotFoundExitCode = 127
+
+// IsCommandNotFound reports whether a command failed because the shell could not find it.
+//
+// Commands run through a shell, so a missing executable surfaces as the
+// shell's exit status 127 rather than as exec.ErrNotFound.
+//
+// Parameters:
+//   - err: Error returned by Execute or ExecuteWithContext
+//
+// Returns:
+//   - bool: true if the command or one of its pipeline stages was not found
+//
+// Example:
+//
+//	if _, err := cmdexec.Execute("bun pm ls", nil, dir, 30, nil); cmdexec.IsCommandNotFound(err) {
+//	    // bun is not installed
+//	}
+func IsCommandNotFound(err error) bool {
+	if errors.Is(err, exec.ErrNotFound) {
+		return true
+	}
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == commandNotFoundExitCode
+}
+
 // parseCommandArgs parses a command string into arguments, respecting quotes.
 //
 // This function splits a command string into individual arguments while properly
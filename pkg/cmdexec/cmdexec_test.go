@@ -2,6 +2,7 @@ package cmdexec
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
@@ -819,3 +820,26 @@ func TestSetProcGroup(t *testing.T) {
 		assert.True(t, cmd.SysProcAttr.Setpgid)
 	})
 }
+
+// TestIsCommandNotFound tests the behavior of IsCommandNotFound.
+//
+// It verifies:
+//   - A missing command run through the shell is reported as not found
+//   - A command that runs and fails is not
+//   - exec.ErrNotFound is reported as not found; nil is not
+func TestIsCommandNotFound(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping Unix-specific test on Windows")
+	}
+
+	_, err := Execute("goupdate-missing-command-for-test --version", nil, t.TempDir(), 10, nil)
+	require.Error(t, err)
+	assert.True(t, IsCommandNotFound(err))
+
+	_, err = Execute("exit 3", nil, t.TempDir(), 10, nil)
+	require.Error(t, err)
+	assert.False(t, IsCommandNotFound(err))
+
+	assert.True(t, IsCommandNotFound(fmt.Errorf("lookup: %w", exec.ErrNotFound)))
+	assert.False(t, IsCommandNotFound(nil))
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// ConfigDirName is the config directory auto-detected in the working directory
+// when none of LocalConfigNames exists.
+const ConfigDirName = ".goupdate.d"
+
+// FindConfigDir returns the config directory to auto-detect in a directory.
+//
+// Parameters:
+//   - workDir: directory to search
+//
+// Returns:
+//   - string: path of workDir/.goupdate.d, or empty if it is not a directory
+func FindConfigDir(workDir string) string {
+	path := filepath.Join(workDir, ConfigDirName)
+	if !IsConfigDir(path) {
+		return ""
+	}
+	return path
+}
+
+// IsConfigDir reports whether a config path is a directory of config files.
+//
+// Parameters:
+//   - path: config path given by --config, --config-dir, or extends
+//
+// Returns:
+//   - bool: true if path exists and is a directory
+func IsConfigDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// ConfigDirFiles lists the config files of a config directory.
+//
+// Files ending in .yml, .yaml, or .toml are returned in alphabetical order,
+// which is the order they are merged in. Subdirectories are not searched.
+//
+// Parameters:
+//   - dir: config directory
+//
+// Returns:
+//   - []string: paths of the config files, sorted by name
+//   - error: error if the directory cannot be read
+func ConfigDirFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yml", ".yaml", ".toml":
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// loadConfigDir loads a directory of config files into one configuration.
+//
+// Each file is a config of its own: it may use extends, resolved relative to
+// the directory. Every distinct extends entry across the files is merged first,
+// so several files can list "extends: [default]" without re-applying the
+// defaults over earlier files. The files' own settings are then merged on top
+// in alphabetical order, later files overriding earlier ones.
+//
+// Security settings come from the root config. A directory extended by another
+// config uses that config's settings and ignores the security sections of its
+// files. A directory loaded as the root config takes its settings from its own
+// files, later files overriding earlier ones.
+//
+// It performs the following operations:
+//   - Step 1: Load every .yml, .yaml and .toml file in the directory within the root's size limit
+//   - Step 2: Reject a rule declared by two files unless the later one sets override: true
+//   - Step 3: Merge the distinct extends of all files into a base config under the root's security settings
+//   - Step 4: Merge each file's own settings over the base in file order
+//
+// Parameters:
+//   - dir: config directory
+//   - stack: configs being loaded, to detect extends cycles through the directory
+//   - rootCfg: the root configuration containing security settings; nil when the directory is the root config
+//
+// Returns:
+//   - *Config: the merged configuration
+//   - error: *errors.ValidationError for conflicting rules, or error if a file cannot be loaded
+func loadConfigDir(dir string, stack map[string]bool, rootCfg *Config) (*Config, error) {
+	files, err := ConfigDirFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .yml, .yaml or .toml config files in %s", dir)
+	}
+
+	maxFileSize := int64(DefaultMaxConfigFileSize)
+	if rootCfg != nil {
+		maxFileSize = rootCfg.GetMaxConfigFileSize()
+	}
+
+	loaded := make([]*Config, 0, len(files))
+	declaredBy := make(map[string]string)
+	root := rootCfg
+	if root == nil {
+		root = &Config{}
+		root.SetRootConfig(true)
+	}
+	for _, path := range files {
+		cfg, err := loadConfigFileWithLimit(path, maxFileSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", filepath.Base(path), err)
+		}
+		// Only rules the file declares itself can conflict; rules it extends are shared
+		for key, rule := range cfg.Rules {
+			if first, ok := declaredBy[key]; ok && !rule.Override {
+				return nil, &errors.ValidationError{
+					Category:   errors.ValidationCategoryConfig,
+					Field:      "rules." + key,
+					Message:    fmt.Sprintf("rule %q in %s is already defined in %s", key, filepath.Base(path), filepath.Base(first)),
+					Expected:   "override: true on the later rule to merge it over the earlier one",
+					DocSection: "config-directories",
+				}
+			}
+			declaredBy[key] = path
+		}
+		if rootCfg == nil && cfg.Security != nil {
+			root.Security = cfg.Security
+		}
+		loaded = append(loaded, cfg)
+	}
+
+	base := &Config{Rules: make(map[string]PackageManagerCfg)}
+	extended := make(map[string]bool)
+	for _, cfg := range loaded {
+		for _, extend := range cfg.Extends {
+			key := extend
+			if extend != "default" && !filepath.IsAbs(extend) {
+				key = filepath.Join(dir, extend)
+			}
+			if extended[key] {
+				continue
+			}
+			extended[key] = true
+
+			extendCfg, err := processExtendsWithStackSecure(&Config{Extends: []string{extend}, Rules: make(map[string]PackageManagerCfg)}, dir, stack, root)
+			if err != nil {
+				return nil, err
+			}
+			base = mergeConfigs(base, extendCfg)
+		}
+	}
+
+	result := base
+	for _, cfg := range loaded {
+		cfg.Extends = nil
+		result = mergeConfigs(result, cfg)
+	}
+
+	verbose.Printf("Config directory %s: merged %d files, %d rules\n", dir, len(files), len(result.Rules))
+	return result, nil
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+)
+
+// writeConfigDir creates a config directory holding the given files.
+func writeConfigDir(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+}
+
+// TestConfigDirFiles tests listing the files of a config directory.
+//
+// It verifies:
+//   - .yml, .yaml and .toml files are returned in alphabetical order
+//   - Other files and subdirectories are skipped
+func TestConfigDirFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigDir(t, dir, map[string]string{
+		"20-pip.yaml":  "",
+		"10-npm.yml":   "",
+		"30-go.toml":   "",
+		"README.md":    "",
+		"disabled.bak": "",
+	})
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested.yml"), 0o755))
+
+	files, err := ConfigDirFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "10-npm.yml"),
+		filepath.Join(dir, "20-pip.yaml"),
+		filepath.Join(dir, "30-go.toml"),
+	}, files)
+
+	_, err = ConfigDirFiles(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+// TestLoadConfigDir tests loading rules from a directory of config files.
+//
+// It verifies:
+//   - Rules from every file are merged, and extends: default applies once
+//   - A .goupdate.d directory is auto-detected when no config file exists
+//   - A local config file takes precedence over .goupdate.d
+//   - A rule declared by two files is a *errors.ValidationError
+//   - override: true merges the later file's rule over the earlier one
+//   - A config file can extend a config directory
+//   - An extended directory's files are held to the root config's security settings
+//   - A directory loaded as the root config uses its files' security settings
+//   - An empty directory is an error
+func TestLoadConfigDir(t *testing.T) {
+	t.Run("merges files in order", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfigDir(t, dir, map[string]string{
+			"npm.yml": "extends: [default]\nrules:\n  npm:\n    groups:\n      frontend: [react]\n",
+			"pip.yml": "extends: [default]\nrules:\n  custom:\n    manager: custom\n    include: [\"*.custom\"]\n    format: raw\n",
+		})
+
+		cfg, err := LoadConfig(dir, dir)
+		require.NoError(t, err)
+		assert.Contains(t, cfg.Rules, "custom")
+		assert.Contains(t, cfg.Rules, "requirements")
+		// The second file's extends must not reset the first file's npm groups
+		assert.Contains(t, cfg.Rules["npm"].Groups, "frontend")
+		assert.NotEmpty(t, cfg.Rules["npm"].Include)
+		assert.Empty(t, cfg.Extends)
+	})
+
+	t.Run("auto-detects .goupdate.d", func(t *testing.T) {
+		workDir := t.TempDir()
+		writeConfigDir(t, filepath.Join(workDir, ConfigDirName), map[string]string{
+			"custom.yml": "rules:\n  custom:\n    manager: custom\n    include: [\"*.custom\"]\n    format: raw\n",
+		})
+
+		cfg, err := LoadConfig("", workDir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"custom"}, keysOf(cfg.Rules))
+		assert.Equal(t, workDir, cfg.WorkingDir)
+
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, ".goupdate.yml"), []byte("rules:\n  local:\n    manager: local\n    include: [\"*.local\"]\n    format: raw\n"), 0o644))
+		cfg, err = LoadConfig("", workDir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"local"}, keysOf(cfg.Rules))
+	})
+
+	t.Run("conflicting rules", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfigDir(t, dir, map[string]string{
+			"a.yml": "rules:\n  npm:\n    manager: js\n    include: [\"**/package.json\"]\n",
+			"b.yml": "rules:\n  npm:\n    manager: js\n    include: [\"web/package.json\"]\n",
+		})
+
+		_, err := LoadConfig(dir, dir)
+		require.Error(t, err)
+		var ve *errors.ValidationError
+		require.True(t, stderrors.As(err, &ve))
+		assert.Equal(t, "rules.npm", ve.Field)
+		assert.Contains(t, ve.Message, `rule "npm" in b.yml is already defined in a.yml`)
+	})
+
+	t.Run("override marker", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfigDir(t, dir, map[string]string{
+			"a.yml": "rules:\n  npm:\n    manager: js\n    include: [\"**/package.json\"]\n    format: json\n",
+			"b.yml": "rules:\n  npm:\n    override: true\n    include: [\"web/package.json\"]\n",
+		})
+
+		cfg, err := LoadConfig(dir, dir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"web/package.json"}, cfg.Rules["npm"].Include)
+		assert.Equal(t, "json", cfg.Rules["npm"].Format)
+	})
+
+	t.Run("extends a directory", func(t *testing.T) {
+		workDir := t.TempDir()
+		writeConfigDir(t, filepath.Join(workDir, "shared"), map[string]string{
+			"custom.yml": "rules:\n  custom:\n    manager: custom\n    include: [\"*.custom\"]\n    format: raw\n",
+		})
+		configPath := filepath.Join(workDir, "config.yml")
+		require.NoError(t, os.WriteFile(configPath, []byte("extends: [shared]\nrules:\n  custom:\n    format: json\n"), 0o644))
+
+		cfg, err := LoadConfig(configPath, workDir)
+		require.NoError(t, err)
+		assert.Equal(t, "json", cfg.Rules["custom"].Format)
+		assert.Equal(t, "custom", cfg.Rules["custom"].Manager)
+	})
+
+	t.Run("extended directory uses the root's security settings", func(t *testing.T) {
+		workDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, "other.yml"), []byte("rules:\n  other:\n    manager: other\n    include: [\"*.other\"]\n    format: raw\n"), 0o644))
+		writeConfigDir(t, filepath.Join(workDir, "shared", "dir"), map[string]string{
+			"a.yml": "security:\n  allow_path_traversal: true\nextends: [\"../../other.yml\"]\n",
+		})
+		configPath := filepath.Join(workDir, "config.yml")
+		require.NoError(t, os.WriteFile(configPath, []byte("extends: [shared/dir]\n"), 0o644))
+
+		_, err := LoadConfig(configPath, workDir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "path traversal not allowed")
+
+		require.NoError(t, os.WriteFile(configPath, []byte("security:\n  max_config_file_size: 10\nextends: [shared/dir]\n"), 0o644))
+		_, err = LoadConfig(configPath, workDir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config file too large")
+	})
+
+	t.Run("root directory uses its own security settings", func(t *testing.T) {
+		workDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, "other.yml"), []byte("rules:\n  other:\n    manager: other\n    include: [\"*.other\"]\n    format: raw\n"), 0o644))
+		dir := filepath.Join(workDir, "dir")
+		writeConfigDir(t, dir, map[string]string{
+			"a.yml": "security:\n  allow_path_traversal: true\n",
+			"b.yml": "extends: [\"../other.yml\"]\n",
+		})
+
+		cfg, err := LoadConfig(dir, workDir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"other"}, keysOf(cfg.Rules))
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		_, err := LoadConfig(t.TempDir(), "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no .yml, .yaml or .toml config files")
+	})
+}
+
+// keysOf returns the sorted rule keys of a configuration.
+func keysOf(rules map[string]PackageManagerCfg) []string {
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
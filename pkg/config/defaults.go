@@ -27,6 +27,27 @@ func loadDefaultConfig() *Config {
 	return &Config{Rules: make(map[string]PackageManagerCfg)}
 }
 
+// DefaultRules returns the rules of the embedded default configuration.
+//
+// Each call returns a freshly parsed map, so callers may modify it.
+//
+// Returns:
+//   - map[string]PackageManagerCfg: the built-in rules keyed by rule name
+func DefaultRules() map[string]PackageManagerCfg {
+	return loadDefaultConfig().Rules
+}
+
+// DefaultExcludeVersions returns the global exclude_versions patterns of the embedded default configuration.
+//
+// These are the built-in pre-release exclusions (alpha, beta, rc, ...), which
+// --allow-prerelease lifts while keeping user-defined patterns.
+//
+// Returns:
+//   - []string: the built-in exclusion patterns
+func DefaultExcludeVersions() []string {
+	return loadDefaultConfig().ExcludeVersions
+}
+
 // GetDefaultConfig returns the embedded default configuration YAML.
 //
 // This returns the raw YAML string from the embedded default.yml file.
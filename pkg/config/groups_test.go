@@ -258,6 +258,7 @@ func TestRuleLevelPackageSettings(t *testing.T) {
 // It verifies:
 //   - Rule-level package settings have highest priority
 //   - Group-level settings apply to all packages in the group
+//   - Rule-level include_transitive applies to every package
 func TestShouldUpdateWithAllDependencies(t *testing.T) {
 	rule := PackageManagerCfg{
 		Packages: map[string]PackageSettings{
@@ -287,4 +288,10 @@ func TestShouldUpdateWithAllDependencies(t *testing.T) {
 
 	// Unknown package returns false
 	assert.False(t, rule.ShouldUpdateWithAllDependencies("unknown-pkg"))
+
+	// Rule-level include_transitive applies to every package
+	includeTransitive := true
+	rule.IncludeTransitive = &includeTransitive
+	assert.True(t, rule.ShouldUpdateWithAllDependencies("no-flag-pkg"))
+	assert.True(t, rule.ShouldUpdateWithAllDependencies("unknown-pkg"))
 }
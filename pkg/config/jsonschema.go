@@ -0,0 +1,251 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// jsonSchemaDraft07 is the meta-schema URI of the generated document.
+const jsonSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// schemaEnums lists the allowed values of enum-like string fields.
+//
+// Keys are "<TypeName>.<yaml key>". TestJSONSchemaCoverage fails if a key no
+// longer names a config field.
+var schemaEnums = map[string][]string{
+	"PackageManagerCfg.format":             {"json", "yaml", "xml", "raw"},
+	"PackageManagerCfg.constraint_style":   {ConstraintStylePreserve, ConstraintStyleCaret, ConstraintStyleTilde, ConstraintStyleExact},
+	"PackageManagerCfg.selection_strategy": {SelectionStrategyHighest, SelectionStrategyLowestNonVulnerable, SelectionStrategyMostRecentByDate},
+	"OutdatedCfg.format":                   {"json", "yaml", "raw"},
+	"OutdatedOverrideCfg.format":           {"json", "yaml", "raw"},
+	"ReleaseDatesCfg.format":               {"json", "raw"},
+	"YankedCfg.format":                     {YankedFormatJSON, YankedFormatRaw, YankedFormatGoMod},
+	"RepositoryCfg.format":                 {"json", "raw"},
+	"LockCommandExtractionCfg.format":      {"json", "raw"},
+	"VersioningCfg.format":                 {"semver", "numeric", "regex", "ordered", "list", "sorted"},
+	"VersioningCfg.sort":                   {"asc", "desc"},
+	"SystemTestsCfg.run_mode":              {SystemTestRunModeAfterEach, SystemTestRunModeAfterAll, SystemTestRunModeNone},
+	"NotifyCfg.format":                     {NotifyFormatJSON, NotifyFormatSlack},
+	"TreeCfg.format":                       {TreeFormatNPMLs, TreeFormatGoModGraph, TreeFormatComposerTree},
+	"WorkspaceFileCfg.format":              {WorkspaceFormatGoWork},
+}
+
+// schemaRequired lists the keys that must be present in each config type.
+//
+// Rule fields are not required because rules may partially override an
+// extended rule.
+var schemaRequired = map[string][]string{
+	"LockFileCfg":      {"files"},
+	"BadgeColorCfg":    {"color"},
+	"DedupeCfg":        {"commands"},
+	"NotifyCfg":        {"url"},
+	"PatternCfg":       {"pattern"},
+	"ResolveDigestCfg": {"commands", "pattern"},
+	"SystemTestCfg":    {"name", "commands"},
+	"SystemTestsCfg":   {"tests"},
+	"TreeCfg":          {"commands", "format"},
+	"WorkspaceFileCfg": {"file", "format"},
+}
+
+// customSchemas describes types with custom YAML unmarshaling whose accepted
+// shape differs from their Go struct fields.
+var customSchemas = map[string]func() map[string]any{
+	"GroupCfg":         groupJSONSchema,
+	"LatestMappingCfg": latestMappingJSONSchema,
+}
+
+// JSONSchema generates a JSON Schema (draft-07) document for the config file.
+//
+// The schema is built by reflecting over Config and every type reachable from
+// it, using the yaml struct tags as property names. Each config type becomes an
+// entry under "definitions" with additionalProperties disabled, matching the
+// strict unknown-field checks of ValidateConfigFile. Enum values and required
+// keys come from schemaEnums and schemaRequired; GroupCfg and LatestMappingCfg
+// use hand-written schemas because they accept several YAML shapes.
+//
+// Returns:
+//   - []byte: Indented JSON Schema document
+//   - error: When a config field has a type that cannot be described
+func JSONSchema() ([]byte, error) {
+	gen := &schemaGenerator{definitions: make(map[string]any)}
+
+	root, err := gen.structSchema(reflect.TypeOf(Config{}))
+	if err != nil {
+		return nil, err
+	}
+
+	root["$schema"] = jsonSchemaDraft07
+	root["title"] = "goupdate configuration"
+	root["description"] = "Schema for .goupdate.yml and goupdate.toml configuration files."
+	root["definitions"] = gen.definitions
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaGenerator accumulates definitions while walking config types.
+type schemaGenerator struct {
+	definitions map[string]any
+}
+
+// structSchema builds an object schema for a config struct type.
+//
+// Parameters:
+//   - t: The struct type to describe
+//
+// Returns:
+//   - map[string]any: Object schema with one property per yaml-tagged field
+//   - error: When a field type is unsupported
+func (g *schemaGenerator) structSchema(t reflect.Type) (map[string]any, error) {
+	properties := make(map[string]any)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := yamlFieldName(field)
+		if key == "" {
+			continue
+		}
+
+		prop, err := g.typeSchema(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		if values, ok := schemaEnums[t.Name()+"."+key]; ok {
+			prop["enum"] = values
+		}
+		properties[key] = prop
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if required, ok := schemaRequired[t.Name()]; ok {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// typeSchema builds the schema for a field type, registering named structs as definitions.
+//
+// Parameters:
+//   - t: The field type to describe
+//
+// Returns:
+//   - map[string]any: Schema for the type, or a $ref to its definition
+//   - error: When the type kind cannot be described
+func (g *schemaGenerator) typeSchema(t reflect.Type) (map[string]any, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Interface:
+		return map[string]any{}, nil
+	case reflect.Slice:
+		items, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := g.definitions[name]; !ok {
+			// Reserve the name first so recursive types terminate
+			g.definitions[name] = nil
+			if custom, ok := customSchemas[name]; ok {
+				g.definitions[name] = custom()
+			} else {
+				def, err := g.structSchema(t)
+				if err != nil {
+					return nil, err
+				}
+				g.definitions[name] = def
+			}
+		}
+		return map[string]any{"$ref": "#/definitions/" + name}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// yamlFieldName returns the YAML key of a struct field.
+//
+// Parameters:
+//   - field: The struct field
+//
+// Returns:
+//   - string: The key from the yaml tag, or empty for unexported and `yaml:"-"` fields
+func yamlFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" || name == "" {
+		return ""
+	}
+	return name
+}
+
+// groupJSONSchema describes GroupCfg, which accepts a package list or a settings map.
+//
+// Returns:
+//   - map[string]any: Schema matching GroupCfg.UnmarshalYAML
+func groupJSONSchema() map[string]any {
+	packages := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	return map[string]any{
+		"oneOf": []any{
+			packages,
+			map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"packages":              packages,
+					"members":               packages,
+					"with_all_dependencies": map[string]any{"type": "boolean"},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+}
+
+// latestMappingJSONSchema describes LatestMappingCfg, whose mappings may be maps or sequences.
+//
+// Returns:
+//   - map[string]any: Schema matching LatestMappingCfg.UnmarshalYAML
+func latestMappingJSONSchema() map[string]any {
+	mapping := map[string]any{
+		"oneOf": []any{
+			map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"default":  mapping,
+			"packages": map[string]any{"type": "object", "additionalProperties": mapping},
+		},
+		"additionalProperties": false,
+	}
+}
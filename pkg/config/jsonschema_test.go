@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeJSONSchema generates the config schema and decodes it into a generic map.
+func decodeJSONSchema(t *testing.T) map[string]any {
+	t.Helper()
+
+	data, err := JSONSchema()
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	return schema
+}
+
+// TestJSONSchema tests the generated JSON Schema document.
+//
+// It verifies:
+//   - The document declares draft-07 and rejects unknown top-level keys
+//   - Rules reference the PackageManagerCfg definition
+//   - Enum values are emitted for run_mode and format fields
+//   - Required keys are emitted for system tests
+//   - Groups accept both list and map shapes
+func TestJSONSchema(t *testing.T) {
+	schema := decodeJSONSchema(t)
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", schema["$schema"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	properties := schema["properties"].(map[string]any)
+	rules := properties["rules"].(map[string]any)
+	assert.Equal(t, "object", rules["type"])
+	assert.Equal(t, "#/definitions/PackageManagerCfg", rules["additionalProperties"].(map[string]any)["$ref"])
+
+	definitions := schema["definitions"].(map[string]any)
+	systemTests := definitions["SystemTestsCfg"].(map[string]any)
+	runMode := systemTests["properties"].(map[string]any)["run_mode"].(map[string]any)
+	assert.Equal(t, []any{"after_each", "after_all", "none"}, runMode["enum"])
+
+	systemTest := definitions["SystemTestCfg"].(map[string]any)
+	assert.Equal(t, []any{"name", "commands"}, systemTest["required"])
+
+	rule := definitions["PackageManagerCfg"].(map[string]any)
+	format := rule["properties"].(map[string]any)["format"].(map[string]any)
+	assert.Contains(t, format["enum"], "raw")
+
+	group := definitions["GroupCfg"].(map[string]any)
+	assert.Len(t, group["oneOf"], 2)
+}
+
+// TestJSONSchemaCoverage keeps the JSON Schema in sync with the config types.
+//
+// It verifies:
+//   - Every yaml-tagged field of every config type reachable from Config has a schema property
+//   - Every schemaEnums and schemaRequired entry names an existing config field
+func TestJSONSchemaCoverage(t *testing.T) {
+	definitions := decodeJSONSchema(t)["definitions"].(map[string]any)
+
+	fields := make(map[string]map[string]bool)
+	var walk func(reflect.Type)
+	walk = func(typ reflect.Type) {
+		for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct || fields[typ.Name()] != nil {
+			return
+		}
+		fields[typ.Name()] = make(map[string]bool)
+		for i := 0; i < typ.NumField(); i++ {
+			if key := yamlFieldName(typ.Field(i)); key != "" {
+				fields[typ.Name()][key] = true
+			}
+			walk(typ.Field(i).Type)
+		}
+	}
+	walk(reflect.TypeOf(Config{}))
+
+	for typeName, keys := range fields {
+		if typeName == "Config" {
+			continue
+		}
+		def, ok := definitions[typeName].(map[string]any)
+		require.True(t, ok, "missing schema definition for %s", typeName)
+		if _, custom := customSchemas[typeName]; custom {
+			continue
+		}
+		properties := def["properties"].(map[string]any)
+		for key := range keys {
+			assert.Contains(t, properties, key, "%s.%s has no schema property", typeName, key)
+		}
+	}
+
+	for enumKey := range schemaEnums {
+		typeName, key, _ := strings.Cut(enumKey, ".")
+		assert.True(t, fields[typeName][key], "schemaEnums entry %s does not name a config field", enumKey)
+	}
+	for typeName, keys := range schemaRequired {
+		for _, key := range keys {
+			assert.True(t, fields[typeName][key], "schemaRequired entry %s.%s does not name a config field", typeName, key)
+		}
+	}
+}
@@ -1,5 +1,5 @@
 // Package config handles configuration loading, validation, and merging for goupdate.
-// It supports YAML-based configuration files with inheritance (extends), rule-based
+// It supports YAML and TOML configuration files with inheritance (extends), rule-based
 // package manager definitions, and package-specific overrides.
 package config
 
@@ -15,13 +15,16 @@ import (
 
 // LoadConfig loads configuration from the specified path or defaults.
 //
-// If configPath is provided, it loads that specific config file.
-// Otherwise, it looks for .goupdate.yml in the working directory.
+// If configPath is provided, it loads that specific config file, or every
+// config file of it when it is a directory (see loadConfigDir).
+// Otherwise, it looks for .goupdate.yml, .goupdate.toml, or goupdate.toml in
+// the working directory (first match wins, see LocalConfigNames), and then
+// for a .goupdate.d directory (see ConfigDirName).
 // If no config is found, it returns the built-in default configuration.
 // Supports config inheritance via the extends mechanism.
 //
 // Parameters:
-//   - configPath: path to the config file, or empty to use defaults
+//   - configPath: path to the config file or directory, or empty to use defaults
 //   - workDir: working directory for the configuration
 //
 // Returns:
@@ -31,7 +34,15 @@ func LoadConfig(configPath, workDir string) (*Config, error) {
 	var cfg *Config
 	var extended []string
 
-	if configPath != "" {
+	if configPath != "" && IsConfigDir(configPath) {
+		verbose.Infof("Loading config directory: %s", configPath)
+		loaded, err := loadConfigDir(configPath, make(map[string]bool), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config directory: %w", err)
+		}
+		cfg = loaded
+		cfg.SetRootConfig(true)
+	} else if configPath != "" {
 		verbose.Infof("Loading config from: %s", configPath)
 		// Load specified config
 		loaded, err := loadConfigFile(configPath)
@@ -49,9 +60,8 @@ func LoadConfig(configPath, workDir string) (*Config, error) {
 		}
 		verbose.ConfigLoaded(configPath, extended)
 	} else {
-		// Try .goupdate.yml in working directory
-		localConfig := filepath.Join(workDir, ".goupdate.yml")
-		if _, err := os.Stat(localConfig); err == nil {
+		// Try local config files in working directory
+		if localConfig := FindLocalConfig(workDir); localConfig != "" {
 			verbose.Infof("Found local config: %s", localConfig)
 			loaded, err := loadConfigFile(localConfig)
 			if err == nil {
@@ -67,6 +77,18 @@ func LoadConfig(configPath, workDir string) (*Config, error) {
 			}
 		}
 
+		if cfg == nil {
+			if configDir := FindConfigDir(workDir); configDir != "" {
+				verbose.Infof("Found config directory: %s", configDir)
+				loaded, err := loadConfigDir(configDir, make(map[string]bool), nil)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load config directory: %w", err)
+				}
+				cfg = loaded
+				cfg.SetRootConfig(true)
+			}
+		}
+
 		if cfg == nil {
 			verbose.Info("Using built-in default configuration")
 			// Use defaults
@@ -84,6 +106,9 @@ func LoadConfig(configPath, workDir string) (*Config, error) {
 	if err := validateGroupMembership(cfg); err != nil {
 		return nil, err
 	}
+	if err := validateRegistryUsage(cfg); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
@@ -99,7 +124,7 @@ func LoadConfig(configPath, workDir string) (*Config, error) {
 //
 // Returns:
 //   - *Config: the loaded configuration
-//   - error: error if file is too large, not found, or has invalid YAML
+//   - error: error if file is too large, not found, or has invalid YAML or TOML
 func loadConfigFileWithLimit(path string, maxSize int64) (*Config, error) {
 	// Check file size before reading to prevent memory exhaustion
 	info, err := os.Stat(path)
@@ -119,6 +144,12 @@ func loadConfigFileWithLimit(path string, maxSize int64) (*Config, error) {
 		return nil, err
 	}
 
+	if IsTOMLConfigPath(path) {
+		if data, err = tomlToYAML(data); err != nil {
+			return nil, err
+		}
+	}
+
 	return loadConfigData(data)
 }
 
@@ -193,11 +224,17 @@ func LoadConfigFileStrict(path string) (*Config, error) {
 	}
 
 	// Validate for unknown fields
-	result := ValidateConfigFile(data)
+	result := ValidateConfigFileAt(path, data)
 	if result.HasErrors() {
 		return nil, fmt.Errorf("%s", result.ErrorMessages())
 	}
 
+	if IsTOMLConfigPath(path) {
+		if data, err = tomlToYAML(data); err != nil {
+			return nil, err
+		}
+	}
+
 	return loadConfigData(data)
 }
 
@@ -332,7 +369,8 @@ func processExtendsWithStackSecure(cfg *Config, baseDir string, stack map[string
 				return nil, fmt.Errorf("failed to resolve extend path '%s': %w", extend, absErr)
 			}
 
-			if _, statErr := os.Stat(absPath); statErr != nil {
+			info, statErr := os.Stat(absPath)
+			if statErr != nil {
 				return nil, fmt.Errorf("failed to resolve extend '%s': %w", extend, statErr)
 			}
 
@@ -344,19 +382,28 @@ func processExtendsWithStackSecure(cfg *Config, baseDir string, stack map[string
 			stack[extendKey] = true
 			cleanupKey = true
 
-			// Load with configurable size limit from root config
-			loaded, err := loadConfigFileWithLimit(extendPath, maxFileSize)
-			if err != nil {
-				return nil, fmt.Errorf("failed to load extend '%s': %w", extend, err)
-			}
+			if info.IsDir() {
+				// A config directory resolves the extends of its own files under the root's security settings
+				loaded, err := loadConfigDir(extendPath, stack, rootCfg)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load extend '%s': %w", extend, err)
+				}
+				extendCfg = loaded
+			} else {
+				// Load with configurable size limit from root config
+				loaded, err := loadConfigFileWithLimit(extendPath, maxFileSize)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load extend '%s': %w", extend, err)
+				}
 
-			// Recursively process extends in the extended config (using root config's security settings)
-			loaded, err = processExtendsWithStackSecure(loaded, filepath.Dir(extendPath), stack, rootCfg)
-			if err != nil {
-				return nil, err
-			}
+				// Recursively process extends in the extended config (using root config's security settings)
+				loaded, err = processExtendsWithStackSecure(loaded, filepath.Dir(extendPath), stack, rootCfg)
+				if err != nil {
+					return nil, err
+				}
 
-			extendCfg = loaded
+				extendCfg = loaded
+			}
 		}
 
 		base = mergeConfigs(base, extendCfg)
@@ -739,3 +739,42 @@ func TestLoadConfigFileStrict(t *testing.T) {
 		assert.Nil(t, cfg)
 	})
 }
+
+// TestLoadConfigRegistryUsage tests rejecting registry overrides the rule cannot use.
+//
+// It verifies:
+//   - A registry on a built-in rule whose commands read $GOUPDATE_REGISTRY loads
+//   - A registry on a rule whose commands ignore it fails with a ValidationError
+//   - A custom rule whose commands read $GOUPDATE_REGISTRY loads
+//   - Disabled rules are not checked
+func TestLoadConfigRegistryUsage(t *testing.T) {
+	load := func(t *testing.T, content string) (*Config, error) {
+		path := filepath.Join(t.TempDir(), ".goupdate.yml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return LoadConfig(path, t.TempDir())
+	}
+
+	cfg, err := load(t, "extends: [default]\nrules:\n  npm:\n    registry: https://npm.example.com/\n")
+	require.NoError(t, err)
+	assert.Equal(t, "https://npm.example.com/", cfg.Rules["npm"].Registry)
+
+	_, err = load(t, "extends: [default]\nrules:\n  composer:\n    registry: https://repo.example.com/\n")
+	var validationErr ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "rules.composer.registry", validationErr.Field)
+	assert.Contains(t, err.Error(), "rule composer does not support registry")
+
+	_, err = load(t, `rules:
+  custom:
+    manager: custom
+    include: ["*.custom"]
+    format: raw
+    registry: https://mirror.example.com
+    outdated:
+      commands: curl -s "$GOUPDATE_REGISTRY/{{package}}"
+`)
+	require.NoError(t, err)
+
+	_, err = load(t, "extends: [default]\nrules:\n  composer:\n    enabled: false\n    registry: https://repo.example.com/\n")
+	require.NoError(t, err)
+}
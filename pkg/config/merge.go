@@ -1,5 +1,7 @@
 package config
 
+import "strings"
+
 // mergeConfigs merges two configurations with custom taking precedence.
 //
 // This performs a deep merge of two Config structures, where custom settings
@@ -23,6 +25,9 @@ func mergeConfigs(base, custom *Config) *Config {
 		Groups:          make(map[string]GroupCfg),
 		Incremental:     base.Incremental,
 		SystemTests:     base.SystemTests,
+		Notify:          base.Notify,
+		Badge:           base.Badge,
+		Severity:        mergeSeverity(base.Severity, custom.Severity),
 	}
 
 	for key, rule := range base.Rules {
@@ -58,6 +63,14 @@ func mergeConfigs(base, custom *Config) *Config {
 		merged.SystemTests = mergeSystemTests(merged.SystemTests, custom.SystemTests)
 	}
 
+	if custom.Notify != nil {
+		merged.Notify = custom.Notify
+	}
+
+	if custom.Badge != nil {
+		merged.Badge = custom.Badge
+	}
+
 	return merged
 }
 
@@ -271,6 +284,48 @@ func mergeRules(base, custom PackageManagerCfg) PackageManagerCfg {
 	if len(custom.PackageOverrides) > 0 {
 		merged.PackageOverrides = custom.PackageOverrides
 	}
+	if len(custom.Pin) > 0 {
+		merged.Pin = custom.Pin
+	}
+	if custom.MaxRequestsPerSecond != nil {
+		merged.MaxRequestsPerSecond = custom.MaxRequestsPerSecond
+	}
+	if custom.IncludeTransitive != nil {
+		merged.IncludeTransitive = custom.IncludeTransitive
+	}
+	if custom.VersionSource != "" {
+		merged.VersionSource = custom.VersionSource
+	}
+	if custom.ConstraintStyle != "" {
+		merged.ConstraintStyle = custom.ConstraintStyle
+	}
+	if custom.SelectionStrategy != "" {
+		merged.SelectionStrategy = custom.SelectionStrategy
+	}
+	if custom.QuarantineDays != nil {
+		merged.QuarantineDays = custom.QuarantineDays
+	}
+	if custom.Workspace != nil {
+		merged.Workspace = custom.Workspace
+	}
+	if custom.WorkspaceFile != nil {
+		merged.WorkspaceFile = custom.WorkspaceFile
+	}
+	if custom.Registry != "" {
+		merged.Registry = custom.Registry
+	}
+	if custom.ChangelogURL != "" {
+		merged.ChangelogURL = custom.ChangelogURL
+	}
+	if len(custom.Paths) > 0 {
+		merged.Paths = custom.Paths
+	}
+	if custom.Tree != nil {
+		merged.Tree = custom.Tree
+	}
+	if custom.UnsupportedReasons != nil {
+		merged.UnsupportedReasons = custom.UnsupportedReasons
+	}
 	if custom.Extraction != nil {
 		merged.Extraction = custom.Extraction
 	}
@@ -289,7 +344,37 @@ func mergeRules(base, custom PackageManagerCfg) PackageManagerCfg {
 	if custom.Incremental != nil {
 		merged.Incremental = mergeStringLists(merged.Incremental, custom.Incremental)
 	}
+	if custom.Severity != nil {
+		merged.Severity = mergeSeverity(merged.Severity, custom.Severity)
+	}
+
+	return merged
+}
 
+// mergeSeverity merges two severity mappings by status, with custom taking precedence.
+//
+// Parameters:
+//   - base: the base mapping; may be nil
+//   - custom: the overriding mapping; may be nil
+//
+// Returns:
+//   - map[string]string: the merged mapping, or nil when both are empty
+func mergeSeverity(base, custom map[string]string) map[string]string {
+	if len(base) == 0 && len(custom) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(custom))
+	for status, severity := range base {
+		merged[status] = severity
+	}
+	for status, severity := range custom {
+		for existing := range merged {
+			if strings.EqualFold(existing, status) {
+				delete(merged, existing)
+			}
+		}
+		merged[status] = severity
+	}
 	return merged
 }
 
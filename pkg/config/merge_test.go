@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 // TestMergeVersionPatterns tests the behavior of mergeVersionPatterns.
@@ -333,6 +335,7 @@ func TestMergeRulesOverride(t *testing.T) {
 		ConstraintMapping: map[string]string{"^": "base"},
 		LatestMapping:     &LatestMappingCfg{Default: map[string]string{"base": "1.0.0"}},
 		PackageOverrides:  map[string]PackageOverrideCfg{"pkg": {Version: "1.0.0"}},
+		Pin:               map[string]string{"pkg": "1.x"},
 		Extraction:        &ExtractionCfg{Pattern: "base"},
 		Outdated:          &OutdatedCfg{Commands: "base {{package}}"},
 		Update:            &UpdateCfg{Commands: "base {{package}}"},
@@ -353,6 +356,7 @@ func TestMergeRulesOverride(t *testing.T) {
 		ConstraintMapping: map[string]string{"~": "override"},
 		LatestMapping:     &LatestMappingCfg{Default: map[string]string{"custom": "2.0.0"}},
 		PackageOverrides:  map[string]PackageOverrideCfg{"pkg": {Version: "2.0.0", Ignore: true}},
+		Pin:               map[string]string{"other": "^2.0.0"},
 		Extraction:        &ExtractionCfg{Pattern: "custom"},
 		Outdated:          &OutdatedCfg{Commands: "custom {{package}}"},
 		Update:            &UpdateCfg{Commands: "custom {{package}}"},
@@ -361,6 +365,15 @@ func TestMergeRulesOverride(t *testing.T) {
 		Incremental:       []string{"pkg-b"},
 	}
 
+	baseRate, customRate := 10.0, 2.5
+	base.MaxRequestsPerSecond = &baseRate
+	custom.MaxRequestsPerSecond = &customRate
+	includeTransitive := true
+	custom.IncludeTransitive = &includeTransitive
+	custom.Registry = "https://npm.example.com"
+	custom.ChangelogURL = "https://github.com/{{package}}"
+	custom.Paths = []string{"frontend"}
+
 	result := mergeRules(base, custom)
 
 	assert.Equal(t, "pnpm", result.Manager)
@@ -376,6 +389,12 @@ func TestMergeRulesOverride(t *testing.T) {
 	assert.Equal(t, map[string]string{"~": "override"}, result.ConstraintMapping)
 	assert.Equal(t, map[string]string{"base": "1.0.0", "custom": "2.0.0"}, result.LatestMapping.Default)
 	assert.Equal(t, map[string]PackageOverrideCfg{"pkg": {Version: "2.0.0", Ignore: true}}, result.PackageOverrides)
+	assert.Equal(t, map[string]string{"other": "^2.0.0"}, result.Pin)
+	assert.Equal(t, 2.5, result.GetMaxRequestsPerSecond())
+	assert.True(t, result.IsIncludeTransitive())
+	assert.Equal(t, "https://npm.example.com", result.Registry)
+	assert.Equal(t, "https://github.com/{{package}}", result.ChangelogURL)
+	assert.Equal(t, []string{"frontend"}, result.Paths)
 	assert.Equal(t, "custom", result.Extraction.Pattern)
 	assert.Equal(t, "custom {{package}}", result.Outdated.Commands)
 	assert.Equal(t, "custom {{package}}", result.Update.Commands)
@@ -646,6 +665,114 @@ func TestMergeRulesEnabled(t *testing.T) {
 	})
 }
 
+// TestMergeRulesIncludeTransitive tests the behavior of merging include_transitive in rules.
+//
+// It verifies:
+//   - Explicit false overrides a base that enables include_transitive
+//   - Nil include_transitive preserves the base value
+func TestMergeRulesIncludeTransitive(t *testing.T) {
+	t.Run("false overrides base true", func(t *testing.T) {
+		enabled, disabled := true, false
+		base := PackageManagerCfg{Manager: "js", IncludeTransitive: &enabled}
+		custom := PackageManagerCfg{IncludeTransitive: &disabled}
+
+		result := mergeRules(base, custom)
+
+		assert.NotNil(t, result.IncludeTransitive)
+		assert.False(t, result.IsIncludeTransitive())
+	})
+
+	t.Run("nil preserves base", func(t *testing.T) {
+		enabled := true
+		base := PackageManagerCfg{Manager: "js", IncludeTransitive: &enabled}
+		custom := PackageManagerCfg{Manager: "pnpm"}
+
+		result := mergeRules(base, custom)
+
+		assert.True(t, result.IsIncludeTransitive())
+	})
+}
+
+// TestMergeRulesWorkspace tests the behavior of merging workspace in rules.
+//
+// It verifies:
+//   - Explicit false overrides a base that enables workspace
+//   - Nil workspace preserves the base value
+func TestMergeRulesWorkspace(t *testing.T) {
+	t.Run("false overrides base true", func(t *testing.T) {
+		enabled, disabled := true, false
+		base := PackageManagerCfg{Manager: "js", Workspace: &enabled}
+		custom := PackageManagerCfg{Workspace: &disabled}
+
+		result := mergeRules(base, custom)
+
+		assert.NotNil(t, result.Workspace)
+		assert.False(t, result.IsWorkspace())
+	})
+
+	t.Run("nil preserves base", func(t *testing.T) {
+		enabled := true
+		base := PackageManagerCfg{Manager: "js", Workspace: &enabled}
+		custom := PackageManagerCfg{Manager: "pnpm"}
+
+		result := mergeRules(base, custom)
+
+		assert.True(t, result.IsWorkspace())
+	})
+}
+
+// TestMergeRulesMaxRequestsPerSecond tests the behavior of merging max_requests_per_second in rules.
+//
+// It verifies:
+//   - A child config setting max_requests_per_second: 0 removes the base throttle
+//   - Unset max_requests_per_second preserves the base throttle
+func TestMergeRulesMaxRequestsPerSecond(t *testing.T) {
+	rate := 5.0
+	base := PackageManagerCfg{Manager: "js", MaxRequestsPerSecond: &rate}
+
+	t.Run("zero overrides base", func(t *testing.T) {
+		var custom PackageManagerCfg
+		require.NoError(t, yaml.Unmarshal([]byte("max_requests_per_second: 0\n"), &custom))
+
+		result := mergeRules(base, custom)
+
+		assert.NotNil(t, result.MaxRequestsPerSecond)
+		assert.Zero(t, result.GetMaxRequestsPerSecond())
+	})
+
+	t.Run("unset preserves base", func(t *testing.T) {
+		result := mergeRules(base, PackageManagerCfg{Manager: "pnpm"})
+
+		assert.Equal(t, 5.0, result.GetMaxRequestsPerSecond())
+	})
+}
+
+// TestMergeRulesQuarantineDays tests the behavior of merging quarantine_days in rules.
+//
+// It verifies:
+//   - A child config setting quarantine_days: 0 turns off the base window
+//   - Unset quarantine_days preserves the base window
+func TestMergeRulesQuarantineDays(t *testing.T) {
+	days := 14
+	base := PackageManagerCfg{Manager: "js", QuarantineDays: &days}
+
+	t.Run("zero overrides base", func(t *testing.T) {
+		var custom PackageManagerCfg
+		require.NoError(t, yaml.Unmarshal([]byte("quarantine_days: 0\n"), &custom))
+
+		result := mergeRules(base, custom)
+
+		assert.NotNil(t, result.QuarantineDays)
+		assert.Zero(t, result.GetQuarantineDays())
+	})
+
+	t.Run("unset preserves base", func(t *testing.T) {
+		result := mergeRules(base, PackageManagerCfg{Manager: "pnpm"})
+
+		assert.Equal(t, 14, result.GetQuarantineDays())
+	})
+}
+
 // TestMergePackageSettings tests the behavior of mergePackageSettings.
 //
 // It verifies:
@@ -10,11 +10,22 @@ type Config struct {
 	Incremental     []string                     `yaml:"incremental,omitempty"`
 	SystemTests     *SystemTestsCfg              `yaml:"system_tests,omitempty"`
 	Security        *SecurityCfg                 `yaml:"security,omitempty"`
+	Notify          *NotifyCfg                   `yaml:"notify,omitempty"`
+	Badge           *BadgeCfg                    `yaml:"badge,omitempty"`
+	Severity        map[string]string            `yaml:"severity,omitempty"`
 
 	// NoTimeout is a runtime flag that disables command timeouts when set to true.
 	// It is not persisted to YAML and is set by CLI flags (--no-timeout).
 	NoTimeout bool `yaml:"-"`
 
+	// AllowPrerelease is a runtime flag that includes pre-release versions as update candidates.
+	// It is not persisted to YAML and is set by CLI flags (--allow-prerelease).
+	AllowPrerelease bool `yaml:"-"`
+
+	// IncludeTransitive is a runtime flag that lets lock commands of every rule update
+	// transitive dependencies. It is not persisted to YAML and is set by CLI flags (--include-transitive).
+	IncludeTransitive bool `yaml:"-"`
+
 	// isRootConfig is set to true only for the root config file (not imported configs).
 	// Security settings can only be enabled from the root config.
 	isRootConfig bool `yaml:"-"`
@@ -140,6 +151,10 @@ const DefaultMaxConfigFileSize = 10 * 1024 * 1024
 // DefaultMaxRegexComplexity is the default maximum regex pattern length.
 const DefaultMaxRegexComplexity = 1000
 
+// RegistryEnvVar is the environment variable that passes a rule's registry
+// override to its outdated and release date commands.
+const RegistryEnvVar = "GOUPDATE_REGISTRY"
+
 // PackageSettings holds per-package configuration options at the package manager level.
 type PackageSettings struct {
 	// WithAllDependencies enables updating with all dependencies (-W flag for composer).
@@ -176,16 +191,74 @@ type PackageManagerCfg struct {
 	ConstraintMapping map[string]string             `yaml:"constraint_mapping,omitempty"`
 	LatestMapping     *LatestMappingCfg             `yaml:"latest_mapping,omitempty"`
 	PackageOverrides  map[string]PackageOverrideCfg `yaml:"package_overrides,omitempty"`
-	Extraction        *ExtractionCfg                `yaml:"extraction,omitempty"`
-	Outdated          *OutdatedCfg                  `yaml:"outdated,omitempty"`
-	Update            *UpdateCfg                    `yaml:"update,omitempty"`
-	LockFiles         []LockFileCfg                 `yaml:"lock_files,omitempty"`
+	// Pin holds a version range per package name (e.g. "2.x", "^1.4", "<3").
+	// Update targets must satisfy the pin, whatever --major/--minor/--patch allow.
+	Pin        map[string]string `yaml:"pin,omitempty"`
+	Extraction *ExtractionCfg    `yaml:"extraction,omitempty"`
+	Outdated   *OutdatedCfg      `yaml:"outdated,omitempty"`
+	Update     *UpdateCfg        `yaml:"update,omitempty"`
+	LockFiles  []LockFileCfg     `yaml:"lock_files,omitempty"`
 	// SelfPinning indicates that the manifest file itself acts as the lock file.
 	// When true, declared versions are used as installed versions (e.g., requirements.txt, Dockerfile).
 	// This avoids "Unsupported" status for package managers without separate lock files.
 	SelfPinning bool                   `yaml:"self_pinning,omitempty"`
 	Metadata    map[string]interface{} `yaml:"metadata,omitempty"`
 	Incremental []string               `yaml:"incremental,omitempty"`
+	// MaxRequestsPerSecond caps the rule's registry requests (version, release date and
+	// digest lookups, update commands) across all workers. Zero means unlimited.
+	MaxRequestsPerSecond *float64 `yaml:"max_requests_per_second,omitempty"`
+	// IncludeTransitive lets the rule's lock commands update transitive dependencies
+	// (with_all_dependencies for every package) and skips the check that no
+	// untargeted package changed during an update. Defaults to false if not specified.
+	IncludeTransitive *bool `yaml:"include_transitive,omitempty"`
+	// Registry overrides the registry base URL used by the rule's version lookups
+	// (e.g. a private mirror). Outdated commands receive it as $GOUPDATE_REGISTRY.
+	Registry string `yaml:"registry,omitempty"`
+	// ChangelogURL is the release notes URL template used by --changelog, with
+	// {{package}}, {{from}} and {{to}} placeholders. GitHub repository URLs are
+	// read through the Releases API; other URLs are fetched as plain text.
+	ChangelogURL string `yaml:"changelog_url,omitempty"`
+	// Paths limits manifest detection to these directories, relative to the working
+	// directory (e.g. "frontend" in a monorepo). Empty means the whole working directory.
+	Paths []string `yaml:"paths,omitempty"`
+	// Tree configures how scan --deep resolves the full transitive dependency
+	// tree of a manifest. Rules without it are reported as unsupported.
+	Tree *TreeCfg `yaml:"tree,omitempty"`
+	// Severity maps update statuses (Failed, ConfigError, SummarizeError) of this
+	// rule's packages to error, warn, or ignore, overriding the global severity.
+	Severity map[string]string `yaml:"severity,omitempty"`
+	// VersionSource reads candidate versions from a local index instead of running
+	// the outdated command (e.g. "file:///mirror/versions.json" or a directory
+	// holding "<rule>.json"), for air-gapped environments.
+	VersionSource string `yaml:"version_source,omitempty"`
+	// ConstraintStyle controls the operator written in front of an updated
+	// declared version: preserve (default) keeps the original operator, while
+	// caret, tilde and exact normalize it using the manager's spelling.
+	ConstraintStyle string `yaml:"constraint_style,omitempty"`
+	// SelectionStrategy picks the update target among the candidate versions
+	// allowed by --major/--minor/--patch and the constraint: highest (default),
+	// lowest-non-vulnerable, or most-recent-by-date.
+	SelectionStrategy string `yaml:"selection_strategy,omitempty"`
+	// QuarantineDays skips versions published less than this many days ago,
+	// so updates only move to settled releases. --quarantine-days overrides it.
+	// Zero or unset disables the quarantine.
+	QuarantineDays *int `yaml:"quarantine_days,omitempty"`
+	// Workspace makes manifests without a lock file next to them use the nearest
+	// lock file in a parent directory (e.g. the root pnpm-lock.yaml of a pnpm
+	// workspace). Lock commands then run once in that directory. Defaults to false
+	// if not specified.
+	Workspace *bool `yaml:"workspace,omitempty"`
+	// WorkspaceFile configures a file listing the modules of a workspace (e.g.
+	// go.work). Listed modules are detected even outside the include patterns,
+	// and its commands sync the workspace once the rule's updates succeed.
+	WorkspaceFile *WorkspaceFileCfg `yaml:"workspace_file,omitempty"`
+	// Override marks a rule in a config directory file as deliberately merged
+	// over the same rule from an earlier file. Without it, a rule declared by
+	// two files is a validation error.
+	Override bool `yaml:"override,omitempty"`
+	// UnsupportedReasons replaces the generic unsupported messages of the rule's
+	// packages with ecosystem-specific ones (e.g. how to pin a Brewfile entry).
+	UnsupportedReasons *UnsupportedReasonsCfg `yaml:"unsupported_reasons,omitempty"`
 }
 
 // IsEnabled returns true if the rule is enabled (defaults to true if not specified).
@@ -202,12 +275,53 @@ func (p *PackageManagerCfg) IsEnabled() bool {
 	return *p.Enabled
 }
 
+// IsIncludeTransitive returns true if the rule lets lock commands update transitive
+// dependencies (defaults to false if not specified).
+//
+// Returns:
+//   - bool: true if include_transitive is set to true, false otherwise
+func (p *PackageManagerCfg) IsIncludeTransitive() bool {
+	return p.IncludeTransitive != nil && *p.IncludeTransitive
+}
+
+// IsWorkspace returns true if the rule's manifests share the nearest lock file in a
+// parent directory (defaults to false if not specified).
+//
+// Returns:
+//   - bool: true if workspace is set to true, false otherwise
+func (p *PackageManagerCfg) IsWorkspace() bool {
+	return p.Workspace != nil && *p.Workspace
+}
+
+// GetMaxRequestsPerSecond returns the rule's request rate cap (defaults to 0 if not specified).
+//
+// Returns:
+//   - float64: max_requests_per_second, or 0 (unlimited) when unset
+func (p *PackageManagerCfg) GetMaxRequestsPerSecond() float64 {
+	if p.MaxRequestsPerSecond == nil {
+		return 0
+	}
+	return *p.MaxRequestsPerSecond
+}
+
+// GetQuarantineDays returns the rule's quarantine window (defaults to 0 if not specified).
+//
+// Returns:
+//   - int: quarantine_days, or 0 when unset
+func (p *PackageManagerCfg) GetQuarantineDays() int {
+	if p.QuarantineDays == nil {
+		return 0
+	}
+	return *p.QuarantineDays
+}
+
 // ShouldUpdateWithAllDependencies returns true if the package should be updated
 // with all its dependencies (e.g., -W flag for composer).
 //
 // Resolution order (first match wins):
-//  1. Individual package settings in rules.<manager>.packages.<package>
-//  2. Group-level with_all_dependencies setting (if package is in a group)
+//  1. Rule-level include_transitive setting
+//  2. Individual package settings in rules.<manager>.packages.<package>
+//  3. Group-level with_all_dependencies setting (if package is in a group)
 //
 // Parameters:
 //   - packageName: the name of the package to check
@@ -215,6 +329,10 @@ func (p *PackageManagerCfg) IsEnabled() bool {
 // Returns:
 //   - bool: true if the package should be updated with all dependencies
 func (p *PackageManagerCfg) ShouldUpdateWithAllDependencies(packageName string) bool {
+	if p.IsIncludeTransitive() {
+		return true
+	}
+
 	// Check individual package settings first (highest priority)
 	if p.Packages != nil {
 		if settings, ok := p.Packages[packageName]; ok {
@@ -292,6 +410,13 @@ type LockFileCfg struct {
 	// Default: 60 seconds.
 	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
 
+	// RefreshCommands regenerates the lock file from the current manifests for
+	// `update --lockfile-only`. It runs once per lock directory with the rule's
+	// update env and timeout, so it must not use {{package}}. Rules without it
+	// are reported as unsupported by --lockfile-only.
+	// Example: "npm install --package-lock-only --ignore-scripts"
+	RefreshCommands string `yaml:"refresh_commands,omitempty"`
+
 	// CommandExtraction configures how to extract versions from command output.
 	// If not specified, the command output is expected to be JSON with
 	// {"package": "version"} or [{"name": "...", "version": "..."}] format.
@@ -377,6 +502,22 @@ type ExtractionCfg struct {
 	DevElement string `yaml:"dev_element,omitempty"`
 	// DevElementValue specifies the element text value that marks a dev dependency (e.g., "all").
 	DevElementValue string `yaml:"dev_element_value,omitempty"`
+	// DevGroups lists Ruby Gemfile group names whose gems are dev dependencies (e.g., "development", "test").
+	// Gems inside "group ... do" blocks or declared with a group:/groups: option are typed "dev"
+	// when every group they belong to is listed here.
+	DevGroups []string `yaml:"dev_groups,omitempty"`
+	// DevScopes lists glob patterns matched against a raw pattern's "scope" group; matches
+	// whose scope fits any of them are dev dependencies (e.g., "test*" for Gradle testImplementation).
+	DevScopes []string `yaml:"dev_scopes,omitempty"`
+	// VersionRefPattern is a raw regex with named groups "ref" and "version" that defines
+	// named versions (e.g., a Gradle catalog [versions] table). Declarations that capture a
+	// "ref" group instead of a version take the referenced version, and updates rewrite the
+	// definition so every package sharing it moves together.
+	VersionRefPattern string `yaml:"version_ref_pattern,omitempty"`
+	// SourcePattern is a raw regex with named groups "n" (or "name") and "source" that
+	// marks packages declared elsewhere in the file as installed from a non-registry
+	// source (e.g., uv's [tool.uv.sources] table redirecting a dependency to git or a path).
+	SourcePattern string `yaml:"source_pattern,omitempty"`
 }
 
 // OutdatedCfg holds configuration for outdated version checking.
@@ -397,7 +538,7 @@ type OutdatedCfg struct {
 	// Versioning configures version parsing and sorting.
 	Versioning *VersioningCfg `yaml:"versioning,omitempty"`
 
-	// ExcludeVersions lists specific versions to exclude.
+	// ExcludeVersions lists versions to exclude, exactly or as globs ("4.0.*").
 	ExcludeVersions []string `yaml:"exclude_versions,omitempty"`
 
 	// ExcludeVersionPatterns lists regex patterns for versions to exclude.
@@ -405,6 +546,176 @@ type OutdatedCfg struct {
 
 	// TimeoutSeconds sets command execution timeout.
 	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+
+	// Retries is the number of additional attempts made when a version lookup
+	// fails with a transient error (network failure, registry timeout, 5xx).
+	// Zero disables retries.
+	Retries int `yaml:"retries,omitempty"`
+
+	// RetryBackoffMs is the base delay in milliseconds before the first retry.
+	// The delay doubles on each subsequent attempt and includes random jitter.
+	RetryBackoffMs int `yaml:"retry_backoff,omitempty"`
+
+	// ReleaseDates configures an optional lookup of version publish timestamps.
+	// When set, the outdated command reports the age of available releases and
+	// supports hiding releases younger than --min-age days.
+	ReleaseDates *ReleaseDatesCfg `yaml:"release_dates,omitempty"`
+
+	// Yanked configures an optional lookup of versions the registry marks
+	// yanked, deprecated, or retracted. Those versions are never offered as
+	// update targets unless --allow-yanked is set.
+	Yanked *YankedCfg `yaml:"yanked,omitempty"`
+
+	// Repository configures an optional lookup of the package's source
+	// repository URL, reported as "repository" in structured output.
+	Repository *RepositoryCfg `yaml:"repository,omitempty"`
+}
+
+// ReleaseDatesCfg configures how to look up publish timestamps for versions.
+type ReleaseDatesCfg struct {
+	// Commands is a multiline string supporting piped (|) and sequential (newline) execution.
+	// The same placeholders as the outdated commands are available.
+	Commands string `yaml:"commands,omitempty"`
+
+	// Format specifies the output format: json or raw.
+	Format string `yaml:"format,omitempty"`
+
+	// JSONKey is a dot-separated path to an object mapping versions to timestamps.
+	// Empty uses the top-level object.
+	JSONKey string `yaml:"json_key,omitempty"`
+
+	// Pattern is a regex with named groups "version" and "published" for raw format extraction.
+	Pattern string `yaml:"pattern,omitempty"`
+
+	// TimeoutSeconds sets command execution timeout.
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+}
+
+// YankedCfg configures how to look up yanked, deprecated, or retracted versions.
+type YankedCfg struct {
+	// Commands is a multiline string supporting piped (|) and sequential (newline) execution.
+	// The same placeholders as the outdated commands are available.
+	Commands string `yaml:"commands,omitempty"`
+
+	// Format specifies the output format: json, raw, or go-mod.
+	Format string `yaml:"format,omitempty"`
+
+	// JSONKey is a dot-separated path to the yanked versions in JSON output.
+	// Empty uses the top-level value.
+	JSONKey string `yaml:"json_key,omitempty"`
+
+	// ReasonKey names the field holding the yank reason when JSON output is an
+	// array of version objects (or a single one) with a "version" field, as
+	// printed by `npm view <pkg>@'*' name version deprecated --json`.
+	ReasonKey string `yaml:"reason_key,omitempty"`
+
+	// Pattern is a regex with named group "version", and optionally "reason", for raw format extraction.
+	Pattern string `yaml:"pattern,omitempty"`
+
+	// TimeoutSeconds sets command execution timeout.
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+}
+
+// RepositoryCfg configures how to look up the source repository URL of a package.
+type RepositoryCfg struct {
+	// Commands is a multiline string supporting piped (|) and sequential (newline) execution.
+	// The same placeholders as the outdated commands are available.
+	Commands string `yaml:"commands,omitempty"`
+
+	// Format specifies the output format: json or raw.
+	Format string `yaml:"format,omitempty"`
+
+	// JSONKeys are dot-separated paths tried in order; the first one holding a
+	// URL, or an object with a "url" field, wins. Empty uses the top-level value.
+	JSONKeys []string `yaml:"json_keys,omitempty"`
+
+	// Pattern is a regex with named group "url" for raw format extraction.
+	Pattern string `yaml:"pattern,omitempty"`
+
+	// TimeoutSeconds sets command execution timeout.
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+}
+
+// Yanked version output formats accepted by YankedCfg.Format.
+const (
+	YankedFormatJSON  = "json"
+	YankedFormatRaw   = "raw"
+	YankedFormatGoMod = "go-mod"
+)
+
+// Constraint styles accepted by PackageManagerCfg.ConstraintStyle.
+const (
+	ConstraintStylePreserve = "preserve"
+	ConstraintStyleCaret    = "caret"
+	ConstraintStyleTilde    = "tilde"
+	ConstraintStyleExact    = "exact"
+)
+
+// Selection strategies accepted by PackageManagerCfg.SelectionStrategy.
+const (
+	SelectionStrategyHighest             = "highest"
+	SelectionStrategyLowestNonVulnerable = "lowest-non-vulnerable"
+	SelectionStrategyMostRecentByDate    = "most-recent-by-date"
+)
+
+// SelectionStrategyFor returns the selection strategy of a package's rule.
+//
+// Parameters:
+//   - p: the package reference containing rule information
+//   - cfg: the configuration containing rule settings
+//
+// Returns:
+//   - string: The rule's selection_strategy; SelectionStrategyHighest when unset
+func SelectionStrategyFor(p PackageRef, cfg *Config) string {
+	if cfg != nil {
+		if strategy := cfg.Rules[p.GetRule()].SelectionStrategy; strategy != "" {
+			return strategy
+		}
+	}
+	return SelectionStrategyHighest
+}
+
+// QuarantineDaysFor returns the quarantine window of a package's rule.
+//
+// Parameters:
+//   - p: The package reference
+//   - cfg: The global configuration; may be nil
+//
+// Returns:
+//   - int: The rule's quarantine_days; 0 when unset
+func QuarantineDaysFor(p PackageRef, cfg *Config) int {
+	if cfg == nil {
+		return 0
+	}
+	rule := cfg.Rules[p.GetRule()]
+	return rule.GetQuarantineDays()
+}
+
+// constraintStyleOperators holds the operator each manager writes for a
+// normalizing constraint style. Managers without an entry, and styles missing
+// from a manager's entry, only support preserve.
+var constraintStyleOperators = map[string]map[string]string{
+	"js":     {ConstraintStyleCaret: "^", ConstraintStyleTilde: "~", ConstraintStyleExact: ""},
+	"php":    {ConstraintStyleCaret: "^", ConstraintStyleTilde: "~", ConstraintStyleExact: ""},
+	"deno":   {ConstraintStyleCaret: "^", ConstraintStyleTilde: "~", ConstraintStyleExact: ""},
+	"dart":   {ConstraintStyleCaret: "^", ConstraintStyleExact: ""},
+	"rust":   {ConstraintStyleCaret: "^", ConstraintStyleTilde: "~", ConstraintStyleExact: "="},
+	"python": {ConstraintStyleTilde: "~=", ConstraintStyleExact: "=="},
+	"ruby":   {ConstraintStyleTilde: "~>", ConstraintStyleExact: ""},
+}
+
+// ConstraintStyleOperator returns the operator a manager writes for a constraint style.
+//
+// Parameters:
+//   - manager: The rule's manager (e.g. "js", "python")
+//   - style: The constraint style; empty means preserve
+//
+// Returns:
+//   - string: The operator in the manager's spelling (e.g. "~=" for tilde in python)
+//   - bool: false for preserve and for styles the manager has no operator for
+func ConstraintStyleOperator(manager, style string) (string, bool) {
+	operator, ok := constraintStyleOperators[manager][style]
+	return operator, ok
 }
 
 // OutdatedExtractionCfg configures how to extract versions from command output.
@@ -433,7 +744,7 @@ type OutdatedOverrideCfg struct {
 	// Extraction overrides the extraction configuration.
 	Extraction *OutdatedExtractionCfg `yaml:"extraction,omitempty"`
 
-	// ExcludeVersions lists specific versions to exclude.
+	// ExcludeVersions lists versions to exclude, exactly or as globs ("4.0.*").
 	ExcludeVersions []string `yaml:"exclude_versions,omitempty"`
 
 	// ExcludeVersionPatterns lists regex patterns for versions to exclude.
@@ -444,6 +755,12 @@ type OutdatedOverrideCfg struct {
 
 	// TimeoutSeconds overrides the timeout.
 	TimeoutSeconds *int `yaml:"timeout_seconds,omitempty"`
+
+	// Retries overrides the number of retries for transient lookup failures.
+	Retries *int `yaml:"retries,omitempty"`
+
+	// RetryBackoffMs overrides the base retry delay in milliseconds.
+	RetryBackoffMs *int `yaml:"retry_backoff,omitempty"`
 }
 
 // UpdateCfg holds configuration for update commands.
@@ -462,6 +779,121 @@ type UpdateCfg struct {
 
 	// TimeoutSeconds sets command execution timeout.
 	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+
+	// AllowPrerelease includes pre-release versions (e.g., 2.0.0-beta.1) as update candidates.
+	// Stable releases are still preferred when both are available for the same update level.
+	AllowPrerelease bool `yaml:"allow_prerelease,omitempty"`
+
+	// ResolveDigest looks up the commit digest of the target version for declarations
+	// pinned by digest (raw patterns with a "digest" group). Without it, digest-pinned
+	// declarations cannot be updated.
+	ResolveDigest *ResolveDigestCfg `yaml:"resolve_digest,omitempty"`
+
+	// GroupCommands runs the lock command once for the whole rule: the manifest edits
+	// of all update groups are applied first, and a failure rolls back every group of the rule.
+	GroupCommands bool `yaml:"group_commands,omitempty"`
+
+	// Dedupe configures the command run once after the rule's updates succeed when
+	// update --dedupe is set, e.g. npm dedupe to fold duplicated transitive packages.
+	Dedupe *DedupeCfg `yaml:"dedupe,omitempty"`
+}
+
+// DedupeCfg configures the dedupe step run after a rule's updates.
+type DedupeCfg struct {
+	// Commands is a multiline string supporting piped (|) and sequential (newline) execution.
+	// It runs once in every lock file directory of the updated packages and takes no placeholders.
+	Commands string `yaml:"commands,omitempty"`
+
+	// Env holds environment variables to set when executing commands.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// TimeoutSeconds sets command execution timeout.
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+
+	// Critical rolls back the rule's updates when the dedupe command fails.
+	// By default a failure is only reported as a warning.
+	Critical bool `yaml:"critical,omitempty"`
+}
+
+// ResolveDigestCfg configures the digest lookup for digest-pinned declarations.
+type ResolveDigestCfg struct {
+	// Commands is a multiline string supporting piped (|) and sequential (newline) execution.
+	// {{package}} is the package name and {{version}} the target version.
+	Commands string `yaml:"commands,omitempty"`
+
+	// Pattern is a regex with a named group "digest" matched against the command output.
+	Pattern string `yaml:"pattern,omitempty"`
+
+	// TimeoutSeconds sets command execution timeout.
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+}
+
+// TreeCfg configures the dependency tree lookup used by scan --deep.
+type TreeCfg struct {
+	// Commands is a multiline string supporting piped (|) and sequential (newline) execution.
+	// It runs once per manifest, in the manifest's directory, and takes no placeholders.
+	Commands string `yaml:"commands,omitempty"`
+
+	// Env sets environment variables for commands.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Format names the command output parser: npm-ls, go-mod-graph or composer-tree.
+	Format string `yaml:"format,omitempty"`
+
+	// TimeoutSeconds sets command execution timeout.
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+}
+
+// Dependency tree output formats accepted by TreeCfg.Format.
+const (
+	TreeFormatNPMLs        = "npm-ls"
+	TreeFormatGoModGraph   = "go-mod-graph"
+	TreeFormatComposerTree = "composer-tree"
+)
+
+// WorkspaceFileCfg configures a workspace file such as go.work.
+type WorkspaceFileCfg struct {
+	// File is the workspace file name, looked up in the working directory and,
+	// for updated modules, in their parent directories.
+	File string `yaml:"file,omitempty"`
+
+	// Format names the workspace file parser: go-work.
+	Format string `yaml:"format,omitempty"`
+
+	// Commands is a multiline string supporting piped (|) and sequential (newline) execution.
+	// It runs once in the workspace root after the rule's updates succeed, e.g.
+	// go work sync, and takes no placeholders. Empty skips the sync.
+	Commands string `yaml:"commands,omitempty"`
+
+	// Env sets environment variables for commands.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// TimeoutSeconds sets command execution timeout.
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+}
+
+// Workspace file formats accepted by WorkspaceFileCfg.Format.
+const (
+	WorkspaceFormatGoWork = "go-work"
+)
+
+// UnsupportedReasonsCfg holds the messages reported for a rule's packages that
+// cannot be updated. Empty messages fall back to the generic ones.
+type UnsupportedReasonsCfg struct {
+	// Unpinned is reported for packages declared without a version ("*"),
+	// e.g. Brewfile formulae or GitHub Actions pinned to a bare commit SHA.
+	Unpinned string `yaml:"unpinned,omitempty"`
+
+	// LookupFailed is reported when the version lookup fails, e.g. because
+	// the registry API is rate limited.
+	LookupFailed string `yaml:"lookup_failed,omitempty"`
+
+	// NonRegistry is reported for packages installed from a non-registry source.
+	NonRegistry string `yaml:"non_registry,omitempty"`
+
+	// Sources holds non-registry messages per source (e.g. "branch" or "sdk"),
+	// taking precedence over NonRegistry.
+	Sources map[string]string `yaml:"sources,omitempty"`
 }
 
 // UpdateOverrideCfg holds per-package update override configuration.
@@ -477,6 +909,9 @@ type UpdateOverrideCfg struct {
 
 	// TimeoutSeconds overrides the timeout.
 	TimeoutSeconds *int `yaml:"timeout_seconds,omitempty"`
+
+	// AllowPrerelease overrides whether pre-release versions are update candidates.
+	AllowPrerelease *bool `yaml:"allow_prerelease,omitempty"`
 }
 
 // VersioningCfg holds configuration for version parsing and sorting.
@@ -484,6 +919,9 @@ type VersioningCfg struct {
 	Format string `yaml:"format,omitempty"`
 	Regex  string `yaml:"regex,omitempty"`
 	Sort   string `yaml:"sort,omitempty"`
+	// MatchPrecision keeps only candidates with as many version segments as the
+	// current version, so a floating "v4" moves to "v5" rather than "v5.0.0".
+	MatchPrecision bool `yaml:"match_precision,omitempty"`
 }
 
 // SystemTestCfg defines a single system test configuration.
@@ -578,3 +1016,66 @@ const (
 	SystemTestRunModeAfterAll  = "after_all"
 	SystemTestRunModeNone      = "none"
 )
+
+// BadgeCfg configures the shields.io badge written by outdated --output shields.
+type BadgeCfg struct {
+	// Label is the left-hand text of the badge.
+	// Default: "outdated"
+	Label string `yaml:"label,omitempty"`
+
+	// Colors maps outdated counts to badge colors. The color of the highest
+	// min that the count reaches is used.
+	// Default: 0 brightgreen, 1 yellow, 10 orange, 25 red
+	Colors []BadgeColorCfg `yaml:"colors,omitempty"`
+}
+
+// BadgeColorCfg is one color threshold of the badge.
+type BadgeColorCfg struct {
+	// Min is the smallest outdated count the color applies to.
+	Min int `yaml:"min"`
+
+	// Color is a shields.io color name or hex value (e.g. "orange", "e05d44").
+	Color string `yaml:"color"`
+}
+
+// NotifyCfg configures the notification sent when the update command finishes.
+type NotifyCfg struct {
+	// URL is the webhook endpoint that receives the summary as an HTTP POST.
+	// Environment variables are expanded (e.g. "${SLACK_WEBHOOK_URL}") so
+	// tokens embedded in the URL can stay out of the config file.
+	URL string `yaml:"url"`
+
+	// Format selects the payload shape:
+	// - "json": The run summary as a JSON object (default)
+	// - "slack": A Slack-compatible message with a "text" field
+	Format string `yaml:"format,omitempty"`
+
+	// Headers holds extra HTTP headers, such as Authorization.
+	// Values support environment variable expansion.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// TimeoutSeconds limits how long delivery may take.
+	// Default: 10
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+
+	// OnDryRun also sends a notification for --dry-run runs.
+	// Default: false
+	OnDryRun bool `yaml:"on_dry_run,omitempty"`
+}
+
+// GetFormat returns the payload format (defaults to "json").
+//
+// Returns:
+//   - string: the payload format (default: "json")
+func (n *NotifyCfg) GetFormat() string {
+	if n.Format == "" {
+		return NotifyFormatJSON
+	}
+	return n.Format
+}
+
+// Notification payload format constants.
+const (
+	NotifyFormatJSON  = "json"
+	NotifyFormatSlack = "slack"
+)
@@ -228,3 +228,38 @@ func TestSystemTestsCfgDefaults(t *testing.T) {
 		assert.Equal(t, "after_each", cfg.GetRunMode())
 	})
 }
+
+// TestSelectionStrategyFor tests resolving a package's selection strategy.
+//
+// It verifies:
+//   - The rule's selection_strategy is returned
+//   - Unset strategies, unknown rules and a nil config default to highest
+func TestSelectionStrategyFor(t *testing.T) {
+	cfg := &Config{Rules: map[string]PackageManagerCfg{
+		"npm":  {SelectionStrategy: SelectionStrategyMostRecentByDate},
+		"yarn": {},
+	}}
+
+	assert.Equal(t, SelectionStrategyMostRecentByDate, SelectionStrategyFor(prereleaseRef{name: "react", rule: "npm"}, cfg))
+	assert.Equal(t, SelectionStrategyHighest, SelectionStrategyFor(prereleaseRef{name: "react", rule: "yarn"}, cfg))
+	assert.Equal(t, SelectionStrategyHighest, SelectionStrategyFor(prereleaseRef{name: "react", rule: "pnpm"}, cfg))
+	assert.Equal(t, SelectionStrategyHighest, SelectionStrategyFor(prereleaseRef{name: "react", rule: "npm"}, nil))
+}
+
+// TestQuarantineDaysFor tests resolving a package's quarantine window.
+//
+// It verifies:
+//   - The rule's quarantine_days is returned
+//   - Unset windows, unknown rules and a nil config disable the quarantine
+func TestQuarantineDaysFor(t *testing.T) {
+	days := 14
+	cfg := &Config{Rules: map[string]PackageManagerCfg{
+		"npm":  {QuarantineDays: &days},
+		"yarn": {},
+	}}
+
+	assert.Equal(t, 14, QuarantineDaysFor(prereleaseRef{name: "react", rule: "npm"}, cfg))
+	assert.Zero(t, QuarantineDaysFor(prereleaseRef{name: "react", rule: "yarn"}, cfg))
+	assert.Zero(t, QuarantineDaysFor(prereleaseRef{name: "react", rule: "pnpm"}, cfg))
+	assert.Zero(t, QuarantineDaysFor(prereleaseRef{name: "react", rule: "npm"}, nil))
+}
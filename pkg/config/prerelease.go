@@ -0,0 +1,35 @@
+package config
+
+// ShouldAllowPrerelease reports whether pre-release versions may be selected as update targets for a package.
+//
+// Pre-releases are allowed when the runtime AllowPrerelease flag is set
+// (--allow-prerelease), or when the package's rule enables
+// update.allow_prerelease. A package override's update.allow_prerelease
+// takes precedence over the rule setting.
+//
+// Parameters:
+//   - p: the package reference containing name and rule information
+//   - cfg: the configuration containing rule settings and runtime flags
+//
+// Returns:
+//   - bool: true if pre-release versions should be included as candidates
+func ShouldAllowPrerelease(p PackageRef, cfg *Config) bool {
+	if cfg == nil {
+		return false
+	}
+
+	if cfg.AllowPrerelease {
+		return true
+	}
+
+	rule, ok := cfg.Rules[p.GetRule()]
+	if !ok {
+		return false
+	}
+
+	if override, ok := rule.PackageOverrides[p.GetName()]; ok && override.Update != nil && override.Update.AllowPrerelease != nil {
+		return *override.Update.AllowPrerelease
+	}
+
+	return rule.Update != nil && rule.Update.AllowPrerelease
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// prereleaseRef is a minimal PackageRef for ShouldAllowPrerelease tests.
+type prereleaseRef struct {
+	name string
+	rule string
+}
+
+func (r prereleaseRef) GetName() string { return r.name }
+func (r prereleaseRef) GetRule() string { return r.rule }
+
+// TestShouldAllowPrerelease tests the behavior of ShouldAllowPrerelease.
+//
+// It verifies:
+//   - Nil config and unknown rules disallow pre-releases
+//   - The runtime AllowPrerelease flag allows pre-releases for every package
+//   - The rule's update.allow_prerelease setting is honored
+//   - A package override takes precedence over the rule setting
+func TestShouldAllowPrerelease(t *testing.T) {
+	allow := true
+	deny := false
+	cfg := &Config{
+		Rules: map[string]PackageManagerCfg{
+			"npm": {
+				Update: &UpdateCfg{AllowPrerelease: true},
+				PackageOverrides: map[string]PackageOverrideCfg{
+					"react": {Update: &UpdateOverrideCfg{AllowPrerelease: &deny}},
+				},
+			},
+			"mod": {
+				Update: &UpdateCfg{},
+				PackageOverrides: map[string]PackageOverrideCfg{
+					"golang.org/x/net": {Update: &UpdateOverrideCfg{AllowPrerelease: &allow}},
+				},
+			},
+		},
+	}
+
+	assert.False(t, ShouldAllowPrerelease(prereleaseRef{name: "react", rule: "npm"}, nil))
+	assert.False(t, ShouldAllowPrerelease(prereleaseRef{name: "flask", rule: "pip"}, cfg))
+	assert.True(t, ShouldAllowPrerelease(prereleaseRef{name: "lodash", rule: "npm"}, cfg))
+	assert.False(t, ShouldAllowPrerelease(prereleaseRef{name: "react", rule: "npm"}, cfg))
+	assert.True(t, ShouldAllowPrerelease(prereleaseRef{name: "golang.org/x/net", rule: "mod"}, cfg))
+	assert.False(t, ShouldAllowPrerelease(prereleaseRef{name: "github.com/spf13/cobra", rule: "mod"}, cfg))
+
+	cfg.AllowPrerelease = true
+	assert.True(t, ShouldAllowPrerelease(prereleaseRef{name: "react", rule: "npm"}, cfg))
+	assert.True(t, ShouldAllowPrerelease(prereleaseRef{name: "flask", rule: "pip"}, cfg))
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+)
+
+// Severity levels a status can be mapped to with the severity config.
+const (
+	// SeverityError counts the package's failure towards the exit code (the default).
+	SeverityError = "error"
+	// SeverityWarn reports the failure but keeps it out of the exit code.
+	SeverityWarn = "warn"
+	// SeverityIgnore drops the failure from the exit code and the failure count.
+	SeverityIgnore = "ignore"
+)
+
+// SeverityStatuses lists the update statuses a severity can be mapped for.
+//
+// These are the statuses that carry an error; every one defaults to SeverityError.
+var SeverityStatuses = []string{constants.StatusFailed, constants.StatusConfigError, constants.StatusSummarizeError}
+
+// SeverityEntry is one line of the effective severity mapping.
+//
+// Fields:
+//   - Rule: The rule the entry applies to; empty for the global mapping
+//   - Status: The update status, e.g. "Failed"
+//   - Severity: SeverityError, SeverityWarn, or SeverityIgnore
+type SeverityEntry struct {
+	Rule     string `json:"rule,omitempty" xml:"rule,attr,omitempty"`
+	Status   string `json:"status" xml:"status,attr"`
+	Severity string `json:"severity" xml:"severity,attr"`
+}
+
+// String renders the entry as "Status=severity" or "rule:Status=severity".
+//
+// Returns:
+//   - string: The entry in the form used by the run summary
+func (e SeverityEntry) String() string {
+	if e.Rule == "" {
+		return fmt.Sprintf("%s=%s", e.Status, e.Severity)
+	}
+	return fmt.Sprintf("%s:%s=%s", e.Rule, e.Status, e.Severity)
+}
+
+// StatusSeverity returns the severity of an update status for a rule.
+//
+// A rule's own severity mapping takes precedence over the global one; statuses
+// mapped by neither are SeverityError, which is the behavior without a mapping.
+//
+// Parameters:
+//   - rule: The rule of the package
+//   - status: The package's update status
+//
+// Returns:
+//   - string: SeverityError, SeverityWarn, or SeverityIgnore
+func (c *Config) StatusSeverity(rule, status string) string {
+	if c == nil {
+		return SeverityError
+	}
+	if severity, ok := lookupSeverity(c.Rules[rule].Severity, status); ok {
+		return severity
+	}
+	if severity, ok := lookupSeverity(c.Severity, status); ok {
+		return severity
+	}
+	return SeverityError
+}
+
+// EffectiveSeverities lists the severity mapping in force for the run.
+//
+// It performs the following operations:
+//   - Step 1: List every status of SeverityStatuses with its global severity
+//   - Step 2: Append each rule override that differs from the global severity, sorted by rule
+//
+// Returns:
+//   - []SeverityEntry: Global entries first, then rule overrides
+func (c *Config) EffectiveSeverities() []SeverityEntry {
+	var entries []SeverityEntry
+	for _, status := range SeverityStatuses {
+		entries = append(entries, SeverityEntry{Status: status, Severity: c.StatusSeverity("", status)})
+	}
+	if c == nil {
+		return entries
+	}
+
+	rules := make([]string, 0, len(c.Rules))
+	for rule := range c.Rules {
+		if len(c.Rules[rule].Severity) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Strings(rules)
+	for _, rule := range rules {
+		for _, status := range SeverityStatuses {
+			severity := c.StatusSeverity(rule, status)
+			if severity != c.StatusSeverity("", status) {
+				entries = append(entries, SeverityEntry{Rule: rule, Status: status, Severity: severity})
+			}
+		}
+	}
+	return entries
+}
+
+// HasSeverityOverrides reports whether any status is mapped to something other than SeverityError.
+//
+// Returns:
+//   - bool: true when the severity config changes the default behavior
+func (c *Config) HasSeverityOverrides() bool {
+	for _, entry := range c.EffectiveSeverities() {
+		if entry.Severity != SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupSeverity finds the severity of a status in a mapping, ignoring case.
+//
+// Parameters:
+//   - mapping: Status to severity mapping; may be nil
+//   - status: The status to look up
+//
+// Returns:
+//   - string: The normalized severity
+//   - bool: false when the status is not mapped
+func lookupSeverity(mapping map[string]string, status string) (string, bool) {
+	for key, severity := range mapping {
+		if strings.EqualFold(key, status) {
+			return strings.ToLower(strings.TrimSpace(severity)), true
+		}
+	}
+	return "", false
+}
+
+// validateSeverity checks a severity mapping.
+//
+// Parameters:
+//   - field: the field path for error messages
+//   - mapping: the status to severity mapping
+//   - result: validation result to append errors to
+func validateSeverity(field string, mapping map[string]string, result *ValidationResult) {
+	keys := make([]string, 0, len(mapping))
+	for status := range mapping {
+		keys = append(keys, status)
+	}
+	sort.Strings(keys)
+
+	for _, status := range keys {
+		known := false
+		for _, s := range SeverityStatuses {
+			known = known || strings.EqualFold(s, status)
+		}
+		if !known {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:      fmt.Sprintf("%s.%s", field, status),
+				Message:    fmt.Sprintf("unknown status '%s'", status),
+				Expected:   strings.Join(SeverityStatuses, ", "),
+				DocSection: "severity",
+			})
+		}
+
+		switch strings.ToLower(strings.TrimSpace(mapping[status])) {
+		case SeverityError, SeverityWarn, SeverityIgnore:
+		default:
+			result.Errors = append(result.Errors, ValidationError{
+				Field:      fmt.Sprintf("%s.%s", field, status),
+				Message:    fmt.Sprintf("invalid severity '%s'", mapping[status]),
+				Expected:   "error, warn, or ignore",
+				DocSection: "severity",
+			})
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+)
+
+// TestStatusSeverity tests resolving the severity of an update status.
+//
+// It verifies:
+//   - Statuses mapped by no config are errors, including for a nil config
+//   - A rule's mapping takes precedence over the global one
+//   - Status names and severities are matched ignoring case
+func TestStatusSeverity(t *testing.T) {
+	var nilCfg *Config
+	assert.Equal(t, SeverityError, nilCfg.StatusSeverity("npm", constants.StatusFailed))
+	assert.Equal(t, SeverityError, (&Config{}).StatusSeverity("npm", constants.StatusFailed))
+
+	cfg := &Config{
+		Severity: map[string]string{"configerror": "Ignore"},
+		Rules: map[string]PackageManagerCfg{
+			"npm": {Severity: map[string]string{constants.StatusFailed: SeverityWarn, constants.StatusConfigError: SeverityError}},
+		},
+	}
+	assert.Equal(t, SeverityWarn, cfg.StatusSeverity("npm", constants.StatusFailed))
+	assert.Equal(t, SeverityError, cfg.StatusSeverity("pip", constants.StatusFailed))
+	assert.Equal(t, SeverityError, cfg.StatusSeverity("npm", constants.StatusConfigError))
+	assert.Equal(t, SeverityIgnore, cfg.StatusSeverity("pip", constants.StatusConfigError))
+}
+
+// TestEffectiveSeverities tests listing the severity mapping in force.
+//
+// It verifies:
+//   - The default mapping lists every status as an error and has no overrides
+//   - Rule overrides are listed after the global entries only when they differ
+func TestEffectiveSeverities(t *testing.T) {
+	var nilCfg *Config
+	assert.Equal(t, []SeverityEntry{
+		{Status: constants.StatusFailed, Severity: SeverityError},
+		{Status: constants.StatusConfigError, Severity: SeverityError},
+		{Status: constants.StatusSummarizeError, Severity: SeverityError},
+	}, nilCfg.EffectiveSeverities())
+	assert.False(t, nilCfg.HasSeverityOverrides())
+
+	cfg := &Config{
+		Severity: map[string]string{constants.StatusSummarizeError: SeverityWarn},
+		Rules: map[string]PackageManagerCfg{
+			"npm": {Severity: map[string]string{constants.StatusFailed: SeverityWarn}},
+			"pip": {Severity: map[string]string{constants.StatusFailed: SeverityError}},
+		},
+	}
+	var lines []string
+	for _, entry := range cfg.EffectiveSeverities() {
+		lines = append(lines, entry.String())
+	}
+	assert.Equal(t, []string{"Failed=error", "ConfigError=error", "SummarizeError=warn", "npm:Failed=warn"}, lines)
+	assert.True(t, cfg.HasSeverityOverrides())
+}
+
+// TestValidateSeverity tests validating severity mappings.
+//
+// It verifies:
+//   - Known statuses mapped to error, warn, or ignore are accepted globally and per rule
+//   - Unknown statuses and severities are reported with their field path
+func TestValidateSeverity(t *testing.T) {
+	valid := `
+severity:
+  Failed: warn
+rules:
+  npm:
+    manager: js
+    severity:
+      configerror: IGNORE
+`
+	assert.False(t, ValidateConfigFile([]byte(valid)).HasErrors())
+
+	invalid := `
+severity:
+  Skipped: warn
+rules:
+  npm:
+    manager: js
+    severity:
+      Failed: fatal
+`
+	result := ValidateConfigFile([]byte(invalid))
+	var fields []string
+	for _, err := range result.Errors {
+		fields = append(fields, err.Field)
+		assert.Equal(t, "severity", err.DocSection)
+	}
+	assert.ElementsMatch(t, []string{"severity.Skipped", "rules.npm.severity.Failed"}, fields)
+}
+
+// TestMergeSeverity tests merging severity mappings of a base and a custom config.
+//
+// It verifies:
+//   - Custom entries override base entries of the same status, ignoring case
+//   - Base entries not overridden are kept
+//   - Two empty mappings merge to nil
+func TestMergeSeverity(t *testing.T) {
+	merged := mergeSeverity(
+		map[string]string{"failed": SeverityWarn, constants.StatusConfigError: SeverityIgnore},
+		map[string]string{constants.StatusFailed: SeverityError},
+	)
+	assert.Equal(t, map[string]string{constants.StatusFailed: SeverityError, constants.StatusConfigError: SeverityIgnore}, merged)
+	assert.Nil(t, mergeSeverity(nil, map[string]string{}))
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+	"gopkg.in/yaml.v3"
+)
+
+// LocalConfigNames lists the config files auto-detected in the working directory.
+//
+// When several exist, the first one in this order wins:
+// .goupdate.yml, then .goupdate.toml, then goupdate.toml.
+var LocalConfigNames = []string{".goupdate.yml", ".goupdate.toml", "goupdate.toml"}
+
+// yamlLinePattern matches the line reference in YAML type error messages.
+var yamlLinePattern = regexp.MustCompile(`line \d+: `)
+
+// unknownFieldLinePattern matches the line suffix of unknown-field validation messages.
+var unknownFieldLinePattern = regexp.MustCompile(` \(line \d+\)`)
+
+// FindLocalConfig returns the config file to auto-detect in a directory.
+//
+// Candidates are checked in LocalConfigNames order. When more than one
+// exists, the first is used and the others are reported as ignored in
+// verbose output.
+//
+// Parameters:
+//   - workDir: directory to search
+//
+// Returns:
+//   - string: path of the config file to use, or empty if none exists
+func FindLocalConfig(workDir string) string {
+	var found []string
+	for _, name := range LocalConfigNames {
+		path := filepath.Join(workDir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			found = append(found, path)
+		}
+	}
+
+	if len(found) == 0 {
+		return ""
+	}
+	if len(found) > 1 {
+		verbose.Infof("Multiple config files found; using %s and ignoring %s", found[0], strings.Join(found[1:], ", "))
+	}
+	return found[0]
+}
+
+// IsTOMLConfigPath reports whether a config path should be decoded as TOML.
+//
+// Parameters:
+//   - path: config file path
+//
+// Returns:
+//   - bool: true if the file has a .toml extension
+func IsTOMLConfigPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// tomlToYAML converts TOML configuration data to YAML.
+//
+// TOML configs are decoded into generic values and re-encoded as YAML so
+// they are loaded and validated through the same yaml struct tags and
+// custom unmarshalers as YAML configs.
+//
+// Parameters:
+//   - data: TOML configuration data as bytes
+//
+// Returns:
+//   - []byte: equivalent YAML configuration data
+//   - error: ValidationError with the line number if the TOML is malformed
+func tomlToYAML(data []byte) ([]byte, error) {
+	var doc map[string]interface{}
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, ValidationError{
+			Message:    fmt.Sprintf("TOML syntax error: %s", err),
+			DocSection: "configuration",
+		}
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert TOML config: %w", err)
+	}
+	return out, nil
+}
+
+// ValidateConfigFileAt validates configuration data read from path.
+//
+// TOML files (see IsTOMLConfigPath) are converted before validation; all
+// other files are validated as YAML with ValidateConfigFile. Line numbers
+// in TOML unknown-field errors refer to the original TOML file.
+//
+// Parameters:
+//   - path: config file path, used to pick the format
+//   - data: configuration data as bytes
+//
+// Returns:
+//   - *ValidationResult: validation result with any errors and warnings found
+func ValidateConfigFileAt(path string, data []byte) *ValidationResult {
+	if !IsTOMLConfigPath(path) {
+		return ValidateConfigFile(data)
+	}
+
+	converted, err := tomlToYAML(data)
+	if err != nil {
+		if verr, ok := err.(ValidationError); ok {
+			return &ValidationResult{Errors: []ValidationError{verr}}
+		}
+		return &ValidationResult{Errors: []ValidationError{{Message: err.Error()}}}
+	}
+
+	result := ValidateConfigFile(converted)
+	for i := range result.Errors {
+		result.Errors[i].Message = remapTOMLLine(result.Errors[i].Message, data)
+	}
+	return result
+}
+
+// remapTOMLLine rewrites converted-YAML line numbers to refer to the TOML source.
+//
+// Unknown-field messages carry the line of the generated YAML; this looks up
+// the field as a key or table name in the TOML source instead. Line
+// references that cannot be mapped back to the TOML file are dropped.
+//
+// Parameters:
+//   - message: validation error message
+//   - data: original TOML data
+//
+// Returns:
+//   - string: message with line numbers that refer to the TOML file
+func remapTOMLLine(message string, data []byte) string {
+	if loc := unknownFieldLinePattern.FindStringIndex(message); loc != nil {
+		replacement := ""
+		if line := tomlKeyLine(data, extractQuotedField(message)); line > 0 {
+			replacement = fmt.Sprintf(" (line %d)", line)
+		}
+		message = message[:loc[0]] + replacement + message[loc[1]:]
+	}
+	return yamlLinePattern.ReplaceAllString(message, "")
+}
+
+// extractQuotedField returns the first single-quoted name in a validation message.
+//
+// Parameters:
+//   - message: validation error message such as "unknown field 'foo' (line 3)"
+//
+// Returns:
+//   - string: the quoted field name, or empty if none
+func extractQuotedField(message string) string {
+	start := strings.Index(message, "'")
+	if start < 0 {
+		return ""
+	}
+	end := strings.Index(message[start+1:], "'")
+	if end < 0 {
+		return ""
+	}
+	return message[start+1 : start+1+end]
+}
+
+// tomlKeyLine finds the line declaring a key or table segment in TOML data.
+//
+// Parameters:
+//   - data: TOML configuration data
+//   - key: bare key name to locate
+//
+// Returns:
+//   - int: 1-based line number, or 0 if the key is not declared
+func tomlKeyLine(data []byte, key string) int {
+	if key == "" {
+		return 0
+	}
+
+	quoted := regexp.QuoteMeta(key)
+	keyPattern := regexp.MustCompile(`^\s*(?:[\w"'.-]+\.)?"?` + quoted + `"?\s*=`)
+	tablePattern := regexp.MustCompile(`^\s*\[\[?[^\]]*\b` + quoted + `"?\]\]?`)
+
+	for i, line := range strings.Split(string(data), "\n") {
+		if keyPattern.MatchString(line) || tablePattern.MatchString(line) {
+			return i + 1
+		}
+	}
+	return 0
+}
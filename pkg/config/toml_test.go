@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tomlTestConfig is a TOML config extending the defaults with one custom rule and group.
+const tomlTestConfig = `extends = ["default"]
+
+[rules.custom-rule]
+manager = "custom"
+include = ["*.custom"]
+format = "raw"
+
+[rules.custom-rule.fields]
+packages = "prod"
+
+[rules.custom-rule.update]
+allow_prerelease = true
+
+[rules.custom-rule.groups]
+tooling = ["lint", "fmt"]
+`
+
+// TestLoadConfigTOML tests loading TOML configuration files.
+//
+// It verifies:
+//   - An explicit .toml path is decoded into the same Config as YAML
+//   - Extends, nested tables, and custom group unmarshaling work for TOML
+//   - .goupdate.toml is auto-detected in the working directory
+func TestLoadConfigTOML(t *testing.T) {
+	t.Run("explicit path", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configFile := filepath.Join(tmpDir, "custom.toml")
+		require.NoError(t, os.WriteFile(configFile, []byte(tomlTestConfig), 0644))
+
+		cfg, err := LoadConfig(configFile, tmpDir)
+		require.NoError(t, err)
+
+		require.Contains(t, cfg.Rules, "custom-rule")
+		rule := cfg.Rules["custom-rule"]
+		assert.Equal(t, "custom", rule.Manager)
+		assert.Equal(t, []string{"*.custom"}, rule.Include)
+		assert.Equal(t, "prod", rule.Fields["packages"])
+		require.NotNil(t, rule.Update)
+		assert.True(t, rule.Update.AllowPrerelease)
+		assert.Equal(t, []string{"lint", "fmt"}, rule.Groups["tooling"].Packages)
+		assert.Contains(t, cfg.Rules, "npm")
+	})
+
+	t.Run("auto-detected", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".goupdate.toml"), []byte(tomlTestConfig), 0644))
+
+		cfg, err := LoadConfig("", tmpDir)
+		require.NoError(t, err)
+		assert.Contains(t, cfg.Rules, "custom-rule")
+	})
+}
+
+// TestLoadConfigTOMLSyntaxError tests loading malformed TOML.
+//
+// It verifies:
+//   - A malformed TOML file returns a ValidationError
+//   - The error message includes the line number
+func TestLoadConfigTOMLSyntaxError(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "goupdate.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte("[rules.npm]\nmanager = \"js\"\ninclude = [\n"), 0644))
+
+	_, err := LoadConfig(configFile, tmpDir)
+	require.Error(t, err)
+
+	var verr ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Contains(t, verr.Message, "TOML syntax error")
+	assert.Contains(t, verr.Message, "line 3")
+}
+
+// TestFindLocalConfig tests the precedence of auto-detected config files.
+//
+// It verifies:
+//   - No config file returns an empty path
+//   - goupdate.toml is found on its own
+//   - .goupdate.toml takes precedence over goupdate.toml
+//   - .goupdate.yml takes precedence over TOML configs
+func TestFindLocalConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.Empty(t, FindLocalConfig(tmpDir))
+
+	for _, name := range []string{"goupdate.toml", ".goupdate.toml", ".goupdate.yml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(""), 0644))
+		assert.Equal(t, filepath.Join(tmpDir, name), FindLocalConfig(tmpDir))
+	}
+}
+
+// TestValidateConfigFileAt tests format-aware config validation.
+//
+// It verifies:
+//   - YAML paths are validated as YAML
+//   - TOML syntax errors report the TOML line number
+//   - TOML unknown fields report the line in the TOML source
+//   - Valid TOML passes validation
+func TestValidateConfigFileAt(t *testing.T) {
+	t.Run("yaml path", func(t *testing.T) {
+		result := ValidateConfigFileAt(".goupdate.yml", []byte("rules: {}\n"))
+		assert.False(t, result.HasErrors())
+	})
+
+	t.Run("toml syntax error", func(t *testing.T) {
+		result := ValidateConfigFileAt("goupdate.toml", []byte("extends = [\"default\"]\nrules = = 1\n"))
+		require.True(t, result.HasErrors())
+		assert.Contains(t, result.Errors[0].Message, "TOML syntax error")
+		assert.Contains(t, result.Errors[0].Message, "line 2")
+	})
+
+	t.Run("toml unknown field", func(t *testing.T) {
+		data := "extends = [\"default\"]\n\n[rules.npm]\nmanager = \"js\"\nincldue = [\"package.json\"]\n"
+		result := ValidateConfigFileAt(".goupdate.toml", []byte(data))
+		require.True(t, result.HasErrors())
+		assert.Contains(t, result.Errors[0].Message, "unknown field 'incldue' (line 5)")
+	})
+
+	t.Run("valid toml", func(t *testing.T) {
+		result := ValidateConfigFileAt(".goupdate.toml", []byte(tomlTestConfig))
+		assert.False(t, result.HasErrors(), result.ErrorMessages())
+	})
+}
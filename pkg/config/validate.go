@@ -3,9 +3,13 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"net/url"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/verbose"
 	"gopkg.in/yaml.v3"
 )
@@ -90,6 +94,14 @@ func (r *ValidationResult) ErrorMessages() string {
 	return "Configuration validation failed:\n" + strings.Join(msgs, "\n")
 }
 
+// Err returns the validation errors as a single error.
+//
+// Returns:
+//   - error: The errors joined with errors.JoinErrors, so errors.As finds each ValidationError; nil when there are none
+func (r *ValidationResult) Err() error {
+	return errors.JoinErrors(r.Errors)
+}
+
 // VerboseErrorMessages returns detailed error messages with schema hints.
 //
 // This is like ErrorMessages but includes additional context such as
@@ -111,27 +123,59 @@ func (r *ValidationResult) VerboseErrorMessages() string {
 // Schema information for validation errors
 var configSchema = map[string]schemaInfo{
 	"Config": {
-		fields: "extends, working_dir, rules, exclude_versions, groups, incremental, system_tests",
+		fields: "extends, working_dir, rules, exclude_versions, groups, incremental, system_tests, security, notify, badge, severity",
 		doc:    "configuration",
 	},
 	"PackageManagerCfg": {
-		fields: "enabled, manager, include, exclude, groups, format, fields, ignore, exclude_versions, constraint_mapping, latest_mapping, package_overrides, extraction, outdated, update, lock_files, self_pinning, metadata, incremental",
+		fields: "enabled, manager, include, exclude, groups, format, fields, ignore, exclude_versions, constraint_mapping, latest_mapping, package_overrides, pin, extraction, outdated, update, lock_files, self_pinning, metadata, incremental, max_requests_per_second, include_transitive, registry, changelog_url, paths, tree, severity, version_source, constraint_style, selection_strategy, quarantine_days, workspace, workspace_file, override, unsupported_reasons",
 		doc:    "rules",
 	},
 	"OutdatedCfg": {
-		fields: "commands, env, format, extraction, versioning, exclude_versions, exclude_version_patterns, timeout_seconds",
+		fields: "commands, env, format, extraction, versioning, exclude_versions, exclude_version_patterns, timeout_seconds, retries, retry_backoff, release_dates, yanked, repository",
 		doc:    "outdated",
 	},
+	"ReleaseDatesCfg": {
+		fields: "commands, format, json_key, pattern, timeout_seconds",
+		doc:    "release-dates",
+	},
+	"YankedCfg": {
+		fields: "commands, format, json_key, reason_key, pattern, timeout_seconds",
+		doc:    "yanked-versions",
+	},
+	"RepositoryCfg": {
+		fields: "commands, format, json_keys, pattern, timeout_seconds",
+		doc:    "source-repositories",
+	},
 	"UpdateCfg": {
-		fields: "commands, env, group, timeout_seconds",
+		fields: "commands, env, group, timeout_seconds, allow_prerelease, resolve_digest, group_commands, dedupe",
 		doc:    "update",
 	},
+	"DedupeCfg": {
+		fields: "commands, env, timeout_seconds, critical",
+		doc:    "dedupe-after-updates",
+	},
+	"ResolveDigestCfg": {
+		fields: "commands, pattern, timeout_seconds",
+		doc:    "digest-pinned-declarations",
+	},
+	"TreeCfg": {
+		fields: "commands, env, format, timeout_seconds",
+		doc:    "dependency-trees",
+	},
+	"WorkspaceFileCfg": {
+		fields: "file, format, commands, env, timeout_seconds",
+		doc:    "go-workspaces",
+	},
+	"UnsupportedReasonsCfg": {
+		fields: "unpinned, lookup_failed, non_registry, sources",
+		doc:    "unsupported-reasons",
+	},
 	"LockFileCfg": {
-		fields: "files, format, extraction, commands, env, timeout_seconds, command_extraction",
+		fields: "files, format, extraction, commands, env, timeout_seconds, command_extraction, refresh_commands",
 		doc:    "lock-files",
 	},
 	"ExtractionCfg": {
-		fields: "pattern, path, name_attr, version_attr, name_element, version_element, dev_attr, dev_value, dev_element, dev_element_value",
+		fields: "pattern, patterns, path, name_attr, version_attr, name_element, version_element, dev_attr, dev_value, dev_element, dev_element_value, dev_groups, dev_scopes, version_ref_pattern, source_pattern",
 		doc:    "extraction",
 	},
 	"OutdatedExtractionCfg": {
@@ -143,7 +187,7 @@ var configSchema = map[string]schemaInfo{
 		doc:    "package-overrides",
 	},
 	"VersioningCfg": {
-		fields: "format, regex, sort",
+		fields: "format, regex, sort, match_precision",
 		doc:    "versioning",
 	},
 	"LatestMappingCfg": {
@@ -162,6 +206,18 @@ var configSchema = map[string]schemaInfo{
 		fields: "name, commands, env, timeout_seconds, continue_on_fail",
 		doc:    "system-tests",
 	},
+	"NotifyCfg": {
+		fields: "url, format, headers, timeout_seconds, on_dry_run",
+		doc:    "notifications",
+	},
+	"BadgeCfg": {
+		fields: "label, colors",
+		doc:    "outdated-badge",
+	},
+	"BadgeColorCfg": {
+		fields: "min, color",
+		doc:    "outdated-badge",
+	},
 }
 
 type schemaInfo struct {
@@ -309,6 +365,88 @@ func validateConfigStruct(cfg *Config, result *ValidationResult) {
 	if cfg.SystemTests != nil {
 		validateSystemTests(cfg.SystemTests, result)
 	}
+
+	// Validate notify configuration
+	if cfg.Notify != nil {
+		validateNotify(cfg.Notify, result)
+	}
+
+	if cfg.Badge != nil {
+		validateBadge(cfg.Badge, result)
+	}
+
+	validateSeverity("severity", cfg.Severity, result)
+}
+
+// validateNotify validates the update notification configuration.
+//
+// This checks that a webhook URL is set, the format is known, and the
+// timeout is not negative.
+//
+// Parameters:
+//   - n: the notify configuration to validate
+//   - result: validation result to append errors to
+func validateNotify(n *NotifyCfg, result *ValidationResult) {
+	if strings.TrimSpace(n.URL) == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      "notify.url",
+			Message:    "webhook url is required",
+			DocSection: "notifications",
+		})
+	}
+
+	if n.Format != "" && n.Format != NotifyFormatJSON && n.Format != NotifyFormatSlack {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      "notify.format",
+			Message:    fmt.Sprintf("invalid format '%s'", n.Format),
+			Expected:   "json or slack",
+			DocSection: "notifications",
+		})
+	}
+
+	if n.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      "notify.timeout_seconds",
+			Message:    "timeout cannot be negative",
+			DocSection: "notifications",
+		})
+	}
+}
+
+// validateBadge validates the outdated badge configuration.
+//
+// This checks that every color threshold names a color, has a non-negative
+// minimum, and that no minimum is listed twice.
+//
+// Parameters:
+//   - b: the badge configuration to validate
+//   - result: validation result to append errors to
+func validateBadge(b *BadgeCfg, result *ValidationResult) {
+	seen := make(map[int]bool)
+	for i, c := range b.Colors {
+		field := fmt.Sprintf("badge.colors[%d]", i)
+		if strings.TrimSpace(c.Color) == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:      field + ".color",
+				Message:    "color is required",
+				DocSection: "outdated-badge",
+			})
+		}
+		if c.Min < 0 {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:      field + ".min",
+				Message:    "minimum outdated count cannot be negative",
+				DocSection: "outdated-badge",
+			})
+		} else if seen[c.Min] {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:      field + ".min",
+				Message:    fmt.Sprintf("duplicate minimum %d", c.Min),
+				DocSection: "outdated-badge",
+			})
+		}
+		seen[c.Min] = true
+	}
 }
 
 // validateSystemTests validates system tests configuration.
@@ -380,7 +518,7 @@ func validateSystemTests(st *SystemTestsCfg, result *ValidationResult) {
 // validateRule validates a package manager rule configuration.
 //
 // This checks include patterns, groups, lock files, outdated config,
-// and package overrides for validity.
+// update timeouts, and package overrides for validity.
 //
 // Parameters:
 //   - name: the rule name
@@ -427,6 +565,78 @@ func validateRule(name string, rule *PackageManagerCfg, result *ValidationResult
 				Message: "lock file must specify format or extraction",
 			})
 		}
+		// The refresh runs once per lock directory, never for a single package
+		if strings.Contains(lf.RefreshCommands, "{{package}}") {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:      lfPrefix + ".refresh_commands",
+				Message:    "refresh_commands cannot use the {{package}} placeholder",
+				Expected:   "a command regenerating the whole lock file (e.g. npm install --package-lock-only)",
+				DocSection: "lock-files",
+			})
+		}
+	}
+
+	if rule.GetMaxRequestsPerSecond() < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".max_requests_per_second",
+			Message:  "request rate cannot be negative",
+			Expected: "positive number of requests per second (0 disables the limit)",
+		})
+	}
+
+	validateRegistry(prefix+".registry", rule.Registry, result)
+	validateVersionSource(prefix+".version_source", rule.VersionSource, result)
+	validateConstraintStyle(prefix+".constraint_style", rule.Manager, rule.ConstraintStyle, result)
+	validateSelectionStrategy(prefix+".selection_strategy", rule, result)
+	if rule.GetQuarantineDays() < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      prefix + ".quarantine_days",
+			Message:    "quarantine_days cannot be negative",
+			Expected:   "a number of days, or 0 to disable the quarantine",
+			DocSection: "quarantine",
+		})
+	}
+	if rule.IsWorkspace() && len(rule.LockFiles) == 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      prefix + ".workspace",
+			Message:    "workspace needs lock_files to find the workspace root",
+			Expected:   "lock_files with the root lock file pattern, or workspace: false",
+			DocSection: "workspaces",
+		})
+	}
+	validateChangelogURL(prefix+".changelog_url", rule.ChangelogURL, result)
+	validateRulePaths(prefix+".paths", rule.Paths, result)
+	validateSeverity(prefix+".severity", rule.Severity, result)
+	if rule.Tree != nil {
+		validateTree(prefix+".tree", rule.Tree, result)
+	}
+	if rule.WorkspaceFile != nil {
+		validateWorkspaceFile(prefix+".workspace_file", rule.WorkspaceFile, result)
+	}
+
+	// Version refs need both the ref name and its version to resolve and rewrite
+	if rule.Extraction != nil && rule.Extraction.VersionRefPattern != "" {
+		refPattern := rule.Extraction.VersionRefPattern
+		if !strings.Contains(refPattern, "?P<ref>") || !strings.Contains(refPattern, "?P<version>") {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    prefix + ".extraction.version_ref_pattern",
+				Message:  "version ref pattern requires ref and version groups",
+				Expected: "regex with (?P<ref>...) and (?P<version>...) named groups",
+			})
+		}
+	}
+
+	// Source tables must name the package and the source to mark it NonRegistry
+	if rule.Extraction != nil && rule.Extraction.SourcePattern != "" {
+		sourcePattern := rule.Extraction.SourcePattern
+		hasName := strings.Contains(sourcePattern, "?P<n>") || strings.Contains(sourcePattern, "?P<name>")
+		if !hasName || !strings.Contains(sourcePattern, "?P<source>") {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    prefix + ".extraction.source_pattern",
+				Message:  "source pattern requires name and source groups",
+				Expected: "regex with (?P<n>...) and (?P<source>...) named groups",
+			})
+		}
 	}
 
 	// Validate outdated config
@@ -434,6 +644,21 @@ func validateRule(name string, rule *PackageManagerCfg, result *ValidationResult
 		validateOutdated(prefix+".outdated", rule.Outdated, result)
 	}
 
+	// Validate update timeout
+	if rule.Update != nil && rule.Update.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".update.timeout_seconds",
+			Message:  "timeout cannot be negative",
+			Expected: "non-negative integer (0 disables the timeout)",
+		})
+	}
+	if rule.Update != nil && rule.Update.ResolveDigest != nil {
+		validateResolveDigest(prefix+".update.resolve_digest", rule.Update.ResolveDigest, result)
+	}
+	if rule.Update != nil && rule.Update.Dedupe != nil {
+		validateDedupe(prefix+".update.dedupe", rule.Update.Dedupe, result)
+	}
+
 	// Validate package overrides
 	for pkgName, override := range rule.PackageOverrides {
 		if pkgName == "" {
@@ -444,12 +669,238 @@ func validateRule(name string, rule *PackageManagerCfg, result *ValidationResult
 		}
 		validatePackageOverride(fmt.Sprintf("%s.package_overrides.%s", prefix, pkgName), &override, result)
 	}
+
+	// Validate pins; range syntax is checked by outdated.ValidatePins once the config is loaded
+	for pkgName, pin := range rule.Pin {
+		if pkgName == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   prefix + ".pin",
+				Message: "pin package name cannot be empty",
+			})
+		}
+		if strings.TrimSpace(pin) == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    fmt.Sprintf("%s.pin.%s", prefix, pkgName),
+				Message:  "pin cannot be empty",
+				Expected: `version range such as "2.x", "^1.4.0", or ">=1.2 <2"`,
+			})
+		}
+	}
+}
+
+// validateRegistry checks a rule's registry override.
+//
+// The registry must be an absolute http or https URL. Credentials embedded in
+// the URL are reported as a warning, since they would end up in command lines
+// and logs; tools read credentials from .npmrc, .netrc or their env vars instead.
+//
+// Parameters:
+//   - field: the field path for error messages
+//   - registry: the configured registry URL; empty means no override
+//   - result: validation result to append errors and warnings to
+func validateRegistry(field, registry string, result *ValidationResult) {
+	if registry == "" {
+		return
+	}
+
+	u, err := url.Parse(registry)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      field,
+			Message:    fmt.Sprintf("invalid registry URL %q", registry),
+			Expected:   "absolute http or https URL (e.g. https://npm.example.com/)",
+			DocSection: "private-registries",
+		})
+		return
+	}
+
+	if u.User != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s: registry URL contains credentials; prefer .npmrc, .netrc or the package manager's environment variables", field))
+	}
+}
+
+// validateVersionSource checks a rule's offline version source.
+//
+// Parameters:
+//   - field: the field path for error messages
+//   - source: the configured version source; empty means the outdated command is used
+//   - result: validation result to append errors to
+func validateVersionSource(field, source string, result *ValidationResult) {
+	if source == "" {
+		return
+	}
+
+	if !strings.HasPrefix(source, "file://") || strings.TrimPrefix(source, "file://") == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      field,
+			Message:    fmt.Sprintf("invalid version source %q", source),
+			Expected:   "file:// URL of a JSON index file or directory (e.g. file:///mirror/versions.json)",
+			DocSection: "offline-version-sources",
+		})
+	}
+}
+
+// validateConstraintStyle checks a rule's constraint style against its manager.
+//
+// Parameters:
+//   - field: the field path for error messages
+//   - manager: the rule's manager, which decides the operators a style writes
+//   - style: the configured constraint style; empty means preserve
+//   - result: validation result to append errors to
+func validateConstraintStyle(field, manager, style string, result *ValidationResult) {
+	switch style {
+	case "", ConstraintStylePreserve:
+		return
+	case ConstraintStyleCaret, ConstraintStyleTilde, ConstraintStyleExact:
+	default:
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      field,
+			Message:    fmt.Sprintf("invalid constraint style %q", style),
+			Expected:   "one of: preserve, caret, tilde, exact",
+			DocSection: "constraint-style",
+		})
+		return
+	}
+
+	if _, ok := ConstraintStyleOperator(manager, style); !ok {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      field,
+			Message:    fmt.Sprintf("constraint style %q is not supported for manager %q", style, manager),
+			Expected:   "preserve, or a style with an operator for the manager (see the constraint style table)",
+			DocSection: "constraint-style",
+		})
+	}
+}
+
+// validateSelectionStrategy checks a rule's selection strategy.
+//
+// most-recent-by-date compares publish times, so the rule needs
+// outdated.release_dates commands to look them up.
+//
+// Parameters:
+//   - field: Field path used in error messages
+//   - rule: The rule to check
+//   - result: Validation result receiving errors
+func validateSelectionStrategy(field string, rule *PackageManagerCfg, result *ValidationResult) {
+	switch rule.SelectionStrategy {
+	case "", SelectionStrategyHighest, SelectionStrategyLowestNonVulnerable:
+	case SelectionStrategyMostRecentByDate:
+		if rule.Outdated == nil || rule.Outdated.ReleaseDates == nil || strings.TrimSpace(rule.Outdated.ReleaseDates.Commands) == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:      field,
+				Message:    "selection strategy \"most-recent-by-date\" needs release dates",
+				Expected:   "outdated.release_dates commands for the rule, or another selection strategy",
+				DocSection: "selection-strategy",
+			})
+		}
+	default:
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      field,
+			Message:    fmt.Sprintf("invalid selection strategy %q", rule.SelectionStrategy),
+			Expected:   "one of: highest, lowest-non-vulnerable, most-recent-by-date",
+			DocSection: "selection-strategy",
+		})
+	}
+}
+
+// validateChangelogURL checks a rule's release notes URL template.
+//
+// Placeholders are expanded with sample values before the URL is parsed, so
+// templates such as "https://github.com/{{package}}" are accepted.
+//
+// Parameters:
+//   - field: the field path for error messages
+//   - template: the configured URL template; empty means no template
+//   - result: validation result to append errors to
+func validateChangelogURL(field, template string, result *ValidationResult) {
+	if template == "" {
+		return
+	}
+
+	sample := strings.NewReplacer("{{package}}", "pkg", "{{from}}", "1.0.0", "{{to}}", "2.0.0").Replace(template)
+	u, err := url.Parse(sample)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    field,
+			Message:  fmt.Sprintf("invalid changelog URL %q", template),
+			Expected: "absolute http or https URL template (e.g. https://github.com/{{package}})",
+		})
+	}
+}
+
+// validateRulePaths checks the directories a rule detects manifests in.
+//
+// Paths are relative to the working directory and may not leave it, so a
+// shared config cannot point detection or lock commands outside the project.
+//
+// Parameters:
+//   - field: the field path for error messages
+//   - paths: the configured directories; empty means the whole working directory
+//   - result: validation result to append errors to
+func validateRulePaths(field string, paths []string, result *ValidationResult) {
+	for i, dir := range paths {
+		clean := path.Clean(strings.ReplaceAll(dir, "\\", "/"))
+		if strings.TrimSpace(dir) != "" && !path.IsAbs(clean) && clean != ".." && !strings.HasPrefix(clean, "../") {
+			continue
+		}
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      fmt.Sprintf("%s[%d]", field, i),
+			Message:    fmt.Sprintf("invalid rule path %q", dir),
+			Expected:   "directory relative to the working directory (e.g. frontend or services/api)",
+			DocSection: "monorepos",
+		})
+	}
+}
+
+// validateRegistryUsage checks that every rule with a registry override uses it.
+//
+// The override only reaches the registry through $GOUPDATE_REGISTRY, so a rule
+// whose outdated and release date commands never read it would silently keep
+// querying the public registry. This runs on the merged configuration, since a
+// rule usually sets registry in one file and inherits its commands from another.
+// Disabled rules are skipped.
+//
+// Parameters:
+//   - cfg: the merged configuration to validate
+//
+// Returns:
+//   - error: ValidationError for the first rule, by name, whose commands ignore its registry
+func validateRegistryUsage(cfg *Config) error {
+	names := make([]string, 0, len(cfg.Rules))
+	for name := range cfg.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rule := cfg.Rules[name]
+		if rule.Registry == "" || !rule.IsEnabled() {
+			continue
+		}
+		var commands string
+		if rule.Outdated != nil {
+			commands = rule.Outdated.Commands
+			if rule.Outdated.ReleaseDates != nil {
+				commands += "\n" + rule.Outdated.ReleaseDates.Commands
+			}
+		}
+		if !strings.Contains(commands, RegistryEnvVar) {
+			return ValidationError{
+				Field:      fmt.Sprintf("rules.%s.registry", name),
+				Message:    fmt.Sprintf("rule %s does not support registry: its outdated commands do not use $%s", name, RegistryEnvVar),
+				Expected:   fmt.Sprintf("outdated.commands referencing $%s, or no registry", RegistryEnvVar),
+				DocSection: "private-registries",
+			}
+		}
+	}
+	return nil
 }
 
 // validateOutdated validates outdated configuration.
 //
-// This checks that commands contain required placeholders and warns
-// if the {{package}} placeholder is missing.
+// This checks that commands contain required placeholders, warns
+// if the {{package}} placeholder is missing, and rejects negative
+// retry settings and malformed exclude_versions globs.
 //
 // Parameters:
 //   - prefix: field path prefix for error messages
@@ -463,11 +914,315 @@ func validateOutdated(prefix string, outdated *OutdatedCfg, result *ValidationRe
 			result.Warnings = append(result.Warnings, fmt.Sprintf("%s.commands: missing {{package}} placeholder", prefix))
 		}
 	}
+
+	validateExcludeVersions(prefix+".exclude_versions", outdated.ExcludeVersions, result)
+
+	if outdated.Retries < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".retries",
+			Message:  "retries cannot be negative",
+			Expected: "non-negative integer",
+		})
+	}
+
+	if outdated.RetryBackoffMs < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".retry_backoff",
+			Message:  "retry backoff cannot be negative",
+			Expected: "non-negative integer (milliseconds)",
+		})
+	}
+
+	if outdated.ReleaseDates != nil {
+		validateReleaseDates(prefix+".release_dates", outdated.ReleaseDates, result)
+	}
+
+	if outdated.Yanked != nil {
+		validateYanked(prefix+".yanked", outdated.Yanked, result)
+	}
+
+	if outdated.Repository != nil {
+		validateRepository(prefix+".repository", outdated.Repository, result)
+	}
+}
+
+// validateReleaseDates validates release date lookup configuration.
+//
+// This requires commands, accepts only the json and raw formats, and requires
+// a pattern with "version" and "published" named groups for raw output.
+//
+// Parameters:
+//   - prefix: field path prefix for error messages
+//   - releaseDates: the release date configuration to validate
+//   - result: validation result to append errors and warnings to
+func validateReleaseDates(prefix string, releaseDates *ReleaseDatesCfg, result *ValidationResult) {
+	if strings.TrimSpace(releaseDates.Commands) == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".commands",
+			Message:  "release date lookup requires commands",
+			Expected: "command printing publish timestamps, e.g. npm view {{package}} time --json",
+		})
+	} else if !strings.Contains(releaseDates.Commands, "{{package}}") {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s.commands: missing {{package}} placeholder", prefix))
+	}
+
+	switch strings.ToLower(strings.TrimSpace(releaseDates.Format)) {
+	case "", "json":
+	case "raw":
+		if !strings.Contains(releaseDates.Pattern, "?P<version>") || !strings.Contains(releaseDates.Pattern, "?P<published>") {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    prefix + ".pattern",
+				Message:  "raw release dates require a pattern with version and published groups",
+				Expected: "regex with (?P<version>...) and (?P<published>...) named groups",
+			})
+		}
+	default:
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".format",
+			Message:  fmt.Sprintf("unsupported release dates format %q", releaseDates.Format),
+			Expected: "one of: json, raw",
+		})
+	}
+
+	if releaseDates.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".timeout_seconds",
+			Message:  "timeout cannot be negative",
+			Expected: "non-negative integer (0 disables the timeout)",
+		})
+	}
+}
+
+// validateYanked validates yanked version lookup configuration.
+//
+// This requires commands, accepts only the json, raw, and go-mod formats, and
+// requires a pattern with a "version" named group for raw output.
+//
+// Parameters:
+//   - prefix: field path prefix for error messages
+//   - yanked: the yanked version configuration to validate
+//   - result: validation result to append errors and warnings to
+func validateYanked(prefix string, yanked *YankedCfg, result *ValidationResult) {
+	if strings.TrimSpace(yanked.Commands) == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".commands",
+			Message:  "yanked version lookup requires commands",
+			Expected: "command printing yanked versions, e.g. npm view {{package}}@'*' name version deprecated --json",
+		})
+	} else if !strings.Contains(yanked.Commands, "{{package}}") {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s.commands: missing {{package}} placeholder", prefix))
+	}
+
+	switch strings.ToLower(strings.TrimSpace(yanked.Format)) {
+	case "", YankedFormatJSON, YankedFormatGoMod:
+	case YankedFormatRaw:
+		if !strings.Contains(yanked.Pattern, "?P<version>") {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    prefix + ".pattern",
+				Message:  "raw yanked versions require a pattern with a version group",
+				Expected: "regex with a (?P<version>...) named group and an optional (?P<reason>...) group",
+			})
+		}
+	default:
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".format",
+			Message:  fmt.Sprintf("unsupported yanked versions format %q", yanked.Format),
+			Expected: "one of: json, raw, go-mod",
+		})
+	}
+
+	if yanked.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".timeout_seconds",
+			Message:  "timeout cannot be negative",
+			Expected: "non-negative integer (0 disables the timeout)",
+		})
+	}
+}
+
+// validateRepository validates source repository lookup configuration.
+//
+// This requires commands, accepts only the json and raw formats, and requires
+// a pattern with a "url" named group for raw output.
+//
+// Parameters:
+//   - prefix: field path prefix for error messages
+//   - repository: the repository lookup configuration to validate
+//   - result: validation result to append errors and warnings to
+func validateRepository(prefix string, repository *RepositoryCfg, result *ValidationResult) {
+	if strings.TrimSpace(repository.Commands) == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".commands",
+			Message:  "repository lookup requires commands",
+			Expected: "command printing the repository URL, e.g. npm view {{package}} repository --json",
+		})
+	} else if !strings.Contains(repository.Commands, "{{package}}") {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s.commands: missing {{package}} placeholder", prefix))
+	}
+
+	switch strings.ToLower(strings.TrimSpace(repository.Format)) {
+	case "", "json":
+	case "raw":
+		if !strings.Contains(repository.Pattern, "?P<url>") {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    prefix + ".pattern",
+				Message:  "raw repository output requires a pattern with a url group",
+				Expected: "regex with a (?P<url>...) named group",
+			})
+		}
+	default:
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".format",
+			Message:  fmt.Sprintf("unsupported repository format %q", repository.Format),
+			Expected: "one of: json, raw",
+		})
+	}
+
+	if repository.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".timeout_seconds",
+			Message:  "timeout cannot be negative",
+			Expected: "non-negative integer (0 disables the timeout)",
+		})
+	}
+}
+
+// validateDedupe validates dedupe step configuration.
+//
+// Parameters:
+//   - prefix: field path prefix for error messages
+//   - dedupe: the dedupe configuration to validate
+//   - result: validation result to append errors to
+func validateDedupe(prefix string, dedupe *DedupeCfg, result *ValidationResult) {
+	if strings.TrimSpace(dedupe.Commands) == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".commands",
+			Message:  "dedupe requires commands",
+			Expected: "command folding duplicated packages in the lock file (e.g. npm dedupe)",
+		})
+	}
+
+	if dedupe.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".timeout_seconds",
+			Message:  "timeout cannot be negative",
+			Expected: "non-negative integer (0 disables the timeout)",
+		})
+	}
+}
+
+// validateResolveDigest validates digest lookup configuration.
+//
+// This requires commands and a pattern with a "digest" named group.
+//
+// Parameters:
+//   - prefix: field path prefix for error messages
+//   - resolveDigest: the digest lookup configuration to validate
+//   - result: validation result to append errors and warnings to
+func validateResolveDigest(prefix string, resolveDigest *ResolveDigestCfg, result *ValidationResult) {
+	if strings.TrimSpace(resolveDigest.Commands) == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".commands",
+			Message:  "digest lookup requires commands",
+			Expected: "command printing the digest of {{package}} at {{version}}",
+		})
+	} else if !strings.Contains(resolveDigest.Commands, "{{version}}") {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s.commands: missing {{version}} placeholder", prefix))
+	}
+
+	if !strings.Contains(resolveDigest.Pattern, "?P<digest>") {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".pattern",
+			Message:  "digest lookup requires a pattern with a digest group",
+			Expected: "regex with a (?P<digest>...) named group",
+		})
+	}
+
+	if resolveDigest.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".timeout_seconds",
+			Message:  "timeout cannot be negative",
+			Expected: "non-negative integer (0 disables the timeout)",
+		})
+	}
+}
+
+// validateTree validates dependency tree lookup configuration.
+//
+// This requires commands and one of the tree output formats, and rejects a
+// negative timeout.
+//
+// Parameters:
+//   - prefix: field path prefix for error messages
+//   - tree: the dependency tree configuration to validate
+//   - result: validation result to append errors and warnings to
+func validateTree(prefix string, tree *TreeCfg, result *ValidationResult) {
+	if strings.TrimSpace(tree.Commands) == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".commands",
+			Message:  "dependency tree lookup requires commands",
+			Expected: "command printing the dependency tree, e.g. npm ls --all --json",
+		})
+	}
+
+	switch strings.ToLower(strings.TrimSpace(tree.Format)) {
+	case TreeFormatNPMLs, TreeFormatGoModGraph, TreeFormatComposerTree:
+	default:
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".format",
+			Message:  fmt.Sprintf("unsupported dependency tree format %q", tree.Format),
+			Expected: fmt.Sprintf("one of: %s, %s, %s", TreeFormatNPMLs, TreeFormatGoModGraph, TreeFormatComposerTree),
+		})
+	}
+
+	if tree.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".timeout_seconds",
+			Message:  "timeout cannot be negative",
+			Expected: "non-negative integer (0 disables the timeout)",
+		})
+	}
+}
+
+// validateWorkspaceFile validates workspace file configuration.
+//
+// Parameters:
+//   - prefix: field path prefix for error messages
+//   - workspaceFile: the workspace file configuration to validate
+//   - result: validation result to append errors to
+func validateWorkspaceFile(prefix string, workspaceFile *WorkspaceFileCfg, result *ValidationResult) {
+	file := strings.TrimSpace(workspaceFile.File)
+	if file == "" || strings.ContainsAny(file, `/\`) {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      prefix + ".file",
+			Message:    fmt.Sprintf("invalid workspace file name %q", workspaceFile.File),
+			Expected:   "a file name without directories, e.g. go.work",
+			DocSection: "go-workspaces",
+		})
+	}
+
+	if strings.ToLower(strings.TrimSpace(workspaceFile.Format)) != WorkspaceFormatGoWork {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:      prefix + ".format",
+			Message:    fmt.Sprintf("unsupported workspace file format %q", workspaceFile.Format),
+			Expected:   WorkspaceFormatGoWork,
+			DocSection: "go-workspaces",
+		})
+	}
+
+	if workspaceFile.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".timeout_seconds",
+			Message:  "timeout cannot be negative",
+			Expected: "non-negative integer (0 disables the timeout)",
+		})
+	}
 }
 
 // validatePackageOverride validates package override configuration.
 //
-// This warns if a constraint is specified but empty.
+// This warns if a constraint is specified but empty and rejects a negative
+// update timeout.
 //
 // Parameters:
 //   - prefix: field path prefix for error messages
@@ -478,6 +1233,43 @@ func validatePackageOverride(prefix string, override *PackageOverrideCfg, result
 	if override.Constraint != nil && *override.Constraint == "" {
 		result.Warnings = append(result.Warnings, fmt.Sprintf("%s.constraint: empty constraint specified", prefix))
 	}
+
+	if override.Outdated != nil {
+		validateExcludeVersions(prefix+".outdated.exclude_versions", override.Outdated.ExcludeVersions, result)
+	}
+
+	if override.Update != nil && override.Update.TimeoutSeconds != nil && *override.Update.TimeoutSeconds < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    prefix + ".update.timeout_seconds",
+			Message:  "timeout cannot be negative",
+			Expected: "non-negative integer (0 disables the timeout)",
+		})
+	}
+}
+
+// validateExcludeVersions validates exclude_versions glob entries.
+//
+// Entries containing glob characters must be accepted by path.Match; exact
+// versions and empty entries are left to the version filter.
+//
+// Parameters:
+//   - field: field path for error messages
+//   - versions: the exclude_versions entries to validate
+//   - result: validation result to append errors to
+func validateExcludeVersions(field string, versions []string, result *ValidationResult) {
+	for i, version := range versions {
+		trimmed := strings.TrimSpace(version)
+		if !strings.ContainsAny(trimmed, "*?[") {
+			continue
+		}
+		if _, err := path.Match(trimmed, ""); err != nil {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    fmt.Sprintf("%s[%d]", field, i),
+				Message:  fmt.Sprintf("invalid version glob %q", version),
+				Expected: `version or glob (e.g. "4.0.0", "2.3.*")`,
+			})
+		}
+	}
 }
 
 // extractFieldAndType extracts the unknown field name and the type it was found in.
@@ -570,6 +1362,8 @@ var commonTypos = map[string]map[string]string{
 		"exclude_version":     "exclude_versions",
 		"group":               "groups",
 		"incremental_package": "incremental",
+		"notification":        "notify",
+		"notifications":       "notify",
 	},
 	"PackageManagerCfg": {
 		"enable":              "enabled",
@@ -585,11 +1379,25 @@ var commonTypos = map[string]map[string]string{
 		"exclude_version":     "exclude_versions",
 		"constraint_map":      "constraint_mapping",
 		"constraintMapping":   "constraint_mapping",
+		"constraintStyle":     "constraint_style",
+		"constraint-style":    "constraint_style",
+		"selectionStrategy":   "selection_strategy",
+		"selection-strategy":  "selection_strategy",
+		"quarantineDays":      "quarantine_days",
+		"quarantine-days":     "quarantine_days",
+		"quarantine":          "quarantine_days",
+		"workspaces":          "workspace",
+		"workspaceFile":       "workspace_file",
+		"workspace-file":      "workspace_file",
+		"unsupportedReasons":  "unsupported_reasons",
+		"unsupported-reasons": "unsupported_reasons",
+		"unsupported_reason":  "unsupported_reasons",
 		"latest_map":          "latest_mapping",
 		"latestMapping":       "latest_mapping",
 		"self-pinning":        "self_pinning",
 		"selfPinning":         "self_pinning",
 		"incremental_package": "incremental",
+		"rate_limit":          "max_requests_per_second",
 	},
 	"OutdatedCfg": {
 		"command":                 "commands",
@@ -598,11 +1406,72 @@ var commonTypos = map[string]map[string]string{
 		"exclude_version":         "exclude_versions",
 		"exclude_version_pattern": "exclude_version_patterns",
 		"excludeVersionPatterns":  "exclude_version_patterns",
+		"retry":                   "retries",
+		"max_retries":             "retries",
+		"retryBackoff":            "retry_backoff",
+		"retry-backoff":           "retry_backoff",
+		"backoff":                 "retry_backoff",
+		"releaseDates":            "release_dates",
+		"release-dates":           "release_dates",
+		"yank":                    "yanked",
+		"deprecated":              "yanked",
+		"retracted":               "yanked",
+		"repo":                    "repository",
+		"source_repository":       "repository",
+	},
+	"ReleaseDatesCfg": {
+		"command":        "commands",
+		"timeout":        "timeout_seconds",
+		"timeoutSeconds": "timeout_seconds",
+		"json-key":       "json_key",
+		"jsonKey":        "json_key",
+	},
+	"YankedCfg": {
+		"command":        "commands",
+		"timeout":        "timeout_seconds",
+		"timeoutSeconds": "timeout_seconds",
+		"json-key":       "json_key",
+		"jsonKey":        "json_key",
+	},
+	"RepositoryCfg": {
+		"command":        "commands",
+		"timeout":        "timeout_seconds",
+		"timeoutSeconds": "timeout_seconds",
+		"json_key":       "json_keys",
+		"json-keys":      "json_keys",
+		"jsonKeys":       "json_keys",
 	},
 	"UpdateCfg": {
-		"lock_commands":  "commands",
-		"lock_command":   "commands",
-		"lockCommands":   "commands",
+		"lock_commands":     "commands",
+		"lock_command":      "commands",
+		"lockCommands":      "commands",
+		"timeout":           "timeout_seconds",
+		"timeoutSeconds":    "timeout_seconds",
+		"allowPrerelease":   "allow_prerelease",
+		"allow_prereleases": "allow_prerelease",
+		"prerelease":        "allow_prerelease",
+		"resolveDigest":     "resolve_digest",
+		"resolve-digest":    "resolve_digest",
+		"digest":            "resolve_digest",
+	},
+	"DedupeCfg": {
+		"command":        "commands",
+		"timeout":        "timeout_seconds",
+		"timeoutSeconds": "timeout_seconds",
+	},
+	"ResolveDigestCfg": {
+		"command":        "commands",
+		"timeout":        "timeout_seconds",
+		"timeoutSeconds": "timeout_seconds",
+	},
+	"WorkspaceFileCfg": {
+		"command":        "commands",
+		"name":           "file",
+		"timeout":        "timeout_seconds",
+		"timeoutSeconds": "timeout_seconds",
+	},
+	"TreeCfg": {
+		"command":        "commands",
 		"timeout":        "timeout_seconds",
 		"timeoutSeconds": "timeout_seconds",
 	},
@@ -610,6 +1479,9 @@ var commonTypos = map[string]map[string]string{
 		"file":               "files",
 		"command":            "commands",
 		"command_extraction": "command_extraction",
+		"refresh_command":    "refresh_commands",
+		"refresh-commands":   "refresh_commands",
+		"refreshCommands":    "refresh_commands",
 	},
 	"ExtractionCfg": {
 		"name-attr":       "name_attr",
@@ -639,6 +1511,15 @@ var commonTypos = map[string]map[string]string{
 		"stopOnFail":    "stop_on_fail",
 		"stop-on-fail":  "stop_on_fail",
 	},
+	"NotifyCfg": {
+		"webhook":         "url",
+		"webhook_url":     "url",
+		"timeout":         "timeout_seconds",
+		"timeoutSeconds":  "timeout_seconds",
+		"timeout-seconds": "timeout_seconds",
+		"onDryRun":        "on_dry_run",
+		"on-dry-run":      "on_dry_run",
+	},
 	"SystemTestCfg": {
 		"command":          "commands",
 		"timeout":          "timeout_seconds",
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LockScopeDir returns the directory holding the lock files of a manifest.
+//
+// Rules lock next to their manifests. A workspace rule uses the nearest
+// directory from manifestDir upwards that holds one of its lock files instead,
+// so every member of a pnpm, yarn or npm workspace shares the root lock file.
+// The search never leaves rootDir, so a lock file above the project (for
+// example in the home directory) is not adopted.
+//
+// It performs the following operations:
+//   - Step 1: Return manifestDir unless the rule is a workspace rule and manifestDir is inside rootDir
+//   - Step 2: Walk up from manifestDir to rootDir until a directory holds a lock file of the rule
+//   - Step 3: Fall back to manifestDir when none of them holds one
+//
+// Parameters:
+//   - ruleCfg: The manifest's rule configuration
+//   - manifestDir: Directory of the manifest file
+//   - rootDir: Working directory bounding the search; empty uses the current directory
+//
+// Returns:
+//   - string: The workspace root, or manifestDir unchanged when the lock file is next to the manifest or none exists
+func LockScopeDir(ruleCfg PackageManagerCfg, manifestDir, rootDir string) string {
+	if !ruleCfg.IsWorkspace() || len(ruleCfg.LockFiles) == 0 {
+		return manifestDir
+	}
+
+	start, err := filepath.Abs(manifestDir)
+	if err != nil {
+		return manifestDir
+	}
+	root, err := filepath.Abs(rootDir)
+	if err != nil {
+		return manifestDir
+	}
+	if rel, err := filepath.Rel(root, start); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return manifestDir
+	}
+
+	for dir := start; ; {
+		if hasLockFileIn(dir, ruleCfg.LockFiles) {
+			if dir == start {
+				return manifestDir
+			}
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if dir == root || parent == dir {
+			return manifestDir
+		}
+		dir = parent
+	}
+}
+
+// hasLockFileIn reports whether dir directly holds a file matching one of the lock file patterns.
+//
+// Only the last element of each pattern is matched, so "**/pnpm-lock.yaml"
+// looks for pnpm-lock.yaml in dir itself.
+//
+// Parameters:
+//   - dir: Directory to check
+//   - lockFiles: Lock file configurations with file patterns
+//
+// Returns:
+//   - bool: true if a lock file exists in dir
+func hasLockFileIn(dir string, lockFiles []LockFileCfg) bool {
+	for _, lockFile := range lockFiles {
+		for _, pattern := range lockFile.Files {
+			matches, err := filepath.Glob(filepath.Join(dir, filepath.Base(pattern)))
+			if err != nil {
+				continue
+			}
+			for _, match := range matches {
+				if info, statErr := os.Stat(match); statErr == nil && !info.IsDir() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// WorkspaceMembers returns the manifests of the modules listed in the rule's workspace file in dir.
+//
+// Each listed module directory contributes the file matching the last element
+// of one of the rule's include patterns, so a go.work "use ./api" entry yields
+// api/go.mod. Modules without such a file are skipped. Modules outside dir,
+// such as "use ../shared", are returned as well; detection skips them, while a
+// workspace sync must still restore their files.
+//
+// It performs the following operations:
+//   - Step 1: Return nil unless the rule has a workspace_file and dir holds it
+//   - Step 2: Parse the module directories listed in the file
+//   - Step 3: Collect the manifest of every listed module
+//
+// Parameters:
+//   - ruleCfg: The rule configuration
+//   - dir: Directory that may hold the workspace file
+//
+// Returns:
+//   - []string: Absolute manifest paths in the order the modules are listed
+//   - error: When the workspace file cannot be read or its format is unsupported
+func WorkspaceMembers(ruleCfg PackageManagerCfg, dir string) ([]string, error) {
+	if ruleCfg.WorkspaceFile == nil {
+		return nil, nil
+	}
+
+	moduleDirs, err := readWorkspaceFile(ruleCfg.WorkspaceFile, dir)
+	if err != nil || len(moduleDirs) == 0 {
+		return nil, err
+	}
+
+	var manifests []string
+	for _, moduleDir := range moduleDirs {
+		for _, pattern := range ruleCfg.Include {
+			manifest := filepath.Join(moduleDir, filepath.Base(pattern))
+			if info, statErr := os.Stat(manifest); statErr == nil && !info.IsDir() {
+				manifests = append(manifests, manifest)
+				break
+			}
+		}
+	}
+	return manifests, nil
+}
+
+// WorkspaceRoot returns the directory of the workspace file that lists manifestDir as a module.
+//
+// Like the go command, the nearest workspace file from manifestDir upwards
+// decides: a module it does not list is not part of any workspace.
+//
+// Parameters:
+//   - ruleCfg: The manifest's rule configuration
+//   - manifestDir: Directory of the manifest file
+//
+// Returns:
+//   - string: The workspace root
+//   - bool: false when the rule has no workspace_file or the module is not listed
+func WorkspaceRoot(ruleCfg PackageManagerCfg, manifestDir string) (string, bool) {
+	if ruleCfg.WorkspaceFile == nil || strings.TrimSpace(ruleCfg.WorkspaceFile.File) == "" {
+		return "", false
+	}
+
+	start, err := filepath.Abs(manifestDir)
+	if err != nil {
+		return "", false
+	}
+
+	for dir := start; ; {
+		if info, statErr := os.Stat(filepath.Join(dir, ruleCfg.WorkspaceFile.File)); statErr == nil && !info.IsDir() {
+			moduleDirs, readErr := readWorkspaceFile(ruleCfg.WorkspaceFile, dir)
+			if readErr != nil {
+				return "", false
+			}
+			for _, moduleDir := range moduleDirs {
+				if moduleDir == start {
+					return dir, true
+				}
+			}
+			return "", false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// readWorkspaceFile parses the module directories listed in a workspace file.
+//
+// Parameters:
+//   - cfg: The workspace file configuration
+//   - dir: Directory that may hold the workspace file
+//
+// Returns:
+//   - []string: Absolute, cleaned module directories; nil when dir holds no workspace file
+//   - error: When the file cannot be read or its format is unsupported
+func readWorkspaceFile(cfg *WorkspaceFileCfg, dir string) ([]string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(absDir, cfg.File)
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(cfg.Format), WorkspaceFormatGoWork) {
+		return nil, fmt.Errorf("unsupported workspace file format %q", cfg.Format)
+	}
+
+	var moduleDirs []string
+	for _, use := range parseGoWorkUses(string(content)) {
+		moduleDir := filepath.FromSlash(use)
+		if !filepath.IsAbs(moduleDir) {
+			moduleDir = filepath.Join(absDir, moduleDir)
+		}
+		moduleDirs = append(moduleDirs, filepath.Clean(moduleDir))
+	}
+	return moduleDirs, nil
+}
+
+// parseGoWorkUses returns the paths of the use directives in go.work content.
+//
+// Both the single-line form (use ./api) and the block form (use ( ... )) are
+// read; quoted paths are unquoted and // comments are ignored.
+//
+// Parameters:
+//   - content: The go.work file content
+//
+// Returns:
+//   - []string: The listed module paths, relative to the go.work directory unless absolute
+func parseGoWorkUses(content string) []string {
+	var uses []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "use (" || line == "use(":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "use "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "use "))
+		default:
+			continue
+		}
+
+		if use := strings.Trim(line, "\"`"); use != "" {
+			uses = append(uses, use)
+		}
+	}
+	return uses
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLockScopeDir tests finding the lock directory of a manifest.
+//
+// It verifies:
+//   - Rules without workspace always lock next to the manifest
+//   - Workspace members without their own lock file use the nearest parent lock file
+//   - A member with its own lock file keeps it
+//   - A workspace without any lock file falls back to the manifest directory
+//   - The search stops at the working directory and is skipped for manifests outside it
+func TestLockScopeDir(t *testing.T) {
+	root := t.TempDir()
+	member := filepath.Join(root, "packages", "app")
+	standalone := filepath.Join(root, "packages", "standalone")
+	require.NoError(t, os.MkdirAll(member, 0o755))
+	require.NoError(t, os.MkdirAll(standalone, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pnpm-lock.yaml"), []byte("lockfileVersion: '9.0'\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(standalone, "pnpm-lock.yaml"), []byte("lockfileVersion: '9.0'\n"), 0o644))
+
+	ruleCfg := PackageManagerCfg{LockFiles: []LockFileCfg{{Files: []string{"**/pnpm-lock.yaml"}}}}
+	assert.Equal(t, member, LockScopeDir(ruleCfg, member, root))
+
+	workspace := true
+	ruleCfg.Workspace = &workspace
+	assert.Equal(t, root, LockScopeDir(ruleCfg, member, root))
+	assert.Equal(t, standalone, LockScopeDir(ruleCfg, standalone, root))
+	assert.Equal(t, root, LockScopeDir(ruleCfg, root, root))
+
+	// A lock file above the working directory is not adopted
+	packages := filepath.Join(root, "packages")
+	assert.Equal(t, member, LockScopeDir(ruleCfg, member, packages))
+	assert.Equal(t, member, LockScopeDir(ruleCfg, member, member))
+	assert.Equal(t, member, LockScopeDir(ruleCfg, member, standalone))
+
+	ruleCfg.LockFiles = []LockFileCfg{{Files: []string{"**/yarn.lock"}}}
+	assert.Equal(t, member, LockScopeDir(ruleCfg, member, root))
+}
+
+// TestWorkspaceMembers tests reading the modules listed in a go.work file.
+//
+// It verifies:
+//   - Single-line and block use directives are read, with quotes and comments
+//   - Each listed module contributes its manifest, including modules outside the directory,
+//     so a workspace sync can restore them; detection skips those (see packages.DetectFiles)
+//   - Listed modules without a manifest are skipped
+//   - Rules without workspace_file and directories without the file yield no members
+//   - Unsupported formats return an error
+func TestWorkspaceMembers(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "repo")
+	for _, dir := range []string{"api", "libs/core", "empty", "../shared"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+	for _, dir := range []string{"api", "libs/core", "../shared"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte("module example.com/x\n"), 0o644))
+	}
+	goWork := "go 1.22\n\nuse ./api // service\n\nuse (\n\t\"./libs/core\"\n\t./empty\n\t../shared\n)\n\nreplace example.com/old => ./old\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.work"), []byte(goWork), 0o644))
+
+	ruleCfg := PackageManagerCfg{Include: []string{"**/go.mod"}, WorkspaceFile: &WorkspaceFileCfg{File: "go.work", Format: WorkspaceFormatGoWork}}
+	members, err := WorkspaceMembers(ruleCfg, root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "api", "go.mod"),
+		filepath.Join(root, "libs", "core", "go.mod"),
+		filepath.Join(base, "shared", "go.mod"),
+	}, members)
+
+	members, err = WorkspaceMembers(PackageManagerCfg{Include: []string{"**/go.mod"}}, root)
+	require.NoError(t, err)
+	assert.Nil(t, members)
+
+	members, err = WorkspaceMembers(ruleCfg, filepath.Join(root, "api"))
+	require.NoError(t, err)
+	assert.Nil(t, members)
+
+	ruleCfg.WorkspaceFile.Format = "pnpm"
+	_, err = WorkspaceMembers(ruleCfg, root)
+	assert.ErrorContains(t, err, "unsupported workspace file format")
+}
+
+// TestWorkspaceRoot tests finding the workspace a module belongs to.
+//
+// It verifies:
+//   - A listed module resolves to the directory of the go.work file
+//   - A module below the workspace that go.work does not list has no workspace
+//   - Rules without workspace_file and trees without go.work have no workspace
+func TestWorkspaceRoot(t *testing.T) {
+	root := t.TempDir()
+	member := filepath.Join(root, "services", "api")
+	unlisted := filepath.Join(root, "tools")
+	require.NoError(t, os.MkdirAll(member, 0o755))
+	require.NoError(t, os.MkdirAll(unlisted, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.22\n\nuse ./services/api\n"), 0o644))
+
+	ruleCfg := PackageManagerCfg{WorkspaceFile: &WorkspaceFileCfg{File: "go.work", Format: WorkspaceFormatGoWork}}
+	dir, ok := WorkspaceRoot(ruleCfg, member)
+	require.True(t, ok)
+	assert.Equal(t, root, dir)
+
+	_, ok = WorkspaceRoot(ruleCfg, unlisted)
+	assert.False(t, ok)
+
+	_, ok = WorkspaceRoot(PackageManagerCfg{}, member)
+	assert.False(t, ok)
+
+	_, ok = WorkspaceRoot(ruleCfg, t.TempDir())
+	assert.False(t, ok)
+}
@@ -24,6 +24,12 @@ const (
 
 	// StatusOutdated indicates newer versions are available for the package.
 	StatusOutdated = "Outdated"
+
+	// StatusVerified indicates a dry-run rehearsal of an update group succeeded.
+	StatusVerified = "Verified"
+
+	// StatusDeferred indicates an available update was held back by --limit for a later run.
+	StatusDeferred = "Deferred"
 )
 
 // Placeholder values for display when data is not available.
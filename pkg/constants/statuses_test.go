@@ -25,6 +25,7 @@ func TestStatusConstants(t *testing.T) {
 		{"StatusConfigError", StatusConfigError, "ConfigError"},
 		{"StatusSummarizeError", StatusSummarizeError, "SummarizeError"},
 		{"StatusOutdated", StatusOutdated, "Outdated"},
+		{"StatusDeferred", StatusDeferred, "Deferred"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,34 @@
+// Package deptree resolves the full transitive dependency tree of manifests.
+//
+// It is used by `goupdate scan --deep` to go beyond the declared packages:
+// each manifest's package manager is asked for its resolved dependency tree
+// with its own tooling (`npm ls --json`, `go mod graph`, `composer show
+// --tree`), and the result is flattened into formats.Package values whose
+// Direct flag tells declared packages from transitive ones. The resulting set
+// feeds the same consumers as the declared packages, such as SBOM export and
+// advisory lookups.
+//
+// # Configuration
+//
+// A rule opts in with a tree block naming the command and its output format:
+//
+//	tree:
+//	  commands: |
+//	    go mod graph
+//	  format: go-mod-graph
+//
+// The command runs once per manifest, in the manifest's directory. It must be
+// read-only; the built-in rules read lock files or module graphs only.
+//
+// # Resolution
+//
+// Resolve runs the tree commands on a bounded worker pool and waits on each
+// rule's request rate limit before every command, as version lookups do.
+// Manifests of rules without a tree block contribute their declared packages
+// only, and those packages are reported to the unsupported tracker:
+//
+//	pkgs, err := deptree.Resolve(ctx, cfg, detected, deptree.Options{
+//	    Concurrency: 4,
+//	    Unsupported: tracker,
+//	})
+package deptree
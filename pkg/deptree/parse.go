@@ -0,0 +1,243 @@
+package deptree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+)
+
+// treeNode is one package of a resolved dependency tree.
+//
+// Fields:
+//   - Name: Package name
+//   - Version: Resolved version; the requirement as written when Resolved is false
+//   - Direct: Whether the manifest itself requires the package
+//   - Resolved: Whether Version is the installed version rather than a requirement
+type treeNode struct {
+	Name     string
+	Version  string
+	Direct   bool
+	Resolved bool
+}
+
+// parseTree parses tree command output in the given format.
+//
+// Parameters:
+//   - format: One of the config.TreeFormat* values
+//   - output: Raw command output
+//
+// Returns:
+//   - []treeNode: Unique packages sorted by name and version
+//   - error: When the output is empty, cannot be parsed, or the format is unknown
+func parseTree(format string, output []byte) ([]treeNode, error) {
+	output = bytes.TrimPrefix(output, []byte("\xef\xbb\xbf"))
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, fmt.Errorf("tree command printed no output")
+	}
+
+	nodes := newNodeSet()
+	var err error
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case config.TreeFormatNPMLs:
+		err = parseNPMLs(output, nodes)
+	case config.TreeFormatGoModGraph:
+		err = parseGoModGraph(output, nodes)
+	case config.TreeFormatComposerTree:
+		err = parseComposerTree(output, nodes)
+	default:
+		return nil, fmt.Errorf("unsupported dependency tree format: %s (supported: %s, %s, %s)", format, config.TreeFormatNPMLs, config.TreeFormatGoModGraph, config.TreeFormatComposerTree)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nodes.sorted(), nil
+}
+
+// nodeSet collects tree nodes, merging repeated name and version pairs.
+type nodeSet map[string]*treeNode
+
+// newNodeSet creates an empty node set.
+func newNodeSet() nodeSet {
+	return make(nodeSet)
+}
+
+// add records a node; a package reached both directly and transitively stays direct.
+func (s nodeSet) add(node treeNode) {
+	key := node.Name + "@" + node.Version
+	if existing, ok := s[key]; ok {
+		existing.Direct = existing.Direct || node.Direct
+		return
+	}
+	s[key] = &node
+}
+
+// sorted returns the nodes ordered by name and version.
+func (s nodeSet) sorted() []treeNode {
+	nodes := make([]treeNode, 0, len(s))
+	for _, node := range s {
+		nodes = append(nodes, *node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Name != nodes[j].Name {
+			return nodes[i].Name < nodes[j].Name
+		}
+		return nodes[i].Version < nodes[j].Version
+	})
+	return nodes
+}
+
+// npmLsNode is a package in `npm ls --all --json` output.
+type npmLsNode struct {
+	Version      string               `json:"version"`
+	Missing      bool                 `json:"missing"`
+	Dependencies map[string]npmLsNode `json:"dependencies"`
+}
+
+// parseNPMLs parses `npm ls --all --json` output.
+//
+// The root's dependencies are direct; everything below them is transitive.
+// Missing packages and entries without a version are skipped.
+//
+// Parameters:
+//   - output: JSON printed by npm ls
+//   - nodes: Set receiving the packages
+//
+// Returns:
+//   - error: When the output is not valid JSON
+func parseNPMLs(output []byte, nodes nodeSet) error {
+	var root npmLsNode
+	if err := json.Unmarshal(output, &root); err != nil {
+		return fmt.Errorf("failed to parse npm ls JSON: %w", err)
+	}
+
+	var walk func(deps map[string]npmLsNode, direct bool)
+	walk = func(deps map[string]npmLsNode, direct bool) {
+		for name, dep := range deps {
+			if dep.Missing || strings.TrimSpace(dep.Version) == "" {
+				continue
+			}
+			nodes.add(treeNode{Name: name, Version: dep.Version, Direct: direct, Resolved: true})
+			walk(dep.Dependencies, false)
+		}
+	}
+	walk(root.Dependencies, true)
+	return nil
+}
+
+// parseGoModGraph parses `go mod graph` output.
+//
+// Each line is an edge "from to", where the main module appears without a
+// version. Edges leaving the main module are direct requirements. The graph
+// lists every required version of a module; minimal version selection builds
+// with the highest, so only that one is kept. The go and toolchain pseudo
+// modules are skipped.
+//
+// Parameters:
+//   - output: Text printed by go mod graph
+//   - nodes: Set receiving the modules
+//
+// Returns:
+//   - error: When a line is not an edge between two modules
+func parseGoModGraph(output []byte, nodes nodeSet) error {
+	selected := make(map[string]string)
+	direct := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("unexpected go mod graph line: %q", line)
+		}
+
+		path, version, ok := strings.Cut(fields[1], "@")
+		if !ok || path == "go" || path == "toolchain" {
+			continue
+		}
+		if current, seen := selected[path]; !seen || semver.Compare(version, current) > 0 {
+			selected[path] = version
+		}
+		if !strings.Contains(fields[0], "@") {
+			direct[path] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read go mod graph output: %w", err)
+	}
+
+	for path, version := range selected {
+		nodes.add(treeNode{Name: path, Version: version, Direct: direct[path], Resolved: true})
+	}
+	return nil
+}
+
+// composerTreeNode is a package in `composer show --tree --format=json` output.
+type composerTreeNode struct {
+	Name     string             `json:"name"`
+	Version  string             `json:"version"`
+	Requires []composerTreeNode `json:"requires"`
+}
+
+// parseComposerTree parses `composer show --tree --format=json` output.
+//
+// Top-level entries are the root requirements with their installed versions.
+// Nested entries carry the requirement as written by their parent, so their
+// version is taken from a top-level entry of the same name when there is one
+// and left unresolved otherwise, keeping the first requirement seen. Platform
+// packages (php, ext-*, lib-*), whose names have no vendor prefix, are skipped.
+//
+// Parameters:
+//   - output: JSON printed by composer show --tree
+//   - nodes: Set receiving the packages
+//
+// Returns:
+//   - error: When the output is not valid JSON
+func parseComposerTree(output []byte, nodes nodeSet) error {
+	var payload struct {
+		Installed []composerTreeNode `json:"installed"`
+	}
+	if err := json.Unmarshal(output, &payload); err != nil {
+		return fmt.Errorf("failed to parse composer tree JSON: %w", err)
+	}
+
+	installed := make(map[string]string, len(payload.Installed))
+	for _, root := range payload.Installed {
+		installed[root.Name] = root.Version
+	}
+
+	unresolved := make(map[string]bool)
+	var walk func(deps []composerTreeNode)
+	walk = func(deps []composerTreeNode) {
+		for _, dep := range deps {
+			if !strings.Contains(dep.Name, "/") {
+				continue
+			}
+			if version, ok := installed[dep.Name]; ok {
+				nodes.add(treeNode{Name: dep.Name, Version: version, Resolved: true})
+			} else if !unresolved[dep.Name] {
+				unresolved[dep.Name] = true
+				nodes.add(treeNode{Name: dep.Name, Version: strings.TrimSpace(dep.Version)})
+			}
+			walk(dep.Requires)
+		}
+	}
+	for _, root := range payload.Installed {
+		if !strings.Contains(root.Name, "/") {
+			continue
+		}
+		nodes.add(treeNode{Name: root.Name, Version: root.Version, Direct: true, Resolved: true})
+		walk(root.Requires)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package deptree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+)
+
+// TestParseNPMLs tests parsing `npm ls --all --json` output.
+//
+// It verifies:
+//   - The root's dependencies are direct and nested ones transitive
+//   - A package reached directly and transitively stays direct
+//   - The same package at two versions is kept twice
+//   - Missing packages are skipped
+func TestParseNPMLs(t *testing.T) {
+	output := `{
+  "name": "web",
+  "version": "1.0.0",
+  "dependencies": {
+    "react": {
+      "version": "18.2.0",
+      "dependencies": {
+        "loose-envify": {"version": "1.4.0", "dependencies": {"js-tokens": {"version": "4.0.0"}}}
+      }
+    },
+    "js-tokens": {"version": "4.0.0"},
+    "legacy": {"version": "1.0.0", "dependencies": {"js-tokens": {"version": "3.0.2"}}},
+    "left-pad": {"required": "^1.3.0", "missing": true}
+  }
+}`
+
+	nodes, err := parseTree(config.TreeFormatNPMLs, []byte(output))
+	require.NoError(t, err)
+	assert.Equal(t, []treeNode{
+		{Name: "js-tokens", Version: "3.0.2", Resolved: true},
+		{Name: "js-tokens", Version: "4.0.0", Direct: true, Resolved: true},
+		{Name: "legacy", Version: "1.0.0", Direct: true, Resolved: true},
+		{Name: "loose-envify", Version: "1.4.0", Resolved: true},
+		{Name: "react", Version: "18.2.0", Direct: true, Resolved: true},
+	}, nodes)
+}
+
+// TestParseGoModGraph tests parsing `go mod graph` output.
+//
+// It verifies:
+//   - Edges leaving the main module mark direct requirements
+//   - Only the highest version of a module is kept
+//   - The go and toolchain pseudo modules are skipped
+//   - A malformed line is an error
+func TestParseGoModGraph(t *testing.T) {
+	output := `example.com/app github.com/spf13/cobra@v1.7.0
+example.com/app go@1.24.0
+example.com/app golang.org/x/mod@v0.20.0
+github.com/spf13/cobra@v1.7.0 github.com/spf13/pflag@v1.0.5
+github.com/spf13/cobra@v1.7.0 golang.org/x/mod@v0.30.0
+github.com/spf13/pflag@v1.0.5 toolchain@go1.24.0
+`
+
+	nodes, err := parseTree(config.TreeFormatGoModGraph, []byte(output))
+	require.NoError(t, err)
+	assert.Equal(t, []treeNode{
+		{Name: "github.com/spf13/cobra", Version: "v1.7.0", Direct: true, Resolved: true},
+		{Name: "github.com/spf13/pflag", Version: "v1.0.5", Resolved: true},
+		{Name: "golang.org/x/mod", Version: "v0.30.0", Direct: true, Resolved: true},
+	}, nodes)
+
+	_, err = parseTree(config.TreeFormatGoModGraph, []byte("example.com/app\n"))
+	assert.ErrorContains(t, err, "unexpected go mod graph line")
+}
+
+// TestParseComposerTree tests parsing `composer show --tree --format=json` output.
+//
+// It verifies:
+//   - Top-level entries are direct with their installed versions
+//   - Nested entries take the installed version of a top-level entry of the same name
+//   - Other nested entries keep their first requirement and are unresolved
+//   - Platform packages are skipped
+func TestParseComposerTree(t *testing.T) {
+	output := `{"installed": [
+  {"name": "monolog/monolog", "version": "2.9.1", "requires": [
+    {"name": "php", "version": ">=7.2"},
+    {"name": "psr/log", "version": "^1.0.1 || ^2.0", "requires": [{"name": "psr/container", "version": "^1.0"}]}
+  ]},
+  {"name": "psr/log", "version": "2.0.0", "requires": []},
+  {"name": "symfony/console", "version": "v6.4.1", "requires": [{"name": "psr/container", "version": "^2.0"}]}
+]}`
+
+	nodes, err := parseTree(config.TreeFormatComposerTree, []byte(output))
+	require.NoError(t, err)
+	assert.Equal(t, []treeNode{
+		{Name: "monolog/monolog", Version: "2.9.1", Direct: true, Resolved: true},
+		{Name: "psr/container", Version: "^1.0"},
+		{Name: "psr/log", Version: "2.0.0", Direct: true, Resolved: true},
+		{Name: "symfony/console", Version: "v6.4.1", Direct: true, Resolved: true},
+	}, nodes)
+}
+
+// TestParseTreeErrors tests parseTree failures.
+//
+// It verifies:
+//   - Empty output is an error
+//   - An unknown format is an error
+//   - Invalid JSON is an error
+func TestParseTreeErrors(t *testing.T) {
+	_, err := parseTree(config.TreeFormatNPMLs, []byte("  \n"))
+	assert.ErrorContains(t, err, "no output")
+
+	_, err = parseTree("pnpm-ls", []byte("{}"))
+	assert.ErrorContains(t, err, "unsupported dependency tree format")
+
+	_, err = parseTree(config.TreeFormatComposerTree, []byte("not json"))
+	assert.ErrorContains(t, err, "composer tree JSON")
+}
@@ -0,0 +1,281 @@
+package deptree
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ajxudir/goupdate/pkg/cmdexec"
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/packages"
+	"github.com/ajxudir/goupdate/pkg/ratelimit"
+	"github.com/ajxudir/goupdate/pkg/supervision"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// UnsupportedReason is reported for the declared packages of rules without a tree command.
+const UnsupportedReason = "transitive dependencies not resolved: no tree command configured"
+
+// execTreeFunc runs a rule's tree command in dir.
+// It is a variable so tests can replace command execution.
+var execTreeFunc = func(ctx context.Context, tree *config.TreeCfg, dir string) ([]byte, error) {
+	return cmdexec.ExecuteWithContext(ctx, tree.Commands, tree.Env, dir, tree.TimeoutSeconds, nil)
+}
+
+// Options controls dependency tree resolution.
+//
+// Fields:
+//   - Concurrency: Maximum number of tree commands running at once; values below 1 run them one by one
+//   - Unsupported: Receives the declared packages of rules without a tree command; may be nil
+type Options struct {
+	Concurrency int
+	Unsupported supervision.Tracker
+}
+
+// manifestJob is one detected manifest whose tree is resolved.
+type manifestJob struct {
+	rule string
+	file string
+}
+
+// manifestResult is the outcome of resolving one manifest.
+type manifestResult struct {
+	pkgs        []formats.Package
+	unsupported []formats.Package
+	err         error
+}
+
+// Configured reports whether a rule can resolve dependency trees.
+//
+// Parameters:
+//   - ruleCfg: The rule configuration to check
+//
+// Returns:
+//   - bool: true if the rule has tree commands configured
+func Configured(ruleCfg *config.PackageManagerCfg) bool {
+	return ruleCfg != nil && ruleCfg.Tree != nil && strings.TrimSpace(ruleCfg.Tree.Commands) != ""
+}
+
+// Resolve builds the full dependency set of the detected manifests.
+//
+// It performs the following operations:
+//   - Step 1: Parse each manifest for its declared packages
+//   - Step 2: Run the rule's tree command in the manifest's directory, on up to Concurrency workers
+//   - Step 3: Flatten the tree into packages, marking the manifest's own dependencies as Direct
+//   - Step 4: Keep only the declared packages of rules without a tree command and report them as unsupported
+//
+// Every tree command waits on its rule's request rate limit first. A manifest
+// that fails does not stop the others; its error is joined into the returned
+// error and the packages of the remaining manifests are still returned.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - cfg: The global configuration
+//   - detected: Map of rule names to manifest paths, as returned by packages.DetectFiles
+//   - opts: Concurrency and unsupported tracking options
+//
+// Returns:
+//   - []formats.Package: Packages sorted by rule and manifest, direct packages first, then by name
+//   - error: The joined errors of failed manifests; nil when every manifest was resolved
+func Resolve(ctx context.Context, cfg *config.Config, detected map[string][]string, opts Options) ([]formats.Package, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("configuration is required")
+	}
+
+	jobs := manifestJobs(detected)
+	results := make([]manifestResult, len(jobs))
+	workers := make(chan struct{}, max(opts.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+			results[i] = resolveManifest(ctx, cfg, job)
+		}()
+	}
+	wg.Wait()
+
+	var pkgs []formats.Package
+	var errs error
+	for i, result := range results {
+		if result.err != nil {
+			errs = stderrors.Join(errs, fmt.Errorf("%s: %w", jobs[i].file, result.err))
+			continue
+		}
+		pkgs = append(pkgs, result.pkgs...)
+		if opts.Unsupported != nil {
+			for _, p := range result.unsupported {
+				opts.Unsupported.Add(p, UnsupportedReason)
+			}
+		}
+	}
+	return pkgs, errs
+}
+
+// manifestJobs flattens detected manifests into jobs sorted by rule and file.
+func manifestJobs(detected map[string][]string) []manifestJob {
+	var jobs []manifestJob
+	for rule, files := range detected {
+		for _, file := range files {
+			jobs = append(jobs, manifestJob{rule: rule, file: file})
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].rule != jobs[j].rule {
+			return jobs[i].rule < jobs[j].rule
+		}
+		return jobs[i].file < jobs[j].file
+	})
+	return jobs
+}
+
+// resolveManifest resolves the dependency tree of a single manifest.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - cfg: The global configuration
+//   - job: The rule and manifest to resolve
+//
+// Returns:
+//   - manifestResult: The manifest's packages, the packages to report as unsupported, or the failure
+func resolveManifest(ctx context.Context, cfg *config.Config, job manifestJob) manifestResult {
+	if err := ctx.Err(); err != nil {
+		return manifestResult{err: err}
+	}
+
+	ruleCfg, ok := cfg.Rules[job.rule]
+	if !ok {
+		return manifestResult{err: fmt.Errorf("rule %s not found", job.rule)}
+	}
+	declared, err := declaredPackages(job, &ruleCfg)
+	if err != nil {
+		return manifestResult{err: err}
+	}
+
+	if !Configured(&ruleCfg) {
+		verbose.Printf("Dependency tree: %s (%s) has no tree command, using declared packages\n", job.file, job.rule)
+		return manifestResult{pkgs: declared, unsupported: declared}
+	}
+
+	if err := ratelimit.ForRule(cfg, job.rule).Wait(ctx); err != nil {
+		return manifestResult{err: err}
+	}
+
+	treeCfg := *ruleCfg.Tree
+	if cfg.NoTimeout {
+		treeCfg.TimeoutSeconds = 0
+	}
+	verbose.Printf("Dependency tree: resolving %s (%s)\n", job.file, job.rule)
+	output, err := execTreeFunc(ctx, &treeCfg, filepath.Dir(job.file))
+	if err != nil {
+		return manifestResult{err: fmt.Errorf("failed to resolve dependency tree: %w", err)}
+	}
+	nodes, err := parseTree(treeCfg.Format, output)
+	if err != nil {
+		return manifestResult{err: err}
+	}
+
+	return manifestResult{pkgs: treePackages(job, &ruleCfg, declared, nodes)}
+}
+
+// declaredPackages parses the packages a manifest declares, marked as direct.
+//
+// Parameters:
+//   - job: The rule and manifest to parse
+//   - ruleCfg: The rule configuration
+//
+// Returns:
+//   - []formats.Package: Declared packages with rule, package type and Direct set
+//   - error: When the manifest cannot be parsed
+func declaredPackages(job manifestJob, ruleCfg *config.PackageManagerCfg) ([]formats.Package, error) {
+	list, err := packages.NewDynamicParser().ParseFile(job.file, ruleCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	declared := list.Packages
+	for i := range declared {
+		declared[i].Rule = job.rule
+		declared[i].PackageType = ruleCfg.Manager
+		declared[i].Direct = true
+	}
+	return declared, nil
+}
+
+// treePackages converts a manifest's tree nodes into packages.
+//
+// Direct nodes take the declared version, constraint, type and line of the
+// matching declaration. Declared packages missing from the tree are kept as
+// direct packages with the NotInLock status, so the set never loses a
+// declaration.
+//
+// Parameters:
+//   - job: The rule and manifest the tree belongs to
+//   - ruleCfg: The rule configuration
+//   - declared: Packages declared by the manifest
+//   - nodes: Tree nodes parsed from the tree command output
+//
+// Returns:
+//   - []formats.Package: Direct packages first, then transitive ones, each sorted by name and version
+func treePackages(job manifestJob, ruleCfg *config.PackageManagerCfg, declared []formats.Package, nodes []treeNode) []formats.Package {
+	declaredByName := make(map[string]formats.Package, len(declared))
+	for _, p := range declared {
+		if _, exists := declaredByName[p.Name]; !exists {
+			declaredByName[p.Name] = p
+		}
+	}
+
+	inTree := make(map[string]bool, len(nodes))
+	pkgs := make([]formats.Package, 0, len(nodes))
+	for _, node := range nodes {
+		p := formats.Package{
+			Name:             node.Name,
+			Version:          node.Version,
+			Rule:             job.rule,
+			PackageType:      ruleCfg.Manager,
+			Source:           job.file,
+			InstalledVersion: node.Version,
+			InstallStatus:    lock.InstallStatusLockFound,
+			Direct:           node.Direct,
+		}
+		if !node.Resolved {
+			p.InstalledVersion = ""
+			p.InstallStatus = lock.InstallStatusVersionMissing
+		}
+		if decl, ok := declaredByName[node.Name]; ok && node.Direct {
+			p.Version = decl.Version
+			p.Constraint = decl.Constraint
+			p.Type = decl.Type
+			p.Line = decl.Line
+			p.IgnoreReason = decl.IgnoreReason
+			inTree[node.Name] = true
+		}
+		pkgs = append(pkgs, p)
+	}
+
+	for _, p := range declared {
+		if !inTree[p.Name] {
+			p.InstallStatus = lock.InstallStatusNotInLock
+			pkgs = append(pkgs, p)
+		}
+	}
+
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		if pkgs[i].Direct != pkgs[j].Direct {
+			return pkgs[i].Direct
+		}
+		if pkgs[i].Name != pkgs[j].Name {
+			return pkgs[i].Name < pkgs[j].Name
+		}
+		return pkgs[i].InstalledVersion < pkgs[j].InstalledVersion
+	})
+	return pkgs
+}
@@ -0,0 +1,204 @@
+package deptree
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/supervision"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// writeManifest writes content to dir/name below root and returns its path.
+func writeManifest(t *testing.T, root, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(root, dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// stubTreeExec replaces execTreeFunc for the duration of the test.
+func stubTreeExec(t *testing.T, fn func(ctx context.Context, tree *config.TreeCfg, dir string) ([]byte, error)) {
+	t.Helper()
+	original := execTreeFunc
+	execTreeFunc = fn
+	t.Cleanup(func() { execTreeFunc = original })
+}
+
+// treeRule returns an npm rule resolving trees with npm ls.
+func treeRule() config.PackageManagerCfg {
+	rule := testutil.NPMRule()
+	rule.Tree = &config.TreeCfg{Commands: "npm ls --all --json", Format: config.TreeFormatNPMLs, TimeoutSeconds: 30}
+	return rule
+}
+
+// TestResolve tests resolving the dependency trees of detected manifests.
+//
+// It verifies:
+//   - Tree commands run in the manifest's directory and their packages are tagged with rule and source
+//   - Direct packages take the declared version, constraint and type; transitive ones the resolved version
+//   - Declared packages missing from the tree are kept with the NotInLock status
+//   - Rules without a tree command keep their declared packages and report them as unsupported
+//   - A failing manifest is reported without dropping the others
+//   - NoTimeout clears the tree command timeout
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	web := writeManifest(t, root, "web", "package.json", `{"dependencies": {"react": "^18.2.0", "left-pad": "^1.3.0"}, "devDependencies": {"jest": "~29.7.0"}}`)
+	api := writeManifest(t, root, "api", "package.json", `{"dependencies": {"express": "^4.18.0"}}`)
+	lib := writeManifest(t, root, "lib", "composer.json", `{"require": {"monolog/monolog": "^2.9"}}`)
+
+	var mu sync.Mutex
+	var dirs []string
+	stubTreeExec(t, func(_ context.Context, tree *config.TreeCfg, dir string) ([]byte, error) {
+		mu.Lock()
+		dirs = append(dirs, dir)
+		mu.Unlock()
+		assert.Zero(t, tree.TimeoutSeconds)
+		if dir == filepath.Dir(api) {
+			return nil, stderrors.New("npm ERR! missing: express")
+		}
+		return []byte(`{"dependencies": {
+  "react": {"version": "18.2.0", "dependencies": {"loose-envify": {"version": "1.4.0"}}},
+  "jest": {"version": "29.7.0"}
+}}`), nil
+	})
+
+	cfg := testutil.NewConfig().WithRule("npm", treeRule()).WithRule("composer", testutil.ComposerRule()).Build()
+	cfg.NoTimeout = true
+	tracker := supervision.NewUnsupportedTracker()
+	detected := map[string][]string{"npm": {web, api}, "composer": {lib}}
+
+	pkgs, err := Resolve(context.Background(), cfg, detected, Options{Concurrency: 2, Unsupported: tracker})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, api+": failed to resolve dependency tree: npm ERR! missing: express")
+	assert.ElementsMatch(t, []string{filepath.Dir(web), filepath.Dir(api)}, dirs)
+
+	type row struct {
+		Rule, Name, Version, Constraint, Type, Installed, Status string
+		Direct                                                   bool
+	}
+	var rows []row
+	for _, p := range pkgs {
+		rows = append(rows, row{p.Rule, p.Name, p.Version, p.Constraint, p.Type, p.InstalledVersion, p.InstallStatus, p.Direct})
+	}
+	assert.Equal(t, []row{
+		{"composer", "monolog/monolog", "2.9", "^", "prod", "", "", true},
+		{"npm", "jest", "29.7.0", "~", "dev", "29.7.0", lock.InstallStatusLockFound, true},
+		{"npm", "left-pad", "1.3.0", "^", "prod", "", lock.InstallStatusNotInLock, true},
+		{"npm", "react", "18.2.0", "^", "prod", "18.2.0", lock.InstallStatusLockFound, true},
+		{"npm", "loose-envify", "1.4.0", "", "", "1.4.0", lock.InstallStatusLockFound, false},
+	}, rows)
+	for _, p := range pkgs {
+		assert.Equal(t, p.Rule == "npm", p.Source == web, p.Name)
+	}
+	assert.Equal(t, "js", pkgs[1].PackageType)
+
+	messages := tracker.Messages()
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "composer")
+	assert.Contains(t, messages[0], UnsupportedReason)
+}
+
+// TestResolveConcurrencyAndRateLimit tests the resource limits of Resolve.
+//
+// It verifies:
+//   - No more than Concurrency tree commands run at once
+//   - A rule's request rate limit is waited on before each tree command
+//   - A cancelled context stops resolution with the context error
+func TestResolveConcurrencyAndRateLimit(t *testing.T) {
+	root := t.TempDir()
+	var manifests []string
+	for i := 0; i < 6; i++ {
+		manifests = append(manifests, writeManifest(t, root, fmt.Sprintf("app%d", i), "package.json", `{"dependencies": {"react": "^18.2.0"}}`))
+	}
+
+	var mu sync.Mutex
+	running, peak, calls := 0, 0, 0
+	stubTreeExec(t, func(context.Context, *config.TreeCfg, string) ([]byte, error) {
+		mu.Lock()
+		running++
+		calls++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return []byte(`{"dependencies": {"react": {"version": "18.2.0"}}}`), nil
+	})
+
+	cfg := testutil.NewConfig().WithRule("npm", treeRule()).Build()
+	pkgs, err := Resolve(context.Background(), cfg, map[string][]string{"npm": manifests}, Options{Concurrency: 2})
+	require.NoError(t, err)
+	assert.Len(t, pkgs, 6)
+	assert.Equal(t, 6, calls)
+	assert.LessOrEqual(t, peak, 2)
+
+	limited := treeRule()
+	rate := 0.001
+	limited.MaxRequestsPerSecond = &rate
+	cfg = testutil.NewConfig().WithRule("npm-deptree-limited", limited).Build()
+	detected := map[string][]string{"npm-deptree-limited": manifests[:1]}
+	_, err = Resolve(context.Background(), cfg, detected, Options{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	calls = 0
+	_, err = Resolve(ctx, cfg, detected, Options{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, calls)
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	_, err = Resolve(cancelled, cfg, map[string][]string{"npm": manifests[:1]}, Options{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// TestConfigured tests which rules can resolve dependency trees.
+//
+// It verifies:
+//   - A rule needs tree commands
+//   - The built-in npm, composer and mod rules resolve trees; pnpm and yarn do not
+func TestConfigured(t *testing.T) {
+	assert.False(t, Configured(nil))
+	assert.False(t, Configured(&config.PackageManagerCfg{}))
+	assert.False(t, Configured(&config.PackageManagerCfg{Tree: &config.TreeCfg{Format: config.TreeFormatNPMLs}}))
+
+	cfg, err := config.LoadConfig("", t.TempDir())
+	require.NoError(t, err)
+	for rule, want := range map[string]bool{"npm": true, "composer": true, "mod": true, "pnpm": false, "yarn": false, "requirements": false} {
+		ruleCfg, ok := cfg.Rules[rule]
+		if !ok {
+			continue
+		}
+		assert.Equal(t, want, Configured(&ruleCfg), rule)
+	}
+}
+
+// TestResolveRequiresConfig tests Resolve without a configuration.
+//
+// It verifies:
+//   - A nil configuration is an error
+//   - No manifests resolve to no packages
+func TestResolveRequiresConfig(t *testing.T) {
+	_, err := Resolve(context.Background(), nil, nil, Options{})
+	assert.Error(t, err)
+
+	pkgs, err := Resolve(context.Background(), &config.Config{}, nil, Options{})
+	require.NoError(t, err)
+	assert.Empty(t, pkgs)
+	assert.IsType(t, []formats.Package(nil), pkgs)
+}
@@ -0,0 +1,231 @@
+package display
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+)
+
+// ColorMode controls whether display output uses status icons.
+//
+// It implements the pflag.Value interface so it can be bound directly to a
+// command-line flag; invalid values are rejected when flags are parsed.
+type ColorMode string
+
+// Color modes accepted by the --color flag.
+const (
+	// ColorAuto enables icons only when stdout is a terminal.
+	ColorAuto ColorMode = "auto"
+
+	// ColorAlways always enables icons.
+	ColorAlways ColorMode = "always"
+
+	// ColorNever disables icons and prints plain text labels.
+	ColorNever ColorMode = "never"
+)
+
+// StatusStyle selects how status icons are rendered.
+//
+// Like ColorMode it implements the pflag.Value interface. StatusStyleAuto
+// leaves the decision to the color mode: emoji when icons are enabled, plain
+// labels otherwise.
+type StatusStyle string
+
+// Status styles accepted by the --status-style flag.
+const (
+	// StatusStyleAuto follows --color: emoji on a terminal, plain labels otherwise.
+	StatusStyleAuto StatusStyle = "auto"
+
+	// StatusStyleEmoji always prefixes statuses with emoji icons (e.g. "🟢 Updated").
+	StatusStyleEmoji StatusStyle = "emoji"
+
+	// StatusStyleASCII prefixes statuses with ASCII markers (e.g. "[+] Updated").
+	StatusStyleASCII StatusStyle = "ascii"
+
+	// StatusStyleText prints plain labels (e.g. "Updated").
+	StatusStyleText StatusStyle = "text"
+)
+
+// asciiIcons maps each status icon to the marker used by StatusStyleASCII.
+var asciiIcons = map[string]string{
+	constants.IconSuccess:       "[+]",
+	constants.IconPending:       "[~]",
+	constants.IconError:         "[x]",
+	constants.IconWarning:       "[!]",
+	constants.IconInfo:          "[i]",
+	constants.IconNotConfigured: "[ ]",
+	constants.IconBlocked:       "[#]",
+	constants.IconPinned:        "[=]",
+	constants.IconIgnored:       "[-]",
+	constants.IconWarn:          "[!]",
+}
+
+// colorEnabled is the resolved color setting used by the formatting functions.
+// It defaults to enabled until SetColorMode is called.
+var colorEnabled = true
+
+// asciiEnabled replaces emoji icons with ASCII markers when icons are enabled.
+var asciiEnabled = false
+
+// isTerminalFunc reports whether stdout is a terminal (overridable for tests).
+var isTerminalFunc = func() bool {
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// String returns the mode name.
+//
+// Returns:
+//   - string: the mode name, "auto" when unset
+func (m *ColorMode) String() string {
+	if m == nil || *m == "" {
+		return string(ColorAuto)
+	}
+	return string(*m)
+}
+
+// Set parses and stores a mode name.
+//
+// Parameters:
+//   - value: "auto", "always", or "never" (case-insensitive)
+//
+// Returns:
+//   - error: when value is not a known mode
+func (m *ColorMode) Set(value string) error {
+	switch mode := ColorMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case ColorAuto, ColorAlways, ColorNever:
+		*m = mode
+		return nil
+	default:
+		return fmt.Errorf("invalid color mode %q: must be auto, always, or never", value)
+	}
+}
+
+// Type returns the flag type name shown in help output.
+//
+// Returns:
+//   - string: "mode"
+func (m *ColorMode) Type() string {
+	return "mode"
+}
+
+// SetColorMode resolves a color mode and applies it to all display output.
+//
+// The NO_COLOR environment variable (any non-empty value) overrides the mode
+// to never. Auto enables icons only when stdout is a terminal.
+//
+// Parameters:
+//   - mode: the requested mode; empty is treated as auto
+func SetColorMode(mode ColorMode) {
+	switch {
+	case os.Getenv("NO_COLOR") != "":
+		colorEnabled = false
+	case mode == ColorAlways:
+		colorEnabled = true
+	case mode == ColorNever:
+		colorEnabled = false
+	default:
+		colorEnabled = isTerminalFunc()
+	}
+}
+
+// ColorEnabled reports whether icons are enabled for display output.
+//
+// Returns:
+//   - bool: true if icons should be printed
+func ColorEnabled() bool {
+	return colorEnabled
+}
+
+// String returns the style name.
+//
+// Returns:
+//   - string: the style name, "auto" when unset
+func (s *StatusStyle) String() string {
+	if s == nil || *s == "" {
+		return string(StatusStyleAuto)
+	}
+	return string(*s)
+}
+
+// Set parses and stores a style name.
+//
+// Parameters:
+//   - value: "auto", "emoji", "ascii", or "text" (case-insensitive)
+//
+// Returns:
+//   - error: when value is not a known style
+func (s *StatusStyle) Set(value string) error {
+	switch style := StatusStyle(strings.ToLower(strings.TrimSpace(value))); style {
+	case StatusStyleAuto, StatusStyleEmoji, StatusStyleASCII, StatusStyleText:
+		*s = style
+		return nil
+	default:
+		return fmt.Errorf("invalid status style %q: must be auto, emoji, ascii, or text", value)
+	}
+}
+
+// Type returns the flag type name shown in help output.
+//
+// Returns:
+//   - string: "style"
+func (s *StatusStyle) Type() string {
+	return "style"
+}
+
+// SetStatusStyle applies a status style to all display output.
+//
+// Call it after SetColorMode: an explicit style overrides the color mode
+// (including NO_COLOR, since ASCII markers and plain labels carry no color),
+// while auto keeps the icons SetColorMode resolved and renders them as emoji.
+//
+// Parameters:
+//   - style: the requested style; empty is treated as auto
+func SetStatusStyle(style StatusStyle) {
+	asciiEnabled = false
+	switch style {
+	case StatusStyleEmoji:
+		colorEnabled = true
+	case StatusStyleASCII:
+		colorEnabled = true
+		asciiEnabled = true
+	case StatusStyleText:
+		colorEnabled = false
+	}
+}
+
+// styledIcon returns icon in the active status style.
+//
+// Parameters:
+//   - icon: an emoji icon from the constants package
+//
+// Returns:
+//   - string: the ASCII marker for icon in ASCII style, otherwise icon
+func styledIcon(icon string) string {
+	if asciiEnabled {
+		if marker, ok := asciiIcons[icon]; ok {
+			return marker
+		}
+	}
+	return icon
+}
+
+// withIcon prefixes text with an icon when color is enabled.
+//
+// Parameters:
+//   - icon: the icon to prefix
+//   - text: the label text
+//
+// Returns:
+//   - string: "icon text" in the active status style when color is enabled, otherwise text
+func withIcon(icon, text string) string {
+	if !colorEnabled || icon == "" {
+		return text
+	}
+	return styledIcon(icon) + " " + text
+}
@@ -26,7 +26,8 @@ import (
 func FormatConstraintDisplay(p formats.Package) string {
 	display, ok, warn := utils.GetConstraintDisplay(p.Constraint)
 	if warn {
-		warnings.Warnf("⚠️ %s (%s/%s): Unmapped constraint '%s', falling back to exact to be safe\n", p.Name, p.PackageType, p.Rule, p.Constraint)
+		warnings.Warn(warnings.CodeUnmappedConstraint, warnings.PackageRef(p.Name, p.PackageType, p.Rule),
+			"⚠️ %s (%s/%s): Unmapped constraint '%s', falling back to exact to be safe", p.Name, p.PackageType, p.Rule, p.Constraint)
 	}
 
 	if !ok {
@@ -2,11 +2,16 @@ package display
 
 import (
 	"bytes"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/warnings"
 )
 
 // TestPrintUnsupportedMessages tests the PrintUnsupportedMessages function.
@@ -173,6 +178,47 @@ func TestPrintSummary(t *testing.T) {
 	})
 }
 
+// TestPrintTotals tests the update totals footer.
+//
+// It verifies that:
+//   - All four counts are printed after a blank line
+//   - Dry runs label updated packages as planned
+//   - The icon follows Passed when color is enabled and is omitted when disabled
+//   - A severity mapping adds the warned count and a severity line
+func TestPrintTotals(t *testing.T) {
+	t.Run("plain", func(t *testing.T) {
+		setColorEnabledForTest(t, false)
+		var buf bytes.Buffer
+		PrintTotals(&buf, Totals{Updated: 3, UpToDate: 12, Failed: 1, Unsupported: 2})
+		assert.Equal(t, "\nUpdated: 3, Up-to-date: 12, Failed: 1, Unsupported: 2\n", buf.String())
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		setColorEnabledForTest(t, false)
+		var buf bytes.Buffer
+		PrintTotals(&buf, Totals{Updated: 2, DryRun: true, Passed: true})
+		assert.Contains(t, buf.String(), "Planned: 2, Up-to-date: 0")
+	})
+
+	t.Run("icon follows passed", func(t *testing.T) {
+		setColorEnabledForTest(t, true)
+		var buf bytes.Buffer
+		PrintTotals(&buf, Totals{Updated: 1, Passed: true})
+		assert.Contains(t, buf.String(), IconSuccess+" Updated: 1")
+
+		buf.Reset()
+		PrintTotals(&buf, Totals{Updated: 1, Failed: 1})
+		assert.Contains(t, buf.String(), IconError+" Updated: 1")
+	})
+
+	t.Run("severity mapping", func(t *testing.T) {
+		setColorEnabledForTest(t, false)
+		var buf bytes.Buffer
+		PrintTotals(&buf, Totals{Updated: 1, Warned: 1, Passed: true, Severity: []string{"Failed=error", "npm:Failed=warn"}})
+		assert.Equal(t, "\nUpdated: 1, Up-to-date: 0, Failed: 0, Unsupported: 0, Warned: 1\nSeverity: Failed=error, npm:Failed=warn\n", buf.String())
+	})
+}
+
 // TestNewWarningCollector tests the NewWarningCollector function.
 //
 // It verifies that:
@@ -189,6 +235,47 @@ func TestNewWarningCollector(t *testing.T) {
 	assert.Equal(t, "test warning", collector.Messages()[0])
 }
 
+// TestWarningCollectorStructured tests collecting classified warnings.
+//
+// It verifies that:
+//   - Warnings written through warnings.Warn keep their code and package reference
+//   - Plain-text writes are classified as GENERAL
+//   - Messages still lists every warning as text
+//   - Reset clears classified warnings too
+func TestWarningCollectorStructured(t *testing.T) {
+	collector := NewWarningCollector()
+	restore := warnings.SetWarningWriter(collector)
+	t.Cleanup(restore)
+
+	warnings.Warn(warnings.CodeLockMissing, "react (js/npm)", "no lock file for %s\n", "npm")
+	warnings.Warnf("plain warning\n")
+
+	assert.Equal(t, []warnings.Warning{
+		{Code: warnings.CodeLockMissing, Message: "no lock file for npm", PackageRef: "react (js/npm)"},
+		{Code: warnings.CodeGeneral, Message: "plain warning"},
+	}, collector.Warnings())
+	assert.Equal(t, []string{"no lock file for npm", "plain warning"}, collector.Messages())
+
+	collector.Reset()
+	assert.Empty(t, collector.Warnings())
+}
+
+// TestPrintStructuredWarnings tests the PrintStructuredWarnings function.
+//
+// It verifies that:
+//   - Empty warnings produce no output
+//   - Each warning is printed with the icon and its code in brackets
+func TestPrintStructuredWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	PrintStructuredWarnings(&buf, nil)
+	assert.Empty(t, buf.String())
+
+	PrintStructuredWarnings(&buf, []warnings.Warning{{Code: warnings.CodeFloatingConstraint, Message: "Floating constraint '5.*'"}})
+	assert.Contains(t, buf.String(), "[FLOATING_CONSTRAINT] Floating constraint '5.*'")
+	assert.Contains(t, buf.String(), constants.IconWarn)
+	assert.True(t, strings.HasPrefix(buf.String(), "\n"), "Should start with blank line")
+}
+
 // TestPrintNoPackagesMessage tests the PrintNoPackagesMessage function.
 //
 // It verifies that:
@@ -339,6 +426,36 @@ func TestSafeVersionValue(t *testing.T) {
 	assert.Equal(t, "1.2.3", SafeVersionValue("1.2.3", "default"))
 }
 
+// TestFormatAge tests the FormatAge function.
+//
+// It verifies that:
+//   - Ages under a day and negative ages show as "<1d"
+//   - Days, months, and years are chosen by magnitude
+func TestFormatAge(t *testing.T) {
+	day := 24 * time.Hour
+	assert.Equal(t, "<1d", FormatAge(-time.Hour))
+	assert.Equal(t, "<1d", FormatAge(5*time.Hour))
+	assert.Equal(t, "45d", FormatAge(45*day))
+	assert.Equal(t, "13mo", FormatAge(400*day))
+	assert.Equal(t, "3y", FormatAge(1100*day))
+}
+
+// TestFormatSource tests the FormatSource function.
+//
+// It verifies that:
+//   - Paths inside the base directory are shown relative to it, with the line when known
+//   - Paths outside the base directory and an empty base directory keep the path
+//   - An empty source formats as ""
+func TestFormatSource(t *testing.T) {
+	base := t.TempDir()
+	assert.Equal(t, "web/package.json:12", FormatSource(filepath.Join(base, "web", "package.json"), 12, base))
+	assert.Equal(t, "go.mod", FormatSource(filepath.Join(base, "go.mod"), 0, base))
+	outside := filepath.Join(filepath.Dir(base), "other", "go.mod")
+	assert.Equal(t, filepath.ToSlash(outside)+":3", FormatSource(outside, 3, base))
+	assert.Equal(t, "go.mod:1", FormatSource("go.mod", 1, ""))
+	assert.Empty(t, FormatSource("", 5, base))
+}
+
 // TestFormatStatus tests the FormatStatus function.
 //
 // It verifies that status strings are formatted with appropriate icons.
@@ -963,12 +1080,14 @@ func TestFormatStatusAllBranches(t *testing.T) {
 		{constants.StatusUpdated, constants.IconSuccess},
 		{constants.StatusPlanned, constants.IconPending},
 		{constants.StatusUpToDate, constants.IconSuccess},
+		{constants.StatusVerified, constants.IconSuccess},
 		{constants.StatusFailed, constants.IconError},
 		{constants.StatusOutdated, constants.IconWarning},
 		{constants.StatusConfigError, constants.IconError},
 		{constants.StatusSummarizeError, constants.IconError},
 		{"NotConfigured", constants.IconNotConfigured},
 		{"Floating", constants.IconBlocked},
+		{"NonRegistry", constants.IconBlocked},
 		{"unknown", ""},
 	}
 
@@ -996,6 +1115,7 @@ func TestStatusIconAllBranches(t *testing.T) {
 	}{
 		{constants.StatusUpdated, constants.IconSuccess},
 		{constants.StatusUpToDate, constants.IconSuccess},
+		{constants.StatusVerified, constants.IconSuccess},
 		{constants.StatusPlanned, constants.IconPending},
 		{constants.StatusFailed, constants.IconError},
 		{constants.StatusConfigError, constants.IconError},
@@ -1003,6 +1123,7 @@ func TestStatusIconAllBranches(t *testing.T) {
 		{constants.StatusOutdated, constants.IconWarning},
 		{"NotConfigured", constants.IconNotConfigured},
 		{"Floating", constants.IconBlocked},
+		{"NonRegistry", constants.IconBlocked},
 		{"unknown", ""},
 	}
 
@@ -1033,6 +1154,7 @@ func TestIsPendingStatus(t *testing.T) {
 //   - NotInLock returns info icon
 //   - LockMissing returns warning icon
 //   - Floating returns blocked icon
+//   - NonRegistry returns blocked icon
 //   - NotConfigured returns not-configured icon
 //   - VersionMissing returns error icon
 //   - SelfPinned returns pinned icon
@@ -1059,6 +1181,11 @@ func TestFormatInstallStatus(t *testing.T) {
 		assert.Contains(t, result, constants.IconBlocked)
 	})
 
+	t.Run("NonRegistry", func(t *testing.T) {
+		result := FormatInstallStatus("NonRegistry")
+		assert.Contains(t, result, constants.IconBlocked)
+	})
+
 	t.Run("NotConfigured", func(t *testing.T) {
 		result := FormatInstallStatus("NotConfigured")
 		assert.Contains(t, result, constants.IconNotConfigured)
@@ -1279,3 +1406,161 @@ func TestFormatVersion(t *testing.T) {
 		assert.Equal(t, "", result)
 	})
 }
+
+// setColorEnabledForTest sets the resolved color setting and restores it after the test.
+func setColorEnabledForTest(t *testing.T, enabled bool) {
+	t.Helper()
+	original := colorEnabled
+	t.Cleanup(func() { colorEnabled = original })
+	colorEnabled = enabled
+}
+
+// TestColorModeSet tests the ColorMode flag value.
+//
+// It verifies that:
+//   - auto, always, and never are accepted case-insensitively
+//   - Unknown modes are rejected
+//   - An unset mode reports auto
+func TestColorModeSet(t *testing.T) {
+	var mode ColorMode
+	assert.Equal(t, "auto", mode.String())
+	assert.Equal(t, "mode", mode.Type())
+
+	for _, value := range []string{"auto", "Always", "NEVER"} {
+		assert.NoError(t, mode.Set(value))
+	}
+	assert.Equal(t, ColorNever, mode)
+
+	assert.Error(t, mode.Set("sometimes"))
+	assert.Equal(t, ColorNever, mode)
+}
+
+// TestSetColorMode tests how color modes are resolved.
+//
+// It verifies that:
+//   - always and never force the setting regardless of the terminal
+//   - auto follows whether stdout is a terminal
+//   - NO_COLOR overrides every mode to never
+func TestSetColorMode(t *testing.T) {
+	setColorEnabledForTest(t, true)
+	originalTerminal := isTerminalFunc
+	t.Cleanup(func() { isTerminalFunc = originalTerminal })
+	t.Setenv("NO_COLOR", "")
+
+	isTerminalFunc = func() bool { return false }
+	SetColorMode(ColorAlways)
+	assert.True(t, ColorEnabled())
+	SetColorMode(ColorAuto)
+	assert.False(t, ColorEnabled())
+
+	isTerminalFunc = func() bool { return true }
+	SetColorMode(ColorAuto)
+	assert.True(t, ColorEnabled())
+	SetColorMode(ColorNever)
+	assert.False(t, ColorEnabled())
+
+	t.Setenv("NO_COLOR", "1")
+	SetColorMode(ColorAlways)
+	assert.False(t, ColorEnabled())
+}
+
+// TestStatusStyleSet tests the StatusStyle flag value.
+//
+// It verifies that:
+//   - auto, emoji, ascii, and text are accepted case-insensitively
+//   - Unknown styles are rejected and leave the value unchanged
+func TestStatusStyleSet(t *testing.T) {
+	var style StatusStyle
+	assert.Equal(t, "auto", style.String())
+	assert.Equal(t, "style", style.Type())
+
+	for _, value := range []string{"auto", "Emoji", "TEXT", "ascii"} {
+		assert.NoError(t, style.Set(value))
+	}
+	assert.Equal(t, StatusStyleASCII, style)
+
+	assert.Error(t, style.Set("fancy"))
+	assert.Equal(t, StatusStyleASCII, style)
+}
+
+// TestSetStatusStyle tests rendering statuses in each style.
+//
+// It verifies that:
+//   - Every update and lock status has an icon in emoji and ASCII style
+//   - Text style prints plain labels, also for warnings
+//   - Auto keeps the color mode's decision
+func TestSetStatusStyle(t *testing.T) {
+	setColorEnabledForTest(t, true)
+	t.Cleanup(func() { SetStatusStyle(StatusStyleAuto) })
+
+	statuses := []string{
+		constants.StatusUpdated, constants.StatusFailed, constants.StatusUpToDate, constants.StatusPlanned,
+		constants.StatusConfigError, constants.StatusSummarizeError, constants.StatusOutdated,
+		lock.InstallStatusLockFound, lock.InstallStatusNotInLock, lock.InstallStatusLockMissing,
+		lock.InstallStatusFloating, lock.InstallStatusNonRegistry, lock.InstallStatusNotConfigured,
+		lock.InstallStatusVersionMissing, lock.InstallStatusSelfPinned, lock.InstallStatusIgnored,
+	}
+
+	SetStatusStyle(StatusStyleEmoji)
+	assert.Equal(t, "🟢 Updated", FormatStatus(constants.StatusUpdated))
+	for _, status := range statuses {
+		assert.NotEqual(t, status, FormatStatus(status), status)
+	}
+
+	SetStatusStyle(StatusStyleASCII)
+	assert.Equal(t, "[+] Updated", FormatStatus(constants.StatusUpdated))
+	assert.Equal(t, "[x] Failed", FormatStatus(constants.StatusFailed))
+	assert.Equal(t, "[~] Planned", FormatStatus(constants.StatusPlanned))
+	assert.Equal(t, "[x] Failed(2)", FormatStatusWithIcon("Failed(2)"))
+	assert.Equal(t, "[+]", StatusIcon(constants.StatusUpToDate))
+	for _, status := range statuses {
+		formatted := FormatStatus(status)
+		assert.True(t, strings.HasPrefix(formatted, "["), formatted)
+		assert.True(t, strings.HasSuffix(formatted, "] "+status), formatted)
+	}
+	var buf bytes.Buffer
+	PrintWarningsInline(&buf, []string{"missing lock"})
+	assert.Equal(t, "[!] missing lock\n", buf.String())
+
+	SetStatusStyle(StatusStyleText)
+	for _, status := range statuses {
+		assert.Equal(t, status, FormatStatus(status))
+	}
+	buf.Reset()
+	PrintWarningsInline(&buf, []string{"missing lock"})
+	assert.Equal(t, "Warning: missing lock\n", buf.String())
+
+	colorEnabled = true
+	SetStatusStyle(StatusStyleAuto)
+	assert.Equal(t, "🟢 Updated", FormatStatus(constants.StatusUpdated))
+	colorEnabled = false
+	SetStatusStyle(StatusStyleAuto)
+	assert.Equal(t, "Updated", FormatStatus(constants.StatusUpdated))
+}
+
+// TestDisplayWithoutColor tests display output when color is disabled.
+//
+// It verifies that:
+//   - FormatStatus, FormatStatusWithIcon, and FormatInstallStatus return plain labels
+//   - StatusIcon returns no icon
+//   - PrintWarnings and PrintWarningsInline use a plain "Warning:" prefix
+//   - PrintNoPackagesMessage output is unchanged
+func TestDisplayWithoutColor(t *testing.T) {
+	setColorEnabledForTest(t, false)
+
+	assert.Equal(t, "Updated", FormatStatus(constants.StatusUpdated))
+	assert.Equal(t, "Failed", FormatStatus(constants.StatusFailed))
+	assert.Equal(t, "Failed(1)", FormatStatusWithIcon("Failed(1)"))
+	assert.Equal(t, "LockFound", FormatInstallStatus("LockFound"))
+	assert.Empty(t, StatusIcon(constants.StatusUpdated))
+
+	var buf bytes.Buffer
+	PrintWarnings(&buf, []string{"floating constraint"})
+	PrintWarningsInline(&buf, []string{"missing lock"})
+	assert.Equal(t, "\nWarning: floating constraint\nWarning: missing lock\n", buf.String())
+	assert.NotContains(t, buf.String(), constants.IconWarn)
+
+	buf.Reset()
+	PrintNoPackagesMessage(&buf, "")
+	assert.Equal(t, "No packages found\n", buf.String())
+}
@@ -0,0 +1,195 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/filtering"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// GroupBy selects how package output is split into sections.
+//
+// It implements the pflag.Value interface so it can be bound directly to the
+// --group-by flag; invalid values are rejected when flags are parsed.
+type GroupBy string
+
+// Section keys accepted by the --group-by flag.
+const (
+	// GroupByNone prints a single flat table (the default).
+	GroupByNone GroupBy = "none"
+
+	// GroupByRule creates one section per rule (npm, mod, ...).
+	GroupByRule GroupBy = "rule"
+
+	// GroupByType creates one section per dependency type (prod, dev).
+	GroupByType GroupBy = "type"
+
+	// GroupByGroup creates one section per package group.
+	GroupByGroup GroupBy = "group"
+)
+
+// UngroupedSection is the section name for packages without a section key,
+// such as packages that belong to no group. It is always listed last.
+const UngroupedSection = "ungrouped"
+
+// PackageSection is a named slice of packages sharing a section key.
+//
+// Fields:
+//   - Key: The section name (rule, type, or group name)
+//   - Packages: Packages in the section, in display order
+type PackageSection struct {
+	Key      string
+	Packages []formats.Package
+}
+
+// String returns the grouping name.
+//
+// Returns:
+//   - string: the grouping name, "none" when unset
+func (g *GroupBy) String() string {
+	if g == nil || *g == "" {
+		return string(GroupByNone)
+	}
+	return string(*g)
+}
+
+// Set parses and stores a grouping name.
+//
+// Parameters:
+//   - value: "rule", "type", "group", or "none" (case-insensitive)
+//
+// Returns:
+//   - error: when value is not a known grouping
+func (g *GroupBy) Set(value string) error {
+	switch by := GroupBy(strings.ToLower(strings.TrimSpace(value))); by {
+	case GroupByNone, GroupByRule, GroupByType, GroupByGroup:
+		*g = by
+		return nil
+	default:
+		return fmt.Errorf("invalid group-by %q: must be rule, type, group, or none", value)
+	}
+}
+
+// Type returns the flag type name shown in help output.
+//
+// Returns:
+//   - string: "key"
+func (g *GroupBy) Type() string {
+	return "key"
+}
+
+// Enabled reports whether output should be split into sections.
+//
+// Returns:
+//   - bool: false for "none" and the zero value
+func (g GroupBy) Enabled() bool {
+	return g != "" && g != GroupByNone
+}
+
+// SectionKey returns the section a package belongs to.
+//
+// Parameters:
+//   - p: The package
+//   - by: The grouping; GroupByNone returns an empty key
+//
+// Returns:
+//   - string: The rule, type, or group of the package, or UngroupedSection when empty
+func SectionKey(p formats.Package, by GroupBy) string {
+	var key string
+	switch by {
+	case GroupByRule:
+		key = p.Rule
+	case GroupByType:
+		key = p.Type
+	case GroupByGroup:
+		key = p.Group
+	default:
+		return ""
+	}
+
+	if strings.TrimSpace(key) == "" {
+		return UngroupedSection
+	}
+	return key
+}
+
+// GroupPackages splits packages into sections for display.
+//
+// Sections are ordered by key with UngroupedSection last, and packages within
+// each section keep the order of filtering.SortPackagesForDisplay. Without a
+// grouping, a single section with an empty key holds every package.
+//
+// Parameters:
+//   - pkgs: Packages to group
+//   - by: The grouping
+//
+// Returns:
+//   - []PackageSection: Non-empty sections in display order
+//
+// Example:
+//
+//	for _, section := range display.GroupPackages(pkgs, display.GroupByRule) {
+//	    fmt.Println(display.SectionTitle(display.GroupByRule, section.Key, len(section.Packages)))
+//	}
+func GroupPackages(pkgs []formats.Package, by GroupBy) []PackageSection {
+	sorted := filtering.SortPackagesForDisplay(pkgs)
+	if !by.Enabled() {
+		return []PackageSection{{Packages: sorted}}
+	}
+
+	index := make(map[string]int)
+	var sections []PackageSection
+	for _, p := range sorted {
+		key := SectionKey(p, by)
+		i, ok := index[key]
+		if !ok {
+			i = len(sections)
+			index[key] = i
+			sections = append(sections, PackageSection{Key: key})
+		}
+		sections[i].Packages = append(sections[i].Packages, p)
+	}
+
+	sort.SliceStable(sections, func(i, j int) bool {
+		if (sections[i].Key == UngroupedSection) != (sections[j].Key == UngroupedSection) {
+			return sections[j].Key == UngroupedSection
+		}
+		return strings.ToLower(sections[i].Key) < strings.ToLower(sections[j].Key)
+	})
+
+	return sections
+}
+
+// FlattenSections returns the packages of all sections in section order.
+//
+// Parameters:
+//   - sections: Sections from GroupPackages
+//
+// Returns:
+//   - []formats.Package: All packages, section by section
+func FlattenSections(sections []PackageSection) []formats.Package {
+	var pkgs []formats.Package
+	for _, section := range sections {
+		pkgs = append(pkgs, section.Packages...)
+	}
+	return pkgs
+}
+
+// SectionTitle returns the subheader printed above a section table.
+//
+// Parameters:
+//   - by: The grouping
+//   - key: The section key
+//   - count: Number of packages in the section
+//
+// Returns:
+//   - string: Subheader such as "rule: npm (12 packages)"
+func SectionTitle(by GroupBy, key string, count int) string {
+	noun := "packages"
+	if count == 1 {
+		noun = "package"
+	}
+	return fmt.Sprintf("%s: %s (%d %s)", by, key, count, noun)
+}
@@ -0,0 +1,79 @@
+package display
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestGroupBySet tests the GroupBy flag value.
+//
+// It verifies that:
+//   - rule, type, group, and none are accepted case-insensitively
+//   - Unknown keys are rejected
+//   - An unset value reports none and is not enabled
+func TestGroupBySet(t *testing.T) {
+	var by GroupBy
+	assert.Equal(t, "none", by.String())
+	assert.Equal(t, "key", by.Type())
+	assert.False(t, by.Enabled())
+
+	for _, value := range []string{"rule", "TYPE", " group ", "none"} {
+		assert.NoError(t, by.Set(value))
+	}
+	assert.Equal(t, GroupByNone, by)
+	assert.False(t, by.Enabled())
+
+	require.NoError(t, by.Set("Rule"))
+	assert.True(t, by.Enabled())
+
+	assert.Error(t, by.Set("manager"))
+	assert.Equal(t, GroupByRule, by)
+}
+
+// TestGroupPackages tests splitting packages into display sections.
+//
+// It verifies that:
+//   - Sections are keyed by rule, type, or group and ordered by key
+//   - Packages without a key are collected in a trailing "ungrouped" section
+//   - Packages keep display order within each section
+//   - Without a grouping, a single unnamed section holds every package
+func TestGroupPackages(t *testing.T) {
+	pkgs := []formats.Package{
+		{Rule: "npm", Type: "prod", Name: "react", Group: "ui"},
+		{Rule: "mod", Type: "prod", Name: "github.com/spf13/cobra"},
+		{Rule: "npm", Type: "dev", Name: "jest"},
+		{Rule: "npm", Type: "prod", Name: "axios", Group: "http"},
+	}
+
+	byRule := GroupPackages(pkgs, GroupByRule)
+	require.Len(t, byRule, 2)
+	assert.Equal(t, "mod", byRule[0].Key)
+	assert.Equal(t, "npm", byRule[1].Key)
+	require.Len(t, byRule[1].Packages, 3)
+
+	byGroup := GroupPackages(pkgs, GroupByGroup)
+	keys := make([]string, 0, len(byGroup))
+	for _, section := range byGroup {
+		keys = append(keys, section.Key)
+	}
+	assert.Equal(t, []string{"http", "ui", UngroupedSection}, keys)
+	assert.Len(t, byGroup[2].Packages, 2)
+
+	byType := GroupPackages(pkgs, GroupByType)
+	require.Len(t, byType, 2)
+	assert.Equal(t, "dev", byType[0].Key)
+	assert.Len(t, FlattenSections(byType), len(pkgs))
+
+	flat := GroupPackages(pkgs, GroupByNone)
+	require.Len(t, flat, 1)
+	assert.Empty(t, flat[0].Key)
+	assert.Len(t, flat[0].Packages, len(pkgs))
+
+	assert.Empty(t, SectionKey(pkgs[0], GroupByNone))
+	assert.Equal(t, "rule: npm (3 packages)", SectionTitle(GroupByRule, "npm", 3))
+	assert.Equal(t, "group: ui (1 package)", SectionTitle(GroupByGroup, "ui", 1))
+}
@@ -6,11 +6,13 @@ import (
 	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/warnings"
 )
 
 // PrintWarnings prints warning messages to the writer.
 //
-// Formats each warning on its own line with a warning icon prefix.
+// Formats each warning on its own line with a warning icon prefix, or a
+// plain "Warning:" prefix when color is disabled (see SetColorMode).
 // Does nothing if warnings slice is empty.
 // Prints a blank line before the warnings for separation.
 //
@@ -30,10 +32,45 @@ func PrintWarnings(w io.Writer, warnings []string) {
 
 	_, _ = fmt.Fprintln(w)
 	for _, warning := range warnings {
-		_, _ = fmt.Fprintf(w, "%s %s\n", constants.IconWarn, warning)
+		_, _ = fmt.Fprintf(w, "%s %s\n", warningPrefix(), warning)
 	}
 }
 
+// PrintStructuredWarnings prints classified warnings to the given writer.
+//
+// Each warning is rendered as "<prefix> [CODE] message". Like PrintWarnings,
+// a blank line is printed first and nothing is printed for an empty list.
+//
+// Parameters:
+//   - w: Destination writer
+//   - list: Warnings to print
+//
+// Example:
+//
+//	display.PrintStructuredWarnings(os.Stdout, collector.Warnings())
+//	// Warning: [INVALID_CONSTRAINT] Invalid constraint '=>' for package 'react', using exact match
+func PrintStructuredWarnings(w io.Writer, list []warnings.Warning) {
+	if len(list) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(w)
+	for _, warning := range list {
+		_, _ = fmt.Fprintf(w, "%s %s\n", warningPrefix(), warning)
+	}
+}
+
+// warningPrefix returns the prefix for warning lines.
+//
+// Returns:
+//   - string: the warning icon, or "Warning:" when color is disabled
+func warningPrefix() string {
+	if !colorEnabled {
+		return "Warning:"
+	}
+	return styledIcon(constants.IconWarn)
+}
+
 // PrintWarningsInline prints warning messages without a leading blank line.
 //
 // Same as PrintWarnings but without the leading blank line.
@@ -47,7 +84,7 @@ func PrintWarningsInline(w io.Writer, warnings []string) {
 	}
 
 	for _, warning := range warnings {
-		_, _ = fmt.Fprintf(w, "%s %s\n", constants.IconWarn, warning)
+		_, _ = fmt.Fprintf(w, "%s %s\n", warningPrefix(), warning)
 	}
 }
 
@@ -163,8 +200,84 @@ func PrintSummary(w io.Writer, summary Summary) {
 	_, _ = fmt.Fprintln(w)
 }
 
+// Totals holds the final counts of an update run.
+//
+// Fields:
+//   - Updated: Packages updated (or planned in a dry run)
+//   - UpToDate: Packages already at their target version
+//   - Failed: Failures that count towards the exit code
+//   - Unsupported: Packages that cannot be updated automatically
+//   - DryRun: Label updated packages as planned
+//   - Passed: Whether the run exits with code 0
+type Totals struct {
+	// Updated is the number of updated or planned packages.
+	Updated int
+
+	// UpToDate is the number of packages already at their target version.
+	UpToDate int
+
+	// Failed is the number of failures.
+	Failed int
+
+	// Unsupported is the number of packages that cannot be updated automatically.
+	Unsupported int
+
+	// DryRun labels updated packages as planned.
+	DryRun bool
+
+	// Passed reports whether the run exits with code 0.
+	Passed bool
+
+	// Warned is the number of failures reported as warnings by the severity config.
+	Warned int
+
+	// Severity is the effective severity mapping; empty when it is the default.
+	Severity []string
+}
+
+// PrintTotals prints the one-line footer that ends table output.
+//
+// The line is prefixed with a success or error icon matching Passed when color
+// is enabled (see SetColorMode), so the footer always agrees with the exit code.
+// A severity mapping other than the default adds the warned count and a second
+// line listing the mapping, so a run's exit code can be traced back to it.
+//
+// Parameters:
+//   - w: Writer to output to
+//   - totals: Counts to display
+//
+// Example output:
+//
+//	<blank line>
+//	Updated: 3, Up-to-date: 12, Failed: 1, Unsupported: 2
+//	Updated: 3, Up-to-date: 12, Failed: 0, Unsupported: 2, Warned: 1
+//	Severity: Failed=error, ConfigError=error, SummarizeError=error, npm:Failed=warn
+func PrintTotals(w io.Writer, totals Totals) {
+	label := "Updated"
+	if totals.DryRun {
+		label = "Planned"
+	}
+	line := fmt.Sprintf("%s: %d, Up-to-date: %d, Failed: %d, Unsupported: %d", label, totals.Updated, totals.UpToDate, totals.Failed, totals.Unsupported)
+	if totals.Warned > 0 || len(totals.Severity) > 0 {
+		line += fmt.Sprintf(", Warned: %d", totals.Warned)
+	}
+
+	icon := IconError
+	if totals.Passed {
+		icon = IconSuccess
+	}
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, withIcon(icon, line))
+	if len(totals.Severity) > 0 {
+		_, _ = fmt.Fprintf(w, "Severity: %s\n", strings.Join(totals.Severity, ", "))
+	}
+}
+
 // PrintNoPackagesMessage prints a "no packages found" message.
 //
+// The message is plain text in every color mode, so it is safe for CI logs.
+//
 // Parameters:
 //   - w: Writer to output to
 //   - context: Context string describing what filters were used (optional)
@@ -218,6 +331,7 @@ func PrintNoPackagesMessageWithFilters(w io.Writer, typeFlag, pmFlag, ruleFlag s
 //	display.PrintWarnings(os.Stderr, collector.Messages())
 type WarningCollector struct {
 	messages []string
+	warnings []warnings.Warning
 }
 
 // Write implements io.Writer for capturing warning messages.
@@ -236,11 +350,27 @@ func (c *WarningCollector) Write(p []byte) (int, error) {
 		trimmed := strings.TrimSpace(line)
 		if trimmed != "" {
 			c.messages = append(c.messages, trimmed)
+			c.warnings = append(c.warnings, warnings.Warning{Code: warnings.CodeGeneral, Message: trimmed})
 		}
 	}
 	return len(p), nil
 }
 
+// WriteWarning implements warnings.StructuredWriter so warning codes are kept.
+//
+// The message is also recorded in Messages for plain-text output.
+//
+// Parameters:
+//   - w: The classified warning
+func (c *WarningCollector) WriteWarning(w warnings.Warning) {
+	w.Message = strings.TrimSpace(w.Message)
+	if w.Message == "" {
+		return
+	}
+	c.messages = append(c.messages, w.Message)
+	c.warnings = append(c.warnings, w)
+}
+
 // Messages returns a copy of all collected warning messages.
 //
 // Creates a defensive copy to prevent external modification of the internal slice.
@@ -253,11 +383,24 @@ func (c *WarningCollector) Messages() []string {
 	return copied
 }
 
+// Warnings returns a copy of all collected warnings with their codes.
+//
+// Warnings written as plain text carry warnings.CodeGeneral.
+//
+// Returns:
+//   - []warnings.Warning: Copy of all collected warnings, in the order they were written
+func (c *WarningCollector) Warnings() []warnings.Warning {
+	copied := make([]warnings.Warning, len(c.warnings))
+	copy(copied, c.warnings)
+	return copied
+}
+
 // Reset clears all collected messages.
 //
 // Use this when you want to reuse the same collector for a new batch of warnings.
 func (c *WarningCollector) Reset() {
 	c.messages = nil
+	c.warnings = nil
 }
 
 // NewWarningCollector creates a new WarningCollector.
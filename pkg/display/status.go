@@ -1,7 +1,6 @@
 package display
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/constants"
@@ -62,7 +61,9 @@ const (
 //   - status: The status string (e.g., "Updated", "Failed", "Planned")
 //
 // Returns:
-//   - string: Formatted status with icon prefix (e.g., "🟢 Updated")
+//   - string: Formatted status with icon prefix (e.g., "🟢 Updated", or "[+] Updated"
+//     in ASCII style), or the plain label (e.g., "Updated") when color is disabled
+//     (see SetColorMode and SetStatusStyle)
 //
 // Example:
 //
@@ -72,27 +73,34 @@ const (
 func FormatStatus(status string) string {
 	switch status {
 	case constants.StatusUpdated:
-		return fmt.Sprintf("%s %s", constants.IconSuccess, constants.StatusUpdated)
+		return withIcon(constants.IconSuccess, constants.StatusUpdated)
 	case constants.StatusPlanned:
-		return fmt.Sprintf("%s %s", constants.IconPending, constants.StatusPlanned)
+		return withIcon(constants.IconPending, constants.StatusPlanned)
 	case constants.StatusUpToDate:
-		return fmt.Sprintf("%s %s", constants.IconSuccess, constants.StatusUpToDate)
+		return withIcon(constants.IconSuccess, constants.StatusUpToDate)
+	case constants.StatusVerified:
+		return withIcon(constants.IconSuccess, constants.StatusVerified)
 	case constants.StatusFailed:
-		return fmt.Sprintf("%s %s", constants.IconError, constants.StatusFailed)
+		return withIcon(constants.IconError, constants.StatusFailed)
 	case constants.StatusOutdated:
-		return fmt.Sprintf("%s %s", constants.IconWarning, constants.StatusOutdated)
+		return withIcon(constants.IconWarning, constants.StatusOutdated)
 	case lock.InstallStatusNotConfigured:
-		return fmt.Sprintf("%s %s", constants.IconNotConfigured, lock.InstallStatusNotConfigured)
+		return withIcon(constants.IconNotConfigured, lock.InstallStatusNotConfigured)
 	case lock.InstallStatusFloating:
-		return fmt.Sprintf("%s %s", constants.IconBlocked, lock.InstallStatusFloating)
+		return withIcon(constants.IconBlocked, lock.InstallStatusFloating)
+	case lock.InstallStatusNonRegistry:
+		return withIcon(constants.IconBlocked, lock.InstallStatusNonRegistry)
 	case constants.StatusConfigError:
-		return fmt.Sprintf("%s %s", constants.IconError, constants.StatusConfigError)
+		return withIcon(constants.IconError, constants.StatusConfigError)
 	case constants.StatusSummarizeError:
-		return fmt.Sprintf("%s %s", constants.IconError, constants.StatusSummarizeError)
+		return withIcon(constants.IconError, constants.StatusSummarizeError)
 	case lock.InstallStatusIgnored:
-		return fmt.Sprintf("%s %s", constants.IconIgnored, lock.InstallStatusIgnored)
+		return withIcon(constants.IconIgnored, lock.InstallStatusIgnored)
+	case constants.StatusDeferred:
+		return withIcon(constants.IconInfo, constants.StatusDeferred)
 	default:
-		return status
+		// Lock statuses (LockFound, LockMissing, ...) use their install status icons
+		return FormatInstallStatus(status)
 	}
 }
 
@@ -102,26 +110,33 @@ func FormatStatus(status string) string {
 //   - status: The status string
 //
 // Returns:
-//   - string: The icon for this status, or empty string if unknown
+//   - string: The icon for this status in the active status style, or empty string
+//     if unknown or color is disabled
 //
 // Example:
 //
 //	display.StatusIcon("Updated")  // Returns "🟢"
 //	display.StatusIcon("Failed")   // Returns "❌"
 func StatusIcon(status string) string {
+	if !colorEnabled {
+		return ""
+	}
+
 	switch status {
-	case constants.StatusUpdated, constants.StatusUpToDate:
-		return constants.IconSuccess
+	case constants.StatusUpdated, constants.StatusUpToDate, constants.StatusVerified:
+		return styledIcon(constants.IconSuccess)
 	case constants.StatusPlanned:
-		return constants.IconPending
+		return styledIcon(constants.IconPending)
 	case constants.StatusFailed, constants.StatusConfigError, constants.StatusSummarizeError:
-		return constants.IconError
+		return styledIcon(constants.IconError)
 	case constants.StatusOutdated:
-		return constants.IconWarning
+		return styledIcon(constants.IconWarning)
+	case constants.StatusDeferred:
+		return styledIcon(constants.IconInfo)
 	case lock.InstallStatusNotConfigured:
-		return constants.IconNotConfigured
-	case lock.InstallStatusFloating:
-		return constants.IconBlocked
+		return styledIcon(constants.IconNotConfigured)
+	case lock.InstallStatusFloating, lock.InstallStatusNonRegistry, lock.InstallStatusLockUnavailable:
+		return styledIcon(constants.IconBlocked)
 	default:
 		return ""
 	}
@@ -170,25 +185,29 @@ func IsPendingStatus(status string) bool {
 //   - status: Installation status (e.g., "LockFound", "NotInLock", "Floating")
 //
 // Returns:
-//   - string: Formatted status with icon
+//   - string: Formatted status with icon (plain label when color is disabled)
 func FormatInstallStatus(status string) string {
 	switch status {
 	case lock.InstallStatusLockFound:
-		return fmt.Sprintf("%s LockFound", constants.IconSuccess)
+		return withIcon(constants.IconSuccess, "LockFound")
 	case lock.InstallStatusNotInLock:
-		return fmt.Sprintf("%s NotInLock", constants.IconInfo)
+		return withIcon(constants.IconInfo, "NotInLock")
 	case lock.InstallStatusLockMissing:
-		return fmt.Sprintf("%s LockMissing", constants.IconWarning)
+		return withIcon(constants.IconWarning, "LockMissing")
+	case lock.InstallStatusLockUnavailable:
+		return withIcon(constants.IconBlocked, "LockUnavailable")
 	case lock.InstallStatusFloating:
-		return fmt.Sprintf("%s Floating", constants.IconBlocked)
+		return withIcon(constants.IconBlocked, "Floating")
+	case lock.InstallStatusNonRegistry:
+		return withIcon(constants.IconBlocked, "NonRegistry")
 	case lock.InstallStatusNotConfigured:
-		return fmt.Sprintf("%s NotConfigured", constants.IconNotConfigured)
+		return withIcon(constants.IconNotConfigured, "NotConfigured")
 	case lock.InstallStatusVersionMissing:
-		return fmt.Sprintf("%s VersionMissing", constants.IconError)
+		return withIcon(constants.IconError, "VersionMissing")
 	case lock.InstallStatusSelfPinned:
-		return fmt.Sprintf("%s SelfPinned", constants.IconPinned)
+		return withIcon(constants.IconPinned, "SelfPinned")
 	case lock.InstallStatusIgnored:
-		return fmt.Sprintf("%s Ignored", constants.IconIgnored)
+		return withIcon(constants.IconIgnored, "Ignored")
 	default:
 		return status
 	}
@@ -196,19 +215,23 @@ func FormatInstallStatus(status string) string {
 
 // statusIconMap maps lowercase status prefixes to their icons.
 var statusIconMap = map[string]string{
-	strings.ToLower(constants.StatusOutdated):         constants.IconWarning,
-	strings.ToLower(lock.InstallStatusNotConfigured):  constants.IconNotConfigured,
-	strings.ToLower(lock.InstallStatusFloating):       constants.IconBlocked,
-	strings.ToLower(constants.StatusUpToDate):         constants.IconSuccess,
-	strings.ToLower(constants.StatusUpdated):          constants.IconSuccess,
-	strings.ToLower(lock.InstallStatusLockFound):      constants.IconSuccess,
-	strings.ToLower(lock.InstallStatusSelfPinned):     constants.IconPinned,
-	strings.ToLower(lock.InstallStatusNotInLock):      constants.IconInfo,
-	strings.ToLower(lock.InstallStatusLockMissing):    constants.IconWarning,
-	strings.ToLower(lock.InstallStatusVersionMissing): constants.IconBlocked,
-	strings.ToLower(lock.InstallStatusIgnored):        constants.IconIgnored,
-	strings.ToLower(constants.StatusFailed):           constants.IconError,
-	strings.ToLower(constants.StatusPlanned):          constants.IconPending,
+	strings.ToLower(constants.StatusOutdated):          constants.IconWarning,
+	strings.ToLower(lock.InstallStatusNotConfigured):   constants.IconNotConfigured,
+	strings.ToLower(lock.InstallStatusFloating):        constants.IconBlocked,
+	strings.ToLower(lock.InstallStatusNonRegistry):     constants.IconBlocked,
+	strings.ToLower(constants.StatusUpToDate):          constants.IconSuccess,
+	strings.ToLower(constants.StatusUpdated):           constants.IconSuccess,
+	strings.ToLower(constants.StatusVerified):          constants.IconSuccess,
+	strings.ToLower(lock.InstallStatusLockFound):       constants.IconSuccess,
+	strings.ToLower(lock.InstallStatusSelfPinned):      constants.IconPinned,
+	strings.ToLower(lock.InstallStatusNotInLock):       constants.IconInfo,
+	strings.ToLower(lock.InstallStatusLockMissing):     constants.IconWarning,
+	strings.ToLower(lock.InstallStatusLockUnavailable): constants.IconBlocked,
+	strings.ToLower(lock.InstallStatusVersionMissing):  constants.IconBlocked,
+	strings.ToLower(lock.InstallStatusIgnored):         constants.IconIgnored,
+	strings.ToLower(constants.StatusFailed):            constants.IconError,
+	strings.ToLower(constants.StatusPlanned):           constants.IconPending,
+	strings.ToLower(constants.StatusDeferred):          constants.IconInfo,
 }
 
 // FormatStatusWithIcon formats any status string with the appropriate icon prefix.
@@ -220,7 +243,8 @@ var statusIconMap = map[string]string{
 //   - status: The status string to format
 //
 // Returns:
-//   - string: Formatted status with icon prefix (e.g., "🟢 Updated", "❌ Failed(1)")
+//   - string: Formatted status with icon prefix (e.g., "🟢 Updated", "❌ Failed(1)"),
+//     or the unchanged status when color is disabled
 //
 // Example:
 //
@@ -232,7 +256,7 @@ func FormatStatusWithIcon(status string) string {
 
 	for key, icon := range statusIconMap {
 		if normalized == key || strings.HasPrefix(normalized, key+"(") {
-			return icon + " " + status
+			return withIcon(icon, status)
 		}
 	}
 
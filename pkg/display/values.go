@@ -1,8 +1,11 @@
 package display
 
 import (
+	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ajxudir/goupdate/pkg/constants"
 )
@@ -77,6 +80,72 @@ func SafeVersionValue(val, placeholder string) string {
 	return val
 }
 
+// FormatAge formats how long ago something happened in compact units.
+//
+// Ages under a day show as "<1d", under 60 days in days, under two years in
+// months (30 days), and anything older in years (365 days).
+//
+// Parameters:
+//   - age: Elapsed time; negative values are treated as zero
+//
+// Returns:
+//   - string: Compact age such as "12d", "5mo", or "3y"
+//
+// Example:
+//
+//	display.FormatAge(45 * 24 * time.Hour)  // Returns "45d"
+//	display.FormatAge(400 * 24 * time.Hour) // Returns "13mo"
+func FormatAge(age time.Duration) string {
+	days := int(age.Hours() / 24)
+	switch {
+	case days < 1:
+		return "<1d"
+	case days < 60:
+		return fmt.Sprintf("%dd", days)
+	case days < 730:
+		return fmt.Sprintf("%dmo", days/30)
+	default:
+		return fmt.Sprintf("%dy", days/365)
+	}
+}
+
+// FormatSource formats where a package was declared for the SOURCE column.
+//
+// The path is shown relative to baseDir when it lies inside it, followed by
+// ":line" when the line is known.
+//
+// Parameters:
+//   - source: Manifest path the package was parsed from
+//   - line: 1-based declaration line; 0 when unknown
+//   - baseDir: Directory paths are shown relative to; empty keeps source as-is
+//
+// Returns:
+//   - string: Location such as "web/package.json:12", or "" when source is empty
+//
+// Example:
+//
+//	display.FormatSource("/repo/web/package.json", 12, "/repo") // Returns "web/package.json:12"
+//	display.FormatSource("/repo/go.mod", 0, "/repo")            // Returns "go.mod"
+func FormatSource(source string, line int, baseDir string) string {
+	if source == "" {
+		return ""
+	}
+	if baseDir != "" {
+		absSource, srcErr := filepath.Abs(source)
+		absBase, baseErr := filepath.Abs(baseDir)
+		if srcErr == nil && baseErr == nil {
+			if rel, err := filepath.Rel(absBase, absSource); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				source = rel
+			}
+		}
+	}
+	source = filepath.ToSlash(source)
+	if line > 0 {
+		return fmt.Sprintf("%s:%d", source, line)
+	}
+	return source
+}
+
 // HasAvailableUpdates returns true if any version update is available.
 //
 // Checks if major, minor, or patch versions are non-empty and not placeholders.
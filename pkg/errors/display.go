@@ -21,6 +21,12 @@ import (
 //	Error: <error message>
 //	  Hint: <actionable hint if available>
 //
+// Outside verbose mode, errors with identical messages are collapsed into a
+// single entry with a count suffix (for example "Error: registry down (× 14)").
+// Collapsed entries keep the position of their first occurrence and every
+// distinct hint line seen for that message, so the output order is stable.
+// Verbose mode prints every error in full.
+//
 // Example:
 //
 //	errors.PrintErrorWithHints(os.Stderr, collectedErrors, verbose)
@@ -29,11 +35,104 @@ func PrintErrorWithHints(w io.Writer, errs []error, verbose bool) {
 		return
 	}
 
+	if verbose {
+		for _, err := range errs {
+			printSingleError(w, err, verbose)
+		}
+		return
+	}
+
+	for _, group := range groupErrors(errs) {
+		printErrorGroup(w, group)
+	}
+}
+
+// errorGroup holds the rendered output of errors sharing the same message.
+//
+// Fields:
+//   - headline: First output line of the first error in the group
+//   - details: Distinct follow-up lines (hints) in first-seen order
+//   - count: Number of errors collapsed into the group
+type errorGroup struct {
+	headline string
+	details  []string
+	count    int
+}
+
+// groupErrors renders errors and collapses those with identical messages.
+//
+// Nil errors are skipped. Groups are returned in the order their message
+// first appears in errs.
+//
+// Parameters:
+//   - errs: Errors to group
+//
+// Returns:
+//   - []*errorGroup: One group per distinct error message
+func groupErrors(errs []error) []*errorGroup {
+	var groups []*errorGroup
+	byMessage := make(map[string]*errorGroup)
+
 	for _, err := range errs {
-		printSingleError(w, err, verbose)
+		if err == nil {
+			continue
+		}
+
+		var sb strings.Builder
+		printSingleError(&sb, err, false)
+		lines := strings.Split(strings.TrimRight(sb.String(), "\n"), "\n")
+
+		group, ok := byMessage[err.Error()]
+		if !ok {
+			group = &errorGroup{headline: lines[0]}
+			byMessage[err.Error()] = group
+			groups = append(groups, group)
+		}
+		group.count++
+
+		for _, line := range lines[1:] {
+			if !containsString(group.details, line) {
+				group.details = append(group.details, line)
+			}
+		}
+	}
+
+	return groups
+}
+
+// printErrorGroup prints a collapsed error group.
+//
+// Parameters:
+//   - w: Writer to output to
+//   - group: The group to print; a count suffix is added when it holds more than one error
+func printErrorGroup(w io.Writer, group *errorGroup) {
+	headline := group.headline
+	if group.count > 1 {
+		headline = fmt.Sprintf("%s (\u00d7 %d)", headline, group.count)
+	}
+	_, _ = fmt.Fprintln(w, headline)
+	for _, line := range group.details {
+		_, _ = fmt.Fprintln(w, line)
 	}
 }
 
+// containsString reports whether s is present in list.
+//
+// Parameters:
+//   - list: Strings to search
+//   - s: String to look for
+//
+// Returns:
+//   - bool: true if s is an element of list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 // printSingleError prints a single error with appropriate formatting.
 //
 // This function determines the error type and dispatches to the appropriate
@@ -50,19 +149,19 @@ func printSingleError(w io.Writer, err error, verbose bool) {
 	}
 
 	// Check for validation errors - format specially
-	if ve, ok := IsValidationError(err); ok {
+	if ve, ok := shownError[*ValidationError](err); ok {
 		printValidationError(w, ve, verbose)
 		return
 	}
 
 	// Check for unsupported errors - format specially
-	if ue, ok := IsUnsupportedError(err); ok {
+	if ue, ok := shownError[*UnsupportedError](err); ok {
 		printUnsupportedError(w, ue, verbose)
 		return
 	}
 
 	// Check for partial success errors - format specially
-	if pse, ok := IsPartialSuccess(err); ok {
+	if pse, ok := shownError[*PartialSuccessError](err); ok {
 		printPartialSuccessError(w, pse, verbose)
 		return
 	}
@@ -72,6 +171,42 @@ func printSingleError(w io.Writer, err error, verbose bool) {
 	_, _ = fmt.Fprintf(w, "Error: %s\n", enhanced)
 }
 
+// shownError finds the first error of type T that err displays as.
+//
+// It walks the wrap chain like errors.As but does not look past an ExitError
+// with its own message: that message is what the user sees, and its cause is
+// only kept for errors.Is/As.
+//
+// Parameters:
+//   - err: The error to search
+//
+// Returns:
+//   - T: The matching error
+//   - bool: true if a matching error was found
+func shownError[T error](err error) (T, bool) {
+	var zero T
+	if err == nil {
+		return zero, false
+	}
+	if match, ok := err.(T); ok {
+		return match, true
+	}
+	if exitErr, ok := err.(*ExitError); ok && exitErr.Message != "" {
+		return zero, false
+	}
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		return shownError[T](wrapped.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, inner := range wrapped.Unwrap() {
+			if match, ok := shownError[T](inner); ok {
+				return match, true
+			}
+		}
+	}
+	return zero, false
+}
+
 // printValidationError prints a validation error with appropriate detail level.
 //
 // In verbose mode, prints the full VerboseError with expected values and hints.
@@ -309,6 +444,14 @@ func (r *ValidationResult) VerboseErrorMessage() string {
 	return sb.String()
 }
 
+// Err returns the validation errors as a single error.
+//
+// Returns:
+//   - error: The errors joined with JoinErrors, so errors.As finds each *ValidationError; nil when there are none
+func (r *ValidationResult) Err() error {
+	return JoinErrors(r.Errors)
+}
+
 // PrintTo writes validation results to the given writer.
 //
 // Parameters:
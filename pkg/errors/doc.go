@@ -5,6 +5,7 @@
 //   - PartialSuccessError: Some operations succeeded, some failed
 //   - ValidationError: Configuration or preflight validation failures
 //   - UnsupportedError: Operations not supported for specific packages
+//   - Summary: Totals and exit code of an update run (see update.BuildSummary)
 //
 // Error Display:
 //
@@ -14,12 +15,33 @@
 //
 // Error Checking:
 //
-// Use the Is* functions to check error types:
+// Use the Is* functions to check error types. Each returns the typed error
+// and whether it was found, and sees through wrapping (fmt.Errorf("%w"),
+// errors.Join, ExitError):
+//   - IsExitError: exit code to use
+//   - IsPartialSuccessError: some packages updated, some failed
+//   - IsValidationError: configuration or preflight problem
+//   - IsUnsupportedError: package cannot be processed
+//
+// For example:
 //
 //	if exitErr, ok := errors.IsExitError(err); ok {
 //	    os.Exit(exitErr.Code)
 //	}
 //
+//	if pse, ok := errors.IsPartialSuccessError(err); ok {
+//	    // pse.Succeeded packages were updated; pse.Errors explains the rest
+//	} else if ve, ok := errors.IsValidationError(err); ok {
+//	    // configuration problem in ve.Field
+//	}
+//
+// An ExitError keeps the error that caused it, either from a %w verb in
+// NewExitErrorf or set with WithCause, so the original stays reachable:
+//
+//	err := errors.NewExitErrorf(errors.ExitConfigError, "validation failed: %w", cause)
+//	var ve *errors.ValidationError
+//	stderrors.As(err, &ve) // true when cause holds a ValidationError
+//
 // Exit Codes:
 //
 // Standard exit codes are defined for scripting integration:
@@ -27,4 +49,5 @@
 //   - ExitPartialFailure (1): Some operations failed
 //   - ExitFailure (2): All operations failed or critical error
 //   - ExitConfigError (3): Configuration or validation error
+//   - ExitOnlyUnsupported (4): Nothing updated or failed, only unsupported packages (--fail-on only-unsupported)
 package errors
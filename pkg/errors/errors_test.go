@@ -3,9 +3,12 @@ package errors
 import (
 	"bytes"
 	stderrors "errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestExitCodes tests the exit code constants.
@@ -461,6 +464,7 @@ func TestValidationError(t *testing.T) {
 // It verifies that:
 //   - NewValidationResult creates empty result
 //   - AddError adds errors and HasErrors reflects state
+//   - Err joins the errors so errors.As finds each of them
 //   - AddWarning adds warnings and HasWarnings reflects state
 //   - ErrorMessage returns formatted error summary
 //   - VerboseErrorMessage includes detailed information
@@ -485,6 +489,21 @@ func TestValidationResult(t *testing.T) {
 		assert.Len(t, result.Errors, 1)
 	})
 
+	t.Run("Err joins the errors", func(t *testing.T) {
+		result := NewValidationResult()
+		assert.NoError(t, result.Err())
+
+		first := &ValidationError{Category: ValidationCategoryConfig, Field: "a", Message: "first"}
+		result.AddError(first)
+		result.AddError(&ValidationError{Category: ValidationCategoryConfig, Field: "b", Message: "second"})
+		err := result.Err()
+		require.Error(t, err)
+		var ve *ValidationError
+		require.True(t, stderrors.As(err, &ve))
+		assert.Same(t, first, ve)
+		assert.Contains(t, err.Error(), "second")
+	})
+
 	t.Run("AddWarning and HasWarnings", func(t *testing.T) {
 		result := NewValidationResult()
 		assert.False(t, result.HasWarnings())
@@ -685,6 +704,49 @@ func TestPrintErrorWithHints(t *testing.T) {
 	})
 }
 
+// TestPrintErrorWithHintsDedup tests collapsing of repeated errors.
+//
+// It verifies that:
+//   - Identical messages collapse into one line with a "× N" count
+//   - Collapsed output keeps first-seen order and the hint of the message
+//   - Verbose mode prints every error without collapsing
+func TestPrintErrorWithHintsDedup(t *testing.T) {
+	errs := []error{
+		stderrors.New("registry ECONNREFUSED"),
+		stderrors.New("unrelated failure"),
+		stderrors.New("registry ECONNREFUSED"),
+		nil,
+		stderrors.New("registry ECONNREFUSED"),
+	}
+
+	t.Run("collapses identical messages", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintErrorWithHints(&buf, errs, false)
+		output := buf.String()
+
+		lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "Error: registry ECONNREFUSED (\u00d7 3)", lines[0])
+		assert.Contains(t, lines[1], "\U0001F4A1")
+		assert.Equal(t, "Error: unrelated failure", lines[2])
+	})
+
+	t.Run("output is stable", func(t *testing.T) {
+		var first, second bytes.Buffer
+		PrintErrorWithHints(&first, errs, false)
+		PrintErrorWithHints(&second, errs, false)
+		assert.Equal(t, first.String(), second.String())
+	})
+
+	t.Run("verbose keeps every error", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintErrorWithHints(&buf, errs, true)
+		output := buf.String()
+		assert.Equal(t, 3, strings.Count(output, "Error: registry ECONNREFUSED"))
+		assert.NotContains(t, output, "\u00d7")
+	})
+}
+
 // TestFormatValidationError tests the FormatValidationError function.
 //
 // Parameters:
@@ -977,3 +1039,252 @@ func TestValidationErrorAllBranches(t *testing.T) {
 		assert.Contains(t, verbose, "Hint: Try this fix")
 	})
 }
+
+// TestIsHelpersDetectWrappedErrors tests that the Is* helpers see through wrapping.
+//
+// It verifies that:
+//   - Each helper finds its type wrapped with fmt.Errorf("%w"), errors.Join, and ExitError
+//   - IsPartialSuccess and IsPartialSuccessError agree
+//   - Helpers do not match the other error types
+func TestIsHelpersDetectWrappedErrors(t *testing.T) {
+	partial := NewPartialSuccessError(2, 1, []error{stderrors.New("failed")})
+	validation := NewConfigValidationError("rules.npm.format", "invalid format")
+	unsupported := NewUnsupportedError("update", "floating constraint", "lodash")
+
+	wrappers := map[string]func(error) error{
+		"direct":     func(err error) error { return err },
+		"fmt.Errorf": func(err error) error { return fmt.Errorf("update failed: %w", err) },
+		"errors.Join": func(err error) error {
+			return stderrors.Join(stderrors.New("other"), err)
+		},
+		"ExitError": func(err error) error { return NewExitError(ExitPartialFailure, err) },
+		"nested": func(err error) error {
+			return fmt.Errorf("outer: %w", NewExitError(ExitFailure, fmt.Errorf("inner: %w", err)))
+		},
+	}
+
+	for name, wrap := range wrappers {
+		t.Run(name, func(t *testing.T) {
+			pse, ok := IsPartialSuccessError(wrap(partial))
+			assert.True(t, ok)
+			assert.Same(t, partial, pse)
+			legacy, ok := IsPartialSuccess(wrap(partial))
+			assert.True(t, ok)
+			assert.Same(t, partial, legacy)
+
+			ve, ok := IsValidationError(wrap(validation))
+			assert.True(t, ok)
+			assert.Same(t, validation, ve)
+
+			ue, ok := IsUnsupportedError(wrap(unsupported))
+			assert.True(t, ok)
+			assert.Same(t, unsupported, ue)
+
+			_, ok = IsPartialSuccessError(wrap(validation))
+			assert.False(t, ok)
+			_, ok = IsValidationError(wrap(unsupported))
+			assert.False(t, ok)
+			_, ok = IsUnsupportedError(wrap(partial))
+			assert.False(t, ok)
+		})
+	}
+
+	pse, ok := IsPartialSuccessError(nil)
+	assert.False(t, ok)
+	assert.Nil(t, pse)
+}
+
+// TestParseFailOn tests parsing --fail-on values.
+//
+// It verifies that:
+//   - Every policy parses, case-insensitively
+//   - An empty value selects the default partial policy
+//   - Unknown values are rejected with the accepted values listed
+func TestParseFailOn(t *testing.T) {
+	for _, policy := range FailOnPolicies {
+		got, err := ParseFailOn(strings.ToUpper(string(policy)))
+		require.NoError(t, err)
+		assert.Equal(t, policy, got)
+	}
+
+	got, err := ParseFailOn("")
+	require.NoError(t, err)
+	assert.Equal(t, FailOnPartial, got)
+
+	_, err = ParseFailOn("sometimes")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "none, any-failure, any-unsupported, only-unsupported, partial")
+}
+
+// TestFailOnPolicyExitCode tests mapping run outcomes to exit codes.
+//
+// It verifies that:
+//   - partial keeps the default mapping and ignores unsupported packages
+//   - none always succeeds
+//   - any-failure fails on any failure, even a partial success
+//   - any-unsupported returns ExitConfigError for unsupported packages when nothing failed
+//   - only-unsupported returns ExitOnlyUnsupported only when nothing was updated or failed
+func TestFailOnPolicyExitCode(t *testing.T) {
+	clean := RunOutcome{Succeeded: 3}
+	unsupported := RunOutcome{Succeeded: 3, Unsupported: 1}
+	partial := RunOutcome{Succeeded: 2, Failed: 1, Partial: true}
+	failed := RunOutcome{Failed: 2, Unsupported: 1}
+	onlyUnsupported := RunOutcome{Unsupported: 2}
+
+	tests := []struct {
+		policy  FailOnPolicy
+		outcome RunOutcome
+		want    int
+	}{
+		{FailOnPartial, clean, ExitSuccess},
+		{FailOnPartial, unsupported, ExitSuccess},
+		{FailOnPartial, partial, ExitPartialFailure},
+		{FailOnPartial, failed, ExitFailure},
+		{FailOnNone, partial, ExitSuccess},
+		{FailOnNone, failed, ExitSuccess},
+		{FailOnAnyFailure, unsupported, ExitSuccess},
+		{FailOnAnyFailure, partial, ExitFailure},
+		{FailOnAnyFailure, failed, ExitFailure},
+		{FailOnAnyUnsupported, clean, ExitSuccess},
+		{FailOnAnyUnsupported, unsupported, ExitConfigError},
+		{FailOnAnyUnsupported, partial, ExitPartialFailure},
+		{FailOnAnyUnsupported, failed, ExitFailure},
+		{FailOnAnyUnsupported, onlyUnsupported, ExitConfigError},
+		{FailOnPartial, onlyUnsupported, ExitSuccess},
+		{FailOnOnlyUnsupported, onlyUnsupported, ExitOnlyUnsupported},
+		{FailOnOnlyUnsupported, unsupported, ExitSuccess},
+		{FailOnOnlyUnsupported, partial, ExitPartialFailure},
+		{FailOnOnlyUnsupported, failed, ExitFailure},
+		{FailOnOnlyUnsupported, RunOutcome{}, ExitSuccess},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.policy.ExitCode(tt.outcome), "%s %+v", tt.policy, tt.outcome)
+	}
+}
+
+// TestExitErrorWithReason tests recording why an exit code was chosen.
+//
+// It verifies that:
+//   - WithReason sets Reason and returns the same error
+//   - Reason does not change the error message
+func TestExitErrorWithReason(t *testing.T) {
+	err := NewExitErrorf(ExitConfigError, "2 unsupported packages")
+	assert.Same(t, err, err.WithReason("--fail-on any-unsupported"))
+	assert.Equal(t, "--fail-on any-unsupported", err.Reason)
+	assert.Equal(t, "2 unsupported packages", err.Error())
+}
+
+// TestExitErrorCause tests reaching the cause of an ExitError.
+//
+// It verifies that:
+//   - NewExitErrorf wraps a %w operand as the cause and keeps the formatted message
+//   - Several %w operands are all reachable with errors.Is
+//   - WithCause keeps the message while errors.As finds the cause through wrapping
+//   - PrintErrorWithHints shows the exit error's message, not the cause, even when wrapped
+//   - An exit error without a message is formatted by its cause
+func TestExitErrorCause(t *testing.T) {
+	network := stderrors.New("dial tcp: connection refused")
+	err := NewExitErrorf(ExitFailure, "fetching versions: %w", network)
+	assert.Equal(t, "fetching versions: dial tcp: connection refused", err.Error())
+	assert.Same(t, network, stderrors.Unwrap(err))
+	assert.True(t, stderrors.Is(fmt.Errorf("update: %w", err), network))
+	assert.Nil(t, NewExitErrorf(ExitFailure, "no cause %s", "here").Err)
+
+	other := stderrors.New("timeout")
+	both := NewExitErrorf(ExitFailure, "%w; %w", network, other)
+	assert.True(t, stderrors.Is(both, network))
+	assert.True(t, stderrors.Is(both, other))
+
+	cause := &ValidationError{Category: ValidationCategoryPreflight, Message: "lock file has uncommitted changes"}
+	validation := NewExitErrorf(ExitConfigError, "validation failed: %d problems found", 1).WithCause(stderrors.Join(cause))
+	assert.Equal(t, "validation failed: 1 problems found", validation.Error())
+	var ve *ValidationError
+	require.True(t, stderrors.As(fmt.Errorf("run: %w", validation), &ve))
+	assert.Same(t, cause, ve)
+	exitErr, ok := IsExitError(fmt.Errorf("run: %w", validation))
+	require.True(t, ok)
+	assert.Equal(t, ExitConfigError, exitErr.Code)
+	assert.Same(t, validation, validation.WithCause(nil))
+	assert.NotNil(t, validation.Err)
+
+	var buf bytes.Buffer
+	PrintErrorWithHints(&buf, []error{validation}, false)
+	assert.Equal(t, "Error: validation failed: 1 problems found\n", buf.String())
+
+	buf.Reset()
+	PrintErrorWithHints(&buf, []error{fmt.Errorf("run: %w", validation)}, false)
+	assert.Equal(t, "Error: run: validation failed: 1 problems found\n", buf.String())
+
+	buf.Reset()
+	PrintErrorWithHints(&buf, []error{NewExitError(ExitConfigError, cause)}, false)
+	assert.Equal(t, "Validation Error: lock file has uncommitted changes\n", buf.String())
+}
+
+// TestJoinErrors tests joining a typed error list.
+//
+// It verifies that:
+//   - An empty list joins to nil
+//   - Every element is reachable with errors.As
+func TestJoinErrors(t *testing.T) {
+	assert.NoError(t, JoinErrors([]*UnsupportedError{}))
+
+	first := &UnsupportedError{Reason: "first"}
+	second := &UnsupportedError{Reason: "second"}
+	err := JoinErrors([]*UnsupportedError{first, second})
+	require.Error(t, err)
+	var ue *UnsupportedError
+	require.True(t, stderrors.As(err, &ue))
+	assert.Same(t, first, ue)
+	assert.Contains(t, err.Error(), "second")
+}
+
+// TestNewSummary tests building a run summary from counts.
+//
+// It verifies that:
+//   - Counts, partial reason and failures are copied into the summary
+//   - The exit code is derived with the policy, and an empty policy selects partial
+//   - Outcome, Passed and Reason reflect the summary
+func TestNewSummary(t *testing.T) {
+	failure := fmt.Errorf("lodash: lock failed")
+	outcome := RunOutcome{Succeeded: 2, Failed: 1, Unsupported: 1, Partial: true}
+
+	summary := NewSummary(outcome, "partial failure with --continue-on-fail", []error{failure}, "")
+	assert.Equal(t, 2, summary.Updated)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, 1, summary.Unsupported)
+	assert.True(t, summary.Partial)
+	assert.Equal(t, "partial failure with --continue-on-fail", summary.PartialReason)
+	assert.Equal(t, FailOnPartial, summary.Policy)
+	assert.Equal(t, ExitPartialFailure, summary.ExitCode)
+	assert.Equal(t, []error{failure}, summary.Failures)
+	assert.Equal(t, outcome, summary.Outcome())
+	assert.False(t, summary.Passed())
+	assert.Equal(t, OutcomePartialSuccess, summary.Kind())
+	assert.Equal(t, "--fail-on partial: partial-success (2 succeeded, 1 failed, 1 unsupported)", summary.Reason())
+
+	assert.True(t, NewSummary(outcome, "", nil, FailOnNone).Passed())
+	assert.Equal(t, ExitFailure, NewSummary(outcome, "", nil, FailOnAnyFailure).ExitCode)
+}
+
+// TestRunOutcomeKind tests classifying run outcomes.
+//
+// It verifies that:
+//   - Failures are a partial success or a failure depending on Partial
+//   - Zero updated and failed with unsupported packages is only-unsupported
+//   - Everything else, including an empty run, is a success
+//   - OnlyUnsupportedExitCode maps only-unsupported runs to the given code
+func TestRunOutcomeKind(t *testing.T) {
+	assert.Equal(t, OutcomePartialSuccess, RunOutcome{Succeeded: 1, Failed: 1, Partial: true}.Kind())
+	assert.Equal(t, OutcomeFailure, RunOutcome{Failed: 1, Unsupported: 2}.Kind())
+	assert.Equal(t, OutcomeOnlyUnsupported, RunOutcome{Unsupported: 2}.Kind())
+	assert.Equal(t, OutcomeSuccess, RunOutcome{Succeeded: 1, Unsupported: 2}.Kind())
+	assert.Equal(t, OutcomeSuccess, RunOutcome{}.Kind())
+
+	assert.Equal(t, ExitConfigError, OnlyUnsupportedExitCode(RunOutcome{Unsupported: 2}, ExitConfigError))
+	assert.Equal(t, ExitOnlyUnsupported, OnlyUnsupportedExitCode(RunOutcome{Unsupported: 2}, ExitOnlyUnsupported))
+	assert.Equal(t, ExitSuccess, OnlyUnsupportedExitCode(RunOutcome{Succeeded: 1, Unsupported: 2}, ExitConfigError))
+
+	onlyUnsupported := NewSummary(RunOutcome{Unsupported: 2}, "", nil, FailOnOnlyUnsupported)
+	assert.Equal(t, "--fail-on only-unsupported: only-unsupported (0 succeeded, 0 failed, 2 unsupported)", onlyUnsupported.Reason())
+}
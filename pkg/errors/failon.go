@@ -0,0 +1,180 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FailOnPolicy selects which run outcomes produce a non-zero exit code.
+//
+// It is set with the --fail-on flag of the update command.
+type FailOnPolicy string
+
+const (
+	// FailOnPartial is the default: failures exit with ExitPartialFailure when
+	// the run partially succeeded and ExitFailure otherwise.
+	FailOnPartial FailOnPolicy = "partial"
+
+	// FailOnNone always exits with ExitSuccess; failures are still reported.
+	FailOnNone FailOnPolicy = "none"
+
+	// FailOnAnyFailure exits with ExitFailure when any package failed, even if
+	// others succeeded.
+	FailOnAnyFailure FailOnPolicy = "any-failure"
+
+	// FailOnAnyUnsupported behaves like FailOnPartial and additionally exits
+	// with ExitConfigError when any package is unsupported.
+	FailOnAnyUnsupported FailOnPolicy = "any-unsupported"
+
+	// FailOnOnlyUnsupported behaves like FailOnPartial and additionally exits
+	// with ExitOnlyUnsupported when nothing was updated or failed but packages
+	// are unsupported, so a run that could do nothing is not a silent success.
+	FailOnOnlyUnsupported FailOnPolicy = "only-unsupported"
+)
+
+// FailOnPolicies lists the accepted --fail-on values in display order.
+var FailOnPolicies = []FailOnPolicy{FailOnNone, FailOnAnyFailure, FailOnAnyUnsupported, FailOnOnlyUnsupported, FailOnPartial}
+
+// OutcomeKind classifies a finished run, independent of the --fail-on policy.
+type OutcomeKind string
+
+const (
+	// OutcomeSuccess means nothing failed and the run was not only unsupported packages.
+	OutcomeSuccess OutcomeKind = "success"
+
+	// OutcomePartialSuccess means some packages failed while others succeeded.
+	OutcomePartialSuccess OutcomeKind = "partial-success"
+
+	// OutcomeFailure means packages failed and the run is not a partial success.
+	OutcomeFailure OutcomeKind = "failure"
+
+	// OutcomeOnlyUnsupported means nothing was updated or failed, but packages
+	// are unsupported: the run could not do anything.
+	OutcomeOnlyUnsupported OutcomeKind = "only-unsupported"
+)
+
+// RunOutcome summarizes a finished run for exit code selection.
+//
+// Fields:
+//   - Succeeded: Packages updated (or planned, for dry runs)
+//   - Failed: Failures recorded during the run
+//   - Unsupported: Packages that cannot be updated automatically
+//   - Partial: Whether the failures count as a partial success under the default mapping
+type RunOutcome struct {
+	Succeeded   int
+	Failed      int
+	Unsupported int
+	Partial     bool
+}
+
+// OnlyUnsupported reports whether the run neither updated nor failed anything
+// while packages are unsupported.
+//
+// Returns:
+//   - bool: true for zero succeeded, zero failed and at least one unsupported package
+func (o RunOutcome) OnlyUnsupported() bool {
+	return o.Succeeded == 0 && o.Failed == 0 && o.Unsupported > 0
+}
+
+// Kind classifies the outcome.
+//
+// Returns:
+//   - OutcomeKind: OutcomeFailure or OutcomePartialSuccess when packages failed,
+//     OutcomeOnlyUnsupported when only unsupported packages remain, OutcomeSuccess otherwise
+func (o RunOutcome) Kind() OutcomeKind {
+	switch {
+	case o.Failed > 0 && o.Partial:
+		return OutcomePartialSuccess
+	case o.Failed > 0:
+		return OutcomeFailure
+	case o.OnlyUnsupported():
+		return OutcomeOnlyUnsupported
+	}
+	return OutcomeSuccess
+}
+
+// OnlyUnsupportedExitCode returns the exit code for a run that could only
+// report unsupported packages.
+//
+// Parameters:
+//   - outcome: The finished run's counts
+//   - code: Exit code to use when outcome.OnlyUnsupported(), such as
+//     ExitConfigError or ExitOnlyUnsupported
+//
+// Returns:
+//   - int: code when nothing was updated or failed but packages are unsupported; ExitSuccess otherwise
+//
+// Example:
+//
+//	code := errors.OnlyUnsupportedExitCode(errors.RunOutcome{Unsupported: 2}, errors.ExitConfigError) // 3
+func OnlyUnsupportedExitCode(outcome RunOutcome, code int) int {
+	if outcome.OnlyUnsupported() {
+		return code
+	}
+	return ExitSuccess
+}
+
+// ParseFailOn parses a --fail-on value.
+//
+// Parameters:
+//   - value: One of none, any-failure, any-unsupported, only-unsupported, partial; empty selects partial
+//
+// Returns:
+//   - FailOnPolicy: The parsed policy
+//   - error: When value is not a known policy
+//
+// Example:
+//
+//	policy, err := errors.ParseFailOn("any-unsupported")
+func ParseFailOn(value string) (FailOnPolicy, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return FailOnPartial, nil
+	}
+	for _, policy := range FailOnPolicies {
+		if value == string(policy) {
+			return policy, nil
+		}
+	}
+
+	names := make([]string, len(FailOnPolicies))
+	for i, policy := range FailOnPolicies {
+		names[i] = string(policy)
+	}
+	return "", fmt.Errorf("invalid --fail-on value %q (expected one of: %s)", value, strings.Join(names, ", "))
+}
+
+// ExitCode maps a run outcome to an exit code under the policy.
+//
+// Parameters:
+//   - outcome: The finished run's counts
+//
+// Returns:
+//   - int: ExitSuccess, ExitPartialFailure, ExitFailure, ExitConfigError, or ExitOnlyUnsupported
+func (p FailOnPolicy) ExitCode(outcome RunOutcome) int {
+	switch p {
+	case FailOnNone:
+		return ExitSuccess
+	case FailOnAnyFailure:
+		if outcome.Failed > 0 {
+			return ExitFailure
+		}
+		return ExitSuccess
+	}
+
+	if outcome.Failed > 0 {
+		if outcome.Partial {
+			return ExitPartialFailure
+		}
+		return ExitFailure
+	}
+	switch p {
+	case FailOnAnyUnsupported:
+		if outcome.Unsupported > 0 {
+			return ExitConfigError
+		}
+	case FailOnOnlyUnsupported:
+		return OnlyUnsupportedExitCode(outcome, ExitOnlyUnsupported)
+	}
+	return ExitSuccess
+}
@@ -30,6 +30,7 @@ var CommandResolutionHints = map[string]string{
 	"node":     "Install Node.js: https://nodejs.org/",
 	"yarn":     "Install Yarn: https://yarnpkg.com/getting-started/install",
 	"pnpm":     "Install pnpm: https://pnpm.io/installation",
+	"bun":      "Install Bun: https://bun.sh/docs/installation",
 	"pip":      "Install Python: https://python.org/downloads/",
 	"pip3":     "Install Python: https://python.org/downloads/",
 	"python":   "Install Python: https://python.org/downloads/",
@@ -42,9 +43,14 @@ var CommandResolutionHints = map[string]string{
 	"gem":      "Install Ruby: https://ruby-lang.org/en/downloads/",
 	"bundle":   "Install Bundler: gem install bundler",
 	"bundler":  "Install Bundler: gem install bundler",
+	"brew":     "Install Homebrew: https://brew.sh/",
+	"dart":     "Install Dart: https://dart.dev/get-dart",
+	"flutter":  "Install Flutter: https://docs.flutter.dev/get-started/install",
+	"swift":    "Install Swift: https://www.swift.org/install/",
 	"cargo":    "Install Rust: https://rustup.rs/",
 	"mvn":      "Install Maven: https://maven.apache.org/install.html",
 	"gradle":   "Install Gradle: https://gradle.org/install/",
+	"deno":     "Install Deno: https://docs.deno.com/runtime/getting_started/installation/",
 
 	// Common Unix tools
 	"grep": "Unix tool - typically pre-installed on Linux/macOS",
@@ -53,6 +59,7 @@ var CommandResolutionHints = map[string]string{
 	"sort": "Unix tool - typically pre-installed on Linux/macOS",
 	"curl": "Install curl: https://curl.se/download.html (often pre-installed)",
 	"wget": "Install wget: https://www.gnu.org/software/wget/ or use curl instead",
+	"git":  "Install Git: https://git-scm.com/downloads",
 
 	// JSON/YAML processing tools
 	"jq": "Install jq: https://jqlang.github.io/jq/download/ (JSON processor)",
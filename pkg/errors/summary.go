@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"fmt"
+)
+
+// Summary aggregates what happened during an update run.
+//
+// It is the single source for the counts shown in the totals footer and for
+// the exit code, so embedders can inspect a run without re-deriving either
+// from individual results. Build it with update.BuildSummary.
+//
+// Fields:
+//   - Updated: Packages updated (or planned, for dry runs)
+//   - Failed: Failures recorded during the run
+//   - Unsupported: Packages that cannot be updated automatically
+//   - Partial: Whether the failures count as a partial success under the default mapping
+//   - PartialReason: Why the run is partial; empty when it is not
+//   - Policy: The --fail-on policy the exit code was derived with
+//   - ExitCode: ExitSuccess, ExitPartialFailure, ExitFailure, ExitConfigError, or ExitOnlyUnsupported
+//   - Failures: The errors recorded during the run that count towards the exit code, in order
+//   - Warned: Failures reported as warnings by the severity config
+//   - Warnings: The failures counted in Warned, in order
+//   - Severity: The effective severity mapping (e.g. "Failed=warn"); empty when it is the default
+type Summary struct {
+	Updated       int
+	Failed        int
+	Unsupported   int
+	Partial       bool
+	PartialReason string
+	Policy        FailOnPolicy
+	ExitCode      int
+	Failures      []error
+	Warned        int
+	Warnings      []error
+	Severity      []string
+}
+
+// NewSummary builds a summary from run counts and derives its exit code.
+//
+// Parameters:
+//   - outcome: The finished run's counts
+//   - partialReason: Why the run is partial; empty when outcome.Partial is false
+//   - failures: The errors recorded during the run
+//   - policy: The --fail-on policy; empty selects FailOnPartial
+//
+// Returns:
+//   - Summary: The summary with ExitCode set by policy.ExitCode
+func NewSummary(outcome RunOutcome, partialReason string, failures []error, policy FailOnPolicy) Summary {
+	if policy == "" {
+		policy = FailOnPartial
+	}
+	return Summary{
+		Updated:       outcome.Succeeded,
+		Failed:        outcome.Failed,
+		Unsupported:   outcome.Unsupported,
+		Partial:       outcome.Partial,
+		PartialReason: partialReason,
+		Policy:        policy,
+		ExitCode:      policy.ExitCode(outcome),
+		Failures:      failures,
+	}
+}
+
+// Outcome returns the counts the exit code was derived from.
+//
+// Returns:
+//   - RunOutcome: The summary's counts
+func (s Summary) Outcome() RunOutcome {
+	return RunOutcome{
+		Succeeded:   s.Updated,
+		Failed:      s.Failed,
+		Unsupported: s.Unsupported,
+		Partial:     s.Partial,
+	}
+}
+
+// Kind classifies the run independent of its policy.
+//
+// Returns:
+//   - OutcomeKind: The outcome's kind, e.g. OutcomePartialSuccess or OutcomeOnlyUnsupported
+func (s Summary) Kind() OutcomeKind {
+	return s.Outcome().Kind()
+}
+
+// Passed reports whether the run exits successfully under its policy.
+//
+// Returns:
+//   - bool: true when ExitCode is ExitSuccess
+func (s Summary) Passed() bool {
+	return s.ExitCode == ExitSuccess
+}
+
+// Reason describes the outcome kind and counts behind the exit code.
+//
+// The kind lets scripts tell a partial success from a run that found only
+// unsupported packages without parsing the counts.
+//
+// Returns:
+//   - string: For example "--fail-on partial: partial-success (3 succeeded, 1 failed, 0 unsupported)"
+func (s Summary) Reason() string {
+	return fmt.Sprintf("--fail-on %s: %s (%d succeeded, %d failed, %d unsupported)", s.Policy, s.Kind(), s.Updated, s.Failed, s.Unsupported)
+}
@@ -22,6 +22,11 @@ const (
 	// ExitConfigError indicates a configuration or validation error.
 	// The command could not proceed due to invalid config or missing requirements.
 	ExitConfigError = 3
+
+	// ExitOnlyUnsupported indicates nothing was updated or failed because every
+	// candidate package is unsupported (floating constraint, no lock file, ...).
+	// It is only returned with --fail-on only-unsupported.
+	ExitOnlyUnsupported = 4
 )
 
 // ExitError represents a command termination with a specific exit code.
@@ -32,7 +37,8 @@ const (
 // Fields:
 //   - Code: Exit code (use constants ExitSuccess, ExitError, ExitPartialSuccess)
 //   - Message: Human-readable error message
-//   - Err: Underlying error that caused this exit, may be nil
+//   - Err: Underlying error that caused this exit, may be nil; reachable through errors.Is/As
+//   - Reason: Why this exit code was chosen (e.g. the --fail-on policy that applied)
 //
 // Example:
 //
@@ -50,8 +56,13 @@ type ExitError struct {
 	Message string
 
 	// Err is the underlying error that caused this exit.
-	// May be nil if no underlying error exists.
+	// May be nil if no underlying error exists. When Message is set, Err is
+	// only the cause for errors.Is/As and does not change Error().
 	Err error
+
+	// Reason explains why this exit code was chosen, such as the --fail-on
+	// policy that mapped the run outcome to Code. It is not part of Error().
+	Reason string
 }
 
 // Error implements the error interface.
@@ -97,11 +108,51 @@ func NewExitError(code int, err error) *ExitError {
 	return &ExitError{Code: code, Err: err}
 }
 
+// WithReason records why the exit code was chosen.
+//
+// Parameters:
+//   - reason: Description of the policy or outcome behind Code
+//
+// Returns:
+//   - *ExitError: The same error, for chaining
+//
+// Example:
+//
+//	return errors.NewExitError(errors.ExitConfigError, err).WithReason("--fail-on any-unsupported: 2 unsupported packages")
+func (e *ExitError) WithReason(reason string) *ExitError {
+	e.Reason = reason
+	return e
+}
+
+// WithCause records the underlying error behind the exit's message.
+//
+// The message stays what Error() and PrintErrorWithHints show; the cause is
+// what errors.Is and errors.As reach through Unwrap.
+//
+// Parameters:
+//   - cause: The original error, e.g. a *ValidationError or a network error; nil keeps the current cause
+//
+// Returns:
+//   - *ExitError: The same error, for chaining
+//
+// Example:
+//
+//	return errors.NewExitErrorf(errors.ExitConfigError, "validation failed: %d problems found", n).WithCause(result.Err())
+func (e *ExitError) WithCause(cause error) *ExitError {
+	if cause != nil {
+		e.Err = cause
+	}
+	return e
+}
+
 // NewExitErrorf creates an ExitError with the given code and formatted message.
 //
+// Like fmt.Errorf, the %w verb wraps its operand: the formatted text becomes
+// the message and the wrapped error becomes the cause returned by Unwrap.
+//
 // Parameters:
 //   - code: Exit code
-//   - format: Printf-style format string
+//   - format: Printf-style format string, may contain %w
 //   - args: Format arguments
 //
 // Returns:
@@ -109,9 +160,18 @@ func NewExitError(code int, err error) *ExitError {
 //
 // Example:
 //
-//	err := errors.NewExitErrorf(errors.ExitFailure, "failed to process %s", filename)
+//	err := errors.NewExitErrorf(errors.ExitFailure, "failed to process %s: %w", filename, err)
 func NewExitErrorf(code int, format string, args ...interface{}) *ExitError {
-	return &ExitError{Code: code, Message: fmt.Sprintf(format, args...)}
+	formatted := fmt.Errorf(format, args...)
+	exitErr := &ExitError{Code: code, Message: formatted.Error()}
+	switch wrapped := formatted.(type) {
+	case interface{ Unwrap() error }:
+		exitErr.Err = wrapped.Unwrap()
+	case interface{ Unwrap() []error }:
+		// Several %w verbs: keep the formatted error, which unwraps to all of them
+		exitErr.Err = formatted
+	}
+	return exitErr
 }
 
 // GetExitCode extracts the exit code from an error.
@@ -165,6 +225,27 @@ func IsExitError(err error) (*ExitError, bool) {
 	return nil, false
 }
 
+// JoinErrors joins a list of errors into a single error.
+//
+// Each element stays reachable through errors.Is and errors.As, so callers can
+// return a result's errors as one value without losing their types.
+//
+// Parameters:
+//   - errs: The errors to join
+//
+// Returns:
+//   - error: The joined errors; nil when errs is empty
+func JoinErrors[E error](errs []E) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	joined := make([]error, 0, len(errs))
+	for _, err := range errs {
+		joined = append(joined, err)
+	}
+	return errors.Join(joined...)
+}
+
 // PartialSuccessError indicates that some operations succeeded while others failed.
 //
 // This is used when processing multiple packages and some updates succeed
@@ -226,7 +307,7 @@ func NewPartialSuccessError(succeeded, failed int, errs []error) *PartialSuccess
 	}
 }
 
-// IsPartialSuccess checks if err is a PartialSuccessError and returns it.
+// IsPartialSuccessError checks if err is a PartialSuccessError and returns it.
 //
 // Parameters:
 //   - err: The error to check
@@ -237,10 +318,10 @@ func NewPartialSuccessError(succeeded, failed int, errs []error) *PartialSuccess
 //
 // Example:
 //
-//	if pse, ok := errors.IsPartialSuccess(err); ok {
+//	if pse, ok := errors.IsPartialSuccessError(err); ok {
 //	    fmt.Printf("%d succeeded, %d failed\n", pse.Succeeded, pse.Failed)
 //	}
-func IsPartialSuccess(err error) (*PartialSuccessError, bool) {
+func IsPartialSuccessError(err error) (*PartialSuccessError, bool) {
 	var pse *PartialSuccessError
 	if errors.As(err, &pse) {
 		return pse, true
@@ -248,6 +329,20 @@ func IsPartialSuccess(err error) (*PartialSuccessError, bool) {
 	return nil, false
 }
 
+// IsPartialSuccess checks if err is a PartialSuccessError and returns it.
+//
+// It is equivalent to IsPartialSuccessError and kept for existing callers.
+//
+// Parameters:
+//   - err: The error to check
+//
+// Returns:
+//   - *PartialSuccessError: The PartialSuccessError if err is one, nil otherwise
+//   - bool: true if err is a PartialSuccessError
+func IsPartialSuccess(err error) (*PartialSuccessError, bool) {
+	return IsPartialSuccessError(err)
+}
+
 // UnsupportedError indicates an operation is not supported for a package.
 //
 // This replaces the separate UnsupportedError types from pkg/outdated
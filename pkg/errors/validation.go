@@ -158,6 +158,12 @@ func (e *ValidationError) VerboseError() string {
 // Returns:
 //   - *ValidationError: The ValidationError if err is one, nil otherwise
 //   - bool: true if err is a ValidationError
+//
+// Example:
+//
+//	if ve, ok := errors.IsValidationError(err); ok {
+//	    fmt.Printf("invalid %s: %s\n", ve.Field, ve.Message)
+//	}
 func IsValidationError(err error) (*ValidationError, bool) {
 	var ve *ValidationError
 	if errors.As(err, &ve) {
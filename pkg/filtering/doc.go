@@ -21,6 +21,14 @@
 //	opts := filtering.FromFlags(typeFlag, pmFlag, ruleFlag, nameFlag, groupFlag)
 //	filtered := filtering.FilterPackages(packages, opts)
 //
+// Select Expressions:
+//
+// ParseSelect compiles a --select expression into a Selector. Plain ANDed
+// equality comparisons reuse FilterOptions; anything else is matched per package:
+//
+//	sel, err := filtering.ParseSelect("rule==npm && type==prod || gap>=major")
+//	filtered := filtering.SelectPackages(packages, sel)
+//
 // Group Assignment:
 //
 // Assign groups to packages based on configuration:
@@ -32,4 +40,8 @@
 // Sort packages for consistent display:
 //
 //	sorted := filtering.SortPackagesForDisplay(packages)
+//
+// Or pick a different primary key (name, rule, version-gap, status):
+//
+//	sorted := filtering.SortPackagesForDisplayBy(packages, filtering.SortByVersionGap)
 package filtering
@@ -0,0 +1,96 @@
+package filtering
+
+import (
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// declaredRangeOperators maps declaration constraints to range operators.
+//
+// "==" and "exact" pin a version, "~=" is the compatible-release operator;
+// constraints outside this map cannot be checked for drift.
+var declaredRangeOperators = map[string]string{
+	"":      "=",
+	"=":     "=",
+	"==":    "=",
+	"exact": "=",
+	"^":     "^",
+	"~":     "~",
+	"~=":    "~",
+	">=":    ">=",
+	"<=":    "<=",
+	">":     ">",
+	"<":     "<",
+}
+
+// IsDrifted reports whether a package's installed version falls outside its declaration.
+//
+// The declaration is the package's Constraint applied to its Version, with
+// npm semantics: "^1.2.3" allows the same major, "~1.2.3" the same minor, and
+// an exact partial version such as "1.2" the whole 1.2.x line. Packages whose
+// installed or declared version is missing, not semver, or declared with a
+// constraint that cannot be checked are never drifted.
+//
+// Parameters:
+//   - p: Package with its declared and installed versions
+//
+// Returns:
+//   - bool: true if the installed version does not satisfy the declaration
+//
+// Example:
+//
+//	p := formats.Package{Version: "1.4.0", Constraint: "^", InstalledVersion: "2.0.1"}
+//	filtering.IsDrifted(p) // true
+func IsDrifted(p formats.Package) bool {
+	installed := strings.TrimSpace(p.InstalledVersion)
+	if installed == "" || installed == constants.PlaceholderNA {
+		return false
+	}
+	if _, ok := canonicalRangeVersion(installed); !ok {
+		return false
+	}
+
+	declared, ok := declaredRange(p)
+	if !ok {
+		return false
+	}
+	return !declared.Contains(installed)
+}
+
+// declaredRange builds the range a package's declaration allows.
+//
+// Parameters:
+//   - p: Package with Version and Constraint
+//
+// Returns:
+//   - VersionRange: The declared range
+//   - bool: false if the declaration is missing, a wildcard, or cannot be parsed
+func declaredRange(p formats.Package) (VersionRange, bool) {
+	version := strings.TrimSpace(p.Version)
+	if version == "" || version == constants.PlaceholderNA || version == constants.PlaceholderWildcard {
+		return VersionRange{}, false
+	}
+
+	op, ok := declaredRangeOperators[strings.ToLower(strings.TrimSpace(p.Constraint))]
+	if !ok {
+		return VersionRange{}, false
+	}
+
+	expr := op + version
+	if op == "=" {
+		// A version that carries its own operator ("^1.2.0") is used as written
+		expr = version
+		bare := strings.TrimPrefix(version, "v")
+		if bare != "" && bare[0] >= '0' && bare[0] <= '9' && len(strings.Split(bare, ".")) < 3 {
+			expr += ".x"
+		}
+	}
+
+	r, err := ParseVersionRange(expr)
+	if err != nil {
+		return VersionRange{}, false
+	}
+	return r, true
+}
@@ -0,0 +1,78 @@
+package filtering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestIsDrifted tests detecting installed versions outside their declaration.
+//
+// It verifies:
+//   - Caret, tilde, comparator and exact declarations are honoured
+//   - Exact partial versions cover their whole line
+//   - A version written with its own operator is used as written
+//   - Missing, placeholder, wildcard and non-semver versions are never drifted
+//   - Unknown constraints are never drifted
+func TestIsDrifted(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		constraint string
+		installed  string
+		want       bool
+	}{
+		{name: "caret satisfied", version: "1.4.0", constraint: "^", installed: "1.9.2"},
+		{name: "caret drifted", version: "1.4.0", constraint: "^", installed: "2.0.1", want: true},
+		{name: "tilde drifted", version: "1.4.0", constraint: "~", installed: "1.5.0", want: true},
+		{name: "compatible release", version: "1.4", constraint: "~=", installed: "1.4.7"},
+		{name: "lower bound drifted", version: "2.0.0", constraint: ">=", installed: "1.9.0", want: true},
+		{name: "exact satisfied", version: "v1.2.3", constraint: "", installed: "v1.2.3"},
+		{name: "exact drifted", version: "1.2.3", constraint: "==", installed: "1.2.4", want: true},
+		{name: "partial exact line", version: "1.2", constraint: "", installed: "1.2.9"},
+		{name: "partial exact drifted", version: "1.2", constraint: "=", installed: "1.3.0", want: true},
+		{name: "operator in version", version: "^2.1.0", constraint: "", installed: "3.0.0", want: true},
+		{name: "missing installed", version: "1.0.0", constraint: "^", installed: ""},
+		{name: "placeholder installed", version: "1.0.0", constraint: "^", installed: "#N/A"},
+		{name: "non-semver installed", version: "1.0.0", constraint: "^", installed: "latest"},
+		{name: "missing declared", version: "", constraint: "", installed: "1.0.0"},
+		{name: "wildcard declared", version: "*", constraint: "", installed: "9.0.0"},
+		{name: "unknown constraint", version: "1.0", constraint: "~>", installed: "3.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := formats.Package{Name: "pkg", Version: tt.version, Constraint: tt.constraint, InstalledVersion: tt.installed}
+			assert.Equal(t, tt.want, IsDrifted(p))
+		})
+	}
+}
+
+// TestFilterPackagesDriftedOnly tests the DriftedOnly filter option.
+//
+// It verifies:
+//   - Only drifted packages are kept and other filters still apply
+//   - DriftedOnly makes the options non-empty and needs installed versions
+func TestFilterPackagesDriftedOnly(t *testing.T) {
+	pkgs := []formats.Package{
+		{Name: "react", Type: "prod", Version: "17.0.0", Constraint: "^", InstalledVersion: "18.2.0"},
+		{Name: "jest", Type: "dev", Version: "29.0.0", Constraint: "^", InstalledVersion: "30.0.0"},
+		{Name: "vue", Type: "prod", Version: "3.0.0", Constraint: "^", InstalledVersion: "3.4.0"},
+		{Name: "lodash", Type: "prod", Version: "4.17.0", Constraint: "^"},
+	}
+
+	opts := FilterOptions{DriftedOnly: true, Type: "prod"}
+	filtered := FilterPackages(pkgs, opts)
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, "react", filtered[0].Name)
+	}
+	assert.Len(t, FilterPackages(pkgs, FilterOptions{DriftedOnly: true}), 2)
+
+	assert.False(t, opts.IsEmpty())
+	assert.True(t, opts.HasDriftFilter())
+	assert.True(t, opts.HasInstalledFilter())
+	assert.False(t, FilterOptions{}.HasInstalledFilter())
+	assert.True(t, FilterOptions{VersionConstraint: "<2"}.HasInstalledFilter())
+}
@@ -276,6 +276,11 @@ func TestFilterOptionsIsEmpty(t *testing.T) {
 		opts := FilterOptions{File: "*.json"}
 		assert.False(t, opts.IsEmpty())
 	})
+
+	t.Run("with security filter", func(t *testing.T) {
+		opts := FilterOptions{SecurityOnly: true}
+		assert.False(t, opts.IsEmpty())
+	})
 }
 
 // TestFilterOptionsHasTypeFilter tests the HasTypeFilter method.
@@ -349,6 +354,57 @@ func TestFilterOptionsHasFileFilter(t *testing.T) {
 	assert.True(t, FilterOptions{File: "*.json"}.HasFileFilter())
 }
 
+// nameAdvisoryMatcher marks packages as affected by name.
+type nameAdvisoryMatcher map[string]bool
+
+// IsAffected reports whether the package name is marked as affected.
+func (m nameAdvisoryMatcher) IsAffected(p formats.Package) bool {
+	return m[p.Name]
+}
+
+// TestFilterOptionsHasSecurityFilter tests the HasSecurityFilter method.
+//
+// It verifies that:
+//   - SecurityOnly false returns false
+//   - SecurityOnly true returns true
+func TestFilterOptionsHasSecurityFilter(t *testing.T) {
+	assert.False(t, FilterOptions{}.HasSecurityFilter())
+	assert.True(t, FilterOptions{SecurityOnly: true}.HasSecurityFilter())
+}
+
+// TestFilterPackagesSecurityOnly tests FilterPackages with SecurityOnly set.
+//
+// It verifies that:
+//   - Only packages reported as affected are kept
+//   - A nil matcher filters out every package
+//   - SecurityOnly combines with other filters
+func TestFilterPackagesSecurityOnly(t *testing.T) {
+	pkgs := []formats.Package{
+		{Name: "lodash", Type: "prod", Rule: "npm"},
+		{Name: "react", Type: "prod", Rule: "npm"},
+		{Name: "minimist", Type: "dev", Rule: "npm"},
+	}
+	matcher := nameAdvisoryMatcher{"lodash": true, "minimist": true}
+
+	t.Run("keeps affected packages", func(t *testing.T) {
+		result := FilterPackages(pkgs, FilterOptions{SecurityOnly: true, Advisories: matcher})
+		assert.Len(t, result, 2)
+		assert.Equal(t, "lodash", result[0].Name)
+		assert.Equal(t, "minimist", result[1].Name)
+	})
+
+	t.Run("nil matcher keeps nothing", func(t *testing.T) {
+		result := FilterPackages(pkgs, FilterOptions{SecurityOnly: true})
+		assert.Empty(t, result)
+	})
+
+	t.Run("combined with type filter", func(t *testing.T) {
+		result := FilterPackages(pkgs, FilterOptions{Type: "prod", SecurityOnly: true, Advisories: matcher})
+		assert.Len(t, result, 1)
+		assert.Equal(t, "lodash", result[0].Name)
+	})
+}
+
 // TestFromFlagsWithFile tests the FromFlagsWithFile constructor.
 //
 // Parameters:
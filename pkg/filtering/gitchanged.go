@@ -0,0 +1,141 @@
+package filtering
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// gitCommandFunc runs a git command in a directory; replaced in tests.
+var gitCommandFunc = runGitCommand
+
+// runGitCommand runs git with the given arguments in dir.
+//
+// Parameters:
+//   - dir: Directory to run git in
+//   - args: Arguments passed to git
+//
+// Returns:
+//   - []byte: Standard output of the command
+//   - error: When git fails, including its trimmed error output
+func runGitCommand(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		detail := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			detail = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return out, fmt.Errorf("git %s: %w: %s", args[0], err, detail)
+	}
+	return out, nil
+}
+
+// ChangedFilesSinceGit lists the files changed on the current branch since a git ref.
+//
+// It performs the following operations:
+//   - Step 1: Reject a ref starting with "-", which git would read as an option
+//   - Step 2: Find the root of the git working tree containing dir
+//   - Step 3: Run "git diff --name-only <ref>...HEAD", i.e. compare HEAD with its merge base with ref
+//   - Step 4: Resolve the listed paths against the working tree root
+//
+// Uncommitted changes are not included.
+//
+// Parameters:
+//   - dir: Directory inside the git working tree
+//   - ref: Base ref, e.g. "origin/main"
+//
+// Returns:
+//   - map[string]bool: Absolute, symlink-resolved paths of the changed files
+//   - error: When ref starts with "-", dir is not inside a git working tree, or ref cannot be compared
+func ChangedFilesSinceGit(dir, ref string) (map[string]bool, error) {
+	if strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid --changed-since-git ref %q: a ref cannot start with '-'", ref)
+	}
+
+	out, err := gitCommandFunc(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("--changed-since-git requires a git repository: %s is not inside a git working tree: %w", dir, err)
+	}
+	root := resolvePath(strings.TrimSpace(string(out)))
+
+	out, err = gitCommandFunc(dir, "diff", "--name-only", ref+"...HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files changed since %s: %w", ref, err)
+	}
+
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			changed[filepath.Join(root, filepath.FromSlash(line))] = true
+		}
+	}
+	verbose.Debugf("%d file(s) changed since %s", len(changed), ref)
+	return changed, nil
+}
+
+// FilterPackagesChangedSinceGit keeps the packages whose manifest changed since a git ref.
+//
+// Packages declared in manifests that did not change are excluded, as are
+// packages without a source manifest.
+//
+// Parameters:
+//   - pkgs: The packages to filter
+//   - ref: Base ref, e.g. "origin/main"; empty disables the filter
+//   - baseDir: Directory inside the git working tree; relative sources are resolved against it
+//
+// Returns:
+//   - []formats.Package: Packages from manifests changed since ref
+//   - error: When baseDir is not inside a git working tree or ref cannot be compared
+//
+// Example:
+//
+//	filtered, err := filtering.FilterPackagesChangedSinceGit(pkgs, "origin/main", "/project")
+func FilterPackagesChangedSinceGit(pkgs []formats.Package, ref, baseDir string) ([]formats.Package, error) {
+	if ref == "" {
+		return pkgs, nil
+	}
+
+	changed, err := ChangedFilesSinceGit(baseDir, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []formats.Package
+	for _, p := range pkgs {
+		if p.Source == "" {
+			continue
+		}
+		source := p.Source
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(baseDir, source)
+		}
+		if changed[resolvePath(source)] {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// resolvePath returns the absolute path with symlinks resolved, so paths
+// reported by git compare equal to paths found while scanning.
+//
+// Parameters:
+//   - path: The path to resolve
+//
+// Returns:
+//   - string: The resolved path; the cleaned absolute path when it cannot be resolved
+func resolvePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
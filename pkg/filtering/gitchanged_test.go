@@ -0,0 +1,74 @@
+package filtering
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestFilterPackagesChangedSinceGit tests limiting packages to manifests changed since a git ref.
+//
+// It verifies:
+//   - An empty ref returns the packages unchanged without running git
+//   - git diff compares HEAD with the merge base of the ref
+//   - Packages from changed manifests are kept, relative sources included
+//   - Packages from unchanged manifests or without a source are excluded
+//   - A ref starting with "-" is rejected before git runs
+//   - A directory outside a git working tree fails with a clear error
+func TestFilterPackagesChangedSinceGit(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"web/package.json", "api/composer.json"} {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+	}
+
+	var calls [][]string
+	original := gitCommandFunc
+	t.Cleanup(func() { gitCommandFunc = original })
+	gitCommandFunc = func(dir string, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		if args[0] == "rev-parse" {
+			return []byte(root + "\n"), nil
+		}
+		return []byte("web/package.json\nREADME.md\n"), nil
+	}
+
+	pkgs := []formats.Package{
+		{Name: "react", Source: filepath.Join(root, "web", "package.json")},
+		{Name: "react-dom", Source: filepath.Join("web", "package.json")},
+		{Name: "monolog/monolog", Source: filepath.Join(root, "api", "composer.json")},
+		{Name: "virtual"},
+	}
+
+	unchanged, err := FilterPackagesChangedSinceGit(pkgs, "", root)
+	require.NoError(t, err)
+	assert.Equal(t, pkgs, unchanged)
+	assert.Empty(t, calls)
+
+	filtered, err := FilterPackagesChangedSinceGit(pkgs, "origin/main", root)
+	require.NoError(t, err)
+	var names []string
+	for _, p := range filtered {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"react", "react-dom"}, names)
+	assert.Equal(t, []string{"diff", "--name-only", "origin/main...HEAD"}, calls[1])
+
+	calls = nil
+	_, err = FilterPackagesChangedSinceGit(pkgs, "--output=/tmp/owned", root)
+	assert.ErrorContains(t, err, "cannot start with '-'")
+	assert.Empty(t, calls)
+
+	gitCommandFunc = func(dir string, args ...string) ([]byte, error) {
+		return nil, errors.New("git rev-parse: exit status 128: fatal: not a git repository")
+	}
+	_, err = FilterPackagesChangedSinceGit(pkgs, "origin/main", root)
+	assert.ErrorContains(t, err, "--changed-since-git requires a git repository")
+}
@@ -1,10 +1,12 @@
 package filtering
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/formats"
 )
 
@@ -141,6 +143,70 @@ func SortedGroupKeys(groups map[string]config.GroupCfg) []string {
 	return keys
 }
 
+// ValidateGroupMembers reports group members that match no detected package.
+//
+// Rule-level groups (rules.<rule>.groups) are checked against the packages of
+// their rule and only when that rule detected at least one package, so groups
+// of rules unused in the project are not reported. Top-level groups are
+// checked against all packages. Matching is case-insensitive, like
+// PackageMatchesGroup.
+//
+// Parameters:
+//   - pkgs: Detected packages
+//   - cfg: Configuration containing group definitions
+//
+// Returns:
+//   - []*errors.ValidationError: One config error per unmatched member, in group order
+func ValidateGroupMembers(pkgs []formats.Package, cfg *config.Config) []*errors.ValidationError {
+	if len(pkgs) == 0 {
+		return nil
+	}
+
+	namesByRule := make(map[string]map[string]bool)
+	allNames := make(map[string]bool)
+	for _, p := range pkgs {
+		name := strings.ToLower(p.Name)
+		if namesByRule[p.Rule] == nil {
+			namesByRule[p.Rule] = make(map[string]bool)
+		}
+		namesByRule[p.Rule][name] = true
+		allNames[name] = true
+	}
+
+	var errs []*errors.ValidationError
+	check := func(field string, group config.GroupCfg, names map[string]bool, scope string) {
+		for _, member := range group.Packages {
+			trimmed := strings.TrimSpace(member)
+			if trimmed == "" || names[strings.ToLower(trimmed)] {
+				continue
+			}
+			errs = append(errs, errors.NewConfigValidationError(field, fmt.Sprintf("package %q does not match any package detected %s", trimmed, scope)))
+		}
+	}
+
+	ruleKeys := make([]string, 0, len(cfg.Rules))
+	for ruleKey := range cfg.Rules {
+		ruleKeys = append(ruleKeys, ruleKey)
+	}
+	sort.Strings(ruleKeys)
+	for _, ruleKey := range ruleKeys {
+		names, ok := namesByRule[ruleKey]
+		if !ok {
+			continue
+		}
+		groups := cfg.Rules[ruleKey].Groups
+		for _, groupID := range SortedGroupKeys(groups) {
+			check(fmt.Sprintf("rules.%s.groups.%s", ruleKey, groupID), groups[groupID], names, "for rule "+ruleKey)
+		}
+	}
+
+	for _, groupID := range SortedGroupKeys(cfg.Groups) {
+		check("groups."+groupID, cfg.Groups[groupID], allNames, "in any rule")
+	}
+
+	return errs
+}
+
 // ResolveUpdateGroup returns the group from update config if set.
 //
 // Parameters:
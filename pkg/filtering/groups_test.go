@@ -287,3 +287,42 @@ func TestGroupPackages(t *testing.T) {
 	assert.Len(t, result["utils"], 1)
 	assert.Len(t, result[""], 1)
 }
+
+// TestValidateGroupMembers tests the behavior of ValidateGroupMembers.
+//
+// It verifies:
+//   - Rule-level group members must match a package of their rule
+//   - Top-level group members may match a package of any rule
+//   - Matching is case-insensitive and blank members are ignored
+//   - Groups of rules without detected packages are not checked
+//   - No packages produce no errors
+func TestValidateGroupMembers(t *testing.T) {
+	cfg := &config.Config{
+		Groups: map[string]config.GroupCfg{
+			"core": {Packages: []string{"lodash", "requests", "django"}},
+		},
+		Rules: map[string]config.PackageManagerCfg{
+			"npm": {Groups: map[string]config.GroupCfg{
+				"frontend": {Packages: []string{"React", " ", "requests"}},
+			}},
+			"composer": {Groups: map[string]config.GroupCfg{
+				"laravel": {Packages: []string{"laravel/framework"}},
+			}},
+		},
+	}
+	pkgs := []formats.Package{
+		{Name: "react", Rule: "npm"},
+		{Name: "lodash", Rule: "npm"},
+		{Name: "requests", Rule: "pip"},
+	}
+
+	errs := ValidateGroupMembers(pkgs, cfg)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "rules.npm.groups.frontend", errs[0].Field)
+		assert.Contains(t, errs[0].Message, `"requests"`)
+		assert.Equal(t, "groups.core", errs[1].Field)
+		assert.Contains(t, errs[1].Message, `"django"`)
+	}
+
+	assert.Empty(t, ValidateGroupMembers(nil, cfg))
+}
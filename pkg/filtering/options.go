@@ -1,6 +1,9 @@
 package filtering
 
 import (
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/utils"
 )
 
@@ -19,6 +22,9 @@ const FilterAll = "all"
 //   - Name: Package name (case-insensitive)
 //   - Group: Package group (case-insensitive)
 //   - File: File path patterns (supports globs)
+//   - SecurityOnly: Keep only packages affected by a known advisory
+//   - Advisories: Advisory data used when SecurityOnly is set
+//   - VersionConstraint: Semver range the installed version must satisfy
 type FilterOptions struct {
 	// Type filters by dependency type (prod, dev, all).
 	Type string
@@ -37,6 +43,32 @@ type FilterOptions struct {
 
 	// File filters by file path patterns (comma-separated, supports globs).
 	File string
+
+	// SecurityOnly keeps only packages whose current version is affected by
+	// at least one advisory in Advisories.
+	SecurityOnly bool
+
+	// Advisories reports which packages are affected by known advisories.
+	// When SecurityOnly is set and Advisories is nil, no packages match.
+	Advisories AdvisoryMatcher
+
+	// VersionConstraint keeps packages whose InstalledVersion satisfies the
+	// semver range (e.g. "<2.0.0", ">=1.2 <2"). Packages with a missing or
+	// non-semver installed version are excluded. See ParseVersionRange.
+	VersionConstraint string
+
+	// DriftedOnly keeps packages whose InstalledVersion does not satisfy the
+	// declared Version and Constraint. Packages with a missing installed or
+	// declared version are excluded. See IsDrifted.
+	DriftedOnly bool
+}
+
+// AdvisoryMatcher reports whether a package is affected by a known advisory.
+//
+// Standard implementation: *security.Report
+type AdvisoryMatcher interface {
+	// IsAffected returns true if the package's current version has at least one advisory.
+	IsAffected(p formats.Package) bool
 }
 
 // parsedFilters holds pre-parsed filter slices for efficient matching.
@@ -103,7 +135,29 @@ func (o FilterOptions) IsEmpty() bool {
 		(o.Rule == "" || o.Rule == FilterAll) &&
 		o.Name == "" &&
 		o.Group == "" &&
-		o.File == ""
+		o.File == "" &&
+		!o.SecurityOnly &&
+		o.VersionConstraint == "" &&
+		!o.DriftedOnly
+}
+
+// Validate checks that the filter options are well formed.
+//
+// Returns:
+//   - error: *errors.ValidationError when VersionConstraint is not a valid range; nil otherwise
+//
+// Example:
+//
+//	opts := filtering.FilterOptions{VersionConstraint: "<2.0.0"}
+//	if err := opts.Validate(); err != nil {
+//	    return err
+//	}
+func (o FilterOptions) Validate() error {
+	if !o.HasVersionConstraint() {
+		return nil
+	}
+	_, err := ParseVersionRange(o.VersionConstraint)
+	return err
 }
 
 // HasTypeFilter returns true if a type filter is set and not "all".
@@ -146,6 +200,38 @@ func (o FilterOptions) HasGroupFilter() bool {
 	return o.Group != ""
 }
 
+// HasSecurityFilter returns true if security-only filtering is enabled.
+//
+// Returns:
+//   - bool: true if SecurityOnly is set
+func (o FilterOptions) HasSecurityFilter() bool {
+	return o.SecurityOnly
+}
+
+// HasVersionConstraint returns true if a version range filter is set.
+//
+// Returns:
+//   - bool: true if VersionConstraint is set to a non-blank value
+func (o FilterOptions) HasVersionConstraint() bool {
+	return strings.TrimSpace(o.VersionConstraint) != ""
+}
+
+// HasDriftFilter returns true if only drifted packages are kept.
+//
+// Returns:
+//   - bool: true if DriftedOnly is set
+func (o FilterOptions) HasDriftFilter() bool {
+	return o.DriftedOnly
+}
+
+// HasInstalledFilter returns true if a filter needs installed versions.
+//
+// Returns:
+//   - bool: true if a version range or the drift filter is set
+func (o FilterOptions) HasInstalledFilter() bool {
+	return o.HasVersionConstraint() || o.HasDriftFilter()
+}
+
 // HasFileFilter returns true if a file filter is set.
 //
 // Returns:
@@ -303,3 +389,20 @@ func (o FilterOptions) WithFile(file string) FilterOptions {
 	o.File = file
 	return o
 }
+
+// WithVersionConstraint returns a copy with the version range filter set.
+//
+// Parameters:
+//   - constraint: Semver range the installed version must satisfy
+//
+// Returns:
+//   - FilterOptions: New FilterOptions with updated VersionConstraint field
+//
+// Example:
+//
+//	opts := filtering.FilterOptions{}
+//	opts = opts.WithVersionConstraint("<2.0.0")
+func (o FilterOptions) WithVersionConstraint(constraint string) FilterOptions {
+	o.VersionConstraint = constraint
+	return o
+}
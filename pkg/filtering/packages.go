@@ -7,8 +7,10 @@ import (
 
 // FilterPackages filters packages based on the provided options.
 //
-// Applies all filters in sequence: type, pm, rule, name, group.
-// Packages must match ALL specified filters to be included.
+// Applies all filters in sequence: type, pm, rule, name, group, security,
+// version range, drift. Packages must match ALL specified filters to be included.
+// An invalid VersionConstraint matches no packages; call
+// FilterOptions.Validate first to report it.
 //
 // Parameters:
 //   - pkgs: Slice of packages to filter
@@ -41,12 +43,33 @@ func FilterPackages(pkgs []formats.Package, opts FilterOptions) []formats.Packag
 		if !matchesGroup(p, opts.Group, parsed.groups) {
 			continue
 		}
+		if opts.SecurityOnly && !matchesAdvisories(p, opts.Advisories) {
+			continue
+		}
+		if opts.HasVersionConstraint() && !matchesVersionRange(p, opts.VersionConstraint) {
+			continue
+		}
+		if opts.DriftedOnly && !IsDrifted(p) {
+			continue
+		}
 		filtered = append(filtered, p)
 	}
 
 	return filtered
 }
 
+// matchesAdvisories checks if a package is affected by a known advisory.
+//
+// Parameters:
+//   - p: Package to check
+//   - advisories: Advisory data; nil matches nothing
+//
+// Returns:
+//   - bool: true if the package is affected by at least one advisory
+func matchesAdvisories(p formats.Package, advisories AdvisoryMatcher) bool {
+	return advisories != nil && advisories.IsAffected(p)
+}
+
 // FilterPackagesWithFilters filters packages based on type, package manager, rule, name, and group flags.
 //
 // This is a convenience function that creates FilterOptions from individual flag values.
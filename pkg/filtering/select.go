@@ -0,0 +1,568 @@
+package filtering
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// SelectFields lists the fields a --select expression can compare, in documentation order.
+var SelectFields = []string{"rule", "pm", "type", "name", "installed", "declared", "status", "gap"}
+
+// SelectGaps lists the values of the gap field from smallest to largest.
+var SelectGaps = []string{"none", "patch", "minor", "major"}
+
+// selectExpected describes valid --select syntax in validation errors.
+const selectExpected = "comparisons such as rule==npm or gap>=major joined with &&, || and !, grouped with parentheses; fields: rule, pm, type, name, installed, declared, status, gap"
+
+// Selector is a parsed --select expression.
+//
+// A nil *Selector matches every package, so callers can pass the result of
+// ParseSelect("") around without checking it.
+type Selector struct {
+	expr       string
+	root       selectNode
+	usesGap    bool
+	simpleOpts *FilterOptions
+}
+
+// selectNode is a node of a parsed --select expression.
+type selectNode interface {
+	match(p formats.Package) bool
+}
+
+// selectAnd matches when both sides match.
+type selectAnd struct{ left, right selectNode }
+
+func (n selectAnd) match(p formats.Package) bool { return n.left.match(p) && n.right.match(p) }
+
+// selectOr matches when either side matches.
+type selectOr struct{ left, right selectNode }
+
+func (n selectOr) match(p formats.Package) bool { return n.left.match(p) || n.right.match(p) }
+
+// selectNot matches when its operand does not.
+type selectNot struct{ node selectNode }
+
+func (n selectNot) match(p formats.Package) bool { return !n.node.match(p) }
+
+// selectCompare is a single "field op value" comparison.
+//
+// Fields:
+//   - field: Compared field, one of SelectFields
+//   - op: Comparison operator
+//   - value: Value compared against
+//   - opts: Filter options equivalent to field==value, when FilterOptions can express it
+//   - fn: Compiled comparison
+type selectCompare struct {
+	field string
+	op    string
+	value string
+	opts  *FilterOptions
+	fn    func(p formats.Package) bool
+}
+
+func (n selectCompare) match(p formats.Package) bool { return n.fn(p) }
+
+// ParseSelect parses a --select expression into a Selector.
+//
+// Comparisons have the form field op value and are combined with && (binds
+// tighter), || and !, grouped with parentheses. Values may be quoted with
+// single or double quotes.
+//
+//   - rule, pm, type, status: == and != with an exact value or a glob
+//   - name: == and != with an exact name or a glob such as "@types/*" (case-insensitive)
+//   - installed, declared: any of == != < <= > >= against a version; == also takes ranges like 2.x
+//   - gap: any of == != < <= > >= against none, patch, minor or major
+//
+// The gap is measured from the installed version to the newest available
+// version (see VersionGap), so it is only known once versions have been
+// looked up; before that, every gap comparison is false.
+//
+// Parameters:
+//   - expr: The expression, e.g. "rule==npm && type==prod || gap>=major"
+//
+// Returns:
+//   - *Selector: The parsed selector; nil for a blank expression
+//   - error: *errors.ValidationError describing the first syntax error
+//
+// Example:
+//
+//	sel, err := filtering.ParseSelect("rule==npm && type==prod || gap>=major")
+//	kept := filtering.SelectPackages(packages, sel)
+func ParseSelect(expr string) (*Selector, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	tokens, err := lexSelect(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &selectParser{expr: expr, tokens: tokens}
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := parser.peek(); tok.kind != selectEOF {
+		return nil, parser.errorf(tok, "unexpected %q", tok.text)
+	}
+
+	return &Selector{expr: expr, root: root, usesGap: parser.usesGap, simpleOpts: simpleFilterOptions(root)}, nil
+}
+
+// Match reports whether a package satisfies the expression.
+//
+// Parameters:
+//   - p: Package to check; LatestVersion must be set for gap comparisons to match
+//
+// Returns:
+//   - bool: true if the package matches, or the selector is nil
+func (s *Selector) Match(p formats.Package) bool {
+	return s == nil || s.root.match(p)
+}
+
+// NeedsLatest reports whether the expression compares the gap, which is only
+// known after versions have been looked up.
+//
+// Returns:
+//   - bool: true if any comparison uses the gap field
+func (s *Selector) NeedsLatest() bool {
+	return s != nil && s.usesGap
+}
+
+// String returns the expression as given to ParseSelect.
+//
+// Returns:
+//   - string: The original expression; empty for a nil selector
+func (s *Selector) String() string {
+	if s == nil {
+		return ""
+	}
+	return s.expr
+}
+
+// SelectPackages returns the packages that match a selector.
+//
+// Expressions that only AND exact rule, pm, type, name and installed
+// comparisons are the same as the plain filter flags and run through
+// FilterPackages.
+//
+// Parameters:
+//   - pkgs: Packages to filter
+//   - s: Parsed selector; nil keeps every package
+//
+// Returns:
+//   - []formats.Package: Matching packages in their original order
+func SelectPackages(pkgs []formats.Package, s *Selector) []formats.Package {
+	if s == nil {
+		return pkgs
+	}
+	if s.simpleOpts != nil {
+		return FilterPackages(pkgs, *s.simpleOpts)
+	}
+
+	var selected []formats.Package
+	for _, p := range pkgs {
+		if s.Match(p) {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// simpleFilterOptions merges a conjunction of FilterOptions comparisons.
+//
+// Parameters:
+//   - node: Root of the parsed expression
+//
+// Returns:
+//   - *FilterOptions: The merged options, or nil when the expression uses
+//     ||, !, a negated or ordered comparison, or repeats a field
+func simpleFilterOptions(node selectNode) *FilterOptions {
+	var leaves []selectCompare
+	var collect func(n selectNode) bool
+	collect = func(n selectNode) bool {
+		switch n := n.(type) {
+		case selectAnd:
+			return collect(n.left) && collect(n.right)
+		case selectCompare:
+			if n.opts == nil {
+				return false
+			}
+			leaves = append(leaves, n)
+			return true
+		default:
+			return false
+		}
+	}
+	if !collect(node) {
+		return nil
+	}
+
+	var merged FilterOptions
+	seen := make(map[string]bool)
+	for _, leaf := range leaves {
+		if seen[leaf.field] {
+			return nil
+		}
+		seen[leaf.field] = true
+		switch leaf.field {
+		case "rule":
+			merged.Rule = leaf.opts.Rule
+		case "pm":
+			merged.PM = leaf.opts.PM
+		case "type":
+			merged.Type = leaf.opts.Type
+		case "name":
+			merged.Name = leaf.opts.Name
+		case "installed":
+			merged.VersionConstraint = leaf.opts.VersionConstraint
+		}
+	}
+	return &merged
+}
+
+// compileCompare builds the comparison function of a field, operator and value.
+//
+// Parameters:
+//   - field: Field name, one of SelectFields
+//   - op: Comparison operator
+//   - value: Value compared against
+//
+// Returns:
+//   - selectCompare: The compiled comparison
+//   - string: Reason the comparison is invalid; empty on success
+func compileCompare(field, op, value string) (selectCompare, string) {
+	cmp := selectCompare{field: field, op: op, value: value}
+	ordered := op != "==" && op != "!="
+	negate := op == "!="
+
+	switch field {
+	case "rule", "pm", "type", "status", "name":
+		if ordered {
+			return cmp, fmt.Sprintf("%s only supports == and !=", field)
+		}
+		get := map[string]func(p formats.Package) string{
+			"rule":   func(p formats.Package) string { return p.Rule },
+			"pm":     func(p formats.Package) string { return p.PackageType },
+			"type":   func(p formats.Package) string { return p.Type },
+			"status": func(p formats.Package) string { return p.InstallStatus },
+			"name":   func(p formats.Package) string { return p.Name },
+		}[field]
+
+		var eq func(p formats.Package) bool
+		switch {
+		case strings.ContainsAny(value, "*?["):
+			glob := NewGlobMatcher(strings.ToLower(value))
+			eq = func(p formats.Package) bool { return glob.Match(strings.ToLower(get(p))) }
+		case field == "status":
+			eq = func(p formats.Package) bool { return strings.EqualFold(p.InstallStatus, value) }
+		default:
+			opts := map[string]FilterOptions{
+				"rule": {Rule: value},
+				"pm":   {PM: value},
+				"type": {Type: value},
+				"name": {Name: value},
+			}[field]
+			if !negate {
+				cmp.opts = &opts
+			}
+			eq = func(p formats.Package) bool { return len(FilterPackages([]formats.Package{p}, opts)) == 1 }
+		}
+		cmp.fn = eq
+		if negate {
+			cmp.fn = func(p formats.Package) bool { return !eq(p) }
+		}
+		return cmp, ""
+
+	case "installed", "declared":
+		rangeExpr := value
+		if ordered {
+			rangeExpr = op + value
+		}
+		r, err := ParseVersionRange(rangeExpr)
+		if err != nil {
+			return cmp, fmt.Sprintf("invalid version %q for %s", value, field)
+		}
+		get := func(p formats.Package) string { return p.InstalledVersion }
+		if field == "declared" {
+			get = func(p formats.Package) string { return p.Version }
+		} else if !negate {
+			cmp.opts = &FilterOptions{VersionConstraint: rangeExpr}
+		}
+		cmp.fn = func(p formats.Package) bool { return r.Contains(get(p)) }
+		if negate {
+			cmp.fn = func(p formats.Package) bool { return get(p) != "" && !r.Contains(get(p)) }
+		}
+		return cmp, ""
+
+	case "gap":
+		want := -1
+		for i, name := range SelectGaps {
+			if strings.EqualFold(value, name) {
+				want = i
+			}
+		}
+		if want < 0 {
+			return cmp, fmt.Sprintf("gap must be one of %s, got %q", strings.Join(SelectGaps, ", "), value)
+		}
+		cmp.fn = func(p formats.Package) bool {
+			got, ok := gapLevel(p)
+			if !ok {
+				return false
+			}
+			switch op {
+			case "==":
+				return got == want
+			case "!=":
+				return got != want
+			case "<":
+				return got < want
+			case "<=":
+				return got <= want
+			case ">":
+				return got > want
+			default:
+				return got >= want
+			}
+		}
+		return cmp, ""
+	}
+
+	return cmp, fmt.Sprintf("unknown field %q", field)
+}
+
+// gapLevel returns the index in SelectGaps of how far a package is behind.
+//
+// Parameters:
+//   - p: Package with LatestVersion set
+//
+// Returns:
+//   - int: 0 (none) to 3 (major)
+//   - bool: false when LatestVersion is not known yet
+func gapLevel(p formats.Package) (int, bool) {
+	if strings.TrimSpace(p.LatestVersion) == "" {
+		return 0, false
+	}
+	gap := VersionGap(p)
+	switch {
+	case gap[0] > 0:
+		return 3, true
+	case gap[1] > 0:
+		return 2, true
+	case gap[2] > 0:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// selectTokenKind classifies a token of a --select expression.
+type selectTokenKind int
+
+const (
+	selectEOF selectTokenKind = iota
+	selectWord
+	selectOp
+	selectAndTok
+	selectOrTok
+	selectNotTok
+	selectOpen
+	selectClose
+)
+
+// selectToken is a token of a --select expression.
+//
+// Fields:
+//   - kind: Token classification
+//   - text: Token text; quotes are removed from quoted words
+//   - pos: Byte offset of the token in the expression
+type selectToken struct {
+	kind selectTokenKind
+	text string
+	pos  int
+}
+
+// lexSelect splits a --select expression into tokens.
+//
+// Parameters:
+//   - expr: The expression
+//
+// Returns:
+//   - []selectToken: Tokens ending with a selectEOF token
+//   - error: *errors.ValidationError for an unterminated quote or a stray character
+func lexSelect(expr string) ([]selectToken, error) {
+	var tokens []selectToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(expr[i:], "&&"):
+			tokens = append(tokens, selectToken{selectAndTok, "&&", i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, selectToken{selectOrTok, "||", i})
+			i += 2
+		case strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="),
+			strings.HasPrefix(expr[i:], ">="), strings.HasPrefix(expr[i:], "<="):
+			tokens = append(tokens, selectToken{selectOp, expr[i : i+2], i})
+			i += 2
+		case c == '<' || c == '>':
+			tokens = append(tokens, selectToken{selectOp, string(c), i})
+			i++
+		case c == '!':
+			tokens = append(tokens, selectToken{selectNotTok, "!", i})
+			i++
+		case c == '(':
+			tokens = append(tokens, selectToken{selectOpen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, selectToken{selectClose, ")", i})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, selectError(expr, i, "unterminated quote")
+			}
+			tokens = append(tokens, selectToken{selectWord, expr[i+1 : i+1+end], i})
+			i += end + 2
+		case c == '=' || c == '&' || c == '|':
+			return nil, selectError(expr, i, fmt.Sprintf("unexpected %q (use ==, && or ||)", string(c)))
+		default:
+			start := i
+			for i < len(expr) && !unicode.IsSpace(rune(expr[i])) && !strings.ContainsRune("()!=<>&|\"'", rune(expr[i])) {
+				i++
+			}
+			tokens = append(tokens, selectToken{selectWord, expr[start:i], start})
+		}
+	}
+	return append(tokens, selectToken{selectEOF, "end of expression", len(expr)}), nil
+}
+
+// selectParser is a recursive-descent parser over --select tokens.
+//
+// Fields:
+//   - expr: The expression, for error messages
+//   - tokens: Tokens from lexSelect
+//   - pos: Index of the next token
+//   - usesGap: Set when a gap comparison was parsed
+type selectParser struct {
+	expr    string
+	tokens  []selectToken
+	pos     int
+	usesGap bool
+}
+
+func (p *selectParser) peek() selectToken { return p.tokens[p.pos] }
+
+func (p *selectParser) next() selectToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != selectEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *selectParser) errorf(tok selectToken, format string, args ...any) error {
+	return selectError(p.expr, tok.pos, fmt.Sprintf(format, args...))
+}
+
+// parseOr parses: and ("||" and)*
+func (p *selectParser) parseOr() (selectNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == selectOrTok {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = selectOr{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd parses: unary ("&&" unary)*
+func (p *selectParser) parseAnd() (selectNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == selectAndTok {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = selectAnd{left, right}
+	}
+	return left, nil
+}
+
+// parseUnary parses: "!" unary | "(" or ")" | field op value
+func (p *selectParser) parseUnary() (selectNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case selectNotTok:
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return selectNot{node}, nil
+	case selectOpen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != selectClose {
+			return nil, p.errorf(closing, "expected \")\", got %q", closing.text)
+		}
+		return node, nil
+	case selectWord:
+		field := strings.ToLower(tok.text)
+		op := p.next()
+		if op.kind != selectOp {
+			return nil, p.errorf(op, "expected an operator after %q, got %q", tok.text, op.text)
+		}
+		value := p.next()
+		if value.kind != selectWord {
+			return nil, p.errorf(value, "expected a value after %s%s, got %q", tok.text, op.text, value.text)
+		}
+		cmp, reason := compileCompare(field, op.text, value.text)
+		if reason != "" {
+			return nil, p.errorf(tok, "%s", reason)
+		}
+		if field == "gap" {
+			p.usesGap = true
+		}
+		return cmp, nil
+	default:
+		return nil, p.errorf(tok, "expected a comparison, got %q", tok.text)
+	}
+}
+
+// selectError builds the validation error for a malformed --select expression.
+//
+// Parameters:
+//   - expr: The expression
+//   - pos: Byte offset of the problem
+//   - reason: What is wrong
+//
+// Returns:
+//   - *errors.ValidationError: Error for the select field
+func selectError(expr string, pos int, reason string) *errors.ValidationError {
+	return &errors.ValidationError{
+		Category: errors.ValidationCategoryConfig,
+		Field:    "select",
+		Message:  fmt.Sprintf("invalid expression %q at position %d: %s", expr, pos+1, reason),
+		Expected: selectExpected,
+	}
+}
@@ -0,0 +1,138 @@
+package filtering
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// selectFixture is the package list shared by the --select tests.
+var selectFixture = []formats.Package{
+	{Name: "react", Rule: "npm", PackageType: "js", Type: "prod", Version: "17.0.0", InstalledVersion: "17.0.2", InstallStatus: "LockFound", LatestVersion: "18.2.0"},
+	{Name: "@types/node", Rule: "npm", PackageType: "js", Type: "dev", Version: "20.1.0", InstalledVersion: "20.1.0", InstallStatus: "LockFound", LatestVersion: "20.1.4"},
+	{Name: "golang.org/x/mod", Rule: "mod", PackageType: "golang", Type: "prod", Version: "0.14.0", InstalledVersion: "0.14.0", InstallStatus: "LockFound", LatestVersion: "0.20.0"},
+	{Name: "github.com/spf13/cobra", Rule: "mod", PackageType: "golang", Type: "prod", Version: "1.8.0", InstalledVersion: "1.8.0", InstallStatus: "LockFound", LatestVersion: "2.0.0"},
+	{Name: "requests", Rule: "requirements", PackageType: "python", Type: "prod", Version: "2.28.0", InstalledVersion: "", InstallStatus: "NotInLock"},
+}
+
+// TestSelectPackages tests filtering packages with --select expressions.
+//
+// It verifies:
+//   - && binds tighter than ||, and parentheses and ! change grouping
+//   - Every field is compared: rule, pm, type, name (glob), installed, declared, status, gap
+//   - Gap comparisons never match before LatestVersion is known
+//   - A blank expression keeps every package
+func TestSelectPackages(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"rule==npm && type==prod || gap>=major", []string{"react", "github.com/spf13/cobra"}},
+		{"rule==npm && (type==prod || gap>=major)", []string{"react"}},
+		{"!(rule==npm) && type==prod", []string{"golang.org/x/mod", "github.com/spf13/cobra", "requests"}},
+		{"pm==golang && gap==minor", []string{"golang.org/x/mod"}},
+		{"gap<minor", []string{"@types/node"}},
+		{"name=='@types/*'", []string{"@types/node"}},
+		{"name==REACT", []string{"react"}},
+		{"name!=@types/*", []string{"react", "golang.org/x/mod", "github.com/spf13/cobra", "requests"}},
+		{"installed>=17 && installed<20", []string{"react"}},
+		{"installed!=17.0.2 && rule==npm", []string{"@types/node"}},
+		{"declared==2.x", []string{"requests"}},
+		{"declared<1", []string{"golang.org/x/mod"}},
+		{"status==notinlock", []string{"requests"}},
+		{"rule==mod* && type!=dev", []string{"golang.org/x/mod", "github.com/spf13/cobra"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			sel, err := ParseSelect(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, packageNames(SelectPackages(selectFixture, sel)))
+		})
+	}
+
+	t.Run("gap before lookup", func(t *testing.T) {
+		sel, err := ParseSelect("gap>=none || rule==requirements")
+		require.NoError(t, err)
+		assert.True(t, sel.NeedsLatest())
+		pkgs := []formats.Package{{Name: "react", Rule: "npm", InstalledVersion: "17.0.2"}, selectFixture[4]}
+		assert.Equal(t, []string{"requests"}, packageNames(SelectPackages(pkgs, sel)))
+	})
+
+	t.Run("blank expression", func(t *testing.T) {
+		sel, err := ParseSelect("  ")
+		require.NoError(t, err)
+		assert.Nil(t, sel)
+		assert.Len(t, SelectPackages(selectFixture, sel), len(selectFixture))
+		assert.True(t, sel.Match(formats.Package{}))
+		assert.False(t, sel.NeedsLatest())
+	})
+}
+
+// TestParseSelectSimpleFilterOptions tests reusing FilterOptions for plain expressions.
+//
+// It verifies:
+//   - ANDed equality comparisons merge into one FilterOptions
+//   - ||, !, != and repeated fields fall back to expression matching
+func TestParseSelectSimpleFilterOptions(t *testing.T) {
+	sel, err := ParseSelect("rule==npm && type==prod && installed<18")
+	require.NoError(t, err)
+	require.NotNil(t, sel.simpleOpts)
+	assert.Equal(t, FilterOptions{Rule: "npm", Type: "prod", VersionConstraint: "<18"}, *sel.simpleOpts)
+
+	for _, expr := range []string{"rule==npm || rule==mod", "!rule==npm", "rule!=npm", "rule==npm && rule==mod", "name==re*", "gap>=major"} {
+		sel, err := ParseSelect(expr)
+		require.NoError(t, err, expr)
+		assert.Nil(t, sel.simpleOpts, expr)
+	}
+}
+
+// TestParseSelectErrors tests reporting malformed --select expressions.
+//
+// It verifies:
+//   - Errors are *errors.ValidationError for the select field with the position
+//   - Unknown fields, bad operators, values and unbalanced syntax are rejected
+func TestParseSelectErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"color==red", `unknown field "color"`},
+		{"rule>npm", "rule only supports == and !="},
+		{"gap>=huge", "gap must be one of none, patch, minor, major"},
+		{"installed<=abc", `invalid version "abc" for installed`},
+		{"rule=npm", `position 5: unexpected "="`},
+		{"rule==npm &&", "expected a comparison"},
+		{"(rule==npm", `expected ")"`},
+		{"rule==npm)", `unexpected ")"`},
+		{"rule npm", "expected an operator"},
+		{"rule==", "expected a value"},
+		{"name=='react", "unterminated quote"},
+		{"rule==npm & type==prod", "use ==, && or ||"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseSelect(tt.expr)
+			require.Error(t, err)
+			var ve *errors.ValidationError
+			require.True(t, stderrors.As(err, &ve))
+			assert.Equal(t, "select", ve.Field)
+			assert.Contains(t, ve.Message, tt.want)
+		})
+	}
+}
+
+// packageNames returns the names of packages in order.
+func packageNames(pkgs []formats.Package) []string {
+	names := make([]string, 0, len(pkgs))
+	for _, p := range pkgs {
+		names = append(names, p.Name)
+	}
+	return names
+}
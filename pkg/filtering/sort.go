@@ -0,0 +1,164 @@
+package filtering
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// SortKey selects the primary order used by SortPackagesForDisplayBy.
+type SortKey string
+
+const (
+	// SortByRule is the default display order of SortPackagesForDisplay:
+	// rule, package type, group, dependency type, then name.
+	SortByRule SortKey = "rule"
+
+	// SortByName orders packages by name, then by the default order.
+	SortByName SortKey = "name"
+
+	// SortByVersionGap orders packages furthest behind their latest version first.
+	// The gap compares major, then minor, then patch distance between the
+	// installed version and LatestVersion. Packages without a comparable gap sort last.
+	SortByVersionGap SortKey = "version-gap"
+
+	// SortByStatus orders packages by install status, then by the default order.
+	SortByStatus SortKey = "status"
+)
+
+// SortKeys lists the accepted sort keys in documentation order.
+var SortKeys = []SortKey{SortByName, SortByRule, SortByVersionGap, SortByStatus}
+
+// ParseSortKey converts a user-supplied value into a SortKey.
+//
+// Matching is case-insensitive. An empty value selects SortByRule.
+//
+// Parameters:
+//   - value: The sort key, e.g. "version-gap"
+//
+// Returns:
+//   - SortKey: The parsed key
+//   - error: *errors.ValidationError when the key is unknown
+//
+// Example:
+//
+//	key, err := filtering.ParseSortKey("version-gap") // SortByVersionGap
+func ParseSortKey(value string) (SortKey, error) {
+	normalized := SortKey(strings.ToLower(strings.TrimSpace(value)))
+	if normalized == "" {
+		return SortByRule, nil
+	}
+	for _, key := range SortKeys {
+		if normalized == key {
+			return key, nil
+		}
+	}
+
+	expected := make([]string, len(SortKeys))
+	for i, key := range SortKeys {
+		expected[i] = string(key)
+	}
+	return "", &errors.ValidationError{
+		Category: errors.ValidationCategoryConfig,
+		Field:    "sort",
+		Message:  fmt.Sprintf("unknown sort key %q", value),
+		Expected: strings.Join(expected, ", "),
+	}
+}
+
+// SortPackagesForDisplayBy returns packages sorted by the given key.
+//
+// Ties are broken with the SortPackagesForDisplay order, so packages with the
+// same name, status, or version gap still appear in a stable, predictable order.
+// Unknown keys fall back to SortPackagesForDisplay.
+//
+// Version-gap sorting reads LatestVersion, which is only set once versions have
+// been looked up (e.g. by the outdated command).
+//
+// Parameters:
+//   - pkgs: Packages to sort
+//   - key: Primary sort key
+//
+// Returns:
+//   - []formats.Package: Sorted copy of packages
+//
+// Example:
+//
+//	// installed 1.0.0 → latest 3.0.0 sorts before installed 2.1.0 → latest 2.4.0
+//	sorted := filtering.SortPackagesForDisplayBy(packages, filtering.SortByVersionGap)
+func SortPackagesForDisplayBy(pkgs []formats.Package, key SortKey) []formats.Package {
+	sorted := SortPackagesForDisplay(pkgs)
+
+	switch key {
+	case SortByName:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+		})
+	case SortByStatus:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i].InstallStatus) < strings.ToLower(sorted[j].InstallStatus)
+		})
+	case SortByVersionGap:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a, b := VersionGap(sorted[i]), VersionGap(sorted[j])
+			for k := range a {
+				if a[k] != b[k] {
+					return a[k] > b[k]
+				}
+			}
+			return false
+		})
+	}
+
+	return sorted
+}
+
+// VersionGap returns how far a package is behind its latest version.
+//
+// The gap is the difference in major, minor, and patch numbers between the
+// installed version (or the declared version when nothing is installed) and
+// LatestVersion. Parts below the first differing part are taken from the
+// latest version as-is, so 1.9.9 → 2.0.1 is {1, 0, 1}.
+//
+// Parameters:
+//   - p: Package with LatestVersion set
+//
+// Returns:
+//   - [3]int: Major, minor, and patch distance; {-1, -1, -1} when either version
+//     is not semver or the package is not behind
+//
+// Example:
+//
+//	filtering.VersionGap(formats.Package{InstalledVersion: "1.2.0", LatestVersion: "1.4.3"}) // {0, 2, 3}
+func VersionGap(p formats.Package) [3]int {
+	none := [3]int{-1, -1, -1}
+
+	current := p.InstalledVersion
+	if _, ok := canonicalRangeVersion(current); !ok {
+		current = p.Version
+	}
+	from, ok := canonicalRangeVersion(current)
+	if !ok {
+		return none
+	}
+	to, ok := canonicalRangeVersion(p.LatestVersion)
+	if !ok || semver.Compare(to, from) <= 0 {
+		return none
+	}
+
+	fromMajor, fromMinor, fromPatch := rangeVersionParts(from)
+	toMajor, toMinor, toPatch := rangeVersionParts(to)
+	switch {
+	case toMajor != fromMajor:
+		return [3]int{toMajor - fromMajor, toMinor, toPatch}
+	case toMinor != fromMinor:
+		return [3]int{0, toMinor - fromMinor, toPatch}
+	default:
+		return [3]int{0, 0, toPatch - fromPatch}
+	}
+}
@@ -0,0 +1,91 @@
+package filtering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestParseSortKey tests the behavior of ParseSortKey.
+//
+// It verifies:
+//   - Known keys are parsed case-insensitively
+//   - An empty value selects the default rule order
+//   - Unknown keys return a ValidationError listing the accepted keys
+func TestParseSortKey(t *testing.T) {
+	key, err := ParseSortKey(" Version-Gap ")
+	require.NoError(t, err)
+	assert.Equal(t, SortByVersionGap, key)
+
+	key, err = ParseSortKey("")
+	require.NoError(t, err)
+	assert.Equal(t, SortByRule, key)
+
+	_, err = ParseSortKey("age")
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, validationErr.Expected, "version-gap")
+}
+
+// TestSortPackagesForDisplayBy tests the behavior of SortPackagesForDisplayBy.
+//
+// It verifies:
+//   - The rule key matches SortPackagesForDisplay
+//   - The name key orders by name case-insensitively, breaking ties by rule
+//   - The status key groups packages by install status
+//   - The version-gap key puts packages furthest behind first and packages without a gap last
+//   - The input slice is not modified
+func TestSortPackagesForDisplayBy(t *testing.T) {
+	pkgs := []formats.Package{
+		{Name: "zod", Rule: "npm", InstallStatus: "LockFound", InstalledVersion: "3.22.0", LatestVersion: "3.23.8"},
+		{Name: "Axios", Rule: "npm", InstallStatus: "NotInLock", InstalledVersion: "0.27.2", LatestVersion: "1.7.2"},
+		{Name: "lodash", Rule: "npm", InstallStatus: "LockFound", InstalledVersion: "4.17.21", LatestVersion: "4.17.21"},
+		{Name: "axios", Rule: "composer", InstallStatus: "LockFound", InstalledVersion: "1.0.0", LatestVersion: "1.0.3"},
+		{Name: "react", Rule: "npm", InstallStatus: "LockFound", Version: "17.0.0", InstalledVersion: "#N/A", LatestVersion: "18.3.1"},
+	}
+	original := append([]formats.Package(nil), pkgs...)
+
+	names := func(sorted []formats.Package) []string {
+		out := make([]string, len(sorted))
+		for i, p := range sorted {
+			out[i] = p.Rule + "/" + p.Name
+		}
+		return out
+	}
+
+	assert.Equal(t, SortPackagesForDisplay(pkgs), SortPackagesForDisplayBy(pkgs, SortByRule))
+	assert.Equal(t, SortPackagesForDisplay(pkgs), SortPackagesForDisplayBy(pkgs, SortKey("unknown")))
+
+	assert.Equal(t, []string{"composer/axios", "npm/Axios", "npm/lodash", "npm/react", "npm/zod"},
+		names(SortPackagesForDisplayBy(pkgs, SortByName)))
+
+	assert.Equal(t, []string{"composer/axios", "npm/lodash", "npm/react", "npm/zod", "npm/Axios"},
+		names(SortPackagesForDisplayBy(pkgs, SortByStatus)))
+
+	assert.Equal(t, []string{"npm/Axios", "npm/react", "npm/zod", "composer/axios", "npm/lodash"},
+		names(SortPackagesForDisplayBy(pkgs, SortByVersionGap)))
+
+	assert.Equal(t, original, pkgs)
+}
+
+// TestVersionGap tests the behavior of VersionGap.
+//
+// It verifies:
+//   - Major, minor, and patch distances are computed from the installed version
+//   - The declared version is used when nothing is installed
+//   - Packages that are current, ahead, or not semver have no gap
+func TestVersionGap(t *testing.T) {
+	assert.Equal(t, [3]int{0, 2, 3}, VersionGap(formats.Package{InstalledVersion: "1.2.0", LatestVersion: "1.4.3"}))
+	assert.Equal(t, [3]int{1, 0, 1}, VersionGap(formats.Package{InstalledVersion: "1.9.9", LatestVersion: "2.0.1"}))
+	assert.Equal(t, [3]int{0, 0, 2}, VersionGap(formats.Package{Version: "v1.0.1", InstalledVersion: "#N/A", LatestVersion: "v1.0.3"}))
+
+	none := [3]int{-1, -1, -1}
+	assert.Equal(t, none, VersionGap(formats.Package{InstalledVersion: "2.0.0", LatestVersion: "2.0.0"}))
+	assert.Equal(t, none, VersionGap(formats.Package{InstalledVersion: "3.0.0", LatestVersion: "2.0.0"}))
+	assert.Equal(t, none, VersionGap(formats.Package{InstalledVersion: "1.0.0"}))
+	assert.Equal(t, none, VersionGap(formats.Package{InstalledVersion: "latest", Version: "*", LatestVersion: "1.0.0"}))
+}
@@ -0,0 +1,401 @@
+package filtering
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// VersionRange is a parsed semver range expression.
+//
+// A range is a list of alternatives separated by "||". A version satisfies
+// the range when it satisfies every comparator of at least one alternative.
+type VersionRange struct {
+	alternatives [][]versionComparator
+}
+
+// versionComparator is a single "<op><version>" term of a range.
+//
+// Fields:
+//   - op: Comparison operator (=, !=, <, <=, >, >=)
+//   - version: Canonical semver version with a leading "v"
+type versionComparator struct {
+	op      string
+	version string
+}
+
+// versionRangeOperators lists comparator prefixes, longest first so that
+// "<=" is not read as "<".
+var versionRangeOperators = []string{">=", "<=", "!=", ">", "<", "=", "^", "~"}
+
+// ParseVersionRange parses a semver range expression.
+//
+// Supported syntax:
+//   - Comparators: =1.2.3, !=1.2.3, <2.0.0, <=2, >1.0, >=1.2.0 (a bare version means "=")
+//   - Caret and tilde: ^1.2.3 (same major), ~1.2.3 (same minor)
+//   - Wildcards: 2.x, 1.4.*, or x (any version)
+//   - AND: comparators separated by spaces or commas (">=1.0.0 <2.0.0")
+//   - OR: alternatives separated by "||" ("<1.0.0 || >=3.0.0")
+//
+// Partial versions are padded with zeros, so "<2" means "<2.0.0".
+//
+// Parameters:
+//   - expr: The range expression
+//
+// Returns:
+//   - VersionRange: The parsed range
+//   - error: *errors.ValidationError when the expression is empty or malformed
+//
+// Example:
+//
+//	r, err := filtering.ParseVersionRange(">=1.2.0 <2.0.0")
+//	r.Contains("1.4.0") // true
+func ParseVersionRange(expr string) (VersionRange, error) {
+	var r VersionRange
+
+	for _, alternative := range strings.Split(expr, "||") {
+		terms := strings.FieldsFunc(alternative, func(c rune) bool {
+			return c == ' ' || c == ',' || c == '\t'
+		})
+		if len(terms) == 0 {
+			return VersionRange{}, invalidVersionRange(expr, "empty range")
+		}
+
+		var comparators []versionComparator
+		for i := 0; i < len(terms); i++ {
+			term := terms[i]
+			// Allow a space between operator and version (">= 1.2.0")
+			if isBareOperator(term) && i+1 < len(terms) {
+				i++
+				term += terms[i]
+			}
+
+			parsed, err := parseVersionComparator(term)
+			if err != nil {
+				return VersionRange{}, invalidVersionRange(expr, err.Error())
+			}
+			comparators = append(comparators, parsed...)
+		}
+		r.alternatives = append(r.alternatives, comparators)
+	}
+
+	return r, nil
+}
+
+// Contains reports whether a version satisfies the range.
+//
+// Parameters:
+//   - version: Version to check, with or without a leading "v"
+//
+// Returns:
+//   - bool: true if the version is valid semver and satisfies the range
+func (r VersionRange) Contains(version string) bool {
+	canonical, ok := canonicalRangeVersion(version)
+	if !ok {
+		return false
+	}
+
+	for _, comparators := range r.alternatives {
+		if satisfiesAll(canonical, comparators) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByVersionRange keeps packages whose installed version satisfies a range.
+//
+// Packages with a missing or non-semver InstalledVersion are excluded.
+//
+// Parameters:
+//   - pkgs: Slice of packages to filter
+//   - expr: Range expression; empty returns pkgs unchanged
+//
+// Returns:
+//   - []formats.Package: Packages inside the range
+//   - error: *errors.ValidationError when expr is malformed
+//
+// Example:
+//
+//	filtered, err := filtering.FilterByVersionRange(packages, "<2.0.0")
+func FilterByVersionRange(pkgs []formats.Package, expr string) ([]formats.Package, error) {
+	if strings.TrimSpace(expr) == "" {
+		return pkgs, nil
+	}
+
+	r, err := ParseVersionRange(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []formats.Package
+	for _, p := range pkgs {
+		if r.Contains(p.InstalledVersion) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
+
+// matchesVersionRange checks if a package's installed version satisfies the range.
+//
+// An invalid expression matches nothing; callers should check
+// FilterOptions.Validate first to surface the error.
+//
+// Parameters:
+//   - p: Package to check
+//   - expr: Range expression
+//
+// Returns:
+//   - bool: true if the package matches
+func matchesVersionRange(p formats.Package, expr string) bool {
+	r, err := ParseVersionRange(expr)
+	if err != nil {
+		return false
+	}
+	return r.Contains(p.InstalledVersion)
+}
+
+// parseVersionComparator parses a single range term.
+//
+// Caret and tilde terms expand into a lower and upper bound.
+//
+// Parameters:
+//   - term: The term, e.g. ">=1.2.0" or "^1.2"
+//
+// Returns:
+//   - []versionComparator: One comparator, or two for ^ and ~
+//   - error: When the operator or version is invalid
+func parseVersionComparator(term string) ([]versionComparator, error) {
+	op := "="
+	for _, candidate := range versionRangeOperators {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+	raw := strings.TrimPrefix(term, op)
+	if op == "=" {
+		raw = strings.TrimPrefix(raw, "=")
+		if comparators, ok := wildcardComparators(raw); ok {
+			return comparators, nil
+		}
+	}
+
+	version, ok := canonicalRangeVersion(raw)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a semver version", raw)
+	}
+
+	switch op {
+	case "^":
+		return []versionComparator{{op: ">=", version: version}, {op: "<", version: caretUpperBound(version)}}, nil
+	case "~":
+		return []versionComparator{{op: ">=", version: version}, {op: "<", version: tildeUpperBound(version)}}, nil
+	default:
+		return []versionComparator{{op: op, version: version}}, nil
+	}
+}
+
+// wildcardComparators expands an x-range such as "2.x" or "1.4.*".
+//
+// Parameters:
+//   - raw: The version part of a term, with or without a leading "v"
+//
+// Returns:
+//   - []versionComparator: A lower and upper bound, or a single lower bound for "x"
+//   - bool: false if raw is not an x-range
+func wildcardComparators(raw string) ([]versionComparator, bool) {
+	parts := strings.Split(strings.TrimPrefix(raw, "v"), ".")
+	fixed := 0
+	for fixed < len(parts) && !isWildcardPart(parts[fixed]) {
+		if _, err := strconv.Atoi(parts[fixed]); err != nil {
+			return nil, false
+		}
+		fixed++
+	}
+	if fixed == len(parts) || fixed > 2 {
+		return nil, false
+	}
+	for _, part := range parts[fixed:] {
+		if !isWildcardPart(part) {
+			return nil, false
+		}
+	}
+
+	switch fixed {
+	case 0:
+		return []versionComparator{{op: ">=", version: "v0.0.0-0"}}, true
+	case 1:
+		major, _ := strconv.Atoi(parts[0])
+		return []versionComparator{
+			{op: ">=", version: fmt.Sprintf("v%d.0.0", major)},
+			{op: "<", version: fmt.Sprintf("v%d.0.0-0", major+1)},
+		}, true
+	default:
+		major, _ := strconv.Atoi(parts[0])
+		minor, _ := strconv.Atoi(parts[1])
+		return []versionComparator{
+			{op: ">=", version: fmt.Sprintf("v%d.%d.0", major, minor)},
+			{op: "<", version: fmt.Sprintf("v%d.%d.0-0", major, minor+1)},
+		}, true
+	}
+}
+
+// isWildcardPart reports whether a version part is an x-range wildcard.
+func isWildcardPart(part string) bool {
+	return part == "x" || part == "X" || part == "*"
+}
+
+// isBareOperator reports whether a term is an operator without a version.
+func isBareOperator(term string) bool {
+	for _, op := range versionRangeOperators {
+		if term == op {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalRangeVersion converts a version into canonical semver form.
+//
+// Parameters:
+//   - version: Version with or without a leading "v"; partial versions are padded
+//
+// Returns:
+//   - string: Canonical version such as "v1.2.0"
+//   - bool: false if the version is empty or not semver
+func canonicalRangeVersion(version string) (string, bool) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return "", false
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return "", false
+	}
+	return semver.Canonical(version), true
+}
+
+// Below reports whether a version is below the range, so an upgrade can satisfy it.
+//
+// A version is below an alternative when every comparator it fails is a lower
+// bound (>, >=, or = with a higher version). It is below the range when it is
+// below at least one alternative.
+//
+// Parameters:
+//   - version: Version to check, with or without a leading "v"
+//
+// Returns:
+//   - bool: true if the version is valid semver, outside the range, and below it
+func (r VersionRange) Below(version string) bool {
+	canonical, ok := canonicalRangeVersion(version)
+	if !ok || r.Contains(canonical) {
+		return false
+	}
+
+	for _, comparators := range r.alternatives {
+		if belowAll(canonical, comparators) {
+			return true
+		}
+	}
+	return false
+}
+
+// belowAll reports whether every comparator a canonical version fails is a lower bound.
+func belowAll(version string, comparators []versionComparator) bool {
+	for _, c := range comparators {
+		if satisfiesAll(version, []versionComparator{c}) {
+			continue
+		}
+		switch c.op {
+		case ">", ">=":
+		case "=":
+			if semver.Compare(version, c.version) > 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// satisfiesAll reports whether a canonical version satisfies every comparator.
+func satisfiesAll(version string, comparators []versionComparator) bool {
+	for _, c := range comparators {
+		cmp := semver.Compare(version, c.version)
+		var ok bool
+		switch c.op {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// caretUpperBound returns the exclusive upper bound of a caret range.
+//
+// ^1.2.3 allows up to the next major, ^0.2.3 up to the next minor, and
+// ^0.0.3 up to the next patch, matching npm semantics.
+func caretUpperBound(version string) string {
+	major, minor, patch := rangeVersionParts(version)
+	switch {
+	case major > 0:
+		return fmt.Sprintf("v%d.0.0-0", major+1)
+	case minor > 0:
+		return fmt.Sprintf("v0.%d.0-0", minor+1)
+	default:
+		return fmt.Sprintf("v0.0.%d-0", patch+1)
+	}
+}
+
+// tildeUpperBound returns the exclusive upper bound of a tilde range (next minor).
+func tildeUpperBound(version string) string {
+	major, minor, _ := rangeVersionParts(version)
+	return fmt.Sprintf("v%d.%d.0-0", major, minor+1)
+}
+
+// rangeVersionParts splits a canonical version into major, minor, and patch numbers.
+func rangeVersionParts(version string) (int, int, int) {
+	core := strings.TrimPrefix(version, "v")
+	if idx := strings.IndexAny(core, "-+"); idx >= 0 {
+		core = core[:idx]
+	}
+	parts := strings.Split(core, ".")
+	nums := make([]int, 3)
+	for i := 0; i < len(parts) && i < 3; i++ {
+		nums[i], _ = strconv.Atoi(parts[i])
+	}
+	return nums[0], nums[1], nums[2]
+}
+
+// invalidVersionRange builds the validation error for a malformed range.
+func invalidVersionRange(expr, reason string) *errors.ValidationError {
+	return &errors.ValidationError{
+		Category: errors.ValidationCategoryConfig,
+		Field:    "--version-range",
+		Message:  fmt.Sprintf("invalid range %q: %s", expr, reason),
+		Expected: "semver comparators such as <2.0.0, >=1.2 <2, ^1.4.0 or <1 || >=3",
+	}
+}
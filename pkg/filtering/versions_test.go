@@ -0,0 +1,140 @@
+package filtering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestParseVersionRange tests parsing and matching of semver range expressions.
+//
+// It verifies:
+//   - Comparators, partial versions, and "v" prefixes are supported
+//   - Space, comma, and "||" combine comparators as AND and OR
+//   - Caret and tilde ranges expand to npm-style bounds
+//   - Wildcards such as 2.x and 1.4.* cover a major or minor line
+//   - Non-semver versions never match
+//   - Below reports versions an upgrade can move into the range, but not versions above it
+//   - Malformed expressions return a ValidationError
+func TestParseVersionRange(t *testing.T) {
+	tests := []struct {
+		expr    string
+		matches []string
+		misses  []string
+	}{
+		{expr: "<2.0.0", matches: []string{"1.9.9", "v0.1.0"}, misses: []string{"2.0.0", "2.1.0"}},
+		{expr: "<2", matches: []string{"1.99.0"}, misses: []string{"2.0.0"}},
+		{expr: ">=1.2 <2", matches: []string{"1.2.0", "1.8.3"}, misses: []string{"1.1.9", "2.0.0"}},
+		{expr: ">= 1.2.0, <= 1.4.0", matches: []string{"1.4.0"}, misses: []string{"1.4.1"}},
+		{expr: "<1.0.0 || >=3.0.0", matches: []string{"0.9.0", "3.2.0"}, misses: []string{"2.0.0"}},
+		{expr: "1.2.3", matches: []string{"v1.2.3"}, misses: []string{"1.2.4"}},
+		{expr: "!=1.2.3", matches: []string{"1.2.4"}, misses: []string{"1.2.3"}},
+		{expr: "^1.4.0", matches: []string{"1.4.0", "1.9.0"}, misses: []string{"1.3.9", "2.0.0", "2.0.0-rc.1"}},
+		{expr: "^0.2.3", matches: []string{"0.2.9"}, misses: []string{"0.3.0"}},
+		{expr: "~1.4.2", matches: []string{"1.4.9"}, misses: []string{"1.5.0"}},
+		{expr: "2.x", matches: []string{"2.0.0", "v2.9.1"}, misses: []string{"1.9.9", "3.0.0", "3.0.0-rc.1"}},
+		{expr: "1.4.*", matches: []string{"1.4.0", "1.4.12"}, misses: []string{"1.3.9", "1.5.0"}},
+		{expr: "x", matches: []string{"0.0.1", "12.0.0"}, misses: []string{"latest"}},
+		{expr: ">0.0.0", misses: []string{"", "latest", "abc123", "1.2.3.4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			r, err := ParseVersionRange(tt.expr)
+			require.NoError(t, err)
+			for _, v := range tt.matches {
+				assert.True(t, r.Contains(v), "%s should satisfy %s", v, tt.expr)
+			}
+			for _, v := range tt.misses {
+				assert.False(t, r.Contains(v), "%s should not satisfy %s", v, tt.expr)
+			}
+		})
+	}
+
+	t.Run("below", func(t *testing.T) {
+		for expr, below := range map[string][]string{
+			"2.x":                {"1.9.9"},
+			"^1.4.0":             {"1.3.0"},
+			"1.2.3":              {"1.2.2"},
+			"<1.0.0 || >=3.0.0":  {"2.0.0"},
+			">=1.2 <2 || >=3 <4": {"1.0.0", "2.5.0"},
+			"!=1.2.3":            nil,
+			"<2.0.0":             nil,
+		} {
+			r, err := ParseVersionRange(expr)
+			require.NoError(t, err)
+			for _, v := range below {
+				assert.True(t, r.Below(v), "%s should be below %s", v, expr)
+			}
+		}
+
+		r, err := ParseVersionRange("2.x")
+		require.NoError(t, err)
+		for _, v := range []string{"2.1.0", "3.0.0", "latest"} {
+			assert.False(t, r.Below(v), "%s should not be below 2.x", v)
+		}
+		r, err = ParseVersionRange("!=1.2.3")
+		require.NoError(t, err)
+		assert.False(t, r.Below("1.2.3"))
+	})
+
+	for _, expr := range []string{"", "<", "<two", "1.0 ||", "=>1.0.0", "2.x.1", "1.2.3.x", ">=2.x"} {
+		t.Run("invalid "+expr, func(t *testing.T) {
+			_, err := ParseVersionRange(expr)
+			require.Error(t, err)
+			ve, ok := errors.IsValidationError(err)
+			require.True(t, ok)
+			assert.Equal(t, "--version-range", ve.Field)
+		})
+	}
+}
+
+// TestFilterByVersionRange tests filtering packages by installed version.
+//
+// It verifies:
+//   - Packages whose installed version satisfies the range are kept
+//   - Missing and non-semver installed versions are excluded
+//   - An empty range returns all packages and an invalid range returns an error
+//   - FilterPackages and FilterOptions.Validate honour VersionConstraint
+func TestFilterByVersionRange(t *testing.T) {
+	pkgs := []formats.Package{
+		{Name: "old", Type: "prod", InstalledVersion: "1.4.2"},
+		{Name: "new", Type: "prod", InstalledVersion: "2.3.0"},
+		{Name: "dev-old", Type: "dev", InstalledVersion: "v1.0.0"},
+		{Name: "missing", Type: "prod"},
+		{Name: "commit", Type: "prod", InstalledVersion: "abc1234"},
+	}
+
+	filtered, err := FilterByVersionRange(pkgs, "<2.0.0")
+	require.NoError(t, err)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "old", filtered[0].Name)
+	assert.Equal(t, "dev-old", filtered[1].Name)
+
+	all, err := FilterByVersionRange(pkgs, "  ")
+	require.NoError(t, err)
+	assert.Len(t, all, len(pkgs))
+
+	_, err = FilterByVersionRange(pkgs, "<<2")
+	assert.Error(t, err)
+
+	t.Run("filter options", func(t *testing.T) {
+		opts := FilterOptions{Type: "prod"}.WithVersionConstraint("<2.0.0")
+		assert.False(t, opts.IsEmpty())
+		assert.True(t, opts.HasVersionConstraint())
+		require.NoError(t, opts.Validate())
+
+		result := FilterPackages(pkgs, opts)
+		require.Len(t, result, 1)
+		assert.Equal(t, "old", result[0].Name)
+
+		invalid := opts.WithVersionConstraint("<two")
+		assert.Error(t, invalid.Validate())
+		assert.Empty(t, FilterPackages(pkgs, invalid))
+		assert.NoError(t, FilterOptions{}.Validate())
+	})
+}
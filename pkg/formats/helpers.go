@@ -3,6 +3,7 @@ package formats
 import (
 	"bufio"
 	"fmt"
+	"path"
 	"regexp"
 	"strings"
 
@@ -84,11 +85,13 @@ func GetNestedField(data map[string]interface{}, field string) interface{} {
 //
 // It scans the text for section headers in brackets (e.g., [section-name]) and
 // returns all lines that belong to the specified section until the next section
-// header is encountered.
+// header is encountered. The section name may contain "*" wildcards (e.g.,
+// "tool.poetry.group.*.dependencies"), in which case lines from every matching
+// section are returned.
 //
 // Parameters:
 //   - text: The full text content to scan
-//   - section: The section name to extract (without brackets)
+//   - section: The section name or wildcard pattern to extract (without brackets)
 //
 // Returns:
 //   - string: The lines belonging to the section, joined by newlines
@@ -106,7 +109,7 @@ func extractSection(text, section string) string {
 			continue
 		}
 
-		if currentSection == section {
+		if sectionMatches(currentSection, section) {
 			lines = append(lines, line)
 		}
 	}
@@ -114,6 +117,95 @@ func extractSection(text, section string) string {
 	return strings.Join(lines, "\n")
 }
 
+// extractTOMLArray extracts the items of a TOML array assigned to a key within a section.
+//
+// The field is split at its last dot into a section and a key, so
+// "project.dependencies" selects the "dependencies = [...]" array of the [project]
+// table. The array may span several lines; brackets inside quoted strings (such as
+// PEP 508 extras) and comments do not end it.
+//
+// Parameters:
+//   - text: The full text content to scan
+//   - field: The section name and key joined by a dot (the section may contain "*" wildcards)
+//
+// Returns:
+//   - string: The text between the array's brackets, or "" if the key is not an array
+func extractTOMLArray(text, field string) string {
+	idx := strings.LastIndex(field, ".")
+	if idx <= 0 || idx == len(field)-1 {
+		return ""
+	}
+	section, key := field[:idx], field[idx+1:]
+
+	body := extractSection(text, section)
+	if body == "" {
+		return ""
+	}
+
+	keyPattern := regexp.MustCompile(`(?m)^[ \t]*["']?` + regexp.QuoteMeta(key) + `["']?[ \t]*=[ \t]*\[`)
+	var items []string
+	for _, loc := range keyPattern.FindAllStringIndex(body, -1) {
+		items = append(items, scanTOMLArray(body[loc[1]:]))
+	}
+	return strings.Join(items, "\n")
+}
+
+// scanTOMLArray returns the text of a TOML array up to its closing bracket.
+//
+// Parameters:
+//   - text: The text following the array's opening bracket
+//
+// Returns:
+//   - string: The array body without the closing bracket; the rest of text if it is unterminated
+func scanTOMLArray(text string) string {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case c == '[':
+			depth++
+		case c == ']':
+			if depth == 0 {
+				return text[:i]
+			}
+			depth--
+		}
+	}
+	return text
+}
+
+// sectionMatches reports whether a section header matches a configured section name.
+//
+// Parameters:
+//   - current: The section header found in the text
+//   - section: The configured section name, optionally containing "*" wildcards
+//
+// Returns:
+//   - bool: true if the header equals the name or matches the wildcard pattern
+func sectionMatches(current, section string) bool {
+	if current == section {
+		return true
+	}
+	if current == "" || !strings.Contains(section, "*") {
+		return false
+	}
+	matched, err := path.Match(section, current)
+	return err == nil && matched
+}
+
 // processVersion parses a version string and applies all standard transformations.
 //
 // It performs the following operations:
@@ -213,3 +305,116 @@ func parseImageFromMap(depMap map[string]interface{}, resolvedName, versionStr s
 
 	return versionStr, resolvedName
 }
+
+// yamlNonRegistrySourceKeys are dependency map keys that point outside a package registry.
+//
+// They follow Dart pub's pubspec.yaml dependency sources, e.g. "flutter: {sdk: flutter}"
+// or "my_pkg: {git: https://...}". Keys are checked in order.
+var yamlNonRegistrySourceKeys = []string{"sdk", "git", "path"}
+
+// parseSourceFromMap extracts the version and non-registry source from a dependency map.
+//
+// A string "version" key (as used by pub hosted dependencies) supplies the version
+// when none was found otherwise. Maps naming an sdk, git, or path source return that
+// key as the source so the package is reported as NonRegistry instead of looked up.
+//
+// Parameters:
+//   - depMap: The dependency map to inspect
+//   - versionStr: The version string found so far (e.g., from an image specification)
+//
+// Returns:
+//   - string: The resolved version string
+//   - string: The non-registry source key, or empty for registry dependencies
+func parseSourceFromMap(depMap map[string]interface{}, versionStr string) (string, string) {
+	if v, ok := depMap["version"].(string); ok && versionStr == "" {
+		versionStr = v
+	}
+
+	for _, key := range yamlNonRegistrySourceKeys {
+		if _, ok := depMap[key]; ok {
+			return versionStr, key
+		}
+	}
+
+	return versionStr, ""
+}
+
+// rubyGroupBlockPattern matches a Gemfile "group :a, :b do" block opener and captures its group list.
+var rubyGroupBlockPattern = regexp.MustCompile(`^group\s*\(?\s*(.+?)\s*\)?\s+do\b`)
+
+// rubyInlineGroupPattern matches a gem's "group: :test" or "groups: [:development, :test]" option.
+var rubyInlineGroupPattern = regexp.MustCompile(`\bgroups?:\s*(\[[^\]]*\]|:\w+|["']\w+["'])`)
+
+// rubyBlockOpenerPattern matches lines that open a Ruby block closed by "end".
+var rubyBlockOpenerPattern = regexp.MustCompile(`^(?:if|unless|case|begin|while|until|def|class|module)\b|\bdo(?:\s*\|[^|]*\|)?\s*(?:#.*)?$`)
+
+// rubySymbolPattern matches Ruby symbol or string group names in a group list.
+var rubySymbolPattern = regexp.MustCompile(`:(\w+)|["'](\w+)["']`)
+
+// splitGroupBlocks splits Gemfile text into lines belonging to prod and dev groups.
+//
+// It performs the following operations:
+//   - Tracks nested Ruby blocks so "end" closes the matching "group ... do" block
+//   - Treats lines inside a group block as dev when every group of the block is a dev group
+//   - Treats a gem with a group:/groups: option as dev when every listed group is a dev group
+//
+// Parameters:
+//   - text: The Gemfile content
+//   - devGroups: Group names whose gems are dev dependencies (e.g., "development", "test")
+//
+// Returns:
+//   - string: Lines outside dev groups
+//   - string: Lines inside dev groups
+func splitGroupBlocks(text string, devGroups []string) (string, string) {
+	dev := make(map[string]bool, len(devGroups))
+	for _, group := range devGroups {
+		dev[strings.TrimPrefix(strings.TrimSpace(group), ":")] = true
+	}
+
+	allDev := func(list string) bool {
+		matches := rubySymbolPattern.FindAllStringSubmatch(list, -1)
+		if len(matches) == 0 {
+			return false
+		}
+		for _, m := range matches {
+			if !dev[m[1]+m[2]] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var prodLines, devLines []string
+	var stack []bool
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		inDev := len(stack) > 0 && stack[len(stack)-1]
+
+		switch {
+		case trimmed == "end" || strings.HasPrefix(trimmed, "end ") || strings.HasPrefix(trimmed, "end#"):
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		case rubyGroupBlockPattern.MatchString(trimmed):
+			list := rubyGroupBlockPattern.FindStringSubmatch(trimmed)[1]
+			stack = append(stack, inDev || allDev(list))
+			continue
+		case rubyBlockOpenerPattern.MatchString(trimmed):
+			stack = append(stack, inDev)
+		}
+
+		if m := rubyInlineGroupPattern.FindStringSubmatch(trimmed); m != nil && allDev(m[1]) {
+			inDev = true
+		}
+
+		if inDev {
+			devLines = append(devLines, line)
+		} else {
+			prodLines = append(prodLines, line)
+		}
+	}
+
+	return strings.Join(prodLines, "\n"), strings.Join(devLines, "\n")
+}
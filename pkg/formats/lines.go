@@ -0,0 +1,77 @@
+package formats
+
+import "strings"
+
+// AssignLines records the manifest line each package was declared on.
+//
+// Format parsers decode structured documents and lose positions, so lines are
+// recovered from the raw content: a package's line is the first line holding
+// its name as a whole token, preferring one that also holds the declared
+// version. Each line is claimed by at most one package, so a name declared
+// twice gets two distinct lines. Packages whose name cannot be found keep
+// Line 0.
+//
+// Parameters:
+//   - content: The raw manifest content the packages were parsed from
+//   - pkgs: Packages parsed from content, updated in place
+func AssignLines(content []byte, pkgs []Package) {
+	lines := strings.Split(string(content), "\n")
+	claimed := make(map[int]bool, len(pkgs))
+
+	for i := range pkgs {
+		line := findDeclarationLine(lines, pkgs[i], claimed)
+		pkgs[i].Line = line
+		if line > 0 {
+			claimed[line] = true
+		}
+	}
+}
+
+// findDeclarationLine returns the 1-based line declaring p, or 0 when no unclaimed line names it.
+func findDeclarationLine(lines []string, p Package, claimed map[int]bool) int {
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		return 0
+	}
+	version := strings.TrimSpace(p.Version)
+
+	first := 0
+	for i, line := range lines {
+		if claimed[i+1] || !containsToken(line, name) {
+			continue
+		}
+		if version == "" || strings.Contains(line, version) {
+			return i + 1
+		}
+		if first == 0 {
+			first = i + 1
+		}
+	}
+	return first
+}
+
+// containsToken reports whether name occurs in line without adjoining name characters,
+// so "react" is not found in "react-dom" or "@types/react".
+func containsToken(line, name string) bool {
+	for offset := 0; ; {
+		idx := strings.Index(line[offset:], name)
+		if idx < 0 {
+			return false
+		}
+		start := offset + idx
+		end := start + len(name)
+		if (start == 0 || !isNameByte(line[start-1])) && (end == len(line) || !isNameByte(line[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+// isNameByte reports whether b can be part of a package name.
+func isNameByte(b byte) bool {
+	switch {
+	case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9':
+		return true
+	}
+	return strings.IndexByte("-_./@", b) >= 0
+}
@@ -0,0 +1,57 @@
+package formats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAssignLines tests recovering declaration lines from manifest content.
+//
+// It verifies:
+//   - Names are matched as whole tokens, not inside longer names
+//   - A line holding the declared version is preferred over earlier mentions
+//   - A name declared twice gets two distinct lines
+//   - Packages whose name is not found keep line 0
+func TestAssignLines(t *testing.T) {
+	content := []byte(`{
+  "name": "react-app",
+  "scripts": {"test": "jest"},
+  "dependencies": {
+    "@types/react": "^18.0.0",
+    "react": "^18.2.0"
+  },
+  "devDependencies": {
+    "jest": "^29.0.0",
+    "react": "^18.2.0"
+  }
+}`)
+
+	pkgs := []Package{
+		{Name: "react", Version: "18.2.0"},
+		{Name: "jest", Version: "29.0.0"},
+		{Name: "react", Version: "18.2.0"},
+		{Name: "@types/react", Version: "18.0.0"},
+		{Name: "lodash", Version: "4.17.21"},
+		{Name: "", Version: "1.0.0"},
+	}
+	AssignLines(content, pkgs)
+
+	assert.Equal(t, 6, pkgs[0].Line)
+	assert.Equal(t, 9, pkgs[1].Line)
+	assert.Equal(t, 10, pkgs[2].Line)
+	assert.Equal(t, 5, pkgs[3].Line)
+	assert.Zero(t, pkgs[4].Line)
+	assert.Zero(t, pkgs[5].Line)
+}
+
+// TestAssignLinesNameOnly tests declarations whose version is on another line.
+//
+// It verifies:
+//   - The first line naming the package is used when no line holds the version
+func TestAssignLinesNameOnly(t *testing.T) {
+	content := []byte("<dependency>\n  <artifactId>guava</artifactId>\n  <version>33.0.0</version>\n</dependency>\n")
+	pkgs := []Package{{Name: "guava", Version: "33.0.0"}}
+	AssignLines(content, pkgs)
+	assert.Equal(t, 2, pkgs[0].Line)
+}
@@ -15,6 +15,7 @@ import "github.com/ajxudir/goupdate/pkg/config"
 //   - PackageType: The package manager name (e.g., "npm", "pip", "nuget")
 //   - Rule: The update rule name from configuration
 //   - Source: The source file path where this package was declared
+//   - Line: The 1-based line of the declaration in Source; 0 when unknown
 //   - InstalledVersion: The currently installed version (if known)
 //   - InstallStatus: The installation status (e.g., "installed", "missing")
 //   - Group: Optional dependency group or category
@@ -27,10 +28,26 @@ type Package struct {
 	PackageType      string `json:"package_type"`
 	Rule             string `json:"rule"`
 	Source           string `json:"source"`
+	Line             int    `json:"line,omitempty"`
 	InstalledVersion string `json:"installed_version"`
 	InstallStatus    string `json:"install_status"`
 	Group            string `json:"group,omitempty"`
 	IgnoreReason     string `json:"ignore_reason,omitempty"`
+	// NonRegistrySource names the non-registry source option (e.g., "git", "path") when the
+	// package is not installed from its registry; empty for registry packages.
+	NonRegistrySource string `json:"non_registry_source,omitempty"`
+	// LatestVersion is the newest available version found by an outdated check; empty until
+	// versions have been looked up. Used by filtering.SortPackagesForDisplayBy for version-gap sorting.
+	LatestVersion string `json:"latest_version,omitempty"`
+	// Direct marks a package of a resolved dependency tree (scan --deep) as declared by its
+	// manifest; false for transitive packages. Packages parsed from manifests leave it unset.
+	Direct bool `json:"direct,omitempty"`
+	// Repository is the source repository URL found during version lookups (see
+	// outdated.LookupRepository); empty when unknown or not looked up.
+	Repository string `json:"repository,omitempty"`
+	// UsedBy lists the project files, relative to the manifest, that reference a centrally
+	// managed .NET package (Directory.Packages.props); empty for other packages.
+	UsedBy []string `json:"used_by,omitempty"`
 }
 
 // GetName returns the package name and implements the config.PackageRef interface.
@@ -2,6 +2,8 @@ package formats
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/utils"
@@ -17,14 +19,23 @@ type RawParser struct{}
 //
 // It performs the following operations:
 //   - Converts the raw bytes to text
-//   - Extracts sections from INI-style files if multiple fields are configured
-//   - Applies regex patterns to match package declarations
+//   - Extracts sections from INI-style files if multiple fields are configured, or the
+//     items of a TOML array when a field names a key within a section
+//   - Splits Ruby "group ... do" blocks into prod and dev text if dev groups are configured
+//   - Collects named version definitions if a version ref pattern is configured
+//   - Applies every applicable regex pattern to match package declarations
 //   - Extracts package name, version, and constraint from regex named groups
 //   - Applies constraint mapping and package overrides
 //   - Filters ignored packages based on configuration
 //
 // The regex pattern should use named groups: "name", "version", and optionally "constraint".
-// Alternative group names "n" and "version_alt" are also supported.
+// Alternative group names "n" and "version_alt" are also supported. An optional "source"
+// group marks packages installed from a non-registry source (e.g., git or path gems).
+// A "scope" group is matched against extraction.dev_scopes to type dev dependencies,
+// and a "ref" group takes its version from extraction.version_ref_pattern definitions
+// (e.g., Gradle catalog entries using version.ref). Packages named by
+// extraction.source_pattern take their non-registry source from that table when
+// the declaration itself has none.
 //
 // Parameters:
 //   - content: The raw bytes of the text package manifest file
@@ -37,72 +48,223 @@ func (p *RawParser) Parse(content []byte, cfg *config.PackageManagerCfg) ([]Pack
 	text := string(content)
 	var packages []Package
 
+	// Return empty if no pattern configured
+	patterns := utils.SelectPatterns(text, cfg.Extraction)
+	if len(patterns) == 0 {
+		return packages, nil
+	}
+
+	refs, err := extractVersionRefs(text, cfg.Extraction.VersionRefPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := extractSources(text, cfg.Extraction.SourcePattern)
+	if err != nil {
+		return nil, err
+	}
+
 	for fieldName, pkgType := range cfg.Fields {
-		var pattern string
-		if cfg.Extraction != nil && cfg.Extraction.Pattern != "" {
-			pattern = cfg.Extraction.Pattern
-		} else {
-			// Return empty if no pattern configured
-			return packages, nil
-		}
 
 		sectionText := text
 		if len(cfg.Fields) > 1 {
 			sectionText = extractSection(text, fieldName)
+			if sectionText == "" {
+				sectionText = extractTOMLArray(text, fieldName)
+			}
 			if sectionText == "" {
 				continue
 			}
 		}
 
-		matches, err := utils.ExtractAllMatches(pattern, sectionText)
-		if err != nil {
-			return nil, fmt.Errorf("invalid regex pattern: %w", err)
+		chunks := []rawChunk{{text: sectionText, pkgType: pkgType}}
+		if len(cfg.Extraction.DevGroups) > 0 {
+			prodText, devText := splitGroupBlocks(sectionText, cfg.Extraction.DevGroups)
+			chunks = []rawChunk{{text: prodText, pkgType: pkgType}, {text: devText, pkgType: "dev"}}
 		}
 
-		for _, match := range matches {
-			name := match["name"]
-			if name == "" {
-				name = match["n"]
-			}
-			constraint := match["constraint"]
-			version := match["version"]
-			if version == "" {
-				version = match["version_alt"]
+		for _, chunk := range chunks {
+			for _, pattern := range patterns {
+				parsed, err := parseRawMatches(pattern, chunk.text, chunk.pkgType, refs, sources, cfg)
+				if err != nil {
+					return nil, err
+				}
+				packages = append(packages, parsed...)
 			}
+		}
+	}
 
-			if name == "" {
-				continue
-			}
+	return packages, nil
+}
 
-			vInfo := utils.VersionInfo{
-				Constraint: constraint,
-				Version:    version,
-			}
+// rawChunk is a block of manifest text whose matches share a dependency type.
+type rawChunk struct {
+	text    string
+	pkgType string
+}
 
-			if cfg.ConstraintMapping != nil {
-				vInfo.Constraint = utils.MapConstraint(vInfo.Constraint, cfg.ConstraintMapping)
-			}
+// extractVersionRefs collects named version definitions from manifest text.
+//
+// Parameters:
+//   - text: The full manifest text
+//   - pattern: Regex with named groups "ref" and "version"; empty disables refs
+//
+// Returns:
+//   - map[string]string: Version by ref name; nil when no pattern is configured
+//   - error: Returns an error if the regex pattern is invalid
+func extractVersionRefs(text, pattern string) (map[string]string, error) {
+	if pattern == "" {
+		return nil, nil
+	}
 
-			// Apply package-specific overrides
-			vInfo = utils.ApplyPackageOverride(name, vInfo, cfg)
+	matches, err := utils.ExtractAllMatches(pattern, text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version ref pattern: %w", err)
+	}
 
-			vInfo = utils.NormalizeDeclaredVersion(name, vInfo, cfg)
+	refs := make(map[string]string, len(matches))
+	for _, match := range matches {
+		ref := strings.TrimSpace(match["ref"])
+		if ref == "" {
+			continue
+		}
+		refs[ref] = strings.TrimSpace(match["version"])
+	}
+	return refs, nil
+}
 
-			pkg := Package{
-				Name:        name,
-				Version:     vInfo.Version,
-				Constraint:  vInfo.Constraint,
-				Type:        pkgType,
-				PackageType: cfg.Manager,
-			}
+// extractSources collects the non-registry sources of packages from a source table.
+//
+// Parameters:
+//   - text: The full manifest text
+//   - pattern: Regex with named groups "n" (or "name") and "source"; empty disables the lookup
+//
+// Returns:
+//   - map[string]string: Source by lower-cased package name; nil when no pattern is configured
+//   - error: Returns an error if the regex pattern is invalid
+func extractSources(text, pattern string) (map[string]string, error) {
+	if pattern == "" {
+		return nil, nil
+	}
 
-			// Check if package should be ignored and set reason
-			if reason := getIgnoreReason(name, cfg); reason != "" {
-				pkg.IgnoreReason = reason
-			}
+	matches, err := utils.ExtractAllMatches(pattern, text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source pattern: %w", err)
+	}
 
-			packages = append(packages, pkg)
+	sources := make(map[string]string, len(matches))
+	for _, match := range matches {
+		name := match["name"]
+		if name == "" {
+			name = match["n"]
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || match["source"] == "" {
+			continue
 		}
+		sources[name] = strings.TrimSpace(match["source"])
+	}
+	return sources, nil
+}
+
+// isDevScope reports whether a captured scope matches any configured dev scope glob.
+//
+// Parameters:
+//   - scope: The captured scope (e.g., "testImplementation")
+//   - devScopes: Glob patterns accepted by path.Match
+//
+// Returns:
+//   - bool: true if the scope marks a dev dependency
+func isDevScope(scope string, devScopes []string) bool {
+	if scope == "" {
+		return false
+	}
+	for _, pattern := range devScopes {
+		if ok, _ := path.Match(pattern, scope); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRawMatches applies the extraction pattern to text and builds packages of one type.
+//
+// Parameters:
+//   - pattern: The extraction regex with named groups
+//   - text: The manifest text to match
+//   - pkgType: The dependency type assigned to every match (e.g., "prod", "dev")
+//   - refs: Named version definitions resolving "ref" captures; may be nil
+//   - sources: Non-registry sources by lower-cased package name; may be nil
+//   - cfg: The package manager configuration with mappings and overrides
+//
+// Returns:
+//   - []Package: Packages built from the matches
+//   - error: Returns an error if the regex pattern is invalid
+func parseRawMatches(pattern, text, pkgType string, refs, sources map[string]string, cfg *config.PackageManagerCfg) ([]Package, error) {
+	var packages []Package
+
+	matches, err := utils.ExtractAllMatches(pattern, text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	for _, match := range matches {
+		name := match["name"]
+		if name == "" {
+			name = match["n"]
+		}
+		constraint := match["constraint"]
+		version := match["version"]
+		if version == "" {
+			version = match["version_alt"]
+		}
+		if ref := strings.TrimSpace(match["ref"]); version == "" && ref != "" {
+			version = refs[ref]
+		}
+
+		if name == "" {
+			continue
+		}
+
+		matchType := pkgType
+		if isDevScope(match["scope"], cfg.Extraction.DevScopes) {
+			matchType = "dev"
+		}
+
+		vInfo := utils.VersionInfo{
+			Constraint: constraint,
+			Version:    version,
+		}
+
+		if cfg.ConstraintMapping != nil {
+			vInfo.Constraint = utils.MapConstraint(vInfo.Constraint, cfg.ConstraintMapping)
+		}
+
+		// Apply package-specific overrides
+		vInfo = utils.ApplyPackageOverride(name, vInfo, cfg)
+
+		vInfo = utils.NormalizeDeclaredVersion(name, vInfo, cfg)
+
+		source := match["source"]
+		if source == "" {
+			source = sources[strings.ToLower(name)]
+		}
+
+		pkg := Package{
+			Name:              name,
+			Version:           vInfo.Version,
+			Constraint:        vInfo.Constraint,
+			Type:              matchType,
+			PackageType:       cfg.Manager,
+			NonRegistrySource: source,
+		}
+
+		// Check if package should be ignored and set reason
+		if reason := getIgnoreReason(name, cfg); reason != "" {
+			pkg.IgnoreReason = reason
+		}
+
+		packages = append(packages, pkg)
 	}
 
 	return packages, nil
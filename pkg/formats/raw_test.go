@@ -314,3 +314,275 @@ func TestRawParserInvalidPattern(t *testing.T) {
 	_, err := parser.Parse([]byte("package"), cfg)
 	assert.Error(t, err)
 }
+
+// TestRawParserPoetryWildcardSections tests parsing pyproject.toml with wildcard group sections.
+//
+// It verifies:
+//   - Wildcard field names match every dependency group
+//   - Inline table declarations expose their version key
+//   - Path dependencies without a version are skipped
+//   - Non-dependency sections are not parsed
+func TestRawParserPoetryWildcardSections(t *testing.T) {
+	parser := &RawParser{}
+	cfg := &config.PackageManagerCfg{
+		Manager: "python",
+		Extraction: &config.ExtractionCfg{
+			Pattern: `(?m)^(?P<n>[A-Za-z0-9][\w\-\.]*)\s*=\s*(?:\{[^}\n]*?\bversion\s*=\s*)?"(?P<constraint>\^|~=|~|[><=!]+)?\s*(?P<version>[\w\.\-\+\*]+)?`,
+		},
+		Fields: map[string]string{
+			"tool.poetry.dependencies":         "prod",
+			"tool.poetry.group.*.dependencies": "dev",
+		},
+	}
+
+	content := []byte(`[tool.poetry]
+name = "demo"
+version = "0.1.0"
+
+[tool.poetry.dependencies]
+requests = "^2.31.0"
+celery = { version = "~5.3", extras = ["redis"], markers = "python_version >= '3.10'" }
+local-lib = { path = "../local-lib" }
+
+[tool.poetry.group.dev.dependencies]
+pytest = "^7.4.3"
+
+[tool.poetry.group.docs.dependencies]
+mkdocs = "1.5.3"`)
+
+	packages, err := parser.Parse(content, cfg)
+	require.NoError(t, err)
+
+	byName := map[string]Package{}
+	for _, pkg := range packages {
+		byName[pkg.Name] = pkg
+	}
+
+	require.Len(t, byName, 4)
+	assert.NotContains(t, byName, "name")
+	assert.NotContains(t, byName, "local-lib")
+	assert.Equal(t, "prod", byName["requests"].Type)
+	assert.Equal(t, "^", byName["requests"].Constraint)
+	assert.Equal(t, "5.3", byName["celery"].Version)
+	assert.Equal(t, "~", byName["celery"].Constraint)
+	assert.Equal(t, "dev", byName["pytest"].Type)
+	assert.Equal(t, "dev", byName["mkdocs"].Type)
+}
+
+// TestSectionMatches tests the behavior of sectionMatches.
+//
+// It verifies:
+//   - Exact names match
+//   - Wildcards match a single dotted segment
+//   - Lines outside any section never match a wildcard
+func TestSectionMatches(t *testing.T) {
+	assert.True(t, sectionMatches("packages", "packages"))
+	assert.False(t, sectionMatches("dev-packages", "packages"))
+	assert.True(t, sectionMatches("tool.poetry.group.dev.dependencies", "tool.poetry.group.*.dependencies"))
+	assert.True(t, sectionMatches("tool.poetry.group.docs.dependencies", "tool.poetry.group.*.dependencies"))
+	assert.False(t, sectionMatches("tool.poetry.dependencies", "tool.poetry.group.*.dependencies"))
+	assert.False(t, sectionMatches("", "*"))
+}
+
+// TestExtractTOMLArray tests the behavior of extractTOMLArray.
+//
+// It verifies:
+//   - Single-line and multi-line arrays are extracted from their section
+//   - Brackets inside quoted strings and comments do not end the array
+//   - Keys that are missing, not arrays, or lack a section yield ""
+//   - Wildcard sections collect the arrays of every matching table
+func TestExtractTOMLArray(t *testing.T) {
+	content := `[project]
+name = "demo"
+keywords = ["api"]
+dependencies = [
+    "fastapi>=0.110.0",  # web [framework]
+    "pydantic[email]~=2.6.4",
+    "httpx>=0.27.0; python_version >= '3.10'",
+]
+
+[tool.uv.a]
+dev = ["pytest>=8.0"]
+
+[tool.uv.b]
+dev = ["ruff==0.4.1"]`
+
+	deps := extractTOMLArray(content, "project.dependencies")
+	assert.Contains(t, deps, `"fastapi>=0.110.0"`)
+	assert.Contains(t, deps, `"pydantic[email]~=2.6.4"`)
+	assert.Contains(t, deps, `"httpx>=0.27.0; python_version >= '3.10'"`)
+	assert.NotContains(t, deps, "api\"]")
+
+	assert.Equal(t, `"api"`, extractTOMLArray(content, "project.keywords"))
+	assert.Empty(t, extractTOMLArray(content, "project.name"))
+	assert.Empty(t, extractTOMLArray(content, "project.missing"))
+	assert.Empty(t, extractTOMLArray(content, "dependencies"))
+	assert.Empty(t, extractTOMLArray(content, "project."))
+	assert.Equal(t, "\"pytest>=8.0\"\n\"ruff==0.4.1\"", extractTOMLArray(content, "tool.uv.*.dev"))
+	assert.Equal(t, `"open`, scanTOMLArray(`"open`))
+}
+
+// TestRawParserPEP508Arrays tests parsing PEP 508 requirement arrays of a pyproject.toml.
+//
+// It verifies:
+//   - Array fields and array tables are both parsed, with their configured types
+//   - Extras, markers and upper bounds do not leak into the version
+//   - Direct references capture their scheme as the source
+//   - The source pattern marks dependencies redirected by another table
+func TestRawParserPEP508Arrays(t *testing.T) {
+	parser := &RawParser{}
+	cfg := &config.PackageManagerCfg{
+		Manager: "python",
+		Extraction: &config.ExtractionCfg{
+			Pattern:       `(?m)(?:^|[\[,])[ \t]*"(?P<n>[A-Za-z0-9][\w\-\.]*)(?:[ \t]*\[[^\]"]*\])?[ \t]*(?:@[ \t]*(?P<source>git|hg|svn|bzr|file|https?)\b|(?P<constraint>[><=~!]+)[ \t]*(?P<version>[\w\.\-\+\*]+))?`,
+			SourcePattern: `(?m)^"?(?P<n>[A-Za-z0-9][\w\-\.]*)"?\s*=\s*\{[^}\n]*?\b(?P<source>git|path|url|workspace)\s*=`,
+		},
+		Fields: map[string]string{
+			"project.dependencies": "prod",
+			"dependency-groups":    "dev",
+		},
+	}
+
+	content := []byte(`[project]
+name = "demo"
+dependencies = ["pydantic[email]~=2.6.4", "httpx>=0.27.0,<1; python_version >= '3.10'",
+    "billing-sdk @ git+https://github.com/example/billing-sdk",
+    "internal-utils",
+]
+
+[dependency-groups]
+dev = ["pytest>=8.1.1"]
+
+[tool.uv.sources]
+internal-utils = { path = "../internal-utils", editable = true }`)
+
+	packages, err := parser.Parse(content, cfg)
+	require.NoError(t, err)
+
+	byName := map[string]Package{}
+	for _, pkg := range packages {
+		byName[pkg.Name] = pkg
+	}
+
+	require.Len(t, byName, 5)
+	assert.NotContains(t, byName, "demo")
+	assert.Equal(t, "2.6.4", byName["pydantic"].Version)
+	assert.Equal(t, "~=", byName["pydantic"].Constraint)
+	assert.Equal(t, "0.27.0", byName["httpx"].Version)
+	assert.Equal(t, "git", byName["billing-sdk"].NonRegistrySource)
+	assert.Equal(t, "path", byName["internal-utils"].NonRegistrySource)
+	assert.Equal(t, "prod", byName["internal-utils"].Type)
+	assert.Equal(t, "dev", byName["pytest"].Type)
+}
+
+// TestRawParserGemfileGroups tests parsing a Gemfile with dev groups configured.
+//
+// It verifies:
+//   - Gems inside development/test group blocks are dev
+//   - Gems with an inline group option are dev
+//   - Gems shared with a non-dev group stay prod
+//   - Nested non-group blocks inherit the enclosing group
+//   - Git and path gems capture their source option
+func TestRawParserGemfileGroups(t *testing.T) {
+	parser := &RawParser{}
+	cfg := &config.PackageManagerCfg{
+		Manager: "ruby",
+		Extraction: &config.ExtractionCfg{
+			Pattern:   `(?m)^[ \t]*gem[ \t]+["'](?P<n>[A-Za-z0-9][\w\.\-]*)["'](?:[ \t]*,[ \t]*["'](?P<constraint>~>|>=|<=|!=|=|>|<)?[ \t]*(?P<version>[\w\.\-]+)["'])?(?:[^\n#]*?\b(?P<source>git|github|gist|bitbucket|path)(?::|[ \t]*=>))?`,
+			DevGroups: []string{"development", "test"},
+		},
+		Fields: map[string]string{"gem": "prod"},
+	}
+
+	content := []byte(`source "https://rubygems.org"
+
+gem "rails", "~> 7.1.2"
+gem "rubocop", "~> 1.59", require: false, groups: [:development, :test]
+gem "auth", :git => "https://github.com/example/auth.git"
+
+group :development, :test do
+  if ENV["DEBUGGER"]
+    gem "debug", "1.9.1"
+  end
+  gem "local_tools", path: "../tools"
+end
+
+gem "puma", "6.4.0"
+
+group :production, :development do
+  gem "lograge", "0.14.0"
+end`)
+
+	packages, err := parser.Parse(content, cfg)
+	require.NoError(t, err)
+
+	byName := map[string]Package{}
+	for _, pkg := range packages {
+		byName[pkg.Name] = pkg
+	}
+
+	require.Len(t, byName, 7)
+	assert.Equal(t, "prod", byName["rails"].Type)
+	assert.Equal(t, "~>", byName["rails"].Constraint)
+	assert.Equal(t, "7.1.2", byName["rails"].Version)
+	assert.Equal(t, "dev", byName["rubocop"].Type)
+	assert.Equal(t, "dev", byName["debug"].Type)
+	assert.Equal(t, "dev", byName["local_tools"].Type)
+	assert.Equal(t, "prod", byName["puma"].Type, "group blocks end at their matching end")
+	assert.Equal(t, "prod", byName["lograge"].Type)
+	assert.Equal(t, "git", byName["auth"].NonRegistrySource)
+	assert.Equal(t, "path", byName["local_tools"].NonRegistrySource)
+	assert.Empty(t, byName["rails"].NonRegistrySource)
+}
+
+// TestRawParserPatternsVersionRefsAndScopes tests multi-pattern extraction with version refs and dev scopes.
+//
+// It verifies:
+//   - Every pattern in extraction.patterns is applied to the content
+//   - A "ref" capture takes its version from the version_ref_pattern definition
+//   - An undefined ref has no version, like any declaration missing one
+//   - A "scope" capture matching a dev_scopes glob types the package as dev
+//   - An invalid version_ref_pattern returns an error
+func TestRawParserPatternsVersionRefsAndScopes(t *testing.T) {
+	parser := &RawParser{}
+	cfg := &config.PackageManagerCfg{
+		Manager: "java",
+		Extraction: &config.ExtractionCfg{
+			Patterns: []config.PatternCfg{
+				{Name: "build", Pattern: `(?m)^[ \t]*(?P<scope>\w+)\("(?P<n>[\w.\-]+:[\w.\-]+):(?P<version>[^"]+)"\)`},
+				{Name: "catalog", Pattern: `(?m)^[\w.\-]+ = \{ module = "(?P<n>[^"]+)", version\.ref = "(?P<ref>[^"]+)" \}`},
+			},
+			VersionRefPattern: `(?m)^(?P<ref>[\w.\-]+) = "(?P<version>[^":]+)"`,
+			DevScopes:         []string{"test*"},
+		},
+		Fields: map[string]string{"dependencies": "prod"},
+	}
+
+	content := []byte(`okhttp = "4.12.0"
+okhttp = { module = "com.squareup.okhttp3:okhttp", version.ref = "okhttp" }
+logging = { module = "com.squareup.okhttp3:logging-interceptor", version.ref = "okhttp" }
+missing = { module = "org.example:missing", version.ref = "nope" }
+    implementation("com.google.guava:guava:32.1.3-jre")
+    testImplementation("junit:junit:4.13.2")`)
+
+	packages, err := parser.Parse(content, cfg)
+	require.NoError(t, err)
+
+	byName := map[string]Package{}
+	for _, pkg := range packages {
+		byName[pkg.Name] = pkg
+	}
+
+	require.Len(t, byName, 5)
+	assert.Equal(t, "4.12.0", byName["com.squareup.okhttp3:okhttp"].Version)
+	assert.Equal(t, "4.12.0", byName["com.squareup.okhttp3:logging-interceptor"].Version)
+	assert.Equal(t, "*", byName["org.example:missing"].Version)
+	assert.Equal(t, "prod", byName["com.google.guava:guava"].Type)
+	assert.Equal(t, "32.1.3-jre", byName["com.google.guava:guava"].Version)
+	assert.Equal(t, "dev", byName["junit:junit"].Type)
+	assert.Equal(t, "prod", byName["com.squareup.okhttp3:okhttp"].Type)
+
+	cfg.Extraction.VersionRefPattern = `(?P<ref>[`
+	_, err = parser.Parse(content, cfg)
+	assert.ErrorContains(t, err, "invalid version ref pattern")
+}
@@ -24,6 +24,7 @@ type XMLParser struct{}
 //   - Applies version parsing, constraints, and package overrides
 //   - Identifies dev dependencies based on configured markers
 //   - Falls back to PackageReference nodes for .NET/NuGet projects
+//   - Reads central package management entries (Directory.Packages.props)
 //
 // Parameters:
 //   - content: The raw bytes of the XML package manifest file
@@ -98,46 +99,163 @@ func (p *XMLParser) Parse(content []byte, cfg *config.PackageManagerCfg) ([]Pack
 	}
 
 	if len(packages) == 0 && (cfg.Manager == "nuget" || cfg.Manager == "dotnet") {
-		packageRefs := utils.FindXMLNodes(&root, "ItemGroup/PackageReference")
-		for _, ref := range packageRefs {
-			name := utils.GetXMLAttr(ref, "Include")
-			version := utils.GetXMLAttr(ref, "Version")
+		packages = parseNugetFallback(&root, cfg)
+	}
 
-			if name == "" || version == "" {
-				continue
+	return packages, nil
+}
+
+// parseNugetFallback extracts packages from .NET/NuGet project and props files.
+//
+// It reads the following nodes:
+//   - ItemGroup/PackageReference with a Version or VersionOverride attribute
+//   - ItemGroup/PackageVersion entries of a central package management file
+//     (Directory.Packages.props), unless ManagePackageVersionsCentrally is false
+//   - ItemGroup/GlobalPackageReference entries, which are always dev dependencies
+//
+// Versionless PackageReference entries in projects using central package
+// management are skipped; their version is declared and updated in the props file.
+//
+// Parameters:
+//   - root: The parsed XML document root
+//   - cfg: The package manager configuration with overrides and dev markers
+//
+// Returns:
+//   - []Package: The packages found in the document
+func parseNugetFallback(root *utils.XMLNode, cfg *config.PackageManagerCfg) []Package {
+	var packages []Package
+
+	add := func(node *utils.XMLNode, versionAttrs []string, forceDev bool) {
+		name := utils.GetXMLAttr(node, "Include")
+		version := ""
+		for _, attr := range versionAttrs {
+			if version = utils.GetXMLAttr(node, attr); version != "" {
+				break
 			}
+		}
 
-			vInfo := utils.ParseVersion(version)
+		if name == "" || version == "" {
+			return
+		}
 
-			// Apply package-specific overrides
-			vInfo = utils.ApplyPackageOverride(name, vInfo, cfg)
+		vInfo := utils.ParseVersion(version)
 
-			vInfo = utils.NormalizeDeclaredVersion(name, vInfo, cfg)
+		// Apply package-specific overrides
+		vInfo = utils.ApplyPackageOverride(name, vInfo, cfg)
 
-			// Determine package type - check for dev dependency markers
-			pkgType := "prod"
-			if cfg.Extraction != nil && isDevDependency(ref, cfg.Extraction) {
-				pkgType = "dev"
-			}
+		vInfo = utils.NormalizeDeclaredVersion(name, vInfo, cfg)
 
-			pkg := Package{
-				Name:        name,
-				Version:     vInfo.Version,
-				Constraint:  vInfo.Constraint,
-				Type:        pkgType,
-				PackageType: cfg.Manager,
-			}
+		// Determine package type - check for dev dependency markers
+		pkgType := "prod"
+		if forceDev || (cfg.Extraction != nil && isDevDependency(node, cfg.Extraction)) {
+			pkgType = "dev"
+		}
 
-			// Check if package should be ignored and set reason
-			if reason := getIgnoreReason(name, cfg); reason != "" {
-				pkg.IgnoreReason = reason
-			}
+		pkg := Package{
+			Name:        name,
+			Version:     vInfo.Version,
+			Constraint:  vInfo.Constraint,
+			Type:        pkgType,
+			PackageType: cfg.Manager,
+		}
 
-			packages = append(packages, pkg)
+		// Check if package should be ignored and set reason
+		if reason := getIgnoreReason(name, cfg); reason != "" {
+			pkg.IgnoreReason = reason
 		}
+
+		packages = append(packages, pkg)
 	}
 
-	return packages, nil
+	for _, ref := range utils.FindXMLNodes(root, "ItemGroup/PackageReference") {
+		add(ref, []string{"Version", "VersionOverride"}, false)
+	}
+
+	if centralPackageManagementEnabled(root) {
+		for _, ref := range utils.FindXMLNodes(root, "ItemGroup/PackageVersion") {
+			add(ref, []string{"Version"}, false)
+		}
+		for _, ref := range utils.FindXMLNodes(root, "ItemGroup/GlobalPackageReference") {
+			add(ref, []string{"Version"}, true)
+		}
+	}
+
+	return packages
+}
+
+// centralPackageManagementEnabled reports whether PackageVersion entries of a
+// document should be read.
+//
+// The ManagePackageVersionsCentrally property is commonly set in
+// Directory.Packages.props itself, but may also live in Directory.Build.props,
+// so a missing property is treated as enabled. Only an explicit false disables it.
+//
+// Parameters:
+//   - root: The parsed XML document root
+//
+// Returns:
+//   - bool: false only when ManagePackageVersionsCentrally is explicitly false
+func centralPackageManagementEnabled(root *utils.XMLNode) bool {
+	for _, node := range utils.FindXMLNodes(root, "PropertyGroup/ManagePackageVersionsCentrally") {
+		if strings.EqualFold(strings.TrimSpace(node.Content), "false") {
+			return false
+		}
+	}
+	return true
+}
+
+// CentralPackageReferences returns the packages a .NET project takes from central package management.
+//
+// These are the PackageReference entries without a Version or VersionOverride
+// attribute, whose version is declared in Directory.Packages.props. Entries
+// with a VersionOverride are parsed from the project itself.
+//
+// Parameters:
+//   - content: The raw bytes of the project file (.csproj, .vbproj, .fsproj)
+//
+// Returns:
+//   - []string: Package names in declaration order
+//   - error: Returns an error if the XML is invalid
+func CentralPackageReferences(content []byte) ([]string, error) {
+	var root utils.XMLNode
+	if err := xml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("invalid XML: %w", err)
+	}
+
+	var names []string
+	for _, ref := range utils.FindXMLNodes(&root, "ItemGroup/PackageReference") {
+		name := utils.GetXMLAttr(ref, "Include")
+		if name == "" || utils.GetXMLAttr(ref, "Version") != "" || utils.GetXMLAttr(ref, "VersionOverride") != "" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// GlobalPackageReferences returns the GlobalPackageReference names of a Directory.Packages.props file.
+//
+// Global package references apply to every project below the props file.
+//
+// Parameters:
+//   - content: The raw bytes of the props file
+//
+// Returns:
+//   - []string: Package names in declaration order
+//   - error: Returns an error if the XML is invalid
+func GlobalPackageReferences(content []byte) ([]string, error) {
+	var root utils.XMLNode
+	if err := xml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("invalid XML: %w", err)
+	}
+
+	var names []string
+	for _, ref := range utils.FindXMLNodes(&root, "ItemGroup/GlobalPackageReference") {
+		if name := utils.GetXMLAttr(ref, "Include"); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
 }
 
 // isDevDependency checks if an XML node represents a dev dependency based on extraction config.
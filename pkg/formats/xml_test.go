@@ -119,6 +119,90 @@ func TestXMLParserNugetFallback(t *testing.T) {
 	assert.Equal(t, "prod", packages[0].Type)
 }
 
+// TestXMLParserCentralPackageManagement tests parsing of Directory.Packages.props entries.
+//
+// It verifies:
+//   - PackageVersion entries are parsed as prod packages
+//   - GlobalPackageReference entries are parsed as dev packages
+//   - Versionless PackageReference entries are skipped while VersionOverride is read
+//   - CentralPackageReferences and GlobalPackageReferences list the centrally versioned names
+//   - PackageVersion entries are ignored when ManagePackageVersionsCentrally is false
+func TestXMLParserCentralPackageManagement(t *testing.T) {
+	parser := &XMLParser{}
+	cfg := &config.PackageManagerCfg{
+		Manager: "dotnet",
+		Fields: map[string]string{
+			"ItemGroup/PackageReference": "prod",
+		},
+		Extraction: &config.ExtractionCfg{DevElement: "PrivateAssets", DevElementValue: "all"},
+	}
+
+	t.Run("props file", func(t *testing.T) {
+		content := []byte(`<Project>
+  <PropertyGroup>
+    <ManagePackageVersionsCentrally>true</ManagePackageVersionsCentrally>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageVersion Include="Newtonsoft.Json" Version="13.0.1" />
+    <PackageVersion Include="Serilog" Version="3.1.1" />
+  </ItemGroup>
+  <ItemGroup>
+    <GlobalPackageReference Include="StyleCop.Analyzers" Version="1.1.118" />
+  </ItemGroup>
+</Project>`)
+
+		packages, err := parser.Parse(content, cfg)
+		require.NoError(t, err)
+		require.Len(t, packages, 3)
+
+		assert.Equal(t, "Newtonsoft.Json", packages[0].Name)
+		assert.Equal(t, "13.0.1", packages[0].Version)
+		assert.Equal(t, "prod", packages[0].Type)
+		assert.Equal(t, "Serilog", packages[1].Name)
+		assert.Equal(t, "StyleCop.Analyzers", packages[2].Name)
+		assert.Equal(t, "dev", packages[2].Type)
+
+		globals, err := GlobalPackageReferences(content)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"StyleCop.Analyzers"}, globals)
+	})
+
+	t.Run("project with central versions", func(t *testing.T) {
+		content := []byte(`<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" />
+    <PackageReference Include="Serilog" VersionOverride="3.0.0" />
+  </ItemGroup>
+</Project>`)
+
+		packages, err := parser.Parse(content, cfg)
+		require.NoError(t, err)
+		require.Len(t, packages, 1)
+
+		assert.Equal(t, "Serilog", packages[0].Name)
+		assert.Equal(t, "3.0.0", packages[0].Version)
+
+		central, err := CentralPackageReferences(content)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Newtonsoft.Json"}, central)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		content := []byte(`<Project>
+  <PropertyGroup>
+    <ManagePackageVersionsCentrally>false</ManagePackageVersionsCentrally>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageVersion Include="Newtonsoft.Json" Version="13.0.1" />
+  </ItemGroup>
+</Project>`)
+
+		packages, err := parser.Parse(content, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, packages)
+	})
+}
+
 // TestXMLParserWithExtractionAttributes tests custom extraction attribute names.
 //
 // It verifies:
@@ -9,8 +9,8 @@ import (
 
 // YAMLParser parses YAML package files.
 //
-// It supports YAML-based package managers such as Composer, Conda, and Docker Compose,
-// handling both map-based and array-based dependency structures with nested field access.
+// It supports YAML-based package managers such as Composer, Conda, Docker Compose, and
+// Dart pub, handling both map-based and array-based dependency structures with nested field access.
 type YAMLParser struct{}
 
 // Parse parses YAML content and extracts package dependencies.
@@ -20,6 +20,7 @@ type YAMLParser struct{}
 //   - Retrieves dependency fields using dot notation (e.g., "dependencies.production")
 //   - Handles both map-based dependencies (name: version) and array-based dependencies
 //   - Extracts container image specifications for Docker Compose files
+//   - Reads "version" keys and sdk/git/path sources from map-valued dependencies (Dart pub)
 //   - Applies version parsing, constraint mapping, and package overrides
 //   - Filters ignored packages based on configuration
 //
@@ -57,22 +58,28 @@ func (p *YAMLParser) Parse(content []byte, cfg *config.PackageManagerCfg) ([]Pac
 					versionStr = fmt.Sprintf("%v", v)
 				}
 				resolvedName := name
+				source := ""
 
-				switch depMap := version.(type) {
+				var depMap map[string]interface{}
+				switch m := version.(type) {
 				case map[string]interface{}:
-					versionStr, resolvedName = parseImageFromMap(depMap, resolvedName, versionStr, cfg)
+					depMap = m
 				case map[interface{}]interface{}:
-					normalized := make(map[string]interface{})
-					for k, v := range depMap {
+					depMap = make(map[string]interface{})
+					for k, v := range m {
 						if key, ok := k.(string); ok {
-							normalized[key] = v
+							depMap[key] = v
 						}
 					}
-					versionStr, resolvedName = parseImageFromMap(normalized, resolvedName, versionStr, cfg)
+				}
+				if depMap != nil {
+					versionStr, resolvedName = parseImageFromMap(depMap, resolvedName, versionStr, cfg)
+					versionStr, source = parseSourceFromMap(depMap, versionStr)
 				}
 
 				vInfo := processVersion(versionStr, resolvedName, cfg)
 				pkg := newPackage(resolvedName, vInfo, pkgType, cfg)
+				pkg.NonRegistrySource = source
 
 				// Check if package should be ignored and set reason
 				if reason := getIgnoreReason(resolvedName, cfg); reason != "" {
@@ -270,6 +270,55 @@ func TestParseImageFromMapVariations(t *testing.T) {
 	assert.Equal(t, "nginx", name)
 }
 
+// TestYAMLParserSourceMaps tests map-valued dependencies as used by Dart pub.
+//
+// It verifies:
+//   - Hosted dependencies read their version from the "version" key
+//   - sdk, git, and path dependencies are marked with their source key
+//   - Plain string dependencies have no non-registry source
+func TestYAMLParserSourceMaps(t *testing.T) {
+	parser := &YAMLParser{}
+	cfg := &config.PackageManagerCfg{
+		Manager: "dart",
+		Fields:  map[string]string{"dependencies": "prod"},
+	}
+
+	content := []byte(`
+dependencies:
+  flutter:
+    sdk: flutter
+  http: ^1.1.0
+  internal_api:
+    hosted: https://pub.example.com
+    version: ^2.3.0
+  forked:
+    git:
+      url: https://github.com/example/forked.git
+  local_utils:
+    path: ../local_utils
+`)
+
+	packages, err := parser.Parse(content, cfg)
+	require.NoError(t, err)
+	require.Len(t, packages, 5)
+
+	packageMap := map[string]Package{}
+	for _, p := range packages {
+		packageMap[p.Name] = p
+	}
+
+	assert.Equal(t, "1.1.0", packageMap["http"].Version)
+	assert.Equal(t, "^", packageMap["http"].Constraint)
+	assert.Empty(t, packageMap["http"].NonRegistrySource)
+
+	assert.Equal(t, "2.3.0", packageMap["internal_api"].Version)
+	assert.Empty(t, packageMap["internal_api"].NonRegistrySource)
+
+	assert.Equal(t, "sdk", packageMap["flutter"].NonRegistrySource)
+	assert.Equal(t, "git", packageMap["forked"].NonRegistrySource)
+	assert.Equal(t, "path", packageMap["local_utils"].NonRegistrySource)
+}
+
 // TestYAMLParserWithOverrides tests package overrides in YAMLParser.
 //
 // It verifies:
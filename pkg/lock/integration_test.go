@@ -1,10 +1,14 @@
 package lock
 
 import (
+	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/packages"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -303,10 +307,96 @@ func TestIntegration_Yarn(t *testing.T) {
 	assert.Equal(t, InstallStatusLockFound, statusLookup["express"])
 }
 
+// TestIntegration_Bun tests the behavior of bun package resolution with real testdata.
+//
+// It verifies:
+//   - Installed versions are resolved from the text bun.lock, by package key
+//   - bun.lockb is read through bun pm ls when bun is installed
+//   - Without bun, packages locked by bun.lockb are LockUnavailable instead of failing resolution
+func TestIntegration_Bun(t *testing.T) {
+	resolve := func(t *testing.T, dir string) map[string]formats.Package {
+		testdataDir, err := filepath.Abs(filepath.Join("../testdata", dir))
+		require.NoError(t, err, "failed to get absolute path to testdata")
+
+		cfg, err := config.LoadConfig("", testdataDir)
+		require.NoError(t, err)
+
+		rule := cfg.Rules["bun"]
+		result, err := packages.NewDynamicParser().ParseFile(filepath.Join(testdataDir, "package.json"), &rule)
+		require.NoError(t, err)
+		for i := range result.Packages {
+			result.Packages[i].Rule = "bun"
+		}
+
+		enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+		require.NoError(t, err)
+
+		byName := make(map[string]formats.Package)
+		for _, pkg := range enriched {
+			byName[pkg.Name] = pkg
+		}
+		return byName
+	}
+
+	// withPath runs bun lookups against a PATH holding only sh and the given scripts
+	withPath := func(t *testing.T, scripts map[string]string) {
+		if runtime.GOOS == "windows" {
+			t.Skip("skipping Unix-specific test on Windows")
+		}
+		sh, err := exec.LookPath("sh")
+		require.NoError(t, err)
+		bin := t.TempDir()
+		require.NoError(t, os.Symlink(sh, filepath.Join(bin, "sh")))
+		for name, script := range scripts {
+			require.NoError(t, os.WriteFile(filepath.Join(bin, name), []byte(script), 0o755))
+		}
+		t.Setenv("SHELL", "")
+		t.Setenv("PATH", bin)
+	}
+
+	t.Run("text lock", func(t *testing.T) {
+		byName := resolve(t, "bun")
+		require.Len(t, byName, 5)
+		assert.Equal(t, "18.3.1", byName["react"].InstalledVersion)
+		assert.Equal(t, "5.59.20", byName["@tanstack/react-query"].InstalledVersion)
+		assert.Equal(t, "3.23.8", byName["zod"].InstalledVersion)
+		assert.Equal(t, "1.1.13", byName["@types/bun"].InstalledVersion)
+		assert.Equal(t, "dev", byName["typescript"].Type)
+		assert.Equal(t, InstallStatusLockFound, byName["typescript"].InstallStatus)
+	})
+
+	t.Run("binary lock with bun", func(t *testing.T) {
+		withPath(t, map[string]string{"bun": `#!/bin/sh
+[ "$1 $2 $3" = "pm ls --all" ] || exit 2
+echo "$PWD node_modules (6)"
+echo "├── @tanstack/react-query@5.59.20"
+echo "├── @types/bun@1.1.13"
+echo "├── react@18.3.1"
+echo "├── typescript@5.6.3"
+echo "└── zod@3.23.8"
+`})
+		byName := resolve(t, "bun_lockb")
+		assert.Equal(t, "18.3.1", byName["react"].InstalledVersion)
+		assert.Equal(t, "1.1.13", byName["@types/bun"].InstalledVersion)
+		assert.Equal(t, InstallStatusLockFound, byName["zod"].InstallStatus)
+	})
+
+	t.Run("binary lock without bun", func(t *testing.T) {
+		withPath(t, nil)
+		byName := resolve(t, "bun_lockb")
+		require.Len(t, byName, 5)
+		for name, pkg := range byName {
+			assert.Equal(t, "#N/A", pkg.InstalledVersion, name)
+			assert.Equal(t, InstallStatusLockUnavailable, pkg.InstallStatus, name)
+		}
+	})
+}
+
 // TestIntegration_Requirements tests the behavior of pip requirements.txt resolution with real testdata.
 //
 // requirements.txt uses self-pinning mode - the declared version IS the installed version.
-// This test verifies packages with pinned versions (==) use declared version.
+// This test verifies packages with pinned versions (==) use declared version, and that
+// the PEP 508 extraction shared with the uv rule marks direct references NonRegistry.
 func TestIntegration_Requirements(t *testing.T) {
 	testdataDir, err := filepath.Abs("../testdata/requirements")
 	require.NoError(t, err, "failed to get absolute path to testdata")
@@ -341,6 +431,9 @@ func TestIntegration_Requirements(t *testing.T) {
 	// Floating constraints (*) should have Floating status
 	assert.Equal(t, InstallStatusFloating, statusLookup["celery"], "celery with * should be Floating")
 	assert.Equal(t, InstallStatusFloating, statusLookup["uvicorn"], "uvicorn with * should be Floating")
+
+	assert.Equal(t, InstallStatusNonRegistry, statusLookup["internal-sdk"], "direct git reference should be NonRegistry")
+	assert.Equal(t, cfg.Rules["requirements"].Extraction.Pattern, cfg.Rules["uv"].Extraction.Pattern, "uv shares the PEP 508 extraction")
 }
 
 // TestIntegration_Pipfile tests the behavior of Pipfile resolution with real testdata.
@@ -349,6 +442,9 @@ func TestIntegration_Requirements(t *testing.T) {
 //   - Pipfile packages are correctly parsed
 //   - Installed versions are resolved from Pipfile.lock
 //   - Package status is correctly set to LockFound
+//   - [dev-packages] entries are dev and resolve from the lock file's develop section
+//   - Inline-table and quoted dotted-name declarations are parsed
+//   - git and path entries are marked NonRegistry with their key as the source
 func TestIntegration_Pipfile(t *testing.T) {
 	testdataDir, err := filepath.Abs("../testdata/pipfile")
 	require.NoError(t, err, "failed to get absolute path to testdata")
@@ -390,6 +486,552 @@ func TestIntegration_Pipfile(t *testing.T) {
 	// Verify status is correct
 	assert.Equal(t, InstallStatusLockFound, statusLookup["django"])
 	assert.Equal(t, InstallStatusLockFound, statusLookup["flask"])
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+	}
+
+	assert.Equal(t, "prod", byName["django"].Type)
+	assert.Equal(t, "dev", byName["pytest"].Type)
+	assert.Equal(t, "7.4.4", byName["pytest"].InstalledVersion)
+	assert.Equal(t, "dev", byName["types-requests"].Type)
+	assert.Equal(t, ">=", byName["types-requests"].Constraint)
+	assert.Equal(t, "2.31.0.20240106", byName["types-requests"].InstalledVersion)
+
+	assert.Equal(t, "6.1", byName["zope.interface"].InstalledVersion, "dotted names are read from quoted keys")
+	assert.Equal(t, "1.39.0", byName["sentry-sdk"].Version)
+	assert.Equal(t, "1.39.1", byName["sentry-sdk"].InstalledVersion)
+	assert.Equal(t, "==", byName["pywin32"].Constraint)
+	assert.Equal(t, "306", byName["pywin32"].InstalledVersion)
+
+	assert.Equal(t, InstallStatusNonRegistry, byName["internal-auth"].InstallStatus)
+	assert.Equal(t, "git", byName["internal-auth"].NonRegistrySource)
+	assert.Equal(t, InstallStatusNonRegistry, byName["billing"].InstallStatus)
+	assert.Equal(t, "path", byName["billing"].NonRegistrySource)
+	assert.NotContains(t, byName, "python_version", "[requires] is not a package section")
+}
+
+// TestIntegration_Poetry tests the behavior of Poetry resolution with real testdata.
+//
+// It verifies:
+//   - Main dependencies are prod and every dependency group is dev
+//   - Installed versions are resolved from poetry.lock, including case-normalized names
+//   - The python interpreter requirement is ignored
+//   - Path dependencies without a version are skipped
+func TestIntegration_Poetry(t *testing.T) {
+	testdataDir, err := filepath.Abs("../testdata/poetry")
+	require.NoError(t, err, "failed to get absolute path to testdata")
+
+	cfg, err := config.LoadConfig("", testdataDir)
+	require.NoError(t, err)
+
+	parser := packages.NewDynamicParser()
+	rule := cfg.Rules["poetry"]
+	result, err := parser.ParseFile(filepath.Join(testdataDir, "pyproject.toml"), &rule)
+	require.NoError(t, err)
+
+	for i := range result.Packages {
+		result.Packages[i].Rule = "poetry"
+	}
+
+	enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+	require.NoError(t, err)
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+	}
+
+	assert.NotContains(t, byName, "internal-utils", "path dependencies have no version and should be skipped")
+	assert.Equal(t, InstallStatusIgnored, byName["python"].InstallStatus)
+
+	// Django is declared with a capital letter but poetry.lock stores "django"
+	assert.Equal(t, "4.2.8", byName["Django"].InstalledVersion)
+	assert.Equal(t, InstallStatusLockFound, byName["Django"].InstallStatus)
+	assert.Equal(t, "^", byName["Django"].Constraint)
+	assert.Equal(t, "prod", byName["Django"].Type)
+
+	assert.Equal(t, "~", byName["requests"].Constraint)
+	assert.Equal(t, "2.31.0", byName["requests"].InstalledVersion)
+	assert.Equal(t, "5.3.6", byName["celery"].InstalledVersion, "inline table versions should be parsed")
+	assert.Equal(t, "306", byName["pywin32"].InstalledVersion)
+	assert.Equal(t, InstallStatusFloating, byName["gunicorn"].InstallStatus)
+
+	assert.Equal(t, "dev", byName["pytest"].Type)
+	assert.Equal(t, "dev", byName["black"].Type)
+	assert.Equal(t, "dev", byName["mkdocs"].Type, "non-dev groups are also dev dependencies")
+	assert.Equal(t, "7.4.3", byName["pytest"].InstalledVersion)
+}
+
+// TestIntegration_UV tests the behavior of uv resolution with real testdata.
+//
+// It verifies:
+//   - [project] dependencies and optional extras are prod; dependency groups and [tool.uv] dev-dependencies are dev
+//   - Only dependency arrays are parsed, not keywords, classifiers or build requirements
+//   - Installed versions are resolved from uv.lock, including case-normalized names
+//   - Extras, upper bounds and markers do not change the captured version
+//   - Direct git references and [tool.uv.sources] paths are NonRegistry
+func TestIntegration_UV(t *testing.T) {
+	testdataDir, err := filepath.Abs("../testdata/uv")
+	require.NoError(t, err, "failed to get absolute path to testdata")
+
+	cfg, err := config.LoadConfig("", testdataDir)
+	require.NoError(t, err)
+
+	parser := packages.NewDynamicParser()
+	rule := cfg.Rules["uv"]
+	result, err := parser.ParseFile(filepath.Join(testdataDir, "pyproject.toml"), &rule)
+	require.NoError(t, err)
+
+	for i := range result.Packages {
+		result.Packages[i].Rule = "uv"
+	}
+
+	enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+	require.NoError(t, err)
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+	}
+	assert.Len(t, byName, 12)
+	for _, notDependency := range []string{"billing", "invoices", "Programming", "Framework", "hatchling"} {
+		assert.NotContains(t, byName, notDependency)
+	}
+
+	assert.Equal(t, ">=", byName["fastapi"].Constraint)
+	assert.Equal(t, "0.110.0", byName["fastapi"].Version)
+	assert.Equal(t, "0.110.1", byName["fastapi"].InstalledVersion)
+	assert.Equal(t, "prod", byName["fastapi"].Type)
+
+	assert.Equal(t, "2.6.4", byName["pydantic"].Version, "extras are not part of the version")
+	assert.Equal(t, "2.6.4", byName["pydantic"].InstalledVersion)
+	assert.Equal(t, "0.27.0", byName["httpx"].Version, "upper bounds and markers are not part of the version")
+
+	// SQLAlchemy is declared with capitals but uv.lock stores "sqlalchemy"
+	assert.Equal(t, "2.0.29", byName["SQLAlchemy"].InstalledVersion)
+	assert.Equal(t, InstallStatusLockFound, byName["SQLAlchemy"].InstallStatus)
+
+	assert.Equal(t, "prod", byName["psycopg"].Type, "optional extras ship with the package")
+	assert.Equal(t, "dev", byName["pytest"].Type)
+	assert.Equal(t, "dev", byName["mkdocs"].Type)
+	assert.Equal(t, "dev", byName["mypy"].Type, "legacy [tool.uv] dev-dependencies are dev")
+	assert.Equal(t, "1.9.0", byName["mypy"].InstalledVersion)
+
+	assert.Equal(t, InstallStatusNonRegistry, byName["billing-sdk"].InstallStatus)
+	assert.Equal(t, "git", byName["billing-sdk"].NonRegistrySource)
+	assert.Equal(t, InstallStatusNonRegistry, byName["internal-utils"].InstallStatus)
+	assert.Equal(t, "path", byName["internal-utils"].NonRegistrySource)
+}
+
+// TestIntegration_Bundler tests the behavior of Bundler resolution with real testdata.
+//
+// It verifies:
+//   - Gems in development/test groups are dev, including inline group options
+//   - Gems shared with a non-dev group stay prod
+//   - Installed versions are resolved from Gemfile.lock, stripping platform suffixes
+//   - Git and path gems are marked NonRegistry with their source option
+//   - Gems without a version requirement are Floating
+func TestIntegration_Bundler(t *testing.T) {
+	testdataDir, err := filepath.Abs("../testdata/bundler")
+	require.NoError(t, err, "failed to get absolute path to testdata")
+
+	cfg, err := config.LoadConfig("", testdataDir)
+	require.NoError(t, err)
+
+	parser := packages.NewDynamicParser()
+	rule := cfg.Rules["bundler"]
+	result, err := parser.ParseFile(filepath.Join(testdataDir, "Gemfile"), &rule)
+	require.NoError(t, err)
+
+	for i := range result.Packages {
+		result.Packages[i].Rule = "bundler"
+	}
+
+	enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+	require.NoError(t, err)
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+	}
+
+	assert.NotContains(t, byName, "ruby", "the ruby version directive is not a gem")
+
+	assert.Equal(t, "prod", byName["rails"].Type)
+	assert.Equal(t, "~", byName["rails"].Constraint)
+	assert.Equal(t, "7.1.2", byName["rails"].Version)
+	assert.Equal(t, "7.1.2", byName["rails"].InstalledVersion)
+	assert.Equal(t, InstallStatusLockFound, byName["rails"].InstallStatus)
+	assert.Equal(t, ">=", byName["pg"].Constraint)
+	assert.Equal(t, "1.5.4", byName["pg"].InstalledVersion)
+	assert.Equal(t, "7.2", byName["sidekiq"].Version, "only the first requirement is parsed")
+	assert.Equal(t, "70.1", byName["activerecord-jdbcpostgresql-adapter"].InstalledVersion)
+	assert.Equal(t, "prod", byName["activerecord-jdbcpostgresql-adapter"].Type)
+	assert.Equal(t, InstallStatusFloating, byName["bootsnap"].InstallStatus)
+
+	assert.Equal(t, "dev", byName["rspec-rails"].Type)
+	assert.Equal(t, "dev", byName["debug"].Type)
+	assert.Equal(t, "dev", byName["web-console"].Type)
+	assert.Equal(t, "dev", byName["capybara"].Type)
+	assert.Equal(t, "dev", byName["rubocop"].Type, "inline group options are dev")
+	assert.Equal(t, "prod", byName["lograge"].Type, "gems shared with production stay prod")
+
+	assert.Equal(t, InstallStatusNonRegistry, byName["internal_auth"].InstallStatus)
+	assert.Equal(t, "git", byName["internal_auth"].NonRegistrySource)
+	assert.Equal(t, "0.3.0", byName["internal_auth"].InstalledVersion)
+	assert.Equal(t, InstallStatusNonRegistry, byName["billing_engine"].InstallStatus)
+	assert.Equal(t, "path", byName["billing_engine"].NonRegistrySource)
+}
+
+// TestIntegration_Brew tests the behavior of Homebrew Brewfile resolution with real testdata.
+//
+// It verifies:
+//   - brew formulae are parsed with single or double quotes and trailing options
+//   - Unpinned formulae are marked Floating regardless of what is installed locally
+//   - cask, tap, and mas entries are marked NonRegistry with their keyword as the source
+//   - The lock command extraction reads the last installed version from brew list output,
+//     without the bottle revision
+func TestIntegration_Brew(t *testing.T) {
+	testdataDir, err := filepath.Abs("../testdata/brew")
+	require.NoError(t, err, "failed to get absolute path to testdata")
+
+	cfg, err := config.LoadConfig("", testdataDir)
+	require.NoError(t, err)
+
+	parser := packages.NewDynamicParser()
+	rule := cfg.Rules["brew"]
+	result, err := parser.ParseFile(filepath.Join(testdataDir, "Brewfile"), &rule)
+	require.NoError(t, err)
+
+	for i := range result.Packages {
+		result.Packages[i].Rule = "brew"
+	}
+
+	enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+	require.NoError(t, err)
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+	}
+	require.Len(t, byName, 11)
+
+	for _, name := range []string{"git", "jq", "node@20", "postgresql@16", "hashicorp/tap/terraform", "wget"} {
+		assert.Equal(t, "brew", byName[name].PackageType, name)
+		assert.Equal(t, "*", byName[name].Version, name)
+		assert.Empty(t, byName[name].NonRegistrySource, name)
+		assert.Equal(t, InstallStatusFloating, byName[name].InstallStatus, name)
+	}
+
+	assert.Equal(t, "cask", byName["docker"].NonRegistrySource)
+	assert.Equal(t, "tap", byName["homebrew/bundle"].NonRegistrySource)
+	assert.Equal(t, "mas", byName["Xcode"].NonRegistrySource)
+	for _, name := range []string{"docker", "visual-studio-code", "homebrew/bundle", "hashicorp/tap", "Xcode"} {
+		assert.Equal(t, InstallStatusNonRegistry, byName[name].InstallStatus, name)
+	}
+
+	output := "git 2.43.0\nnode@20 20.10.0 20.11.1\npostgresql@16 16.1_1\n"
+	installed, err := parseLockCommandOutput([]byte(output), rule.LockFiles[0].CommandExtraction)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"git": "2.43.0", "node@20": "20.11.1", "postgresql@16": "16.1"}, installed)
+}
+
+// TestIntegration_Pub tests the behavior of Dart/Flutter pubspec.yaml resolution with real testdata.
+//
+// It verifies:
+//   - dependencies and dev_dependencies are parsed as prod and dev packages
+//   - The environment sdk constraint and flutter section are not parsed as packages
+//   - Installed versions are read from pubspec.lock
+//   - sdk, git, and path dependencies are marked NonRegistry with their source key
+func TestIntegration_Pub(t *testing.T) {
+	testdataDir, err := filepath.Abs("../testdata/pub")
+	require.NoError(t, err, "failed to get absolute path to testdata")
+
+	cfg, err := config.LoadConfig("", testdataDir)
+	require.NoError(t, err)
+
+	parser := packages.NewDynamicParser()
+	rule := cfg.Rules["pub"]
+	result, err := parser.ParseFile(filepath.Join(testdataDir, "pubspec.yaml"), &rule)
+	require.NoError(t, err)
+
+	for i := range result.Packages {
+		result.Packages[i].Rule = "pub"
+	}
+
+	enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+	require.NoError(t, err)
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+	}
+	require.Len(t, byName, 11)
+	assert.NotContains(t, byName, "sdk")
+	assert.NotContains(t, byName, "uses-material-design")
+
+	assert.Equal(t, "prod", byName["http"].Type)
+	assert.Equal(t, "^", byName["http"].Constraint)
+	assert.Equal(t, "1.1.0", byName["http"].Version)
+	assert.Equal(t, "1.2.0", byName["http"].InstalledVersion)
+	assert.Equal(t, InstallStatusLockFound, byName["http"].InstallStatus)
+	assert.Equal(t, "6.1.1", byName["provider"].InstalledVersion)
+	assert.Equal(t, "dev", byName["mocktail"].Type)
+	assert.Equal(t, "1.0.3", byName["mocktail"].InstalledVersion)
+
+	assert.Equal(t, "sdk", byName["flutter"].NonRegistrySource)
+	assert.Equal(t, "sdk", byName["flutter_test"].NonRegistrySource)
+	assert.Equal(t, "git", byName["analytics_client"].NonRegistrySource)
+	assert.Equal(t, "path", byName["shared_theme"].NonRegistrySource)
+	for _, name := range []string{"flutter", "flutter_test", "analytics_client", "shared_theme"} {
+		assert.Equal(t, InstallStatusNonRegistry, byName[name].InstallStatus, name)
+	}
+}
+
+// TestIntegration_Swiftpm tests the behavior of Swift Package Manager resolution with real testdata.
+//
+// It verifies:
+//   - .package(url:) entries are parsed with the repository URL as the package name, including multi-line entries
+//   - from:, .upToNextMajor, .upToNextMinor, and exact: map to ^, ^, ~, and exact constraints
+//   - Path dependencies are not parsed as packages
+//   - Installed versions are read from Package.resolved pins
+//   - branch:, revision:, and version range dependencies are marked NonRegistry
+func TestIntegration_Swiftpm(t *testing.T) {
+	testdataDir, err := filepath.Abs("../testdata/swiftpm")
+	require.NoError(t, err, "failed to get absolute path to testdata")
+
+	cfg, err := config.LoadConfig("", testdataDir)
+	require.NoError(t, err)
+
+	parser := packages.NewDynamicParser()
+	rule := cfg.Rules["swiftpm"]
+	result, err := parser.ParseFile(filepath.Join(testdataDir, "Package.swift"), &rule)
+	require.NoError(t, err)
+
+	for i := range result.Packages {
+		result.Packages[i].Rule = "swiftpm"
+	}
+
+	enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+	require.NoError(t, err)
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+	}
+	require.Len(t, byName, 7)
+	assert.NotContains(t, byName, "../SharedModels")
+
+	nio := byName["https://github.com/apple/swift-nio.git"]
+	assert.Equal(t, "swift", nio.PackageType)
+	assert.Equal(t, "prod", nio.Type)
+	assert.Equal(t, "^", nio.Constraint)
+	assert.Equal(t, "2.62.0", nio.Version)
+	assert.Equal(t, "2.62.0", nio.InstalledVersion)
+	assert.Equal(t, InstallStatusLockFound, nio.InstallStatus)
+
+	swiftLog := byName["https://github.com/apple/swift-log.git"]
+	assert.Equal(t, "~", swiftLog.Constraint)
+	assert.Equal(t, "1.5.3", swiftLog.Version)
+	assert.Equal(t, "1.5.4", swiftLog.InstalledVersion)
+
+	parserPkg := byName["https://github.com/apple/swift-argument-parser"]
+	assert.Empty(t, parserPkg.Constraint)
+	assert.Equal(t, "1.3.0", parserPkg.InstalledVersion)
+
+	alamofire := byName["https://github.com/Alamofire/Alamofire.git"]
+	assert.Equal(t, "^", alamofire.Constraint)
+	assert.Equal(t, "5.8.0", alamofire.Version)
+	assert.Equal(t, "5.8.1", alamofire.InstalledVersion)
+
+	assert.Equal(t, "..<", byName["https://github.com/apple/swift-algorithms"].NonRegistrySource)
+	assert.Equal(t, "branch", byName["https://github.com/apple/swift-collections.git"].NonRegistrySource)
+	assert.Equal(t, "revision", byName["https://github.com/SnapKit/SnapKit.git"].NonRegistrySource)
+	for _, name := range []string{
+		"https://github.com/apple/swift-algorithms",
+		"https://github.com/apple/swift-collections.git",
+		"https://github.com/SnapKit/SnapKit.git",
+	} {
+		assert.Equal(t, InstallStatusNonRegistry, byName[name].InstallStatus, name)
+	}
+}
+
+// TestIntegration_GitHubActions tests the behavior of GitHub Actions workflow pins with real testdata.
+//
+// It verifies:
+//   - uses: entries are parsed with owner/repo as the package name, including quoted and sub-path actions
+//   - Local (./) and docker:// actions are not parsed as packages
+//   - Tag pins and commented SHA pins are self-pinned to their version
+//   - A SHA pin without a version comment has no comparable version
+func TestIntegration_GitHubActions(t *testing.T) {
+	testdataDir, err := filepath.Abs("../testdata/github_actions")
+	require.NoError(t, err, "failed to get absolute path to testdata")
+
+	cfg, err := config.LoadConfig("", testdataDir)
+	require.NoError(t, err)
+
+	parser := packages.NewDynamicParser()
+	rule := cfg.Rules["github-actions"]
+	result, err := parser.ParseFile(filepath.Join(testdataDir, ".github", "workflows", "ci.yml"), &rule)
+	require.NoError(t, err)
+
+	for i := range result.Packages {
+		result.Packages[i].Rule = "github-actions"
+	}
+
+	enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+	require.NoError(t, err)
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+		assert.NotContains(t, pkg.Name, "./", "local actions must not be parsed")
+		assert.NotContains(t, pkg.Name, "docker:", "docker actions must not be parsed")
+	}
+	require.Len(t, byName, 6)
+
+	assert.Equal(t, "prod", byName["actions/checkout"].Type)
+	assert.Equal(t, "v4", byName["actions/checkout"].Version)
+	assert.Equal(t, "v4", byName["actions/checkout"].InstalledVersion)
+	assert.Equal(t, InstallStatusSelfPinned, byName["actions/checkout"].InstallStatus)
+	assert.Equal(t, "v5.0.0", byName["actions/setup-go"].InstalledVersion)
+	assert.Equal(t, "v6.1.0", byName["golangci/golangci-lint-action"].InstalledVersion)
+	assert.Equal(t, "v3.25.0", byName["github/codeql-action"].InstalledVersion)
+	assert.Equal(t, "v4.0.2", byName["actions/cache"].InstalledVersion)
+
+	// No version comment: the empty version becomes "*" and cannot be compared
+	assert.Equal(t, "*", byName["actions/upload-artifact"].Version)
+	assert.Equal(t, InstallStatusFloating, byName["actions/upload-artifact"].InstallStatus)
+}
+
+// TestIntegration_Gradle tests the behavior of Gradle build script and version catalog resolution with real testdata.
+//
+// It verifies:
+//   - String notation coordinates are parsed from build.gradle and build.gradle.kts as group:artifact
+//   - Interpolated and dynamic versions, project and file dependencies are not parsed as packages
+//   - Test configurations are dev; other configurations (including debugImplementation) are prod
+//   - Catalog libraries resolve version.ref through the [versions] table
+//   - The group/name catalog notation is not parsed
+//   - Declared versions are self-pinned as the installed version
+func TestIntegration_Gradle(t *testing.T) {
+	testdataDir, err := filepath.Abs("../testdata/gradle")
+	require.NoError(t, err, "failed to get absolute path to testdata")
+
+	cfg, err := config.LoadConfig("", testdataDir)
+	require.NoError(t, err)
+
+	parser := packages.NewDynamicParser()
+	rule := cfg.Rules["gradle"]
+
+	var parsed []formats.Package
+	for _, file := range []string{"build.gradle", filepath.Join("app", "build.gradle.kts"), filepath.Join("gradle", "libs.versions.toml")} {
+		result, err := parser.ParseFile(filepath.Join(testdataDir, file), &rule)
+		require.NoError(t, err, file)
+		parsed = append(parsed, result.Packages...)
+	}
+
+	for i := range parsed {
+		parsed[i].Rule = "gradle"
+	}
+
+	enriched, err := ApplyInstalledVersions(parsed, cfg, testdataDir)
+	require.NoError(t, err)
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+	}
+	require.Len(t, byName, 17)
+	assert.NotContains(t, byName, "org.jetbrains.kotlin:kotlin-reflect")
+	assert.NotContains(t, byName, "org.slf4j:slf4j-api")
+	assert.NotContains(t, byName, "com.squareup.retrofit2:retrofit")
+
+	guava := byName["com.google.guava:guava"]
+	assert.Equal(t, "java", guava.PackageType)
+	assert.Equal(t, "prod", guava.Type)
+	assert.Equal(t, "32.1.3-jre", guava.InstalledVersion)
+	assert.Equal(t, InstallStatusSelfPinned, guava.InstallStatus)
+
+	assert.Equal(t, "dev", byName["junit:junit"].Type)
+	assert.Equal(t, "dev", byName["androidx.test.espresso:espresso-core"].Type)
+	assert.Equal(t, "prod", byName["com.squareup.leakcanary:leakcanary-android"].Type)
+	assert.Equal(t, "1.11.0", byName["com.google.android.material:material"].Version)
+	assert.Equal(t, "4.12.0", byName["com.squareup.okhttp3:okhttp-bom"].Version)
+
+	assert.Equal(t, "4.12.0", byName["com.squareup.okhttp3:okhttp"].InstalledVersion)
+	assert.Equal(t, "4.12.0", byName["com.squareup.okhttp3:logging-interceptor"].InstalledVersion)
+	assert.Equal(t, "1.9.22", byName["org.jetbrains.kotlin:kotlin-stdlib"].InstalledVersion)
+	assert.Equal(t, "1.7.3", byName["org.jetbrains.kotlinx:kotlinx-coroutines-core"].InstalledVersion)
+	assert.Equal(t, "5.0.1", byName["com.jakewharton.timber:timber"].InstalledVersion)
+}
+
+// TestIntegration_Deno tests the behavior of deno.json import map resolution with real testdata.
+//
+// It verifies:
+//   - npm: and jsr: specifiers are parsed with their registry prefix and constraint
+//   - Versioned URL imports are parsed as the URL up to the version
+//   - Task commands and the $schema URL are not parsed as packages
+//   - Installed versions resolve from deno.lock version 4 and version 3
+//   - URL imports without a version are reported as non-registry sources
+func TestIntegration_Deno(t *testing.T) {
+	resolve := func(t *testing.T, dir string) map[string]formats.Package {
+		testdataDir, err := filepath.Abs(filepath.Join("../testdata", dir))
+		require.NoError(t, err, "failed to get absolute path to testdata")
+
+		cfg, err := config.LoadConfig("", testdataDir)
+		require.NoError(t, err)
+
+		rule := cfg.Rules["deno"]
+		result, err := packages.NewDynamicParser().ParseFile(filepath.Join(testdataDir, "deno.json"), &rule)
+		require.NoError(t, err)
+		for i := range result.Packages {
+			result.Packages[i].Rule = "deno"
+		}
+
+		enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+		require.NoError(t, err)
+
+		byName := make(map[string]formats.Package)
+		for _, pkg := range enriched {
+			byName[pkg.Name] = pkg
+		}
+		return byName
+	}
+
+	t.Run("lock version 4", func(t *testing.T) {
+		byName := resolve(t, "deno")
+		require.Len(t, byName, 10)
+		assert.NotContains(t, byName, "npm:depcheck")
+
+		preact := byName["npm:preact"]
+		assert.Equal(t, "deno", preact.PackageType)
+		assert.Equal(t, "^", preact.Constraint)
+		assert.Equal(t, "10.24.3", preact.Version)
+		assert.Equal(t, "10.25.0", preact.InstalledVersion)
+		assert.Equal(t, InstallStatusLockFound, preact.InstallStatus)
+
+		assert.Equal(t, "~", byName["jsr:@std/path"].Constraint)
+		assert.Equal(t, "1.0.8", byName["jsr:@std/path"].InstalledVersion)
+		assert.Equal(t, "1.0.8", byName["jsr:@std/assert"].InstalledVersion)
+		assert.Equal(t, "22.9.0", byName["npm:@types/node"].InstalledVersion)
+		assert.Equal(t, "v3.23.8", byName["https://deno.land/x/zod"].InstalledVersion)
+		assert.Equal(t, "0.224.0", byName["https://deno.land/std"].InstalledVersion)
+		assert.Equal(t, "1.3.0", byName["https://esm.sh/@preact/signals"].InstalledVersion)
+
+		lodash := byName["https://cdn.skypack.dev/lodash-es"]
+		assert.Equal(t, "https", lodash.NonRegistrySource)
+		assert.Equal(t, InstallStatusNonRegistry, lodash.InstallStatus)
+	})
+
+	t.Run("lock version 3", func(t *testing.T) {
+		byName := resolve(t, "deno_v3")
+		require.Len(t, byName, 3)
+		assert.Equal(t, "0.221.0", byName["jsr:@std/assert"].InstalledVersion)
+		assert.Equal(t, "5.3.0", byName["npm:chalk"].InstalledVersion)
+		assert.Equal(t, "v12.6.1", byName["https://deno.land/x/oak"].InstalledVersion)
+	})
 }
 
 // TestIntegration_MSBuild tests the behavior of MSBuild/csproj package resolution with real testdata.
@@ -440,6 +1082,71 @@ func TestIntegration_MSBuild(t *testing.T) {
 	assert.Equal(t, InstallStatusLockFound, statusLookup["Microsoft.EntityFrameworkCore"])
 }
 
+// TestIntegration_MSBuildCentralPackageManagement tests central package management with real testdata.
+//
+// It verifies:
+//   - Directory.Packages.props is detected by the msbuild rule
+//   - PackageVersion entries are parsed, with GlobalPackageReference entries as dev
+//   - Versionless project references are not parsed twice; VersionOverride is read from the project
+//   - Central entries are attributed to the projects referencing them, global references to every project
+//   - A VersionOverride that differs from the central version is reported from the project,
+//     which does not count as a user of the central entry
+//   - Installed versions are resolved from project lock files below the props file
+func TestIntegration_MSBuildCentralPackageManagement(t *testing.T) {
+	testdataDir, err := filepath.Abs("../testdata/msbuild_cpm")
+	require.NoError(t, err, "failed to get absolute path to testdata")
+
+	cfg, err := config.LoadConfig("", testdataDir)
+	require.NoError(t, err)
+
+	parser := packages.NewDynamicParser()
+	rule := cfg.Rules["msbuild"]
+
+	files, err := packages.DetectFiles(cfg, testdataDir)
+	require.NoError(t, err)
+	assert.Contains(t, files["msbuild"], filepath.Join(testdataDir, "Directory.Packages.props"))
+
+	result, err := parser.ParseFile(filepath.Join(testdataDir, "Directory.Packages.props"), &rule)
+	require.NoError(t, err)
+	require.Len(t, result.Packages, 6)
+
+	for i := range result.Packages {
+		result.Packages[i].Rule = "msbuild"
+	}
+
+	enriched, err := ApplyInstalledVersions(result.Packages, cfg, testdataDir)
+	require.NoError(t, err)
+
+	byName := make(map[string]formats.Package)
+	for _, pkg := range enriched {
+		byName[pkg.Name] = pkg
+	}
+
+	assert.Equal(t, "8.0.0", byName["Microsoft.Extensions.Hosting"].Version)
+	assert.Equal(t, "8.0.0", byName["Microsoft.Extensions.Hosting"].InstalledVersion)
+	assert.Equal(t, InstallStatusLockFound, byName["Microsoft.Extensions.Hosting"].InstallStatus)
+	assert.Equal(t, "8.0.1", byName["Serilog.AspNetCore"].InstalledVersion)
+	assert.Equal(t, "prod", byName["xunit"].Type)
+	assert.Equal(t, "dev", byName["StyleCop.Analyzers"].Type)
+
+	assert.Equal(t, []string{"src/Api/Api.csproj"}, byName["Microsoft.Extensions.Hosting"].UsedBy)
+	assert.Equal(t, []string{"tests/Api.Tests/Api.Tests.csproj"}, byName["xunit"].UsedBy)
+	assert.Equal(t, []string{"src/Api/Api.csproj", "tests/Api.Tests/Api.Tests.csproj"}, byName["StyleCop.Analyzers"].UsedBy)
+	assert.Empty(t, byName["Swashbuckle.AspNetCore"].UsedBy)
+
+	api, err := parser.ParseFile(filepath.Join(testdataDir, "src", "Api", "Api.csproj"), &rule)
+	require.NoError(t, err)
+	require.Len(t, api.Packages, 1)
+	assert.Equal(t, "Swashbuckle.AspNetCore", api.Packages[0].Name)
+	assert.Equal(t, "6.4.0", api.Packages[0].Version)
+	assert.Equal(t, "6.5.0", byName["Swashbuckle.AspNetCore"].Version)
+	assert.NotEqual(t, byName["Swashbuckle.AspNetCore"].Version, api.Packages[0].Version, "VersionOverride must not be replaced by the central version")
+
+	tests, err := parser.ParseFile(filepath.Join(testdataDir, "tests", "Api.Tests", "Api.Tests.csproj"), &rule)
+	require.NoError(t, err)
+	assert.Empty(t, tests.Packages)
+}
+
 // TestIntegration_NuGet tests the behavior of NuGet packages.config resolution with real testdata.
 //
 // It verifies:
@@ -229,6 +229,52 @@ func TestApplyInstalledVersionsUsesPackageScope(t *testing.T) {
 	assert.Equal(t, "#N/A", enriched[1].InstalledVersion)
 }
 
+// TestApplyInstalledVersionsUsesWorkspaceRoot tests lock resolution for workspace members.
+//
+// It verifies:
+//   - Members of a workspace rule read the lock file of the workspace root
+//   - Each member keeps its own manifest as Source
+//   - Without workspace, members without a lock file next to them have LockMissing status
+func TestApplyInstalledVersionsUsesWorkspaceRoot(t *testing.T) {
+	root := t.TempDir()
+	for _, member := range []string{"app", "lib"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "packages", member), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package-lock.json"), []byte(`{"react":{"version":"18.2.0"},"lodash":{"version":"4.17.21"}}`), 0o644))
+
+	workspace, noWorkspace := true, false
+	ruleCfg := config.PackageManagerCfg{
+		Workspace: &workspace,
+		LockFiles: []config.LockFileCfg{{
+			Files:      []string{"**/package-lock.json"},
+			Extraction: &config.ExtractionCfg{Pattern: `(?s)"(?P<n>[^"}]+)"\s*:\s*\{[^}]*"version"\s*:\s*"(?P<version>[^"]+)"`},
+		}},
+	}
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{"npm": ruleCfg}}
+
+	appManifest := filepath.Join(root, "packages", "app", "package.json")
+	libManifest := filepath.Join(root, "packages", "lib", "package.json")
+	pkgs := []formats.Package{
+		{Name: "react", Rule: "npm", Source: appManifest},
+		{Name: "lodash", Rule: "npm", Source: libManifest},
+	}
+
+	enriched, err := ApplyInstalledVersions(pkgs, cfg, root)
+	require.NoError(t, err)
+	assert.Equal(t, InstallStatusLockFound, enriched[0].InstallStatus)
+	assert.Equal(t, "18.2.0", enriched[0].InstalledVersion)
+	assert.Equal(t, appManifest, enriched[0].Source)
+	assert.Equal(t, InstallStatusLockFound, enriched[1].InstallStatus)
+	assert.Equal(t, "4.17.21", enriched[1].InstalledVersion)
+	assert.Equal(t, libManifest, enriched[1].Source)
+
+	ruleCfg.Workspace = &noWorkspace
+	cfg.Rules["npm"] = ruleCfg
+	enriched, err = ApplyInstalledVersions([]formats.Package{{Name: "react", Rule: "npm", Source: appManifest}}, cfg, root)
+	require.NoError(t, err)
+	assert.Equal(t, InstallStatusLockMissing, enriched[0].InstallStatus)
+}
+
 // TestApplyInstalledVersionsUsesWorkingDirFallback tests the behavior of working directory fallback.
 //
 // It verifies:
@@ -403,6 +449,29 @@ func TestResolveInstalledVersionsFromCustomLockFile(t *testing.T) {
 	assert.Equal(t, "9.9.9", resolved["lib"])
 }
 
+// TestResolveInstalledVersionsLockToolMissing tests lock files whose command tool is not installed.
+//
+// It verifies:
+//   - A lock command whose tool is missing fails with ErrLockToolMissing
+//   - Another lock file of the rule that can be read is used instead
+func TestResolveInstalledVersionsLockToolMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.lockb"), []byte{0x00, 0x01}, 0o644))
+	binary := config.LockFileCfg{Files: []string{"app.lockb"}, Commands: "goupdate-missing-lock-tool ls", TimeoutSeconds: 10}
+
+	_, found, err := resolveInstalledVersions(tmpDir, []config.LockFileCfg{binary})
+	assert.True(t, found)
+	assert.ErrorIs(t, err, ErrLockToolMissing)
+	assert.ErrorContains(t, err, "app.lockb")
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.lock"), []byte("tool 1.2.3"), 0o644))
+	text := config.LockFileCfg{Files: []string{"app.lock"}, Extraction: &config.ExtractionCfg{Pattern: `(?m)^(?P<n>\w+)\s+(?P<version>[\d\.]+)`}}
+	resolved, found, err := resolveInstalledVersions(tmpDir, []config.LockFileCfg{text, binary})
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "1.2.3", resolved["tool"])
+}
+
 // TestResolveInstalledVersionsHandlesEmptyAndMissingFiles tests the behavior with empty and missing files.
 //
 // It verifies:
@@ -1151,3 +1220,28 @@ func TestExtractVersionsFromCommand(t *testing.T) {
 
 // Silence unused import warnings
 var _ = utils.FindFilesByPatterns
+
+// TestLookupInstalledVersion tests the behavior of lookupInstalledVersion.
+//
+// It verifies:
+//   - Exact name matches are preferred
+//   - Names are matched case-insensitively as a fallback
+//   - Missing names return false
+func TestLookupInstalledVersion(t *testing.T) {
+	installed := map[string]string{
+		"django":   "4.2.8",
+		"Requests": "2.31.0",
+		"requests": "2.30.0",
+	}
+
+	version, ok := lookupInstalledVersion(installed, "requests")
+	assert.True(t, ok)
+	assert.Equal(t, "2.30.0", version)
+
+	version, ok = lookupInstalledVersion(installed, "Django")
+	assert.True(t, ok)
+	assert.Equal(t, "4.2.8", version)
+
+	_, ok = lookupInstalledVersion(installed, "flask")
+	assert.False(t, ok)
+}
@@ -16,6 +16,7 @@ package lock
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -33,13 +34,20 @@ var (
 	extractVersionsFromFn = extractVersionsFromLock
 )
 
+// ErrLockToolMissing is returned when a lock file's command cannot run because its tool is not installed.
+//
+// ApplyInstalledVersions does not fail on it: packages of the affected scope get
+// InstallStatusLockUnavailable and are tracked as unsupported.
+var ErrLockToolMissing = errors.New("lock command tool not installed")
+
 // ApplyInstalledVersions enriches packages with installed version and status information
 // based on lock file configuration.
 //
 // It performs the following operations:
-//   - Groups packages by rule and scope directory
+//   - Groups packages by rule and scope directory (the workspace root for workspace rules)
 //   - Resolves installed versions from lock files for each scope
 //   - Sets InstalledVersion and InstallStatus fields for each package
+//   - Marks packages LockUnavailable when their lock file needs a tool that is not installed
 //   - Handles self-pinning rules where manifest is the lock file
 //   - Marks floating constraints that cannot be updated automatically
 //
@@ -116,6 +124,12 @@ func ApplyInstalledVersions(packages []formats.Package, cfg *config.Config, base
 			if scopeDir == "" {
 				scopeDir = "."
 			}
+			// Workspace members read the lock file of the workspace root
+			rootDir := baseDir
+			if rootDir == "" {
+				rootDir = cfg.WorkingDir
+			}
+			scopeDir = config.LockScopeDir(ruleCfg, scopeDir, rootDir)
 
 			scopes[scopeKey{rule: ruleKey, dir: scopeDir}] = append(scopes[scopeKey{rule: ruleKey, dir: scopeDir}], idx)
 		}
@@ -128,6 +142,14 @@ func ApplyInstalledVersions(packages []formats.Package, cfg *config.Config, base
 		}
 
 		installed, foundLock, err := resolveInstalledVersions(key.dir, ruleCfg.LockFiles)
+		if errors.Is(err, ErrLockToolMissing) {
+			verbose.Printf("Lock resolution: %s lock in %q is unreadable: %v\n", key.rule, key.dir, err)
+			for _, idx := range indexes {
+				packages[idx].InstalledVersion = "#N/A"
+				packages[idx].InstallStatus = InstallStatusLockUnavailable
+			}
+			continue
+		}
 		if err != nil {
 			verbose.Printf("Lock resolution ERROR: failed to resolve lock files for %s: %v\n", key.rule, err)
 			return nil, fmt.Errorf("failed to resolve lock files for %s: %w", key.rule, err)
@@ -146,7 +168,7 @@ func ApplyInstalledVersions(packages []formats.Package, cfg *config.Config, base
 
 		for _, idx := range indexes {
 			name := packages[idx].Name
-			if version, ok := installed[name]; ok && version != "" {
+			if version, ok := lookupInstalledVersion(installed, name); ok && version != "" {
 				packages[idx].InstalledVersion = version
 				packages[idx].InstallStatus = InstallStatusLockFound
 				continue
@@ -175,6 +197,15 @@ func ApplyInstalledVersions(packages []formats.Package, cfg *config.Config, base
 		}
 	}
 
+	// Mark packages installed from non-registry sources (git, path, etc.)
+	// These have no registry versions to compare against and must be updated at their source.
+	for idx := range packages {
+		if packages[idx].NonRegistrySource != "" {
+			packages[idx].InstallStatus = InstallStatusNonRegistry
+			verbose.Printf("NonRegistry: %s is installed from a %s source - manual update required", packages[idx].Name, packages[idx].NonRegistrySource)
+		}
+	}
+
 	// Mark packages with IgnoreReason as Ignored
 	// This takes precedence over other statuses as ignored packages should not be updated
 	for idx := range packages {
@@ -187,6 +218,33 @@ func ApplyInstalledVersions(packages []formats.Package, cfg *config.Config, base
 	return packages, nil
 }
 
+// lookupInstalledVersion finds the installed version for a package name.
+//
+// An exact match is preferred. Otherwise the name is matched case-insensitively,
+// since some lock files store canonical lowercase names (e.g., poetry.lock records
+// "django" for a "Django" dependency).
+//
+// Parameters:
+//   - installed: Map of package names to installed versions from lock files
+//   - name: The declared package name
+//
+// Returns:
+//   - string: The installed version
+//   - bool: true if the package was found
+func lookupInstalledVersion(installed map[string]string, name string) (string, bool) {
+	if version, ok := installed[name]; ok {
+		return version, true
+	}
+
+	for lockName, version := range installed {
+		if strings.EqualFold(lockName, name) {
+			return version, true
+		}
+	}
+
+	return "", false
+}
+
 // issueLatestWarning checks if a package uses a latest indicator without a lock file
 // and tracks warning deduplication.
 //
@@ -227,6 +285,8 @@ func issueLatestWarning(pkg formats.Package, ruleCfg config.PackageManagerCfg, s
 //   - Extracts package name-version mappings from each found lock file
 //   - Aggregates results across multiple lock files
 //   - Tracks whether any lock files were found
+//   - Skips lock files whose command tool is not installed, failing with
+//     ErrLockToolMissing only when no other lock file could be read
 //
 // Parameters:
 //   - baseDir: Base directory to search for lock files
@@ -235,10 +295,13 @@ func issueLatestWarning(pkg formats.Package, ruleCfg config.PackageManagerCfg, s
 // Returns:
 //   - map[string]string: Map of package names to installed versions
 //   - bool: True if any lock files were found, false otherwise
-//   - error: When file search or version extraction fails, returns error; otherwise returns nil
+//   - error: When file search or version extraction fails, returns error; ErrLockToolMissing
+//     when every found lock file needs a tool that is not installed; otherwise returns nil
 func resolveInstalledVersions(baseDir string, lockCfgs []config.LockFileCfg) (map[string]string, bool, error) {
 	installed := make(map[string]string)
 	foundAny := false
+	read := false
+	var toolMissing error
 
 	for _, lockCfg := range lockCfgs {
 		if len(lockCfg.Files) == 0 {
@@ -258,11 +321,17 @@ func resolveInstalledVersions(baseDir string, lockCfgs []config.LockFileCfg) (ma
 		foundAny = true
 		for _, file := range files {
 			matches, err := extractVersionsFromFn(file, &lockCfg)
+			if errors.Is(err, ErrLockToolMissing) {
+				verbose.Printf("Lock resolution: skipping %s: %v\n", file, err)
+				toolMissing = fmt.Errorf("%s: %w", filepath.Base(file), err)
+				continue
+			}
 			if err != nil {
 				verbose.Printf("Lock resolution ERROR: failed to extract versions from %s: %v\n", file, err)
 				return nil, false, fmt.Errorf("failed to extract versions from %s: %w", file, err)
 			}
 
+			read = true
 			verbose.Printf("Lock extraction: %s → %d packages", filepath.Base(file), len(matches))
 
 			for name, version := range matches {
@@ -274,6 +343,10 @@ func resolveInstalledVersions(baseDir string, lockCfgs []config.LockFileCfg) (ma
 		}
 	}
 
+	if !read && toolMissing != nil {
+		return nil, true, toolMissing
+	}
+
 	return installed, foundAny, nil
 }
 
@@ -365,6 +438,7 @@ func extractVersionsFromLock(path string, cfg *config.LockFileCfg) (map[string]s
 //
 // If a command exits with non-zero status but produces valid output (common with
 // npm ls when packages are missing), the output is still parsed successfully.
+// A command whose tool is not installed fails with ErrLockToolMissing.
 //
 // Parameters:
 //   - path: Absolute or relative path to the lock file
@@ -396,6 +470,9 @@ func extractVersionsFromCommand(path string, cfg *config.LockFileCfg) (map[strin
 	}
 
 	// If we couldn't parse output and command failed, return the command error
+	if cmdexec.IsCommandNotFound(cmdErr) {
+		return nil, fmt.Errorf("%w: %w", ErrLockToolMissing, cmdErr)
+	}
 	if cmdErr != nil {
 		return nil, fmt.Errorf("lock file command failed: %w", cmdErr)
 	}
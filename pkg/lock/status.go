@@ -13,8 +13,16 @@ const (
 	// that cannot be updated automatically. Users must either remove the floating constraint
 	// or handle updates manually.
 	InstallStatusFloating = "Floating"
+	// InstallStatusNonRegistry indicates the package is installed from a non-registry source
+	// (e.g., a Gemfile gem with a git: or path: option). Such packages have no registry versions
+	// to compare against and must be updated manually at their source.
+	InstallStatusNonRegistry = "NonRegistry"
 	// InstallStatusIgnored indicates the package is excluded from processing based on
 	// configuration (ignore patterns or package_overrides.ignore = true).
 	// The package is still reported for visibility, but no updates will be performed.
 	InstallStatusIgnored = "Ignored"
+	// InstallStatusLockUnavailable indicates a lock file exists but cannot be read because
+	// the tool its lock command needs is not installed (e.g., bun for a binary bun.lockb).
+	// Installed versions are unknown, so the package is reported as unsupported.
+	InstallStatusLockUnavailable = "LockUnavailable"
 )
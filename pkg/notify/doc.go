@@ -0,0 +1,23 @@
+// Package notify delivers a summary of an update run to a webhook.
+//
+// It is used by `goupdate update` when the config file has a notify block,
+// so scheduled update jobs can report to a chat channel or an HTTP endpoint
+// without wrapping the CLI in a script.
+//
+// # Core Types
+//
+// Summary is the payload built from the final update results and the
+// unsupported tracker:
+//
+//	summary := notify.BuildSummary(results, unsupported, dryRun)
+//	if err := notify.Send(ctx, cfg.Notify, summary); err != nil {
+//	    // report the failure; it never changes the exit code
+//	}
+//
+// # Redaction
+//
+// Webhook URLs often embed their secret (Slack incoming webhooks do), so
+// errors and diagnostics only ever show RedactURL(url): the scheme and host,
+// with the path, query, and credentials replaced. Header values are never
+// printed.
+package notify
@@ -0,0 +1,268 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/supervision"
+	"github.com/ajxudir/goupdate/pkg/update"
+)
+
+// DefaultTimeout bounds delivery when notify.timeout_seconds is not set.
+const DefaultTimeout = 10 * time.Second
+
+// redactedPlaceholder replaces secrets in diagnostics.
+const redactedPlaceholder = "***"
+
+// maxErrorBodySize caps how much of an error response is read.
+const maxErrorBodySize = 512
+
+// Summary is the notification payload for a finished update run.
+//
+// Fields:
+//   - DryRun: Whether the run only planned updates
+//   - Total: Number of packages processed
+//   - Updated: Packages updated (or planned, for dry runs)
+//   - Failed: Packages whose update failed
+//   - Unsupported: Packages that cannot be updated automatically
+//   - Changes: The updated (or planned) packages
+//   - FailedPackages: Names of packages whose update failed
+//   - UnsupportedReasons: Why packages are unsupported, one entry per rule and reason
+type Summary struct {
+	DryRun             bool     `json:"dry_run"`
+	Total              int      `json:"total"`
+	Updated            int      `json:"updated"`
+	Failed             int      `json:"failed"`
+	Unsupported        int      `json:"unsupported"`
+	Changes            []Change `json:"changes"`
+	FailedPackages     []string `json:"failed_packages,omitempty"`
+	UnsupportedReasons []string `json:"unsupported_reasons,omitempty"`
+}
+
+// Change describes one changed package.
+//
+// Fields:
+//   - Name: Package name
+//   - Rule: Rule that owns the package
+//   - From: Version before the update
+//   - To: Target version
+type Change struct {
+	Name string `json:"name"`
+	Rule string `json:"rule"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// BuildSummary builds the notification payload from the final update results.
+//
+// Packages count as unsupported when their status is tracked as unsupported
+// (floating, non-registry, not configured, version missing) or their error is
+// an UnsupportedError. The tracker contributes the reasons.
+//
+// Parameters:
+//   - results: Update results in display order
+//   - unsupported: Tracker of unsupported packages; may be nil
+//   - dryRun: Whether the run was a dry run
+//
+// Returns:
+//   - Summary: Counts of updated, failed, and unsupported packages plus the changed packages
+func BuildSummary(results []update.UpdateResult, unsupported *supervision.UnsupportedTracker, dryRun bool) Summary {
+	summary := Summary{DryRun: dryRun, Total: len(results), Changes: []Change{}}
+
+	for _, res := range results {
+		switch {
+		case update.ShouldTrackUnsupported(res.Status) || (res.Err != nil && errors.IsUnsupported(res.Err)):
+			summary.Unsupported++
+		case res.Status == constants.StatusUpdated || res.Status == constants.StatusPlanned:
+			summary.Updated++
+			summary.Changes = append(summary.Changes, Change{
+				Name: res.Pkg.Name,
+				Rule: res.Pkg.Rule,
+				From: previousVersion(res),
+				To:   res.Target,
+			})
+		case res.Status == constants.StatusFailed:
+			summary.Failed++
+			summary.FailedPackages = append(summary.FailedPackages, res.Pkg.Name)
+		}
+	}
+
+	if unsupported != nil {
+		for _, info := range unsupported.GroupedMessages() {
+			summary.UnsupportedReasons = append(summary.UnsupportedReasons, fmt.Sprintf("%s (%s): %s", info.Rule, info.PackageType, info.Reason))
+		}
+	}
+
+	return summary
+}
+
+// previousVersion returns the version a package had before the update.
+//
+// Parameters:
+//   - res: The update result
+//
+// Returns:
+//   - string: The original installed version, falling back to the declared version
+func previousVersion(res update.UpdateResult) string {
+	for _, v := range []string{res.OriginalInstalled, res.OriginalVersion, res.Pkg.InstalledVersion, res.Pkg.Version} {
+		if v = strings.TrimSpace(v); v != "" && v != constants.PlaceholderNA {
+			return v
+		}
+	}
+	return ""
+}
+
+// Send posts the summary to the configured webhook.
+//
+// The URL and header values are expanded from the environment. Any error
+// mentions the webhook only through RedactURL, so secrets embedded in the
+// URL never reach logs.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - cfg: The notify configuration
+//   - summary: The payload to deliver
+//
+// Returns:
+//   - error: When the request fails or the endpoint answers with a non-2xx status
+func Send(ctx context.Context, cfg *config.NotifyCfg, summary Summary) error {
+	if cfg == nil {
+		return nil
+	}
+
+	endpoint := strings.TrimSpace(os.ExpandEnv(cfg.URL))
+	if endpoint == "" {
+		return fmt.Errorf("notify.url is empty")
+	}
+	redacted := RedactURL(endpoint)
+
+	body, err := Payload(cfg.GetFormat(), summary)
+	if err != nil {
+		return err
+	}
+
+	timeout := DefaultTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid notification url %s", redacted)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range cfg.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// url.Error repeats the full URL; keep only the underlying cause
+		var urlErr *url.Error
+		if stderrors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send notification to %s: %w", redacted, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("notification to %s failed: %s %s", redacted, resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	return nil
+}
+
+// Payload encodes the summary in the requested format.
+//
+// Parameters:
+//   - format: config.NotifyFormatJSON or config.NotifyFormatSlack
+//   - summary: The run summary
+//
+// Returns:
+//   - []byte: JSON request body
+//   - error: When the format is unknown
+func Payload(format string, summary Summary) ([]byte, error) {
+	switch format {
+	case config.NotifyFormatJSON, "":
+		return json.Marshal(summary)
+	case config.NotifyFormatSlack:
+		return json.Marshal(struct {
+			Text string `json:"text"`
+		}{Text: SlackText(summary)})
+	default:
+		return nil, fmt.Errorf("unknown notify format %q", format)
+	}
+}
+
+// SlackText renders the summary as a Slack message.
+//
+// Parameters:
+//   - summary: The run summary
+//
+// Returns:
+//   - string: A headline with the counts followed by one bullet per changed package
+//
+// Example output:
+//
+//	*goupdate*: 2 updated, 1 failed, 0 unsupported
+//	• `react` 18.2.0 → 18.3.1 (npm)
+func SlackText(summary Summary) string {
+	verb := "updated"
+	if summary.DryRun {
+		verb = "planned"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*goupdate*: %d %s, %d failed, %d unsupported", summary.Updated, verb, summary.Failed, summary.Unsupported)
+	if summary.DryRun {
+		sb.WriteString(" (dry run)")
+	}
+	for _, change := range summary.Changes {
+		fmt.Fprintf(&sb, "\n• `%s` %s → %s (%s)", change.Name, change.From, change.To, change.Rule)
+	}
+	if len(summary.FailedPackages) > 0 {
+		fmt.Fprintf(&sb, "\nFailed: %s", strings.Join(summary.FailedPackages, ", "))
+	}
+	return sb.String()
+}
+
+// RedactURL hides the secret parts of a webhook URL for display.
+//
+// Parameters:
+//   - raw: The webhook URL
+//
+// Returns:
+//   - string: scheme://host with credentials, path, and query replaced by "***";
+//     "***" when raw cannot be parsed
+//
+// Example:
+//
+//	notify.RedactURL("https://hooks.slack.com/services/T0/B0/secret") // "https://hooks.slack.com/***"
+func RedactURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return redactedPlaceholder
+	}
+
+	redacted := parsed.Scheme + "://" + parsed.Host
+	if parsed.User != nil || (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" {
+		redacted += "/" + redactedPlaceholder
+	}
+	return redacted
+}
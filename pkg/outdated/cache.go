@@ -0,0 +1,256 @@
+package outdated
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// DefaultCacheTTL is how long cached version lookups stay fresh by default.
+const DefaultCacheTTL = time.Hour
+
+// cacheDirName is the subdirectory of the user cache directory used by goupdate.
+const cacheDirName = "goupdate"
+
+// cacheNowFunc returns the current time for cache freshness checks.
+// It is a variable so tests can move the clock.
+var cacheNowFunc = time.Now
+
+// userCacheDirFunc returns the user cache directory ($XDG_CACHE_HOME on Linux).
+// It is a variable so tests can redirect the cache.
+var userCacheDirFunc = os.UserCacheDir
+
+// VersionCache stores version lookup results on disk.
+//
+// Entries are keyed by rule, package name, and installed version, so an entry
+// stops matching as soon as the installed version changes. Each entry also
+// records a fingerprint of the package's effective outdated configuration;
+// editing the rule (commands, exclusions, versioning) invalidates it too.
+//
+// Fields:
+//   - Dir: Directory holding one JSON file per entry
+//   - TTL: How long an entry is served before the registry is queried again
+type VersionCache struct {
+	Dir string
+	TTL time.Duration
+}
+
+// versionCacheEntry is the on-disk format of a cached lookup.
+type versionCacheEntry struct {
+	Rule      string    `json:"rule"`
+	Name      string    `json:"name"`
+	Installed string    `json:"installed"`
+	Config    string    `json:"config"`
+	Versions  []string  `json:"versions"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// DefaultCacheDir returns the directory used for the version cache.
+//
+// Returns:
+//   - string: "goupdate" under the user cache directory (e.g. $XDG_CACHE_HOME/goupdate)
+//   - error: When the user cache directory cannot be determined
+func DefaultCacheDir() (string, error) {
+	base, err := userCacheDirFunc()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, cacheDirName), nil
+}
+
+// NewVersionCache creates a cache in the default cache directory.
+//
+// Parameters:
+//   - ttl: How long entries stay fresh; values <= 0 disable the cache
+//
+// Returns:
+//   - *VersionCache: The cache, or nil when caching is disabled or no cache directory is available
+func NewVersionCache(ttl time.Duration) *VersionCache {
+	if ttl <= 0 {
+		return nil
+	}
+
+	dir, err := DefaultCacheDir()
+	if err != nil {
+		verbose.Infof("Version cache disabled: %v", err)
+		return nil
+	}
+
+	return &VersionCache{Dir: dir, TTL: ttl}
+}
+
+// Get returns the cached versions for a package when a fresh entry exists.
+//
+// Missing, expired, unreadable, or corrupt entries are treated as a miss so a
+// damaged cache never fails the run.
+//
+// Parameters:
+//   - p: The package being checked
+//   - fingerprint: Fingerprint of the package's effective outdated configuration
+//
+// Returns:
+//   - []string: The cached newer versions
+//   - bool: true on a cache hit
+func (c *VersionCache) Get(p formats.Package, fingerprint string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.entryPath(p))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry versionCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		verbose.Infof("Ignoring corrupt version cache entry for %s: %v", p.Name, err)
+		return nil, false
+	}
+
+	if entry.Rule != p.Rule || entry.Name != p.Name || entry.Installed != CurrentVersionForOutdated(p) || entry.Config != fingerprint {
+		return nil, false
+	}
+
+	if age := cacheNowFunc().Sub(entry.Timestamp); age < 0 || age >= c.TTL {
+		return nil, false
+	}
+
+	return entry.Versions, true
+}
+
+// Put stores the versions found for a package.
+//
+// Write failures are logged and otherwise ignored; the cache is best effort.
+//
+// Parameters:
+//   - p: The package that was checked
+//   - fingerprint: Fingerprint of the package's effective outdated configuration
+//   - versions: The newer versions returned by the lookup
+func (c *VersionCache) Put(p formats.Package, fingerprint string, versions []string) {
+	if c == nil {
+		return
+	}
+
+	if versions == nil {
+		versions = []string{}
+	}
+
+	data, err := json.Marshal(versionCacheEntry{
+		Rule:      p.Rule,
+		Name:      p.Name,
+		Installed: CurrentVersionForOutdated(p),
+		Config:    fingerprint,
+		Versions:  versions,
+		Timestamp: cacheNowFunc(),
+	})
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		verbose.Infof("Could not create version cache directory %s: %v", c.Dir, err)
+		return
+	}
+
+	// Write to a temporary file first so concurrent lookups never read a partial entry
+	tmp, err := os.CreateTemp(c.Dir, ".entry-*")
+	if err != nil {
+		verbose.Infof("Could not write version cache entry for %s: %v", p.Name, err)
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.entryPath(p)); err != nil {
+		_ = os.Remove(tmp.Name())
+		verbose.Infof("Could not write version cache entry for %s: %v", p.Name, err)
+	}
+}
+
+// entryPath returns the file holding the entry for a package.
+//
+// Parameters:
+//   - p: The package
+//
+// Returns:
+//   - string: Path named by a hash of rule, name, and installed version
+func (c *VersionCache) entryPath(p formats.Package) string {
+	sum := sha256.Sum256([]byte(p.Rule + "\x00" + p.Name + "\x00" + CurrentVersionForOutdated(p)))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// WithCache wraps a version lister so results are served from an on-disk cache.
+//
+// Only successful lookups are cached; errors always reach the caller and the
+// next run queries the registry again. A nil cache returns fn unchanged.
+//
+// Parameters:
+//   - fn: The version lister to wrap
+//   - cache: The cache to consult, or nil to disable caching
+//
+// Returns:
+//   - ListNewerVersionsFunc: A lister with the same signature that consults the cache first
+func WithCache(fn ListNewerVersionsFunc, cache *VersionCache) ListNewerVersionsFunc {
+	if cache == nil {
+		return fn
+	}
+
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		fingerprint, ok := cacheFingerprint(p, cfg)
+		if !ok {
+			return fn(ctx, p, cfg, baseDir)
+		}
+
+		if versions, hit := cache.Get(p, fingerprint); hit {
+			verbose.Infof("Using cached versions for %s (%d candidates)", p.Name, len(versions))
+			return versions, nil
+		}
+
+		versions, err := fn(ctx, p, cfg, baseDir)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.Put(p, fingerprint, versions)
+		return versions, nil
+	}
+}
+
+// cacheFingerprint hashes the effective outdated configuration of a package.
+//
+// Parameters:
+//   - p: The package being checked
+//   - cfg: The global configuration
+//
+// Returns:
+//   - string: Hex digest of the configuration
+//   - bool: false when the configuration cannot be resolved, in which case the cache is bypassed
+func cacheFingerprint(p formats.Package, cfg *config.Config) (string, bool) {
+	if cfg == nil {
+		return "", false
+	}
+
+	outdatedCfg, err := resolveOutdatedCfg(p, cfg)
+	if err != nil {
+		return "", false
+	}
+
+	data, err := json.Marshal(outdatedCfg)
+	if err != nil {
+		return "", false
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), true
+}
@@ -0,0 +1,157 @@
+package outdated
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// stubCacheClock pins the cache clock for the duration of a test.
+// It returns a pointer to the current time so tests can advance it.
+func stubCacheClock(t *testing.T) *time.Time {
+	t.Helper()
+
+	orig := cacheNowFunc
+	t.Cleanup(func() { cacheNowFunc = orig })
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cacheNowFunc = func() time.Time { return now }
+	return &now
+}
+
+// TestWithCache tests the behavior of WithCache.
+//
+// It verifies:
+//   - A second lookup within the TTL is served from the cache
+//   - Entries expire after the TTL
+//   - A changed installed version misses the cache
+//   - A changed rule configuration misses the cache
+//   - Errors are not cached
+//   - Corrupt cache files are ignored and replaced
+//   - A nil cache returns the lister unchanged
+func TestWithCache(t *testing.T) {
+	pkg := formats.Package{Name: "lodash", Rule: "npm", Version: "^4.17.0", InstalledVersion: "4.17.20"}
+
+	newLister := func(calls *int) ListNewerVersionsFunc {
+		return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			*calls++
+			return []string{"4.17.21"}, nil
+		}
+	}
+
+	t.Run("serves fresh entries and expires stale ones", func(t *testing.T) {
+		now := stubCacheClock(t)
+		calls := 0
+		lister := WithCache(newLister(&calls), &VersionCache{Dir: t.TempDir(), TTL: time.Hour})
+		cfg := retryTestConfig(0, 0)
+
+		versions, err := lister(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"4.17.21"}, versions)
+
+		*now = now.Add(59 * time.Minute)
+		versions, err = lister(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"4.17.21"}, versions)
+		assert.Equal(t, 1, calls)
+
+		*now = now.Add(2 * time.Minute)
+		_, err = lister(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("installed version and config changes invalidate entries", func(t *testing.T) {
+		stubCacheClock(t)
+		calls := 0
+		lister := WithCache(newLister(&calls), &VersionCache{Dir: t.TempDir(), TTL: time.Hour})
+
+		_, err := lister(context.Background(), pkg, retryTestConfig(0, 0), ".")
+		require.NoError(t, err)
+
+		upgraded := pkg
+		upgraded.InstalledVersion = "4.17.21"
+		_, err = lister(context.Background(), upgraded, retryTestConfig(0, 0), ".")
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+
+		_, err = lister(context.Background(), pkg, retryTestConfig(2, 0), ".")
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		stubCacheClock(t)
+		calls := 0
+		lister := WithCache(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return nil, errors.New("registry unavailable")
+		}, &VersionCache{Dir: t.TempDir(), TTL: time.Hour})
+
+		for i := 0; i < 2; i++ {
+			_, err := lister(context.Background(), pkg, retryTestConfig(0, 0), ".")
+			require.Error(t, err)
+		}
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("corrupt entries are ignored", func(t *testing.T) {
+		stubCacheClock(t)
+		cache := &VersionCache{Dir: t.TempDir(), TTL: time.Hour}
+		require.NoError(t, os.WriteFile(cache.entryPath(pkg), []byte("{not json"), 0o644))
+
+		calls := 0
+		lister := WithCache(newLister(&calls), cache)
+		versions, err := lister(context.Background(), pkg, retryTestConfig(0, 0), ".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"4.17.21"}, versions)
+		assert.Equal(t, 1, calls)
+
+		_, err = lister(context.Background(), pkg, retryTestConfig(0, 0), ".")
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls, "corrupt entry is replaced by the fresh result")
+	})
+
+	t.Run("nil cache is a no-op", func(t *testing.T) {
+		calls := 0
+		lister := WithCache(newLister(&calls), nil)
+		for i := 0; i < 2; i++ {
+			_, err := lister(context.Background(), pkg, retryTestConfig(0, 0), ".")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, calls)
+	})
+}
+
+// TestNewVersionCache tests cache construction.
+//
+// It verifies:
+//   - The cache lives in a goupdate directory under the user cache directory
+//   - A non-positive TTL disables caching
+//   - A missing user cache directory disables caching
+func TestNewVersionCache(t *testing.T) {
+	orig := userCacheDirFunc
+	t.Cleanup(func() { userCacheDirFunc = orig })
+
+	base := t.TempDir()
+	userCacheDirFunc = func() (string, error) { return base, nil }
+
+	cache := NewVersionCache(30 * time.Minute)
+	require.NotNil(t, cache)
+	assert.Equal(t, filepath.Join(base, "goupdate"), cache.Dir)
+	assert.Equal(t, 30*time.Minute, cache.TTL)
+
+	assert.Nil(t, NewVersionCache(0))
+
+	userCacheDirFunc = func() (string, error) { return "", errors.New("no home") }
+	assert.Nil(t, NewVersionCache(time.Hour))
+}
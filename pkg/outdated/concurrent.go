@@ -0,0 +1,170 @@
+package outdated
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// DefaultConcurrency returns the default number of concurrent version lookups.
+//
+// Returns:
+//   - int: The number of logical CPUs, at least 1
+func DefaultConcurrency() int {
+	if n := runtime.NumCPU(); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// versionLookupResult holds the outcome of a single version lookup.
+type versionLookupResult struct {
+	versions []string
+	err      error
+}
+
+// VersionLookups fans out version lookups across a bounded worker pool.
+//
+// Results are stored by package index so callers can consume them in the same
+// order as the input slice, regardless of which lookup finishes first. This
+// keeps table rows, structured output, and progress counting deterministic.
+type VersionLookups struct {
+	ctx     context.Context
+	pkgs    []formats.Package
+	cfg     *config.Config
+	baseDir string
+	lister  ListNewerVersionsFunc
+
+	concurrent bool
+	results    []versionLookupResult
+	durations  []time.Duration
+	done       []chan struct{}
+}
+
+// StartVersionLookups begins looking up newer versions for the given packages.
+//
+// Only packages whose index satisfies include are scheduled; a nil include
+// schedules every package. When concurrency is greater than 1, lookups run in
+// the background on up to concurrency workers. Otherwise each lookup runs
+// lazily on the caller's goroutine when Get is called, matching the serial
+// behavior. Versions outside the package's pin are dropped from every result.
+//
+// Parameters:
+//   - ctx: Context for cancellation; pending lookups are skipped once cancelled
+//   - pkgs: Packages to look up, in the order results will be consumed
+//   - cfg: The global configuration
+//   - baseDir: Base directory for command execution
+//   - concurrency: Maximum number of lookups running at once
+//   - lister: The function used to list newer versions
+//   - include: Optional predicate selecting, by index, which packages need a lookup
+//
+// Returns:
+//   - *VersionLookups: Handle used to retrieve results by package index
+func StartVersionLookups(ctx context.Context, pkgs []formats.Package, cfg *config.Config, baseDir string, concurrency int, lister ListNewerVersionsFunc, include func(i int) bool) *VersionLookups {
+	lookups := &VersionLookups{
+		ctx:        ctx,
+		pkgs:       pkgs,
+		cfg:        cfg,
+		baseDir:    baseDir,
+		lister:     lister,
+		concurrent: concurrency > 1,
+		results:    make([]versionLookupResult, len(pkgs)),
+		durations:  make([]time.Duration, len(pkgs)),
+		done:       make([]chan struct{}, len(pkgs)),
+	}
+
+	if !lookups.concurrent {
+		return lookups
+	}
+
+	jobs := make(chan int)
+	for i := range pkgs {
+		if include == nil || include(i) {
+			lookups.done[i] = make(chan struct{})
+		}
+	}
+
+	workers := concurrency
+	if workers > len(pkgs) {
+		workers = len(pkgs)
+	}
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					lookups.results[i] = versionLookupResult{err: err}
+				} else {
+					versions, err := lookups.lookup(i)
+					lookups.results[i] = versionLookupResult{versions: versions, err: err}
+				}
+				close(lookups.done[i])
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range pkgs {
+			if lookups.done[i] != nil {
+				jobs <- i
+			}
+		}
+	}()
+
+	return lookups
+}
+
+// Get returns the lookup result for the package at index i.
+//
+// In concurrent mode this blocks until the lookup for that package has
+// finished. In serial mode, or for packages that were not scheduled, the
+// lookup runs immediately on the caller's goroutine.
+//
+// Parameters:
+//   - i: Index of the package in the slice passed to StartVersionLookups
+//
+// Returns:
+//   - []string: Newer versions available for the package
+//   - error: The error returned by the lister for this package
+func (l *VersionLookups) Get(i int) ([]string, error) {
+	if l.concurrent && l.done[i] != nil {
+		<-l.done[i]
+		return l.results[i].versions, l.results[i].err
+	}
+	return l.lookup(i)
+}
+
+// Duration returns how long the lookup for the package at index i took.
+//
+// It is only meaningful after Get has returned for that index; lookups that
+// have not run report zero.
+//
+// Parameters:
+//   - i: Index of the package in the slice passed to StartVersionLookups
+//
+// Returns:
+//   - time.Duration: Time spent in the lister for this package
+func (l *VersionLookups) Duration(i int) time.Duration {
+	return l.durations[i]
+}
+
+// lookup runs the lister for the package at index i and drops unpinned versions.
+//
+// Parameters:
+//   - i: Index of the package in the slice passed to StartVersionLookups
+//
+// Returns:
+//   - []string: Newer versions inside the package's pin
+//   - error: The error returned by the lister for this package, or an invalid pin error
+func (l *VersionLookups) lookup(i int) ([]string, error) {
+	start := time.Now()
+	versions, err := l.lister(l.ctx, l.pkgs[i], l.cfg, l.baseDir)
+	l.durations[i] = time.Since(start)
+	if err != nil {
+		return versions, err
+	}
+	return FilterPinnedVersions(l.pkgs[i], l.cfg, versions)
+}
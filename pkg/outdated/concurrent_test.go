@@ -0,0 +1,137 @@
+package outdated
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestStartVersionLookups tests the behavior of StartVersionLookups.
+//
+// It verifies:
+//   - Results are returned by index regardless of completion order
+//   - Per-package errors are preserved
+//   - Concurrency never exceeds the configured limit
+//   - Excluded packages are not looked up in the background
+//   - Serial mode runs lookups lazily on Get
+//   - Each lookup records how long the lister took
+func TestStartVersionLookups(t *testing.T) {
+	pkgs := []formats.Package{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+
+	t.Run("concurrent results keep input order", func(t *testing.T) {
+		var running, peak int32
+		lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+					break
+				}
+			}
+			// Earlier packages finish last to exercise out-of-order completion
+			time.Sleep(time.Duration(len(pkgs)-int(p.Name[0]-'a')) * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			if p.Name == "c" {
+				return nil, errors.New("lookup failed for c")
+			}
+			return []string{p.Name + "-2.0.0"}, nil
+		}
+
+		lookups := StartVersionLookups(context.Background(), pkgs, &config.Config{}, ".", 2, lister, nil)
+
+		for i, p := range pkgs {
+			versions, err := lookups.Get(i)
+			if p.Name == "c" {
+				require.Error(t, err)
+				assert.Nil(t, versions)
+				continue
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{p.Name + "-2.0.0"}, versions)
+		}
+		assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+	})
+
+	t.Run("excluded packages are not scheduled", func(t *testing.T) {
+		var mu sync.Mutex
+		var seen []string
+		lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			mu.Lock()
+			seen = append(seen, p.Name)
+			mu.Unlock()
+			return nil, nil
+		}
+
+		lookups := StartVersionLookups(context.Background(), pkgs, &config.Config{}, ".", 4, lister, func(i int) bool {
+			return pkgs[i].Name != "b"
+		})
+		for i, p := range pkgs {
+			if p.Name != "b" {
+				_, _ = lookups.Get(i)
+			}
+		}
+
+		assert.ElementsMatch(t, []string{"a", "c", "d", "e"}, seen)
+	})
+
+	t.Run("serial mode looks up lazily", func(t *testing.T) {
+		calls := 0
+		lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return []string{p.Name}, nil
+		}
+
+		lookups := StartVersionLookups(context.Background(), pkgs, &config.Config{}, ".", 1, lister, nil)
+		assert.Equal(t, 0, calls)
+
+		versions, err := lookups.Get(3)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"d"}, versions)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("lookup durations are recorded", func(t *testing.T) {
+		lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			if p.Name == "b" {
+				time.Sleep(5 * time.Millisecond)
+			}
+			return nil, nil
+		}
+
+		for _, concurrency := range []int{1, 3} {
+			lookups := StartVersionLookups(context.Background(), pkgs, &config.Config{}, ".", concurrency, lister, func(i int) bool {
+				return pkgs[i].Name != "e"
+			})
+			_, _ = lookups.Get(1)
+			assert.GreaterOrEqual(t, lookups.Duration(1), 5*time.Millisecond)
+			assert.Zero(t, lookups.Duration(4), "lookup never ran")
+		}
+	})
+
+	t.Run("cancelled context skips pending lookups", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			return []string{"1.0.0"}, nil
+		}
+
+		lookups := StartVersionLookups(ctx, pkgs, &config.Config{}, ".", 3, lister, nil)
+		_, err := lookups.Get(4)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// TestDefaultConcurrency tests that DefaultConcurrency is always positive.
+func TestDefaultConcurrency(t *testing.T) {
+	assert.GreaterOrEqual(t, DefaultConcurrency(), 1)
+}
@@ -7,6 +7,7 @@ package outdated
 import (
 	"context"
 	"fmt"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -26,6 +27,10 @@ var (
 	fallbackExcludeVersionPatterns = []string{productionSafeVersionPattern}
 )
 
+// RegistryEnvVar is the environment variable that passes a rule's registry
+// override to its outdated and release date commands (see config.RegistryEnvVar).
+const RegistryEnvVar = config.RegistryEnvVar
+
 var supportedConstraints = map[string]bool{
 	"":   true,
 	"^":  true,
@@ -39,6 +44,7 @@ var supportedConstraints = map[string]bool{
 }
 
 // ListNewerVersions runs the configured command for a package and returns newer versions.
+// Rules with a version_source read the candidates from their offline index instead.
 // It prefers installed versions for comparison and falls back to declared constraints.
 // The context parameter allows callers to cancel long-running operations.
 func ListNewerVersions(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
@@ -56,14 +62,7 @@ func ListNewerVersions(ctx context.Context, p formats.Package, cfg *config.Confi
 		return nil, err
 	}
 
-	scopeDir := resolveOutdatedScope(p, cfg, baseDir)
-
-	output, err := runOutdatedCommand(ctx, outdatedCfg, p, scopeDir)
-	if err != nil {
-		return nil, err
-	}
-
-	versions, err := parseAvailableVersionsForPackage(p.Name, outdatedCfg, output)
+	versions, err := listRegistryVersions(ctx, outdatedCfg, p, cfg, baseDir)
 	if err != nil {
 		return nil, err
 	}
@@ -79,18 +78,93 @@ func ListNewerVersions(ctx context.Context, p formats.Package, cfg *config.Confi
 	}
 	versions = versionsAfterExclusions
 
-	filtered := filterNewerVersionsWithStrategy(CurrentVersionForOutdated(p), versions, strategy)
+	current := CurrentVersionForOutdated(p)
+	filtered := filterNewerVersionsWithStrategy(current, versions, strategy)
+	if outdatedCfg.Versioning != nil && outdatedCfg.Versioning.MatchPrecision {
+		// Match before filtering, which dedupes "v5" and "v5.0.0" into one entry
+		if precise := filterNewerVersionsWithStrategy(current, matchRefPrecision(current, versions), strategy); len(precise) > 0 {
+			filtered = precise
+		}
+	}
 	verbose.VersionsFiltered(p.Name, filtered)
 
 	return filtered, nil
 }
 
+// ListAllVersions returns every version the registry lists for a package.
+//
+// Unlike ListNewerVersions, older versions and versions matched by the rule's
+// exclusion patterns are kept, so callers can check whether an exact version
+// exists, e.g. for update --update-to.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package to look up
+//   - cfg: Configuration holding the package's rule
+//   - baseDir: Directory the outdated command runs relative to
+//
+// Returns:
+//   - []string: All versions found, unfiltered
+//   - error: When the rule has no outdated configuration or the lookup fails
+func ListAllVersions(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("configuration is required")
+	}
+
+	outdatedCfg, err := resolveOutdatedCfg(p, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return listRegistryVersions(ctx, outdatedCfg, p, cfg, baseDir)
+}
+
+// listRegistryVersions lists a package's versions from its offline index or its outdated command.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - outdatedCfg: The effective outdated configuration
+//   - p: The package to look up
+//   - cfg: Configuration holding the package's rule
+//   - baseDir: Directory the outdated command runs relative to
+//
+// Returns:
+//   - []string: All versions found, unfiltered
+//   - error: When the index cannot be read or the command fails
+func listRegistryVersions(ctx context.Context, outdatedCfg *config.OutdatedCfg, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+	if source := cfg.Rules[p.Rule].VersionSource; source != "" {
+		// Offline rules never run the outdated command, so no registry is contacted
+		return listOfflineVersions(source, p, baseDir)
+	}
+	return listCommandVersions(ctx, outdatedCfg, p, resolveOutdatedScope(p, cfg, baseDir))
+}
+
+// listCommandVersions runs the outdated command of a package and parses the versions it prints.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - outdatedCfg: The effective outdated configuration
+//   - p: The package to look up
+//   - scopeDir: Directory the command runs in
+//
+// Returns:
+//   - []string: All versions found in the command output, unfiltered
+//   - error: When the command fails or its output cannot be parsed
+func listCommandVersions(ctx context.Context, outdatedCfg *config.OutdatedCfg, p formats.Package, scopeDir string) ([]string, error) {
+	output, err := runOutdatedCommand(ctx, outdatedCfg, p, scopeDir)
+	if err != nil {
+		return nil, err
+	}
+	return parseAvailableVersionsForPackage(p.Name, outdatedCfg, output)
+}
+
 // resolveOutdatedCfg builds the effective outdated configuration for a package.
 //
 // It performs the following operations:
 //   - Retrieves base configuration from the package's rule
 //   - Applies package-specific overrides if configured
-//   - Merges default version exclusions
+//   - Merges default version exclusions unless pre-releases are allowed
+//   - Exports the rule's registry override as $GOUPDATE_REGISTRY
 //   - Applies NoTimeout flag from runtime config
 //
 // Parameters:
@@ -106,11 +180,16 @@ func resolveOutdatedCfg(p formats.Package, cfg *config.Config) (*config.Outdated
 		return nil, fmt.Errorf("rule configuration missing for %s", p.Rule)
 	}
 
-	if ruleCfg.Outdated == nil {
-		return nil, &errors.UnsupportedError{Reason: fmt.Sprintf("outdated configuration missing for %s", p.Rule)}
+	outdatedCfg := ruleCfg.Outdated
+	if outdatedCfg == nil {
+		// An offline version source needs no command; exclusions and versioning keep their defaults
+		if ruleCfg.VersionSource == "" {
+			return nil, &errors.UnsupportedError{Reason: fmt.Sprintf("outdated configuration missing for %s", p.Rule)}
+		}
+		outdatedCfg = &config.OutdatedCfg{}
 	}
 
-	effective := cloneOutdatedCfg(ruleCfg.Outdated)
+	effective := cloneOutdatedCfg(outdatedCfg)
 
 	var overrideCfg *config.OutdatedOverrideCfg
 	if ruleCfg.PackageOverrides != nil {
@@ -135,9 +214,33 @@ func resolveOutdatedCfg(p formats.Package, cfg *config.Config) (*config.Outdated
 		if overrideCfg.TimeoutSeconds != nil {
 			effective.TimeoutSeconds = *overrideCfg.TimeoutSeconds
 		}
+
+		if overrideCfg.Retries != nil {
+			effective.Retries = *overrideCfg.Retries
+		}
+
+		if overrideCfg.RetryBackoffMs != nil {
+			effective.RetryBackoffMs = *overrideCfg.RetryBackoffMs
+		}
+	}
+
+	// Built-in exclusions filter pre-releases; lift only those when pre-releases are allowed
+	if config.ShouldAllowPrerelease(p, cfg) {
+		verbose.Debugf("Pre-release versions allowed for %s; skipping built-in pre-release exclusions", p.Name)
+		if custom := withoutPrereleaseExclusions(resolveDefaultExclusions(cfg, ruleCfg)); len(custom) > 0 {
+			applyDefaultExclusions(effective, custom)
+		}
+	} else {
+		applyDefaultExclusions(effective, resolveDefaultExclusions(cfg, ruleCfg))
 	}
 
-	applyDefaultExclusions(effective, resolveDefaultExclusions(cfg, ruleCfg))
+	// Point the commands at the rule's registry mirror
+	if ruleCfg.Registry != "" {
+		if effective.Env == nil {
+			effective.Env = make(map[string]string, 1)
+		}
+		effective.Env[RegistryEnvVar] = strings.TrimRight(ruleCfg.Registry, "/")
+	}
 
 	// Apply NoTimeout flag from runtime config
 	if cfg.NoTimeout {
@@ -162,6 +265,28 @@ func resolveDefaultExclusions(cfg *config.Config, ruleCfg config.PackageManagerC
 	return cfg.ExcludeVersions
 }
 
+// withoutPrereleaseExclusions drops the built-in pre-release patterns from exclusion patterns.
+//
+// Parameters:
+//   - patterns: Exclusion patterns from the rule or global config
+//
+// Returns:
+//   - []string: The user-defined patterns, in their original order
+func withoutPrereleaseExclusions(patterns []string) []string {
+	builtin := map[string]bool{productionSafeVersionPattern: true}
+	for _, pattern := range config.DefaultExcludeVersions() {
+		builtin[pattern] = true
+	}
+
+	var custom []string
+	for _, pattern := range patterns {
+		if !builtin[pattern] {
+			custom = append(custom, pattern)
+		}
+	}
+	return custom
+}
+
 // cloneOutdatedCfg creates a deep copy of an outdated configuration.
 //
 // It performs the following operations:
@@ -198,6 +323,22 @@ func cloneOutdatedCfg(cfg *config.OutdatedCfg) *config.OutdatedCfg {
 		cloned.Extraction = &extraction
 	}
 
+	if cfg.ReleaseDates != nil {
+		releaseDates := *cfg.ReleaseDates
+		cloned.ReleaseDates = &releaseDates
+	}
+
+	if cfg.Yanked != nil {
+		yanked := *cfg.Yanked
+		cloned.Yanked = &yanked
+	}
+
+	if cfg.Repository != nil {
+		repository := *cfg.Repository
+		repository.JSONKeys = cloneStringSlice(cfg.Repository.JSONKeys)
+		cloned.Repository = &repository
+	}
+
 	return &cloned
 }
 
@@ -295,7 +436,6 @@ func applyDefaultExclusions(cfg *config.OutdatedCfg, defaults []string) {
 //   - Validates that command is configured
 //   - Executes command with package information and context
 //   - Normalizes known errors to UnsupportedError when appropriate
-//   - Extracts command name for error reporting
 //
 // Parameters:
 //   - ctx: Context for cancellation support
@@ -313,18 +453,7 @@ func runOutdatedCommand(ctx context.Context, cfg *config.OutdatedCfg, p formats.
 
 	output, err := execOutdatedFunc(ctx, cfg, p.Name, CurrentVersionForOutdated(p), p.Constraint, dir)
 	if err != nil {
-		// Extract first command name for error message
-		commandName := ""
-		// Normalize line endings for cross-platform compatibility (CRLF -> LF)
-		lines := strings.Split(strings.ReplaceAll(cfg.Commands, "\r\n", "\n"), "\n")
-		if len(lines) > 0 {
-			parts := strings.Fields(strings.TrimSpace(lines[0]))
-			if len(parts) > 0 {
-				commandName = parts[0]
-			}
-		}
-
-		if normalized := normalizeOutdatedError(err, commandName); normalized != err {
+		if normalized := normalizeOutdatedError(err, cfg.Commands); normalized != err {
 			return nil, normalized
 		}
 
@@ -334,6 +463,38 @@ func runOutdatedCommand(ctx context.Context, cfg *config.OutdatedCfg, p formats.
 	return output, nil
 }
 
+// matchRefPrecision keeps refs with as many version segments as the current ref.
+//
+// It backs the versioning.match_precision option. Actions are often pinned to a
+// floating major tag such as "v4", and repositories publish both "v5" and
+// "v5.0.0". Keeping the pin's precision makes "v4" move to "v5" instead of
+// "v5.0.0". Full versions and refs with no same-precision candidate are
+// returned unchanged.
+//
+// Parameters:
+//   - current: The currently pinned ref (e.g., "v4" or "v4.1.1")
+//   - versions: Newer refs
+//
+// Returns:
+//   - []string: Refs matching the pin's precision, or versions unchanged
+func matchRefPrecision(current string, versions []string) []string {
+	segments := countConstraintSegments(current)
+	if segments == 0 || segments >= 3 {
+		return versions
+	}
+
+	matched := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if countConstraintSegments(v) == segments {
+			matched = append(matched, v)
+		}
+	}
+	if len(matched) == 0 {
+		return versions
+	}
+	return matched
+}
+
 // ensureGoModFlag adds -mod=mod flag to Go commands if not already present.
 //
 // It performs the following operations:
@@ -374,39 +535,83 @@ func ensureGoModFlag(command string, args []string) []string {
 // normalizeOutdatedError converts known command-specific errors to UnsupportedError.
 //
 // It performs the following operations:
-//   - Checks if command is "dotnet" (case-insensitive)
-//   - Detects known unsupported scenarios (missing assets, multiple projects)
+//   - Checks if the first command is "dotnet" (case-insensitive)
+//   - Detects known unsupported dotnet scenarios (missing assets, multiple projects)
+//   - Detects GitHub API rate-limit and offline responses for commands calling api.github.com
 //   - Wraps detected errors as UnsupportedError
 //
+// Other failures, including timeouts, are returned unchanged so they surface
+// as real errors.
+//
 // Parameters:
 //   - err: The error to potentially normalize
-//   - command: The command name that was executed
+//   - commands: The commands that were executed
 //
 // Returns:
 //   - error: UnsupportedError if error matches known patterns; original error otherwise
-func normalizeOutdatedError(err error, command string) error {
+func normalizeOutdatedError(err error, commands string) error {
 	if err == nil {
 		return nil
 	}
 
-	if !strings.EqualFold(command, "dotnet") {
+	message := err.Error()
+
+	if strings.EqualFold(firstCommandName(commands), "dotnet") {
+		if strings.Contains(message, "No assets file was found") || strings.Contains(message, "Found more than one project") {
+			return &errors.UnsupportedError{Reason: message}
+		}
 		return err
 	}
 
-	message := err.Error()
-	if strings.Contains(message, "No assets file was found") || strings.Contains(message, "Found more than one project") {
-		return &errors.UnsupportedError{Reason: message}
+	if strings.Contains(commands, "api.github.com") {
+		for _, marker := range githubUnavailableMarkers {
+			if strings.Contains(message, marker) {
+				return &errors.UnsupportedError{Reason: fmt.Sprintf("GitHub API unavailable (rate limited or offline): %s", message)}
+			}
+		}
 	}
 
 	return err
 }
 
+// githubUnavailableMarkers are curl and GitHub API messages meaning the API
+// refused the request for rate limiting or could not be reached at all.
+var githubUnavailableMarkers = []string{
+	"returned error: 403",
+	"returned error: 429",
+	"API rate limit exceeded",
+	"Could not resolve host",
+	"Failed to connect",
+	"Couldn't connect",
+}
+
+// firstCommandName returns the program name of the first command line.
+//
+// Parameters:
+//   - commands: Multiline command string
+//
+// Returns:
+//   - string: The first word of the first line; empty when there is none
+func firstCommandName(commands string) string {
+	// Normalize line endings for cross-platform compatibility (CRLF -> LF)
+	lines := strings.Split(strings.ReplaceAll(commands, "\r\n", "\n"), "\n")
+	if len(lines) == 0 {
+		return ""
+	}
+	parts := strings.Fields(strings.TrimSpace(lines[0]))
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[0]
+}
+
 // applyVersionExclusions filters out versions matching exclusion rules.
 //
 // It performs the following operations:
 //   - Builds exclusion set from exact version matches
+//   - Collects exclude_versions entries with glob characters ("4.0.*") as globs
 //   - Compiles and validates regex patterns for safety
-//   - Filters versions against both exact matches and patterns
+//   - Filters versions against exact matches, globs and patterns
 //
 // Parameters:
 //   - versions: List of available versions to filter
@@ -421,8 +626,14 @@ func applyVersionExclusions(versions []string, cfg *config.OutdatedCfg, secCfg *
 	}
 
 	exclude := make(map[string]struct{}, len(cfg.ExcludeVersions))
+	var globs []string
 	for _, v := range cfg.ExcludeVersions {
-		if trimmed := strings.TrimSpace(v); trimmed != "" {
+		trimmed := strings.TrimSpace(v)
+		switch {
+		case trimmed == "":
+		case strings.ContainsAny(trimmed, "*?["):
+			globs = append(globs, trimmed)
+		default:
 			exclude[trimmed] = struct{}{}
 		}
 	}
@@ -466,6 +677,10 @@ func applyVersionExclusions(versions []string, cfg *config.OutdatedCfg, secCfg *
 			continue
 		}
 
+		if matchVersionGlob(globs, trimmed) {
+			continue
+		}
+
 		excluded := false
 		for _, re := range regexes {
 			if re.MatchString(trimmed) {
@@ -482,6 +697,31 @@ func applyVersionExclusions(versions []string, cfg *config.OutdatedCfg, secCfg *
 	return filtered, nil
 }
 
+// matchVersionGlob reports whether a version matches any exclude_versions glob.
+//
+// Globs use path.Match syntax ("*" matches any run of characters, "?" a single
+// one) and are compared with and without a leading "v", so "1.5.*" also
+// excludes "v1.5.2".
+//
+// Parameters:
+//   - globs: Version globs to check
+//   - version: The version to match
+//
+// Returns:
+//   - bool: true if a glob matched
+func matchVersionGlob(globs []string, version string) bool {
+	bare := strings.TrimPrefix(version, "v")
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, version); ok {
+			return true
+		}
+		if ok, _ := path.Match(strings.TrimPrefix(glob, "v"), bare); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // CurrentVersionForOutdated returns the version to use for outdated comparison.
 func CurrentVersionForOutdated(p formats.Package) string {
 	current := strings.TrimSpace(p.InstalledVersion)
@@ -515,6 +755,35 @@ func CurrentVersionForOutdated(p formats.Package) string {
 //   - string: Best patch update candidate (or "#N/A" if none)
 //   - error: When versioning strategy creation fails; nil on success
 func SummarizeAvailableVersions(current string, versions []string, cfg *config.VersioningCfg, incremental bool) (string, string, string, error) {
+	return SummarizeAvailableVersionsWith(current, versions, cfg, incremental, SummarizeOptions{})
+}
+
+// SummarizeOptions holds optional behavior for SummarizeAvailableVersionsWith.
+type SummarizeOptions struct {
+	// PreferStable selects a stable release over a pre-release within the same
+	// major, minor, or patch category, even when the pre-release is newer.
+	// Pre-releases are only selected when no stable release exists in the category.
+	PreferStable bool
+}
+
+// SummarizeAvailableVersionsWith returns the best major, minor, and patch candidates using options.
+//
+// It behaves like SummarizeAvailableVersions, with additional options for
+// candidate selection (see SummarizeOptions).
+//
+// Parameters:
+//   - current: The current version to compare against
+//   - versions: List of available versions to evaluate
+//   - cfg: Versioning configuration (nil uses semver defaults)
+//   - incremental: When true, selects nearest version; when false, selects newest
+//   - opts: Additional selection options
+//
+// Returns:
+//   - string: Best major update candidate (or "#N/A" if none)
+//   - string: Best minor update candidate (or "#N/A" if none)
+//   - string: Best patch update candidate (or "#N/A" if none)
+//   - error: When versioning strategy creation fails; nil on success
+func SummarizeAvailableVersionsWith(current string, versions []string, cfg *config.VersioningCfg, incremental bool, opts SummarizeOptions) (string, string, string, error) {
 	strategy, err := newVersioningStrategy(cfg)
 	if err != nil {
 		return "#N/A", "#N/A", "#N/A", err
@@ -532,6 +801,14 @@ func SummarizeAvailableVersions(current string, versions []string, cfg *config.V
 			return true
 		}
 
+		if opts.PreferStable {
+			candidatePre := utils.IsPreReleaseVersion(candidate.raw)
+			parsedPre := utils.IsPreReleaseVersion(parsed.raw)
+			if candidatePre != parsedPre {
+				return candidatePre
+			}
+		}
+
 		if incremental {
 			return strategy.compare(parsed, *candidate) < 0
 		}
@@ -669,15 +946,19 @@ func filterNewerVersionsWithStrategy(current string, versions []string, strategy
 }
 
 // UpdateSelectionFlags controls which version upgrades to consider.
+//
+// MaxBump narrows the versions the Major/Minor/Patch scope chooses from.
 type UpdateSelectionFlags struct {
-	Major bool
-	Minor bool
-	Patch bool
+	Major   bool
+	Minor   bool
+	Patch   bool
+	MaxBump MaxBump
 }
 
 // FilterVersionsByConstraint narrows available versions to those permitted by the package constraint or flag overrides.
 //
 // When flags are provided, they override constraint semantics to limit the scope of acceptable upgrades.
+// A MaxBump cap is applied last, measured from the installed version.
 //
 // Special handling:
 //   - Non-semver versions (4+ segments like 1.0.0.0, calver like 2024.01.15) are passed through
@@ -781,6 +1062,10 @@ func FilterVersionsByConstraint(p formats.Package, versions []string, flags Upda
 		}
 	}
 
+	if flags.MaxBump.IsSet() {
+		allowed = FilterVersionsByMaxBump(currentVersion, allowed, flags.MaxBump)
+	}
+
 	return allowed
 }
 
@@ -79,6 +79,7 @@ func TestResolveDefaultExclusionsFallsBackToTopLevel(t *testing.T) {
 // It verifies:
 //   - Excludes versions matching exact strings
 //   - Excludes versions matching regex patterns
+//   - Excludes versions matching globs, with or without a leading "v"
 func TestApplyVersionExclusions(t *testing.T) {
 	cfg := &config.OutdatedCfg{
 		ExcludeVersions:        []string{"1.0.0-beta"},
@@ -89,6 +90,11 @@ func TestApplyVersionExclusions(t *testing.T) {
 	filtered, err := applyVersionExclusions(versions, cfg, nil)
 	require.NoError(t, err)
 	assert.Equal(t, []string{"1.0.0", "2.1.0"}, filtered)
+
+	cfg = &config.OutdatedCfg{ExcludeVersions: []string{"3.2.*", "v1.5.?"}}
+	filtered, err = applyVersionExclusions([]string{"3.1.0", "3.2.0", "3.2.4", "v1.5.2", "1.5.3", "1.6.0"}, cfg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3.1.0", "1.6.0"}, filtered)
 }
 
 // TestApplyVersionExclusionsInvalidPattern tests the behavior of applyVersionExclusions with invalid regex.
@@ -286,6 +292,8 @@ func TestExtractExitCode(t *testing.T) {
 //   - Converts dotnet "No assets file" error to UnsupportedError
 //   - Converts dotnet "Found more than one project" error to UnsupportedError
 //   - Returns original error for other dotnet errors
+//   - Converts GitHub API rate-limit and offline errors to UnsupportedError
+//   - Returns original error for other GitHub API failures
 func TestNormalizeOutdatedError(t *testing.T) {
 	t.Run("nil error returns nil", func(t *testing.T) {
 		assert.Nil(t, normalizeOutdatedError(nil, "dotnet"))
@@ -315,6 +323,32 @@ func TestNormalizeOutdatedError(t *testing.T) {
 		assert.Equal(t, err, result)
 		assert.False(t, pkgerrors.IsUnsupported(result))
 	})
+
+	githubCommand := "curl -sSf https://api.github.com/repos/{{package}}/tags"
+
+	t.Run("GitHub API rate limit and offline become UnsupportedError", func(t *testing.T) {
+		for _, message := range []string{
+			"exit status 22: curl: (22) The requested URL returned error: 403",
+			"exit status 22: curl: (22) The requested URL returned error: 429",
+			"exit status 6: curl: (6) Could not resolve host: api.github.com",
+		} {
+			result := normalizeOutdatedError(errors.New(message), githubCommand)
+			assert.True(t, pkgerrors.IsUnsupported(result), message)
+		}
+	})
+
+	t.Run("other GitHub API failures return original", func(t *testing.T) {
+		for _, message := range []string{
+			"exit status 22: curl: (22) The requested URL returned error: 404",
+			"command timed out after 30 seconds: signal: killed",
+		} {
+			err := errors.New(message)
+			result := normalizeOutdatedError(err, githubCommand)
+			assert.Equal(t, err, result)
+		}
+		err := errors.New("curl: (22) The requested URL returned error: 403")
+		assert.Equal(t, err, normalizeOutdatedError(err, "curl -sSf https://registry.npmjs.org/{{package}}"))
+	})
 }
 
 // TestResolveOutdatedCfg tests the behavior of resolveOutdatedCfg.
@@ -328,7 +362,84 @@ func TestNormalizeOutdatedError(t *testing.T) {
 //   - Package override exclude patterns applied
 //   - Package override timeout applied
 //   - NoTimeout flag clears timeout
+//   - Default exclusions skipped when pre-releases are allowed
+//   - User-defined exclusions kept when pre-releases are allowed
+//   - Registry override exported as GOUPDATE_REGISTRY without a trailing slash
 func TestResolveOutdatedCfg(t *testing.T) {
+	t.Run("registry override exported to commands", func(t *testing.T) {
+		pkg := formats.Package{Rule: "npm", Name: "react"}
+		cfg := &config.Config{
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Registry: "https://npm.example.com/",
+					Outdated: &config.OutdatedCfg{
+						Commands: "npm view {{package}} versions --json",
+						Env:      map[string]string{"NPM_CONFIG_USERCONFIG": ".npmrc"},
+					},
+				},
+			},
+		}
+
+		result, err := resolveOutdatedCfg(pkg, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "https://npm.example.com", result.Env[RegistryEnvVar])
+		assert.Equal(t, ".npmrc", result.Env["NPM_CONFIG_USERCONFIG"])
+		assert.NotContains(t, cfg.Rules["npm"].Outdated.Env, RegistryEnvVar, "rule config is not modified")
+
+		rule := cfg.Rules["npm"]
+		rule.Registry = ""
+		cfg.Rules["npm"] = rule
+		result, err = resolveOutdatedCfg(pkg, cfg)
+		require.NoError(t, err)
+		assert.NotContains(t, result.Env, RegistryEnvVar)
+	})
+
+	t.Run("default exclusions skipped when pre-releases allowed", func(t *testing.T) {
+		pkg := formats.Package{Rule: "npm", Name: "react"}
+		cfg := &config.Config{
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Outdated: &config.OutdatedCfg{
+						Commands:               "npm view {{package}} versions --json",
+						ExcludeVersionPatterns: []string{"^0\\."},
+					},
+				},
+			},
+		}
+
+		result, err := resolveOutdatedCfg(pkg, cfg)
+		require.NoError(t, err)
+		assert.Greater(t, len(result.ExcludeVersionPatterns), 1)
+
+		cfg.AllowPrerelease = true
+		result, err = resolveOutdatedCfg(pkg, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"^0\\."}, result.ExcludeVersionPatterns)
+	})
+
+	t.Run("user exclusions kept when pre-releases allowed", func(t *testing.T) {
+		pkg := formats.Package{Rule: "npm", Name: "react"}
+		cfg := &config.Config{
+			ExcludeVersions: append(config.DefaultExcludeVersions(), "(?i)-internal"),
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {Outdated: &config.OutdatedCfg{Commands: "npm view {{package}} versions --json"}},
+			},
+			AllowPrerelease: true,
+		}
+
+		result, err := resolveOutdatedCfg(pkg, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"(?i)-internal"}, result.ExcludeVersionPatterns)
+
+		cfg.Rules["npm"] = config.PackageManagerCfg{
+			ExcludeVersions: []string{"(?i)[._-]beta", "^9\\."},
+			Outdated:        &config.OutdatedCfg{Commands: "npm view {{package}} versions --json"},
+		}
+		result, err = resolveOutdatedCfg(pkg, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"^9\\."}, result.ExcludeVersionPatterns)
+	})
+
 	t.Run("missing rule returns error", func(t *testing.T) {
 		pkg := formats.Package{Rule: "unknown"}
 		cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{}}
@@ -575,6 +686,43 @@ func TestRunOutdatedCommand(t *testing.T) {
 		_, err := runOutdatedCommand(context.Background(), cfg, pkg, ".")
 		assert.Error(t, err)
 	})
+
+	t.Run("rate-limited GitHub API lookup is unsupported", func(t *testing.T) {
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			return nil, errors.New("curl: (22) The requested URL returned error: 403")
+		}
+		cfg := &config.OutdatedCfg{Commands: "curl -sSf https://api.github.com/repos/{{package}}/tags"}
+		pkg := formats.Package{Name: "actions/checkout", Version: "v4", PackageType: "github-actions"}
+		_, err := runOutdatedCommand(context.Background(), cfg, pkg, ".")
+		require.Error(t, err)
+		assert.True(t, pkgerrors.IsUnsupported(err))
+		assert.Contains(t, err.Error(), "GitHub API unavailable")
+	})
+
+	t.Run("other github-actions failures are errors", func(t *testing.T) {
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			return nil, errors.New("command timed out after 30 seconds")
+		}
+		cfg := &config.OutdatedCfg{Commands: "curl -sSf https://api.github.com/repos/{{package}}/tags"}
+		pkg := formats.Package{Name: "actions/checkout", Version: "v4", PackageType: "github-actions"}
+		_, err := runOutdatedCommand(context.Background(), cfg, pkg, ".")
+		require.Error(t, err)
+		assert.False(t, pkgerrors.IsUnsupported(err))
+	})
+}
+
+// TestMatchRefPrecision tests the behavior of matchRefPrecision.
+//
+// It verifies:
+//   - Major and minor pins keep only refs with the same number of segments
+//   - Full versions are returned unchanged
+//   - All refs are returned when none has the pin's precision
+func TestMatchRefPrecision(t *testing.T) {
+	versions := []string{"v5", "v5.0.0", "v4.2", "v4.2.1"}
+	assert.Equal(t, []string{"v5"}, matchRefPrecision("v4", versions))
+	assert.Equal(t, []string{"v4.2"}, matchRefPrecision("v4.1", versions))
+	assert.Equal(t, versions, matchRefPrecision("v4.1.1", versions))
+	assert.Equal(t, []string{"v5.0.0"}, matchRefPrecision("v4", []string{"v5.0.0"}))
 }
 
 // TestListNewerVersions tests the behavior of ListNewerVersions.
@@ -584,6 +732,8 @@ func TestRunOutdatedCommand(t *testing.T) {
 //   - Missing rule returns error
 //   - Successful version listing
 //   - Excludes versions matching patterns
+//   - A package override's exclude_versions globs replace the rule list
+//   - versioning.match_precision keeps the current ref's precision
 //   - Invalid versioning strategy returns error
 func TestListNewerVersions(t *testing.T) {
 	// Save original function
@@ -648,6 +798,55 @@ func TestListNewerVersions(t *testing.T) {
 		assert.Contains(t, versions, "2.0.0")
 	})
 
+	t.Run("package override exclude_versions globs replace the rule list", func(t *testing.T) {
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			return []byte(`["17.0.1", "17.0.2", "18.0.0", "18.1.0"]`), nil
+		}
+
+		cfg := &config.Config{
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Outdated: &config.OutdatedCfg{
+						Commands:        "npm view {{package}} versions --json",
+						ExcludeVersions: []string{"17.0.2"},
+					},
+					PackageOverrides: map[string]config.PackageOverrideCfg{
+						"react": {Outdated: &config.OutdatedOverrideCfg{ExcludeVersions: []string{"18.*"}}},
+					},
+				},
+			},
+		}
+
+		lodash := formats.Package{Name: "lodash", Rule: "npm", Version: "17.0.0", InstalledVersion: "17.0.0"}
+		versions, err := ListNewerVersions(context.Background(), lodash, cfg, ".")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"17.0.1", "18.0.0", "18.1.0"}, versions)
+
+		react := formats.Package{Name: "react", Rule: "npm", Version: "17.0.0", InstalledVersion: "17.0.0"}
+		versions, err = ListNewerVersions(context.Background(), react, cfg, ".")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"17.0.1", "17.0.2"}, versions)
+	})
+
+	t.Run("match_precision keeps the current ref's precision", func(t *testing.T) {
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			return []byte(`["v4", "v5.0.0", "v5"]`), nil
+		}
+
+		pkg := formats.Package{Name: "actions/checkout", Rule: "actions", Version: "v4", InstalledVersion: "v4"}
+		outdatedCfg := &config.OutdatedCfg{Commands: "curl {{package}}"}
+		cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{"actions": {Outdated: outdatedCfg}}}
+
+		versions, err := ListNewerVersions(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v5.0.0"}, versions)
+
+		outdatedCfg.Versioning = &config.VersioningCfg{MatchPrecision: true}
+		versions, err = ListNewerVersions(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v5"}, versions)
+	})
+
 	t.Run("invalid versioning strategy returns error", func(t *testing.T) {
 		pkg := formats.Package{Name: "test", Rule: "npm", Version: "1.0.0"}
 		cfg := &config.Config{
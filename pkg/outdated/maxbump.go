@@ -0,0 +1,170 @@
+package outdated
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// MaxBump caps how far an update target may move from the installed version.
+//
+// A cap of "minor:2" allows candidates in the same major version whose minor
+// version is at most 2 above the installed one (any patch). A cap of
+// "patch:5" allows candidates in the same major.minor whose patch is at most
+// 5 above the installed one. A cap of "major:1" allows at most one major bump.
+//
+// Fields:
+//   - Level: Version segment the cap applies to ("major", "minor", or "patch"); empty means no cap
+//   - Steps: Maximum increase of that segment
+type MaxBump struct {
+	Level string
+	Steps int
+}
+
+// IsSet reports whether a cap is configured.
+//
+// Returns:
+//   - bool: true when Level is set
+func (m MaxBump) IsSet() bool {
+	return m.Level != ""
+}
+
+// String returns the cap in its flag form (e.g. "minor:2").
+//
+// Returns:
+//   - string: the cap as "level:steps", or empty if unset
+func (m MaxBump) String() string {
+	if !m.IsSet() {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", m.Level, m.Steps)
+}
+
+// ParseMaxBump parses a --max-bump value such as "minor:2" or "patch:5".
+//
+// Parameters:
+//   - value: "level:steps" where level is major, minor, or patch and steps is a non-negative integer; empty disables the cap
+//
+// Returns:
+//   - MaxBump: the parsed cap (zero value for an empty input)
+//   - error: when the level or step count is invalid
+func ParseMaxBump(value string) (MaxBump, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return MaxBump{}, nil
+	}
+
+	level, steps, ok := strings.Cut(value, ":")
+	if !ok {
+		return MaxBump{}, fmt.Errorf("invalid --max-bump %q: expected <level>:<steps> (e.g. minor:2)", value)
+	}
+
+	level = strings.ToLower(strings.TrimSpace(level))
+	switch level {
+	case "major", "minor", "patch":
+	default:
+		return MaxBump{}, fmt.Errorf("invalid --max-bump level %q: must be major, minor, or patch", level)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(steps))
+	if err != nil || n < 0 {
+		return MaxBump{}, fmt.Errorf("invalid --max-bump steps %q: must be a non-negative integer", steps)
+	}
+
+	return MaxBump{Level: level, Steps: n}, nil
+}
+
+// FilterVersionsByMaxBump keeps versions that stay within the cap from current.
+//
+// When current is not a semver version the cap cannot be measured and all
+// versions are returned unchanged. Otherwise non-semver candidates are
+// dropped, since their distance from current is unknown.
+//
+// Parameters:
+//   - current: installed version the cap is measured from
+//   - versions: candidate versions
+//   - bump: the cap to apply; an unset cap returns versions unchanged
+//
+// Returns:
+//   - []string: candidates within the cap, in their original order
+func FilterVersionsByMaxBump(current string, versions []string, bump MaxBump) []string {
+	base := canonicalSemver(current)
+	if !bump.IsSet() || base == "" {
+		return versions
+	}
+	baseMajor, baseMinor, basePatch := semverParts(semverCore(base))
+
+	allowed := make([]string, 0, len(versions))
+	for _, raw := range versions {
+		canonical := canonicalSemver(raw)
+		if canonical == "" {
+			continue
+		}
+		major, minor, patch := semverParts(semverCore(canonical))
+
+		var within bool
+		switch bump.Level {
+		case "major":
+			within = major-baseMajor <= bump.Steps
+		case "minor":
+			within = major == baseMajor && minor-baseMinor <= bump.Steps
+		case "patch":
+			within = major == baseMajor && minor == baseMinor && patch-basePatch <= bump.Steps
+		}
+
+		if within {
+			allowed = append(allowed, raw)
+		}
+	}
+
+	return allowed
+}
+
+// VersionGap measures how far target is from current.
+//
+// The gap is the difference of the first semver component that changes: a
+// major bump reports only the major difference, a minor bump only the minor
+// one, so gaps compare component by component.
+//
+// Parameters:
+//   - current: installed version
+//   - target: version the package would be updated to
+//
+// Returns:
+//   - [3]int: major, minor, and patch difference
+//   - bool: false when either version is not semver
+//
+// Example:
+//
+//	gap, _ := outdated.VersionGap("1.9.0", "3.0.0") // [2 0 0]
+//	gap, _ = outdated.VersionGap("1.2.3", "1.2.7")  // [0 0 4]
+func VersionGap(current, target string) ([3]int, bool) {
+	from, to := canonicalSemver(current), canonicalSemver(target)
+	if from == "" || to == "" {
+		return [3]int{}, false
+	}
+	fromMajor, fromMinor, fromPatch := semverParts(semverCore(from))
+	toMajor, toMinor, toPatch := semverParts(semverCore(to))
+
+	switch {
+	case toMajor != fromMajor:
+		return [3]int{toMajor - fromMajor, 0, 0}, true
+	case toMinor != fromMinor:
+		return [3]int{0, toMinor - fromMinor, 0}, true
+	default:
+		return [3]int{0, 0, toPatch - fromPatch}, true
+	}
+}
+
+// semverCore strips pre-release and build metadata from a canonical semver version.
+//
+// Parameters:
+//   - canonical: canonical semver version (e.g. "v1.2.3-rc.1")
+//
+// Returns:
+//   - string: the major.minor.patch core (e.g. "v1.2.3")
+func semverCore(canonical string) string {
+	return strings.TrimSuffix(semver.Canonical(canonical), semver.Prerelease(canonical))
+}
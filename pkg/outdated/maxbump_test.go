@@ -0,0 +1,114 @@
+package outdated
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestParseMaxBump tests the behavior of ParseMaxBump.
+//
+// It verifies:
+//   - Empty input returns an unset cap
+//   - Valid level:steps values are parsed case-insensitively
+//   - Missing separators, unknown levels, and negative or non-numeric steps are rejected
+func TestParseMaxBump(t *testing.T) {
+	bump, err := ParseMaxBump("")
+	require.NoError(t, err)
+	assert.False(t, bump.IsSet())
+
+	bump, err = ParseMaxBump("Minor:2")
+	require.NoError(t, err)
+	assert.Equal(t, MaxBump{Level: "minor", Steps: 2}, bump)
+	assert.Equal(t, "minor:2", bump.String())
+
+	for _, invalid := range []string{"minor", "build:1", "patch:-1", "major:x"} {
+		_, err := ParseMaxBump(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// TestFilterVersionsByMaxBump tests the behavior of FilterVersionsByMaxBump.
+//
+// It verifies:
+//   - minor:N keeps the same major and at most N minor steps
+//   - patch:N keeps the same major.minor and at most N patch steps
+//   - major:N allows at most N major steps
+//   - Non-semver candidates are dropped while non-semver current versions disable the cap
+func TestFilterVersionsByMaxBump(t *testing.T) {
+	versions := []string{"1.2.4", "1.2.9", "1.3.0", "1.4.1-rc.1", "1.5.0", "2.0.0", "3.0.0", "2024.01.15.1"}
+
+	tests := []struct {
+		name     string
+		current  string
+		bump     MaxBump
+		expected []string
+	}{
+		{"unset", "1.2.3", MaxBump{}, versions},
+		{"minor cap", "1.2.3", MaxBump{Level: "minor", Steps: 2}, []string{"1.2.4", "1.2.9", "1.3.0", "1.4.1-rc.1"}},
+		{"patch cap", "1.2.3", MaxBump{Level: "patch", Steps: 5}, []string{"1.2.4"}},
+		{"major cap", "1.2.3", MaxBump{Level: "major", Steps: 1}, []string{"1.2.4", "1.2.9", "1.3.0", "1.4.1-rc.1", "1.5.0", "2.0.0"}},
+		{"non-semver current", "2024.01.15.0", MaxBump{Level: "minor", Steps: 1}, versions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FilterVersionsByMaxBump(tt.current, versions, tt.bump))
+		})
+	}
+}
+
+// TestSelectTargetWithMaxBump tests that --max-bump narrows the set the level flags choose from.
+//
+// It verifies:
+//   - The highest candidate within the cap is selected rather than the latest
+//   - No target is selected when no candidate fits the cap
+func TestSelectTargetWithMaxBump(t *testing.T) {
+	p := formats.Package{Name: "lib", Version: "1.2.3", InstalledVersion: "1.2.3", Constraint: "^"}
+	versions := []string{"1.3.0", "1.4.0", "1.6.0", "2.0.0"}
+
+	selection := UpdateSelectionFlags{Major: true, MaxBump: MaxBump{Level: "minor", Steps: 2}}
+	filtered := FilterVersionsByConstraint(p, versions, selection)
+	major, minor, patch, err := SummarizeAvailableVersions(p.InstalledVersion, filtered, nil, false)
+	require.NoError(t, err)
+	target, err := SelectTargetVersion(major, minor, patch, selection, p.Constraint, false)
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", target)
+
+	selection.MaxBump = MaxBump{Level: "patch", Steps: 3}
+	filtered = FilterVersionsByConstraint(p, versions, selection)
+	assert.Empty(t, filtered)
+	major, minor, patch, err = SummarizeAvailableVersions(p.InstalledVersion, filtered, nil, false)
+	require.NoError(t, err)
+	target, _ = SelectTargetVersion(major, minor, patch, selection, p.Constraint, false)
+	assert.Empty(t, target)
+}
+
+// TestVersionGap tests the behavior of VersionGap.
+//
+// It verifies:
+//   - Only the first changed component is measured
+//   - Partial and v-prefixed versions are compared as semver
+//   - Non-semver versions report no gap
+func TestVersionGap(t *testing.T) {
+	tests := []struct {
+		current, target string
+		expected        [3]int
+	}{
+		{"1.9.0", "3.0.0", [3]int{2, 0, 0}},
+		{"1.2.3", "1.5.0", [3]int{0, 3, 0}},
+		{"v1.2.3", "1.2.7", [3]int{0, 0, 4}},
+		{"1.2", "1.2.1", [3]int{0, 0, 1}},
+	}
+	for _, tt := range tests {
+		gap, ok := VersionGap(tt.current, tt.target)
+		assert.True(t, ok, tt.current)
+		assert.Equal(t, tt.expected, gap, tt.current)
+	}
+
+	_, ok := VersionGap("latest", "1.0.0")
+	assert.False(t, ok)
+}
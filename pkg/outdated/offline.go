@@ -0,0 +1,140 @@
+package outdated
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// OfflineSourceScheme is the prefix of a rule's version_source pointing at a local index.
+const OfflineSourceScheme = "file://"
+
+// OfflineOperation is the UnsupportedError operation for packages missing from an offline index.
+const OfflineOperation = "offline"
+
+// offlineIndex is a parsed offline version index.
+//
+// Fields:
+//   - modTime: Modification time of the file when it was read
+//   - size: Size of the file when it was read
+//   - versions: Candidate versions per package name
+type offlineIndex struct {
+	modTime  time.Time
+	size     int64
+	versions map[string][]string
+}
+
+var (
+	offlineIndexMu    sync.Mutex
+	offlineIndexCache = make(map[string]*offlineIndex)
+)
+
+// OfflineIndexPath resolves the index file of a rule's version_source.
+//
+// It performs the following operations:
+//   - Step 1: Strip the file:// scheme; a relative path is resolved against baseDir
+//   - Step 2: When the path is a directory, use the rule's "<rule>.json" inside it
+//
+// Parameters:
+//   - source: The rule's version_source, e.g. "file:///mirror/npm.json"
+//   - rule: The rule name, used to pick the file of a directory index
+//   - baseDir: Directory relative paths are resolved against
+//
+// Returns:
+//   - string: Path of the JSON index file
+//   - error: When source does not use the file:// scheme
+func OfflineIndexPath(source, rule, baseDir string) (string, error) {
+	if !strings.HasPrefix(source, OfflineSourceScheme) {
+		return "", fmt.Errorf("unsupported version_source %q: expected %s<path>", source, OfflineSourceScheme)
+	}
+
+	path := filepath.FromSlash(strings.TrimPrefix(source, OfflineSourceScheme))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, rule+".json")
+	}
+	return path, nil
+}
+
+// listOfflineVersions reads the candidate versions of a package from a rule's offline index.
+//
+// The index is a JSON object mapping package names to arrays of versions, e.g.
+// {"react": ["18.2.0", "18.3.1"]}. It is never combined with a registry lookup:
+// a package without an entry is reported as an UnsupportedError with
+// OfflineOperation, so no network access is attempted for it.
+//
+// Parameters:
+//   - source: The rule's version_source
+//   - p: The package to look up
+//   - baseDir: Directory relative paths are resolved against
+//
+// Returns:
+//   - []string: All versions listed for the package, unfiltered
+//   - error: When the index cannot be read or parsed, or has no entry for the package
+func listOfflineVersions(source string, p formats.Package, baseDir string) ([]string, error) {
+	path, err := OfflineIndexPath(source, p.Rule, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := loadOfflineIndex(path)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, ok := index[p.Name]
+	if !ok {
+		verbose.Debugf("Offline index %s has no entry for %s", path, p.Name)
+		return nil, errors.NewUnsupportedError(OfflineOperation, "no offline version data", p.Name)
+	}
+	verbose.Debugf("Offline index %s lists %d versions for %s", path, len(versions), p.Name)
+	return versions, nil
+}
+
+// loadOfflineIndex reads and parses an offline index file.
+//
+// Parsed indexes are cached per path and re-read when the file's size or
+// modification time changes, so a large mirror index is parsed once per run
+// rather than once per package.
+//
+// Parameters:
+//   - path: Path of the JSON index file
+//
+// Returns:
+//   - map[string][]string: Versions per package name
+//   - error: When the file cannot be read or is not a JSON object of version arrays
+func loadOfflineIndex(path string) (map[string][]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline version index: %w", err)
+	}
+
+	offlineIndexMu.Lock()
+	defer offlineIndexMu.Unlock()
+
+	if cached, ok := offlineIndexCache[path]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.versions, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline version index: %w", err)
+	}
+	var versions map[string][]string
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("failed to parse offline version index %s: %w", path, err)
+	}
+
+	offlineIndexCache[path] = &offlineIndex{modTime: info.ModTime(), size: info.Size(), versions: versions}
+	return versions, nil
+}
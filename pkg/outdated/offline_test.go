@@ -0,0 +1,91 @@
+package outdated
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	goerrors "github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestOfflineIndexPath tests resolving a rule's version_source to its index file.
+//
+// It verifies:
+//   - Absolute file:// paths are used as they are
+//   - Relative paths are resolved against the base directory
+//   - A directory resolves to the rule's <rule>.json inside it
+//   - Sources without the file:// scheme are rejected
+func TestOfflineIndexPath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "mirror"), 0o755))
+
+	path, err := OfflineIndexPath("file://"+filepath.ToSlash(filepath.Join(dir, "versions.json")), "npm", "/elsewhere")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "versions.json"), path)
+
+	path, err = OfflineIndexPath("file://versions.json", "npm", dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "versions.json"), path)
+
+	path, err = OfflineIndexPath("file://mirror", "npm", dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "mirror", "npm.json"), path)
+
+	_, err = OfflineIndexPath("https://mirror.example.com", "npm", dir)
+	assert.ErrorContains(t, err, "unsupported version_source")
+}
+
+// TestListNewerVersionsOffline tests version lookups of rules with an offline version source.
+//
+// It verifies:
+//   - Candidates come from the index and are filtered like command output, without an outdated command
+//   - ListAllVersions keeps older and excluded versions
+//   - A package missing from the index is unsupported with OfflineOperation
+//   - An unreadable or malformed index is an error
+//   - A changed index file is re-read
+func TestListNewerVersionsOffline(t *testing.T) {
+	dir := t.TempDir()
+	index := filepath.Join(dir, "versions.json")
+	require.NoError(t, os.WriteFile(index, []byte(`{"react": ["17.0.2", "18.2.0", "18.3.0-rc.1", "16.0.0"]}`), 0o644))
+
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"npm": {Manager: "js", VersionSource: "file://versions.json"},
+	}}
+	react := formats.Package{Name: "react", Rule: "npm", PackageType: "js", Version: "17.0.2", InstalledVersion: "17.0.2"}
+
+	versions, err := ListNewerVersions(context.Background(), react, cfg, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"18.2.0"}, versions)
+
+	versions, err = ListAllVersions(context.Background(), react, cfg, dir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"17.0.2", "18.2.0", "18.3.0-rc.1", "16.0.0"}, versions)
+
+	_, err = ListNewerVersions(context.Background(), formats.Package{Name: "vue", Rule: "npm", Version: "3.0.0"}, cfg, dir)
+	ue, ok := goerrors.IsUnsupportedError(err)
+	require.True(t, ok, "expected unsupported error, got %v", err)
+	assert.Equal(t, OfflineOperation, ue.Operation)
+	assert.Equal(t, "no offline version data", ue.Reason)
+
+	// Rewrite with a different size and time so the cached index is stale
+	require.NoError(t, os.WriteFile(index, []byte(`{"react": ["17.0.2", "19.0.0"], "vue": []}`), 0o644))
+	require.NoError(t, os.Chtimes(index, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+	versions, err = ListNewerVersions(context.Background(), react, cfg, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"19.0.0"}, versions)
+
+	require.NoError(t, os.WriteFile(index, []byte(`["react"]`), 0o644))
+	_, err = ListNewerVersions(context.Background(), react, cfg, dir)
+	assert.ErrorContains(t, err, "failed to parse offline version index")
+
+	cfg.Rules["npm"] = config.PackageManagerCfg{VersionSource: "file://missing.json"}
+	_, err = ListNewerVersions(context.Background(), react, cfg, dir)
+	assert.ErrorContains(t, err, "failed to read offline version index")
+}
@@ -0,0 +1,173 @@
+package outdated
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/filtering"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// PinnedRange returns the pin configured for a package.
+//
+// Parameters:
+//   - p: The package to resolve the pin for
+//   - cfg: The global configuration
+//
+// Returns:
+//   - string: The pin range expression, trimmed
+//   - bool: true if the package's rule pins it
+func PinnedRange(p formats.Package, cfg *config.Config) (string, bool) {
+	if cfg == nil {
+		return "", false
+	}
+
+	ruleCfg, ok := cfg.Rules[p.Rule]
+	if !ok {
+		return "", false
+	}
+
+	// Package names match case-insensitively, like package_overrides
+	pin, ok := ruleCfg.Pin[p.Name]
+	if !ok {
+		for name, candidate := range ruleCfg.Pin {
+			if strings.EqualFold(name, p.Name) {
+				pin, ok = candidate, true
+				break
+			}
+		}
+	}
+	if !ok || strings.TrimSpace(pin) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(pin), true
+}
+
+// ValidatePins parses every pin of the configuration with the range parser.
+//
+// It is called when the configuration is loaded, so a malformed pin fails the
+// run instead of being skipped by PinViolation. Empty pins are left to the
+// config schema validation.
+//
+// Parameters:
+//   - cfg: The global configuration
+//
+// Returns:
+//   - error: The *errors.ValidationError of every malformed pin joined, or nil
+func ValidatePins(cfg *config.Config) error {
+	if cfg == nil {
+		return nil
+	}
+
+	var errs []error
+	for ruleName, ruleCfg := range cfg.Rules {
+		for name, pin := range ruleCfg.Pin {
+			pin = strings.TrimSpace(pin)
+			if pin == "" {
+				continue
+			}
+			if _, err := parsePin(formats.Package{Rule: ruleName, Name: name}, pin); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	// Map order is random; keep the report stable
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return stderrors.Join(errs...)
+}
+
+// parsePin parses the pin of a package into a version range.
+//
+// Parameters:
+//   - p: The package the pin belongs to
+//   - pin: The pin range expression
+//
+// Returns:
+//   - filtering.VersionRange: The parsed range
+//   - error: *errors.ValidationError naming the pin's config field when the range is malformed
+func parsePin(p formats.Package, pin string) (filtering.VersionRange, error) {
+	r, err := filtering.ParseVersionRange(pin)
+	if err != nil {
+		if ve, ok := errors.IsValidationError(err); ok {
+			ve.Field = fmt.Sprintf("rules.%s.pin.%s", p.Rule, p.Name)
+		}
+		return filtering.VersionRange{}, err
+	}
+	return r, nil
+}
+
+// FilterPinnedVersions drops versions outside the package's pin.
+//
+// Pins use the --version-range syntax ("2.x", "^1.4.0", ">=1.2 <2"). Like
+// exclude_versions, filtering runs right after the version lookup and before
+// constraint and --major/--minor/--patch selection, so the pin is the
+// outermost limit on the update target. Versions that are not semver never
+// satisfy a pin. When no newer version satisfies the pin the result is empty
+// and the package reports as up to date.
+//
+// Parameters:
+//   - p: The package the versions belong to
+//   - cfg: The global configuration
+//   - versions: Available versions to filter
+//
+// Returns:
+//   - []string: Versions inside the pin, in their original order
+//   - error: *errors.ValidationError when the pin is not a valid range
+func FilterPinnedVersions(p formats.Package, cfg *config.Config, versions []string) ([]string, error) {
+	pin, ok := PinnedRange(p, cfg)
+	if !ok || len(versions) == 0 {
+		return versions, nil
+	}
+
+	r, err := parsePin(p, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if !r.Contains(v) {
+			verbose.Debugf("Skipping version %s of %s (pin: %s)", v, p.Name, pin)
+			continue
+		}
+		kept = append(kept, v)
+	}
+
+	return kept, nil
+}
+
+// PinViolation reports whether a package's current version lies above its pin.
+//
+// A version below the pin is not a violation, since updating moves it into the
+// pin. Packages without a pin, with an invalid pin, or whose current version is
+// not semver are never reported.
+//
+// Parameters:
+//   - p: The package to check
+//   - cfg: The global configuration
+//
+// Returns:
+//   - string: Reason suitable for the unsupported tracker
+//   - bool: true if the current version violates the pin
+func PinViolation(p formats.Package, cfg *config.Config) (string, bool) {
+	pin, ok := PinnedRange(p, cfg)
+	if !ok {
+		return "", false
+	}
+
+	r, err := parsePin(p, pin)
+	if err != nil {
+		return "", false
+	}
+
+	current := CurrentVersionForOutdated(p)
+	if canonicalSemver(current) == "" || r.Contains(current) || r.Below(current) {
+		return "", false
+	}
+	return fmt.Sprintf("installed version %s violates pin %q; change the version or the pin", current, pin), true
+}
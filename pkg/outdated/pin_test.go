@@ -0,0 +1,161 @@
+package outdated
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestFilterPinnedVersions tests dropping versions outside a package's pin.
+//
+// It verifies:
+//   - Only versions inside the pin are kept, in their original order
+//   - Wildcard, caret, and comparator pins are supported
+//   - Pins match package names case-insensitively
+//   - Packages without a pin return versions unchanged
+//   - An invalid pin returns a ValidationError naming the pin's config field
+func TestFilterPinnedVersions(t *testing.T) {
+	cfg := &config.Config{
+		Rules: map[string]config.PackageManagerCfg{
+			"npm": {Pin: map[string]string{
+				"react":   "17.x",
+				"express": "^4.17.0",
+				"lodash":  ">=4.17.0 <4.17.21",
+				"broken":  "4.x.1",
+			}},
+			"pip": {},
+		},
+	}
+	versions := []string{"4.17.20", "4.17.21", "4.18.0", "5.0.0", "17.0.2", "18.2.0", "latest"}
+
+	filtered, err := FilterPinnedVersions(formats.Package{Name: "react", Rule: "npm"}, cfg, versions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"17.0.2"}, filtered)
+
+	filtered, err = FilterPinnedVersions(formats.Package{Name: "express", Rule: "npm"}, cfg, versions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4.17.20", "4.17.21", "4.18.0"}, filtered)
+
+	filtered, err = FilterPinnedVersions(formats.Package{Name: "lodash", Rule: "npm"}, cfg, versions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4.17.20"}, filtered)
+
+	filtered, err = FilterPinnedVersions(formats.Package{Name: "React", Rule: "npm"}, cfg, versions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"17.0.2"}, filtered)
+
+	filtered, err = FilterPinnedVersions(formats.Package{Name: "vue", Rule: "npm"}, cfg, versions)
+	require.NoError(t, err)
+	assert.Equal(t, versions, filtered)
+
+	filtered, err = FilterPinnedVersions(formats.Package{Name: "requests", Rule: "pip"}, nil, versions)
+	require.NoError(t, err)
+	assert.Equal(t, versions, filtered)
+
+	_, err = FilterPinnedVersions(formats.Package{Name: "broken", Rule: "npm"}, cfg, versions)
+	require.Error(t, err)
+	ve, ok := errors.IsValidationError(err)
+	require.True(t, ok)
+	assert.Equal(t, "rules.npm.pin.broken", ve.Field)
+}
+
+// TestValidatePins tests parsing every configured pin when the config is loaded.
+//
+// It verifies:
+//   - Valid and empty pins pass
+//   - Each malformed pin is reported as a ValidationError naming its config field
+func TestValidatePins(t *testing.T) {
+	assert.NoError(t, ValidatePins(nil))
+
+	cfg := &config.Config{
+		Rules: map[string]config.PackageManagerCfg{
+			"npm": {Pin: map[string]string{"react": "17.x", "vue": " "}},
+		},
+	}
+	assert.NoError(t, ValidatePins(cfg))
+
+	cfg.Rules["npm"].Pin["broken"] = "<<1"
+	cfg.Rules["pip"] = config.PackageManagerCfg{Pin: map[string]string{"django": "4.x.1"}}
+	err := ValidatePins(cfg)
+	require.Error(t, err)
+	ve, ok := errors.IsValidationError(err)
+	require.True(t, ok)
+	assert.Equal(t, "rules.npm.pin.broken", ve.Field)
+	assert.Contains(t, err.Error(), "rules.pip.pin.django")
+}
+
+// TestPinViolation tests detection of packages whose current version is above their pin.
+//
+// It verifies:
+//   - A version inside the pin is not a violation
+//   - A version below the pin is not a violation, since updating moves it into the pin
+//   - A version above the pin is reported with the version and the pin
+//   - The declared version is checked when nothing is installed
+//   - Unpinned packages, non-semver versions, and invalid pins are never reported
+func TestPinViolation(t *testing.T) {
+	cfg := &config.Config{
+		Rules: map[string]config.PackageManagerCfg{
+			"npm": {Pin: map[string]string{"react": "17.x", "broken": "<<1"}},
+		},
+	}
+
+	_, violated := PinViolation(formats.Package{Name: "react", Rule: "npm", InstalledVersion: "17.0.2"}, cfg)
+	assert.False(t, violated)
+
+	reason, violated := PinViolation(formats.Package{Name: "react", Rule: "npm", InstalledVersion: "18.2.0"}, cfg)
+	assert.True(t, violated)
+	assert.Equal(t, `installed version 18.2.0 violates pin "17.x"; change the version or the pin`, reason)
+
+	_, violated = PinViolation(formats.Package{Name: "react", Rule: "npm", Version: "18.0.0", InstalledVersion: "#N/A"}, cfg)
+	assert.True(t, violated)
+
+	for _, p := range []formats.Package{
+		{Name: "react", Rule: "npm", InstalledVersion: "16.14.0"},
+		{Name: "vue", Rule: "npm", InstalledVersion: "3.0.0"},
+		{Name: "react", Rule: "npm", InstalledVersion: "latest"},
+		{Name: "broken", Rule: "npm", InstalledVersion: "2.0.0"},
+	} {
+		_, violated = PinViolation(p, cfg)
+		assert.False(t, violated, p.Name)
+	}
+}
+
+// TestVersionLookupsPin tests that version lookups apply pins.
+//
+// It verifies:
+//   - Serial and concurrent lookups drop versions outside the pin
+//   - An invalid pin is returned as the lookup error
+func TestVersionLookupsPin(t *testing.T) {
+	cfg := &config.Config{
+		Rules: map[string]config.PackageManagerCfg{
+			"npm": {
+				Pin: map[string]string{"a": "2.x", "b": "2.x.y"},
+			},
+		},
+	}
+	pkgs := []formats.Package{
+		{Name: "a", Rule: "npm"},
+		{Name: "b", Rule: "npm"},
+	}
+	lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"2.0.1", "2.1.0", "2.2.0", "3.0.0"}, nil
+	}
+
+	for _, concurrency := range []int{1, 2} {
+		lookups := StartVersionLookups(context.Background(), pkgs, cfg, ".", concurrency, lister, nil)
+
+		versions, err := lookups.Get(0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2.0.1", "2.1.0", "2.2.0"}, versions)
+
+		_, err = lookups.Get(1)
+		_, ok := errors.IsValidationError(err)
+		assert.True(t, ok, "%v", err)
+	}
+}
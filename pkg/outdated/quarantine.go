@@ -0,0 +1,96 @@
+package outdated
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// QuarantineOperation is the UnsupportedError operation for packages the quarantine cannot check.
+const QuarantineOperation = "quarantine"
+
+// FilterCandidatesQuarantined keeps candidates published at least days before now.
+//
+// Unlike FilterCandidatesByMinAge, candidates without a known publish
+// timestamp are dropped, since they cannot be shown to have settled. A
+// non-positive days returns the candidates unchanged.
+//
+// Parameters:
+//   - candidates: Candidates to filter
+//   - days: Length of the quarantine window in days
+//   - now: Reference time
+//
+// Returns:
+//   - []VersionCandidate: Candidates published outside the quarantine window
+func FilterCandidatesQuarantined(candidates []VersionCandidate, days int, now time.Time) []VersionCandidate {
+	if days <= 0 {
+		return candidates
+	}
+
+	window := time.Duration(days) * 24 * time.Hour
+	kept := make([]VersionCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if !c.Published.IsZero() && now.Sub(c.Published) >= window {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// WithQuarantine wraps a version lister so it skips versions published within a quarantine window.
+//
+// The window is the rule's quarantine_days unless override is set. Publish
+// timestamps come from the rule's outdated.release_dates lookup. When every
+// newer version is quarantined the lister returns none, so the package is
+// reported up to date. When the rule has no lookup configured, or the registry
+// returns no timestamps, the package is reported as an UnsupportedError with
+// QuarantineOperation so callers route it to the unsupported tracker.
+//
+// Parameters:
+//   - fn: The version lister to wrap
+//   - listDates: The release date lookup
+//   - override: Window in days from --quarantine-days; nil uses each rule's quarantine_days
+//   - now: Reference time
+//
+// Returns:
+//   - ListNewerVersionsFunc: Lister returning only versions outside the quarantine window
+func WithQuarantine(fn ListNewerVersionsFunc, listDates ListReleaseDatesFunc, override *int, now time.Time) ListNewerVersionsFunc {
+	if override != nil && *override <= 0 {
+		return fn
+	}
+
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		days := config.QuarantineDaysFor(p, cfg)
+		if override != nil {
+			days = *override
+		}
+
+		versions, err := fn(ctx, p, cfg, baseDir)
+		if err != nil || len(versions) == 0 || days <= 0 {
+			return versions, err
+		}
+
+		if !ReleaseDatesConfigured(p, cfg) {
+			return nil, errors.NewUnsupportedError(QuarantineOperation, "no outdated.release_dates lookup is configured for this rule", p.Name)
+		}
+
+		dates, err := listDates(ctx, p, cfg, baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("release dates for the quarantine: %w", err)
+		}
+		if len(dates) == 0 {
+			return nil, errors.NewUnsupportedError(QuarantineOperation, "the registry returned no release dates", p.Name)
+		}
+
+		candidates := FilterCandidatesQuarantined(dates.Candidates(versions), days, now)
+		if excluded := len(versions) - len(candidates); excluded > 0 {
+			verbose.Infof("Quarantined %d release(s) of %s published within the last %d days", excluded, p.Name, days)
+		}
+		return CandidateVersions(candidates), nil
+	}
+}
@@ -0,0 +1,99 @@
+package outdated
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	goerrors "github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestFilterCandidatesQuarantined tests dropping versions inside the quarantine window.
+//
+// It verifies:
+//   - Candidates published fewer than days ago are dropped
+//   - Candidates exactly at the window boundary are kept
+//   - Candidates without a publish timestamp are dropped
+//   - A non-positive window keeps every candidate
+func TestFilterCandidatesQuarantined(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	candidates := []VersionCandidate{
+		{Version: "1.1.0", Published: now.AddDate(0, 0, -30)},
+		{Version: "1.2.0", Published: now.AddDate(0, 0, -7)},
+		{Version: "1.3.0", Published: now.AddDate(0, 0, -2)},
+		{Version: "1.4.0"},
+	}
+
+	assert.Equal(t, []string{"1.1.0", "1.2.0"}, CandidateVersions(FilterCandidatesQuarantined(candidates, 7, now)))
+	assert.Equal(t, candidates, FilterCandidatesQuarantined(candidates, 0, now))
+	assert.Empty(t, FilterCandidatesQuarantined(candidates, 60, now))
+}
+
+// TestWithQuarantine tests wrapping a version lister with a quarantine window.
+//
+// It verifies:
+//   - The rule's quarantine_days applies when no override is given
+//   - An override replaces the rule's window, and a zero override disables it
+//   - Every newer version being quarantined yields no versions
+//   - Rules without release dates, or empty registry dates, return an UnsupportedError
+//   - Release date lookup errors are returned as regular errors
+//   - Rules without a window never look up release dates
+func TestWithQuarantine(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	window := 7
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"npm":  {QuarantineDays: &window, Outdated: &config.OutdatedCfg{Commands: "echo", ReleaseDates: &config.ReleaseDatesCfg{Commands: "echo"}}},
+		"mod":  {QuarantineDays: &window, Outdated: &config.OutdatedCfg{Commands: "echo"}},
+		"pnpm": {Outdated: &config.OutdatedCfg{Commands: "echo"}},
+	}}
+	list := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"1.1.0", "1.2.0"}, nil
+	}
+	var lookups int
+	dates := ReleaseDates{"1.1.0": now.AddDate(0, 0, -10), "1.2.0": now.AddDate(0, 0, -1)}
+	var datesErr error
+	listDates := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (ReleaseDates, error) {
+		lookups++
+		return dates, datesErr
+	}
+	npm := formats.Package{Name: "demo", Rule: "npm"}
+	days := func(n int) *int { return &n }
+
+	versions, err := WithQuarantine(list, listDates, nil, now)(context.Background(), npm, cfg, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.1.0"}, versions)
+
+	versions, err = WithQuarantine(list, listDates, days(14), now)(context.Background(), npm, cfg, ".")
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+
+	_, err = WithQuarantine(list, listDates, nil, now)(context.Background(), formats.Package{Name: "demo", Rule: "mod"}, cfg, ".")
+	ue, ok := goerrors.IsUnsupportedError(err)
+	require.True(t, ok)
+	assert.Equal(t, QuarantineOperation, ue.Operation)
+
+	lookups = 0
+	versions, err = WithQuarantine(list, listDates, nil, now)(context.Background(), formats.Package{Name: "demo", Rule: "pnpm"}, cfg, ".")
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+	versions, err = WithQuarantine(list, listDates, days(0), now)(context.Background(), npm, cfg, ".")
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.Zero(t, lookups)
+
+	dates = ReleaseDates{}
+	_, err = WithQuarantine(list, listDates, nil, now)(context.Background(), npm, cfg, ".")
+	assert.True(t, goerrors.IsUnsupported(err))
+
+	datesErr = errors.New("registry down")
+	_, err = WithQuarantine(list, listDates, nil, now)(context.Background(), npm, cfg, ".")
+	require.Error(t, err)
+	assert.False(t, goerrors.IsUnsupported(err))
+	assert.Contains(t, err.Error(), "registry down")
+}
@@ -0,0 +1,313 @@
+package outdated
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/ratelimit"
+)
+
+// ListReleaseDatesFunc is the function signature for looking up version publish timestamps.
+type ListReleaseDatesFunc func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (ReleaseDates, error)
+
+// ReleaseDates maps version strings to the time they were published.
+type ReleaseDates map[string]time.Time
+
+// VersionCandidate is an available version together with its publish timestamp.
+//
+// Fields:
+//   - Version: The version string as returned by the version lookup
+//   - Published: When the version was published; zero if the registry does not expose it
+type VersionCandidate struct {
+	Version   string
+	Published time.Time
+}
+
+// releaseTimeLayouts are the timestamp layouts accepted in release date output.
+var releaseTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ReleaseDatesConfigured reports whether the package's rule can look up release dates.
+//
+// Parameters:
+//   - p: The package to check
+//   - cfg: The global configuration
+//
+// Returns:
+//   - bool: true if the rule has outdated.release_dates commands configured
+func ReleaseDatesConfigured(p formats.Package, cfg *config.Config) bool {
+	if cfg == nil {
+		return false
+	}
+	ruleCfg, ok := cfg.Rules[p.Rule]
+	if !ok || ruleCfg.Outdated == nil || ruleCfg.Outdated.ReleaseDates == nil {
+		return false
+	}
+	return strings.TrimSpace(ruleCfg.Outdated.ReleaseDates.Commands) != ""
+}
+
+// ListReleaseDates runs the configured release date command for a package.
+//
+// The command runs in the same scope, environment, and placeholder context as
+// the outdated command. Rules without outdated.release_dates return nil dates
+// and no error, so callers can treat release dates as optional.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package to look up
+//   - cfg: The global configuration
+//   - baseDir: Base directory for command execution
+//
+// Returns:
+//   - ReleaseDates: Publish timestamps keyed by version; nil when not configured
+//   - error: When the command fails or its output cannot be parsed
+func ListReleaseDates(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (ReleaseDates, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("configuration is required")
+	}
+
+	outdatedCfg, err := resolveOutdatedCfg(p, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseCfg := outdatedCfg.ReleaseDates
+	if releaseCfg == nil || strings.TrimSpace(releaseCfg.Commands) == "" {
+		return nil, nil
+	}
+
+	timeout := releaseCfg.TimeoutSeconds
+	if cfg.NoTimeout {
+		timeout = 0
+	}
+
+	lookupCfg := &config.OutdatedCfg{
+		Commands:       releaseCfg.Commands,
+		Env:            outdatedCfg.Env,
+		TimeoutSeconds: timeout,
+	}
+
+	// Release date lookups hit the same registry as version lookups
+	if err := ratelimit.ForRule(cfg, p.Rule).Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	output, err := execOutdatedFunc(ctx, lookupCfg, p.Name, CurrentVersionForOutdated(p), p.Constraint, resolveOutdatedScope(p, cfg, baseDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up release dates: %w", err)
+	}
+
+	return parseReleaseDates(releaseCfg, output)
+}
+
+// parseReleaseDates extracts publish timestamps from release date command output.
+//
+// JSON output must resolve to an object mapping versions to timestamps (for
+// example `npm view <pkg> time --json`). Raw output is matched with a pattern
+// whose "version" and "published" named groups supply each entry. Entries with
+// timestamps that cannot be parsed are skipped.
+//
+// Parameters:
+//   - cfg: Release date configuration containing format and extraction settings
+//   - output: Raw command output bytes to parse
+//
+// Returns:
+//   - ReleaseDates: Parsed publish timestamps keyed by version
+//   - error: When the format is unsupported or the output cannot be parsed
+func parseReleaseDates(cfg *config.ReleaseDatesCfg, output []byte) (ReleaseDates, error) {
+	output = stripBOM(output)
+	dates := make(ReleaseDates)
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Format)) {
+	case "", "json":
+		var payload any
+		if err := json.Unmarshal(output, &payload); err != nil {
+			return nil, fmt.Errorf("failed to parse release dates JSON: %w", err)
+		}
+
+		node := payload
+		if cfg.JSONKey != "" {
+			for _, part := range strings.Split(cfg.JSONKey, ".") {
+				currentMap, ok := node.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("json key %s not found", cfg.JSONKey)
+				}
+				node = currentMap[part]
+			}
+		}
+
+		entries, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("release dates did not resolve to an object of version timestamps")
+		}
+
+		for version, value := range entries {
+			if published, ok := parseReleaseTime(fmt.Sprint(value)); ok {
+				dates[version] = published
+			}
+		}
+	case "raw":
+		re, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid release dates pattern: %w", err)
+		}
+
+		versionIdx, publishedIdx := re.SubexpIndex("version"), re.SubexpIndex("published")
+		if versionIdx < 0 || publishedIdx < 0 {
+			return nil, fmt.Errorf("release dates pattern requires version and published named groups")
+		}
+
+		for _, match := range re.FindAllStringSubmatch(string(output), -1) {
+			version := strings.TrimSpace(match[versionIdx])
+			if published, ok := parseReleaseTime(match[publishedIdx]); ok && version != "" {
+				dates[version] = published
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported release dates format: %s (supported: json, raw)", cfg.Format)
+	}
+
+	return dates, nil
+}
+
+// parseReleaseTime parses a publish timestamp in one of the accepted layouts.
+//
+// Parameters:
+//   - value: Timestamp string from command output
+//
+// Returns:
+//   - time.Time: The parsed timestamp
+//   - bool: false if the value matches none of the accepted layouts
+func parseReleaseTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range releaseTimeLayouts {
+		if published, err := time.Parse(layout, value); err == nil {
+			return published, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Candidates pairs versions with their publish timestamps.
+//
+// Versions without a known timestamp get a zero Published time. Order is preserved.
+//
+// Parameters:
+//   - versions: Versions returned by the version lookup
+//
+// Returns:
+//   - []VersionCandidate: One candidate per version
+func (d ReleaseDates) Candidates(versions []string) []VersionCandidate {
+	candidates := make([]VersionCandidate, 0, len(versions))
+	for _, v := range versions {
+		candidates = append(candidates, VersionCandidate{Version: v, Published: d[v]})
+	}
+	return candidates
+}
+
+// SupersededSince returns when the first version newer than current was published.
+//
+// Parameters:
+//   - current: installed version
+//
+// Returns:
+//   - time.Time: Earliest publish timestamp of a newer version
+//   - bool: false if current is not semver or no newer version has a known timestamp
+func (d ReleaseDates) SupersededSince(current string) (time.Time, bool) {
+	base := canonicalSemver(current)
+	if base == "" {
+		return time.Time{}, false
+	}
+	var earliest time.Time
+	for version, published := range d {
+		canonical := canonicalSemver(version)
+		if canonical == "" || published.IsZero() || semver.Compare(canonical, base) <= 0 {
+			continue
+		}
+		if earliest.IsZero() || published.Before(earliest) {
+			earliest = published
+		}
+	}
+	return earliest, !earliest.IsZero()
+}
+
+// FilterCandidatesByMinAge drops candidates published less than minAge before now.
+//
+// Candidates without a known publish timestamp are kept, since their age
+// cannot be judged. A non-positive minAge returns the candidates unchanged.
+//
+// Parameters:
+//   - candidates: Candidates to filter
+//   - minAge: Minimum time since publication
+//   - now: Reference time
+//
+// Returns:
+//   - []VersionCandidate: Candidates that are old enough or of unknown age
+func FilterCandidatesByMinAge(candidates []VersionCandidate, minAge time.Duration, now time.Time) []VersionCandidate {
+	if minAge <= 0 {
+		return candidates
+	}
+
+	kept := make([]VersionCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.Published.IsZero() || now.Sub(c.Published) >= minAge {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// SupersededAge returns how long ago the oldest known candidate was published.
+//
+// Candidates are versions newer than the installed one, so the oldest of them
+// marks when the installed version was first superseded.
+//
+// Parameters:
+//   - candidates: Newer version candidates
+//   - now: Reference time
+//
+// Returns:
+//   - time.Duration: Time since the earliest publish timestamp
+//   - bool: false if no candidate has a known publish timestamp
+func SupersededAge(candidates []VersionCandidate, now time.Time) (time.Duration, bool) {
+	var earliest time.Time
+	for _, c := range candidates {
+		if c.Published.IsZero() {
+			continue
+		}
+		if earliest.IsZero() || c.Published.Before(earliest) {
+			earliest = c.Published
+		}
+	}
+	if earliest.IsZero() {
+		return 0, false
+	}
+	return now.Sub(earliest), true
+}
+
+// CandidateVersions returns the version strings of the candidates.
+//
+// Parameters:
+//   - candidates: Candidates to convert
+//
+// Returns:
+//   - []string: Versions in candidate order
+func CandidateVersions(candidates []VersionCandidate) []string {
+	versions := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		versions = append(versions, c.Version)
+	}
+	return versions
+}
@@ -0,0 +1,190 @@
+package outdated
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestParseReleaseDates tests parsing of release date command output.
+//
+// It verifies:
+//   - JSON objects map versions to timestamps, skipping unparsable values
+//   - A json_key path selects a nested object
+//   - Raw output is matched with version and published named groups
+//   - Unsupported formats and patterns without named groups return errors
+func TestParseReleaseDates(t *testing.T) {
+	t.Run("json top-level object", func(t *testing.T) {
+		cfg := &config.ReleaseDatesCfg{Format: "json"}
+		output := []byte(`{"created":"2020-01-01T00:00:00.000Z","1.0.0":"2020-01-02T10:00:00.000Z","2.0.0":"2024-05-01T00:00:00Z","bad":"soon"}`)
+
+		dates, err := parseReleaseDates(cfg, output)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2020, 1, 2, 10, 0, 0, 0, time.UTC), dates["1.0.0"])
+		assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), dates["2.0.0"])
+		assert.NotContains(t, dates, "bad")
+	})
+
+	t.Run("json nested key", func(t *testing.T) {
+		cfg := &config.ReleaseDatesCfg{JSONKey: "data.time"}
+		output := []byte(`{"data":{"time":{"1.2.0":"2023-03-04"}}}`)
+
+		dates, err := parseReleaseDates(cfg, output)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 3, 4, 0, 0, 0, 0, time.UTC), dates["1.2.0"])
+	})
+
+	t.Run("json not an object", func(t *testing.T) {
+		_, err := parseReleaseDates(&config.ReleaseDatesCfg{}, []byte(`["1.0.0"]`))
+		assert.Error(t, err)
+	})
+
+	t.Run("raw pattern", func(t *testing.T) {
+		cfg := &config.ReleaseDatesCfg{
+			Format:  "raw",
+			Pattern: `(?m)^(?P<version>\S+) (?P<published>\S+)$`,
+		}
+		output := []byte("1.0.0 2021-06-01\n1.1.0 2021-07-01T12:00:00Z\n")
+
+		dates, err := parseReleaseDates(cfg, output)
+		require.NoError(t, err)
+		assert.Len(t, dates, 2)
+		assert.Equal(t, time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC), dates["1.1.0"])
+	})
+
+	t.Run("raw pattern without named groups", func(t *testing.T) {
+		cfg := &config.ReleaseDatesCfg{Format: "raw", Pattern: `(\S+) (\S+)`}
+		_, err := parseReleaseDates(cfg, []byte("1.0.0 2021-06-01"))
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := parseReleaseDates(&config.ReleaseDatesCfg{Format: "yaml"}, []byte("a: b"))
+		assert.Error(t, err)
+	})
+}
+
+// TestListReleaseDates tests running the release date lookup for a package.
+//
+// It verifies:
+//   - Rules without release_dates return nil dates and no error
+//   - The release date command runs with the package placeholders and timeout
+//   - --no-timeout clears the release date timeout
+//   - Command failures are wrapped with context
+func TestListReleaseDates(t *testing.T) {
+	originalFunc := execOutdatedFunc
+	t.Cleanup(func() { execOutdatedFunc = originalFunc })
+
+	newConfig := func(releaseDates *config.ReleaseDatesCfg) *config.Config {
+		return &config.Config{
+			Rules: map[string]config.PackageManagerCfg{
+				"npm": {
+					Outdated: &config.OutdatedCfg{
+						Commands:     "npm view {{package}} versions --json",
+						ReleaseDates: releaseDates,
+					},
+				},
+			},
+		}
+	}
+	pkg := formats.Package{Name: "left-pad", Rule: "npm", Version: "1.0.0", InstalledVersion: "1.0.0"}
+
+	t.Run("not configured", func(t *testing.T) {
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			t.Fatal("command should not run")
+			return nil, nil
+		}
+
+		dates, err := ListReleaseDates(context.Background(), pkg, newConfig(nil), ".")
+		require.NoError(t, err)
+		assert.Nil(t, dates)
+		assert.False(t, ReleaseDatesConfigured(pkg, newConfig(nil)))
+	})
+
+	t.Run("runs release date command", func(t *testing.T) {
+		var gotCfg *config.OutdatedCfg
+		var gotPkg string
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			gotCfg, gotPkg = cfg, pkg
+			return []byte(`{"1.1.0":"2024-01-01T00:00:00Z"}`), nil
+		}
+
+		cfg := newConfig(&config.ReleaseDatesCfg{Commands: "npm view {{package}} time --json", TimeoutSeconds: 15})
+		dates, err := ListReleaseDates(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+		assert.True(t, ReleaseDatesConfigured(pkg, cfg))
+		assert.Equal(t, "left-pad", gotPkg)
+		assert.Equal(t, "npm view {{package}} time --json", gotCfg.Commands)
+		assert.Equal(t, 15, gotCfg.TimeoutSeconds)
+		assert.Contains(t, dates, "1.1.0")
+
+		cfg.NoTimeout = true
+		_, err = ListReleaseDates(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+		assert.Equal(t, 0, gotCfg.TimeoutSeconds)
+	})
+
+	t.Run("command failure", func(t *testing.T) {
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			return nil, errors.New("registry unreachable")
+		}
+
+		cfg := newConfig(&config.ReleaseDatesCfg{Commands: "npm view {{package}} time --json"})
+		_, err := ListReleaseDates(context.Background(), pkg, cfg, ".")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to look up release dates")
+	})
+}
+
+// TestReleaseCandidates tests the release age helpers.
+//
+// It verifies:
+//   - Candidates carry publish timestamps, zero when unknown
+//   - FilterCandidatesByMinAge drops young releases and keeps unknown ones
+//   - SupersededAge reports the age of the oldest known release
+//   - SupersededSince finds the first release newer than the installed version
+func TestReleaseCandidates(t *testing.T) {
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	dates := ReleaseDates{
+		"1.1.0": now.AddDate(0, 0, -90),
+		"1.2.0": now.AddDate(0, 0, -20),
+		"1.3.0": now.AddDate(0, 0, -2),
+	}
+
+	candidates := dates.Candidates([]string{"1.1.0", "1.2.0", "1.3.0", "1.4.0"})
+	require.Len(t, candidates, 4)
+	assert.True(t, candidates[3].Published.IsZero())
+
+	t.Run("min age filter", func(t *testing.T) {
+		kept := FilterCandidatesByMinAge(candidates, 7*24*time.Hour, now)
+		assert.Equal(t, []string{"1.1.0", "1.2.0", "1.4.0"}, CandidateVersions(kept))
+		assert.Equal(t, candidates, FilterCandidatesByMinAge(candidates, 0, now))
+	})
+
+	t.Run("superseded age", func(t *testing.T) {
+		age, ok := SupersededAge(candidates, now)
+		require.True(t, ok)
+		assert.Equal(t, 90*24*time.Hour, age)
+
+		_, ok = SupersededAge(ReleaseDates{}.Candidates([]string{"2.0.0"}), now)
+		assert.False(t, ok)
+	})
+
+	t.Run("superseded since", func(t *testing.T) {
+		since, ok := dates.SupersededSince("1.1.0")
+		require.True(t, ok)
+		assert.Equal(t, now.AddDate(0, 0, -20), since)
+
+		_, ok = dates.SupersededSince("1.3.0")
+		assert.False(t, ok)
+		_, ok = dates.SupersededSince("latest")
+		assert.False(t, ok)
+	})
+}
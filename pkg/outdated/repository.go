@@ -0,0 +1,358 @@
+package outdated
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/ratelimit"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// ListRepositoryFunc is the function signature for looking up a package's source repository URL.
+type ListRepositoryFunc func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (string, error)
+
+// repositoryHosts are the code hosts whose URLs name a repository in their first two path segments.
+var repositoryHosts = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+	"codeberg.org":  true,
+}
+
+// goMajorSuffix matches the /vN major version suffix of a Go module path.
+var goMajorSuffix = regexp.MustCompile(`/v[0-9]+$`)
+
+// LookupRepository returns the source repository URL of a package.
+//
+// The lookup is best-effort: an empty URL with no error means the repository
+// is unknown. Go modules and GitHub Actions derive the URL from the package
+// name; other rules run their outdated.repository command.
+//
+// It performs the following operations:
+//   - Step 1: Run the rule's outdated.repository command when configured
+//   - Step 2: Otherwise derive the URL from a Go module path or an action's owner/repo
+//   - Step 3: Normalize the URL (see NormalizeRepositoryURL)
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package to look up
+//   - cfg: The global configuration
+//   - baseDir: Base directory for command execution
+//
+// Returns:
+//   - string: The repository URL; empty when unknown
+//   - error: When the command fails or its output cannot be parsed
+func LookupRepository(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (string, error) {
+	if cfg == nil {
+		return "", fmt.Errorf("configuration is required")
+	}
+
+	ruleCfg := cfg.Rules[p.Rule]
+	if ruleCfg.Outdated == nil || ruleCfg.Outdated.Repository == nil || ruleCfg.VersionSource != "" {
+		return repositoryFromName(p), nil
+	}
+
+	outdatedCfg, err := resolveOutdatedCfg(p, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	repositoryCfg := outdatedCfg.Repository
+	if repositoryCfg == nil || strings.TrimSpace(repositoryCfg.Commands) == "" {
+		return repositoryFromName(p), nil
+	}
+
+	timeout := repositoryCfg.TimeoutSeconds
+	if cfg.NoTimeout {
+		timeout = 0
+	}
+
+	lookupCfg := &config.OutdatedCfg{
+		Commands:       repositoryCfg.Commands,
+		Env:            outdatedCfg.Env,
+		TimeoutSeconds: timeout,
+	}
+
+	// Repository lookups hit the same registry as version lookups
+	if err := ratelimit.ForRule(cfg, p.Rule).Wait(ctx); err != nil {
+		return "", err
+	}
+
+	output, err := execOutdatedFunc(ctx, lookupCfg, p.Name, CurrentVersionForOutdated(p), p.Constraint, resolveOutdatedScope(p, cfg, baseDir))
+	if err != nil {
+		return "", fmt.Errorf("failed to look up repository: %w", err)
+	}
+
+	return parseRepository(repositoryCfg, output)
+}
+
+// parseRepository extracts the repository URL from repository command output.
+//
+// JSON output is read at each of JSONKeys in turn; a value is either the URL
+// itself or an object with a "url" field (for example npm's repository
+// object). Raw output is matched with a pattern whose "url" group holds the
+// URL. Values that do not normalize to an http(s) URL are skipped.
+//
+// Parameters:
+//   - cfg: Repository configuration containing format and extraction settings
+//   - output: Raw command output bytes to parse
+//
+// Returns:
+//   - string: The normalized repository URL; empty when none was found
+//   - error: When the format is unsupported or the output cannot be parsed
+func parseRepository(cfg *config.RepositoryCfg, output []byte) (string, error) {
+	output = stripBOM(output)
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Format)) {
+	case "", "json":
+		if len(strings.TrimSpace(string(output))) == 0 {
+			return "", nil
+		}
+		var payload any
+		if err := json.Unmarshal(output, &payload); err != nil {
+			return "", fmt.Errorf("failed to parse repository JSON: %w", err)
+		}
+
+		keys := cfg.JSONKeys
+		if len(keys) == 0 {
+			keys = []string{""}
+		}
+		for _, key := range keys {
+			if url := NormalizeRepositoryURL(repositoryValue(jsonPath(payload, key))); url != "" {
+				return url, nil
+			}
+		}
+	case "raw":
+		re, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid repository pattern: %w", err)
+		}
+
+		urlIdx := re.SubexpIndex("url")
+		if urlIdx < 0 {
+			return "", fmt.Errorf("repository pattern requires a url named group")
+		}
+
+		for _, match := range re.FindAllStringSubmatch(string(output), -1) {
+			if url := NormalizeRepositoryURL(match[urlIdx]); url != "" {
+				return url, nil
+			}
+		}
+	default:
+		return "", fmt.Errorf("unsupported repository format: %s (supported: json, raw)", cfg.Format)
+	}
+
+	return "", nil
+}
+
+// jsonPath walks a dot-separated path through decoded JSON objects.
+//
+// Parameters:
+//   - node: Decoded JSON value
+//   - path: Dot-separated object keys; empty returns node
+//
+// Returns:
+//   - any: The value at path, or nil when a key is missing
+func jsonPath(node any, path string) any {
+	if path == "" {
+		return node
+	}
+	for _, part := range strings.Split(path, ".") {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = object[part]
+	}
+	return node
+}
+
+// repositoryValue returns the URL held by a JSON value.
+//
+// Parameters:
+//   - value: A string, or an object with a "url" string field
+//
+// Returns:
+//   - string: The URL; empty for other values
+func repositoryValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if url, ok := v["url"].(string); ok {
+			return url
+		}
+	}
+	return ""
+}
+
+// NormalizeRepositoryURL turns a repository reference into a browsable https URL.
+//
+// Registry metadata spells repositories in several ways; git+https, git://
+// and scp-style SSH URLs, GitHub shorthands and trailing ".git" all map to the
+// same https URL. URLs on known code hosts are trimmed to the repository.
+//
+// Parameters:
+//   - raw: The repository reference from registry metadata
+//
+// Returns:
+//   - string: The https URL, or empty when raw is not a repository URL
+//
+// Example:
+//
+//	outdated.NormalizeRepositoryURL("git+ssh://git@github.com/facebook/react.git") // "https://github.com/facebook/react"
+func NormalizeRepositoryURL(raw string) string {
+	url := strings.TrimSpace(raw)
+	if i := strings.IndexAny(url, "#?"); i >= 0 {
+		url = url[:i]
+	}
+	url = strings.TrimPrefix(url, "git+")
+
+	switch {
+	case strings.HasPrefix(url, "github:"):
+		url = "https://github.com/" + strings.TrimPrefix(url, "github:")
+	case strings.HasPrefix(url, "gitlab:"):
+		url = "https://gitlab.com/" + strings.TrimPrefix(url, "gitlab:")
+	case strings.HasPrefix(url, "bitbucket:"):
+		url = "https://bitbucket.org/" + strings.TrimPrefix(url, "bitbucket:")
+	case strings.HasPrefix(url, "git@"):
+		// scp-style git@host:owner/repo
+		url = "https://" + strings.Replace(strings.TrimPrefix(url, "git@"), ":", "/", 1)
+	}
+	for _, scheme := range []string{"ssh://git@", "ssh://", "git://", "http://"} {
+		if strings.HasPrefix(url, scheme) {
+			url = "https://" + strings.TrimPrefix(url, scheme)
+			break
+		}
+	}
+
+	path, ok := strings.CutPrefix(url, "https://")
+	if !ok {
+		return ""
+	}
+	path = strings.TrimSuffix(strings.TrimRight(path, "/"), ".git")
+
+	parts := strings.Split(path, "/")
+	if parts[0] == "" {
+		return ""
+	}
+	url = "https://" + path
+	if repositoryHosts[strings.ToLower(parts[0])] && len(parts) > 3 {
+		url = "https://" + strings.Join(parts[:3], "/")
+	}
+	return url
+}
+
+// repositoryFromName derives the repository URL of packages named after it.
+//
+// Parameters:
+//   - p: The package; Go modules and GitHub Actions are supported
+//
+// Returns:
+//   - string: The repository URL; empty for other packages
+func repositoryFromName(p formats.Package) string {
+	switch p.PackageType {
+	case "golang":
+		path := goMajorSuffix.ReplaceAllString(p.Name, "")
+		if !strings.Contains(path, "/") {
+			return ""
+		}
+		return NormalizeRepositoryURL("https://" + path)
+	case "github-actions":
+		return NormalizeRepositoryURL("https://github.com/" + p.Name)
+	}
+	return ""
+}
+
+// RepositoryIndex collects the repository URLs found during version lookups.
+//
+// It is safe for concurrent use, since lookups run on a worker pool.
+type RepositoryIndex struct {
+	mu   sync.Mutex
+	urls map[string]string
+}
+
+// NewRepositoryIndex creates an empty repository index.
+//
+// Returns:
+//   - *RepositoryIndex: Index to pass to WithRepository
+func NewRepositoryIndex() *RepositoryIndex {
+	return &RepositoryIndex{urls: make(map[string]string)}
+}
+
+// Get returns the repository URL recorded for a package.
+//
+// Parameters:
+//   - p: The package; packages of one rule with the same name share a URL
+//
+// Returns:
+//   - string: The repository URL; empty when unknown or the index is nil
+func (r *RepositoryIndex) Get(p formats.Package) string {
+	if r == nil {
+		return ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.urls[repositoryKey(p)]
+}
+
+// lookup reports whether a package was looked up and the URL recorded for it.
+func (r *RepositoryIndex) lookup(p formats.Package) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	url, ok := r.urls[repositoryKey(p)]
+	return url, ok
+}
+
+// put records the repository URL of a package.
+func (r *RepositoryIndex) put(p formats.Package, url string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.urls[repositoryKey(p)] = url
+}
+
+// repositoryKey identifies a package in a RepositoryIndex.
+func repositoryKey(p formats.Package) string {
+	return p.Rule + "\x00" + p.Name
+}
+
+// WithRepository wraps a version lister to look up each package's repository URL.
+//
+// The repository is looked up once per rule and package name, after the
+// version lookup succeeded, and recorded in index. Lookup failures are only
+// logged, since the repository is informational.
+//
+// Parameters:
+//   - fn: The version lister to wrap
+//   - lookup: The repository lookup, usually LookupRepository
+//   - index: Index receiving the URLs; nil returns fn unchanged
+//
+// Returns:
+//   - ListNewerVersionsFunc: fn, recording repository URLs as a side effect
+func WithRepository(fn ListNewerVersionsFunc, lookup ListRepositoryFunc, index *RepositoryIndex) ListNewerVersionsFunc {
+	if index == nil || lookup == nil {
+		return fn
+	}
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		versions, err := fn(ctx, p, cfg, baseDir)
+		if err != nil {
+			return versions, err
+		}
+		if _, done := index.lookup(p); done {
+			return versions, nil
+		}
+
+		url, lookupErr := lookup(ctx, p, cfg, baseDir)
+		if lookupErr != nil {
+			verbose.Printf("Repository lookup for %s failed: %v\n", p.Name, lookupErr)
+		}
+		index.put(p, url)
+		return versions, nil
+	}
+}
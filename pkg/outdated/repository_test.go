@@ -0,0 +1,204 @@
+package outdated
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestNormalizeRepositoryURL tests turning repository references into https URLs.
+//
+// It verifies:
+//   - git+, git://, ssh and scp-style URLs and host shorthands become https URLs
+//   - ".git", fragments and paths inside a code host repository are dropped
+//   - Values that are not URLs normalize to empty
+func TestNormalizeRepositoryURL(t *testing.T) {
+	tests := map[string]string{
+		"git+https://github.com/facebook/react.git":              "https://github.com/facebook/react",
+		"git+ssh://git@github.com/lodash/lodash.git":             "https://github.com/lodash/lodash",
+		"git://github.com/expressjs/express.git":                 "https://github.com/expressjs/express",
+		"git@gitlab.com:group/project.git":                       "https://gitlab.com/group/project",
+		"github:vercel/next.js":                                  "https://github.com/vercel/next.js",
+		"https://github.com/babel/babel/tree/main/packages/core": "https://github.com/babel/babel",
+		"https://github.com/psf/requests#readme":                 "https://github.com/psf/requests",
+		"http://svn.example.org/project/":                        "https://svn.example.org/project",
+		"https://golang.org/x/mod":                               "https://golang.org/x/mod",
+		"":                                                       "",
+		"facebook/react":                                         "",
+		"https://":                                               "",
+	}
+
+	for raw, want := range tests {
+		assert.Equal(t, want, NormalizeRepositoryURL(raw), raw)
+	}
+}
+
+// TestParseRepository tests extracting repository URLs from command output.
+//
+// It verifies:
+//   - npm's repository object and string forms are read from the top level
+//   - json_keys are tried in order and skip values that are not URLs
+//   - Raw output is matched with the url group
+//   - Empty output is an unknown repository; bad JSON and formats are errors
+func TestParseRepository(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    config.RepositoryCfg
+		output string
+		want   string
+	}{
+		{"npm object", config.RepositoryCfg{}, `{"type":"git","url":"git+https://github.com/facebook/react.git","directory":"packages/react"}`, "https://github.com/facebook/react"},
+		{"npm string", config.RepositoryCfg{Format: "json"}, `"github:lodash/lodash"`, "https://github.com/lodash/lodash"},
+		{"composer keys", config.RepositoryCfg{JSONKeys: []string{"support.source", "source.url"}}, `{"support":{"source":"not a url"},"source":{"type":"git","url":"https://github.com/symfony/console.git"}}`, "https://github.com/symfony/console"},
+		{"pypi keys", config.RepositoryCfg{JSONKeys: []string{"info.project_urls.Source", "info.project_urls.Source Code"}}, `{"info":{"project_urls":{"Source Code":"https://github.com/django/django"}}}`, "https://github.com/django/django"},
+		{"no match", config.RepositoryCfg{JSONKeys: []string{"repository"}}, `{"homepage":"https://example.org"}`, ""},
+		{"empty output", config.RepositoryCfg{}, "  \n", ""},
+		{"raw", config.RepositoryCfg{Format: "raw", Pattern: `source_code_uri: (?P<url>\S+)`}, "name: rails\nsource_code_uri: https://github.com/rails/rails/tree/v7.1.0\n", "https://github.com/rails/rails"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRepository(&tt.cfg, []byte(tt.output))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := parseRepository(&config.RepositoryCfg{}, []byte("{"))
+	assert.ErrorContains(t, err, "failed to parse repository JSON")
+	_, err = parseRepository(&config.RepositoryCfg{Format: "raw", Pattern: `(\S+)`}, []byte("x"))
+	assert.ErrorContains(t, err, "url named group")
+	_, err = parseRepository(&config.RepositoryCfg{Format: "yaml"}, []byte("x"))
+	assert.ErrorContains(t, err, "unsupported repository format")
+}
+
+// TestLookupRepository tests looking up a package's repository URL.
+//
+// It verifies:
+//   - The configured command runs with the package placeholders and timeout
+//   - Go modules and GitHub Actions derive the URL from their name without a command
+//   - Rules without a repository lookup or with an offline version source report no URL
+//   - Command failures are returned
+func TestLookupRepository(t *testing.T) {
+	originalFunc := execOutdatedFunc
+	t.Cleanup(func() { execOutdatedFunc = originalFunc })
+
+	cfg := &config.Config{
+		Rules: map[string]config.PackageManagerCfg{
+			"npm": {Outdated: &config.OutdatedCfg{
+				Commands:   "npm view {{package}} versions --json",
+				Repository: &config.RepositoryCfg{Commands: "npm view {{package}} repository --json", TimeoutSeconds: 15},
+			}},
+			"offline": {VersionSource: "versions.json", Outdated: &config.OutdatedCfg{
+				Repository: &config.RepositoryCfg{Commands: "npm view {{package}} repository --json"},
+			}},
+			"mod":     {Outdated: &config.OutdatedCfg{Commands: "go list -m -json -versions {{package}}"}},
+			"actions": {},
+			"pip":     {Outdated: &config.OutdatedCfg{Commands: "curl {{package}}"}},
+		},
+	}
+
+	t.Run("runs repository command", func(t *testing.T) {
+		var gotCfg *config.OutdatedCfg
+		var gotPkg string
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			gotCfg, gotPkg = cfg, pkg
+			return []byte(`{"type":"git","url":"git+https://github.com/facebook/react.git"}`), nil
+		}
+
+		url, err := LookupRepository(context.Background(), formats.Package{Name: "react", Rule: "npm", PackageType: "js"}, cfg, ".")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/facebook/react", url)
+		assert.Equal(t, "react", gotPkg)
+		assert.Equal(t, "npm view {{package}} repository --json", gotCfg.Commands)
+		assert.Equal(t, 15, gotCfg.TimeoutSeconds)
+	})
+
+	t.Run("derived from name", func(t *testing.T) {
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			t.Fatal("command should not run")
+			return nil, nil
+		}
+
+		for _, tt := range []struct {
+			pkg  formats.Package
+			want string
+		}{
+			{formats.Package{Name: "github.com/spf13/cobra", Rule: "mod", PackageType: "golang"}, "https://github.com/spf13/cobra"},
+			{formats.Package{Name: "github.com/jackc/pgx/v5", Rule: "mod", PackageType: "golang"}, "https://github.com/jackc/pgx"},
+			{formats.Package{Name: "golang.org/x/mod", Rule: "mod", PackageType: "golang"}, "https://golang.org/x/mod"},
+			{formats.Package{Name: "actions/cache/save", Rule: "actions", PackageType: "github-actions"}, "https://github.com/actions/cache"},
+			{formats.Package{Name: "requests", Rule: "pip", PackageType: "python"}, ""},
+			{formats.Package{Name: "left-pad", Rule: "offline", PackageType: "js"}, ""},
+		} {
+			url, err := LookupRepository(context.Background(), tt.pkg, cfg, ".")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, url, tt.pkg.Name)
+		}
+	})
+
+	t.Run("command failure", func(t *testing.T) {
+		execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+			return nil, errors.New("E404")
+		}
+
+		_, err := LookupRepository(context.Background(), formats.Package{Name: "react", Rule: "npm", PackageType: "js"}, cfg, ".")
+		assert.ErrorContains(t, err, "failed to look up repository: E404")
+	})
+
+	_, err := LookupRepository(context.Background(), formats.Package{}, nil, ".")
+	assert.Error(t, err)
+}
+
+// TestWithRepository tests recording repository URLs during version lookups.
+//
+// It verifies:
+//   - Each rule and package name is looked up once
+//   - Failed version lookups and failed repository lookups record nothing useful
+//   - A nil index returns the lister unchanged
+func TestWithRepository(t *testing.T) {
+	lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Name == "broken" {
+			return nil, errors.New("lookup failed")
+		}
+		return []string{"2.0.0"}, nil
+	}
+	var calls atomic.Int32
+	lookup := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (string, error) {
+		calls.Add(1)
+		if p.Name == "private" {
+			return "", errors.New("E403")
+		}
+		return "https://github.com/example/" + p.Name, nil
+	}
+
+	index := NewRepositoryIndex()
+	wrapped := WithRepository(lister, lookup, index)
+	react := formats.Package{Name: "react", Rule: "npm", Source: "a/package.json"}
+	for _, p := range []formats.Package{react, {Name: "react", Rule: "npm", Source: "b/package.json"}, {Name: "private", Rule: "npm"}} {
+		versions, err := wrapped(context.Background(), p, nil, ".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2.0.0"}, versions)
+	}
+	_, err := wrapped(context.Background(), formats.Package{Name: "broken", Rule: "npm"}, nil, ".")
+	assert.Error(t, err)
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "https://github.com/example/react", index.Get(react))
+	assert.Empty(t, index.Get(formats.Package{Name: "react", Rule: "yarn"}))
+	assert.Empty(t, index.Get(formats.Package{Name: "private", Rule: "npm"}))
+	assert.Empty(t, index.Get(formats.Package{Name: "broken", Rule: "npm"}))
+
+	var nilIndex *RepositoryIndex
+	assert.Empty(t, nilIndex.Get(react))
+	unwrapped := WithRepository(lister, lookup, nil)
+	_, _ = unwrapped(context.Background(), react, nil, ".")
+	assert.Equal(t, int32(2), calls.Load())
+}
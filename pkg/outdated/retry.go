@@ -0,0 +1,368 @@
+package outdated
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/ratelimit"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// ListNewerVersionsFunc is the function signature for listing newer versions of a package.
+type ListNewerVersionsFunc func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error)
+
+// maxRetryBackoff caps the delay between two attempts regardless of the attempt count.
+const maxRetryBackoff = 30 * time.Second
+
+// rateLimitedRetries is the number of retries for a rate-limited (429) lookup on a
+// rule with max_requests_per_second when the rule configures no retries itself.
+const rateLimitedRetries = 3
+
+// minRateLimitedBackoff is the smallest base delay used to retry a rate-limited lookup.
+const minRateLimitedBackoff = time.Second
+
+// retrySleepFunc waits for the given duration or until the context is done.
+// It is a variable so tests can avoid real sleeps.
+var retrySleepFunc = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryJitterFunc returns a random jitter in the range [0, max).
+// It is a variable so tests can make backoff delays deterministic.
+var retryJitterFunc = func(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// nonRetryableMarkers are error substrings that indicate a permanent failure.
+// These are checked before transient markers so "not found" fails fast.
+var nonRetryableMarkers = []string{
+	"not found",
+	"no such package",
+	"does not exist",
+	"no matching version",
+	"unknown revision",
+}
+
+// nonRetryableStatuses are HTTP statuses that indicate a permanent failure.
+var nonRetryableStatuses = []string{"404"}
+
+// transientMarkers are error substrings that indicate a temporary failure worth retrying.
+var transientMarkers = []string{
+	"timed out",
+	"timeout",
+	"connection reset",
+	"connection refused",
+	"temporary failure",
+	"try again",
+	"etimedout",
+	"econnreset",
+	"econnrefused",
+	"eai_again",
+	"network is unreachable",
+	"tls handshake",
+	"unexpected eof",
+	"too many requests",
+	"bad gateway",
+	"service unavailable",
+	"gateway timeout",
+}
+
+// transientStatuses are HTTP statuses that indicate a temporary failure worth retrying.
+var transientStatuses = []string{"429", "500", "502", "503", "504"}
+
+// rateLimitMarkers are error substrings that indicate the registry throttled the request.
+var rateLimitMarkers = []string{
+	"too many requests",
+	"rate limit",
+}
+
+// httpStatusPattern matches a three-digit code that is shaped like an HTTP status:
+// "HTTP 503", "HTTP/2 429", "status 404", "status code: 500", "error: 502", "(504)",
+// npm's "npm ERR! 404", or npm's "E404". The code is captured in one of the groups.
+// Bare digits inside a version, hash, port, or package name are not statuses.
+var httpStatusPattern = regexp.MustCompile(`(?i)(?:\bhttp(?:/[\d.]+)?|\bstatus(?:\s+code)?|\bcode|\berror|\berr!|\bresponse)[\s:=]*(\d{3})\b|\be(\d{3})\b|\((\d{3})\)`)
+
+// hasHTTPStatus reports whether message contains one of codes shaped like an HTTP status.
+//
+// Parameters:
+//   - message: The error message to search
+//   - codes: The status codes to look for
+//
+// Returns:
+//   - bool: true if any status-shaped code in message is one of codes
+func hasHTTPStatus(message string, codes []string) bool {
+	for _, match := range httpStatusPattern.FindAllStringSubmatch(message, -1) {
+		for _, group := range match[1:] {
+			for _, code := range codes {
+				if group == code {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// WithRetry wraps a version lister so transient failures are retried.
+//
+// The number of retries and the base backoff are read from the package's
+// effective outdated configuration (rule settings plus package overrides).
+// Each retry waits for the base backoff doubled per attempt, plus jitter of up
+// to half the delay, capped at 30 seconds. Errors that are not transient fail
+// fast without retrying, as does context cancellation. On a rule with
+// max_requests_per_second, a rate-limited (429) lookup is retried even when
+// the rule configures no retries, backing off at least one second.
+//
+// Parameters:
+//   - fn: The version lister to wrap
+//
+// Returns:
+//   - ListNewerVersionsFunc: A lister with the same signature that retries transient errors
+func WithRetry(fn ListNewerVersionsFunc) ListNewerVersionsFunc {
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		retries, backoff := resolveRetryPolicy(p, cfg)
+
+		versions, err := fn(ctx, p, cfg, baseDir)
+		if retries == 0 && IsRateLimitError(err) {
+			retries, backoff = rateLimitedRetryPolicy(p, cfg)
+		}
+		for attempt := 1; err != nil && attempt <= retries; attempt++ {
+			if !(IsRetryableError(err) || IsRateLimitError(err)) || ctx.Err() != nil {
+				return nil, err
+			}
+
+			delay := retryDelay(backoff, attempt)
+			verbose.Infof("Version lookup for %s failed (%v), retry %d/%d in %s", p.Name, err, attempt, retries, delay)
+
+			if sleepErr := retrySleepFunc(ctx, delay); sleepErr != nil {
+				return nil, err
+			}
+
+			versions, err = fn(ctx, p, cfg, baseDir)
+			if err == nil {
+				verbose.Infof("Version lookup for %s succeeded after %d retries", p.Name, attempt)
+			} else if attempt == retries {
+				verbose.Infof("Version lookup for %s failed after %d retries", p.Name, retries)
+			}
+		}
+
+		return versions, err
+	}
+}
+
+// WithRateLimit wraps a version lister so each call waits for the rule's rate limiter.
+//
+// Rules without max_requests_per_second are not throttled. The limiter is shared
+// by every worker, so it caps sustained throughput regardless of concurrency.
+//
+// Parameters:
+//   - fn: The version lister to wrap
+//
+// Returns:
+//   - ListNewerVersionsFunc: A lister with the same signature that respects the rule's rate limit
+func WithRateLimit(fn ListNewerVersionsFunc) ListNewerVersionsFunc {
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if err := ratelimit.ForRule(cfg, p.Rule).Wait(ctx); err != nil {
+			return nil, err
+		}
+		return fn(ctx, p, cfg, baseDir)
+	}
+}
+
+// ListNewerVersionsWithRetry lists newer versions and retries transient failures.
+//
+// This is ListNewerVersions wrapped with WithRateLimit and WithRetry, using the
+// rate limit and retry settings configured on the package's rule. Every attempt,
+// including retries, waits for the rate limiter.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package to check
+//   - cfg: The global configuration
+//   - baseDir: Base directory for command execution
+//
+// Returns:
+//   - []string: Newer versions available for the package
+//   - error: The last error encountered when all attempts fail
+func ListNewerVersionsWithRetry(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+	return WithRetry(WithRateLimit(ListNewerVersions))(ctx, p, cfg, baseDir)
+}
+
+// ListAllVersionsWithRetry lists every version of a package and retries transient failures.
+//
+// This is ListAllVersions wrapped with WithRateLimit and WithRetry, like
+// ListNewerVersionsWithRetry.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package to check
+//   - cfg: The global configuration
+//   - baseDir: Base directory for command execution
+//
+// Returns:
+//   - []string: All versions the registry lists for the package
+//   - error: The last error encountered when all attempts fail
+func ListAllVersionsWithRetry(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+	return WithRetry(WithRateLimit(ListAllVersions))(ctx, p, cfg, baseDir)
+}
+
+// IsRetryableError reports whether a version lookup error is likely transient.
+//
+// Unsupported errors, context cancellation, and errors that look like a
+// missing package are never retried. Other errors are retried only when they
+// match a known transient pattern such as a timeout or a 5xx response.
+//
+// Parameters:
+//   - err: The error to classify
+//
+// Returns:
+//   - bool: true if retrying the lookup may succeed
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.IsUnsupported(err) {
+		return false
+	}
+
+	if stderrors.Is(err, context.Canceled) {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, marker := range nonRetryableMarkers {
+		if strings.Contains(message, marker) {
+			return false
+		}
+	}
+	if hasHTTPStatus(message, nonRetryableStatuses) {
+		return false
+	}
+
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	for _, marker := range transientMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+
+	return hasHTTPStatus(message, transientStatuses)
+}
+
+// IsRateLimitError reports whether a version lookup error means the registry throttled the request.
+//
+// Parameters:
+//   - err: The error to classify
+//
+// Returns:
+//   - bool: true if the error has an HTTP 429 status or a rate limit message
+func IsRateLimitError(err error) bool {
+	if err == nil || errors.IsUnsupported(err) {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, marker := range rateLimitMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return hasHTTPStatus(message, []string{"429"})
+}
+
+// rateLimitedRetryPolicy returns the retry policy for a rate-limited lookup.
+//
+// Parameters:
+//   - p: The package being checked
+//   - cfg: The global configuration
+//
+// Returns:
+//   - int: rateLimitedRetries when the rule has max_requests_per_second, 0 otherwise
+//   - time.Duration: Base backoff of one request interval, at least minRateLimitedBackoff
+func rateLimitedRetryPolicy(p formats.Package, cfg *config.Config) (int, time.Duration) {
+	limiter := ratelimit.ForRule(cfg, p.Rule)
+	if limiter == nil {
+		return 0, 0
+	}
+
+	backoff := limiter.Interval()
+	if backoff < minRateLimitedBackoff {
+		backoff = minRateLimitedBackoff
+	}
+	return rateLimitedRetries, backoff
+}
+
+// resolveRetryPolicy returns the retry count and base backoff for a package.
+//
+// Parameters:
+//   - p: The package being checked
+//   - cfg: The global configuration
+//
+// Returns:
+//   - int: Number of retries (0 when not configured or config cannot be resolved)
+//   - time.Duration: Base backoff delay before the first retry
+func resolveRetryPolicy(p formats.Package, cfg *config.Config) (int, time.Duration) {
+	if cfg == nil {
+		return 0, 0
+	}
+
+	outdatedCfg, err := resolveOutdatedCfg(p, cfg)
+	if err != nil || outdatedCfg.Retries <= 0 {
+		return 0, 0
+	}
+
+	backoff := time.Duration(outdatedCfg.RetryBackoffMs) * time.Millisecond
+	if backoff < 0 {
+		backoff = 0
+	}
+
+	return outdatedCfg.Retries, backoff
+}
+
+// retryDelay computes the exponential backoff delay for a retry attempt.
+//
+// Parameters:
+//   - base: The base delay before the first retry
+//   - attempt: The 1-based retry attempt number
+//
+// Returns:
+//   - time.Duration: base * 2^(attempt-1) plus jitter, capped at maxRetryBackoff
+func retryDelay(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+
+	delay += retryJitterFunc(delay / 2)
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+
+	return delay
+}
@@ -0,0 +1,390 @@
+package outdated
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	pkgerrors "github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// stubRetrySleep replaces the retry sleep and jitter functions for the duration of a test.
+// It returns a pointer to the recorded delays.
+func stubRetrySleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+
+	origSleep := retrySleepFunc
+	origJitter := retryJitterFunc
+	t.Cleanup(func() {
+		retrySleepFunc = origSleep
+		retryJitterFunc = origJitter
+	})
+
+	var delays []time.Duration
+	retrySleepFunc = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	retryJitterFunc = func(time.Duration) time.Duration { return 0 }
+
+	return &delays
+}
+
+// retryTestConfig builds a config with a single npm rule using the given retry settings.
+func retryTestConfig(retries, backoffMs int) *config.Config {
+	return &config.Config{
+		Rules: map[string]config.PackageManagerCfg{
+			"npm": {
+				Outdated: &config.OutdatedCfg{
+					Commands:       "npm view {{package}} versions --json",
+					Retries:        retries,
+					RetryBackoffMs: backoffMs,
+				},
+			},
+		},
+	}
+}
+
+// TestWithRetry tests the behavior of WithRetry.
+//
+// It verifies:
+//   - Transient errors are retried until success
+//   - Backoff doubles on each attempt
+//   - Non-retryable errors fail fast
+//   - Retries stop once the configured count is exhausted
+//   - No retries happen when retries is not configured
+func TestWithRetry(t *testing.T) {
+	pkg := formats.Package{Name: "lodash", Rule: "npm"}
+
+	t.Run("retries transient errors until success", func(t *testing.T) {
+		delays := stubRetrySleep(t)
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			if calls < 3 {
+				return nil, fmt.Errorf("request failed: ETIMEDOUT")
+			}
+			return []string{"1.0.1"}, nil
+		})
+
+		versions, err := lister(context.Background(), pkg, retryTestConfig(3, 100), ".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.0.1"}, versions)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *delays)
+	})
+
+	t.Run("non-retryable errors fail fast", func(t *testing.T) {
+		delays := stubRetrySleep(t)
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return nil, fmt.Errorf("npm ERR! 404 package not found")
+		})
+
+		_, err := lister(context.Background(), pkg, retryTestConfig(3, 100), ".")
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("gives up after configured retries", func(t *testing.T) {
+		stubRetrySleep(t)
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return nil, fmt.Errorf("503 service unavailable")
+		})
+
+		_, err := lister(context.Background(), pkg, retryTestConfig(2, 10), ".")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("no retries when not configured", func(t *testing.T) {
+		stubRetrySleep(t)
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return nil, fmt.Errorf("connection reset by peer")
+		})
+
+		_, err := lister(context.Background(), pkg, retryTestConfig(0, 0), ".")
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("package override retries take precedence", func(t *testing.T) {
+		stubRetrySleep(t)
+		cfg := retryTestConfig(0, 0)
+		retries := 1
+		rule := cfg.Rules["npm"]
+		rule.PackageOverrides = map[string]config.PackageOverrideCfg{
+			"lodash": {Outdated: &config.OutdatedOverrideCfg{Retries: &retries}},
+		}
+		cfg.Rules["npm"] = rule
+
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return nil, fmt.Errorf("connection reset by peer")
+		})
+
+		_, err := lister(context.Background(), pkg, cfg, ".")
+		require.Error(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("stops when context is cancelled", func(t *testing.T) {
+		stubRetrySleep(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			cancel()
+			return nil, fmt.Errorf("request timeout")
+		})
+
+		_, err := lister(ctx, pkg, retryTestConfig(5, 10), ".")
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("reports retry count in verbose output", func(t *testing.T) {
+		stubRetrySleep(t)
+		var buf bytes.Buffer
+		verbose.Enable()
+		verbose.SetWriter(&buf)
+		t.Cleanup(func() {
+			verbose.Disable()
+			verbose.SetWriter(os.Stderr)
+		})
+
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			if calls == 1 {
+				return nil, fmt.Errorf("502 bad gateway")
+			}
+			return []string{"2.0.0"}, nil
+		})
+
+		_, err := lister(context.Background(), pkg, retryTestConfig(2, 10), ".")
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "retry 1/2")
+		assert.Contains(t, buf.String(), "succeeded after 1 retries")
+	})
+}
+
+// TestIsRetryableError tests the behavior of IsRetryableError.
+//
+// It verifies:
+//   - Transient network and registry errors are retryable
+//   - Not-found, unsupported, and cancellation errors are not retryable
+//   - Status codes are only matched when shaped like an HTTP status
+//   - Unknown errors are not retryable
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"timeout", errors.New("command timed out after 30 seconds"), true},
+		{"connection reset", errors.New("read: connection reset by peer"), true},
+		{"dns", errors.New("getaddrinfo EAI_AGAIN registry.npmjs.org"), true},
+		{"rate limit", errors.New("429 Too Many Requests"), true},
+		{"deadline", fmt.Errorf("lookup: %w", context.DeadlineExceeded), true},
+		{"not found", errors.New("npm ERR! 404 Not Found - lodashh"), false},
+		{"missing package", errors.New("package does not exist"), false},
+		{"unsupported", &pkgerrors.UnsupportedError{Reason: "timeout"}, false},
+		{"cancelled", fmt.Errorf("lookup: %w", context.Canceled), false},
+		{"parse error", errors.New("failed to parse versions"), false},
+		{"http 503", errors.New("unexpected HTTP 503 from registry"), true},
+		{"status code 500", errors.New("GET https://proxy.golang.org/x: status code: 500"), true},
+		{"parenthesized 504", errors.New("request failed (504)"), true},
+		{"npm E502", errors.New("npm ERR! code E502"), true},
+		{"status 404", errors.New("GET https://pypi.org/simple/x: status 404"), false},
+		{"npm E404", errors.New("npm ERR! code E404"), false},
+		{"timeout naming 404 version", errors.New("command timed out fetching pkg@2.404.0"), true},
+		{"timeout naming 404 hash", errors.New("timeout resolving h1:ab404cd"), true},
+		{"500 in version", errors.New("failed to parse version 5.0.500"), false},
+		{"502 in hash", errors.New("checksum mismatch: h1:e502cd9f"), false},
+		{"503 in port", errors.New("dial tcp 10.0.0.1:5030: permission denied"), false},
+		{"504 in package name", errors.New("exit status 1: package lib-504 failed"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryableError(tt.err))
+		})
+	}
+}
+
+// TestRetryDelay tests the behavior of retryDelay.
+//
+// It verifies:
+//   - Zero base disables waiting
+//   - Delay doubles per attempt
+//   - Delay is capped at maxRetryBackoff
+func TestRetryDelay(t *testing.T) {
+	stubRetrySleep(t)
+
+	assert.Equal(t, time.Duration(0), retryDelay(0, 3))
+	assert.Equal(t, 100*time.Millisecond, retryDelay(100*time.Millisecond, 1))
+	assert.Equal(t, 400*time.Millisecond, retryDelay(100*time.Millisecond, 3))
+	assert.Equal(t, maxRetryBackoff, retryDelay(10*time.Second, 10))
+}
+
+// TestWithRetryRateLimited tests the behavior of WithRetry on rate-limited lookups.
+//
+// It verifies:
+//   - A 429 response is retried on rules with max_requests_per_second even when retries is 0
+//   - Rate-limited retries back off for at least minRateLimitedBackoff
+//   - A 429 response is not retried when the rule has no rate limit and no retries
+func TestWithRetryRateLimited(t *testing.T) {
+	pkg := formats.Package{Name: "lodash", Rule: "npm", PackageType: "js"}
+
+	t.Run("retries 429 when rate limit is configured", func(t *testing.T) {
+		delays := stubRetrySleep(t)
+		cfg := retryTestConfig(0, 0)
+		rule := cfg.Rules["npm"]
+		rate := 1000000.0
+		rule.MaxRequestsPerSecond = &rate
+		cfg.Rules["npm"] = rule
+
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			if calls < 3 {
+				return nil, fmt.Errorf("npm ERR! 429 Too Many Requests")
+			}
+			return []string{"2.0.0"}, nil
+		})
+
+		versions, err := lister(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2.0.0"}, versions)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{minRateLimitedBackoff, 2 * minRateLimitedBackoff}, *delays)
+	})
+
+	t.Run("gives up after rateLimitedRetries", func(t *testing.T) {
+		stubRetrySleep(t)
+		cfg := retryTestConfig(0, 0)
+		rule := cfg.Rules["npm"]
+		rate := 1000000.0
+		rule.MaxRequestsPerSecond = &rate
+		cfg.Rules["npm"] = rule
+
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return nil, fmt.Errorf("rate limit exceeded")
+		})
+
+		_, err := lister(context.Background(), pkg, cfg, ".")
+		require.Error(t, err)
+		assert.Equal(t, rateLimitedRetries+1, calls)
+	})
+
+	t.Run("does not retry 429 without rate limit", func(t *testing.T) {
+		stubRetrySleep(t)
+		calls := 0
+		lister := WithRetry(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return nil, fmt.Errorf("429 Too Many Requests")
+		})
+
+		_, err := lister(context.Background(), pkg, retryTestConfig(0, 0), ".")
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+// TestWithRateLimit tests the behavior of WithRateLimit.
+//
+// It verifies:
+//   - The wrapped lister is called when no rate limit is configured
+//   - A lookup waiting for the limiter returns the context error without calling the lister
+func TestWithRateLimit(t *testing.T) {
+	pkg := formats.Package{Name: "lodash", Rule: "npm", PackageType: "js"}
+
+	t.Run("passes through without limit", func(t *testing.T) {
+		calls := 0
+		lister := WithRateLimit(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return []string{"2.0.0"}, nil
+		})
+
+		versions, err := lister(context.Background(), pkg, retryTestConfig(0, 0), ".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2.0.0"}, versions)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops waiting when context is done", func(t *testing.T) {
+		cfg := retryTestConfig(0, 0)
+		rule := cfg.Rules["npm"]
+		rate := 0.001
+		rule.MaxRequestsPerSecond = &rate
+		cfg.Rules["npm"] = rule
+
+		calls := 0
+		lister := WithRateLimit(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			calls++
+			return nil, nil
+		})
+
+		_, err := lister(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = lister(ctx, pkg, cfg, ".")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+// TestIsRateLimitError tests the behavior of IsRateLimitError.
+//
+// It verifies:
+//   - HTTP 429 statuses and rate limit messages are detected
+//   - A 429 inside a version, hash, or port is not a rate limit
+//   - Other transient and unsupported errors are not rate limit errors
+func TestIsRateLimitError(t *testing.T) {
+	assert.False(t, IsRateLimitError(nil))
+	assert.True(t, IsRateLimitError(errors.New("npm ERR! 429 Too Many Requests")))
+	assert.True(t, IsRateLimitError(errors.New("API rate limit exceeded")))
+	for _, msg := range []string{
+		"curl: (22) The requested URL returned error: 429",
+		"unexpected HTTP/2 429 from registry",
+		"GET https://proxy.golang.org/x: status code 429",
+		"npm ERR! code E429",
+		"request failed (429)",
+	} {
+		assert.True(t, IsRateLimitError(errors.New(msg)), msg)
+	}
+	for _, msg := range []string{
+		"no matching version 1.4290.0",
+		"checksum mismatch: h1:ab429cd",
+		"dial tcp 10.0.0.1:4290: connection refused",
+		"exit status 1: package 429-utils failed",
+	} {
+		assert.False(t, IsRateLimitError(errors.New(msg)), msg)
+	}
+	assert.False(t, IsRateLimitError(errors.New("502 bad gateway")))
+	assert.False(t, IsRateLimitError(&pkgerrors.UnsupportedError{Reason: "429"}))
+}
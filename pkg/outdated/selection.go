@@ -0,0 +1,245 @@
+package outdated
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/utils"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// TargetSelector picks the update target of a package among candidate versions.
+//
+// Selectors implement a rule's selection_strategy. The candidates are already
+// bounded by --major/--minor/--patch, the constraint, pins and ignore lists
+// (see ScopeCandidates), so a selector only decides which of them to take.
+type TargetSelector interface {
+	// SelectTarget returns the version to update to.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation
+	//   - p: The package being updated
+	//   - candidates: Versions newer than the installed one, in lookup order; never empty
+	//   - cfg: The global configuration
+	//   - baseDir: Base directory for command execution
+	//
+	// Returns:
+	//   - string: The target; empty keeps the package at its current version
+	//   - error: When the data the strategy needs cannot be looked up
+	SelectTarget(ctx context.Context, p formats.Package, candidates []string, cfg *config.Config, baseDir string) (string, error)
+}
+
+// TargetSelectorFunc is a function type that implements TargetSelector.
+type TargetSelectorFunc func(ctx context.Context, p formats.Package, candidates []string, cfg *config.Config, baseDir string) (string, error)
+
+// SelectTarget implements TargetSelector for TargetSelectorFunc.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package being updated
+//   - candidates: Versions newer than the installed one
+//   - cfg: The global configuration
+//   - baseDir: Base directory for command execution
+//
+// Returns:
+//   - string: The target returned by the underlying function
+//   - error: The error returned by the underlying function
+func (f TargetSelectorFunc) SelectTarget(ctx context.Context, p formats.Package, candidates []string, cfg *config.Config, baseDir string) (string, error) {
+	return f(ctx, p, candidates, cfg, baseDir)
+}
+
+// TargetSelectors maps selection strategy names to their selectors.
+//
+// The highest strategy needs no entry: it is the regular major/minor/patch
+// selection (see SelectTargetVersion). Strategies without an entry fall back
+// to it as well.
+type TargetSelectors map[string]TargetSelector
+
+// Select picks the target of a package with its rule's selection strategy.
+//
+// It performs the following operations:
+//   - Step 1: Return highest unchanged for the highest strategy or an unregistered one
+//   - Step 2: Narrow the versions to in-scope candidates newer than the installed version
+//   - Step 3: Let the strategy's selector choose among them
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package being updated
+//   - versions: Versions permitted by the constraint and selection flags
+//   - flags: Selection flags (--major/--minor/--patch) bounding the candidates
+//   - preferStable: Drop pre-release candidates when a stable one exists
+//   - highest: Target chosen by SelectTargetVersion
+//   - cfg: The global configuration
+//   - baseDir: Base directory for command execution
+//
+// Returns:
+//   - string: The target; empty when no candidate is left
+//   - error: When the candidates cannot be computed or the selector fails
+func (s TargetSelectors) Select(ctx context.Context, p formats.Package, versions []string, flags UpdateSelectionFlags, preferStable bool, highest string, cfg *config.Config, baseDir string) (string, error) {
+	strategy := config.SelectionStrategyFor(p, cfg)
+	selector, ok := s[strategy]
+	if strategy == config.SelectionStrategyHighest || !ok {
+		return highest, nil
+	}
+
+	candidates, err := ScopeCandidates(CurrentVersionForOutdated(p), versions, flags, p.Constraint, VersioningFor(p, cfg), preferStable)
+	if err != nil || len(candidates) == 0 {
+		return "", err
+	}
+
+	target, err := selector.SelectTarget(ctx, p, candidates, cfg, baseDir)
+	if err != nil {
+		return "", fmt.Errorf("%s selection failed: %w", strategy, err)
+	}
+	if target != "" && target != highest {
+		verbose.Debugf("Package %s: %s selected %s instead of %s", p.Name, strategy, target, highest)
+	}
+	return target, nil
+}
+
+// ScopeCandidates returns the versions a selection strategy may choose from.
+//
+// Candidates are newer than the current version and inside the scope that
+// SelectTargetVersion uses for the same flags and constraint, so every
+// strategy stays within --major/--minor/--patch.
+//
+// Parameters:
+//   - current: The installed (or declared) version
+//   - versions: Versions permitted by the constraint and selection flags
+//   - flags: Selection flags (--major/--minor/--patch)
+//   - constraint: The package constraint, used when no flag is set
+//   - cfg: Versioning configuration (nil uses semver)
+//   - preferStable: Drop pre-releases when at least one stable candidate exists
+//
+// Returns:
+//   - []string: Candidates in their original order; empty when current cannot be parsed
+//   - error: When the versioning configuration is invalid
+func ScopeCandidates(current string, versions []string, flags UpdateSelectionFlags, constraint string, cfg *config.VersioningCfg, preferStable bool) ([]string, error) {
+	strategy, err := newVersioningStrategy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	base, ok := strategy.parseVersion(current)
+	if !ok {
+		return nil, nil
+	}
+
+	scope := determineScope(flags, constraint)
+	var stable, all []string
+	for _, version := range versions {
+		parsed, valid := strategy.parseVersion(version)
+		if !valid || strategy.compare(parsed, base) <= 0 {
+			continue
+		}
+		if scope != "major" && parsed.major != base.major {
+			continue
+		}
+		if scope == "patch" && parsed.minor != base.minor {
+			continue
+		}
+		all = append(all, version)
+		if !utils.IsPreReleaseVersion(version) {
+			stable = append(stable, version)
+		}
+	}
+
+	if preferStable && len(stable) > 0 {
+		return stable, nil
+	}
+	return all, nil
+}
+
+// HighestCandidate returns the highest of the candidate versions.
+//
+// Parameters:
+//   - candidates: Versions to compare
+//   - cfg: Versioning configuration (nil uses semver)
+//
+// Returns:
+//   - string: The highest version; empty when candidates is empty
+func HighestCandidate(candidates []string, cfg *config.VersioningCfg) string {
+	return pickCandidate(candidates, cfg, 1)
+}
+
+// LowestCandidate returns the lowest of the candidate versions.
+//
+// Parameters:
+//   - candidates: Versions to compare
+//   - cfg: Versioning configuration (nil uses semver)
+//
+// Returns:
+//   - string: The lowest version; empty when candidates is empty
+func LowestCandidate(candidates []string, cfg *config.VersioningCfg) string {
+	return pickCandidate(candidates, cfg, -1)
+}
+
+// pickCandidate returns the candidate that compares in direction (1 highest, -1 lowest) against all others.
+func pickCandidate(candidates []string, cfg *config.VersioningCfg, direction int) string {
+	best := ""
+	for _, v := range candidates {
+		if best == "" {
+			best = v
+			continue
+		}
+		cmp, err := CompareVersions(v, best, cfg)
+		if err != nil {
+			return ""
+		}
+		if cmp*direction > 0 {
+			best = v
+		}
+	}
+	return best
+}
+
+// VersioningFor returns the versioning configuration of a package's rule.
+//
+// Parameters:
+//   - p: The package
+//   - cfg: The global configuration; may be nil
+//
+// Returns:
+//   - *config.VersioningCfg: The rule's outdated.versioning; nil for semver defaults
+func VersioningFor(p formats.Package, cfg *config.Config) *config.VersioningCfg {
+	if cfg == nil {
+		return nil
+	}
+	if rule, ok := cfg.Rules[p.Rule]; ok && rule.Outdated != nil {
+		return rule.Outdated.Versioning
+	}
+	return nil
+}
+
+// MostRecentByDate returns a selector that picks the most recently published candidate.
+//
+// It prefers, for example, a backported 1.9.5 released after 2.0.0. Candidates
+// without a known publish time are skipped; when none has one, the highest
+// candidate is selected.
+//
+// Parameters:
+//   - listDates: Release date lookup, usually ListReleaseDates
+//
+// Returns:
+//   - TargetSelector: Selector for the most-recent-by-date strategy
+func MostRecentByDate(listDates ListReleaseDatesFunc) TargetSelector {
+	return TargetSelectorFunc(func(ctx context.Context, p formats.Package, candidates []string, cfg *config.Config, baseDir string) (string, error) {
+		dates, err := listDates(ctx, p, cfg, baseDir)
+		if err != nil {
+			return "", err
+		}
+
+		var newest VersionCandidate
+		for _, candidate := range dates.Candidates(candidates) {
+			if candidate.Published.After(newest.Published) {
+				newest = candidate
+			}
+		}
+		if newest.Version == "" {
+			verbose.Infof("No release dates known for the candidates of %s; selecting the highest version", p.Name)
+			return HighestCandidate(candidates, VersioningFor(p, cfg)), nil
+		}
+		return newest.Version, nil
+	})
+}
@@ -0,0 +1,153 @@
+package outdated
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestScopeCandidates tests narrowing versions to the candidates of a selection strategy.
+//
+// It verifies:
+//   - Versions at or below the current version are dropped
+//   - --minor and --patch, or a ^ or ~ constraint, bound the candidates like SelectTargetVersion
+//   - Pre-releases are dropped only when a stable candidate exists and stable is preferred
+//   - An unparseable current version yields no candidates
+func TestScopeCandidates(t *testing.T) {
+	versions := []string{"1.1.0", "1.2.0", "1.2.5", "1.3.0-rc.1", "2.0.0", "2.1.0", "1.2.3"}
+
+	tests := []struct {
+		name       string
+		flags      UpdateSelectionFlags
+		constraint string
+		stable     bool
+		want       []string
+	}{
+		{"major", UpdateSelectionFlags{Major: true}, "^", false, []string{"1.2.5", "1.3.0-rc.1", "2.0.0", "2.1.0"}},
+		{"minor flag", UpdateSelectionFlags{Minor: true}, "", false, []string{"1.2.5", "1.3.0-rc.1"}},
+		{"caret constraint", UpdateSelectionFlags{}, "^", true, []string{"1.2.5"}},
+		{"patch flag", UpdateSelectionFlags{Patch: true}, "", false, []string{"1.2.5"}},
+		{"tilde constraint", UpdateSelectionFlags{}, "~", false, []string{"1.2.5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ScopeCandidates("1.2.3", versions, tt.flags, tt.constraint, nil, tt.stable)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := ScopeCandidates("1.2.3", []string{"1.3.0-rc.1"}, UpdateSelectionFlags{}, "^", nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.3.0-rc.1"}, got)
+
+	got, err = ScopeCandidates("latest", versions, UpdateSelectionFlags{}, "", nil, false)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// TestTargetSelectorsSelect tests choosing a target with a rule's selection strategy.
+//
+// It verifies:
+//   - The highest strategy, unset strategies and strategies without a selector keep the highest target
+//   - A registered selector chooses among the in-scope candidates only
+//   - No selector runs when no candidate is left
+//   - Selector errors name the strategy
+func TestTargetSelectorsSelect(t *testing.T) {
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"npm":  {SelectionStrategy: config.SelectionStrategyMostRecentByDate},
+		"yarn": {SelectionStrategy: config.SelectionStrategyHighest},
+		"pnpm": {SelectionStrategy: config.SelectionStrategyLowestNonVulnerable},
+		"bun":  {},
+	}}
+	var got []string
+	selectors := TargetSelectors{
+		config.SelectionStrategyMostRecentByDate: TargetSelectorFunc(func(ctx context.Context, p formats.Package, candidates []string, cfg *config.Config, baseDir string) (string, error) {
+			got = candidates
+			if p.Name == "broken" {
+				return "", errors.New("E404")
+			}
+			return candidates[0], nil
+		}),
+	}
+	versions := []string{"17.0.1", "17.0.2", "18.2.0"}
+	pkg := func(rule, name string) formats.Package {
+		return formats.Package{Rule: rule, Name: name, Version: "17.0.0", InstalledVersion: "17.0.0", Constraint: "^"}
+	}
+
+	for _, rule := range []string{"yarn", "pnpm", "bun"} {
+		target, err := selectors.Select(context.Background(), pkg(rule, "react"), versions, UpdateSelectionFlags{}, false, "17.0.2", cfg, ".")
+		require.NoError(t, err)
+		assert.Equal(t, "17.0.2", target, rule)
+	}
+	assert.Nil(t, got)
+
+	target, err := selectors.Select(context.Background(), pkg("npm", "react"), versions, UpdateSelectionFlags{}, false, "17.0.2", cfg, ".")
+	require.NoError(t, err)
+	assert.Equal(t, "17.0.1", target)
+	assert.Equal(t, []string{"17.0.1", "17.0.2"}, got)
+
+	got = nil
+	target, err = selectors.Select(context.Background(), pkg("npm", "react"), []string{"16.0.0"}, UpdateSelectionFlags{}, false, "", cfg, ".")
+	require.NoError(t, err)
+	assert.Empty(t, target)
+	assert.Nil(t, got)
+
+	_, err = selectors.Select(context.Background(), pkg("npm", "broken"), versions, UpdateSelectionFlags{}, false, "17.0.2", cfg, ".")
+	assert.EqualError(t, err, "most-recent-by-date selection failed: E404")
+
+	var none TargetSelectors
+	target, err = none.Select(context.Background(), pkg("npm", "react"), versions, UpdateSelectionFlags{}, false, "17.0.2", cfg, ".")
+	require.NoError(t, err)
+	assert.Equal(t, "17.0.2", target)
+}
+
+// TestMostRecentByDate tests the most-recent-by-date selection strategy.
+//
+// It verifies:
+//   - The most recently published candidate wins over a higher version
+//   - Versions that are not candidates are ignored even when newer
+//   - Without any known publish time the highest candidate is selected
+//   - Release date lookup errors are returned
+func TestMostRecentByDate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	dates := ReleaseDates{"1.9.4": day(1), "2.0.0": day(5), "1.9.5": day(9), "1.9.6": day(20)}
+	var lookupErr error
+	selector := MostRecentByDate(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (ReleaseDates, error) {
+		return dates, lookupErr
+	})
+	p := formats.Package{Name: "lib", Rule: "npm"}
+
+	target, err := selector.SelectTarget(context.Background(), p, []string{"1.9.4", "1.9.5", "2.0.0"}, nil, ".")
+	require.NoError(t, err)
+	assert.Equal(t, "1.9.5", target)
+
+	target, err = selector.SelectTarget(context.Background(), p, []string{"3.0.0", "2.1.0"}, nil, ".")
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.0", target)
+
+	lookupErr = errors.New("registry down")
+	_, err = selector.SelectTarget(context.Background(), p, []string{"1.9.5"}, nil, ".")
+	assert.EqualError(t, err, "registry down")
+}
+
+// TestHighestAndLowestCandidate tests picking the extremes of candidate versions.
+//
+// It verifies:
+//   - Versions are compared by the versioning strategy, not as strings
+//   - Empty candidate lists yield an empty version
+func TestHighestAndLowestCandidate(t *testing.T) {
+	candidates := []string{"1.10.0", "1.9.0", "1.2.0"}
+	assert.Equal(t, "1.10.0", HighestCandidate(candidates, nil))
+	assert.Equal(t, "1.2.0", LowestCandidate(candidates, nil))
+	assert.Empty(t, HighestCandidate(nil, nil))
+	assert.Empty(t, LowestCandidate(nil, nil))
+}
@@ -0,0 +1,187 @@
+package outdated
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// StateFileName is the file in the working directory that records the last update run.
+const StateFileName = ".goupdate-state.json"
+
+// SinceLastRun is the --since value that refers to the last recorded update run.
+const SinceLastRun = "last-run"
+
+// SinceOperation is the UnsupportedError operation for packages that --since cannot filter.
+const SinceOperation = "since"
+
+// RunState is the content of the state file.
+//
+// Fields:
+//   - LastRun: When the last successful update run finished
+type RunState struct {
+	LastRun time.Time `json:"last_run"`
+}
+
+// LoadRunState reads the state file from a directory.
+//
+// Parameters:
+//   - dir: Directory containing the state file
+//
+// Returns:
+//   - *RunState: The recorded state; nil when no state file exists
+//   - error: When the file exists but cannot be read or parsed
+func LoadRunState(dir string) (*RunState, error) {
+	path := filepath.Join(dir, StateFileName)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var state RunState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// SaveRunState records the time of an update run in the state file.
+//
+// Parameters:
+//   - dir: Directory to write the state file to
+//   - now: Time of the run
+//
+// Returns:
+//   - error: When the file cannot be written
+func SaveRunState(dir string, now time.Time) error {
+	data, err := json.MarshalIndent(RunState{LastRun: now.UTC()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, StateFileName)
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ParseSince parses a --since value.
+//
+// Accepted values are an ISO date (2024-05-01), an RFC 3339 timestamp
+// (2024-05-01T12:00:00Z), or "last-run" for the time recorded in the state
+// file of dir by the last successful update run.
+//
+// Parameters:
+//   - value: The flag value; empty disables the filter
+//   - dir: Directory containing the state file
+//
+// Returns:
+//   - time.Time: The cutoff; zero when value is empty
+//   - error: When the value is invalid or no run has been recorded yet
+func ParseSince(value, dir string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if strings.EqualFold(value, SinceLastRun) {
+		state, err := LoadRunState(dir)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if state == nil || state.LastRun.IsZero() {
+			return time.Time{}, fmt.Errorf("--since %s: no update run recorded in %s yet", SinceLastRun, filepath.Join(dir, StateFileName))
+		}
+		return state.LastRun, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if since, err := time.Parse(layout, value); err == nil {
+			return since, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: expected an ISO date (2006-01-02), an RFC 3339 timestamp, or %s", value, SinceLastRun)
+}
+
+// FilterCandidatesSince keeps candidates published after since.
+//
+// Candidates without a known publish timestamp are dropped, since they cannot
+// be shown to be new. A zero since returns the candidates unchanged.
+//
+// Parameters:
+//   - candidates: Candidates to filter
+//   - since: Cutoff time
+//
+// Returns:
+//   - []VersionCandidate: Candidates published after since
+func FilterCandidatesSince(candidates []VersionCandidate, since time.Time) []VersionCandidate {
+	if since.IsZero() {
+		return candidates
+	}
+
+	kept := make([]VersionCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if !c.Published.IsZero() && c.Published.After(since) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// WithReleasedSince wraps a version lister so it only returns versions published after since.
+//
+// Publish timestamps come from the rule's outdated.release_dates lookup. When
+// the rule has no lookup configured, or the registry returns no timestamps,
+// the package is reported as an UnsupportedError with SinceOperation so callers
+// route it to the unsupported tracker instead of silently dropping it.
+//
+// Parameters:
+//   - fn: The version lister to wrap
+//   - listDates: The release date lookup
+//   - since: Cutoff time; zero returns fn unchanged
+//
+// Returns:
+//   - ListNewerVersionsFunc: Lister returning only versions released after since
+func WithReleasedSince(fn ListNewerVersionsFunc, listDates ListReleaseDatesFunc, since time.Time) ListNewerVersionsFunc {
+	if since.IsZero() {
+		return fn
+	}
+
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		versions, err := fn(ctx, p, cfg, baseDir)
+		if err != nil || len(versions) == 0 {
+			return versions, err
+		}
+
+		if !ReleaseDatesConfigured(p, cfg) {
+			return nil, errors.NewUnsupportedError(SinceOperation, "no outdated.release_dates lookup is configured for this rule", p.Name)
+		}
+
+		dates, err := listDates(ctx, p, cfg, baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("release dates for --since: %w", err)
+		}
+		if len(dates) == 0 {
+			return nil, errors.NewUnsupportedError(SinceOperation, "the registry returned no release dates", p.Name)
+		}
+
+		candidates := FilterCandidatesSince(dates.Candidates(versions), since)
+		if excluded := len(versions) - len(candidates); excluded > 0 {
+			verbose.Infof("Excluded %d release(s) of %s published before %s", excluded, p.Name, since.Format(time.RFC3339))
+		}
+		return CandidateVersions(candidates), nil
+	}
+}
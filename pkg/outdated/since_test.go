@@ -0,0 +1,158 @@
+package outdated
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	goerrors "github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestParseSince tests parsing of --since values.
+//
+// It verifies:
+//   - Empty values disable the filter
+//   - ISO dates and RFC 3339 timestamps are accepted
+//   - last-run reads the time recorded in the state file
+//   - last-run without a recorded run and invalid values return errors
+func TestParseSince(t *testing.T) {
+	dir := t.TempDir()
+
+	since, err := ParseSince("", dir)
+	require.NoError(t, err)
+	assert.True(t, since.IsZero())
+
+	since, err = ParseSince("2024-05-01", dir)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), since)
+
+	since, err = ParseSince("2024-05-01T12:30:00Z", dir)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), since)
+
+	_, err = ParseSince(SinceLastRun, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no update run recorded")
+
+	recorded := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, SaveRunState(dir, recorded))
+	since, err = ParseSince("last-run", dir)
+	require.NoError(t, err)
+	assert.True(t, recorded.Equal(since))
+
+	_, err = ParseSince("yesterday", dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --since")
+}
+
+// TestRunState tests reading and writing the state file.
+//
+// It verifies:
+//   - A missing state file returns nil state and no error
+//   - Saved state is read back in UTC
+//   - A corrupt state file returns an error
+func TestRunState(t *testing.T) {
+	dir := t.TempDir()
+
+	state, err := LoadRunState(dir)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	local := time.Date(2025, 3, 1, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	require.NoError(t, SaveRunState(dir, local))
+
+	state, err = LoadRunState(dir)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, time.UTC, state.LastRun.Location())
+	assert.True(t, local.Equal(state.LastRun))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, StateFileName), []byte("{"), 0o644))
+	_, err = LoadRunState(dir)
+	assert.Error(t, err)
+}
+
+// TestFilterCandidatesSince tests filtering of candidates by publish date.
+//
+// It verifies:
+//   - Candidates published after the cutoff are kept
+//   - Candidates published at or before the cutoff, or with unknown dates, are dropped
+//   - A zero cutoff returns the candidates unchanged
+func TestFilterCandidatesSince(t *testing.T) {
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	candidates := []VersionCandidate{
+		{Version: "1.0.0", Published: since.AddDate(0, 0, -1)},
+		{Version: "1.1.0", Published: since},
+		{Version: "1.2.0", Published: since.AddDate(0, 0, 1)},
+		{Version: "1.3.0"},
+	}
+
+	assert.Equal(t, []string{"1.2.0"}, CandidateVersions(FilterCandidatesSince(candidates, since)))
+	assert.Len(t, FilterCandidatesSince(candidates, time.Time{}), 4)
+}
+
+// TestWithReleasedSince tests the --since version lister wrapper.
+//
+// It verifies:
+//   - Only versions published after the cutoff are returned
+//   - Rules without release dates and empty date lookups return an UnsupportedError
+//   - Release date lookup failures are returned as regular errors
+//   - A zero cutoff and empty version lists skip the date lookup
+func TestWithReleasedSince(t *testing.T) {
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"npm": {Outdated: &config.OutdatedCfg{Commands: "echo", ReleaseDates: &config.ReleaseDatesCfg{Commands: "echo"}}},
+		"mod": {Outdated: &config.OutdatedCfg{Commands: "echo"}},
+	}}
+	list := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if p.Name == "none" {
+			return nil, nil
+		}
+		return []string{"1.1.0", "1.2.0"}, nil
+	}
+	var lookups int
+	var dates ReleaseDates
+	var datesErr error
+	listDates := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (ReleaseDates, error) {
+		lookups++
+		return dates, datesErr
+	}
+	wrapped := WithReleasedSince(list, listDates, since)
+
+	dates = ReleaseDates{"1.1.0": since.AddDate(0, -1, 0), "1.2.0": since.AddDate(0, 0, 3)}
+	versions, err := wrapped(context.Background(), formats.Package{Name: "demo", Rule: "npm"}, cfg, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.0"}, versions)
+
+	_, err = wrapped(context.Background(), formats.Package{Name: "demo", Rule: "mod"}, cfg, ".")
+	ue, ok := goerrors.IsUnsupportedError(err)
+	require.True(t, ok)
+	assert.Equal(t, SinceOperation, ue.Operation)
+
+	dates = ReleaseDates{}
+	_, err = wrapped(context.Background(), formats.Package{Name: "demo", Rule: "npm"}, cfg, ".")
+	assert.True(t, goerrors.IsUnsupported(err))
+
+	datesErr = errors.New("registry down")
+	_, err = wrapped(context.Background(), formats.Package{Name: "demo", Rule: "npm"}, cfg, ".")
+	require.Error(t, err)
+	assert.False(t, goerrors.IsUnsupported(err))
+	assert.Contains(t, err.Error(), "registry down")
+
+	lookups = 0
+	versions, err = wrapped(context.Background(), formats.Package{Name: "none", Rule: "npm"}, cfg, ".")
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+	versions, err = WithReleasedSince(list, listDates, time.Time{})(context.Background(), formats.Package{Name: "demo", Rule: "npm"}, cfg, ".")
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.Zero(t, lookups)
+}
@@ -278,6 +278,30 @@ func TestSummarizeAvailableVersionsInvalidBase(t *testing.T) {
 	assert.Equal(t, "#N/A", patch)
 }
 
+// TestSummarizeAvailableVersionsWithPreferStable tests pre-release handling in SummarizeAvailableVersionsWith.
+//
+// It verifies:
+//   - Stable releases win over newer pre-releases in the same category
+//   - Pre-releases are selected when a category has no stable release
+//   - Without PreferStable the newest version wins regardless of pre-release status
+func TestSummarizeAvailableVersionsWithPreferStable(t *testing.T) {
+	versions := []string{"2.0.0-rc.1", "1.2.0-beta.1", "1.1.0", "1.0.1"}
+
+	t.Run("stable preferred within category", func(t *testing.T) {
+		major, minor, patch, err := SummarizeAvailableVersionsWith("1.0.0", versions, nil, false, SummarizeOptions{PreferStable: true})
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0-rc.1", major)
+		assert.Equal(t, "1.1.0", minor)
+		assert.Equal(t, "1.0.1", patch)
+	})
+
+	t.Run("newest wins without prefer stable", func(t *testing.T) {
+		_, minor, _, err := SummarizeAvailableVersionsWith("1.0.0", versions, nil, false, SummarizeOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.0-beta.1", minor)
+	})
+}
+
 // TestGetVersionCandidates tests the behavior of getVersionCandidates.
 //
 // It verifies:
@@ -549,3 +549,24 @@ func canonicalSemver(version string) string {
 
 	return ""
 }
+
+// CompareVersions compares two version strings using the given versioning configuration.
+//
+// Parameters:
+//   - a: The first version string
+//   - b: The second version string
+//   - cfg: Versioning configuration; if nil, semver comparison is used
+//
+// Returns:
+//   - int: Negative if a < b, zero if a == b, positive if a > b
+//   - error: When the versioning configuration is invalid; returns nil on success
+func CompareVersions(a, b string, cfg *config.VersioningCfg) (int, error) {
+	strategy, err := newVersioningStrategy(cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	pa, _ := strategy.parseVersion(a)
+	pb, _ := strategy.parseVersion(b)
+	return strategy.compare(pa, pb), nil
+}
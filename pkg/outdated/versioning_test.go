@@ -931,3 +931,26 @@ func TestVersionOrdering(t *testing.T) {
 		assert.Equal(t, 0, strategy.compare(p1, p2), "1.0.0 should equal v1.0.0")
 	})
 }
+
+// TestCompareVersions tests the behavior of CompareVersions.
+//
+// It verifies:
+//   - Semver ordering is used by default
+//   - Numeric format compares extracted parts
+//   - Invalid versioning configuration returns an error
+func TestCompareVersions(t *testing.T) {
+	cmp, err := CompareVersions("1.2.3", "v1.10.0", nil)
+	require.NoError(t, err)
+	assert.Less(t, cmp, 0)
+
+	cmp, err = CompareVersions("2.0.0", "2.0.0", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, cmp)
+
+	cmp, err = CompareVersions("2025.1", "2024.9", &config.VersioningCfg{Format: "numeric"})
+	require.NoError(t, err)
+	assert.Greater(t, cmp, 0)
+
+	_, err = CompareVersions("1.0.0", "2.0.0", &config.VersioningCfg{Format: "unknown"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,433 @@
+package outdated
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/ratelimit"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// ListYankedVersionsFunc is the function signature for looking up yanked versions.
+type ListYankedVersionsFunc func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (YankedVersions, error)
+
+// YankedVersions holds the versions a registry marks yanked, deprecated, or retracted.
+//
+// Fields:
+//   - Versions: Individual yanked versions mapped to the registry's reason; the reason may be empty
+//   - Ranges: Retracted version intervals, as declared by Go retract directives
+type YankedVersions struct {
+	Versions map[string]string
+	Ranges   []RetractedRange
+}
+
+// RetractedRange is an inclusive interval of retracted versions.
+//
+// Fields:
+//   - Low: Lowest retracted version
+//   - High: Highest retracted version; equal to Low for a single version
+//   - Reason: Rationale given with the retraction; may be empty
+type RetractedRange struct {
+	Low    string
+	High   string
+	Reason string
+}
+
+// IsEmpty reports whether no version is yanked.
+//
+// Returns:
+//   - bool: true if there are neither yanked versions nor retracted ranges
+func (y YankedVersions) IsEmpty() bool {
+	return len(y.Versions) == 0 && len(y.Ranges) == 0
+}
+
+// Reason reports whether a version is yanked and why.
+//
+// Versions are compared as listed and with or without a "v" prefix; retracted
+// ranges are compared as semver.
+//
+// Parameters:
+//   - version: The version to check
+//
+// Returns:
+//   - string: The registry's reason; may be empty
+//   - bool: true if the version is yanked
+func (y YankedVersions) Reason(version string) (string, bool) {
+	for _, candidate := range []string{version, strings.TrimPrefix(version, "v"), "v" + version} {
+		if reason, ok := y.Versions[candidate]; ok {
+			return reason, true
+		}
+	}
+
+	canonical := canonicalSemver(version)
+	if canonical == "" {
+		return "", false
+	}
+	for _, r := range y.Ranges {
+		low, high := canonicalSemver(r.Low), canonicalSemver(r.High)
+		if low != "" && high != "" && semver.Compare(low, canonical) <= 0 && semver.Compare(canonical, high) <= 0 {
+			return r.Reason, true
+		}
+	}
+	return "", false
+}
+
+// YankedConfigured reports whether the package's rule can look up yanked versions.
+//
+// Rules reading versions from an offline version_source never run the
+// lookup, so they make no network calls.
+//
+// Parameters:
+//   - p: The package to check
+//   - cfg: The global configuration
+//
+// Returns:
+//   - bool: true if the rule has outdated.yanked commands configured and no version_source
+func YankedConfigured(p formats.Package, cfg *config.Config) bool {
+	if cfg == nil {
+		return false
+	}
+	ruleCfg, ok := cfg.Rules[p.Rule]
+	if !ok || ruleCfg.Outdated == nil || ruleCfg.Outdated.Yanked == nil || ruleCfg.VersionSource != "" {
+		return false
+	}
+	return strings.TrimSpace(ruleCfg.Outdated.Yanked.Commands) != ""
+}
+
+// ListYankedVersions runs the configured yanked version command for a package.
+//
+// The command runs in the same scope, environment, and placeholder context as
+// the outdated command. Rules without outdated.yanked return no yanked
+// versions and no error.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package to look up
+//   - cfg: The global configuration
+//   - baseDir: Base directory for command execution
+//
+// Returns:
+//   - YankedVersions: The yanked versions; empty when not configured
+//   - error: When the command fails or its output cannot be parsed
+func ListYankedVersions(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (YankedVersions, error) {
+	if cfg == nil {
+		return YankedVersions{}, fmt.Errorf("configuration is required")
+	}
+
+	outdatedCfg, err := resolveOutdatedCfg(p, cfg)
+	if err != nil {
+		return YankedVersions{}, err
+	}
+
+	yankedCfg := outdatedCfg.Yanked
+	if yankedCfg == nil || strings.TrimSpace(yankedCfg.Commands) == "" {
+		return YankedVersions{}, nil
+	}
+
+	timeout := yankedCfg.TimeoutSeconds
+	if cfg.NoTimeout {
+		timeout = 0
+	}
+
+	lookupCfg := &config.OutdatedCfg{
+		Commands:       yankedCfg.Commands,
+		Env:            outdatedCfg.Env,
+		TimeoutSeconds: timeout,
+	}
+
+	// Yanked lookups hit the same registry as version lookups
+	if err := ratelimit.ForRule(cfg, p.Rule).Wait(ctx); err != nil {
+		return YankedVersions{}, err
+	}
+
+	output, err := execOutdatedFunc(ctx, lookupCfg, p.Name, CurrentVersionForOutdated(p), p.Constraint, resolveOutdatedScope(p, cfg, baseDir))
+	if err != nil {
+		return YankedVersions{}, fmt.Errorf("failed to look up yanked versions: %w", err)
+	}
+
+	return parseYankedVersions(yankedCfg, output)
+}
+
+// parseYankedVersions extracts yanked versions from yanked version command output.
+//
+// JSON output must resolve to an array of versions or to an object mapping
+// versions to a deprecation message or flag; false, null, and empty values
+// mean not yanked. With reason_key set it is instead an array of version
+// objects, or a single one, whose reason_key field holds the message or flag. Raw output is matched with a pattern whose "version" group,
+// and optional "reason" group, supply each entry. go-mod output is a go.mod
+// file, or the JSON printed by `go mod download -json`, whose retract
+// directives are read.
+//
+// Parameters:
+//   - cfg: Yanked configuration containing format and extraction settings
+//   - output: Raw command output bytes to parse
+//
+// Returns:
+//   - YankedVersions: The parsed yanked versions
+//   - error: When the format is unsupported or the output cannot be parsed
+func parseYankedVersions(cfg *config.YankedCfg, output []byte) (YankedVersions, error) {
+	output = stripBOM(output)
+	yanked := YankedVersions{Versions: make(map[string]string)}
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Format)) {
+	case "", config.YankedFormatJSON:
+		if len(strings.TrimSpace(string(output))) == 0 {
+			return yanked, nil
+		}
+
+		var payload any
+		if err := json.Unmarshal(output, &payload); err != nil {
+			return YankedVersions{}, fmt.Errorf("failed to parse yanked versions JSON: %w", err)
+		}
+
+		node := payload
+		if cfg.JSONKey != "" {
+			for _, part := range strings.Split(cfg.JSONKey, ".") {
+				currentMap, ok := node.(map[string]any)
+				if !ok {
+					return YankedVersions{}, fmt.Errorf("json key %s not found", cfg.JSONKey)
+				}
+				node = currentMap[part]
+			}
+		}
+
+		if cfg.ReasonKey != "" {
+			if err := collectYankedObjects(node, cfg.ReasonKey, yanked.Versions); err != nil {
+				return YankedVersions{}, err
+			}
+			break
+		}
+
+		switch entries := node.(type) {
+		case nil:
+		case []any:
+			for _, entry := range entries {
+				if version := strings.TrimSpace(fmt.Sprint(entry)); version != "" {
+					yanked.Versions[version] = ""
+				}
+			}
+		case map[string]any:
+			for version, value := range entries {
+				switch v := value.(type) {
+				case nil:
+				case bool:
+					if v {
+						yanked.Versions[version] = ""
+					}
+				case string:
+					if strings.TrimSpace(v) != "" {
+						yanked.Versions[version] = strings.TrimSpace(v)
+					}
+				default:
+					yanked.Versions[version] = fmt.Sprint(v)
+				}
+			}
+		default:
+			return YankedVersions{}, fmt.Errorf("yanked versions did not resolve to an array or object of versions")
+		}
+	case config.YankedFormatRaw:
+		re, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return YankedVersions{}, fmt.Errorf("invalid yanked versions pattern: %w", err)
+		}
+
+		versionIdx, reasonIdx := re.SubexpIndex("version"), re.SubexpIndex("reason")
+		if versionIdx < 0 {
+			return YankedVersions{}, fmt.Errorf("yanked versions pattern requires a version named group")
+		}
+
+		for _, match := range re.FindAllStringSubmatch(string(output), -1) {
+			version := strings.TrimSpace(match[versionIdx])
+			if version == "" {
+				continue
+			}
+			reason := ""
+			if reasonIdx >= 0 {
+				reason = strings.TrimSpace(match[reasonIdx])
+			}
+			yanked.Versions[version] = reason
+		}
+	case config.YankedFormatGoMod:
+		output, err := goModContent(output)
+		if err != nil {
+			return YankedVersions{}, err
+		}
+		file, err := modfile.ParseLax("go.mod", output, nil)
+		if err != nil {
+			return YankedVersions{}, fmt.Errorf("failed to parse go.mod retractions: %w", err)
+		}
+		for _, r := range file.Retract {
+			yanked.Ranges = append(yanked.Ranges, RetractedRange{Low: r.Low, High: r.High, Reason: r.Rationale})
+		}
+	default:
+		return YankedVersions{}, fmt.Errorf("unsupported yanked versions format: %s (supported: json, raw, go-mod)", cfg.Format)
+	}
+
+	return yanked, nil
+}
+
+// collectYankedObjects records the yanked entries of JSON version objects.
+//
+// It performs the following operations:
+//   - Accepts an array of objects, or a single object when only one version matched
+//   - Reads each object's "version" field
+//   - Records the version when its reasonKey field is a non-empty string or true
+//
+// Parameters:
+//   - node: The decoded JSON value
+//   - reasonKey: Field holding the yank reason or flag
+//   - versions: Map the yanked versions and reasons are added to
+//
+// Returns:
+//   - error: When the value is not an object or an array of objects
+func collectYankedObjects(node any, reasonKey string, versions map[string]string) error {
+	var entries []any
+	switch v := node.(type) {
+	case nil:
+		return nil
+	case []any:
+		entries = v
+	case map[string]any:
+		entries = []any{v}
+	default:
+		return fmt.Errorf("yanked versions did not resolve to version objects")
+	}
+
+	for _, entry := range entries {
+		object, ok := entry.(map[string]any)
+		if !ok {
+			return fmt.Errorf("yanked versions did not resolve to version objects")
+		}
+		version := strings.TrimSpace(fmt.Sprint(object["version"]))
+		if object["version"] == nil || version == "" {
+			continue
+		}
+		switch reason := object[reasonKey].(type) {
+		case bool:
+			if reason {
+				versions[version] = ""
+			}
+		case string:
+			if strings.TrimSpace(reason) != "" {
+				versions[version] = strings.TrimSpace(reason)
+			}
+		}
+	}
+	return nil
+}
+
+// goModContent returns the go.mod file of go-mod yanked version output.
+//
+// Output printed by `go mod download -json` names the downloaded go.mod in
+// its GoMod field; that file is read. Any other output is the go.mod itself.
+//
+// Parameters:
+//   - output: Command output
+//
+// Returns:
+//   - []byte: The go.mod content
+//   - error: When the download failed or the go.mod cannot be read
+func goModContent(output []byte) ([]byte, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(output), []byte("{")) {
+		return output, nil
+	}
+
+	var download struct {
+		GoMod string
+		Error string
+	}
+	if err := json.Unmarshal(output, &download); err != nil {
+		return nil, fmt.Errorf("failed to parse go mod download output: %w", err)
+	}
+	if download.Error != "" {
+		return nil, fmt.Errorf("go mod download: %s", download.Error)
+	}
+	if download.GoMod == "" {
+		return nil, fmt.Errorf("go mod download output has no GoMod file")
+	}
+
+	content, err := os.ReadFile(download.GoMod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod for retractions: %w", err)
+	}
+	return content, nil
+}
+
+// WithoutYanked wraps a version lister so it never returns yanked, deprecated, or retracted versions.
+//
+// It performs the following operations:
+//   - Step 1: List versions with the wrapped lister
+//   - Step 2: Look up the rule's yanked versions (outdated.yanked) and drop them
+//   - Step 3: Warn when every version newer than the installed one was dropped,
+//     since the package is then reported as up to date
+//
+// Rules without a yanked lookup are returned unchanged. A failed lookup keeps
+// the versions and warns, so a registry hiccup does not fail the package.
+//
+// Parameters:
+//   - fn: The version lister to wrap
+//   - listYanked: The yanked version lookup
+//   - allow: true for --allow-yanked; returns fn unchanged
+//
+// Returns:
+//   - ListNewerVersionsFunc: Lister returning only versions that are not yanked
+func WithoutYanked(fn ListNewerVersionsFunc, listYanked ListYankedVersionsFunc, allow bool) ListNewerVersionsFunc {
+	if allow {
+		return fn
+	}
+
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		versions, err := fn(ctx, p, cfg, baseDir)
+		if err != nil || len(versions) == 0 || !YankedConfigured(p, cfg) {
+			return versions, err
+		}
+
+		ref := warnings.PackageRef(p.Name, p.PackageType, p.Rule)
+		yanked, err := listYanked(ctx, p, cfg, baseDir)
+		if err != nil {
+			warnings.Warn(warnings.CodeYankedUnavailable, ref, "⚠️ yanked versions unavailable for %s: %v", p.Name, err)
+			return versions, nil
+		}
+		if yanked.IsEmpty() {
+			return versions, nil
+		}
+
+		kept := make([]string, 0, len(versions))
+		var dropped []string
+		for _, v := range versions {
+			if reason, ok := yanked.Reason(v); ok {
+				dropped = append(dropped, v)
+				verbose.Debugf("Excluded yanked version %s of %s: %s", v, p.Name, reason)
+				continue
+			}
+			kept = append(kept, v)
+		}
+		if len(dropped) == 0 {
+			return versions, nil
+		}
+		verbose.Infof("Excluded %d yanked version(s) of %s", len(dropped), p.Name)
+
+		var versioning *config.VersioningCfg
+		if outdatedCfg, cfgErr := resolveOutdatedCfg(p, cfg); cfgErr == nil {
+			versioning = outdatedCfg.Versioning
+		}
+		current := CurrentVersionForOutdated(p)
+		newerYanked, _ := FilterNewerVersions(current, dropped, versioning)
+		newerKept, _ := FilterNewerVersions(current, kept, versioning)
+		if len(newerYanked) > 0 && len(newerKept) == 0 {
+			warnings.Warn(warnings.CodeYankedOnly, ref, "⚠️ %s: every newer version is yanked (%s); keeping %s (use --allow-yanked to include them)", p.Name, strings.Join(newerYanked, ", "), current)
+		}
+		return kept, nil
+	}
+}
@@ -0,0 +1,244 @@
+package outdated
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// TestParseYankedVersions tests parsing of yanked version command output.
+//
+// It verifies:
+//   - JSON arrays list yanked versions; JSON objects map versions to a message or flag
+//   - Empty output means nothing is yanked
+//   - JSON version objects with reason_key, including a single object for one matching version
+//   - Raw output is matched with version and optional reason named groups
+//   - go-mod output yields the retract directives of a go.mod file, read directly
+//     or through the GoMod path printed by go mod download -json
+//   - Unsupported formats and patterns without a version group return errors
+func TestParseYankedVersions(t *testing.T) {
+	t.Run("json array", func(t *testing.T) {
+		yanked, err := parseYankedVersions(&config.YankedCfg{JSONKey: "yanked"}, []byte(`{"yanked":["1.0.1","1.0.2"]}`))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"1.0.1": "", "1.0.2": ""}, yanked.Versions)
+	})
+
+	t.Run("json object", func(t *testing.T) {
+		output := []byte(`{"1.0.0":false,"1.0.1":"use 1.0.2","1.0.2":null,"1.0.3":true,"1.0.4":""}`)
+		yanked, err := parseYankedVersions(&config.YankedCfg{Format: "json"}, output)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"1.0.1": "use 1.0.2", "1.0.3": ""}, yanked.Versions)
+	})
+
+	t.Run("json version objects", func(t *testing.T) {
+		cfg := &config.YankedCfg{Format: "json", ReasonKey: "deprecated"}
+		output := []byte(`[{"name":"request","version":"2.88.0"},{"name":"request","version":"2.88.2","deprecated":"request has been deprecated"},{"name":"request","version":"3.0.0","deprecated":true}]`)
+		yanked, err := parseYankedVersions(cfg, output)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"2.88.2": "request has been deprecated", "3.0.0": ""}, yanked.Versions)
+
+		yanked, err = parseYankedVersions(cfg, []byte(`{"name":"left-pad","version":"1.3.0","deprecated":"use String.prototype.padStart()"}`))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"1.3.0": "use String.prototype.padStart()"}, yanked.Versions)
+
+		_, err = parseYankedVersions(cfg, []byte(`["1.0.0"]`))
+		assert.Error(t, err)
+	})
+
+	t.Run("empty output", func(t *testing.T) {
+		yanked, err := parseYankedVersions(&config.YankedCfg{}, []byte("\n"))
+		require.NoError(t, err)
+		assert.True(t, yanked.IsEmpty())
+	})
+
+	t.Run("raw pattern", func(t *testing.T) {
+		cfg := &config.YankedCfg{Format: "raw", Pattern: `(?m)^\S+@(?P<version>\S+) '(?P<reason>[^']*)'$`}
+		output := []byte("request@2.88.1 'request has been deprecated'\nrequest@2.88.2 'request has been deprecated'\n")
+		yanked, err := parseYankedVersions(cfg, output)
+		require.NoError(t, err)
+		assert.Equal(t, "request has been deprecated", yanked.Versions["2.88.2"])
+		assert.Len(t, yanked.Versions, 2)
+	})
+
+	t.Run("go mod retractions", func(t *testing.T) {
+		output := []byte("module example.com/lib\n\ngo 1.21\n\nretract (\n\tv1.4.1 // Published by mistake.\n\t[v1.2.0, v1.2.3] // Data race.\n)\n")
+		yanked, err := parseYankedVersions(&config.YankedCfg{Format: config.YankedFormatGoMod}, output)
+		require.NoError(t, err)
+		assert.Equal(t, []RetractedRange{
+			{Low: "v1.4.1", High: "v1.4.1", Reason: "Published by mistake."},
+			{Low: "v1.2.0", High: "v1.2.3", Reason: "Data race."},
+		}, yanked.Ranges)
+	})
+
+	t.Run("go mod download output", func(t *testing.T) {
+		goMod := filepath.Join(t.TempDir(), "v1.5.0.mod")
+		require.NoError(t, os.WriteFile(goMod, []byte("module example.com/lib\n\nretract v1.4.1\n"), 0o644))
+		output, err := json.Marshal(map[string]string{"Path": "example.com/lib", "Version": "v1.5.0", "GoMod": goMod})
+		require.NoError(t, err)
+
+		yanked, err := parseYankedVersions(&config.YankedCfg{Format: config.YankedFormatGoMod}, output)
+		require.NoError(t, err)
+		_, ok := yanked.Reason("v1.4.1")
+		assert.True(t, ok)
+
+		_, err = parseYankedVersions(&config.YankedCfg{Format: config.YankedFormatGoMod}, []byte(`{"Error":"module not found"}`))
+		assert.ErrorContains(t, err, "go mod download: module not found")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := parseYankedVersions(&config.YankedCfg{}, []byte(`"1.0.0"`))
+		assert.Error(t, err)
+		_, err = parseYankedVersions(&config.YankedCfg{Format: "raw", Pattern: `(\S+)`}, []byte("1.0.0"))
+		assert.ErrorContains(t, err, "version named group")
+		_, err = parseYankedVersions(&config.YankedCfg{Format: "yaml"}, []byte("1.0.0"))
+		assert.ErrorContains(t, err, "unsupported yanked versions format")
+	})
+}
+
+// TestYankedVersionsReason tests matching versions against yanked versions.
+//
+// It verifies:
+//   - Listed versions match with or without a "v" prefix
+//   - Retracted ranges match every version inside the inclusive interval
+//   - Other versions are not yanked
+func TestYankedVersionsReason(t *testing.T) {
+	yanked := YankedVersions{
+		Versions: map[string]string{"v2.0.1": "broken build"},
+		Ranges:   []RetractedRange{{Low: "v1.2.0", High: "v1.2.3", Reason: "Data race."}},
+	}
+
+	reason, ok := yanked.Reason("2.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, "broken build", reason)
+
+	for _, v := range []string{"v1.2.0", "v1.2.2", "v1.2.3"} {
+		reason, ok = yanked.Reason(v)
+		assert.True(t, ok, v)
+		assert.Equal(t, "Data race.", reason)
+	}
+	for _, v := range []string{"v1.1.9", "v1.2.4", "2.0.0", "not-a-version"} {
+		_, ok = yanked.Reason(v)
+		assert.False(t, ok, v)
+	}
+}
+
+// TestListYankedVersions tests running the yanked version lookup.
+//
+// It verifies:
+//   - Rules without yanked return nothing and run no command
+//   - The yanked command runs with the package placeholders and timeout
+//   - Command failures are wrapped with context
+func TestListYankedVersions(t *testing.T) {
+	originalFunc := execOutdatedFunc
+	t.Cleanup(func() { execOutdatedFunc = originalFunc })
+
+	newConfig := func(yanked *config.YankedCfg) *config.Config {
+		return &config.Config{Rules: map[string]config.PackageManagerCfg{
+			"mod": {Outdated: &config.OutdatedCfg{Commands: "go list -m -json -versions {{package}}", Yanked: yanked}},
+		}}
+	}
+	pkg := formats.Package{Name: "example.com/lib", Rule: "mod", Version: "v1.1.0", InstalledVersion: "v1.1.0"}
+
+	execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+		t.Fatal("command should not run")
+		return nil, nil
+	}
+	yanked, err := ListYankedVersions(context.Background(), pkg, newConfig(nil), ".")
+	require.NoError(t, err)
+	assert.True(t, yanked.IsEmpty())
+	assert.False(t, YankedConfigured(pkg, newConfig(nil)))
+
+	var gotCfg *config.OutdatedCfg
+	var gotPkg string
+	execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+		gotCfg, gotPkg = cfg, pkg
+		return []byte("module example.com/lib\n\nretract v1.2.0\n"), nil
+	}
+	cfg := newConfig(&config.YankedCfg{Commands: "go mod download -json {{package}}@latest", Format: config.YankedFormatGoMod, TimeoutSeconds: 20})
+	yanked, err = ListYankedVersions(context.Background(), pkg, cfg, ".")
+	require.NoError(t, err)
+	assert.True(t, YankedConfigured(pkg, cfg))
+	assert.Equal(t, "example.com/lib", gotPkg)
+	assert.Equal(t, 20, gotCfg.TimeoutSeconds)
+	_, ok := yanked.Reason("v1.2.0")
+	assert.True(t, ok)
+
+	execOutdatedFunc = func(ctx context.Context, cfg *config.OutdatedCfg, pkg, version, constraint, dir string) ([]byte, error) {
+		return nil, errors.New("proxy unreachable")
+	}
+	_, err = ListYankedVersions(context.Background(), pkg, cfg, ".")
+	assert.ErrorContains(t, err, "failed to look up yanked versions")
+}
+
+// TestWithoutYanked tests the yanked version lister wrapper.
+//
+// It verifies:
+//   - Yanked versions are dropped from the listed versions
+//   - When every newer version is yanked, none is returned and a YANKED_ONLY warning is written
+//   - A failed lookup keeps the versions and writes a YANKED_UNAVAILABLE warning
+//   - --allow-yanked, rules without a lookup and offline version_source rules skip the lookup
+func TestWithoutYanked(t *testing.T) {
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"npm":     {Outdated: &config.OutdatedCfg{Commands: "echo", Yanked: &config.YankedCfg{Commands: "echo"}}},
+		"bower":   {Outdated: &config.OutdatedCfg{Commands: "echo"}},
+		"offline": {VersionSource: "file://versions", Outdated: &config.OutdatedCfg{Yanked: &config.YankedCfg{Commands: "echo"}}},
+	}}
+	list := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"1.0.0", "1.1.0", "1.2.0"}, nil
+	}
+	var lookups int
+	var yanked YankedVersions
+	var yankedErr error
+	listYanked := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) (YankedVersions, error) {
+		lookups++
+		return yanked, yankedErr
+	}
+	var buf bytes.Buffer
+	t.Cleanup(warnings.SetWarningWriter(&buf))
+
+	pkg := formats.Package{Name: "left-pad", Rule: "npm", PackageType: "js", Version: "1.0.0", InstalledVersion: "1.0.0"}
+	wrapped := WithoutYanked(list, listYanked, false)
+
+	yanked = YankedVersions{Versions: map[string]string{"1.2.0": "critical bug"}}
+	versions, err := wrapped(context.Background(), pkg, cfg, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "1.1.0"}, versions)
+	assert.Empty(t, buf.String())
+
+	yanked = YankedVersions{Versions: map[string]string{"1.1.0": "", "1.2.0": ""}}
+	versions, err = wrapped(context.Background(), pkg, cfg, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0"}, versions)
+	assert.Contains(t, buf.String(), "every newer version is yanked (1.2.0, 1.1.0); keeping 1.0.0")
+
+	buf.Reset()
+	yankedErr = errors.New("registry down")
+	versions, err = wrapped(context.Background(), pkg, cfg, ".")
+	require.NoError(t, err)
+	assert.Len(t, versions, 3)
+	assert.Contains(t, buf.String(), "yanked versions unavailable for left-pad: registry down")
+
+	lookups = 0
+	versions, err = WithoutYanked(list, listYanked, true)(context.Background(), pkg, cfg, ".")
+	require.NoError(t, err)
+	assert.Len(t, versions, 3)
+	for _, rule := range []string{"bower", "offline"} {
+		pkg.Rule = rule
+		versions, err = wrapped(context.Background(), pkg, cfg, ".")
+		require.NoError(t, err)
+		assert.Len(t, versions, 3)
+	}
+	assert.Zero(t, lookups)
+	assert.False(t, YankedConfigured(pkg, cfg))
+}
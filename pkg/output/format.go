@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"unicode/utf8"
 )
 
 // Format represents the output format type.
@@ -23,15 +24,22 @@ const (
 	FormatJSON Format = "json"
 	// FormatXML outputs data as XML.
 	FormatXML Format = "xml"
+	// FormatJUnit outputs update results as a JUnit XML test report.
+	FormatJUnit Format = "junit"
+	// FormatMarkdown outputs update results as a GitHub-flavored markdown summary.
+	FormatMarkdown Format = "markdown"
+	// FormatShields outputs the outdated count as a shields.io endpoint badge.
+	FormatShields Format = "shields"
 )
 
 // ParseFormat parses a format string into a Format type.
 //
-// The parsing is case-insensitive. Valid values are "csv", "json", and "xml".
+// The parsing is case-insensitive. Valid values are "csv", "json", "xml", "junit",
+// "markdown" (or its alias "md"), and "shields".
 // Any unrecognized format returns FormatTable as the default.
 //
 // Parameters:
-//   - s: Format string to parse (e.g., "csv", "JSON", "XmL")
+//   - s: Format string to parse (e.g., "csv", "JSON", "XmL", "junit")
 //
 // Returns:
 //   - Format: The parsed format, or FormatTable if unrecognized
@@ -43,6 +51,12 @@ func ParseFormat(s string) Format {
 		return FormatJSON
 	case "xml":
 		return FormatXML
+	case "junit":
+		return FormatJUnit
+	case "markdown", "md":
+		return FormatMarkdown
+	case "shields":
+		return FormatShields
 	default:
 		return FormatTable
 	}
@@ -50,16 +64,74 @@ func ParseFormat(s string) Format {
 
 // IsStructuredFormat returns true if the format requires structured output (not table).
 //
-// Structured formats (CSV, JSON, XML) are typically used for machine consumption
-// and require different data collection than the interactive table format.
+// Structured formats (CSV, JSON, XML, JUnit, Markdown, Shields) are written in one
+// piece to stdout and require different data collection than the interactive table format.
 //
 // Parameters:
 //   - f: The format to check
 //
 // Returns:
-//   - bool: true if format is CSV, JSON, or XML; false for table format
+//   - bool: true if format is CSV, JSON, XML, JUnit, Markdown, or Shields; false for table format
 func IsStructuredFormat(f Format) bool {
-	return f == FormatCSV || f == FormatJSON || f == FormatXML
+	return f == FormatCSV || f == FormatJSON || f == FormatXML || f == FormatJUnit || f == FormatMarkdown || f == FormatShields
+}
+
+// ValidateJUnitSupport rejects the JUnit format for commands that cannot produce it.
+//
+// JUnit reports map package updates to test cases, so only the update command
+// supports them. Other commands call this before doing any work so users get
+// an immediate error instead of a failure after packages have been processed.
+//
+// Parameters:
+//   - format: The output format being used
+//   - command: Name of the command being run (e.g., "list")
+//
+// Returns:
+//   - error: Validation error if format is JUnit, or nil otherwise
+func ValidateJUnitSupport(format Format, command string) error {
+	if format != FormatJUnit {
+		return nil
+	}
+
+	return fmt.Errorf("--output junit is not supported by the %s command\n  💡 JUnit reports are only available for update; use --output json/csv/xml instead", command)
+}
+
+// ValidateUpdateOnlyFormat rejects formats that only the update command can produce.
+//
+// JUnit reports and markdown summaries both describe update results, so other
+// commands call this before doing any work.
+//
+// Parameters:
+//   - format: The output format being used
+//   - command: Name of the command being run (e.g., "list")
+//
+// Returns:
+//   - error: Validation error if format is JUnit or Markdown, or nil otherwise
+func ValidateUpdateOnlyFormat(format Format, command string) error {
+	if format == FormatMarkdown {
+		return fmt.Errorf("--output markdown is not supported by the %s command\n  💡 Markdown summaries are only available for update; use --output json/csv/xml instead", command)
+	}
+
+	return ValidateJUnitSupport(format, command)
+}
+
+// ValidateShieldsSupport rejects the shields badge format for commands other than outdated.
+//
+// The badge shows the number of outdated packages, which only the outdated
+// command computes.
+//
+// Parameters:
+//   - format: The output format being used
+//   - command: Name of the command being run (e.g., "list")
+//
+// Returns:
+//   - error: Validation error if format is Shields, or nil otherwise
+func ValidateShieldsSupport(format Format, command string) error {
+	if format != FormatShields {
+		return nil
+	}
+
+	return fmt.Errorf("--output shields is not supported by the %s command\n  💡 Badges are only available for outdated; use --output json instead", command)
 }
 
 // ValidateStructuredOutputFlags validates that flags are compatible with structured output formats.
@@ -113,6 +185,71 @@ func ValidateUpdateStructuredFlags(format Format, yes, dryRun bool) error {
 	return nil
 }
 
+// CSVDelimiter is the field separator used for CSV output.
+//
+// It implements the pflag.Value interface so it can be bound directly to a
+// command-line flag; invalid values are rejected when flags are parsed.
+type CSVDelimiter rune
+
+// DefaultCSVDelimiter is the RFC 4180 field separator.
+const DefaultCSVDelimiter CSVDelimiter = ','
+
+// csvDelimiter is the field separator used by WriteCSV.
+// It defaults to a comma until SetCSVDelimiter is called.
+var csvDelimiter = DefaultCSVDelimiter
+
+// String returns the delimiter character.
+//
+// Returns:
+//   - string: the delimiter, "\t" for tab, "," when unset
+func (d *CSVDelimiter) String() string {
+	if d == nil || *d == 0 {
+		return string(DefaultCSVDelimiter)
+	}
+	if *d == '\t' {
+		return `\t`
+	}
+	return string(*d)
+}
+
+// Set parses and stores a delimiter.
+//
+// Parameters:
+//   - value: a single character, or "tab" / "\t" for a tab
+//
+// Returns:
+//   - error: when value is not a single character usable as a CSV delimiter
+func (d *CSVDelimiter) Set(value string) error {
+	if strings.EqualFold(value, "tab") || value == `\t` {
+		value = "\t"
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	if size == 0 || size != len(value) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return fmt.Errorf("invalid CSV delimiter %q: must be a single character other than a quote or newline", value)
+	}
+	*d = CSVDelimiter(r)
+	return nil
+}
+
+// Type returns the flag type name shown in help output.
+//
+// Returns:
+//   - string: "char"
+func (d *CSVDelimiter) Type() string {
+	return "char"
+}
+
+// SetCSVDelimiter sets the field separator used for all CSV output.
+//
+// Parameters:
+//   - d: the delimiter; zero restores the default comma
+func SetCSVDelimiter(d CSVDelimiter) {
+	if d == 0 {
+		d = DefaultCSVDelimiter
+	}
+	csvDelimiter = d
+}
+
 // Formatter handles writing data in a specific format.
 //
 // Fields:
@@ -149,11 +286,13 @@ func (f *Formatter) Format() Format {
 // WriteCSV writes data as CSV to the output writer.
 //
 // It performs the following operations:
-//   - Step 1: Creates a CSV writer
+//   - Step 1: Creates a CSV writer using the configured delimiter (see SetCSVDelimiter)
 //   - Step 2: Writes the header row
 //   - Step 3: Writes all data rows
 //   - Step 4: Flushes the buffer and returns any errors
 //
+// Fields containing the delimiter, quotes, or newlines are quoted as per RFC 4180.
+//
 // Note: csv.Writer buffers all writes and only reports errors via Error() after Flush().
 //
 // Parameters:
@@ -164,6 +303,7 @@ func (f *Formatter) Format() Format {
 //   - error: When write or flush fails, returns the underlying error; otherwise returns nil
 func (f *Formatter) WriteCSV(headers []string, rows [][]string) error {
 	w := csv.NewWriter(f.writer)
+	w.Comma = rune(csvDelimiter)
 
 	_ = w.Write(headers)
 	for _, row := range rows {
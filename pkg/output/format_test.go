@@ -27,6 +27,11 @@ func TestParseFormat(t *testing.T) {
 		{"JSON", FormatJSON},
 		{"xml", FormatXML},
 		{"XML", FormatXML},
+		{"junit", FormatJUnit},
+		{"JUnit", FormatJUnit},
+		{"markdown", FormatMarkdown},
+		{"md", FormatMarkdown},
+		{"shields", FormatShields},
 		{"table", FormatTable},
 		{"TABLE", FormatTable},
 		{"", FormatTable},
@@ -44,12 +49,15 @@ func TestParseFormat(t *testing.T) {
 // TestIsStructuredFormat tests the behavior of IsStructuredFormat.
 //
 // It verifies:
-//   - Returns true for CSV, JSON, XML formats
+//   - Returns true for CSV, JSON, XML, JUnit, Markdown, Shields formats
 //   - Returns false for table format
 func TestIsStructuredFormat(t *testing.T) {
 	assert.True(t, IsStructuredFormat(FormatCSV))
 	assert.True(t, IsStructuredFormat(FormatJSON))
 	assert.True(t, IsStructuredFormat(FormatXML))
+	assert.True(t, IsStructuredFormat(FormatJUnit))
+	assert.True(t, IsStructuredFormat(FormatMarkdown))
+	assert.True(t, IsStructuredFormat(FormatShields))
 	assert.False(t, IsStructuredFormat(FormatTable))
 }
 
@@ -98,6 +106,55 @@ func TestFormatter_WriteCSV_WithQuotes(t *testing.T) {
 	assert.Contains(t, output, "NAME,DESCRIPTION")
 }
 
+// TestCSVDelimiter tests parsing of --csv-delimiter values.
+//
+// It verifies:
+//   - Single characters are accepted as given
+//   - "tab" and "\t" select a tab
+//   - Empty, multi-character, quote, and newline values are rejected
+//   - The zero value prints as the default comma
+func TestCSVDelimiter(t *testing.T) {
+	var d CSVDelimiter
+	assert.Equal(t, ",", d.String())
+	assert.Equal(t, "char", d.Type())
+
+	require.NoError(t, d.Set(";"))
+	assert.Equal(t, CSVDelimiter(';'), d)
+	require.NoError(t, d.Set("TAB"))
+	assert.Equal(t, CSVDelimiter('\t'), d)
+	assert.Equal(t, `\t`, d.String())
+	require.NoError(t, d.Set(`\t`))
+	assert.Equal(t, CSVDelimiter('\t'), d)
+
+	for _, value := range []string{"", ";;", `"`, "\n", "\r"} {
+		assert.Error(t, d.Set(value), "value %q", value)
+	}
+	assert.Equal(t, CSVDelimiter('\t'), d)
+}
+
+// TestFormatter_WriteCSV_Delimiter tests WriteCSV with a custom delimiter.
+//
+// It verifies:
+//   - Fields are separated by the configured delimiter
+//   - Fields containing the delimiter are quoted while commas are left as is
+//   - Setting the zero delimiter restores the comma
+func TestFormatter_WriteCSV_Delimiter(t *testing.T) {
+	t.Cleanup(func() { SetCSVDelimiter(DefaultCSVDelimiter) })
+
+	SetCSVDelimiter(';')
+	var buf bytes.Buffer
+	require.NoError(t, NewFormatter(FormatCSV, &buf).WriteCSV(
+		[]string{"NAME", "VERSION"},
+		[][]string{{"@scope/pkg", "1.0.0"}, {"a;b", "1,2"}},
+	))
+	assert.Equal(t, "NAME;VERSION\n@scope/pkg;1.0.0\n\"a;b\";1,2\n", buf.String())
+
+	SetCSVDelimiter(0)
+	buf.Reset()
+	require.NoError(t, NewFormatter(FormatCSV, &buf).WriteCSV([]string{"A", "B"}, [][]string{{"1", "2"}}))
+	assert.Equal(t, "A,B\n1,2\n", buf.String())
+}
+
 // TestFormatter_WriteJSON tests the behavior of WriteJSON.
 //
 // It verifies:
@@ -316,3 +373,37 @@ func TestValidateUpdateStructuredFlags(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateJUnitSupport tests the behavior of ValidateJUnitSupport.
+//
+// It verifies:
+//   - Returns nil for non-JUnit formats
+//   - Returns an error naming the command for JUnit format
+func TestValidateJUnitSupport(t *testing.T) {
+	for _, format := range []Format{FormatTable, FormatJSON, FormatCSV, FormatXML} {
+		assert.NoError(t, ValidateJUnitSupport(format, "list"))
+	}
+
+	err := ValidateJUnitSupport(FormatJUnit, "outdated")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported by the outdated command")
+}
+
+// TestValidateUpdateOnlyFormat tests the behavior of ValidateUpdateOnlyFormat.
+//
+// It verifies:
+//   - Returns nil for formats every command supports
+//   - Returns an error naming the command for JUnit and Markdown formats
+func TestValidateUpdateOnlyFormat(t *testing.T) {
+	for _, format := range []Format{FormatTable, FormatJSON, FormatCSV, FormatXML} {
+		assert.NoError(t, ValidateUpdateOnlyFormat(format, "list"))
+	}
+
+	err := ValidateUpdateOnlyFormat(FormatMarkdown, "scan")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--output markdown is not supported by the scan command")
+
+	err = ValidateUpdateOnlyFormat(FormatJUnit, "outdated")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--output junit is not supported by the outdated command")
+}
@@ -0,0 +1,236 @@
+package output
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+)
+
+// junitSystemTestSuite is the suite name used for system test failures.
+const junitSystemTestSuite = "system-tests"
+
+// JUnitTestSuites is the root element of a JUnit XML report.
+//
+// Fields:
+//   - XMLName: XML root element name
+//   - Name: Report name
+//   - Tests: Total number of test cases across all suites
+//   - Failures: Total number of failed test cases
+//   - Skipped: Total number of skipped test cases
+//   - Suites: Test suites, one per rule plus one for system test failures
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups the test cases of a single rule.
+//
+// Fields:
+//   - Name: Suite name (the rule name, or "system-tests")
+//   - Tests: Number of test cases in the suite
+//   - Failures: Number of failed test cases in the suite
+//   - Skipped: Number of skipped test cases in the suite
+//   - Cases: Test cases in the suite
+type JUnitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase represents a single package update or system test run.
+//
+// Fields:
+//   - Name: Test case name (package name and target version)
+//   - Classname: Dotted class name identifying the rule, package manager, and type
+//   - Failure: Failure details (nil when the case passed or was skipped)
+//   - Skipped: Skip details (nil when the case ran)
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+}
+
+// JUnitFailure describes why a test case failed.
+//
+// Fields:
+//   - Message: Short failure message
+//   - Type: Failure category (the update status or system test severity)
+//   - Text: Full failure details
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitSkipped describes why a test case was skipped.
+//
+// Fields:
+//   - Message: Reason the package was not updated
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// BuildJUnitReport converts update results into a JUnit report.
+//
+// Each package becomes a test case in a suite named after its rule, in the
+// order rules first appear. Updated, up-to-date, and planned packages pass;
+// failed packages and configuration or summarize errors become failures; all
+// other statuses (e.g., NotConfigured, Floating, Ignored) are skipped. System
+// test failures are appended as a separate "system-tests" suite.
+//
+// Parameters:
+//   - result: Update result data to convert
+//
+// Returns:
+//   - *JUnitTestSuites: The JUnit report
+func BuildJUnitReport(result *UpdateResult) *JUnitTestSuites {
+	report := &JUnitTestSuites{Name: "goupdate"}
+	if result == nil {
+		return report
+	}
+
+	suiteIndex := make(map[string]int)
+	for _, pkg := range result.Packages {
+		idx, ok := suiteIndex[pkg.Rule]
+		if !ok {
+			idx = len(report.Suites)
+			suiteIndex[pkg.Rule] = idx
+			report.Suites = append(report.Suites, JUnitTestSuite{Name: pkg.Rule})
+		}
+		report.Suites[idx].add(junitPackageCase(pkg))
+	}
+
+	if len(result.SystemTestFailures) > 0 {
+		suite := JUnitTestSuite{Name: junitSystemTestSuite}
+		for _, failure := range result.SystemTestFailures {
+			suite.add(junitSystemTestCase(failure))
+		}
+		report.Suites = append(report.Suites, suite)
+	}
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+	}
+
+	return report
+}
+
+// add appends a test case to the suite and updates its counters.
+//
+// Parameters:
+//   - tc: The test case to add
+func (s *JUnitTestSuite) add(tc JUnitTestCase) {
+	s.Cases = append(s.Cases, tc)
+	s.Tests++
+	if tc.Failure != nil {
+		s.Failures++
+	}
+	if tc.Skipped != nil {
+		s.Skipped++
+	}
+}
+
+// junitPackageCase maps a single package update entry to a test case.
+//
+// Parameters:
+//   - pkg: The package update entry
+//
+// Returns:
+//   - JUnitTestCase: The test case for the package
+func junitPackageCase(pkg UpdatePackage) JUnitTestCase {
+	name := pkg.Name
+	if pkg.Target != "" && pkg.Target != constants.PlaceholderNA {
+		name = pkg.Name + "@" + pkg.Target
+	}
+
+	tc := JUnitTestCase{
+		Name:      name,
+		Classname: junitClassname(pkg.Rule, pkg.PM, pkg.Type),
+	}
+
+	switch {
+	case isJUnitPassingStatus(pkg.Status):
+	case isJUnitFailureStatus(pkg.Status):
+		message := pkg.Error
+		if message == "" {
+			message = pkg.Status
+		}
+		tc.Failure = &JUnitFailure{Message: message, Type: pkg.Status, Text: message}
+	default:
+		message := pkg.Status
+		if pkg.Error != "" {
+			message = pkg.Status + ": " + pkg.Error
+		}
+		tc.Skipped = &JUnitSkipped{Message: message}
+	}
+
+	return tc
+}
+
+// junitSystemTestCase maps a system test failure to a failed test case.
+//
+// Parameters:
+//   - failure: The system test failure
+//
+// Returns:
+//   - JUnitTestCase: The failed test case
+func junitSystemTestCase(failure UpdateSystemTest) JUnitTestCase {
+	severity := "warning"
+	if failure.Critical {
+		severity = "critical"
+	}
+
+	return JUnitTestCase{
+		Name:      "after " + failure.Package + " update",
+		Classname: junitSystemTestSuite,
+		Failure: &JUnitFailure{
+			Message: "system tests failed after " + failure.Package + " update",
+			Type:    severity,
+			Text:    failure.Details,
+		},
+	}
+}
+
+// junitClassname joins non-empty parts into a dotted JUnit class name.
+//
+// Parameters:
+//   - parts: Name segments (rule, package manager, dependency type)
+//
+// Returns:
+//   - string: Dotted class name
+func junitClassname(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ".")
+}
+
+// isJUnitPassingStatus reports whether an update status maps to a passing test case.
+func isJUnitPassingStatus(status string) bool {
+	switch status {
+	case constants.StatusUpdated, constants.StatusUpToDate, constants.StatusPlanned:
+		return true
+	default:
+		return false
+	}
+}
+
+// isJUnitFailureStatus reports whether an update status maps to a failed test case.
+func isJUnitFailureStatus(status string) bool {
+	return strings.HasPrefix(status, constants.StatusFailed) ||
+		status == constants.StatusConfigError ||
+		status == constants.StatusSummarizeError
+}
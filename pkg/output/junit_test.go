@@ -0,0 +1,114 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildJUnitReport tests the behavior of BuildJUnitReport.
+//
+// It verifies:
+//   - Packages are grouped into suites by rule in first-seen order
+//   - Updated, UpToDate, and Planned statuses pass
+//   - Failed statuses become failures carrying the error message
+//   - Unsupported statuses are skipped
+//   - System test failures are appended as a separate suite
+//   - Totals are summed across suites
+func TestBuildJUnitReport(t *testing.T) {
+	result := &UpdateResult{
+		Packages: []UpdatePackage{
+			{Rule: "npm", PM: "js", Type: "prod", Name: "lodash", Target: "4.17.21", Status: "Updated"},
+			{Rule: "mod", PM: "golang", Type: "prod", Name: "github.com/pkg/errors", Target: "#N/A", Status: "UpToDate"},
+			{Rule: "npm", PM: "js", Type: "dev", Name: "jest", Target: "29.0.0", Status: "Failed", Error: "npm install failed"},
+			{Rule: "npm", PM: "js", Type: "prod", Name: "react", Target: "#N/A", Status: "Floating"},
+			{Rule: "mod", PM: "golang", Type: "prod", Name: "golang.org/x/text", Target: "0.14.0", Status: "Planned"},
+		},
+		SystemTestFailures: []UpdateSystemTest{
+			{Package: "lodash", Critical: false, Details: "  ✗ unit-tests\n"},
+		},
+	}
+
+	report := BuildJUnitReport(result)
+
+	assert.Equal(t, "goupdate", report.Name)
+	assert.Equal(t, 6, report.Tests)
+	assert.Equal(t, 2, report.Failures)
+	assert.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Suites, 3)
+
+	npm := report.Suites[0]
+	assert.Equal(t, "npm", npm.Name)
+	assert.Equal(t, 3, npm.Tests)
+	assert.Equal(t, 1, npm.Failures)
+	assert.Equal(t, 1, npm.Skipped)
+	require.Len(t, npm.Cases, 3)
+	assert.Equal(t, "lodash@4.17.21", npm.Cases[0].Name)
+	assert.Equal(t, "npm.js.prod", npm.Cases[0].Classname)
+	assert.Nil(t, npm.Cases[0].Failure)
+	assert.Nil(t, npm.Cases[0].Skipped)
+	require.NotNil(t, npm.Cases[1].Failure)
+	assert.Equal(t, "npm install failed", npm.Cases[1].Failure.Message)
+	assert.Equal(t, "Failed", npm.Cases[1].Failure.Type)
+	require.NotNil(t, npm.Cases[2].Skipped)
+	assert.Equal(t, "Floating", npm.Cases[2].Skipped.Message)
+	assert.Equal(t, "react", npm.Cases[2].Name)
+
+	mod := report.Suites[1]
+	assert.Equal(t, "mod", mod.Name)
+	assert.Equal(t, 2, mod.Tests)
+	assert.Equal(t, 0, mod.Failures)
+	assert.Equal(t, "github.com/pkg/errors", mod.Cases[0].Name)
+
+	systemTests := report.Suites[2]
+	assert.Equal(t, "system-tests", systemTests.Name)
+	require.Len(t, systemTests.Cases, 1)
+	require.NotNil(t, systemTests.Cases[0].Failure)
+	assert.Equal(t, "after lodash update", systemTests.Cases[0].Name)
+	assert.Equal(t, "warning", systemTests.Cases[0].Failure.Type)
+	assert.Contains(t, systemTests.Cases[0].Failure.Text, "unit-tests")
+}
+
+// TestBuildJUnitReport_Nil tests the behavior of BuildJUnitReport with nil input.
+//
+// It verifies:
+//   - Returns an empty report without suites
+func TestBuildJUnitReport_Nil(t *testing.T) {
+	report := BuildJUnitReport(nil)
+	assert.Equal(t, 0, report.Tests)
+	assert.Empty(t, report.Suites)
+}
+
+// TestWriteUpdateResult_JUnit tests the behavior of WriteUpdateResult with JUnit format.
+//
+// It verifies:
+//   - Writes a valid JUnit XML document with testsuites root
+//   - Failure and skipped elements are emitted for matching cases
+func TestWriteUpdateResult_JUnit(t *testing.T) {
+	result := &UpdateResult{
+		Packages: []UpdatePackage{
+			{Rule: "npm", PM: "js", Type: "prod", Name: "lodash", Target: "4.17.21", Status: "Updated"},
+			{Rule: "npm", PM: "js", Type: "prod", Name: "axios", Target: "1.6.0", Status: "Failed", Error: "lock failed"},
+			{Rule: "npm", PM: "js", Type: "prod", Name: "react", Target: "#N/A", Status: "NotConfigured"},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WriteUpdateResult(&buf, FormatJUnit, result)
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `<?xml version="1.0" encoding="UTF-8"?>`)
+	assert.Contains(t, out, `<testsuites name="goupdate" tests="3" failures="1" skipped="1">`)
+	assert.Contains(t, out, `<testsuite name="npm" tests="3" failures="1" skipped="1">`)
+	assert.Contains(t, out, `<failure message="lock failed" type="Failed">lock failed</failure>`)
+	assert.Contains(t, out, `<skipped message="NotConfigured"></skipped>`)
+
+	var parsed JUnitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &parsed))
+	require.Len(t, parsed.Suites, 1)
+	assert.Len(t, parsed.Suites[0].Cases, 3)
+}
@@ -0,0 +1,137 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+)
+
+// markdownHeading is the section heading of the markdown update summary.
+const markdownHeading = "## Dependency updates"
+
+// WriteUpdateMarkdown writes update results as a GitHub-flavored markdown summary.
+//
+// The summary is meant to be pasted into pull request descriptions. It renders
+// one table row per package (Package | Manager | From | To | Status), followed
+// by a totals line and, when present, a list of unsupported packages taken from
+// result.Unsupported. When every package is up to date the table collapses to a
+// single "No changes" note.
+//
+// The "From" column shows the installed version, falling back to the declared
+// version when no lock file version is known. Callers are expected to have
+// normalized blank versions with display.SafeInstalledValue and
+// display.SafeDeclaredValue, as PrintUpdateStructured does.
+//
+// Parameters:
+//   - w: Writer to output the markdown to
+//   - result: Update result data to render
+//
+// Returns:
+//   - error: When writing fails; returns nil on success
+func WriteUpdateMarkdown(w io.Writer, result *UpdateResult) error {
+	var b strings.Builder
+
+	heading := markdownHeading
+	if result.Summary.DryRun {
+		heading += " (dry run)"
+	}
+	b.WriteString(heading + "\n\n")
+
+	if allUpToDate(result.Packages) {
+		fmt.Fprintf(&b, "No changes: all %d packages are up to date.\n", len(result.Packages))
+	} else {
+		b.WriteString("| Package | Manager | From | To | Status |\n")
+		b.WriteString("|---------|---------|------|----|--------|\n")
+		for _, pkg := range result.Packages {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				markdownCode(pkg.Name),
+				markdownCell(pkg.PM),
+				markdownCell(markdownFromVersion(pkg)),
+				markdownCell(pkg.Target),
+				markdownCell(pkg.Status))
+		}
+		fmt.Fprintf(&b, "\n**Total:** %d packages, %d updated, %d failed\n",
+			result.Summary.TotalPackages, result.Summary.UpdatedPackages, result.Summary.FailedPackages)
+	}
+
+	if len(result.Unsupported) > 0 {
+		b.WriteString("\n### Unsupported packages\n\n")
+		for _, msg := range result.Unsupported {
+			fmt.Fprintf(&b, "- %s\n", markdownLine(msg))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// allUpToDate reports whether no package in the list has anything to report.
+//
+// Parameters:
+//   - packages: Update package entries to check
+//
+// Returns:
+//   - bool: true if every package is UpToDate (or the list is empty)
+func allUpToDate(packages []UpdatePackage) bool {
+	for _, pkg := range packages {
+		if pkg.Status != constants.StatusUpToDate {
+			return false
+		}
+	}
+	return true
+}
+
+// markdownFromVersion returns the version a package is updated from.
+//
+// Parameters:
+//   - pkg: Update package entry
+//
+// Returns:
+//   - string: The installed version, or the declared version when the installed one is unknown
+func markdownFromVersion(pkg UpdatePackage) string {
+	if pkg.InstalledVersion == "" || pkg.InstalledVersion == constants.PlaceholderNA {
+		return pkg.Version
+	}
+	return pkg.InstalledVersion
+}
+
+// markdownCell escapes a value for use inside a markdown table cell.
+//
+// Pipes would end the cell and newlines would end the row, so both are escaped
+// or flattened.
+//
+// Parameters:
+//   - s: Cell value
+//
+// Returns:
+//   - string: The escaped value
+func markdownCell(s string) string {
+	return strings.ReplaceAll(markdownLine(s), "|", `\|`)
+}
+
+// markdownCode wraps a value in an inline code span for a table cell.
+//
+// Parameters:
+//   - s: Cell value
+//
+// Returns:
+//   - string: The value as inline code, or an empty string if s is empty
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(markdownLine(s), "`", "'") + "`"
+}
+
+// markdownLine flattens a value onto a single line.
+//
+// Parameters:
+//   - s: Value that may contain line breaks
+//
+// Returns:
+//   - string: The trimmed value with line breaks replaced by spaces
+func markdownLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,86 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteUpdateMarkdown tests the behavior of WriteUpdateMarkdown.
+//
+// It verifies:
+//   - One table row is written per package with Package, Manager, From, To, and Status
+//   - The From column falls back to the declared version when installed is unknown
+//   - Pipes and line breaks in cells are escaped
+//   - A totals line and the unsupported section follow the table
+//   - Dry runs are marked in the heading
+func TestWriteUpdateMarkdown(t *testing.T) {
+	result := &UpdateResult{
+		Summary: UpdateSummary{TotalPackages: 3, UpdatedPackages: 1, FailedPackages: 1, DryRun: true},
+		Packages: []UpdatePackage{
+			{Name: "lodash", PM: "js", Version: "^4.17.0", InstalledVersion: "4.17.20", Target: "4.17.21", Status: "Planned"},
+			{Name: "jest", PM: "js", Version: "^29.0.0", InstalledVersion: "#N/A", Target: "29.7.0", Status: "Failed"},
+			{Name: "react", PM: "js", Version: "*", InstalledVersion: "18.2.0", Target: "#N/A", Status: "UpToDate"},
+		},
+		Unsupported: []string{"npm (js): 1 package - Floating constraint |x|"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteUpdateMarkdown(&buf, result))
+
+	expected := "## Dependency updates (dry run)\n\n" +
+		"| Package | Manager | From | To | Status |\n" +
+		"|---------|---------|------|----|--------|\n" +
+		"| `lodash` | js | 4.17.20 | 4.17.21 | Planned |\n" +
+		"| `jest` | js | ^29.0.0 | 29.7.0 | Failed |\n" +
+		"| `react` | js | 18.2.0 | #N/A | UpToDate |\n" +
+		"\n**Total:** 3 packages, 1 updated, 1 failed\n" +
+		"\n### Unsupported packages\n\n" +
+		"- npm (js): 1 package - Floating constraint |x|\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+// TestWriteUpdateMarkdownNoChanges tests the "No changes" collapse of WriteUpdateMarkdown.
+//
+// It verifies:
+//   - The table is replaced by a note when every package is UpToDate
+//   - An empty result also renders the note
+//   - The unsupported section is still written
+func TestWriteUpdateMarkdownNoChanges(t *testing.T) {
+	t.Run("all up to date", func(t *testing.T) {
+		result := &UpdateResult{
+			Summary:     UpdateSummary{TotalPackages: 2},
+			Packages:    []UpdatePackage{{Name: "a", Status: "UpToDate"}, {Name: "b", Status: "UpToDate"}},
+			Unsupported: []string{"custom: 1 package - no update command"},
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteUpdateMarkdown(&buf, result))
+
+		assert.Equal(t, "## Dependency updates\n\nNo changes: all 2 packages are up to date.\n"+
+			"\n### Unsupported packages\n\n- custom: 1 package - no update command\n", buf.String())
+	})
+
+	t.Run("no packages", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteUpdateMarkdown(&buf, &UpdateResult{}))
+
+		assert.Contains(t, buf.String(), "No changes")
+		assert.NotContains(t, buf.String(), "| Package |")
+	})
+}
+
+// TestMarkdownCell tests the behavior of markdownCell and markdownCode.
+//
+// It verifies:
+//   - Pipes are escaped so they do not end the cell
+//   - Line breaks are flattened to spaces
+//   - Backticks inside code spans are replaced
+func TestMarkdownCell(t *testing.T) {
+	assert.Equal(t, `a \| b`, markdownCell("a | b"))
+	assert.Equal(t, "line one line two", markdownCell("line one\nline two"))
+	assert.Equal(t, "`we'ird`", markdownCode("we`ird"))
+	assert.Equal(t, "", markdownCode(""))
+}
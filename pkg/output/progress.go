@@ -6,8 +6,12 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 )
 
+// progressBarWidth is the number of cells in the bar drawn by ReportPackage.
+const progressBarWidth = 20
+
 // Progress provides a simple progress indicator for long-running operations.
 //
 // Fields:
@@ -18,6 +22,8 @@ import (
 //   - mu: Mutex to protect concurrent access to progress state
 //   - enabled: Whether progress output is enabled
 //   - lastWidth: Width of the last rendered progress line for proper clearing
+//   - started: When the progress was created, used to estimate the time remaining
+//   - now: Clock used for the estimate; replaced in tests
 type Progress struct {
 	writer    io.Writer
 	total     int
@@ -26,6 +32,8 @@ type Progress struct {
 	mu        sync.Mutex
 	enabled   bool
 	lastWidth int
+	started   time.Time
+	now       func() time.Time
 }
 
 // NewProgress creates a new progress indicator and returns it.
@@ -43,6 +51,8 @@ func NewProgress(writer io.Writer, total int, message string) *Progress {
 		total:   total,
 		message: message,
 		enabled: true,
+		started: time.Now(),
+		now:     time.Now,
 	}
 }
 
@@ -88,6 +98,63 @@ func (p *Progress) Increment() {
 	}
 }
 
+// ReportPackage records a finished package and renders a bar with the time remaining.
+//
+// It performs the following operations:
+//   - Step 1: Locks mutex, stores current and total, copies values, unlocks
+//   - Step 2: Estimates the time remaining from the average time per finished package
+//   - Step 3: Renders the bar, counts, estimate and package name outside the critical section
+//
+// Parameters:
+//   - current: Number of packages finished so far
+//   - total: Number of packages in the run; replaces the total given to NewProgress
+//   - name: Name of the package that finished; may be empty
+//
+// This method is thread-safe and implements update.PackageProgressReporter.
+// If the receiver is nil, this method is a no-op (nil-safe).
+func (p *Progress) ReportPackage(current, total int, name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.current = current
+	p.total = total
+	enabled := p.enabled
+	started := p.started
+	now := p.now
+	p.mu.Unlock()
+
+	if !enabled || total <= 0 {
+		return
+	}
+
+	filled := min(max(current, 0)*progressBarWidth/total, progressBarWidth)
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled)
+	line := fmt.Sprintf("%s: [%s] %d/%d (%.0f%%)", p.message, bar, current, total, float64(current)/float64(total)*100)
+	if current > 0 && current < total && now != nil && !started.IsZero() {
+		remaining := now().Sub(started) / time.Duration(current) * time.Duration(total-current)
+		line += " ETA " + formatETA(remaining)
+	}
+	if name != "" {
+		line += " " + name
+	}
+	p.writeLine("\r" + line)
+}
+
+// formatETA formats an estimated duration rounded to whole seconds.
+//
+// Parameters:
+//   - d: The estimated duration
+//
+// Returns:
+//   - string: The duration such as "45s" or "2m5s"; "<1s" below one second
+func formatETA(d time.Duration) string {
+	if d < time.Second {
+		return "<1s"
+	}
+	return d.Round(time.Second).String()
+}
+
 // SetCurrent sets the current progress value to a specific step and re-renders.
 //
 // It performs the following operations:
@@ -183,8 +250,7 @@ func (p *Progress) render() {
 // It performs the following operations:
 //   - Step 1: Calculates percentage from current and total
 //   - Step 2: Formats the progress line with message and percentage
-//   - Step 3: Locks mutex briefly to update lastWidth and pad if needed
-//   - Step 4: Writes to the output writer and flushes if it's a file
+//   - Step 3: Writes the line through writeLine, which pads and flushes it
 //
 // This method is safe to call without holding the lock for current/total,
 // but uses the lock for lastWidth to prevent display corruption.
@@ -194,8 +260,14 @@ func (p *Progress) render() {
 //   - total: Total number of steps
 func (p *Progress) renderValues(current, total int) {
 	percentage := float64(current) / float64(total) * 100
-	line := fmt.Sprintf("\r%s: %d/%d (%.0f%%)", p.message, current, total, percentage)
+	p.writeLine(fmt.Sprintf("\r%s: %d/%d (%.0f%%)", p.message, current, total, percentage))
+}
 
+// writeLine writes a rendered progress line, padding it over a longer previous one.
+//
+// Parameters:
+//   - line: The line to write, starting with a carriage return
+func (p *Progress) writeLine(line string) {
 	// Lock only for lastWidth access to prevent display corruption
 	p.mu.Lock()
 	// Clear previous content if the new line is shorter
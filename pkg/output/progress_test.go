@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -214,3 +215,46 @@ func TestProgress_ClearWithoutRender(t *testing.T) {
 
 	assert.Empty(t, buf.String())
 }
+
+// TestProgress_ReportPackage tests the bar rendered by ReportPackage.
+//
+// It verifies:
+//   - The bar, counts, percentage and package name are rendered
+//   - The time remaining is estimated from the average time per package
+//   - No estimate is shown before the first or after the last package
+//   - The total passed in replaces the one given to NewProgress
+//   - Disabled and nil progress render nothing
+func TestProgress_ReportPackage(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewProgress(&buf, 1, "Updating packages")
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	p.started = start
+	p.now = func() time.Time { return start.Add(20 * time.Second) }
+
+	p.ReportPackage(0, 4, "")
+	assert.Contains(t, buf.String(), "[--------------------] 0/4 (0%)")
+	assert.NotContains(t, buf.String(), "ETA")
+
+	buf.Reset()
+	p.ReportPackage(2, 4, "react")
+	assert.Equal(t, "\rUpdating packages: [##########----------] 2/4 (50%) ETA 20s react", strings.TrimRight(buf.String(), " "))
+
+	buf.Reset()
+	p.ReportPackage(4, 4, "vue")
+	assert.Contains(t, buf.String(), "[####################] 4/4 (100%) vue")
+	assert.NotContains(t, buf.String(), "ETA")
+
+	buf.Reset()
+	p.Done()
+	assert.Contains(t, buf.String(), "4/4")
+
+	buf.Reset()
+	p.SetEnabled(false)
+	p.ReportPackage(1, 4, "react")
+	assert.Empty(t, buf.String())
+
+	var nilProgress *Progress
+	assert.NotPanics(t, func() { nilProgress.ReportPackage(1, 2, "react") })
+	assert.Equal(t, "<1s", formatETA(300*time.Millisecond))
+	assert.Equal(t, "2m5s", formatETA(125*time.Second+200*time.Millisecond))
+}
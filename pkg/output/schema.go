@@ -0,0 +1,9 @@
+package output
+
+// SchemaVersion is the version of the JSON and XML output schema.
+//
+// Every structured result carries it as schema_version (schemaVersion in
+// XML). Key names are stable within a version: new keys may be added,
+// but a key is only removed or changes meaning together with a version bump.
+// A renamed key is written under both names until then.
+const SchemaVersion = 1
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// jsonKeyPaths returns the sorted, distinct key paths of a JSON document.
+//
+// Array elements share the path of their array with a "[]" suffix, so the
+// result describes the shape of the document rather than its content.
+func jsonKeyPaths(t *testing.T, data []byte) []string {
+	t.Helper()
+	var doc any
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	seen := make(map[string]bool)
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, child := range v {
+				path := key
+				if prefix != "" {
+					path = prefix + "." + key
+				}
+				seen[path] = true
+				walk(path, child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(prefix+"[]", child)
+			}
+		}
+	}
+	walk("", doc)
+
+	paths := make([]string, 0, len(seen))
+	for path := range seen {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// TestOutdatedResultJSONShape snapshots the JSON keys of a fully populated outdated result.
+//
+// It verifies:
+//   - Every key of schema version 1 is present under its documented name
+//   - No key is added or removed without updating this snapshot (and SchemaVersion on removal)
+func TestOutdatedResultJSONShape(t *testing.T) {
+	result := &OutdatedResult{
+		Summary: OutdatedSummary{TotalPackages: 1, OutdatedPackages: 1},
+		Packages: []OutdatedPackage{{
+			Rule: "npm", PM: "js", Type: "prod", Constraint: "^", Version: "17.0.0", InstalledVersion: "17.0.2",
+			Major: "18.2.0", Minor: "17.1.0", Patch: "17.0.3", Age: "30d", Status: "Outdated", Group: "frontend",
+			Name: "react", Source: "package.json", Line: 3, Error: "registry timeout",
+		}},
+		Warnings:       []string{"lock file missing"},
+		Errors:         []string{"npm: failed"},
+		WarningDetails: []warnings.Warning{{Code: "W001", Message: "lock file missing", PackageRef: "react"}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteOutdatedResult(&buf, FormatJSON, result))
+
+	assert.Equal(t, []string{
+		"errors",
+		"packages",
+		"packages[].age",
+		"packages[].constraint",
+		"packages[].error",
+		"packages[].group",
+		"packages[].installed_version",
+		"packages[].line",
+		"packages[].major",
+		"packages[].minor",
+		"packages[].name",
+		"packages[].patch",
+		"packages[].pm",
+		"packages[].rule",
+		"packages[].source",
+		"packages[].status",
+		"packages[].type",
+		"packages[].version",
+		"schema_version",
+		"summary",
+		"summary.failed_packages",
+		"summary.has_major",
+		"summary.has_minor",
+		"summary.has_patch",
+		"summary.outdated_packages",
+		"summary.total_packages",
+		"summary.uptodate_packages",
+		"warning_details",
+		"warning_details[].code",
+		"warning_details[].message",
+		"warning_details[].package",
+		"warnings",
+	}, jsonKeyPaths(t, buf.Bytes()))
+}
+
+// TestSchemaVersion tests that every structured result is stamped with SchemaVersion.
+//
+// It verifies:
+//   - JSON output of each result type has a top-level schema_version
+//   - XML output carries the version in a schemaVersion element
+func TestSchemaVersion(t *testing.T) {
+	writers := map[string]func(*bytes.Buffer, Format) error{
+		"scan":      func(b *bytes.Buffer, f Format) error { return WriteScanResult(b, f, &ScanResult{}) },
+		"deep scan": func(b *bytes.Buffer, f Format) error { return WriteDeepScanResult(b, f, &DeepScanResult{}) },
+		"list":      func(b *bytes.Buffer, f Format) error { return WriteListResult(b, f, &ListResult{}) },
+		"outdated":  func(b *bytes.Buffer, f Format) error { return WriteOutdatedResult(b, f, &OutdatedResult{}) },
+		"update":    func(b *bytes.Buffer, f Format) error { return WriteUpdateResult(b, f, &UpdateResult{}) },
+		"validate":  func(b *bytes.Buffer, f Format) error { return WriteValidateResult(b, f, &ValidateResult{}) },
+	}
+	for name, write := range writers {
+		var buf bytes.Buffer
+		require.NoError(t, write(&buf, FormatJSON), name)
+		var doc struct {
+			SchemaVersion *int `json:"schema_version"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc), name)
+		if assert.NotNil(t, doc.SchemaVersion, name) {
+			assert.Equal(t, SchemaVersion, *doc.SchemaVersion, name)
+		}
+
+		buf.Reset()
+		require.NoError(t, write(&buf, FormatXML), name)
+		assert.Contains(t, buf.String(), "<schemaVersion>1</schemaVersion>", name)
+	}
+}
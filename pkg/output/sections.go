@@ -0,0 +1,173 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ListSection is one section of list output split by --group-by.
+//
+// Fields:
+//   - Name: Section key (rule, type, or group name); the JSON object key
+//   - Summary: Statistics for the packages in the section
+//   - Packages: Package entries in the section
+type ListSection struct {
+	Name     string        `json:"-" xml:"name,attr"`
+	Summary  ListSummary   `json:"summary" xml:"summary"`
+	Packages []ListPackage `json:"packages" xml:"packages>package"`
+}
+
+// ListSections is an ordered list of list sections.
+//
+// It marshals to a JSON object keyed by section name, preserving section order.
+type ListSections []ListSection
+
+// MarshalJSON encodes the sections as an object keyed by section name.
+//
+// Returns:
+//   - []byte: JSON object with one key per section, in order
+//   - error: When a section cannot be encoded
+func (s ListSections) MarshalJSON() ([]byte, error) {
+	return marshalSections(len(s), func(i int) (string, any) {
+		return s[i].Name, s[i]
+	})
+}
+
+// UnmarshalJSON decodes an object keyed by section name, preserving key order.
+//
+// Parameters:
+//   - data: JSON object produced by MarshalJSON
+//
+// Returns:
+//   - error: When data is not an object of sections
+func (s *ListSections) UnmarshalJSON(data []byte) error {
+	*s = nil
+	return unmarshalSections(data, func(name string, dec *json.Decoder) error {
+		section := ListSection{Name: name}
+		if err := dec.Decode(&section); err != nil {
+			return err
+		}
+		*s = append(*s, section)
+		return nil
+	})
+}
+
+// OutdatedSection is one section of outdated output split by --group-by.
+//
+// Fields:
+//   - Name: Section key (rule, type, or group name); the JSON object key
+//   - Summary: Statistics for the packages in the section
+//   - Packages: Package entries in the section
+type OutdatedSection struct {
+	Name     string            `json:"-" xml:"name,attr"`
+	Summary  OutdatedSummary   `json:"summary" xml:"summary"`
+	Packages []OutdatedPackage `json:"packages" xml:"packages>package"`
+}
+
+// OutdatedSections is an ordered list of outdated sections.
+//
+// It marshals to a JSON object keyed by section name, preserving section order.
+type OutdatedSections []OutdatedSection
+
+// MarshalJSON encodes the sections as an object keyed by section name.
+//
+// Returns:
+//   - []byte: JSON object with one key per section, in order
+//   - error: When a section cannot be encoded
+func (s OutdatedSections) MarshalJSON() ([]byte, error) {
+	return marshalSections(len(s), func(i int) (string, any) {
+		return s[i].Name, s[i]
+	})
+}
+
+// UnmarshalJSON decodes an object keyed by section name, preserving key order.
+//
+// Parameters:
+//   - data: JSON object produced by MarshalJSON
+//
+// Returns:
+//   - error: When data is not an object of sections
+func (s *OutdatedSections) UnmarshalJSON(data []byte) error {
+	*s = nil
+	return unmarshalSections(data, func(name string, dec *json.Decoder) error {
+		section := OutdatedSection{Name: name}
+		if err := dec.Decode(&section); err != nil {
+			return err
+		}
+		*s = append(*s, section)
+		return nil
+	})
+}
+
+// marshalSections encodes named values as a JSON object with ordered keys.
+//
+// encoding/json sorts map keys, so the object is written by hand to keep the
+// display order of the sections.
+//
+// Parameters:
+//   - n: Number of sections
+//   - section: Returns the key and value of the i-th section
+//
+// Returns:
+//   - []byte: JSON object
+//   - error: When a key or value cannot be encoded
+func marshalSections(n int, section func(i int) (string, any)) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 0; i < n; i++ {
+		name, value := section(i)
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalSections walks a JSON object of sections in key order.
+//
+// Parameters:
+//   - data: JSON object keyed by section name
+//   - section: Decodes the value of one section from dec
+//
+// Returns:
+//   - error: When data is not an object or a section cannot be decoded
+func unmarshalSections(data []byte, section func(name string, dec *json.Decoder) error) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("sections must be a JSON object")
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := tok.(string)
+		if err := section(name, dec); err != nil {
+			return err
+		}
+	}
+
+	_, err = dec.Token()
+	return err
+}
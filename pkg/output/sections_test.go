@@ -0,0 +1,103 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteListResult_Sections tests list output split into sections.
+//
+// It verifies:
+//   - JSON sections are an object keyed by section name in section order
+//   - Sections round-trip through json.Unmarshal with their names and summaries
+//   - CSV adds a leading SECTION column
+//   - XML writes one section element per section with a name attribute
+func TestWriteListResult_Sections(t *testing.T) {
+	express := ListPackage{Rule: "npm", PM: "js", Type: "prod", Name: "express", Version: "4.18.0"}
+	cobra := ListPackage{Rule: "mod", PM: "golang", Type: "prod", Name: "github.com/spf13/cobra", Version: "v1.8.0"}
+	result := &ListResult{
+		Summary:  ListSummary{TotalPackages: 2},
+		Packages: []ListPackage{express, cobra},
+		GroupBy:  "rule",
+		Sections: ListSections{
+			{Name: "npm", Summary: ListSummary{TotalPackages: 1}, Packages: []ListPackage{express}},
+			{Name: "mod", Summary: ListSummary{TotalPackages: 1}, Packages: []ListPackage{cobra}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteListResult(&buf, FormatJSON, result))
+	out := buf.String()
+	assert.Contains(t, out, `"group_by":"rule"`)
+	assert.Less(t, strings.Index(out, `"npm":{`), strings.Index(out, `"mod":{`), "sections keep their order")
+
+	var parsed ListResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	require.Len(t, parsed.Sections, 2)
+	assert.Equal(t, "npm", parsed.Sections[0].Name)
+	assert.Equal(t, 1, parsed.Sections[0].Summary.TotalPackages)
+	assert.Equal(t, "express", parsed.Sections[0].Packages[0].Name)
+	assert.Equal(t, "mod", parsed.Sections[1].Name)
+
+	buf.Reset()
+	require.NoError(t, WriteListResult(&buf, FormatCSV, result))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "SECTION,RULE,PM,TYPE,CONSTRAINT,VERSION,INSTALLED,STATUS,GROUP,NAME,SOURCE,LINE", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "npm,npm,js"))
+	assert.True(t, strings.HasPrefix(lines[2], "mod,mod,golang"))
+
+	buf.Reset()
+	require.NoError(t, WriteListResult(&buf, FormatXML, result))
+	assert.Contains(t, buf.String(), `<listResult groupBy="rule">`)
+	assert.Contains(t, buf.String(), `<section name="npm">`)
+}
+
+// TestWriteOutdatedResult_Sections tests outdated output split into sections.
+//
+// It verifies:
+//   - JSON sections carry a per-section summary and round-trip through json.Unmarshal
+//   - CSV adds a leading SECTION column
+//   - Flat results omit group_by and sections
+func TestWriteOutdatedResult_Sections(t *testing.T) {
+	react := OutdatedPackage{Rule: "npm", Type: "prod", Name: "react", Status: "Outdated"}
+	jest := OutdatedPackage{Rule: "npm", Type: "dev", Name: "jest", Status: "UpToDate"}
+	result := &OutdatedResult{
+		Summary:  OutdatedSummary{TotalPackages: 2, OutdatedPackages: 1, UpToDatePackages: 1},
+		Packages: []OutdatedPackage{jest, react},
+		GroupBy:  "type",
+		Sections: OutdatedSections{
+			{Name: "dev", Summary: OutdatedSummary{TotalPackages: 1, UpToDatePackages: 1}, Packages: []OutdatedPackage{jest}},
+			{Name: "prod", Summary: OutdatedSummary{TotalPackages: 1, OutdatedPackages: 1}, Packages: []OutdatedPackage{react}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteOutdatedResult(&buf, FormatJSON, result))
+
+	var parsed OutdatedResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	require.Len(t, parsed.Sections, 2)
+	assert.Equal(t, "prod", parsed.Sections[1].Name)
+	assert.Equal(t, 1, parsed.Sections[1].Summary.OutdatedPackages)
+
+	buf.Reset()
+	require.NoError(t, WriteOutdatedResult(&buf, FormatCSV, result))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "SECTION,RULE"))
+	assert.True(t, strings.HasPrefix(lines[1], "dev,npm"))
+
+	buf.Reset()
+	flat := &OutdatedResult{Packages: []OutdatedPackage{react}}
+	require.NoError(t, WriteOutdatedResult(&buf, FormatJSON, flat))
+	assert.NotContains(t, buf.String(), "sections")
+	assert.NotContains(t, buf.String(), "group_by")
+
+	assert.Error(t, json.Unmarshal([]byte(`{"sections": []}`), &parsed))
+}
@@ -0,0 +1,90 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// DefaultShieldsLabel is the badge label used when none is configured.
+const DefaultShieldsLabel = "outdated"
+
+// ShieldsColor picks the badge color for outdated counts from Min upwards.
+//
+// Fields:
+//   - Min: Smallest outdated count the color applies to
+//   - Color: shields.io color name or hex value (e.g. "orange", "e05d44")
+type ShieldsColor struct {
+	Min   int
+	Color string
+}
+
+// DefaultShieldsColors are the badge color thresholds used when none are configured.
+var DefaultShieldsColors = []ShieldsColor{
+	{Min: 0, Color: "brightgreen"},
+	{Min: 1, Color: "yellow"},
+	{Min: 10, Color: "orange"},
+	{Min: 25, Color: "red"},
+}
+
+// ShieldsBadge is a shields.io endpoint badge.
+//
+// See https://shields.io/badges/endpoint-badge for the schema.
+//
+// Fields:
+//   - SchemaVersion: Endpoint schema version, always 1
+//   - Label: Left-hand text of the badge
+//   - Message: Right-hand text of the badge, the outdated count
+//   - Color: Right-hand background color
+type ShieldsBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// NewShieldsBadge builds the badge for an outdated count.
+//
+// It performs the following operations:
+//   - Step 1: Fall back to DefaultShieldsLabel and DefaultShieldsColors when unset
+//   - Step 2: Pick the color of the highest threshold whose Min is at most count
+//
+// Parameters:
+//   - count: Number of outdated packages
+//   - label: Badge label; empty uses DefaultShieldsLabel
+//   - colors: Color thresholds in any order; empty uses DefaultShieldsColors
+//
+// Returns:
+//   - ShieldsBadge: The badge; the color is "lightgrey" when no threshold applies
+func NewShieldsBadge(count int, label string, colors []ShieldsColor) ShieldsBadge {
+	if label == "" {
+		label = DefaultShieldsLabel
+	}
+	if len(colors) == 0 {
+		colors = DefaultShieldsColors
+	}
+
+	sorted := append([]ShieldsColor{}, colors...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Min < sorted[j].Min })
+	color := "lightgrey"
+	for _, threshold := range sorted {
+		if count >= threshold.Min {
+			color = threshold.Color
+		}
+	}
+
+	return ShieldsBadge{SchemaVersion: 1, Label: label, Message: strconv.Itoa(count), Color: color}
+}
+
+// WriteShieldsBadge writes a badge as shields.io endpoint JSON.
+//
+// Parameters:
+//   - w: Writer to output the badge to
+//   - badge: The badge to write
+//
+// Returns:
+//   - error: When encoding or writing fails; returns nil on success
+func WriteShieldsBadge(w io.Writer, badge ShieldsBadge) error {
+	return json.NewEncoder(w).Encode(badge)
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewShieldsBadge tests building the shields.io badge for an outdated count.
+//
+// It verifies:
+//   - The default label and colors are used when none are configured
+//   - The color of the highest reached threshold wins, whatever the threshold order
+//   - Counts below every threshold fall back to lightgrey
+func TestNewShieldsBadge(t *testing.T) {
+	assert.Equal(t, ShieldsBadge{SchemaVersion: 1, Label: "outdated", Message: "0", Color: "brightgreen"}, NewShieldsBadge(0, "", nil))
+	assert.Equal(t, "yellow", NewShieldsBadge(9, "", nil).Color)
+	assert.Equal(t, "orange", NewShieldsBadge(12, "", nil).Color)
+	assert.Equal(t, "red", NewShieldsBadge(25, "", nil).Color)
+
+	colors := []ShieldsColor{{Min: 5, Color: "critical"}, {Min: 2, Color: "important"}}
+	badge := NewShieldsBadge(3, "deps", colors)
+	assert.Equal(t, "deps", badge.Label)
+	assert.Equal(t, "important", badge.Color)
+	assert.Equal(t, "critical", NewShieldsBadge(7, "deps", colors).Color)
+	assert.Equal(t, "lightgrey", NewShieldsBadge(1, "deps", colors).Color)
+}
+
+// TestWriteShieldsBadge tests writing the shields.io endpoint JSON.
+//
+// It verifies:
+//   - The badge is written with the endpoint schema keys
+func TestWriteShieldsBadge(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteShieldsBadge(&buf, NewShieldsBadge(12, "", nil)))
+	assert.Equal(t, `{"schemaVersion":1,"label":"outdated","message":"12","color":"orange"}`+"\n", buf.String())
+}
@@ -1,17 +1,23 @@
 package output
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
 
 // ScanResult represents the output data for the scan command.
 //
 // Fields:
 //   - XMLName: XML root element name (used only for XML marshaling)
+//   - SchemaVersion: Version of the output schema, set to SchemaVersion when written
 //   - Summary: Aggregate statistics about the scan operation
 //   - Files: List of individual file entries discovered during scanning
 type ScanResult struct {
-	XMLName xml.Name    `json:"-" xml:"scanResult"`
-	Summary ScanSummary `json:"summary" xml:"summary"`
-	Files   []ScanEntry `json:"files" xml:"files>file"`
+	XMLName       xml.Name    `json:"-" xml:"scanResult"`
+	SchemaVersion int         `json:"schema_version" xml:"schemaVersion"`
+	Summary       ScanSummary `json:"summary" xml:"summary"`
+	Files         []ScanEntry `json:"files" xml:"files>file"`
 }
 
 // ScanSummary holds summary statistics for scan results.
@@ -50,18 +56,136 @@ type ScanEntry struct {
 	Error  string `json:"error,omitempty" xml:"error,omitempty"`
 }
 
+// DeepScanResult represents the output data for scan --deep.
+//
+// Fields:
+//   - XMLName: XML root element name (used only for XML marshaling)
+//   - SchemaVersion: Version of the output schema, set to SchemaVersion when written
+//   - Summary: Aggregate statistics about the resolved dependency set
+//   - Packages: Direct and transitive packages of every scanned manifest
+//   - Unsupported: Unsupported messages for rules whose trees could not be resolved (omitted if empty)
+//   - Errors: Manifests whose tree resolution failed (omitted if empty)
+type DeepScanResult struct {
+	XMLName       xml.Name          `json:"-" xml:"deepScanResult"`
+	SchemaVersion int               `json:"schema_version" xml:"schemaVersion"`
+	Summary       DeepScanSummary   `json:"summary" xml:"summary"`
+	Packages      []DeepScanPackage `json:"packages" xml:"packages>package"`
+	Unsupported   []string          `json:"unsupported,omitempty" xml:"unsupported>message,omitempty"`
+	Errors        []string          `json:"errors,omitempty" xml:"errors>error,omitempty"`
+}
+
+// DeepScanSummary holds summary statistics for scan --deep results.
+//
+// Fields:
+//   - Directory: The directory path that was scanned
+//   - TotalPackages: Number of packages in the resolved dependency set
+//   - DirectPackages: Packages declared by a manifest
+//   - TransitivePackages: Packages only required by other packages
+type DeepScanSummary struct {
+	Directory          string `json:"directory" xml:"directory"`
+	TotalPackages      int    `json:"total_packages" xml:"totalPackages"`
+	DirectPackages     int    `json:"direct_packages" xml:"directPackages"`
+	TransitivePackages int    `json:"transitive_packages" xml:"transitivePackages"`
+}
+
+// DeepScanPackage represents a package of the resolved dependency set.
+//
+// Fields:
+//   - Rule: The rule whose manifest requires the package
+//   - PM: Package manager identifier (e.g., "js", "golang")
+//   - Name: Package name
+//   - Version: Declared version for direct packages, resolved version otherwise
+//   - InstalledVersion: Resolved version (empty if the tree only names a requirement)
+//   - Status: Install status (e.g., "LockFound", "VersionMissing")
+//   - Direct: Whether the manifest declares the package itself
+//   - Source: Manifest the package belongs to
+type DeepScanPackage struct {
+	Rule             string `json:"rule" xml:"rule"`
+	PM               string `json:"pm" xml:"pm"`
+	Name             string `json:"name" xml:"name"`
+	Version          string `json:"version" xml:"version"`
+	InstalledVersion string `json:"installed_version" xml:"installedVersion"`
+	Status           string `json:"status" xml:"status"`
+	Direct           bool   `json:"direct" xml:"direct"`
+	Source           string `json:"source" xml:"source"`
+}
+
+// PlanDiffResult represents the output data for update --plan-diff.
+//
+// Fields:
+//   - XMLName: XML root element name (used only for XML marshaling)
+//   - SchemaVersion: Version of the output schema, set to SchemaVersion when written
+//   - Summary: Counts of added, removed, changed and unchanged packages
+//   - Changes: Packages whose pending update differs between the two plans
+type PlanDiffResult struct {
+	XMLName       xml.Name        `json:"-" xml:"planDiffResult"`
+	SchemaVersion int             `json:"schema_version" xml:"schemaVersion"`
+	Summary       PlanDiffSummary `json:"summary" xml:"summary"`
+	Changes       []PlanDiffEntry `json:"changes" xml:"changes>change"`
+}
+
+// PlanDiffSummary holds summary statistics for update --plan-diff results.
+//
+// Fields:
+//   - OldPlan: Path of the old plan file
+//   - NewPlan: Path of the new plan file
+//   - Added: Packages only the new plan updates
+//   - Removed: Packages only the old plan updates
+//   - Changed: Packages both plans update to different targets
+//   - Unchanged: Packages both plans update to the same target
+type PlanDiffSummary struct {
+	OldPlan   string `json:"old_plan" xml:"oldPlan"`
+	NewPlan   string `json:"new_plan" xml:"newPlan"`
+	Added     int    `json:"added" xml:"added"`
+	Removed   int    `json:"removed" xml:"removed"`
+	Changed   int    `json:"changed" xml:"changed"`
+	Unchanged int    `json:"unchanged" xml:"unchanged"`
+}
+
+// PlanDiffEntry represents a package whose pending update differs between two plans.
+//
+// Fields:
+//   - Rule: Rule name that manages this package
+//   - PM: Package manager identifier (e.g., "js", "golang")
+//   - Type: Dependency type (e.g., "prod", "dev")
+//   - Name: Package name
+//   - Change: "added", "removed" or "changed"
+//   - Original: Declared version the update starts from
+//   - OldTarget: Target in the old plan (omitted for added packages)
+//   - NewTarget: Target in the new plan (omitted for removed packages)
+//   - Source: Manifest the package is declared in
+type PlanDiffEntry struct {
+	Rule      string `json:"rule" xml:"rule"`
+	PM        string `json:"pm" xml:"pm"`
+	Type      string `json:"type" xml:"type"`
+	Name      string `json:"name" xml:"name"`
+	Change    string `json:"change" xml:"change"`
+	Original  string `json:"original" xml:"original"`
+	OldTarget string `json:"old_target,omitempty" xml:"oldTarget,omitempty"`
+	NewTarget string `json:"new_target,omitempty" xml:"newTarget,omitempty"`
+	Source    string `json:"source" xml:"source"`
+}
+
 // ListResult represents the output data for the list command.
 //
 // Fields:
 //   - XMLName: XML root element name (used only for XML marshaling)
+//   - SchemaVersion: Version of the output schema, set to SchemaVersion when written
 //   - Summary: Aggregate statistics about the list operation
 //   - Packages: List of package entries
+//   - GroupBy: Section key selected with --group-by (omitted when output is flat)
+//   - Sections: Packages split by GroupBy, keyed by section name (omitted when output is flat)
 //   - Warnings: Warning messages generated during the list operation (omitted if empty)
+//   - WarningDetails: The same warnings with stable codes, plus per-package status warnings (omitted if empty)
 type ListResult struct {
-	XMLName  xml.Name      `json:"-" xml:"listResult"`
-	Summary  ListSummary   `json:"summary" xml:"summary"`
-	Packages []ListPackage `json:"packages" xml:"packages>package"`
-	Warnings []string      `json:"warnings,omitempty" xml:"warnings>warning,omitempty"`
+	XMLName        xml.Name           `json:"-" xml:"listResult"`
+	SchemaVersion  int                `json:"schema_version" xml:"schemaVersion"`
+	Summary        ListSummary        `json:"summary" xml:"summary"`
+	Packages       []ListPackage      `json:"packages" xml:"packages>package"`
+	GroupBy        string             `json:"group_by,omitempty" xml:"groupBy,attr,omitempty"`
+	Sections       ListSections       `json:"sections,omitempty" xml:"sections>section,omitempty"`
+	Warnings       []string           `json:"warnings,omitempty" xml:"warnings>warning,omitempty"`
+	WarningDetails []warnings.Warning `json:"warning_details,omitempty" xml:"warningDetails>warning,omitempty"`
 }
 
 // ListSummary holds summary statistics for list results.
@@ -84,33 +208,47 @@ type ListSummary struct {
 //   - Status: Current status of the package (e.g., "ok", "missing")
 //   - Group: Optional grouping identifier (omitted if empty)
 //   - Name: Package name
+//   - Source: Manifest file the package was declared in (omitted if unknown)
+//   - Line: Line of the declaration in Source (omitted if unknown)
+//   - UsedBy: Projects referencing a centrally managed .NET package (omitted if empty)
 type ListPackage struct {
-	Rule             string `json:"rule" xml:"rule"`
-	PM               string `json:"pm" xml:"pm"`
-	Type             string `json:"type" xml:"type"`
-	Constraint       string `json:"constraint" xml:"constraint"`
-	Version          string `json:"version" xml:"version"`
-	InstalledVersion string `json:"installed_version" xml:"installedVersion"`
-	Status           string `json:"status" xml:"status"`
-	Group            string `json:"group,omitempty" xml:"group,omitempty"`
-	Name             string `json:"name" xml:"name"`
-	IgnoreReason     string `json:"ignore_reason,omitempty" xml:"ignoreReason,omitempty"`
+	Rule             string   `json:"rule" xml:"rule"`
+	PM               string   `json:"pm" xml:"pm"`
+	Type             string   `json:"type" xml:"type"`
+	Constraint       string   `json:"constraint" xml:"constraint"`
+	Version          string   `json:"version" xml:"version"`
+	InstalledVersion string   `json:"installed_version" xml:"installedVersion"`
+	Status           string   `json:"status" xml:"status"`
+	Group            string   `json:"group,omitempty" xml:"group,omitempty"`
+	Name             string   `json:"name" xml:"name"`
+	Source           string   `json:"source,omitempty" xml:"source,omitempty"`
+	Line             int      `json:"line,omitempty" xml:"line,omitempty"`
+	IgnoreReason     string   `json:"ignore_reason,omitempty" xml:"ignoreReason,omitempty"`
+	UsedBy           []string `json:"used_by,omitempty" xml:"usedBy>project,omitempty"`
 }
 
 // OutdatedResult represents the output data for the outdated command.
 //
 // Fields:
 //   - XMLName: XML root element name (used only for XML marshaling)
+//   - SchemaVersion: Version of the output schema, set to SchemaVersion when written
 //   - Summary: Aggregate statistics about the outdated operation
 //   - Packages: List of package entries with version information
+//   - GroupBy: Section key selected with --group-by (omitted when output is flat)
+//   - Sections: Packages split by GroupBy, keyed by section name (omitted when output is flat)
 //   - Warnings: Warning messages generated during the outdated check (omitted if empty)
+//   - WarningDetails: The same warnings with stable codes, plus per-package status warnings (omitted if empty)
 //   - Errors: Error messages generated during the outdated check (omitted if empty)
 type OutdatedResult struct {
-	XMLName  xml.Name          `json:"-" xml:"outdatedResult"`
-	Summary  OutdatedSummary   `json:"summary" xml:"summary"`
-	Packages []OutdatedPackage `json:"packages" xml:"packages>package"`
-	Warnings []string          `json:"warnings,omitempty" xml:"warnings>warning,omitempty"`
-	Errors   []string          `json:"errors,omitempty" xml:"errors>error,omitempty"`
+	XMLName        xml.Name           `json:"-" xml:"outdatedResult"`
+	SchemaVersion  int                `json:"schema_version" xml:"schemaVersion"`
+	Summary        OutdatedSummary    `json:"summary" xml:"summary"`
+	Packages       []OutdatedPackage  `json:"packages" xml:"packages>package"`
+	GroupBy        string             `json:"group_by,omitempty" xml:"groupBy,attr,omitempty"`
+	Sections       OutdatedSections   `json:"sections,omitempty" xml:"sections>section,omitempty"`
+	Warnings       []string           `json:"warnings,omitempty" xml:"warnings>warning,omitempty"`
+	Errors         []string           `json:"errors,omitempty" xml:"errors>error,omitempty"`
+	WarningDetails []warnings.Warning `json:"warning_details,omitempty" xml:"warningDetails>warning,omitempty"`
 }
 
 // OutdatedSummary holds summary statistics for outdated results.
@@ -145,9 +283,13 @@ type OutdatedSummary struct {
 //   - Major: Latest available major version
 //   - Minor: Latest available minor version
 //   - Patch: Latest available patch version
+//   - Age: How long the installed version has been superseded (omitted unless --age or --min-age is set)
 //   - Status: Current status (e.g., "outdated", "up-to-date", "failed")
 //   - Group: Optional grouping identifier (omitted if empty)
 //   - Name: Package name
+//   - Source: Manifest file the package was declared in (omitted if unknown)
+//   - Line: Line of the declaration in Source (omitted if unknown)
+//   - Repository: Source repository URL found during the version lookup (omitted if unknown)
 //   - Error: Error message if the version check failed (omitted if empty)
 type OutdatedPackage struct {
 	Rule             string `json:"rule" xml:"rule"`
@@ -159,9 +301,13 @@ type OutdatedPackage struct {
 	Major            string `json:"major" xml:"major"`
 	Minor            string `json:"minor" xml:"minor"`
 	Patch            string `json:"patch" xml:"patch"`
+	Age              string `json:"age,omitempty" xml:"age,omitempty"`
 	Status           string `json:"status" xml:"status"`
 	Group            string `json:"group,omitempty" xml:"group,omitempty"`
 	Name             string `json:"name" xml:"name"`
+	Source           string `json:"source,omitempty" xml:"source,omitempty"`
+	Line             int    `json:"line,omitempty" xml:"line,omitempty"`
+	Repository       string `json:"repository,omitempty" xml:"repository,omitempty"`
 	Error            string `json:"error,omitempty" xml:"error,omitempty"`
 }
 
@@ -169,16 +315,26 @@ type OutdatedPackage struct {
 //
 // Fields:
 //   - XMLName: XML root element name (used only for XML marshaling)
+//   - SchemaVersion: Version of the output schema, set to SchemaVersion when written
 //   - Summary: Aggregate statistics about the update operation
 //   - Packages: List of package entries with update information
 //   - Warnings: Warning messages generated during the update operation (omitted if empty)
+//   - WarningDetails: The same warnings with stable codes, plus per-package status warnings (omitted if empty)
 //   - Errors: Error messages generated during the update operation (omitted if empty)
+//   - SystemTestFailures: System test runs that failed after updates (an empty list when none failed)
+//   - Dedupe: Dedupe commands run by --dedupe (omitted if none ran)
+//   - Unsupported: Unsupported package messages, rendered only by the markdown format
 type UpdateResult struct {
-	XMLName  xml.Name        `json:"-" xml:"updateResult"`
-	Summary  UpdateSummary   `json:"summary" xml:"summary"`
-	Packages []UpdatePackage `json:"packages" xml:"packages>package"`
-	Warnings []string        `json:"warnings,omitempty" xml:"warnings>warning,omitempty"`
-	Errors   []string        `json:"errors,omitempty" xml:"errors>error,omitempty"`
+	XMLName            xml.Name           `json:"-" xml:"updateResult"`
+	SchemaVersion      int                `json:"schema_version" xml:"schemaVersion"`
+	Summary            UpdateSummary      `json:"summary" xml:"summary"`
+	Packages           []UpdatePackage    `json:"packages" xml:"packages>package"`
+	Warnings           []string           `json:"warnings,omitempty" xml:"warnings>warning,omitempty"`
+	WarningDetails     []warnings.Warning `json:"warning_details,omitempty" xml:"warningDetails>warning,omitempty"`
+	Errors             []string           `json:"errors,omitempty" xml:"errors>error,omitempty"`
+	SystemTestFailures []UpdateSystemTest `json:"system_test_failures" xml:"systemTestFailures>systemTestFailure,omitempty"`
+	Dedupe             []UpdateDedupeStep `json:"dedupe,omitempty" xml:"dedupe>step,omitempty"`
+	Unsupported        []string           `json:"-" xml:"-"`
 }
 
 // UpdateSummary holds summary statistics for update results.
@@ -187,12 +343,14 @@ type UpdateResult struct {
 //   - TotalPackages: Total number of packages processed
 //   - UpdatedPackages: Number of packages successfully updated
 //   - FailedPackages: Number of packages that failed to update
+//   - DeferredPackages: Number of packages whose update was held back by --limit (omitted if zero)
 //   - DryRun: Whether this was a dry-run (no actual updates performed)
 type UpdateSummary struct {
-	TotalPackages   int  `json:"total_packages" xml:"totalPackages"`
-	UpdatedPackages int  `json:"updated_packages" xml:"updatedPackages"`
-	FailedPackages  int  `json:"failed_packages" xml:"failedPackages"`
-	DryRun          bool `json:"dry_run" xml:"dryRun"`
+	TotalPackages    int  `json:"total_packages" xml:"totalPackages"`
+	UpdatedPackages  int  `json:"updated_packages" xml:"updatedPackages"`
+	FailedPackages   int  `json:"failed_packages" xml:"failedPackages"`
+	DeferredPackages int  `json:"deferred_packages,omitempty" xml:"deferredPackages,omitempty"`
+	DryRun           bool `json:"dry_run" xml:"dryRun"`
 }
 
 // UpdatePackage represents a package entry in the update output.
@@ -208,17 +366,137 @@ type UpdateSummary struct {
 //   - Status: Current status (e.g., "updated", "failed", "skipped")
 //   - Group: Optional grouping identifier (omitted if empty)
 //   - Name: Package name
+//   - Source: Manifest file the package was declared in (omitted if unknown)
+//   - Line: Line of the declaration in Source (omitted if unknown)
+//   - Repository: Source repository URL found during the version lookup (omitted if unknown)
 //   - Error: Error message if the update failed (omitted if empty)
+//   - Diff: Unified diff of the manifest edit when --show-diff is used (omitted if empty)
+//   - Changelog: Release notes URL and snippet when --changelog is used (omitted if not fetched)
+//   - DurationMs: Milliseconds spent applying the update (0 for skipped packages)
+//   - PlanDurationMs: Milliseconds spent looking up versions during planning (omitted if no lookup ran)
 type UpdatePackage struct {
-	Rule             string `json:"rule" xml:"rule"`
-	PM               string `json:"pm" xml:"pm"`
-	Type             string `json:"type" xml:"type"`
-	Constraint       string `json:"constraint" xml:"constraint"`
-	Version          string `json:"version" xml:"version"`
-	InstalledVersion string `json:"installed_version" xml:"installedVersion"`
-	Target           string `json:"target" xml:"target"`
-	Status           string `json:"status" xml:"status"`
-	Group            string `json:"group,omitempty" xml:"group,omitempty"`
-	Name             string `json:"name" xml:"name"`
-	Error            string `json:"error,omitempty" xml:"error,omitempty"`
+	Rule             string           `json:"rule" xml:"rule"`
+	PM               string           `json:"pm" xml:"pm"`
+	Type             string           `json:"type" xml:"type"`
+	Constraint       string           `json:"constraint" xml:"constraint"`
+	Version          string           `json:"version" xml:"version"`
+	InstalledVersion string           `json:"installed_version" xml:"installedVersion"`
+	Target           string           `json:"target" xml:"target"`
+	Status           string           `json:"status" xml:"status"`
+	Group            string           `json:"group,omitempty" xml:"group,omitempty"`
+	Name             string           `json:"name" xml:"name"`
+	Source           string           `json:"source,omitempty" xml:"source,omitempty"`
+	Line             int              `json:"line,omitempty" xml:"line,omitempty"`
+	Repository       string           `json:"repository,omitempty" xml:"repository,omitempty"`
+	Error            string           `json:"error,omitempty" xml:"error,omitempty"`
+	Diff             string           `json:"diff,omitempty" xml:"diff,omitempty"`
+	Changelog        *UpdateChangelog `json:"changelog,omitempty" xml:"changelog,omitempty"`
+	DurationMs       int64            `json:"duration_ms" xml:"durationMs"`
+	PlanDurationMs   int64            `json:"plan_duration_ms,omitempty" xml:"planDurationMs,omitempty"`
+}
+
+// UpdateChangelog holds the release notes fetched for an update.
+//
+// Fields:
+//   - URL: Page with the full release notes
+//   - Snippet: Truncated release notes text (omitted if the source had none)
+type UpdateChangelog struct {
+	URL     string `json:"url" xml:"url"`
+	Snippet string `json:"snippet,omitempty" xml:"snippet,omitempty"`
+}
+
+// UpdateSystemTest represents a failed system test run in the update output.
+//
+// Fields:
+//   - Package: Package (or "group") whose update triggered the test run
+//   - Critical: Whether the failure was critical and caused a rollback
+//   - Details: Formatted test output describing the failed tests
+//   - Tests: The failed tests of the run (empty when the result cannot list them)
+type UpdateSystemTest struct {
+	Package  string           `json:"package" xml:"package"`
+	Critical bool             `json:"critical" xml:"critical"`
+	Details  string           `json:"details" xml:"details"`
+	Tests    []UpdateTestFail `json:"tests" xml:"tests>test,omitempty"`
+}
+
+// UpdateDedupeStep represents a dedupe command run by update --dedupe.
+//
+// Fields:
+//   - Rule: Rule whose update.dedupe command ran
+//   - Dir: Lock file directory the command ran in
+//   - Status: "ok", "warning" (failed, updates kept), or "failed" (rule rolled back)
+//   - Error: The failure; omitted on success
+//   - RolledBack: Number of packages rolled back; omitted when zero
+type UpdateDedupeStep struct {
+	Rule       string `json:"rule" xml:"rule"`
+	Dir        string `json:"dir" xml:"dir"`
+	Status     string `json:"status" xml:"status"`
+	Error      string `json:"error,omitempty" xml:"error,omitempty"`
+	RolledBack int    `json:"rolled_back,omitempty" xml:"rolledBack,omitempty"`
+}
+
+// UpdateTestFail represents a single failed system test in the update output.
+//
+// Fields:
+//   - Name: Name of the test from the system_tests config
+//   - Critical: Whether the test stops the update on failure (continue_on_fail is not set)
+//   - Error: Error message of the failure (omitted if empty)
+//   - Output: Captured stdout and stderr of the test command (omitted if empty)
+type UpdateTestFail struct {
+	Name     string `json:"name" xml:"name"`
+	Critical bool   `json:"critical" xml:"critical"`
+	Error    string `json:"error,omitempty" xml:"error,omitempty"`
+	Output   string `json:"output,omitempty" xml:"output,omitempty"`
+}
+
+// ValidateResult represents the output data for the validate command.
+//
+// Fields:
+//   - XMLName: XML root element name (used only for XML marshaling)
+//   - SchemaVersion: Version of the output schema, set to SchemaVersion when written
+//   - Summary: Aggregate statistics about the validation
+//   - Valid: Whether configuration and environment passed every check
+//   - Commands: One entry per rule, kind, and command checked
+//   - Errors: Every problem found, formatted as in the human output
+//   - Warnings: Warning messages collected while loading packages
+type ValidateResult struct {
+	XMLName       xml.Name          `json:"-" xml:"validateResult"`
+	SchemaVersion int               `json:"schema_version" xml:"schemaVersion"`
+	Summary       ValidateSummary   `json:"summary" xml:"summary"`
+	Valid         bool              `json:"valid" xml:"valid"`
+	Commands      []ValidateCommand `json:"commands" xml:"commands>command"`
+	Errors        []string          `json:"errors,omitempty" xml:"errors>error,omitempty"`
+	Warnings      []string          `json:"warnings,omitempty" xml:"warnings>warning,omitempty"`
+}
+
+// ValidateSummary holds summary statistics for validate results.
+//
+// Fields:
+//   - TotalPackages: Number of packages validated after filtering
+//   - Rules: Number of distinct rules among the packages
+//   - Problems: Number of problems found
+//   - MissingCommands: Number of distinct commands not found
+type ValidateSummary struct {
+	TotalPackages   int `json:"total_packages" xml:"totalPackages"`
+	Rules           int `json:"rules" xml:"rules"`
+	Problems        int `json:"problems" xml:"problems"`
+	MissingCommands int `json:"missing_commands" xml:"missingCommands"`
+}
+
+// ValidateCommand represents the pre-flight check of one command of one rule.
+//
+// Fields:
+//   - Rule: The rule the command belongs to
+//   - Kind: Where the rule uses the command: "outdated", "update", or "lock"
+//   - Command: The command name
+//   - Found: Whether the command is available
+//   - Path: Resolved executable path (omitted when missing or found only as a shell alias or function)
+//   - Error: Why the command is unavailable, with its resolution hint (omitted if found)
+type ValidateCommand struct {
+	Rule    string `json:"rule" xml:"rule"`
+	Kind    string `json:"kind" xml:"kind"`
+	Command string `json:"command" xml:"name"`
+	Found   bool   `json:"found" xml:"found"`
+	Path    string `json:"path,omitempty" xml:"path,omitempty"`
+	Error   string `json:"error,omitempty" xml:"error,omitempty"`
 }
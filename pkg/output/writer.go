@@ -3,12 +3,13 @@ package output
 import (
 	"fmt"
 	"io"
+	"strconv"
 )
 
 // WriteScanResult writes scan results in the specified format.
 //
 // It performs the following operations:
-//   - Step 1: Creates a formatter for the requested format
+//   - Step 1: Stamps the result with SchemaVersion and creates a formatter for the requested format
 //   - Step 2: Writes the scan result using format-specific logic
 //
 // Parameters:
@@ -19,6 +20,7 @@ import (
 // Returns:
 //   - error: When format is unsupported, returns an error; when write fails, returns the underlying error; otherwise returns nil
 func WriteScanResult(w io.Writer, format Format, result *ScanResult) error {
+	result.SchemaVersion = SchemaVersion
 	formatter := NewFormatter(format, w)
 
 	switch format {
@@ -50,10 +52,105 @@ func writeScanCSV(f *Formatter, result *ScanResult) error {
 	return f.WriteCSV(headers, rows)
 }
 
+// WriteDeepScanResult writes scan --deep results in the specified format.
+//
+// It performs the following operations:
+//   - Step 1: Stamps the result with SchemaVersion and creates a formatter for the requested format
+//   - Step 2: Writes the resolved packages using format-specific logic
+//
+// Parameters:
+//   - w: Destination writer for the output
+//   - format: Output format (FormatJSON, FormatXML, or FormatCSV)
+//   - result: Deep scan result data to write
+//
+// Returns:
+//   - error: When format is unsupported, returns an error; when write fails, returns the underlying error; otherwise returns nil
+func WriteDeepScanResult(w io.Writer, format Format, result *DeepScanResult) error {
+	result.SchemaVersion = SchemaVersion
+	formatter := NewFormatter(format, w)
+
+	switch format {
+	case FormatJSON:
+		return formatter.WriteJSON(result)
+	case FormatXML:
+		return formatter.WriteXML(result)
+	case FormatCSV:
+		return writeDeepScanCSV(formatter, result)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// writeDeepScanCSV writes scan --deep results in CSV format using the formatter.
+//
+// Parameters:
+//   - f: The formatter instance to use for CSV writing
+//   - result: Deep scan result data containing package entries
+//
+// Returns:
+//   - error: When CSV write fails; returns nil on success
+func writeDeepScanCSV(f *Formatter, result *DeepScanResult) error {
+	headers := []string{"RULE", "PM", "NAME", "VERSION", "INSTALLED", "STATUS", "DIRECT", "SOURCE"}
+	rows := make([][]string, 0, len(result.Packages))
+	for _, pkg := range result.Packages {
+		rows = append(rows, []string{pkg.Rule, pkg.PM, pkg.Name, pkg.Version, pkg.InstalledVersion, pkg.Status, strconv.FormatBool(pkg.Direct), pkg.Source})
+	}
+	return f.WriteCSV(headers, rows)
+}
+
+// WritePlanDiffResult writes update --plan-diff results in the specified format.
+//
+// It performs the following operations:
+//   - Step 1: Stamps the result with SchemaVersion and creates a formatter for the requested format
+//   - Step 2: Writes the plan changes using format-specific logic
+//
+// Parameters:
+//   - w: Destination writer for the output
+//   - format: Output format (FormatJSON, FormatXML, or FormatCSV)
+//   - result: Plan diff result data to write
+//
+// Returns:
+//   - error: When format is unsupported, returns an error; when write fails, returns the underlying error; otherwise returns nil
+func WritePlanDiffResult(w io.Writer, format Format, result *PlanDiffResult) error {
+	result.SchemaVersion = SchemaVersion
+	if result.Changes == nil {
+		result.Changes = []PlanDiffEntry{}
+	}
+	formatter := NewFormatter(format, w)
+
+	switch format {
+	case FormatJSON:
+		return formatter.WriteJSON(result)
+	case FormatXML:
+		return formatter.WriteXML(result)
+	case FormatCSV:
+		return writePlanDiffCSV(formatter, result)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// writePlanDiffCSV writes update --plan-diff results in CSV format using the formatter.
+//
+// Parameters:
+//   - f: The formatter instance to use for CSV writing
+//   - result: Plan diff result data containing the changed packages
+//
+// Returns:
+//   - error: When CSV write fails; returns nil on success
+func writePlanDiffCSV(f *Formatter, result *PlanDiffResult) error {
+	headers := []string{"RULE", "PM", "TYPE", "NAME", "CHANGE", "ORIGINAL", "OLD TARGET", "NEW TARGET", "SOURCE"}
+	rows := make([][]string, 0, len(result.Changes))
+	for _, entry := range result.Changes {
+		rows = append(rows, []string{entry.Rule, entry.PM, entry.Type, entry.Name, entry.Change, entry.Original, entry.OldTarget, entry.NewTarget, entry.Source})
+	}
+	return f.WriteCSV(headers, rows)
+}
+
 // WriteListResult writes list results in the specified format.
 //
 // It performs the following operations:
-//   - Step 1: Creates a formatter for the requested format
+//   - Step 1: Stamps the result with SchemaVersion and creates a formatter for the requested format
 //   - Step 2: Writes the list result using format-specific logic
 //
 // Parameters:
@@ -64,6 +161,7 @@ func writeScanCSV(f *Formatter, result *ScanResult) error {
 // Returns:
 //   - error: When format is unsupported, returns an error; when write fails, returns the underlying error; otherwise returns nil
 func WriteListResult(w io.Writer, format Format, result *ListResult) error {
+	result.SchemaVersion = SchemaVersion
 	formatter := NewFormatter(format, w)
 
 	switch format {
@@ -80,6 +178,9 @@ func WriteListResult(w io.Writer, format Format, result *ListResult) error {
 
 // writeListCSV writes list results in CSV format using the formatter.
 //
+// When the result is split into sections, a leading SECTION column holds the
+// section name and rows are written section by section.
+//
 // Parameters:
 //   - f: The formatter instance to use for CSV writing
 //   - result: List result data containing package entries
@@ -87,10 +188,9 @@ func WriteListResult(w io.Writer, format Format, result *ListResult) error {
 // Returns:
 //   - error: When CSV write fails; returns nil on success
 func writeListCSV(f *Formatter, result *ListResult) error {
-	headers := []string{"RULE", "PM", "TYPE", "CONSTRAINT", "VERSION", "INSTALLED", "STATUS", "GROUP", "NAME"}
-	rows := make([][]string, 0, len(result.Packages))
-	for _, pkg := range result.Packages {
-		rows = append(rows, []string{
+	headers := []string{"RULE", "PM", "TYPE", "CONSTRAINT", "VERSION", "INSTALLED", "STATUS", "GROUP", "NAME", "SOURCE", "LINE"}
+	row := func(pkg ListPackage) []string {
+		return []string{
 			pkg.Rule,
 			pkg.PM,
 			pkg.Type,
@@ -100,7 +200,24 @@ func writeListCSV(f *Formatter, result *ListResult) error {
 			pkg.Status,
 			pkg.Group,
 			pkg.Name,
-		})
+			pkg.Source,
+			formatLine(pkg.Line),
+		}
+	}
+
+	if len(result.Sections) > 0 {
+		rows := make([][]string, 0, len(result.Packages))
+		for _, section := range result.Sections {
+			for _, pkg := range section.Packages {
+				rows = append(rows, append([]string{section.Name}, row(pkg)...))
+			}
+		}
+		return f.WriteCSV(append([]string{"SECTION"}, headers...), rows)
+	}
+
+	rows := make([][]string, 0, len(result.Packages))
+	for _, pkg := range result.Packages {
+		rows = append(rows, row(pkg))
 	}
 	return f.WriteCSV(headers, rows)
 }
@@ -108,7 +225,7 @@ func writeListCSV(f *Formatter, result *ListResult) error {
 // WriteOutdatedResult writes outdated results in the specified format.
 //
 // It performs the following operations:
-//   - Step 1: Creates a formatter for the requested format
+//   - Step 1: Stamps the result with SchemaVersion and creates a formatter for the requested format
 //   - Step 2: Writes the outdated result using format-specific logic
 //
 // Parameters:
@@ -119,6 +236,7 @@ func writeListCSV(f *Formatter, result *ListResult) error {
 // Returns:
 //   - error: When format is unsupported, returns an error; when write fails, returns the underlying error; otherwise returns nil
 func WriteOutdatedResult(w io.Writer, format Format, result *OutdatedResult) error {
+	result.SchemaVersion = SchemaVersion
 	formatter := NewFormatter(format, w)
 
 	switch format {
@@ -135,6 +253,9 @@ func WriteOutdatedResult(w io.Writer, format Format, result *OutdatedResult) err
 
 // writeOutdatedCSV writes outdated results in CSV format using the formatter.
 //
+// When the result is split into sections, a leading SECTION column holds the
+// section name and rows are written section by section.
+//
 // Parameters:
 //   - f: The formatter instance to use for CSV writing
 //   - result: Outdated result data containing package version information
@@ -142,10 +263,9 @@ func WriteOutdatedResult(w io.Writer, format Format, result *OutdatedResult) err
 // Returns:
 //   - error: When CSV write fails; returns nil on success
 func writeOutdatedCSV(f *Formatter, result *OutdatedResult) error {
-	headers := []string{"RULE", "PM", "TYPE", "CONSTRAINT", "VERSION", "INSTALLED", "MAJOR", "MINOR", "PATCH", "STATUS", "GROUP", "NAME", "ERROR"}
-	rows := make([][]string, 0, len(result.Packages))
-	for _, pkg := range result.Packages {
-		rows = append(rows, []string{
+	headers := []string{"RULE", "PM", "TYPE", "CONSTRAINT", "VERSION", "INSTALLED", "MAJOR", "MINOR", "PATCH", "STATUS", "GROUP", "NAME", "ERROR", "SOURCE", "LINE"}
+	row := func(pkg OutdatedPackage) []string {
+		return []string{
 			pkg.Rule,
 			pkg.PM,
 			pkg.Type,
@@ -159,7 +279,24 @@ func writeOutdatedCSV(f *Formatter, result *OutdatedResult) error {
 			pkg.Group,
 			pkg.Name,
 			pkg.Error,
-		})
+			pkg.Source,
+			formatLine(pkg.Line),
+		}
+	}
+
+	if len(result.Sections) > 0 {
+		rows := make([][]string, 0, len(result.Packages))
+		for _, section := range result.Sections {
+			for _, pkg := range section.Packages {
+				rows = append(rows, append([]string{section.Name}, row(pkg)...))
+			}
+		}
+		return f.WriteCSV(append([]string{"SECTION"}, headers...), rows)
+	}
+
+	rows := make([][]string, 0, len(result.Packages))
+	for _, pkg := range result.Packages {
+		rows = append(rows, row(pkg))
 	}
 	return f.WriteCSV(headers, rows)
 }
@@ -167,17 +304,21 @@ func writeOutdatedCSV(f *Formatter, result *OutdatedResult) error {
 // WriteUpdateResult writes update results in the specified format.
 //
 // It performs the following operations:
-//   - Step 1: Creates a formatter for the requested format
+//   - Step 1: Stamps the result with SchemaVersion and creates a formatter for the requested format
 //   - Step 2: Writes the update result using format-specific logic
 //
 // Parameters:
 //   - w: Destination writer for the output
-//   - format: Output format (FormatJSON, FormatXML, or FormatCSV)
+//   - format: Output format (FormatJSON, FormatXML, FormatCSV, or FormatJUnit)
 //   - result: Update result data to write
 //
 // Returns:
 //   - error: When format is unsupported, returns an error; when write fails, returns the underlying error; otherwise returns nil
 func WriteUpdateResult(w io.Writer, format Format, result *UpdateResult) error {
+	result.SchemaVersion = SchemaVersion
+	if result.SystemTestFailures == nil {
+		result.SystemTestFailures = []UpdateSystemTest{}
+	}
 	formatter := NewFormatter(format, w)
 
 	switch format {
@@ -187,6 +328,10 @@ func WriteUpdateResult(w io.Writer, format Format, result *UpdateResult) error {
 		return formatter.WriteXML(result)
 	case FormatCSV:
 		return writeUpdateCSV(formatter, result)
+	case FormatJUnit:
+		return formatter.WriteXML(BuildJUnitReport(result))
+	case FormatMarkdown:
+		return WriteUpdateMarkdown(w, result)
 	default:
 		return fmt.Errorf("unsupported format: %s", format)
 	}
@@ -201,7 +346,7 @@ func WriteUpdateResult(w io.Writer, format Format, result *UpdateResult) error {
 // Returns:
 //   - error: When CSV write fails; returns nil on success
 func writeUpdateCSV(f *Formatter, result *UpdateResult) error {
-	headers := []string{"RULE", "PM", "TYPE", "CONSTRAINT", "VERSION", "INSTALLED", "TARGET", "STATUS", "GROUP", "NAME", "ERROR"}
+	headers := []string{"RULE", "PM", "TYPE", "CONSTRAINT", "VERSION", "INSTALLED", "TARGET", "STATUS", "GROUP", "NAME", "ERROR", "SOURCE", "LINE"}
 	rows := make([][]string, 0, len(result.Packages))
 	for _, pkg := range result.Packages {
 		rows = append(rows, []string{
@@ -216,7 +361,63 @@ func writeUpdateCSV(f *Formatter, result *UpdateResult) error {
 			pkg.Group,
 			pkg.Name,
 			pkg.Error,
+			pkg.Source,
+			formatLine(pkg.Line),
 		})
 	}
 	return f.WriteCSV(headers, rows)
 }
+
+// formatLine renders a declaration line for CSV output, leaving unknown (zero) lines empty.
+func formatLine(line int) string {
+	if line <= 0 {
+		return ""
+	}
+	return strconv.Itoa(line)
+}
+
+// WriteValidateResult writes validate results in the specified format.
+//
+// It performs the following operations:
+//   - Step 1: Stamps the result with SchemaVersion and creates a formatter for the requested format
+//   - Step 2: Writes the validate result using format-specific logic
+//
+// Parameters:
+//   - w: Destination writer for the output
+//   - format: Output format (FormatJSON, FormatXML, or FormatCSV)
+//   - result: Validate result data to write
+//
+// Returns:
+//   - error: When format is unsupported, returns an error; when write fails, returns the underlying error; otherwise returns nil
+func WriteValidateResult(w io.Writer, format Format, result *ValidateResult) error {
+	result.SchemaVersion = SchemaVersion
+	formatter := NewFormatter(format, w)
+
+	switch format {
+	case FormatJSON:
+		return formatter.WriteJSON(result)
+	case FormatXML:
+		return formatter.WriteXML(result)
+	case FormatCSV:
+		return writeValidateCSV(formatter, result)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// writeValidateCSV writes the command checks of validate results in CSV format.
+//
+// Parameters:
+//   - f: The formatter instance to use for CSV writing
+//   - result: Validate result data containing command checks
+//
+// Returns:
+//   - error: When CSV write fails; returns nil on success
+func writeValidateCSV(f *Formatter, result *ValidateResult) error {
+	headers := []string{"RULE", "KIND", "COMMAND", "FOUND", "PATH", "ERROR"}
+	rows := make([][]string, 0, len(result.Commands))
+	for _, c := range result.Commands {
+		rows = append(rows, []string{c.Rule, c.Kind, c.Command, strconv.FormatBool(c.Found), c.Path, c.Error})
+	}
+	return f.WriteCSV(headers, rows)
+}
@@ -2,6 +2,7 @@ package output
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"encoding/xml"
 	"strings"
@@ -95,6 +96,95 @@ func TestWriteScanResult_CSV(t *testing.T) {
 	assert.Contains(t, lines[2], "composer")
 }
 
+// TestWriteDeepScanResult tests the behavior of WriteDeepScanResult in every structured format.
+//
+// It verifies:
+//   - JSON round-trips the summary, the direct flag and the unsupported messages
+//   - XML uses the deepScanResult root element
+//   - CSV writes a DIRECT column
+//   - Table format is rejected
+func TestWriteDeepScanResult(t *testing.T) {
+	result := &DeepScanResult{
+		Summary: DeepScanSummary{Directory: "/test", TotalPackages: 2, DirectPackages: 1, TransitivePackages: 1},
+		Packages: []DeepScanPackage{
+			{Rule: "npm", PM: "js", Name: "react", Version: "^18.2.0", InstalledVersion: "18.2.0", Status: "LockFound", Direct: true, Source: "package.json"},
+			{Rule: "npm", PM: "js", Name: "loose-envify", Version: "1.4.0", InstalledVersion: "1.4.0", Status: "LockFound", Source: "package.json"},
+		},
+		Unsupported: []string{"pnpm (js): transitive dependencies not resolved"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDeepScanResult(&buf, FormatJSON, result))
+	var parsed DeepScanResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	assert.Equal(t, 1, parsed.Summary.TransitivePackages)
+	require.Len(t, parsed.Packages, 2)
+	assert.True(t, parsed.Packages[0].Direct)
+	assert.False(t, parsed.Packages[1].Direct)
+	assert.Equal(t, result.Unsupported, parsed.Unsupported)
+
+	buf.Reset()
+	require.NoError(t, WriteDeepScanResult(&buf, FormatXML, result))
+	assert.Contains(t, buf.String(), "<deepScanResult>")
+	assert.Contains(t, buf.String(), "<direct>true</direct>")
+
+	buf.Reset()
+	require.NoError(t, WriteDeepScanResult(&buf, FormatCSV, result))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "DIRECT")
+	assert.Contains(t, lines[1], "react")
+	assert.Contains(t, lines[1], "true")
+	assert.Contains(t, lines[2], "false")
+
+	assert.Error(t, WriteDeepScanResult(&buf, FormatTable, result))
+}
+
+// TestWritePlanDiffResult tests the behavior of WritePlanDiffResult in every structured format.
+//
+// It verifies:
+//   - JSON round-trips the summary and omits the target missing on each side
+//   - XML uses the planDiffResult root element
+//   - CSV writes one row per change
+//   - An empty diff is written as an empty changes list
+func TestWritePlanDiffResult(t *testing.T) {
+	result := &PlanDiffResult{
+		Summary: PlanDiffSummary{OldPlan: "old.json", NewPlan: "new.json", Added: 1, Changed: 1, Unchanged: 3},
+		Changes: []PlanDiffEntry{
+			{Rule: "npm", PM: "js", Type: "prod", Name: "react", Change: "changed", Original: "17.0.0", OldTarget: "18.2.0", NewTarget: "19.0.0", Source: "package.json"},
+			{Rule: "npm", PM: "js", Type: "dev", Name: "jest", Change: "added", Original: "29.0.0", NewTarget: "29.7.0", Source: "package.json"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePlanDiffResult(&buf, FormatJSON, result))
+	var parsed PlanDiffResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	assert.Equal(t, SchemaVersion, parsed.SchemaVersion)
+	assert.Equal(t, result.Summary, parsed.Summary)
+	require.Len(t, parsed.Changes, 2)
+	assert.Equal(t, "19.0.0", parsed.Changes[0].NewTarget)
+	assert.NotContains(t, buf.String(), `"old_target":""`)
+
+	buf.Reset()
+	require.NoError(t, WritePlanDiffResult(&buf, FormatXML, result))
+	assert.Contains(t, buf.String(), "<planDiffResult>")
+	assert.Contains(t, buf.String(), "<oldTarget>18.2.0</oldTarget>")
+
+	buf.Reset()
+	require.NoError(t, WritePlanDiffResult(&buf, FormatCSV, result))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "OLD TARGET")
+	assert.Contains(t, lines[2], "added")
+
+	buf.Reset()
+	require.NoError(t, WritePlanDiffResult(&buf, FormatJSON, &PlanDiffResult{}))
+	assert.Contains(t, buf.String(), `"changes":[]`)
+
+	assert.Error(t, WritePlanDiffResult(&buf, FormatTable, result))
+}
+
 // TestWriteListResult_JSON tests the behavior of WriteListResult with JSON format.
 //
 // It verifies:
@@ -128,11 +218,13 @@ func TestWriteListResult_JSON(t *testing.T) {
 //
 // It verifies:
 //   - Writes CSV with package columns
+//   - Source and line columns are filled in, and left empty for unknown lines
 func TestWriteListResult_CSV(t *testing.T) {
 	var buf bytes.Buffer
 	result := &ListResult{
 		Packages: []ListPackage{
-			{Rule: "npm", PM: "js", Type: "prod", Name: "express", Version: "4.18.0", Status: "LockFound"},
+			{Rule: "npm", PM: "js", Type: "prod", Name: "express", Version: "4.18.0", Status: "LockFound", Source: "web/package.json", Line: 12},
+			{Rule: "npm", PM: "js", Type: "prod", Name: "vue", Version: "3.4.0", Status: "LockFound", Source: "web/package.json"},
 		},
 	}
 
@@ -140,9 +232,10 @@ func TestWriteListResult_CSV(t *testing.T) {
 	require.NoError(t, err)
 
 	output := buf.String()
-	assert.Contains(t, output, "RULE,PM,TYPE,CONSTRAINT,VERSION,INSTALLED,STATUS,GROUP,NAME")
+	assert.Contains(t, output, "RULE,PM,TYPE,CONSTRAINT,VERSION,INSTALLED,STATUS,GROUP,NAME,SOURCE,LINE")
 	assert.Contains(t, output, "npm")
-	assert.Contains(t, output, "express")
+	assert.Contains(t, output, "express,web/package.json,12\n")
+	assert.Contains(t, output, "vue,web/package.json,\n")
 }
 
 // TestWriteOutdatedResult_JSON tests the behavior of WriteOutdatedResult with JSON format.
@@ -253,11 +346,60 @@ func TestWriteUpdateResult_CSV(t *testing.T) {
 	require.NoError(t, err)
 
 	output := buf.String()
-	assert.Contains(t, output, "RULE,PM,TYPE,CONSTRAINT,VERSION,INSTALLED,TARGET,STATUS,GROUP,NAME,ERROR")
+	assert.Contains(t, output, "RULE,PM,TYPE,CONSTRAINT,VERSION,INSTALLED,TARGET,STATUS,GROUP,NAME,ERROR,SOURCE,LINE")
 	assert.Contains(t, output, "express")
 	assert.Contains(t, output, "4.18.0")
 }
 
+// TestWriteResult_CSVQuoting tests RFC 4180 quoting of package names in CSV output.
+//
+// It verifies:
+//   - Names containing commas or quotes are quoted and round-trip through a CSV reader
+//   - Scoped npm and composer vendor/name values are written unquoted
+//   - List, outdated, and update results all produce parseable rows
+func TestWriteResult_CSVQuoting(t *testing.T) {
+	names := []string{"@babel/core", "laravel/framework", "odd,name", `say "hi"`}
+
+	var list ListResult
+	var outdated OutdatedResult
+	var update UpdateResult
+	for _, name := range names {
+		list.Packages = append(list.Packages, ListPackage{Rule: "npm", PM: "js", Type: "prod", Name: name, Version: "1.0.0", InstalledVersion: "#N/A"})
+		outdated.Packages = append(outdated.Packages, OutdatedPackage{Rule: "npm", PM: "js", Type: "prod", Name: name, Version: "1.0.0", Major: "2.0.0"})
+		update.Packages = append(update.Packages, UpdatePackage{Rule: "npm", PM: "js", Type: "prod", Name: name, Version: "1.0.0", Target: "2.0.0"})
+	}
+
+	writers := map[string]func(*bytes.Buffer) error{
+		"list":     func(buf *bytes.Buffer) error { return WriteListResult(buf, FormatCSV, &list) },
+		"outdated": func(buf *bytes.Buffer) error { return WriteOutdatedResult(buf, FormatCSV, &outdated) },
+		"update":   func(buf *bytes.Buffer) error { return WriteUpdateResult(buf, FormatCSV, &update) },
+	}
+	for command, write := range writers {
+		t.Run(command, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, write(&buf))
+
+			assert.Contains(t, buf.String(), `,"odd,name"`)
+			assert.Contains(t, buf.String(), `,"say ""hi"""`)
+			assert.Contains(t, buf.String(), ",@babel/core")
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, records, len(names)+1)
+			nameCol := -1
+			for i, header := range records[0] {
+				if header == "NAME" {
+					nameCol = i
+				}
+			}
+			require.GreaterOrEqual(t, nameCol, 0)
+			for i, name := range names {
+				assert.Equal(t, name, records[i+1][nameCol])
+			}
+		})
+	}
+}
+
 // TestWriteResult_UnsupportedFormat tests the behavior of Write functions with unsupported format.
 //
 // It verifies:
@@ -337,7 +479,7 @@ func TestWriteOutdatedResult_CSV(t *testing.T) {
 
 	output := buf.String()
 	// Verify header
-	assert.Contains(t, output, "RULE,PM,TYPE,CONSTRAINT,VERSION,INSTALLED,MAJOR,MINOR,PATCH,STATUS,GROUP,NAME,ERROR")
+	assert.Contains(t, output, "RULE,PM,TYPE,CONSTRAINT,VERSION,INSTALLED,MAJOR,MINOR,PATCH,STATUS,GROUP,NAME,ERROR,SOURCE,LINE")
 	// Verify data
 	assert.Contains(t, output, "npm")
 	assert.Contains(t, output, "express")
@@ -391,3 +533,38 @@ func TestWriteUpdateResult_XML(t *testing.T) {
 	assert.Contains(t, output, "<?xml version=")
 	assert.Contains(t, output, "<updateResult>")
 }
+
+// TestWriteValidateResult tests the behavior of WriteValidateResult.
+//
+// It verifies:
+//   - JSON lists every command check with found, path, and error
+//   - CSV writes one row per command check
+func TestWriteValidateResult(t *testing.T) {
+	result := &ValidateResult{
+		Summary: ValidateSummary{TotalPackages: 2, Rules: 1, Problems: 1, MissingCommands: 1},
+		Commands: []ValidateCommand{
+			{Rule: "npm", Kind: "outdated", Command: "npm", Found: true, Path: "/usr/bin/npm"},
+			{Rule: "npm", Kind: "lock", Command: "jq", Error: "command not found: jq"},
+		},
+		Errors: []string{"command not found: jq"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteValidateResult(&buf, FormatJSON, result))
+	var decoded ValidateResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, result.Commands, decoded.Commands)
+	assert.Equal(t, 1, decoded.Summary.MissingCommands)
+	assert.False(t, decoded.Valid)
+	assert.Contains(t, buf.String(), `"found":false`)
+
+	buf.Reset()
+	require.NoError(t, WriteValidateResult(&buf, FormatCSV, result))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"RULE", "KIND", "COMMAND", "FOUND", "PATH", "ERROR"},
+		{"npm", "outdated", "npm", "true", "/usr/bin/npm", ""},
+		{"npm", "lock", "jq", "false", "", "command not found: jq"},
+	}, rows)
+}
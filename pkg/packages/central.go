@@ -0,0 +1,100 @@
+package packages
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// centralPackagesFile is the MSBuild central package management file.
+const centralPackagesFile = "Directory.Packages.props"
+
+// projectFileExts are the MSBuild project file extensions that can use central package management.
+var projectFileExts = map[string]bool{".csproj": true, ".vbproj": true, ".fsproj": true}
+
+// attributeCentralPackages records which projects use each package of a Directory.Packages.props file.
+//
+// It performs the following operations:
+//   - Step 1: Walks the projects below the props file, skipping bin, obj, and hidden
+//     directories and subtrees governed by their own Directory.Packages.props
+//   - Step 2: Collects each project's versionless PackageReference entries
+//   - Step 3: Sets UsedBy of every package to the projects referencing it; global
+//     package references are used by every project
+//
+// Projects that pin a package with VersionOverride declare their own version and
+// are not counted as users of the central entry. Unreadable projects are skipped.
+//
+// Parameters:
+//   - propsPath: Path of the Directory.Packages.props file
+//   - content: Content of the props file
+//   - packages: Packages parsed from the props file; UsedBy is set in place
+func attributeCentralPackages(propsPath string, content []byte, packages []formats.Package) {
+	root := filepath.Dir(propsPath)
+	usedBy := make(map[string][]string)
+	var projects []string
+
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			name := d.Name()
+			if name == "bin" || name == "obj" || strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			// A nested props file governs its own subtree
+			if _, statErr := os.Stat(filepath.Join(path, centralPackagesFile)); statErr == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !projectFileExts[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		projectContent, readErr := os.ReadFile(path)
+		if readErr != nil {
+			verbose.Printf("Central package management: skipping %s: %v\n", path, readErr)
+			return nil
+		}
+		names, parseErr := formats.CentralPackageReferences(projectContent)
+		if parseErr != nil {
+			verbose.Printf("Central package management: skipping %s: %v\n", path, parseErr)
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			rel = path
+		}
+		rel = filepath.ToSlash(rel)
+		projects = append(projects, rel)
+		for _, name := range names {
+			key := strings.ToLower(name)
+			usedBy[key] = append(usedBy[key], rel)
+		}
+		return nil
+	})
+
+	globals, _ := formats.GlobalPackageReferences(content)
+	for _, name := range globals {
+		usedBy[strings.ToLower(name)] = projects
+	}
+
+	for i := range packages {
+		users := usedBy[strings.ToLower(packages[i].Name)]
+		if len(users) == 0 {
+			continue
+		}
+		packages[i].UsedBy = append([]string(nil), users...)
+		sort.Strings(packages[i].UsedBy)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/utils"
@@ -18,10 +19,13 @@ import (
 //   - Validates the configuration
 //   - Iterates through each enabled package manager rule
 //   - Walks the directory tree to find matching files
+//   - Adds the modules listed in a rule's workspace file, such as go.work (see addWorkspaceMembers)
+//   - Infers rules from the base directory's lock files when no rule matched (see InferRules)
 //   - Resolves conflicts when multiple rules match the same file
 //
 // Parameters:
-//   - cfg: Configuration containing package manager rules with include/exclude patterns
+//   - cfg: Configuration containing package manager rules with include/exclude patterns;
+//     inferred rules are added to cfg.Rules
 //   - baseDir: Base directory to search from; uses cfg.WorkingDir if empty, or "." if both empty
 //
 // Returns:
@@ -63,12 +67,29 @@ func DetectFiles(cfg *config.Config, baseDir string) (map[string][]string, error
 		if err != nil {
 			return nil, err
 		}
+		files, err = addWorkspaceMembers(baseDir, rule, files)
+		if err != nil {
+			return nil, err
+		}
 
 		if len(files) > 0 {
 			detected[ruleKey] = files
 		}
 	}
 
+	if len(detected) == 0 {
+		// No rule matched: fall back to the rules the lock files point to
+		for _, ruleKey := range InferRules(cfg, baseDir) {
+			files, err := detectForRule(baseDir, cfg.Rules[ruleKey])
+			if err != nil {
+				return nil, err
+			}
+			if len(files) > 0 {
+				detected[ruleKey] = files
+			}
+		}
+	}
+
 	// Log summary of detection results
 	if len(detected) > 0 {
 		matchedCount := len(detected)
@@ -79,21 +100,19 @@ func DetectFiles(cfg *config.Config, baseDir string) (map[string][]string, error
 		verbose.Printf("File detection: %d/%d rules matched, %d files found", matchedCount, len(cfg.Rules), totalFiles)
 	}
 
-	return resolveRuleConflicts(cfg, detected), nil
+	return resolveRuleConflicts(cfg, detected, baseDir), nil
 }
 
 // detectForRule finds all files matching a single rule's include/exclude patterns.
 //
 // It performs the following operations:
 //   - Validates the base directory exists and is accessible
-//   - Walks the directory tree using filepath.Walk
-//   - Skips directories, broken symlinks, and inaccessible paths
-//   - Applies include/exclude pattern matching to regular files
-//   - Returns absolute paths of all matching files
+//   - Walks the base directory, or each of the rule's paths below it when set
+//   - Returns absolute paths of all matching files, each reported once
 //
 // Parameters:
 //   - baseDir: Base directory to search from; must be an existing directory
-//   - rule: Package manager configuration with Include and Exclude patterns
+//   - rule: Package manager configuration with Include, Exclude and Paths
 //
 // Returns:
 //   - []string: List of absolute file paths matching the rule's patterns
@@ -108,14 +127,106 @@ func detectForRule(baseDir string, rule config.PackageManagerCfg) ([]string, err
 		return nil, fmt.Errorf("base path is not a directory: %s", baseDir)
 	}
 
+	if len(rule.Paths) == 0 {
+		return walkRuleDir(baseDir, rule)
+	}
+
+	var matches []string
+	seen := make(map[string]bool)
+	for _, dir := range rule.Paths {
+		root := filepath.Join(baseDir, filepath.FromSlash(dir))
+		if info, statErr := os.Stat(root); statErr != nil || !info.IsDir() {
+			warnings.Warn(warnings.CodePathSkipped, "", "⚠️ skipping rule path %s: not a directory", root)
+			continue
+		}
+
+		files, walkErr := walkRuleDir(root, rule)
+		if walkErr != nil {
+			return nil, walkErr
+		}
+		for _, file := range files {
+			if !seen[file] {
+				seen[file] = true
+				matches = append(matches, file)
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// addWorkspaceMembers adds the manifests of the modules listed in the rule's workspace file.
+//
+// A go.work file may list modules outside the include patterns or the rule's
+// paths. Manifests that were already detected are not added again, so a
+// module found by both the walk and the workspace file is parsed once.
+// Modules outside baseDir, such as "use ../shared", are skipped with a
+// warning: updates never reach beyond the directory being scanned.
+//
+// Parameters:
+//   - baseDir: Base directory holding the workspace file
+//   - rule: Package manager configuration with WorkspaceFile
+//   - files: Files detected by walking the rule's directories
+//
+// Returns:
+//   - []string: files followed by the workspace manifests not already among them
+//   - error: When the workspace file or baseDir cannot be resolved
+func addWorkspaceMembers(baseDir string, rule config.PackageManagerCfg, files []string) ([]string, error) {
+	members, err := config.WorkspaceMembers(rule, baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+	if len(members) == 0 {
+		return files, nil
+	}
+
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(files))
+	for _, file := range files {
+		if abs, absErr := filepath.Abs(file); absErr == nil {
+			seen[abs] = true
+		}
+	}
+	for _, member := range members {
+		if rel, relErr := filepath.Rel(absBase, member); relErr != nil || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			warnings.Warn(warnings.CodePathSkipped, "", "⚠️ skipping workspace module %s: outside %s", filepath.Dir(member), baseDir)
+			continue
+		}
+		if !seen[member] {
+			seen[member] = true
+			verbose.Debugf("Workspace module %s is not matched by the include patterns; adding it", member)
+			files = append(files, member)
+		}
+	}
+	return files, nil
+}
+
+// walkRuleDir walks one directory tree and collects the files matching a rule.
+//
+// It performs the following operations:
+//   - Walks the directory tree using filepath.Walk
+//   - Skips directories, broken symlinks, and inaccessible paths
+//   - Applies include/exclude pattern matching to paths relative to root
+//
+// Parameters:
+//   - root: Directory to walk; include/exclude patterns are matched relative to it
+//   - rule: Package manager configuration with Include and Exclude patterns
+//
+// Returns:
+//   - []string: List of file paths matching the rule's patterns
+//   - error: When walk encounters unrecoverable errors, returns error; otherwise returns nil
+func walkRuleDir(root string, rule config.PackageManagerCfg) ([]string, error) {
 	var matches []string
 
-	err = filepath.Walk(baseDir, func(path string, info os.FileInfo, walkErr error) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, walkErr error) error {
 		// Handle walk errors (e.g., permission denied, broken symlinks)
 		if walkErr != nil {
 			// For broken symlinks or inaccessible files, skip with warning
 			if os.IsNotExist(walkErr) || os.IsPermission(walkErr) {
-				warnings.Warnf("⚠️ skipping inaccessible path %s: %v\n", path, walkErr)
+				warnings.Warn(warnings.CodePathSkipped, "", "⚠️ skipping inaccessible path %s: %v", path, walkErr)
 				return nil
 			}
 			// For other errors, continue walking
@@ -134,13 +245,13 @@ func detectForRule(baseDir string, rule config.PackageManagerCfg) ([]string, err
 			realPath, evalErr := filepath.EvalSymlinks(path)
 			if evalErr != nil {
 				// Broken symlink - skip with warning
-				warnings.Warnf("⚠️ skipping broken symlink %s: %v\n", path, evalErr)
+				warnings.Warn(warnings.CodePathSkipped, "", "⚠️ skipping broken symlink %s: %v", path, evalErr)
 				return nil
 			}
 			// Check if symlink target is a directory (shouldn't match file patterns)
 			realInfo, statErr := os.Stat(realPath)
 			if statErr != nil {
-				warnings.Warnf("⚠️ skipping symlink with inaccessible target %s: %v\n", path, statErr)
+				warnings.Warn(warnings.CodePathSkipped, "", "⚠️ skipping symlink with inaccessible target %s: %v", path, statErr)
 				return nil
 			}
 			if realInfo.IsDir() {
@@ -149,7 +260,7 @@ func detectForRule(baseDir string, rule config.PackageManagerCfg) ([]string, err
 		}
 
 		relPath := path
-		if rel, relErr := filepath.Rel(baseDir, path); relErr == nil {
+		if rel, relErr := filepath.Rel(root, path); relErr == nil {
 			relPath = rel
 		}
 
@@ -174,10 +285,11 @@ func detectForRule(baseDir string, rule config.PackageManagerCfg) ([]string, err
 // Parameters:
 //   - cfg: Configuration containing all package manager rules
 //   - detected: Map of rule names to matched file lists, potentially with overlaps
+//   - baseDir: Directory the files were detected in; bounds the workspace root lookup
 //
 // Returns:
 //   - map[string][]string: Updated map where each file appears under exactly one rule
-func resolveRuleConflicts(cfg *config.Config, detected map[string][]string) map[string][]string {
+func resolveRuleConflicts(cfg *config.Config, detected map[string][]string, baseDir string) map[string][]string {
 	fileToRules := make(map[string][]string)
 	for rule, files := range detected {
 		for _, file := range files {
@@ -192,7 +304,7 @@ func resolveRuleConflicts(cfg *config.Config, detected map[string][]string) map[
 		}
 		conflictCount++
 
-		selected := selectRuleForFile(cfg, file, rules)
+		selected := selectRuleForFile(cfg, file, rules, baseDir)
 		verbose.Printf("Conflict: %s matched %v → selected %s\n", filepath.Base(file), rules, selected)
 
 		for _, rule := range rules {
@@ -217,18 +329,20 @@ func resolveRuleConflicts(cfg *config.Config, detected map[string][]string) map[
 // selectRuleForFile chooses which rule should handle a file when multiple rules match.
 //
 // It performs the following operations:
-//   - Prioritizes rules by known package manager order (npm, pnpm, yarn, then alphabetical)
-//   - Checks each prioritized rule for the presence of its lock files
+//   - Prioritizes rules by known package manager order (npm, pnpm, yarn, bun, then alphabetical)
+//   - Checks each prioritized rule for the presence of its lock files, next to the
+//     file or in the workspace root for workspace rules
 //   - Returns the first rule with a lock file present, or the highest priority rule if none found
 //
 // Parameters:
 //   - cfg: Configuration containing rule definitions with lock file patterns
 //   - file: Absolute path to the manifest file that multiple rules matched
 //   - rules: List of rule names that all matched the file
+//   - baseDir: Directory bounding the workspace root lookup
 //
 // Returns:
 //   - string: Name of the rule that should handle this file
-func selectRuleForFile(cfg *config.Config, file string, rules []string) string {
+func selectRuleForFile(cfg *config.Config, file string, rules []string, baseDir string) string {
 	dir := filepath.Dir(file)
 
 	prioritized := prioritizeRules(rules)
@@ -238,7 +352,7 @@ func selectRuleForFile(cfg *config.Config, file string, rules []string) string {
 		if !ok {
 			continue
 		}
-		if hasLockFile(dir, rule.LockFiles) {
+		if hasLockFile(config.LockScopeDir(rule, dir, baseDir), rule.LockFiles) {
 			return ruleName
 		}
 	}
@@ -258,26 +372,26 @@ func selectRuleForFile(cfg *config.Config, file string, rules []string) string {
 // Returns:
 //   - string: Name of the rule that should handle this file based on lock files and priority
 func ResolveRuleForFile(cfg *config.Config, file string, rules []string) string {
-	return selectRuleForFile(cfg, file, rules)
+	return selectRuleForFile(cfg, file, rules, cfg.WorkingDir)
 }
 
 // prioritizeRules orders rule names by known package manager priority and alphabetically.
 //
 // It performs the following operations:
 //   - Creates a copy of the rules list to avoid modifying the input
-//   - Applies stable sorting with npm (priority 0), pnpm (priority 1), yarn (priority 2)
+//   - Applies stable sorting with npm (priority 0), pnpm (priority 1), yarn (priority 2), bun (priority 3)
 //   - Unknown package managers are sorted alphabetically after known ones
 //
 // Parameters:
 //   - rules: List of rule names to prioritize
 //
 // Returns:
-//   - []string: New slice with rules ordered by priority (npm, pnpm, yarn, then alphabetical)
+//   - []string: New slice with rules ordered by priority (npm, pnpm, yarn, bun, then alphabetical)
 func prioritizeRules(rules []string) []string {
 	ordered := make([]string, len(rules))
 	copy(ordered, rules)
 
-	priority := map[string]int{"npm": 0, "pnpm": 1, "yarn": 2}
+	priority := map[string]int{"npm": 0, "pnpm": 1, "yarn": 2, "bun": 3}
 	sort.SliceStable(ordered, func(i, j int) bool {
 		left := ordered[i]
 		right := ordered[j]
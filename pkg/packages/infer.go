@@ -0,0 +1,96 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// InferRules enables built-in rules for the lock files found in a directory.
+//
+// It is the fallback for configs whose rules match no manifest, such as a
+// .goupdate.yml that does not extend default: a package-lock.json means npm,
+// go.sum means mod, and so on. Only rules missing from cfg are inferred, so an
+// explicitly disabled rule stays disabled. When lock files point to several
+// rules of one package manager (e.g. package-lock.json and pnpm-lock.yaml),
+// none of them is enabled and a warning asks for an explicit rule instead.
+//
+// It performs the following operations:
+//   - Step 1: Match the files of baseDir against each built-in rule's lock file names
+//   - Step 2: Warn about and drop managers with more than one matching rule
+//   - Step 3: Add the remaining rules' defaults to cfg.Rules
+//
+// Parameters:
+//   - cfg: Configuration to add the inferred rules to; modified in place
+//   - baseDir: Directory holding the lock files; subdirectories are not searched
+//
+// Returns:
+//   - []string: Keys of the rules added to cfg, sorted; nil when none were inferred
+func InferRules(cfg *config.Config, baseDir string) []string {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Rules == nil {
+		cfg.Rules = make(map[string]config.PackageManagerCfg)
+	}
+
+	defaults := config.DefaultRules()
+	byManager := make(map[string][]string)
+	lockOf := make(map[string]string)
+	for key, rule := range defaults {
+		if _, configured := cfg.Rules[key]; configured {
+			continue
+		}
+		if lockFile := findLockFile(baseDir, rule.LockFiles); lockFile != "" {
+			byManager[rule.Manager] = append(byManager[rule.Manager], key)
+			lockOf[key] = lockFile
+		}
+	}
+
+	var inferred []string
+	for manager, keys := range byManager {
+		sort.Strings(keys)
+		if len(keys) > 1 {
+			found := make([]string, 0, len(keys))
+			for _, key := range keys {
+				found = append(found, key+" ("+lockOf[key]+")")
+			}
+			warnings.Warn(warnings.CodeAmbiguousLockFiles, "", "⚠️ lock files of several %s rules found in %s: %s; set --rule or configure the rule to use", manager, baseDir, strings.Join(found, ", "))
+			continue
+		}
+		inferred = append(inferred, keys[0])
+	}
+
+	sort.Strings(inferred)
+	for _, key := range inferred {
+		cfg.Rules[key] = defaults[key]
+		verbose.Printf("Inferred rule %s from %s\n", key, lockOf[key])
+	}
+
+	return inferred
+}
+
+// findLockFile returns the first lock file of a rule present in a directory.
+//
+// Parameters:
+//   - dir: Directory to look in
+//   - lockFiles: The rule's lock file configurations
+//
+// Returns:
+//   - string: Base name of the lock file found, or empty if none exists
+func findLockFile(dir string, lockFiles []config.LockFileCfg) string {
+	for _, lockFile := range lockFiles {
+		for _, pattern := range lockFile.Files {
+			name := filepath.Base(pattern)
+			if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+				return name
+			}
+		}
+	}
+	return ""
+}
@@ -41,6 +41,8 @@ func TestDynamicParserParseFile(t *testing.T) {
 	assert.Equal(t, jsonFile, result.Source)
 	assert.Len(t, result.Packages, 1)
 	assert.Equal(t, "test", result.Packages[0].Name)
+	assert.Equal(t, jsonFile, result.Packages[0].Source)
+	assert.Equal(t, 1, result.Packages[0].Line)
 
 	// Test parser error
 	badJSONFile := filepath.Join(tmpDir, "invalid.json")
@@ -158,6 +160,27 @@ func TestResolveRuleForFilePrefersLocksAndPriority(t *testing.T) {
 	assert.Equal(t, "npm", ResolveRuleForFile(cfg, manifest, []string{"npm", "pnpm"}))
 }
 
+func TestResolveRuleForFileUsesWorkspaceRootLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	memberDir := filepath.Join(tmpDir, "packages", "app")
+	require.NoError(t, os.MkdirAll(memberDir, 0o755))
+	manifest := filepath.Join(memberDir, "package.json")
+	require.NoError(t, os.WriteFile(manifest, []byte(`{}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "pnpm-lock.yaml"), []byte("lockfileVersion: '9.0'"), 0o644))
+
+	pnpm := config.PackageManagerCfg{LockFiles: []config.LockFileCfg{{Files: []string{"**/pnpm-lock.yaml"}}}}
+	cfg := &config.Config{WorkingDir: tmpDir, Rules: map[string]config.PackageManagerCfg{
+		"npm":  {LockFiles: []config.LockFileCfg{{Files: []string{"**/package-lock.json"}}}},
+		"pnpm": pnpm,
+	}}
+	assert.Equal(t, "npm", ResolveRuleForFile(cfg, manifest, []string{"npm", "pnpm"}))
+
+	workspace := true
+	pnpm.Workspace = &workspace
+	cfg.Rules["pnpm"] = pnpm
+	assert.Equal(t, "pnpm", ResolveRuleForFile(cfg, manifest, []string{"npm", "pnpm"}))
+}
+
 func TestPrioritizeRulesOrdersKnownManagersFirst(t *testing.T) {
 	ordered := prioritizeRules([]string{"custom", "yarn", "npm"})
 	assert.Equal(t, []string{"npm", "yarn", "custom"}, ordered)
@@ -249,6 +272,68 @@ func TestDetectForRuleWithFileBase(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDetectForRuleWithPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"frontend", "frontend/fixtures", "services/api", "legacy"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "package.json"), []byte("{}"), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte("{}"), 0o644))
+
+	buf := &bytes.Buffer{}
+	restore := warnings.SetWarningWriter(buf)
+	defer restore()
+
+	rule := config.PackageManagerCfg{
+		Include: []string{"**/package.json"},
+		Exclude: []string{"fixtures/**"},
+		Paths:   []string{"frontend", "services/api", "frontend", "missing"},
+	}
+
+	files, err := detectForRule(tmpDir, rule)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(tmpDir, "frontend", "package.json"),
+		filepath.Join(tmpDir, "services", "api", "package.json"),
+	}, files)
+	assert.Contains(t, buf.String(), "skipping rule path")
+}
+
+func TestDetectFilesAddsGoWorkModules(t *testing.T) {
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "repo")
+	for _, dir := range []string{"repo/api", "repo/worker", "shared"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "go.mod"), []byte("module example.com/x\n"), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.22\n\nuse (\n\t./api\n\t./worker\n\t../shared\n)\n"), 0o644))
+
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"mod": {
+			Include:       []string{"**/go.mod"},
+			Exclude:       []string{"worker/**"},
+			WorkspaceFile: &config.WorkspaceFileCfg{File: "go.work", Format: config.WorkspaceFormatGoWork},
+		},
+	}}
+
+	buf := &bytes.Buffer{}
+	restore := warnings.SetWarningWriter(buf)
+	defer restore()
+
+	detected, err := DetectFiles(cfg, root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "api", "go.mod"),
+		filepath.Join(root, "worker", "go.mod"),
+	}, detected["mod"])
+	assert.Contains(t, buf.String(), "skipping workspace module "+filepath.Join(tmpDir, "shared")+": outside "+root)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.work"), []byte("use ./api\n"), 0o644))
+	cfg.Rules["mod"].WorkspaceFile.Format = "unknown"
+	_, err = DetectFiles(cfg, root)
+	assert.ErrorContains(t, err, "failed to read workspace file")
+}
+
 func TestDetectForRuleSkipsUnreadablePaths(t *testing.T) {
 	tmpDir := t.TempDir()
 	readable := filepath.Join(tmpDir, "readable.json")
@@ -455,3 +540,63 @@ func TestDetectForRuleSymlinks(t *testing.T) {
 		assert.NotContains(t, files, dirSymlink)
 	})
 }
+
+// TestInferRules tests enabling built-in rules from the lock files of a directory.
+//
+// It verifies:
+//   - A go.sum and a composer.lock enable the mod and composer rules with their defaults
+//   - Rules already in the config, even disabled ones, are not replaced
+//   - Lock files of two rules of one manager warn and enable neither
+//   - DetectFiles falls back to inferred rules when no configured rule matches
+func TestInferRules(t *testing.T) {
+	t.Run("infers rules from lock files", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), nil, 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "composer.lock"), nil, 0o644))
+
+		cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{}}
+		assert.Equal(t, []string{"composer", "mod"}, InferRules(cfg, dir))
+		assert.Equal(t, "golang", cfg.Rules["mod"].Manager)
+		assert.NotEmpty(t, cfg.Rules["mod"].Include)
+	})
+
+	t.Run("keeps configured rules", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), nil, 0o644))
+
+		disabled := false
+		cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{"mod": {Enabled: &disabled}}}
+		assert.Empty(t, InferRules(cfg, dir))
+		assert.Empty(t, cfg.Rules["mod"].Include)
+	})
+
+	t.Run("ambiguous lock files warn", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "package-lock.json"), nil, 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pnpm-lock.yaml"), nil, 0o644))
+
+		var buf bytes.Buffer
+		restore := warnings.SetWarningWriter(&buf)
+		defer restore()
+
+		cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{}}
+		assert.Empty(t, InferRules(cfg, dir))
+		assert.Empty(t, cfg.Rules)
+		assert.Contains(t, buf.String(), "lock files of several js rules found")
+		assert.Contains(t, buf.String(), "npm (package-lock.json), pnpm (pnpm-lock.yaml)")
+	})
+
+	t.Run("detect falls back to inferred rules", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), nil, 0o644))
+
+		cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+			"custom": {Manager: "custom", Include: []string{"*.custom"}, Format: "raw"},
+		}}
+		detected, err := DetectFiles(cfg, dir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "go.mod")}, detected["mod"])
+		assert.Contains(t, cfg.Rules, "mod")
+	})
+}
@@ -6,6 +6,7 @@ package packages
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/config"
@@ -35,6 +36,8 @@ func NewDynamicParser() *DynamicParser {
 //   - Validates the package manager configuration
 //   - Reads the file contents from disk
 //   - Dispatches to the appropriate format parser (JSON, YAML, TOML, etc.)
+//   - Records the source file and declaration line of every package
+//   - Records the projects using each package of a Directory.Packages.props file
 //   - Returns a structured list of packages with their metadata
 //
 // Parameters:
@@ -76,6 +79,15 @@ func (dp *DynamicParser) ParseFile(filePath string, cfg *config.PackageManagerCf
 		return nil, err
 	}
 
+	for i := range packages {
+		packages[i].Source = filePath
+	}
+	formats.AssignLines(content, packages)
+
+	if cfg.Manager == "dotnet" && filepath.Base(filePath) == centralPackagesFile {
+		attributeCentralPackages(filePath, content, packages)
+	}
+
 	verbose.Printf("Parsed %d packages from %s\n", len(packages), filePath)
 
 	return &formats.PackageList{
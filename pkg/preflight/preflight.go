@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/verbose"
 )
@@ -24,6 +25,7 @@ var CommandResolutionHints = map[string]string{
 	"node":     "Install Node.js: https://nodejs.org/",
 	"yarn":     "Install Yarn: https://yarnpkg.com/getting-started/install",
 	"pnpm":     "Install pnpm: https://pnpm.io/installation",
+	"bun":      "Install Bun: https://bun.sh/docs/installation",
 	"pip":      "Install Python: https://python.org/downloads/",
 	"pip3":     "Install Python: https://python.org/downloads/",
 	"python":   "Install Python: https://python.org/downloads/",
@@ -36,9 +38,14 @@ var CommandResolutionHints = map[string]string{
 	"gem":      "Install Ruby: https://ruby-lang.org/en/downloads/",
 	"bundle":   "Install Bundler: gem install bundler",
 	"bundler":  "Install Bundler: gem install bundler",
+	"brew":     "Install Homebrew: https://brew.sh/",
+	"dart":     "Install Dart: https://dart.dev/get-dart",
+	"flutter":  "Install Flutter: https://docs.flutter.dev/get-started/install",
+	"swift":    "Install Swift: https://www.swift.org/install/",
 	"cargo":    "Install Rust: https://rustup.rs/",
 	"mvn":      "Install Maven: https://maven.apache.org/install.html",
 	"gradle":   "Install Gradle: https://gradle.org/install/",
+	"deno":     "Install Deno: https://docs.deno.com/runtime/getting_started/installation/",
 
 	// Common Unix tools (pre-installed on Linux/macOS)
 	"grep": "Unix tool - typically pre-installed on Linux/macOS",
@@ -47,6 +54,7 @@ var CommandResolutionHints = map[string]string{
 	"sort": "Unix tool - typically pre-installed on Linux/macOS",
 	"curl": "Install curl: https://curl.se/download.html (often pre-installed)",
 	"wget": "Install wget: https://www.gnu.org/software/wget/ or use curl instead",
+	"git":  "Install Git: https://git-scm.com/downloads",
 
 	// JSON/YAML processing tools
 	"jq": "Install jq: https://jqlang.github.io/jq/download/ (JSON processor)",
@@ -82,17 +90,51 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("command not found: %s\n  Resolution: Ensure '%s' is installed and available in your PATH.\n             If using a custom tool, install it or update your config to use an available alternative.", e.Command, e.Command)
 }
 
+// Kinds of commands a CommandCheck can belong to.
+const (
+	// CommandKindOutdated is a command of a rule's outdated.commands.
+	CommandKindOutdated = "outdated"
+	// CommandKindUpdate is a command of a rule's update.commands.
+	CommandKindUpdate = "update"
+	// CommandKindLock is a command of a rule's lock_files[].commands.
+	CommandKindLock = "lock"
+)
+
+// CommandCheck is the outcome of checking one command of one rule.
+//
+// Checks are the machine-readable counterpart of Errors: every command a
+// rule uses gets a check, found or not, so CI can report exactly which tool
+// is missing for which rule.
+//
+// Fields:
+//   - Rule: The rule the command belongs to
+//   - Kind: CommandKindOutdated, CommandKindUpdate, or CommandKindLock
+//   - Command: The command name
+//   - Found: Whether the command is available
+//   - Path: Resolved executable path; empty when missing or found only as a shell alias or function
+//   - Error: The validation error when the command is missing; nil otherwise
+type CommandCheck struct {
+	Rule    string
+	Kind    string
+	Command string
+	Found   bool
+	Path    string
+	Error   *ValidationError
+}
+
 // ValidateResult holds the result of pre-flight validation.
 //
 // This structure aggregates all validation errors and warnings discovered during
 // pre-flight checks. It provides methods to check for errors and format error messages.
 //
 // Fields:
-//   - Errors: List of validation errors for missing or unavailable commands
+//   - Errors: List of validation errors for missing or unavailable commands, one per command
 //   - Warnings: List of warning messages (currently unused, reserved for future use)
+//   - Checks: One entry per rule, kind, and command checked, in check order
 type ValidateResult struct {
 	Errors   []ValidationError
 	Warnings []string
+	Checks   []CommandCheck
 }
 
 // HasErrors returns true if there are validation errors.
@@ -106,6 +148,18 @@ func (r *ValidateResult) HasErrors() bool {
 	return len(r.Errors) > 0
 }
 
+// Err returns the validation errors as a single error.
+//
+// Returns:
+//   - error: The errors joined with errors.JoinErrors, so errors.As finds each *ValidationError; nil when there are none
+func (r *ValidateResult) Err() error {
+	errs := make([]*ValidationError, 0, len(r.Errors))
+	for i := range r.Errors {
+		errs = append(errs, &r.Errors[i])
+	}
+	return errors.JoinErrors(errs)
+}
+
 // ErrorMessage returns a formatted error message for all validation errors.
 //
 // This method consolidates all validation errors into a single, user-friendly
@@ -126,59 +180,121 @@ func (r *ValidateResult) ErrorMessage() string {
 	return sb.String()
 }
 
+// commandLookup is the cached outcome of looking up one command.
+//
+// Fields:
+//   - path: Resolved executable path; empty when not found through PATH
+//   - err: The validation error when the command is missing
+type commandLookup struct {
+	path string
+	err  *ValidationError
+}
+
+// commandChecker checks commands of rules, looking each command up once.
+//
+// Fields:
+//   - result: The result checks and errors are added to
+//   - lookups: Lookup outcome per command name
+//   - checked: Rule, kind, and command triples already recorded
+type commandChecker struct {
+	result  *ValidateResult
+	lookups map[string]commandLookup
+	checked map[string]bool
+}
+
+// newCommandChecker creates a checker adding to a new, empty result.
+//
+// Returns:
+//   - *commandChecker: Checker with empty caches
+func newCommandChecker() *commandChecker {
+	return &commandChecker{
+		result:  &ValidateResult{},
+		lookups: make(map[string]commandLookup),
+		checked: make(map[string]bool),
+	}
+}
+
+// check records a check for every command in a rule's commands string.
+//
+// A missing command is added to the result's errors the first time it is
+// looked up, so a tool used by several rules is reported once while each
+// rule still gets its own check.
+//
+// Parameters:
+//   - rule: The rule the commands belong to
+//   - kind: CommandKindOutdated, CommandKindUpdate, or CommandKindLock
+//   - commands: The rule's multi-line commands string
+func (c *commandChecker) check(rule, kind, commands string) {
+	for _, cmd := range extractCommands(commands) {
+		key := rule + "\x00" + kind + "\x00" + cmd
+		if c.checked[key] {
+			continue
+		}
+		c.checked[key] = true
+
+		lookup, ok := c.lookups[cmd]
+		if !ok {
+			path, err := lookupCommand(cmd)
+			lookup = commandLookup{path: path, err: err}
+			c.lookups[cmd] = lookup
+			if err != nil {
+				c.result.Errors = append(c.result.Errors, *err)
+			}
+		}
+		c.result.Checks = append(c.result.Checks, CommandCheck{
+			Rule:    rule,
+			Kind:    kind,
+			Command: cmd,
+			Found:   lookup.err == nil,
+			Path:    lookup.path,
+			Error:   lookup.err,
+		})
+	}
+}
+
+// checkRule records the outdated and update commands of a rule.
+//
+// Outdated commands are skipped for rules with a version_source.
+//
+// Parameters:
+//   - rule: The rule name
+//   - ruleCfg: The rule's configuration
+func (c *commandChecker) checkRule(rule string, ruleCfg config.PackageManagerCfg) {
+	// Rules with an offline version source never run their outdated commands
+	if ruleCfg.Outdated != nil && ruleCfg.VersionSource == "" {
+		c.check(rule, CommandKindOutdated, ruleCfg.Outdated.Commands)
+	}
+	if ruleCfg.Update != nil {
+		c.check(rule, CommandKindUpdate, ruleCfg.Update.Commands)
+	}
+}
+
 // ValidatePackages checks that all required commands for the given packages are available.
 //
 // It performs the following operations:
 //   - Extracts all commands from outdated and update configurations for each package's rule
 //   - Validates that each unique command exists in the system PATH or as a shell alias
 //   - Collects validation errors with resolution hints for missing commands
+//   - Records a CommandCheck per rule and command
 //
 // Parameters:
 //   - packages: List of packages to validate, each containing a rule name
 //   - cfg: Configuration containing rule definitions with outdated and update commands
 //
 // Returns:
-//   - *ValidateResult: Result containing any validation errors; never nil
+//   - *ValidateResult: Result containing any validation errors and the command checks; never nil
 func ValidatePackages(packages []formats.Package, cfg *config.Config) *ValidateResult {
 	verbose.Debugf("Preflight: validating commands for %d packages", len(packages))
-	result := &ValidateResult{}
-	checkedCommands := make(map[string]bool)
+	checker := newCommandChecker()
 
 	for _, p := range packages {
-		ruleCfg, ok := cfg.Rules[p.Rule]
-		if !ok {
-			continue
-		}
-
-		// Check outdated commands
-		if ruleCfg.Outdated != nil {
-			commands := extractCommands(ruleCfg.Outdated.Commands)
-			for _, cmd := range commands {
-				if !checkedCommands[cmd] {
-					checkedCommands[cmd] = true
-					if err := validateCommand(cmd); err != nil {
-						result.Errors = append(result.Errors, *err)
-					}
-				}
-			}
-		}
-
-		// Check update commands
-		if ruleCfg.Update != nil {
-			commands := extractCommands(ruleCfg.Update.Commands)
-			for _, cmd := range commands {
-				if !checkedCommands[cmd] {
-					checkedCommands[cmd] = true
-					if err := validateCommand(cmd); err != nil {
-						result.Errors = append(result.Errors, *err)
-					}
-				}
-			}
+		if ruleCfg, ok := cfg.Rules[p.Rule]; ok {
+			checker.checkRule(p.Rule, ruleCfg)
 		}
 	}
 
-	verbose.Debugf("Preflight: package validation complete - %d unique commands checked, %d errors", len(checkedCommands), len(result.Errors))
-	return result
+	verbose.Debugf("Preflight: package validation complete - %d unique commands checked, %d errors", len(checker.lookups), len(checker.result.Errors))
+	return checker.result
 }
 
 // ValidateRules checks that all required commands for the given rules are available.
@@ -187,53 +303,56 @@ func ValidatePackages(packages []formats.Package, cfg *config.Config) *ValidateR
 //   - Extracts all commands from outdated and update configurations for each rule
 //   - Validates that each unique command exists in the system PATH or as a shell alias
 //   - Collects validation errors with resolution hints for missing commands
+//   - Records a CommandCheck per rule and command
 //
 // Parameters:
 //   - rules: List of rule names to validate
 //   - cfg: Configuration containing rule definitions with outdated and update commands
 //
 // Returns:
-//   - *ValidateResult: Result containing any validation errors; never nil
+//   - *ValidateResult: Result containing any validation errors and the command checks; never nil
 func ValidateRules(rules []string, cfg *config.Config) *ValidateResult {
 	verbose.Debugf("Preflight: validating commands for %d rules", len(rules))
-	result := &ValidateResult{}
-	checkedCommands := make(map[string]bool)
+	checker := newCommandChecker()
 
 	for _, ruleName := range rules {
-		ruleCfg, ok := cfg.Rules[ruleName]
-		if !ok {
-			continue
+		if ruleCfg, ok := cfg.Rules[ruleName]; ok {
+			checker.checkRule(ruleName, ruleCfg)
 		}
+	}
 
-		// Check outdated commands
-		if ruleCfg.Outdated != nil {
-			commands := extractCommands(ruleCfg.Outdated.Commands)
-			for _, cmd := range commands {
-				if !checkedCommands[cmd] {
-					checkedCommands[cmd] = true
-					if err := validateCommand(cmd); err != nil {
-						result.Errors = append(result.Errors, *err)
-					}
-				}
-			}
-		}
+	verbose.Debugf("Preflight: rule validation complete - %d unique commands checked, %d errors", len(checker.lookups), len(checker.result.Errors))
+	return checker.result
+}
 
-		// Check update commands
-		if ruleCfg.Update != nil {
-			commands := extractCommands(ruleCfg.Update.Commands)
-			for _, cmd := range commands {
-				if !checkedCommands[cmd] {
-					checkedCommands[cmd] = true
-					if err := validateCommand(cmd); err != nil {
-						result.Errors = append(result.Errors, *err)
-					}
-				}
-			}
+// ValidateLockCommands checks that the lock file commands of the given packages' rules are available.
+//
+// Lock file commands resolve installed versions (lock_files[].commands). They
+// are not part of ValidatePackages because a missing lock command only leaves
+// installed versions unresolved; callers that want a full environment check
+// combine both results.
+//
+// Parameters:
+//   - packages: List of packages to validate, each containing a rule name
+//   - cfg: Configuration containing rule definitions with lock file commands
+//
+// Returns:
+//   - *ValidateResult: Result containing any validation errors and the command checks; never nil
+func ValidateLockCommands(packages []formats.Package, cfg *config.Config) *ValidateResult {
+	checker := newCommandChecker()
+
+	for _, p := range packages {
+		ruleCfg, ok := cfg.Rules[p.Rule]
+		if !ok {
+			continue
+		}
+		for _, lockCfg := range ruleCfg.LockFiles {
+			checker.check(p.Rule, CommandKindLock, lockCfg.Commands)
 		}
 	}
 
-	verbose.Debugf("Preflight: rule validation complete - %d unique commands checked, %d errors", len(checkedCommands), len(result.Errors))
-	return result
+	verbose.Debugf("Preflight: lock command validation complete - %d unique commands checked, %d errors", len(checker.lookups), len(checker.result.Errors))
+	return checker.result
 }
 
 // extractCommands extracts all command names from a multiline commands string.
@@ -301,6 +420,18 @@ func extractCommands(commands string) []string {
 
 // validateCommand checks if a command exists in PATH or as a shell alias.
 //
+// Parameters:
+//   - cmd: The command name to validate (e.g., "npm", "jq", "grep")
+//
+// Returns:
+//   - *ValidationError: Error with resolution hint if command not found; nil if command exists or cmd is empty
+func validateCommand(cmd string) *ValidationError {
+	_, err := lookupCommand(cmd)
+	return err
+}
+
+// lookupCommand finds a command in PATH or as a shell alias.
+//
 // It performs the following operations:
 //   - Returns nil for empty command names
 //   - First attempts exec.LookPath for fast binary lookup in PATH
@@ -308,23 +439,24 @@ func extractCommands(commands string) []string {
 //   - Returns ValidationError with resolution hint if command is not found
 //
 // Parameters:
-//   - cmd: The command name to validate (e.g., "npm", "jq", "grep")
+//   - cmd: The command name to look up (e.g., "npm", "jq", "grep")
 //
 // Returns:
+//   - string: The resolved executable path; empty when found only through the shell or missing
 //   - *ValidationError: Error with resolution hint if command not found; nil if command exists or cmd is empty
-func validateCommand(cmd string) *ValidationError {
+func lookupCommand(cmd string) (string, *ValidationError) {
 	if cmd == "" {
-		return nil
+		return "", nil
 	}
 
 	// First try exec.LookPath (faster, finds binaries)
-	if _, err := exec.LookPath(cmd); err == nil {
-		return nil
+	if path, err := exec.LookPath(cmd); err == nil {
+		return path, nil
 	}
 
 	// Fall back to shell-based check to support aliases
 	if commandExistsInShell(cmd) {
-		return nil
+		return "", nil
 	}
 
 	hint := CommandResolutionHints[cmd]
@@ -333,7 +465,7 @@ func validateCommand(cmd string) *ValidationError {
 	} else {
 		verbose.Printf("Preflight ERROR: command %q not found (no resolution hint available)\n", cmd)
 	}
-	return &ValidationError{
+	return "", &ValidationError{
 		Command: cmd,
 		Hint:    hint,
 	}
@@ -1,6 +1,7 @@
 package preflight
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"testing"
@@ -158,6 +159,7 @@ func TestValidationError(t *testing.T) {
 //   - Non-existent commands in outdated and update configs are detected
 //   - Multiple missing commands are all detected
 //   - Error message is generated for missing commands
+//   - Err exposes every missing command as a *ValidationError
 func TestValidatePackagesDetectsMissingCommands(t *testing.T) {
 	cfg := &config.Config{
 		Rules: map[string]config.PackageManagerCfg{
@@ -191,6 +193,14 @@ func TestValidatePackagesDetectsMissingCommands(t *testing.T) {
 	if msg == "" {
 		t.Error("ErrorMessage() should not be empty when there are errors")
 	}
+
+	var missing *ValidationError
+	if !errors.As(result.Err(), &missing) || missing.Command != result.Errors[0].Command {
+		t.Errorf("Err() should expose the first missing command, got %v", result.Err())
+	}
+	if (&ValidateResult{}).Err() != nil {
+		t.Error("Err() should be nil without errors")
+	}
 }
 
 // TestValidateRulesDetectsMissingCommands tests the behavior of rule validation with missing commands.
@@ -674,3 +684,105 @@ func TestGetShellCommandCheckFallback(t *testing.T) {
 		t.Errorf("getShellCommandCheck() should return at least 2 args, got %d", len(args))
 	}
 }
+
+// TestValidateLockCommands tests the behavior of lock file command validation.
+//
+// It verifies:
+//   - Missing lock file commands are reported once per command
+//   - Rules without lock file commands and unknown rules are skipped
+//   - Available lock file commands pass
+func TestValidateLockCommands(t *testing.T) {
+	cfg := &config.Config{
+		Rules: map[string]config.PackageManagerCfg{
+			"custom": {
+				LockFiles: []config.LockFileCfg{
+					{Files: []string{"custom.lock"}, Commands: "nonexistent_lock_cmd_xyz list"},
+					{Files: []string{"other.lock"}, Commands: "nonexistent_lock_cmd_xyz show | sort"},
+				},
+			},
+			"files": {
+				LockFiles: []config.LockFileCfg{{Files: []string{"files.lock"}}},
+			},
+		},
+	}
+
+	packages := []formats.Package{
+		{Name: "a", Rule: "custom"},
+		{Name: "b", Rule: "custom"},
+		{Name: "c", Rule: "files"},
+		{Name: "d", Rule: "unknown"},
+	}
+
+	result := ValidateLockCommands(packages, cfg)
+	if len(result.Errors) != 1 {
+		t.Fatalf("ValidateLockCommands() should detect 1 missing command, got %d", len(result.Errors))
+	}
+	if result.Errors[0].Command != "nonexistent_lock_cmd_xyz" {
+		t.Errorf("ValidateLockCommands() reported %q, want nonexistent_lock_cmd_xyz", result.Errors[0].Command)
+	}
+
+	cfg.Rules["custom"] = config.PackageManagerCfg{
+		LockFiles: []config.LockFileCfg{{Files: []string{"custom.lock"}, Commands: "echo ok"}},
+	}
+	if result := ValidateLockCommands(packages, cfg); result.HasErrors() {
+		t.Errorf("ValidateLockCommands() should pass for available commands, got %s", result.ErrorMessage())
+	}
+}
+
+// TestValidateChecks tests the per-rule command checks of a validation result.
+//
+// It verifies:
+//   - Every command of every rule gets a check, found or not, in check order
+//   - Found commands carry their resolved path; missing ones carry the error
+//   - A command used by two rules is checked for each rule but reported as one error
+func TestValidateChecks(t *testing.T) {
+	cfg := &config.Config{
+		Rules: map[string]config.PackageManagerCfg{
+			"npm": {
+				Outdated: &config.OutdatedCfg{Commands: "sh -c true"},
+				Update:   &config.UpdateCfg{Commands: "nonexistent_check_cmd_xyz install"},
+			},
+			"pip": {
+				Outdated: &config.OutdatedCfg{Commands: "nonexistent_check_cmd_xyz list"},
+			},
+		},
+	}
+	packages := []formats.Package{
+		{Name: "a", Rule: "npm"},
+		{Name: "b", Rule: "npm"},
+		{Name: "c", Rule: "pip"},
+	}
+
+	result := ValidatePackages(packages, cfg)
+	if len(result.Errors) != 1 {
+		t.Fatalf("ValidatePackages() should report the missing command once, got %d errors", len(result.Errors))
+	}
+	if len(result.Checks) != 3 {
+		t.Fatalf("ValidatePackages() should record 3 checks, got %d: %+v", len(result.Checks), result.Checks)
+	}
+
+	shPath, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	found := result.Checks[0]
+	if found.Rule != "npm" || found.Kind != CommandKindOutdated || found.Command != "sh" || !found.Found || found.Path != shPath || found.Error != nil {
+		t.Errorf("unexpected check for sh: %+v", found)
+	}
+	for i, rule := range []string{"npm", "pip"} {
+		missing := result.Checks[i+1]
+		if missing.Rule != rule || missing.Command != "nonexistent_check_cmd_xyz" || missing.Found || missing.Path != "" || missing.Error == nil {
+			t.Errorf("unexpected check for missing command of %s: %+v", rule, missing)
+		}
+	}
+	if result.Checks[1].Kind != CommandKindUpdate || result.Checks[2].Kind != CommandKindOutdated {
+		t.Errorf("unexpected kinds: %s, %s", result.Checks[1].Kind, result.Checks[2].Kind)
+	}
+
+	lock := ValidateLockCommands(packages, &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"npm": {LockFiles: []config.LockFileCfg{{Commands: "nonexistent_check_cmd_xyz ls"}}},
+	}})
+	if len(lock.Checks) != 1 || lock.Checks[0].Kind != CommandKindLock || lock.Checks[0].Found {
+		t.Errorf("unexpected lock checks: %+v", lock.Checks)
+	}
+}
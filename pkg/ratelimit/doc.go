@@ -0,0 +1,18 @@
+// Package ratelimit throttles registry requests made on behalf of a rule.
+//
+// Rules with max_requests_per_second set share one limiter per rule for the
+// whole process, so version lookups from every worker, digest lookups, and
+// update commands together stay under the configured rate no matter how many
+// workers run concurrently.
+//
+// # Core Types
+//
+// Limiter is a token bucket holding a single token that refills at the
+// configured rate. Callers block in Wait until a token is available:
+//
+//	if err := ratelimit.ForRule(cfg, p.Rule).Wait(ctx); err != nil {
+//		return nil, err
+//	}
+//
+// A nil Limiter never blocks, so rules without a limit need no special casing.
+package ratelimit
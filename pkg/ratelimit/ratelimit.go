@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// nowFunc returns the current time.
+// It is a variable so tests can control the clock.
+var nowFunc = time.Now
+
+// sleepFunc waits for the given duration or until the context is done.
+// It is a variable so tests can avoid real sleeps.
+var sleepFunc = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Limiter is a token bucket with a capacity of one token.
+//
+// Requests are spaced at least one interval apart, so the sustained rate never
+// exceeds the configured requests per second and there are no bursts.
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// New creates a limiter allowing perSecond requests per second.
+//
+// Parameters:
+//   - perSecond: Sustained request rate; fractional rates such as 0.5 are allowed
+//
+// Returns:
+//   - *Limiter: The limiter, or nil when perSecond is not positive (unlimited)
+func New(perSecond float64) *Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &Limiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Interval returns the minimum delay between two requests.
+//
+// Returns:
+//   - time.Duration: The spacing between requests; zero for a nil limiter
+func (l *Limiter) Interval() time.Duration {
+	if l == nil {
+		return 0
+	}
+	return l.interval
+}
+
+// Wait blocks until the caller may make a request.
+//
+// The caller's slot is reserved before sleeping, so concurrent callers queue
+// up in arrival order instead of racing for the same token.
+//
+// Parameters:
+//   - ctx: Context for cancellation while waiting
+//
+// Returns:
+//   - error: The context error when cancelled before the slot is reached; nil otherwise
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := nowFunc()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	verbose.Tracef("Rate limit: waiting %s before next request", delay)
+	return sleepFunc(ctx, delay)
+}
+
+// registry holds the shared limiter of each rule.
+var registry = struct {
+	sync.Mutex
+	limiters map[string]*Limiter
+}{limiters: make(map[string]*Limiter)}
+
+// ForRule returns the shared limiter of a rule.
+//
+// Parameters:
+//   - cfg: The global configuration
+//   - rule: The rule name
+//
+// Returns:
+//   - *Limiter: The rule's limiter, or nil when the rule has no max_requests_per_second
+func ForRule(cfg *config.Config, rule string) *Limiter {
+	if cfg == nil {
+		return nil
+	}
+	ruleCfg, ok := cfg.Rules[rule]
+	if !ok {
+		return nil
+	}
+	return For(rule, ruleCfg.GetMaxRequestsPerSecond())
+}
+
+// For returns the shared limiter of a rule at a given rate.
+//
+// Every caller for the same rule and rate gets the same limiter, so the limit
+// applies across workers and across version lookups and updates.
+//
+// Parameters:
+//   - rule: The rule name
+//   - perSecond: The rule's max_requests_per_second
+//
+// Returns:
+//   - *Limiter: The shared limiter, or nil when perSecond is not positive
+func For(rule string, perSecond float64) *Limiter {
+	limiter := New(perSecond)
+	if limiter == nil {
+		return nil
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	// Key by rate too, so a reloaded config with a new rate gets a fresh limiter
+	key := rule + "@" + limiter.interval.String()
+	if existing, ok := registry.limiters[key]; ok {
+		return existing
+	}
+	registry.limiters[key] = limiter
+	return limiter
+}
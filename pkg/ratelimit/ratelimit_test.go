@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+)
+
+// stubClock freezes nowFunc at a fixed time and records sleeps instead of waiting.
+// It returns a setter for the current time and a function returning the recorded delays.
+func stubClock(t *testing.T) (func(time.Time), func() []time.Duration) {
+	t.Helper()
+
+	origNow := nowFunc
+	origSleep := sleepFunc
+	t.Cleanup(func() {
+		nowFunc = origNow
+		sleepFunc = origSleep
+	})
+
+	var mu sync.Mutex
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var delays []time.Duration
+
+	nowFunc = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	sleepFunc = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+
+	setNow := func(tm time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = tm
+	}
+	recorded := func() []time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Duration(nil), delays...)
+	}
+	return setNow, recorded
+}
+
+// TestNew tests the behavior of New.
+//
+// It verifies:
+//   - Non-positive rates return a nil limiter that never blocks
+//   - The interval is one second divided by the rate, including fractional rates
+func TestNew(t *testing.T) {
+	assert.Nil(t, New(0))
+	assert.Nil(t, New(-1))
+
+	var unlimited *Limiter
+	assert.NoError(t, unlimited.Wait(context.Background()))
+	assert.Zero(t, unlimited.Interval())
+
+	assert.Equal(t, 200*time.Millisecond, New(5).Interval())
+	assert.Equal(t, 2*time.Second, New(0.5).Interval())
+}
+
+// TestLimiterWait tests the behavior of Limiter.Wait.
+//
+// It verifies:
+//   - The first request proceeds immediately
+//   - Back-to-back requests are spaced one interval apart
+//   - Idle time does not accumulate into a burst
+//   - A cancelled context returns its error
+func TestLimiterWait(t *testing.T) {
+	setNow, recorded := stubClock(t)
+	start := nowFunc()
+	limiter := New(5)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+	assert.Equal(t, []time.Duration{200 * time.Millisecond, 400 * time.Millisecond}, recorded())
+
+	// After a long idle period only one request proceeds without waiting
+	setNow(start.Add(time.Minute))
+	require.NoError(t, limiter.Wait(context.Background()))
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.Equal(t, []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 200 * time.Millisecond}, recorded())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx), context.Canceled)
+}
+
+// TestLimiterWaitConcurrent tests the behavior of Limiter.Wait with concurrent callers.
+//
+// It verifies:
+//   - Each concurrent caller reserves a distinct slot, so throughput is capped regardless of worker count
+func TestLimiterWaitConcurrent(t *testing.T) {
+	_, recorded := stubClock(t)
+	limiter := New(10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, limiter.Wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	delays := recorded()
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+	expected := make([]time.Duration, 0, 7)
+	for i := 1; i < 8; i++ {
+		expected = append(expected, time.Duration(i)*100*time.Millisecond)
+	}
+	assert.Equal(t, expected, delays)
+}
+
+// TestForRule tests the behavior of ForRule.
+//
+// It verifies:
+//   - Rules without max_requests_per_second, unknown rules, and a nil config have no limiter
+//   - Callers for the same rule share one limiter
+//   - Different rules and changed rates get separate limiters
+func TestForRule(t *testing.T) {
+	rate, changedRate := 4.0, 2.0
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"npm":      {MaxRequestsPerSecond: &rate},
+		"composer": {MaxRequestsPerSecond: &rate},
+		"mod":      {},
+	}}
+
+	assert.Nil(t, ForRule(nil, "npm"))
+	assert.Nil(t, ForRule(cfg, "mod"))
+	assert.Nil(t, ForRule(cfg, "missing"))
+
+	npm := ForRule(cfg, "npm")
+	require.NotNil(t, npm)
+	assert.Same(t, npm, ForRule(cfg, "npm"))
+	assert.NotSame(t, npm, ForRule(cfg, "composer"))
+
+	changed := &config.Config{Rules: map[string]config.PackageManagerCfg{"npm": {MaxRequestsPerSecond: &changedRate}}}
+	assert.NotSame(t, npm, ForRule(changed, "npm"))
+	assert.Equal(t, 500*time.Millisecond, ForRule(changed, "npm").Interval())
+}
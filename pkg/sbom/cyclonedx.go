@@ -0,0 +1,269 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/utils"
+)
+
+// CycloneDXSpecVersion is the CycloneDX specification version produced by BuildCycloneDX.
+const CycloneDXSpecVersion = "1.5"
+
+// Document is a CycloneDX BOM document.
+//
+// Fields:
+//   - BOMFormat: Always "CycloneDX"
+//   - SpecVersion: CycloneDX specification version
+//   - SerialNumber: Unique URN identifying this BOM
+//   - Version: BOM revision, always 1
+//   - Metadata: Generation time and tool information
+//   - Components: One library component per installed package
+type Document struct {
+	BOMFormat    string      `json:"bomFormat"`
+	SpecVersion  string      `json:"specVersion"`
+	SerialNumber string      `json:"serialNumber,omitempty"`
+	Version      int         `json:"version"`
+	Metadata     Metadata    `json:"metadata"`
+	Components   []Component `json:"components"`
+}
+
+// Metadata describes when and by which tool the BOM was generated.
+//
+// Fields:
+//   - Timestamp: Generation time in RFC 3339 format
+//   - Tools: Tools that produced the BOM
+type Metadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     Tools  `json:"tools"`
+}
+
+// Tools lists the tools that produced the BOM.
+//
+// Fields:
+//   - Components: Tool components (goupdate itself)
+type Tools struct {
+	Components []Component `json:"components"`
+}
+
+// Component is a CycloneDX component.
+//
+// Fields:
+//   - Type: Component type ("library" for packages, "application" for tools)
+//   - BOMRef: Unique reference within the BOM (the Package URL for packages)
+//   - Name: Package name
+//   - Version: Installed version
+//   - Scope: "required" for prod dependencies, "optional" for dev dependencies
+//   - PURL: Package URL
+//   - Properties: goupdate-specific details (rule, type, source manifests)
+type Component struct {
+	Type       string     `json:"type"`
+	BOMRef     string     `json:"bom-ref,omitempty"`
+	Name       string     `json:"name"`
+	Version    string     `json:"version,omitempty"`
+	Scope      string     `json:"scope,omitempty"`
+	PURL       string     `json:"purl,omitempty"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+// Property is a CycloneDX name/value property.
+//
+// Fields:
+//   - Name: Property name, namespaced with "goupdate:"
+//   - Value: Property value
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Options configures BOM generation.
+//
+// Fields:
+//   - ToolVersion: goupdate version recorded in the BOM metadata
+//   - Timestamp: Generation time; zero uses the current time
+//   - SerialNumber: BOM serial number; empty generates a random UUID URN
+type Options struct {
+	ToolVersion  string
+	Timestamp    time.Time
+	SerialNumber string
+}
+
+// BuildCycloneDX builds a CycloneDX document from resolved packages.
+//
+// It performs the following operations:
+//   - Step 1: Skip packages without a concrete installed version (see HasConcreteVersion)
+//   - Step 2: Map each remaining package to a library component keyed by Package URL
+//   - Step 3: Merge duplicate Package URLs, recording every source manifest
+//   - Step 4: Sort components by Package URL for stable output
+//
+// Parameters:
+//   - pkgs: Packages with installed versions applied (see lock.ApplyInstalledVersions)
+//   - opts: Generation options
+//
+// Returns:
+//   - *Document: The CycloneDX document
+//   - []formats.Package: Packages omitted because they have no concrete installed version
+func BuildCycloneDX(pkgs []formats.Package, opts Options) (*Document, []formats.Package) {
+	timestamp := opts.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	serial := opts.SerialNumber
+	if serial == "" {
+		serial = newSerialNumber()
+	}
+
+	byPURL := make(map[string]*Component)
+	var omitted []formats.Package
+
+	for _, p := range pkgs {
+		if !HasConcreteVersion(p) {
+			omitted = append(omitted, p)
+			continue
+		}
+
+		version := strings.TrimSpace(p.InstalledVersion)
+		purl := PackageURL(p, version)
+
+		if existing, ok := byPURL[purl]; ok {
+			if p.Source != "" {
+				existing.Properties = append(existing.Properties, Property{Name: "goupdate:source", Value: p.Source})
+			}
+			// A package required anywhere is required for the whole set
+			if p.Type != "dev" {
+				existing.Scope = "required"
+			}
+			continue
+		}
+
+		byPURL[purl] = &Component{
+			Type:       "library",
+			BOMRef:     purl,
+			Name:       p.Name,
+			Version:    version,
+			Scope:      componentScope(p),
+			PURL:       purl,
+			Properties: componentProperties(p),
+		}
+	}
+
+	components := make([]Component, 0, len(byPURL))
+	for _, component := range byPURL {
+		components = append(components, *component)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].PURL < components[j].PURL
+	})
+
+	return &Document{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  CycloneDXSpecVersion,
+		SerialNumber: serial,
+		Version:      1,
+		Metadata: Metadata{
+			Timestamp: timestamp.UTC().Format(time.RFC3339),
+			Tools: Tools{Components: []Component{{
+				Type:    "application",
+				Name:    "goupdate",
+				Version: opts.ToolVersion,
+			}}},
+		},
+		Components: components,
+	}, omitted
+}
+
+// HasConcreteVersion reports whether a package has a concrete installed version.
+//
+// Packages with a missing installed version ("", "#N/A"), a floating
+// installed value ("*", "5.*"), or VersionMissing status cannot be pinned to a
+// single Package URL and are excluded from the BOM.
+//
+// Parameters:
+//   - p: Package to check
+//
+// Returns:
+//   - bool: true if the installed version identifies a single release
+func HasConcreteVersion(p formats.Package) bool {
+	if p.InstallStatus == lock.InstallStatusVersionMissing {
+		return false
+	}
+	version := strings.TrimSpace(p.InstalledVersion)
+	if version == "" || version == constants.PlaceholderNA {
+		return false
+	}
+	return !utils.IsFloatingConstraint(version)
+}
+
+// WriteCycloneDXJSON writes a CycloneDX document as indented JSON.
+//
+// Parameters:
+//   - w: Writer to output to
+//   - doc: The document to write
+//
+// Returns:
+//   - error: Returns error if encoding or writing fails
+func WriteCycloneDXJSON(w io.Writer, doc *Document) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write CycloneDX JSON: %w", err)
+	}
+	return nil
+}
+
+// componentScope maps a dependency type to a CycloneDX scope.
+//
+// Parameters:
+//   - p: Package providing the dependency type
+//
+// Returns:
+//   - string: "optional" for dev dependencies, otherwise "required"
+func componentScope(p formats.Package) string {
+	if p.Type == "dev" {
+		return "optional"
+	}
+	return "required"
+}
+
+// componentProperties records goupdate-specific package details as properties.
+//
+// Parameters:
+//   - p: Package to describe
+//
+// Returns:
+//   - []Property: Rule, dependency type, install status, non-registry source, and source manifest
+func componentProperties(p formats.Package) []Property {
+	var props []Property
+	add := func(name, value string) {
+		if value != "" {
+			props = append(props, Property{Name: "goupdate:" + name, Value: value})
+		}
+	}
+
+	add("rule", p.Rule)
+	add("type", p.Type)
+	add("install_status", p.InstallStatus)
+	add("non_registry_source", p.NonRegistrySource)
+	add("source", p.Source)
+	return props
+}
+
+// newSerialNumber generates a random UUID URN for the BOM serial number.
+//
+// Returns:
+//   - string: A version 4 UUID URN (e.g., "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79")
+func newSerialNumber() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
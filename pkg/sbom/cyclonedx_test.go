@@ -0,0 +1,123 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+)
+
+// TestBuildCycloneDX tests the behavior of BuildCycloneDX.
+//
+// It verifies:
+//   - Packages with concrete installed versions become library components
+//   - Components are sorted by Package URL and carry goupdate properties
+//   - Dev dependencies are optional and prod dependencies required
+//   - Duplicate Package URLs are merged, recording every source and widening scope
+//   - Packages without a concrete installed version are returned as omitted
+//   - Metadata records the tool version, timestamp, and serial number
+func TestBuildCycloneDX(t *testing.T) {
+	pkgs := []formats.Package{
+		{Name: "react", PackageType: "js", Rule: "npm", Type: "prod", InstalledVersion: "18.2.0", InstallStatus: lock.InstallStatusLockFound, Source: "web/package.json"},
+		{Name: "jest", PackageType: "js", Rule: "npm", Type: "dev", InstalledVersion: "29.7.0", InstallStatus: lock.InstallStatusLockFound, Source: "web/package.json"},
+		{Name: "react", PackageType: "js", Rule: "npm", Type: "dev", InstalledVersion: "18.2.0", InstallStatus: lock.InstallStatusLockFound, Source: "admin/package.json"},
+		{Name: "jest", PackageType: "js", Rule: "npm", Type: "dev", InstalledVersion: "29.7.0", InstallStatus: lock.InstallStatusLockFound, Source: "admin/package.json"},
+		{Name: "lodash", PackageType: "js", Rule: "npm", Type: "prod", InstalledVersion: "#N/A", InstallStatus: lock.InstallStatusNotInLock},
+		{Name: "requests", PackageType: "python", Rule: "requirements", Type: "prod", Version: "*", InstalledVersion: "*", InstallStatus: lock.InstallStatusFloating},
+		{Name: "flask", PackageType: "python", Rule: "requirements", Type: "prod", InstalledVersion: "", InstallStatus: lock.InstallStatusVersionMissing},
+	}
+
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	doc, omitted := BuildCycloneDX(pkgs, Options{ToolVersion: "1.2.0", Timestamp: timestamp, SerialNumber: "urn:uuid:test"})
+
+	assert.Equal(t, "CycloneDX", doc.BOMFormat)
+	assert.Equal(t, "1.5", doc.SpecVersion)
+	assert.Equal(t, "urn:uuid:test", doc.SerialNumber)
+	assert.Equal(t, 1, doc.Version)
+	assert.Equal(t, "2024-05-01T12:00:00Z", doc.Metadata.Timestamp)
+	require.Len(t, doc.Metadata.Tools.Components, 1)
+	assert.Equal(t, "goupdate", doc.Metadata.Tools.Components[0].Name)
+	assert.Equal(t, "1.2.0", doc.Metadata.Tools.Components[0].Version)
+
+	require.Len(t, doc.Components, 2)
+	jest, react := doc.Components[0], doc.Components[1]
+
+	assert.Equal(t, "pkg:npm/jest@29.7.0", jest.PURL)
+	assert.Equal(t, jest.PURL, jest.BOMRef)
+	assert.Equal(t, "library", jest.Type)
+	assert.Equal(t, "optional", jest.Scope)
+	assert.Contains(t, jest.Properties, Property{Name: "goupdate:source", Value: "admin/package.json"})
+
+	assert.Equal(t, "pkg:npm/react@18.2.0", react.PURL)
+	assert.Equal(t, "18.2.0", react.Version)
+	assert.Equal(t, "required", react.Scope, "a package required by any manifest is required")
+	assert.Contains(t, react.Properties, Property{Name: "goupdate:rule", Value: "npm"})
+	assert.Contains(t, react.Properties, Property{Name: "goupdate:source", Value: "web/package.json"})
+	assert.Contains(t, react.Properties, Property{Name: "goupdate:source", Value: "admin/package.json"})
+
+	require.Len(t, omitted, 3)
+	assert.Equal(t, "lodash", omitted[0].Name)
+	assert.Equal(t, "requests", omitted[1].Name)
+	assert.Equal(t, "flask", omitted[2].Name)
+}
+
+// TestBuildCycloneDXDefaults tests BuildCycloneDX defaults.
+//
+// It verifies:
+//   - A random UUID URN serial number is generated when none is given
+//   - An empty package list produces an empty components array, not null
+func TestBuildCycloneDXDefaults(t *testing.T) {
+	doc, omitted := BuildCycloneDX(nil, Options{})
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, doc.SerialNumber)
+	assert.NotEmpty(t, doc.Metadata.Timestamp)
+	assert.Empty(t, omitted)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCycloneDXJSON(&buf, doc))
+	assert.Contains(t, buf.String(), `"components": []`)
+}
+
+// TestWriteCycloneDXJSON tests the behavior of WriteCycloneDXJSON.
+//
+// It verifies:
+//   - The document is written as valid JSON with CycloneDX field names
+func TestWriteCycloneDXJSON(t *testing.T) {
+	doc, _ := BuildCycloneDX([]formats.Package{
+		{Name: "rails", PackageType: "ruby", Rule: "bundler", Type: "prod", InstalledVersion: "7.1.2", InstallStatus: lock.InstallStatusLockFound},
+	}, Options{ToolVersion: "dev", SerialNumber: "urn:uuid:test"})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCycloneDXJSON(&buf, doc))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "CycloneDX", decoded["bomFormat"])
+	assert.Equal(t, "1.5", decoded["specVersion"])
+
+	components := decoded["components"].([]interface{})
+	require.Len(t, components, 1)
+	component := components[0].(map[string]interface{})
+	assert.Equal(t, "pkg:gem/rails@7.1.2", component["purl"])
+	assert.Equal(t, "pkg:gem/rails@7.1.2", component["bom-ref"])
+}
+
+// TestHasConcreteVersion tests the behavior of HasConcreteVersion.
+//
+// It verifies:
+//   - Lock-resolved and self-pinned versions are concrete
+//   - Missing, placeholder, and floating installed values are not
+//   - VersionMissing status is never concrete
+func TestHasConcreteVersion(t *testing.T) {
+	assert.True(t, HasConcreteVersion(formats.Package{InstalledVersion: "1.2.3", InstallStatus: lock.InstallStatusLockFound}))
+	assert.True(t, HasConcreteVersion(formats.Package{InstalledVersion: "2.31.0", InstallStatus: lock.InstallStatusSelfPinned}))
+	assert.False(t, HasConcreteVersion(formats.Package{InstalledVersion: ""}))
+	assert.False(t, HasConcreteVersion(formats.Package{InstalledVersion: "#N/A", InstallStatus: lock.InstallStatusLockMissing}))
+	assert.False(t, HasConcreteVersion(formats.Package{InstalledVersion: "5.*"}))
+	assert.False(t, HasConcreteVersion(formats.Package{InstalledVersion: "1.0.0", InstallStatus: lock.InstallStatusVersionMissing}))
+}
@@ -0,0 +1,23 @@
+// Package sbom builds software bills of materials from resolved packages.
+//
+// It is used by `goupdate sbom` to export the dependency set that goupdate
+// already collects (declared packages enriched with installed versions from
+// lock files) as a CycloneDX document.
+//
+// # Core Types
+//
+// BuildCycloneDX converts packages into a CycloneDX 1.5 Document. Each package
+// with a concrete installed version becomes a library component identified by
+// a Package URL (see PackageURL):
+//
+//	doc, omitted := sbom.BuildCycloneDX(packages, sbom.Options{ToolVersion: "1.2.0"})
+//	err := sbom.WriteCycloneDXJSON(os.Stdout, doc)
+//
+// # Omitted Packages
+//
+// Packages without a concrete installed version (missing lock entries,
+// floating versions with nothing installed, unconfigured lock files) cannot be
+// identified by a versioned Package URL. They are returned separately so the
+// caller can report them to the unsupported tracker instead of silently
+// dropping them.
+package sbom
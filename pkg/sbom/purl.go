@@ -0,0 +1,87 @@
+package sbom
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// purlTypes maps package manager identifiers to Package URL types.
+var purlTypes = map[string]string{
+	"js":     "npm",
+	"golang": "golang",
+	"php":    "composer",
+	"python": "pypi",
+	"dotnet": "nuget",
+	"ruby":   "gem",
+	"java":   "maven",
+}
+
+// PurlType returns the Package URL type for a package manager.
+//
+// Parameters:
+//   - packageType: Package manager identifier (e.g., "js", "golang")
+//
+// Returns:
+//   - string: The Package URL type (e.g., "npm", "golang"), or "generic" for unknown managers
+func PurlType(packageType string) string {
+	if purlType, ok := purlTypes[strings.ToLower(packageType)]; ok {
+		return purlType
+	}
+	return "generic"
+}
+
+// PackageURL builds a Package URL (purl) for a package at a version.
+//
+// It performs the following operations:
+//   - Maps the package manager to a purl type (see PurlType)
+//   - Normalizes PyPI names to lowercase with dashes, as the purl spec requires
+//   - Turns Maven group:artifact names into group/artifact
+//   - Splits slash-separated names into namespace and name segments
+//   - Percent-encodes each segment and the version
+//
+// Parameters:
+//   - p: Package providing PackageType and Name
+//   - version: The version to encode (typically the installed version)
+//
+// Returns:
+//   - string: The Package URL (e.g., "pkg:npm/%40babel/core@7.23.0")
+//
+// Example:
+//
+//	purl := sbom.PackageURL(formats.Package{Name: "github.com/pkg/errors", PackageType: "golang"}, "v0.9.1")
+//	// purl == "pkg:golang/github.com/pkg/errors@v0.9.1"
+func PackageURL(p formats.Package, version string) string {
+	purlType := PurlType(p.PackageType)
+
+	name := strings.TrimSpace(p.Name)
+	if purlType == "pypi" {
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	}
+	if purlType == "maven" {
+		name = strings.Replace(name, ":", "/", 1)
+	}
+
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = escapePurlSegment(segment)
+	}
+
+	purl := "pkg:" + purlType + "/" + strings.Join(segments, "/")
+	if version != "" {
+		purl += "@" + escapePurlSegment(version)
+	}
+	return purl
+}
+
+// escapePurlSegment percent-encodes a single purl path segment.
+//
+// Parameters:
+//   - segment: A namespace, name, or version segment
+//
+// Returns:
+//   - string: The encoded segment, with "@" always encoded as "%40"
+func escapePurlSegment(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+}
@@ -0,0 +1,44 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestPackageURL tests the behavior of PackageURL.
+//
+// It verifies:
+//   - Each built-in package manager maps to its purl type
+//   - Scoped npm names encode "@" and keep the namespace separator
+//   - Go module paths and Composer vendors become namespace segments
+//   - PyPI names are normalized to lowercase with dashes
+//   - Maven group:artifact names become a group namespace and artifact name
+//   - Unknown managers fall back to the generic type
+func TestPackageURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkg      formats.Package
+		version  string
+		expected string
+	}{
+		{"npm", formats.Package{Name: "lodash", PackageType: "js"}, "4.17.21", "pkg:npm/lodash@4.17.21"},
+		{"npm scoped", formats.Package{Name: "@babel/core", PackageType: "js"}, "7.23.0", "pkg:npm/%40babel/core@7.23.0"},
+		{"golang", formats.Package{Name: "github.com/pkg/errors", PackageType: "golang"}, "v0.9.1", "pkg:golang/github.com/pkg/errors@v0.9.1"},
+		{"composer", formats.Package{Name: "laravel/framework", PackageType: "php"}, "10.0.0", "pkg:composer/laravel/framework@10.0.0"},
+		{"pypi", formats.Package{Name: "Django_Extensions", PackageType: "python"}, "3.2.3", "pkg:pypi/django-extensions@3.2.3"},
+		{"nuget", formats.Package{Name: "Newtonsoft.Json", PackageType: "dotnet"}, "13.0.3", "pkg:nuget/Newtonsoft.Json@13.0.3"},
+		{"gem", formats.Package{Name: "rails", PackageType: "ruby"}, "7.1.2", "pkg:gem/rails@7.1.2"},
+		{"maven", formats.Package{Name: "com.squareup.okhttp3:okhttp", PackageType: "java"}, "4.12.0", "pkg:maven/com.squareup.okhttp3/okhttp@4.12.0"},
+		{"generic", formats.Package{Name: "tool", PackageType: "custom"}, "1.0+build 2", "pkg:generic/tool@1.0+build%202"},
+		{"no version", formats.Package{Name: "lodash", PackageType: "js"}, "", "pkg:npm/lodash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PackageURL(tt.pkg, tt.version))
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/filtering"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/supervision"
+	"github.com/ajxudir/goupdate/pkg/utils"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// Advisory describes a single known vulnerability affecting a package version.
+type Advisory struct {
+	// ID is the advisory identifier (e.g., "GHSA-jf85-cpcp-j695").
+	ID string
+
+	// Summary is a short human-readable description.
+	Summary string
+
+	// Aliases lists alternative identifiers such as CVE numbers.
+	Aliases []string
+
+	// FixedVersions lists versions in which the advisory is resolved.
+	// Multiple entries occur when fixes were backported to several release lines.
+	FixedVersions []string
+}
+
+// Provider looks up advisories affecting a specific package version.
+type Provider interface {
+	// Query returns the advisories that affect the given package version.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation
+	//   - ecosystem: Advisory ecosystem name (e.g., "npm", "PyPI", "Go")
+	//   - name: Package name
+	//   - version: Installed version to check
+	//
+	// Returns:
+	//   - []Advisory: Advisories affecting the version; empty if none
+	//   - error: When the lookup fails
+	Query(ctx context.Context, ecosystem, name, version string) ([]Advisory, error)
+}
+
+// ruleEcosystems maps built-in rule names to advisory ecosystems.
+var ruleEcosystems = map[string]string{
+	"npm":          "npm",
+	"pnpm":         "npm",
+	"yarn":         "npm",
+	"bun":          "npm",
+	"composer":     "Packagist",
+	"requirements": "PyPI",
+	"pipfile":      "PyPI",
+	"poetry":       "PyPI",
+	"uv":           "PyPI",
+	"bundler":      "RubyGems",
+	"mod":          "Go",
+	"msbuild":      "NuGet",
+	"nuget":        "NuGet",
+}
+
+// managerEcosystems maps package manager identifiers to advisory ecosystems.
+// Used as a fallback for custom rule names.
+var managerEcosystems = map[string]string{
+	"js":     "npm",
+	"php":    "Packagist",
+	"python": "PyPI",
+	"golang": "Go",
+	"dotnet": "NuGet",
+}
+
+// EcosystemFor returns the advisory ecosystem for a package.
+//
+// Parameters:
+//   - p: The package to map
+//
+// Returns:
+//   - string: The ecosystem name used by advisory databases
+//   - bool: false if no ecosystem is known for the package's rule or manager
+func EcosystemFor(p formats.Package) (string, bool) {
+	if ecosystem, ok := ruleEcosystems[strings.ToLower(p.Rule)]; ok {
+		return ecosystem, true
+	}
+	if ecosystem, ok := managerEcosystems[strings.ToLower(p.PackageType)]; ok {
+		return ecosystem, true
+	}
+	return "", false
+}
+
+// Report holds advisories found for a set of packages.
+//
+// Report implements filtering.AdvisoryMatcher.
+type Report struct {
+	affected map[string][]Advisory
+}
+
+// NewReport creates an empty advisory report.
+//
+// Returns:
+//   - *Report: An empty report
+func NewReport() *Report {
+	return &Report{affected: make(map[string][]Advisory)}
+}
+
+// Verify that Report implements the filtering.AdvisoryMatcher interface.
+var _ filtering.AdvisoryMatcher = (*Report)(nil)
+
+// reportKey builds the key identifying a package version in a report.
+func reportKey(p formats.Package) string {
+	return p.Rule + "|" + p.PackageType + "|" + p.Name + "|" + outdated.CurrentVersionForOutdated(p)
+}
+
+// Add records advisories for a package. Empty advisory lists are ignored.
+//
+// Parameters:
+//   - p: The package the advisories apply to
+//   - advisories: Advisories affecting the package's current version
+func (r *Report) Add(p formats.Package, advisories []Advisory) {
+	if len(advisories) == 0 {
+		return
+	}
+	key := reportKey(p)
+	r.affected[key] = append(r.affected[key], advisories...)
+}
+
+// IsAffected reports whether the package's current version has known advisories.
+//
+// Parameters:
+//   - p: The package to check
+//
+// Returns:
+//   - bool: true if at least one advisory affects the package
+func (r *Report) IsAffected(p formats.Package) bool {
+	if r == nil {
+		return false
+	}
+	return len(r.affected[reportKey(p)]) > 0
+}
+
+// Advisories returns the advisories recorded for a package.
+//
+// Parameters:
+//   - p: The package to look up
+//
+// Returns:
+//   - []Advisory: Advisories affecting the package, or nil if none
+func (r *Report) Advisories(p formats.Package) []Advisory {
+	if r == nil {
+		return nil
+	}
+	return r.affected[reportKey(p)]
+}
+
+// Count returns the number of affected packages in the report.
+//
+// Returns:
+//   - int: Number of package versions with at least one advisory
+func (r *Report) Count() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.affected)
+}
+
+// Scan queries advisories for each package and builds a report.
+//
+// Packages without a known ecosystem, without a concrete installed version,
+// or whose lookup fails are added to the tracker with a reason explaining why
+// no advisory data is available. Ignored packages are skipped.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - pkgs: Packages to check
+//   - provider: Advisory provider used for lookups
+//   - tracker: Tracker for packages without advisory data; may be nil
+//
+// Returns:
+//   - *Report: Advisories found for the scanned packages
+func Scan(ctx context.Context, pkgs []formats.Package, provider Provider, tracker supervision.Tracker) *Report {
+	report := NewReport()
+
+	for _, p := range pkgs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		reason := scanPackage(ctx, p, provider, report)
+		if reason != "" && tracker != nil {
+			tracker.Add(p, reason)
+		}
+	}
+
+	verbose.Infof("Security scan: %d of %d packages affected by known advisories", report.Count(), len(pkgs))
+	return report
+}
+
+// scanPackage queries advisories for a single package and records them in the report.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - p: The package to check
+//   - provider: Advisory provider used for the lookup
+//   - report: Report to record advisories in
+//
+// Returns:
+//   - string: Reason no advisory data is available, or empty on success
+func scanPackage(ctx context.Context, p formats.Package, provider Provider, report *Report) string {
+	if p.InstallStatus == lock.InstallStatusIgnored {
+		return ""
+	}
+
+	ecosystem, ok := EcosystemFor(p)
+	if !ok {
+		return fmt.Sprintf("no advisory data source for %s packages; --only-security cannot check them", p.Rule)
+	}
+
+	version := outdated.CurrentVersionForOutdated(p)
+	if version == "" || version == constants.PlaceholderNA || utils.IsFloatingConstraint(version) {
+		return "installed version unknown; advisories cannot be checked"
+	}
+
+	advisories, err := provider.Query(ctx, ecosystem, p.Name, version)
+	if err != nil {
+		verbose.Infof("Advisory lookup failed for %s@%s: %v", p.Name, version, err)
+		return fmt.Sprintf("advisory lookup failed: %v", err)
+	}
+
+	if len(advisories) > 0 {
+		ids := make([]string, 0, len(advisories))
+		for _, a := range advisories {
+			ids = append(ids, a.ID)
+		}
+		verbose.Infof("%s@%s affected by %s", p.Name, version, strings.Join(ids, ", "))
+	}
+
+	report.Add(p, advisories)
+	return ""
+}
+
+// PreferFixedVersions wraps a version lister so vulnerable packages target the
+// lowest version that clears all of their advisories.
+//
+// For packages in the report, the returned versions are reduced to the single
+// lowest available version that is at or above every advisory's fix. If no
+// available version clears all advisories, or the advisories carry no fix
+// information, the original list is returned unchanged.
+//
+// Parameters:
+//   - lister: The version lister to wrap
+//   - report: Advisories found by Scan
+//
+// Returns:
+//   - outdated.ListNewerVersionsFunc: A lister that prefers fixing versions
+func PreferFixedVersions(lister outdated.ListNewerVersionsFunc, report *Report) outdated.ListNewerVersionsFunc {
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		versions, err := lister(ctx, p, cfg, baseDir)
+		if err != nil || !report.IsAffected(p) {
+			return versions, err
+		}
+
+		var versioning *config.VersioningCfg
+		if cfg != nil {
+			if rule, ok := cfg.Rules[p.Rule]; ok && rule.Outdated != nil {
+				versioning = rule.Outdated.Versioning
+			}
+		}
+
+		fixed, ok := LowestFixingVersion(outdated.CurrentVersionForOutdated(p), versions, report.Advisories(p), versioning)
+		if !ok {
+			verbose.Infof("No available version of %s clears all advisories; keeping regular target selection", p.Name)
+			return versions, nil
+		}
+
+		verbose.Infof("Selecting %s for %s: lowest version clearing all advisories", fixed, p.Name)
+		return []string{fixed}, nil
+	}
+}
+
+// LowestNonVulnerable returns a selector for the lowest-non-vulnerable selection strategy.
+//
+// A package affected by advisories targets the lowest candidate that clears
+// all of them (see LowestFixingVersion). When no candidate does, or the
+// advisories carry no fix information, the highest candidate is selected, as
+// the newest release is the most likely to contain fixes. Packages without
+// advisories take the lowest candidate, the smallest step that keeps them
+// clear of known vulnerabilities.
+//
+// Parameters:
+//   - report: Advisories found by Scan for the packages being updated
+//
+// Returns:
+//   - outdated.TargetSelector: Selector choosing the lowest non-vulnerable candidate
+func LowestNonVulnerable(report *Report) outdated.TargetSelector {
+	return outdated.TargetSelectorFunc(func(ctx context.Context, p formats.Package, candidates []string, cfg *config.Config, baseDir string) (string, error) {
+		versioning := outdated.VersioningFor(p, cfg)
+		if !report.IsAffected(p) {
+			return outdated.LowestCandidate(candidates, versioning), nil
+		}
+
+		fixed, ok := LowestFixingVersion(outdated.CurrentVersionForOutdated(p), candidates, report.Advisories(p), versioning)
+		if !ok {
+			verbose.Infof("No candidate of %s clears all advisories; selecting the highest version", p.Name)
+			return outdated.HighestCandidate(candidates, versioning), nil
+		}
+		return fixed, nil
+	})
+}
+
+// LowestFixingVersion returns the lowest available version that clears all advisories.
+//
+// For each advisory, the required version is the lowest fix above the current
+// version. The overall requirement is the highest of those, and the result is
+// the lowest available version at or above it.
+//
+// Parameters:
+//   - current: The currently installed version
+//   - available: Candidate versions
+//   - advisories: Advisories affecting the current version
+//   - versioning: Versioning configuration used for comparisons; may be nil
+//
+// Returns:
+//   - string: The lowest clearing version
+//   - bool: false if fix information is missing or no candidate clears all advisories
+func LowestFixingVersion(current string, available []string, advisories []Advisory, versioning *config.VersioningCfg) (string, bool) {
+	compare := func(a, b string) int {
+		cmp, err := outdated.CompareVersions(a, b, versioning)
+		if err != nil {
+			return strings.Compare(a, b)
+		}
+		return cmp
+	}
+
+	required := ""
+	for _, advisory := range advisories {
+		advisoryFix := ""
+		for _, fix := range advisory.FixedVersions {
+			if compare(fix, current) <= 0 {
+				continue
+			}
+			if advisoryFix == "" || compare(fix, advisoryFix) < 0 {
+				advisoryFix = fix
+			}
+		}
+		if advisoryFix == "" {
+			return "", false
+		}
+		if required == "" || compare(advisoryFix, required) > 0 {
+			required = advisoryFix
+		}
+	}
+
+	if required == "" {
+		return "", false
+	}
+
+	best := ""
+	for _, v := range available {
+		if compare(v, required) < 0 {
+			continue
+		}
+		if best == "" || compare(v, best) < 0 {
+			best = v
+		}
+	}
+
+	return best, best != ""
+}
@@ -0,0 +1,255 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/supervision"
+)
+
+// fixtureProvider serves advisories parsed from recorded OSV responses.
+type fixtureProvider struct {
+	responses map[string][]byte
+	err       error
+	queries   []string
+}
+
+// Query returns advisories parsed from the recorded response for the package.
+func (p *fixtureProvider) Query(ctx context.Context, ecosystem, name, version string) ([]Advisory, error) {
+	p.queries = append(p.queries, ecosystem+"/"+name+"@"+version)
+	if p.err != nil {
+		return nil, p.err
+	}
+	data, ok := p.responses[name]
+	if !ok {
+		return nil, nil
+	}
+	return parseOSVResponse(data, name)
+}
+
+// TestEcosystemFor tests mapping packages to advisory ecosystems.
+//
+// It verifies that:
+//   - Built-in rules map to their ecosystem
+//   - Custom rules fall back to the package manager
+//   - Unknown rules and managers are reported as unsupported
+func TestEcosystemFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		pkg       formats.Package
+		ecosystem string
+		ok        bool
+	}{
+		{"npm rule", formats.Package{Rule: "npm", PackageType: "js"}, "npm", true},
+		{"composer rule", formats.Package{Rule: "composer", PackageType: "php"}, "Packagist", true},
+		{"requirements rule", formats.Package{Rule: "requirements", PackageType: "python"}, "PyPI", true},
+		{"mod rule", formats.Package{Rule: "mod", PackageType: "golang"}, "Go", true},
+		{"nuget rule", formats.Package{Rule: "nuget", PackageType: "dotnet"}, "NuGet", true},
+		{"custom rule with js manager", formats.Package{Rule: "frontend", PackageType: "js"}, "npm", true},
+		{"unknown", formats.Package{Rule: "custom", PackageType: "custom"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ecosystem, ok := EcosystemFor(tt.pkg)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.ecosystem, ecosystem)
+		})
+	}
+}
+
+// TestReport tests recording and querying advisories in a Report.
+//
+// It verifies that:
+//   - Packages are affected only when advisories were added
+//   - Empty advisory lists are ignored
+//   - A nil report is safe to query
+func TestReport(t *testing.T) {
+	lodash := formats.Package{Rule: "npm", PackageType: "js", Name: "lodash", Version: "4.17.11", InstalledVersion: "4.17.11"}
+	react := formats.Package{Rule: "npm", PackageType: "js", Name: "react", Version: "17.0.0", InstalledVersion: "17.0.0"}
+
+	report := NewReport()
+	report.Add(lodash, []Advisory{{ID: "GHSA-jf85-cpcp-j695"}})
+	report.Add(react, nil)
+
+	assert.True(t, report.IsAffected(lodash))
+	assert.False(t, report.IsAffected(react))
+	assert.Len(t, report.Advisories(lodash), 1)
+	assert.Equal(t, 1, report.Count())
+
+	var nilReport *Report
+	assert.False(t, nilReport.IsAffected(lodash))
+	assert.Nil(t, nilReport.Advisories(lodash))
+	assert.Equal(t, 0, nilReport.Count())
+}
+
+// TestScan tests scanning packages for advisories.
+//
+// It verifies that:
+//   - Affected packages are recorded in the report
+//   - Ignored packages are not queried
+//   - Packages without an ecosystem or installed version are tracked as unsupported
+//   - Lookup failures are tracked as unsupported
+func TestScan(t *testing.T) {
+	pkgs := []formats.Package{
+		{Rule: "npm", PackageType: "js", Name: "lodash", Version: "4.17.11", InstalledVersion: "4.17.11"},
+		{Rule: "npm", PackageType: "js", Name: "react", Version: "17.0.0", InstalledVersion: "17.0.0"},
+		{Rule: "npm", PackageType: "js", Name: "skipped", Version: "1.0.0", InstalledVersion: "1.0.0", InstallStatus: lock.InstallStatusIgnored},
+		{Rule: "custom", PackageType: "custom", Name: "internal", Version: "1.0.0", InstalledVersion: "1.0.0"},
+		{Rule: "npm", PackageType: "js", Name: "unknown", Version: "*", InstalledVersion: "#N/A"},
+	}
+
+	t.Run("records affected packages", func(t *testing.T) {
+		provider := &fixtureProvider{responses: map[string][]byte{"lodash": readLodashFixture(t)}}
+		tracker := supervision.NewUnsupportedTracker()
+
+		report := Scan(context.Background(), pkgs, provider, tracker)
+
+		assert.True(t, report.IsAffected(pkgs[0]))
+		assert.False(t, report.IsAffected(pkgs[1]))
+		assert.Equal(t, 1, report.Count())
+		assert.Equal(t, []string{"npm/lodash@4.17.11", "npm/react@17.0.0"}, provider.queries)
+
+		messages := tracker.Messages()
+		require.Len(t, messages, 2)
+		assert.Contains(t, messages[0], "no advisory data source for custom packages")
+		assert.Contains(t, messages[1], "installed version unknown")
+	})
+
+	t.Run("lookup failure is tracked", func(t *testing.T) {
+		provider := &fixtureProvider{err: errors.New("connection refused")}
+		tracker := supervision.NewUnsupportedTracker()
+
+		report := Scan(context.Background(), pkgs[:1], provider, tracker)
+
+		assert.Equal(t, 0, report.Count())
+		messages := tracker.Messages()
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "advisory lookup failed: connection refused")
+	})
+
+	t.Run("nil tracker", func(t *testing.T) {
+		provider := &fixtureProvider{}
+		assert.NotPanics(t, func() {
+			Scan(context.Background(), pkgs, provider, nil)
+		})
+	})
+}
+
+// TestLowestFixingVersion tests selection of the lowest version clearing all advisories.
+//
+// It verifies that:
+//   - The highest per-advisory fix determines the requirement
+//   - The lowest available version at or above the requirement is chosen
+//   - Missing fix information or no clearing candidate returns false
+func TestLowestFixingVersion(t *testing.T) {
+	advisories, err := parseOSVResponse(readLodashFixture(t), "lodash")
+	require.NoError(t, err)
+
+	t.Run("lodash fixture", func(t *testing.T) {
+		v, ok := LowestFixingVersion("4.17.11", []string{"4.17.21", "4.17.12", "4.17.19", "4.17.20"}, advisories, nil)
+		require.True(t, ok)
+		assert.Equal(t, "4.17.19", v)
+	})
+
+	t.Run("backported fixes pick the line above current", func(t *testing.T) {
+		backported := []Advisory{{ID: "A", FixedVersions: []string{"1.2.5", "2.0.3"}}}
+		v, ok := LowestFixingVersion("2.0.1", []string{"2.0.2", "2.0.3", "2.1.0"}, backported, nil)
+		require.True(t, ok)
+		assert.Equal(t, "2.0.3", v)
+	})
+
+	t.Run("no candidate clears advisories", func(t *testing.T) {
+		_, ok := LowestFixingVersion("4.17.11", []string{"4.17.12", "4.17.15"}, advisories, nil)
+		assert.False(t, ok)
+	})
+
+	t.Run("advisory without fix", func(t *testing.T) {
+		_, ok := LowestFixingVersion("1.0.0", []string{"1.0.1"}, []Advisory{{ID: "A"}}, nil)
+		assert.False(t, ok)
+	})
+}
+
+// TestPreferFixedVersions tests the fixing-version lister wrapper.
+//
+// It verifies that:
+//   - Affected packages receive only the lowest clearing version
+//   - Unaffected packages receive the original versions
+//   - Affected packages fall back to the original versions when nothing clears
+//   - Lister errors are passed through
+func TestPreferFixedVersions(t *testing.T) {
+	advisories, err := parseOSVResponse(readLodashFixture(t), "lodash")
+	require.NoError(t, err)
+
+	lodash := formats.Package{Rule: "npm", PackageType: "js", Name: "lodash", Version: "4.17.11", InstalledVersion: "4.17.11"}
+	react := formats.Package{Rule: "npm", PackageType: "js", Name: "react", Version: "17.0.0", InstalledVersion: "17.0.0"}
+	report := NewReport()
+	report.Add(lodash, advisories)
+
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{"npm": {Outdated: &config.OutdatedCfg{}}}}
+
+	available := []string{"4.17.12", "4.17.19", "4.17.21"}
+	lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return available, nil
+	}
+	wrapped := PreferFixedVersions(lister, report)
+
+	versions, err := wrapped(context.Background(), lodash, cfg, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4.17.19"}, versions)
+
+	versions, err = wrapped(context.Background(), react, cfg, ".")
+	require.NoError(t, err)
+	assert.Equal(t, available, versions)
+
+	available = []string{"4.17.12"}
+	versions, err = wrapped(context.Background(), lodash, cfg, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4.17.12"}, versions)
+
+	failing := PreferFixedVersions(func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return nil, errors.New("registry down")
+	}, report)
+	_, err = failing(context.Background(), lodash, cfg, ".")
+	assert.EqualError(t, err, "registry down")
+}
+
+// TestLowestNonVulnerable tests the lowest-non-vulnerable selection strategy.
+//
+// It verifies that:
+//   - Affected packages target the lowest candidate clearing all advisories
+//   - Affected packages without a clearing candidate target the highest candidate
+//   - Unaffected packages, and every package without a report, target the lowest candidate
+func TestLowestNonVulnerable(t *testing.T) {
+	advisories, err := parseOSVResponse(readLodashFixture(t), "lodash")
+	require.NoError(t, err)
+
+	lodash := formats.Package{Rule: "npm", PackageType: "js", Name: "lodash", Version: "4.17.11", InstalledVersion: "4.17.11"}
+	react := formats.Package{Rule: "npm", PackageType: "js", Name: "react", Version: "17.0.0", InstalledVersion: "17.0.0"}
+	report := NewReport()
+	report.Add(lodash, advisories)
+	selector := LowestNonVulnerable(report)
+
+	target, err := selector.SelectTarget(context.Background(), lodash, []string{"4.17.21", "4.17.12", "4.17.19"}, nil, ".")
+	require.NoError(t, err)
+	assert.Equal(t, "4.17.19", target)
+
+	target, err = selector.SelectTarget(context.Background(), lodash, []string{"4.17.12", "4.17.15"}, nil, ".")
+	require.NoError(t, err)
+	assert.Equal(t, "4.17.15", target)
+
+	target, err = selector.SelectTarget(context.Background(), react, []string{"18.2.0", "17.0.2", "17.0.1"}, nil, ".")
+	require.NoError(t, err)
+	assert.Equal(t, "17.0.1", target)
+
+	target, err = LowestNonVulnerable(nil).SelectTarget(context.Background(), lodash, []string{"4.17.21", "4.17.12"}, nil, ".")
+	require.NoError(t, err)
+	assert.Equal(t, "4.17.12", target)
+}
@@ -0,0 +1,31 @@
+// Package security provides advisory lookups for installed package versions.
+//
+// It is used by `goupdate update --only-security` to restrict an update run
+// to packages whose installed version is affected by at least one known
+// vulnerability, and to steer target selection toward the lowest version
+// that clears every advisory.
+//
+// # Core Types
+//
+// Provider looks up advisories for a single package version. OSVClient is the
+// default implementation backed by the OSV.dev query API:
+//
+//	provider := security.NewOSVClient()
+//	advisories, err := provider.Query(ctx, "npm", "lodash", "4.17.11")
+//
+// Report collects the advisories found for a set of packages and implements
+// filtering.AdvisoryMatcher so it can be passed to filtering.FilterOptions:
+//
+//	report := security.Scan(ctx, packages, provider, tracker)
+//	vulnerable := filtering.FilterPackages(packages, filtering.FilterOptions{
+//	    SecurityOnly: true,
+//	    Advisories:   report,
+//	})
+//
+// # Ecosystems
+//
+// Packages are mapped to OSV ecosystems by rule name first and package
+// manager second (see EcosystemFor). Packages without a known ecosystem, or
+// whose lookup fails, are reported to the unsupported tracker instead of
+// being silently dropped.
+package security
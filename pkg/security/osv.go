@@ -0,0 +1,175 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultOSVEndpoint is the OSV.dev query API endpoint.
+const DefaultOSVEndpoint = "https://api.osv.dev/v1/query"
+
+// defaultOSVTimeout bounds a single OSV query.
+const defaultOSVTimeout = 30 * time.Second
+
+// maxOSVResponseSize caps the response body read from the OSV API (10MB).
+const maxOSVResponseSize = 10 * 1024 * 1024
+
+// OSVClient queries the OSV.dev vulnerability database.
+//
+// OSVClient implements Provider.
+type OSVClient struct {
+	// Endpoint is the query API URL. Defaults to DefaultOSVEndpoint.
+	Endpoint string
+
+	// HTTPClient is the client used for requests.
+	HTTPClient *http.Client
+}
+
+// NewOSVClient creates an OSV client using the public OSV.dev endpoint.
+//
+// Returns:
+//   - *OSVClient: Client with default endpoint and timeout
+func NewOSVClient() *OSVClient {
+	return &OSVClient{
+		Endpoint:   DefaultOSVEndpoint,
+		HTTPClient: &http.Client{Timeout: defaultOSVTimeout},
+	}
+}
+
+// osvQuery is the request body for the OSV query API.
+type osvQuery struct {
+	Version string     `json:"version"`
+	Package osvPackage `json:"package"`
+}
+
+// osvPackage identifies a package in OSV requests and responses.
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+// osvResponse is the response body of the OSV query API.
+type osvResponse struct {
+	Vulns []osvVuln `json:"vulns"`
+}
+
+// osvVuln is a single vulnerability entry in an OSV response.
+type osvVuln struct {
+	ID       string        `json:"id"`
+	Summary  string        `json:"summary"`
+	Aliases  []string      `json:"aliases"`
+	Affected []osvAffected `json:"affected"`
+}
+
+// osvAffected describes the affected ranges for one package.
+type osvAffected struct {
+	Package osvPackage `json:"package"`
+	Ranges  []osvRange `json:"ranges"`
+}
+
+// osvRange is a version range made of introduced/fixed events.
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+// osvEvent is a single range event.
+type osvEvent struct {
+	Introduced string `json:"introduced,omitempty"`
+	Fixed      string `json:"fixed,omitempty"`
+}
+
+// Query returns advisories affecting the given package version.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - ecosystem: OSV ecosystem name (e.g., "npm", "PyPI")
+//   - name: Package name
+//   - version: Installed version to check
+//
+// Returns:
+//   - []Advisory: Advisories affecting the version
+//   - error: When the request fails or the response cannot be parsed
+func (c *OSVClient) Query(ctx context.Context, ecosystem, name, version string) ([]Advisory, error) {
+	body, err := json.Marshal(osvQuery{Version: version, Package: osvPackage{Name: name, Ecosystem: ecosystem}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OSV query: %w", err)
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultOSVEndpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OSV request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultOSVTimeout}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OSV request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOSVResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OSV response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV query returned %s", resp.Status)
+	}
+
+	return parseOSVResponse(data, name)
+}
+
+// parseOSVResponse converts an OSV query response into advisories.
+//
+// Fixed versions are collected only from affected entries matching the
+// queried package name, since a vulnerability may span several packages.
+//
+// Parameters:
+//   - data: Raw JSON response body
+//   - name: Package name the query was made for
+//
+// Returns:
+//   - []Advisory: Parsed advisories
+//   - error: When the response is not valid JSON
+func parseOSVResponse(data []byte, name string) ([]Advisory, error) {
+	var resp osvResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse OSV response: %w", err)
+	}
+
+	advisories := make([]Advisory, 0, len(resp.Vulns))
+	for _, vuln := range resp.Vulns {
+		advisory := Advisory{ID: vuln.ID, Summary: vuln.Summary, Aliases: vuln.Aliases}
+		for _, affected := range vuln.Affected {
+			if affected.Package.Name != "" && affected.Package.Name != name {
+				continue
+			}
+			for _, r := range affected.Ranges {
+				for _, event := range r.Events {
+					if event.Fixed != "" {
+						advisory.FixedVersions = append(advisory.FixedVersions, event.Fixed)
+					}
+				}
+			}
+		}
+		advisories = append(advisories, advisory)
+	}
+
+	return advisories, nil
+}
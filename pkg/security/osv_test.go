@@ -0,0 +1,110 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readLodashFixture loads the recorded OSV response for lodash 4.17.11.
+func readLodashFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "osv_lodash_4.17.11.json"))
+	require.NoError(t, err)
+	return data
+}
+
+// TestParseOSVResponse tests parsing of OSV query responses.
+//
+// It verifies that:
+//   - Each vulnerability becomes an advisory with ID, summary, and aliases
+//   - Fixed versions are collected only for the queried package
+//   - Invalid JSON returns an error
+func TestParseOSVResponse(t *testing.T) {
+	t.Run("lodash fixture", func(t *testing.T) {
+		advisories, err := parseOSVResponse(readLodashFixture(t), "lodash")
+		require.NoError(t, err)
+		require.Len(t, advisories, 2)
+
+		assert.Equal(t, "GHSA-jf85-cpcp-j695", advisories[0].ID)
+		assert.Equal(t, "Prototype Pollution in lodash", advisories[0].Summary)
+		assert.Equal(t, []string{"CVE-2019-10744"}, advisories[0].Aliases)
+		assert.Equal(t, []string{"4.17.12"}, advisories[0].FixedVersions)
+
+		assert.Equal(t, "GHSA-p6mc-m468-83gw", advisories[1].ID)
+		assert.Equal(t, []string{"4.17.19"}, advisories[1].FixedVersions, "lodash-es fix must not apply to lodash")
+	})
+
+	t.Run("empty response", func(t *testing.T) {
+		advisories, err := parseOSVResponse([]byte(`{}`), "lodash")
+		require.NoError(t, err)
+		assert.Empty(t, advisories)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := parseOSVResponse([]byte(`{`), "lodash")
+		assert.Error(t, err)
+	})
+}
+
+// TestOSVClientQuery tests OSVClient.Query against a local HTTP server.
+//
+// It verifies that:
+//   - The request body carries the package name, ecosystem, and version
+//   - A 200 response is parsed into advisories
+//   - A non-200 response returns an error
+func TestOSVClientQuery(t *testing.T) {
+	fixture := readLodashFixture(t)
+
+	t.Run("success", func(t *testing.T) {
+		var got osvQuery
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &got)
+			_, _ = w.Write(fixture)
+		}))
+		t.Cleanup(server.Close)
+
+		client := &OSVClient{Endpoint: server.URL, HTTPClient: server.Client()}
+		advisories, err := client.Query(context.Background(), "npm", "lodash", "4.17.11")
+		require.NoError(t, err)
+		assert.Len(t, advisories, 2)
+		assert.Equal(t, "lodash", got.Package.Name)
+		assert.Equal(t, "npm", got.Package.Ecosystem)
+		assert.Equal(t, "4.17.11", got.Version)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(server.Close)
+
+		client := &OSVClient{Endpoint: server.URL, HTTPClient: server.Client()}
+		_, err := client.Query(context.Background(), "npm", "lodash", "4.17.11")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+	})
+}
+
+// TestNewOSVClient tests the default OSV client configuration.
+//
+// It verifies that:
+//   - The public endpoint is used
+//   - An HTTP client with a timeout is configured
+func TestNewOSVClient(t *testing.T) {
+	client := NewOSVClient()
+	assert.Equal(t, DefaultOSVEndpoint, client.Endpoint)
+	require.NotNil(t, client.HTTPClient)
+	assert.Equal(t, defaultOSVTimeout, client.HTTPClient.Timeout)
+}
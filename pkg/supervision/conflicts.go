@@ -0,0 +1,162 @@
+package supervision
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// Declaration is one place a package is declared.
+//
+// Fields:
+//   - Version: Declared version in the manifest
+//   - Source: Manifest path, relative to the base directory when possible
+type Declaration struct {
+	Version string
+	Source  string
+}
+
+// VersionConflict is a package declared at different versions across manifests.
+//
+// Fields:
+//   - Rule: Configuration rule the declarations belong to
+//   - PackageType: Package manager type (e.g. "js")
+//   - Name: Package name
+//   - Declarations: Every declaration, sorted by version then source
+type VersionConflict struct {
+	Rule         string
+	PackageType  string
+	Name         string
+	Declarations []Declaration
+}
+
+// Versions returns the distinct declared versions of the conflict, sorted.
+//
+// Returns:
+//   - []string: Declared versions without duplicates
+func (c VersionConflict) Versions() []string {
+	var versions []string
+	seen := make(map[string]bool)
+	for _, d := range c.Declarations {
+		if !seen[d.Version] {
+			seen[d.Version] = true
+			versions = append(versions, d.Version)
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Warning converts the conflict into a classified warning.
+//
+// Returns:
+//   - warnings.Warning: A warnings.CodeVersionConflict warning listing each version and file
+func (c VersionConflict) Warning() warnings.Warning {
+	locations := make([]string, 0, len(c.Declarations))
+	for _, d := range c.Declarations {
+		locations = append(locations, fmt.Sprintf("%s in %s", d.Version, d.Source))
+	}
+	return warnings.Warning{
+		Code:       warnings.CodeVersionConflict,
+		Message:    fmt.Sprintf("⚠️ %s is declared at %d different versions: %s", c.Name, len(c.Versions()), strings.Join(locations, ", ")),
+		PackageRef: warnings.PackageRef(c.Name, c.PackageType, c.Rule),
+	}
+}
+
+// FindVersionConflicts groups packages by rule and name and returns the names
+// declared at more than one version.
+//
+// Packages without a declared version are ignored, so a name that is only
+// pinned in one manifest and left open in another is not a conflict.
+//
+// Parameters:
+//   - pkgs: Packages as returned by package detection
+//   - baseDir: Directory manifest paths are shown relative to; empty keeps them as-is
+//
+// Returns:
+//   - []VersionConflict: Conflicts sorted by rule then name; nil when every name is declared at one version
+//
+// Example:
+//
+//	for _, c := range supervision.FindVersionConflicts(pkgs, workDir) {
+//	    fmt.Println(c.Name, c.Versions()) // react [17.0.2 18.2.0]
+//	}
+func FindVersionConflicts(pkgs []formats.Package, baseDir string) []VersionConflict {
+	byKey := make(map[string]*VersionConflict)
+	var keys []string
+	for _, p := range pkgs {
+		version := strings.TrimSpace(p.Version)
+		if version == "" {
+			continue
+		}
+
+		key := p.Rule + "\x00" + p.Name
+		conflict, ok := byKey[key]
+		if !ok {
+			conflict = &VersionConflict{Rule: p.Rule, PackageType: p.PackageType, Name: p.Name}
+			byKey[key] = conflict
+			keys = append(keys, key)
+		}
+		conflict.Declarations = append(conflict.Declarations, Declaration{Version: version, Source: relativeSource(p.Source, baseDir)})
+	}
+
+	sort.Strings(keys)
+	var conflicts []VersionConflict
+	for _, key := range keys {
+		conflict := byKey[key]
+		if len(conflict.Versions()) < 2 {
+			continue
+		}
+		sort.SliceStable(conflict.Declarations, func(i, j int) bool {
+			a, b := conflict.Declarations[i], conflict.Declarations[j]
+			if a.Version != b.Version {
+				return a.Version < b.Version
+			}
+			return a.Source < b.Source
+		})
+		conflicts = append(conflicts, *conflict)
+	}
+	return conflicts
+}
+
+// WarnVersionConflicts writes one warning per conflicting package to the
+// configured warning writer.
+//
+// Parameters:
+//   - pkgs: Packages as returned by package detection
+//   - baseDir: Directory manifest paths are shown relative to
+//
+// Returns:
+//   - int: Number of conflicts found
+func WarnVersionConflicts(pkgs []formats.Package, baseDir string) int {
+	conflicts := FindVersionConflicts(pkgs, baseDir)
+	for _, c := range conflicts {
+		w := c.Warning()
+		warnings.Warn(w.Code, w.PackageRef, "%s", w.Message)
+	}
+	return len(conflicts)
+}
+
+// relativeSource returns source relative to baseDir when it lies inside it.
+func relativeSource(source, baseDir string) string {
+	if source == "" || baseDir == "" {
+		return source
+	}
+	absSource, err := filepath.Abs(source)
+	if err != nil {
+		return source
+	}
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return source
+	}
+	rel, err := filepath.Rel(absBase, absSource)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return source
+	}
+	return filepath.ToSlash(rel)
+}
@@ -23,6 +23,13 @@
 //	    Count:       5,
 //	}
 //
+// GroupedMessages aggregates tracked packages by rule and exact reason, for
+// compact per-rule summaries:
+//
+//	for _, info := range tracker.GroupedMessages() {
+//	    fmt.Printf("%s: %d packages - %s\n", info.Rule, info.Count, info.Reason)
+//	}
+//
 // # Helper Functions
 //
 // ShouldTrackUnsupported determines if a package status indicates tracking:
@@ -38,6 +45,14 @@
 //	// Returns: "No concrete version found in manifest or lock file."
 //	// Or: "Floating constraint '>=5.0.0' - update manually or remove constraint."
 //
+// FindVersionConflicts flags names declared at different versions across the
+// manifests of a rule, as in sprawling monorepos; WarnVersionConflicts writes
+// them as VERSION_CONFLICT warnings:
+//
+//	for _, c := range supervision.FindVersionConflicts(pkgs, workDir) {
+//	    fmt.Println(c.Warning().Message)
+//	}
+//
 // # Thread Safety
 //
 // UnsupportedTracker is safe for concurrent use from multiple goroutines.
@@ -1,12 +1,18 @@
 package supervision
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/warnings"
 )
 
 // TestNewUnsupportedTracker tests the behavior of NewUnsupportedTracker.
@@ -23,13 +29,15 @@ func TestNewUnsupportedTracker(t *testing.T) {
 // TestShouldTrackUnsupported tests the behavior of ShouldTrackUnsupported.
 //
 // It verifies:
-//   - NotConfigured, Floating, and VersionMissing statuses return true
+//   - NotConfigured, Floating, NonRegistry, and VersionMissing statuses return true
 //   - Other statuses return false
 //   - Empty status returns false
 func TestShouldTrackUnsupported(t *testing.T) {
 	assert.True(t, ShouldTrackUnsupported(lock.InstallStatusNotConfigured))
 	assert.True(t, ShouldTrackUnsupported(lock.InstallStatusFloating))
+	assert.True(t, ShouldTrackUnsupported(lock.InstallStatusNonRegistry))
 	assert.True(t, ShouldTrackUnsupported(lock.InstallStatusVersionMissing))
+	assert.True(t, ShouldTrackUnsupported(lock.InstallStatusLockUnavailable))
 	assert.False(t, ShouldTrackUnsupported("ok"))
 	assert.False(t, ShouldTrackUnsupported(""))
 }
@@ -40,6 +48,7 @@ func TestShouldTrackUnsupported(t *testing.T) {
 //   - Empty and whitespace-only reasons are ignored
 //   - Packages with reasons are tracked correctly
 //   - Count is incremented for same rule/package-type combination
+//   - Packages with a different install status in the same rule are tracked separately
 func TestUnsupportedTrackerAdd(t *testing.T) {
 	tracker := NewUnsupportedTracker()
 
@@ -70,6 +79,16 @@ func TestUnsupportedTrackerAdd(t *testing.T) {
 		assert.Len(t, messages, 1)
 		assert.Contains(t, messages[0], "2 packages")
 	})
+
+	t.Run("separates install statuses within a rule", func(t *testing.T) {
+		nonRegistry := pkg
+		nonRegistry.InstallStatus = lock.InstallStatusNonRegistry
+		tracker.Add(nonRegistry, "non-registry reason")
+		messages := tracker.Messages()
+		assert.Len(t, messages, 2)
+		assert.Contains(t, messages[0], "non-registry reason (1 packages)")
+		assert.Contains(t, messages[1], "some reason (2 packages)")
+	})
 }
 
 // TestUnsupportedTrackerMessages tests the behavior of message generation.
@@ -92,11 +111,57 @@ func TestUnsupportedTrackerMessages(t *testing.T) {
 	assert.Contains(t, messages[1], "rule2")
 }
 
+// TestUnsupportedTrackerGroupedMessages tests aggregation by rule and reason.
+//
+// It verifies:
+//   - An empty tracker returns nil
+//   - Identical reasons within a rule are counted together
+//   - Different reasons within a rule get separate entries, even with the same status
+//   - The same reason in different rules is kept per rule
+//   - Entries are sorted by rule, package type, and reason
+//   - Messages keeps its status-based grouping
+func TestUnsupportedTrackerGroupedMessages(t *testing.T) {
+	tracker := NewUnsupportedTracker()
+	assert.Nil(t, tracker.GroupedMessages())
+
+	floating := "Floating constraint - update manually or remove constraint."
+	missing := "No concrete version found in manifest or lock file."
+
+	npmFloating := formats.Package{Rule: "npm", PackageType: "js", InstallStatus: lock.InstallStatusFloating}
+	npmMissing := formats.Package{Rule: "npm", PackageType: "js", InstallStatus: lock.InstallStatusVersionMissing}
+	modFloating := formats.Package{Rule: "mod", PackageType: "golang", InstallStatus: lock.InstallStatusFloating}
+
+	for i := 0; i < 3; i++ {
+		tracker.Add(npmFloating, floating)
+	}
+	tracker.Add(npmFloating, "Floating constraint '>=2' - update manually or remove constraint.")
+	tracker.Add(npmMissing, missing)
+	tracker.Add(npmMissing, missing)
+	tracker.Add(modFloating, floating)
+
+	assert.Equal(t, []UnsupportedRuleInfo{
+		{Rule: "mod", PackageType: "golang", Reason: floating, Count: 1},
+		{Rule: "npm", PackageType: "js", Reason: "Floating constraint '>=2' - update manually or remove constraint.", Count: 1},
+		{Rule: "npm", PackageType: "js", Reason: floating, Count: 3},
+		{Rule: "npm", PackageType: "js", Reason: missing, Count: 2},
+	}, tracker.GroupedMessages())
+
+	messages := tracker.Messages()
+	assert.Len(t, messages, 3)
+	assert.Equal(t, 7, tracker.TotalPackages())
+}
+
 // TestDeriveUnsupportedReason tests the behavior of reason derivation.
 //
 // It verifies:
 //   - VersionMissing status produces appropriate reason
 //   - Floating constraint produces appropriate reason
+//   - NonRegistry status produces a non-registry reason and takes precedence over floating versions
+//   - Built-in rules report their unsupported_reasons: Brewfile unpinned and cask/tap/mas
+//     entries, SDK sources, Deno URL imports, Swift branch/revision/range pins, and
+//     GitHub Actions bare SHA pins and missing API data
+//   - Rules without unsupported_reasons keep the generic reasons
+//   - Packages --since could not filter report the missing release dates
 //   - NotConfigured status returns empty reason
 //   - Latest missing flag returns empty reason
 func TestDeriveUnsupportedReason(t *testing.T) {
@@ -119,6 +184,141 @@ func TestDeriveUnsupportedReason(t *testing.T) {
 		assert.Contains(t, reason, "Floating constraint")
 	})
 
+	t.Run("non-registry source", func(t *testing.T) {
+		pkg := formats.Package{
+			Name:              "internal_auth",
+			Version:           "*",
+			InstallStatus:     lock.InstallStatusNonRegistry,
+			NonRegistrySource: "git",
+		}
+		reason := DeriveUnsupportedReason(pkg, nil, nil, false)
+		assert.Contains(t, reason, "Non-registry source")
+	})
+
+	defaults, err := config.LoadConfig("", t.TempDir())
+	require.NoError(t, err)
+
+	t.Run("unpinned brew formula", func(t *testing.T) {
+		pkg := formats.Package{
+			Name:          "git",
+			Rule:          "brew",
+			PackageType:   "brew",
+			Version:       "*",
+			InstallStatus: lock.InstallStatusFloating,
+		}
+		reason := DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Equal(t, "Brewfile entries are unpinned; goupdate reports drift only.", reason)
+	})
+
+	t.Run("brew cask entry", func(t *testing.T) {
+		pkg := formats.Package{
+			Name:              "docker",
+			Rule:              "brew",
+			PackageType:       "brew",
+			Version:           "*",
+			InstallStatus:     lock.InstallStatusNonRegistry,
+			NonRegistrySource: "cask",
+		}
+		reason := DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Contains(t, reason, "cask, tap, and mas entries are not supported")
+	})
+
+	t.Run("sdk package", func(t *testing.T) {
+		pkg := formats.Package{
+			Name:              "flutter",
+			Rule:              "pub",
+			PackageType:       "dart",
+			Version:           "*",
+			InstallStatus:     lock.InstallStatusNonRegistry,
+			NonRegistrySource: "sdk",
+		}
+		reason := DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Contains(t, reason, "ship with the Dart/Flutter SDK")
+
+		pkg.NonRegistrySource = "git"
+		reason = DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Contains(t, reason, "Non-registry source")
+	})
+
+	t.Run("deno url import without version", func(t *testing.T) {
+		pkg := formats.Package{
+			Name:              "https://cdn.skypack.dev/lodash-es",
+			Rule:              "deno",
+			PackageType:       "deno",
+			Version:           "*",
+			InstallStatus:     lock.InstallStatusNonRegistry,
+			NonRegistrySource: "https",
+		}
+		reason := DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Contains(t, reason, "Deno URL import without a version")
+	})
+
+	t.Run("swift package pinned to a branch", func(t *testing.T) {
+		pkg := formats.Package{
+			Name:              "https://github.com/apple/swift-collections.git",
+			Rule:              "swiftpm",
+			PackageType:       "swift",
+			Version:           "*",
+			InstallStatus:     lock.InstallStatusNonRegistry,
+			NonRegistrySource: "branch",
+		}
+		reason := DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Contains(t, reason, "pinned to a branch or revision")
+
+		pkg.NonRegistrySource = "revision"
+		reason = DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Contains(t, reason, "pinned to a branch or revision")
+
+		pkg.NonRegistrySource = "..<"
+		reason = DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Contains(t, reason, "version range")
+	})
+
+	t.Run("github action pinned to a bare sha", func(t *testing.T) {
+		pkg := formats.Package{
+			Name:          "actions/upload-artifact",
+			Rule:          "github-actions",
+			PackageType:   "github-actions",
+			Version:       "*",
+			InstallStatus: lock.InstallStatusFloating,
+		}
+		reason := DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Contains(t, reason, "without a version comment")
+	})
+
+	t.Run("github action without api data", func(t *testing.T) {
+		pkg := formats.Package{Name: "actions/checkout", Rule: "github-actions", PackageType: "github-actions", Version: "v4"}
+		reason := DeriveUnsupportedReason(pkg, defaults, assert.AnError, false)
+		assert.Contains(t, reason, "GITHUB_TOKEN")
+	})
+
+	t.Run("rule without unsupported reasons", func(t *testing.T) {
+		pkg := formats.Package{Name: "lodash", Rule: "npm", Version: "*", InstallStatus: lock.InstallStatusFloating}
+		reason := DeriveUnsupportedReason(pkg, defaults, nil, false)
+		assert.Contains(t, reason, "Floating constraint '*'")
+	})
+
+	t.Run("since without release dates", func(t *testing.T) {
+		pkg := formats.Package{Name: "demo", Rule: "mod", Version: "v1.0.0"}
+		err := errors.NewUnsupportedError(outdated.SinceOperation, "no outdated.release_dates lookup is configured for this rule", pkg.Name)
+		reason := DeriveUnsupportedReason(pkg, nil, err, false)
+		assert.Equal(t, "Release dates unavailable - no outdated.release_dates lookup is configured for this rule; --since cannot filter its versions.", reason)
+	})
+
+	t.Run("quarantine without release dates", func(t *testing.T) {
+		pkg := formats.Package{Name: "demo", Rule: "mod", Version: "v1.0.0"}
+		err := errors.NewUnsupportedError(outdated.QuarantineOperation, "the registry returned no release dates", pkg.Name)
+		reason := DeriveUnsupportedReason(pkg, nil, err, false)
+		assert.Equal(t, "Release dates unavailable - the registry returned no release dates; the quarantine cannot check its versions.", reason)
+	})
+
+	t.Run("missing from offline index", func(t *testing.T) {
+		pkg := formats.Package{Name: "react", Rule: "npm", Version: "18.0.0"}
+		err := errors.NewUnsupportedError(outdated.OfflineOperation, "no offline version data", pkg.Name)
+		reason := DeriveUnsupportedReason(pkg, nil, err, false)
+		assert.Contains(t, reason, "No offline version data")
+	})
+
 	t.Run("not configured status returns empty", func(t *testing.T) {
 		pkg := formats.Package{
 			Name:          "test",
@@ -136,6 +336,50 @@ func TestDeriveUnsupportedReason(t *testing.T) {
 	})
 }
 
+// TestDeriveUnsupportedReasonVersionKinds tests reasons derived from the declared version.
+//
+// It verifies:
+//   - Floating constraints produce a floating reason
+//   - NuGet floating wildcards mention the --pin-floating opt-in
+//   - Pre-release versions produce a pre-release reason with the --allow-prerelease hint
+//   - Pre-release versions produce no pre-release reason when pre-releases are allowed
+//     by the flag or the rule, or when the package is not configured
+//   - Build metadata versions produce a build metadata reason
+//   - Floating takes precedence over pre-release detection
+//   - Stable versions produce no reason
+func TestDeriveUnsupportedReasonVersionKinds(t *testing.T) {
+	tests := []struct {
+		name        string
+		packageType string
+		version     string
+		status      string
+		cfg         *config.Config
+		expected    string
+	}{
+		{"nuget wildcard", "dotnet", "13.0.*", "", nil, "Floating constraint '13.0.*' - use --pin-floating to pin the highest matching release, or update manually."},
+		{"wildcard", "", "5.*", "", nil, "Floating constraint '5.*' - update manually or remove constraint."},
+		{"nuget range", "", "[8.0.0,9.0.0)", "", nil, "Floating constraint '[8.0.0,9.0.0)' - update manually or remove constraint."},
+		{"floating pre-release range", "", ">=1.0.0-beta <2.0.0", "", nil, "Floating constraint '>=1.0.0-beta <2.0.0' - update manually or remove constraint."},
+		{"beta pre-release", "", "1.0.0-beta.3", "", nil, "Pre-release constraint '1.0.0-beta.3' - pre-releases are not auto-updated; use --allow-prerelease."},
+		{"release candidate with v prefix", "", "v2.0.0-rc.1", "", nil, "Pre-release constraint 'v2.0.0-rc.1' - pre-releases are not auto-updated; use --allow-prerelease."},
+		{"pre-release with build metadata", "", "1.0.0-alpha+build.5", "", nil, "Pre-release constraint '1.0.0-alpha+build.5' - pre-releases are not auto-updated; use --allow-prerelease."},
+		{"build metadata", "", "1.2.3+build.5", "", nil, "Build metadata version '1.2.3+build.5' - build metadata is ignored when comparing versions; update manually."},
+		{"stable version", "", "1.2.3", "", nil, ""},
+		{"pre-release allowed by flag", "", "1.0.0-beta.3", "", &config.Config{AllowPrerelease: true}, ""},
+		{"pre-release allowed by rule", "", "1.0.0-beta.3", "", &config.Config{Rules: map[string]config.PackageManagerCfg{
+			"rule1": {Update: &config.UpdateCfg{AllowPrerelease: true}},
+		}}, ""},
+		{"pre-release not configured", "", "1.0.0-beta.3", lock.InstallStatusNotConfigured, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := formats.Package{Name: "test", Rule: "rule1", PackageType: tt.packageType, Version: tt.version, InstallStatus: tt.status}
+			assert.Equal(t, tt.expected, DeriveUnsupportedReason(pkg, tt.cfg, nil, false))
+		})
+	}
+}
+
 // TestUnsupportedTrackerCount tests the behavior of tracker count.
 //
 // It verifies:
@@ -177,3 +421,169 @@ func TestUnsupportedTrackerTotalPackages(t *testing.T) {
 	tracker.Add(formats.Package{Rule: "rule2", PackageType: "go"}, "reason2")
 	assert.Equal(t, 3, tracker.TotalPackages())
 }
+
+// TestStatusWarnings tests classifying packages by install status.
+//
+// It verifies:
+//   - Floating, lock-missing, version-missing, and non-registry packages get their stable codes
+//   - Each warning references its package as "name (type/rule)"
+//   - Packages with other statuses produce no warning
+func TestStatusWarnings(t *testing.T) {
+	pkgs := []formats.Package{
+		{Name: "serilog", PackageType: "dotnet", Rule: "nuget", Version: "5.*", InstallStatus: lock.InstallStatusFloating},
+		{Name: "react", PackageType: "js", Rule: "npm", Version: "^18.0.0", InstallStatus: lock.InstallStatusLockFound},
+		{Name: "lodash", PackageType: "js", Rule: "npm", Version: "^4.0.0", InstallStatus: lock.InstallStatusLockMissing},
+		{Name: "rails", PackageType: "ruby", Rule: "bundler", InstallStatus: lock.InstallStatusVersionMissing},
+		{Name: "local", PackageType: "ruby", Rule: "bundler", InstallStatus: lock.InstallStatusNonRegistry},
+	}
+
+	list := StatusWarnings(pkgs)
+
+	codes := make([]warnings.Code, len(list))
+	for i, w := range list {
+		codes[i] = w.Code
+	}
+	assert.Equal(t, []warnings.Code{
+		warnings.CodeFloatingConstraint,
+		warnings.CodeLockMissing,
+		warnings.CodeVersionMissing,
+		warnings.CodeNonRegistry,
+	}, codes)
+	assert.Equal(t, "serilog (dotnet/nuget)", list[0].PackageRef)
+	assert.Contains(t, list[0].Message, "'5.*'")
+	assert.Contains(t, list[1].Message, "'npm'")
+
+	assert.Nil(t, StatusWarnings(pkgs[1:2]))
+}
+
+// TestFindVersionConflicts tests the cross-manifest version conflict analysis.
+//
+// It verifies:
+//   - Names declared at different versions within a rule are reported with every declaration
+//   - The same name in different rules, or at one version everywhere, is not a conflict
+//   - Packages without a declared version are ignored
+//   - Sources are shown relative to the base directory
+//   - WarnVersionConflicts emits one VERSION_CONFLICT warning per conflict
+func TestFindVersionConflicts(t *testing.T) {
+	pkgs := []formats.Package{
+		{Rule: "npm", PackageType: "js", Name: "react", Version: "18.2.0", Source: "/repo/apps/web/package.json"},
+		{Rule: "npm", PackageType: "js", Name: "react", Version: "17.0.2", Source: "/repo/apps/admin/package.json"},
+		{Rule: "npm", PackageType: "js", Name: "react", Version: "18.2.0", Source: "/repo/package.json"},
+		{Rule: "npm", PackageType: "js", Name: "lodash", Version: "4.17.21", Source: "/repo/apps/web/package.json"},
+		{Rule: "npm", PackageType: "js", Name: "lodash", Version: "4.17.21", Source: "/repo/apps/admin/package.json"},
+		{Rule: "npm", PackageType: "js", Name: "vite", Version: "", Source: "/repo/apps/web/package.json"},
+		{Rule: "npm", PackageType: "js", Name: "vite", Version: "5.0.0", Source: "/repo/apps/admin/package.json"},
+		{Rule: "pnpm", PackageType: "js", Name: "react", Version: "16.0.0", Source: "/repo/legacy/package.json"},
+	}
+
+	conflicts := FindVersionConflicts(pkgs, "/repo")
+	assert.Equal(t, []VersionConflict{{
+		Rule:        "npm",
+		PackageType: "js",
+		Name:        "react",
+		Declarations: []Declaration{
+			{Version: "17.0.2", Source: "apps/admin/package.json"},
+			{Version: "18.2.0", Source: "apps/web/package.json"},
+			{Version: "18.2.0", Source: "package.json"},
+		},
+	}}, conflicts)
+	assert.Equal(t, []string{"17.0.2", "18.2.0"}, conflicts[0].Versions())
+	assert.Empty(t, FindVersionConflicts(pkgs[3:], "/repo"))
+
+	assert.Equal(t, "/repo/apps/web/package.json", FindVersionConflicts(pkgs, "/other")[0].Declarations[1].Source)
+
+	sink := &warningSink{}
+	restore := warnings.SetWarningWriter(sink)
+	count := WarnVersionConflicts(pkgs, "/repo")
+	restore()
+
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []warnings.Warning{{
+		Code:       warnings.CodeVersionConflict,
+		Message:    "⚠️ react is declared at 2 different versions: 17.0.2 in apps/admin/package.json, 18.2.0 in apps/web/package.json, 18.2.0 in package.json",
+		PackageRef: "react (js/npm)",
+	}}, sink.list)
+}
+
+type warningSink struct {
+	list []warnings.Warning
+}
+
+func (s *warningSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *warningSink) WriteWarning(w warnings.Warning) { s.list = append(s.list, w) }
+
+// TestUnsupportedTrackerReset tests clearing a tracker between passes.
+//
+// It verifies:
+//   - Reset drops every message and count
+//   - The tracker keeps working after Reset
+//   - Reset on a nil tracker is a no-op
+func TestUnsupportedTrackerReset(t *testing.T) {
+	tracker := NewUnsupportedTracker()
+	tracker.Add(formats.Package{Rule: "npm", PackageType: "js", InstallStatus: lock.InstallStatusFloating}, "floating")
+	tracker.Add(formats.Package{Rule: "mod", PackageType: "golang", InstallStatus: lock.InstallStatusNotConfigured}, "no lock")
+
+	tracker.Reset()
+	assert.Nil(t, tracker.Messages())
+	assert.Nil(t, tracker.GroupedMessages())
+	assert.Equal(t, 0, tracker.TotalPackages())
+
+	tracker.Add(formats.Package{Rule: "npm", PackageType: "js", InstallStatus: lock.InstallStatusFloating}, "floating")
+	assert.Equal(t, 1, tracker.TotalPackages())
+
+	var nilTracker *UnsupportedTracker
+	assert.NotPanics(t, nilTracker.Reset)
+}
+
+// TestUnsupportedTrackerMerge tests folding one tracker into another.
+//
+// It verifies:
+//   - Matching entries add up their counts in Messages and GroupedMessages
+//   - Entries only in the other tracker are copied, and the other tracker is unchanged
+//   - Later changes to the other tracker do not leak into the merged one
+//   - Merging nil or into itself is safe, and concurrent merges are counted exactly once each
+func TestUnsupportedTrackerMerge(t *testing.T) {
+	floating := formats.Package{Rule: "npm", PackageType: "js", InstallStatus: lock.InstallStatusFloating}
+	missing := formats.Package{Rule: "mod", PackageType: "golang", InstallStatus: lock.InstallStatusVersionMissing}
+
+	total := NewUnsupportedTracker()
+	total.Add(floating, "floating")
+
+	pass := NewUnsupportedTracker()
+	pass.Add(floating, "floating")
+	pass.Add(floating, "floating")
+	pass.Add(missing, "no version")
+
+	total.Merge(pass)
+	assert.Equal(t, 4, total.TotalPackages())
+	assert.Equal(t, 2, total.Count())
+	assert.Contains(t, total.Messages(), "⛔ npm (js): floating (3 packages)")
+	assert.Equal(t, []UnsupportedRuleInfo{
+		{Rule: "mod", PackageType: "golang", Reason: "no version", Count: 1},
+		{Rule: "npm", PackageType: "js", Reason: "floating", Count: 3},
+	}, total.GroupedMessages())
+	assert.Equal(t, 3, pass.TotalPackages())
+
+	pass.Add(missing, "no version")
+	pass.Reset()
+	assert.Equal(t, 4, total.TotalPackages())
+
+	total.Merge(nil)
+	total.Merge(total)
+	assert.Equal(t, 8, total.TotalPackages())
+
+	concurrent := NewUnsupportedTracker()
+	single := NewUnsupportedTracker()
+	single.Add(missing, "no version")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			concurrent.Merge(single)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 20, concurrent.TotalPackages())
+}
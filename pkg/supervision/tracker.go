@@ -8,8 +8,10 @@ import (
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/outdated"
 	"github.com/ajxudir/goupdate/pkg/utils"
 	"github.com/ajxudir/goupdate/pkg/verbose"
 )
@@ -30,11 +32,13 @@ type UnsupportedRuleInfo struct {
 
 // UnsupportedTracker collects unique unsupported reasons grouped by rule.
 //
-// It is safe for concurrent use. Packages are grouped by their rule and
-// package type combination, with counts aggregated for each unique reason.
+// It is safe for concurrent use. Packages are grouped by their rule, package
+// type, and install status combination, with counts aggregated for each group.
+// A second index groups packages by their exact reason for GroupedMessages.
 type UnsupportedTracker struct {
-	mu    sync.RWMutex
-	rules map[string]*UnsupportedRuleInfo
+	mu       sync.RWMutex
+	rules    map[string]*UnsupportedRuleInfo
+	byReason map[string]*UnsupportedRuleInfo
 }
 
 // NewUnsupportedTracker creates a new UnsupportedTracker.
@@ -46,7 +50,10 @@ type UnsupportedTracker struct {
 //
 //	tracker := supervision.NewUnsupportedTracker()
 func NewUnsupportedTracker() *UnsupportedTracker {
-	return &UnsupportedTracker{rules: make(map[string]*UnsupportedRuleInfo)}
+	return &UnsupportedTracker{
+		rules:    make(map[string]*UnsupportedRuleInfo),
+		byReason: make(map[string]*UnsupportedRuleInfo),
+	}
 }
 
 // ShouldTrackUnsupported returns true if the status indicates the package should be tracked.
@@ -54,7 +61,9 @@ func NewUnsupportedTracker() *UnsupportedTracker {
 // Trackable statuses include:
 //   - InstallStatusNotConfigured: Lock file not configured
 //   - InstallStatusFloating: Floating version constraint
+//   - InstallStatusNonRegistry: Installed from a git, path, or other non-registry source
 //   - InstallStatusVersionMissing: No concrete version found
+//   - InstallStatusLockUnavailable: Lock file needs a tool that is not installed
 //
 // Parameters:
 //   - status: Package install status string
@@ -70,14 +79,18 @@ func NewUnsupportedTracker() *UnsupportedTracker {
 func ShouldTrackUnsupported(status string) bool {
 	return strings.EqualFold(status, lock.InstallStatusNotConfigured) ||
 		strings.EqualFold(status, lock.InstallStatusFloating) ||
-		strings.EqualFold(status, lock.InstallStatusVersionMissing)
+		strings.EqualFold(status, lock.InstallStatusNonRegistry) ||
+		strings.EqualFold(status, lock.InstallStatusVersionMissing) ||
+		strings.EqualFold(status, lock.InstallStatusLockUnavailable)
 }
 
 // Add tracks an unsupported package with a reason.
 //
-// Packages are grouped by their rule and package type combination.
-// If a package with the same combination already exists, the count is
-// incremented. Empty reasons are ignored.
+// Packages are grouped by their rule, package type, and install status
+// combination, so packages blocked for different reasons (e.g., Floating
+// and NonRegistry) are reported separately. If a package with the same
+// combination already exists, the count is incremented. Empty reasons are
+// ignored.
 //
 // Parameters:
 //   - p: Package to track
@@ -92,11 +105,23 @@ func (t *UnsupportedTracker) Add(p formats.Package, reason string) {
 		return
 	}
 
-	key := fmt.Sprintf("%s|%s", p.PackageType, p.Rule)
+	key := fmt.Sprintf("%s|%s|%s", p.PackageType, p.Rule, p.InstallStatus)
+	reasonKey := fmt.Sprintf("%s|%s|%s", p.PackageType, p.Rule, reason)
 
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if info, exists := t.byReason[reasonKey]; exists {
+		info.Count++
+	} else {
+		t.byReason[reasonKey] = &UnsupportedRuleInfo{
+			Rule:        p.Rule,
+			PackageType: p.PackageType,
+			Reason:      reason,
+			Count:       1,
+		}
+	}
+
 	if info, exists := t.rules[key]; exists {
 		info.Count++
 		return
@@ -112,7 +137,7 @@ func (t *UnsupportedTracker) Add(p formats.Package, reason string) {
 
 // Messages returns formatted messages for all tracked unsupported rules.
 //
-// Messages are sorted by rule name, then by package type. Each message
+// Messages are sorted by rule name, then by package type, then by reason. Each message
 // includes an icon, rule name, package type, reason, and package count.
 //
 // Returns:
@@ -142,7 +167,10 @@ func (t *UnsupportedTracker) Messages() []string {
 		if entries[i].Rule != entries[j].Rule {
 			return entries[i].Rule < entries[j].Rule
 		}
-		return entries[i].PackageType < entries[j].PackageType
+		if entries[i].PackageType != entries[j].PackageType {
+			return entries[i].PackageType < entries[j].PackageType
+		}
+		return entries[i].Reason < entries[j].Reason
 	})
 
 	messages := make([]string, 0, len(entries))
@@ -154,6 +182,49 @@ func (t *UnsupportedTracker) Messages() []string {
 	return messages
 }
 
+// GroupedMessages returns tracked packages aggregated by rule and reason.
+//
+// Unlike Messages, which groups by install status and keeps the first reason
+// seen, each distinct reason within a rule gets its own entry with the number
+// of packages that reported it. This lets callers print compact per-rule
+// summaries such as "npm: 5 packages with floating constraints".
+//
+// Entries are sorted by rule name, then by package type, then by reason.
+//
+// Returns:
+//   - []UnsupportedRuleInfo: Copies of the aggregated entries, or nil if no packages tracked
+//
+// Example:
+//
+//	for _, info := range tracker.GroupedMessages() {
+//	    fmt.Printf("%s: %d packages - %s\n", info.Rule, info.Count, info.Reason)
+//	}
+func (t *UnsupportedTracker) GroupedMessages() []UnsupportedRuleInfo {
+	t.mu.RLock()
+	if len(t.byReason) == 0 {
+		t.mu.RUnlock()
+		return nil
+	}
+
+	entries := make([]UnsupportedRuleInfo, 0, len(t.byReason))
+	for _, info := range t.byReason {
+		entries = append(entries, *info)
+	}
+	t.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Rule != entries[j].Rule {
+			return entries[i].Rule < entries[j].Rule
+		}
+		if entries[i].PackageType != entries[j].PackageType {
+			return entries[i].PackageType < entries[j].PackageType
+		}
+		return entries[i].Reason < entries[j].Reason
+	})
+
+	return entries
+}
+
 // Count returns the total number of unique rule/package-type combinations tracked.
 //
 // Returns:
@@ -166,9 +237,15 @@ func (t *UnsupportedTracker) Count() int {
 
 // TotalPackages returns the total number of packages tracked across all rules.
 //
+// It is safe to call on a nil tracker, so a nil *UnsupportedTracker can be
+// passed where an update.UnsupportedCounter is expected.
+//
 // Returns:
-//   - int: Sum of all package counts
+//   - int: Sum of all package counts; 0 for a nil tracker
 func (t *UnsupportedTracker) TotalPackages() int {
+	if t == nil {
+		return 0
+	}
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
@@ -179,20 +256,103 @@ func (t *UnsupportedTracker) TotalPackages() int {
 	return total
 }
 
+// Reset clears every tracked package so the tracker can be reused for another pass.
+//
+// It is safe to call concurrently with Add; packages added after Reset
+// returns are tracked as usual. Safe to call on a nil tracker.
+//
+// Example:
+//
+//	for _, dir := range dirs {
+//	    tracker.Reset()
+//	    runPass(dir, tracker)
+//	}
+func (t *UnsupportedTracker) Reset() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	clear(t.rules)
+	clear(t.byReason)
+}
+
+// Merge folds a snapshot of another tracker into this one.
+//
+// Entries sharing a rule, package type and install status (or reason, for
+// GroupedMessages) add up their counts; other entries are copied. The other
+// tracker is read under its own lock before this one is locked, so two
+// trackers can merge into each other concurrently without deadlocking, and
+// merging a tracker into itself doubles its counts. A nil other is ignored.
+//
+// Parameters:
+//   - other: Tracker whose packages are added; it is not modified
+//
+// Example:
+//
+//	total := supervision.NewUnsupportedTracker()
+//	for _, pass := range passes {
+//	    total.Merge(pass.Unsupported)
+//	}
+func (t *UnsupportedTracker) Merge(other *UnsupportedTracker) {
+	if t == nil || other == nil {
+		return
+	}
+
+	other.mu.RLock()
+	rules := snapshotRuleInfos(other.rules)
+	byReason := snapshotRuleInfos(other.byReason)
+	other.mu.RUnlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	mergeRuleInfos(t.rules, rules)
+	mergeRuleInfos(t.byReason, byReason)
+}
+
+// snapshotRuleInfos copies tracked entries so they can be read without the tracker's lock.
+func snapshotRuleInfos(entries map[string]*UnsupportedRuleInfo) map[string]UnsupportedRuleInfo {
+	snapshot := make(map[string]UnsupportedRuleInfo, len(entries))
+	for key, info := range entries {
+		snapshot[key] = *info
+	}
+	return snapshot
+}
+
+// mergeRuleInfos adds snapshot entries to dst, summing the counts of existing keys.
+func mergeRuleInfos(dst map[string]*UnsupportedRuleInfo, snapshot map[string]UnsupportedRuleInfo) {
+	for key, info := range snapshot {
+		if existing, ok := dst[key]; ok {
+			existing.Count += info.Count
+			continue
+		}
+		copied := info
+		dst[key] = &copied
+	}
+}
+
+// dotnetPackageType is the package manager of the built-in msbuild and nuget rules.
+const dotnetPackageType = "dotnet"
+
 // DeriveUnsupportedReason determines the reason why a package cannot be updated.
 //
-// It returns a human-readable message for unsupported packages based on their
-// status and version constraints. Returns empty string if no specific reason
-// can be determined.
+// It performs the following operations:
+//   - Step 1: Explain lookups that --since, the quarantine, or offline mode could not run
+//   - Step 2: Use the rule's unsupported_reasons for non-registry sources, unpinned
+//     packages, and failed lookups
+//   - Step 3: Fall back to generic messages for missing versions, unreadable lock files,
+//     non-registry sources, floating constraints, pre-releases that are not allowed,
+//     and build metadata
+//   - Step 4: Print configuration help for packages without lock or outdated settings
 //
 // Parameters:
 //   - p: Package to analyze
-//   - cfg: Configuration (reserved for future use)
-//   - err: Error from previous operations (reserved for future use)
+//   - cfg: Configuration holding the package's rule; may be nil
+//   - err: Error from the version lookup, if any
 //   - latestMissing: true if outdated commands are not available
 //
 // Returns:
-//   - string: Human-readable reason, or empty string
+//   - string: Human-readable reason, or empty string if no specific reason applies
 //
 // Example:
 //
@@ -200,19 +360,64 @@ func (t *UnsupportedTracker) TotalPackages() int {
 //	if reason != "" {
 //	    tracker.Add(pkg, reason)
 //	}
-func DeriveUnsupportedReason(p formats.Package, _ *config.Config, _ error, latestMissing bool) string {
+func DeriveUnsupportedReason(p formats.Package, cfg *config.Config, err error, latestMissing bool) string {
+	// --since needs publish timestamps; explain why the package could not be filtered
+	if ue, ok := errors.IsUnsupportedError(err); ok && ue.Operation == outdated.SinceOperation {
+		return fmt.Sprintf("Release dates unavailable - %s; --since cannot filter its versions.", ue.Reason)
+	}
+	if ue, ok := errors.IsUnsupportedError(err); ok && ue.Operation == outdated.QuarantineOperation {
+		return fmt.Sprintf("Release dates unavailable - %s; the quarantine cannot check its versions.", ue.Reason)
+	}
+
+	if ue, ok := errors.IsUnsupportedError(err); ok && ue.Operation == outdated.OfflineOperation {
+		return "No offline version data - add the package to the rule's version_source index to check it offline."
+	}
+
+	if reason := ruleUnsupportedReason(p, cfg, err); reason != "" {
+		verbose.Debugf("Package '%s' is unsupported by rule '%s': %s", p.Name, p.Rule, reason)
+		return reason
+	}
+
 	// VersionMissing status - no concrete version could be determined
 	if strings.EqualFold(p.InstallStatus, lock.InstallStatusVersionMissing) {
 		verbose.UnsupportedHelp(p.Rule, "lock")
 		return "No concrete version found in manifest or lock file."
 	}
 
+	// The lock file exists, but the tool that reads it (e.g. bun for bun.lockb) is missing
+	if strings.EqualFold(p.InstallStatus, lock.InstallStatusLockUnavailable) {
+		return "Lock file unreadable - the package manager that reads it is not installed; install it to resolve installed versions."
+	}
+
+	// Non-registry sources (git, path, etc.) have no registry versions to compare against
+	if strings.EqualFold(p.InstallStatus, lock.InstallStatusNonRegistry) {
+		verbose.Debugf("Package '%s' is installed from a %s source - cannot auto-update", p.Name, p.NonRegistrySource)
+		return "Non-registry source - packages from git or path sources have no registry versions; update the source manually."
+	}
+
 	// Floating constraints (5.*, >=8.0.0, [8.0.0,9.0.0), etc.) cannot be updated automatically
 	if utils.IsFloatingConstraint(p.Version) {
 		verbose.Debugf("Package '%s' has floating constraint '%s' - cannot auto-update", p.Name, p.Version)
+		if p.PackageType == dotnetPackageType && utils.IsFloatingWildcard(p.Version) {
+			return fmt.Sprintf("Floating constraint '%s' - use --pin-floating to pin the highest matching release, or update manually.", p.Version)
+		}
 		return fmt.Sprintf("Floating constraint '%s' - update manually or remove constraint.", p.Version)
 	}
 
+	// Pre-release versions (1.0.0-beta.3) are skipped unless pre-releases are allowed;
+	// only blame the pre-release when the package is otherwise configured to update
+	if utils.IsPreReleaseVersion(p.Version) && !config.ShouldAllowPrerelease(p, cfg) &&
+		!strings.EqualFold(p.InstallStatus, lock.InstallStatusNotConfigured) && !latestMissing {
+		verbose.Debugf("Package '%s' is pinned to pre-release '%s' - not auto-updated", p.Name, p.Version)
+		return fmt.Sprintf("Pre-release constraint '%s' - pre-releases are not auto-updated; use --allow-prerelease.", p.Version)
+	}
+
+	// Build metadata (1.0.0+build.5) is ignored by SemVer precedence, so versions cannot be ordered
+	if utils.HasBuildMetadata(p.Version) {
+		verbose.Debugf("Package '%s' has build metadata '%s' - cannot compare versions", p.Name, p.Version)
+		return fmt.Sprintf("Build metadata version '%s' - build metadata is ignored when comparing versions; update manually.", p.Version)
+	}
+
 	// NotConfigured status - provide verbose help for configuration
 	if strings.EqualFold(p.InstallStatus, lock.InstallStatusNotConfigured) {
 		verbose.UnsupportedHelp(p.Rule, "lock")
@@ -227,3 +432,42 @@ func DeriveUnsupportedReason(p formats.Package, _ *config.Config, _ error, lates
 	// Only show messages for cases that require explanation (VersionMissing, Floating)
 	return ""
 }
+
+// ruleUnsupportedReason returns the unsupported_reasons message of the package's rule.
+//
+// It performs the following operations:
+//   - Step 1: For non-registry packages, return the message for their source, or the rule's non_registry message
+//   - Step 2: For packages declared without a version, return the unpinned message
+//   - Step 3: For failed version lookups, return the lookup_failed message
+//
+// Parameters:
+//   - p: Package to analyze
+//   - cfg: Configuration holding the package's rule; may be nil
+//   - err: Error from the version lookup, if any
+//
+// Returns:
+//   - string: The configured message, or empty string when the rule sets none
+func ruleUnsupportedReason(p formats.Package, cfg *config.Config, err error) string {
+	if cfg == nil {
+		return ""
+	}
+	rule, ok := cfg.Rules[p.Rule]
+	if !ok || rule.UnsupportedReasons == nil {
+		return ""
+	}
+	reasons := rule.UnsupportedReasons
+
+	if strings.EqualFold(p.InstallStatus, lock.InstallStatusNonRegistry) {
+		if reason := reasons.Sources[p.NonRegistrySource]; reason != "" {
+			return reason
+		}
+		return reasons.NonRegistry
+	}
+	if p.Version == "*" || strings.EqualFold(p.InstallStatus, lock.InstallStatusVersionMissing) {
+		return reasons.Unpinned
+	}
+	if err != nil {
+		return reasons.LookupFailed
+	}
+	return ""
+}
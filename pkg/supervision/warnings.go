@@ -0,0 +1,64 @@
+package supervision
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// StatusWarnings returns one classified warning per package whose install
+// status needs attention.
+//
+// The warnings are meant for structured output, where each package is reported
+// with a stable code; the console keeps showing the grouped unsupported summary.
+//
+// Statuses and their codes:
+//   - InstallStatusFloating: warnings.CodeFloatingConstraint
+//   - InstallStatusLockMissing: warnings.CodeLockMissing
+//   - InstallStatusVersionMissing: warnings.CodeVersionMissing
+//   - InstallStatusNonRegistry: warnings.CodeNonRegistry
+//
+// Parameters:
+//   - pkgs: Packages with resolved install status
+//
+// Returns:
+//   - []warnings.Warning: Warnings in package order; nil when no package needs attention
+//
+// Example:
+//
+//	details := supervision.StatusWarnings(pkgs)
+//	// [{FLOATING_CONSTRAINT "Floating constraint '5.*' - update manually or remove constraint." "serilog (dotnet/nuget)"}]
+func StatusWarnings(pkgs []formats.Package) []warnings.Warning {
+	var list []warnings.Warning
+	for _, p := range pkgs {
+		var code warnings.Code
+		var message string
+
+		switch {
+		case strings.EqualFold(p.InstallStatus, lock.InstallStatusFloating):
+			code = warnings.CodeFloatingConstraint
+			message = fmt.Sprintf("Floating constraint '%s' - update manually or remove constraint.", p.Version)
+		case strings.EqualFold(p.InstallStatus, lock.InstallStatusLockMissing):
+			code = warnings.CodeLockMissing
+			message = fmt.Sprintf("No lock file found for rule '%s' - run the package manager install to create it.", p.Rule)
+		case strings.EqualFold(p.InstallStatus, lock.InstallStatusVersionMissing):
+			code = warnings.CodeVersionMissing
+			message = "No concrete version found in manifest or lock file."
+		case strings.EqualFold(p.InstallStatus, lock.InstallStatusNonRegistry):
+			code = warnings.CodeNonRegistry
+			message = "Non-registry source - update the source manually."
+		default:
+			continue
+		}
+
+		list = append(list, warnings.Warning{
+			Code:       code,
+			Message:    message,
+			PackageRef: warnings.PackageRef(p.Name, p.PackageType, p.Rule),
+		})
+	}
+	return list
+}
@@ -0,0 +1,15 @@
+plugins {
+    id("com.android.application")
+    alias(libs.plugins.kotlin.android)
+}
+
+dependencies {
+    implementation(libs.okhttp)
+    implementation(libs.kotlin.stdlib)
+    implementation("androidx.core:core-ktx:1.12.0")
+    implementation("com.google.android.material:material:1.11.0@aar")
+    implementation(enforcedPlatform("com.squareup.okhttp3:okhttp-bom:4.12.0"))
+    testImplementation(libs.junit.jupiter)
+    androidTestImplementation("androidx.test.espresso:espresso-core:3.5.1")
+    debugImplementation("com.squareup.leakcanary:leakcanary-android:2.13")
+}
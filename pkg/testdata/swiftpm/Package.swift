@@ -0,0 +1,36 @@
+// swift-tools-version:5.9
+import PackageDescription
+
+let package = Package(
+    name: "InventoryService",
+    platforms: [
+        .macOS(.v13),
+    ],
+    dependencies: [
+        .package(url: "https://github.com/apple/swift-nio.git", from: "2.62.0"),
+        .package(url: "https://github.com/apple/swift-log.git", .upToNextMinor(from: "1.5.3")),
+        .package(url: "https://github.com/apple/swift-argument-parser", exact: "1.3.0"),
+        .package(
+            url: "https://github.com/Alamofire/Alamofire.git",
+            .upToNextMajor(from: "5.8.0")
+        ),
+        .package(url: "https://github.com/apple/swift-algorithms", "1.0.0"..<"2.0.0"),
+        .package(url: "https://github.com/apple/swift-collections.git", branch: "main"),
+        .package(url: "https://github.com/SnapKit/SnapKit.git", revision: "f222cbdf325885926566172f6f5f06af95473158"),
+        .package(path: "../SharedModels"),
+    ],
+    targets: [
+        .executableTarget(
+            name: "InventoryService",
+            dependencies: [
+                .product(name: "NIO", package: "swift-nio"),
+                .product(name: "Logging", package: "swift-log"),
+                .product(name: "ArgumentParser", package: "swift-argument-parser"),
+                .product(name: "Alamofire", package: "Alamofire"),
+                .product(name: "Algorithms", package: "swift-algorithms"),
+                .product(name: "Collections", package: "swift-collections"),
+                .product(name: "SnapKit", package: "SnapKit"),
+            ]
+        ),
+    ]
+)
@@ -0,0 +1,278 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// ContentBackups holds the raw bytes of manifests and lock files captured before a group is mutated.
+//
+// A failed group is rolled back by writing the captured bytes back, which is
+// exact and needs no lock command, instead of re-applying the original
+// versions through the updater. Each plan points at the backup of the group it
+// was applied in, so groups running in parallel never share an entry. Backups
+// are kept for the whole run because the after_all tests and dedupe roll back
+// groups that finished long before.
+//
+// Fields:
+//   - mu: Guards groups and order while groups run in parallel
+//   - groups: The backup of each plan's group
+//   - order: Every backup in the order it was captured
+type ContentBackups struct {
+	mu     sync.Mutex
+	groups map[*PlannedUpdate]*groupBackup
+	order  []*groupBackup
+}
+
+// groupBackup is the content of one group's files before the group was mutated.
+//
+// Fields:
+//   - seq: Position in ContentBackups.order; later groups saw the changes of earlier ones
+//   - plans: The group's planned updates
+//   - paths: Every file the backup covers, existing or absent
+//   - files: Files that existed, with their content and permissions
+//   - absent: Files that did not exist yet and are removed on restore
+type groupBackup struct {
+	seq    int
+	plans  []*PlannedUpdate
+	paths  map[string]bool
+	files  []fileBackup
+	absent []string
+}
+
+// NewContentBackups creates an empty backup store.
+//
+// Returns:
+//   - *ContentBackups: A store safe for concurrent use
+func NewContentBackups() *ContentBackups {
+	return &ContentBackups{groups: make(map[*PlannedUpdate]*groupBackup)}
+}
+
+// WithContentBackups sets the content backup store and returns the context for chaining.
+//
+// Rollback restores the captured bytes of a failed group's manifests and lock
+// files and only falls back to re-applying the original versions when no
+// backup was captured. A nil store disables content backups.
+func (ctx *UpdateContext) WithContentBackups(backups *ContentBackups) *UpdateContext {
+	ctx.Backups = backups
+	return ctx
+}
+
+// captureGroupBackup snapshots a group's manifests and lock files before it is mutated.
+//
+// It performs the following operations:
+//   - Step 1: Collect the manifests of the plans and the lock files next to them
+//   - Step 2: Read every file that exists and note the ones that do not
+//   - Step 3: Point each plan at the group's backup
+//
+// A file that cannot be read, or a plan whose manifest path is unknown, leaves
+// the group without a backup, so its rollback falls back to RollbackPlans'
+// version-based path.
+//
+// Parameters:
+//   - plans: The group's planned updates
+func (ctx *UpdateContext) captureGroupBackup(plans []*PlannedUpdate) {
+	if ctx.Backups == nil || ctx.DryRun || len(plans) == 0 {
+		return
+	}
+	for _, plan := range plans {
+		if plan.Res.Pkg.Source == "" {
+			verbose.Debugf("Content backup skipped: %s has no manifest path", plan.Res.Pkg.Name)
+			return
+		}
+	}
+
+	paths := groupBackupPaths(ctx, plans)
+	backup := &groupBackup{plans: plans, paths: make(map[string]bool, len(paths))}
+	for _, path := range paths {
+		backup.paths[path] = true
+		if _, err := statFileFunc(path); os.IsNotExist(err) {
+			backup.absent = append(backup.absent, path)
+		}
+	}
+	files, err := backupFiles(paths)
+	if err != nil {
+		verbose.Printf("Content backup skipped, rollback will re-apply original versions: %v\n", err)
+		return
+	}
+	backup.files = files
+	verbose.Debugf("Content backup captured: %d files, %d absent", len(backup.files), len(backup.absent))
+
+	ctx.Backups.mu.Lock()
+	defer ctx.Backups.mu.Unlock()
+	backup.seq = len(ctx.Backups.order)
+	ctx.Backups.order = append(ctx.Backups.order, backup)
+	for _, plan := range plans {
+		ctx.Backups.groups[plan] = backup
+	}
+}
+
+// restoreGroupBackup writes back the captured content of the plans' files.
+//
+// It performs the following operations:
+//   - Step 1: Look up the backup of every plan; give up when one has none
+//   - Step 2: Give up when restoring would also revert an updated plan outside plans
+//   - Step 3: Write each captured file back with its original permissions, newest backup first
+//   - Step 4: Remove files that did not exist when the backup was taken
+//
+// Step 2 covers a single plan of a group rolled back on its own and a group
+// whose files were changed again by a later group that stays updated; both
+// fall back to RollbackPlans' version-based path.
+//
+// Parameters:
+//   - plans: The applied plans to roll back
+//
+// Returns:
+//   - bool: true when every plan had a backup and restoring was attempted
+//   - error: The joined write and remove failures; nil when everything was restored
+func (ctx *UpdateContext) restoreGroupBackup(plans []*PlannedUpdate) (bool, error) {
+	if ctx == nil || ctx.Backups == nil || len(plans) == 0 {
+		return false, nil
+	}
+
+	ctx.Backups.mu.Lock()
+	var backups []*groupBackup
+	seen := make(map[*groupBackup]bool)
+	inSet := make(map[*PlannedUpdate]bool, len(plans))
+	for _, plan := range plans {
+		inSet[plan] = true
+		backup, ok := ctx.Backups.groups[plan]
+		if !ok {
+			ctx.Backups.mu.Unlock()
+			return false, nil
+		}
+		if !seen[backup] {
+			seen[backup] = true
+			backups = append(backups, backup)
+		}
+	}
+	for _, backup := range backups {
+		if plan := ctx.Backups.keptUpdate(backup, inSet); plan != nil {
+			ctx.Backups.mu.Unlock()
+			verbose.Printf("Content backup not restored, it would also revert %s\n", plan.Res.Pkg.Name)
+			return false, nil
+		}
+	}
+	ctx.Backups.mu.Unlock()
+
+	// Later groups captured the changes of earlier ones, so they are undone first
+	sort.Slice(backups, func(i, j int) bool { return backups[i].seq > backups[j].seq })
+
+	var errs []error
+	for _, backup := range backups {
+		errs = append(errs, restoreBackups(backup.files)...)
+		for _, path := range backup.absent {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("failed to remove %s: %w", path, err))
+			}
+		}
+	}
+	return true, errors.Join(errs...)
+}
+
+// keptUpdate finds an updated plan that restoring a backup would revert although it is not rolled back.
+//
+// The plans of the backup's own group and of every later group sharing one of
+// its files are checked, since writing the backup back also undoes their
+// changes. The caller must hold mu.
+//
+// Parameters:
+//   - backup: The backup about to be restored
+//   - inSet: The plans being rolled back
+//
+// Returns:
+//   - *PlannedUpdate: The first updated plan outside inSet; nil when restoring is safe
+func (b *ContentBackups) keptUpdate(backup *groupBackup, inSet map[*PlannedUpdate]bool) *PlannedUpdate {
+	for _, other := range b.order[backup.seq:] {
+		if other != backup && !sharesPath(backup, other) {
+			continue
+		}
+		for _, plan := range other.plans {
+			if plan.Res.Status == constants.StatusUpdated && !inSet[plan] {
+				return plan
+			}
+		}
+	}
+	return nil
+}
+
+// sharesPath reports whether two backups cover a common file.
+func sharesPath(a, b *groupBackup) bool {
+	for path := range a.paths {
+		if b.paths[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// groupBackupPaths lists the files a group may write: its manifests and their lock files.
+//
+// Lock file patterns without glob characters are listed even when the file
+// does not exist yet, so a lock file created by the group is removed again
+// on restore.
+//
+// Parameters:
+//   - ctx: Update context providing the configuration and working directory
+//   - plans: The group's planned updates
+//
+// Returns:
+//   - []string: Distinct paths in sorted order
+func groupBackupPaths(ctx *UpdateContext, plans []*PlannedUpdate) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	workDir := ctx.WorkDir
+	if workDir == "" {
+		workDir = "."
+	}
+	for _, plan := range plans {
+		if plan.Res.Pkg.Source != "" {
+			add(plan.Res.Pkg.Source)
+		}
+		if ctx.Cfg == nil {
+			continue
+		}
+		dir := packageLockDir(ctx.Cfg, plan.Res.Pkg, workDir)
+		for _, lockCfg := range ctx.Cfg.Rules[plan.Res.Pkg.Rule].LockFiles {
+			for _, pattern := range lockCfg.Files {
+				full := scopedLockPattern(dir, pattern)
+				matches, err := filepath.Glob(full)
+				if err == nil && len(matches) > 0 {
+					for _, match := range matches {
+						add(match)
+					}
+				} else if !hasGlobMeta(pattern) {
+					add(full)
+				}
+			}
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// hasGlobMeta reports whether a pattern contains glob characters.
+func hasGlobMeta(pattern string) bool {
+	for _, c := range pattern {
+		switch c {
+		case '*', '?', '[', '\\':
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package update
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// backupFixture writes an npm manifest and lock file and returns a group of two plans declared in it.
+func backupFixture(t *testing.T) (dir string, cfg *config.Config, plans []*PlannedUpdate) {
+	t.Helper()
+	dir = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"dependencies": {"react": "17.0.0", "vue": "2.0.0"}}`), 0o640))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte(`{"lockfileVersion": 3}`), 0o600))
+
+	rule := testutil.NPMRule()
+	rule.LockFiles = []config.LockFileCfg{{Files: []string{"package-lock.json", "npm-shrinkwrap.json"}}}
+	cfg = testutil.NewConfig().WithRule("npm", rule).Build()
+
+	for _, pkg := range []struct{ name, version, target string }{{"react", "17.0.0", "18.0.0"}, {"vue", "2.0.0", "3.0.0"}} {
+		p := testutil.NPMPackage(pkg.name, pkg.version, pkg.version)
+		p.Source = filepath.Join(dir, "package.json")
+		plans = append(plans, &PlannedUpdate{
+			Res:      UpdateResult{Pkg: p, Target: pkg.target, Status: constants.StatusPlanned},
+			Cfg:      &config.UpdateCfg{Commands: "npm install"},
+			Original: pkg.version,
+			GroupKey: "npm:js:frontend",
+		})
+	}
+	return dir, cfg, plans
+}
+
+// TestRollbackFromContentBackup tests rolling back a failed group from its content backup.
+//
+// It verifies:
+//   - The manifest and lock file get back their exact bytes and permissions
+//   - A lock file created by the group is removed again
+//   - The updater is not called to roll back, so no lock command runs
+//   - The drift check passes against the restored manifest
+//   - The backup is kept after the group finished
+func TestRollbackFromContentBackup(t *testing.T) {
+	dir, cfg, plans := backupFixture(t)
+	manifest := filepath.Join(dir, "package.json")
+	lockFile := filepath.Join(dir, "package-lock.json")
+	shrinkwrap := filepath.Join(dir, "npm-shrinkwrap.json")
+	originalManifest, err := os.ReadFile(manifest)
+	require.NoError(t, err)
+
+	var calls []string
+	updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		calls = append(calls, p.Name+"@"+target)
+		if p.Name == "vue" {
+			return errors.New("npm ERR! peer conflict")
+		}
+		require.NoError(t, os.WriteFile(manifest, []byte(`{"dependencies": {"react": "18.0.0", "vue": "2.0.0"}}`), 0o644))
+		require.NoError(t, os.WriteFile(lockFile, []byte(`{"lockfileVersion": 3, "changed": true}`), 0o644))
+		return os.WriteFile(shrinkwrap, []byte(`{}`), 0o644)
+	}
+	// The reloaded react version follows the manifest, so validation and the drift check see the real state
+	reload := func() ([]formats.Package, error) {
+		react := plans[0].Res.Pkg
+		content, err := os.ReadFile(manifest)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(string(content), `"react": "18.0.0"`) {
+			react.Version, react.InstalledVersion = "18.0.0", "18.0.0"
+		}
+		return []formats.Package{react, plans[1].Res.Pkg}, nil
+	}
+
+	backups := NewContentBackups()
+	ctx := NewUpdateContext(cfg, dir, nil).
+		WithUpdaterFunc(updater).
+		WithReloadList(reload).
+		WithContentBackups(backups).
+		WithFlags(false, false, true)
+	var results []UpdateResult
+
+	ProcessGroupedPlansLive(ctx, plans, &results, ExecutionCallbacks{DeriveReason: func(formats.Package, *config.Config, error, bool) string { return "" }})
+
+	assert.Equal(t, []string{"react@18.0.0", "vue@3.0.0"}, calls)
+	content, err := os.ReadFile(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, originalManifest, content)
+	content, err = os.ReadFile(lockFile)
+	require.NoError(t, err)
+	assert.Equal(t, `{"lockfileVersion": 3}`, string(content))
+	assert.NoFileExists(t, shrinkwrap)
+	if info, err := os.Stat(manifest); assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	}
+	assert.Equal(t, constants.StatusFailed, plans[0].Res.Status)
+	for _, failure := range ctx.Failures {
+		assert.NotContains(t, failure.Error(), "drift check failed")
+	}
+	assert.Len(t, backups.order, 1)
+}
+
+// TestRollbackPlansFallsBackWithoutBackup tests RollbackPlans when no content backup can be used.
+//
+// It verifies:
+//   - Plans without a captured backup are rolled back through the updater
+//   - A partial backup covering only some plans is not used
+//   - No backup is taken when a plan's manifest path is unknown
+//   - Dry runs never restore content
+func TestRollbackPlansFallsBackWithoutBackup(t *testing.T) {
+	dir, cfg, plans := backupFixture(t)
+
+	var calls []string
+	updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		calls = append(calls, p.Name+"@"+target)
+		return nil
+	}
+	ctx := NewUpdateContext(cfg, dir, nil).WithContentBackups(NewContentBackups())
+
+	require.NoError(t, RollbackPlans(plans, cfg, dir, ctx, errors.New("boom"), updater, false, false))
+	assert.Equal(t, []string{"react@17.0.0", "vue@2.0.0"}, calls)
+
+	calls = nil
+	ctx.captureGroupBackup(plans[:1])
+	require.NoError(t, RollbackPlans(plans, cfg, dir, ctx, errors.New("boom"), updater, false, false))
+	assert.Equal(t, []string{"react@17.0.0", "vue@2.0.0"}, calls)
+
+	calls = nil
+	ctx.captureGroupBackup(plans)
+	require.NoError(t, RollbackPlans(plans, cfg, dir, ctx, errors.New("boom"), updater, true, false))
+	assert.Equal(t, []string{"react@17.0.0", "vue@2.0.0"}, calls)
+
+	calls = nil
+	require.NoError(t, RollbackPlans(plans, cfg, dir, NewUpdateContext(cfg, dir, nil), errors.New("boom"), updater, false, false))
+	assert.Len(t, calls, 2)
+
+	calls = nil
+	ctx = NewUpdateContext(cfg, dir, nil).WithContentBackups(NewContentBackups())
+	plans[1].Res.Pkg.Source = ""
+	ctx.captureGroupBackup(plans)
+	require.NoError(t, RollbackPlans(plans, cfg, dir, ctx, errors.New("boom"), updater, false, false))
+	assert.Len(t, calls, 2)
+}
+
+// TestRollbackBatchFromContentBackups tests rolling back a whole run from the backups of its groups.
+//
+// It verifies:
+//   - Single-package groups get a backup too
+//   - Groups that changed the same manifest in turn are restored newest first
+//   - Rolling back only one group's plan falls back to the updater while a later group stays updated
+func TestRollbackBatchFromContentBackups(t *testing.T) {
+	dir, cfg, plans := backupFixture(t)
+	manifest := filepath.Join(dir, "package.json")
+	originalManifest, err := os.ReadFile(manifest)
+	require.NoError(t, err)
+	plans[1].GroupKey = "npm:js:backend"
+
+	var calls []string
+	updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		calls = append(calls, p.Name+"@"+target)
+		content, err := os.ReadFile(manifest)
+		if err != nil {
+			return err
+		}
+		updated := strings.Replace(string(content), `"`+p.Name+`": "`+p.Version+`"`, `"`+p.Name+`": "`+target+`"`, 1)
+		return os.WriteFile(manifest, []byte(updated), 0o644)
+	}
+
+	backups := NewContentBackups()
+	ctx := NewUpdateContext(cfg, dir, nil).
+		WithUpdaterFunc(updater).
+		WithContentBackups(backups).
+		WithFlags(false, false, true)
+	var results []UpdateResult
+	ProcessGroupedPlansLive(ctx, plans, &results, ExecutionCallbacks{DeriveReason: func(formats.Package, *config.Config, error, bool) string { return "" }})
+	require.Len(t, backups.order, 2)
+	for _, plan := range plans {
+		require.Equal(t, constants.StatusUpdated, plan.Res.Status)
+	}
+
+	calls = nil
+	restored, err := ctx.restoreGroupBackup(plans[:1])
+	require.NoError(t, err)
+	assert.False(t, restored)
+	assert.Empty(t, calls)
+
+	rolledBack, err := RollbackBatch(ctx, plans, results, errors.New("after_all tests failed"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, rolledBack)
+	assert.Empty(t, calls)
+	content, err := os.ReadFile(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, originalManifest, content)
+}
+
+// TestGroupBackupPaths tests which files a group backup covers.
+//
+// It verifies:
+//   - Manifests and existing lock files are listed once each
+//   - Literal lock file names are listed even when missing; glob patterns only when they match
+//   - Packages without a manifest path use the working directory
+func TestGroupBackupPaths(t *testing.T) {
+	dir, cfg, plans := backupFixture(t)
+	rule := cfg.Rules["npm"]
+	rule.LockFiles = append(rule.LockFiles, config.LockFileCfg{Files: []string{"*.lockb"}})
+	cfg.Rules["npm"] = rule
+
+	ctx := NewUpdateContext(cfg, dir, nil)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "npm-shrinkwrap.json"),
+		filepath.Join(dir, "package-lock.json"),
+		filepath.Join(dir, "package.json"),
+	}, groupBackupPaths(ctx, plans))
+
+	plans[0].Res.Pkg.Source = ""
+	plans = plans[:1]
+	assert.Equal(t, []string{
+		filepath.Join(dir, "npm-shrinkwrap.json"),
+		filepath.Join(dir, "package-lock.json"),
+	}, groupBackupPaths(ctx, plans))
+}
@@ -0,0 +1,90 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/changelog"
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// maxChangelogLines caps the release notes lines printed below a table row.
+const maxChangelogLines = 6
+
+// AttachChangelogs fetches the release notes for every plan that will be applied.
+//
+// The notes cover the versions after the installed version (or the declared
+// version when nothing is installed) up to the target, and are stored on
+// plan.Res.Changelog so they flow into the live table and structured output.
+// Plans that are skipped get no notes. Fetching is best-effort: failures are
+// reported in verbose mode and never change a plan's status.
+//
+// Parameters:
+//   - ctx: Context for cancellation; fetching stops once it is done
+//   - plans: The planned updates
+//   - cfg: Global configuration containing rule definitions
+//   - provider: Release notes provider
+func AttachChangelogs(ctx context.Context, plans []*PlannedUpdate, cfg *config.Config, provider changelog.Provider) {
+	if provider == nil || cfg == nil {
+		return
+	}
+
+	for _, plan := range plans {
+		if ctx.Err() != nil {
+			return
+		}
+		if plan == nil || ShouldSkipUpdate(&plan.Res) {
+			continue
+		}
+
+		from := outdated.CurrentVersionForOutdated(plan.Res.Pkg)
+		if from == "" {
+			from = plan.Original
+		}
+
+		notes, err := provider.Fetch(ctx, plan.Res.Pkg, cfg.Rules[plan.Res.Pkg.Rule], from, plan.Res.Target)
+		if err != nil {
+			verbose.Printf("Release notes for %s skipped: %v\n", plan.Res.Pkg.Name, err)
+			continue
+		}
+		plan.Res.Changelog = notes
+	}
+}
+
+// PrintChangelog writes release notes below a table row.
+//
+// The URL is always printed; the snippet follows, cut to a few lines, only
+// when withSnippet is set (--show-diff or verbose mode).
+//
+// Parameters:
+//   - w: Destination writer
+//   - notes: Release notes attached by AttachChangelogs; nothing is written when nil
+//   - withSnippet: Whether to print the truncated release notes text
+func PrintChangelog(w io.Writer, notes *changelog.Notes, withSnippet bool) {
+	if notes == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "    ↳ Release notes: %s\n", notes.URL)
+	if !withSnippet {
+		return
+	}
+
+	var lines []string
+	for _, line := range strings.Split(notes.Snippet, "\n") {
+		if line = strings.TrimRight(line, " \t\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	for i, line := range lines {
+		if i == maxChangelogLines {
+			_, _ = fmt.Fprintln(w, "      …")
+			break
+		}
+		_, _ = fmt.Fprintf(w, "      %s\n", line)
+	}
+}
@@ -0,0 +1,104 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ajxudir/goupdate/pkg/changelog"
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// stubChangelogProvider returns canned notes and records the requested ranges.
+type stubChangelogProvider struct {
+	notes    map[string]*changelog.Notes
+	requests []string
+}
+
+// Fetch returns the notes registered for the package name, or an error.
+func (s *stubChangelogProvider) Fetch(_ context.Context, p formats.Package, rule config.PackageManagerCfg, from, to string) (*changelog.Notes, error) {
+	s.requests = append(s.requests, fmt.Sprintf("%s %s..%s (%s)", p.Name, from, to, rule.Manager))
+	if notes, ok := s.notes[p.Name]; ok {
+		return notes, nil
+	}
+	return nil, fmt.Errorf("GitHub API rate limit exceeded")
+}
+
+// TestAttachChangelogs tests attaching release notes to planned updates.
+//
+// It verifies:
+//   - Notes are fetched from the installed version to the target with the package's rule
+//   - Skipped plans are not fetched
+//   - Fetch failures leave the plan without notes and never change its status
+func TestAttachChangelogs(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+	provider := &stubChangelogProvider{notes: map[string]*changelog.Notes{
+		"react": {URL: "https://github.com/facebook/react/releases/tag/v18.2.0", Snippet: "## v18.2.0\nFixes"},
+	}}
+
+	react := &PlannedUpdate{Res: UpdateResult{Pkg: testutil.NPMPackage("react", "18.0.0", "18.1.0"), Target: "18.2.0", Status: constants.StatusPlanned}}
+	vue := &PlannedUpdate{Res: UpdateResult{Pkg: testutil.NPMPackage("vue", "3.0.0", "3.0.0"), Target: "3.4.0", Status: constants.StatusPlanned}}
+	upToDate := &PlannedUpdate{Res: UpdateResult{Pkg: testutil.NPMPackage("lodash", "4.17.21", "4.17.21"), Status: constants.StatusUpToDate}}
+
+	AttachChangelogs(context.Background(), []*PlannedUpdate{react, vue, upToDate, nil}, cfg, provider)
+
+	assert.Equal(t, []string{"react 18.1.0..18.2.0 (js)", "vue 3.0.0..3.4.0 (js)"}, provider.requests)
+	assert.Equal(t, "https://github.com/facebook/react/releases/tag/v18.2.0", react.Res.Changelog.URL)
+	assert.Nil(t, vue.Res.Changelog)
+	assert.Equal(t, constants.StatusPlanned, vue.Res.Status)
+	assert.Nil(t, vue.Res.Err)
+	assert.Nil(t, upToDate.Res.Changelog)
+}
+
+// TestAttachChangelogsCancelled tests that no notes are fetched once the context is done.
+//
+// It verifies:
+//   - A cancelled context stops fetching before the first request
+func TestAttachChangelogsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	provider := &stubChangelogProvider{}
+	plan := &PlannedUpdate{Res: UpdateResult{Pkg: testutil.NPMPackage("react", "18.0.0", "18.0.0"), Target: "18.2.0", Status: constants.StatusPlanned}}
+	AttachChangelogs(ctx, []*PlannedUpdate{plan}, testutil.NewConfig().Build(), provider)
+
+	assert.Empty(t, provider.requests)
+}
+
+// TestPrintChangelog tests printing release notes below a table row.
+//
+// It verifies:
+//   - Only the URL is printed without the snippet flag
+//   - The snippet is printed without blank lines and cut after a few lines
+//   - Nil notes print nothing
+func TestPrintChangelog(t *testing.T) {
+	notes := &changelog.Notes{
+		URL:     "https://github.com/o/r/releases",
+		Snippet: "## v2.0.0\n\n- one\n- two\n- three\n- four\n- five\n- six",
+	}
+
+	var buf bytes.Buffer
+	PrintChangelog(&buf, notes, false)
+	assert.Equal(t, "    ↳ Release notes: https://github.com/o/r/releases\n", buf.String())
+
+	buf.Reset()
+	PrintChangelog(&buf, notes, true)
+	assert.Equal(t, "    ↳ Release notes: https://github.com/o/r/releases\n"+
+		"      ## v2.0.0\n"+
+		"      - one\n"+
+		"      - two\n"+
+		"      - three\n"+
+		"      - four\n"+
+		"      - five\n"+
+		"      …\n", buf.String())
+
+	buf.Reset()
+	PrintChangelog(&buf, nil, true)
+	assert.Empty(t, buf.String())
+}
@@ -804,7 +804,7 @@ func TestChaos_CollectUpdateErrors_MixedResults(t *testing.T) {
 		{Pkg: testutil.NPMPackage("pkg3", "1.0.0", "1.0.0"), Err: nil},
 	}
 
-	errs := CollectUpdateErrors(results)
+	errs := CollectUpdateErrors(results, nil)
 
 	assert.Len(t, errs, 1, "should collect only actual errors")
 	assert.Contains(t, errs[0].Error(), "actual error")
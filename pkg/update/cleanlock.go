@@ -0,0 +1,94 @@
+package update
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// CheckCleanLockFiles fails when a lock file of the packages has uncommitted git changes.
+//
+// Lock commands rewrite whole lock files, so pre-existing edits would be mixed
+// into the update and restored over by a rollback (--require-clean-lock).
+//
+// It performs the following operations:
+//   - Step 1: Collect the lock files of each package's rule next to its manifest
+//   - Step 2: Ask git for the uncommitted changes of those files
+//   - Step 3: List every dirty lock file in one validation error
+//
+// Parameters:
+//   - cfg: Configuration used to find each rule's lock files
+//   - packages: Packages about to be updated
+//   - dir: Directory inside the git working tree
+//
+// Returns:
+//   - error: *errors.ValidationError listing the dirty lock files, or when git cannot be run; nil when every lock file is clean
+func CheckCleanLockFiles(cfg *config.Config, packages []formats.Package, dir string) error {
+	paths := lockFilesOf(cfg, packages)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	out, err := gitCommandFunc(commitDir(dir), append([]string{"status", "--porcelain", "--"}, paths...)...)
+	if err != nil {
+		return &errors.ValidationError{
+			Category: errors.ValidationCategoryPreflight,
+			Message:  fmt.Sprintf("cannot check lock files for uncommitted changes: %v", err),
+		}
+	}
+
+	var dirty []string
+	for _, line := range strings.Split(string(out), "\n") {
+		// Porcelain lines are a two-letter status, a space, and the path
+		if len(line) > 3 {
+			dirty = append(dirty, strings.TrimSpace(line[3:]))
+		}
+	}
+	if len(dirty) == 0 {
+		return nil
+	}
+
+	return &errors.ValidationError{
+		Category: errors.ValidationCategoryPreflight,
+		Message:  fmt.Sprintf("%d lock file(s) have uncommitted changes: %s", len(dirty), strings.Join(dirty, ", ")),
+	}
+}
+
+// lockFilesOf returns the absolute lock file paths of the packages' rules.
+//
+// Parameters:
+//   - cfg: Configuration holding each rule's lock file patterns
+//   - packages: Packages whose manifests anchor the patterns
+//
+// Returns:
+//   - []string: Existing lock files, sorted and without duplicates
+func lockFilesOf(cfg *config.Config, packages []formats.Package) []string {
+	if cfg == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, p := range packages {
+		if p.Source == "" {
+			continue
+		}
+		for _, path := range getLockFilePaths(cfg.Rules[p.Rule], packageLockDir(cfg, p, "")) {
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
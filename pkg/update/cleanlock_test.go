@@ -0,0 +1,62 @@
+package update
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestCheckCleanLockFiles tests the --require-clean-lock preflight check.
+//
+// It verifies:
+//   - Committed lock files pass, even when other files are dirty
+//   - Modified and untracked lock files are listed in a preflight validation error
+//   - Packages without lock files are not checked
+//   - A directory outside a git repository is reported as a validation error
+func TestCheckCleanLockFiles(t *testing.T) {
+	dir := initGitRepo(t)
+	lockPath := filepath.Join(dir, "package-lock.json")
+	require.NoError(t, os.WriteFile(lockPath, []byte(`{"lockfileVersion":3}`), 0o644))
+	_, err := runGitCommand(dir, "add", "package-lock.json")
+	require.NoError(t, err)
+	_, err = runGitCommand(dir, "commit", "-q", "-m", "lock")
+	require.NoError(t, err)
+
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"npm":  {Manager: "js", LockFiles: []config.LockFileCfg{{Files: []string{"package-lock.json", "yarn.lock"}}}},
+		"none": {Manager: "js"},
+	}}
+	packages := []formats.Package{
+		{Name: "react", Rule: "npm", Source: filepath.Join(dir, "package.json")},
+		{Name: "lodash", Rule: "npm", Source: filepath.Join(dir, "package.json")},
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{}`), 0o644))
+	assert.NoError(t, CheckCleanLockFiles(cfg, packages, dir))
+
+	require.NoError(t, os.WriteFile(lockPath, []byte(`{"lockfileVersion":2}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "yarn.lock"), []byte("# yarn\n"), 0o644))
+	err = CheckCleanLockFiles(cfg, packages, dir)
+	require.Error(t, err)
+	validationErr, ok := errors.IsValidationError(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.ValidationCategoryPreflight, validationErr.Category)
+	assert.Contains(t, err.Error(), "2 lock file(s) have uncommitted changes")
+	assert.Contains(t, err.Error(), "package-lock.json")
+	assert.Contains(t, err.Error(), "yarn.lock")
+
+	assert.NoError(t, CheckCleanLockFiles(cfg, []formats.Package{{Name: "x", Rule: "none", Source: filepath.Join(dir, "package.json")}}, dir))
+
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "package-lock.json"), []byte(`{}`), 0o644))
+	err = CheckCleanLockFiles(cfg, []formats.Package{{Name: "react", Rule: "npm", Source: filepath.Join(outside, "package.json")}}, outside)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot check lock files for uncommitted changes")
+}
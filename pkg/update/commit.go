@@ -0,0 +1,356 @@
+package update
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// DefaultCommitMessage is the message template used by `goupdate update --commit`.
+//
+// Supported placeholders are {{name}}, {{from}}, {{to}}, {{rule}} and {{group}}.
+const DefaultCommitMessage = "chore(deps): bump {{name}} from {{from}} to {{to}}"
+
+// groupCommitSubject is the subject of a commit covering several packages of
+// an update group; each package gets a line rendered from the message template.
+const groupCommitSubject = "chore(deps): bump {{count}} packages in the {{group}} group"
+
+// dedupeCommitSubject is the message of the commit holding a rule's dedupe changes.
+const dedupeCommitSubject = "chore(deps): dedupe {{rule}} lock files"
+
+// gitCommandFunc runs a git command in a directory; replaced in tests.
+var gitCommandFunc = runGitCommand
+
+// runGitCommand runs git with the given arguments in dir.
+//
+// Parameters:
+//   - dir: Directory to run git in
+//   - args: Arguments passed to git
+//
+// Returns:
+//   - []byte: Combined output of the command
+//   - error: When git fails, including its trimmed output
+func runGitCommand(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return out, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// CheckCleanWorkTree fails when the git working tree in dir has uncommitted changes.
+//
+// Commit mode stages the manifests and lock files it touched, so pre-existing
+// changes in those files would end up in the update commits. The run state file
+// written for --since last-run is never staged and does not count as a change.
+//
+// Parameters:
+//   - dir: Directory inside the git working tree
+//
+// Returns:
+//   - error: When dir is not inside a git working tree or the tree is dirty
+func CheckCleanWorkTree(dir string) error {
+	out, err := gitCommandFunc(commitDir(dir), "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+
+	var dirty []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		line = strings.TrimSpace(line)
+		if fields := strings.Fields(line); len(fields) == 0 || filepath.Base(strings.Trim(fields[len(fields)-1], `"`)) == outdated.StateFileName {
+			continue
+		}
+		dirty = append(dirty, line)
+	}
+	if len(dirty) == 0 {
+		return nil
+	}
+
+	const shown = 5
+	listed := dirty
+	if len(listed) > shown {
+		listed = listed[:shown]
+	}
+	msg := fmt.Sprintf("working tree has %d uncommitted change(s): %s", len(dirty), strings.Join(listed, ", "))
+	if len(dirty) > shown {
+		msg += ", ..."
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// RenderCommitMessage renders a commit message template for an update result.
+//
+// Parameters:
+//   - template: Message template; DefaultCommitMessage when empty
+//   - res: The successful update result
+//
+// Returns:
+//   - string: The rendered message
+func RenderCommitMessage(template string, res UpdateResult) string {
+	if strings.TrimSpace(template) == "" {
+		template = DefaultCommitMessage
+	}
+	return strings.NewReplacer(
+		"{{name}}", res.Pkg.Name,
+		"{{from}}", SafeFromVersion(res),
+		"{{to}}", res.Target,
+		"{{rule}}", res.Pkg.Rule,
+		"{{group}}", res.Group,
+	).Replace(template)
+}
+
+// Committer commits each successful update group to git.
+//
+// Commits are serialized so groups running in parallel never race on the
+// git index.
+//
+// Fields:
+//   - Dir: Directory inside the git working tree
+//   - Message: Message template; DefaultCommitMessage when empty
+type Committer struct {
+	Dir     string
+	Message string
+
+	mu sync.Mutex
+}
+
+// NewCommitter creates a committer for the working tree containing dir.
+//
+// Parameters:
+//   - dir: Directory inside the git working tree
+//   - message: Message template; DefaultCommitMessage when empty
+//
+// Returns:
+//   - *Committer: The committer
+func NewCommitter(dir, message string) *Committer {
+	return &Committer{Dir: dir, Message: message}
+}
+
+// CommitGroup creates one commit for the updated packages of a group.
+//
+// It performs the following operations:
+//   - Step 1: Keep the plans that are still updated, so rolled-back plans are never committed
+//   - Step 2: Stage their manifests and lock files, skipping files git ignores (see commitFiles)
+//   - Step 3: Commit only those files, skipping the commit when none of them changed
+//
+// Parameters:
+//   - cfg: Configuration used to find each rule's lock files
+//   - plans: The group's plans after validation, system tests and rollback
+//
+// Returns:
+//   - error: When staging or committing fails
+func (c *Committer) CommitGroup(cfg *config.Config, plans []*PlannedUpdate) error {
+	var updated []UpdateResult
+	for _, plan := range plans {
+		if plan.Res.Status == constants.StatusUpdated {
+			updated = append(updated, plan.Res)
+		}
+	}
+	if len(updated) == 0 {
+		return nil
+	}
+
+	return c.commitFiles(c.commitPaths(cfg, updated), c.groupMessage(updated), updated[0].Pkg.Name)
+}
+
+// CommitDedupe creates one commit for the lock files a rule's dedupe command rewrote.
+//
+// Dedupe runs after the rule's groups were committed, so its changes get a
+// commit of their own instead of being left in the working tree.
+//
+// Parameters:
+//   - cfg: Configuration used to find the rule's lock files
+//   - rule: Rule whose dedupe command ran
+//   - dirs: Lock file directories the command ran in
+//
+// Returns:
+//   - error: When staging or committing fails
+func (c *Committer) CommitDedupe(cfg *config.Config, rule string, dirs []string) error {
+	if cfg == nil || len(dirs) == 0 {
+		return nil
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		for _, lockPath := range getLockFilePaths(cfg.Rules[rule], dir) {
+			if abs, err := filepath.Abs(lockPath); err == nil {
+				lockPath = abs
+			}
+			paths = append(paths, lockPath)
+		}
+	}
+	sort.Strings(paths)
+	message := strings.ReplaceAll(dedupeCommitSubject, "{{rule}}", rule)
+	return c.commitFiles(paths, message, "dedupe of rule "+rule)
+}
+
+// commitFiles stages the given files and commits them when any of them changed.
+//
+// Parameters:
+//   - paths: Absolute paths to commit; paths git ignores are skipped
+//   - message: Commit message
+//   - what: Name used in errors and logs
+//
+// Returns:
+//   - error: When staging or committing fails
+func (c *Committer) commitFiles(paths []string, message, what string) error {
+	dir := commitDir(c.Dir)
+	paths, err := trackablePaths(dir, paths)
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", what, err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := gitCommandFunc(dir, append([]string{"add", "--"}, paths...)...); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", what, err)
+	}
+	out, err := gitCommandFunc(dir, append([]string{"status", "--porcelain", "--"}, paths...)...)
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", what, err)
+	}
+	if strings.TrimSpace(string(out)) == "" {
+		verbose.Debugf("Nothing to commit for %s", what)
+		return nil
+	}
+	if _, err := gitCommandFunc(dir, append([]string{"commit", "-m", message, "--"}, paths...)...); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", what, err)
+	}
+
+	verbose.Printf("Committed: %s\n", strings.SplitN(message, "\n", 2)[0])
+	return nil
+}
+
+// commitPaths returns the absolute manifest and lock file paths of updated packages.
+func (c *Committer) commitPaths(cfg *config.Config, updated []UpdateResult) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(path string) {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, res := range updated {
+		if res.Pkg.Source == "" {
+			continue
+		}
+		add(res.Pkg.Source)
+		if cfg != nil {
+			for _, lockPath := range getLockFilePaths(cfg.Rules[res.Pkg.Rule], packageLockDir(cfg, res.Pkg, commitDir(c.Dir))) {
+				add(lockPath)
+			}
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// trackablePaths drops the paths git ignores, such as a gitignored lock file.
+//
+// Staging an ignored path fails, and the repository chose not to track it.
+// Tracked files are never reported as ignored, so they are always kept.
+//
+// Parameters:
+//   - dir: Directory inside the git working tree
+//   - paths: Candidate paths to stage
+//
+// Returns:
+//   - []string: The paths that are not ignored, in order
+//   - error: When git cannot check the paths
+func trackablePaths(dir string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	out, err := gitCommandFunc(dir, append([]string{"check-ignore", "--"}, paths...)...)
+	if err != nil {
+		// Exit status 1 means none of the paths is ignored
+		var exitErr *exec.ExitError
+		if stderrors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return paths, nil
+		}
+		return nil, err
+	}
+
+	ignored := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			ignored[line] = true
+		}
+	}
+	kept := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if ignored[path] {
+			verbose.Debugf("Not committing %s: ignored by git", path)
+			continue
+		}
+		kept = append(kept, path)
+	}
+	return kept, nil
+}
+
+// groupMessage renders the commit message for the updated packages of a group.
+//
+// A single package uses the message template. Several packages get a subject
+// naming the group and one template line per package in the body.
+func (c *Committer) groupMessage(updated []UpdateResult) string {
+	if len(updated) == 1 {
+		return RenderCommitMessage(c.Message, updated[0])
+	}
+
+	group := updated[0].Group
+	if group == "" {
+		group = updated[0].Pkg.Rule
+	}
+	lines := []string{
+		strings.NewReplacer("{{count}}", strconv.Itoa(len(updated)), "{{group}}", group).Replace(groupCommitSubject),
+		"",
+	}
+	for _, res := range updated {
+		lines = append(lines, "- "+RenderCommitMessage(c.Message, res))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// commitDir returns dir, defaulting to the current directory.
+func commitDir(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
+
+// commitGroup commits a processed group when commit mode is enabled.
+//
+// A failed commit is recorded as a failure; the update itself stays applied.
+func (ctx *UpdateContext) commitGroup(plans []*PlannedUpdate) {
+	if ctx.Committer == nil || ctx.DryRun || len(plans) == 0 {
+		return
+	}
+	if err := ctx.Committer.CommitGroup(ctx.Cfg, plans); err != nil {
+		ctx.AppendFailure(err)
+	}
+}
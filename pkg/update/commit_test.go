@@ -0,0 +1,261 @@
+package update
+
+import (
+	stderrors "errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// initGitRepo creates a git repository with an initial commit of package.json.
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"dependencies":{"react":"17.0.0"}}`), 0o644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"config", "commit.gpgsign", "false"},
+		{"add", "."},
+		{"commit", "-q", "-m", "initial"},
+	} {
+		_, err := runGitCommand(dir, args...)
+		require.NoError(t, err)
+	}
+	return dir
+}
+
+// gitLog returns the full messages of the commits in dir, newest first.
+func gitLog(t *testing.T, dir string) []string {
+	t.Helper()
+	out, err := runGitCommand(dir, "log", "--format=%B%x00")
+	require.NoError(t, err)
+	var messages []string
+	for _, msg := range strings.Split(string(out), "\x00") {
+		if msg = strings.TrimSpace(msg); msg != "" {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// TestCheckCleanWorkTree tests the dirty working tree check.
+//
+// It verifies:
+//   - A clean working tree passes
+//   - Modified and untracked files are listed in the error
+//   - The --since last-run state file does not count as a change
+//   - A directory outside a git repository fails
+func TestCheckCleanWorkTree(t *testing.T) {
+	dir := initGitRepo(t)
+	require.NoError(t, CheckCleanWorkTree(dir))
+
+	require.NoError(t, outdated.SaveRunState(dir, time.Now()))
+	require.NoError(t, CheckCleanWorkTree(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644))
+	err := CheckCleanWorkTree(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 uncommitted change(s)")
+	assert.Contains(t, err.Error(), "package.json")
+	assert.Contains(t, err.Error(), "notes.txt")
+
+	require.Error(t, CheckCleanWorkTree(t.TempDir()))
+}
+
+// TestRenderCommitMessage tests rendering the commit message template.
+//
+// It verifies:
+//   - All placeholders are replaced
+//   - An empty template falls back to DefaultCommitMessage
+func TestRenderCommitMessage(t *testing.T) {
+	res := UpdateResult{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Target: "18.2.0", Group: "ui"}
+
+	assert.Equal(t, "chore(deps): bump react from 17.0.0 to 18.2.0", RenderCommitMessage("", res))
+	assert.Equal(t, "deps(npm/ui): react 18.2.0", RenderCommitMessage("deps({{rule}}/{{group}}): {{name}} {{to}}", res))
+}
+
+// TestCommitterCommitGroup tests committing update groups to a real git repository.
+//
+// It verifies:
+//   - A single updated package gets a commit with the templated message
+//   - Plans that are no longer updated (e.g. rolled back) are not committed
+//   - Several packages of a group share one commit listing each package
+//   - Unchanged files create no commit
+//   - A gitignored lock file is left unstaged instead of failing the commit
+func TestCommitterCommitGroup(t *testing.T) {
+	dir := initGitRepo(t)
+	manifest := filepath.Join(dir, "package.json")
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+	committer := NewCommitter(dir, "")
+
+	plan := func(name, target, status string) *PlannedUpdate {
+		pkg := testutil.NPMPackage(name, "17.0.0", "17.0.0")
+		pkg.Source = manifest
+		return &PlannedUpdate{Res: UpdateResult{Pkg: pkg, Target: target, Status: status, Group: "ui"}}
+	}
+
+	require.NoError(t, os.WriteFile(manifest, []byte(`{"dependencies":{"react":"18.2.0"}}`), 0o644))
+	require.NoError(t, committer.CommitGroup(cfg, []*PlannedUpdate{plan("react", "18.2.0", constants.StatusUpdated)}))
+	assert.Equal(t, "chore(deps): bump react from 17.0.0 to 18.2.0", gitLog(t, dir)[0])
+
+	require.NoError(t, os.WriteFile(manifest, []byte(`{"dependencies":{"react":"19.0.0"}}`), 0o644))
+	require.NoError(t, committer.CommitGroup(cfg, []*PlannedUpdate{plan("react", "19.0.0", constants.StatusFailed)}))
+	assert.Len(t, gitLog(t, dir), 2)
+
+	require.NoError(t, os.WriteFile(manifest, []byte(`{"dependencies":{"react":"19.0.0","react-dom":"19.0.0"}}`), 0o644))
+	require.NoError(t, committer.CommitGroup(cfg, []*PlannedUpdate{
+		plan("react", "19.0.0", constants.StatusUpdated),
+		plan("react-dom", "19.0.0", constants.StatusUpdated),
+	}))
+	log := gitLog(t, dir)
+	require.Len(t, log, 3)
+	assert.Equal(t, "chore(deps): bump 2 packages in the ui group\n\n"+
+		"- chore(deps): bump react from 17.0.0 to 19.0.0\n"+
+		"- chore(deps): bump react-dom from 17.0.0 to 19.0.0", log[0])
+	require.NoError(t, CheckCleanWorkTree(dir))
+
+	require.NoError(t, committer.CommitGroup(cfg, []*PlannedUpdate{plan("react", "19.0.0", constants.StatusUpdated)}))
+	assert.Len(t, gitLog(t, dir), 3)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("package-lock.json\n"), 0o644))
+	_, err := runGitCommand(dir, "add", ".gitignore")
+	require.NoError(t, err)
+	_, err = runGitCommand(dir, "commit", "-q", "-m", "ignore lock")
+	require.NoError(t, err)
+	lockCfg := testutil.NewConfig().WithRule("npm", config.PackageManagerCfg{
+		Manager:   "js",
+		LockFiles: []config.LockFileCfg{{Files: []string{"**/package-lock.json"}}},
+	}).Build()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte(`{"lockfileVersion":3}`), 0o644))
+	require.NoError(t, os.WriteFile(manifest, []byte(`{"dependencies":{"react":"19.1.0","react-dom":"19.0.0"}}`), 0o644))
+	require.NoError(t, committer.CommitGroup(lockCfg, []*PlannedUpdate{plan("react", "19.1.0", constants.StatusUpdated)}))
+	assert.Equal(t, "chore(deps): bump react from 17.0.0 to 19.1.0", gitLog(t, dir)[0])
+	require.NoError(t, CheckCleanWorkTree(dir))
+}
+
+// TestCommitterCommitGroupWorkspace tests committing a workspace member outside the process directory.
+//
+// It verifies:
+//   - The root lock file of a workspace rule is staged with the member manifest
+//     when the work dir is not the current directory
+func TestCommitterCommitGroupWorkspace(t *testing.T) {
+	dir := initGitRepo(t)
+	member := filepath.Join(dir, "packages", "app")
+	manifest := filepath.Join(member, "package.json")
+	lockFile := filepath.Join(dir, "pnpm-lock.yaml")
+	require.NoError(t, os.MkdirAll(member, 0o755))
+	require.NoError(t, os.WriteFile(manifest, []byte(`{"dependencies":{"react":"17.0.0"}}`), 0o644))
+	require.NoError(t, os.WriteFile(lockFile, []byte("react: 17.0.0\n"), 0o644))
+	_, err := runGitCommand(dir, "add", ".")
+	require.NoError(t, err)
+	_, err = runGitCommand(dir, "commit", "-q", "-m", "workspace")
+	require.NoError(t, err)
+
+	workspace := true
+	cfg := testutil.NewConfig().WithRule("pnpm", config.PackageManagerCfg{
+		Manager:   "js",
+		Workspace: &workspace,
+		LockFiles: []config.LockFileCfg{{Files: []string{"**/pnpm-lock.yaml"}}},
+	}).Build()
+	pkg := testutil.NPMPackage("react", "17.0.0", "17.0.0")
+	pkg.Rule = "pnpm"
+	pkg.Source = manifest
+
+	require.NoError(t, os.WriteFile(manifest, []byte(`{"dependencies":{"react":"18.2.0"}}`), 0o644))
+	require.NoError(t, os.WriteFile(lockFile, []byte("react: 18.2.0\n"), 0o644))
+	committer := NewCommitter(dir, "")
+	require.NoError(t, committer.CommitGroup(cfg, []*PlannedUpdate{{Res: UpdateResult{Pkg: pkg, Target: "18.2.0", Status: constants.StatusUpdated}}}))
+
+	assert.Equal(t, "chore(deps): bump react from 17.0.0 to 18.2.0", gitLog(t, dir)[0])
+	require.NoError(t, CheckCleanWorkTree(dir))
+}
+
+// TestProcessGroupedPlansCommit tests commit mode during execution.
+//
+// It verifies:
+//   - A successful group is committed once, after its lock command
+//   - A group that is rolled back is not committed
+//   - Dry runs never commit
+func TestProcessGroupedPlansCommit(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+
+	originalExec := execCommandFunc
+	execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		return nil, nil
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	var commits []string
+	originalGit := gitCommandFunc
+	gitCommandFunc = func(dir string, args ...string) ([]byte, error) {
+		switch args[0] {
+		case "status":
+			return []byte(" M package.json\n"), nil
+		case "commit":
+			commits = append(commits, args[2])
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { gitCommandFunc = originalGit })
+
+	plans := func(group string) []*PlannedUpdate {
+		var out []*PlannedUpdate
+		for _, name := range []string{"react", "react-dom"} {
+			pkg := testutil.NPMPackage(name, "1.0.0", "1.0.0")
+			pkg.Source = filepath.Join("/repo", group, "package.json")
+			out = append(out, &PlannedUpdate{
+				Res:      UpdateResult{Pkg: pkg, Target: "2.0.0", Status: constants.StatusPlanned, Group: group},
+				Cfg:      &config.UpdateCfg{Commands: "npm install"},
+				Original: "1.0.0",
+				GroupKey: group,
+			})
+		}
+		return out
+	}
+	callbacks := ExecutionCallbacks{DeriveReason: func(formats.Package, *config.Config, error, bool) string { return "" }}
+
+	run := func(dryRun bool, updater PackageUpdater, groupPlans []*PlannedUpdate) {
+		ctx := NewUpdateContext(cfg, "/repo", nil).
+			WithUpdaterFunc(updater).
+			WithFlags(dryRun, false, false).
+			WithCommitter(NewCommitter("/repo", ""))
+		var results []UpdateResult
+		ProcessGroupedPlansLive(ctx, groupPlans, &results, callbacks)
+	}
+	ok := func(formats.Package, string, *config.Config, string, bool, bool) error { return nil }
+
+	run(false, ok, plans("ui"))
+	require.Len(t, commits, 1)
+	assert.True(t, strings.HasPrefix(commits[0], "chore(deps): bump 2 packages in the ui group"))
+
+	failing := func(p formats.Package, target string, _ *config.Config, _ string, _ bool, _ bool) error {
+		if p.Name == "react-dom" && target == "2.0.0" {
+			return stderrors.New("install failed")
+		}
+		return nil
+	}
+	run(false, failing, plans("tools"))
+	assert.Len(t, commits, 1)
+
+	run(true, ok, plans("docs"))
+	assert.Len(t, commits, 1)
+}
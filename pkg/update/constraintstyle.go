@@ -0,0 +1,47 @@
+package update
+
+import (
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// styledConstraint returns the operator a rule's constraint_style writes for a package.
+//
+// Wildcards ("*") and ranges ("1.0 - 2.0", "^1 || ^2") are not a single
+// operator and version, so they keep their original spelling.
+//
+// Parameters:
+//   - p: The package being updated
+//   - ruleCfg: The package's rule configuration
+//
+// Returns:
+//   - string: The operator in the manager's spelling (may be empty for exact)
+//   - bool: false when the declared constraint is preserved
+func styledConstraint(p formats.Package, ruleCfg config.PackageManagerCfg) (string, bool) {
+	operator, ok := config.ConstraintStyleOperator(ruleCfg.Manager, ruleCfg.ConstraintStyle)
+	if !ok {
+		return "", false
+	}
+	if p.Constraint == "*" || p.Version == "" || strings.Contains(p.Version, " - ") || strings.Contains(p.Version, "||") {
+		return "", false
+	}
+	return operator, true
+}
+
+// applyConstraintStyle replaces a package's constraint with the one its rule's
+// constraint_style writes.
+//
+// Parameters:
+//   - p: The package being updated
+//   - ruleCfg: The package's rule configuration
+//
+// Returns:
+//   - formats.Package: The package with the styled constraint, or p unchanged when preserved
+func applyConstraintStyle(p formats.Package, ruleCfg config.PackageManagerCfg) formats.Package {
+	if operator, ok := styledConstraint(p, ruleCfg); ok {
+		p.Constraint = operator
+	}
+	return p
+}
@@ -0,0 +1,120 @@
+package update
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// TestConstraintStyleRoundTrip tests rewriting declared versions with a constraint_style.
+//
+// Each case writes a manifest, parses it with the built-in rule, updates the
+// package through renderDeclaredVersion, and parses the result again.
+//
+// It verifies:
+//   - preserve (and an unset style) keeps the original operator for npm, composer and pip
+//   - caret, tilde and exact normalize the operator in the manager's spelling
+//     (composer "~", pip "~=" and "==", npm "" for exact)
+//   - The rewritten manifest parses back to the target version and the written operator
+func TestConstraintStyleRoundTrip(t *testing.T) {
+	var defaults config.Config
+	require.NoError(t, yaml.Unmarshal([]byte(config.GetDefaultConfig()), &defaults))
+
+	tests := []struct {
+		name       string
+		rule       string
+		file       string
+		content    string
+		pkg        string
+		style      string
+		target     string
+		want       string
+		constraint string
+	}{
+		{"npm preserve", "npm", "package.json", `{"dependencies":{"react":"~17.0.0"}}`, "react", config.ConstraintStylePreserve, "17.0.2", `"react": "~17.0.2"`, "~"},
+		{"npm unset", "npm", "package.json", `{"dependencies":{"react":">=17.0.0"}}`, "react", "", "18.2.0", `"react": ">=18.2.0"`, ">="},
+		{"npm caret", "npm", "package.json", `{"dependencies":{"react":"~17.0.0"}}`, "react", config.ConstraintStyleCaret, "18.2.0", `"react": "^18.2.0"`, "^"},
+		{"npm tilde", "npm", "package.json", `{"dependencies":{"react":"17.0.0"}}`, "react", config.ConstraintStyleTilde, "18.2.0", `"react": "~18.2.0"`, "~"},
+		{"npm exact", "npm", "package.json", `{"dependencies":{"react":"^17.0.0"}}`, "react", config.ConstraintStyleExact, "18.2.0", `"react": "18.2.0"`, ""},
+		{"composer preserve", "composer", "composer.json", `{"require":{"monolog/monolog":"^2.0"}}`, "monolog/monolog", config.ConstraintStylePreserve, "2.9.1", `"monolog/monolog": "^2.9.1"`, "^"},
+		{"composer tilde", "composer", "composer.json", `{"require":{"monolog/monolog":"^2.0"}}`, "monolog/monolog", config.ConstraintStyleTilde, "2.9.1", `"monolog/monolog": "~2.9.1"`, "~"},
+		{"composer exact", "composer", "composer.json", `{"require":{"monolog/monolog":"~2.0"}}`, "monolog/monolog", config.ConstraintStyleExact, "2.9.1", `"monolog/monolog": "2.9.1"`, ""},
+		{"pip preserve", "requirements", "requirements.txt", "requests>=2.28.0\n", "requests", config.ConstraintStylePreserve, "2.31.0", "requests>=2.31.0", ">="},
+		{"pip tilde", "requirements", "requirements.txt", "requests>=2.28.0\n", "requests", config.ConstraintStyleTilde, "2.31.0", "requests~=2.31.0", "~="},
+		{"pip exact", "requirements", "requirements.txt", "requests~=2.28.0\n", "requests", config.ConstraintStyleExact, "2.31.0", "requests==2.31.0", "=="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleCfg := defaults.Rules[tt.rule]
+			ruleCfg.ConstraintStyle = tt.style
+			cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{tt.rule: ruleCfg}}
+
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+			pkg := parseStyledPackage(t, ruleCfg, []byte(tt.content), tt.pkg)
+			pkg.Rule, pkg.Source = tt.rule, path
+
+			_, updated, err := renderDeclaredVersion(pkg, tt.target, cfg)
+			require.NoError(t, err)
+			assert.Contains(t, string(updated), tt.want)
+
+			reparsed := parseStyledPackage(t, ruleCfg, updated, tt.pkg)
+			assert.Equal(t, tt.constraint, reparsed.Constraint)
+			assert.Equal(t, tt.target, reparsed.Version)
+		})
+	}
+}
+
+// TestStyledConstraint tests choosing the operator a constraint_style writes.
+//
+// It verifies:
+//   - Wildcards and ranges keep their original spelling
+//   - Styles the manager has no operator for fall back to preserve
+func TestStyledConstraint(t *testing.T) {
+	ruleCfg := config.PackageManagerCfg{Manager: "js", ConstraintStyle: config.ConstraintStyleCaret}
+
+	operator, ok := styledConstraint(formats.Package{Constraint: "~", Version: "1.2.0"}, ruleCfg)
+	assert.True(t, ok)
+	assert.Equal(t, "^", operator)
+
+	for _, p := range []formats.Package{
+		{Constraint: "*", Version: "*"},
+		{Version: "1.0.0 - 2.0.0"},
+		{Version: "^1.0.0 || ^2.0.0"},
+	} {
+		_, ok = styledConstraint(p, ruleCfg)
+		assert.False(t, ok, p.Version)
+	}
+
+	ruleCfg.Manager = "python"
+	_, ok = styledConstraint(formats.Package{Constraint: ">=", Version: "1.2.0"}, ruleCfg)
+	assert.False(t, ok)
+}
+
+// parseStyledPackage parses a manifest with a rule and returns the named package.
+func parseStyledPackage(t *testing.T, ruleCfg config.PackageManagerCfg, content []byte, name string) formats.Package {
+	t.Helper()
+	var parser interface {
+		Parse([]byte, *config.PackageManagerCfg) ([]formats.Package, error)
+	} = &formats.JSONParser{}
+	if ruleCfg.Format == "raw" {
+		parser = &formats.RawParser{}
+	}
+	packages, err := parser.Parse(content, &ruleCfg)
+	require.NoError(t, err)
+	for _, p := range packages {
+		if p.Name == name {
+			return p
+		}
+	}
+	t.Fatalf("package %s not found in %s", name, content)
+	return formats.Package{}
+}
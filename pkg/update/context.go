@@ -1,6 +1,11 @@
 package update
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/outdated"
@@ -35,6 +40,12 @@ type UpdateContext struct {
 	ContinueOnError bool
 	SkipLockRun     bool
 	IncrementalMode bool // Force incremental updates (one version step at a time)
+	Staged          bool // Walk each package through every release up to its target
+	StrictLock      bool // Fail when the lock file does not hold the target version after the lock command
+	GroupCommands   bool // Run one lock command per rule instead of per group, for every rule
+	Dedupe          bool // Run each rule's update.dedupe command once its updates succeed
+	// GroupConcurrency is the maximum number of update groups of a rule processed at once; <= 1 is sequential
+	GroupConcurrency int
 
 	// Version selection flags (also used for display formatting)
 	Selection outdated.UpdateSelectionFlags
@@ -44,11 +55,21 @@ type UpdateContext struct {
 	Failures    []error
 	Baseline    map[string]VersionSnapshot
 
+	// RuleOutcomes records per-rule successes and failures in processing order
+	RuleOutcomes []RuleOutcome
+
 	// Display
 	Table *output.Table
 
 	// System tests
-	SystemTestRunner *systemtest.Runner
+	SystemTestRunner   *systemtest.Runner
+	SystemTestFailures []SystemTestFailure // Non-critical failures collected across all groups
+
+	// Committer commits each successful update group to git; nil disables commit mode
+	Committer *Committer
+
+	// Backups holds the content of each group's files for byte-exact rollback; nil disables it
+	Backups *ContentBackups
 
 	// Functions
 	ReloadList func() ([]formats.Package, error)
@@ -61,6 +82,30 @@ type UpdateContext struct {
 
 	// SkipSystemTests flag (set by CLI)
 	SkipSystemTests bool
+
+	// Ctx carries cancellation (e.g. SIGINT) into the execution loop; nil means never cancelled
+	Ctx context.Context
+
+	// mu guards Failures, SystemTestFailures, Baseline and cancelRecorded while groups run in parallel
+	mu sync.Mutex
+
+	// cancelRecorded ensures a cancellation is appended to Failures only once
+	cancelRecorded bool
+
+	// targets holds the keys of packages with a pending update in this run
+	targets map[string]bool
+
+	// DryRunCommands lists the lock commands a dry run skipped, in processing order
+	DryRunCommands []DryRunCommand
+
+	// DedupeSteps lists the dedupe commands run after each rule's updates, in processing order
+	DedupeSteps []DedupeStep
+
+	// dryRunGuarded records that guardDryRun already wrapped UpdaterFunc
+	dryRunGuarded bool
+
+	// unguardedUpdater is UpdaterFunc before guardDryRun wrapped it; only dry-run verification uses it
+	unguardedUpdater PackageUpdater
 }
 
 // NewUpdateContext creates a new UpdateContext with the given parameters.
@@ -123,6 +168,100 @@ func (ctx *UpdateContext) WithIncrementalMode(incremental bool) *UpdateContext {
 	return ctx
 }
 
+// WithStaged sets the staged mode flag and returns the context for chaining.
+//
+// In staged mode each package is updated one release at a time up to its
+// target, with the lock command, validation, and system tests run after every
+// step. The first failing step is rolled back and the last good version kept.
+func (ctx *UpdateContext) WithStaged(staged bool) *UpdateContext {
+	ctx.Staged = staged
+	return ctx
+}
+
+// WithGroupCommands sets the group commands flag and returns the context for chaining.
+//
+// With group commands every rule behaves as if it set update.group_commands:
+// the manifest edits of all its groups are applied first and its lock command
+// runs once for the whole run.
+func (ctx *UpdateContext) WithGroupCommands(group bool) *UpdateContext {
+	ctx.GroupCommands = group
+	return ctx
+}
+
+// WithDedupe sets the dedupe flag and returns the context for chaining.
+//
+// With dedupe, each rule's update.dedupe command runs once after the rule's
+// updates succeed (see runRuleDedupe). Dry runs and --skip-lock never run it.
+func (ctx *UpdateContext) WithDedupe(dedupe bool) *UpdateContext {
+	ctx.Dedupe = dedupe
+	return ctx
+}
+
+// groupsRuleCommands reports whether a rule runs one lock command for all its groups.
+//
+// Batching needs a lock command, so it is off for --skip-lock and staged mode,
+// where each group keeps its own processing. Dry runs do not batch either
+// (see ruleBatches) but still report the batched command (see PlanDryRunCommands).
+// A lock command naming {{package}}, such as bun update {{package}}, would run
+// once without a package, so rules using one keep a command per group.
+//
+// Parameters:
+//   - rule: The rule name
+//   - plans: The rule's planned updates
+//
+// Returns:
+//   - bool: true when the --group-commands flag or the rule's update.group_commands is set
+//     and no plan's lock command uses {{package}}
+func (ctx *UpdateContext) groupsRuleCommands(rule string, plans []*PlannedUpdate) bool {
+	if ctx.SkipLockRun || ctx.Staged {
+		return false
+	}
+	if !ctx.GroupCommands {
+		if ctx.Cfg == nil {
+			return false
+		}
+		ruleCfg, ok := ctx.Cfg.Rules[rule]
+		if !ok || ruleCfg.Update == nil || !ruleCfg.Update.GroupCommands {
+			return false
+		}
+	}
+	for _, plan := range plans {
+		if plan.Cfg != nil && strings.Contains(plan.Cfg.Commands, "{{package}}") {
+			verbose.Printf("Rule %s: lock command uses {{package}}, running it per group\n", rule)
+			return false
+		}
+	}
+	return true
+}
+
+// WithStrictLock sets the strict lock flag and returns the context for chaining.
+//
+// In strict mode every applied package must be found in its lock file at the
+// target version after the lock command; otherwise the package fails and a
+// group sharing a lock command is rolled back.
+func (ctx *UpdateContext) WithStrictLock(strict bool) *UpdateContext {
+	ctx.StrictLock = strict
+	return ctx
+}
+
+// WithGroupConcurrency sets how many update groups of a rule may run at once and returns the context for chaining.
+//
+// Groups whose packages share a lock file are still serialized. Values of 1
+// or less, staged mode, and system tests after each update keep groups sequential.
+func (ctx *UpdateContext) WithGroupConcurrency(n int) *UpdateContext {
+	ctx.GroupConcurrency = n
+	return ctx
+}
+
+// WithCommitter enables commit mode and returns the context for chaining.
+//
+// After each group finishes, including any rollback, its packages that are
+// still updated are committed together. Dry runs never commit.
+func (ctx *UpdateContext) WithCommitter(c *Committer) *UpdateContext {
+	ctx.Committer = c
+	return ctx
+}
+
 // WithDeriveUnsupportedReason sets the function to derive unsupported reasons.
 func (ctx *UpdateContext) WithDeriveUnsupportedReason(fn func(p formats.Package, cfg *config.Config, err error, latestMissing bool) string) *UpdateContext {
 	ctx.DeriveUnsupportedReason = fn
@@ -135,6 +274,52 @@ func (ctx *UpdateContext) WithUpdaterFunc(fn func(p formats.Package, target stri
 	return ctx
 }
 
+// WithContext sets the cancellation context and returns the context for chaining.
+//
+// Once ctx is cancelled, the execution loop stops scheduling new package
+// updates between plans. Updates that already completed keep their results,
+// and a group that was only partially applied under a shared lock command is
+// rolled back like any other group failure.
+func (ctx *UpdateContext) WithContext(c context.Context) *UpdateContext {
+	ctx.Ctx = c
+	return ctx
+}
+
+// Err returns the cancellation error of the attached context, or nil when no
+// context is set or it has not been cancelled.
+func (ctx *UpdateContext) Err() error {
+	if ctx.Ctx == nil {
+		return nil
+	}
+	return ctx.Ctx.Err()
+}
+
+// checkCancelled reports whether the attached context has been cancelled.
+//
+// The first time a cancellation is observed it is recorded as a failure so
+// the command exits non-zero; later calls only report it.
+//
+// Returns:
+//   - error: The cancellation error wrapped as "update cancelled", or nil if not cancelled
+func (ctx *UpdateContext) checkCancelled() error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+	cancelErr := fmt.Errorf("update cancelled: %w", err)
+	ctx.mu.Lock()
+	first := !ctx.cancelRecorded
+	if first {
+		ctx.cancelRecorded = true
+		ctx.Failures = append(ctx.Failures, cancelErr)
+	}
+	ctx.mu.Unlock()
+	if first {
+		verbose.Infof("Update cancelled; no further package updates will be started")
+	}
+	return cancelErr
+}
+
 // ShouldRunSystemTestsAfterEach returns true if system tests should run after each update.
 func (ctx *UpdateContext) ShouldRunSystemTestsAfterEach() bool {
 	return ctx.SystemTestRunner != nil && ctx.SystemTestRunner.ShouldRunAfterEach() && !ctx.SkipSystemTests
@@ -143,16 +328,27 @@ func (ctx *UpdateContext) ShouldRunSystemTestsAfterEach() bool {
 // AppendFailure adds an error to the failures slice.
 func (ctx *UpdateContext) AppendFailure(err error) {
 	if err != nil {
+		ctx.mu.Lock()
+		defer ctx.mu.Unlock()
 		ctx.Failures = append(ctx.Failures, err)
 	}
 }
 
-// SnapshotVersions creates a map of package keys to their version snapshots.
+// appendSystemTestFailures adds system test failures collected by a group.
+func (ctx *UpdateContext) appendSystemTestFailures(failures ...SystemTestFailure) {
+	if len(failures) > 0 {
+		ctx.mu.Lock()
+		defer ctx.mu.Unlock()
+		ctx.SystemTestFailures = append(ctx.SystemTestFailures, failures...)
+	}
+}
+
+// SnapshotVersions creates a map of package source keys to their version snapshots.
 // This captures the baseline state before updates for drift detection.
 func SnapshotVersions(packages []formats.Package) map[string]VersionSnapshot {
 	snapshots := make(map[string]VersionSnapshot)
 	for _, p := range packages {
-		key := PackageKey(p)
+		key := PackageSourceKey(p)
 		snapshots[key] = VersionSnapshot{Version: p.Version, Installed: p.InstalledVersion}
 	}
 	verbose.Debugf("Baseline snapshot captured: %d packages", len(snapshots))
@@ -163,3 +359,11 @@ func SnapshotVersions(packages []formats.Package) map[string]VersionSnapshot {
 func PackageKey(p formats.Package) string {
 	return p.Rule + "|" + p.PackageType + "|" + p.Type + "|" + p.Name
 }
+
+// PackageSourceKey generates a key for a package in a specific manifest.
+//
+// Modules of a workspace and manifests of a monorepo can declare the same
+// dependency at different versions, so snapshots and targets are kept per manifest.
+func PackageSourceKey(p formats.Package) string {
+	return PackageKey(p) + "|" + p.Source
+}
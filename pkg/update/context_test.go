@@ -1,6 +1,7 @@
 package update
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -194,6 +195,36 @@ func TestUpdateContextWithIncrementalMode(t *testing.T) {
 	})
 }
 
+func TestUpdateContextWithContext(t *testing.T) {
+	t.Run("reports no error without a context", func(t *testing.T) {
+		ctx := &UpdateContext{}
+
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("sets context and reports cancellation", func(t *testing.T) {
+		ctx := &UpdateContext{}
+		c, cancel := context.WithCancel(context.Background())
+
+		result := ctx.WithContext(c)
+
+		assert.Same(t, ctx, result)
+		assert.NoError(t, ctx.Err())
+		cancel()
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("records cancellation failure once", func(t *testing.T) {
+		c, cancel := context.WithCancel(context.Background())
+		cancel()
+		ctx := NewUpdateContext(nil, "", nil).WithContext(c)
+
+		assert.Error(t, ctx.checkCancelled())
+		assert.Error(t, ctx.checkCancelled())
+		assert.Len(t, ctx.Failures, 1)
+	})
+}
+
 func TestUpdateContextWithDeriveUnsupportedReason(t *testing.T) {
 	t.Run("sets derive function", func(t *testing.T) {
 		ctx := &UpdateContext{}
@@ -357,12 +388,12 @@ func TestSnapshotVersions(t *testing.T) {
 		snapshots := SnapshotVersions(packages)
 		assert.Len(t, snapshots, 2)
 
-		reactKey := PackageKey(packages[0])
+		reactKey := PackageSourceKey(packages[0])
 		assert.Contains(t, snapshots, reactKey)
 		assert.Equal(t, "17.0.0", snapshots[reactKey].Version)
 		assert.Equal(t, "17.0.0", snapshots[reactKey].Installed)
 
-		vueKey := PackageKey(packages[1])
+		vueKey := PackageSourceKey(packages[1])
 		assert.Contains(t, snapshots, vueKey)
 		assert.Equal(t, "3.0.0", snapshots[vueKey].Version)
 		assert.Equal(t, "3.0.0", snapshots[vueKey].Installed)
@@ -391,7 +422,7 @@ func TestSnapshotVersions(t *testing.T) {
 		}
 
 		snapshots := SnapshotVersions(packages)
-		key := PackageKey(packages[0])
+		key := PackageSourceKey(packages[0])
 		assert.Equal(t, "4.17.0", snapshots[key].Version)
 		assert.Equal(t, "4.17.21", snapshots[key].Installed)
 	})
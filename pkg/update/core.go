@@ -4,6 +4,7 @@
 package update
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/ratelimit"
 	"github.com/ajxudir/goupdate/pkg/verbose"
 	"github.com/ajxudir/goupdate/pkg/warnings"
 )
@@ -168,6 +170,69 @@ func RunGroupLockCommand(cfg *config.UpdateCfg, workDir string, withAllDeps bool
 	return err
 }
 
+// groupLockDirs returns the directories a group's lock command runs in.
+//
+// The lock command runs next to the group's manifests, so a group in a
+// monorepo subdirectory locks that subdirectory rather than the repository root.
+// Manifests in several directories get one lock run per directory, and the
+// members of a workspace get a single run in the workspace root.
+//
+// Parameters:
+//   - cfg: Configuration holding the group's rule; nil uses the manifest directories
+//   - workDir: Fallback directory for packages without a manifest path
+//   - plans: The group's applied plans
+//
+// Returns:
+//   - []string: Distinct directories in plan order
+func groupLockDirs(cfg *config.Config, workDir string, plans []*PlannedUpdate) []string {
+	if workDir == "" {
+		workDir = "."
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, plan := range plans {
+		dir := packageLockDir(cfg, plan.Res.Pkg, workDir)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		dirs = append(dirs, workDir)
+	}
+	return dirs
+}
+
+// runGroupLock runs a group's lock command in each of its lock directories.
+//
+// Parameters:
+//   - cfg: Update configuration of the group
+//   - rules: Configuration holding the group's rule, used to find workspace roots
+//   - workDir: Fallback directory for packages without a manifest path
+//   - plans: The group's applied plans
+//   - withAllDeps: Whether transitive dependencies may be updated
+//
+// Returns:
+//   - error: The first lock failure; remaining directories are not locked
+func runGroupLock(cfg *config.UpdateCfg, rules *config.Config, workDir string, plans []*PlannedUpdate, withAllDeps bool) error {
+	var limiter *ratelimit.Limiter
+	if len(plans) > 0 {
+		limiter = ratelimit.ForRule(rules, plans[0].Res.Pkg.Rule)
+	}
+
+	for _, dir := range groupLockDirs(rules, workDir, plans) {
+		// Group lock commands fetch from the same registry as version lookups
+		if err := limiter.Wait(context.Background()); err != nil {
+			return err
+		}
+		if err := RunGroupLockCommand(cfg, dir, withAllDeps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // fileBackup stores the original content of a file for rollback
 type fileBackup struct {
 	path    string
@@ -263,9 +328,12 @@ func restoreBackups(backups []fileBackup) []error {
 //
 // It performs the following operations:
 //   - Step 1: Iterate through all lock file configurations in the rule
-//   - Step 2: For each pattern, use glob matching to find matching files
+//   - Step 2: For each pattern, use glob matching to find matching files in scopeDir
 //   - Step 3: Collect all matched file paths
 //
+// A leading "**/" finds lock files anywhere during detection; from a scope
+// directory the lock file is looked up in that directory itself.
+//
 // Parameters:
 //   - ruleCfg: Package manager configuration containing lock file patterns
 //   - scopeDir: Base directory to resolve lock file patterns from
@@ -277,7 +345,7 @@ func getLockFilePaths(ruleCfg config.PackageManagerCfg, scopeDir string) []strin
 	for _, lockCfg := range ruleCfg.LockFiles {
 		for _, pattern := range lockCfg.Files {
 			// Try to find matching files
-			matches, err := filepath.Glob(filepath.Join(scopeDir, pattern))
+			matches, err := filepath.Glob(scopedLockPattern(scopeDir, pattern))
 			if err == nil && len(matches) > 0 {
 				paths = append(paths, matches...)
 			}
@@ -286,6 +354,42 @@ func getLockFilePaths(ruleCfg config.PackageManagerCfg, scopeDir string) []strin
 	return paths
 }
 
+// scopedLockPattern joins a lock file pattern to the directory it is looked up in.
+//
+// Parameters:
+//   - scopeDir: The lock directory
+//   - pattern: Lock file pattern from the rule, e.g. "**/package-lock.json"
+//
+// Returns:
+//   - string: The pattern below scopeDir, without a leading "**/"
+func scopedLockPattern(scopeDir, pattern string) string {
+	return filepath.Join(scopeDir, strings.TrimPrefix(pattern, "**/"))
+}
+
+// packageLockDir returns the directory holding a package's lock files, where its
+// lock command runs.
+//
+// This is the manifest directory, or the workspace root for workspace rules
+// (see config.LockScopeDir).
+//
+// Parameters:
+//   - cfg: Configuration holding the package's rule; nil uses the manifest directory
+//   - p: The package
+//   - workDir: Fallback directory for packages without a manifest path
+//
+// Returns:
+//   - string: The lock directory
+func packageLockDir(cfg *config.Config, p formats.Package, workDir string) string {
+	dir := workDir
+	if p.Source != "" {
+		dir = filepath.Dir(p.Source)
+	}
+	if cfg == nil {
+		return dir
+	}
+	return config.LockScopeDir(cfg.Rules[p.Rule], dir, workDir)
+}
+
 // UpdatePackage attempts to update a package to the provided target version.
 // When dryRun is true, no files or lock commands are executed.
 // Flow: 1) Backup manifest and lock files 2) Update declared version 3) Run lock command 4) Rollback on failure
@@ -303,6 +407,8 @@ func UpdatePackage(p formats.Package, target string, cfg *config.Config, workDir
 	if !ruleOk {
 		return fmt.Errorf("rule configuration missing for %s", p.Rule)
 	}
+	// Lock commands receive the constraint the manifest is rewritten with
+	p = applyConstraintStyle(p, ruleCfg)
 
 	scopeDir := workDir
 	if p.Source != "" {
@@ -314,6 +420,12 @@ func UpdatePackage(p formats.Package, target string, cfg *config.Config, workDir
 	if scopeDir == "" {
 		scopeDir = "."
 	}
+	// Workspace members lock in the workspace root
+	rootDir := workDir
+	if rootDir == "" {
+		rootDir = cfg.WorkingDir
+	}
+	scopeDir = config.LockScopeDir(ruleCfg, scopeDir, rootDir)
 
 	// Read original manifest content for rollback if needed
 	originalContent, readErr := readFileFunc(p.Source)
@@ -337,13 +449,18 @@ func UpdatePackage(p formats.Package, target string, cfg *config.Config, workDir
 	}
 
 	// Check if this package needs -W flag (with all dependencies)
-	withAllDeps := ruleCfg.ShouldUpdateWithAllDependencies(p.Name)
+	withAllDeps := allowsTransitive(cfg, p)
 
 	runLockCommand := func(version string) error {
 		if strings.TrimSpace(effectiveCfg.Commands) == "" {
 			return &errors.UnsupportedError{Reason: fmt.Sprintf("lock update missing for %s", p.Rule)}
 		}
 
+		// Lock commands fetch from the same registry as version lookups
+		if err := ratelimit.ForRule(cfg, p.Rule).Wait(context.Background()); err != nil {
+			return err
+		}
+
 		if _, err := execCommandFunc(effectiveCfg, p.Name, version, p.Constraint, scopeDir, withAllDeps); err != nil {
 			verbose.Printf("Lock command failed for %s: %v\n", p.Name, err)
 			return err
@@ -393,6 +510,11 @@ func UpdatePackage(p formats.Package, target string, cfg *config.Config, workDir
 		return nil
 	}
 
+	// Self-pinning manifests are their own lock file; there is nothing to regenerate
+	if ruleCfg.SelfPinning && strings.TrimSpace(effectiveCfg.Commands) == "" {
+		return nil
+	}
+
 	// Step 2: Run lock command to regenerate lock file
 	if err := runLockCommand(target); err != nil {
 		return performRollback(err)
@@ -419,40 +541,17 @@ func UpdatePackage(p formats.Package, target string, cfg *config.Config, workDir
 // Returns:
 //   - error: Returns error if rule configuration is missing, file read/write fails, or update fails; returns nil on success
 func updateDeclaredVersion(p formats.Package, target string, cfg *config.Config, scopeDir string, dryRun bool) error {
-	ruleCfg, ok := cfg.Rules[p.Rule]
-	if !ok {
-		return fmt.Errorf("rule configuration missing for %s", p.Rule)
-	}
-
 	// Capture file modification time before read for drift detection
 	var readModTime int64
 	if info, statErr := statFileFunc(p.Source); statErr == nil {
 		readModTime = info.ModTime().UnixNano()
 	}
 
-	content, err := readFileFunc(p.Source)
-	if err != nil {
-		return fmt.Errorf("failed to read %s: %w", p.Source, err)
-	}
-
-	// Get updater from registry (supports extensibility for new formats)
-	updater, err := getUpdaterForFormat(ruleCfg.Format)
+	_, updated, err := renderDeclaredVersion(p, target, cfg)
 	if err != nil {
 		return err
 	}
 
-	updated, err := updater.UpdateVersion(content, p, ruleCfg, target)
-	if err != nil {
-		return err
-	}
-
-	// Preserve trailing newline from original file
-	if len(content) > 0 && content[len(content)-1] == '\n' {
-		if len(updated) == 0 || updated[len(updated)-1] != '\n' {
-			updated = append(updated, '\n')
-		}
-	}
-
 	if dryRun {
 		return nil
 	}
@@ -474,3 +573,49 @@ func updateDeclaredVersion(p formats.Package, target string, cfg *config.Config,
 	_ = scopeDir // reserved for future scope-based updates
 	return nil
 }
+
+// renderDeclaredVersion computes the manifest content with the declared version
+// moved to target, without writing it. The rule's constraint_style decides the
+// operator written in front of target.
+//
+// Parameters:
+//   - p: The package to update with source file and version information
+//   - target: The target version to update to
+//   - cfg: Global configuration containing rule definitions
+//
+// Returns:
+//   - []byte: The current manifest content
+//   - []byte: The manifest content after the update, keeping a trailing newline
+//   - error: Returns error if rule configuration is missing, the file cannot be read, or the updater fails
+func renderDeclaredVersion(p formats.Package, target string, cfg *config.Config) ([]byte, []byte, error) {
+	ruleCfg, ok := cfg.Rules[p.Rule]
+	if !ok {
+		return nil, nil, fmt.Errorf("rule configuration missing for %s", p.Rule)
+	}
+	p = applyConstraintStyle(p, ruleCfg)
+
+	content, err := readFileFunc(p.Source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", p.Source, err)
+	}
+
+	// Get updater from registry (supports extensibility for new formats)
+	updater, err := getUpdaterForFormat(ruleCfg.Format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated, err := updater.UpdateVersion(content, p, ruleCfg, target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Preserve trailing newline from original file
+	if len(content) > 0 && content[len(content)-1] == '\n' {
+		if len(updated) == 0 || updated[len(updated)-1] != '\n' {
+			updated = append(updated, '\n')
+		}
+	}
+
+	return content, updated, nil
+}
@@ -0,0 +1,129 @@
+package update
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/cmdexec"
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// DedupeStep is the outcome of a rule's dedupe command in one directory.
+//
+// Fields:
+//   - Rule: Rule whose update.dedupe command ran
+//   - Dir: Lock file directory the command ran in
+//   - Command: The configured command
+//   - Critical: Whether a failure rolls back the rule's updates
+//   - Err: The command failure; nil when the command succeeded
+//   - RolledBack: Number of packages rolled back because of a critical failure
+//   - Duration: Time spent running the command
+type DedupeStep struct {
+	Rule       string
+	Dir        string
+	Command    string
+	Critical   bool
+	Err        error
+	RolledBack int
+	Duration   time.Duration
+}
+
+// execDedupeFunc runs a dedupe command; tests stub it.
+var execDedupeFunc = func(cfg *config.DedupeCfg, dir string) ([]byte, error) {
+	return cmdexec.Execute(cfg.Commands, cfg.Env, dir, cfg.TimeoutSeconds, nil)
+}
+
+// runRuleDedupe runs a rule's dedupe command after its updates succeeded.
+//
+// Updating packages one group at a time leaves transitive duplicates in the
+// lock file that a single dedupe pass folds together. It is gated like the
+// lock commands: nothing runs in a dry run or with --skip-lock.
+//
+// It performs the following operations:
+//   - Step 1: Skip unless --dedupe is set, the rule configures update.dedupe,
+//     a package was updated and the rule recorded no failure
+//   - Step 2: Run the command once in every lock file directory of the updated packages
+//   - Step 3: Record each run in UpdateContext.DedupeSteps
+//   - Step 4: On failure, warn; with critical: true, record the failure and roll
+//     back every updated package of the rule (see RollbackBatch)
+//   - Step 5: In commit mode, commit the lock files of the directories that were
+//     deduped, since the rule's groups are already committed
+//
+// The update command refuses --commit with a critical dedupe command, whose
+// rollback would undo committed files.
+//
+// Parameters:
+//   - ctx: Update context with configuration and state
+//   - plans: The rule's plans
+//   - results: The rule's results; entries of rolled back packages are updated in place
+//   - failuresBefore: Length of ctx.Failures before the rule was processed
+func runRuleDedupe(ctx *UpdateContext, plans []*PlannedUpdate, results []UpdateResult, failuresBefore int) {
+	if !ctx.Dedupe || ctx.DryRun || ctx.SkipLockRun || ctx.Cfg == nil || len(plans) == 0 {
+		return
+	}
+	rule := planRule(plans[0])
+	ruleCfg, ok := ctx.Cfg.Rules[rule]
+	if !ok || ruleCfg.Update == nil || ruleCfg.Update.Dedupe == nil || strings.TrimSpace(ruleCfg.Update.Dedupe.Commands) == "" {
+		return
+	}
+	if len(ctx.Failures) > failuresBefore {
+		verbose.Printf("Dedupe skipped for rule %s: the rule's updates did not all succeed\n", rule)
+		return
+	}
+
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, plan := range plans {
+		if plan.Res.Status != constants.StatusUpdated {
+			continue
+		}
+		dir := packageLockDir(ctx.Cfg, plan.Res.Pkg, ctx.WorkDir)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+
+	dedupe := ruleCfg.Update.Dedupe
+	var deduped []string
+	for _, dir := range dirs {
+		step := DedupeStep{Rule: rule, Dir: dir, Command: strings.TrimSpace(dedupe.Commands), Critical: dedupe.Critical}
+		start := time.Now()
+		out, err := execDedupeFunc(dedupe, dir)
+		step.Duration = time.Since(start)
+		if err != nil {
+			step.Err = fmt.Errorf("dedupe failed for rule %s in %s: %w", rule, dir, err)
+			verbose.Printf("Dedupe FAILED for rule %s in %s: %v\n%s\n", rule, dir, err, strings.TrimSpace(string(out)))
+		} else {
+			verbose.Debugf("Dedupe for rule %s in %s completed in %s", rule, dir, step.Duration.Round(time.Millisecond))
+		}
+
+		if step.Err != nil && step.Critical {
+			ctx.AppendFailure(step.Err)
+			step.RolledBack, err = RollbackBatch(ctx, plans, results, step.Err)
+			if err != nil {
+				ctx.AppendFailure(fmt.Errorf("rollback after failed dedupe of rule %s: %w", rule, err))
+			}
+			ctx.DedupeSteps = append(ctx.DedupeSteps, step)
+			return
+		}
+		if step.Err != nil {
+			warnings.Warn(warnings.CodeDedupeFailed, "", "⚠️ %v; the rule's updates are kept", step.Err)
+		} else {
+			deduped = append(deduped, dir)
+		}
+		ctx.DedupeSteps = append(ctx.DedupeSteps, step)
+	}
+
+	if ctx.Committer != nil {
+		if err := ctx.Committer.CommitDedupe(ctx.Cfg, rule, deduped); err != nil {
+			ctx.AppendFailure(err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package update
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/output"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// TestRunRuleDedupe tests running a rule's dedupe command after its updates.
+//
+// It verifies:
+//   - Nothing runs without --dedupe, in a dry run, with --skip-lock, or after a failed update
+//   - The command runs once per lock file directory of the updated packages
+//   - A failure warns and keeps the updates by default
+//   - Commit mode commits the lock files the command rewrote
+//   - A critical failure is recorded and rolls back the rule's updates
+//   - DedupeEntries reports each step's status
+func TestRunRuleDedupe(t *testing.T) {
+	newCfg := func(critical bool) *config.Config {
+		return testutil.NewConfig().WithRule("npm", config.PackageManagerCfg{
+			Update: &config.UpdateCfg{Dedupe: &config.DedupeCfg{Commands: "npm dedupe", Critical: critical}},
+		}).Build()
+	}
+	newPlans := func() []*PlannedUpdate {
+		return []*PlannedUpdate{
+			{Res: UpdateResult{Pkg: testutil.NewPackage("react").WithRule("npm").WithVersion("18.0.0").WithSource("/test/web/package.json").Build(), Status: constants.StatusUpdated, Target: "18.0.0"}, Original: "17.0.0"},
+			{Res: UpdateResult{Pkg: testutil.NewPackage("vue").WithRule("npm").WithVersion("3.0.0").WithSource("/test/app/package.json").Build(), Status: constants.StatusUpdated, Target: "3.0.0"}, Original: "2.7.0"},
+			{Res: UpdateResult{Pkg: testutil.NewPackage("lodash").WithRule("npm").WithVersion("4.17.21").WithSource("/test/web/package.json").Build(), Status: constants.StatusUpdated, Target: "4.17.21"}, Original: "4.17.20"},
+			{Res: UpdateResult{Pkg: testutil.NewPackage("axios").WithRule("npm").WithVersion("1.0.0").WithSource("/test/api/package.json").Build(), Status: constants.StatusUpToDate}, Original: "1.0.0"},
+		}
+	}
+	stubExec := func(t *testing.T, err error) *[]string {
+		t.Helper()
+		var dirs []string
+		orig := execDedupeFunc
+		execDedupeFunc = func(cfg *config.DedupeCfg, dir string) ([]byte, error) {
+			dirs = append(dirs, dir)
+			return nil, err
+		}
+		t.Cleanup(func() { execDedupeFunc = orig })
+		return &dirs
+	}
+
+	t.Run("skipped", func(t *testing.T) {
+		dirs := stubExec(t, nil)
+		for name, ctx := range map[string]*UpdateContext{
+			"flag off":  NewUpdateContext(newCfg(false), "/test", nil),
+			"dry run":   NewUpdateContext(newCfg(false), "/test", nil).WithDedupe(true).WithFlags(true, false, false),
+			"skip lock": NewUpdateContext(newCfg(false), "/test", nil).WithDedupe(true).WithFlags(false, false, true),
+			"no config": NewUpdateContext(testutil.NewConfig().Build(), "/test", nil).WithDedupe(true),
+		} {
+			runRuleDedupe(ctx, newPlans(), nil, 0)
+			assert.Empty(t, ctx.DedupeSteps, name)
+		}
+
+		ctx := NewUpdateContext(newCfg(false), "/test", nil).WithDedupe(true)
+		ctx.AppendFailure(errors.New("react: lock command failed"))
+		runRuleDedupe(ctx, newPlans(), nil, 0)
+		assert.Empty(t, ctx.DedupeSteps)
+		assert.Empty(t, *dirs)
+	})
+
+	t.Run("once per lock directory", func(t *testing.T) {
+		dirs := stubExec(t, nil)
+		ctx := NewUpdateContext(newCfg(false), "/test", nil).WithDedupe(true)
+
+		runRuleDedupe(ctx, newPlans(), nil, 0)
+		assert.Equal(t, []string{"/test/app", "/test/web"}, *dirs)
+		require.Len(t, ctx.DedupeSteps, 2)
+		assert.Equal(t, "npm", ctx.DedupeSteps[0].Rule)
+		assert.Equal(t, "npm dedupe", ctx.DedupeSteps[0].Command)
+		assert.NoError(t, ctx.DedupeSteps[0].Err)
+		assert.Equal(t, []string{"ok", "ok"}, dedupeStatuses(DedupeEntries(ctx.DedupeSteps)))
+	})
+
+	t.Run("failure keeps updates", func(t *testing.T) {
+		stubExec(t, errors.New("exit status 1"))
+		var buf bytes.Buffer
+		restore := warnings.SetWarningWriter(&buf)
+		defer restore()
+		plans := newPlans()
+		ctx := NewUpdateContext(newCfg(false), "/test", nil).WithDedupe(true)
+
+		runRuleDedupe(ctx, plans, nil, 0)
+		assert.Empty(t, ctx.Failures)
+		assert.Len(t, ctx.DedupeSteps, 2)
+		assert.Equal(t, constants.StatusUpdated, plans[0].Res.Status)
+		assert.Contains(t, buf.String(), "dedupe failed for rule npm in /test/app: exit status 1; the rule's updates are kept")
+		assert.Equal(t, []string{"warning", "warning"}, dedupeStatuses(DedupeEntries(ctx.DedupeSteps)))
+	})
+
+	t.Run("commit mode commits the deduped lock files", func(t *testing.T) {
+		stubExec(t, nil)
+		root := t.TempDir()
+		lockPath := filepath.Join(root, "web", "package-lock.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(lockPath), 0o755))
+		require.NoError(t, os.WriteFile(lockPath, []byte(`{"lockfileVersion":3}`), 0o644))
+		cfg := testutil.NewConfig().WithRule("npm", config.PackageManagerCfg{
+			LockFiles: []config.LockFileCfg{{Files: []string{"**/package-lock.json"}}},
+			Update:    &config.UpdateCfg{Dedupe: &config.DedupeCfg{Commands: "npm dedupe"}},
+		}).Build()
+
+		var staged, commits []string
+		originalGit := gitCommandFunc
+		gitCommandFunc = func(dir string, args ...string) ([]byte, error) {
+			switch args[0] {
+			case "add":
+				staged = append(staged, args[2:]...)
+			case "status":
+				return []byte(" M web/package-lock.json\n"), nil
+			case "commit":
+				commits = append(commits, args[2])
+			}
+			return nil, nil
+		}
+		t.Cleanup(func() { gitCommandFunc = originalGit })
+
+		plans := []*PlannedUpdate{
+			{Res: UpdateResult{Pkg: testutil.NewPackage("react").WithRule("npm").WithVersion("18.0.0").WithSource(filepath.Join(root, "web", "package.json")).Build(), Status: constants.StatusUpdated, Target: "18.0.0"}, Original: "17.0.0"},
+		}
+		ctx := NewUpdateContext(cfg, root, nil).WithDedupe(true).WithCommitter(NewCommitter(root, ""))
+
+		runRuleDedupe(ctx, plans, nil, 0)
+		assert.Empty(t, ctx.Failures)
+		assert.Equal(t, []string{lockPath}, staged)
+		assert.Equal(t, []string{"chore(deps): dedupe npm lock files"}, commits)
+	})
+
+	t.Run("critical failure rolls back", func(t *testing.T) {
+		dirs := stubExec(t, errors.New("exit status 1"))
+		plans := newPlans()
+		results := []UpdateResult{plans[0].Res, plans[1].Res, plans[2].Res, plans[3].Res}
+		var calls []string
+		ctx := NewUpdateContext(newCfg(true), "/test", nil).WithDedupe(true).WithUpdaterFunc(func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			calls = append(calls, p.Name+"@"+target)
+			return nil
+		})
+
+		runRuleDedupe(ctx, plans, results, 0)
+		assert.Equal(t, []string{"/test/app"}, *dirs)
+		assert.Equal(t, []string{"react@17.0.0", "vue@2.7.0", "lodash@4.17.20"}, calls)
+		require.Len(t, ctx.Failures, 1)
+		assert.Contains(t, ctx.Failures[0].Error(), "dedupe failed for rule npm in /test/app")
+		assert.Equal(t, constants.StatusFailed, results[0].Status)
+		assert.Equal(t, constants.StatusUpToDate, results[3].Status)
+		require.Len(t, ctx.DedupeSteps, 1)
+		assert.Equal(t, 3, ctx.DedupeSteps[0].RolledBack)
+
+		entries := DedupeEntries(ctx.DedupeSteps)
+		assert.Equal(t, []string{"failed"}, dedupeStatuses(entries))
+		assert.Equal(t, 3, entries[0].RolledBack)
+	})
+}
+
+// dedupeStatuses returns the status of each dedupe output entry in order.
+func dedupeStatuses(entries []output.UpdateDedupeStep) []string {
+	statuses := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		statuses = append(statuses, entry.Status)
+	}
+	return statuses
+}
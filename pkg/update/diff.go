@@ -0,0 +1,184 @@
+package update
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change.
+const diffContextLines = 1
+
+// ManifestDiff computes the unified diff of the manifest edit an update would make.
+//
+// It uses the same rewrite logic as UpdatePackage but never writes to disk.
+// Lock files are not diffed: they are regenerated by the rule's lock command,
+// so their content is only known after that command runs.
+//
+// Parameters:
+//   - p: The package to update
+//   - target: The target version
+//   - cfg: Global configuration containing rule definitions
+//   - workDir: Directory the file labels are made relative to
+//
+// Returns:
+//   - string: Unified diff with a/ and b/ file labels; empty when the manifest would not change
+//   - error: When the manifest cannot be read or rewritten
+//
+// Example output:
+//
+//	--- a/package.json
+//	+++ b/package.json
+//	@@ -3,3 +3,3 @@
+//	   "dependencies": {
+//	-    "react": "^17.0.0"
+//	+    "react": "^18.0.0"
+//	   }
+func ManifestDiff(p formats.Package, target string, cfg *config.Config, workDir string) (string, error) {
+	original, updated, err := renderDeclaredVersion(p, target, cfg)
+	if err != nil {
+		return "", err
+	}
+	if string(original) == string(updated) {
+		return "", nil
+	}
+
+	label := filepath.ToSlash(p.Source)
+	if rel, relErr := filepath.Rel(workDir, p.Source); relErr == nil && !strings.HasPrefix(rel, "..") {
+		label = filepath.ToSlash(rel)
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(original)),
+		B:        difflib.SplitLines(string(updated)),
+		FromFile: "a/" + label,
+		ToFile:   "b/" + label,
+		Context:  diffContextLines,
+	})
+}
+
+// AttachDiffs computes the manifest diff for every plan that will be applied.
+//
+// The diff is stored on plan.Res.Diff so it flows into the live table and
+// structured output. Plans that are skipped get no diff; failures to compute a
+// diff are reported in verbose mode and never fail the update.
+//
+// Parameters:
+//   - plans: The planned updates
+//   - cfg: Global configuration containing rule definitions
+//   - workDir: Directory the file labels are made relative to
+func AttachDiffs(plans []*PlannedUpdate, cfg *config.Config, workDir string) {
+	for _, plan := range plans {
+		if plan == nil || ShouldSkipUpdate(&plan.Res) {
+			continue
+		}
+
+		diff, err := ManifestDiff(plan.Res.Pkg, plan.Res.Target, cfg, workDir)
+		if err != nil {
+			verbose.Printf("Diff for %s skipped: %v\n", plan.Res.Pkg.Name, err)
+			continue
+		}
+		plan.Res.Diff = diff
+	}
+}
+
+// CollapsedDiffLines condenses a unified diff into one line per changed line.
+//
+// Removed and added lines of a hunk are paired in order; unpaired lines are
+// shown on their own.
+//
+// Parameters:
+//   - diff: A unified diff as returned by ManifestDiff
+//
+// Returns:
+//   - []string: Lines in the form "file:line old → new"; nil for an empty diff
+//
+// Example:
+//
+//	update.CollapsedDiffLines(diff) // ["package.json:4 \"react\": \"^17.0.0\" → \"react\": \"^18.0.0\""]
+func CollapsedDiffLines(diff string) []string {
+	var (
+		lines   []string
+		file    string
+		oldLine int
+		start   int
+		removed []string
+		added   []string
+	)
+
+	flush := func() {
+		for i := 0; i < len(removed) || i < len(added); i++ {
+			var old, updated string
+			if i < len(removed) {
+				old = removed[i]
+			}
+			if i < len(added) {
+				updated = added[i]
+			}
+			lines = append(lines, fmt.Sprintf("%s:%d %s → %s", file, start+i, old, updated))
+		}
+		removed, added = nil, nil
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			flush()
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimSpace(line[4:]), "b/")
+		case strings.HasPrefix(line, "@@ "):
+			flush()
+			oldLine = hunkStart(line)
+		case strings.HasPrefix(line, "-"):
+			if len(removed) == 0 && len(added) == 0 {
+				start = oldLine
+			}
+			removed = append(removed, strings.TrimSpace(line[1:]))
+			oldLine++
+		case strings.HasPrefix(line, "+"):
+			if len(removed) == 0 && len(added) == 0 {
+				start = oldLine
+			}
+			added = append(added, strings.TrimSpace(line[1:]))
+		default:
+			flush()
+			oldLine++
+		}
+	}
+	flush()
+
+	return lines
+}
+
+// hunkStart returns the first original line number of a "@@ -N,M +N,M @@" header.
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 2 {
+		return 0
+	}
+	old := strings.TrimPrefix(fields[1], "-")
+	if idx := strings.Index(old, ","); idx >= 0 {
+		old = old[:idx]
+	}
+	n, _ := strconv.Atoi(old)
+	return n
+}
+
+// PrintCollapsedDiff writes the collapsed form of a diff below a table row.
+//
+// Parameters:
+//   - w: Destination writer
+//   - diff: A unified diff as returned by ManifestDiff; nothing is written when empty
+func PrintCollapsedDiff(w io.Writer, diff string) {
+	for _, line := range CollapsedDiffLines(diff) {
+		_, _ = fmt.Fprintf(w, "    ↳ %s\n", line)
+	}
+}
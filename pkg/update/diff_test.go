@@ -0,0 +1,117 @@
+package update
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// diffTestConfig returns a JSON rule config used by the diff tests.
+func diffTestConfig() *config.Config {
+	return &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"r": {
+			Format: "json",
+			Fields: map[string]string{"dependencies": "prod"},
+			Update: &config.UpdateCfg{Commands: "echo {{package}}"},
+		},
+	}}
+}
+
+// TestManifestDiff tests previewing a manifest edit as a unified diff.
+//
+// It verifies:
+//   - The changed line is shown as a removal and an addition with file labels relative to workDir
+//   - The manifest on disk is not modified
+//   - An update that leaves the manifest unchanged yields an empty diff
+//   - Read errors are returned
+func TestManifestDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "package.json")
+	original := "{\n  \"dependencies\": {\n    \"demo\": \"^1.0.0\"\n  }\n}\n"
+	require.NoError(t, writeFile(path, original))
+
+	pkg := formats.Package{Name: "demo", Rule: "r", PackageType: "js", Type: "prod", Constraint: "^", Version: "1.0.0", Source: path}
+
+	diff, err := ManifestDiff(pkg, "1.2.0", diffTestConfig(), tmpDir)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "--- a/package.json\n+++ b/package.json\n")
+	assert.Contains(t, diff, "-    \"demo\": \"^1.0.0\"\n")
+	assert.Contains(t, diff, "+    \"demo\": \"^1.2.0\"\n")
+
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, original, string(content))
+
+	unchanged, err := ManifestDiff(pkg, "1.0.0", diffTestConfig(), tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, unchanged)
+
+	pkg.Source = filepath.Join(tmpDir, "missing.json")
+	_, err = ManifestDiff(pkg, "1.2.0", diffTestConfig(), tmpDir)
+	assert.Error(t, err)
+}
+
+// TestAttachDiffs tests attaching diffs to planned updates.
+//
+// It verifies:
+//   - Plans that will be applied receive a diff
+//   - Skipped plans and plans whose diff fails are left without one
+func TestAttachDiffs(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "package.json")
+	require.NoError(t, writeFile(path, "{\n  \"dependencies\": {\n    \"demo\": \"^1.0.0\"\n  }\n}\n"))
+
+	planned := &PlannedUpdate{Res: UpdateResult{
+		Pkg:    formats.Package{Name: "demo", Rule: "r", Constraint: "^", Version: "1.0.0", Source: path},
+		Target: "1.2.0",
+		Status: constants.StatusPlanned,
+	}}
+	upToDate := &PlannedUpdate{Res: UpdateResult{
+		Pkg:    formats.Package{Name: "demo", Rule: "r", Constraint: "^", Version: "1.0.0", Source: path},
+		Status: constants.StatusUpToDate,
+	}}
+	missing := &PlannedUpdate{Res: UpdateResult{
+		Pkg:    formats.Package{Name: "gone", Rule: "r", Source: filepath.Join(tmpDir, "missing.json")},
+		Target: "1.0.0",
+		Status: constants.StatusPlanned,
+	}}
+
+	AttachDiffs([]*PlannedUpdate{planned, upToDate, missing, nil}, diffTestConfig(), tmpDir)
+
+	assert.Contains(t, planned.Res.Diff, "+    \"demo\": \"^1.2.0\"")
+	assert.Empty(t, upToDate.Res.Diff)
+	assert.Empty(t, missing.Res.Diff)
+}
+
+// TestCollapsedDiffLines tests condensing a unified diff for table output.
+//
+// It verifies:
+//   - Removed and added lines are paired with the original line number
+//   - Separate hunks keep their own line numbers
+//   - Unpaired additions are shown with an empty old side
+//   - An empty diff yields no lines
+func TestCollapsedDiffLines(t *testing.T) {
+	diff := "--- a/package.json\n" +
+		"+++ b/package.json\n" +
+		"@@ -2,3 +2,3 @@\n" +
+		"   \"dependencies\": {\n" +
+		"-    \"react\": \"^17.0.0\"\n" +
+		"+    \"react\": \"^18.0.0\"\n" +
+		"   }\n" +
+		"@@ -9,2 +9,3 @@\n" +
+		"   \"devDependencies\": {\n" +
+		"+    \"jest\": \"^29.0.0\"\n" +
+		"   }\n"
+
+	assert.Equal(t, []string{
+		"package.json:3 \"react\": \"^17.0.0\" → \"react\": \"^18.0.0\"",
+		"package.json:10  → \"jest\": \"^29.0.0\"",
+	}, CollapsedDiffLines(diff))
+	assert.Nil(t, CollapsedDiffLines(""))
+}
@@ -0,0 +1,73 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/cmdexec"
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/ratelimit"
+	"github.com/ajxudir/goupdate/pkg/utils"
+)
+
+// resolveDigestFunc looks up the digest of a target version.
+// This allows for dependency injection during testing.
+var resolveDigestFunc = resolveDigest
+
+// executeDigestFunc runs the digest lookup command.
+// This allows for dependency injection during testing.
+var executeDigestFunc cmdexec.ExecuteFunc = cmdexec.Execute
+
+// resolveDigest runs the rule's update.resolve_digest command for a target version.
+//
+// It is used for digest-pinned declarations such as
+// "uses: actions/checkout@<sha> # v4.1.1", where both the digest and the
+// version comment must move to the target.
+//
+// Parameters:
+//   - p: The package being updated; its Source directory is the working directory
+//   - ruleCfg: Package manager configuration with update.resolve_digest
+//   - target: The target version substituted for {{version}}
+//
+// Returns:
+//   - string: The digest captured by the pattern's "digest" group
+//   - error: UnsupportedError when resolve_digest is not configured; error when the command fails or prints no digest
+func resolveDigest(p formats.Package, ruleCfg config.PackageManagerCfg, target string) (string, error) {
+	if ruleCfg.Update == nil || ruleCfg.Update.ResolveDigest == nil || strings.TrimSpace(ruleCfg.Update.ResolveDigest.Commands) == "" {
+		return "", &errors.UnsupportedError{
+			Package:   p.Name,
+			Operation: "update",
+			Reason:    "declaration is pinned by digest and update.resolve_digest is not configured",
+		}
+	}
+	digestCfg := ruleCfg.Update.ResolveDigest
+
+	dir := ""
+	if p.Source != "" {
+		dir = filepath.Dir(p.Source)
+	}
+
+	if err := ratelimit.For(p.Rule, ruleCfg.GetMaxRequestsPerSecond()).Wait(context.Background()); err != nil {
+		return "", err
+	}
+
+	output, err := executeDigestFunc(digestCfg.Commands, nil, dir, digestCfg.TimeoutSeconds, cmdexec.BuildReplacements(p.Name, target, p.Constraint))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve digest for %s@%s: %w", p.Name, target, err)
+	}
+
+	match, err := utils.ExtractNamedGroups(digestCfg.Pattern, string(output))
+	if err != nil {
+		return "", fmt.Errorf("invalid resolve_digest pattern: %w", err)
+	}
+	digest := strings.TrimSpace(match["digest"])
+	if digest == "" {
+		return "", fmt.Errorf("no digest found for %s@%s", p.Name, target)
+	}
+
+	return digest, nil
+}
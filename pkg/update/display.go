@@ -14,6 +14,7 @@ import (
 	"github.com/ajxudir/goupdate/pkg/outdated"
 	"github.com/ajxudir/goupdate/pkg/output"
 	"github.com/ajxudir/goupdate/pkg/systemtest"
+	"github.com/ajxudir/goupdate/pkg/verbose"
 )
 
 // Note: ShouldTrackUnsupported and CollectUpdateErrors are defined in execution.go
@@ -26,6 +27,7 @@ type UpdateSummaryCounts struct {
 	HasMajor int // Packages with major updates still available
 	HasMinor int // Packages with minor updates still available
 	HasPatch int // Packages with patch updates still available
+	Deferred int // Packages with an update held back by --limit
 }
 
 // UpdateSummaryMode indicates whether the summary is for preview or post-update.
@@ -78,8 +80,13 @@ func ComputeSummaryFromPlans(plans []*PlannedUpdate) UpdateSummaryCounts {
 	for _, plan := range plans {
 		res := plan.Res
 
+		if res.Status == constants.StatusDeferred {
+			counts.Deferred++
+			continue
+		}
 		if res.Status == lock.InstallStatusNotConfigured || res.Status == constants.StatusConfigError ||
-			res.Status == constants.StatusFailed || res.Status == constants.StatusSummarizeError || res.Status == lock.InstallStatusFloating {
+			res.Status == constants.StatusFailed || res.Status == constants.StatusSummarizeError || res.Status == lock.InstallStatusFloating ||
+			res.Status == lock.InstallStatusNonRegistry {
 			if res.Err != nil || strings.HasPrefix(res.Status, constants.StatusFailed) {
 				counts.Failed++
 			}
@@ -117,10 +124,13 @@ func ComputeSummaryFromResults(results []UpdateResult) UpdateSummaryCounts {
 			counts.ToUpdate++
 		case constants.StatusUpToDate:
 			counts.UpToDate++
+		case constants.StatusDeferred:
+			counts.Deferred++
+			continue
 		default:
 			if res.Err != nil || strings.HasPrefix(res.Status, constants.StatusFailed) {
 				counts.Failed++
-			} else if res.Status != lock.InstallStatusNotConfigured && res.Status != lock.InstallStatusFloating {
+			} else if res.Status != lock.InstallStatusNotConfigured && res.Status != lock.InstallStatusFloating && res.Status != lock.InstallStatusNonRegistry {
 				counts.UpToDate++
 			}
 		}
@@ -241,6 +251,7 @@ func PrintOutdatedCheckRow(plan *PlannedUpdate, table *output.Table, selection o
 		statusDisplay,
 		res.Group,
 		res.Pkg.Name,
+		formatUpdateDuration(res.Duration),
 	)
 	fmt.Println(row)
 	_ = os.Stdout.Sync()
@@ -252,6 +263,7 @@ func DeriveOutdatedStatus(plan *PlannedUpdate) string {
 
 	// Handle special statuses
 	if res.Status == lock.InstallStatusFloating ||
+		res.Status == lock.InstallStatusNonRegistry ||
 		res.Status == lock.InstallStatusIgnored ||
 		res.Status == lock.InstallStatusNotConfigured {
 		return res.Status
@@ -289,7 +301,8 @@ func BuildOutdatedCheckTable(packages []formats.Package, selection outdated.Upda
 		AddColumnWithMinWidth("PATCH", 12).
 		AddColumnWithMinWidth("STATUS", 14).
 		AddConditionalColumn("GROUP", showGroup).
-		AddColumn("NAME")
+		AddColumn("NAME").
+		AddConditionalColumn("TIME", verbose.IsEnabled())
 
 	for _, p := range packages {
 		constraintDisplay := FormatConstraintDisplay(p, selection)
@@ -335,13 +348,24 @@ func PrintUpdateRow(res UpdateResult, table *output.Table, dryRun bool, selectio
 		statusDisplay,
 		res.Group,
 		res.Pkg.Name,
+		formatUpdateDuration(res.Duration),
 	)
 	fmt.Println(row)
 	// Force flush to ensure realtime output in CI environments (GitHub Actions, etc.)
 	_ = os.Stdout.Sync()
 }
 
+// formatUpdateDuration formats the time spent applying an update for the TIME column.
+// Packages that were not applied have no duration and show an empty cell.
+func formatUpdateDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return FormatTestDuration(d)
+}
+
 // BuildUpdateTableFromPackages creates a table with column widths calculated from package data.
+// The TIME column is only shown in verbose mode.
 func BuildUpdateTableFromPackages(packages []formats.Package, selection outdated.UpdateSelectionFlags) *output.Table {
 	groups := make([]string, len(packages))
 	for i, p := range packages {
@@ -359,7 +383,8 @@ func BuildUpdateTableFromPackages(packages []formats.Package, selection outdated
 		AddColumnWithMinWidth("TARGET", 12).
 		AddColumnWithMinWidth("STATUS", 14).
 		AddConditionalColumn("GROUP", showGroup).
-		AddColumn("NAME")
+		AddColumn("NAME").
+		AddConditionalColumn("TIME", verbose.IsEnabled())
 
 	for _, p := range packages {
 		constraintDisplay := FormatConstraintDisplay(p, selection)
@@ -383,9 +408,14 @@ func BuildUpdateTableFromPackages(packages []formats.Package, selection outdated
 // PrintUpdatePreview prints a detailed preview showing packages that will be updated.
 func PrintUpdatePreview(plans []*PlannedUpdate, table *output.Table, selection outdated.UpdateSelectionFlags) {
 	var willUpdate, hasMoreUpdates []*PlannedUpdate
+	var deferred []UpdateResult
 
 	for _, plan := range plans {
 		res := plan.Res
+		if res.Status == constants.StatusDeferred {
+			deferred = append(deferred, res)
+			continue
+		}
 		if IsNonUpdatableStatus(res.Status) {
 			continue
 		}
@@ -415,6 +445,11 @@ func PrintUpdatePreview(plans []*PlannedUpdate, table *output.Table, selection o
 			maxNameWidth = len(plan.Res.Pkg.Name)
 		}
 	}
+	for _, res := range deferred {
+		if len(res.Pkg.Name) > maxNameWidth {
+			maxNameWidth = len(res.Pkg.Name)
+		}
+	}
 	nameFormat := fmt.Sprintf("  %%-%ds", maxNameWidth)
 
 	scope := DetermineScopeDescription(selection)
@@ -451,10 +486,28 @@ func PrintUpdatePreview(plans []*PlannedUpdate, table *output.Table, selection o
 		fmt.Println()
 	}
 
+	printDeferredUpdates(deferred, nameFormat)
+
 	counts := ComputeSummaryFromPlans(plans)
 	PrintUpdateSummaryLines(counts, SummaryModePreview)
 }
 
+// printDeferredUpdates lists the updates held back by --limit.
+//
+// Parameters:
+//   - deferred: Results with constants.StatusDeferred; nothing is printed when empty
+//   - nameFormat: Printf format aligning the package name column
+func printDeferredUpdates(deferred []UpdateResult, nameFormat string) {
+	if len(deferred) == 0 {
+		return
+	}
+	fmt.Println("Deferred to a later run (--limit):")
+	for _, res := range deferred {
+		fmt.Printf(nameFormat+" %s → %s\n", res.Pkg.Name, SafeFromVersion(res), res.Target)
+	}
+	fmt.Println()
+}
+
 // FormatSummaryStrings formats the summary counts into display strings for cmd layer.
 // Always shows counts (even zeros) for regex-friendly output.
 func FormatSummaryStrings(counts UpdateSummaryCounts, mode UpdateSummaryMode) (summaryLine, availableLine string) {
@@ -483,6 +536,9 @@ func FormatSummaryStrings(counts UpdateSummaryCounts, mode UpdateSummaryMode) (s
 	if counts.Failed > 0 {
 		parts = append(parts, fmt.Sprintf("%d failed", counts.Failed))
 	}
+	if counts.Deferred > 0 {
+		parts = append(parts, fmt.Sprintf("%d deferred", counts.Deferred))
+	}
 
 	if len(parts) > 0 {
 		summaryLine = fmt.Sprintf("Summary: %s", strings.Join(parts, ", "))
@@ -519,17 +575,21 @@ func PrintUpdateSummary(results []UpdateResult, dryRun bool, afterAllTestResult
 		return
 	}
 
-	var updated, failed, hasMoreUpdates []UpdateResult
+	var updated, failed, hasMoreUpdates, deferred []UpdateResult
 
 	for _, res := range results {
 		if res.Status == constants.StatusFailed {
 			failed = append(failed, res)
 			continue
 		}
+		if res.Status == constants.StatusDeferred {
+			deferred = append(deferred, res)
+			continue
+		}
 		if res.Status == constants.StatusConfigError || res.Status == constants.StatusSummarizeError {
 			continue
 		}
-		if res.Status == lock.InstallStatusNotConfigured || res.Status == lock.InstallStatusFloating {
+		if res.Status == lock.InstallStatusNotConfigured || res.Status == lock.InstallStatusFloating || res.Status == lock.InstallStatusNonRegistry {
 			continue
 		}
 
@@ -557,6 +617,11 @@ func PrintUpdateSummary(results []UpdateResult, dryRun bool, afterAllTestResult
 			maxNameWidth = len(res.Pkg.Name)
 		}
 	}
+	for _, res := range deferred {
+		if len(res.Pkg.Name) > maxNameWidth {
+			maxNameWidth = len(res.Pkg.Name)
+		}
+	}
 	nameFormat := fmt.Sprintf("  %%-%ds", maxNameWidth)
 	nameFormatWithIcon := fmt.Sprintf("  %%s %%-%ds", maxNameWidth)
 
@@ -627,6 +692,7 @@ func PrintUpdateSummary(results []UpdateResult, dryRun bool, afterAllTestResult
 			fmt.Println()
 		}
 	}
+	printDeferredUpdates(deferred, nameFormat)
 
 	counts := ComputeSummaryFromResults(results)
 	mode := SummaryModeResult
@@ -706,11 +772,38 @@ func PrintUpdateErrorsWithHints(errs []error, enhanceFunc func(error) string) {
 	}
 }
 
-// PrintUpdateStructured outputs update results in a structured format (CSV, JSON, XML).
+// PrintUpdateStructured outputs update results in a structured format (CSV, JSON, XML, JUnit).
 func PrintUpdateStructured(results []UpdateResult, warnings []string, errs []string, format output.Format, dryRun bool, selection outdated.UpdateSelectionFlags, writeFunc func(w io.Writer, format output.Format, result *output.UpdateResult) error) error {
+	return PrintUpdateStructuredWithSystemTests(results, nil, warnings, errs, format, dryRun, selection, writeFunc)
+}
+
+// PrintUpdateStructuredWithSystemTests outputs update results and system test failures in a structured format.
+//
+// System test failures are included in the result so JUnit reports can list
+// them as additional test cases; other formats include them when non-empty.
+func PrintUpdateStructuredWithSystemTests(results []UpdateResult, systemTestFailures []SystemTestFailure, warnings []string, errs []string, format output.Format, dryRun bool, selection outdated.UpdateSelectionFlags, writeFunc func(w io.Writer, format output.Format, result *output.UpdateResult) error) error {
+	return writeFunc(os.Stdout, format, BuildUpdateOutput(results, systemTestFailures, warnings, errs, dryRun, selection))
+}
+
+// BuildUpdateOutput converts update results into the structured output model.
+//
+// Updated results are reported as planned for dry runs, and the summary
+// counts updated and failed packages.
+//
+// Parameters:
+//   - results: Update results in execution order
+//   - systemTestFailures: System test failures collected during updates
+//   - warnings: Warning messages to include
+//   - errs: Error messages to include
+//   - dryRun: Whether the run was a dry run
+//   - selection: Version selection flags used for the constraint column
+//
+// Returns:
+//   - *output.UpdateResult: The structured result shared by every output format and --report-file
+func BuildUpdateOutput(results []UpdateResult, systemTestFailures []SystemTestFailure, warnings []string, errs []string, dryRun bool, selection outdated.UpdateSelectionFlags) *output.UpdateResult {
 	packages := make([]output.UpdatePackage, 0, len(results))
 
-	var updatedCount, failedCount int
+	var updatedCount, failedCount, deferredCount int
 
 	for _, res := range results {
 		status := res.Status
@@ -730,6 +823,11 @@ func PrintUpdateStructured(results []UpdateResult, warnings []string, errs []str
 			target = constants.PlaceholderNA
 		}
 
+		var notes *output.UpdateChangelog
+		if res.Changelog != nil {
+			notes = &output.UpdateChangelog{URL: res.Changelog.URL, Snippet: res.Changelog.Snippet}
+		}
+
 		packages = append(packages, output.UpdatePackage{
 			Rule:             res.Pkg.Rule,
 			PM:               res.Pkg.PackageType,
@@ -741,12 +839,21 @@ func PrintUpdateStructured(results []UpdateResult, warnings []string, errs []str
 			Status:           status,
 			Group:            res.Group,
 			Name:             res.Pkg.Name,
+			Source:           res.Pkg.Source,
+			Line:             res.Pkg.Line,
+			Repository:       res.Pkg.Repository,
 			Error:            errStr,
+			Diff:             res.Diff,
+			Changelog:        notes,
+			DurationMs:       res.Duration.Milliseconds(),
+			PlanDurationMs:   res.PlanDuration.Milliseconds(),
 		})
 
 		switch status {
 		case constants.StatusUpdated, constants.StatusPlanned:
 			updatedCount++
+		case constants.StatusDeferred:
+			deferredCount++
 		default:
 			if res.Err != nil || strings.HasPrefix(status, constants.StatusFailed) {
 				failedCount++
@@ -754,19 +861,93 @@ func PrintUpdateStructured(results []UpdateResult, warnings []string, errs []str
 		}
 	}
 
-	result := &output.UpdateResult{
+	return &output.UpdateResult{
 		Summary: output.UpdateSummary{
-			TotalPackages:   len(packages),
-			UpdatedPackages: updatedCount,
-			FailedPackages:  failedCount,
-			DryRun:          dryRun,
+			TotalPackages:    len(packages),
+			UpdatedPackages:  updatedCount,
+			FailedPackages:   failedCount,
+			DeferredPackages: deferredCount,
+			DryRun:           dryRun,
 		},
-		Packages: packages,
-		Warnings: warnings,
-		Errors:   errs,
+		Packages:           packages,
+		Warnings:           warnings,
+		Errors:             errs,
+		SystemTestFailures: systemTestEntries(systemTestFailures),
 	}
+}
+
+// systemTestEntries converts system test failures into structured output entries.
+//
+// Parameters:
+//   - failures: System test failures collected during execution
+//
+// Returns:
+//   - []output.UpdateSystemTest: One entry per failure; empty, not nil, when no test failed
+func systemTestEntries(failures []SystemTestFailure) []output.UpdateSystemTest {
+	entries := make([]output.UpdateSystemTest, 0, len(failures))
+	for _, failure := range failures {
+		var details string
+		if failure.Result != nil {
+			details = failure.Result.FormatResultsQuiet()
+		}
+		entries = append(entries, output.UpdateSystemTest{
+			Package:  failure.PkgName,
+			Critical: failure.IsCritical,
+			Details:  details,
+			Tests:    failedTestEntries(failure.Result),
+		})
+	}
+	return entries
+}
+
+// DedupeEntries converts dedupe steps into structured output entries.
+//
+// Parameters:
+//   - steps: Dedupe steps recorded in UpdateContext.DedupeSteps
+//
+// Returns:
+//   - []output.UpdateDedupeStep: One entry per step; nil when no dedupe ran
+func DedupeEntries(steps []DedupeStep) []output.UpdateDedupeStep {
+	var entries []output.UpdateDedupeStep
+	for _, step := range steps {
+		entry := output.UpdateDedupeStep{Rule: step.Rule, Dir: step.Dir, Status: "ok", RolledBack: step.RolledBack}
+		if step.Err != nil {
+			entry.Error = step.Err.Error()
+			entry.Status = "warning"
+			if step.Critical {
+				entry.Status = "failed"
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
 
-	return writeFunc(os.Stdout, format, result)
+// failedTestEntries lists the failed tests of a system test result.
+//
+// Parameters:
+//   - result: A SystemTestFailure result; may be nil
+//
+// Returns:
+//   - []output.UpdateTestFail: The failed tests; empty when result cannot list them
+func failedTestEntries(result interface{ FormatResultsQuiet() string }) []output.UpdateTestFail {
+	tests := []output.UpdateTestFail{}
+	details, ok := result.(SystemTestDetails)
+	if !ok {
+		return tests
+	}
+	for _, test := range details.FailedTests() {
+		entry := output.UpdateTestFail{
+			Name:     test.Name,
+			Critical: !test.ContinueOnFail,
+			Output:   test.Output,
+		}
+		if test.Error != nil {
+			entry.Error = test.Error.Error()
+		}
+		tests = append(tests, entry)
+	}
+	return tests
 }
 
 // printSystemTestResultDirect prints system test results using the actual systemtest.Result type.
@@ -1,11 +1,14 @@
 package update
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"testing"
 	"time"
 
+	"github.com/ajxudir/goupdate/pkg/changelog"
 	"github.com/ajxudir/goupdate/pkg/constants"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/lock"
@@ -13,7 +16,9 @@ import (
 	"github.com/ajxudir/goupdate/pkg/output"
 	"github.com/ajxudir/goupdate/pkg/systemtest"
 	"github.com/ajxudir/goupdate/pkg/testutil"
+	"github.com/ajxudir/goupdate/pkg/verbose"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Note: We use lock.InstallStatusNotConfigured and lock.InstallStatusFloating in tests
@@ -468,8 +473,35 @@ func TestBuildUpdateTableFromPackages(t *testing.T) {
 
 		table := BuildUpdateTableFromPackages(packages, selection)
 
-		// Should have 10 columns when group is shown
-		assert.Equal(t, 10, table.ColumnCount())
+		// Should have 11 columns: GROUP and the verbose-only TIME column are conditional
+		assert.Equal(t, 11, table.ColumnCount())
+	})
+
+	t.Run("shows time column in verbose mode", func(t *testing.T) {
+		packages := []formats.Package{
+			testutil.NPMPackage("react", "17.0.0", "17.0.0"),
+		}
+		selection := outdated.UpdateSelectionFlags{}
+
+		table := BuildUpdateTableFromPackages(packages, selection)
+		assert.NotContains(t, table.HeaderRow(), "TIME")
+
+		verbose.Enable()
+		t.Cleanup(verbose.Disable)
+
+		table = BuildUpdateTableFromPackages(packages, selection)
+		assert.Contains(t, table.HeaderRow(), "TIME")
+
+		res := UpdateResult{
+			Pkg:      testutil.NPMPackage("react", "17.0.0", "17.0.0"),
+			Target:   "18.0.0",
+			Status:   constants.StatusUpdated,
+			Duration: 1500 * time.Millisecond,
+		}
+		output := testutil.CaptureStdout(t, func() {
+			PrintUpdateRow(res, table, false, selection)
+		})
+		assert.Contains(t, output, "1.5s")
 	})
 }
 
@@ -971,6 +1003,74 @@ func TestPrintUpdateStructured(t *testing.T) {
 		assert.Equal(t, 1, calledResult.Summary.FailedPackages)
 	})
 
+	t.Run("includes durations in milliseconds", func(t *testing.T) {
+		results := []UpdateResult{
+			{
+				Pkg:          testutil.NPMPackage("react", "17.0.0", "17.0.0"),
+				Target:       "18.0.0",
+				Status:       constants.StatusUpdated,
+				Duration:     1500 * time.Millisecond,
+				PlanDuration: 250 * time.Millisecond,
+			},
+			{
+				Pkg:    testutil.NPMPackage("vue", "3.0.0", "3.0.0"),
+				Status: constants.StatusUpToDate,
+			},
+		}
+
+		var buf bytes.Buffer
+		writeFunc := func(w io.Writer, format output.Format, result *output.UpdateResult) error {
+			return output.WriteUpdateResult(&buf, format, result)
+		}
+
+		err := PrintUpdateStructured(results, nil, nil, output.FormatJSON, true, outdated.UpdateSelectionFlags{}, writeFunc)
+		require.NoError(t, err)
+
+		var decoded struct {
+			Packages []map[string]any `json:"packages"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded.Packages, 2)
+		assert.EqualValues(t, 1500, decoded.Packages[0]["duration_ms"])
+		assert.EqualValues(t, 250, decoded.Packages[0]["plan_duration_ms"])
+		assert.EqualValues(t, 0, decoded.Packages[1]["duration_ms"], "duration_ms is present for skipped packages")
+		assert.NotContains(t, decoded.Packages[1], "plan_duration_ms")
+	})
+
+	t.Run("includes release notes url and snippet", func(t *testing.T) {
+		results := []UpdateResult{
+			{
+				Pkg:       testutil.NPMPackage("react", "17.0.0", "17.0.0"),
+				Target:    "18.0.0",
+				Status:    constants.StatusPlanned,
+				Changelog: &changelog.Notes{URL: "https://github.com/facebook/react/releases/tag/v18.0.0", Snippet: "## v18.0.0\nConcurrent rendering"},
+			},
+			{
+				Pkg:    testutil.NPMPackage("vue", "3.0.0", "3.0.0"),
+				Status: constants.StatusUpToDate,
+			},
+		}
+
+		var buf bytes.Buffer
+		writeFunc := func(w io.Writer, format output.Format, result *output.UpdateResult) error {
+			return output.WriteUpdateResult(&buf, format, result)
+		}
+
+		err := PrintUpdateStructured(results, nil, nil, output.FormatJSON, true, outdated.UpdateSelectionFlags{}, writeFunc)
+		require.NoError(t, err)
+
+		var decoded struct {
+			Packages []map[string]any `json:"packages"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded.Packages, 2)
+		assert.Equal(t, map[string]any{
+			"url":     "https://github.com/facebook/react/releases/tag/v18.0.0",
+			"snippet": "## v18.0.0\nConcurrent rendering",
+		}, decoded.Packages[0]["changelog"])
+		assert.NotContains(t, decoded.Packages[1], "changelog")
+	})
+
 	t.Run("includes warnings and errors", func(t *testing.T) {
 		results := []UpdateResult{}
 		warnings := []string{"warning1"}
@@ -987,9 +1087,62 @@ func TestPrintUpdateStructured(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, calledResult.Warnings, 1)
 		assert.Len(t, calledResult.Errors, 1)
+		assert.Empty(t, calledResult.SystemTestFailures)
 	})
 }
 
+// TestPrintUpdateStructuredWithSystemTests tests system test failures in structured output.
+//
+// It verifies:
+//   - Each failure keeps its package, criticality, and formatted details
+//   - Results that can list their failed tests expose each test's name, criticality, error, and output
+//   - Results that cannot list them, or are nil, get an empty test list
+//   - JSON output carries an empty system_test_failures list when no test failed
+func TestPrintUpdateStructuredWithSystemTests(t *testing.T) {
+	results := []UpdateResult{
+		{
+			Pkg:    testutil.NPMPackage("react", "17.0.0", "17.0.0"),
+			Target: "18.0.0",
+			Status: constants.StatusUpdated,
+		},
+	}
+	lodashResult := &systemtest.Result{Phase: "after_each", Tests: []systemtest.TestResult{
+		{Name: "unit", Passed: true},
+		{Name: "e2e", Error: errors.New("exit status 1"), Output: "1 failing"},
+		{Name: "lint", ContinueOnFail: true, Output: "2 warnings"},
+	}}
+	failures := []SystemTestFailure{
+		{PkgName: "react", Result: &mockTestResultOutput{resultOutput: "  ✗ e2e\n"}, IsCritical: true},
+		{PkgName: "group", Result: nil, IsCritical: false},
+		{PkgName: "lodash", Result: lodashResult, IsCritical: true},
+	}
+
+	var calledResult *output.UpdateResult
+	writeFunc := func(w io.Writer, format output.Format, result *output.UpdateResult) error {
+		calledResult = result
+		return nil
+	}
+
+	err := PrintUpdateStructuredWithSystemTests(results, failures, nil, nil, output.FormatJUnit, false, outdated.UpdateSelectionFlags{}, writeFunc)
+
+	assert.NoError(t, err)
+	assert.Len(t, calledResult.Packages, 1)
+	assert.Equal(t, []output.UpdateSystemTest{
+		{Package: "react", Critical: true, Details: "  ✗ e2e\n", Tests: []output.UpdateTestFail{}},
+		{Package: "group", Critical: false, Details: "", Tests: []output.UpdateTestFail{}},
+		{Package: "lodash", Critical: true, Details: lodashResult.FormatResultsQuiet(), Tests: []output.UpdateTestFail{
+			{Name: "e2e", Critical: true, Error: "exit status 1", Output: "1 failing"},
+			{Name: "lint", Critical: false, Output: "2 warnings"},
+		}},
+	}, calledResult.SystemTestFailures)
+
+	var buf bytes.Buffer
+	require.NoError(t, PrintUpdateStructuredWithSystemTests(results, nil, nil, nil, output.FormatJSON, false, outdated.UpdateSelectionFlags{}, func(w io.Writer, format output.Format, result *output.UpdateResult) error {
+		return output.WriteUpdateResult(&buf, format, result)
+	}))
+	assert.Contains(t, buf.String(), `"system_test_failures":[]`)
+}
+
 func TestPrintUpdateErrorsWithHints(t *testing.T) {
 	t.Run("prints errors with hints", func(t *testing.T) {
 		errs := []error{
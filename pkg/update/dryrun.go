@@ -0,0 +1,190 @@
+package update
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/cmdexec"
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// DryRunCommand is a lock command a dry run skipped.
+//
+// Fields:
+//   - Rule: Rule the command belongs to
+//   - Packages: Packages the command would have locked; several for a group lock
+//   - Dir: Directory the command would have run in
+//   - Command: The command with every placeholder replaced
+type DryRunCommand struct {
+	Rule     string   `json:"rule"`
+	Packages []string `json:"packages"`
+	Dir      string   `json:"dir"`
+	Command  string   `json:"command"`
+}
+
+// String formats the command as "rule (dir): command".
+func (c DryRunCommand) String() string {
+	return fmt.Sprintf("%s (%s): %s", c.Rule, c.Dir, c.Command)
+}
+
+// PlanDryRunCommands lists the lock commands a real run of the plans would execute.
+//
+// It performs the following operations:
+//   - Step 1: Split each rule's plans into the batches a real run processes (see ruleBatches)
+//   - Step 2: For a batch of several plans, render the group lock once per manifest directory
+//   - Step 3: Otherwise render each pending plan's own lock command in its manifest directory
+//
+// Nothing is listed for --skip-lock, for plans without a pending update, and for
+// rules without lock commands. Staged runs lock once per release step; their
+// commands are listed once for the final target.
+//
+// Parameters:
+//   - ctx: Update context holding the run flags and configuration
+//   - plans: Planned updates of the run
+//
+// Returns:
+//   - []DryRunCommand: The commands in processing order
+func PlanDryRunCommands(ctx *UpdateContext, plans []*PlannedUpdate) []DryRunCommand {
+	if ctx.SkipLockRun || ctx.Cfg == nil {
+		return nil
+	}
+
+	var commands []DryRunCommand
+	for _, rulePlans := range PartitionPlans(plans, planRule) {
+		rule := planRule(rulePlans[0])
+		batches := PartitionPlans(rulePlans, planGroup)
+		if len(batches) > 1 && ctx.groupsRuleCommands(rule, rulePlans) {
+			batches = [][]*PlannedUpdate{rulePlans}
+		}
+
+		for _, batch := range batches {
+			var pending []*PlannedUpdate
+			for _, plan := range batch {
+				if !ShouldSkipUpdate(&plan.Res) {
+					pending = append(pending, plan)
+				}
+			}
+			if len(pending) == 0 {
+				continue
+			}
+			if len(batch) > 1 && !ctx.Staged {
+				commands = append(commands, groupDryRunCommands(ctx, rule, pending)...)
+				continue
+			}
+			for _, plan := range pending {
+				if command, ok := packageDryRunCommand(ctx, plan); ok {
+					commands = append(commands, command)
+				}
+			}
+		}
+	}
+	return commands
+}
+
+// groupDryRunCommands renders a group's shared lock command for each of its manifest directories.
+//
+// Parameters:
+//   - ctx: Update context holding the configuration
+//   - rule: Rule of the group
+//   - plans: Pending plans of the group
+//
+// Returns:
+//   - []DryRunCommand: One command per directory; nil when the group has no lock command
+func groupDryRunCommands(ctx *UpdateContext, rule string, plans []*PlannedUpdate) []DryRunCommand {
+	cfg := groupLockConfig(plans)
+	if cfg == nil || strings.TrimSpace(cfg.Commands) == "" {
+		return nil
+	}
+
+	withAllDeps := false
+	names := make([]string, 0, len(plans))
+	for _, plan := range plans {
+		withAllDeps = withAllDeps || allowsTransitive(ctx.Cfg, plan.Res.Pkg)
+		names = append(names, plan.Res.Pkg.Name)
+	}
+
+	rendered := cmdexec.RenderCommands(cfg.Commands, updateReplacements("", "", "", withAllDeps))
+	dirs := groupLockDirs(ctx.Cfg, ctx.WorkDir, plans)
+	commands := make([]DryRunCommand, 0, len(dirs))
+	for _, dir := range dirs {
+		commands = append(commands, DryRunCommand{Rule: rule, Packages: names, Dir: dir, Command: rendered})
+	}
+	return commands
+}
+
+// packageDryRunCommand renders the lock command UpdatePackage would run for a single plan.
+//
+// Parameters:
+//   - ctx: Update context holding the configuration and working directory
+//   - plan: A pending plan
+//
+// Returns:
+//   - DryRunCommand: The rendered command
+//   - bool: false when the plan's rule has no lock command
+func packageDryRunCommand(ctx *UpdateContext, plan *PlannedUpdate) (DryRunCommand, bool) {
+	p := plan.Res.Pkg
+	cfg, err := ResolveUpdateCfg(p, ctx.Cfg)
+	if err != nil || cfg == nil || strings.TrimSpace(cfg.Commands) == "" {
+		return DryRunCommand{}, false
+	}
+
+	dir := packageLockDir(ctx.Cfg, p, ctx.WorkDir)
+	if dir == "" {
+		dir = ctx.Cfg.WorkingDir
+	}
+	if dir == "" {
+		dir = "."
+	}
+
+	replacements := updateReplacements(p.Name, plan.Res.Target, p.Constraint, allowsTransitive(ctx.Cfg, p))
+	return DryRunCommand{
+		Rule:     p.Rule,
+		Packages: []string{p.Name},
+		Dir:      dir,
+		Command:  cmdexec.RenderCommands(cfg.Commands, replacements),
+	}, true
+}
+
+// guardDryRun prepares a dry run: it records the skipped lock commands and
+// locks the updater into dry-run mode.
+//
+// Dry runs must never mutate the project. Execution paths already pass
+// dryRun=true to the updater, but a custom updater or a future code path
+// could still reach a lock command, so the updater is wrapped to refuse every
+// call without dryRun. The refusal fails the package instead of running anything.
+// Calling it again or outside a dry run does nothing. Dry-run verification,
+// which edits temporary copies only, keeps the unguarded updater (see rehearsalUpdater).
+//
+// Parameters:
+//   - plans: Planned updates of the run
+func (ctx *UpdateContext) guardDryRun(plans []*PlannedUpdate) {
+	if !ctx.DryRun || ctx.dryRunGuarded {
+		return
+	}
+	ctx.dryRunGuarded = true
+	ctx.DryRunCommands = PlanDryRunCommands(ctx, plans)
+	verbose.Debugf("Dry run: skipping %d lock command(s)", len(ctx.DryRunCommands))
+
+	if updater := ctx.UpdaterFunc; updater != nil {
+		ctx.unguardedUpdater = updater
+		ctx.UpdaterFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			if !dryRun {
+				return fmt.Errorf("dry run: refusing to apply %s@%s", p.Name, target)
+			}
+			return updater(p, target, cfg, workDir, dryRun, skipLock)
+		}
+	}
+}
+
+// rehearsalUpdater returns the updater dry-run verification applies temporary copies with.
+//
+// Returns:
+//   - PackageUpdater: The updater as it was before guardDryRun wrapped it
+func (ctx *UpdateContext) rehearsalUpdater() PackageUpdater {
+	if ctx.unguardedUpdater != nil {
+		return ctx.unguardedUpdater
+	}
+	return ctx.UpdaterFunc
+}
@@ -0,0 +1,105 @@
+package update
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// dryRunPlan returns a planned npm update of a package in dir.
+func dryRunPlan(name, group, dir string) *PlannedUpdate {
+	pkg := testutil.NPMPackage(name, "1.0.0", "1.0.0")
+	pkg.Source = dir + "/package.json"
+	return &PlannedUpdate{
+		Res:      UpdateResult{Pkg: pkg, Target: "2.0.0", Status: constants.StatusPlanned},
+		Cfg:      &config.UpdateCfg{Commands: "npm install {{package}}@{{version}}"},
+		Original: "1.0.0",
+		GroupKey: group,
+	}
+}
+
+// TestPlanDryRunCommands tests listing the lock commands a dry run skips.
+//
+// It verifies:
+//   - A single package lists its own rendered command in its manifest directory
+//   - A group lists its shared lock command once, without package placeholders
+//   - Plans without a pending update and --skip-lock list nothing
+//   - With group commands the whole rule lists one command even in a dry run,
+//     unless its lock command uses {{package}}
+func TestPlanDryRunCommands(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+	newPlans := func() []*PlannedUpdate {
+		skipped := dryRunPlan("left-pad", "left-pad", "web")
+		skipped.Res.Status = constants.StatusDeferred
+		return []*PlannedUpdate{
+			dryRunPlan("react", "react", "web"),
+			dryRunPlan("react-dom", "react", "web"),
+			dryRunPlan("lodash", "lodash", "api"),
+			skipped,
+		}
+	}
+
+	ctx := NewUpdateContext(cfg, ".", nil).WithFlags(true, false, false)
+	commands := PlanDryRunCommands(ctx, newPlans())
+	require.Len(t, commands, 2)
+	assert.Equal(t, DryRunCommand{Rule: "npm", Packages: []string{"react", "react-dom"}, Dir: "web", Command: "npm install @"}, commands[0])
+	assert.Equal(t, DryRunCommand{Rule: "npm", Packages: []string{"lodash"}, Dir: "api", Command: "npm install lodash@2.0.0"}, commands[1])
+	assert.Equal(t, "npm (api): npm install lodash@2.0.0", commands[1].String())
+
+	ctx.GroupCommands = true
+	commands = PlanDryRunCommands(ctx, newPlans())
+	require.Len(t, commands, 2)
+	assert.Equal(t, []string{"react", "react-dom"}, commands[0].Packages)
+
+	plans := newPlans()
+	for _, plan := range plans {
+		plan.Cfg = &config.UpdateCfg{Commands: "npm install"}
+	}
+	commands = PlanDryRunCommands(ctx, plans)
+	require.Len(t, commands, 2)
+	assert.Equal(t, []string{"react", "react-dom", "lodash"}, commands[0].Packages)
+	assert.Equal(t, []string{"web", "api"}, []string{commands[0].Dir, commands[1].Dir})
+
+	ctx.SkipLockRun = true
+	assert.Empty(t, PlanDryRunCommands(ctx, newPlans()))
+}
+
+// TestGuardDryRun tests that a dry run never applies an update.
+//
+// It verifies:
+//   - The skipped lock commands are recorded on the context
+//   - Updater calls with dryRun=true pass through
+//   - Updater calls without dryRun are refused before reaching the updater
+//   - Runs that are not dry runs keep their updater unchanged
+func TestGuardDryRun(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+	var applied []bool
+	updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		applied = append(applied, dryRun)
+		return nil
+	}
+
+	ctx := NewUpdateContext(cfg, ".", nil).WithFlags(true, false, false).WithUpdaterFunc(updater)
+	var results []UpdateResult
+	ProcessGroupedPlansLive(ctx, []*PlannedUpdate{dryRunPlan("lodash", "lodash", "api")}, &results, ExecutionCallbacks{})
+
+	assert.Equal(t, []bool{true}, applied)
+	require.Len(t, ctx.DryRunCommands, 1)
+	assert.Equal(t, "npm install lodash@2.0.0", ctx.DryRunCommands[0].Command)
+
+	err := ctx.UpdaterFunc(testutil.NPMPackage("lodash", "1.0.0", "1.0.0"), "2.0.0", cfg, ".", false, false)
+	assert.EqualError(t, err, "dry run: refusing to apply lodash@2.0.0")
+	assert.Equal(t, []bool{true}, applied)
+
+	live := NewUpdateContext(cfg, ".", nil).WithUpdaterFunc(updater)
+	live.guardDryRun(nil)
+	require.NoError(t, live.UpdaterFunc(testutil.NPMPackage("lodash", "1.0.0", "1.0.0"), "2.0.0", cfg, ".", false, false))
+	assert.Equal(t, []bool{true, false}, applied)
+	assert.Nil(t, live.DryRunCommands)
+}
@@ -44,6 +44,20 @@ func executeUpdateCommand(cfg *config.UpdateCfg, pkg, version, constraint, dir s
 		return nil, &errors.UnsupportedError{Reason: "no commands configured"}
 	}
 
+	return cmdexec.Execute(cfg.Commands, cfg.Env, dir, cfg.TimeoutSeconds, updateReplacements(pkg, version, constraint, withAllDeps))
+}
+
+// updateReplacements builds the placeholder values of a lock command.
+//
+// Parameters:
+//   - pkg: Package name for {{package}}; empty for a group lock
+//   - version: Target version for {{version}}
+//   - constraint: Version constraint for {{constraint}}
+//   - withAllDeps: When true, {{with_all_deps_flag}} is replaced with "-W"; otherwise it's empty
+//
+// Returns:
+//   - map[string]string: Replacement values keyed by placeholder name
+func updateReplacements(pkg, version, constraint string, withAllDeps bool) map[string]string {
 	replacements := cmdexec.BuildReplacements(pkg, version, constraint)
 
 	// Add with_all_deps_flag placeholder (used by composer -W flag)
@@ -52,6 +66,5 @@ func executeUpdateCommand(cfg *config.UpdateCfg, pkg, version, constraint, dir s
 	} else {
 		replacements["with_all_deps_flag"] = ""
 	}
-
-	return cmdexec.Execute(cfg.Commands, cfg.Env, dir, cfg.TimeoutSeconds, replacements)
+	return replacements
 }
@@ -4,12 +4,14 @@ import (
 	stderrors "errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/constants"
 	"github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/systemtest"
 	"github.com/ajxudir/goupdate/pkg/verbose"
 )
 
@@ -38,8 +40,22 @@ type ExecutionCallbacks struct {
 // ValidateUpdatedPackage validates that a package was updated successfully using drift detection.
 // This is the post-update drift check that verifies the manifest and lock file were correctly updated.
 func ValidateUpdatedPackage(plan *PlannedUpdate, reloadList func() ([]formats.Package, error), baseline map[string]VersionSnapshot) error {
+	_, err := reloadAndValidate(plan, reloadList)
+	return err
+}
+
+// reloadAndValidate reloads the package list and runs the post-update drift check for plan.
+//
+// Parameters:
+//   - plan: The applied plan to check
+//   - reloadList: Function that reloads packages with installed versions; nil skips the check
+//
+// Returns:
+//   - []formats.Package: The reloaded packages (nil when reloadList is nil)
+//   - error: Reload error or version mismatch for the planned package
+func reloadAndValidate(plan *PlannedUpdate, reloadList func() ([]formats.Package, error)) ([]formats.Package, error) {
 	if reloadList == nil {
-		return nil
+		return nil, nil
 	}
 
 	// Suppress verbose output during reload to reduce noise
@@ -49,34 +65,26 @@ func ValidateUpdatedPackage(plan *PlannedUpdate, reloadList func() ([]formats.Pa
 
 	if err != nil {
 		verbose.Printf("Drift check FAILED: %s - reload error: %v\n", plan.Res.Pkg.Name, err)
-		return err
+		return nil, err
 	}
 
-	key := PackageKey(plan.Res.Pkg)
-	var found *formats.Package
-	for idx := range packages {
-		p := packages[idx]
-		if PackageKey(p) == key {
-			found = &p
-			break
-		}
-	}
+	found := findReloadedPackage(packages, plan.Res.Pkg)
 
 	if found == nil {
 		verbose.Printf("Drift check FAILED: %s not found after reload\n", plan.Res.Pkg.Name)
-		return fmt.Errorf("package %s (%s/%s) missing after update validation", plan.Res.Pkg.Name, plan.Res.Pkg.PackageType, plan.Res.Pkg.Rule)
+		return nil, fmt.Errorf("package %s (%s/%s) missing after update validation", plan.Res.Pkg.Name, plan.Res.Pkg.PackageType, plan.Res.Pkg.Rule)
 	}
 
 	if !versionsMatch(found.Version, plan.Res.Target) {
 		verbose.Printf("Drift check MISMATCH: %s expected %s, got %s\n",
 			plan.Res.Pkg.Name, plan.Res.Target, found.Version)
-		return fmt.Errorf("version mismatch after update: expected %s, found %s", plan.Res.Target, found.Version)
+		return nil, fmt.Errorf("version mismatch after update: expected %s, found %s", plan.Res.Target, found.Version)
 	}
 
 	if found.InstalledVersion != "" && found.InstalledVersion != constants.PlaceholderNA && !versionsMatch(found.InstalledVersion, plan.Res.Target) {
 		verbose.Printf("Drift check MISMATCH: %s installed=%s, expected %s (lock file not updated)\n",
 			plan.Res.Pkg.Name, found.InstalledVersion, plan.Res.Target)
-		return fmt.Errorf("installed version mismatch after update: expected %s, got %s (lock file may not have been updated)", plan.Res.Target, found.InstalledVersion)
+		return nil, fmt.Errorf("installed version mismatch after update: expected %s, got %s (lock file may not have been updated)", plan.Res.Target, found.InstalledVersion)
 	}
 
 	// Update the plan's package with the reloaded values for accurate display
@@ -84,14 +92,69 @@ func ValidateUpdatedPackage(plan *PlannedUpdate, reloadList func() ([]formats.Pa
 	plan.Res.Pkg.InstalledVersion = found.InstalledVersion
 
 	verbose.Debugf("Drift check: %s → %s ✓", plan.Res.Pkg.Name, plan.Res.Target)
-	return nil
+	return packages, nil
+}
+
+// findReloadedPackage finds a planned package in a reloaded package list.
+//
+// Workspace members and monorepo manifests can declare the same dependency, so
+// the package from the planned package's own manifest is preferred over the
+// first package with the same key.
+//
+// Parameters:
+//   - packages: The reloaded packages
+//   - pkg: The planned package
+//
+// Returns:
+//   - *formats.Package: A copy of the matching package, or nil when none matches
+func findReloadedPackage(packages []formats.Package, pkg formats.Package) *formats.Package {
+	key := PackageKey(pkg)
+	var found *formats.Package
+	for idx := range packages {
+		p := packages[idx]
+		if PackageKey(p) != key {
+			continue
+		}
+		if p.Source == pkg.Source {
+			return &p
+		}
+		if found == nil {
+			found = &p
+		}
+	}
+	return found
 }
 
 // ValidatePreUpdateState performs a pre-update drift check to verify the package is at its expected original version.
 // This detects if another process modified the package between planning and execution.
 func ValidatePreUpdateState(plan *PlannedUpdate, reloadList func() ([]formats.Package, error)) error {
+	current, ok := reloadedDeclaredVersion(plan.Res.Pkg, reloadList)
+	if !ok {
+		return nil // Non-fatal - continue with update
+	}
+
+	if !versionsMatch(current, plan.Original) {
+		verbose.Printf("Pre-update drift DETECTED: %s changed %s → %s (external modification?)\n",
+			plan.Res.Pkg.Name, plan.Original, current)
+		// Update the Original to match current state so rollback works correctly
+		plan.Original = current
+	}
+
+	return nil
+}
+
+// reloadedDeclaredVersion returns the version a package currently declares on disk.
+//
+// Parameters:
+//   - pkg: The planned package
+//   - reloadList: Function reloading the package list; may be nil
+//
+// Returns:
+//   - string: The reloaded declared version
+//   - bool: False when there is no reload function, the reload fails, or the package is gone
+func reloadedDeclaredVersion(pkg formats.Package, reloadList func() ([]formats.Package, error)) (string, bool) {
 	if reloadList == nil {
-		return nil
+		return "", false
 	}
 
 	// Suppress verbose output during reload to reduce noise
@@ -100,37 +163,36 @@ func ValidatePreUpdateState(plan *PlannedUpdate, reloadList func() ([]formats.Pa
 	verbose.Unsuppress()
 
 	if err != nil {
-		return nil // Non-fatal - continue with update
-	}
-
-	key := PackageKey(plan.Res.Pkg)
-	var found *formats.Package
-	for idx := range packages {
-		p := packages[idx]
-		if PackageKey(p) == key {
-			found = &p
-			break
-		}
+		return "", false
 	}
 
+	found := findReloadedPackage(packages, pkg)
 	if found == nil {
-		return nil // Non-fatal
+		return "", false
 	}
-
-	if !versionsMatch(found.Version, plan.Original) {
-		verbose.Printf("Pre-update drift DETECTED: %s changed %s → %s (external modification?)\n",
-			plan.Res.Pkg.Name, plan.Original, found.Version)
-		// Update the Original to match current state so rollback works correctly
-		plan.Original = found.Version
-	}
-
-	return nil
+	return found.Version, true
 }
 
 // RollbackPlans rolls back all applied plans to their original versions.
 // Returns a combined error if any rollbacks failed, allowing callers to know if rollback was successful.
+//
+// When the context holds a content backup for every plan, the captured bytes
+// of the group's manifests and lock files are written back and no lock command
+// runs. Otherwise, or when restoring the backup fails, each plan's original
+// version is re-applied through the updater. The drift check runs either way.
 func RollbackPlans(plans []*PlannedUpdate, cfg *config.Config, workDir string, ctx *UpdateContext, groupErr error, updater PackageUpdater, dryRun, skipLock bool) error {
 	verbose.Printf("Rolling back %d packages due to error: %v\n", len(plans), groupErr)
+
+	if !dryRun {
+		restored, restoreErr := ctx.restoreGroupBackup(plans)
+		if restored && restoreErr == nil {
+			return finishByteRollback(plans, ctx, groupErr)
+		}
+		if restoreErr != nil {
+			verbose.Printf("Content backup restore FAILED, re-applying original versions: %v\n", restoreErr)
+		}
+	}
+
 	var rollbackErrors []error
 
 	for _, plan := range plans {
@@ -152,12 +214,7 @@ func RollbackPlans(plans []*PlannedUpdate, cfg *config.Config, workDir string, c
 				}
 			}
 		}
-		if plan.Res.Status == constants.StatusUpdated {
-			plan.Res.Status = constants.StatusFailed
-			if plan.Res.Err == nil {
-				plan.Res.Err = groupErr
-			}
-		}
+		markRolledBack(plan, groupErr)
 	}
 
 	if len(rollbackErrors) > 0 {
@@ -168,6 +225,108 @@ func RollbackPlans(plans []*PlannedUpdate, cfg *config.Config, workDir string, c
 	return nil
 }
 
+// RollbackBatch rolls back every plan applied in a run as one set.
+//
+// Used when a check of the whole batch fails critically: a single after_all
+// test run or a rule's dedupe command cannot tell which update broke the
+// build, so every package updated in the batch is reverted to its Original
+// version instead of only being marked failed. The content backups captured
+// before each group ran are still held, so the files are restored byte for
+// byte; plans without one are re-applied through the updater.
+//
+// It performs the following operations:
+//   - Step 1: Collect the plans whose status is updated
+//   - Step 2: Roll them back with RollbackPlans, which drift-checks each package
+//   - Step 3: Copy the rolled back status into the matching results
+//   - Step 4: Stop counting the rolled back packages as successes of their rule
+//
+// Parameters:
+//   - ctx: Update context providing the configuration, updater, reload function and rule outcomes
+//   - plans: All plans of the run
+//   - results: Results of the run; entries of rolled back packages are updated in place
+//   - batchErr: The system test failure that caused the rollback
+//
+// Returns:
+//   - int: Number of packages rolled back
+//   - error: The combined rollback and drift check failures; nil when every package is back at its original version
+func RollbackBatch(ctx *UpdateContext, plans []*PlannedUpdate, results []UpdateResult, batchErr error) (int, error) {
+	applied := make([]*PlannedUpdate, 0, len(plans))
+	for _, plan := range plans {
+		if plan.Res.Status == constants.StatusUpdated {
+			applied = append(applied, plan)
+		}
+	}
+	if len(applied) == 0 {
+		return 0, nil
+	}
+
+	rollbackErr := RollbackPlans(applied, ctx.Cfg, ctx.WorkDir, ctx, batchErr, ctx.UpdaterFunc, ctx.DryRun, ctx.SkipLockRun)
+
+	for i := range results {
+		if results[i].Status != constants.StatusUpdated {
+			continue
+		}
+		key := PackageKey(results[i].Pkg)
+		for _, plan := range applied {
+			if PackageKey(plan.Res.Pkg) == key && plan.Res.Pkg.Source == results[i].Pkg.Source {
+				results[i] = plan.Res
+				break
+			}
+		}
+	}
+
+	rolledBack := make(map[string]int)
+	for _, plan := range applied {
+		rolledBack[planRule(plan)]++
+	}
+	for i := range ctx.RuleOutcomes {
+		outcome := &ctx.RuleOutcomes[i]
+		outcome.Succeeded = max(outcome.Succeeded-rolledBack[outcome.Rule], 0)
+	}
+
+	return len(applied), rollbackErr
+}
+
+// finishByteRollback verifies plans whose files were restored from a content backup.
+//
+// Parameters:
+//   - plans: The rolled back plans
+//   - ctx: Update context providing the reload function
+//   - groupErr: The error that caused the rollback
+//
+// Returns:
+//   - error: The joined drift check failures; nil when every plan is back at its original version
+func finishByteRollback(plans []*PlannedUpdate, ctx *UpdateContext, groupErr error) error {
+	var driftErrors []error
+	for _, plan := range plans {
+		verbose.Debugf("Restored %s to %s from content backup", plan.Res.Pkg.Name, plan.Original)
+		if ctx.ReloadList != nil {
+			if driftErr := verifyRollbackDrift(plan, ctx.ReloadList); driftErr != nil {
+				verbose.Printf("DRIFT CHECK FAILED for %s: %v\n", plan.Res.Pkg.Name, driftErr)
+				driftErrors = append(driftErrors, driftErr)
+			}
+		}
+		markRolledBack(plan, groupErr)
+	}
+
+	if len(driftErrors) > 0 {
+		verbose.Printf("Rollback completed with %d errors\n", len(driftErrors))
+		return stderrors.Join(driftErrors...)
+	}
+	verbose.Debugf("Rollback from content backup completed successfully for all %d packages", len(plans))
+	return nil
+}
+
+// markRolledBack marks a plan that was updated before the rollback as failed with the group error.
+func markRolledBack(plan *PlannedUpdate, groupErr error) {
+	if plan.Res.Status == constants.StatusUpdated {
+		plan.Res.Status = constants.StatusFailed
+		if plan.Res.Err == nil {
+			plan.Res.Err = groupErr
+		}
+	}
+}
+
 // verifyRollbackDrift verifies that a rollback actually restored the package to its original version.
 // This drift check helps detect cases where the rollback command succeeded but the manifest wasn't updated.
 func verifyRollbackDrift(plan *PlannedUpdate, reloadList func() ([]formats.Package, error)) error {
@@ -185,15 +344,7 @@ func verifyRollbackDrift(plan *PlannedUpdate, reloadList func() ([]formats.Packa
 		return fmt.Errorf("drift check failed: could not reload packages: %w", err)
 	}
 
-	key := PackageKey(plan.Res.Pkg)
-	var found *formats.Package
-	for idx := range packages {
-		p := packages[idx]
-		if PackageKey(p) == key {
-			found = &p
-			break
-		}
-	}
+	found := findReloadedPackage(packages, plan.Res.Pkg)
 
 	if found == nil {
 		verbose.Printf("Rollback drift check FAILED: %s not found after reload\n", plan.Res.Pkg.Name)
@@ -250,14 +401,26 @@ func ApplyPlannedUpdate(plan *PlannedUpdate, cfg *config.Config, workDir string,
 func ShouldTrackUnsupported(status string) bool {
 	return strings.EqualFold(status, lock.InstallStatusNotConfigured) ||
 		strings.EqualFold(status, lock.InstallStatusFloating) ||
-		strings.EqualFold(status, lock.InstallStatusVersionMissing)
+		strings.EqualFold(status, lock.InstallStatusNonRegistry) ||
+		strings.EqualFold(status, lock.InstallStatusVersionMissing) ||
+		strings.EqualFold(status, lock.InstallStatusLockUnavailable)
 }
 
 // CollectUpdateErrors collects errors from update results.
-func CollectUpdateErrors(results []UpdateResult) []error {
+//
+// Results whose status the config's severity mapping sets to warn or ignore
+// are left out, so they do not drive the exit code.
+//
+// Parameters:
+//   - results: Update results to collect from
+//   - cfg: Configuration holding the severity mapping; nil counts every error
+//
+// Returns:
+//   - []error: Errors of results whose status maps to config.SeverityError
+func CollectUpdateErrors(results []UpdateResult, cfg *config.Config) []error {
 	var errs []error
 	for _, res := range results {
-		if res.Err != nil && !errors.IsUnsupported(res.Err) {
+		if res.Err != nil && !errors.IsUnsupported(res.Err) && cfg.StatusSeverity(res.Pkg.Rule, res.Status) == config.SeverityError {
 			errs = append(errs, res.Err)
 		}
 	}
@@ -271,39 +434,233 @@ type SystemTestFailure struct {
 	IsCritical bool
 }
 
+// SystemTestDetails is implemented by system test results that can list their failed tests.
+//
+// Structured output detects it on SystemTestFailure.Result with a type
+// assertion, so results that only format their output keep working.
+type SystemTestDetails interface {
+	FailedTests() []systemtest.TestResult
+}
+
 // ExecutionOptions holds options for execution functions.
 type ExecutionOptions struct {
 	DryRun      bool
 	SkipLockRun bool
 }
 
+// RuleOutcome summarizes how the plans of a single rule were processed.
+//
+// Rules are processed independently, so a failure in one rule (e.g., a
+// composer lock command error) does not affect the outcome of another.
+type RuleOutcome struct {
+	// Rule is the rule name the outcome belongs to.
+	Rule string
+
+	// Succeeded is the number of packages that were updated or planned.
+	Succeeded int
+
+	// Failures contains the errors recorded while processing the rule's plans.
+	Failures []error
+}
+
+// FullySucceeded reports whether the rule updated at least one package without any failure.
+//
+// Returns:
+//   - bool: true when the rule has successes and no failures
+func (o RuleOutcome) FullySucceeded() bool {
+	return o.Succeeded > 0 && len(o.Failures) == 0
+}
+
+// CountFullySucceededRules returns the number of rules that fully succeeded.
+//
+// Parameters:
+//   - outcomes: Per-rule outcomes collected during execution
+//
+// Returns:
+//   - int: Number of outcomes for which FullySucceeded is true
+func CountFullySucceededRules(outcomes []RuleOutcome) int {
+	count := 0
+	for _, outcome := range outcomes {
+		if outcome.FullySucceeded() {
+			count++
+		}
+	}
+	return count
+}
+
+// PartitionPlans splits plans into consecutive runs sharing the same key.
+//
+// Plans are expected to be sorted (see SortResolvedPlans) so that all plans
+// with the same key are adjacent.
+//
+// Parameters:
+//   - plans: Sorted planned updates
+//   - key: Function returning the partition key of a plan
+//
+// Returns:
+//   - [][]*PlannedUpdate: Consecutive partitions in their original order
+func PartitionPlans(plans []*PlannedUpdate, key func(*PlannedUpdate) string) [][]*PlannedUpdate {
+	var partitions [][]*PlannedUpdate
+	start := 0
+	for start < len(plans) {
+		end := start + 1
+		for end < len(plans) && key(plans[end]) == key(plans[start]) {
+			end++
+		}
+		partitions = append(partitions, plans[start:end])
+		start = end
+	}
+	return partitions
+}
+
+// planRule returns the rule name of a plan, used as the rule partition key.
+func planRule(plan *PlannedUpdate) string {
+	return plan.Res.Pkg.Rule
+}
+
+// planGroup returns the group key of a plan, used as the group partition key.
+func planGroup(plan *PlannedUpdate) string {
+	return plan.GroupKey
+}
+
+// ruleBatches splits a rule's plans into the batches processed together.
+//
+// Normally every update group is its own batch. When the rule runs its lock
+// command once for the whole run (see UpdateContext.groupsRuleCommands), all
+// plans form a single batch: every manifest edit of every group is applied
+// first, the lock command runs once, every applied plan is validated, and a
+// failure rolls back every applied plan of the rule.
+//
+// Parameters:
+//   - ctx: Update context holding the run flags and configuration
+//   - plans: Sorted planned updates belonging to a single rule
+//
+// Returns:
+//   - [][]*PlannedUpdate: The batches in plan order
+func ruleBatches(ctx *UpdateContext, plans []*PlannedUpdate) [][]*PlannedUpdate {
+	groups := PartitionPlans(plans, planGroup)
+	if len(groups) > 1 && !ctx.DryRun && ctx.groupsRuleCommands(planRule(plans[0]), plans) {
+		verbose.Debugf("Rule %s: running one lock command for %d groups", planRule(plans[0]), len(groups))
+		return [][]*PlannedUpdate{plans}
+	}
+	return groups
+}
+
+// processRulePlans processes the groups of a single rule and records its outcome.
+//
+// It performs the following operations:
+//   - Step 1: Split the rule's plans into update groups, or a single batch (see ruleBatches)
+//   - Step 2: Process the groups with the given group processor, in parallel when enabled (see runRuleGroups)
+//   - Step 3: Run the rule's dedupe command when --dedupe is set (see runRuleDedupe)
+//   - Step 4: Record the failures and successes attributable to this rule on the context
+//
+// Groups never span rules, so a group failure (and SummarizeGroupFailure)
+// only ever affects the plans of the rule it belongs to.
+//
+// Parameters:
+//   - ctx: Update context containing configuration and tracking state
+//   - plans: Planned updates belonging to a single rule
+//   - results: Pointer to results slice receiving the rule's results
+//   - processGroup: Function processing a single group of plans into the given results slice
+func processRulePlans(ctx *UpdateContext, plans []*PlannedUpdate, results *[]UpdateResult, processGroup func(groupPlans []*PlannedUpdate, results *[]UpdateResult)) {
+	rule := planRule(plans[0])
+	failuresBefore := len(ctx.Failures)
+	resultsBefore := len(*results)
+
+	verbose.Debugf("Processing %d packages for rule %s", len(plans), rule)
+
+	runRuleGroups(ctx, ruleBatches(ctx, plans), results, processGroup)
+	runRuleDedupe(ctx, plans, (*results)[resultsBefore:], failuresBefore)
+
+	outcome := RuleOutcome{Rule: rule}
+	if len(ctx.Failures) > failuresBefore {
+		outcome.Failures = append([]error(nil), ctx.Failures[failuresBefore:]...)
+	}
+	for _, res := range (*results)[resultsBefore:] {
+		if res.Status == constants.StatusUpdated || res.Status == constants.StatusPlanned {
+			outcome.Succeeded++
+		}
+	}
+
+	if len(outcome.Failures) > 0 {
+		verbose.Infof("Rule %s: %d updated, %d failed", rule, outcome.Succeeded, len(outcome.Failures))
+	}
+	ctx.RuleOutcomes = append(ctx.RuleOutcomes, outcome)
+}
+
 // ProcessGroupedPlansLive processes all grouped plans with live output.
+//
+// Each rule's plans are processed independently: errors are collected per
+// rule (see UpdateContext.RuleOutcomes) and never abort the other rules.
+// When the context set via UpdateContext.WithContext is cancelled, no further
+// plans are started; results collected so far are kept and the cancellation
+// is recorded as a failure.
+//
+// Within a rule, groups run in parallel up to UpdateContext.GroupConcurrency;
+// groups sharing a lock file are serialized and results are merged in plan order.
+//
+// In a dry run the lock commands a real run would execute are recorded in
+// UpdateContext.DryRunCommands and the updater refuses to apply anything (see guardDryRun).
 func ProcessGroupedPlansLive(ctx *UpdateContext, plans []*PlannedUpdate, results *[]UpdateResult, callbacks ExecutionCallbacks) {
 	if len(plans) == 0 {
 		return
 	}
 
 	verbose.Debugf("Processing %d packages for update", len(plans))
+	ctx.recordTargets(plans)
+	ctx.guardDryRun(plans)
+	if ctx.groupConcurrency() > 1 {
+		callbacks = syncCallbacks(callbacks)
+	}
 
-	start := 0
-	for start < len(plans) {
-		end := start + 1
-		for end < len(plans) && plans[end].GroupKey == plans[start].GroupKey {
-			end++
+	for _, rulePlans := range PartitionPlans(plans, planRule) {
+		if ctx.checkCancelled() != nil {
+			return
 		}
+		processRulePlans(ctx, rulePlans, results, func(groupPlans []*PlannedUpdate, groupResults *[]UpdateResult) {
+			processGroupPlansLive(ctx, groupPlans, groupResults, callbacks)
+		})
+	}
+}
 
-		processGroupPlansLive(ctx, plans[start:end], results, callbacks)
-		start = end
+// groupLockConfig returns the update configuration used for a group's shared lock command.
+//
+// The commands and environment come from the first plan with a configuration.
+// The timeout is the most generous one in the group so a package with a longer
+// per-package timeout_seconds is not cut short by a sibling's shorter limit;
+// a plan without a timeout (0) disables the limit for the whole group.
+//
+// Parameters:
+//   - plans: Planned updates for packages in the group
+//
+// Returns:
+//   - *config.UpdateCfg: A copy of the group's update configuration, or nil if no plan has one
+func groupLockConfig(plans []*PlannedUpdate) *config.UpdateCfg {
+	var groupCfg *config.UpdateCfg
+	for _, plan := range plans {
+		if plan.Cfg == nil {
+			continue
+		}
+		if groupCfg == nil {
+			cloned := *plan.Cfg
+			groupCfg = &cloned
+			continue
+		}
+		if groupCfg.TimeoutSeconds > 0 && (plan.Cfg.TimeoutSeconds <= 0 || plan.Cfg.TimeoutSeconds > groupCfg.TimeoutSeconds) {
+			groupCfg.TimeoutSeconds = plan.Cfg.TimeoutSeconds
+		}
 	}
+	return groupCfg
 }
 
 // processGroupPlansLive processes a single group of plans with live output and rollback support.
 //
 // It performs the following operations:
 //   - Step 1: Determine if group-level locking should be used (when multiple packages in group)
-//   - Step 2: Process packages either with group lock or individually
+//   - Step 2: Process packages either with group lock, individually, or staged (see processGroupStaged)
 //   - Step 3: Rollback all applied updates if group-level error occurs
-//   - Step 4: Display system test failures if any occurred
+//   - Step 4: Commit the packages that are still updated when commit mode is enabled
+//   - Step 5: Display system test failures if any occurred
 //
 // Parameters:
 //   - ctx: Update context containing configuration and tracking state
@@ -321,18 +678,24 @@ func processGroupPlansLive(ctx *UpdateContext, plans []*PlannedUpdate, results *
 	useGroupLock := len(plans) > 1
 	var groupUpdateCfg *config.UpdateCfg
 	if useGroupLock {
-		for _, plan := range plans {
-			if plan.Cfg != nil {
-				groupUpdateCfg = plan.Cfg
-				break
-			}
-		}
+		groupUpdateCfg = groupLockConfig(plans)
 	}
 
 	var groupErr error
 	applied := make([]*PlannedUpdate, 0, len(plans))
 	var systemTestFailures []SystemTestFailure
 
+	if ctx.Staged {
+		// Staged steps run their own lock commands and keep the last good version, so there is no group rollback
+		_ = processGroupStaged(ctx, plans, results, &systemTestFailures, nil, callbacks)
+		ctx.commitGroup(plans)
+		DisplaySystemTestFailures(systemTestFailures)
+		ctx.appendSystemTestFailures(systemTestFailures...)
+		return
+	}
+
+	ctx.captureGroupBackup(plans)
+
 	if useGroupLock && !ctx.DryRun && !ctx.SkipLockRun {
 		groupErr = processGroupWithGroupLock(ctx, plans, groupUpdateCfg, &applied, results, &systemTestFailures, callbacks)
 	} else {
@@ -346,15 +709,17 @@ func processGroupPlansLive(ctx *UpdateContext, plans []*PlannedUpdate, results *
 		}
 		SummarizeGroupFailure(plans, groupErr)
 	}
+	ctx.commitGroup(plans)
 
 	DisplaySystemTestFailures(systemTestFailures)
+	ctx.appendSystemTestFailures(systemTestFailures...)
 }
 
 // processGroupWithGroupLock processes a group using a single lock command for all packages.
 //
 // It performs the following operations:
 //   - Step 1: Update declared versions for all packages (skip lock commands)
-//   - Step 2: Run a single group-level lock command after all updates
+//   - Step 2: Run a single group-level lock command after all updates, then the workspace sync (see runWorkspaceSync)
 //   - Step 3: Validate all packages were updated correctly
 //   - Step 4: Run system tests if configured
 //   - Step 5: Append results and invoke display callbacks
@@ -376,8 +741,15 @@ func processGroupWithGroupLock(ctx *UpdateContext, plans []*PlannedUpdate, group
 	}
 
 	var groupErr error
+	var cancelErr error
 
 	for _, plan := range plans {
+		// Stop before touching another manifest; the partially applied group is rolled back by the caller
+		if cancelErr = ctx.checkCancelled(); cancelErr != nil {
+			groupErr = stderrors.Join(groupErr, cancelErr)
+			break
+		}
+
 		res := &plan.Res
 		if ShouldSkipUpdate(res) {
 			handleSkippedUpdate(ctx, res, results, callbacks)
@@ -389,7 +761,9 @@ func processGroupWithGroupLock(ctx *UpdateContext, plans []*PlannedUpdate, group
 			_ = ValidatePreUpdateState(plan, ctx.ReloadList)
 		}
 
+		start := time.Now()
 		updateErr := ctx.UpdaterFunc(plan.Res.Pkg, plan.Res.Target, ctx.Cfg, ctx.WorkDir, ctx.DryRun, true)
+		res.Duration = time.Since(start)
 		if updateErr != nil {
 			HandleUpdateError(updateErr, res, ctx, callbacks.DeriveReason)
 			if !errors.IsUnsupported(updateErr) {
@@ -401,19 +775,30 @@ func processGroupWithGroupLock(ctx *UpdateContext, plans []*PlannedUpdate, group
 		*applied = append(*applied, plan)
 	}
 
+	if cancelErr != nil {
+		for _, plan := range *applied {
+			plan.Res.Status = constants.StatusFailed
+			plan.Res.Err = cancelErr
+		}
+	}
+
 	if len(*applied) > 0 && groupErr == nil && !ctx.DryRun {
 		// Check if any package in the group needs -W flag (with all dependencies)
 		withAllDeps := false
 		for _, plan := range *applied {
-			if ruleCfg, ok := ctx.Cfg.Rules[plan.Res.Pkg.Rule]; ok {
-				if ruleCfg.ShouldUpdateWithAllDependencies(plan.Res.Pkg.Name) {
-					withAllDeps = true
-					break
-				}
+			if allowsTransitive(ctx.Cfg, plan.Res.Pkg) {
+				withAllDeps = true
+				break
 			}
 		}
 		verbose.Debugf("Post-manifest drift check: running group lock command to sync lock file")
-		lockErr := RunGroupLockCommand(groupUpdateCfg, ctx.WorkDir, withAllDeps)
+		lockStart := time.Now()
+		lockErr := runGroupLock(groupUpdateCfg, ctx.Cfg, ctx.WorkDir, *applied, withAllDeps)
+		// The shared lock command counts towards every package it synced
+		lockDuration := time.Since(lockStart)
+		for _, plan := range *applied {
+			plan.Res.Duration += lockDuration
+		}
 		if lockErr != nil {
 			groupErr = lockErr
 			ctx.AppendFailure(fmt.Errorf("group lock failed: %w", lockErr))
@@ -421,12 +806,14 @@ func processGroupWithGroupLock(ctx *UpdateContext, plans []*PlannedUpdate, group
 				plan.Res.Status = constants.StatusFailed
 				plan.Res.Err = lockErr
 			}
+		} else {
+			runWorkspaceSync(ctx, *applied)
 		}
 	}
 
 	if groupErr == nil {
 		for _, plan := range *applied {
-			validateErr := ValidateUpdatedPackage(plan, ctx.ReloadList, ctx.Baseline)
+			validateErr := ctx.validateUpdate(plan)
 			if validateErr != nil {
 				plan.Res.Status = constants.StatusFailed
 				plan.Res.Err = validateErr
@@ -460,7 +847,7 @@ func processGroupWithGroupLock(ctx *UpdateContext, plans []*PlannedUpdate, group
 // processGroupPerPackage processes each package in a group individually with separate lock commands.
 //
 // It performs the following operations:
-//   - Step 1: For each package, update declared version and run individual lock command
+//   - Step 1: For each package, update declared version, run individual lock command and the workspace sync
 //   - Step 2: Validate each package after update
 //   - Step 3: Run system tests after each package if configured
 //   - Step 4: Append results and invoke display callbacks for each package
@@ -479,6 +866,11 @@ func processGroupPerPackage(ctx *UpdateContext, plans []*PlannedUpdate, applied
 	var groupErr error
 
 	for _, plan := range plans {
+		// Each applied package already ran its own lock command, so stopping here leaves no partial state
+		if ctx.checkCancelled() != nil {
+			return groupErr
+		}
+
 		res := &plan.Res
 		if ShouldSkipUpdate(res) {
 			handleSkippedUpdate(ctx, res, results, callbacks)
@@ -490,7 +882,9 @@ func processGroupPerPackage(ctx *UpdateContext, plans []*PlannedUpdate, applied
 			_ = ValidatePreUpdateState(plan, ctx.ReloadList)
 		}
 
+		start := time.Now()
 		updateErr := ApplyPlannedUpdate(plan, ctx.Cfg, ctx.WorkDir, ctx.UpdaterFunc, ctx.DryRun, ctx.SkipLockRun)
+		res.Duration = time.Since(start)
 		if updateErr != nil {
 			HandleUpdateError(updateErr, res, ctx, callbacks.DeriveReason)
 			if !errors.IsUnsupported(updateErr) {
@@ -506,7 +900,8 @@ func processGroupPerPackage(ctx *UpdateContext, plans []*PlannedUpdate, applied
 
 		*applied = append(*applied, plan)
 		if !ctx.DryRun {
-			validateErr := ValidateUpdatedPackage(plan, ctx.ReloadList, ctx.Baseline)
+			runWorkspaceSync(ctx, []*PlannedUpdate{plan})
+			validateErr := ctx.validateUpdate(plan)
 			if validateErr != nil {
 				res.Status = constants.StatusFailed
 				res.Err = validateErr
@@ -526,7 +921,9 @@ func processGroupPerPackage(ctx *UpdateContext, plans []*PlannedUpdate, applied
 		RefreshAvailableVersions(plan)
 
 		if ctx.ShouldRunSystemTestsAfterEach() {
-			_ = runPackageSystemTests(ctx, plan, &groupErr, systemTestFailures)
+			if testErr := runPackageSystemTests(ctx, plan, systemTestFailures); testErr != nil {
+				groupErr = stderrors.Join(groupErr, testErr)
+			}
 		}
 
 		appendResultAndPrint(ctx, res, results, callbacks)
@@ -613,6 +1010,11 @@ func runGroupSystemTests(ctx *UpdateContext, applied []*PlannedUpdate, systemTes
 			plan.Res.Status = constants.StatusFailed
 			plan.Res.Err = fmt.Errorf("system tests failed: %s", testResult.Summary())
 		}
+		*systemTestFailures = append(*systemTestFailures, SystemTestFailure{
+			PkgName:    "group",
+			Result:     testResult,
+			IsCritical: true,
+		})
 		err := fmt.Errorf("system tests failed: %s", testResult.Summary())
 		ctx.AppendFailure(err)
 		return err
@@ -644,12 +1046,11 @@ func runGroupSystemTests(ctx *UpdateContext, applied []*PlannedUpdate, systemTes
 // Parameters:
 //   - ctx: Update context with system test runner configuration
 //   - plan: The planned update that was applied
-//   - groupErr: Pointer to group error to accumulate errors
 //   - systemTestFailures: Pointer to slice collecting system test failures
 //
 // Returns:
-//   - error: Returns nil; errors are tracked via context and groupErr pointer
-func runPackageSystemTests(ctx *UpdateContext, plan *PlannedUpdate, groupErr *error, systemTestFailures *[]SystemTestFailure) error {
+//   - error: Returns error if critical tests fail and stop-on-fail is enabled; returns nil otherwise
+func runPackageSystemTests(ctx *UpdateContext, plan *PlannedUpdate, systemTestFailures *[]SystemTestFailure) error {
 	testResult := ctx.SystemTestRunner.RunAfterUpdate()
 	plan.Res.SystemTestResult = testResult
 	isCritical := testResult.HasCriticalFailure() && ctx.SystemTestRunner.StopOnFail()
@@ -673,7 +1074,6 @@ func runPackageSystemTests(ctx *UpdateContext, plan *PlannedUpdate, groupErr *er
 		plan.Res.Status = constants.StatusFailed
 		plan.Res.Err = fmt.Errorf("system tests failed: %s", testResult.Summary())
 		ctx.AppendFailure(fmt.Errorf("%s: %w", plan.Res.Pkg.Name, plan.Res.Err))
-		*groupErr = stderrors.Join(*groupErr, plan.Res.Err)
 	}
 	if !testResult.Passed() {
 		verbose.Debugf("System tests: %d/%d passed for %s (%v)",
@@ -687,6 +1087,9 @@ func runPackageSystemTests(ctx *UpdateContext, plan *PlannedUpdate, groupErr *er
 		verbose.Debugf("System tests passed for %s (%d/%d, %v)",
 			plan.Res.Pkg.Name, testResult.PassedCount(), len(testResult.Tests), testResult.TotalDuration)
 	}
+	if isCritical {
+		return plan.Res.Err
+	}
 	return nil
 }
 
@@ -708,22 +1111,33 @@ func DisplaySystemTestFailures(failures []SystemTestFailure) {
 }
 
 // ProcessGroupedPlansWithProgress processes all grouped plans with progress indicator.
+//
+// Like ProcessGroupedPlansLive, each rule's plans are processed independently
+// and their outcomes are recorded on the context. Cancellation is honoured
+// between plans in the same way, and groups run in parallel under the same
+// rules; progress increments are serialized across groups. When progress
+// implements PackageProgressReporter it is told the position, total and name
+// of every finished package instead of being incremented.
 func ProcessGroupedPlansWithProgress(ctx *UpdateContext, plans []*PlannedUpdate, results *[]UpdateResult, progress ProgressReporter, callbacks ExecutionCallbacks) {
 	if len(plans) == 0 {
 		return
 	}
 
 	verbose.Debugf("Processing %d packages for update", len(plans))
+	ctx.recordTargets(plans)
+	ctx.guardDryRun(plans)
+	progress = trackProgress(progress, len(plans))
+	if ctx.groupConcurrency() > 1 {
+		callbacks = syncCallbacks(callbacks)
+	}
 
-	start := 0
-	for start < len(plans) {
-		end := start + 1
-		for end < len(plans) && plans[end].GroupKey == plans[start].GroupKey {
-			end++
+	for _, rulePlans := range PartitionPlans(plans, planRule) {
+		if ctx.checkCancelled() != nil {
+			return
 		}
-
-		processGroupPlansWithProgress(ctx, plans[start:end], results, progress, callbacks)
-		start = end
+		processRulePlans(ctx, rulePlans, results, func(groupPlans []*PlannedUpdate, groupResults *[]UpdateResult) {
+			processGroupPlansWithProgress(ctx, groupPlans, groupResults, progress, callbacks)
+		})
 	}
 }
 
@@ -732,12 +1146,29 @@ type ProgressReporter interface {
 	Increment()
 }
 
+// PackageProgressReporter is a ProgressReporter that also learns which package finished.
+//
+// Execution functions detect it with a type assertion, so reporters that only
+// implement Increment keep working unchanged.
+type PackageProgressReporter interface {
+	ProgressReporter
+
+	// ReportPackage is called instead of Increment once a package finishes.
+	//
+	// Parameters:
+	//   - current: Number of packages finished so far, including this one
+	//   - total: Number of packages in the run
+	//   - name: Name of the package that finished
+	ReportPackage(current, total int, name string)
+}
+
 // processGroupPlansWithProgress processes a single group with progress indicator and rollback support.
 //
 // It performs the following operations:
 //   - Step 1: Determine if group-level locking should be used
-//   - Step 2: Process packages with progress reporting
+//   - Step 2: Process packages with progress reporting, staged when ctx.Staged is set
 //   - Step 3: Rollback all applied updates if group-level error occurs
+//   - Step 4: Commit the packages that are still updated when commit mode is enabled
 //
 // Parameters:
 //   - ctx: Update context containing configuration and tracking state
@@ -756,17 +1187,23 @@ func processGroupPlansWithProgress(ctx *UpdateContext, plans []*PlannedUpdate, r
 	useGroupLock := len(plans) > 1
 	var groupUpdateCfg *config.UpdateCfg
 	if useGroupLock {
-		for _, plan := range plans {
-			if plan.Cfg != nil {
-				groupUpdateCfg = plan.Cfg
-				break
-			}
-		}
+		groupUpdateCfg = groupLockConfig(plans)
 	}
 
 	var groupErr error
 	applied := make([]*PlannedUpdate, 0, len(plans))
 
+	if ctx.Staged {
+		// Structured output must not receive live rows, so steps are not reported through OnResultReady
+		stagedCallbacks := callbacks
+		stagedCallbacks.OnResultReady = nil
+		_ = processGroupStaged(ctx, plans, results, &ctx.SystemTestFailures, progress, stagedCallbacks)
+		ctx.commitGroup(plans)
+		return
+	}
+
+	ctx.captureGroupBackup(plans)
+
 	if useGroupLock && !ctx.DryRun && !ctx.SkipLockRun {
 		groupErr = processGroupWithGroupLockProgress(ctx, plans, groupUpdateCfg, &applied, results, progress, callbacks)
 	} else {
@@ -780,13 +1217,14 @@ func processGroupPlansWithProgress(ctx *UpdateContext, plans []*PlannedUpdate, r
 		}
 		SummarizeGroupFailure(plans, groupErr)
 	}
+	ctx.commitGroup(plans)
 }
 
 // processGroupWithGroupLockProgress processes a group using a single lock command with progress reporting.
 //
 // It performs the following operations:
 //   - Step 1: Update declared versions for all packages
-//   - Step 2: Run a single group-level lock command after all updates
+//   - Step 2: Run a single group-level lock command after all updates, then the workspace sync (see runWorkspaceSync)
 //   - Step 3: Validate all packages were updated correctly
 //   - Step 4: Append results and increment progress for each package
 //
@@ -807,17 +1245,22 @@ func processGroupWithGroupLockProgress(ctx *UpdateContext, plans []*PlannedUpdat
 	}
 
 	var groupErr error
+	var cancelErr error
 
 	for _, plan := range plans {
+		// Stop before touching another manifest; the partially applied group is rolled back by the caller
+		if cancelErr = ctx.checkCancelled(); cancelErr != nil {
+			groupErr = stderrors.Join(groupErr, cancelErr)
+			break
+		}
+
 		res := &plan.Res
 		if ShouldSkipUpdate(res) {
 			if ShouldTrackUnsupported(res.Status) {
 				ctx.Unsupported.Add(res.Pkg, callbacks.DeriveReason(res.Pkg, ctx.Cfg, res.Err, false))
 			}
 			*results = append(*results, *res)
-			if progress != nil {
-				progress.Increment()
-			}
+			advanceProgress(progress, res.Pkg.Name)
 			continue
 		}
 
@@ -826,7 +1269,9 @@ func processGroupWithGroupLockProgress(ctx *UpdateContext, plans []*PlannedUpdat
 			_ = ValidatePreUpdateState(plan, ctx.ReloadList)
 		}
 
+		start := time.Now()
 		updateErr := ctx.UpdaterFunc(plan.Res.Pkg, plan.Res.Target, ctx.Cfg, ctx.WorkDir, ctx.DryRun, true)
+		res.Duration = time.Since(start)
 		if updateErr != nil {
 			HandleUpdateError(updateErr, res, ctx, callbacks.DeriveReason)
 			if !errors.IsUnsupported(updateErr) {
@@ -838,19 +1283,30 @@ func processGroupWithGroupLockProgress(ctx *UpdateContext, plans []*PlannedUpdat
 		*applied = append(*applied, plan)
 	}
 
+	if cancelErr != nil {
+		for _, plan := range *applied {
+			plan.Res.Status = constants.StatusFailed
+			plan.Res.Err = cancelErr
+		}
+	}
+
 	if len(*applied) > 0 && groupErr == nil && !ctx.DryRun {
 		// Check if any package in the group needs -W flag (with all dependencies)
 		withAllDeps := false
 		for _, plan := range *applied {
-			if ruleCfg, ok := ctx.Cfg.Rules[plan.Res.Pkg.Rule]; ok {
-				if ruleCfg.ShouldUpdateWithAllDependencies(plan.Res.Pkg.Name) {
-					withAllDeps = true
-					break
-				}
+			if allowsTransitive(ctx.Cfg, plan.Res.Pkg) {
+				withAllDeps = true
+				break
 			}
 		}
 		verbose.Debugf("Post-manifest drift check: running group lock command to sync lock file")
-		lockErr := RunGroupLockCommand(groupUpdateCfg, ctx.WorkDir, withAllDeps)
+		lockStart := time.Now()
+		lockErr := runGroupLock(groupUpdateCfg, ctx.Cfg, ctx.WorkDir, *applied, withAllDeps)
+		// The shared lock command counts towards every package it synced
+		lockDuration := time.Since(lockStart)
+		for _, plan := range *applied {
+			plan.Res.Duration += lockDuration
+		}
 		if lockErr != nil {
 			groupErr = lockErr
 			ctx.AppendFailure(fmt.Errorf("group lock failed: %w", lockErr))
@@ -858,12 +1314,14 @@ func processGroupWithGroupLockProgress(ctx *UpdateContext, plans []*PlannedUpdat
 				plan.Res.Status = constants.StatusFailed
 				plan.Res.Err = lockErr
 			}
+		} else {
+			runWorkspaceSync(ctx, *applied)
 		}
 	}
 
 	if groupErr == nil {
 		for _, plan := range *applied {
-			validateErr := ValidateUpdatedPackage(plan, ctx.ReloadList, ctx.Baseline)
+			validateErr := ctx.validateUpdate(plan)
 			if validateErr != nil {
 				plan.Res.Status = constants.StatusFailed
 				plan.Res.Err = validateErr
@@ -874,24 +1332,15 @@ func processGroupWithGroupLockProgress(ctx *UpdateContext, plans []*PlannedUpdat
 				plan.Res.Err = nil
 				RefreshAvailableVersions(plan)
 			}
-			if ShouldTrackUnsupported(plan.Res.Status) {
-				ctx.Unsupported.Add(plan.Res.Pkg, callbacks.DeriveReason(plan.Res.Pkg, ctx.Cfg, plan.Res.Err, false))
-			}
-			*results = append(*results, plan.Res)
-			if progress != nil {
-				progress.Increment()
-			}
 		}
-	} else {
-		for _, plan := range *applied {
-			if ShouldTrackUnsupported(plan.Res.Status) {
-				ctx.Unsupported.Add(plan.Res.Pkg, callbacks.DeriveReason(plan.Res.Pkg, ctx.Cfg, plan.Res.Err, false))
-			}
-			*results = append(*results, plan.Res)
-			if progress != nil {
-				progress.Increment()
-			}
+	}
+
+	for _, plan := range *applied {
+		if ShouldTrackUnsupported(plan.Res.Status) {
+			ctx.Unsupported.Add(plan.Res.Pkg, callbacks.DeriveReason(plan.Res.Pkg, ctx.Cfg, plan.Res.Err, false))
 		}
+		*results = append(*results, plan.Res)
+		advanceProgress(progress, plan.Res.Pkg.Name)
 	}
 
 	return groupErr
@@ -900,7 +1349,7 @@ func processGroupWithGroupLockProgress(ctx *UpdateContext, plans []*PlannedUpdat
 // processGroupPerPackageProgress processes each package individually with separate lock commands and progress reporting.
 //
 // It performs the following operations:
-//   - Step 1: For each package, update declared version and run individual lock command
+//   - Step 1: For each package, update declared version, run individual lock command and the workspace sync
 //   - Step 2: Validate each package after update
 //   - Step 3: Append results and increment progress for each package
 //
@@ -918,15 +1367,18 @@ func processGroupPerPackageProgress(ctx *UpdateContext, plans []*PlannedUpdate,
 	var groupErr error
 
 	for _, plan := range plans {
+		// Each applied package already ran its own lock command, so stopping here leaves no partial state
+		if ctx.checkCancelled() != nil {
+			return groupErr
+		}
+
 		res := &plan.Res
 		if ShouldSkipUpdate(res) {
 			if ShouldTrackUnsupported(res.Status) {
 				ctx.Unsupported.Add(res.Pkg, callbacks.DeriveReason(res.Pkg, ctx.Cfg, res.Err, false))
 			}
 			*results = append(*results, *res)
-			if progress != nil {
-				progress.Increment()
-			}
+			advanceProgress(progress, res.Pkg.Name)
 			continue
 		}
 
@@ -935,7 +1387,9 @@ func processGroupPerPackageProgress(ctx *UpdateContext, plans []*PlannedUpdate,
 			_ = ValidatePreUpdateState(plan, ctx.ReloadList)
 		}
 
+		start := time.Now()
 		updateErr := ApplyPlannedUpdate(plan, ctx.Cfg, ctx.WorkDir, ctx.UpdaterFunc, ctx.DryRun, ctx.SkipLockRun)
+		res.Duration = time.Since(start)
 		if updateErr != nil {
 			HandleUpdateError(updateErr, res, ctx, callbacks.DeriveReason)
 			if !errors.IsUnsupported(updateErr) {
@@ -945,9 +1399,7 @@ func processGroupPerPackageProgress(ctx *UpdateContext, plans []*PlannedUpdate,
 				ctx.Unsupported.Add(res.Pkg, callbacks.DeriveReason(res.Pkg, ctx.Cfg, res.Err, false))
 			}
 			*results = append(*results, *res)
-			if progress != nil {
-				progress.Increment()
-			}
+			advanceProgress(progress, res.Pkg.Name)
 			// Stop on first error unless ContinueOnError is set
 			if !ctx.ContinueOnError && !errors.IsUnsupported(updateErr) {
 				return groupErr
@@ -957,7 +1409,8 @@ func processGroupPerPackageProgress(ctx *UpdateContext, plans []*PlannedUpdate,
 
 		*applied = append(*applied, plan)
 		if !ctx.DryRun {
-			validateErr := ValidateUpdatedPackage(plan, ctx.ReloadList, ctx.Baseline)
+			runWorkspaceSync(ctx, []*PlannedUpdate{plan})
+			validateErr := ctx.validateUpdate(plan)
 			if validateErr != nil {
 				res.Status = constants.StatusFailed
 				res.Err = validateErr
@@ -967,9 +1420,7 @@ func processGroupPerPackageProgress(ctx *UpdateContext, plans []*PlannedUpdate,
 					ctx.Unsupported.Add(res.Pkg, callbacks.DeriveReason(res.Pkg, ctx.Cfg, res.Err, false))
 				}
 				*results = append(*results, *res)
-				if progress != nil {
-					progress.Increment()
-				}
+				advanceProgress(progress, res.Pkg.Name)
 				// Stop on first validation error unless ContinueOnError is set
 				if !ctx.ContinueOnError {
 					return groupErr
@@ -986,9 +1437,7 @@ func processGroupPerPackageProgress(ctx *UpdateContext, plans []*PlannedUpdate,
 			ctx.Unsupported.Add(res.Pkg, callbacks.DeriveReason(res.Pkg, ctx.Cfg, res.Err, false))
 		}
 		*results = append(*results, *res)
-		if progress != nil {
-			progress.Increment()
-		}
+		advanceProgress(progress, plan.Res.Pkg.Name)
 	}
 
 	return groupErr
@@ -1,8 +1,12 @@
 package update
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/constants"
@@ -11,6 +15,7 @@ import (
 	"github.com/ajxudir/goupdate/pkg/lock"
 	"github.com/ajxudir/goupdate/pkg/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Note: mockUnsupportedTracker is defined in context_test.go
@@ -23,6 +28,7 @@ import (
 //   - Returns error on reload failure
 //   - Returns error when package not found after reload
 //   - Returns error when version mismatch
+//   - Picks the package from the planned manifest when several manifests declare it
 func TestValidateUpdatedPackage(t *testing.T) {
 	t.Run("returns nil with nil reloadList", func(t *testing.T) {
 		plan := &PlannedUpdate{
@@ -109,6 +115,25 @@ func TestValidateUpdatedPackage(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "version mismatch")
 	})
+
+	t.Run("checks the package of the planned manifest", func(t *testing.T) {
+		// Workspace members declaring the same dependency share a key
+		app := testutil.NPMPackage("react", "17.0.0", "17.0.0")
+		app.Source = "packages/app/package.json"
+		lib := testutil.NPMPackage("react", "17.0.0", "18.0.0")
+		lib.Source = "packages/lib/package.json"
+		plan := &PlannedUpdate{Res: UpdateResult{Pkg: app, Target: "18.0.0"}}
+
+		reloadFunc := func() ([]formats.Package, error) {
+			updated := app
+			updated.Version, updated.InstalledVersion = "18.0.0", "18.0.0"
+			return []formats.Package{lib, updated}, nil
+		}
+
+		err := ValidateUpdatedPackage(plan, reloadFunc, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "18.0.0", plan.Res.Pkg.Version)
+	})
 }
 
 // TestValidateUpdatedPackageInstalledVersionMismatch tests the behavior of ValidateUpdatedPackage with InstalledVersion mismatches.
@@ -210,6 +235,7 @@ func TestValidateUpdatedPackageInstalledVersionMismatch(t *testing.T) {
 //   - Handles empty results
 //   - Handles nil results
 //   - Excludes unsupported errors
+//   - Leaves out statuses the severity mapping sets to warn or ignore
 func TestCollectUpdateErrors(t *testing.T) {
 	t.Run("collects errors from failed results", func(t *testing.T) {
 		results := []UpdateResult{
@@ -217,7 +243,7 @@ func TestCollectUpdateErrors(t *testing.T) {
 			{Pkg: formats.Package{Name: "vue"}, Status: constants.StatusUpdated},
 		}
 
-		errs := CollectUpdateErrors(results)
+		errs := CollectUpdateErrors(results, nil)
 		assert.Len(t, errs, 1)
 	})
 
@@ -227,17 +253,17 @@ func TestCollectUpdateErrors(t *testing.T) {
 			{Pkg: formats.Package{Name: "vue"}, Status: constants.StatusUpdated},
 		}
 
-		errs := CollectUpdateErrors(results)
+		errs := CollectUpdateErrors(results, nil)
 		assert.Empty(t, errs)
 	})
 
 	t.Run("handles empty results", func(t *testing.T) {
-		errs := CollectUpdateErrors([]UpdateResult{})
+		errs := CollectUpdateErrors([]UpdateResult{}, nil)
 		assert.Empty(t, errs)
 	})
 
 	t.Run("handles nil results", func(t *testing.T) {
-		errs := CollectUpdateErrors(nil)
+		errs := CollectUpdateErrors(nil, nil)
 		assert.Empty(t, errs)
 	})
 
@@ -247,9 +273,24 @@ func TestCollectUpdateErrors(t *testing.T) {
 			{Pkg: formats.Package{Name: "vue"}, Status: lock.InstallStatusNotConfigured, Err: &pkgerrors.UnsupportedError{Reason: "no config"}},
 		}
 
-		errs := CollectUpdateErrors(results)
+		errs := CollectUpdateErrors(results, nil)
 		assert.Len(t, errs, 1)
 	})
+
+	t.Run("applies the severity mapping", func(t *testing.T) {
+		npmErr := errors.New("npm failed")
+		pipErr := errors.New("pip failed")
+		results := []UpdateResult{
+			{Pkg: formats.Package{Name: "react", Rule: "npm"}, Status: constants.StatusFailed, Err: npmErr},
+			{Pkg: formats.Package{Name: "requests", Rule: "pip"}, Status: constants.StatusFailed, Err: pipErr},
+		}
+		npmWarn := testutil.NewConfig().WithRule("npm", config.PackageManagerCfg{Severity: map[string]string{constants.StatusFailed: config.SeverityWarn}}).Build()
+		ignoreAll := &config.Config{Severity: map[string]string{constants.StatusFailed: config.SeverityIgnore}}
+
+		assert.Equal(t, []error{npmErr, pipErr}, CollectUpdateErrors(results, nil))
+		assert.Equal(t, []error{pipErr}, CollectUpdateErrors(results, npmWarn))
+		assert.Empty(t, CollectUpdateErrors(results, ignoreAll))
+	})
 }
 
 // TestSummarizeGroupFailure tests the behavior of SummarizeGroupFailure.
@@ -506,6 +547,7 @@ func TestApplyPlannedUpdate(t *testing.T) {
 //   - Processes single plan
 //   - Processes multiple groups
 //   - Skips updates with non-updatable status
+//   - Isolates failures per rule and records rule outcomes
 func TestProcessGroupedPlansLive(t *testing.T) {
 	mockUpdater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
 		return nil
@@ -613,6 +655,56 @@ func TestProcessGroupedPlansLive(t *testing.T) {
 		assert.Len(t, results, 1)
 		assert.Equal(t, lock.InstallStatusNotConfigured, results[0].Status)
 	})
+
+	t.Run("isolates failures per rule", func(t *testing.T) {
+		cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+		failingUpdater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			if p.Rule == "composer" {
+				return errors.New("composer lock failed")
+			}
+			return nil
+		}
+		ctx := NewUpdateContext(cfg, "/test", &mockUnsupportedTracker{}).
+			WithUpdaterFunc(failingUpdater).
+			WithFlags(false, true, false)
+		var results []UpdateResult
+		callbacks := ExecutionCallbacks{DeriveReason: mockDeriveReason}
+		// All plans share a group key; groups must still never span rules.
+		plans := []*PlannedUpdate{
+			{
+				Res:      UpdateResult{Pkg: testutil.ComposerPackage("laravel/framework", "10.0.0", "10.0.0"), Target: "11.0.0", Status: constants.StatusPlanned},
+				Cfg:      &config.UpdateCfg{},
+				GroupKey: "core",
+			},
+			{
+				Res:      UpdateResult{Pkg: testutil.ComposerPackage("symfony/console", "6.0.0", "6.0.0"), Target: "7.0.0", Status: constants.StatusPlanned},
+				Cfg:      &config.UpdateCfg{},
+				GroupKey: "core",
+			},
+			{
+				Res:      UpdateResult{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Target: "18.0.0", Status: constants.StatusPlanned},
+				GroupKey: "core",
+			},
+		}
+
+		ProcessGroupedPlansLive(ctx, plans, &results, callbacks)
+
+		if !assert.Len(t, ctx.RuleOutcomes, 2) {
+			return
+		}
+		assert.Equal(t, "composer", ctx.RuleOutcomes[0].Rule)
+		assert.Len(t, ctx.RuleOutcomes[0].Failures, 2)
+		assert.False(t, ctx.RuleOutcomes[0].FullySucceeded())
+		assert.Equal(t, "npm", ctx.RuleOutcomes[1].Rule)
+		assert.Equal(t, 1, ctx.RuleOutcomes[1].Succeeded)
+		assert.True(t, ctx.RuleOutcomes[1].FullySucceeded())
+
+		// The composer group failure must not be summarized onto the npm plan
+		assert.Equal(t, constants.StatusFailed, plans[0].Res.Status)
+		assert.Equal(t, constants.StatusFailed, plans[1].Res.Status)
+		assert.Equal(t, constants.StatusUpdated, plans[2].Res.Status)
+		assert.Equal(t, 1, CountFullySucceededRules(ctx.RuleOutcomes))
+	})
 }
 
 // TestHandleSkippedUpdate tests the behavior of handleSkippedUpdate.
@@ -831,6 +923,7 @@ func TestDisplaySystemTestFailures(t *testing.T) {
 //   - Processes package with successful update
 //   - Handles update error
 //   - Skips packages with non-updatable status
+//   - Records the time spent applying each update, including dry runs
 func TestProcessGroupPerPackage(t *testing.T) {
 	mockDeriveReason := func(p formats.Package, cfg *config.Config, err error, latestMissing bool) string {
 		return "test reason"
@@ -866,6 +959,45 @@ func TestProcessGroupPerPackage(t *testing.T) {
 		assert.Len(t, results, 1)
 	})
 
+	t.Run("records update duration", func(t *testing.T) {
+		mockUpdater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			time.Sleep(2 * time.Millisecond)
+			return nil
+		}
+
+		cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+		ctx := NewUpdateContext(cfg, "/test", nil).
+			WithUpdaterFunc(mockUpdater).
+			WithFlags(true, false, false) // dry run
+		plans := []*PlannedUpdate{
+			{
+				Res: UpdateResult{
+					Pkg:    testutil.NPMPackage("react", "17.0.0", "17.0.0"),
+					Target: "18.0.0",
+					Status: constants.StatusPlanned,
+				},
+			},
+			{
+				Res: UpdateResult{
+					Pkg:    testutil.NPMPackage("vue", "3.0.0", "3.0.0"),
+					Status: constants.StatusUpToDate,
+				},
+			},
+		}
+
+		applied := make([]*PlannedUpdate, 0)
+		var results []UpdateResult
+		var failures []SystemTestFailure
+		callbacks := ExecutionCallbacks{DeriveReason: mockDeriveReason}
+
+		err := processGroupPerPackage(ctx, plans, &applied, &results, &failures, callbacks)
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.GreaterOrEqual(t, results[0].Duration, 2*time.Millisecond)
+		assert.Zero(t, results[1].Duration, "skipped packages are not timed")
+	})
+
 	t.Run("handles update error", func(t *testing.T) {
 		mockUpdater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
 			return errors.New("update failed")
@@ -2287,13 +2419,11 @@ func TestRunPackageSystemTests(t *testing.T) {
 			},
 			Original: "17.0.0",
 		}
-		var groupErr error
 		var failures []SystemTestFailure
 
-		err := runPackageSystemTests(ctx, plan, &groupErr, &failures)
+		err := runPackageSystemTests(ctx, plan, &failures)
 
 		assert.NoError(t, err)
-		assert.NoError(t, groupErr)
 		assert.Empty(t, failures)
 	})
 
@@ -2327,13 +2457,11 @@ func TestRunPackageSystemTests(t *testing.T) {
 			},
 			Original: "17.0.0",
 		}
-		var groupErr error
 		var failures []SystemTestFailure
 
-		err := runPackageSystemTests(ctx, plan, &groupErr, &failures)
+		err := runPackageSystemTests(ctx, plan, &failures)
 
-		assert.NoError(t, err) // runPackageSystemTests always returns nil
-		assert.Error(t, groupErr)
+		assert.Error(t, err)
 		assert.True(t, rollbackCalled, "rollback should have been called")
 		assert.Equal(t, constants.StatusFailed, plan.Res.Status)
 	})
@@ -2366,13 +2494,11 @@ func TestRunPackageSystemTests(t *testing.T) {
 			},
 			Original: "17.0.0",
 		}
-		var groupErr error
 		var failures []SystemTestFailure
 
-		err := runPackageSystemTests(ctx, plan, &groupErr, &failures)
+		err := runPackageSystemTests(ctx, plan, &failures)
 
-		assert.NoError(t, err)
-		assert.Error(t, groupErr) // groupErr should be set from critical failure
+		assert.Error(t, err) // The critical failure is returned
 		// Rollback failure is recorded in ctx.Failures
 		assert.Len(t, ctx.Failures, 2) // One for rollback failure, one for system test failure
 	})
@@ -2404,13 +2530,11 @@ func TestRunPackageSystemTests(t *testing.T) {
 			},
 			Original: "17.0.0",
 		}
-		var groupErr error
 		var failures []SystemTestFailure
 
-		err := runPackageSystemTests(ctx, plan, &groupErr, &failures)
+		err := runPackageSystemTests(ctx, plan, &failures)
 
-		assert.NoError(t, err)
-		assert.NoError(t, groupErr) // Non-critical failures don't set groupErr
+		assert.NoError(t, err) // Non-critical failures are not returned
 		assert.Len(t, failures, 1)
 		assert.Equal(t, "react", failures[0].PkgName)
 		assert.False(t, failures[0].IsCritical)
@@ -2815,13 +2939,11 @@ func TestRunPackageSystemTestsWithDriftCheck(t *testing.T) {
 			},
 			Original: "17.0.0",
 		}
-		var groupErr error
 		var failures []SystemTestFailure
 
-		err := runPackageSystemTests(ctx, plan, &groupErr, &failures)
+		err := runPackageSystemTests(ctx, plan, &failures)
 
-		assert.NoError(t, err)
-		assert.Error(t, groupErr)
+		assert.Error(t, err)
 		assert.True(t, driftCheckCalled, "drift check should be called after rollback")
 	})
 
@@ -2860,11 +2982,11 @@ func TestRunPackageSystemTestsWithDriftCheck(t *testing.T) {
 			},
 			Original: "17.0.0",
 		}
-		var groupErr error
 		var failures []SystemTestFailure
 
-		_ = runPackageSystemTests(ctx, plan, &groupErr, &failures)
+		err := runPackageSystemTests(ctx, plan, &failures)
 
+		assert.Error(t, err)
 		// Drift check failure should be recorded in ctx.Failures
 		assert.Len(t, ctx.Failures, 2) // system test failure + drift check failure
 	})
@@ -3303,3 +3425,485 @@ func TestProcessGroupWithGroupLockProgressSystemTests(t *testing.T) {
 		assert.Equal(t, constants.StatusFailed, results[0].Status)
 	})
 }
+
+// TestPartitionPlans tests the behavior of PartitionPlans.
+//
+// It verifies:
+//   - Empty input yields no partitions
+//   - Consecutive plans with the same key share a partition
+//   - Non-adjacent plans with the same key form separate partitions
+func TestPartitionPlans(t *testing.T) {
+	assert.Empty(t, PartitionPlans(nil, planRule))
+
+	plans := []*PlannedUpdate{
+		{Res: UpdateResult{Pkg: formats.Package{Name: "a", Rule: "composer"}}},
+		{Res: UpdateResult{Pkg: formats.Package{Name: "b", Rule: "composer"}}},
+		{Res: UpdateResult{Pkg: formats.Package{Name: "c", Rule: "npm"}}},
+		{Res: UpdateResult{Pkg: formats.Package{Name: "d", Rule: "composer"}}},
+	}
+
+	partitions := PartitionPlans(plans, planRule)
+
+	if !assert.Len(t, partitions, 3) {
+		return
+	}
+	assert.Len(t, partitions[0], 2)
+	assert.Equal(t, "npm", partitions[1][0].Res.Pkg.Rule)
+	assert.Equal(t, "d", partitions[2][0].Res.Pkg.Name)
+}
+
+// TestProcessGroupedPlansCancellation tests cancellation handling in the execution loop.
+//
+// It verifies:
+//   - A pre-cancelled context starts no updates and records one failure
+//   - Cancelling between per-package plans keeps completed results and stops scheduling
+//   - Cancelling mid-group under a shared lock rolls back the applied manifests
+//   - The progress variant honours cancellation the same way
+func TestProcessGroupedPlansCancellation(t *testing.T) {
+	mockDeriveReason := func(p formats.Package, cfg *config.Config, err error, latestMissing bool) string {
+		return "test reason"
+	}
+	newPlans := func() []*PlannedUpdate {
+		return []*PlannedUpdate{
+			{
+				Res:      UpdateResult{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Target: "18.0.0", Status: constants.StatusPlanned},
+				Original: "17.0.0",
+				GroupKey: "npm:js:frontend",
+			},
+			{
+				Res:      UpdateResult{Pkg: testutil.NPMPackage("vue", "2.0.0", "2.0.0"), Target: "3.0.0", Status: constants.StatusPlanned},
+				Original: "2.0.0",
+				GroupKey: "npm:js:frontend",
+			},
+		}
+	}
+
+	t.Run("pre-cancelled context starts no updates", func(t *testing.T) {
+		cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+		cancelled, cancel := context.WithCancel(context.Background())
+		cancel()
+		calls := 0
+		updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			calls++
+			return nil
+		}
+		ctx := NewUpdateContext(cfg, "/test", nil).
+			WithUpdaterFunc(updater).
+			WithFlags(true, false, false).
+			WithContext(cancelled)
+		var results []UpdateResult
+
+		ProcessGroupedPlansLive(ctx, newPlans(), &results, ExecutionCallbacks{DeriveReason: mockDeriveReason})
+
+		assert.Empty(t, results)
+		assert.Equal(t, 0, calls)
+		assert.Len(t, ctx.Failures, 1)
+		assert.ErrorIs(t, ctx.Failures[0], context.Canceled)
+	})
+
+	t.Run("keeps completed results and stops scheduling", func(t *testing.T) {
+		cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+		runCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var updated []string
+		updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			updated = append(updated, p.Name)
+			cancel() // Interrupt arrives while the first package is updating
+			return nil
+		}
+		ctx := NewUpdateContext(cfg, "/test", nil).
+			WithUpdaterFunc(updater).
+			WithFlags(true, false, false).
+			WithContext(runCtx)
+		var results []UpdateResult
+
+		ProcessGroupedPlansLive(ctx, newPlans(), &results, ExecutionCallbacks{DeriveReason: mockDeriveReason})
+
+		assert.Equal(t, []string{"react"}, updated)
+		assert.Len(t, results, 1)
+		assert.Equal(t, constants.StatusUpdated, results[0].Status)
+		assert.Len(t, ctx.Failures, 1)
+		assert.ErrorIs(t, ctx.Failures[0], context.Canceled)
+	})
+
+	t.Run("rolls back a partially applied group lock", func(t *testing.T) {
+		cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+		runCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var calls []string
+		updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			calls = append(calls, p.Name+"@"+target)
+			cancel()
+			return nil
+		}
+		ctx := NewUpdateContext(cfg, "/test", nil).
+			WithUpdaterFunc(updater).
+			WithFlags(false, false, false).
+			WithContext(runCtx)
+		plans := newPlans()
+		for _, plan := range plans {
+			plan.Cfg = cfg.Rules["npm"].Update
+		}
+		var results []UpdateResult
+
+		ProcessGroupedPlansLive(ctx, plans, &results, ExecutionCallbacks{DeriveReason: mockDeriveReason})
+
+		assert.Equal(t, []string{"react@18.0.0", "react@17.0.0"}, calls, "applied manifest change must be rolled back")
+		assert.Len(t, results, 1)
+		assert.Equal(t, constants.StatusFailed, results[0].Status)
+		assert.ErrorIs(t, results[0].Err, context.Canceled)
+	})
+
+	t.Run("progress variant honours cancellation", func(t *testing.T) {
+		cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+		runCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			cancel()
+			return nil
+		}
+		ctx := NewUpdateContext(cfg, "/test", nil).
+			WithUpdaterFunc(updater).
+			WithFlags(true, false, false).
+			WithContext(runCtx)
+		var results []UpdateResult
+		progress := &mockProgressReporter{}
+
+		ProcessGroupedPlansWithProgress(ctx, newPlans(), &results, progress, ExecutionCallbacks{DeriveReason: mockDeriveReason})
+
+		assert.Len(t, results, 1)
+		assert.Equal(t, 1, progress.count)
+		assert.Len(t, ctx.Failures, 1)
+	})
+}
+
+// TestGroupLockConfig tests the behavior of groupLockConfig.
+//
+// It verifies:
+//   - Returns nil when no plan has a configuration
+//   - Uses the largest timeout in the group
+//   - A plan without a timeout disables the group timeout
+//   - The plan configurations are not mutated
+func TestGroupLockConfig(t *testing.T) {
+	t.Run("returns nil without configuration", func(t *testing.T) {
+		assert.Nil(t, groupLockConfig([]*PlannedUpdate{{}, {}}))
+	})
+
+	t.Run("uses the largest timeout", func(t *testing.T) {
+		first := &config.UpdateCfg{Commands: "npm install", TimeoutSeconds: 60}
+		plans := []*PlannedUpdate{{}, {Cfg: first}, {Cfg: &config.UpdateCfg{Commands: "other", TimeoutSeconds: 600}}}
+
+		groupCfg := groupLockConfig(plans)
+
+		assert.Equal(t, "npm install", groupCfg.Commands)
+		assert.Equal(t, 600, groupCfg.TimeoutSeconds)
+		assert.Equal(t, 60, first.TimeoutSeconds)
+	})
+
+	t.Run("unlimited timeout wins", func(t *testing.T) {
+		plans := []*PlannedUpdate{
+			{Cfg: &config.UpdateCfg{TimeoutSeconds: 60}},
+			{Cfg: &config.UpdateCfg{TimeoutSeconds: 0}},
+			{Cfg: &config.UpdateCfg{TimeoutSeconds: 300}},
+		}
+
+		assert.Equal(t, 0, groupLockConfig(plans).TimeoutSeconds)
+	})
+}
+
+// TestProcessGroupedPlansLockTimeout tests a timed-out group lock command.
+//
+// It verifies:
+//   - The group lock runs with the group's timeout
+//   - Every package in the group is marked failed with the timeout error
+//   - The applied manifest changes are rolled back
+func TestProcessGroupedPlansLockTimeout(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+	timeoutErr := errors.New("command timed out after 5 seconds: signal: killed")
+
+	var lockTimeout int
+	originalExec := execCommandFunc
+	execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		lockTimeout = cfg.TimeoutSeconds
+		return nil, timeoutErr
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	var calls []string
+	updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		calls = append(calls, p.Name+"@"+target)
+		return nil
+	}
+	ctx := NewUpdateContext(cfg, "/test", nil).
+		WithUpdaterFunc(updater).
+		WithFlags(false, false, false)
+	plans := []*PlannedUpdate{
+		{
+			Res:      UpdateResult{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Target: "18.0.0", Status: constants.StatusPlanned},
+			Cfg:      &config.UpdateCfg{Commands: "npm install", TimeoutSeconds: 2},
+			Original: "17.0.0",
+			GroupKey: "npm:js:frontend",
+		},
+		{
+			Res:      UpdateResult{Pkg: testutil.NPMPackage("vue", "2.0.0", "2.0.0"), Target: "3.0.0", Status: constants.StatusPlanned},
+			Cfg:      &config.UpdateCfg{Commands: "npm install", TimeoutSeconds: 5},
+			Original: "2.0.0",
+			GroupKey: "npm:js:frontend",
+		},
+	}
+	var results []UpdateResult
+
+	ProcessGroupedPlansLive(ctx, plans, &results, ExecutionCallbacks{DeriveReason: func(formats.Package, *config.Config, error, bool) string { return "" }})
+
+	assert.Equal(t, 5, lockTimeout)
+	assert.Len(t, results, 2)
+	for _, res := range results {
+		assert.Equal(t, constants.StatusFailed, res.Status)
+		assert.ErrorIs(t, res.Err, timeoutErr)
+	}
+	assert.Equal(t, []string{"react@18.0.0", "vue@3.0.0", "react@17.0.0", "vue@2.0.0"}, calls)
+}
+
+// TestProcessGroupedPlansLockDir tests where a group's lock command runs.
+//
+// It verifies:
+//   - The lock command runs in the group's manifest directory, not the working directory
+//   - A group spanning several manifest directories locks each directory once
+func TestProcessGroupedPlansLockDir(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+
+	var lockDirs []string
+	originalExec := execCommandFunc
+	execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		lockDirs = append(lockDirs, dir)
+		return nil, nil
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		return nil
+	}
+	ctx := NewUpdateContext(cfg, "/repo", nil).
+		WithUpdaterFunc(updater).
+		WithFlags(false, false, false)
+
+	plan := func(name, source string) *PlannedUpdate {
+		pkg := testutil.NPMPackage(name, "1.0.0", "1.0.0")
+		pkg.Source = source
+		return &PlannedUpdate{
+			Res:      UpdateResult{Pkg: pkg, Target: "2.0.0", Status: constants.StatusPlanned},
+			Cfg:      &config.UpdateCfg{Commands: "npm install"},
+			Original: "1.0.0",
+			GroupKey: "npm:js:frontend",
+		}
+	}
+	plans := []*PlannedUpdate{
+		plan("react", filepath.Join("/repo", "frontend", "package.json")),
+		plan("react-dom", filepath.Join("/repo", "frontend", "package.json")),
+		plan("vite", filepath.Join("/repo", "tools", "package.json")),
+	}
+	var results []UpdateResult
+
+	ProcessGroupedPlansLive(ctx, plans, &results, ExecutionCallbacks{DeriveReason: func(formats.Package, *config.Config, error, bool) string { return "" }})
+
+	assert.Equal(t, []string{filepath.Join("/repo", "frontend"), filepath.Join("/repo", "tools")}, lockDirs)
+}
+
+// TestGroupCommands tests running one lock command for all groups of a rule.
+//
+// It verifies:
+//   - With update.group_commands every group's manifest edit is applied without its lock command
+//   - The lock command runs once for the rule and every applied plan is validated
+//   - A failing validation rolls back every applied plan of the rule, not just one group
+//   - Without the option, dry runs, and --skip-lock each group keeps its own processing
+//   - A lock command using {{package}} keeps a command per group
+func TestGroupCommands(t *testing.T) {
+	newPlans := func() []*PlannedUpdate {
+		var plans []*PlannedUpdate
+		for _, pkg := range []struct{ name, group string }{{"react", "npm:react"}, {"react-dom", "npm:react"}, {"lodash", "npm:lodash"}} {
+			plans = append(plans, &PlannedUpdate{
+				Res:      UpdateResult{Pkg: testutil.NPMPackage(pkg.name, "1.0.0", "1.0.0"), Target: "2.0.0", Status: constants.StatusPlanned},
+				Cfg:      &config.UpdateCfg{Commands: "npm install"},
+				Original: "1.0.0",
+				GroupKey: pkg.group,
+			})
+		}
+		return plans
+	}
+	newCtx := func(groupCommands bool, versions map[string]string, calls *[]string) *UpdateContext {
+		rule := testutil.NPMRule()
+		rule.Update = &config.UpdateCfg{Commands: "npm install", GroupCommands: groupCommands}
+		cfg := testutil.NewConfig().WithRule("npm", rule).Build()
+		updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			*calls = append(*calls, fmt.Sprintf("%s@%s skipLock=%t", p.Name, target, skipLock))
+			versions[p.Name] = target
+			return nil
+		}
+		reload := func() ([]formats.Package, error) {
+			var pkgs []formats.Package
+			for _, name := range []string{"react", "react-dom", "lodash"} {
+				pkgs = append(pkgs, testutil.NPMPackage(name, versions[name], versions[name]))
+			}
+			return pkgs, nil
+		}
+		return NewUpdateContext(cfg, t.TempDir(), nil).
+			WithUpdaterFunc(updater).
+			WithReloadList(reload).
+			WithFlags(false, false, false)
+	}
+	var locks int
+	originalExec := execCommandFunc
+	execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		locks++
+		return nil, nil
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+	callbacks := ExecutionCallbacks{DeriveReason: func(formats.Package, *config.Config, error, bool) string { return "" }}
+
+	t.Run("one lock command for the rule", func(t *testing.T) {
+		locks = 0
+		var calls []string
+		versions := map[string]string{"react": "1.0.0", "react-dom": "1.0.0", "lodash": "1.0.0"}
+		ctx := newCtx(true, versions, &calls)
+		var results []UpdateResult
+
+		ProcessGroupedPlansLive(ctx, newPlans(), &results, callbacks)
+
+		assert.Equal(t, []string{"react@2.0.0 skipLock=true", "react-dom@2.0.0 skipLock=true", "lodash@2.0.0 skipLock=true"}, calls)
+		assert.Equal(t, 1, locks)
+		require.Len(t, results, 3)
+		for _, res := range results {
+			assert.Equal(t, constants.StatusUpdated, res.Status, res.Pkg.Name)
+		}
+	})
+
+	t.Run("failure rolls back every group", func(t *testing.T) {
+		var calls []string
+		versions := map[string]string{"react": "1.0.0", "react-dom": "1.0.0", "lodash": "1.0.0"}
+		ctx := newCtx(true, versions, &calls)
+		updater := ctx.UpdaterFunc
+		ctx.UpdaterFunc = func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			if p.Name == "lodash" && target == "2.0.0" {
+				// The manifest edit reports success but does not stick, so validation fails
+				calls = append(calls, "lodash@2.0.0 lost")
+				return nil
+			}
+			return updater(p, target, cfg, workDir, dryRun, skipLock)
+		}
+		plans := newPlans()
+		var results []UpdateResult
+
+		ProcessGroupedPlansLive(ctx, plans, &results, callbacks)
+
+		for _, name := range []string{"react", "react-dom", "lodash"} {
+			assert.Equal(t, "1.0.0", versions[name], name)
+		}
+		assert.Contains(t, calls, "react@1.0.0 skipLock=false")
+		assert.Contains(t, calls, "react-dom@1.0.0 skipLock=false")
+		for _, plan := range plans {
+			assert.Equal(t, constants.StatusFailed, plan.Res.Status, plan.Res.Pkg.Name)
+		}
+	})
+
+	t.Run("per group without the option", func(t *testing.T) {
+		for name, setup := range map[string]func(ctx *UpdateContext){
+			"not configured": func(ctx *UpdateContext) {},
+			"dry run":        func(ctx *UpdateContext) { ctx.DryRun = true; ctx.GroupCommands = true },
+			"skip lock":      func(ctx *UpdateContext) { ctx.SkipLockRun = true; ctx.GroupCommands = true },
+		} {
+			var calls []string
+			ctx := newCtx(false, map[string]string{}, &calls)
+			setup(ctx)
+			batches := ruleBatches(ctx, newPlans())
+			assert.Len(t, batches, 2, name)
+		}
+
+		var calls []string
+		ctx := newCtx(false, map[string]string{}, &calls).WithGroupCommands(true)
+		assert.Len(t, ruleBatches(ctx, newPlans()), 1)
+	})
+
+	t.Run("per group when the lock command names the package", func(t *testing.T) {
+		locks = 0
+		var calls []string
+		versions := map[string]string{"react": "1.0.0", "react-dom": "1.0.0", "lodash": "1.0.0"}
+		ctx := newCtx(true, versions, &calls).WithGroupCommands(true)
+		plans := newPlans()
+		for _, plan := range plans {
+			plan.Cfg = &config.UpdateCfg{Commands: "bun update {{package}}"}
+		}
+		assert.Len(t, ruleBatches(ctx, plans), 2)
+
+		var results []UpdateResult
+		ProcessGroupedPlansLive(ctx, plans, &results, callbacks)
+
+		assert.Contains(t, calls, "lodash@2.0.0 skipLock=false")
+		assert.Equal(t, 1, locks)
+		for _, res := range results {
+			assert.Equal(t, constants.StatusUpdated, res.Status, res.Pkg.Name)
+		}
+	})
+}
+
+// TestRollbackBatch tests rolling back every applied plan after a failed after_all run.
+//
+// It verifies:
+//   - Every updated plan is re-applied at its Original version
+//   - Plans that were not updated are left alone
+//   - Results of rolled back packages are marked failed with the batch error
+//   - Rolled back packages no longer count as successes of their rule
+//   - Drift check failures are combined into the returned error
+func TestRollbackBatch(t *testing.T) {
+	newPlans := func() []*PlannedUpdate {
+		return []*PlannedUpdate{
+			{Res: UpdateResult{Pkg: testutil.NPMPackage("react", "18.0.0", "18.0.0"), Status: constants.StatusUpdated, Target: "18.0.0"}, Original: "17.0.0"},
+			{Res: UpdateResult{Pkg: testutil.NPMPackage("vue", "3.0.0", "3.0.0"), Status: constants.StatusUpToDate}, Original: "3.0.0"},
+			{Res: UpdateResult{Pkg: testutil.NPMPackage("lodash", "4.17.21", "4.17.21"), Status: constants.StatusUpdated, Target: "4.17.21"}, Original: "4.17.20"},
+		}
+	}
+	batchErr := errors.New("system tests failed after updates")
+
+	t.Run("rolls back the whole batch", func(t *testing.T) {
+		plans := newPlans()
+		results := []UpdateResult{plans[0].Res, plans[1].Res, plans[2].Res}
+		var calls []string
+		ctx := NewUpdateContext(testutil.NewConfig().Build(), "/test", nil).WithUpdaterFunc(func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			calls = append(calls, p.Name+"@"+target)
+			return nil
+		})
+		ctx.RuleOutcomes = []RuleOutcome{{Rule: "npm", Succeeded: 2}, {Rule: "mod", Succeeded: 1}}
+
+		rolledBack, err := RollbackBatch(ctx, plans, results, batchErr)
+		require.NoError(t, err)
+		assert.Equal(t, 2, rolledBack)
+		assert.Equal(t, []string{"react@17.0.0", "lodash@4.17.20"}, calls)
+		assert.Equal(t, constants.StatusFailed, results[0].Status)
+		assert.Equal(t, batchErr, results[0].Err)
+		assert.Equal(t, constants.StatusUpToDate, results[1].Status)
+		assert.Equal(t, constants.StatusFailed, results[2].Status)
+		assert.Equal(t, []RuleOutcome{{Rule: "npm"}, {Rule: "mod", Succeeded: 1}}, ctx.RuleOutcomes)
+	})
+
+	t.Run("combines drift check failures", func(t *testing.T) {
+		plans := newPlans()
+		ctx := NewUpdateContext(testutil.NewConfig().Build(), "/test", nil).
+			WithUpdaterFunc(func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+				return nil
+			}).
+			WithReloadList(func() ([]formats.Package, error) {
+				return []formats.Package{testutil.NPMPackage("react", "18.0.0", "18.0.0"), testutil.NPMPackage("lodash", "4.17.21", "4.17.21")}, nil
+			})
+
+		_, err := RollbackBatch(ctx, plans, nil, batchErr)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "react version mismatch after rollback")
+		assert.Contains(t, err.Error(), "lodash version mismatch after rollback")
+	})
+
+	t.Run("nothing applied", func(t *testing.T) {
+		plans := newPlans()[1:2]
+		rolledBack, err := RollbackBatch(NewUpdateContext(testutil.NewConfig().Build(), "/test", nil), plans, nil, batchErr)
+		require.NoError(t, err)
+		assert.Zero(t, rolledBack)
+	})
+}
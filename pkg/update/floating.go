@@ -0,0 +1,140 @@
+package update
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/utils"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// dotnetPackageType is the package manager of the built-in msbuild and nuget rules.
+const dotnetPackageType = "dotnet"
+
+// CanPinFloating reports whether a floating version can be resolved to a concrete one.
+//
+// Only NuGet wildcards ("*", "13.*", "13.0.*") qualify: they float to the highest
+// release with a fixed prefix, so the registry alone decides what they resolve to.
+// Ranges and compound constraints express bounds the user chose and are never pinned.
+//
+// Parameters:
+//   - p: The package to check
+//
+// Returns:
+//   - bool: True for dotnet packages declared with a floating wildcard
+func CanPinFloating(p formats.Package) bool {
+	return p.PackageType == dotnetPackageType && utils.IsFloatingWildcard(p.Version)
+}
+
+// planFloatingPin plans pinning a floating wildcard to a concrete version (--pin-floating).
+//
+// It performs the following operations:
+//   - Step 1: List the versions newer than the installed one
+//   - Step 2: Keep the versions matching the wildcard, plus the installed version when it matches
+//   - Step 3: Apply the --major/--minor/--patch scope relative to the installed version
+//   - Step 4: Target the highest remaining version, rewriting the declaration without a constraint
+//
+// When no version qualifies the package is reported as floating, as without --pin-floating.
+//
+// Parameters:
+//   - ctx: Context for cancelling the version lookup
+//   - p: The package declared with a floating wildcard (e.g., "13.0.*")
+//   - res: The initial update result
+//   - updateCfg: Update configuration for the package
+//   - updateCtx: Update context providing configuration, selection and trackers
+//   - originalVersion: The declared wildcard, restored on rollback
+//   - listVersions: Function returning versions newer than the installed one
+//   - deriveReason: Function to derive unsupported reason message
+//
+// Returns:
+//   - *PlannedUpdate: Planned update targeting the resolved version, or a floating plan
+func planFloatingPin(
+	ctx context.Context,
+	p formats.Package,
+	res UpdateResult,
+	updateCfg *config.UpdateCfg,
+	updateCtx *UpdateContext,
+	originalVersion string,
+	listVersions VersionLister,
+	deriveReason UnsupportedReasonDeriver,
+) *PlannedUpdate {
+	cfg := updateCtx.Cfg
+	groupKey := UpdateGroupKey(updateCfg, p)
+	res.Group = NormalizeUpdateGroup(updateCfg, p)
+
+	versions, err := listVersions(ctx, p, cfg, updateCtx.WorkDir)
+	if err != nil {
+		if errors.IsUnsupported(err) {
+			res.Status = lock.InstallStatusNotConfigured
+			if updateCtx.Unsupported != nil {
+				updateCtx.Unsupported.Add(p, deriveReason(p, cfg, err, false))
+			}
+		} else {
+			res.Status = constants.StatusFailed
+			res.Err = err
+			updateCtx.AppendFailure(fmt.Errorf("%s (%s/%s): %w", p.Name, p.PackageType, p.Rule, err))
+		}
+		return &PlannedUpdate{Cfg: updateCfg, Res: res, Original: originalVersion, GroupKey: groupKey}
+	}
+
+	var versioning *config.VersioningCfg
+	if ruleCfg := cfg.Rules[p.Rule]; ruleCfg.Outdated != nil {
+		versioning = ruleCfg.Outdated.Versioning
+	}
+
+	var matching []string
+	installed := outdated.CurrentVersionForOutdated(p)
+	if utils.MatchesFloatingWildcard(p.Version, installed) {
+		matching = append(matching, installed)
+	}
+	for _, v := range versions {
+		if utils.MatchesFloatingWildcard(p.Version, v) {
+			matching = append(matching, v)
+		}
+	}
+
+	// The declaration is rewritten to the bare version, so the wildcard's "*"
+	// constraint must not be prepended to it
+	pinned := p
+	pinned.Constraint = ""
+	allowed := outdated.FilterVersionsByConstraint(pinned, matching, updateCtx.Selection)
+
+	target := ""
+	for _, v := range allowed {
+		if cmp, cmpErr := outdated.CompareVersions(v, target, versioning); target == "" || (cmpErr == nil && cmp > 0) {
+			target = v
+		}
+	}
+	if target == "" {
+		verbose.Debugf("Package %s: no release matches floating constraint %q", p.Name, p.Version)
+		return handleFloatingConstraint(p, updateCfg, updateCtx, originalVersion)
+	}
+
+	major, minor, patch, summarizeErr := outdated.SummarizeAvailableVersions(installed, versions, versioning, false)
+	if summarizeErr != nil {
+		major, minor, patch = constants.PlaceholderNA, constants.PlaceholderNA, constants.PlaceholderNA
+	}
+
+	res.Pkg = pinned
+	res.Target = target
+	res.Available = allowed
+	res.Major = major
+	res.Minor = minor
+	res.Patch = patch
+	verbose.Debugf("Package %s: pinning floating constraint %q to %s", p.Name, p.Version, target)
+
+	return &PlannedUpdate{
+		Cfg:                  updateCfg,
+		Res:                  res,
+		Original:             originalVersion,
+		GroupKey:             groupKey,
+		VersionsInConstraint: allowed,
+		Versioning:           versioning,
+	}
+}
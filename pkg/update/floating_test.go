@@ -0,0 +1,141 @@
+package update
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// nugetPackage returns a packages.config package declared at version with installed resolved.
+func nugetPackage(name, version, installed string) formats.Package {
+	return formats.Package{
+		Rule:             "nuget",
+		PackageType:      "dotnet",
+		Type:             "prod",
+		Name:             name,
+		Version:          version,
+		Constraint:       "",
+		InstalledVersion: installed,
+		InstallStatus:    lock.InstallStatusFloating,
+	}
+}
+
+// TestCanPinFloating tests which floating versions --pin-floating resolves.
+//
+// It verifies:
+//   - NuGet wildcards of dotnet packages can be pinned
+//   - Ranges and wildcards of other package managers cannot
+func TestCanPinFloating(t *testing.T) {
+	assert.True(t, CanPinFloating(nugetPackage("Newtonsoft.Json", "13.0.*", "")))
+	assert.True(t, CanPinFloating(nugetPackage("Newtonsoft.Json", "*", "")))
+	assert.False(t, CanPinFloating(nugetPackage("Newtonsoft.Json", "[13.0,14.0)", "")))
+	assert.False(t, CanPinFloating(nugetPackage("Newtonsoft.Json", "13.0.1", "")))
+	assert.False(t, CanPinFloating(formats.Package{PackageType: "js", Name: "react", Version: "18.*"}))
+}
+
+// TestBuildGroupedPlansPinFloating tests planning NuGet floating versions.
+//
+// It verifies:
+//   - Without PinFloating the package stays floating, is not looked up, and the reason mentions --pin-floating
+//   - With PinFloating the highest version matching the wildcard becomes the target
+//   - --major/--minor/--patch limit the target relative to the installed version
+//   - The installed version is pinned when no newer version matches
+//   - A wildcard nothing matches stays floating
+func TestBuildGroupedPlansPinFloating(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("nuget", config.PackageManagerCfg{Manager: "dotnet"}).Build()
+	updateCfg := &config.UpdateCfg{Commands: "dotnet restore"}
+	released := []string{"13.0.2", "13.0.3", "13.1.0", "14.0.1"}
+
+	var lookedUp []string
+	lister := func(_ context.Context, p formats.Package, _ *config.Config, _ string) ([]string, error) {
+		lookedUp = append(lookedUp, p.Name)
+		return released, nil
+	}
+	deriveReason := func(formats.Package, *config.Config, error, bool) string { return "" }
+
+	plan := func(p formats.Package, opts PlanningOptions, selection outdated.UpdateSelectionFlags) (*PlannedUpdate, *mockUnsupportedTracker) {
+		tracker := &mockUnsupportedTracker{}
+		ctx := NewUpdateContext(cfg, "/test", tracker).WithSelection(selection)
+		plans := BuildGroupedPlans(context.Background(), []ResolvedUpdatePlan{{Pkg: p, Cfg: updateCfg}}, ctx, opts, lister, deriveReason)
+		require.Len(t, plans, 1)
+		return plans[0], tracker
+	}
+	pin := PlanningOptions{PinFloating: true}
+	none := outdated.UpdateSelectionFlags{}
+
+	floating, tracker := plan(nugetPackage("Newtonsoft.Json", "13.0.*", "13.0.1"), PlanningOptions{}, none)
+	assert.Equal(t, lock.InstallStatusFloating, floating.Res.Status)
+	assert.Empty(t, floating.Res.Target)
+	assert.Empty(t, lookedUp)
+	require.Len(t, tracker.reasons, 1)
+	assert.Contains(t, tracker.reasons[0], "--pin-floating")
+	assert.False(t, PlanningOptions{}.NeedsVersionLookup(ResolvedUpdatePlan{Pkg: nugetPackage("Newtonsoft.Json", "13.0.*", "")}))
+	assert.True(t, pin.NeedsVersionLookup(ResolvedUpdatePlan{Pkg: nugetPackage("Newtonsoft.Json", "13.0.*", "")}))
+
+	pinned, tracker := plan(nugetPackage("Newtonsoft.Json", "13.0.*", "13.0.1"), pin, none)
+	assert.Equal(t, []string{"Newtonsoft.Json"}, lookedUp)
+	assert.Equal(t, "13.0.3", pinned.Res.Target)
+	assert.Equal(t, "13.0.*", pinned.Original)
+	assert.Empty(t, pinned.Res.Pkg.Constraint)
+	assert.Equal(t, 1, CountPendingUpdates([]*PlannedUpdate{pinned}))
+	assert.Empty(t, tracker.reasons)
+
+	tests := []struct {
+		name      string
+		version   string
+		selection outdated.UpdateSelectionFlags
+		target    string
+	}{
+		{"major wildcard", "13.*", none, "13.1.0"},
+		{"major wildcard with --patch", "13.*", outdated.UpdateSelectionFlags{Patch: true}, "13.0.3"},
+		{"any version", "*", none, "14.0.1"},
+		{"any version with --minor", "*", outdated.UpdateSelectionFlags{Minor: true}, "13.1.0"},
+		{"any version with --major", "*", outdated.UpdateSelectionFlags{Major: true}, "14.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := nugetPackage("Newtonsoft.Json", tt.version, "13.0.1")
+			if tt.version == "*" {
+				p.Constraint = "*"
+			}
+			result, _ := plan(p, pin, tt.selection)
+			assert.Equal(t, tt.target, result.Res.Target)
+		})
+	}
+
+	released = nil
+	current, _ := plan(nugetPackage("Newtonsoft.Json", "13.0.*", "13.0.3"), pin, none)
+	assert.Equal(t, "13.0.3", current.Res.Target)
+
+	unmatched, tracker := plan(nugetPackage("Newtonsoft.Json", "15.*", "#N/A"), pin, none)
+	assert.Equal(t, lock.InstallStatusFloating, unmatched.Res.Status)
+	assert.Empty(t, unmatched.Res.Target)
+	assert.Len(t, tracker.reasons, 1)
+}
+
+// TestUpdateXMLVersionPinFloating tests rewriting a pinned floating version.
+//
+// It verifies:
+//   - The wildcard is replaced by the bare resolved version
+//   - Rolling back restores the wildcard
+func TestUpdateXMLVersionPinFloating(t *testing.T) {
+	cfg := config.PackageManagerCfg{Manager: "dotnet", Format: "xml", Fields: map[string]string{"ItemGroup/PackageReference": "prod"}, Extraction: &config.ExtractionCfg{DevElement: "PrivateAssets"}}
+	project := []byte(`<Project><ItemGroup><PackageReference Include="Newtonsoft.Json" Version="*" /></ItemGroup></Project>`)
+	p := formats.Package{Name: "Newtonsoft.Json", Source: "Api.csproj"}
+
+	out, err := updateXMLVersion(project, p, cfg, "13.0.3")
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `Version="13.0.3"`)
+
+	out, err = updateXMLVersion(out, p, cfg, "*")
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `Version="*"`)
+}
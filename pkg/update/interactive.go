@@ -0,0 +1,207 @@
+package update
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/utils"
+)
+
+// maxCandidateVersions caps how many versions are offered when picking a target.
+const maxCandidateVersions = 10
+
+// CandidateVersions returns the versions a user may pick as a package's target.
+//
+// Candidates are the versions newer than the current version from the plan's
+// version lookup, newest first. Pre-releases are only offered when the planned
+// target is a pre-release.
+//
+// Parameters:
+//   - plan: The planned update; VersionsInConstraint and Res.Available hold the looked-up versions
+//
+// Returns:
+//   - []string: Up to maxCandidateVersions versions, newest first
+//
+// Example:
+//
+//	// installed 1.0.0, looked up [1.1.0 2.0.0 1.2.0]
+//	update.CandidateVersions(plan) // ["2.0.0", "1.2.0", "1.1.0"]
+func CandidateVersions(plan *PlannedUpdate) []string {
+	current := outdated.CurrentVersionForOutdated(plan.Res.Pkg)
+	allowPrerelease := utils.IsPreReleaseVersion(plan.Res.Target)
+	seen := make(map[string]bool)
+	var candidates []string
+
+	for _, list := range [][]string{plan.VersionsInConstraint, plan.Res.Available} {
+		for _, v := range list {
+			if seen[v] || (!allowPrerelease && utils.IsPreReleaseVersion(v)) {
+				continue
+			}
+			seen[v] = true
+			if cmp, err := outdated.CompareVersions(v, current, plan.Versioning); err == nil && cmp > 0 {
+				candidates = append(candidates, v)
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		cmp, _ := outdated.CompareVersions(candidates[i], candidates[j], plan.Versioning)
+		return cmp > 0
+	})
+	if len(candidates) > maxCandidateVersions {
+		candidates = candidates[:maxCandidateVersions]
+	}
+	return candidates
+}
+
+// SelectPlans lets the user choose which pending updates to apply.
+//
+// It performs the following operations:
+//   - Step 1: List the pending updates with their proposed targets, all selected
+//   - Step 2: Read commands until the user accepts or quits:
+//     numbers toggle packages, "v N" picks another target for package N,
+//     "a" selects all, "n" selects none, Enter accepts, "q" quits
+//   - Step 3: Drop deselected plans and return the rest unchanged
+//
+// Plans without a pending update (up to date, unsupported, failed) are always
+// kept, so the returned slice is what the non-interactive path would execute,
+// minus the deselected packages.
+//
+// Parameters:
+//   - r: Reader for user input
+//   - w: Writer for the list and prompts
+//   - plans: Planned updates from BuildGroupedPlans
+//
+// Returns:
+//   - []*PlannedUpdate: The plans to execute, in their original order
+//   - bool: false when the user quit
+//   - error: When input cannot be read
+func SelectPlans(r *bufio.Reader, w io.Writer, plans []*PlannedUpdate) ([]*PlannedUpdate, bool, error) {
+	var pending []*PlannedUpdate
+	selected := make(map[*PlannedUpdate]bool)
+	for _, plan := range plans {
+		if !ShouldSkipUpdate(&plan.Res) {
+			pending = append(pending, plan)
+			selected[plan] = true
+		}
+	}
+
+	for {
+		printSelection(w, pending, selected)
+		_, _ = fmt.Fprint(w, "Toggle [1-N], 'v N' pick version, 'a' all, 'n' none, Enter to continue, 'q' to quit: ")
+
+		line, err := readLine(r)
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch fields := strings.Fields(strings.ReplaceAll(strings.ToLower(line), ",", " ")); {
+		case len(fields) == 0:
+			kept := make([]*PlannedUpdate, 0, len(plans))
+			for _, plan := range plans {
+				if ShouldSkipUpdate(&plan.Res) || selected[plan] {
+					kept = append(kept, plan)
+				}
+			}
+			return kept, true, nil
+		case fields[0] == "q":
+			return nil, false, nil
+		case fields[0] == "a" || fields[0] == "n":
+			for _, plan := range pending {
+				selected[plan] = fields[0] == "a"
+			}
+		case fields[0] == "v":
+			idx, ok := parseSelectionIndex(fields[1:], len(pending))
+			if !ok {
+				_, _ = fmt.Fprintln(w, "Usage: v N (N is a package number)")
+				continue
+			}
+			if err := pickVersion(r, w, pending[idx]); err != nil {
+				return nil, false, err
+			}
+			selected[pending[idx]] = true
+		default:
+			for _, field := range fields {
+				idx, ok := parseSelectionIndex([]string{field}, len(pending))
+				if !ok {
+					_, _ = fmt.Fprintf(w, "Unknown selection %q\n", field)
+					continue
+				}
+				selected[pending[idx]] = !selected[pending[idx]]
+			}
+		}
+	}
+}
+
+// printSelection writes the numbered list of pending updates.
+func printSelection(w io.Writer, pending []*PlannedUpdate, selected map[*PlannedUpdate]bool) {
+	_, _ = fmt.Fprintln(w)
+	for i, plan := range pending {
+		mark := " "
+		if selected[plan] {
+			mark = "x"
+		}
+		p := plan.Res.Pkg
+		_, _ = fmt.Fprintf(w, "  %2d. [%s] %s (%s/%s) %s → %s\n", i+1, mark, p.Name, p.PackageType, p.Rule,
+			outdated.CurrentVersionForOutdated(p), plan.Res.Target)
+	}
+}
+
+// pickVersion lets the user replace the target of one plan with a candidate version.
+func pickVersion(r *bufio.Reader, w io.Writer, plan *PlannedUpdate) error {
+	candidates := CandidateVersions(plan)
+	if len(candidates) == 0 {
+		_, _ = fmt.Fprintf(w, "No other versions available for %s\n", plan.Res.Pkg.Name)
+		return nil
+	}
+
+	for i, v := range candidates {
+		marker := ""
+		if v == plan.Res.Target {
+			marker = " (planned)"
+		}
+		_, _ = fmt.Fprintf(w, "    %2d. %s%s\n", i+1, v, marker)
+	}
+	_, _ = fmt.Fprintf(w, "Version for %s [1-%d, Enter keeps %s]: ", plan.Res.Pkg.Name, len(candidates), plan.Res.Target)
+
+	line, err := readLine(r)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+	idx, ok := parseSelectionIndex(strings.Fields(line), len(candidates))
+	if !ok {
+		_, _ = fmt.Fprintf(w, "Unknown version %q; keeping %s\n", strings.TrimSpace(line), plan.Res.Target)
+		return nil
+	}
+	plan.Res.Target = candidates[idx]
+	return nil
+}
+
+// parseSelectionIndex parses a single 1-based number into a 0-based index below n.
+func parseSelectionIndex(fields []string, n int) (int, bool) {
+	if len(fields) != 1 {
+		return 0, false
+	}
+	num, err := strconv.Atoi(fields[0])
+	if err != nil || num < 1 || num > n {
+		return 0, false
+	}
+	return num - 1, true
+}
+
+// readLine reads one line of input, accepting a final line without a newline.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("reading selection: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,101 @@
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// TestCandidateVersions tests listing the versions offered when picking a target.
+//
+// It verifies:
+//   - Versions newer than the installed version are returned newest first without duplicates
+//   - Older versions and pre-releases are skipped
+//   - Pre-releases are offered when the planned target is a pre-release
+func TestCandidateVersions(t *testing.T) {
+	plan := &PlannedUpdate{
+		Res: UpdateResult{
+			Pkg:       testutil.NPMPackage("react", "1.0.0", "1.0.0"),
+			Target:    "1.2.0",
+			Available: []string{"1.1.0", "1.2.0"},
+		},
+		VersionsInConstraint: []string{"0.9.0", "1.1.0", "2.0.0", "2.1.0-rc.1", "1.2.0"},
+	}
+	assert.Equal(t, []string{"2.0.0", "1.2.0", "1.1.0"}, CandidateVersions(plan))
+
+	plan.Res.Target = "2.1.0-rc.1"
+	assert.Equal(t, []string{"2.1.0-rc.1", "2.0.0", "1.2.0", "1.1.0"}, CandidateVersions(plan))
+}
+
+// selectionTestPlans returns two pending plans and one up-to-date plan.
+func selectionTestPlans() []*PlannedUpdate {
+	return []*PlannedUpdate{
+		{Res: UpdateResult{Pkg: testutil.NPMPackage("react", "1.0.0", "1.0.0"), Target: "1.2.0", Status: constants.StatusPlanned, Available: []string{"1.1.0", "1.2.0"}}},
+		{Res: UpdateResult{Pkg: testutil.NPMPackage("vue", "3.0.0", "3.0.0"), Status: constants.StatusUpToDate}},
+		{Res: UpdateResult{Pkg: testutil.NPMPackage("lodash", "4.0.0", "4.0.0"), Target: "4.1.0", Status: constants.StatusPlanned}},
+		{Res: UpdateResult{Pkg: testutil.NPMPackage("local", "1.0.0", "1.0.0"), Target: "2.0.0", Status: lock.InstallStatusNonRegistry}},
+	}
+}
+
+// TestSelectPlans tests interactive selection of planned updates.
+//
+// It verifies:
+//   - Enter without changes keeps every plan
+//   - Toggling a number drops that pending plan but keeps plans without a pending update
+//   - "v N" replaces the target with the chosen candidate
+//   - "n" deselects everything and "a" selects everything again
+//   - Unknown input is reported and the prompt repeats
+//   - "q" stops without a selection
+//   - Missing input returns an error
+func TestSelectPlans(t *testing.T) {
+	run := func(input string, plans []*PlannedUpdate) ([]*PlannedUpdate, bool, error, string) {
+		var out bytes.Buffer
+		selected, proceed, err := SelectPlans(bufio.NewReader(strings.NewReader(input)), &out, plans)
+		return selected, proceed, err, out.String()
+	}
+
+	plans := selectionTestPlans()
+	selected, proceed, err, out := run("\n", plans)
+	require.NoError(t, err)
+	assert.True(t, proceed)
+	assert.Equal(t, plans, selected)
+	assert.Contains(t, out, " 1. [x] react (js/npm) 1.0.0 → 1.2.0")
+	assert.Contains(t, out, " 2. [x] lodash (js/npm) 4.0.0 → 4.1.0")
+	assert.NotContains(t, out, "vue")
+
+	plans = selectionTestPlans()
+	selected, proceed, err, _ = run("2\nv 1\n2\n\n", plans)
+	require.NoError(t, err)
+	assert.True(t, proceed)
+	assert.Equal(t, []*PlannedUpdate{plans[0], plans[1], plans[3]}, selected)
+	assert.Equal(t, "1.1.0", plans[0].Res.Target)
+
+	plans = selectionTestPlans()
+	selected, _, err, _ = run("n\n", plans)
+	require.Error(t, err, "input ends before the selection is accepted")
+	assert.Nil(t, selected)
+
+	plans = selectionTestPlans()
+	selected, _, err, out = run("n\na\nfoo 7\n\n", plans)
+	require.NoError(t, err)
+	assert.Len(t, selected, 4)
+	assert.Contains(t, out, `Unknown selection "foo"`)
+	assert.Contains(t, out, `Unknown selection "7"`)
+
+	plans = selectionTestPlans()
+	selected, _, err, _ = run("n\n\n", plans)
+	require.NoError(t, err)
+	assert.Equal(t, []*PlannedUpdate{plans[1], plans[3]}, selected)
+
+	_, proceed, err, _ = run("q\n", selectionTestPlans())
+	require.NoError(t, err)
+	assert.False(t, proceed)
+}
@@ -0,0 +1,152 @@
+package update
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+)
+
+// Priorities --limit-by ranks pending updates with.
+const (
+	// LimitByGap applies the updates with the largest version gap first (the default).
+	LimitByGap = "gap"
+	// LimitByAge applies the updates whose installed version was superseded longest ago first.
+	LimitByAge = "age"
+)
+
+// PlanAgeFunc reports how long ago a plan's installed version was superseded.
+//
+// It returns false when the age is unknown, e.g. for rules without release dates.
+type PlanAgeFunc func(plan *PlannedUpdate) (time.Duration, bool)
+
+// ParseLimitBy validates a --limit-by value.
+//
+// Parameters:
+//   - value: The flag value; empty selects LimitByGap
+//
+// Returns:
+//   - string: LimitByGap or LimitByAge
+//   - error: When value is neither
+func ParseLimitBy(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", LimitByGap:
+		return LimitByGap, nil
+	case LimitByAge:
+		return LimitByAge, nil
+	default:
+		return "", fmt.Errorf("invalid --limit-by %q: expected %s or %s", value, LimitByGap, LimitByAge)
+	}
+}
+
+// limitUnit is a set of pending plans that are applied or deferred together.
+//
+// Fields:
+//   - plans: Pending plans sharing a rule and group key
+//   - gap: Largest version gap of the plans
+//   - age: Longest superseded age of the plans
+//   - hasAge: Whether any plan's age is known
+type limitUnit struct {
+	plans  []*PlannedUpdate
+	gap    [3]int
+	age    time.Duration
+	hasAge bool
+}
+
+// LimitPlans keeps at most limit pending updates and defers the rest.
+//
+// It performs the following operations:
+//   - Step 1: Collect pending updates, keeping the plans of one update group
+//     together; groups are keyed by rule and group key as execution runs them
+//   - Step 2: Rank the groups by priority: largest version gap first, or for
+//     LimitByAge longest superseded first (unknown ages last, then by gap)
+//   - Step 3: Keep groups in rank order while they fit into the limit; a group
+//     that does not fit is deferred and smaller groups after it may still fit
+//   - Step 4: Mark the plans of every deferred group constants.StatusDeferred
+//
+// Deferred plans stay in the list, so they are listed in the output without
+// being applied. Plans without a pending update are left unchanged.
+//
+// Parameters:
+//   - plans: Planned updates from BuildGroupedPlans
+//   - limit: Maximum number of packages to update; zero or less disables the limit
+//   - by: LimitByGap or LimitByAge
+//   - age: Age lookup for LimitByAge; may be nil
+//
+// Returns:
+//   - int: Number of deferred packages
+func LimitPlans(plans []*PlannedUpdate, limit int, by string, age PlanAgeFunc) int {
+	if limit <= 0 {
+		return 0
+	}
+
+	var units []*limitUnit
+	byKey := make(map[string]*limitUnit)
+	for _, plan := range plans {
+		if ShouldSkipUpdate(&plan.Res) || versionsMatch(plan.Res.Target, plan.Original) {
+			continue
+		}
+		key := planRule(plan) + "|" + planGroup(plan)
+		unit, ok := byKey[key]
+		if !ok {
+			unit = &limitUnit{}
+			byKey[key] = unit
+			units = append(units, unit)
+		}
+		unit.plans = append(unit.plans, plan)
+
+		if gap, ok := outdated.VersionGap(outdated.CurrentVersionForOutdated(plan.Res.Pkg), plan.Res.Target); ok && compareGap(gap, unit.gap) > 0 {
+			unit.gap = gap
+		}
+		if by == LimitByAge && age != nil {
+			if d, ok := age(plan); ok && (!unit.hasAge || d > unit.age) {
+				unit.age, unit.hasAge = d, true
+			}
+		}
+	}
+
+	sort.SliceStable(units, func(i, j int) bool {
+		a, b := units[i], units[j]
+		if by == LimitByAge && (a.hasAge != b.hasAge || a.age != b.age) {
+			if a.hasAge != b.hasAge {
+				return a.hasAge
+			}
+			return a.age > b.age
+		}
+		return compareGap(a.gap, b.gap) > 0
+	})
+
+	deferred := 0
+	remaining := limit
+	for _, unit := range units {
+		if len(unit.plans) <= remaining {
+			remaining -= len(unit.plans)
+			continue
+		}
+		for _, plan := range unit.plans {
+			plan.Res.Status = constants.StatusDeferred
+			deferred++
+		}
+	}
+	return deferred
+}
+
+// compareGap compares two version gaps component by component.
+//
+// Parameters:
+//   - a: First gap
+//   - b: Second gap
+//
+// Returns:
+//   - int: Positive when a is larger, negative when b is larger, zero when equal
+func compareGap(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return 0
+}
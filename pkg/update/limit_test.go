@@ -0,0 +1,129 @@
+package update
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// limitPlan returns a pending plan of an npm package from version to target.
+func limitPlan(name, version, target, group string) *PlannedUpdate {
+	key := group
+	if key == "" {
+		key = name
+	}
+	return &PlannedUpdate{
+		Res:      UpdateResult{Pkg: testutil.NPMPackage(name, version, version), Target: target, Status: constants.StatusUpToDate},
+		Original: version,
+		GroupKey: key,
+	}
+}
+
+// statuses returns the name and status of every plan.
+func statuses(plans []*PlannedUpdate) map[string]string {
+	byName := make(map[string]string)
+	for _, plan := range plans {
+		byName[plan.Res.Pkg.Name] = plan.Res.Status
+	}
+	return byName
+}
+
+// TestParseLimitBy tests the behavior of ParseLimitBy.
+//
+// It verifies:
+//   - Empty input selects the gap priority
+//   - gap and age are accepted case-insensitively; anything else is rejected
+func TestParseLimitBy(t *testing.T) {
+	for value, expected := range map[string]string{"": LimitByGap, "Gap": LimitByGap, " age ": LimitByAge} {
+		by, err := ParseLimitBy(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, by, value)
+	}
+	_, err := ParseLimitBy("size")
+	assert.ErrorContains(t, err, "invalid --limit-by")
+}
+
+// TestLimitPlans tests capping the number of updated packages.
+//
+// It verifies:
+//   - A non-positive limit changes nothing
+//   - The largest version gaps are kept and the rest are marked Deferred
+//   - Plans of one update group are kept or deferred together, and a smaller group may fill the rest
+//   - Groups of different rules sharing a group key are limited separately
+//   - Plans without a pending update neither count nor change
+//   - Deferred plans are skipped like other non-updatable plans
+func TestLimitPlans(t *testing.T) {
+	build := func() []*PlannedUpdate {
+		return []*PlannedUpdate{
+			limitPlan("patch", "1.0.0", "1.0.5", ""),
+			limitPlan("major", "1.0.0", "3.0.0", ""),
+			limitPlan("react", "17.0.0", "18.0.0", "react"),
+			limitPlan("react-dom", "17.0.0", "18.0.0", "react"),
+			limitPlan("minor", "1.0.0", "1.4.0", ""),
+			limitPlan("current", "2.0.0", "2.0.0", ""),
+		}
+	}
+
+	plans := build()
+	assert.Equal(t, 0, LimitPlans(plans, 0, LimitByGap, nil))
+	for name, status := range statuses(plans) {
+		assert.NotEqual(t, constants.StatusDeferred, status, name)
+	}
+
+	plans = build()
+	assert.Equal(t, 3, LimitPlans(plans, 2, LimitByGap, nil))
+	assert.Equal(t, map[string]string{
+		"patch":     constants.StatusDeferred,
+		"major":     constants.StatusUpToDate,
+		"react":     constants.StatusDeferred,
+		"react-dom": constants.StatusDeferred,
+		"minor":     constants.StatusUpToDate,
+		"current":   constants.StatusUpToDate,
+	}, statuses(plans))
+	assert.True(t, ShouldSkipUpdate(&plans[0].Res))
+
+	plans = build()
+	assert.Equal(t, 2, LimitPlans(plans, 3, LimitByGap, nil))
+	assert.Equal(t, constants.StatusUpToDate, statuses(plans)["react-dom"])
+	assert.Equal(t, constants.StatusDeferred, statuses(plans)["minor"])
+
+	// Equal group keys of different rules are separate groups
+	plans = build()
+	plans[3].Res.Pkg.Rule = "pnpm"
+	assert.Equal(t, 3, LimitPlans(plans, 2, LimitByGap, nil))
+	assert.Equal(t, constants.StatusUpToDate, statuses(plans)["react"])
+	assert.Equal(t, constants.StatusDeferred, statuses(plans)["react-dom"])
+	assert.Equal(t, constants.StatusDeferred, statuses(plans)["minor"])
+}
+
+// TestLimitPlansByAge tests ranking --limit by how long updates have been pending.
+//
+// It verifies:
+//   - Updates superseded longest ago are kept first
+//   - Updates with an unknown age rank after known ones, ordered by gap
+func TestLimitPlansByAge(t *testing.T) {
+	plans := []*PlannedUpdate{
+		limitPlan("fresh", "1.0.0", "2.0.0", ""),
+		limitPlan("stale", "1.0.0", "1.0.1", ""),
+		limitPlan("unknown-major", "1.0.0", "2.0.0", ""),
+		limitPlan("unknown-patch", "1.0.0", "1.0.1", ""),
+	}
+	ages := map[string]time.Duration{"fresh": time.Hour, "stale": 400 * 24 * time.Hour}
+	age := func(plan *PlannedUpdate) (time.Duration, bool) {
+		d, ok := ages[plan.Res.Pkg.Name]
+		return d, ok
+	}
+
+	assert.Equal(t, 1, LimitPlans(plans, 3, LimitByAge, age))
+	assert.Equal(t, map[string]string{
+		"fresh":         constants.StatusUpToDate,
+		"stale":         constants.StatusUpToDate,
+		"unknown-major": constants.StatusUpToDate,
+		"unknown-patch": constants.StatusDeferred,
+	}, statuses(plans))
+}
@@ -0,0 +1,256 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/ratelimit"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// LockRefresh is the outcome of running a rule's lock command in one manifest directory.
+//
+// Fields:
+//   - Rule: Configuration rule the lock command belongs to
+//   - Dir: Directory the lock command ran in
+//   - Changed: Lock files whose content changed, sorted
+//   - Duration: How long the lock command took
+//   - Skipped: Why the directory was not refreshed (e.g. no lock command); empty when it ran
+//   - Err: Why the lock command failed; the directory's files are restored
+type LockRefresh struct {
+	Rule     string
+	Dir      string
+	Changed  []string
+	Duration time.Duration
+	Skipped  string
+	Err      error
+}
+
+// InstalledChange is a package whose installed version changed during a lock refresh.
+//
+// Fields:
+//   - Pkg: The package as reloaded after the refresh
+//   - From: Installed version before the refresh
+//   - To: Installed version after the refresh
+type InstalledChange struct {
+	Pkg  formats.Package
+	From string
+	To   string
+}
+
+// lockRefreshTarget is one rule and lock directory to refresh, with the manifests locked there.
+type lockRefreshTarget struct {
+	rule      string
+	dir       string
+	manifests []string
+}
+
+// lockRefreshTargets returns the distinct rule and lock directory pairs of pkgs,
+// sorted by rule then directory. Workspace members share their root's target.
+func lockRefreshTargets(cfg *config.Config, workDir string, pkgs []formats.Package) []lockRefreshTarget {
+	if workDir == "" {
+		workDir = "."
+	}
+
+	byKey := make(map[string]*lockRefreshTarget)
+	var keys []string
+	for _, p := range pkgs {
+		dir := packageLockDir(cfg, p, workDir)
+		key := p.Rule + "\x00" + dir
+		target, ok := byKey[key]
+		if !ok {
+			target = &lockRefreshTarget{rule: p.Rule, dir: dir}
+			byKey[key] = target
+			keys = append(keys, key)
+		}
+		if p.Source != "" && !containsString(target.manifests, p.Source) {
+			target.manifests = append(target.manifests, p.Source)
+		}
+	}
+
+	sort.Strings(keys)
+	targets := make([]lockRefreshTarget, 0, len(keys))
+	for _, key := range keys {
+		targets = append(targets, *byKey[key])
+	}
+	return targets
+}
+
+// RefreshLockFiles regenerates lock files to match the current manifests.
+//
+// No versions are looked up and no declared version is rewritten: each rule's
+// lock_files refresh_commands runs once in every directory holding one of its
+// manifests (once in the root for a workspace). Rules without a refresh command
+// are skipped with an unsupported reason.
+//
+// It performs the following operations:
+//   - Step 1: Split the packages into rule and lock directory pairs
+//   - Step 2: Back up the directory's manifests and lock files
+//   - Step 3: Run the rule's refresh command, restoring the backups when it fails
+//   - Step 4: Record which lock files changed
+//
+// Dry runs report the directories without running anything. A failure is
+// recorded on the context and does not stop the remaining directories.
+//
+// Parameters:
+//   - ctx: Update context; Cfg, WorkDir, DryRun and the cancellation context are used
+//   - pkgs: Packages whose manifests should have their lock files refreshed
+//
+// Returns:
+//   - []LockRefresh: One entry per rule and directory, sorted by rule then directory
+func RefreshLockFiles(ctx *UpdateContext, pkgs []formats.Package) []LockRefresh {
+	var refreshes []LockRefresh
+	for _, target := range lockRefreshTargets(ctx.Cfg, ctx.WorkDir, pkgs) {
+		if ctx.checkCancelled() != nil {
+			break
+		}
+		refreshes = append(refreshes, refreshLockTarget(ctx, target))
+	}
+	return refreshes
+}
+
+// LockRefreshOperation is the UnsupportedError operation for rules --lockfile-only cannot refresh.
+const LockRefreshOperation = "lockfile-only"
+
+// refreshUpdateCfg returns the command that refreshes a rule's lock files.
+//
+// The command is the first lock_files[].refresh_commands of the rule, run with
+// the env and timeout of the rule's update config. The rule's update commands
+// are never used: they usually update the single package in {{package}}.
+//
+// Parameters:
+//   - rule: The rule name, for the error
+//   - ruleCfg: The rule configuration
+//
+// Returns:
+//   - *config.UpdateCfg: Config running the refresh command
+//   - error: UnsupportedError with LockRefreshOperation when the rule has no usable refresh command
+func refreshUpdateCfg(rule string, ruleCfg config.PackageManagerCfg) (*config.UpdateCfg, error) {
+	for _, lockCfg := range ruleCfg.LockFiles {
+		commands := strings.TrimSpace(lockCfg.RefreshCommands)
+		if commands == "" {
+			continue
+		}
+		if strings.Contains(commands, "{{package}}") {
+			return nil, errors.NewUnsupportedError(LockRefreshOperation, fmt.Sprintf("lock_files refresh_commands of rule %s cannot use {{package}}", rule), "")
+		}
+		refreshCfg := &config.UpdateCfg{Commands: commands}
+		if ruleCfg.Update != nil {
+			refreshCfg.Env = ruleCfg.Update.Env
+			refreshCfg.TimeoutSeconds = ruleCfg.Update.TimeoutSeconds
+		}
+		return refreshCfg, nil
+	}
+	return nil, errors.NewUnsupportedError(LockRefreshOperation, fmt.Sprintf("rule %s has no lock_files refresh_commands", rule), "")
+}
+
+// refreshLockTarget runs the refresh command of one rule in one directory.
+func refreshLockTarget(ctx *UpdateContext, target lockRefreshTarget) LockRefresh {
+	refresh := LockRefresh{Rule: target.rule, Dir: target.dir}
+	if ctx.Cfg == nil {
+		refresh.Skipped = "configuration is required"
+		return refresh
+	}
+
+	ruleCfg, ok := ctx.Cfg.Rules[target.rule]
+	if !ok {
+		refresh.Skipped = "no rule configured"
+		return refresh
+	}
+	refreshCfg, err := refreshUpdateCfg(target.rule, ruleCfg)
+	if err != nil {
+		refresh.Skipped = err.Error()
+		return refresh
+	}
+	if ctx.DryRun {
+		return refresh
+	}
+
+	lockPaths := getLockFilePaths(ruleCfg, target.dir)
+	backups, err := backupFiles(append(append([]string(nil), target.manifests...), lockPaths...))
+	if err != nil {
+		refresh.Err = err
+		ctx.AppendFailure(fmt.Errorf("%s (%s): %w", target.rule, target.dir, err))
+		return refresh
+	}
+	before := make(map[string][]byte, len(backups))
+	for _, backup := range backups {
+		before[backup.path] = backup.content
+	}
+
+	if ctx.Cfg.NoTimeout {
+		refreshCfg.TimeoutSeconds = 0
+	}
+
+	verbose.Printf("Refreshing lock files for %s in %s\n", target.rule, target.dir)
+	start := time.Now()
+	lockErr := ratelimit.ForRule(ctx.Cfg, target.rule).Wait(context.Background())
+	if lockErr == nil {
+		lockErr = RunGroupLockCommand(refreshCfg, target.dir, false)
+	}
+	refresh.Duration = time.Since(start)
+	if lockErr != nil {
+		if errors.IsUnsupported(lockErr) {
+			refresh.Skipped = lockErr.Error()
+			return refresh
+		}
+		if restoreErrs := restoreBackups(backups); len(restoreErrs) > 0 {
+			lockErr = fmt.Errorf("%w (restore failed: %v)", lockErr, restoreErrs)
+		}
+		refresh.Err = lockErr
+		ctx.AppendFailure(fmt.Errorf("%s (%s): lock command failed: %w", target.rule, target.dir, lockErr))
+		return refresh
+	}
+
+	// Lock files created by the command are changes too
+	for _, path := range getLockFilePaths(ruleCfg, target.dir) {
+		content, readErr := os.ReadFile(path)
+		if readErr != nil {
+			continue
+		}
+		if original, existed := before[path]; !existed || !bytes.Equal(original, content) {
+			refresh.Changed = append(refresh.Changed, path)
+		}
+	}
+	sort.Strings(refresh.Changed)
+	return refresh
+}
+
+// InstalledChanges compares reloaded packages against the baseline taken
+// before a lock refresh.
+//
+// Parameters:
+//   - baseline: Snapshot taken with SnapshotVersions before the refresh
+//   - reloaded: Packages reloaded after the refresh
+//
+// Returns:
+//   - []InstalledChange: Packages whose installed version changed, in reload order
+func InstalledChanges(baseline map[string]VersionSnapshot, reloaded []formats.Package) []InstalledChange {
+	var changes []InstalledChange
+	for _, p := range reloaded {
+		snapshot, ok := baseline[PackageSourceKey(p)]
+		if !ok || versionsMatch(snapshot.Installed, p.InstalledVersion) {
+			continue
+		}
+		changes = append(changes, InstalledChange{Pkg: p, From: snapshot.Installed, To: p.InstalledVersion})
+	}
+	return changes
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package update
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	pkgerrors "github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// TestRefreshLockFiles tests regenerating lock files without bumping manifests.
+//
+// It verifies:
+//   - The refresh command runs once per rule and manifest directory, without package replacements
+//   - The rule's update env and timeout apply to the refresh command
+//   - Changed and newly created lock files are reported; unchanged ones are not
+//   - A failing refresh command restores the directory's files and is recorded as a failure
+//   - Rules without refresh_commands are skipped as unsupported, even with a {{package}} update command
+//   - Dry runs and cancelled contexts run nothing
+func TestRefreshLockFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"web", "admin", "api", "docs"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "package.json"), []byte(`{}`), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "web", "package-lock.json"), []byte("old"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "admin", "package-lock.json"), []byte("same"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "api", "package-lock.json"), []byte("api"), 0o644))
+
+	rule := testutil.NPMRule()
+	rule.Update.Env = map[string]string{"NPM_CONFIG_FUND": "false"}
+	rule.Update.TimeoutSeconds = 90
+	rule.LockFiles = []config.LockFileCfg{{Files: []string{"package-lock.json"}, RefreshCommands: "npm install --package-lock-only"}}
+	cfg := testutil.NewConfig().
+		WithRule("npm", rule).
+		WithRule("manual", config.PackageManagerCfg{Manager: "js", Update: &config.UpdateCfg{Commands: "manual add {{package}}"}}).
+		Build()
+
+	var runs []string
+	originalExec := execCommandFunc
+	execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		assert.Empty(t, pkg)
+		assert.Empty(t, version)
+		assert.Equal(t, "npm install --package-lock-only", cfg.Commands)
+		assert.Equal(t, "false", cfg.Env["NPM_CONFIG_FUND"])
+		assert.Equal(t, 90, cfg.TimeoutSeconds)
+		runs = append(runs, filepath.Base(dir))
+		switch filepath.Base(dir) {
+		case "web":
+			return nil, os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte("new"), 0o644)
+		case "api":
+			_ = os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte("partial"), 0o644)
+			return nil, stderrors.New("npm install failed")
+		case "docs":
+			return nil, os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte("created"), 0o644)
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	pkg := func(rule, name, dir string) formats.Package {
+		return formats.Package{Rule: rule, PackageType: "js", Name: name, Version: "1.0.0", Source: filepath.Join(root, dir, "package.json")}
+	}
+	pkgs := []formats.Package{
+		pkg("npm", "react", "web"),
+		pkg("npm", "react-dom", "web"),
+		pkg("npm", "express", "api"),
+		pkg("npm", "vue", "admin"),
+		pkg("npm", "vitepress", "docs"),
+		pkg("manual", "left-pad", "web"),
+	}
+
+	ctx := NewUpdateContext(cfg, root, nil)
+	refreshes := RefreshLockFiles(ctx, pkgs)
+
+	assert.Equal(t, []string{"admin", "api", "docs", "web"}, runs)
+	require.Len(t, refreshes, 5)
+	assert.Equal(t, "manual", refreshes[0].Rule)
+	assert.Equal(t, "lockfile-only not supported: rule manual has no lock_files refresh_commands", refreshes[0].Skipped)
+	assert.Empty(t, refreshes[1].Changed, "admin lock file is unchanged")
+	assert.Error(t, refreshes[2].Err)
+	assert.Equal(t, []string{filepath.Join(root, "docs", "package-lock.json")}, refreshes[3].Changed)
+	assert.Equal(t, []string{filepath.Join(root, "web", "package-lock.json")}, refreshes[4].Changed)
+
+	restored, err := os.ReadFile(filepath.Join(root, "api", "package-lock.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "api", string(restored))
+	require.Len(t, ctx.Failures, 1)
+	assert.Contains(t, ctx.Failures[0].Error(), "npm install failed")
+
+	runs = nil
+	assert.Len(t, RefreshLockFiles(NewUpdateContext(cfg, root, nil).WithFlags(true, false, false), pkgs), 5)
+	assert.Empty(t, runs)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Empty(t, RefreshLockFiles(NewUpdateContext(cfg, root, nil).WithContext(cancelled), pkgs))
+	assert.Empty(t, runs)
+}
+
+// TestRefreshLockFilesWorkspace tests refreshing the root lock file of a workspace.
+//
+// It verifies:
+//   - The lock command runs once in the workspace root for all members
+//   - A failing lock command restores the root lock file and every member manifest
+func TestRefreshLockFilesWorkspace(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"app", "lib"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "packages", dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "packages", dir, "package.json"), []byte(`{}`), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pnpm-lock.yaml"), []byte("old"), 0o644))
+
+	rule := testutil.NPMRule()
+	workspace := true
+	rule.Workspace = &workspace
+	rule.LockFiles = []config.LockFileCfg{{Files: []string{"**/pnpm-lock.yaml"}, RefreshCommands: "pnpm install --lockfile-only"}}
+	cfg := testutil.NewConfig().WithRule("pnpm", rule).Build()
+
+	var runs []string
+	originalExec := execCommandFunc
+	execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		runs = append(runs, dir)
+		_ = os.WriteFile(filepath.Join(root, "packages", "lib", "package.json"), []byte(`{"changed":true}`), 0o644)
+		_ = os.WriteFile(filepath.Join(dir, "pnpm-lock.yaml"), []byte("partial"), 0o644)
+		return nil, stderrors.New("pnpm install failed")
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	pkgs := []formats.Package{
+		{Rule: "pnpm", Name: "react", Source: filepath.Join(root, "packages", "app", "package.json")},
+		{Rule: "pnpm", Name: "lodash", Source: filepath.Join(root, "packages", "lib", "package.json")},
+	}
+	refreshes := RefreshLockFiles(NewUpdateContext(cfg, root, nil), pkgs)
+
+	assert.Equal(t, []string{root}, runs)
+	require.Len(t, refreshes, 1)
+	assert.Equal(t, root, refreshes[0].Dir)
+	assert.Error(t, refreshes[0].Err)
+
+	lock, err := os.ReadFile(filepath.Join(root, "pnpm-lock.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(lock))
+	manifest, err := os.ReadFile(filepath.Join(root, "packages", "lib", "package.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(manifest))
+}
+
+// TestInstalledChanges tests comparing installed versions after a lock refresh.
+//
+// It verifies:
+//   - Packages with a different installed version are reported with both versions
+//   - A "v" prefix difference and packages missing from the baseline are ignored
+func TestInstalledChanges(t *testing.T) {
+	before := []formats.Package{
+		testutil.NPMPackage("react", "^18.0.0", "18.2.0"),
+		testutil.NPMPackage("vue", "^3.0.0", "3.4.0"),
+	}
+	baseline := SnapshotVersions(before)
+
+	after := []formats.Package{
+		testutil.NPMPackage("react", "^18.0.0", "18.3.1"),
+		testutil.NPMPackage("vue", "^3.0.0", "v3.4.0"),
+		testutil.NPMPackage("lodash", "^4.0.0", "4.17.21"),
+	}
+
+	changes := InstalledChanges(baseline, after)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "react", changes[0].Pkg.Name)
+	assert.Equal(t, "18.2.0", changes[0].From)
+	assert.Equal(t, "18.3.1", changes[0].To)
+}
+
+// TestRefreshUpdateCfg tests resolving a rule's lock refresh command.
+//
+// It verifies:
+//   - The first lock file with refresh_commands is used
+//   - Rules without refresh_commands are unsupported
+//   - A refresh command using {{package}} is unsupported and never run
+func TestRefreshUpdateCfg(t *testing.T) {
+	rule := config.PackageManagerCfg{LockFiles: []config.LockFileCfg{
+		{Files: []string{"yarn.lock"}},
+		{Files: []string{"package-lock.json"}, RefreshCommands: "npm install --package-lock-only\n"},
+	}}
+	refreshCfg, err := refreshUpdateCfg("npm", rule)
+	require.NoError(t, err)
+	assert.Equal(t, "npm install --package-lock-only", refreshCfg.Commands)
+
+	_, err = refreshUpdateCfg("composer", config.PackageManagerCfg{Update: &config.UpdateCfg{Commands: "composer update {{package}}"}})
+	assert.True(t, pkgerrors.IsUnsupported(err))
+
+	_, err = refreshUpdateCfg("bundler", config.PackageManagerCfg{LockFiles: []config.LockFileCfg{{RefreshCommands: "bundle update {{package}}"}}})
+	assert.True(t, pkgerrors.IsUnsupported(err))
+	assert.Contains(t, err.Error(), "cannot use {{package}}")
+}
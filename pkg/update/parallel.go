@@ -0,0 +1,272 @@
+package update
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// groupConcurrency returns how many update groups of a rule may be processed at once.
+//
+// Groups always run sequentially in staged mode and when system tests run
+// after each update, because both mutate and test the shared working tree
+// step by step.
+//
+// Returns:
+//   - int: The effective group concurrency; 1 means sequential processing
+func (ctx *UpdateContext) groupConcurrency() int {
+	if ctx.GroupConcurrency <= 1 || ctx.Staged || ctx.ShouldRunSystemTestsAfterEach() {
+		return 1
+	}
+	return ctx.GroupConcurrency
+}
+
+// lockfileMutexes hands out one mutex per lock file path.
+//
+// Groups that touch the same lock file are serialized through its mutex,
+// while groups with disjoint lock files proceed in parallel.
+type lockfileMutexes struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// newLockfileMutexes creates an empty lock file mutex registry.
+func newLockfileMutexes() *lockfileMutexes {
+	return &lockfileMutexes{locks: make(map[string]*sync.Mutex)}
+}
+
+// lock acquires the mutexes of all keys and returns a function releasing them.
+//
+// Keys must be sorted so that groups sharing several lock files always
+// acquire them in the same order and cannot deadlock.
+//
+// Parameters:
+//   - keys: Sorted lock file keys of a group
+//
+// Returns:
+//   - func(): Releases the acquired mutexes in reverse order
+func (l *lockfileMutexes) lock(keys []string) func() {
+	held := make([]*sync.Mutex, 0, len(keys))
+	for _, key := range keys {
+		l.mu.Lock()
+		m, ok := l.locks[key]
+		if !ok {
+			m = &sync.Mutex{}
+			l.locks[key] = m
+		}
+		l.mu.Unlock()
+
+		m.Lock()
+		held = append(held, m)
+	}
+	return func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			held[i].Unlock()
+		}
+	}
+}
+
+// groupLockfileKeys returns the lock files a group may write, used to serialize groups.
+//
+// Each plan contributes the lock files of its rule found in its lock directory
+// (the manifest directory, or the workspace root), or the directory itself
+// when no lock file exists yet. Shared group
+// lock commands run in the same manifest directories, so they add no keys.
+//
+// Parameters:
+//   - ctx: Update context providing the configuration and working directory
+//   - plans: Planned updates of a single group
+//
+// Returns:
+//   - []string: Sorted, de-duplicated absolute lock file keys
+func groupLockfileKeys(ctx *UpdateContext, plans []*PlannedUpdate) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	add := func(rule, dir string) {
+		paths := []string{dir}
+		if ctx.Cfg != nil {
+			if found := getLockFilePaths(ctx.Cfg.Rules[rule], dir); len(found) > 0 {
+				paths = found
+			}
+		}
+		for _, path := range paths {
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+			if !seen[path] {
+				seen[path] = true
+				keys = append(keys, path)
+			}
+		}
+	}
+
+	workDir := ctx.WorkDir
+	if workDir == "" {
+		workDir = "."
+	}
+	for _, plan := range plans {
+		add(plan.Res.Pkg.Rule, packageLockDir(ctx.Cfg, plan.Res.Pkg, workDir))
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// runRuleGroups runs the update groups of a rule, in parallel when enabled.
+//
+// It performs the following operations:
+//   - Step 1: Fall back to sequential processing when the group concurrency is 1
+//   - Step 2: Start each group once a concurrency slot and the mutexes of its lock files are free
+//   - Step 3: Collect each group's results in its own slot
+//   - Step 4: Merge the slots into results in plan order once all groups finished
+//
+// Rollback stays group-local: each group processor only rolls back the plans
+// it applied itself, so a failing group never touches a sibling's updates.
+//
+// Parameters:
+//   - ctx: Update context containing configuration and tracking state
+//   - groups: The rule's update groups in plan order
+//   - results: Pointer to results slice receiving all group results
+//   - processGroup: Function processing a single group into the given results slice
+func runRuleGroups(ctx *UpdateContext, groups [][]*PlannedUpdate, results *[]UpdateResult, processGroup func(groupPlans []*PlannedUpdate, results *[]UpdateResult)) {
+	concurrency := ctx.groupConcurrency()
+	if concurrency <= 1 || len(groups) <= 1 {
+		for _, groupPlans := range groups {
+			if ctx.checkCancelled() != nil {
+				break
+			}
+			processGroup(groupPlans, results)
+		}
+		return
+	}
+
+	verbose.Debugf("Processing %d groups with up to %d in parallel", len(groups), concurrency)
+
+	slots := make([][]UpdateResult, len(groups))
+	mutexes := newLockfileMutexes()
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, groupPlans := range groups {
+		if ctx.checkCancelled() != nil {
+			break
+		}
+		keys := groupLockfileKeys(ctx, groupPlans)
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, groupPlans []*PlannedUpdate, keys []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			unlock := mutexes.lock(keys)
+			defer unlock()
+			if ctx.checkCancelled() != nil {
+				return
+			}
+			processGroup(groupPlans, &slots[i])
+		}(i, groupPlans, keys)
+	}
+	wg.Wait()
+
+	for _, slot := range slots {
+		*results = append(*results, slot...)
+	}
+}
+
+// progressTracker serializes progress reports and counts finished packages.
+//
+// Fields:
+//   - mu: Guards current and every call into progress
+//   - progress: The wrapped reporter
+//   - current: Number of packages reported so far
+//   - total: Number of packages in the run
+type progressTracker struct {
+	mu       sync.Mutex
+	progress ProgressReporter
+	current  int
+	total    int
+}
+
+// Increment reports an unnamed package.
+func (t *progressTracker) Increment() {
+	t.packageDone("")
+}
+
+// packageDone counts a finished package and reports it to the wrapped reporter.
+//
+// The wrapped reporter receives ReportPackage when it implements
+// PackageProgressReporter and Increment otherwise. Both run under the lock, so
+// concurrent groups never interleave reports or skip a position.
+//
+// Parameters:
+//   - name: Name of the package that finished
+func (t *progressTracker) packageDone(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current++
+	if rich, ok := t.progress.(PackageProgressReporter); ok {
+		rich.ReportPackage(t.current, max(t.total, t.current), name)
+		return
+	}
+	t.progress.Increment()
+}
+
+// trackProgress wraps progress so concurrent groups can report finished packages.
+//
+// Parameters:
+//   - progress: The progress reporter to wrap; nil is returned unchanged
+//   - total: Number of packages in the run
+//
+// Returns:
+//   - ProgressReporter: A reporter safe for concurrent use that counts packages
+func trackProgress(progress ProgressReporter, total int) ProgressReporter {
+	if progress == nil {
+		return nil
+	}
+	if tracker, ok := progress.(*progressTracker); ok {
+		return tracker
+	}
+	return &progressTracker{progress: progress, total: total}
+}
+
+// advanceProgress reports a finished package to progress.
+//
+// Progress wrapped by trackProgress learns the package's position and name;
+// any other reporter is simply incremented.
+//
+// Parameters:
+//   - progress: The progress reporter; may be nil
+//   - name: Name of the package that finished
+func advanceProgress(progress ProgressReporter, name string) {
+	switch p := progress.(type) {
+	case nil:
+		return
+	case *progressTracker:
+		p.packageDone(name)
+	default:
+		p.Increment()
+	}
+}
+
+// syncCallbacks wraps the result callback so concurrent groups print one row at a time.
+//
+// Parameters:
+//   - callbacks: The execution callbacks to wrap
+//
+// Returns:
+//   - ExecutionCallbacks: Callbacks whose OnResultReady is serialized
+func syncCallbacks(callbacks ExecutionCallbacks) ExecutionCallbacks {
+	if callbacks.OnResultReady == nil {
+		return callbacks
+	}
+	var mu sync.Mutex
+	onResultReady := callbacks.OnResultReady
+	callbacks.OnResultReady = func(res UpdateResult, dryRun bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		onResultReady(res, dryRun)
+	}
+	return callbacks
+}
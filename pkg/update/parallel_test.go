@@ -0,0 +1,373 @@
+package update
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/output"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// The CLI progress indicator renders per-package reports.
+var _ PackageProgressReporter = (*output.Progress)(nil)
+
+// parallelPlan builds a planned update for an npm package declared in dir.
+func parallelPlan(name, dir, groupKey string) *PlannedUpdate {
+	pkg := testutil.NPMPackage(name, "1.0.0", "1.0.0")
+	pkg.Source = filepath.Join(dir, "package.json")
+	return &PlannedUpdate{
+		Res:      UpdateResult{Pkg: pkg, Target: "2.0.0", Status: constants.StatusPlanned},
+		Cfg:      &config.UpdateCfg{Commands: "npm install"},
+		Original: "1.0.0",
+		GroupKey: groupKey,
+	}
+}
+
+// countingProgress counts Increment calls; Increment is not safe for concurrent use on its own.
+type countingProgress struct {
+	count int
+}
+
+func (c *countingProgress) Increment() {
+	c.count++
+}
+
+// packageReport is one ReportPackage call.
+type packageReport struct {
+	current, total int
+	name           string
+}
+
+// reportingProgress records ReportPackage calls; like countingProgress it is not safe for concurrent use.
+type reportingProgress struct {
+	increments int
+	reports    []packageReport
+}
+
+func (r *reportingProgress) Increment() {
+	r.increments++
+}
+
+func (r *reportingProgress) ReportPackage(current, total int, name string) {
+	r.reports = append(r.reports, packageReport{current, total, name})
+}
+
+// TestGroupConcurrency tests the effective group concurrency of a context.
+//
+// It verifies:
+//   - Values of 1 or less run groups sequentially
+//   - Staged mode always runs groups sequentially
+//   - Other values are used as given
+func TestGroupConcurrency(t *testing.T) {
+	ctx := NewUpdateContext(testutil.NewConfig().Build(), ".", nil)
+	assert.Equal(t, 1, ctx.groupConcurrency())
+
+	ctx.WithGroupConcurrency(-3)
+	assert.Equal(t, 1, ctx.groupConcurrency())
+
+	ctx.WithGroupConcurrency(4)
+	assert.Equal(t, 4, ctx.groupConcurrency())
+
+	ctx.WithStaged(true)
+	assert.Equal(t, 1, ctx.groupConcurrency())
+}
+
+// TestGroupLockfileKeys tests the lock file keys used to serialize groups.
+//
+// It verifies:
+//   - Existing lock files next to the manifest are used as keys
+//   - The manifest directory is the key when no lock file exists
+//   - Groups with a shared lock command add no keys outside their manifest directories
+//   - Keys are sorted and de-duplicated
+func TestGroupLockfileKeys(t *testing.T) {
+	root := t.TempDir()
+	dirA := filepath.Join(root, "a")
+	dirB := filepath.Join(root, "b")
+	require.NoError(t, os.MkdirAll(dirA, 0o755))
+	require.NoError(t, os.MkdirAll(dirB, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dirA, "package-lock.json"), []byte("{}"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package-lock.json"), []byte("{}"), 0o644))
+
+	rule := testutil.NPMRule()
+	rule.LockFiles = []config.LockFileCfg{{Files: []string{"package-lock.json"}}}
+	cfg := testutil.NewConfig().WithRule("npm", rule).Build()
+	ctx := NewUpdateContext(cfg, root, nil)
+
+	assert.Equal(t, []string{filepath.Join(dirA, "package-lock.json")}, groupLockfileKeys(ctx, []*PlannedUpdate{parallelPlan("react", dirA, "a")}))
+	assert.Equal(t, []string{dirB}, groupLockfileKeys(ctx, []*PlannedUpdate{parallelPlan("vue", dirB, "b")}))
+
+	grouped := []*PlannedUpdate{parallelPlan("react", dirA, "a"), parallelPlan("react-dom", dirA, "a"), parallelPlan("vue", dirB, "a")}
+	assert.Equal(t, []string{filepath.Join(dirA, "package-lock.json"), dirB}, groupLockfileKeys(ctx, grouped))
+
+	ctx.WithFlags(false, false, true)
+	assert.Equal(t, []string{filepath.Join(dirA, "package-lock.json"), dirB}, groupLockfileKeys(ctx, grouped))
+}
+
+// TestProcessGroupedPlansParallel tests parallel processing of a rule's groups.
+//
+// It verifies:
+//   - Groups with disjoint lock files run at the same time
+//   - Groups sharing a lock file never run at the same time
+//   - Results are merged in plan order regardless of completion order
+//   - A failing group with a shared lock command only rolls back its own plans
+//   - Progress is incremented once per package
+func TestProcessGroupedPlansParallel(t *testing.T) {
+	deriveReason := func(formats.Package, *config.Config, error, bool) string { return "" }
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+
+	t.Run("disjoint groups run concurrently", func(t *testing.T) {
+		root := t.TempDir()
+		plans := []*PlannedUpdate{
+			parallelPlan("react", filepath.Join(root, "a"), "a"),
+			parallelPlan("vue", filepath.Join(root, "b"), "b"),
+		}
+
+		// Each update waits until both groups have started, which only succeeds when they overlap
+		var started sync.WaitGroup
+		started.Add(2)
+		var overlapped atomic.Bool
+		updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			started.Done()
+			done := make(chan struct{})
+			go func() {
+				started.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+				overlapped.Store(true)
+			case <-time.After(2 * time.Second):
+			}
+			return nil
+		}
+		ctx := NewUpdateContext(cfg, root, nil).
+			WithUpdaterFunc(updater).
+			WithFlags(false, false, false).
+			WithGroupConcurrency(2)
+		var results []UpdateResult
+
+		ProcessGroupedPlansLive(ctx, plans, &results, ExecutionCallbacks{DeriveReason: deriveReason})
+
+		assert.True(t, overlapped.Load())
+		require.Len(t, results, 2)
+		assert.Equal(t, "react", results[0].Pkg.Name)
+		assert.Equal(t, "vue", results[1].Pkg.Name)
+	})
+
+	t.Run("groups sharing a lock file are serialized", func(t *testing.T) {
+		dir := t.TempDir()
+		plans := []*PlannedUpdate{
+			parallelPlan("react", dir, "a"),
+			parallelPlan("vue", dir, "b"),
+			parallelPlan("svelte", dir, "c"),
+		}
+
+		var active, maxActive atomic.Int32
+		updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			n := active.Add(1)
+			for {
+				m := maxActive.Load()
+				if n <= m || maxActive.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			active.Add(-1)
+			return nil
+		}
+		ctx := NewUpdateContext(cfg, dir, nil).
+			WithUpdaterFunc(updater).
+			WithFlags(false, false, false).
+			WithGroupConcurrency(3)
+		var results []UpdateResult
+
+		ProcessGroupedPlansLive(ctx, plans, &results, ExecutionCallbacks{DeriveReason: deriveReason})
+
+		assert.Equal(t, int32(1), maxActive.Load())
+		assert.Len(t, results, 3)
+	})
+
+	t.Run("results keep plan order", func(t *testing.T) {
+		root := t.TempDir()
+		plans := []*PlannedUpdate{
+			parallelPlan("react", filepath.Join(root, "a"), "a"),
+			parallelPlan("vue", filepath.Join(root, "b"), "b"),
+		}
+
+		// The first group only finishes after the second one has
+		vueDone := make(chan struct{})
+		var order []string
+		var mu sync.Mutex
+		updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			if p.Name == "react" {
+				<-vueDone
+			} else {
+				defer close(vueDone)
+			}
+			mu.Lock()
+			order = append(order, p.Name)
+			mu.Unlock()
+			return nil
+		}
+		var printed []string
+		callbacks := ExecutionCallbacks{
+			DeriveReason:  deriveReason,
+			OnResultReady: func(res UpdateResult, dryRun bool) { printed = append(printed, res.Pkg.Name) },
+		}
+		ctx := NewUpdateContext(cfg, root, nil).
+			WithUpdaterFunc(updater).
+			WithFlags(false, false, false).
+			WithGroupConcurrency(2)
+		var results []UpdateResult
+
+		ProcessGroupedPlansLive(ctx, plans, &results, callbacks)
+
+		assert.Equal(t, []string{"vue", "react"}, order)
+		assert.Equal(t, []string{"vue", "react"}, printed)
+		require.Len(t, results, 2)
+		assert.Equal(t, "react", results[0].Pkg.Name)
+		assert.Equal(t, "vue", results[1].Pkg.Name)
+		require.Len(t, ctx.RuleOutcomes, 1)
+		assert.Equal(t, 2, ctx.RuleOutcomes[0].Succeeded)
+	})
+
+	t.Run("failing group only rolls back its own plans", func(t *testing.T) {
+		lockErr := errors.New("lock failed")
+		originalExec := execCommandFunc
+		execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+			if cfg.Commands == "npm install --frontend" {
+				return nil, lockErr
+			}
+			return nil, nil
+		}
+		t.Cleanup(func() { execCommandFunc = originalExec })
+
+		root := t.TempDir()
+		plans := []*PlannedUpdate{
+			parallelPlan("react", filepath.Join(root, "web"), "frontend"),
+			parallelPlan("react-dom", filepath.Join(root, "web"), "frontend"),
+			parallelPlan("express", filepath.Join(root, "api"), "backend"),
+			parallelPlan("cors", filepath.Join(root, "api"), "backend"),
+		}
+		plans[0].Cfg.Commands = "npm install --frontend"
+
+		var mu sync.Mutex
+		var rollbacks []string
+		updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+			if target == "1.0.0" {
+				mu.Lock()
+				rollbacks = append(rollbacks, p.Name)
+				mu.Unlock()
+			}
+			return nil
+		}
+		ctx := NewUpdateContext(cfg, root, nil).
+			WithUpdaterFunc(updater).
+			WithFlags(false, false, false).
+			WithGroupConcurrency(2)
+		progress := &countingProgress{}
+		var results []UpdateResult
+
+		ProcessGroupedPlansWithProgress(ctx, plans, &results, progress, ExecutionCallbacks{DeriveReason: deriveReason})
+
+		assert.ElementsMatch(t, []string{"react", "react-dom"}, rollbacks)
+		require.Len(t, results, 4)
+		assert.Equal(t, constants.StatusFailed, plans[0].Res.Status)
+		assert.Equal(t, constants.StatusFailed, plans[1].Res.Status)
+		assert.Equal(t, constants.StatusUpdated, plans[2].Res.Status)
+		assert.Equal(t, constants.StatusUpdated, plans[3].Res.Status)
+		assert.Equal(t, 4, progress.count)
+		require.Len(t, ctx.RuleOutcomes, 1)
+		assert.Equal(t, 2, ctx.RuleOutcomes[0].Succeeded)
+		assert.NotEmpty(t, ctx.RuleOutcomes[0].Failures)
+	})
+}
+
+// TestProcessGroupedPlansPackageProgress tests progress reporting with a PackageProgressReporter.
+//
+// It verifies:
+//   - Concurrent groups report every package once, with consecutive positions and the run's total
+//   - ReportPackage replaces Increment for reporters implementing it
+//   - A nil reporter is accepted
+func TestProcessGroupedPlansPackageProgress(t *testing.T) {
+	deriveReason := func(formats.Package, *config.Config, error, bool) string { return "" }
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+	root := t.TempDir()
+	newPlans := func() []*PlannedUpdate {
+		return []*PlannedUpdate{
+			parallelPlan("react", filepath.Join(root, "a"), "a"),
+			parallelPlan("vue", filepath.Join(root, "b"), "b"),
+			parallelPlan("svelte", filepath.Join(root, "c"), "c"),
+		}
+	}
+	updater := func(formats.Package, string, *config.Config, string, bool, bool) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+	newCtx := func() *UpdateContext {
+		return NewUpdateContext(cfg, root, nil).
+			WithUpdaterFunc(updater).
+			WithFlags(false, false, false).
+			WithGroupConcurrency(3)
+	}
+
+	progress := &reportingProgress{}
+	var results []UpdateResult
+	ProcessGroupedPlansWithProgress(newCtx(), newPlans(), &results, progress, ExecutionCallbacks{DeriveReason: deriveReason})
+
+	require.Len(t, results, 3)
+	assert.Zero(t, progress.increments)
+	require.Len(t, progress.reports, 3)
+	var names []string
+	for i, report := range progress.reports {
+		assert.Equal(t, i+1, report.current)
+		assert.Equal(t, 3, report.total)
+		names = append(names, report.name)
+	}
+	assert.ElementsMatch(t, []string{"react", "vue", "svelte"}, names)
+
+	results = nil
+	assert.NotPanics(t, func() {
+		ProcessGroupedPlansWithProgress(newCtx(), newPlans(), &results, nil, ExecutionCallbacks{DeriveReason: deriveReason})
+	})
+	assert.Len(t, results, 3)
+}
+
+// TestAdvanceProgress tests reporting a finished package outside ProcessGroupedPlansWithProgress.
+//
+// It verifies:
+//   - A nil reporter is ignored
+//   - An untracked reporter is incremented even when it implements PackageProgressReporter
+//   - A tracked reporter learns the position, total and name
+//   - Tracking an already tracked reporter keeps the same counter
+func TestAdvanceProgress(t *testing.T) {
+	assert.NotPanics(t, func() { advanceProgress(nil, "react") })
+	assert.Nil(t, trackProgress(nil, 2))
+
+	plain := &reportingProgress{}
+	advanceProgress(plain, "react")
+	assert.Equal(t, 1, plain.increments)
+	assert.Empty(t, plain.reports)
+
+	rich := &reportingProgress{}
+	tracked := trackProgress(rich, 2)
+	assert.Same(t, tracked, trackProgress(tracked, 5))
+	advanceProgress(tracked, "react")
+	tracked.Increment()
+	assert.Equal(t, []packageReport{{1, 2, "react"}, {2, 2, ""}}, rich.reports)
+
+	counting := &countingProgress{}
+	advanceProgress(trackProgress(counting, 1), "vue")
+	assert.Equal(t, 1, counting.count)
+}
@@ -0,0 +1,134 @@
+package update
+
+import (
+	"path/filepath"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// Plan change kinds reported by DiffPlans.
+const (
+	// PlanChangeAdded marks a package only the new plan updates.
+	PlanChangeAdded = "added"
+	// PlanChangeRemoved marks a package only the old plan updates.
+	PlanChangeRemoved = "removed"
+	// PlanChangeChanged marks a package both plans update to different targets.
+	PlanChangeChanged = "changed"
+)
+
+// PlanChange is a difference between the pending updates of two plans.
+//
+// Fields:
+//   - Package: The package as recorded in the new plan (the old plan for removed packages)
+//   - Change: PlanChangeAdded, PlanChangeRemoved or PlanChangeChanged
+//   - Original: Declared version the update starts from
+//   - OldTarget: Target in the old plan; empty for added packages
+//   - NewTarget: Target in the new plan; empty for removed packages
+type PlanChange struct {
+	Package   formats.Package
+	Change    string
+	Original  string
+	OldTarget string
+	NewTarget string
+}
+
+// PlanDiff is the result of comparing two plan files.
+//
+// Fields:
+//   - Changes: Added and changed packages in new plan order, then removed packages in old plan order
+//   - Unchanged: Number of packages both plans update to the same target
+type PlanDiff struct {
+	Changes   []PlanChange
+	Unchanged int
+}
+
+// DiffPlans compares the pending updates of two plan files.
+//
+// Only entries that would be applied count (see RollbackCandidates); a
+// package the old plan skipped and the new plan updates is added. Packages
+// are matched by PackageKey and their manifest path relative to the plan's
+// working directory, so plans written in different checkouts compare equal.
+//
+// It performs the following operations:
+//   - Step 1: Index the pending entries of the old plan
+//   - Step 2: Walk the new plan, reporting added and retargeted packages
+//   - Step 3: Report old entries the new plan no longer updates as removed
+//
+// Parameters:
+//   - oldPlan: The previously written plan
+//   - newPlan: The regenerated plan
+//
+// Returns:
+//   - *PlanDiff: Changes and the count of unchanged packages
+//
+// Example:
+//
+//	diff := update.DiffPlans(oldPlan, newPlan)
+//	for _, c := range diff.Changes {
+//	    fmt.Println(c.Package.Name, c.Change, c.OldTarget, "→", c.NewTarget)
+//	}
+func DiffPlans(oldPlan, newPlan *PlanFile) *PlanDiff {
+	oldEntries := pendingPlanEntries(oldPlan)
+	oldByKey := make(map[string]PlanEntry, len(oldEntries))
+	for _, entry := range oldEntries {
+		oldByKey[planDiffKey(oldPlan, entry.Package)] = entry
+	}
+
+	diff := &PlanDiff{}
+	seen := make(map[string]bool)
+	for _, entry := range pendingPlanEntries(newPlan) {
+		key := planDiffKey(newPlan, entry.Package)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		old, ok := oldByKey[key]
+		switch {
+		case !ok:
+			diff.Changes = append(diff.Changes, PlanChange{Package: entry.Package, Change: PlanChangeAdded, Original: entry.Original, NewTarget: entry.Target})
+		case !versionsMatch(old.Target, entry.Target):
+			diff.Changes = append(diff.Changes, PlanChange{Package: entry.Package, Change: PlanChangeChanged, Original: entry.Original, OldTarget: old.Target, NewTarget: entry.Target})
+		default:
+			diff.Unchanged++
+		}
+	}
+
+	for _, entry := range oldEntries {
+		key := planDiffKey(oldPlan, entry.Package)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		diff.Changes = append(diff.Changes, PlanChange{Package: entry.Package, Change: PlanChangeRemoved, Original: entry.Original, OldTarget: entry.Target})
+	}
+
+	return diff
+}
+
+// pendingPlanEntries returns the entries of a plan file that would be applied.
+func pendingPlanEntries(f *PlanFile) []PlanEntry {
+	if f == nil {
+		return nil
+	}
+	entries := make([]PlanEntry, 0, len(f.Plans))
+	for _, entry := range f.Plans {
+		res := UpdateResult{Pkg: entry.Package, Target: entry.Target, Status: entry.Status}
+		if ShouldSkipUpdate(&res) || versionsMatch(entry.Original, entry.Target) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// planDiffKey identifies a package across plan files written in different working directories.
+func planDiffKey(f *PlanFile, p formats.Package) string {
+	source := p.Source
+	if f.WorkDir != "" && filepath.IsAbs(source) {
+		if rel, err := filepath.Rel(f.WorkDir, source); err == nil {
+			source = rel
+		}
+	}
+	return PackageKey(p) + "|" + filepath.ToSlash(source)
+}
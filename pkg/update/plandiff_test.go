@@ -0,0 +1,57 @@
+package update
+
+import (
+	"testing"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiffPlans tests comparing the pending updates of two plan files.
+//
+// It verifies:
+//   - New pending packages are added and dropped ones are removed
+//   - A package whose target moved is changed; equal targets count as unchanged
+//   - Entries that would not be applied count as absent
+//   - Packages are matched by manifest path relative to each plan's working directory
+func TestDiffPlans(t *testing.T) {
+	pkg := func(name, source string) formats.Package {
+		return formats.Package{Name: name, Rule: "npm", PackageType: "js", Type: "prod", Source: source}
+	}
+	entry := func(p formats.Package, original, target, status string) PlanEntry {
+		return PlanEntry{Package: p, Original: original, Target: target, Status: status}
+	}
+
+	oldPlan := &PlanFile{Version: PlanFileVersion, WorkDir: "/ci/run-1", Plans: []PlanEntry{
+		entry(pkg("react", "/ci/run-1/package.json"), "17.0.0", "18.2.0", constants.StatusPlanned),
+		entry(pkg("lodash", "/ci/run-1/package.json"), "4.17.20", "4.17.21", constants.StatusPlanned),
+		entry(pkg("vue", "/ci/run-1/package.json"), "3.3.0", "3.4.0", constants.StatusPlanned),
+		entry(pkg("axios", "/ci/run-1/package.json"), "1.6.0", "", constants.StatusUpToDate),
+		entry(pkg("jest", "/ci/run-1/package.json"), "29.0.0", "29.7.0", constants.StatusPlanned),
+	}}
+	newPlan := &PlanFile{Version: PlanFileVersion, WorkDir: "/ci/run-2", Plans: []PlanEntry{
+		entry(pkg("react", "/ci/run-2/package.json"), "17.0.0", "19.0.0", constants.StatusPlanned),
+		entry(pkg("lodash", "/ci/run-2/package.json"), "4.17.20", "v4.17.21", constants.StatusPlanned),
+		entry(pkg("axios", "/ci/run-2/package.json"), "1.6.0", "1.7.2", constants.StatusPlanned),
+		entry(pkg("react", "/ci/run-2/web/package.json"), "18.0.0", "19.0.0", constants.StatusPlanned),
+		entry(pkg("jest", "/ci/run-2/package.json"), "29.0.0", "29.0.0", constants.StatusPlanned),
+	}}
+
+	diff := DiffPlans(oldPlan, newPlan)
+
+	require.Len(t, diff.Changes, 5)
+	assert.Equal(t, 1, diff.Unchanged)
+	assert.Equal(t, PlanChange{Package: newPlan.Plans[0].Package, Change: PlanChangeChanged, Original: "17.0.0", OldTarget: "18.2.0", NewTarget: "19.0.0"}, diff.Changes[0])
+	assert.Equal(t, PlanChange{Package: newPlan.Plans[2].Package, Change: PlanChangeAdded, Original: "1.6.0", NewTarget: "1.7.2"}, diff.Changes[1])
+	assert.Equal(t, "/ci/run-2/web/package.json", diff.Changes[2].Package.Source)
+	assert.Equal(t, PlanChangeAdded, diff.Changes[2].Change)
+	assert.Equal(t, PlanChange{Package: oldPlan.Plans[2].Package, Change: PlanChangeRemoved, Original: "3.3.0", OldTarget: "3.4.0"}, diff.Changes[3])
+	assert.Equal(t, "jest", diff.Changes[4].Package.Name)
+	assert.Equal(t, PlanChangeRemoved, diff.Changes[4].Change)
+
+	identical := DiffPlans(newPlan, newPlan)
+	assert.Empty(t, identical.Changes)
+	assert.Equal(t, 4, identical.Unchanged)
+}
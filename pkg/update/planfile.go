@@ -0,0 +1,283 @@
+package update
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// PlanFileVersion is the current version of the saved plan file format.
+const PlanFileVersion = 1
+
+// PlanFile is the serialized form of an update plan.
+//
+// It is written by `goupdate update --plan-out` and read by
+// `goupdate rollback --plan` to restore packages to their original versions,
+// and by `goupdate update --plan-in` to apply the plan without looking up
+// versions again.
+//
+// Fields:
+//   - Version: Plan file format version (PlanFileVersion)
+//   - WorkDir: Working directory the plan was created for
+//   - Plans: Planned updates in execution order
+type PlanFile struct {
+	Version int         `json:"version"`
+	WorkDir string      `json:"work_dir"`
+	Plans   []PlanEntry `json:"plans"`
+}
+
+// PlanEntry is the serialized form of a single PlannedUpdate.
+//
+// Fields:
+//   - Package: The package as it was before the update
+//   - Original: Declared version before the update (the rollback target)
+//   - Target: Version the update moves the package to
+//   - Status: Plan status at the time the plan was written
+//   - GroupKey: Update group the package belongs to
+//   - Commands: Resolved update commands at the time the plan was written
+//   - Versions: Versions found during planning (used by --staged and summary refresh)
+//   - Incremental: Whether the package was planned in incremental mode
+type PlanEntry struct {
+	Package     formats.Package `json:"package"`
+	Original    string          `json:"original"`
+	Target      string          `json:"target"`
+	Status      string          `json:"status"`
+	GroupKey    string          `json:"group_key,omitempty"`
+	Commands    string          `json:"commands,omitempty"`
+	Versions    []string        `json:"versions,omitempty"`
+	Incremental bool            `json:"incremental,omitempty"`
+}
+
+// NewPlanFile builds a plan file from planned updates.
+//
+// Parameters:
+//   - plans: Planned updates to serialize
+//   - workDir: Working directory the plans were created for
+//
+// Returns:
+//   - *PlanFile: Plan file containing one entry per plan
+func NewPlanFile(plans []*PlannedUpdate, workDir string) *PlanFile {
+	file := &PlanFile{
+		Version: PlanFileVersion,
+		WorkDir: workDir,
+		Plans:   make([]PlanEntry, 0, len(plans)),
+	}
+
+	for _, plan := range plans {
+		entry := PlanEntry{
+			Package:     plan.Res.Pkg,
+			Original:    plan.Original,
+			Target:      plan.Res.Target,
+			Status:      plan.Res.Status,
+			GroupKey:    plan.GroupKey,
+			Versions:    plan.VersionsInConstraint,
+			Incremental: plan.Incremental,
+		}
+		if plan.Cfg != nil {
+			entry.Commands = plan.Cfg.Commands
+		}
+		file.Plans = append(file.Plans, entry)
+	}
+
+	return file
+}
+
+// WritePlanFile writes a plan file as indented JSON.
+//
+// Parameters:
+//   - path: Destination file path
+//   - file: Plan file to write
+//
+// Returns:
+//   - error: When encoding or writing fails
+func WritePlanFile(path string, file *PlanFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan file: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write plan file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// ReadPlanFile reads a plan file written by WritePlanFile.
+//
+// Parameters:
+//   - path: Plan file path
+//
+// Returns:
+//   - *PlanFile: The decoded plan file
+//   - error: When the file cannot be read, parsed, or has an unsupported version
+func ReadPlanFile(path string) (*PlanFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file %s: %w", path, err)
+	}
+
+	var file PlanFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file %s: %w", path, err)
+	}
+
+	if file.Version != PlanFileVersion {
+		return nil, fmt.Errorf("unsupported plan file version %d in %s (expected %d)", file.Version, path, PlanFileVersion)
+	}
+
+	return &file, nil
+}
+
+// RollbackCandidates returns the planned updates that need to be reverted.
+//
+// Entries that were never going to be applied (non-updatable status, no
+// target, or a target equal to the original version) are omitted.
+//
+// Returns:
+//   - []*PlannedUpdate: Plans whose Original is the rollback target
+func (f *PlanFile) RollbackCandidates() []*PlannedUpdate {
+	plans := make([]*PlannedUpdate, 0, len(f.Plans))
+	for _, entry := range f.Plans {
+		res := UpdateResult{Pkg: entry.Package, Target: entry.Target, Status: entry.Status}
+		if ShouldSkipUpdate(&res) || entry.Original == "" || versionsMatch(entry.Original, entry.Target) {
+			continue
+		}
+
+		plans = append(plans, &PlannedUpdate{
+			Res:      res,
+			Original: entry.Original,
+			GroupKey: entry.GroupKey,
+		})
+	}
+	return plans
+}
+
+// PlannedUpdates rebuilds the planned updates of a plan file for execution.
+//
+// The update configuration of every entry is resolved again from cfg, so
+// timeouts and environment come from the current configuration. When the
+// resolved commands differ from the commands recorded in the plan, the plan
+// is rejected as stale instead of running commands nobody reviewed.
+//
+// Parameters:
+//   - cfg: Current configuration
+//   - resolver: Resolves the update configuration of a package (e.g. ResolveUpdateCfg)
+//
+// Returns:
+//   - []*PlannedUpdate: Plans in the order they were written
+//   - error: When a rule no longer exists, its update config cannot be resolved, or its commands changed
+func (f *PlanFile) PlannedUpdates(cfg *config.Config, resolver ConfigResolver) ([]*PlannedUpdate, error) {
+	plans := make([]*PlannedUpdate, 0, len(f.Plans))
+	var errs []error
+
+	for _, entry := range f.Plans {
+		p := entry.Package
+		ruleCfg, ok := cfg.Rules[p.Rule]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s (%s/%s): rule %q is not configured", p.Name, p.PackageType, p.Rule, p.Rule))
+			continue
+		}
+
+		res := UpdateResult{
+			Pkg:               p,
+			Target:            entry.Target,
+			Status:            entry.Status,
+			Group:             p.Group,
+			OriginalInstalled: p.InstalledVersion,
+			OriginalVersion:   entry.Original,
+		}
+		updateCfg, err := resolver(p, cfg)
+		if err != nil && !ShouldSkipUpdate(&res) {
+			errs = append(errs, fmt.Errorf("%s (%s/%s): %w", p.Name, p.PackageType, p.Rule, err))
+			continue
+		}
+		if updateCfg != nil && entry.Commands != "" && updateCfg.Commands != entry.Commands {
+			errs = append(errs, fmt.Errorf("%s (%s/%s): update commands changed since the plan was written", p.Name, p.PackageType, p.Rule))
+			continue
+		}
+
+		var versioning *config.VersioningCfg
+		if ruleCfg.Outdated != nil {
+			versioning = ruleCfg.Outdated.Versioning
+		}
+		plans = append(plans, &PlannedUpdate{
+			Cfg:                  updateCfg,
+			Res:                  res,
+			Original:             entry.Original,
+			GroupKey:             entry.GroupKey,
+			VersionsInConstraint: entry.Versions,
+			Versioning:           versioning,
+			Incremental:          entry.Incremental,
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("plan file is stale: %w", stderrors.Join(errs...))
+	}
+	return plans, nil
+}
+
+// CheckPlanDrift verifies that pending plans still start from the recorded versions.
+//
+// Each pending plan goes through ValidatePreUpdateState against the current
+// packages; a changed Original means the manifest was edited after the plan was
+// written. The installed version is compared with the one recorded in the plan
+// as well, so lock file changes are caught too. Unlike a normal run, where
+// drift only moves the rollback target, drift here is an error so a stale plan
+// never mutates files.
+//
+// Parameters:
+//   - plans: Plans from PlanFile.PlannedUpdates
+//   - current: Freshly loaded packages with installed versions
+//
+// Returns:
+//   - error: Every drifted or missing package; nil when the plan still applies
+//
+// Example:
+//
+//	if err := update.CheckPlanDrift(plans, packages); err != nil {
+//	    return err // e.g. "react (js/npm): declared version changed 17.0.0 → 17.0.2"
+//	}
+func CheckPlanDrift(plans []*PlannedUpdate, current []formats.Package) error {
+	var errs []error
+	for _, plan := range plans {
+		if ShouldSkipUpdate(&plan.Res) {
+			continue
+		}
+		p := plan.Res.Pkg
+		ref := fmt.Sprintf("%s (%s/%s)", p.Name, p.PackageType, p.Rule)
+
+		// Another manifest's copy of the dependency says nothing about this one
+		found := findReloadedPackage(current, p)
+		if found == nil || found.Source != p.Source {
+			errs = append(errs, fmt.Errorf("%s: no longer found", ref))
+			continue
+		}
+
+		if !versionsMatch(found.Version, plan.Original) {
+			errs = append(errs, fmt.Errorf("%s: declared version changed %s → %s", ref, plan.Original, found.Version))
+			continue
+		}
+
+		if installedVersionKnown(p.InstalledVersion) && installedVersionKnown(found.InstalledVersion) &&
+			!versionsMatch(found.InstalledVersion, p.InstalledVersion) {
+			errs = append(errs, fmt.Errorf("%s: installed version changed %s → %s", ref, p.InstalledVersion, found.InstalledVersion))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("packages changed since the plan was written: %w", stderrors.Join(errs...))
+	}
+	return nil
+}
+
+// installedVersionKnown reports whether an installed version was resolved.
+func installedVersionKnown(v string) bool {
+	return v != "" && v != constants.PlaceholderNA
+}
@@ -0,0 +1,177 @@
+package update
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanFileRoundTrip tests writing and reading a plan file.
+//
+// It verifies:
+//   - Package, Original, Target, Status, and GroupKey survive a round trip
+//   - Resolved commands, looked-up versions, and incremental mode are recorded
+//   - The working directory and format version are recorded
+func TestPlanFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	plans := []*PlannedUpdate{
+		{
+			Cfg:                  &config.UpdateCfg{Commands: "npm install"},
+			Res:                  UpdateResult{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Target: "18.0.0", Status: constants.StatusPlanned},
+			Original:             "17.0.0",
+			GroupKey:             "frontend",
+			VersionsInConstraint: []string{"17.0.2", "18.0.0"},
+			Incremental:          true,
+		},
+	}
+
+	err := WritePlanFile(path, NewPlanFile(plans, "/work"))
+	assert.NoError(t, err)
+
+	file, err := ReadPlanFile(path)
+	if !assert.NoError(t, err) || !assert.Len(t, file.Plans, 1) {
+		return
+	}
+	assert.Equal(t, PlanFileVersion, file.Version)
+	assert.Equal(t, "/work", file.WorkDir)
+	assert.Equal(t, "react", file.Plans[0].Package.Name)
+	assert.Equal(t, "npm", file.Plans[0].Package.Rule)
+	assert.Equal(t, "17.0.0", file.Plans[0].Original)
+	assert.Equal(t, "18.0.0", file.Plans[0].Target)
+	assert.Equal(t, constants.StatusPlanned, file.Plans[0].Status)
+	assert.Equal(t, "frontend", file.Plans[0].GroupKey)
+	assert.Equal(t, "npm install", file.Plans[0].Commands)
+	assert.Equal(t, []string{"17.0.2", "18.0.0"}, file.Plans[0].Versions)
+	assert.True(t, file.Plans[0].Incremental)
+}
+
+// TestReadPlanFileErrors tests the error handling of ReadPlanFile.
+//
+// It verifies:
+//   - Missing files return an error
+//   - Invalid JSON returns an error
+//   - Unsupported format versions return an error
+func TestReadPlanFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ReadPlanFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte("{not json"), 0o644))
+	_, err = ReadPlanFile(invalid)
+	assert.ErrorContains(t, err, "failed to parse plan file")
+
+	future := filepath.Join(dir, "future.json")
+	assert.NoError(t, os.WriteFile(future, []byte(`{"version": 99, "plans": []}`), 0o644))
+	_, err = ReadPlanFile(future)
+	assert.ErrorContains(t, err, "unsupported plan file version 99")
+}
+
+// TestPlanFileRollbackCandidates tests the behavior of RollbackCandidates.
+//
+// It verifies:
+//   - Planned entries with a different target are returned
+//   - Up-to-date, non-updatable, and failed entries are omitted
+func TestPlanFileRollbackCandidates(t *testing.T) {
+	file := &PlanFile{
+		Version: PlanFileVersion,
+		Plans: []PlanEntry{
+			{Package: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Original: "17.0.0", Target: "18.0.0", Status: constants.StatusPlanned},
+			{Package: testutil.NPMPackage("lodash", "4.17.21", "4.17.21"), Original: "4.17.21", Target: "4.17.21", Status: constants.StatusUpToDate},
+			{Package: testutil.NPMPackage("vue", "*", "3.0.0"), Original: "*", Target: "3.1.0", Status: lock.InstallStatusFloating},
+			{Package: testutil.NPMPackage("axios", "1.0.0", "1.0.0"), Original: "1.0.0", Status: constants.StatusPlanned},
+		},
+	}
+
+	plans := file.RollbackCandidates()
+
+	if !assert.Len(t, plans, 1) {
+		return
+	}
+	assert.Equal(t, "react", plans[0].Res.Pkg.Name)
+	assert.Equal(t, "17.0.0", plans[0].Original)
+	assert.Equal(t, "18.0.0", plans[0].Res.Target)
+}
+
+// TestPlanFilePlannedUpdates tests rebuilding planned updates from a plan file.
+//
+// It verifies:
+//   - Entries become plans with the current update config, versions, and versioning
+//   - Original versions are kept for rollback and summary display
+//   - Changed update commands reject the plan as stale
+//   - Entries for rules that no longer exist reject the plan
+func TestPlanFilePlannedUpdates(t *testing.T) {
+	versioning := &config.VersioningCfg{Format: "semver"}
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"npm": {Outdated: &config.OutdatedCfg{Versioning: versioning}},
+	}}
+	resolver := func(commands string) ConfigResolver {
+		return func(formats.Package, *config.Config) (*config.UpdateCfg, error) {
+			return &config.UpdateCfg{Commands: commands, TimeoutSeconds: 30}, nil
+		}
+	}
+	file := &PlanFile{
+		Version: PlanFileVersion,
+		Plans: []PlanEntry{
+			{Package: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Original: "17.0.0", Target: "17.0.2", GroupKey: "ui", Commands: "npm install", Versions: []string{"17.0.2"}},
+		},
+	}
+
+	plans, err := file.PlannedUpdates(cfg, resolver("npm install"))
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Equal(t, "17.0.2", plans[0].Res.Target)
+	assert.Equal(t, "17.0.0", plans[0].Original)
+	assert.Equal(t, "17.0.0", plans[0].Res.OriginalVersion)
+	assert.Equal(t, "ui", plans[0].GroupKey)
+	assert.Equal(t, 30, plans[0].Cfg.TimeoutSeconds)
+	assert.Equal(t, []string{"17.0.2"}, plans[0].VersionsInConstraint)
+	assert.Same(t, versioning, plans[0].Versioning)
+
+	_, err = file.PlannedUpdates(cfg, resolver("npm ci"))
+	assert.ErrorContains(t, err, "update commands changed since the plan was written")
+
+	file.Plans[0].Package.Rule = "yarn"
+	_, err = file.PlannedUpdates(cfg, resolver("npm install"))
+	assert.ErrorContains(t, err, `rule "yarn" is not configured`)
+}
+
+// TestCheckPlanDrift tests the stale plan check run before applying a saved plan.
+//
+// It verifies:
+//   - Packages still at the recorded versions pass
+//   - A changed declared version or installed version is reported as drift
+//   - A pending package that is no longer found is reported
+//   - Plans without a pending update are not checked
+//   - The recorded Original version is kept after a failed check
+func TestCheckPlanDrift(t *testing.T) {
+	newPlans := func() []*PlannedUpdate {
+		return []*PlannedUpdate{
+			{Res: UpdateResult{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Target: "17.0.2"}, Original: "17.0.0"},
+			{Res: UpdateResult{Pkg: testutil.NPMPackage("vue", "3.0.0", "3.0.0"), Status: constants.StatusUpToDate}, Original: "3.0.0"},
+		}
+	}
+
+	assert.NoError(t, CheckPlanDrift(newPlans(), []formats.Package{testutil.NPMPackage("react", "17.0.0", "17.0.0")}))
+
+	plans := newPlans()
+	err := CheckPlanDrift(plans, []formats.Package{testutil.NPMPackage("react", "17.0.1", "17.0.1")})
+	assert.ErrorContains(t, err, "react (js/npm): declared version changed 17.0.0 → 17.0.1")
+	assert.Equal(t, "17.0.0", plans[0].Original)
+
+	err = CheckPlanDrift(newPlans(), []formats.Package{testutil.NPMPackage("react", "17.0.0", "17.0.1")})
+	assert.ErrorContains(t, err, "installed version changed 17.0.0 → 17.0.1")
+
+	err = CheckPlanDrift(newPlans(), nil)
+	assert.ErrorContains(t, err, "react (js/npm): no longer found")
+	assert.NotContains(t, err.Error(), "vue")
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/ajxudir/goupdate/pkg/changelog"
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/constants"
 	"github.com/ajxudir/goupdate/pkg/errors"
@@ -31,6 +34,10 @@ type UpdateResult struct {
 	OriginalInstalled string             // Original installed version before update (for summary display)
 	OriginalVersion   string             // Original declared version before update (for summary display)
 	SystemTestResult  *systemtest.Result // System test results for this package (if run)
+	Diff              string             // Unified diff of the manifest edit (set with --show-diff)
+	Changelog         *changelog.Notes   // Release notes between the current and target version (set with --changelog)
+	Duration          time.Duration      // Time spent applying the update (manifest edit and lock command)
+	PlanDuration      time.Duration      // Time spent looking up versions during planning
 }
 
 // PlannedUpdate holds the plan for updating a single package.
@@ -59,6 +66,42 @@ type PlanningOptions struct {
 	// Used for progress feedback during the planning phase
 	// The PlannedUpdate contains the result with Major/Minor/Patch info
 	OnPackageChecked func(plan *PlannedUpdate, current, total int)
+	// Concurrency is the maximum number of version lookups running at once.
+	// Values of 1 or less look up versions serially.
+	Concurrency int
+	// OnVersionsResolved is called as each package's version lookup finishes,
+	// in completion order rather than plan order, so it can drive a live count
+	// during discovery. Calls are serialized; resolved counts finished lookups
+	// and total counts packages that need one. Optional.
+	OnVersionsResolved func(p formats.Package, versions []string, err error, resolved, total int)
+	// PinFloating resolves NuGet floating versions ("13.0.*") to the highest
+	// matching release and rewrites the manifest to it. Without it they are
+	// reported as unsupported.
+	PinFloating bool
+	// UpdateTo is the exact target set by --update-to. Candidate selection
+	// (constraint, scope, and incremental steps) is skipped and every looked-up
+	// package targets this version; pair it with ExactVersionLister.
+	UpdateTo string
+	// Selectors choose the target of packages whose rule sets a selection_strategy
+	// other than highest. Strategies without a selector use the highest target.
+	Selectors outdated.TargetSelectors
+}
+
+// NeedsVersionLookup reports whether BuildGroupedPlans looks up versions for a
+// resolved plan with these options.
+//
+// Parameters:
+//   - plan: The resolved plan to check
+//
+// Returns:
+//   - bool: True for plans accepted by the package-level NeedsVersionLookup, and
+//     for pinnable floating versions when PinFloating is set
+func (o PlanningOptions) NeedsVersionLookup(plan ResolvedUpdatePlan) bool {
+	if NeedsVersionLookup(plan) {
+		return true
+	}
+	return o.PinFloating && plan.Err == nil && plan.Pkg.InstallStatus != lock.InstallStatusIgnored &&
+		plan.Pkg.InstallStatus != lock.InstallStatusNonRegistry && CanPinFloating(plan.Pkg)
 }
 
 // VersionLister is a function type for listing newer versions of a package.
@@ -154,6 +197,13 @@ func BuildGroupedPlans(
 	var groupedPlans []*PlannedUpdate
 	total := len(resolved)
 
+	// Fan out version lookups up front; results are consumed in plan order below
+	pkgs := ExtractPackagesFromPlans(resolved)
+	lister := reportResolvedVersions(outdated.ListNewerVersionsFunc(listVersions), resolved, opts)
+	lookups := outdated.StartVersionLookups(ctx, pkgs, updateCtx.Cfg, updateCtx.WorkDir, opts.Concurrency, lister, func(i int) bool {
+		return opts.NeedsVersionLookup(resolved[i])
+	})
+
 	for i, plan := range resolved {
 		// Check for context cancellation to allow early termination
 		if ctx.Err() != nil {
@@ -190,9 +240,28 @@ func BuildGroupedPlans(
 			continue
 		}
 
-		// Handle floating constraints
+		// Handle packages installed from git, path, or other non-registry sources
+		if p.InstallStatus == lock.InstallStatusNonRegistry {
+			planned := handleNonRegistrySource(p, updateCfg, updateCtx, originalVersion)
+			groupedPlans = append(groupedPlans, planned)
+			if opts.OnPackageChecked != nil {
+				opts.OnPackageChecked(planned, i+1, total)
+			}
+			continue
+		}
+
+		// Handle floating constraints, resolving NuGet wildcards when pinning is enabled
 		if IsFloatingConstraint(p) {
-			planned := handleFloatingConstraint(p, updateCfg, updateCtx, originalVersion)
+			var planned *PlannedUpdate
+			if opts.PinFloating && CanPinFloating(p) {
+				lookupIndex := i
+				planned = planFloatingPin(ctx, p, res, updateCfg, updateCtx, originalVersion, func(context.Context, formats.Package, *config.Config, string) ([]string, error) {
+					return lookups.Get(lookupIndex)
+				}, deriveReason)
+				planned.Res.PlanDuration = lookups.Duration(lookupIndex)
+			} else {
+				planned = handleFloatingConstraint(p, updateCfg, updateCtx, originalVersion)
+			}
 			groupedPlans = append(groupedPlans, planned)
 			if opts.OnPackageChecked != nil {
 				opts.OnPackageChecked(planned, i+1, total)
@@ -212,7 +281,12 @@ func BuildGroupedPlans(
 		}
 
 		// Get available versions and plan update
-		planned := planVersionUpdate(ctx, p, res, updateCfg, updateCtx, originalVersion, opts, listVersions, deriveReason)
+		lookupIndex := i
+		cachedLister := func(context.Context, formats.Package, *config.Config, string) ([]string, error) {
+			return lookups.Get(lookupIndex)
+		}
+		planned := planVersionUpdate(ctx, p, res, updateCfg, updateCtx, originalVersion, opts, cachedLister, deriveReason)
+		planned.Res.PlanDuration = lookups.Duration(lookupIndex)
 		groupedPlans = append(groupedPlans, planned)
 
 		// Call progress callback after package is checked
@@ -224,6 +298,63 @@ func BuildGroupedPlans(
 	return groupedPlans
 }
 
+// reportResolvedVersions wraps a version lister so onResolved fires after each lookup.
+//
+// Lookups may finish on several workers at once, so the callback is serialized
+// and receives a running count of finished lookups.
+//
+// Parameters:
+//   - lister: The version lister to wrap
+//   - resolved: Resolved plans, used to count the packages that need a lookup
+//   - opts: Planning options; a nil OnVersionsResolved returns lister unchanged
+//
+// Returns:
+//   - outdated.ListNewerVersionsFunc: A lister with the same results that reports each lookup
+func reportResolvedVersions(lister outdated.ListNewerVersionsFunc, resolved []ResolvedUpdatePlan, opts PlanningOptions) outdated.ListNewerVersionsFunc {
+	onResolved := opts.OnVersionsResolved
+	if onResolved == nil {
+		return lister
+	}
+
+	total := 0
+	for _, plan := range resolved {
+		if opts.NeedsVersionLookup(plan) {
+			total++
+		}
+	}
+
+	var mu sync.Mutex
+	count := 0
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		versions, err := lister(ctx, p, cfg, baseDir)
+
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		onResolved(p, versions, err, count, total)
+		return versions, err
+	}
+}
+
+// NeedsVersionLookup reports whether a resolved plan requires a version lookup.
+//
+// Ignored packages, packages with configuration errors, non-registry sources,
+// floating constraints, and fully pinned exact constraints are planned without
+// querying versions.
+//
+// Parameters:
+//   - plan: The resolved plan to check
+//
+// Returns:
+//   - bool: True if BuildGroupedPlans will list newer versions for the package
+func NeedsVersionLookup(plan ResolvedUpdatePlan) bool {
+	p := plan.Pkg
+	if p.InstallStatus == lock.InstallStatusIgnored || p.InstallStatus == lock.InstallStatusNonRegistry || plan.Err != nil || IsFloatingConstraint(p) {
+		return false
+	}
+	return !(outdated.IsExactConstraint(p.Constraint) && outdated.IsFullyPinnedVersion(p.Version))
+}
+
 // handleConfigError handles packages with configuration errors during planning.
 //
 // It performs the following operations:
@@ -292,7 +423,43 @@ func handleFloatingConstraint(p formats.Package, updateCfg *config.UpdateCfg, up
 		OriginalVersion:   originalVersion,
 	}
 	if updateCtx.Unsupported != nil {
-		updateCtx.Unsupported.Add(p, fmt.Sprintf("floating constraint '%s' cannot be updated automatically; remove the constraint or update manually", p.Version))
+		reason := fmt.Sprintf("floating constraint '%s' cannot be updated automatically; remove the constraint or update manually", p.Version)
+		if CanPinFloating(p) {
+			reason = fmt.Sprintf("floating constraint '%s' is not pinned; use --pin-floating to pin the highest matching release, or update manually", p.Version)
+		}
+		updateCtx.Unsupported.Add(p, reason)
+	}
+	return &PlannedUpdate{Cfg: updateCfg, Res: res, Original: originalVersion, GroupKey: groupKey}
+}
+
+// handleNonRegistrySource handles packages installed from non-registry sources during planning.
+//
+// It performs the following operations:
+//   - Step 1: Normalize update group for display
+//   - Step 2: Create an UpdateResult with non-registry status
+//   - Step 3: Track as unsupported since there are no registry versions to update to
+//   - Step 4: Return a PlannedUpdate with non-registry status
+//
+// Parameters:
+//   - p: The package with a non-registry source (e.g., a gem with git: or path:)
+//   - updateCfg: Update configuration for the package
+//   - updateCtx: Update context for tracking unsupported packages
+//   - originalVersion: Original version of the package for rollback
+//
+// Returns:
+//   - *PlannedUpdate: Planned update with non-registry status and explanation message
+func handleNonRegistrySource(p formats.Package, updateCfg *config.UpdateCfg, updateCtx *UpdateContext, originalVersion string) *PlannedUpdate {
+	groupDisplay := NormalizeUpdateGroup(updateCfg, p)
+	groupKey := UpdateGroupKey(updateCfg, p)
+	res := UpdateResult{
+		Pkg:               p,
+		Status:            lock.InstallStatusNonRegistry,
+		Group:             groupDisplay,
+		OriginalInstalled: p.InstalledVersion,
+		OriginalVersion:   originalVersion,
+	}
+	if updateCtx.Unsupported != nil {
+		updateCtx.Unsupported.Add(p, "non-registry source (git, path, etc.) has no registry versions; update the source manually")
 	}
 	return &PlannedUpdate{Cfg: updateCfg, Res: res, Original: originalVersion, GroupKey: groupKey}
 }
@@ -399,18 +566,28 @@ func planVersionUpdate(
 		return &PlannedUpdate{Cfg: updateCfg, Res: res, Original: originalVersion, GroupKey: groupKey}
 	}
 
+	// Flag packages outside their pin so the policy breach is visible
+	if reason, violated := outdated.PinViolation(p, cfg); violated && updateCtx.Unsupported != nil {
+		updateCtx.Unsupported.Add(p, reason)
+	}
+
 	ruleCfg := cfg.Rules[p.Rule]
 	var versioning *config.VersioningCfg
 	if ruleCfg.Outdated != nil {
 		versioning = ruleCfg.Outdated.Versioning
 	}
 
+	// Pre-releases are only candidates when allowed; prefer stable releases among them
+	summarizeOpts := outdated.SummarizeOptions{
+		PreferStable: config.ShouldAllowPrerelease(p, cfg) || (updateCfg != nil && updateCfg.AllowPrerelease),
+	}
+
 	// Get ALL available versions (including major) without constraint filtering
 	// This ensures users see major updates even when their package uses ^ or ~ constraints
 	allAvailable := outdated.FilterVersionsByConstraint(p, versions, outdated.UpdateSelectionFlags{Major: true})
 
 	// Summarize all available versions (for display - shows what updates exist)
-	major, minor, patch, summarizeErr := outdated.SummarizeAvailableVersions(outdated.CurrentVersionForOutdated(p), allAvailable, versioning, incremental)
+	major, minor, patch, summarizeErr := outdated.SummarizeAvailableVersionsWith(outdated.CurrentVersionForOutdated(p), allAvailable, versioning, incremental, summarizeOpts)
 	if summarizeErr != nil {
 		res.Status = constants.StatusSummarizeError
 		res.Err = summarizeErr
@@ -426,8 +603,23 @@ func planVersionUpdate(
 	// Summarize FILTERED versions to get target based on selection scope.
 	// Error is intentionally ignored - if version selection fails, target will be empty
 	// and the package will be shown as up-to-date (no update available for the filtered scope).
-	filteredMajor, filteredMinor, filteredPatch, _ := outdated.SummarizeAvailableVersions(outdated.CurrentVersionForOutdated(p), filtered, versioning, incremental)
+	filteredMajor, filteredMinor, filteredPatch, _ := outdated.SummarizeAvailableVersionsWith(outdated.CurrentVersionForOutdated(p), filtered, versioning, incremental, summarizeOpts)
 	target, _ := outdated.SelectTargetVersion(filteredMajor, filteredMinor, filteredPatch, selection, p.Constraint, incremental)
+	if opts.UpdateTo == "" {
+		// The rule's selection strategy chooses among the same in-scope versions
+		selected, selectErr := opts.Selectors.Select(ctx, p, filtered, selection, summarizeOpts.PreferStable, target, cfg, updateCtx.WorkDir)
+		if selectErr != nil {
+			res.Status = constants.StatusFailed
+			res.Err = selectErr
+			updateCtx.AppendFailure(fmt.Errorf("%s (%s/%s): %w", p.Name, p.PackageType, p.Rule, selectErr))
+			return &PlannedUpdate{Cfg: updateCfg, Res: res, Original: originalVersion, GroupKey: groupKey}
+		}
+		target = selected
+	}
+	if opts.UpdateTo != "" && len(versions) > 0 {
+		// --update-to names the target; the lister has already verified it
+		target = versions[0]
+	}
 	res.Target = target
 
 	if target != "" {
@@ -466,10 +658,12 @@ func CountPendingUpdates(plans []*PlannedUpdate) int {
 func IsNonUpdatableStatus(status string) bool {
 	return status == lock.InstallStatusNotConfigured ||
 		status == lock.InstallStatusFloating ||
+		status == lock.InstallStatusNonRegistry ||
 		status == lock.InstallStatusIgnored ||
 		status == constants.StatusConfigError ||
 		status == constants.StatusFailed ||
-		status == constants.StatusSummarizeError
+		status == constants.StatusSummarizeError ||
+		status == constants.StatusDeferred
 }
 
 // ShouldSkipUpdate returns true if the update result status indicates the update should be skipped.
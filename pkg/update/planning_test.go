@@ -3,6 +3,8 @@ package update
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/ajxudir/goupdate/pkg/config"
@@ -10,6 +12,7 @@ import (
 	pkgerrors "github.com/ajxudir/goupdate/pkg/errors"
 	"github.com/ajxudir/goupdate/pkg/formats"
 	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/outdated"
 	"github.com/ajxudir/goupdate/pkg/testutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -159,6 +162,7 @@ func TestIsNonUpdatableStatus(t *testing.T) {
 	}{
 		{"not configured", lock.InstallStatusNotConfigured, true},
 		{"floating", lock.InstallStatusFloating, true},
+		{"non-registry", lock.InstallStatusNonRegistry, true},
 		{"config error", constants.StatusConfigError, true},
 		{"failed", constants.StatusFailed, true},
 		{"summarize error", constants.StatusSummarizeError, true},
@@ -322,6 +326,109 @@ func TestBuildGroupedPlans(t *testing.T) {
 		assert.Equal(t, "react", plans[0].Res.Pkg.Name)
 	})
 
+	t.Run("concurrent lookups preserve order and skip non-lookup packages", func(t *testing.T) {
+		cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+		updateCtx := NewUpdateContext(cfg, "/test", &mockUnsupportedTracker{})
+		floating := formats.Package{Name: "floating", Rule: "npm", Version: "*"}
+		resolved := []ResolvedUpdatePlan{
+			{Pkg: testutil.NPMPackage("alpha", "1.0.0", "1.0.0"), Cfg: &config.UpdateCfg{Commands: "npm install"}},
+			{Pkg: floating, Cfg: &config.UpdateCfg{Commands: "npm install"}},
+			{Pkg: testutil.NPMPackage("beta", "1.0.0", "1.0.0"), Cfg: &config.UpdateCfg{Commands: "npm install"}},
+			{Pkg: testutil.NPMPackage("gamma", "1.0.0", "1.0.0"), Cfg: &config.UpdateCfg{Commands: "npm install"}},
+		}
+
+		var mu sync.Mutex
+		looked := map[string]int{}
+		lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			mu.Lock()
+			looked[p.Name]++
+			mu.Unlock()
+			if p.Name == "beta" {
+				return nil, errors.New("lookup failed")
+			}
+			return []string{"1.1.0"}, nil
+		}
+
+		var checked []string
+		opts := PlanningOptions{
+			Concurrency: 4,
+			OnPackageChecked: func(plan *PlannedUpdate, current, total int) {
+				checked = append(checked, plan.Res.Pkg.Name)
+			},
+		}
+
+		plans := BuildGroupedPlans(context.Background(), resolved, updateCtx, opts, lister, mockDeriveReason)
+
+		assert.Len(t, plans, 4)
+		assert.Equal(t, []string{"alpha", "floating", "beta", "gamma"}, checked)
+		assert.Equal(t, map[string]int{"alpha": 1, "beta": 1, "gamma": 1}, looked)
+		assert.Equal(t, constants.StatusFailed, plans[2].Res.Status)
+		assert.Len(t, updateCtx.Failures, 1)
+	})
+
+	t.Run("reports resolved versions in completion order", func(t *testing.T) {
+		cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+		updateCtx := NewUpdateContext(cfg, "/test", &mockUnsupportedTracker{})
+		resolved := []ResolvedUpdatePlan{
+			{Pkg: testutil.NPMPackage("slow", "1.0.0", "1.0.0"), Cfg: &config.UpdateCfg{Commands: "npm install"}},
+			{Pkg: formats.Package{Name: "floating", Rule: "npm", Version: "*"}, Cfg: &config.UpdateCfg{Commands: "npm install"}},
+			{Pkg: testutil.NPMPackage("fast", "1.0.0", "1.0.0"), Cfg: &config.UpdateCfg{Commands: "npm install"}},
+		}
+
+		fastDone := make(chan struct{})
+		lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+			if p.Name == "slow" {
+				<-fastDone
+			}
+			return []string{"1.1.0"}, nil
+		}
+
+		var order []string
+		var counts []int
+		opts := PlanningOptions{
+			Concurrency: 2,
+			OnVersionsResolved: func(p formats.Package, versions []string, err error, resolved, total int) {
+				order = append(order, p.Name)
+				counts = append(counts, resolved)
+				assert.Equal(t, 2, total)
+				assert.Equal(t, []string{"1.1.0"}, versions)
+				assert.NoError(t, err)
+				if p.Name == "fast" {
+					close(fastDone)
+				}
+			},
+		}
+
+		plans := BuildGroupedPlans(context.Background(), resolved, updateCtx, opts, lister, mockDeriveReason)
+
+		assert.Len(t, plans, 3)
+		assert.Equal(t, []string{"fast", "slow"}, order)
+		assert.Equal(t, []int{1, 2}, counts)
+	})
+
+	t.Run("reports resolved versions in serial mode", func(t *testing.T) {
+		cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+		updateCtx := NewUpdateContext(cfg, "/test", nil)
+		resolved := []ResolvedUpdatePlan{
+			{Pkg: testutil.NPMPackage("react", "1.0.0", "1.0.0"), Cfg: &config.UpdateCfg{Commands: "npm install"}},
+			{Pkg: testutil.NPMPackage("vue", "1.0.0", "1.0.0"), Cfg: &config.UpdateCfg{Commands: "npm install"}},
+		}
+
+		var events []string
+		opts := PlanningOptions{
+			OnVersionsResolved: func(p formats.Package, versions []string, err error, resolved, total int) {
+				events = append(events, fmt.Sprintf("resolved %s %d/%d", p.Name, resolved, total))
+			},
+			OnPackageChecked: func(plan *PlannedUpdate, current, total int) {
+				events = append(events, "checked "+plan.Res.Pkg.Name)
+			},
+		}
+
+		BuildGroupedPlans(context.Background(), resolved, updateCtx, opts, mockVersionLister, mockDeriveReason)
+
+		assert.Equal(t, []string{"resolved react 1/2", "checked react", "resolved vue 2/2", "checked vue"}, events)
+	})
+
 	t.Run("handles config errors as unsupported", func(t *testing.T) {
 		cfg := testutil.NewConfig().Build()
 		tracker := &mockUnsupportedTracker{}
@@ -454,6 +561,18 @@ func TestBuildGroupedPlans(t *testing.T) {
 	})
 }
 
+func TestNeedsVersionLookup(t *testing.T) {
+	pinned := testutil.NewPackage("react").WithRule("npm").WithVersion("1.0.0").WithConstraint("=").Build()
+	ignored := testutil.NPMPackage("react", "1.0.0", "1.0.0")
+	ignored.InstallStatus = lock.InstallStatusIgnored
+
+	assert.True(t, NeedsVersionLookup(ResolvedUpdatePlan{Pkg: testutil.NPMPackage("react", "1.0.0", "1.0.0")}))
+	assert.False(t, NeedsVersionLookup(ResolvedUpdatePlan{Pkg: ignored}))
+	assert.False(t, NeedsVersionLookup(ResolvedUpdatePlan{Pkg: testutil.NPMPackage("react", "1.0.0", "1.0.0"), Err: errors.New("config error")}))
+	assert.False(t, NeedsVersionLookup(ResolvedUpdatePlan{Pkg: formats.Package{Name: "react", Version: "*"}}))
+	assert.False(t, NeedsVersionLookup(ResolvedUpdatePlan{Pkg: pinned}))
+}
+
 func TestHandleConfigErrorInternal(t *testing.T) {
 	mockDeriveReason := func(p formats.Package, cfg *config.Config, err error, latestMissing bool) string {
 		return "test reason"
@@ -510,6 +629,31 @@ func TestHandleFloatingConstraintInternal(t *testing.T) {
 	})
 }
 
+func TestHandleNonRegistrySourceInternal(t *testing.T) {
+	t.Run("marks package as non-registry", func(t *testing.T) {
+		pkg := formats.Package{Name: "internal_auth", Rule: "bundler", Version: "*", InstallStatus: lock.InstallStatusNonRegistry, NonRegistrySource: "git"}
+		tracker := &mockUnsupportedTracker{}
+		updateCtx := NewUpdateContext(testutil.NewConfig().Build(), "/test", tracker)
+		updateCfg := &config.UpdateCfg{Commands: "bundle update {{package}} --conservative"}
+
+		result := handleNonRegistrySource(pkg, updateCfg, updateCtx, "*")
+
+		assert.Equal(t, lock.InstallStatusNonRegistry, result.Res.Status)
+		assert.Empty(t, result.Res.Target)
+		assert.Len(t, tracker.packages, 1)
+		assert.False(t, NeedsVersionLookup(ResolvedUpdatePlan{Pkg: pkg, Cfg: updateCfg}))
+	})
+
+	t.Run("handles nil tracker", func(t *testing.T) {
+		pkg := formats.Package{Name: "billing_engine", Rule: "bundler", InstallStatus: lock.InstallStatusNonRegistry, NonRegistrySource: "path"}
+		updateCtx := NewUpdateContext(testutil.NewConfig().Build(), "/test", nil)
+
+		result := handleNonRegistrySource(pkg, nil, updateCtx, "")
+
+		assert.Equal(t, lock.InstallStatusNonRegistry, result.Res.Status)
+	})
+}
+
 func TestHandleExactConstraintInternal(t *testing.T) {
 	t.Run("returns up to date with target as current version", func(t *testing.T) {
 		pkg := testutil.NewPackage("react").WithRule("npm").WithVersion("1.0.0").WithConstraint("").Build()
@@ -638,3 +782,50 @@ func TestHandleIgnoredPackage(t *testing.T) {
 		assert.Equal(t, "", result.Original)
 	})
 }
+
+// TestBuildGroupedPlansSelectionStrategy tests planning with a rule's selection_strategy.
+//
+// It verifies:
+//   - The strategy's selector picks the target among candidates inside the --minor scope
+//   - Rules without a strategy keep the highest target
+//   - A selector error fails the package
+func TestBuildGroupedPlansSelectionStrategy(t *testing.T) {
+	lowest := testutil.NPMRule()
+	lowest.SelectionStrategy = config.SelectionStrategyLowestNonVulnerable
+	cfg := testutil.NewConfig().WithRule("npm", lowest).WithRule("yarn", testutil.NPMRule()).Build()
+	lister := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"17.0.1", "17.0.2", "17.1.0", "18.2.0"}, nil
+	}
+	var candidates []string
+	selectors := outdated.TargetSelectors{
+		config.SelectionStrategyLowestNonVulnerable: outdated.TargetSelectorFunc(func(ctx context.Context, p formats.Package, c []string, cfg *config.Config, baseDir string) (string, error) {
+			if p.Name == "broken" {
+				return "", errors.New("advisory lookup failed")
+			}
+			candidates = c
+			return c[0], nil
+		}),
+	}
+	yarnReact := testutil.NPMPackage("react", "17.0.0", "17.0.0")
+	yarnReact.Rule = "yarn"
+	broken := testutil.NPMPackage("broken", "17.0.0", "17.0.0")
+	resolved := []ResolvedUpdatePlan{
+		{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Cfg: &config.UpdateCfg{Commands: "npm install"}},
+		{Pkg: yarnReact, Cfg: &config.UpdateCfg{Commands: "yarn install"}},
+		{Pkg: broken, Cfg: &config.UpdateCfg{Commands: "npm install"}},
+	}
+	reason := func(formats.Package, *config.Config, error, bool) string { return "" }
+
+	ctx := NewUpdateContext(cfg, ".", nil).WithSelection(outdated.UpdateSelectionFlags{Minor: true})
+	plans := BuildGroupedPlans(context.Background(), resolved, ctx, PlanningOptions{Selectors: selectors}, lister, reason)
+
+	targets := make(map[string]*PlannedUpdate, len(plans))
+	for _, plan := range plans {
+		targets[plan.Res.Pkg.Rule+"/"+plan.Res.Pkg.Name] = plan
+	}
+	assert.Equal(t, "17.0.1", targets["npm/react"].Res.Target)
+	assert.Equal(t, []string{"17.0.1", "17.0.2", "17.1.0"}, candidates)
+	assert.Equal(t, "17.1.0", targets["yarn/react"].Res.Target)
+	assert.Equal(t, constants.StatusFailed, targets["npm/broken"].Res.Status)
+	assert.ErrorContains(t, targets["npm/broken"].Res.Err, "lowest-non-vulnerable selection failed: advisory lookup failed")
+}
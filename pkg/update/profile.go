@@ -0,0 +1,302 @@
+package update
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/output"
+	"github.com/ajxudir/goupdate/pkg/systemtest"
+)
+
+// Phases of an update run timed by --profile.
+const (
+	PhaseConfigLoad       = "config load"
+	PhasePackageDiscovery = "package discovery"
+	PhaseVersionLookup    = "version lookup"
+	PhaseExecution        = "execution"
+	PhaseSystemTests      = "system tests"
+)
+
+// Profile records where the time of an update run goes (--profile).
+//
+// Phases run one after another: Enter ends the current phase and starts the
+// next, so a run that returns early still has its last phase closed by
+// Report. A nil *Profile is valid and records nothing, so callers mark phases
+// unconditionally and pay nothing when profiling is off.
+//
+// Fields:
+//   - startedAt: When the run started
+//   - phases: Accumulated phase durations in the order the phases first ran
+//   - current: The running phase; empty between phases
+//   - enteredAt: When the running phase started
+//   - results: Update results the per-rule timings are derived from
+type Profile struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	phases    []ProfilePhase
+	current   string
+	enteredAt time.Time
+	results   []UpdateResult
+}
+
+// ProfileReport is the timing breakdown of a run.
+//
+// Fields:
+//   - TotalMs: Wall time from the start of the run to the report
+//   - Phases: Wall time of each phase, in run order
+//   - Rules: Per-rule timings, slowest first
+type ProfileReport struct {
+	TotalMs int64          `json:"total_ms"`
+	Phases  []ProfilePhase `json:"phases"`
+	Rules   []RuleProfile  `json:"rules"`
+}
+
+// ProfilePhase is the time spent in one phase of a run.
+//
+// Fields:
+//   - Name: Phase name (one of the Phase constants)
+//   - DurationMs: Time spent in the phase in milliseconds
+type ProfilePhase struct {
+	Name       string `json:"name"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// RuleProfile is the time spent on the packages of one rule.
+//
+// Lookup and execution times are summed per package. Lookups run
+// concurrently, so LookupMs can exceed the version lookup phase.
+//
+// Fields:
+//   - Rule: Rule name
+//   - Packages: Number of packages of the rule in the run
+//   - LookupMs: Time spent looking up versions (UpdateResult.PlanDuration)
+//   - ExecutionMs: Time spent applying updates (UpdateResult.Duration)
+//   - SystemTestMs: Time spent in after_each system tests of the rule's updates
+type RuleProfile struct {
+	Rule         string `json:"rule"`
+	Packages     int    `json:"packages"`
+	LookupMs     int64  `json:"lookup_ms"`
+	ExecutionMs  int64  `json:"execution_ms"`
+	SystemTestMs int64  `json:"system_test_ms"`
+}
+
+// NewProfile starts profiling a run.
+//
+// Parameters:
+//   - startedAt: When the run started
+//
+// Returns:
+//   - *Profile: An empty profile
+func NewProfile(startedAt time.Time) *Profile {
+	return &Profile{startedAt: startedAt}
+}
+
+// Enter ends the running phase and starts timing the next one.
+//
+// Parameters:
+//   - phase: Phase name; time spent in a phase more than once is added up
+//
+// Example:
+//
+//	profile.Enter(update.PhaseConfigLoad)
+//	cfg, err := loadConfig()
+//	profile.Enter(update.PhasePackageDiscovery)
+func (p *Profile) Enter(phase string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.leaveLocked(time.Now())
+	p.current, p.enteredAt = phase, time.Now()
+}
+
+// Leave ends the running phase without starting another, for example while
+// waiting for the user to confirm.
+func (p *Profile) Leave() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.leaveLocked(time.Now())
+}
+
+// leaveLocked records the running phase up to now; p.mu must be held.
+//
+// Parameters:
+//   - now: End of the running phase
+func (p *Profile) leaveLocked(now time.Time) {
+	if p.current != "" {
+		p.addLocked(p.current, now.Sub(p.enteredAt))
+		p.current = ""
+	}
+}
+
+// Add records time spent in a phase outside Enter and Leave.
+//
+// Parameters:
+//   - phase: Phase name
+//   - d: Time spent
+func (p *Profile) Add(phase string, d time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.addLocked(phase, d)
+}
+
+// addLocked adds time to a phase; p.mu must be held.
+//
+// Parameters:
+//   - phase: Phase name
+//   - d: Time spent
+func (p *Profile) addLocked(phase string, d time.Duration) {
+	for i := range p.phases {
+		if p.phases[i].Name == phase {
+			p.phases[i].DurationMs += d.Milliseconds()
+			return
+		}
+	}
+	p.phases = append(p.phases, ProfilePhase{Name: phase, DurationMs: d.Milliseconds()})
+}
+
+// SetResults records the update results the per-rule timings are derived from.
+//
+// The after_each system tests of the results are added to the system tests
+// phase; they ran during execution, so that time is counted in both.
+//
+// Parameters:
+//   - results: Results of the run
+func (p *Profile) SetResults(results []UpdateResult) {
+	if p == nil {
+		return
+	}
+	var afterEach time.Duration
+	for _, tests := range distinctSystemTestResults(results) {
+		afterEach += tests.TotalDuration
+	}
+	if afterEach > 0 {
+		p.Add(PhaseSystemTests, afterEach)
+	}
+	p.mu.Lock()
+	p.results = results
+	p.mu.Unlock()
+}
+
+// Report ends the running phase and builds the timing breakdown.
+//
+// It performs the following operations:
+//   - Step 1: Close the running phase and take the total from the start of the run to finishedAt
+//   - Step 2: Copy the recorded phases in run order
+//   - Step 3: Sum lookup, execution, and system test time per rule, slowest rule first
+//
+// Parameters:
+//   - finishedAt: End of the profiled span
+//
+// Returns:
+//   - *ProfileReport: The breakdown; nil for a nil profile
+func (p *Profile) Report(finishedAt time.Time) *ProfileReport {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.leaveLocked(finishedAt)
+
+	report := &ProfileReport{
+		TotalMs: finishedAt.Sub(p.startedAt).Milliseconds(),
+		Phases:  append([]ProfilePhase{}, p.phases...),
+		Rules:   []RuleProfile{},
+	}
+
+	byRule := make(map[string]*RuleProfile)
+	var order []string
+	counted := make(map[*systemtest.Result]bool)
+	for _, res := range p.results {
+		rule := res.Pkg.Rule
+		entry, ok := byRule[rule]
+		if !ok {
+			entry = &RuleProfile{Rule: rule}
+			byRule[rule] = entry
+			order = append(order, rule)
+		}
+		entry.Packages++
+		entry.LookupMs += res.PlanDuration.Milliseconds()
+		entry.ExecutionMs += res.Duration.Milliseconds()
+		if res.SystemTestResult != nil && !counted[res.SystemTestResult] {
+			counted[res.SystemTestResult] = true
+			entry.SystemTestMs += res.SystemTestResult.TotalDuration.Milliseconds()
+		}
+	}
+	for _, rule := range order {
+		report.Rules = append(report.Rules, *byRule[rule])
+	}
+	sort.SliceStable(report.Rules, func(i, j int) bool {
+		a, b := report.Rules[i], report.Rules[j]
+		return a.LookupMs+a.ExecutionMs+a.SystemTestMs > b.LookupMs+b.ExecutionMs+b.SystemTestMs
+	})
+	return report
+}
+
+// distinctSystemTestResults returns the system test results of a run once each.
+//
+// Packages updated as a group share one result.
+//
+// Parameters:
+//   - results: Results of the run
+//
+// Returns:
+//   - []*systemtest.Result: Each distinct result in result order
+func distinctSystemTestResults(results []UpdateResult) []*systemtest.Result {
+	seen := make(map[*systemtest.Result]bool)
+	var distinct []*systemtest.Result
+	for _, res := range results {
+		if res.SystemTestResult != nil && !seen[res.SystemTestResult] {
+			seen[res.SystemTestResult] = true
+			distinct = append(distinct, res.SystemTestResult)
+		}
+	}
+	return distinct
+}
+
+// PrintProfile prints the timing breakdown of a run.
+//
+// Parameters:
+//   - w: Destination writer
+//   - report: The breakdown built by Profile.Report; nil prints nothing
+func PrintProfile(w io.Writer, report *ProfileReport) {
+	if report == nil {
+		return
+	}
+	ms := func(v int64) string { return FormatTestDuration(time.Duration(v) * time.Millisecond) }
+
+	_, _ = fmt.Fprintf(w, "\nProfile (total %s):\n", ms(report.TotalMs))
+	phases := output.NewTable().AddColumn("PHASE").AddColumn("TIME")
+	for _, phase := range report.Phases {
+		phases.UpdateWidths(phase.Name, ms(phase.DurationMs))
+	}
+	phases.Fprint(w)
+	for _, phase := range report.Phases {
+		_, _ = fmt.Fprintln(w, phases.FormatRow(phase.Name, ms(phase.DurationMs)))
+	}
+
+	if len(report.Rules) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	rules := output.NewTable().AddColumn("RULE").AddColumn("PACKAGES").AddColumn("LOOKUP").AddColumn("EXECUTION").AddColumn("SYSTEM TESTS")
+	for _, rule := range report.Rules {
+		rules.UpdateWidths(rule.Rule, strconv.Itoa(rule.Packages), ms(rule.LookupMs), ms(rule.ExecutionMs), ms(rule.SystemTestMs))
+	}
+	rules.Fprint(w)
+	for _, rule := range report.Rules {
+		_, _ = fmt.Fprintln(w, rules.FormatRow(rule.Rule, strconv.Itoa(rule.Packages), ms(rule.LookupMs), ms(rule.ExecutionMs), ms(rule.SystemTestMs)))
+	}
+	_, _ = fmt.Fprintln(w, "Lookup and execution times are summed per package; lookups run concurrently.")
+}
@@ -0,0 +1,86 @@
+package update
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/systemtest"
+)
+
+// TestProfile tests the --profile timing breakdown.
+//
+// It verifies:
+//   - A nil profile records nothing and reports nil
+//   - Phases keep their first run order and repeated phases add up
+//   - Report closes a phase that is still running
+//   - Per-rule timings sum lookup, execution, and shared system tests once, slowest rule first
+//   - after_each system tests are added to the system tests phase
+func TestProfile(t *testing.T) {
+	var disabled *Profile
+	disabled.Enter(PhaseConfigLoad)
+	disabled.Add(PhaseExecution, time.Second)
+	disabled.SetResults([]UpdateResult{{}})
+	disabled.Leave()
+	assert.Nil(t, disabled.Report(time.Now()))
+
+	started := time.Now()
+	profile := NewProfile(started)
+	profile.Add(PhaseConfigLoad, 20*time.Millisecond)
+	profile.Add(PhaseVersionLookup, 300*time.Millisecond)
+	profile.Add(PhaseConfigLoad, 5*time.Millisecond)
+	profile.Enter(PhaseExecution)
+
+	shared := &systemtest.Result{TotalDuration: 40 * time.Millisecond}
+	profile.SetResults([]UpdateResult{
+		{Pkg: formats.Package{Name: "react", Rule: "npm"}, PlanDuration: 100 * time.Millisecond, Duration: 50 * time.Millisecond, SystemTestResult: shared},
+		{Pkg: formats.Package{Name: "react-dom", Rule: "npm"}, PlanDuration: 100 * time.Millisecond, Duration: 50 * time.Millisecond, SystemTestResult: shared},
+		{Pkg: formats.Package{Name: "gin", Rule: "mod"}, PlanDuration: 900 * time.Millisecond, Duration: time.Second},
+	})
+
+	report := profile.Report(started.Add(3 * time.Second))
+	require.NotNil(t, report)
+	assert.Equal(t, int64(3000), report.TotalMs)
+
+	var names []string
+	durations := map[string]int64{}
+	for _, phase := range report.Phases {
+		names = append(names, phase.Name)
+		durations[phase.Name] = phase.DurationMs
+	}
+	// The running execution phase is recorded when Report closes it
+	assert.Equal(t, []string{PhaseConfigLoad, PhaseVersionLookup, PhaseSystemTests, PhaseExecution}, names)
+	assert.Equal(t, int64(25), durations[PhaseConfigLoad])
+	assert.Equal(t, int64(40), durations[PhaseSystemTests])
+
+	assert.Equal(t, []RuleProfile{
+		{Rule: "mod", Packages: 1, LookupMs: 900, ExecutionMs: 1000},
+		{Rule: "npm", Packages: 2, LookupMs: 200, ExecutionMs: 100, SystemTestMs: 40},
+	}, report.Rules)
+}
+
+// TestPrintProfile tests printing the timing breakdown.
+//
+// It verifies:
+//   - The total, a phase table, and a rule table are printed
+//   - A nil report prints nothing
+func TestPrintProfile(t *testing.T) {
+	var buf bytes.Buffer
+	PrintProfile(&buf, &ProfileReport{
+		TotalMs: 2500,
+		Phases:  []ProfilePhase{{Name: PhaseVersionLookup, DurationMs: 1200}},
+		Rules:   []RuleProfile{{Rule: "npm", Packages: 3, LookupMs: 900, ExecutionMs: 40}},
+	})
+	out := buf.String()
+	assert.Contains(t, out, "Profile (total 2.5s):")
+	assert.Regexp(t, `(?m)^version lookup\s+1\.2s`, out)
+	assert.Regexp(t, `(?m)^npm\s+3\s+900ms\s+40ms\s+0ms`, out)
+
+	buf.Reset()
+	PrintProfile(&buf, nil)
+	assert.Empty(t, buf.String())
+}
@@ -2,6 +2,7 @@ package update
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/config"
@@ -18,11 +19,22 @@ var extractAllMatchesWithIndexFunc = utils.ExtractAllMatchesWithIndex
 //
 // It performs the following operations:
 //   - Step 1: Validate extraction pattern is configured
-//   - Step 2: Extract all matches with named groups from content
-//   - Step 3: Find the match for the target package by name
-//   - Step 4: Locate the version capture group within the match
-//   - Step 5: Determine replacement version (with or without constraint prefix)
-//   - Step 6: Replace version at the exact position in the content
+//   - Step 2: Extract all matches with named groups from content for every applicable pattern
+//   - Step 3: Find every match for the target package by name, narrowed to the
+//     declarations of the package's current version when the name is declared at several
+//   - Step 4: Locate the version capture group within each match, following "ref" captures
+//     to their version_ref_pattern definition
+//   - Step 5: Determine replacement version (with or without constraint prefix), rewriting
+//     a captured "constraint" group when the rule sets a constraint_style
+//   - Step 6: Resolve the target digest when a match captures a "digest" group
+//   - Step 7: Replace versions (and digests) at their exact positions in the content
+//
+// Every declaration of the package at its current version is rewritten, so a GitHub
+// Actions workflow that uses the same action in several jobs stays consistent, while
+// declarations of other packages, or of the same action at another version, are left alone. Matches without a version
+// group (e.g. a bare digest pin) are left untouched. A declaration that references a
+// named version (a Gradle catalog version.ref) rewrites the definition instead, which
+// also moves every other package sharing that ref.
 //
 // Parameters:
 //   - content: The original raw file content as bytes
@@ -32,20 +44,25 @@ var extractAllMatchesWithIndexFunc = utils.ExtractAllMatchesWithIndex
 //
 // Returns:
 //   - []byte: Updated raw content with version replaced
-//   - error: Returns error if extraction pattern missing, package not found, version group missing, or invalid position; returns nil on success
+//   - error: Returns error if extraction pattern missing, package not found, version group missing, invalid position, or digest lookup fails; returns nil on success
 func updateRawVersion(content []byte, p formats.Package, ruleCfg config.PackageManagerCfg, target string) ([]byte, error) {
-	if ruleCfg.Extraction == nil || ruleCfg.Extraction.Pattern == "" {
+	text := string(content)
+	patterns := utils.SelectPatterns(text, ruleCfg.Extraction)
+	if len(patterns) == 0 {
 		return nil, &errors.UnsupportedError{Reason: "missing extraction pattern"}
 	}
 
-	text := string(content)
-	matches, err := extractAllMatchesWithIndexFunc(ruleCfg.Extraction.Pattern, text)
-	if err != nil {
-		return nil, err
+	var matches []utils.MatchWithIndex
+	for _, pattern := range patterns {
+		patternMatches, err := extractAllMatchesWithIndexFunc(pattern, text)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, patternMatches...)
 	}
 
-	// Find the match for the target package
-	var targetMatch *utils.MatchWithIndex
+	// Find every match for the target package
+	var targetMatches []*utils.MatchWithIndex
 	for i := range matches {
 		match := &matches[i]
 		name := match.Groups["name"]
@@ -55,56 +72,186 @@ func updateRawVersion(content []byte, p formats.Package, ruleCfg config.PackageM
 		if name == "" || !strings.EqualFold(strings.TrimSpace(name), p.Name) {
 			continue
 		}
-		targetMatch = match
-		break
+		targetMatches = append(targetMatches, match)
 	}
 
-	if targetMatch == nil {
+	if len(targetMatches) == 0 {
 		return nil, fmt.Errorf("package %s not found in raw content", p.Name)
 	}
+	targetMatches = matchesDeclaringVersion(targetMatches, p.Version)
+
+	var edits []rawEdit
+	edited := make(map[int]bool)
+	digest := ""
+	var err error
+	for _, targetMatch := range targetMatches {
+		// Check if we have a version group with index
+		versionIdx, hasVersionIdx := targetMatch.GroupIndex["version"]
+		if !hasVersionIdx || versionIdx[0] < 0 {
+			// Try version_alt for some formats
+			versionIdx, hasVersionIdx = targetMatch.GroupIndex["version_alt"]
+		}
+
+		replacementMatch := targetMatch
+		if ref := strings.TrimSpace(targetMatch.Groups["ref"]); (!hasVersionIdx || versionIdx[0] < 0) && ref != "" {
+			definition, err := findVersionRef(text, ruleCfg.Extraction.VersionRefPattern, ref)
+			if err != nil {
+				return nil, fmt.Errorf("package %s: %w", p.Name, err)
+			}
+			replacementMatch = definition
+			versionIdx, hasVersionIdx = definition.GroupIndex["version"]
+		}
+
+		if !hasVersionIdx || versionIdx[0] < 0 {
+			continue
+		}
+
+		// Declarations sharing a version ref resolve to the same position
+		if edited[versionIdx[0]] {
+			continue
+		}
+		edited[versionIdx[0]] = true
 
-	// Check if we have a version group with index
-	versionIdx, hasVersionIdx := targetMatch.GroupIndex["version"]
-	if !hasVersionIdx || versionIdx[0] < 0 {
-		// Try version_alt for some formats
-		versionIdx, hasVersionIdx = targetMatch.GroupIndex["version_alt"]
+		// Bounds check to prevent panic
+		if versionIdx[0] > len(text) || versionIdx[1] > len(text) || versionIdx[0] > versionIdx[1] {
+			return nil, fmt.Errorf("invalid version position for package %s", p.Name)
+		}
+
+		_, styled := styledConstraint(p, ruleCfg)
+		edits = append(edits, rawEdit{start: versionIdx[0], end: versionIdx[1], text: rawReplacementVersion(replacementMatch, p, target, styled)})
+
+		// A constraint_style also rewrites a separately captured operator
+		if constraintIdx, ok := replacementMatch.GroupIndex["constraint"]; styled && ok && constraintIdx[0] >= 0 {
+			edits = append(edits, rawEdit{start: constraintIdx[0], end: constraintIdx[1], text: p.Constraint})
+		}
+
+		// Digest-pinned declarations also need the digest of the target version
+		if digestIdx, ok := targetMatch.GroupIndex["digest"]; ok && digestIdx[0] >= 0 {
+			if digest == "" {
+				digest, err = resolveDigestFunc(p, ruleCfg, target)
+				if err != nil {
+					return nil, err
+				}
+			}
+			edits = append(edits, rawEdit{start: digestIdx[0], end: digestIdx[1], text: digest})
+		}
 	}
 
-	if !hasVersionIdx || versionIdx[0] < 0 {
+	if len(edits) == 0 {
 		return nil, fmt.Errorf("no version found for package %s", p.Name)
 	}
 
-	// Bounds check to prevent panic
-	if versionIdx[0] > len(text) || versionIdx[1] > len(text) || versionIdx[0] > versionIdx[1] {
-		return nil, fmt.Errorf("invalid version position for package %s", p.Name)
+	// Replace from the end so earlier positions stay valid
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	result := text
+	for _, edit := range edits {
+		result = result[:edit.start] + edit.text + result[edit.end:]
 	}
 
-	// Determine the replacement version
-	// If the pattern has a separate constraint group, don't include constraint in version
-	// If not, check if the captured version includes a constraint-like prefix
-	_, hasConstraintGroup := targetMatch.GroupIndex["constraint"]
+	return []byte(result), nil
+}
 
-	var newVersion string
-	if hasConstraintGroup {
-		// Constraint is captured separately, just replace the version number
-		newVersion = target
-	} else {
-		// Check if the captured version starts with constraint characters
-		oldVersion := targetMatch.Groups["version"]
-		if oldVersion == "" {
-			oldVersion = targetMatch.Groups["version_alt"]
+// matchesDeclaringVersion narrows a package's declarations to those at its current version.
+//
+// A name declared at several versions (actions/checkout@v3 in one job, @v4 in
+// another) is parsed as one package per version, so only the declarations
+// matching the package's version belong to it. When none match, as when
+// rolling back a declaration that was already rewritten, every match is kept.
+//
+// Parameters:
+//   - matches: Declarations whose name equals the package
+//   - version: The package's declared version
+//
+// Returns:
+//   - []*utils.MatchWithIndex: Declarations at version, or matches when there are none
+func matchesDeclaringVersion(matches []*utils.MatchWithIndex, version string) []*utils.MatchWithIndex {
+	version = strings.TrimSpace(version)
+	if version == "" || version == "*" || len(matches) < 2 {
+		return matches
+	}
+
+	var declared []*utils.MatchWithIndex
+	for _, match := range matches {
+		captured := match.Groups["version"]
+		if captured == "" {
+			captured = match.Groups["version_alt"]
 		}
-		if len(oldVersion) > 0 && strings.ContainsAny(string(oldVersion[0]), "^~<>=!") {
-			// Version includes constraint, include it in replacement
-			newVersion = fmt.Sprintf("%s%s", p.Constraint, target)
-		} else {
-			// Just the version number (constraint is outside capture group)
-			newVersion = target
+		if strings.TrimSpace(captured) == version {
+			declared = append(declared, match)
 		}
 	}
+	if len(declared) == 0 {
+		return matches
+	}
+	return declared
+}
+
+// findVersionRef locates the definition of a named version in raw content.
+//
+// Parameters:
+//   - text: The raw file content
+//   - pattern: The version_ref_pattern with named groups "ref" and "version"
+//   - ref: The ref name to find
+//
+// Returns:
+//   - *utils.MatchWithIndex: The definition match
+//   - error: Returns error if no pattern is configured or the ref is not defined
+func findVersionRef(text, pattern, ref string) (*utils.MatchWithIndex, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("version ref %q used but no version_ref_pattern configured", ref)
+	}
 
-	// Replace at the exact position
-	result := text[:versionIdx[0]] + newVersion + text[versionIdx[1]:]
+	matches, err := extractAllMatchesWithIndexFunc(pattern, text)
+	if err != nil {
+		return nil, err
+	}
 
-	return []byte(result), nil
+	for i := range matches {
+		if strings.TrimSpace(matches[i].Groups["ref"]) == ref {
+			return &matches[i], nil
+		}
+	}
+	return nil, fmt.Errorf("version ref %q is not defined", ref)
+}
+
+// rawEdit is a replacement of content[start:end] with text.
+type rawEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// rawReplacementVersion returns the text that replaces a match's version group.
+//
+// If the pattern has a separate constraint group, only the version number is
+// replaced. Otherwise, a captured version that starts with constraint characters,
+// or any captured version when styled, is rewritten with the package constraint
+// as prefix.
+//
+// Parameters:
+//   - match: The matched declaration
+//   - p: The package being updated
+//   - target: The target version
+//   - styled: Whether the package constraint comes from the rule's constraint_style
+//
+// Returns:
+//   - string: Replacement text for the version group
+func rawReplacementVersion(match *utils.MatchWithIndex, p formats.Package, target string, styled bool) string {
+	if _, hasConstraintGroup := match.GroupIndex["constraint"]; hasConstraintGroup {
+		// Constraint is captured separately, just replace the version number
+		return target
+	}
+
+	// Check if the captured version starts with constraint characters
+	oldVersion := match.Groups["version"]
+	if oldVersion == "" {
+		oldVersion = match.Groups["version_alt"]
+	}
+	if styled || (len(oldVersion) > 0 && strings.ContainsAny(string(oldVersion[0]), "^~<>=!")) {
+		// Version includes constraint, include it in replacement
+		return fmt.Sprintf("%s%s", p.Constraint, target)
+	}
+
+	// Just the version number (constraint is outside capture group)
+	return target
 }
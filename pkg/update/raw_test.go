@@ -1,6 +1,9 @@
 package update
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/ajxudir/goupdate/pkg/config"
@@ -209,3 +212,425 @@ func TestUpdateRawVersionNoExtractionPattern(t *testing.T) {
 	require.Error(t, err)
 	assert.True(t, errors.IsUnsupported(err))
 }
+
+// TestUpdateRawVersionPoetryPreservesMarkers tests rewriting pyproject.toml inline tables.
+//
+// It verifies:
+//   - Only the version span of the inline table is replaced
+//   - Extras and PEP 508 markers are preserved byte-for-byte
+func TestUpdateRawVersionPoetryPreservesMarkers(t *testing.T) {
+	cfg := config.PackageManagerCfg{
+		Format: "raw",
+		Extraction: &config.ExtractionCfg{
+			Pattern: `(?m)^(?P<n>[A-Za-z0-9][\w\-\.]*)\s*=\s*(?:\{[^}\n]*?\bversion\s*=\s*)?"(?P<constraint>\^|~=|~|[><=!]+)?\s*(?P<version>[\w\.\-\+\*]+)?`,
+		},
+	}
+	content := []byte(`[tool.poetry.dependencies]
+python = "^3.10"
+celery = { version = "^5.3.6", extras = ["redis"], markers = "python_version >= '3.10' and sys_platform != 'win32'" }
+`)
+
+	updated, err := updateRawVersion(content, formats.Package{Name: "celery", Constraint: "^"}, cfg, "5.4.0")
+	require.NoError(t, err)
+	assert.Equal(t, `[tool.poetry.dependencies]
+python = "^3.10"
+celery = { version = "^5.4.0", extras = ["redis"], markers = "python_version >= '3.10' and sys_platform != 'win32'" }
+`, string(updated))
+}
+
+// defaultRawRule returns a raw-format rule using the built-in rule's extraction pattern.
+//
+// Parameters:
+//   - t: Test handle
+//   - rule: Name of the built-in rule
+//
+// Returns:
+//   - config.PackageManagerCfg: Rule with the default extraction pattern
+func defaultRawRule(t *testing.T, rule string) config.PackageManagerCfg {
+	t.Helper()
+	cfg, err := config.LoadConfig("", t.TempDir())
+	require.NoError(t, err)
+	ruleCfg, ok := cfg.Rules[rule]
+	require.True(t, ok, rule)
+	require.NotNil(t, ruleCfg.Extraction, rule)
+	return config.PackageManagerCfg{Format: "raw", Extraction: &config.ExtractionCfg{Pattern: ruleCfg.Extraction.Pattern}}
+}
+
+// TestUpdateRawVersionPipfileRoundTrip tests rewriting Pipfile declarations.
+//
+// It verifies:
+//   - String and inline-table declarations have only their version replaced
+//   - Extras and PEP 508 markers are preserved byte-for-byte
+//   - Quoted dotted names are matched
+//   - VCS and local entries next to the package are left unchanged
+func TestUpdateRawVersionPipfileRoundTrip(t *testing.T) {
+	cfg := defaultRawRule(t, "pipfile")
+	vcs := `internal-auth = {git = "https://github.com/example/internal-auth.git", ref = "v0.3.0"}
+billing = {path = "./libs/billing", editable = true}
+`
+
+	tests := []struct {
+		name       string
+		pkg        string
+		constraint string
+		line       string
+		target     string
+		want       string
+	}{
+		{
+			name:       "string declaration",
+			pkg:        "requests",
+			constraint: ">=",
+			line:       `requests = ">=2.31.0"`,
+			target:     "2.32.3",
+			want:       `requests = ">=2.32.3"`,
+		},
+		{
+			name:       "extras",
+			pkg:        "sentry-sdk",
+			constraint: ">=",
+			line:       `sentry-sdk = {version = ">=1.39.0", extras = ["flask", "celery"]}`,
+			target:     "2.0.0",
+			want:       `sentry-sdk = {version = ">=2.0.0", extras = ["flask", "celery"]}`,
+		},
+		{
+			name:       "markers",
+			pkg:        "pywin32",
+			constraint: "==",
+			line:       `pywin32 = {version = "==306", markers = "sys_platform == 'win32'"}`,
+			target:     "308",
+			want:       `pywin32 = {version = "==308", markers = "sys_platform == 'win32'"}`,
+		},
+		{
+			name:       "extras and markers before version",
+			pkg:        "celery",
+			constraint: "~=",
+			line:       `celery = {extras = ["redis"], markers = "python_version >= '3.10' and os_name != 'nt'", version = "~=5.3.6"}`,
+			target:     "5.4.0",
+			want:       `celery = {extras = ["redis"], markers = "python_version >= '3.10' and os_name != 'nt'", version = "~=5.4.0"}`,
+		},
+		{
+			name:       "quoted dotted name",
+			pkg:        "zope.interface",
+			constraint: ">=",
+			line:       `"zope.interface" = ">=6.0"`,
+			target:     "7.1",
+			want:       `"zope.interface" = ">=7.1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := []byte("[packages]\n" + tt.line + "\n" + vcs)
+
+			updated, err := updateRawVersion(content, formats.Package{Name: tt.pkg, Constraint: tt.constraint}, cfg, tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, "[packages]\n"+tt.want+"\n"+vcs, string(updated))
+		})
+	}
+}
+
+// TestUpdateRawVersionReplacesAllDeclarations tests updating a package declared several times.
+//
+// It verifies:
+//   - Every declaration of the package is rewritten
+//   - Sub-path declarations (owner/repo/path) are rewritten with the owner/repo package
+//   - Other packages are left unchanged
+func TestUpdateRawVersionReplacesAllDeclarations(t *testing.T) {
+	cfg := defaultRawRule(t, "github-actions")
+	content := []byte(`jobs:
+  a:
+    steps:
+      - uses: actions/checkout@v4
+      - uses: github/codeql-action/init@v3.25.0
+  b:
+    steps:
+      - uses: actions/checkout@v4
+      - uses: github/codeql-action/analyze@v3.25.0
+      - uses: actions/setup-go@v4
+`)
+
+	updated, err := updateRawVersion(content, formats.Package{Name: "actions/checkout", Source: "ci.yml"}, cfg, "v5")
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(updated), "actions/checkout@v5"))
+	assert.Contains(t, string(updated), "actions/setup-go@v4")
+
+	updated, err = updateRawVersion(content, formats.Package{Name: "github/codeql-action", Source: "ci.yml"}, cfg, "v3.26.0")
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), "github/codeql-action/init@v3.26.0")
+	assert.Contains(t, string(updated), "github/codeql-action/analyze@v3.26.0")
+}
+
+// TestUpdateRawVersionSameVersionPackages tests updating one of several packages pinned at the same version.
+//
+// It verifies:
+//   - Only declarations whose name group equals the package are rewritten
+//   - Declarations of the same action at another version are left alone
+//   - A package whose declared version is no longer present still rewrites every declaration
+func TestUpdateRawVersionSameVersionPackages(t *testing.T) {
+	cfg := defaultRawRule(t, "github-actions")
+	content := []byte(`jobs:
+  a:
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/cache@v4
+  b:
+    steps:
+      - uses: actions/checkout@v3
+      - uses: actions/cache@v4
+`)
+
+	updated, err := updateRawVersion(content, formats.Package{Name: "actions/checkout", Version: "v4", Source: "ci.yml"}, cfg, "v5")
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), "actions/checkout@v5")
+	assert.Contains(t, string(updated), "actions/checkout@v3")
+	assert.Equal(t, 2, strings.Count(string(updated), "actions/cache@v4"))
+
+	updated, err = updateRawVersion([]byte("- uses: actions/checkout@v5\n- uses: actions/checkout@v5\n"), formats.Package{Name: "actions/checkout", Version: "v4", Source: "ci.yml"}, cfg, "v4")
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(updated), "actions/checkout@v4"))
+}
+
+// TestUpdateRawVersionDigestPinned tests updating digest-pinned declarations.
+//
+// It verifies:
+//   - The digest and the version comment both move to the target
+//   - The digest is resolved once per update, even for repeated declarations
+//   - Digest lookup errors are returned
+//   - A bare digest pin without a version is not rewritten
+func TestUpdateRawVersionDigestPinned(t *testing.T) {
+	oldDigest := strings.Repeat("a", 40)
+	newDigest := strings.Repeat("b", 40)
+	cfg := defaultRawRule(t, "github-actions")
+	content := []byte("- uses: actions/cache@" + oldDigest + " # v4.0.2\n- uses: actions/cache@" + oldDigest + " # v4.0.2\n")
+
+	calls := 0
+	original := resolveDigestFunc
+	t.Cleanup(func() { resolveDigestFunc = original })
+	resolveDigestFunc = func(p formats.Package, _ config.PackageManagerCfg, target string) (string, error) {
+		calls++
+		assert.Equal(t, "actions/cache", p.Name)
+		assert.Equal(t, "v4.1.0", target)
+		return newDigest, nil
+	}
+
+	updated, err := updateRawVersion(content, formats.Package{Name: "actions/cache", Source: "ci.yml"}, cfg, "v4.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(updated), "actions/cache@"+newDigest+" # v4.1.0"))
+	assert.Equal(t, 1, calls)
+
+	resolveDigestFunc = func(formats.Package, config.PackageManagerCfg, string) (string, error) {
+		return "", fmt.Errorf("lookup failed")
+	}
+	_, err = updateRawVersion(content, formats.Package{Name: "actions/cache", Source: "ci.yml"}, cfg, "v4.1.0")
+	assert.ErrorContains(t, err, "lookup failed")
+
+	_, err = updateRawVersion([]byte("- uses: actions/cache@"+oldDigest+"\n"), formats.Package{Name: "actions/cache", Source: "ci.yml"}, cfg, "v4.1.0")
+	assert.ErrorContains(t, err, "no version found")
+}
+
+// TestResolveDigest tests running the update.resolve_digest command.
+//
+// It verifies:
+//   - Missing resolve_digest config returns an UnsupportedError
+//   - The command receives the package and target version and runs in the manifest directory
+//   - The digest group is extracted from the output
+//   - Command failures and output without a digest return errors
+func TestResolveDigest(t *testing.T) {
+	p := formats.Package{Name: "actions/cache", Source: "/repo/.github/workflows/ci.yml"}
+
+	_, err := resolveDigest(p, config.PackageManagerCfg{}, "v4.1.0")
+	require.Error(t, err)
+	assert.True(t, errors.IsUnsupported(err))
+
+	cfg := config.PackageManagerCfg{Update: &config.UpdateCfg{ResolveDigest: &config.ResolveDigestCfg{
+		Commands: "lookup {{package}} {{version}}",
+		Pattern:  `(?P<digest>[0-9a-f]{40})`,
+	}}}
+
+	original := executeDigestFunc
+	t.Cleanup(func() { executeDigestFunc = original })
+
+	digest := strings.Repeat("c", 40)
+	executeDigestFunc = func(commands string, _ map[string]string, dir string, _ int, replacements map[string]string) ([]byte, error) {
+		assert.Equal(t, "/repo/.github/workflows", dir)
+		assert.Equal(t, "actions/cache", replacements["package"])
+		assert.Equal(t, "v4.1.0", replacements["version"])
+		return []byte(digest + "\n"), nil
+	}
+	got, err := resolveDigest(p, cfg, "v4.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, digest, got)
+
+	executeDigestFunc = func(string, map[string]string, string, int, map[string]string) ([]byte, error) {
+		return []byte("Not Found"), nil
+	}
+	_, err = resolveDigest(p, cfg, "v4.1.0")
+	assert.ErrorContains(t, err, "no digest found")
+
+	executeDigestFunc = func(string, map[string]string, string, int, map[string]string) ([]byte, error) {
+		return nil, fmt.Errorf("exit status 22")
+	}
+	_, err = resolveDigest(p, cfg, "v4.1.0")
+	assert.ErrorContains(t, err, "failed to resolve digest")
+}
+
+// TestUpdateRawVersionSwiftPackage tests rewriting Package.swift with the built-in swiftpm rule.
+//
+// It verifies:
+//   - Only the version string is replaced; from:, .upToNextMinor(from:), and the URL stay unchanged
+//   - Other packages in the manifest are left untouched
+func TestUpdateRawVersionSwiftPackage(t *testing.T) {
+	cfg, err := config.LoadConfig("", "../testdata/swiftpm")
+	require.NoError(t, err)
+	rule := cfg.Rules["swiftpm"]
+
+	content := []byte(`        .package(url: "https://github.com/apple/swift-nio.git", from: "2.62.0"),
+        .package(url: "https://github.com/apple/swift-log.git", .upToNextMinor(from: "1.5.3")),
+`)
+	p := formats.Package{Name: "https://github.com/apple/swift-log.git", Constraint: "~", Version: "1.5.3", Source: "Package.swift"}
+	updated, err := updateRawVersion(content, p, rule, "1.6.1")
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), `.package(url: "https://github.com/apple/swift-log.git", .upToNextMinor(from: "1.6.1")),`)
+	assert.Contains(t, string(updated), `.package(url: "https://github.com/apple/swift-nio.git", from: "2.62.0"),`)
+
+	p = formats.Package{Name: "https://github.com/apple/swift-nio.git", Constraint: "^", Version: "2.62.0", Source: "Package.swift"}
+	updated, err = updateRawVersion(updated, p, rule, "2.65.0")
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), `.package(url: "https://github.com/apple/swift-nio.git", from: "2.65.0"),`)
+}
+
+// TestUpdateRawVersionGradle tests rewriting Gradle build scripts and version catalogs with the built-in gradle rule.
+//
+// It verifies:
+//   - Build script coordinates have only their version replaced, in Groovy and Kotlin DSL
+//   - A catalog library using version.ref rewrites the [versions] entry, not the library line
+//   - Libraries sharing a version ref move together
+//   - Catalog libraries with an inline version are rewritten in place
+//   - A version ref without a definition is an error
+func TestUpdateRawVersionGradle(t *testing.T) {
+	cfg, err := config.LoadConfig("", "../testdata/gradle")
+	require.NoError(t, err)
+	rule := cfg.Rules["gradle"]
+
+	build := []byte(`dependencies {
+    implementation 'com.google.guava:guava:32.1.3-jre'
+    implementation(platform("org.springframework.boot:spring-boot-dependencies:3.2.0"))
+}
+`)
+	p := formats.Package{Name: "com.google.guava:guava", Version: "32.1.3-jre", Source: "build.gradle"}
+	updated, err := updateRawVersion(build, p, rule, "33.0.0-jre")
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), `implementation 'com.google.guava:guava:33.0.0-jre'`)
+
+	p = formats.Package{Name: "org.springframework.boot:spring-boot-dependencies", Version: "3.2.0", Source: "build.gradle"}
+	updated, err = updateRawVersion(updated, p, rule, "3.2.1")
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), `implementation(platform("org.springframework.boot:spring-boot-dependencies:3.2.1"))`)
+
+	catalog, err := os.ReadFile("../testdata/gradle/gradle/libs.versions.toml")
+	require.NoError(t, err)
+
+	p = formats.Package{Name: "com.squareup.okhttp3:okhttp", Version: "4.12.0", Source: "gradle/libs.versions.toml"}
+	updated, err = updateRawVersion(catalog, p, rule, "4.12.1")
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), `okhttp = "4.12.1"`)
+	assert.Contains(t, string(updated), `okhttp = { module = "com.squareup.okhttp3:okhttp", version.ref = "okhttp" }`)
+	assert.Contains(t, string(updated), `kotlin = "1.9.22"`)
+
+	parsed, err := (&formats.RawParser{}).Parse(updated, &rule)
+	require.NoError(t, err)
+	for _, pkg := range parsed {
+		if pkg.Name == "com.squareup.okhttp3:logging-interceptor" {
+			assert.Equal(t, "4.12.1", pkg.Version, "libraries sharing a version ref move together")
+		}
+	}
+
+	p = formats.Package{Name: "org.jetbrains.kotlinx:kotlinx-coroutines-core", Version: "1.7.3", Source: "gradle/libs.versions.toml"}
+	updated, err = updateRawVersion(updated, p, rule, "1.8.0")
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), `module = "org.jetbrains.kotlinx:kotlinx-coroutines-core", version = "1.8.0" }`)
+
+	p = formats.Package{Name: "org.example:missing", Version: "1.0.0", Source: "gradle/libs.versions.toml"}
+	_, err = updateRawVersion([]byte(`missing = { module = "org.example:missing", version.ref = "nope" }`), p, rule, "1.1.0")
+	assert.ErrorContains(t, err, `version ref "nope" is not defined`)
+}
+
+// TestUpdateRawVersionDeno tests rewriting deno.json import maps with the built-in deno rule.
+//
+// It verifies:
+//   - npm: and jsr: specifiers have only their version replaced, keeping the constraint
+//   - Subpath imports keep their trailing slash
+//   - URL imports keep the v prefix and the module path after the version
+func TestUpdateRawVersionDeno(t *testing.T) {
+	cfg, err := config.LoadConfig("", "../testdata/deno")
+	require.NoError(t, err)
+	rule := cfg.Rules["deno"]
+
+	content, err := os.ReadFile("../testdata/deno/deno.json")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		version string
+		target  string
+		want    string
+	}{
+		{"npm:preact", "10.24.3", "10.25.1", `"preact": "npm:preact@^10.25.1"`},
+		{"npm:@types/node", "22.7.5", "22.10.0", `"@types/node": "npm:@types/node@^22.10.0"`},
+		{"jsr:@std/path", "1.0.6", "1.0.8", `"@std/path/": "jsr:@std/path@~1.0.8/"`},
+		{"https://deno.land/x/zod", "v3.23.8", "v3.24.1", `"zod": "https://deno.land/x/zod@v3.24.1/mod.ts"`},
+		{"https://esm.sh/@preact/signals", "1.3.0", "1.3.1", `"signals": "https://esm.sh/@preact/signals@1.3.1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := formats.Package{Name: tt.name, Version: tt.version, Source: "deno.json"}
+			updated, err := updateRawVersion(content, p, rule, tt.target)
+			require.NoError(t, err)
+			assert.Contains(t, string(updated), tt.want)
+			assert.Contains(t, string(updated), `"chalk": "npm:chalk@5.3.0"`)
+		})
+	}
+}
+
+// TestUpdateRawVersionUV tests rewriting pyproject.toml requirement arrays with the built-in uv rule.
+//
+// It verifies:
+//   - Items of single-line and multi-line arrays have only their version replaced
+//   - Extras, markers and upper bounds stay unchanged
+func TestUpdateRawVersionUV(t *testing.T) {
+	cfg, err := config.LoadConfig("", "../testdata/uv")
+	require.NoError(t, err)
+	rule := cfg.Rules["uv"]
+
+	content := []byte(`[project]
+dependencies = [
+    "pydantic[email]~=2.6.4",
+    "httpx>=0.27.0,<1; python_version >= '3.10'",
+]
+
+[dependency-groups]
+dev = ["pytest>=8.1.1", "ruff==0.4.1"]
+`)
+
+	tests := []struct {
+		name    string
+		version string
+		target  string
+		want    string
+	}{
+		{"pydantic", "2.6.4", "2.7.1", `"pydantic[email]~=2.7.1",`},
+		{"httpx", "0.27.0", "0.28.1", `"httpx>=0.28.1,<1; python_version >= '3.10'",`},
+		{"ruff", "0.4.1", "0.4.4", `dev = ["pytest>=8.1.1", "ruff==0.4.4"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := formats.Package{Name: tt.name, Version: tt.version, Source: "pyproject.toml"}
+			updated, err := updateRawVersion(content, p, rule, tt.target)
+			require.NoError(t, err)
+			assert.Contains(t, string(updated), tt.want)
+		})
+	}
+}
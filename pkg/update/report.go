@@ -0,0 +1,222 @@
+package update
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/output"
+)
+
+// ReportFileVersion is the current version of the run report format.
+const ReportFileVersion = 1
+
+// RunReport is the artifact written by `goupdate update --report-file`.
+//
+// It combines everything a run produced, independent of the console
+// --output format, so CI can upload one file per run. The report is filled
+// in as the run progresses; a run that stops early (for example on a config
+// error) still produces a report with the fields known at that point.
+//
+// Fields:
+//   - Version: Report format version (ReportFileVersion)
+//   - StartedAt: When the run started
+//   - FinishedAt: When the run finished
+//   - DurationMs: Wall time of the run in milliseconds
+//   - Config: The options the run was started with
+//   - Outcome: Exit code and the reason behind it
+//   - Summary: Package counts, as in structured output
+//   - Packages: One entry per update result, including timings
+//   - SystemTestFailures: System tests that failed during the run
+//   - Unsupported: Messages for packages that cannot be updated automatically
+//   - Warnings: Warnings collected during the run
+//   - Errors: Failures recorded during the run
+//   - Profile: Phase and per-rule timings; omitted unless --profile is set
+type RunReport struct {
+	Version            int                       `json:"version"`
+	StartedAt          time.Time                 `json:"started_at"`
+	FinishedAt         time.Time                 `json:"finished_at"`
+	DurationMs         int64                     `json:"duration_ms"`
+	Config             ReportConfig              `json:"config"`
+	Outcome            ReportOutcome             `json:"outcome"`
+	Summary            output.UpdateSummary      `json:"summary"`
+	Packages           []output.UpdatePackage    `json:"packages"`
+	SystemTestFailures []output.UpdateSystemTest `json:"system_test_failures"`
+	Unsupported        []string                  `json:"unsupported,omitempty"`
+	Warnings           []string                  `json:"warnings,omitempty"`
+	Errors             []string                  `json:"errors,omitempty"`
+	Profile            *ProfileReport            `json:"profile,omitempty"`
+}
+
+// ReportConfig summarizes the options of a run.
+//
+// Fields:
+//   - WorkDir: Working directory of the run
+//   - ConfigFile: Config file passed with --config; empty for the default lookup
+//   - Rules: Enabled rules, sorted
+//   - Scope: Version scope description (e.g. "minor")
+//   - FailOn: The --fail-on policy
+//   - DryRun, ContinueOnFail, SkipLock, Staged, Incremental, StrictLock: Run flags
+//   - Limit: The --limit cap on updated packages; omitted when unlimited
+//   - GroupCommands: Whether --group-commands ran one lock command per rule; omitted when off
+type ReportConfig struct {
+	WorkDir        string   `json:"work_dir"`
+	ConfigFile     string   `json:"config_file,omitempty"`
+	Rules          []string `json:"rules,omitempty"`
+	Scope          string   `json:"scope"`
+	FailOn         string   `json:"fail_on"`
+	DryRun         bool     `json:"dry_run"`
+	ContinueOnFail bool     `json:"continue_on_fail"`
+	SkipLock       bool     `json:"skip_lock"`
+	Staged         bool     `json:"staged"`
+	Incremental    bool     `json:"incremental"`
+	StrictLock     bool     `json:"strict_lock"`
+	Limit          int      `json:"limit,omitempty"`
+	GroupCommands  bool     `json:"group_commands,omitempty"`
+}
+
+// ReportOutcome is the final result of a run.
+//
+// Fields:
+//   - ExitCode: The process exit code
+//   - Kind: How the run ended independent of --fail-on (success, partial-success, failure, only-unsupported)
+//   - Reason: Why the run exited with ExitCode (e.g. the --fail-on counts)
+//   - Error: The error the run returned; empty on success
+//   - Updated, Failed, Unsupported: The counts the exit code was derived from
+//   - Warned: Failures reported as warnings by the severity config
+//   - Severity: The effective severity mapping; omitted when it is the default
+type ReportOutcome struct {
+	ExitCode    int      `json:"exit_code"`
+	Kind        string   `json:"kind,omitempty"`
+	Reason      string   `json:"reason,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	Updated     int      `json:"updated"`
+	Failed      int      `json:"failed"`
+	Unsupported int      `json:"unsupported"`
+	Warned      int      `json:"warned,omitempty"`
+	Severity    []string `json:"severity,omitempty"`
+}
+
+// NewRunReport starts a report for a run.
+//
+// Parameters:
+//   - startedAt: When the run started
+//
+// Returns:
+//   - *RunReport: An empty report; Packages is non-nil so it encodes as []
+func NewRunReport(startedAt time.Time) *RunReport {
+	return &RunReport{
+		Version:   ReportFileVersion,
+		StartedAt: startedAt,
+		Packages:  []output.UpdatePackage{},
+	}
+}
+
+// NewReportConfig builds the config section of a report.
+//
+// Parameters:
+//   - cfg: Loaded configuration; may be nil when loading failed
+//   - workDir: Working directory of the run
+//   - selection: Version selection flags
+//
+// Returns:
+//   - ReportConfig: Config section with the working directory, enabled rules and scope set
+func NewReportConfig(cfg *config.Config, workDir string, selection outdated.UpdateSelectionFlags) ReportConfig {
+	rc := ReportConfig{WorkDir: workDir, Scope: DetermineScopeDescription(selection)}
+	if cfg != nil {
+		for name, rule := range cfg.Rules {
+			if rule.IsEnabled() {
+				rc.Rules = append(rc.Rules, name)
+			}
+		}
+		sort.Strings(rc.Rules)
+	}
+	return rc
+}
+
+// SetResults records the results of a run.
+//
+// Parameters:
+//   - structured: Results converted by BuildUpdateOutput
+//   - unsupported: Messages for packages that cannot be updated automatically
+func (r *RunReport) SetResults(structured *output.UpdateResult, unsupported []string) {
+	if structured == nil {
+		return
+	}
+	r.Summary = structured.Summary
+	if structured.Packages != nil {
+		r.Packages = structured.Packages
+	}
+	r.SystemTestFailures = structured.SystemTestFailures
+	r.Warnings = structured.Warnings
+	r.Errors = structured.Errors
+	r.Unsupported = unsupported
+}
+
+// SetSummary records the counts and reason the exit code was derived from.
+//
+// Parameters:
+//   - summary: The run summary built by BuildSummary
+func (r *RunReport) SetSummary(summary errors.Summary) {
+	r.Outcome.Updated = summary.Updated
+	r.Outcome.Failed = summary.Failed
+	r.Outcome.Unsupported = summary.Unsupported
+	r.Outcome.Warned = summary.Warned
+	r.Outcome.Severity = summary.Severity
+	r.Outcome.ExitCode = summary.ExitCode
+	r.Outcome.Kind = string(summary.Kind())
+	r.Outcome.Reason = summary.Reason()
+}
+
+// Finish records the end of the run and its final error.
+//
+// The exit code is taken from runErr, which wins over the summary: a run can
+// fail after its results were recorded, for example when writing the output
+// fails. An ExitError's reason replaces the summary's reason when set.
+//
+// Parameters:
+//   - runErr: The error the run returns; nil on success
+//   - finishedAt: When the run finished
+func (r *RunReport) Finish(runErr error, finishedAt time.Time) {
+	r.FinishedAt = finishedAt
+	r.DurationMs = finishedAt.Sub(r.StartedAt).Milliseconds()
+	r.Outcome.ExitCode = errors.GetExitCode(runErr)
+	if runErr == nil {
+		return
+	}
+
+	r.Outcome.Error = runErr.Error()
+	var exitErr *errors.ExitError
+	if stderrors.As(runErr, &exitErr) && exitErr.Reason != "" {
+		r.Outcome.Reason = exitErr.Reason
+	}
+}
+
+// WriteReportFile writes a run report as indented JSON.
+//
+// The file is written to a temporary file next to path and renamed into
+// place, so a reader never sees a partial report.
+//
+// Parameters:
+//   - path: Destination file path
+//   - report: Report to write
+//
+// Returns:
+//   - error: When encoding or writing fails
+func WriteReportFile(path string, report *RunReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report file: %w", err)
+	}
+
+	if err := writeFileAtomic(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write report file %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package update
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// TestNewReportConfig tests the config section of a run report.
+//
+// It verifies:
+//   - Only enabled rules are listed, sorted
+//   - The scope is derived from the selection flags
+//   - A nil config lists no rules
+func TestNewReportConfig(t *testing.T) {
+	disabled := false
+	cfg := testutil.NewConfig().
+		WithRule("npm", testutil.NPMRule()).
+		WithRule("mod", testutil.GoModRule()).
+		WithRule("pipfile", config.PackageManagerCfg{Enabled: &disabled}).
+		Build()
+
+	rc := NewReportConfig(cfg, "/repo", outdated.UpdateSelectionFlags{Major: true})
+	assert.Equal(t, "/repo", rc.WorkDir)
+	assert.Equal(t, []string{"mod", "npm"}, rc.Rules)
+	assert.Equal(t, DetermineScopeDescription(outdated.UpdateSelectionFlags{Major: true}), rc.Scope)
+
+	assert.Empty(t, NewReportConfig(nil, ".", outdated.UpdateSelectionFlags{}).Rules)
+}
+
+// TestRunReportOutcome tests recording the outcome of a run.
+//
+// It verifies:
+//   - Results and the summary's counts, kind and reason are recorded
+//   - The exit code and reason come from the run's error when it has them
+//   - A nil error exits 0 with the summary's reason
+func TestRunReportOutcome(t *testing.T) {
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	results := []UpdateResult{
+		{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Target: "17.0.2", Status: constants.StatusUpdated, Duration: 1500 * time.Millisecond},
+		{Pkg: testutil.NPMPackage("vue", "3.0.0", "3.0.0"), Target: "3.4.0", Status: constants.StatusFailed, Err: stderrors.New("lock failed")},
+	}
+
+	report := NewRunReport(started)
+	report.SetResults(BuildUpdateOutput(results, nil, []string{"slow registry"}, []string{"vue: lock failed"}, false, outdated.UpdateSelectionFlags{}), []string{"left-pad: no lock file"})
+	report.SetSummary(errors.NewSummary(errors.RunOutcome{Succeeded: 1, Failed: 1, Partial: true}, "partial failure", nil, errors.FailOnPartial))
+
+	runErr := errors.NewExitErrorf(errors.ExitPartialFailure, "1 failed").WithReason("--fail-on partial: custom")
+	report.Finish(runErr, started.Add(3*time.Second))
+
+	assert.Equal(t, int64(3000), report.DurationMs)
+	assert.Equal(t, errors.ExitPartialFailure, report.Outcome.ExitCode)
+	assert.Equal(t, "--fail-on partial: custom", report.Outcome.Reason)
+	assert.Equal(t, "1 failed", report.Outcome.Error)
+	assert.Equal(t, 1, report.Outcome.Updated)
+	assert.Equal(t, 1, report.Outcome.Failed)
+	assert.Equal(t, "partial-success", report.Outcome.Kind)
+	require.Len(t, report.Packages, 2)
+	assert.Equal(t, int64(1500), report.Packages[0].DurationMs)
+	assert.Equal(t, "lock failed", report.Packages[1].Error)
+	assert.Equal(t, 2, report.Summary.TotalPackages)
+	assert.Equal(t, []string{"left-pad: no lock file"}, report.Unsupported)
+	assert.Equal(t, []string{"slow registry"}, report.Warnings)
+
+	success := NewRunReport(started)
+	success.SetSummary(errors.NewSummary(errors.RunOutcome{Succeeded: 2}, "", nil, errors.FailOnPartial))
+	success.Finish(nil, started)
+	assert.Equal(t, errors.ExitSuccess, success.Outcome.ExitCode)
+	assert.Contains(t, success.Outcome.Reason, "2 succeeded")
+	assert.Equal(t, "success", success.Outcome.Kind)
+	assert.Empty(t, success.Outcome.Error)
+}
+
+// TestWriteReportFile tests writing a run report.
+//
+// It verifies:
+//   - The report is written as JSON that decodes back to the same report
+//   - An existing report is replaced without leaving temporary files
+//   - A report without results encodes packages as an empty list
+//   - Unwritable destinations return errors
+func TestWriteReportFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.json")
+	require.NoError(t, os.WriteFile(path, []byte("stale"), 0o644))
+
+	report := NewRunReport(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	report.Finish(nil, report.StartedAt)
+	require.NoError(t, WriteReportFile(path, report))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"packages": []`)
+
+	var decoded RunReport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, report.StartedAt, decoded.StartedAt)
+	assert.Equal(t, ReportFileVersion, decoded.Version)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	err = WriteReportFile(filepath.Join(dir, "missing", "report.json"), report)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to write report file")
+}
@@ -16,7 +16,8 @@ import (
 //   - Step 2: Check that update configuration is defined for the rule
 //   - Step 3: Create a copy of the base update configuration
 //   - Step 4: Apply package-specific overrides if they exist
-//   - Step 5: Merge commands, environment, group, and timeout settings from overrides
+//   - Step 5: Merge commands, environment, group, timeout, and pre-release settings from overrides
+//   - Step 6: Clear the timeout when the runtime NoTimeout flag (--no-timeout) is set
 //
 // Parameters:
 //   - p: The package to resolve configuration for
@@ -54,8 +55,15 @@ func ResolveUpdateCfg(p formats.Package, cfg *config.Config) (*config.UpdateCfg,
 			if override.Update.TimeoutSeconds != nil {
 				effective.TimeoutSeconds = *override.Update.TimeoutSeconds
 			}
+			if override.Update.AllowPrerelease != nil {
+				effective.AllowPrerelease = *override.Update.AllowPrerelease
+			}
 		}
 	}
 
+	if cfg.NoTimeout {
+		effective.TimeoutSeconds = 0
+	}
+
 	return &effective, nil
 }
@@ -0,0 +1,105 @@
+package update
+
+import (
+	stderrors "errors"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+)
+
+// severityRank orders severities so the most severe of several matches wins.
+var severityRank = map[string]int{
+	config.SeverityIgnore: 0,
+	config.SeverityWarn:   1,
+	config.SeverityError:  2,
+}
+
+// failureSeverity returns the severity of a recorded failure.
+//
+// It performs the following operations:
+//   - Step 1: Use the most severe status of the results whose error the failure wraps
+//   - Step 2: Otherwise treat it as a Failed status of the rule whose outcome recorded it
+//   - Step 3: Otherwise count it as an error
+//
+// Parameters:
+//   - failure: An error from ctx.Failures
+//   - results: Final update results
+//   - ctx: Update context holding the config and rule outcomes
+//
+// Returns:
+//   - string: config.SeverityError, config.SeverityWarn, or config.SeverityIgnore
+func failureSeverity(failure error, results []UpdateResult, ctx *UpdateContext) string {
+	severity, matched := "", false
+	for _, res := range results {
+		if res.Err == nil || !stderrors.Is(failure, res.Err) {
+			continue
+		}
+		s := ctx.Cfg.StatusSeverity(res.Pkg.Rule, res.Status)
+		if !matched || severityRank[s] > severityRank[severity] {
+			severity, matched = s, true
+		}
+	}
+	if matched {
+		return severity
+	}
+
+	for _, outcome := range ctx.RuleOutcomes {
+		for _, ruleFailure := range outcome.Failures {
+			if stderrors.Is(failure, ruleFailure) {
+				return ctx.Cfg.StatusSeverity(outcome.Rule, constants.StatusFailed)
+			}
+		}
+	}
+	return config.SeverityError
+}
+
+// classifyFailures splits the recorded failures by severity.
+//
+// Parameters:
+//   - results: Final update results
+//   - ctx: Update context holding failures, the config, and rule outcomes
+//
+// Returns:
+//   - failures: Failures that count towards the exit code
+//   - warnings: Failures mapped to config.SeverityWarn; ignored failures are dropped
+func classifyFailures(results []UpdateResult, ctx *UpdateContext) (failures, warnings []error) {
+	for _, failure := range ctx.Failures {
+		switch failureSeverity(failure, results, ctx) {
+		case config.SeverityWarn:
+			warnings = append(warnings, failure)
+		case config.SeverityIgnore:
+		default:
+			failures = append(failures, failure)
+		}
+	}
+	return failures, warnings
+}
+
+// countSucceededRules counts the rules that updated packages without a failure
+// that counts as an error.
+//
+// Parameters:
+//   - results: Final update results
+//   - ctx: Update context holding the config and rule outcomes
+//
+// Returns:
+//   - int: Number of rules with successes whose failures are all warnings or ignored
+func countSucceededRules(results []UpdateResult, ctx *UpdateContext) int {
+	count := 0
+	for _, outcome := range ctx.RuleOutcomes {
+		if outcome.Succeeded == 0 {
+			continue
+		}
+		succeeded := true
+		for _, failure := range outcome.Failures {
+			if failureSeverity(failure, results, ctx) == config.SeverityError {
+				succeeded = false
+				break
+			}
+		}
+		if succeeded {
+			count++
+		}
+	}
+	return count
+}
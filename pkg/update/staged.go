@@ -0,0 +1,233 @@
+package update
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/outdated"
+	"github.com/ajxudir/goupdate/pkg/utils"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// StagedVersions returns the releases a staged update walks through.
+//
+// The steps are the available versions newer than the current version up to
+// and including the planned target, in ascending order. Pre-releases are only
+// included when the target itself is a pre-release.
+//
+// Parameters:
+//   - plan: The planned update; its Res.Available holds the candidates from the version lister
+//
+// Returns:
+//   - []string: Versions to apply one at a time, ending with the target; nil when there is no target
+//
+// Example:
+//
+//	// installed 1.0.0, available [1.3.0 1.1.0 1.2.0 2.0.0], target 1.3.0
+//	update.StagedVersions(plan) // ["1.1.0", "1.2.0", "1.3.0"]
+func StagedVersions(plan *PlannedUpdate) []string {
+	target := plan.Res.Target
+	if target == "" {
+		return nil
+	}
+
+	current := outdated.CurrentVersionForOutdated(plan.Res.Pkg)
+	allowPrerelease := utils.IsPreReleaseVersion(target)
+	seen := map[string]bool{target: true}
+	steps := make([]string, 0, len(plan.Res.Available)+1)
+
+	for _, candidate := range plan.Res.Available {
+		if seen[candidate] || (!allowPrerelease && utils.IsPreReleaseVersion(candidate)) {
+			continue
+		}
+		newer, err := outdated.CompareVersions(candidate, current, plan.Versioning)
+		if err != nil {
+			return []string{target}
+		}
+		notPastTarget, _ := outdated.CompareVersions(candidate, target, plan.Versioning)
+		if newer > 0 && notPastTarget < 0 {
+			seen[candidate] = true
+			steps = append(steps, candidate)
+		}
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		cmp, _ := outdated.CompareVersions(steps[i], steps[j], plan.Versioning)
+		return cmp < 0
+	})
+	return append(steps, target)
+}
+
+// processGroupStaged processes a group by walking each package through its staged versions.
+//
+// It performs the following operations:
+//   - Step 1: For each package, apply the next staged version with its own lock command and workspace sync
+//   - Step 2: Validate the package and run system tests after each step if configured
+//   - Step 3: Report each successful step through OnResultReady
+//   - Step 4: On the first failing step, restore the last good version and stop that package
+//   - Step 5: Append one final result per package
+//
+// A package that failed after at least one good step keeps that step: its
+// result is Updated at the last good version, with Err describing the failed
+// step, and the failure is recorded on the context. There is no group-wide
+// rollback in staged mode.
+//
+// Parameters:
+//   - ctx: Update context with configuration and state
+//   - plans: Planned updates for packages in this group
+//   - results: Pointer to results slice to append update results
+//   - systemTestFailures: Pointer to slice collecting system test failures
+//   - progress: Progress reporter to increment after each package; may be nil
+//   - callbacks: Callbacks for result display and unsupported reason derivation
+//
+// Returns:
+//   - error: Combined error of the failed steps; nil if every package reached its target
+func processGroupStaged(ctx *UpdateContext, plans []*PlannedUpdate, results *[]UpdateResult, systemTestFailures *[]SystemTestFailure, progress ProgressReporter, callbacks ExecutionCallbacks) error {
+	var groupErr error
+
+	for _, plan := range plans {
+		if ctx.checkCancelled() != nil {
+			return groupErr
+		}
+
+		stop := false
+		res := &plan.Res
+		if ShouldSkipUpdate(res) {
+			handleSkippedUpdate(ctx, res, results, callbacks)
+		} else {
+			stepErr := applyStagedSteps(ctx, plan, systemTestFailures, callbacks)
+			*results = append(*results, *res)
+			if stepErr != nil && !errors.IsUnsupported(stepErr) {
+				groupErr = stderrors.Join(groupErr, stepErr)
+				// Stop on first error unless ContinueOnError is set
+				stop = !ctx.ContinueOnError
+			}
+		}
+
+		advanceProgress(progress, res.Pkg.Name)
+		if stop {
+			return groupErr
+		}
+	}
+
+	return groupErr
+}
+
+// applyStagedSteps walks a single package through its staged versions.
+//
+// Parameters:
+//   - ctx: Update context with configuration and state
+//   - plan: The planned update; Res holds the final result afterwards
+//   - systemTestFailures: Pointer to slice collecting system test failures
+//   - callbacks: Callbacks for result display and unsupported reason derivation
+//
+// Returns:
+//   - error: The error of the failing step, or nil when the target was reached
+func applyStagedSteps(ctx *UpdateContext, plan *PlannedUpdate, systemTestFailures *[]SystemTestFailure, callbacks ExecutionCallbacks) error {
+	original := plan.Original
+	defer func() { plan.Original = original }()
+
+	steps := StagedVersions(plan)
+	verbose.Debugf("Staged update for %s: %v", plan.Res.Pkg.Name, steps)
+
+	// Pre-update drift check: verify package is at expected original version
+	if !ctx.DryRun {
+		if current, ok := reloadedDeclaredVersion(plan.Res.Pkg, ctx.ReloadList); ok && !versionsMatch(current, original) {
+			verbose.Printf("Pre-update drift DETECTED: %s changed %s → %s (external modification?)\n", plan.Res.Pkg.Name, original, current)
+			original = current
+			plan.Original = current
+		}
+	}
+
+	var lastGood *UpdateResult
+	for _, step := range steps {
+		// Each step starts from the last good version, so rollbacks restore it
+		if lastGood != nil {
+			if cancelErr := ctx.checkCancelled(); cancelErr != nil {
+				return finishStagedFailure(ctx, plan, lastGood, step, cancelErr, true, callbacks)
+			}
+			plan.Original = lastGood.Target
+		}
+		plan.Res.Target = step
+
+		start := time.Now()
+		updateErr := ApplyPlannedUpdate(plan, ctx.Cfg, ctx.WorkDir, ctx.UpdaterFunc, ctx.DryRun, ctx.SkipLockRun)
+		plan.Res.Duration += time.Since(start)
+		if updateErr != nil {
+			if lastGood == nil {
+				HandleUpdateError(updateErr, &plan.Res, ctx, callbacks.DeriveReason)
+				if callbacks.OnResultReady != nil {
+					callbacks.OnResultReady(plan.Res, ctx.DryRun)
+				}
+				return updateErr
+			}
+			return finishStagedFailure(ctx, plan, lastGood, step, updateErr, false, callbacks)
+		}
+
+		if !ctx.DryRun {
+			runWorkspaceSync(ctx, []*PlannedUpdate{plan})
+			if validateErr := ctx.validateUpdate(plan); validateErr != nil {
+				_ = RollbackPlans([]*PlannedUpdate{plan}, ctx.Cfg, ctx.WorkDir, ctx, validateErr, ctx.UpdaterFunc, ctx.DryRun, ctx.SkipLockRun)
+				return finishStagedFailure(ctx, plan, lastGood, step, validateErr, false, callbacks)
+			}
+		}
+
+		plan.Res.Status = constants.StatusUpdated
+		plan.Res.Err = nil
+
+		if ctx.ShouldRunSystemTestsAfterEach() {
+			if testErr := runPackageSystemTests(ctx, plan, systemTestFailures); testErr != nil {
+				return finishStagedFailure(ctx, plan, lastGood, step, testErr, true, callbacks)
+			}
+		}
+
+		RefreshAvailableVersions(plan)
+		good := plan.Res
+		lastGood = &good
+		if callbacks.OnResultReady != nil {
+			callbacks.OnResultReady(plan.Res, ctx.DryRun)
+		}
+	}
+
+	return nil
+}
+
+// finishStagedFailure records a failed step and restores the package result.
+//
+// Parameters:
+//   - ctx: Update context for failure tracking
+//   - plan: The planned update being staged
+//   - lastGood: Result of the last successful step, or nil when no step succeeded
+//   - step: The version that failed
+//   - stepErr: Why the step failed
+//   - recorded: Whether stepErr was already appended to ctx.Failures
+//   - callbacks: Callbacks for result display
+//
+// Returns:
+//   - error: stepErr
+func finishStagedFailure(ctx *UpdateContext, plan *PlannedUpdate, lastGood *UpdateResult, step string, stepErr error, recorded bool, callbacks ExecutionCallbacks) error {
+	res := &plan.Res
+	res.Target = step
+	res.Status = constants.StatusFailed
+	res.Err = stepErr
+
+	if !recorded {
+		ctx.AppendFailure(fmt.Errorf("%s (%s/%s): %w", res.Pkg.Name, res.Pkg.PackageType, res.Pkg.Rule, stepErr))
+	}
+	if callbacks.OnResultReady != nil {
+		callbacks.OnResultReady(*res, ctx.DryRun)
+	}
+
+	if lastGood != nil {
+		verbose.Printf("Staged update of %s stopped at %s; keeping %s\n", res.Pkg.Name, step, lastGood.Target)
+		duration := res.Duration
+		*res = *lastGood
+		res.Duration = duration
+		res.Err = fmt.Errorf("staged update stopped at %s: %w", step, stepErr)
+	}
+	return stepErr
+}
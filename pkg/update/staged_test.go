@@ -0,0 +1,116 @@
+package update
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// TestStagedVersions tests selecting the releases a staged update walks through.
+//
+// It verifies:
+//   - Versions between the installed version and the target are returned in ascending order
+//   - Versions past the target, duplicates, and pre-releases are skipped
+//   - Pre-releases are kept when the target is a pre-release
+//   - A plan without a target has no steps
+func TestStagedVersions(t *testing.T) {
+	plan := &PlannedUpdate{Res: UpdateResult{
+		Pkg:       testutil.NPMPackage("react", "1.0.0", "1.0.0"),
+		Target:    "1.3.0",
+		Available: []string{"2.0.0", "1.3.0", "1.1.0", "1.2.0-beta.1", "1.2.0", "1.1.0", "0.9.0"},
+	}}
+	assert.Equal(t, []string{"1.1.0", "1.2.0", "1.3.0"}, StagedVersions(plan))
+
+	plan.Res.Target = "1.2.0-beta.1"
+	assert.Equal(t, []string{"1.1.0", "1.2.0-beta.1"}, StagedVersions(plan))
+
+	plan.Res.Target = ""
+	assert.Nil(t, StagedVersions(plan))
+}
+
+// TestProcessGroupedPlansStaged tests staged execution of grouped plans.
+//
+// It verifies:
+//   - Every step is applied in order and reported through OnResultReady
+//   - The first failing step is reported as failed and stops the package
+//   - The final result keeps the last good version and explains the failed step
+//   - A package whose steps all pass ends at its target
+func TestProcessGroupedPlansStaged(t *testing.T) {
+	var applied []string
+	updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		applied = append(applied, p.Name+"@"+target)
+		if p.Name == "react" && target == "1.3.0" {
+			return errors.New("lock failed")
+		}
+		return nil
+	}
+
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+	ctx := NewUpdateContext(cfg, "/test", nil).
+		WithUpdaterFunc(updater).
+		WithFlags(false, true, false).
+		WithStaged(true)
+
+	plans := []*PlannedUpdate{
+		{
+			Res: UpdateResult{
+				Pkg:       testutil.NPMPackage("react", "1.0.0", "1.0.0"),
+				Target:    "1.3.0",
+				Status:    constants.StatusPlanned,
+				Available: []string{"1.3.0", "1.2.0", "1.1.0"},
+			},
+			Original: "1.0.0",
+			GroupKey: "npm:js",
+		},
+		{
+			Res: UpdateResult{
+				Pkg:       testutil.NPMPackage("lodash", "4.0.0", "4.0.0"),
+				Target:    "4.2.0",
+				Status:    constants.StatusPlanned,
+				Available: []string{"4.2.0", "4.1.0"},
+			},
+			Original: "4.0.0",
+			GroupKey: "npm:js",
+		},
+	}
+
+	var rows []string
+	callbacks := ExecutionCallbacks{
+		DeriveReason: func(p formats.Package, cfg *config.Config, err error, latestMissing bool) string { return "" },
+		OnResultReady: func(res UpdateResult, dryRun bool) {
+			rows = append(rows, res.Pkg.Name+"@"+res.Target+" "+res.Status)
+		},
+	}
+
+	var results []UpdateResult
+	ProcessGroupedPlansLive(ctx, plans, &results, callbacks)
+
+	assert.Equal(t, []string{"react@1.1.0", "react@1.2.0", "react@1.3.0", "lodash@4.1.0", "lodash@4.2.0"}, applied)
+	assert.Equal(t, []string{
+		"react@1.1.0 " + constants.StatusUpdated,
+		"react@1.2.0 " + constants.StatusUpdated,
+		"react@1.3.0 " + constants.StatusFailed,
+		"lodash@4.1.0 " + constants.StatusUpdated,
+		"lodash@4.2.0 " + constants.StatusUpdated,
+	}, rows)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "1.2.0", results[0].Target)
+	assert.Equal(t, constants.StatusUpdated, results[0].Status)
+	require.Error(t, results[0].Err)
+	assert.Contains(t, results[0].Err.Error(), "staged update stopped at 1.3.0")
+	assert.Equal(t, "4.2.0", results[1].Target)
+	assert.Equal(t, constants.StatusUpdated, results[1].Status)
+	assert.NoError(t, results[1].Err)
+
+	require.Len(t, ctx.Failures, 1)
+	assert.Contains(t, ctx.Failures[0].Error(), "lock failed")
+	assert.Equal(t, "1.0.0", plans[0].Original, "the original version is restored on the plan")
+}
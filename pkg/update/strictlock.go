@@ -0,0 +1,59 @@
+package update
+
+import (
+	"fmt"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// tracksLockFile reports whether p's rule keeps installed versions in a separate lock file.
+//
+// Rules without lock files and self-pinning rules (the manifest is the lock
+// file) have nothing a lock command could leave stale.
+//
+// Parameters:
+//   - cfg: The global configuration
+//   - p: The updated package
+//
+// Returns:
+//   - bool: true if the rule configures lock files and is not self-pinning
+func tracksLockFile(cfg *config.Config, p formats.Package) bool {
+	if cfg == nil {
+		return false
+	}
+	ruleCfg, ok := cfg.Rules[p.Rule]
+	return ok && len(ruleCfg.LockFiles) > 0 && !ruleCfg.SelfPinning
+}
+
+// checkLockAdvanced enforces --strict-lock for an applied plan.
+//
+// The drift check only compares installed versions it can find; a lock file the
+// lock command left without the package, or the reload could not resolve, passes
+// it. In strict mode the reloaded installed version must be known and equal to
+// the target.
+//
+// Parameters:
+//   - plan: The applied plan, holding the reloaded package
+//
+// Returns:
+//   - error: Describes the stale lock file; nil when strict mode is off or the lock advanced
+func (ctx *UpdateContext) checkLockAdvanced(plan *PlannedUpdate) error {
+	if !ctx.StrictLock || ctx.SkipLockRun || !tracksLockFile(ctx.Cfg, plan.Res.Pkg) {
+		return nil
+	}
+
+	installed := plan.Res.Pkg.InstalledVersion
+	if !knownInstalled(installed) {
+		verbose.Printf("Strict lock check FAILED: %s has no installed version after the lock command\n", plan.Res.Pkg.Name)
+		return fmt.Errorf("lock file not updated: no installed version of %s found, expected %s (--strict-lock)", plan.Res.Pkg.Name, plan.Res.Target)
+	}
+	if !versionsMatch(installed, plan.Res.Target) {
+		verbose.Printf("Strict lock check FAILED: %s installed=%s, expected %s\n", plan.Res.Pkg.Name, installed, plan.Res.Target)
+		return fmt.Errorf("lock file not updated: %s installed version is %s, expected %s (--strict-lock)", plan.Res.Pkg.Name, installed, plan.Res.Target)
+	}
+
+	verbose.Debugf("Strict lock check: %s installed %s ✓", plan.Res.Pkg.Name, installed)
+	return nil
+}
@@ -0,0 +1,149 @@
+package update
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// strictLockRule returns an npm rule that keeps installed versions in package-lock.json.
+func strictLockRule() config.PackageManagerCfg {
+	rule := testutil.NPMRule()
+	rule.LockFiles = []config.LockFileCfg{{Files: []string{"**/package-lock.json"}}}
+	return rule
+}
+
+// TestTracksLockFile tests which rules have a lock file --strict-lock can check.
+//
+// It verifies:
+//   - Rules with lock files are checked
+//   - Rules without lock files, self-pinning rules and unknown rules are not
+func TestTracksLockFile(t *testing.T) {
+	pkg := testutil.NPMPackage("react", "18.0.0", "18.0.0")
+
+	cfg := testutil.NewConfig().WithRule("npm", strictLockRule()).Build()
+	assert.True(t, tracksLockFile(cfg, pkg))
+
+	selfPinning := strictLockRule()
+	selfPinning.SelfPinning = true
+	assert.False(t, tracksLockFile(testutil.NewConfig().WithRule("npm", selfPinning).Build(), pkg))
+	assert.False(t, tracksLockFile(testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build(), pkg))
+	assert.False(t, tracksLockFile(testutil.NewConfig().Build(), pkg))
+	assert.False(t, tracksLockFile(nil, pkg))
+}
+
+// TestValidateUpdateStrictLock tests the --strict-lock check after a lock command.
+//
+// It verifies:
+//   - A package missing from the lock file passes the drift check but fails in strict mode
+//   - A lock file at the target version passes in strict mode
+//   - --skip-lock disables the check
+func TestValidateUpdateStrictLock(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("npm", strictLockRule()).Build()
+
+	newCtx := func(installed string) (*UpdateContext, *PlannedUpdate) {
+		pkg := testutil.NPMPackage("react", "17.0.0", "17.0.0")
+		plan := &PlannedUpdate{Res: UpdateResult{Pkg: pkg, Target: "18.0.0", Status: constants.StatusPlanned}}
+		reloaded := pkg
+		reloaded.Version, reloaded.InstalledVersion = "18.0.0", installed
+		ctx := NewUpdateContext(cfg, ".", nil).
+			WithReloadList(func() ([]formats.Package, error) { return []formats.Package{reloaded}, nil })
+		return ctx, plan
+	}
+
+	t.Run("missing installed version fails in strict mode", func(t *testing.T) {
+		ctx, plan := newCtx(constants.PlaceholderNA)
+		require.NoError(t, ctx.validateUpdate(plan))
+
+		ctx, plan = newCtx(constants.PlaceholderNA)
+		ctx.WithStrictLock(true)
+		err := ctx.validateUpdate(plan)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lock file not updated: no installed version of react found, expected 18.0.0")
+	})
+
+	t.Run("lock file at target passes", func(t *testing.T) {
+		ctx, plan := newCtx("18.0.0")
+		ctx.WithStrictLock(true)
+		require.NoError(t, ctx.validateUpdate(plan))
+	})
+
+	t.Run("skip lock disables the check", func(t *testing.T) {
+		ctx, plan := newCtx(constants.PlaceholderNA)
+		ctx.WithStrictLock(true).WithFlags(false, false, true)
+		require.NoError(t, ctx.validateUpdate(plan))
+	})
+}
+
+// TestProcessGroupedPlansStrictLock tests that a stale lock file fails and rolls back the group.
+//
+// It verifies:
+//   - Every package of the group is marked failed when one is missing from the lock file
+//   - The applied manifest changes are rolled back
+func TestProcessGroupedPlansStrictLock(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("npm", strictLockRule()).Build()
+
+	locked := false
+	originalExec := execCommandFunc
+	execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		locked = true
+		return nil, nil
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	var calls []string
+	updater := func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+		calls = append(calls, p.Name+"@"+target)
+		return nil
+	}
+
+	react := testutil.NPMPackage("react", "17.0.0", "17.0.0")
+	vue := testutil.NPMPackage("vue", "2.0.0", "2.0.0")
+	// The lock command writes react to the lock file but leaves vue out
+	reload := func() ([]formats.Package, error) {
+		if !locked {
+			return []formats.Package{react, vue}, nil
+		}
+		r, v := react, vue
+		r.Version, r.InstalledVersion = "18.0.0", "18.0.0"
+		v.Version, v.InstalledVersion = "3.0.0", constants.PlaceholderNA
+		return []formats.Package{r, v}, nil
+	}
+
+	ctx := NewUpdateContext(cfg, "/test", nil).
+		WithUpdaterFunc(updater).
+		WithReloadList(reload).
+		WithStrictLock(true).
+		WithFlags(false, false, false)
+	plans := []*PlannedUpdate{
+		{
+			Res:      UpdateResult{Pkg: react, Target: "18.0.0", Status: constants.StatusPlanned},
+			Cfg:      &config.UpdateCfg{Commands: "npm install"},
+			Original: "17.0.0",
+			GroupKey: "npm:js:frontend",
+		},
+		{
+			Res:      UpdateResult{Pkg: vue, Target: "3.0.0", Status: constants.StatusPlanned},
+			Cfg:      &config.UpdateCfg{Commands: "npm install"},
+			Original: "2.0.0",
+			GroupKey: "npm:js:frontend",
+		},
+	}
+	var results []UpdateResult
+
+	ProcessGroupedPlansLive(ctx, plans, &results, ExecutionCallbacks{DeriveReason: func(formats.Package, *config.Config, error, bool) string { return "" }})
+
+	require.Len(t, results, 2)
+	for _, plan := range plans {
+		assert.Equal(t, constants.StatusFailed, plan.Res.Status, plan.Res.Pkg.Name)
+	}
+	assert.Contains(t, plans[1].Res.Err.Error(), "lock file not updated")
+	assert.Contains(t, calls, "react@17.0.0", "group is rolled back")
+	assert.Contains(t, calls, "vue@2.0.0", "group is rolled back")
+}
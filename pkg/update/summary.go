@@ -0,0 +1,134 @@
+package update
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/errors"
+)
+
+// UnsupportedCounter reports how many packages were tracked as unsupported.
+//
+// *supervision.UnsupportedTracker implements it; a nil tracker counts zero.
+type UnsupportedCounter interface {
+	TotalPackages() int
+}
+
+// BuildSummary computes what happened during an update run.
+//
+// It performs the following operations:
+//   - Step 1: Count updated and planned packages as updated
+//   - Step 2: Split recorded failures by the config's severity mapping and count unsupported packages
+//   - Step 3: Mark the run partial when --continue-on-fail kept successes or whole rules succeeded
+//   - Step 4: Derive the exit code from the counts under the --fail-on policy
+//   - Step 5: Record warned failures and, when it differs from the default, the severity mapping
+//
+// Only failures whose status maps to config.SeverityError count as failed;
+// without a severity config every failure does.
+//
+// Parameters:
+//   - results: Final update results
+//   - ctx: Update context holding failures and rule outcomes; may be nil
+//   - unsupported: Tracker of unsupported packages; may be nil
+//   - continueOnFail: Whether --continue-on-fail was set
+//   - policy: The --fail-on policy; empty selects errors.FailOnPartial
+//
+// Returns:
+//   - errors.Summary: Counts, partial reason, failures, and exit code of the run
+func BuildSummary(results []UpdateResult, ctx *UpdateContext, unsupported UnsupportedCounter, continueOnFail bool, policy errors.FailOnPolicy) errors.Summary {
+	if ctx == nil {
+		ctx = &UpdateContext{}
+	}
+
+	updated := 0
+	for _, res := range results {
+		if res.Status == constants.StatusUpdated || res.Status == constants.StatusPlanned {
+			updated++
+		}
+	}
+
+	failures, warnings := classifyFailures(results, ctx)
+	outcome := errors.RunOutcome{
+		Succeeded:   updated,
+		Failed:      len(failures),
+		Unsupported: countUnsupported(results, unsupported),
+	}
+
+	var partialReason string
+	if outcome.Failed > 0 {
+		if updated > 0 && continueOnFail {
+			outcome.Partial = true
+			partialReason = "partial failure with --continue-on-fail"
+		} else if succeededRules := countSucceededRules(results, ctx); succeededRules > 0 {
+			outcome.Partial = true
+			partialReason = fmt.Sprintf("partial failure: %d of %d rules succeeded", succeededRules, len(ctx.RuleOutcomes))
+		}
+	}
+
+	summary := errors.NewSummary(outcome, partialReason, failures, policy)
+	summary.Warned = len(warnings)
+	summary.Warnings = warnings
+	if ctx.Cfg.HasSeverityOverrides() {
+		for _, entry := range ctx.Cfg.EffectiveSeverities() {
+			summary.Severity = append(summary.Severity, entry.String())
+		}
+	}
+	return summary
+}
+
+// countUnsupported counts the packages that cannot be updated automatically.
+//
+// Results are counted like the update summary does; the tracker is used when
+// the unsupported packages never reached planning (e.g. filtered out by
+// --only-security).
+//
+// Parameters:
+//   - results: Final update results
+//   - unsupported: Tracker of unsupported packages; may be nil
+//
+// Returns:
+//   - int: Number of unsupported packages
+func countUnsupported(results []UpdateResult, unsupported UnsupportedCounter) int {
+	count := 0
+	for _, res := range results {
+		if ShouldTrackUnsupported(res.Status) || (res.Err != nil && errors.IsUnsupported(res.Err)) {
+			count++
+		}
+	}
+	if count == 0 && unsupported != nil {
+		count = unsupported.TotalPackages()
+	}
+	return count
+}
+
+// CountFailedPackages counts the packages behind the failures of a run.
+//
+// A failure wrapping the errors of several results, such as a group lock
+// failure, counts each package once, and a package failing more than once
+// counts once. Failures matching no result, such as an after-all system test
+// failure, count one each.
+//
+// Parameters:
+//   - results: Final update results
+//   - failures: Failures that count towards the exit code (see errors.Summary)
+//
+// Returns:
+//   - int: Number of distinct failed packages plus unattributed failures
+func CountFailedPackages(results []UpdateResult, failures []error) int {
+	packages := make(map[string]bool)
+	unattributed := 0
+	for _, failure := range failures {
+		matched := false
+		for _, res := range results {
+			if res.Err != nil && stderrors.Is(failure, res.Err) {
+				packages[PackageKey(res.Pkg)+"|"+res.Pkg.Source] = true
+				matched = true
+			}
+		}
+		if !matched {
+			unattributed++
+		}
+	}
+	return len(packages) + unattributed
+}
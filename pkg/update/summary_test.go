@@ -0,0 +1,194 @@
+package update
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/lock"
+	"github.com/ajxudir/goupdate/pkg/supervision"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// TestBuildSummary tests computing the summary of an update run.
+//
+// It verifies:
+//   - Updated and planned packages count as updated
+//   - Failures come from the context, unsupported packages from the results
+//   - A run is partial with --continue-on-fail or when a whole rule succeeded
+//   - The exit code follows the --fail-on policy
+func TestBuildSummary(t *testing.T) {
+	failure := fmt.Errorf("vue: lock failed")
+	results := []UpdateResult{
+		{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Status: constants.StatusUpdated},
+		{Pkg: testutil.NPMPackage("lodash", "4.0.0", "4.0.0"), Status: constants.StatusPlanned},
+		{Pkg: testutil.NPMPackage("vue", "2.0.0", "2.0.0"), Status: constants.StatusFailed, Err: failure},
+		{Pkg: testutil.NPMPackage("left-pad", "*", "1.0.0"), Status: lock.InstallStatusFloating},
+		{Pkg: testutil.NPMPackage("jquery", "3.0.0", "3.0.0"), Status: constants.StatusUpToDate},
+	}
+
+	tests := []struct {
+		name           string
+		ctx            *UpdateContext
+		continueOnFail bool
+		policy         errors.FailOnPolicy
+		wantPartial    string
+		wantExit       int
+	}{
+		{
+			name:     "failure without partial success",
+			ctx:      &UpdateContext{Failures: []error{failure}},
+			wantExit: errors.ExitFailure,
+		},
+		{
+			name:           "continue on fail",
+			ctx:            &UpdateContext{Failures: []error{failure}},
+			continueOnFail: true,
+			wantPartial:    "partial failure with --continue-on-fail",
+			wantExit:       errors.ExitPartialFailure,
+		},
+		{
+			name: "another rule succeeded",
+			ctx: &UpdateContext{Failures: []error{failure}, RuleOutcomes: []RuleOutcome{
+				{Rule: "npm", Succeeded: 2},
+				{Rule: "composer", Failures: []error{failure}},
+			}},
+			wantPartial: "partial failure: 1 of 2 rules succeeded",
+			wantExit:    errors.ExitPartialFailure,
+		},
+		{
+			name:     "failures ignored",
+			ctx:      &UpdateContext{Failures: []error{failure}},
+			policy:   errors.FailOnNone,
+			wantExit: errors.ExitSuccess,
+		},
+		{
+			name:     "unsupported without failures",
+			ctx:      nil,
+			policy:   errors.FailOnAnyUnsupported,
+			wantExit: errors.ExitConfigError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := BuildSummary(results, tt.ctx, nil, tt.continueOnFail, tt.policy)
+
+			assert.Equal(t, 2, summary.Updated)
+			assert.Equal(t, 1, summary.Unsupported)
+			assert.Equal(t, tt.wantPartial, summary.PartialReason)
+			assert.Equal(t, tt.wantPartial != "", summary.Partial)
+			assert.Equal(t, tt.wantExit, summary.ExitCode)
+			if tt.ctx != nil {
+				assert.Equal(t, len(tt.ctx.Failures), summary.Failed)
+				assert.Equal(t, tt.ctx.Failures, summary.Failures)
+			}
+		})
+	}
+}
+
+// TestBuildSummaryUnsupportedTracker tests counting unsupported packages that never reached planning.
+//
+// It verifies:
+//   - The tracker is counted when no result is unsupported
+//   - A nil tracker counts zero
+func TestBuildSummaryUnsupportedTracker(t *testing.T) {
+	tracker := supervision.NewUnsupportedTracker()
+	tracker.Add(formats.Package{Name: "private-lib", Rule: "npm", PackageType: "js"}, "non-registry source")
+
+	summary := BuildSummary(nil, nil, tracker, false, errors.FailOnAnyUnsupported)
+	assert.Equal(t, 1, summary.Unsupported)
+	assert.Equal(t, errors.ExitConfigError, summary.ExitCode)
+
+	var nilTracker *supervision.UnsupportedTracker
+	summary = BuildSummary(nil, nil, nilTracker, false, errors.FailOnAnyUnsupported)
+	assert.Equal(t, 0, summary.Unsupported)
+	assert.True(t, summary.Passed())
+}
+
+// TestBuildSummarySeverity tests the severity config when deriving the exit code.
+//
+// It verifies:
+//   - A rule's Failed status mapped to warn keeps its failure out of the failed count and exit code
+//   - Warned failures are counted and listed, and the effective mapping is recorded
+//   - Ignored failures are dropped entirely
+//   - A rule whose failures are only warnings counts as succeeded for partial success
+//   - Failures of other rules and failures matching no result still count as errors
+//   - The default mapping leaves Severity empty
+func TestBuildSummarySeverity(t *testing.T) {
+	npmErr := fmt.Errorf("npm ERR! peer conflict")
+	pipErr := fmt.Errorf("pip: resolution failed")
+	npmFailure := fmt.Errorf("vue (js/npm): %w", npmErr)
+	pipFailure := fmt.Errorf("requests (prod/pip): %w", pipErr)
+	lockFailure := fmt.Errorf("group lock failed: boom")
+
+	pipPkg := testutil.NPMPackage("requests", "2.0.0", "2.0.0")
+	pipPkg.Rule = "pip"
+	results := []UpdateResult{
+		{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Status: constants.StatusUpdated},
+		{Pkg: testutil.NPMPackage("vue", "2.0.0", "2.0.0"), Status: constants.StatusFailed, Err: npmErr},
+		{Pkg: pipPkg, Status: constants.StatusFailed, Err: pipErr},
+	}
+	npmWarn := testutil.NewConfig().WithRule("npm", config.PackageManagerCfg{Severity: map[string]string{constants.StatusFailed: config.SeverityWarn}}).Build()
+
+	ctx := &UpdateContext{Cfg: npmWarn, Failures: []error{npmFailure}}
+	summary := BuildSummary(results[:2], ctx, nil, false, "")
+	assert.Equal(t, 0, summary.Failed)
+	assert.Empty(t, summary.Failures)
+	assert.Equal(t, 1, summary.Warned)
+	assert.Equal(t, []error{npmFailure}, summary.Warnings)
+	assert.Equal(t, errors.ExitSuccess, summary.ExitCode)
+	assert.Equal(t, []string{"Failed=error", "ConfigError=error", "SummarizeError=error", "npm:Failed=warn"}, summary.Severity)
+
+	ignore := testutil.NewConfig().WithRule("npm", config.PackageManagerCfg{Severity: map[string]string{constants.StatusFailed: config.SeverityIgnore}}).Build()
+	summary = BuildSummary(results[:2], &UpdateContext{Cfg: ignore, Failures: []error{npmFailure}}, nil, false, "")
+	assert.Equal(t, 0, summary.Failed)
+	assert.Equal(t, 0, summary.Warned)
+	assert.Equal(t, errors.ExitSuccess, summary.ExitCode)
+
+	ctx = &UpdateContext{Cfg: npmWarn, Failures: []error{npmFailure, pipFailure}, RuleOutcomes: []RuleOutcome{
+		{Rule: "npm", Succeeded: 1, Failures: []error{npmFailure}},
+		{Rule: "pip", Failures: []error{pipFailure}},
+	}}
+	summary = BuildSummary(results, ctx, nil, false, "")
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, []error{pipFailure}, summary.Failures)
+	assert.Equal(t, "partial failure: 1 of 2 rules succeeded", summary.PartialReason)
+	assert.Equal(t, errors.ExitPartialFailure, summary.ExitCode)
+
+	ctx = &UpdateContext{Cfg: npmWarn, Failures: []error{lockFailure}, RuleOutcomes: []RuleOutcome{{Rule: "npm", Failures: []error{lockFailure}}}}
+	assert.Equal(t, 0, BuildSummary(results[:1], ctx, nil, false, "").Failed)
+	ctx = &UpdateContext{Cfg: npmWarn, Failures: []error{lockFailure}}
+	assert.Equal(t, 1, BuildSummary(results[:1], ctx, nil, false, "").Failed)
+
+	summary = BuildSummary(results, &UpdateContext{Failures: []error{npmFailure}}, nil, false, "")
+	assert.Equal(t, 1, summary.Failed)
+	assert.Empty(t, summary.Severity)
+}
+
+// TestCountFailedPackages tests counting failed packages instead of failures.
+//
+// It verifies:
+//   - A failure wrapping several results counts each package once
+//   - A package failing more than once counts once
+//   - A failure matching no result counts on its own
+func TestCountFailedPackages(t *testing.T) {
+	reactErr := fmt.Errorf("react: update failed")
+	vueErr := fmt.Errorf("vue: update failed")
+	results := []UpdateResult{
+		{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Status: constants.StatusFailed, Err: reactErr},
+		{Pkg: testutil.NPMPackage("vue", "2.0.0", "2.0.0"), Status: constants.StatusFailed, Err: vueErr},
+		{Pkg: testutil.NPMPackage("jquery", "3.0.0", "3.0.0"), Status: constants.StatusUpToDate},
+	}
+	groupErr := fmt.Errorf("group lock failed: %w", stderrors.Join(reactErr, vueErr))
+
+	assert.Equal(t, 2, CountFailedPackages(results, []error{reactErr, groupErr, vueErr}))
+	assert.Equal(t, 3, CountFailedPackages(results, []error{reactErr, fmt.Errorf("after-all tests failed"), fmt.Errorf("cancelled")}))
+	assert.Zero(t, CountFailedPackages(results, nil))
+}
@@ -0,0 +1,152 @@
+package update
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// allowsTransitive reports whether updating p may also update its transitive dependencies.
+//
+// Transitive updates are allowed by --include-transitive, the rule's
+// include_transitive setting, or with_all_dependencies on the package or its group.
+//
+// Parameters:
+//   - cfg: The global configuration
+//   - p: The package being updated
+//
+// Returns:
+//   - bool: true if the lock command may cascade and untargeted changes are accepted
+func allowsTransitive(cfg *config.Config, p formats.Package) bool {
+	if cfg == nil {
+		return false
+	}
+	if cfg.IncludeTransitive {
+		return true
+	}
+	ruleCfg, ok := cfg.Rules[p.Rule]
+	return ok && ruleCfg.ShouldUpdateWithAllDependencies(p.Name)
+}
+
+// recordTargets remembers which packages this run updates.
+//
+// Packages with a pending update are targeted; every other package listed in the
+// same directory must keep its installed version unless transitive updates are allowed.
+//
+// Parameters:
+//   - plans: All plans of the run
+func (ctx *UpdateContext) recordTargets(plans []*PlannedUpdate) {
+	ctx.targets = make(map[string]bool, len(plans))
+	for _, plan := range plans {
+		if plan.Res.Target != "" && !IsNonUpdatableStatus(plan.Res.Status) {
+			ctx.targets[PackageSourceKey(plan.Res.Pkg)] = true
+		}
+	}
+}
+
+// validateUpdate runs the post-update checks for an applied plan.
+//
+// It performs the following operations:
+//   - Step 1: Reload packages and run the drift check (see ValidateUpdatedPackage)
+//   - Step 2: With --strict-lock, require the lock file to hold the target (see checkLockAdvanced)
+//   - Step 3: Unless transitive updates are allowed, fail when an untargeted package
+//     in the same directory changed its installed version
+//   - Step 4: Record the reloaded versions of that directory as the new baseline
+//
+// Parameters:
+//   - plan: The applied plan to check
+//
+// Returns:
+//   - error: Drift check failure, stale lock file or the first untargeted change; nil when all pass
+func (ctx *UpdateContext) validateUpdate(plan *PlannedUpdate) error {
+	packages, err := reloadAndValidate(plan, ctx.ReloadList)
+	if err != nil || packages == nil {
+		return err
+	}
+
+	if err := ctx.checkLockAdvanced(plan); err != nil {
+		return err
+	}
+
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if !allowsTransitive(ctx.Cfg, plan.Res.Pkg) {
+		if err := ctx.checkUntargetedChanges(plan, packages); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Baseline != nil {
+		for _, p := range packages {
+			if sameDirectory(p, plan.Res.Pkg) {
+				ctx.Baseline[PackageSourceKey(p)] = VersionSnapshot{Version: p.Version, Installed: p.InstalledVersion}
+			}
+		}
+	}
+	return nil
+}
+
+// checkUntargetedChanges compares reloaded packages against the baseline.
+//
+// Only packages of the plan's rule in the same directory are compared, since the
+// lock command cannot change other lock files. Members of a workspace share the
+// root lock file but are still compared per manifest, so an update is never
+// attributed to another member's dependencies. Targeted packages and packages
+// without a known installed version are skipped. The caller must hold ctx.mu.
+//
+// Parameters:
+//   - plan: The applied plan whose lock command ran
+//   - packages: Packages reloaded after the update
+//
+// Returns:
+//   - error: Describes the first untargeted package whose installed version changed
+func (ctx *UpdateContext) checkUntargetedChanges(plan *PlannedUpdate, packages []formats.Package) error {
+	if ctx.Baseline == nil {
+		return nil
+	}
+
+	for _, p := range packages {
+		key := PackageSourceKey(p)
+		if ctx.targets[key] || !sameDirectory(p, plan.Res.Pkg) {
+			continue
+		}
+
+		before, ok := ctx.Baseline[key]
+		if !ok || !knownInstalled(before.Installed) || !knownInstalled(p.InstalledVersion) {
+			continue
+		}
+		if !versionsMatch(before.Installed, p.InstalledVersion) {
+			verbose.Printf("Transitive check FAILED: %s changed %s → %s while updating %s\n", p.Name, before.Installed, p.InstalledVersion, plan.Res.Pkg.Name)
+			return fmt.Errorf("untargeted package %s changed from %s to %s; use --include-transitive or set include_transitive on rule %s to allow it", p.Name, before.Installed, p.InstalledVersion, p.Rule)
+		}
+	}
+	return nil
+}
+
+// sameDirectory reports whether two packages of the same rule come from the same directory.
+//
+// Parameters:
+//   - a: First package
+//   - b: Second package
+//
+// Returns:
+//   - bool: true if both packages share a rule and manifest directory
+func sameDirectory(a, b formats.Package) bool {
+	return a.Rule == b.Rule && filepath.Dir(a.Source) == filepath.Dir(b.Source)
+}
+
+// knownInstalled reports whether an installed version was resolved from a lock file.
+//
+// Parameters:
+//   - version: The installed version
+//
+// Returns:
+//   - bool: false for empty and placeholder versions
+func knownInstalled(version string) bool {
+	return version != "" && version != constants.PlaceholderNA
+}
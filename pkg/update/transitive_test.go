@@ -0,0 +1,154 @@
+package update
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// TestAllowsTransitive tests when lock commands may update transitive dependencies.
+//
+// It verifies:
+//   - A nil config or unknown rule disallows transitive updates
+//   - The --include-transitive runtime flag allows them for every rule
+//   - The rule's include_transitive setting allows them for its packages
+//   - Per-package with_all_dependencies allows them for that package only
+func TestAllowsTransitive(t *testing.T) {
+	pkg := testutil.NPMPackage("react", "^17.0.0", "17.0.2")
+
+	assert.False(t, allowsTransitive(nil, pkg))
+	assert.False(t, allowsTransitive(&config.Config{}, pkg))
+
+	assert.True(t, allowsTransitive(&config.Config{IncludeTransitive: true}, pkg))
+
+	includeTransitive := true
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{"npm": {IncludeTransitive: &includeTransitive}}}
+	assert.True(t, allowsTransitive(cfg, pkg))
+
+	cfg = &config.Config{Rules: map[string]config.PackageManagerCfg{"npm": {
+		Packages: map[string]config.PackageSettings{"react": {WithAllDependencies: true}},
+	}}}
+	assert.True(t, allowsTransitive(cfg, pkg))
+	assert.False(t, allowsTransitive(cfg, testutil.NPMPackage("lodash", "^4.0.0", "4.17.20")))
+}
+
+// TestValidateUpdateUntargetedChanges tests the untargeted-change check after a lock command.
+//
+// It verifies:
+//   - An untargeted package in the same directory that changed its installed version fails validation
+//   - Targeted packages, other directories and #N/A versions are not compared
+//   - --include-transitive accepts the change
+//   - A successful validation refreshes the baseline for later groups
+func TestValidateUpdateUntargetedChanges(t *testing.T) {
+	inDir := func(p formats.Package, source string) formats.Package {
+		p.Source = source
+		return p
+	}
+	react := inDir(testutil.NPMPackage("react", "^17.0.0", "17.0.0"), "app/package.json")
+	lodash := inDir(testutil.NPMPackage("lodash", "^4.17.0", "4.17.20"), "app/package.json")
+	other := inDir(testutil.NPMPackage("lodash", "^4.17.0", "4.17.20"), "lib/package.json")
+	unlocked := inDir(testutil.NPMPackage("chalk", "^5.0.0", constants.PlaceholderNA), "app/package.json")
+	all := []formats.Package{react, lodash, other, unlocked}
+
+	reloaded := func(lodashInstalled string) []formats.Package {
+		r, l, o, u := react, lodash, other, unlocked
+		r.Version, r.InstalledVersion = "17.0.2", "17.0.2"
+		l.InstalledVersion = lodashInstalled
+		o.InstalledVersion = "4.17.21"
+		u.InstalledVersion = "5.3.0"
+		return []formats.Package{r, l, o, u}
+	}
+
+	newCtx := func(cfg *config.Config, after []formats.Package) (*UpdateContext, *PlannedUpdate) {
+		plan := &PlannedUpdate{Res: UpdateResult{Pkg: react, Target: "17.0.2", Status: constants.StatusPlanned}}
+		ctx := NewUpdateContext(cfg, ".", nil).
+			WithBaseline(SnapshotVersions(all)).
+			WithReloadList(func() ([]formats.Package, error) { return after, nil })
+		ctx.recordTargets([]*PlannedUpdate{plan, {Res: UpdateResult{Pkg: lodash, Status: constants.StatusUpToDate}}})
+		return ctx, plan
+	}
+
+	t.Run("fails on untargeted change", func(t *testing.T) {
+		ctx, plan := newCtx(&config.Config{}, reloaded("4.17.21"))
+		err := ctx.validateUpdate(plan)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "untargeted package lodash changed from 4.17.20 to 4.17.21")
+		assert.Contains(t, err.Error(), "--include-transitive")
+	})
+
+	t.Run("passes when only targets changed", func(t *testing.T) {
+		ctx, plan := newCtx(&config.Config{}, reloaded("4.17.20"))
+		require.NoError(t, ctx.validateUpdate(plan))
+		assert.Equal(t, "17.0.2", ctx.Baseline[PackageSourceKey(react)].Installed)
+		assert.Equal(t, "4.17.20", ctx.Baseline[PackageSourceKey(other)].Installed, "other directories keep their baseline")
+	})
+
+	t.Run("include transitive accepts change", func(t *testing.T) {
+		ctx, plan := newCtx(&config.Config{IncludeTransitive: true}, reloaded("4.17.21"))
+		require.NoError(t, ctx.validateUpdate(plan))
+		assert.Equal(t, "4.17.21", ctx.Baseline[PackageSourceKey(lodash)].Installed)
+	})
+
+	t.Run("targeted packages are not compared", func(t *testing.T) {
+		ctx, plan := newCtx(&config.Config{}, reloaded("4.17.21"))
+		ctx.targets[PackageSourceKey(lodash)] = true
+		require.NoError(t, ctx.validateUpdate(plan))
+	})
+}
+
+// TestValidateUpdateSharedDependencyAcrossModules tests modules holding different versions of one dependency.
+//
+// It verifies:
+//   - Each module keeps its own baseline, so an unchanged copy in one module is not compared with another's
+//   - Targeting the dependency in one module does not exempt its copy in another module
+//   - A saved plan for one module's copy is not matched against another module's copy
+func TestValidateUpdateSharedDependencyAcrossModules(t *testing.T) {
+	inModule := func(name, version, source string) formats.Package {
+		return testutil.NewPackage(name).WithRule("mod").WithPackageType("golang").WithVersion(version).WithInstalledVersion(version).WithSource(source).Build()
+	}
+	tool := inModule("example.com/tool", "v1.0.0", "api/go.mod")
+	apiShared := inModule("example.com/shared", "v1.1.0", "api/go.mod")
+	workerShared := inModule("example.com/shared", "v1.0.0", "worker/go.mod")
+	all := []formats.Package{tool, apiShared, workerShared}
+
+	newCtx := func(plan *PlannedUpdate, after []formats.Package) *UpdateContext {
+		ctx := NewUpdateContext(&config.Config{}, ".", nil).
+			WithBaseline(SnapshotVersions(all)).
+			WithReloadList(func() ([]formats.Package, error) { return after, nil })
+		ctx.recordTargets([]*PlannedUpdate{plan})
+		return ctx
+	}
+
+	t.Run("unchanged copy passes", func(t *testing.T) {
+		plan := &PlannedUpdate{Res: UpdateResult{Pkg: tool, Target: "v1.1.0", Status: constants.StatusPlanned}, Original: "v1.0.0"}
+		updatedTool := inModule("example.com/tool", "v1.1.0", "api/go.mod")
+		ctx := newCtx(plan, []formats.Package{updatedTool, apiShared, workerShared})
+		require.NoError(t, ctx.validateUpdate(plan))
+		assert.Equal(t, "v1.1.0", ctx.Baseline[PackageSourceKey(apiShared)].Installed)
+		assert.Equal(t, "v1.0.0", ctx.Baseline[PackageSourceKey(workerShared)].Installed)
+	})
+
+	t.Run("other module's copy stays untargeted", func(t *testing.T) {
+		plan := &PlannedUpdate{Res: UpdateResult{Pkg: tool, Target: "v1.1.0", Status: constants.StatusPlanned}, Original: "v1.0.0"}
+		workerPlan := &PlannedUpdate{Res: UpdateResult{Pkg: workerShared, Target: "v1.2.0", Status: constants.StatusPlanned}, Original: "v1.0.0"}
+		ctx := newCtx(plan, []formats.Package{inModule("example.com/tool", "v1.1.0", "api/go.mod"), inModule("example.com/shared", "v1.2.0", "api/go.mod"), workerShared})
+		ctx.recordTargets([]*PlannedUpdate{plan, workerPlan})
+		err := ctx.validateUpdate(plan)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "untargeted package example.com/shared changed from v1.1.0 to v1.2.0")
+	})
+
+	t.Run("plan drift matches the module", func(t *testing.T) {
+		plan := &PlannedUpdate{Res: UpdateResult{Pkg: apiShared, Target: "v1.2.0", Status: constants.StatusPlanned}, Original: "v1.1.0"}
+		require.NoError(t, CheckPlanDrift([]*PlannedUpdate{plan}, all))
+		err := CheckPlanDrift([]*PlannedUpdate{plan}, []formats.Package{tool, workerShared})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "example.com/shared (golang/mod): no longer found")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	pkgerrors "github.com/ajxudir/goupdate/pkg/errors"
@@ -194,6 +195,37 @@ func TestResolveUpdateCfgOverride(t *testing.T) {
 	assert.Equal(t, overrideGroup, updateCfg.Group)
 }
 
+// TestResolveUpdateCfgTimeout tests timeout resolution in ResolveUpdateCfg.
+//
+// It verifies:
+//   - Per-package timeout_seconds overrides the rule timeout
+//   - The NoTimeout runtime flag clears the timeout
+func TestResolveUpdateCfgTimeout(t *testing.T) {
+	overrideTimeout := 900
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"r": {
+			Update: &config.UpdateCfg{Commands: "base", TimeoutSeconds: 120},
+			PackageOverrides: map[string]config.PackageOverrideCfg{
+				"slow": {Update: &config.UpdateOverrideCfg{TimeoutSeconds: &overrideTimeout}},
+			},
+		},
+	}}
+
+	updateCfg, err := ResolveUpdateCfg(formats.Package{Name: "fast", Rule: "r"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 120, updateCfg.TimeoutSeconds)
+
+	updateCfg, err = ResolveUpdateCfg(formats.Package{Name: "slow", Rule: "r"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 900, updateCfg.TimeoutSeconds)
+
+	cfg.NoTimeout = true
+	updateCfg, err = ResolveUpdateCfg(formats.Package{Name: "slow", Rule: "r"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 0, updateCfg.TimeoutSeconds)
+	assert.Equal(t, 120, cfg.Rules["r"].Update.TimeoutSeconds, "rule config must not be mutated")
+}
+
 // TestResolveUpdateCfgMissingRule tests the behavior of ResolveUpdateCfg with missing rule.
 //
 // It verifies:
@@ -345,6 +377,45 @@ func TestUpdatePackageRunsLockCommand(t *testing.T) {
 	assert.True(t, called)
 }
 
+// TestUpdatePackageGoWorkModule tests updating a module of a go.work workspace.
+//
+// It verifies:
+//   - The lock command runs in the updated module's directory, not the workspace root
+//   - The same dependency in another module of the workspace is left unchanged
+func TestUpdatePackageGoWorkModule(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, writeFile(filepath.Join(root, "go.work"), "go 1.22\n\nuse (\n\t./api\n\t./worker\n)\n"))
+	for _, module := range []string{"api", "worker"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, module), 0o755))
+		require.NoError(t, writeFile(filepath.Join(root, module, "go.mod"), "module example.com/"+module+"\n\nrequire example.com/shared v1.0.0\n"))
+	}
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{"mod": {
+		Format:        "raw",
+		Extraction:    &config.ExtractionCfg{Pattern: `(?m)^(?:\s+|require\s+)(?P<n>[\w\.\-\/]+)\s+(?P<version>v[\w\.\-\+]+)`},
+		Update:        &config.UpdateCfg{Commands: "go mod tidy"},
+		WorkspaceFile: &config.WorkspaceFileCfg{File: "go.work", Format: config.WorkspaceFormatGoWork, Commands: "go work sync"},
+	}}}
+
+	var dirs []string
+	originalExec := execCommandFunc
+	execCommandFunc = func(updateCfg *config.UpdateCfg, pkgName, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		dirs = append(dirs, dir)
+		return nil, nil
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	p := formats.Package{Name: "example.com/shared", Rule: "mod", Version: "v1.0.0", Source: filepath.Join(root, "api", "go.mod")}
+	require.NoError(t, UpdatePackage(p, "v1.1.0", cfg, root, false, false))
+	assert.Equal(t, []string{filepath.Join(root, "api")}, dirs)
+
+	api, err := os.ReadFile(filepath.Join(root, "api", "go.mod"))
+	require.NoError(t, err)
+	assert.Contains(t, string(api), "example.com/shared v1.1.0")
+	worker, err := os.ReadFile(filepath.Join(root, "worker", "go.mod"))
+	require.NoError(t, err)
+	assert.Contains(t, string(worker), "example.com/shared v1.0.0")
+}
+
 // TestUpdatePackageMissingLockCommand tests the behavior of UpdatePackage when lock command is not configured.
 //
 // It verifies:
@@ -359,6 +430,37 @@ func TestUpdatePackageMissingLockCommand(t *testing.T) {
 	require.True(t, pkgerrors.IsUnsupported(err))
 }
 
+// TestUpdatePackageSelfPinningWithoutLockCommand tests UpdatePackage for self-pinning rules.
+//
+// It verifies:
+//   - The manifest is rewritten without running a lock command
+//   - A missing lock command is not reported as unsupported
+func TestUpdatePackageSelfPinningWithoutLockCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "ci.yml")
+	require.NoError(t, writeFile(path, "      - uses: actions/checkout@v4\n"))
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{"github-actions": {
+		Format:      "raw",
+		Extraction:  defaultRawRule(t, "github-actions").Extraction,
+		Update:      &config.UpdateCfg{},
+		SelfPinning: true,
+	}}}
+
+	originalExec := execCommandFunc
+	execCommandFunc = func(*config.UpdateCfg, string, string, string, string, bool) ([]byte, error) {
+		t.Fatal("lock command must not run for self-pinning rules")
+		return nil, nil
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	err := UpdatePackage(formats.Package{Name: "actions/checkout", Rule: "github-actions", Version: "v4", Source: path}, "v5", cfg, tmpDir, false, false)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "actions/checkout@v5")
+}
+
 // TestRollbackOnFailure tests the behavior of rollbackOnFailure.
 //
 // It verifies:
@@ -523,6 +625,31 @@ func TestRunGroupLockCommandFailure(t *testing.T) {
 	require.Error(t, err)
 }
 
+// TestRunGroupLockRateLimit tests that group lock commands wait for the rule's rate limiter.
+//
+// It verifies:
+//   - Each lock directory of a rule with max_requests_per_second waits for a request slot
+func TestRunGroupLockRateLimit(t *testing.T) {
+	originalExec := execCommandFunc
+	var runs []time.Time
+	execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		runs = append(runs, time.Now())
+		return nil, nil
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	rate := 20.0
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{"npm-group-lock-limited": {MaxRequestsPerSecond: &rate}}}
+	plans := []*PlannedUpdate{
+		{Res: UpdateResult{Pkg: formats.Package{Name: "a", Rule: "npm-group-lock-limited", Source: filepath.Join("one", "package.json")}}},
+		{Res: UpdateResult{Pkg: formats.Package{Name: "b", Rule: "npm-group-lock-limited", Source: filepath.Join("two", "package.json")}}},
+	}
+
+	require.NoError(t, runGroupLock(&config.UpdateCfg{Commands: "npm install"}, cfg, ".", plans, false))
+	require.Len(t, runs, 2)
+	assert.GreaterOrEqual(t, runs[1].Sub(runs[0]), 40*time.Millisecond)
+}
+
 // TestResolveUpdateCfgNilUpdate tests the behavior of ResolveUpdateCfg when Update config is nil.
 //
 // It verifies:
@@ -582,6 +709,27 @@ func TestResolveUpdateCfgWithTimeoutOverride(t *testing.T) {
 	assert.Equal(t, 120, updateCfg.TimeoutSeconds)
 }
 
+// TestResolveUpdateCfgWithAllowPrereleaseOverride tests the behavior of ResolveUpdateCfg with allow_prerelease override.
+//
+// It verifies:
+//   - Package-specific allow_prerelease override is applied
+func TestResolveUpdateCfgWithAllowPrereleaseOverride(t *testing.T) {
+	allow := true
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{
+		"r": {
+			Update: &config.UpdateCfg{Commands: "npm install"},
+			PackageOverrides: map[string]config.PackageOverrideCfg{
+				"next-pkg": {
+					Update: &config.UpdateOverrideCfg{AllowPrerelease: &allow},
+				},
+			},
+		},
+	}}
+	updateCfg, err := ResolveUpdateCfg(formats.Package{Name: "next-pkg", Rule: "r"}, cfg)
+	require.NoError(t, err)
+	assert.True(t, updateCfg.AllowPrerelease)
+}
+
 // TestResolveUpdateCfgNilPackageOverrides tests the behavior of ResolveUpdateCfg when PackageOverrides is nil.
 //
 // It verifies:
@@ -1071,7 +1219,22 @@ func TestWriteFilePreservingPermissionsEdgeCases(t *testing.T) {
 //   - Lock file paths are returned from config
 //   - Empty config returns empty list
 //   - Non-existent files are filtered out
+//   - A leading "**/" matches the lock file in the scope directory itself
 func TestGetLockFilePaths(t *testing.T) {
+	t.Run("matches recursive patterns in the scope directory", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		lockFile := filepath.Join(tmpDir, "pnpm-lock.yaml")
+		require.NoError(t, os.WriteFile(lockFile, []byte("lockfileVersion: '9.0'\n"), 0o644))
+
+		ruleCfg := config.PackageManagerCfg{
+			LockFiles: []config.LockFileCfg{
+				{Files: []string{"**/pnpm-lock.yaml"}},
+			},
+		}
+
+		assert.Equal(t, []string{lockFile}, getLockFilePaths(ruleCfg, tmpDir))
+	})
+
 	t.Run("returns lock file paths from config", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		lockFile := filepath.Join(tmpDir, "package-lock.json")
@@ -1110,3 +1273,38 @@ func TestGetLockFilePaths(t *testing.T) {
 		assert.Equal(t, existingFile, paths[0])
 	})
 }
+
+// TestGroupLockDirs tests the directories a group's lock command runs in.
+//
+// It verifies:
+//   - Manifests in different directories get one lock run each
+//   - Workspace members share a single run in the workspace root
+//   - Packages without a manifest path use the working directory
+func TestGroupLockDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"packages/app", "packages/lib"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pnpm-lock.yaml"), []byte("lockfileVersion: '9.0'\n"), 0o644))
+
+	plan := func(dir string) *PlannedUpdate {
+		p := formats.Package{Name: "react", Rule: "pnpm"}
+		if dir != "" {
+			p.Source = filepath.Join(root, dir, "package.json")
+		}
+		return &PlannedUpdate{Res: UpdateResult{Pkg: p}}
+	}
+	plans := []*PlannedUpdate{plan("packages/app"), plan("packages/lib"), plan("packages/app")}
+
+	rule := config.PackageManagerCfg{LockFiles: []config.LockFileCfg{{Files: []string{"**/pnpm-lock.yaml"}}}}
+	cfg := &config.Config{Rules: map[string]config.PackageManagerCfg{"pnpm": rule}}
+	assert.Equal(t, []string{filepath.Join(root, "packages/app"), filepath.Join(root, "packages/lib")}, groupLockDirs(cfg, root, plans))
+	assert.Equal(t, groupLockDirs(cfg, root, plans), groupLockDirs(nil, root, plans))
+
+	workspace := true
+	rule.Workspace = &workspace
+	cfg.Rules["pnpm"] = rule
+	assert.Equal(t, []string{root}, groupLockDirs(cfg, root, plans))
+
+	assert.Equal(t, []string{"."}, groupLockDirs(cfg, "", []*PlannedUpdate{plan("")}))
+}
@@ -0,0 +1,43 @@
+package update
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/formats"
+)
+
+// ExactVersionLister returns a version lister for --update-to that only offers the requested version.
+//
+// It performs the following operations:
+//   - Step 1: Without verification, offer the version without contacting the registry
+//   - Step 2: Otherwise list the package's versions with the wrapped lister
+//   - Step 3: Fail when the version is not among them; offer only the version when it is
+//
+// Parameters:
+//   - list: The lister used to verify the version exists; it should list every
+//     published version, since the requested one may be older than the installed one
+//   - version: The requested target version
+//   - verify: Whether to check the version against the registry (false for --no-verify)
+//
+// Returns:
+//   - VersionLister: A lister returning exactly the requested version
+func ExactVersionLister(list VersionLister, version string, verify bool) VersionLister {
+	return func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		if !verify {
+			return []string{version}, nil
+		}
+
+		versions, err := list(ctx, p, cfg, baseDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			if versionsMatch(v, version) {
+				return []string{v}, nil
+			}
+		}
+		return nil, fmt.Errorf("version %s of %s is not published in the registry (use --no-verify to set it anyway)", version, p.Name)
+	}
+}
@@ -0,0 +1,70 @@
+package update
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// TestExactVersionLister tests the --update-to version lister.
+//
+// It verifies:
+//   - Without verification the version is offered without a registry lookup
+//   - A version found in the registry is offered in the registry's spelling
+//   - A version missing from the registry fails, naming --no-verify
+func TestExactVersionLister(t *testing.T) {
+	lookups := 0
+	registry := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		lookups++
+		return []string{"v1.2.2", "v1.2.3"}, nil
+	}
+	pkg := testutil.NPMPackage("lodash", "1.0.0", "1.0.0")
+
+	versions, err := ExactVersionLister(registry, "9.9.9", false)(context.Background(), pkg, nil, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9.9.9"}, versions)
+	assert.Zero(t, lookups)
+
+	versions, err = ExactVersionLister(registry, "1.2.3", true)(context.Background(), pkg, nil, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.2.3"}, versions)
+
+	_, err = ExactVersionLister(registry, "1.2.4", true)(context.Background(), pkg, nil, ".")
+	assert.EqualError(t, err, "version 1.2.4 of lodash is not published in the registry (use --no-verify to set it anyway)")
+}
+
+// TestBuildGroupedPlansUpdateTo tests planning with an exact --update-to target.
+//
+// It verifies:
+//   - The requested version becomes the target even outside the constraint and selection scope
+//   - A failed verification marks the package Failed with the lister's error
+func TestBuildGroupedPlansUpdateTo(t *testing.T) {
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+	registry := func(ctx context.Context, p formats.Package, cfg *config.Config, baseDir string) ([]string, error) {
+		return []string{"17.0.2", "18.2.0", "19.0.0"}, nil
+	}
+	resolved := []ResolvedUpdatePlan{
+		{Pkg: testutil.NPMPackage("react", "17.0.0", "17.0.0"), Cfg: &config.UpdateCfg{Commands: "npm install"}},
+	}
+	reason := func(formats.Package, *config.Config, error, bool) string { return "" }
+
+	ctx := NewUpdateContext(cfg, ".", nil)
+	opts := PlanningOptions{UpdateTo: "18.2.0"}
+	plans := BuildGroupedPlans(context.Background(), resolved, ctx, opts, ExactVersionLister(registry, "18.2.0", true), reason)
+	require.Len(t, plans, 1)
+	assert.Equal(t, "18.2.0", plans[0].Res.Target)
+	assert.NoError(t, plans[0].Res.Err)
+
+	opts.UpdateTo = "20.0.0"
+	plans = BuildGroupedPlans(context.Background(), resolved, ctx, opts, ExactVersionLister(registry, "20.0.0", true), reason)
+	require.Len(t, plans, 1)
+	assert.Equal(t, constants.StatusFailed, plans[0].Res.Status)
+	assert.ErrorContains(t, plans[0].Res.Err, "version 20.0.0 of react is not published")
+}
@@ -0,0 +1,270 @@
+package update
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/errors"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+)
+
+// verifyTempPrefix names the temporary directories dry-run verification works in.
+const verifyTempPrefix = "goupdate-verify-"
+
+// GroupVerification is the outcome of rehearsing one update group in a temporary copy.
+//
+// Fields:
+//   - Rule: Configuration rule of the group
+//   - Group: Group key of the group; the package name for ungrouped packages
+//   - Packages: Names of the packages whose manifest edits were rehearsed
+//   - Duration: How long the manifest edits and lock command took
+//   - Skipped: Why the lock command was not run (e.g. no lock command); empty when it ran
+//   - Err: Why the manifest edit or lock command failed
+type GroupVerification struct {
+	Rule     string
+	Group    string
+	Packages []string
+	Duration time.Duration
+	Skipped  string
+	Err      error
+}
+
+// VerifyPlans rehearses the planned updates outside the working tree (--dry-run-verify).
+//
+// Each update group is applied to its own temporary copy of the group's
+// manifests and lock files, so a failing lock command is found before a live
+// run. The copy mirrors the absolute paths of the originals below the
+// temporary directory; only manifests and lock files are copied.
+//
+// It performs the following operations:
+//   - Step 1: Split the plans into rules and update groups, keeping plans whose target differs from the original
+//   - Step 2: Copy the group's manifests and lock files into a temporary directory
+//   - Step 3: Apply the planned edits with the context's updater, as a live run does
+//   - Step 4: Run the group's lock command in the copy and remove the copy
+//
+// The working tree is never written, whatever the context's DryRun flag says.
+// A failure is recorded on the context and does not stop the remaining groups.
+//
+// Parameters:
+//   - ctx: Update context; Cfg, WorkDir, UpdaterFunc and the cancellation context are used
+//   - plans: Planned updates, sorted by rule and group as for execution
+//
+// Returns:
+//   - []GroupVerification: One entry per group with at least one pending update, in plan order
+func VerifyPlans(ctx *UpdateContext, plans []*PlannedUpdate) []GroupVerification {
+	var verifications []GroupVerification
+	for _, rulePlans := range PartitionPlans(plans, planRule) {
+		for _, groupPlans := range PartitionPlans(rulePlans, planGroup) {
+			pending := pendingVerifyPlans(groupPlans)
+			if len(pending) == 0 {
+				continue
+			}
+			if ctx.checkCancelled() != nil {
+				return verifications
+			}
+
+			verification := verifyGroup(ctx, pending)
+			if verification.Err != nil {
+				ctx.AppendFailure(fmt.Errorf("%s (%s): dry-run verification failed: %w", verification.Group, verification.Rule, verification.Err))
+			}
+			verifications = append(verifications, verification)
+		}
+	}
+	return verifications
+}
+
+// pendingVerifyPlans returns the plans of a group that would change a manifest.
+func pendingVerifyPlans(plans []*PlannedUpdate) []*PlannedUpdate {
+	var pending []*PlannedUpdate
+	for _, plan := range plans {
+		if ShouldSkipUpdate(&plan.Res) || versionsMatch(plan.Res.Target, plan.Original) {
+			continue
+		}
+		pending = append(pending, plan)
+	}
+	return pending
+}
+
+// verifyGroup applies one group's plans to a temporary copy and runs its lock command there.
+//
+// The temporary directory is removed when the function returns, also when the
+// updater or lock command panics.
+//
+// Parameters:
+//   - ctx: Update context with configuration and updater
+//   - plans: Pending plans of a single group
+//
+// Returns:
+//   - GroupVerification: The outcome of the rehearsal
+func verifyGroup(ctx *UpdateContext, plans []*PlannedUpdate) GroupVerification {
+	verification := GroupVerification{Rule: planRule(plans[0]), Group: planGroup(plans[0])}
+	for _, plan := range plans {
+		verification.Packages = append(verification.Packages, plan.Res.Pkg.Name)
+	}
+	if ctx.Cfg == nil {
+		verification.Err = fmt.Errorf("configuration is required")
+		return verification
+	}
+
+	tempDir, err := os.MkdirTemp("", verifyTempPrefix)
+	if err != nil {
+		verification.Err = fmt.Errorf("failed to create temporary directory: %w", err)
+		return verification
+	}
+	defer func() {
+		if removeErr := os.RemoveAll(tempDir); removeErr != nil {
+			verbose.Printf("Failed to remove %s: %v\n", tempDir, removeErr)
+		}
+	}()
+
+	workDir := ctx.WorkDir
+	if workDir == "" {
+		workDir = "."
+	}
+	tempPlans, err := copyGroupToTemp(ctx, plans, workDir, tempDir)
+	if err != nil {
+		verification.Err = err
+		return verification
+	}
+	tempWorkDir := mirrorPath(tempDir, workDir)
+
+	verbose.Printf("Verifying %s (%s) in %s\n", verification.Group, verification.Rule, tempDir)
+	start := time.Now()
+	if rehearseErr := rehearseGroup(ctx, tempPlans, tempWorkDir); rehearseErr != nil {
+		if errors.IsUnsupported(rehearseErr) {
+			verification.Skipped = rehearseErr.Error()
+		} else {
+			verification.Err = rehearseErr
+		}
+	}
+	verification.Duration = time.Since(start)
+	return verification
+}
+
+// rehearseGroup applies a group's edits to its temporary copy and runs the lock command there.
+//
+// A single package runs its own lock command through the updater, as in a live
+// run; larger groups rewrite every manifest first and share one lock command.
+//
+// Parameters:
+//   - ctx: Update context with configuration and updater
+//   - plans: Plans whose package sources point into the temporary copy
+//   - workDir: Working directory inside the temporary copy
+//
+// Returns:
+//   - error: The edit or lock failure; an UnsupportedError when there is no lock command
+func rehearseGroup(ctx *UpdateContext, plans []*PlannedUpdate, workDir string) error {
+	if len(plans) == 1 {
+		return ctx.rehearsalUpdater()(plans[0].Res.Pkg, plans[0].Res.Target, ctx.Cfg, workDir, false, false)
+	}
+
+	var editErr error
+	for _, plan := range plans {
+		if updateErr := ctx.rehearsalUpdater()(plan.Res.Pkg, plan.Res.Target, ctx.Cfg, workDir, false, true); updateErr != nil {
+			editErr = stderrors.Join(editErr, fmt.Errorf("%s: %w", plan.Res.Pkg.Name, updateErr))
+		}
+	}
+	if editErr != nil {
+		return editErr
+	}
+
+	groupCfg := groupLockConfig(plans)
+	if groupCfg == nil {
+		return &errors.UnsupportedError{Reason: "no update configuration for grouped packages"}
+	}
+	if ctx.Cfg.NoTimeout {
+		groupCfg.TimeoutSeconds = 0
+	}
+	withAllDeps := false
+	for _, plan := range plans {
+		withAllDeps = withAllDeps || allowsTransitive(ctx.Cfg, plan.Res.Pkg)
+	}
+	return runGroupLock(groupCfg, ctx.Cfg, workDir, plans, withAllDeps)
+}
+
+// copyGroupToTemp copies a group's manifests and lock files below tempDir.
+//
+// Parameters:
+//   - ctx: Update context with configuration
+//   - plans: Pending plans of a single group
+//   - workDir: Fallback directory for packages without a manifest path
+//   - tempDir: Temporary directory receiving the copies
+//
+// Returns:
+//   - []*PlannedUpdate: Copies of plans whose package source points into tempDir
+//   - error: When a file cannot be copied
+func copyGroupToTemp(ctx *UpdateContext, plans []*PlannedUpdate, workDir, tempDir string) ([]*PlannedUpdate, error) {
+	var paths []string
+	for _, plan := range plans {
+		if plan.Res.Pkg.Source != "" && !containsString(paths, plan.Res.Pkg.Source) {
+			paths = append(paths, plan.Res.Pkg.Source)
+		}
+	}
+	ruleCfg := ctx.Cfg.Rules[planRule(plans[0])]
+	for _, dir := range groupLockDirs(ctx.Cfg, workDir, plans) {
+		for _, path := range getLockFilePaths(ruleCfg, dir) {
+			if !containsString(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	for _, path := range paths {
+		if err := copyFileTo(path, mirrorPath(tempDir, path)); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(mirrorPath(tempDir, workDir), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", mirrorPath(tempDir, workDir), err)
+	}
+
+	tempPlans := make([]*PlannedUpdate, 0, len(plans))
+	for _, plan := range plans {
+		tempPlan := *plan
+		if plan.Res.Pkg.Source != "" {
+			tempPlan.Res.Pkg.Source = mirrorPath(tempDir, plan.Res.Pkg.Source)
+		}
+		tempPlans = append(tempPlans, &tempPlan)
+	}
+	return tempPlans, nil
+}
+
+// mirrorPath returns where path is copied to below tempDir.
+//
+// Parameters:
+//   - tempDir: Temporary directory holding the copies
+//   - path: Original file or directory path; relative paths are made absolute first
+//
+// Returns:
+//   - string: tempDir joined with the absolute path, without its volume name
+func mirrorPath(tempDir, path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = strings.TrimPrefix(path, filepath.VolumeName(path))
+	return filepath.Join(tempDir, path)
+}
+
+// copyFileTo copies src to dst, creating dst's directory and keeping src's permissions plus owner write.
+func copyFileTo(src, dst string) error {
+	content, err := readFileFunc(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	// The copy must stay writable so the planned edits can be applied to it
+	mode := os.FileMode(0o644)
+	if info, statErr := statFileFunc(src); statErr == nil {
+		mode = info.Mode().Perm() | 0o200
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+	}
+	if err := os.WriteFile(dst, content, mode); err != nil {
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package update
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+)
+
+// verifyPlan returns a planned npm update of name from 17.0.0 to target in the group.
+func verifyPlan(root, dir, name, group, target string) *PlannedUpdate {
+	p := formats.Package{Rule: "npm", PackageType: "js", Type: "prod", Name: name, Version: "17.0.0", Constraint: "^", Source: filepath.Join(root, dir, "package.json")}
+	return &PlannedUpdate{
+		Cfg:      &config.UpdateCfg{Commands: "npm install"},
+		Res:      UpdateResult{Pkg: p, Status: constants.StatusPlanned, Target: target},
+		Original: "17.0.0",
+		GroupKey: group,
+	}
+}
+
+// TestVerifyPlans tests rehearsing planned updates in temporary copies.
+//
+// It verifies:
+//   - Grouped packages are edited together and share one lock command; single packages run their own
+//   - Lock commands run in a copy holding the edited manifest and the lock file, never in the working tree
+//   - A failing lock command is reported on its group and recorded as a failure
+//   - Plans without a pending change are not verified
+//   - The working tree is unchanged and every temporary directory is removed
+func TestVerifyPlans(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	root := filepath.Join(t.TempDir(), "repo")
+	for _, dir := range []string{"web", "api"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+	webManifest := `{"dependencies": {"react": "^17.0.0", "react-dom": "^17.0.0"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(root, "web", "package.json"), []byte(webManifest), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "web", "package-lock.json"), []byte("lock"), 0o444))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "api", "package.json"), []byte(`{"dependencies": {"express": "^17.0.0"}}`), 0o644))
+
+	rule := testutil.NPMRule()
+	rule.LockFiles = []config.LockFileCfg{{Files: []string{"package-lock.json"}}}
+	cfg := testutil.NewConfig().WithRule("npm", rule).Build()
+
+	var runs []string
+	originalExec := execCommandFunc
+	execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+		assert.True(t, strings.HasPrefix(dir, tmp), "lock command must run in the temporary copy, ran in %s", dir)
+		manifest, err := os.ReadFile(filepath.Join(dir, "package.json"))
+		require.NoError(t, err)
+		runs = append(runs, filepath.Base(dir)+":"+pkg)
+
+		if filepath.Base(dir) == "api" {
+			return nil, stderrors.New("npm ERR! peer dependency conflict")
+		}
+		assert.Contains(t, string(manifest), `"react": "^18.2.0"`)
+		assert.Contains(t, string(manifest), `"react-dom": "^18.2.0"`)
+		_, err = os.Stat(filepath.Join(dir, "package-lock.json"))
+		assert.NoError(t, err, "lock file is copied")
+		return nil, os.WriteFile(filepath.Join(dir, "package-lock.json"), []byte("relocked"), 0o644)
+	}
+	t.Cleanup(func() { execCommandFunc = originalExec })
+
+	unchanged := verifyPlan(root, "web", "left-pad", "left-pad", "17.0.0")
+	failed := verifyPlan(root, "web", "vue", "vue", "3.0.0")
+	failed.Res.Status = constants.StatusFailed
+	plans := []*PlannedUpdate{
+		verifyPlan(root, "api", "express", "express", "18.2.0"),
+		unchanged,
+		verifyPlan(root, "web", "react", "frontend", "18.2.0"),
+		verifyPlan(root, "web", "react-dom", "frontend", "18.2.0"),
+		failed,
+	}
+
+	ctx := NewUpdateContext(cfg, root, nil).WithFlags(true, false, false).WithUpdaterFunc(UpdatePackage)
+	verifications := VerifyPlans(ctx, plans)
+
+	require.Len(t, verifications, 2)
+	assert.Equal(t, []string{"api:express", "web:"}, runs)
+
+	assert.Equal(t, "express", verifications[0].Group)
+	assert.ErrorContains(t, verifications[0].Err, "peer dependency conflict")
+	assert.Equal(t, "frontend", verifications[1].Group)
+	assert.Equal(t, "npm", verifications[1].Rule)
+	assert.Equal(t, []string{"react", "react-dom"}, verifications[1].Packages)
+	assert.NoError(t, verifications[1].Err)
+	assert.Empty(t, verifications[1].Skipped)
+
+	require.Len(t, ctx.Failures, 1)
+	assert.Contains(t, ctx.Failures[0].Error(), "express (npm): dry-run verification failed")
+
+	manifest, err := os.ReadFile(filepath.Join(root, "web", "package.json"))
+	require.NoError(t, err)
+	assert.Equal(t, webManifest, string(manifest))
+	lockFile, err := os.ReadFile(filepath.Join(root, "web", "package-lock.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "lock", string(lockFile))
+
+	leftovers, err := os.ReadDir(tmp)
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+// TestVerifyPlansSkipsAndCleansUp tests verification edge cases.
+//
+// It verifies:
+//   - A group without a lock command is skipped, not failed
+//   - The temporary copy is removed when the updater panics
+func TestVerifyPlansSkipsAndCleansUp(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package.json"), []byte(`{"dependencies": {"react": "^17.0.0", "vue": "^17.0.0"}}`), 0o644))
+	cfg := testutil.NewConfig().WithRule("npm", testutil.NPMRule()).Build()
+
+	plans := []*PlannedUpdate{verifyPlan(root, "", "react", "ui", "18.2.0"), verifyPlan(root, "", "vue", "ui", "18.2.0")}
+	for _, plan := range plans {
+		plan.Cfg = &config.UpdateCfg{}
+	}
+	ctx := NewUpdateContext(cfg, root, nil).WithUpdaterFunc(UpdatePackage)
+	verifications := VerifyPlans(ctx, plans)
+	require.Len(t, verifications, 1)
+	assert.NoError(t, verifications[0].Err)
+	assert.Equal(t, "no lock command configured", verifications[0].Skipped)
+	assert.Empty(t, ctx.Failures)
+
+	panicking := NewUpdateContext(cfg, root, nil).WithUpdaterFunc(func(formats.Package, string, *config.Config, string, bool, bool) error {
+		panic("updater crashed")
+	})
+	assert.Panics(t, func() { VerifyPlans(panicking, plans[:1]) })
+
+	leftovers, err := os.ReadDir(tmp)
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
@@ -0,0 +1,127 @@
+package update
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ajxudir/goupdate/pkg/cmdexec"
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/verbose"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// execWorkspaceSyncFunc runs a workspace sync command; tests stub it.
+var execWorkspaceSyncFunc = func(cfg *config.WorkspaceFileCfg, dir string) ([]byte, error) {
+	return cmdexec.Execute(cfg.Commands, cfg.Env, dir, cfg.TimeoutSeconds, nil)
+}
+
+// runWorkspaceSync runs a rule's workspace_file command in every workspace with updated modules.
+//
+// Each module of a go.work workspace is updated and locked in its own
+// directory; go work sync is the workspace-level lock step that follows. It
+// runs right after the lock commands of a group or package, so validation and
+// the commit of --commit see the synced files. Sync may raise a shared
+// dependency in every listed module, so the manifests and lock files of
+// modules without an applied package are restored afterwards: a targeted
+// update only ever changes the modules it selected. It is gated like the lock
+// commands: nothing runs in a dry run or with --skip-lock.
+//
+// It performs the following operations:
+//   - Step 1: Skip unless the plans' rule configures workspace_file commands
+//   - Step 2: Find the workspace root of every applied module (see config.WorkspaceRoot)
+//   - Step 3: Back up the manifests and lock files of the workspace's other modules
+//   - Step 4: Run the command once in each root, then restore the backed up files
+//   - Step 5: Warn on failure; the updates are kept
+//
+// Parameters:
+//   - ctx: Update context with configuration and state
+//   - plans: Plans of one rule whose updates and lock commands succeeded
+func runWorkspaceSync(ctx *UpdateContext, plans []*PlannedUpdate) {
+	if ctx.DryRun || ctx.SkipLockRun || ctx.Cfg == nil || len(plans) == 0 {
+		return
+	}
+	rule := planRule(plans[0])
+	ruleCfg, ok := ctx.Cfg.Rules[rule]
+	if !ok || ruleCfg.WorkspaceFile == nil || strings.TrimSpace(ruleCfg.WorkspaceFile.Commands) == "" {
+		return
+	}
+
+	updated := make(map[string]map[string]bool)
+	for _, plan := range plans {
+		if plan.Res.Pkg.Source == "" {
+			continue
+		}
+		moduleDir, err := filepath.Abs(filepath.Dir(plan.Res.Pkg.Source))
+		if err != nil {
+			continue
+		}
+		root, member := config.WorkspaceRoot(ruleCfg, moduleDir)
+		if !member {
+			continue
+		}
+		if updated[root] == nil {
+			updated[root] = make(map[string]bool)
+		}
+		updated[root][moduleDir] = true
+	}
+	if len(updated) == 0 {
+		return
+	}
+
+	roots := make([]string, 0, len(updated))
+	for root := range updated {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+
+	for _, root := range roots {
+		untouched := untouchedWorkspaceFiles(ruleCfg, root, updated[root])
+		backups, err := backupFiles(untouched)
+		if err != nil {
+			warnings.Warn(warnings.CodeWorkspaceSyncFailed, "", "⚠️ workspace sync skipped for rule %s in %s: %v", rule, root, err)
+			continue
+		}
+
+		start := time.Now()
+		out, err := execWorkspaceSyncFunc(ruleCfg.WorkspaceFile, root)
+		for _, restoreErr := range restoreBackups(backups) {
+			warnings.Warnf("Rollback warning: %v\n", restoreErr)
+		}
+		if err != nil {
+			verbose.Printf("Workspace sync FAILED for rule %s in %s: %v\n%s\n", rule, root, err, strings.TrimSpace(string(out)))
+			warnings.Warn(warnings.CodeWorkspaceSyncFailed, "", "⚠️ workspace sync failed for rule %s in %s: %v; the updates are kept", rule, root, err)
+			continue
+		}
+		verbose.Debugf("Workspace sync for rule %s in %s completed in %s; %d file(s) of other modules kept", rule, root, time.Since(start).Round(time.Millisecond), len(backups))
+	}
+}
+
+// untouchedWorkspaceFiles returns the manifests and lock files of the workspace modules without updates.
+//
+// Parameters:
+//   - ruleCfg: The rule configuration
+//   - root: The workspace root
+//   - updatedDirs: Absolute directories of the modules with an updated package
+//
+// Returns:
+//   - []string: Files that the workspace sync must leave unchanged
+func untouchedWorkspaceFiles(ruleCfg config.PackageManagerCfg, root string, updatedDirs map[string]bool) []string {
+	members, err := config.WorkspaceMembers(ruleCfg, root)
+	if err != nil {
+		verbose.Debugf("Failed to read workspace file in %s: %v", root, err)
+		return nil
+	}
+
+	var files []string
+	for _, manifest := range members {
+		moduleDir := filepath.Dir(manifest)
+		if updatedDirs[moduleDir] {
+			continue
+		}
+		files = append(files, manifest)
+		files = append(files, getLockFilePaths(ruleCfg, moduleDir)...)
+	}
+	return files
+}
@@ -0,0 +1,167 @@
+package update
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ajxudir/goupdate/pkg/config"
+	"github.com/ajxudir/goupdate/pkg/constants"
+	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/ajxudir/goupdate/pkg/testutil"
+	"github.com/ajxudir/goupdate/pkg/warnings"
+)
+
+// TestRunWorkspaceSync tests syncing a go.work workspace after updates are locked.
+//
+// It verifies:
+//   - Nothing runs in a dry run, with --skip-lock, or without commands
+//   - Modules outside a go.work workspace do not trigger a sync
+//   - The command runs once in the workspace root for several updated modules
+//   - Files of modules without an updated package are restored after the sync
+//   - A failure warns and keeps the updates
+//   - A group syncs after its lock command, before it is validated and committed
+func TestRunWorkspaceSync(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	read := func(rel string) string {
+		content, err := os.ReadFile(filepath.Join(root, rel))
+		require.NoError(t, err)
+		return string(content)
+	}
+	modules := []string{"api", "worker", "lib", "standalone"}
+	reset := func() {
+		write("go.work", "go 1.22\n\nuse (\n\t./api\n\t./worker\n\t./lib\n)\n")
+		for _, module := range modules {
+			write(module+"/go.mod", "require example.com/shared v1.0.0\n")
+			write(module+"/go.sum", "example.com/shared v1.0.0 h1:old\n")
+		}
+	}
+
+	newCfg := func(commands string) *config.Config {
+		return testutil.NewConfig().WithRule("mod", config.PackageManagerCfg{
+			Include:       []string{"**/go.mod"},
+			LockFiles:     []config.LockFileCfg{{Files: []string{"**/go.sum"}}},
+			WorkspaceFile: &config.WorkspaceFileCfg{File: "go.work", Format: config.WorkspaceFormatGoWork, Commands: commands},
+		}).Build()
+	}
+	plan := func(module string) *PlannedUpdate {
+		pkg := testutil.NewPackage("example.com/shared").WithRule("mod").WithVersion("v1.0.0").WithSource(filepath.Join(root, module, "go.mod")).Build()
+		return &PlannedUpdate{Res: UpdateResult{Pkg: pkg, Status: constants.StatusPlanned, Target: "v1.1.0"}, Original: "v1.0.0", GroupKey: "shared"}
+	}
+	newPlans := func() []*PlannedUpdate {
+		return []*PlannedUpdate{plan("api"), plan("lib")}
+	}
+	var events []string
+	stubExec := func(t *testing.T, err error) *[]string {
+		t.Helper()
+		var dirs []string
+		orig := execWorkspaceSyncFunc
+		execWorkspaceSyncFunc = func(cfg *config.WorkspaceFileCfg, dir string) ([]byte, error) {
+			dirs = append(dirs, dir)
+			events = append(events, "sync")
+			// go work sync raises the shared dependency in every module
+			for _, module := range modules {
+				write(module+"/go.mod", "require example.com/shared v1.1.0\n")
+				write(module+"/go.sum", "example.com/shared v1.1.0 h1:new\n")
+			}
+			return nil, err
+		}
+		t.Cleanup(func() { execWorkspaceSyncFunc = orig })
+		return &dirs
+	}
+
+	t.Run("skipped", func(t *testing.T) {
+		reset()
+		dirs := stubExec(t, nil)
+		for name, ctx := range map[string]*UpdateContext{
+			"dry run":     NewUpdateContext(newCfg("go work sync"), root, nil).WithFlags(true, false, false),
+			"skip lock":   NewUpdateContext(newCfg("go work sync"), root, nil).WithFlags(false, false, true),
+			"no commands": NewUpdateContext(newCfg(""), root, nil),
+		} {
+			runWorkspaceSync(ctx, newPlans())
+			assert.Empty(t, *dirs, name)
+		}
+
+		runWorkspaceSync(NewUpdateContext(newCfg("go work sync"), root, nil), []*PlannedUpdate{plan("standalone")})
+		assert.Empty(t, *dirs)
+	})
+
+	t.Run("once per workspace keeping other modules", func(t *testing.T) {
+		reset()
+		dirs := stubExec(t, nil)
+		ctx := NewUpdateContext(newCfg("go work sync"), root, nil)
+
+		runWorkspaceSync(ctx, newPlans())
+		assert.Equal(t, []string{root}, *dirs)
+		assert.Equal(t, "require example.com/shared v1.1.0\n", read("api/go.mod"))
+		assert.Equal(t, "require example.com/shared v1.1.0\n", read("lib/go.mod"))
+		assert.Equal(t, "require example.com/shared v1.0.0\n", read("worker/go.mod"))
+		assert.Equal(t, "example.com/shared v1.0.0 h1:old\n", read("worker/go.sum"))
+		assert.Empty(t, ctx.Failures)
+	})
+
+	t.Run("failure keeps updates", func(t *testing.T) {
+		reset()
+		stubExec(t, errors.New("exit status 1"))
+		var buf bytes.Buffer
+		restore := warnings.SetWarningWriter(&buf)
+		defer restore()
+		plans := newPlans()
+		ctx := NewUpdateContext(newCfg("go work sync"), root, nil)
+
+		runWorkspaceSync(ctx, plans)
+		assert.Empty(t, ctx.Failures)
+		assert.Equal(t, constants.StatusPlanned, plans[0].Res.Status)
+		assert.Equal(t, "require example.com/shared v1.0.0\n", read("worker/go.mod"))
+		assert.Contains(t, buf.String(), "workspace sync failed for rule mod in "+root+": exit status 1; the updates are kept")
+	})
+
+	t.Run("runs before the group's validation and commit", func(t *testing.T) {
+		reset()
+		events = nil
+		stubExec(t, nil)
+		originalExec := execCommandFunc
+		execCommandFunc = func(cfg *config.UpdateCfg, pkg, version, constraint, dir string, withAllDeps bool) ([]byte, error) {
+			events = append(events, "lock")
+			return nil, nil
+		}
+		t.Cleanup(func() { execCommandFunc = originalExec })
+		originalGit := gitCommandFunc
+		gitCommandFunc = func(dir string, args ...string) ([]byte, error) {
+			switch args[0] {
+			case "status":
+				return []byte(" M api/go.mod\n"), nil
+			case "commit":
+				events = append(events, "commit")
+			}
+			return nil, nil
+		}
+		t.Cleanup(func() { gitCommandFunc = originalGit })
+
+		plans := newPlans()
+		for _, p := range plans {
+			p.Cfg = &config.UpdateCfg{Commands: "go mod tidy"}
+		}
+		ctx := NewUpdateContext(newCfg("go work sync"), root, nil).
+			WithUpdaterFunc(func(p formats.Package, target string, cfg *config.Config, workDir string, dryRun bool, skipLock bool) error {
+				events = append(events, "update")
+				return nil
+			}).
+			WithCommitter(NewCommitter(root, ""))
+		var results []UpdateResult
+		ProcessGroupedPlansLive(ctx, plans, &results, ExecutionCallbacks{DeriveReason: func(formats.Package, *config.Config, error, bool) string { return "" }})
+		// One lock command per module, then a single sync of the workspace
+		assert.Equal(t, []string{"update", "update", "lock", "lock", "sync", "commit"}, events)
+		assert.Equal(t, "require example.com/shared v1.0.0\n", read("worker/go.mod"))
+	})
+}
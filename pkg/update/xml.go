@@ -82,6 +82,11 @@ func updateXMLVersion(content []byte, p formats.Package, ruleCfg config.PackageM
 	if ruleCfg.Manager == "nuget" || ruleCfg.Manager == "dotnet" {
 		refs := utils.FindXMLNodes(&root, "ItemGroup/PackageReference")
 		updateNodes(refs, "Include", "Version")
+		updateNodes(refs, "Include", "VersionOverride")
+
+		// Central package management (Directory.Packages.props)
+		updateNodes(utils.FindXMLNodes(&root, "ItemGroup/PackageVersion"), "Include", "Version")
+		updateNodes(utils.FindXMLNodes(&root, "ItemGroup/GlobalPackageReference"), "Include", "Version")
 	}
 
 	if !updated {
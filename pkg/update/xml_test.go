@@ -8,6 +8,7 @@ import (
 
 	"github.com/ajxudir/goupdate/pkg/config"
 	"github.com/ajxudir/goupdate/pkg/formats"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -63,3 +64,29 @@ func TestUpdateXMLVersionMarshalError(t *testing.T) {
 	err := UpdatePackage(formats.Package{Name: "Demo", Rule: "xml", Source: xmlPath}, "2.0.0", cfg, tmpDir, false, true)
 	require.Error(t, err)
 }
+
+// TestUpdateXMLVersionCentralPackageManagement tests updates of centrally managed versions.
+//
+// It verifies:
+//   - PackageVersion entries in Directory.Packages.props are rewritten
+//   - GlobalPackageReference entries are rewritten
+//   - VersionOverride attributes in project files are rewritten
+func TestUpdateXMLVersionCentralPackageManagement(t *testing.T) {
+	cfg := config.PackageManagerCfg{Manager: "dotnet", Format: "xml", Fields: map[string]string{"ItemGroup/PackageReference": "prod"}, Extraction: &config.ExtractionCfg{DevElement: "PrivateAssets"}}
+
+	props := []byte(`<Project><ItemGroup><PackageVersion Include="Serilog" Version="3.1.1" /><PackageVersion Include="Moq" Version="4.20.70" /></ItemGroup><ItemGroup><GlobalPackageReference Include="StyleCop.Analyzers" Version="1.1.118" /></ItemGroup></Project>`)
+
+	out, err := updateXMLVersion(props, formats.Package{Name: "Serilog", Source: "Directory.Packages.props"}, cfg, "3.2.0")
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `Include="Serilog" Version="3.2.0"`)
+	assert.Contains(t, string(out), `Include="Moq" Version="4.20.70"`)
+
+	out, err = updateXMLVersion(props, formats.Package{Name: "StyleCop.Analyzers", Source: "Directory.Packages.props"}, cfg, "1.2.0")
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `Include="StyleCop.Analyzers" Version="1.2.0"`)
+
+	project := []byte(`<Project><ItemGroup><PackageReference Include="Serilog" VersionOverride="3.0.0" /></ItemGroup></Project>`)
+	out, err = updateXMLVersion(project, formats.Package{Name: "Serilog", Source: "Api.csproj"}, cfg, "3.0.1")
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `VersionOverride="3.0.1"`)
+}
@@ -17,7 +17,7 @@ var yamlUnmarshalFunc = yaml.Unmarshal
 // It performs the following operations:
 //   - Step 1: Parse YAML content to validate structure
 //   - Step 2: Unmarshal into map structure
-//   - Step 3: Navigate to dependency fields and update package version
+//   - Step 3: Navigate to dependency fields and update package version (or its "version" key for map-valued dependencies)
 //   - Step 4: Marshal back to YAML format
 //
 // Parameters:
@@ -48,10 +48,21 @@ func updateYAMLVersion(content []byte, p formats.Package, ruleCfg config.Package
 			continue
 		}
 
-		if _, ok := deps[p.Name]; ok {
-			found = true
-			deps[p.Name] = fmt.Sprintf("%s%s", p.Constraint, target)
+		current, ok := deps[p.Name]
+		if !ok {
+			continue
+		}
+
+		found = true
+		updated := fmt.Sprintf("%s%s", p.Constraint, target)
+		// Map-valued dependencies (e.g. pub hosted packages) keep their other keys
+		if depMap, isMap := current.(map[string]interface{}); isMap {
+			if _, hasVersion := depMap["version"]; hasVersion {
+				depMap["version"] = updated
+				continue
+			}
 		}
+		deps[p.Name] = updated
 	}
 
 	if !found {
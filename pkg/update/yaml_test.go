@@ -84,6 +84,24 @@ func TestUpdateYAMLVersionNestedFields(t *testing.T) {
 	})
 }
 
+// TestUpdateYAMLVersionMapDependency tests updating map-valued dependencies.
+//
+// It verifies:
+//   - The "version" key of a hosted dependency is updated in place
+//   - Other keys of the dependency map are preserved
+func TestUpdateYAMLVersionMapDependency(t *testing.T) {
+	cfg := config.PackageManagerCfg{Format: "yaml", Fields: map[string]string{"dependencies": "prod"}}
+	content := []byte(`dependencies:
+  internal_api:
+    hosted: https://pub.example.com
+    version: ^2.3.0
+`)
+	updated, err := updateYAMLVersion(content, formats.Package{Name: "internal_api", Constraint: "^", Source: "pubspec.yaml"}, cfg, "2.4.0")
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), "version: ^2.4.0")
+	assert.Contains(t, string(updated), "hosted: https://pub.example.com")
+}
+
 // TestUpdateYAMLVersionParserError tests error handling when YAML parsing fails.
 //
 // It verifies:
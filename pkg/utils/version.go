@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"regexp"
 	"strings"
 
 	"github.com/ajxudir/goupdate/pkg/config"
@@ -39,7 +40,7 @@ func ValidateConstraint(constraint, packageName string) string {
 	}
 
 	verbose.Printf("Constraint validation ERROR: %q is invalid, using exact match\n", constraint)
-	warnings.Warnf("Invalid constraint '%s' for package '%s', using exact match\n", constraint, packageName)
+	warnings.Warn(warnings.CodeInvalidConstraint, packageName, "Invalid constraint '%s' for package '%s', using exact match", constraint, packageName)
 	return ""
 }
 
@@ -185,6 +186,88 @@ func IsFloatingConstraint(version string) bool {
 	return false
 }
 
+// floatingWildcardPattern matches NuGet floating versions: "*", "13.*", "13.0.*".
+var floatingWildcardPattern = regexp.MustCompile(`^(?:\d+\.)*\*$`)
+
+// IsFloatingWildcard checks if a version is a NuGet-style floating wildcard
+// ("*", "13.*", "13.0.*") that resolves to the highest release with the given prefix.
+//
+// Ranges, "x" notation and pre-release floats ("13.0.*-*") are not wildcards.
+//
+// Parameters:
+//   - version: The declared version to check
+//
+// Returns:
+//   - bool: true if the version is a numeric prefix followed by "*"
+func IsFloatingWildcard(version string) bool {
+	return floatingWildcardPattern.MatchString(strings.TrimSpace(version))
+}
+
+// MatchesFloatingWildcard checks if a concrete version satisfies a floating wildcard.
+//
+// Each numeric segment before the "*" must equal the version's segment at the
+// same position; "13.0.*" matches "13.0.3" but not "13.1.0" or "13.0".
+//
+// Parameters:
+//   - pattern: Floating wildcard accepted by IsFloatingWildcard
+//   - version: Concrete version to test; a leading "v" is ignored
+//
+// Returns:
+//   - bool: true if version falls under the wildcard; false for floating versions
+func MatchesFloatingWildcard(pattern, version string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if !IsFloatingWildcard(pattern) || IsFloatingConstraint(version) {
+		return false
+	}
+
+	prefix := strings.Split(strings.TrimSuffix(pattern, "*"), ".")
+	prefix = prefix[:len(prefix)-1]
+	segments := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(segments) <= len(prefix) {
+		return false
+	}
+	for i, want := range prefix {
+		if strings.TrimLeft(segments[i], "0") != strings.TrimLeft(want, "0") {
+			return false
+		}
+	}
+	return true
+}
+
+// preReleasePattern matches SemVer versions with a pre-release identifier (e.g., "1.0.0-beta.3").
+var preReleasePattern = regexp.MustCompile(`^[v=^~<>\s]*\d+(?:\.\d+){0,3}-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*(?:\+[0-9A-Za-z.-]+)?$`)
+
+// buildMetadataPattern matches SemVer versions with build metadata (e.g., "1.0.0+build.5").
+var buildMetadataPattern = regexp.MustCompile(`^[v=^~<>\s]*\d+(?:\.\d+){0,3}(?:-[0-9A-Za-z.-]+)?\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*$`)
+
+// IsPreReleaseVersion checks if a version carries a SemVer pre-release identifier.
+//
+// Leading constraint operators and a "v" prefix are ignored, so "^1.0.0-beta.3"
+// and "v2.0.0-rc.1" are both pre-releases. Floating constraints never match.
+//
+// Parameters:
+//   - version: The declared or installed version string
+//
+// Returns:
+//   - bool: true if the version has a pre-release identifier
+func IsPreReleaseVersion(version string) bool {
+	return preReleasePattern.MatchString(strings.TrimSpace(version))
+}
+
+// HasBuildMetadata checks if a version carries SemVer build metadata.
+//
+// Build metadata ("+build.5") is ignored by SemVer precedence, so versions
+// that differ only in metadata cannot be ordered.
+//
+// Parameters:
+//   - version: The declared or installed version string
+//
+// Returns:
+//   - bool: true if the version has build metadata
+func HasBuildMetadata(version string) bool {
+	return buildMetadataPattern.MatchString(strings.TrimSpace(version))
+}
+
 // ApplyPackageOverride applies package-specific overrides to version info.
 //
 // It performs the following operations:
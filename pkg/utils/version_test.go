@@ -274,3 +274,94 @@ func TestIsFloatingConstraintRealWorldExamples(t *testing.T) {
 		})
 	}
 }
+
+// TestIsFloatingWildcard tests detection of NuGet floating wildcards.
+//
+// It verifies:
+//   - "*" and numeric prefixes ending in "*" are wildcards
+//   - Ranges, x notation, pre-release floats and exact versions are not
+func TestIsFloatingWildcard(t *testing.T) {
+	for _, version := range []string{"*", "13.*", "13.0.*", " 1.2.3.* "} {
+		assert.True(t, IsFloatingWildcard(version), version)
+	}
+	for _, version := range []string{"", "13.0.1", "5.x", "5*", "13.0.*-*", "[8.0.0,9.0.0)", ">=1.0 <2.0"} {
+		assert.False(t, IsFloatingWildcard(version), version)
+	}
+}
+
+// TestMatchesFloatingWildcard tests matching concrete versions against floating wildcards.
+//
+// It verifies:
+//   - Every prefix segment must match, ignoring leading zeros and a "v" prefix
+//   - The version needs at least one segment beyond the prefix
+//   - Non-wildcard patterns and floating versions never match
+func TestMatchesFloatingWildcard(t *testing.T) {
+	assert.True(t, MatchesFloatingWildcard("13.0.*", "13.0.3"))
+	assert.True(t, MatchesFloatingWildcard("13.0.*", "v13.0.3"))
+	assert.True(t, MatchesFloatingWildcard("13.*", "13.4.1"))
+	assert.True(t, MatchesFloatingWildcard("*", "2.0.0"))
+	assert.True(t, MatchesFloatingWildcard("2024.01.*", "2024.1.5"))
+
+	assert.False(t, MatchesFloatingWildcard("13.0.*", "13.1.0"))
+	assert.False(t, MatchesFloatingWildcard("13.0.*", "13.0"))
+	assert.False(t, MatchesFloatingWildcard("13.*", "12.9.9"))
+	assert.False(t, MatchesFloatingWildcard("[13.0,14.0)", "13.5.0"))
+	assert.False(t, MatchesFloatingWildcard("13.*", "13.*"))
+}
+
+// TestIsPreReleaseVersion tests the behavior of IsPreReleaseVersion.
+//
+// It verifies:
+//   - SemVer pre-release identifiers are detected with or without prefixes
+//   - Stable, build-metadata-only, and floating versions are not pre-releases
+func TestIsPreReleaseVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		expected bool
+	}{
+		{"empty string", "", false},
+		{"stable version", "1.0.0", false},
+		{"beta with number", "1.0.0-beta.3", true},
+		{"release candidate", "2.0.0-rc1", true},
+		{"v prefix", "v2.0.0-rc.1", true},
+		{"caret constraint", "^1.0.0-alpha", true},
+		{"pre-release with build metadata", "1.0.0-beta.3+build.5", true},
+		{"build metadata only", "1.0.0+build.5", false},
+		{"floating wildcard", "1.*", false},
+		{"compound range", ">=1.0.0-beta <2.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsPreReleaseVersion(tt.version))
+		})
+	}
+}
+
+// TestHasBuildMetadata tests the behavior of HasBuildMetadata.
+//
+// It verifies:
+//   - SemVer build metadata is detected with or without a pre-release
+//   - Versions without build metadata do not match
+func TestHasBuildMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		expected bool
+	}{
+		{"empty string", "", false},
+		{"stable version", "1.0.0", false},
+		{"build metadata", "1.0.0+build.5", true},
+		{"build metadata with v prefix", "v1.2.3+20130313144700", true},
+		{"pre-release with build metadata", "1.0.0-beta.3+exp.sha.5114f85", true},
+		{"pre-release only", "1.0.0-beta.3", false},
+		{"trailing plus", "1.0.0+", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, HasBuildMetadata(tt.version))
+		})
+	}
+}
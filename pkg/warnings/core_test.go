@@ -47,3 +47,34 @@ func TestWarningWriterReturnsCurrent(t *testing.T) {
 
 	assert.Equal(t, original, WarningWriter())
 }
+
+// TestWarnStructured tests writing classified warnings.
+//
+// It verifies:
+//   - Plain writers receive the message followed by a single newline
+//   - Structured writers receive the code, message, and package reference
+//   - String renders the code in brackets before the message
+func TestWarnStructured(t *testing.T) {
+	var buf bytes.Buffer
+	restore := SetWarningWriter(&buf)
+	Warn(CodeParseFailed, "", "failed to parse %s\n", "go.mod")
+	restore()
+	assert.Equal(t, "failed to parse go.mod\n", buf.String())
+
+	sink := &structuredSink{}
+	restore = SetWarningWriter(sink)
+	Warn(CodeFloatingConstraint, PackageRef("serilog", "dotnet", "nuget"), "floating %s", "5.*")
+	restore()
+
+	assert.Equal(t, []Warning{{Code: CodeFloatingConstraint, Message: "floating 5.*", PackageRef: "serilog (dotnet/nuget)"}}, sink.list)
+	assert.Equal(t, "[FLOATING_CONSTRAINT] floating 5.*", sink.list[0].String())
+}
+
+// structuredSink records warnings passed through StructuredWriter.
+type structuredSink struct {
+	list []Warning
+}
+
+func (s *structuredSink) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *structuredSink) WriteWarning(w Warning) { s.list = append(s.list, w) }
@@ -0,0 +1,147 @@
+package warnings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Code is a stable identifier that classifies a warning.
+//
+// Codes are part of the structured output contract: log pipelines may match
+// on them, so existing values must never be renamed. New codes may be added.
+type Code string
+
+const (
+	// CodeGeneral classifies warnings written as plain text without a code.
+	CodeGeneral Code = "GENERAL"
+
+	// CodeFloatingConstraint marks a package declared with a floating
+	// constraint (e.g. "5.*", ">=8.0.0") that cannot be updated automatically.
+	CodeFloatingConstraint Code = "FLOATING_CONSTRAINT"
+
+	// CodeLockMissing marks a package whose rule configures lock files but
+	// none were found next to the manifest.
+	CodeLockMissing Code = "LOCK_MISSING"
+
+	// CodeVersionMissing marks a package without a concrete installed version.
+	CodeVersionMissing Code = "VERSION_MISSING"
+
+	// CodeNonRegistry marks a package installed from a git, path, or other
+	// non-registry source.
+	CodeNonRegistry Code = "NON_REGISTRY"
+
+	// CodeUnmappedConstraint marks a constraint operator without a mapping,
+	// which falls back to an exact match.
+	CodeUnmappedConstraint Code = "UNMAPPED_CONSTRAINT"
+
+	// CodeInvalidConstraint marks a constraint that could not be parsed.
+	CodeInvalidConstraint Code = "INVALID_CONSTRAINT"
+
+	// CodePathSkipped marks a file or directory skipped during detection.
+	CodePathSkipped Code = "PATH_SKIPPED"
+
+	// CodeParseFailed marks a manifest that could not be parsed.
+	CodeParseFailed Code = "PARSE_FAILED"
+
+	// CodeReleaseDatesUnavailable marks a package whose release dates could
+	// not be fetched for --age or --min-age.
+	CodeReleaseDatesUnavailable Code = "RELEASE_DATES_UNAVAILABLE"
+
+	// CodeYankedUnavailable marks a package whose yanked versions could not
+	// be looked up; its versions are offered unfiltered.
+	CodeYankedUnavailable Code = "YANKED_UNAVAILABLE"
+
+	// CodeYankedOnly marks a package whose only newer versions are yanked,
+	// deprecated, or retracted; it is reported as up to date.
+	CodeYankedOnly Code = "YANKED_ONLY"
+
+	// CodeVersionConflict marks a package declared at different versions in
+	// different manifests of the same rule.
+	CodeVersionConflict Code = "VERSION_CONFLICT"
+
+	// CodeAmbiguousLockFiles marks a directory whose lock files point to more
+	// than one rule of the same package manager, so no rule was inferred.
+	CodeAmbiguousLockFiles Code = "AMBIGUOUS_LOCK_FILES"
+
+	// CodeDedupeFailed marks a non-critical update --dedupe command that
+	// failed; the rule's updates are kept.
+	CodeDedupeFailed Code = "DEDUPE_FAILED"
+
+	// CodeWorkspaceSyncFailed marks a workspace_file command (e.g. go work
+	// sync) that failed after a rule's updates; the updates are kept.
+	CodeWorkspaceSyncFailed Code = "WORKSPACE_SYNC_FAILED"
+)
+
+// Warning is a classified warning message.
+//
+// Fields:
+//   - Code: Stable classification code
+//   - Message: Human-readable warning text
+//   - PackageRef: Package the warning refers to as "name (type/rule)"; empty when not package-specific
+type Warning struct {
+	Code       Code   `json:"code" xml:"code,attr"`
+	Message    string `json:"message" xml:"message"`
+	PackageRef string `json:"package,omitempty" xml:"package,attr,omitempty"`
+}
+
+// String renders the warning as "[CODE] message".
+//
+// Returns:
+//   - string: The code in brackets followed by the message
+func (w Warning) String() string {
+	return fmt.Sprintf("[%s] %s", w.Code, w.Message)
+}
+
+// StructuredWriter is implemented by warning writers that keep warning codes.
+//
+// When the configured warning writer implements it, Warn passes the Warning
+// as-is instead of writing its message as text.
+type StructuredWriter interface {
+	WriteWarning(w Warning)
+}
+
+// Warn writes a classified warning to the configured warning writer.
+//
+// Writers implementing StructuredWriter receive the Warning; any other writer
+// receives the formatted message followed by a newline, exactly as Warnf
+// would write it.
+//
+// Parameters:
+//   - code: Stable classification code
+//   - packageRef: Package the warning refers to; empty when not package-specific
+//   - format: Printf-style format string for the warning message
+//   - args: Variadic arguments to format into the string
+//
+// Example:
+//
+//	warnings.Warn(warnings.CodeParseFailed, "", "⚠️ failed to parse %s: %v", file, err)
+func Warn(code Code, packageRef string, format string, args ...any) {
+	message := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+
+	mu.RLock()
+	w := warnWriter
+	mu.RUnlock()
+
+	if structured, ok := w.(StructuredWriter); ok {
+		structured.WriteWarning(Warning{Code: code, Message: message, PackageRef: packageRef})
+		return
+	}
+	_, _ = fmt.Fprintln(w, message)
+}
+
+// PackageRef formats the reference used in Warning.PackageRef.
+//
+// Parameters:
+//   - name: Package name
+//   - packageType: Package manager type (e.g. "js")
+//   - rule: Configuration rule name
+//
+// Returns:
+//   - string: The reference as "name (type/rule)"
+//
+// Example:
+//
+//	warnings.PackageRef("react", "js", "npm") // "react (js/npm)"
+func PackageRef(name, packageType, rule string) string {
+	return fmt.Sprintf("%s (%s/%s)", name, packageType, rule)
+}